@@ -1,9 +1,12 @@
-GO_ALL_REPO_PKGS := ./cmd/... ./private/...
+GO_ALL_REPO_PKGS := ./cmd/... ./private/... ./protogenutil/...
 #GO_GET_PKGS := $(GO_GET_PKGS)
 GO_BINS := $(GO_BINS) \
 	cmd/buf \
 	cmd/protoc-gen-buf-breaking \
+	cmd/protoc-gen-buf-grpc-service-config \
 	cmd/protoc-gen-buf-lint \
+	cmd/protoc-gen-buf-otel \
+	cmd/protoc-gen-buf-sql \
 	private/buf/bufwkt/cmd/wkt-go-data \
 	private/bufpkg/bufmodule/bufmoduleapi/cmd/buf-legacyfederation-go-data \
 	private/bufpkg/bufmodule/bufmoduletesting/cmd/buf-digest \
@@ -25,7 +28,8 @@ GO_TEST_BINS := $(GO_TEST_BINS) \
 	private/bufpkg/bufcheck/internal/cmd/buf-plugin-duplicate-category \
 	private/bufpkg/bufcheck/internal/cmd/buf-plugin-duplicate-rule
 GO_TEST_WASM_BINS := $(GO_TEST_WASM_BINS) \
-	private/bufpkg/bufcheck/internal/cmd/buf-plugin-suffix
+	private/bufpkg/bufcheck/internal/cmd/buf-plugin-suffix \
+	private/buf/cmd/buf/command/generate/internal/protoc-gen-top-level-type-names-yaml-wasm
 GO_MOD_VERSION := 1.22
 DOCKER_BINS := $(DOCKER_BINS) buf
 FILE_IGNORES := $(FILE_IGNORES) \
@@ -17,12 +17,63 @@ package bufapp
 import (
 	"crypto/tls"
 	"fmt"
+	"net/url"
+	"path/filepath"
 
 	"github.com/bufbuild/buf/private/pkg/app/appext"
 	"github.com/bufbuild/buf/private/pkg/cert/certclient"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
 )
 
-const currentVersion = "v1"
+const (
+	currentVersion = "v1"
+
+	pluginMirrorEnvKey         = "BUF_PLUGIN_MIRROR"
+	pluginChecksumPolicyEnvKey = "BUF_PLUGIN_CHECKSUM_POLICY"
+	moduleMappingsFileEnvKey   = "BUF_MODULE_MAPPINGS_FILE"
+)
+
+const (
+	// PluginChecksumPolicyVerify says to verify the digest of downloaded plugins,
+	// and to fail if the digest does not match.
+	//
+	// This is the default.
+	PluginChecksumPolicyVerify PluginChecksumPolicy = iota + 1
+	// PluginChecksumPolicyWarn says to verify the digest of downloaded plugins,
+	// and to print a warning if the digest does not match.
+	//
+	// Plugins are not cached with this policy.
+	PluginChecksumPolicyWarn
+)
+
+var (
+	// AllPluginChecksumPolicyStrings are all PluginChecksumPolicy strings.
+	AllPluginChecksumPolicyStrings = []string{
+		"verify",
+		"warn",
+	}
+
+	pluginChecksumPolicyToString = map[PluginChecksumPolicy]string{
+		PluginChecksumPolicyVerify: "verify",
+		PluginChecksumPolicyWarn:   "warn",
+	}
+	stringToPluginChecksumPolicy = map[string]PluginChecksumPolicy{
+		"verify": PluginChecksumPolicyVerify,
+		"warn":   PluginChecksumPolicyWarn,
+	}
+)
+
+// PluginChecksumPolicy is the policy for verifying the digests of downloaded plugins.
+type PluginChecksumPolicy int
+
+// String implements fmt.Stringer.
+func (p PluginChecksumPolicy) String() string {
+	s, ok := pluginChecksumPolicyToString[p]
+	if !ok {
+		return fmt.Sprintf("%d", p)
+	}
+	return s
+}
 
 // ExternalConfig is an external config.
 type ExternalConfig struct {
@@ -30,19 +81,67 @@ type ExternalConfig struct {
 
 	Version string                             `json:"version,omitempty" yaml:"version,omitempty"`
 	TLS     certclient.ExternalClientTLSConfig `json:"tls,omitempty" yaml:"tls,omitempty"`
+	Plugins ExternalPluginsConfig              `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+	Modules ExternalModulesConfig              `json:"modules,omitempty" yaml:"modules,omitempty"`
 }
 
 // IsEmpty returns true if the externalConfig is empty.
 func (e ExternalConfig) IsEmpty() bool {
-	return e.Version == "" && e.TLS.IsEmpty()
+	return e.Version == "" && e.TLS.IsEmpty() && e.Plugins.IsEmpty() && e.Modules.IsEmpty()
+}
+
+// ExternalPluginsConfig is an external config for remote plugins.
+type ExternalPluginsConfig struct {
+	// Mirror is the URL of a mirror of the plugin services of the BSR, such as
+	// https://buf-mirror.example.com. If set, remote plugins are downloaded from,
+	// resolved with, and run on the mirror instead of their registry.
+	Mirror string `json:"mirror,omitempty" yaml:"mirror,omitempty"`
+	// ChecksumPolicy is the policy for verifying the digests of downloaded plugins.
+	// Must be one of AllPluginChecksumPolicyStrings.
+	ChecksumPolicy string `json:"checksum_policy,omitempty" yaml:"checksum_policy,omitempty"`
+}
+
+// IsEmpty returns true if the externalPluginsConfig is empty.
+func (e ExternalPluginsConfig) IsEmpty() bool {
+	return e.Mirror == "" && e.ChecksumPolicy == ""
+}
+
+// ExternalModulesConfig is an external config for modules.
+type ExternalModulesConfig struct {
+	// MappingsFile is the path to a module mappings file, which maps the names of
+	// modules to their names on a registry, such as a mirror of buf.build.
+	//
+	// If relative, the path is relative to the configuration directory.
+	MappingsFile string `json:"mappings_file,omitempty" yaml:"mappings_file,omitempty"`
+}
+
+// IsEmpty returns true if the externalModulesConfig is empty.
+func (e ExternalModulesConfig) IsEmpty() bool {
+	return e.MappingsFile == ""
 }
 
 // Config is a config.
 type Config struct {
 	TLS *tls.Config
+	// PluginMirror is the URL of the plugin mirror.
+	//
+	// Empty if no mirror is configured.
+	PluginMirror string
+	// PluginChecksumPolicy is the policy for verifying the digests of downloaded plugins.
+	//
+	// Always set.
+	PluginChecksumPolicy PluginChecksumPolicy
+	// ModuleMappingsFilePath is the path to the module mappings file.
+	//
+	// Empty if no module mappings file is configured.
+	ModuleMappingsFilePath string
 }
 
 // NewConfig returns a new Config for the ExternalConfig.
+//
+// The plugin mirror, checksum policy, and module mappings file can be overridden with the
+// BUF_PLUGIN_MIRROR, BUF_PLUGIN_CHECKSUM_POLICY, and BUF_MODULE_MAPPINGS_FILE environment
+// variables.
 func NewConfig(
 	container appext.NameContainer,
 	externalConfig ExternalConfig,
@@ -54,7 +153,58 @@ func NewConfig(
 	if err != nil {
 		return nil, err
 	}
+	pluginMirror := externalConfig.Plugins.Mirror
+	if envPluginMirror := container.Env(pluginMirrorEnvKey); envPluginMirror != "" {
+		pluginMirror = envPluginMirror
+	}
+	if pluginMirror != "" {
+		if err := validatePluginMirror(pluginMirror); err != nil {
+			return nil, err
+		}
+	}
+	pluginChecksumPolicyString := externalConfig.Plugins.ChecksumPolicy
+	if envPluginChecksumPolicy := container.Env(pluginChecksumPolicyEnvKey); envPluginChecksumPolicy != "" {
+		pluginChecksumPolicyString = envPluginChecksumPolicy
+	}
+	pluginChecksumPolicy := PluginChecksumPolicyVerify
+	if pluginChecksumPolicyString != "" {
+		pluginChecksumPolicy, err = parsePluginChecksumPolicy(pluginChecksumPolicyString)
+		if err != nil {
+			return nil, err
+		}
+	}
+	moduleMappingsFilePath := externalConfig.Modules.MappingsFile
+	if moduleMappingsFilePath != "" && !filepath.IsAbs(moduleMappingsFilePath) {
+		moduleMappingsFilePath = filepath.Join(container.ConfigDirPath(), moduleMappingsFilePath)
+	}
+	if envModuleMappingsFilePath := container.Env(moduleMappingsFileEnvKey); envModuleMappingsFilePath != "" {
+		moduleMappingsFilePath = envModuleMappingsFilePath
+	}
 	return &Config{
-		TLS: tlsConfig,
+		TLS:                    tlsConfig,
+		PluginMirror:           pluginMirror,
+		PluginChecksumPolicy:   pluginChecksumPolicy,
+		ModuleMappingsFilePath: moduleMappingsFilePath,
 	}, nil
 }
+
+// *** PRIVATE ***
+
+func validatePluginMirror(pluginMirror string) error {
+	pluginMirrorURL, err := url.Parse(pluginMirror)
+	if err != nil {
+		return fmt.Errorf("invalid plugin mirror %q: %w", pluginMirror, err)
+	}
+	if (pluginMirrorURL.Scheme != "http" && pluginMirrorURL.Scheme != "https") || pluginMirrorURL.Host == "" {
+		return fmt.Errorf("invalid plugin mirror %q: must be an http or https URL", pluginMirror)
+	}
+	return nil
+}
+
+func parsePluginChecksumPolicy(s string) (PluginChecksumPolicy, error) {
+	pluginChecksumPolicy, ok := stringToPluginChecksumPolicy[s]
+	if !ok {
+		return 0, fmt.Errorf("unknown plugin checksum policy %q, must be one of %s", s, stringutil.SliceToString(AllPluginChecksumPolicyStrings))
+	}
+	return pluginChecksumPolicy, nil
+}
@@ -15,12 +15,109 @@
 package bufapp
 
 import (
+	"path/filepath"
 	"testing"
 
+	"github.com/bufbuild/buf/private/pkg/app"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExternalConfigIsEmpty(t *testing.T) {
 	t.Parallel()
 	assert.True(t, ExternalConfig{}.IsEmpty())
 }
+
+func TestNewConfigPlugins(t *testing.T) {
+	t.Parallel()
+	config, err := NewConfig(newTestContainer(t, nil), ExternalConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, config.PluginMirror)
+	assert.Equal(t, PluginChecksumPolicyVerify, config.PluginChecksumPolicy)
+
+	externalConfig := ExternalConfig{
+		Version: "v1",
+		Plugins: ExternalPluginsConfig{
+			Mirror:         "https://buf-mirror.example.com",
+			ChecksumPolicy: "warn",
+		},
+	}
+	config, err = NewConfig(newTestContainer(t, nil), externalConfig)
+	require.NoError(t, err)
+	assert.Equal(t, "https://buf-mirror.example.com", config.PluginMirror)
+	assert.Equal(t, PluginChecksumPolicyWarn, config.PluginChecksumPolicy)
+
+	config, err = NewConfig(
+		newTestContainer(
+			t,
+			map[string]string{
+				"BUF_PLUGIN_MIRROR":          "http://localhost:8080/buf",
+				"BUF_PLUGIN_CHECKSUM_POLICY": "verify",
+			},
+		),
+		externalConfig,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/buf", config.PluginMirror)
+	assert.Equal(t, PluginChecksumPolicyVerify, config.PluginChecksumPolicy)
+}
+
+func TestNewConfigPluginsInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := NewConfig(
+		newTestContainer(t, map[string]string{"BUF_PLUGIN_MIRROR": "buf-mirror.example.com"}),
+		ExternalConfig{},
+	)
+	require.Error(t, err)
+	_, err = NewConfig(
+		newTestContainer(t, map[string]string{"BUF_PLUGIN_CHECKSUM_POLICY": "none"}),
+		ExternalConfig{},
+	)
+	require.Error(t, err)
+	_, err = NewConfig(
+		newTestContainer(t, nil),
+		ExternalConfig{
+			Plugins: ExternalPluginsConfig{
+				Mirror: "https://buf-mirror.example.com",
+			},
+		},
+	)
+	// Missing version.
+	require.Error(t, err)
+}
+
+func TestNewConfigModules(t *testing.T) {
+	t.Parallel()
+	container := newTestContainer(t, nil)
+	config, err := NewConfig(container, ExternalConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, config.ModuleMappingsFilePath)
+
+	externalConfig := ExternalConfig{
+		Version: "v1",
+		Modules: ExternalModulesConfig{
+			MappingsFile: "buf.mappings.yaml",
+		},
+	}
+	config, err = NewConfig(container, externalConfig)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(container.ConfigDirPath(), "buf.mappings.yaml"), config.ModuleMappingsFilePath)
+
+	config, err = NewConfig(
+		newTestContainer(t, map[string]string{"BUF_MODULE_MAPPINGS_FILE": "mappings.yaml"}),
+		externalConfig,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "mappings.yaml", config.ModuleMappingsFilePath)
+}
+
+func newTestContainer(t *testing.T, env map[string]string) appext.NameContainer {
+	if env == nil {
+		env = make(map[string]string)
+	}
+	env["BUF_CONFIG_DIR"] = t.TempDir()
+	container, err := appext.NewNameContainer(app.NewContainer(env, nil, nil, nil), "buf")
+	require.NoError(t, err)
+	return container
+}
@@ -0,0 +1,88 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufchanged maps the locations of FileAnnotations to the lines changed in a
+// git repository since a ref, so that only the violations in a change are reported.
+//
+// This allows enforcing that changes do not add violations in repositories that have
+// existing violations.
+package bufchanged
+
+import (
+	"context"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/app"
+)
+
+// ChangedLines are the lines changed in a git repository since a ref.
+type ChangedLines interface {
+	// ContainsFileAnnotation returns true if the FileAnnotation is within the changes.
+	//
+	// A FileAnnotation is within the changes if any of its lines were added or modified,
+	// or if lines were removed within its lines, such as for a field deleted from a message.
+	// All lines of added files are changed.
+	//
+	// FileAnnotations that cannot be mapped to the working tree are always within the
+	// changes, so that they are not suppressed. These are FileAnnotations without a
+	// file, and FileAnnotations for files that do not exist, such as deleted files.
+	// FileAnnotations without a location in a changed file are within the changes.
+	ContainsFileAnnotation(fileAnnotation bufanalysis.FileAnnotation) bool
+	// ContainsFile returns true if the file at the path was added or modified.
+	ContainsFile(path string) bool
+
+	isChangedLines()
+}
+
+// NewChangedLinesSinceRef returns the lines changed in the working tree of the git
+// repository containing the directory since the merge base of ref and HEAD, including
+// uncommitted changes and untracked files.
+func NewChangedLinesSinceRef(
+	ctx context.Context,
+	envContainer app.EnvContainer,
+	dirPath string,
+	ref string,
+) (ChangedLines, error) {
+	return newChangedLinesSinceRef(ctx, envContainer, dirPath, ref, false)
+}
+
+// NewChangedFilesSinceRef returns the lines changed in the working tree of the git
+// repository containing the directory since the merge base of ref and HEAD, including
+// uncommitted changes and untracked files, where all lines of changed files are changed.
+//
+// This is used to report all violations in the files changed since the ref.
+func NewChangedFilesSinceRef(
+	ctx context.Context,
+	envContainer app.EnvContainer,
+	dirPath string,
+	ref string,
+) (ChangedLines, error) {
+	return newChangedLinesSinceRef(ctx, envContainer, dirPath, ref, true)
+}
+
+// FilterFileAnnotations returns the FileAnnotations within the ChangedLines.
+//
+// The order of the FileAnnotations is preserved.
+func FilterFileAnnotations(
+	changedLines ChangedLines,
+	fileAnnotations []bufanalysis.FileAnnotation,
+) []bufanalysis.FileAnnotation {
+	var filteredFileAnnotations []bufanalysis.FileAnnotation
+	for _, fileAnnotation := range fileAnnotations {
+		if changedLines.ContainsFileAnnotation(fileAnnotation) {
+			filteredFileAnnotations = append(filteredFileAnnotations, fileAnnotation)
+		}
+	}
+	return filteredFileAnnotations
+}
@@ -0,0 +1,144 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufchanged
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/git"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterFileAnnotations(t *testing.T) {
+	t.Parallel()
+	tempDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	modifiedFilePath := filepath.Join(tempDir, "modified.proto")
+	newFilePath := filepath.Join(tempDir, "new.proto")
+	unchangedFilePath := filepath.Join(tempDir, "unchanged.proto")
+	deletedFilePath := filepath.Join(tempDir, "deleted.proto")
+	for _, filePath := range []string{modifiedFilePath, newFilePath, unchangedFilePath} {
+		require.NoError(t, os.WriteFile(filePath, nil, 0600))
+	}
+	changedLines := newChangedLines(
+		[]*git.ChangedFile{
+			{
+				Path: modifiedFilePath,
+				LineRanges: []git.LineRange{
+					{StartLine: 5, EndLine: 6},
+				},
+				DeletedAfterLines: []int{10},
+			},
+			{
+				Path:  newFilePath,
+				IsNew: true,
+			},
+		},
+		false,
+	)
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		newFileAnnotation(modifiedFilePath, 1, 3),
+		newFileAnnotation(modifiedFilePath, 4, 5),
+		newFileAnnotation(modifiedFilePath, 6, 6),
+		newFileAnnotation(modifiedFilePath, 7, 9),
+		newFileAnnotation(modifiedFilePath, 9, 11),
+		newFileAnnotation(modifiedFilePath, 10, 10),
+		newFileAnnotation(modifiedFilePath, 0, 0),
+		newFileAnnotation(newFilePath, 100, 100),
+		newFileAnnotation(unchangedFilePath, 5, 5),
+		newFileAnnotation(deletedFilePath, 5, 5),
+		bufanalysis.NewFileAnnotation(nil, 0, 0, 0, 0, "COMPILE", "failed", ""),
+	}
+	require.Equal(
+		t,
+		[]bufanalysis.FileAnnotation{
+			fileAnnotations[1],
+			fileAnnotations[2],
+			fileAnnotations[4],
+			fileAnnotations[6],
+			fileAnnotations[7],
+			fileAnnotations[9],
+			fileAnnotations[10],
+		},
+		FilterFileAnnotations(changedLines, fileAnnotations),
+	)
+}
+
+func TestFilterFileAnnotationsAllLinesChanged(t *testing.T) {
+	t.Parallel()
+	tempDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	modifiedFilePath := filepath.Join(tempDir, "modified.proto")
+	unchangedFilePath := filepath.Join(tempDir, "unchanged.proto")
+	for _, filePath := range []string{modifiedFilePath, unchangedFilePath} {
+		require.NoError(t, os.WriteFile(filePath, nil, 0600))
+	}
+	changedLines := newChangedLines(
+		[]*git.ChangedFile{
+			{
+				Path: modifiedFilePath,
+				LineRanges: []git.LineRange{
+					{StartLine: 5, EndLine: 6},
+				},
+			},
+		},
+		true,
+	)
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		newFileAnnotation(modifiedFilePath, 1, 3),
+		newFileAnnotation(modifiedFilePath, 5, 5),
+		newFileAnnotation(unchangedFilePath, 5, 5),
+		bufanalysis.NewFileAnnotation(nil, 0, 0, 0, 0, "COMPILE", "failed", ""),
+	}
+	require.Equal(
+		t,
+		[]bufanalysis.FileAnnotation{
+			fileAnnotations[0],
+			fileAnnotations[1],
+			fileAnnotations[3],
+		},
+		FilterFileAnnotations(changedLines, fileAnnotations),
+	)
+	require.True(t, changedLines.ContainsFile(modifiedFilePath))
+	require.False(t, changedLines.ContainsFile(unchangedFilePath))
+}
+
+func newFileAnnotation(path string, startLine int, endLine int) bufanalysis.FileAnnotation {
+	return bufanalysis.NewFileAnnotation(
+		&fileInfo{path: path},
+		startLine,
+		1,
+		endLine,
+		10,
+		"FIELD_LOWER_SNAKE_CASE",
+		`Field name "fooBar" should be lower_snake_case, such as "foo_bar".`,
+		"",
+	)
+}
+
+type fileInfo struct {
+	path string
+}
+
+func (f *fileInfo) Path() string {
+	return filepath.Base(f.path)
+}
+
+func (f *fileInfo) ExternalPath() string {
+	return f.path
+}
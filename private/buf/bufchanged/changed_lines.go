@@ -0,0 +1,103 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufchanged
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/app"
+	"github.com/bufbuild/buf/private/pkg/git"
+)
+
+type changedLines struct {
+	pathToChangedFile map[string]*git.ChangedFile
+	// allLinesChanged says that all lines of the changed files are changed.
+	allLinesChanged bool
+}
+
+func newChangedLinesSinceRef(
+	ctx context.Context,
+	envContainer app.EnvContainer,
+	dirPath string,
+	ref string,
+	allLinesChanged bool,
+) (*changedLines, error) {
+	changedFiles, err := git.GetChangedFilesSinceRef(ctx, envContainer, dirPath, ref)
+	if err != nil {
+		return nil, err
+	}
+	return newChangedLines(changedFiles, allLinesChanged), nil
+}
+
+func newChangedLines(changedFiles []*git.ChangedFile, allLinesChanged bool) *changedLines {
+	pathToChangedFile := make(map[string]*git.ChangedFile, len(changedFiles))
+	for _, changedFile := range changedFiles {
+		pathToChangedFile[changedFile.Path] = changedFile
+	}
+	return &changedLines{
+		pathToChangedFile: pathToChangedFile,
+		allLinesChanged:   allLinesChanged,
+	}
+}
+
+func (c *changedLines) ContainsFileAnnotation(fileAnnotation bufanalysis.FileAnnotation) bool {
+	fileInfo := fileAnnotation.FileInfo()
+	if fileInfo == nil {
+		return true
+	}
+	path := getAbsPath(fileInfo.ExternalPath())
+	changedFile, ok := c.pathToChangedFile[path]
+	if !ok {
+		// Files that do not exist cannot be mapped to the working tree.
+		_, err := os.Stat(path)
+		return errors.Is(err, fs.ErrNotExist)
+	}
+	startLine := fileAnnotation.StartLine()
+	if c.allLinesChanged || startLine == 0 {
+		return true
+	}
+	endLine := max(fileAnnotation.EndLine(), startLine)
+	for line := startLine; line <= endLine; line++ {
+		if changedFile.ContainsLine(line) {
+			return true
+		}
+	}
+	return changedFile.ContainsDeletionWithin(startLine, endLine)
+}
+
+func (c *changedLines) ContainsFile(path string) bool {
+	_, ok := c.pathToChangedFile[getAbsPath(path)]
+	return ok
+}
+
+func (*changedLines) isChangedLines() {}
+
+// getAbsPath returns the absolute path with symlinks evaluated, so that it can be compared
+// to the paths returned by git, or the absolute path if symlinks cannot be evaluated.
+func getAbsPath(path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if evaluatedPath, err := filepath.EvalSymlinks(absPath); err == nil {
+		return evaluatedPath
+	}
+	return absPath
+}
@@ -14,6 +14,6 @@
 
 // Generated. DO NOT EDIT.
 
-package protogenutil
+package bufchanged
 
 import _ "github.com/bufbuild/buf/private/usage"
@@ -0,0 +1,70 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcli
+
+import (
+	"context"
+	"io"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufbaseline"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/spf13/pflag"
+)
+
+// BindBaseline binds the baseline flag.
+func BindBaseline(flagSet *pflag.FlagSet, addr *string, flagName string) {
+	flagSet.StringVar(
+		addr,
+		flagName,
+		"",
+		`The baseline file of violations to not report. Defaults to `+bufbaseline.DefaultFileName+` in the directory of the workspace or module of the input if it exists`,
+	)
+}
+
+// ReadBaseline reads the baseline at the given path.
+//
+// If path is empty, the baseline is read from bufbaseline.DefaultFileName in the
+// workspace directory of the input, and nil is returned if this file does not exist.
+func ReadBaseline(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	path string,
+) (bufbaseline.Baseline, error) {
+	return readWorkspaceFile(ctx, container, input, path, bufbaseline.DefaultFileName, bufbaseline.ReadBaseline)
+}
+
+// WriteBaseline writes the baseline to the given path.
+//
+// If path is empty, the baseline is written to bufbaseline.DefaultFileName in the
+// workspace directory of the input.
+func WriteBaseline(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	path string,
+	baseline bufbaseline.Baseline,
+) error {
+	return writeWorkspaceFile(
+		ctx,
+		container,
+		input,
+		path,
+		bufbaseline.DefaultFileName,
+		func(writer io.Writer) error {
+			return bufbaseline.WriteBaseline(writer, baseline)
+		},
+	)
+}
@@ -0,0 +1,70 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcli
+
+import (
+	"context"
+	"io"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufbreakingexception"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/spf13/pflag"
+)
+
+// BindBreakingExceptions binds the breaking exceptions flag.
+func BindBreakingExceptions(flagSet *pflag.FlagSet, addr *string, flagName string) {
+	flagSet.StringVar(
+		addr,
+		flagName,
+		"",
+		`The file of approved breaking changes to not report. Defaults to `+bufbreakingexception.DefaultFileName+` in the directory of the workspace or module of the input if it exists`,
+	)
+}
+
+// ReadBreakingExceptions reads the breaking exceptions at the given path.
+//
+// If path is empty, the breaking exceptions are read from bufbreakingexception.DefaultFileName
+// in the workspace directory of the input, and nil is returned if this file does not exist.
+func ReadBreakingExceptions(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	path string,
+) (bufbreakingexception.ExceptionSet, error) {
+	return readWorkspaceFile(ctx, container, input, path, bufbreakingexception.DefaultFileName, bufbreakingexception.ReadExceptionSet)
+}
+
+// WriteBreakingExceptions writes the breaking exceptions to the given path.
+//
+// If path is empty, the breaking exceptions are written to bufbreakingexception.DefaultFileName
+// in the workspace directory of the input.
+func WriteBreakingExceptions(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	path string,
+	exceptionSet bufbreakingexception.ExceptionSet,
+) error {
+	return writeWorkspaceFile(
+		ctx,
+		container,
+		input,
+		path,
+		bufbreakingexception.DefaultFileName,
+		func(writer io.Writer) error {
+			return bufbreakingexception.WriteExceptionSet(writer, exceptionSet)
+		},
+	)
+}
@@ -20,11 +20,15 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/bufbuild/buf/private/buf/bufapp"
+	"github.com/bufbuild/buf/private/buf/bufctl"
 	"github.com/bufbuild/buf/private/buf/bufwkt/bufwktstore"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduleapi"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmodulecache"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmodulemapping"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmodulestore"
 	"github.com/bufbuild/buf/private/bufpkg/bufplugin"
 	"github.com/bufbuild/buf/private/bufpkg/bufplugin/bufpluginapi"
@@ -36,6 +40,9 @@ import (
 	"github.com/bufbuild/buf/private/pkg/app/appext"
 	"github.com/bufbuild/buf/private/pkg/filelock"
 	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+	"github.com/bufbuild/buf/private/pkg/slogext"
+	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
 )
 
@@ -55,9 +62,13 @@ var (
 		v1beta1CacheModuleLockRelDirPath,
 		v2CacheModuleRelDirPath,
 		v3CacheCommitsRelDirPath,
+		v3CacheInteractiveRelDirPath,
+		v3CachePerfRelDirPath,
+		v3CacheLintRelDirPath,
 		v3CacheModuleLockRelDirPath,
 		v3CacheModuleRelDirPath,
 		v3CachePluginRelDirPath,
+		v3CacheRemotePluginResponseRelDirPath,
 		v3CacheWKTRelDirPath,
 		v3CacheWasmRuntimeRelDirPath,
 	}
@@ -120,6 +131,26 @@ var (
 	//
 	// Normalized.
 	v3CacheWasmRuntimeRelDirPath = normalpath.Join("v3", "wasmruntime")
+	// v3CacheRemotePluginResponseRelDirPath is the relative path to the cache directory for the
+	// responses of remote plugins executed by buf generate.
+	//
+	// Normalized.
+	v3CacheRemotePluginResponseRelDirPath = normalpath.Join("v3", "remotepluginresponses")
+	// v3CacheLintRelDirPath is the relative path to the cache directory for the results of
+	// buf lint.
+	//
+	// Normalized.
+	v3CacheLintRelDirPath = normalpath.Join("v3", "lint")
+	// v3CacheInteractiveRelDirPath is the relative path to the cache directory for the
+	// recently used inputs of buf beta interactive.
+	//
+	// Normalized.
+	v3CacheInteractiveRelDirPath = normalpath.Join("v3", "interactive")
+	// v3CachePerfRelDirPath is the relative path to the cache directory for the
+	// local performance statistics of commands.
+	//
+	// Normalized.
+	v3CachePerfRelDirPath = normalpath.Join("v3", "perf")
 )
 
 // NewModuleDataProvider returns a new ModuleDataProvider while creating the
@@ -129,6 +160,10 @@ func NewModuleDataProvider(container appext.Container) (bufmodule.ModuleDataProv
 	if err != nil {
 		return nil, err
 	}
+	moduleMapper, err := newModuleMapper(container)
+	if err != nil {
+		return nil, err
+	}
 	return newModuleDataProvider(
 		container,
 		bufregistryapimodule.NewClientProvider(
@@ -137,6 +172,7 @@ func NewModuleDataProvider(container appext.Container) (bufmodule.ModuleDataProv
 		bufregistryapiowner.NewClientProvider(
 			clientConfig,
 		),
+		moduleMapper,
 	)
 }
 
@@ -147,6 +183,10 @@ func NewCommitProvider(container appext.Container) (bufmodule.CommitProvider, er
 	if err != nil {
 		return nil, err
 	}
+	moduleMapper, err := newModuleMapper(container)
+	if err != nil {
+		return nil, err
+	}
 	return newCommitProvider(
 		container,
 		bufregistryapimodule.NewClientProvider(
@@ -155,13 +195,14 @@ func NewCommitProvider(container appext.Container) (bufmodule.CommitProvider, er
 		bufregistryapiowner.NewClientProvider(
 			clientConfig,
 		),
+		moduleMapper,
 	)
 }
 
 // NewPluginDataProvider returns a new PluginDataProvider while creating the
 // required cache directories.
 func NewPluginDataProvider(container appext.Container) (bufplugin.PluginDataProvider, error) {
-	clientConfig, err := NewConnectClientConfig(container)
+	clientConfig, err := NewPluginConnectClientConfig(container)
 	if err != nil {
 		return nil, err
 	}
@@ -186,6 +227,81 @@ func CreateWasmRuntimeCacheDir(container appext.Container) (string, error) {
 	return fullCacheDirPath, nil
 }
 
+// NewRemotePluginResponseCacheBucket returns a new storage.ReadWriteBucket for caching the
+// responses of remote plugins while creating the required cache directories.
+func NewRemotePluginResponseCacheBucket(container appext.Container) (storage.ReadWriteBucket, error) {
+	if err := createCacheDir(container.CacheDirPath(), v3CacheRemotePluginResponseRelDirPath); err != nil {
+		return nil, err
+	}
+	fullCacheDirPath := normalpath.Join(container.CacheDirPath(), v3CacheRemotePluginResponseRelDirPath)
+	// No symlinks.
+	return storageos.NewProvider().NewReadWriteBucket(fullCacheDirPath)
+}
+
+// NewLintCacheBucket returns a new storage.ReadWriteBucket for caching the results of
+// linting while creating the required cache directories.
+func NewLintCacheBucket(container appext.Container) (storage.ReadWriteBucket, error) {
+	if err := createCacheDir(container.CacheDirPath(), v3CacheLintRelDirPath); err != nil {
+		return nil, err
+	}
+	fullCacheDirPath := normalpath.Join(container.CacheDirPath(), v3CacheLintRelDirPath)
+	// No symlinks.
+	return storageos.NewProvider().NewReadWriteBucket(fullCacheDirPath)
+}
+
+// NewInteractiveCacheBucket returns a new storage.ReadWriteBucket for the recently used
+// inputs of buf beta interactive while creating the required cache directories.
+func NewInteractiveCacheBucket(container appext.Container) (storage.ReadWriteBucket, error) {
+	if err := createCacheDir(container.CacheDirPath(), v3CacheInteractiveRelDirPath); err != nil {
+		return nil, err
+	}
+	fullCacheDirPath := normalpath.Join(container.CacheDirPath(), v3CacheInteractiveRelDirPath)
+	// No symlinks.
+	return storageos.NewProvider().NewReadWriteBucket(fullCacheDirPath)
+}
+
+// NewPerfCacheBucket returns a new storage.ReadWriteBucket for the local performance
+// statistics of commands while creating the required cache directories.
+func NewPerfCacheBucket(container appext.Container) (storage.ReadWriteBucket, error) {
+	if err := createCacheDir(container.CacheDirPath(), v3CachePerfRelDirPath); err != nil {
+		return nil, err
+	}
+	fullCacheDirPath := normalpath.Join(container.CacheDirPath(), v3CachePerfRelDirPath)
+	// No symlinks.
+	return storageos.NewProvider().NewReadWriteBucket(fullCacheDirPath)
+}
+
+// GetCachedModuleFullNames returns the sorted names of the modules that have commits in the
+// module cache.
+func GetCachedModuleFullNames(container appext.Container) ([]string, error) {
+	fullCacheDirPath := normalpath.Unnormalize(normalpath.Join(container.CacheDirPath(), v3CacheModuleRelDirPath))
+	// The module cache stores each commit at digestType/registry/owner/name/commit,
+	// see bufmodulestore.
+	dirPaths, err := filepath.Glob(filepath.Join(fullCacheDirPath, "*", "*", "*", "*"))
+	if err != nil {
+		return nil, err
+	}
+	moduleFullNames := make(map[string]struct{})
+	for _, dirPath := range dirPaths {
+		// OK to use os.Stat instead of os.LStat here as this is CLI-only
+		fileInfo, err := os.Stat(dirPath)
+		if err != nil {
+			return nil, err
+		}
+		if !fileInfo.IsDir() {
+			continue
+		}
+		relDirPath, err := filepath.Rel(fullCacheDirPath, dirPath)
+		if err != nil {
+			return nil, err
+		}
+		// Strip the digest type.
+		_, moduleFullName, _ := strings.Cut(normalpath.Normalize(relDirPath), "/")
+		moduleFullNames[moduleFullName] = struct{}{}
+	}
+	return slicesext.MapKeysToSortedSlice(moduleFullNames), nil
+}
+
 // NewWKTStore returns a new bufwktstore.Store while creating the required cache directories.
 func NewWKTStore(container appext.Container) (bufwktstore.Store, error) {
 	if err := createCacheDir(container.CacheDirPath(), v3CacheWKTRelDirPath); err != nil {
@@ -199,22 +315,27 @@ func NewWKTStore(container appext.Container) (bufwktstore.Store, error) {
 		return nil, err
 	}
 	return bufwktstore.NewStore(
-		container.Logger(),
+		slogext.WithComponent(container.Logger(), bufctl.LogComponentCache),
 		cacheBucket,
 	), nil
 }
 
+// newModuleDataProvider returns a new ModuleDataProvider.
+//
+// The cache stores modules by their names on the registry, so that the cache is not
+// affected by changes to the module mappings.
 func newModuleDataProvider(
 	container appext.Container,
 	moduleClientProvider bufregistryapimodule.ClientProvider,
 	ownerClientProvider bufregistryapiowner.ClientProvider,
+	moduleMapper bufmodulemapping.Mapper,
 ) (bufmodule.ModuleDataProvider, error) {
 	if err := createCacheDir(container.CacheDirPath(), v3CacheModuleRelDirPath); err != nil {
 		return nil, err
 	}
 	fullCacheDirPath := normalpath.Join(container.CacheDirPath(), v3CacheModuleRelDirPath)
 	delegateModuleDataProvider := bufmoduleapi.NewModuleDataProvider(
-		container.Logger(),
+		slogext.WithComponent(container.Logger(), bufctl.LogComponentFetch),
 		moduleClientProvider,
 		newGraphProvider(container, moduleClientProvider, ownerClientProvider),
 	)
@@ -231,47 +352,72 @@ func newModuleDataProvider(
 	if err != nil {
 		return nil, err
 	}
-	return bufmodulecache.NewModuleDataProvider(
-		container.Logger(),
+	var moduleDataProvider bufmodule.ModuleDataProvider = bufmodulecache.NewModuleDataProvider(
+		slogext.WithComponent(container.Logger(), bufctl.LogComponentCache),
 		delegateModuleDataProvider,
 		bufmodulestore.NewModuleDataStore(
-			container.Logger(),
+			slogext.WithComponent(container.Logger(), bufctl.LogComponentCache),
 			cacheBucket,
 			filelocker,
 		),
-	), nil
+	)
+	if moduleMapper != nil {
+		moduleDataProvider = bufmodulemapping.NewModuleDataProvider(moduleDataProvider, moduleMapper)
+	}
+	return moduleDataProvider, nil
 }
 
+// newCommitProvider returns a new CommitProvider.
+//
+// Like newModuleDataProvider, the cache stores commits by the names of their modules
+// on the registry.
 func newCommitProvider(
 	container appext.Container,
 	moduleClientProvider bufregistryapimodule.ClientProvider,
 	ownerClientProvider bufregistryapiowner.ClientProvider,
+	moduleMapper bufmodulemapping.Mapper,
 ) (bufmodule.CommitProvider, error) {
 	if err := createCacheDir(container.CacheDirPath(), v3CacheCommitsRelDirPath); err != nil {
 		return nil, err
 	}
 	fullCacheDirPath := normalpath.Join(container.CacheDirPath(), v3CacheCommitsRelDirPath)
-	delegateReader := bufmoduleapi.NewCommitProvider(container.Logger(), moduleClientProvider, ownerClientProvider)
+	delegateReader := bufmoduleapi.NewCommitProvider(slogext.WithComponent(container.Logger(), bufctl.LogComponentFetch), moduleClientProvider, ownerClientProvider)
 	// No symlinks.
 	storageosProvider := storageos.NewProvider()
 	cacheBucket, err := storageosProvider.NewReadWriteBucket(fullCacheDirPath)
 	if err != nil {
 		return nil, err
 	}
-	return bufmodulecache.NewCommitProvider(
-		container.Logger(),
+	var commitProvider bufmodule.CommitProvider = bufmodulecache.NewCommitProvider(
+		slogext.WithComponent(container.Logger(), bufctl.LogComponentCache),
 		delegateReader,
 		bufmodulestore.NewCommitStore(
-			container.Logger(),
+			slogext.WithComponent(container.Logger(), bufctl.LogComponentCache),
 			cacheBucket,
 		),
-	), nil
+	)
+	if moduleMapper != nil {
+		commitProvider = bufmodulemapping.NewCommitProvider(commitProvider, moduleMapper)
+	}
+	return commitProvider, nil
 }
 
 func newPluginDataProvider(
 	container appext.Container,
 	pluginClientProvider bufregistryapiplugin.ClientProvider,
 ) (bufplugin.PluginDataProvider, error) {
+	config, err := newConfig(container)
+	if err != nil {
+		return nil, err
+	}
+	if config.PluginChecksumPolicy == bufapp.PluginChecksumPolicyWarn {
+		// Plugins that may not match their digest are never cached.
+		return bufpluginapi.NewPluginDataProvider(
+			slogext.WithComponent(container.Logger(), bufctl.LogComponentFetch),
+			pluginClientProvider,
+			bufpluginapi.PluginDataProviderWithWarnOnDigestMismatch(),
+		), nil
+	}
 	if err := createCacheDir(container.CacheDirPath(), v3CachePluginRelDirPath); err != nil {
 		return nil, err
 	}
@@ -282,14 +428,14 @@ func newPluginDataProvider(
 		return nil, err
 	}
 	delegateModuleDataProvider := bufpluginapi.NewPluginDataProvider(
-		container.Logger(),
+		slogext.WithComponent(container.Logger(), bufctl.LogComponentFetch),
 		pluginClientProvider,
 	)
 	return bufplugincache.NewPluginDataProvider(
-		container.Logger(),
+		slogext.WithComponent(container.Logger(), bufctl.LogComponentCache),
 		delegateModuleDataProvider,
 		bufpluginstore.NewPluginDataStore(
-			container.Logger(),
+			slogext.WithComponent(container.Logger(), bufctl.LogComponentCache),
 			cacheBucket,
 		),
 	), nil
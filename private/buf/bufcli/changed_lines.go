@@ -0,0 +1,114 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/bufbuild/buf/private/buf/bufchanged"
+	"github.com/bufbuild/buf/private/buf/buffetch"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/spf13/pflag"
+)
+
+// BindOnlyChangedLines binds the only-changed-lines flag.
+func BindOnlyChangedLines(flagSet *pflag.FlagSet, addr *string, flagName string) {
+	flagSet.StringVar(
+		addr,
+		flagName,
+		"",
+		`Only report violations on the lines changed since the git ref, such as origin/main. The input must be a directory or proto file in a git repository`,
+	)
+}
+
+// BindOnlyChangedFiles binds the only-changed-files flag.
+func BindOnlyChangedFiles(flagSet *pflag.FlagSet, addr *string, flagName string) {
+	flagSet.StringVar(
+		addr,
+		flagName,
+		"",
+		`Only report violations in the files changed since the git ref, such as origin/main. The input must be a directory or proto file in a git repository`,
+	)
+}
+
+// NewChangedLinesForInput returns the lines changed since the git ref in the git
+// repository containing the input.
+//
+// The input must be a directory or proto file.
+func NewChangedLinesForInput(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	ref string,
+	flagName string,
+) (bufchanged.ChangedLines, error) {
+	dirPath, err := getChangedDirPathForInput(ctx, container, input, flagName)
+	if err != nil {
+		return nil, err
+	}
+	return bufchanged.NewChangedLinesSinceRef(ctx, container, dirPath, ref)
+}
+
+// NewChangedFilesForInput returns the lines changed since the git ref in the git
+// repository containing the input, where all lines of changed files are changed.
+//
+// The input must be a directory or proto file.
+func NewChangedFilesForInput(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	ref string,
+	flagName string,
+) (bufchanged.ChangedLines, error) {
+	dirPath, err := getChangedDirPathForInput(ctx, container, input, flagName)
+	if err != nil {
+		return nil, err
+	}
+	return bufchanged.NewChangedFilesSinceRef(ctx, container, dirPath, ref)
+}
+
+// *** PRIVATE ***
+
+func getChangedDirPathForInput(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	flagName string,
+) (string, error) {
+	dirOrProtoFileRef, err := buffetch.NewDirOrProtoFileRefParser(container.Logger()).GetDirOrProtoFileRef(ctx, input)
+	if err != nil {
+		if errors.Is(err, buffetch.ErrModuleFormatDetectedForDirOrProtoFileRef) {
+			return "", appcmd.NewInvalidArgumentErrorf("invalid input %q when using --%s: must be a directory or proto file", input, flagName)
+		}
+		return "", appcmd.NewInvalidArgumentErrorf("invalid input %q when using --%s: %v", input, flagName, err)
+	}
+	var dirPath string
+	switch t := dirOrProtoFileRef.(type) {
+	case buffetch.DirRef:
+		dirPath = t.DirPath()
+	case buffetch.ProtoFileRef:
+		if t.IsDevPath() {
+			return "", appcmd.NewInvalidArgumentErrorf("invalid input %q when using --%s: must be a directory or proto file", input, flagName)
+		}
+		dirPath = filepath.Dir(t.ProtoFilePath())
+	default:
+		return "", fmt.Errorf("unknown DirOrProtoFileRef: %T", dirOrProtoFileRef)
+	}
+	return dirPath, nil
+}
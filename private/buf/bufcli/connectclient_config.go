@@ -15,6 +15,8 @@
 package bufcli
 
 import (
+	"strings"
+
 	"connectrpc.com/connect"
 	otelconnect "connectrpc.com/otelconnect"
 	"github.com/bufbuild/buf/private/buf/bufapp"
@@ -30,16 +32,29 @@ import (
 // up the token in the container or in netrc based on the address of each individual client.
 // It is then set in the header of all outgoing requests from clients created using this config.
 func NewConnectClientConfig(container appext.Container) (*connectclient.Config, error) {
-	envTokenProvider, err := bufconnect.NewTokenProviderFromContainer(container)
+	return newConnectClientConfig(container)
+}
+
+// NewPluginConnectClientConfig creates a new connect.ClientConfig for the plugin services
+// of the BSR, used to resolve, download, and run remote plugins.
+//
+// If a plugin mirror is configured, all requests from clients created using this config
+// are sent to the mirror instead of the registry of the plugin. The token for the registry
+// of the plugin is still used.
+func NewPluginConnectClientConfig(container appext.Container) (*connectclient.Config, error) {
+	config, err := newConfig(container)
 	if err != nil {
 		return nil, err
 	}
-	netrcTokenProvider := bufconnect.NewNetrcTokenProvider(container, netrc.GetMachineForName)
-	return newConnectClientConfigWithOptions(
+	if config.PluginMirror == "" {
+		return newConnectClientConfig(container)
+	}
+	pluginMirror := strings.TrimSuffix(config.PluginMirror, "/")
+	return newConnectClientConfig(
 		container,
-		connectclient.WithAuthInterceptorProvider(
-			bufconnect.NewAuthorizationInterceptorProvider(envTokenProvider, netrcTokenProvider),
-		),
+		connectclient.WithAddressMapper(func(string) string {
+			return pluginMirror
+		}),
 	)
 }
 
@@ -58,6 +73,25 @@ func NewConnectClientConfigWithToken(container appext.Container, token string) (
 	)
 }
 
+func newConnectClientConfig(container appext.Container, opts ...connectclient.ConfigOption) (*connectclient.Config, error) {
+	envTokenProvider, err := bufconnect.NewTokenProviderFromContainer(container)
+	if err != nil {
+		return nil, err
+	}
+	netrcTokenProvider := bufconnect.NewNetrcTokenProvider(container, netrc.GetMachineForName)
+	return newConnectClientConfigWithOptions(
+		container,
+		append(
+			[]connectclient.ConfigOption{
+				connectclient.WithAuthInterceptorProvider(
+					bufconnect.NewAuthorizationInterceptorProvider(envTokenProvider, netrcTokenProvider),
+				),
+			},
+			opts...,
+		)...,
+	)
+}
+
 // Returns a registry provider with the given options applied in addition to default ones for all providers
 func newConnectClientConfigWithOptions(container appext.Container, opts ...connectclient.ConfigOption) (*connectclient.Config, error) {
 	config, err := newConfig(container)
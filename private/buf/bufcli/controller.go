@@ -16,12 +16,12 @@ package bufcli
 
 import (
 	"github.com/bufbuild/buf/private/buf/bufctl"
-	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduleapi"
 	"github.com/bufbuild/buf/private/bufpkg/bufplugin/bufpluginapi"
 	"github.com/bufbuild/buf/private/bufpkg/bufregistryapi/bufregistryapimodule"
 	"github.com/bufbuild/buf/private/bufpkg/bufregistryapi/bufregistryapiowner"
 	"github.com/bufbuild/buf/private/bufpkg/bufregistryapi/bufregistryapiplugin"
 	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/slogext"
 )
 
 // NewController returns a new Controller.
@@ -35,18 +35,31 @@ func NewController(
 			bufctl.WithCopyToInMemory(),
 		)
 	}
+	archiveLimitOptions, err := getArchiveLimitControllerOptions(container)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, archiveLimitOptions...)
 	clientConfig, err := NewConnectClientConfig(container)
 	if err != nil {
 		return nil, err
 	}
 	moduleClientProvider := bufregistryapimodule.NewClientProvider(clientConfig)
 	ownerClientProvider := bufregistryapiowner.NewClientProvider(clientConfig)
-	pluginClientProvider := bufregistryapiplugin.NewClientProvider(clientConfig)
-	moduleDataProvider, err := newModuleDataProvider(container, moduleClientProvider, ownerClientProvider)
+	pluginClientConfig, err := NewPluginConnectClientConfig(container)
+	if err != nil {
+		return nil, err
+	}
+	pluginClientProvider := bufregistryapiplugin.NewClientProvider(pluginClientConfig)
+	moduleMapper, err := newModuleMapper(container)
+	if err != nil {
+		return nil, err
+	}
+	moduleDataProvider, err := newModuleDataProvider(container, moduleClientProvider, ownerClientProvider, moduleMapper)
 	if err != nil {
 		return nil, err
 	}
-	commitProvider, err := newCommitProvider(container, moduleClientProvider, ownerClientProvider)
+	commitProvider, err := newCommitProvider(container, moduleClientProvider, ownerClientProvider, moduleMapper)
 	if err != nil {
 		return nil, err
 	}
@@ -61,11 +74,11 @@ func NewController(
 	return bufctl.NewController(
 		container.Logger(),
 		container,
-		newGraphProvider(container, moduleClientProvider, ownerClientProvider),
-		bufmoduleapi.NewModuleKeyProvider(container.Logger(), moduleClientProvider),
+		newMappedGraphProvider(container, moduleClientProvider, ownerClientProvider, moduleMapper),
+		newModuleKeyProvider(container, moduleClientProvider, moduleMapper),
 		moduleDataProvider,
 		commitProvider,
-		bufpluginapi.NewPluginKeyProvider(container.Logger(), pluginClientProvider),
+		bufpluginapi.NewPluginKeyProvider(slogext.WithComponent(container.Logger(), bufctl.LogComponentFetch), pluginClientProvider),
 		pluginDataProvider,
 		wktStore,
 		// TODO FUTURE: Delete defaultHTTPClient and use the one from newConfig
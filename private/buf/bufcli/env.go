@@ -16,8 +16,13 @@ package bufcli
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/pkg/app"
 	"github.com/bufbuild/buf/private/pkg/app/appext"
 	"github.com/bufbuild/buf/private/pkg/git"
 	"github.com/bufbuild/buf/private/pkg/httpauth"
@@ -29,9 +34,21 @@ const (
 	inputSSHKeyFileEnvKey         = "BUF_INPUT_SSH_KEY_FILE"
 	inputSSHKnownHostsFilesEnvKey = "BUF_INPUT_SSH_KNOWN_HOSTS_FILES"
 
+	inputArchiveMaxSizeEnvKey      = "BUF_INPUT_ARCHIVE_MAX_SIZE"
+	inputArchiveMaxFilesEnvKey     = "BUF_INPUT_ARCHIVE_MAX_FILES"
+	inputArchiveMaxPathDepthEnvKey = "BUF_INPUT_ARCHIVE_MAX_PATH_DEPTH"
+
 	alphaSuppressWarningsEnvKey = "BUF_ALPHA_SUPPRESS_WARNINGS"
 	betaSuppressWarningsEnvKey  = "BUF_BETA_SUPPRESS_WARNINGS"
 
+	perfStatsEnvKey = "BUF_BETA_PERF_STATS"
+
+	crashDirEnvKey = "BUF_CRASH_DIR"
+
+	githubServerURLEnvKey  = "GITHUB_SERVER_URL"
+	githubRepositoryEnvKey = "GITHUB_REPOSITORY"
+	githubSHAEnvKey        = "GITHUB_SHA"
+
 	// This is actually much slower with how it is currently implemented if you use --path.
 	// Example: Build a repo with 1000 .proto files, but filter to a single path. As this is
 	// implemented now, all 1000 .proto file are copied. You could get smarter with caching
@@ -84,3 +101,84 @@ func WarnBetaCommand(_ context.Context, container appext.Container) {
 		container.Logger().Warn("This command is in beta. It is unstable and likely to change. To suppress this warning, set " + betaSuppressWarningsEnvKey + "=1")
 	}
 }
+
+// IsPerfStatsEnabled returns true if the perfStatsEnvKey environment variable is set, in
+// which case the duration and cache statistics of commands are recorded in the cache
+// directory for buf beta perf report.
+func IsPerfStatsEnabled(container app.EnvContainer) bool {
+	return container.Env(perfStatsEnvKey) != ""
+}
+
+// PerfStatsEnvKey returns the environment variable that enables recording the duration
+// and cache statistics of commands.
+func PerfStatsEnvKey() string {
+	return perfStatsEnvKey
+}
+
+// GetCrashDirPath returns the value of the crashDirEnvKey environment variable, which is
+// the directory to write crash bundles to when a command panics.
+//
+// Returns empty if crash bundles should not be written.
+func GetCrashDirPath(container app.EnvContainer) string {
+	return container.Env(crashDirEnvKey)
+}
+
+// CrashDirEnvKey returns the environment variable that sets the directory to write crash
+// bundles to.
+func CrashDirEnvKey() string {
+	return crashDirEnvKey
+}
+
+// GetFileLinkBaseURL returns the base URL to link the locations of FileAnnotations to
+// when printing reports such as Markdown reports.
+//
+// This is only known when running in GitHub Actions, in which case locations are linked
+// to the files at the commit being checked. Returns empty otherwise.
+func GetFileLinkBaseURL(container app.EnvContainer) string {
+	serverURL := container.Env(githubServerURLEnvKey)
+	repository := container.Env(githubRepositoryEnvKey)
+	sha := container.Env(githubSHAEnvKey)
+	if serverURL == "" || repository == "" || sha == "" {
+		return ""
+	}
+	return strings.TrimSuffix(serverURL, "/") + "/" + repository + "/blob/" + sha
+}
+
+// getArchiveLimitControllerOptions returns the ControllerOptions for the limits on archive
+// inputs set by the inputArchiveMax*EnvKey environment variables.
+//
+// Archives are frequently downloaded from untrusted sources such as CI artifacts, so
+// archive inputs have limits by default. A value of 0 removes a limit.
+func getArchiveLimitControllerOptions(container app.EnvContainer) ([]bufctl.ControllerOption, error) {
+	var options []bufctl.ControllerOption
+	if value := container.Env(inputArchiveMaxSizeEnvKey); value != "" {
+		maxSize, err := parseNonNegativeEnvInt(inputArchiveMaxSizeEnvKey, value, 64)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, bufctl.WithMaxArchiveSize(maxSize))
+	}
+	if value := container.Env(inputArchiveMaxFilesEnvKey); value != "" {
+		maxFileCount, err := parseNonNegativeEnvInt(inputArchiveMaxFilesEnvKey, value, 32)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, bufctl.WithMaxArchiveFileCount(int(maxFileCount)))
+	}
+	if value := container.Env(inputArchiveMaxPathDepthEnvKey); value != "" {
+		maxPathDepth, err := parseNonNegativeEnvInt(inputArchiveMaxPathDepthEnvKey, value, 32)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, bufctl.WithMaxArchivePathDepth(int(maxPathDepth)))
+	}
+	return options, nil
+}
+
+func parseNonNegativeEnvInt(envKey string, value string, bitSize int) (int64, error) {
+	parsed, err := strconv.ParseInt(value, 10, bitSize)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("invalid value for %s, must be a non-negative integer: %q", envKey, value)
+	}
+	return parsed, nil
+}
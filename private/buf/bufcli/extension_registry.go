@@ -0,0 +1,46 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcli
+
+import (
+	"context"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufextension"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/spf13/pflag"
+)
+
+// BindExtensionRegistry binds the extension registry flag.
+func BindExtensionRegistry(flagSet *pflag.FlagSet, addr *string, flagName string) {
+	flagSet.StringVar(
+		addr,
+		flagName,
+		"",
+		`The extension registry file to use. Defaults to `+bufextension.DefaultFileName+` in the directory of the workspace or module of the input if it exists`,
+	)
+}
+
+// ReadExtensionRegistry reads the extension registry at the given path.
+//
+// If path is empty, the registry is read from bufextension.DefaultFileName in the
+// workspace directory of the input, and nil is returned if this file does not exist.
+func ReadExtensionRegistry(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	path string,
+) (bufextension.Registry, error) {
+	return readWorkspaceFile(ctx, container, input, path, bufextension.DefaultFileName, bufextension.ReadRegistry)
+}
@@ -143,6 +143,17 @@ By default, symlinks are followed in this CLI, but never followed on the Buf Sch
 	)
 }
 
+// BindDisableGitAttributes binds the disable-git-attributes flag.
+func BindDisableGitAttributes(flagSet *pflag.FlagSet, addr *bool, flagName string) {
+	flagSet.BoolVar(
+		addr,
+		flagName,
+		false,
+		`Do not apply .gitattributes files when reading git inputs
+By default, git inputs are read as git archive would export them, so that files and directories with the export-ignore attribute are excluded`,
+	)
+}
+
 // BindVisibility binds the visibility flag.
 func BindVisibility(flagSet *pflag.FlagSet, addr *string, flagName string, emptyDefault bool) {
 	defaultVisibility := privateVisibility
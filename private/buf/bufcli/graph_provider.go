@@ -15,11 +15,14 @@
 package bufcli
 
 import (
+	"github.com/bufbuild/buf/private/buf/bufctl"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduleapi"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmodulemapping"
 	"github.com/bufbuild/buf/private/bufpkg/bufregistryapi/bufregistryapimodule"
 	"github.com/bufbuild/buf/private/bufpkg/bufregistryapi/bufregistryapiowner"
 	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/slogext"
 )
 
 // NewGraphProvider returns a new GraphProvider.
@@ -28,20 +31,40 @@ func NewGraphProvider(container appext.Container) (bufmodule.GraphProvider, erro
 	if err != nil {
 		return nil, err
 	}
-	return newGraphProvider(
+	moduleMapper, err := newModuleMapper(container)
+	if err != nil {
+		return nil, err
+	}
+	return newMappedGraphProvider(
 		container,
 		bufregistryapimodule.NewClientProvider(clientConfig),
 		bufregistryapiowner.NewClientProvider(clientConfig),
+		moduleMapper,
 	), nil
 }
 
+// newMappedGraphProvider returns a new GraphProvider that maps the names of modules
+// with the Mapper, if the Mapper is not nil.
+func newMappedGraphProvider(
+	container appext.Container,
+	moduleClientProvider bufregistryapimodule.ClientProvider,
+	ownerClientProvider bufregistryapiowner.ClientProvider,
+	moduleMapper bufmodulemapping.Mapper,
+) bufmodule.GraphProvider {
+	graphProvider := newGraphProvider(container, moduleClientProvider, ownerClientProvider)
+	if moduleMapper != nil {
+		return bufmodulemapping.NewGraphProvider(graphProvider, moduleMapper)
+	}
+	return graphProvider
+}
+
 func newGraphProvider(
 	container appext.Container,
 	moduleClientProvider bufregistryapimodule.ClientProvider,
 	ownerClientProvider bufregistryapiowner.ClientProvider,
 ) bufmodule.GraphProvider {
 	return bufmoduleapi.NewGraphProvider(
-		container.Logger(),
+		slogext.WithComponent(container.Logger(), bufctl.LogComponentFetch),
 		moduleClientProvider,
 		ownerClientProvider,
 		// OK if empty
@@ -0,0 +1,119 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcli
+
+import (
+	"fmt"
+
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/spf13/pflag"
+)
+
+const (
+	emitDefaultsFlagName    = "emit-defaults"
+	useProtoNamesFlagName   = "use-proto-names"
+	emitEnumNumbersFlagName = "emit-enum-numbers"
+	indentFlagName          = "indent"
+	discardUnknownFlagName  = "discard-unknown"
+)
+
+// JSONOutputFlags are the flags that control how messages are printed as JSON.
+type JSONOutputFlags struct {
+	EmitDefaults    bool
+	UseProtoNames   bool
+	EmitEnumNumbers bool
+	Indent          int
+	DiscardUnknown  bool
+}
+
+// BindJSONOutputFlags binds the flags that control how messages are printed as JSON.
+//
+// defaultIndent is the default number of spaces to indent with.
+func BindJSONOutputFlags(flagSet *pflag.FlagSet, addr *JSONOutputFlags, defaultIndent int) {
+	flagSet.BoolVar(
+		&addr.EmitDefaults,
+		emitDefaultsFlagName,
+		false,
+		`Emit fields with default values for JSON-encoded messages`,
+	)
+	flagSet.BoolVar(
+		&addr.UseProtoNames,
+		useProtoNamesFlagName,
+		false,
+		`Use the field names as defined in the .proto file instead of the lowerCamelCase JSON names for JSON-encoded messages`,
+	)
+	flagSet.BoolVar(
+		&addr.EmitEnumNumbers,
+		emitEnumNumbersFlagName,
+		false,
+		`Emit enum values as numbers instead of names for JSON-encoded messages`,
+	)
+	flagSet.IntVar(
+		&addr.Indent,
+		indentFlagName,
+		defaultIndent,
+		`The number of spaces to indent JSON-encoded messages with. If zero, the output is compact`,
+	)
+	flagSet.BoolVar(
+		&addr.DiscardUnknown,
+		discardUnknownFlagName,
+		false,
+		`Discard unknown fields when reading binary-encoded messages instead of preserving them`,
+	)
+}
+
+// Validate validates the JSONOutputFlags.
+func (f *JSONOutputFlags) Validate() error {
+	if f.Indent < 0 {
+		return fmt.Errorf("--%s must not be negative", indentFlagName)
+	}
+	return nil
+}
+
+// JSONMarshalerOptions returns the protoencoding.JSONMarshalerOptions for the flags.
+func (f *JSONOutputFlags) JSONMarshalerOptions() []protoencoding.JSONMarshalerOption {
+	jsonMarshalerOptions := []protoencoding.JSONMarshalerOption{
+		protoencoding.JSONMarshalerWithIndentSpaces(f.Indent),
+	}
+	if f.EmitDefaults {
+		jsonMarshalerOptions = append(
+			jsonMarshalerOptions,
+			protoencoding.JSONMarshalerWithEmitUnpopulated(),
+		)
+	}
+	if f.UseProtoNames {
+		jsonMarshalerOptions = append(
+			jsonMarshalerOptions,
+			protoencoding.JSONMarshalerWithUseProtoNames(),
+		)
+	}
+	if f.EmitEnumNumbers {
+		jsonMarshalerOptions = append(
+			jsonMarshalerOptions,
+			protoencoding.JSONMarshalerWithUseEnumNumbers(),
+		)
+	}
+	return jsonMarshalerOptions
+}
+
+// WireUnmarshalerOptions returns the protoencoding.WireUnmarshalerOptions for the flags.
+func (f *JSONOutputFlags) WireUnmarshalerOptions() []protoencoding.WireUnmarshalerOption {
+	if f.DiscardUnknown {
+		return []protoencoding.WireUnmarshalerOption{
+			protoencoding.WireUnmarshalerWithDiscardUnknown(),
+		}
+	}
+	return nil
+}
@@ -15,10 +15,13 @@
 package bufcli
 
 import (
+	"github.com/bufbuild/buf/private/buf/bufctl"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduleapi"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmodulemapping"
 	"github.com/bufbuild/buf/private/bufpkg/bufregistryapi/bufregistryapimodule"
 	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/slogext"
 )
 
 // NewModuleKeyProvider returns a new ModuleKeyProvider.
@@ -27,10 +30,30 @@ func NewModuleKeyProvider(container appext.Container) (bufmodule.ModuleKeyProvid
 	if err != nil {
 		return nil, err
 	}
-	return bufmoduleapi.NewModuleKeyProvider(
-		container.Logger(),
+	moduleMapper, err := newModuleMapper(container)
+	if err != nil {
+		return nil, err
+	}
+	return newModuleKeyProvider(
+		container,
 		bufregistryapimodule.NewClientProvider(
 			clientConfig,
 		),
+		moduleMapper,
 	), nil
 }
+
+func newModuleKeyProvider(
+	container appext.Container,
+	moduleClientProvider bufregistryapimodule.ClientProvider,
+	moduleMapper bufmodulemapping.Mapper,
+) bufmodule.ModuleKeyProvider {
+	moduleKeyProvider := bufmoduleapi.NewModuleKeyProvider(
+		slogext.WithComponent(container.Logger(), bufctl.LogComponentFetch),
+		moduleClientProvider,
+	)
+	if moduleMapper != nil {
+		return bufmodulemapping.NewModuleKeyProvider(moduleKeyProvider, moduleMapper)
+	}
+	return moduleKeyProvider
+}
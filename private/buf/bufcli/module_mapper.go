@@ -0,0 +1,41 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcli
+
+import (
+	"os"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmodulemapping"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+)
+
+// newModuleMapper returns a new Mapper for the module mappings file of the configuration.
+//
+// Returns nil if no module mappings file is configured.
+func newModuleMapper(container appext.Container) (bufmodulemapping.Mapper, error) {
+	config, err := newConfig(container)
+	if err != nil {
+		return nil, err
+	}
+	if config.ModuleMappingsFilePath == "" {
+		return nil, nil
+	}
+	file, err := os.Open(config.ModuleMappingsFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return bufmodulemapping.ReadMapper(file)
+}
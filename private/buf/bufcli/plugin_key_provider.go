@@ -15,20 +15,22 @@
 package bufcli
 
 import (
+	"github.com/bufbuild/buf/private/buf/bufctl"
 	"github.com/bufbuild/buf/private/bufpkg/bufplugin"
 	"github.com/bufbuild/buf/private/bufpkg/bufplugin/bufpluginapi"
 	"github.com/bufbuild/buf/private/bufpkg/bufregistryapi/bufregistryapiplugin"
 	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/slogext"
 )
 
 // NewPluginKeyProvider returns a new PluginKeyProvider.
 func NewPluginKeyProvider(container appext.Container) (bufplugin.PluginKeyProvider, error) {
-	clientConfig, err := NewConnectClientConfig(container)
+	clientConfig, err := NewPluginConnectClientConfig(container)
 	if err != nil {
 		return nil, err
 	}
 	return bufpluginapi.NewPluginKeyProvider(
-		container.Logger(),
+		slogext.WithComponent(container.Logger(), bufctl.LogComponentFetch),
 		bufregistryapiplugin.NewClientProvider(
 			clientConfig,
 		),
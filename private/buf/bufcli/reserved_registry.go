@@ -0,0 +1,142 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufreserved"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/git"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// BindReservedRegistry binds the reserved registry flag.
+func BindReservedRegistry(flagSet *pflag.FlagSet, addr *string, flagName string) {
+	flagSet.StringVar(
+		addr,
+		flagName,
+		"",
+		`The reserved registry file to use. Defaults to `+bufreserved.DefaultFileName+` in the directory of the workspace or module of the input if it exists`,
+	)
+}
+
+// ReadReservedRegistry reads the reserved registry at the given path.
+//
+// If path is empty, the registry is read from bufreserved.DefaultFileName in the
+// workspace directory of the input, and nil is returned if this file does not exist.
+func ReadReservedRegistry(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	path string,
+) (bufreserved.Registry, error) {
+	return readWorkspaceFile(ctx, container, input, path, bufreserved.DefaultFileName, bufreserved.ReadRegistry)
+}
+
+// NewReservedRegistryForGitHistory returns a new reserved registry with the numbers and
+// names used by the .proto files within the directory of the input in every commit
+// reachable from HEAD of the git repository that contains the input.
+//
+// The input must be a directory or proto file. Files are read from the objects of the
+// repository and parsed individually, without their imports, so that commits that do
+// not build are still recorded. Files that cannot be parsed are skipped with a warning.
+func NewReservedRegistryForGitHistory(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	flagName string,
+) (_ bufreserved.Registry, retErr error) {
+	dirPath, err := getChangedDirPathForInput(ctx, container, input, flagName)
+	if err != nil {
+		return nil, err
+	}
+	dirPathInRepository, err := git.GetDirPathInRepository(ctx, container, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := git.GetCommitsForDir(ctx, container, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	objectReader, err := git.NewObjectReader(ctx, container, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, objectReader.Close())
+	}()
+	// The same version of a file is usually present in many commits, so we only parse
+	// each blob once.
+	hashToPath := make(map[string]string)
+	var hashes []string
+	for _, commit := range commits {
+		blobs, err := objectReader.ListBlobs(ctx, commit)
+		if err != nil {
+			return nil, err
+		}
+		for _, blob := range blobs {
+			if normalpath.Ext(blob.Path) != ".proto" ||
+				!normalpath.EqualsOrContainsPath(dirPathInRepository, blob.Path, normalpath.Relative) {
+				continue
+			}
+			if _, ok := hashToPath[blob.Hash]; !ok {
+				hashToPath[blob.Hash] = blob.Path
+				hashes = append(hashes, blob.Hash)
+			}
+		}
+	}
+	fileDescriptorProtos := make([]*descriptorpb.FileDescriptorProto, 0, len(hashes))
+	for _, hash := range hashes {
+		data, err := objectReader.ReadBlob(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		fileDescriptorProto, err := parseFileDescriptorProto(hashToPath[hash], data)
+		if err != nil {
+			container.Logger().Warn(
+				"skipping file in git history that could not be parsed",
+				slog.String("path", hashToPath[hash]),
+				slog.String("blob", hash),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		fileDescriptorProtos = append(fileDescriptorProtos, fileDescriptorProto)
+	}
+	return bufreserved.NewRegistryForFileDescriptorProtos(fileDescriptorProtos...), nil
+}
+
+// *** PRIVATE ***
+
+func parseFileDescriptorProto(path string, data []byte) (*descriptorpb.FileDescriptorProto, error) {
+	handler := reporter.NewHandler(nil)
+	fileNode, err := parser.Parse(path, bytes.NewReader(data), handler)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parser.ResultFromAST(fileNode, true, handler)
+	if err != nil {
+		return nil, err
+	}
+	return result.FileDescriptorProto(), nil
+}
@@ -17,8 +17,10 @@ package bufcli
 import (
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
 )
 
@@ -26,6 +28,7 @@ import (
 var AllRuleFormatStrings = []string{
 	"text",
 	"json",
+	"markdown",
 }
 
 // PrintRules prints the Rules to the writer given the --format and --include-deprecated flag values.
@@ -35,6 +38,8 @@ func PrintRules(writer io.Writer, rules []bufcheck.Rule, format string, includeD
 	case "", "text":
 	case "json":
 		printRulesOptions = append(printRulesOptions, bufcheck.PrintRulesWithJSON())
+	case "markdown":
+		printRulesOptions = append(printRulesOptions, bufcheck.PrintRulesWithMarkdown())
 	default:
 		return fmt.Errorf("unknown format: %q", s)
 	}
@@ -43,3 +48,45 @@ func PrintRules(writer io.Writer, rules []bufcheck.Rule, format string, includeD
 	}
 	return bufcheck.PrintRules(writer, rules, printRulesOptions...)
 }
+
+// RulesToRuleInfos returns the bufanalysis.RuleInfos for the Rules, for printing
+// FileAnnotations in formats that include rule metadata.
+//
+// The Severity of a RuleInfo is SeverityWarning if the ID or a category of the Rule is in
+// warnIDsAndCategories, or if failOnIDsAndCategories is non-empty and neither the ID nor a
+// category of the Rule is in failOnIDsAndCategories. Otherwise, it is SeverityError.
+func RulesToRuleInfos(
+	rules []bufcheck.Rule,
+	warnIDsAndCategories []string,
+	failOnIDsAndCategories []string,
+) []bufanalysis.RuleInfo {
+	ruleInfos := make([]bufanalysis.RuleInfo, len(rules))
+	for i, rule := range rules {
+		categories := rule.Categories()
+		categoryIDs := make([]string, len(categories))
+		for j, category := range categories {
+			categoryIDs[j] = category.ID()
+		}
+		severity := bufanalysis.SeverityError
+		if ruleHasIDOrCategory(rule.ID(), categoryIDs, warnIDsAndCategories) ||
+			(len(failOnIDsAndCategories) > 0 && !ruleHasIDOrCategory(rule.ID(), categoryIDs, failOnIDsAndCategories)) {
+			severity = bufanalysis.SeverityWarning
+		}
+		ruleInfos[i] = bufanalysis.RuleInfo{
+			ID:         rule.ID(),
+			Purpose:    rule.Purpose(),
+			Categories: categoryIDs,
+			Severity:   severity,
+		}
+	}
+	return ruleInfos
+}
+
+// *** PRIVATE ***
+
+func ruleHasIDOrCategory(ruleID string, categoryIDs []string, idsAndCategories []string) bool {
+	return slices.Contains(idsAndCategories, ruleID) ||
+		slices.ContainsFunc(categoryIDs, func(categoryID string) bool {
+			return slices.Contains(idsAndCategories, categoryID)
+		})
+}
@@ -17,6 +17,7 @@ package bufcli
 import (
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduleapi"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmodulemapping"
 	"github.com/bufbuild/buf/private/bufpkg/bufplugin"
 	"github.com/bufbuild/buf/private/bufpkg/bufplugin/bufpluginapi"
 	"github.com/bufbuild/buf/private/bufpkg/bufregistryapi/bufregistryapimodule"
@@ -30,7 +31,11 @@ func NewModuleUploader(container appext.Container) (bufmodule.Uploader, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newModuleUploader(container, bufregistryapimodule.NewClientProvider(clientConfig)), nil
+	moduleMapper, err := newModuleMapper(container)
+	if err != nil {
+		return nil, err
+	}
+	return newModuleUploader(container, bufregistryapimodule.NewClientProvider(clientConfig), moduleMapper), nil
 }
 
 // NewPluginUploader returns a new Uploader for Plugins.
@@ -45,12 +50,19 @@ func NewPluginUploader(container appext.Container) (bufplugin.Uploader, error) {
 func newModuleUploader(
 	container appext.Container,
 	clientProvider bufregistryapimodule.ClientProvider,
+	moduleMapper bufmodulemapping.Mapper,
 ) bufmodule.Uploader {
+	options := []bufmoduleapi.UploaderOption{
+		// OK if empty
+		bufmoduleapi.UploaderWithPublicRegistry(container.Env(publicRegistryEnvKey)),
+	}
+	if moduleMapper != nil {
+		options = append(options, bufmoduleapi.UploaderWithMapper(moduleMapper))
+	}
 	return bufmoduleapi.NewUploader(
 		container.Logger(),
 		clientProvider,
-		// OK if empty
-		bufmoduleapi.UploaderWithPublicRegistry(container.Env(publicRegistryEnvKey)),
+		options...,
 	)
 }
 
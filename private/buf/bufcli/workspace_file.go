@@ -0,0 +1,163 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/bufbuild/buf/private/buf/buffetch"
+	"github.com/bufbuild/buf/private/buf/buftarget"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+)
+
+// readWorkspaceFile reads the file at the given path with readFunc.
+//
+// If path is empty, the file is read from defaultFileName in the workspace directory
+// of the input, and the zero value is returned if this file does not exist. See
+// getWorkspaceFilePath for how the workspace directory is found.
+func readWorkspaceFile[T any](
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	path string,
+	defaultFileName string,
+	readFunc func(io.Reader) (T, error),
+) (T, error) {
+	var zero T
+	optional := path == ""
+	path, err := getWorkspaceFilePath(ctx, container, input, path, defaultFileName)
+	if err != nil {
+		return zero, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if optional && errors.Is(err, fs.ErrNotExist) {
+			return zero, nil
+		}
+		return zero, err
+	}
+	defer file.Close()
+	return readFunc(file)
+}
+
+// writeWorkspaceFile writes the file at the given path with writeFunc.
+//
+// If path is empty, the file is written to defaultFileName in the workspace directory
+// of the input. See getWorkspaceFilePath for how the workspace directory is found.
+func writeWorkspaceFile(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	path string,
+	defaultFileName string,
+	writeFunc func(io.Writer) error,
+) error {
+	path, err := getWorkspaceFilePath(ctx, container, input, path, defaultFileName)
+	if err != nil {
+		return err
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := writeFunc(buffer); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buffer.Bytes(), 0644)
+}
+
+// getWorkspaceFilePath returns the path if it is not empty, otherwise the path of
+// defaultFileName in the workspace directory of the input.
+//
+// The workspace directory is the directory of the buf.work.yaml or v2 buf.yaml that
+// controls the input. If no workspace controls the input, this is the directory of
+// the input, so that a file next to a v1 buf.yaml is used. Inputs that are not local
+// directories or proto files, such as archives and modules, use the current directory.
+func getWorkspaceFilePath(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	path string,
+	defaultFileName string,
+) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	dirPath, err := getWorkspaceDirPathForInput(ctx, container, input)
+	if err != nil {
+		return "", err
+	}
+	return normalpath.Unnormalize(normalpath.Join(dirPath, defaultFileName)), nil
+}
+
+func getWorkspaceDirPathForInput(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+) (string, error) {
+	ref, err := buffetch.NewRefParser(container.Logger()).GetRef(ctx, input)
+	if err != nil {
+		// The input is invalid, the error is reported when the input is built.
+		return ".", nil
+	}
+	var inputDirPath string
+	switch t := ref.(type) {
+	case buffetch.DirRef:
+		inputDirPath = t.DirPath()
+	case buffetch.ProtoFileRef:
+		if t.IsDevPath() {
+			return ".", nil
+		}
+		inputDirPath = filepath.Dir(t.ProtoFilePath())
+	default:
+		// Not a local directory or proto file, such as an image, archive, or module.
+		return ".", nil
+	}
+	absInputDirPath, err := normalpath.NormalizeAndAbsolute(inputDirPath)
+	if err != nil {
+		return "", err
+	}
+	// The controlling workspace may be in any parent directory of the input, so
+	// we search from the root of the file system, as buffetch does.
+	fsRoot := normalpath.Components(absInputDirPath)[0]
+	fsRootInputDirPath, err := normalpath.Rel(fsRoot, absInputDirPath)
+	if err != nil {
+		return "", err
+	}
+	fsRootBucket, err := newOSReadWriteBucketWithSymlinks(fsRoot)
+	if err != nil {
+		return "", err
+	}
+	bucketTargeting, err := buftarget.NewBucketTargeting(
+		ctx,
+		container.Logger(),
+		fsRootBucket,
+		fsRootInputDirPath,
+		nil,
+		nil,
+		buftarget.TerminateAtControllingWorkspace,
+	)
+	if err != nil {
+		return "", err
+	}
+	if controllingWorkspace := bucketTargeting.ControllingWorkspace(); controllingWorkspace != nil {
+		return normalpath.Join(fsRoot, controllingWorkspace.Path()), nil
+	}
+	return absInputDirPath, nil
+}
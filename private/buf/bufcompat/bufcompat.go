@@ -0,0 +1,98 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufcompat reports constructs that are known to be problematic or
+// inconsistent across the runtimes of different languages.
+package bufcompat
+
+import (
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+)
+
+const (
+	// TypeInt64JSON is the FileAnnotation type for fields with a 64-bit integer type.
+	//
+	// These are encoded as strings in JSON, and lose precision in languages such as
+	// JavaScript if they are parsed as numbers.
+	TypeInt64JSON = "INT64_JSON"
+	// TypeFieldNameCaseCollision is the FileAnnotation type for fields whose names
+	// collide with the name of another field of the same message after case conversion,
+	// such as foo_bar and fooBar.
+	//
+	// Code generators for many languages convert field names to camelCase or PascalCase,
+	// and produce code that does not compile for these fields.
+	TypeFieldNameCaseCollision = "FIELD_NAME_CASE_COLLISION"
+	// TypeLargeOneof is the FileAnnotation type for oneofs with more fields than the
+	// maximum set with ReportWithMaxOneofFields.
+	//
+	// Oneofs are generated as enums with associated values in Swift, and large enums
+	// are slow to compile and use a lot of stack space.
+	TypeLargeOneof = "LARGE_ONEOF"
+	// TypeGroup is the FileAnnotation type for fields that use group encoding, either
+	// proto2 groups or fields with the DELIMITED message encoding feature.
+	//
+	// Group encoding is deprecated, and is not supported consistently across runtimes.
+	TypeGroup = "GROUP"
+
+	// DefaultMaxOneofFields is the default maximum number of fields of a oneof.
+	DefaultMaxOneofFields = 50
+)
+
+// AllTypes are all FileAnnotation types that Report returns.
+var AllTypes = []string{
+	TypeInt64JSON,
+	TypeFieldNameCaseCollision,
+	TypeLargeOneof,
+	TypeGroup,
+}
+
+// Report returns FileAnnotations for the constructs in the non-import files of
+// the Image that are known to be problematic or inconsistent across the runtimes
+// of different languages.
+//
+// The FileAnnotations are ordered by file, and then by the order the constructs
+// are declared in. The type of each FileAnnotation is one of AllTypes.
+func Report(image bufimage.Image, options ...ReportOption) ([]bufanalysis.FileAnnotation, error) {
+	reportOptions := newReportOptions()
+	for _, option := range options {
+		option(reportOptions)
+	}
+	return report(image, reportOptions)
+}
+
+// ReportOption is an option for Report.
+type ReportOption func(*reportOptions)
+
+// ReportWithMaxOneofFields returns a new ReportOption that sets the maximum number
+// of fields of a oneof before it is reported as TypeLargeOneof.
+//
+// The default is DefaultMaxOneofFields.
+func ReportWithMaxOneofFields(maxOneofFields int) ReportOption {
+	return func(reportOptions *reportOptions) {
+		reportOptions.maxOneofFields = maxOneofFields
+	}
+}
+
+// *** PRIVATE ***
+
+type reportOptions struct {
+	maxOneofFields int
+}
+
+func newReportOptions() *reportOptions {
+	return &reportOptions{
+		maxOneofFields: DefaultMaxOneofFields,
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcompat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis/bufanalysistesting"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduletesting"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+	image := newTestImage(t)
+	fileAnnotations, err := Report(image, ReportWithMaxOneofFields(2))
+	require.NoError(t, err)
+	bufanalysistesting.AssertFileAnnotationsEqual(
+		t,
+		[]bufanalysis.FileAnnotation{
+			bufanalysistesting.NewFileAnnotation(t, "acme/v1/a.proto", 7, 3, 7, 25, TypeInt64JSON),
+			bufanalysistesting.NewFileAnnotation(t, "acme/v1/a.proto", 9, 3, 9, 35, TypeInt64JSON),
+			bufanalysistesting.NewFileAnnotation(t, "acme/v1/a.proto", 17, 3, 17, 32, TypeInt64JSON),
+			bufanalysistesting.NewFileAnnotation(t, "acme/v1/a.proto", 11, 3, 11, 25, TypeFieldNameCaseCollision),
+			bufanalysistesting.NewFileAnnotation(t, "acme/v1/a.proto", 12, 3, 16, 4, TypeLargeOneof),
+			bufanalysistesting.NewFileAnnotation(t, "acme/v1/a.proto", 19, 5, 19, 25, TypeInt64JSON),
+			bufanalysistesting.NewFileAnnotation(t, "acme/v1/a.proto", 21, 5, 21, 20, TypeFieldNameCaseCollision),
+			bufanalysistesting.NewFileAnnotation(t, "acme/v1/b.proto", 7, 3, 10, 4, TypeGroup),
+			bufanalysistesting.NewFileAnnotation(t, "acme/v1/b.proto", 9, 5, 9, 32, TypeInt64JSON),
+			bufanalysistesting.NewFileAnnotation(t, "acme/v1/b.proto", 16, 3, 16, 31, TypeInt64JSON),
+		},
+		fileAnnotations,
+	)
+}
+
+func TestReportDefaultMaxOneofFields(t *testing.T) {
+	t.Parallel()
+	image := newTestImage(t)
+	fileAnnotations, err := Report(image)
+	require.NoError(t, err)
+	for _, fileAnnotation := range fileAnnotations {
+		require.NotEqual(t, TypeLargeOneof, fileAnnotation.Type())
+	}
+}
+
+func newTestImage(t *testing.T) bufimage.Image {
+	moduleSet, err := bufmoduletesting.NewModuleSetForDirPath("testdata")
+	require.NoError(t, err)
+	image, err := bufimage.BuildImage(
+		context.Background(),
+		slogtestext.NewLogger(t),
+		bufmodule.ModuleSetToModuleReadBucketWithOnlyProtoFiles(moduleSet),
+	)
+	require.NoError(t, err)
+	return image
+}
@@ -0,0 +1,198 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcompat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func report(image bufimage.Image, reportOptions *reportOptions) ([]bufanalysis.FileAnnotation, error) {
+	resolver := image.Resolver()
+	var fileAnnotations []bufanalysis.FileAnnotation
+	for _, imageFile := range image.Files() {
+		if imageFile.IsImport() {
+			continue
+		}
+		fileDescriptor, err := resolver.FindFileByPath(imageFile.Path())
+		if err != nil {
+			return nil, err
+		}
+		reporter := &reporter{
+			imageFile:      imageFile,
+			fileDescriptor: fileDescriptor,
+			reportOptions:  reportOptions,
+		}
+		reporter.reportMessages(fileDescriptor.Messages())
+		reporter.reportExtensions(fileDescriptor.Extensions())
+		fileAnnotations = append(fileAnnotations, reporter.fileAnnotations...)
+	}
+	return fileAnnotations, nil
+}
+
+type reporter struct {
+	imageFile      bufimage.ImageFile
+	fileDescriptor protoreflect.FileDescriptor
+	reportOptions  *reportOptions
+
+	fileAnnotations []bufanalysis.FileAnnotation
+}
+
+func (r *reporter) reportMessages(messageDescriptors protoreflect.MessageDescriptors) {
+	for i := 0; i < messageDescriptors.Len(); i++ {
+		messageDescriptor := messageDescriptors.Get(i)
+		if messageDescriptor.IsMapEntry() {
+			continue
+		}
+		r.reportFields(messageDescriptor.Fields())
+		r.reportFieldNameCaseCollisions(messageDescriptor)
+		r.reportOneofs(messageDescriptor.Oneofs())
+		r.reportMessages(messageDescriptor.Messages())
+		r.reportExtensions(messageDescriptor.Extensions())
+	}
+}
+
+func (r *reporter) reportFields(fieldDescriptors protoreflect.FieldDescriptors) {
+	for i := 0; i < fieldDescriptors.Len(); i++ {
+		r.reportField(fieldDescriptors.Get(i))
+	}
+}
+
+func (r *reporter) reportExtensions(extensionDescriptors protoreflect.ExtensionDescriptors) {
+	for i := 0; i < extensionDescriptors.Len(); i++ {
+		r.reportField(extensionDescriptors.Get(i))
+	}
+}
+
+func (r *reporter) reportField(fieldDescriptor protoreflect.FieldDescriptor) {
+	valueFieldDescriptor := fieldDescriptor
+	if fieldDescriptor.IsMap() {
+		// Map keys are always encoded as strings in JSON, so only the value is checked.
+		valueFieldDescriptor = fieldDescriptor.MapValue()
+	}
+	if is64BitIntegerKind(valueFieldDescriptor.Kind()) {
+		r.addFileAnnotation(
+			fieldDescriptor,
+			TypeInt64JSON,
+			fmt.Sprintf(
+				"Field %q has 64-bit integer type %s, which is encoded as a string in JSON and loses precision if parsed as a number in JavaScript.",
+				fieldDescriptor.FullName(),
+				valueFieldDescriptor.Kind(),
+			),
+		)
+	}
+	if fieldDescriptor.Kind() == protoreflect.GroupKind {
+		r.addFileAnnotation(
+			fieldDescriptor,
+			TypeGroup,
+			fmt.Sprintf(
+				"Field %q uses group encoding, which is deprecated and not supported consistently across runtimes.",
+				fieldDescriptor.FullName(),
+			),
+		)
+	}
+}
+
+func (r *reporter) reportFieldNameCaseCollisions(messageDescriptor protoreflect.MessageDescriptor) {
+	fieldDescriptors := messageDescriptor.Fields()
+	collisionKeyToFieldDescriptor := make(map[string]protoreflect.FieldDescriptor, fieldDescriptors.Len())
+	for i := 0; i < fieldDescriptors.Len(); i++ {
+		fieldDescriptor := fieldDescriptors.Get(i)
+		collisionKey := getCollisionKey(string(fieldDescriptor.Name()))
+		if otherFieldDescriptor, ok := collisionKeyToFieldDescriptor[collisionKey]; ok {
+			r.addFileAnnotation(
+				fieldDescriptor,
+				TypeFieldNameCaseCollision,
+				fmt.Sprintf(
+					"Field %q has a name that collides with field %q after case conversion, which results in generated code that does not compile in many languages.",
+					fieldDescriptor.FullName(),
+					otherFieldDescriptor.Name(),
+				),
+			)
+			continue
+		}
+		collisionKeyToFieldDescriptor[collisionKey] = fieldDescriptor
+	}
+}
+
+func (r *reporter) reportOneofs(oneofDescriptors protoreflect.OneofDescriptors) {
+	for i := 0; i < oneofDescriptors.Len(); i++ {
+		oneofDescriptor := oneofDescriptors.Get(i)
+		if oneofDescriptor.IsSynthetic() {
+			continue
+		}
+		if numFields := oneofDescriptor.Fields().Len(); numFields > r.reportOptions.maxOneofFields {
+			r.addFileAnnotation(
+				oneofDescriptor,
+				TypeLargeOneof,
+				fmt.Sprintf(
+					"Oneof %q has %d fields, more than the maximum of %d. Large oneofs are slow to compile in Swift.",
+					oneofDescriptor.FullName(),
+					numFields,
+					r.reportOptions.maxOneofFields,
+				),
+			)
+		}
+	}
+}
+
+func (r *reporter) addFileAnnotation(descriptor protoreflect.Descriptor, typeString string, message string) {
+	var startLine, startColumn, endLine, endColumn int
+	// The Location is the zero value if there is no source code info.
+	sourceLocation := r.fileDescriptor.SourceLocations().ByDescriptor(descriptor)
+	if sourceLocation.Path != nil {
+		startLine = sourceLocation.StartLine + 1
+		startColumn = sourceLocation.StartColumn + 1
+		endLine = sourceLocation.EndLine + 1
+		endColumn = sourceLocation.EndColumn + 1
+	}
+	r.fileAnnotations = append(
+		r.fileAnnotations,
+		bufanalysis.NewFileAnnotation(
+			r.imageFile,
+			startLine,
+			startColumn,
+			endLine,
+			endColumn,
+			typeString,
+			message,
+			"",
+		),
+	)
+}
+
+func is64BitIntegerKind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Int64Kind,
+		protoreflect.Uint64Kind,
+		protoreflect.Sint64Kind,
+		protoreflect.Fixed64Kind,
+		protoreflect.Sfixed64Kind:
+		return true
+	default:
+		return false
+	}
+}
+
+// getCollisionKey returns the key that two field names collide on if they are
+// equal after case conversion. Underscores are removed and letters are lowercased,
+// so that foo_bar, fooBar, FooBar, and foo__bar all have the same key.
+func getCollisionKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
@@ -0,0 +1,130 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufcrash writes crash bundles that help users file actionable bug reports.
+//
+// A crash bundle is a directory that contains a report with the stack of the panic,
+// the configuration files of the local input, and, if the crash could be reproduced,
+// a minimized set of the .proto files of the input that still results in the crash.
+package bufcrash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/buf/private/buf/bufreduce"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+)
+
+const (
+	reportFileName = "report.txt"
+	inputDirName   = "input"
+)
+
+var (
+	configFileNames = []string{
+		"buf.yaml",
+		"buf.work.yaml",
+		"buf.gen.yaml",
+		"buf.lock",
+		"buf.mod",
+		"buf.work",
+	}
+)
+
+// Report describes a crash.
+type Report struct {
+	// Version is the version of buf.
+	Version string
+	// Command is the path of the command, for example "buf lint".
+	Command string
+	// Args are the positional arguments of the command.
+	Args []string
+	// Panic is the value that was recovered from the panic.
+	Panic string
+	// Stack is the stack of the goroutine that panicked.
+	Stack []byte
+	// Reduction describes the result of reducing the input, for example
+	// "reduced from 120 to 2 .proto files".
+	Reduction string
+}
+
+// ConfigFiles returns the configuration files, such as buf.yaml, of the files.
+func ConfigFiles(pathToData map[string][]byte) map[string][]byte {
+	configPathToData := make(map[string][]byte)
+	for path, data := range pathToData {
+		if slices.Contains(configFileNames, normalpath.Base(path)) {
+			configPathToData[path] = data
+		}
+	}
+	return configPathToData
+}
+
+// WriteBundle writes a new crash bundle within the directory and returns the path of the bundle.
+//
+// Only the configuration files and the .proto files of pathToData are written to the bundle.
+// The home directory of the user is replaced with "~" in the report.
+func WriteBundle(dirPath string, report *Report, pathToData map[string][]byte) (string, error) {
+	bundleDirPath := filepath.Join(dirPath, "buf-crash-"+time.Now().UTC().Format("20060102T150405.000000000Z"))
+	if err := os.MkdirAll(bundleDirPath, 0755); err != nil {
+		return "", err
+	}
+	bundlePathToData := ConfigFiles(pathToData)
+	for path, data := range pathToData {
+		if normalpath.Ext(path) == ".proto" {
+			bundlePathToData[path] = data
+		}
+	}
+	if err := bufreduce.WriteInputDir(filepath.Join(bundleDirPath, inputDirName), bundlePathToData); err != nil {
+		return "", err
+	}
+	homeDirPath, _ := os.UserHomeDir()
+	reportData := []byte(sanitize(getReportString(report, bundlePathToData), homeDirPath))
+	if err := os.WriteFile(filepath.Join(bundleDirPath, reportFileName), reportData, 0644); err != nil {
+		return "", err
+	}
+	return bundleDirPath, nil
+}
+
+// *** PRIVATE ***
+
+func getReportString(report *Report, bundlePathToData map[string][]byte) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "Version: %s\n", report.Version)
+	_, _ = fmt.Fprintf(&sb, "Command: %s\n", report.Command)
+	_, _ = fmt.Fprintf(&sb, "Args: %s\n", strings.Join(report.Args, " "))
+	_, _ = fmt.Fprintf(&sb, "Platform: %s/%s (%s)\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+	if report.Reduction != "" {
+		_, _ = fmt.Fprintf(&sb, "Reduction: %s\n", report.Reduction)
+	}
+	_, _ = fmt.Fprintf(&sb, "Files:\n")
+	for _, path := range slicesext.MapKeysToSortedSlice(bundlePathToData) {
+		_, _ = fmt.Fprintf(&sb, "  %s/%s\n", inputDirName, path)
+	}
+	_, _ = fmt.Fprintf(&sb, "\npanic: %s\n\n%s", report.Panic, report.Stack)
+	return sb.String()
+}
+
+func sanitize(s string, homeDirPath string) string {
+	if homeDirPath == "" || homeDirPath == string(filepath.Separator) {
+		return s
+	}
+	return strings.ReplaceAll(s, homeDirPath, "~")
+}
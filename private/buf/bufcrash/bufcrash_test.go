@@ -0,0 +1,81 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcrash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufbuild/buf/private/buf/bufreduce"
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBundle(t *testing.T) {
+	t.Parallel()
+	inputDirPath := t.TempDir()
+	require.NoError(
+		t,
+		bufreduce.WriteInputDir(
+			inputDirPath,
+			map[string][]byte{
+				"buf.yaml":          []byte("version: v2\n"),
+				"README.md":         []byte("# README\n"),
+				"a/v1/a.proto":      []byte(`syntax = "proto3";`),
+				"b/v1/b.proto":      []byte(`syntax = "proto3"; // crash`),
+				"c/v1/c.proto":      []byte(`syntax = "proto3";`),
+				".git/HEAD":         []byte("ref: refs/heads/main\n"),
+				"d/v1/d_test.proto": []byte(`syntax = "proto3";`),
+			},
+		),
+	)
+	pathToData, err := bufreduce.ReadInputDir(inputDirPath)
+	require.NoError(t, err)
+	reducedPathToData := map[string][]byte{
+		"buf.yaml":     pathToData["buf.yaml"],
+		"README.md":    pathToData["README.md"],
+		"b/v1/b.proto": pathToData["b/v1/b.proto"],
+	}
+	bundleDirPath, err := WriteBundle(
+		t.TempDir(),
+		&Report{
+			Version:   "1.0.0",
+			Command:   "buf lint",
+			Args:      []string{inputDirPath},
+			Panic:     "boom",
+			Stack:     []byte("goroutine 1 [running]:\n"),
+			Reduction: "reduced from 6 to 3 files in 4 attempts",
+		},
+		reducedPathToData,
+	)
+	require.NoError(t, err)
+	bundlePathToData, err := bufreduce.ReadInputDir(bundleDirPath)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"report.txt", "input/buf.yaml", "input/b/v1/b.proto"}, slicesext.MapKeysToSlice(bundlePathToData))
+	report, err := os.ReadFile(filepath.Join(bundleDirPath, "report.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(report), "Command: buf lint\n")
+	assert.Contains(t, string(report), "Reduction: reduced from 6 to 3 files in 4 attempts\n")
+	assert.Contains(t, string(report), "Files:\n  input/b/v1/b.proto\n  input/buf.yaml\n")
+	assert.Contains(t, string(report), "panic: boom\n\ngoroutine 1 [running]:\n")
+}
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "~/proto", sanitize("/home/user/proto", "/home/user"))
+	assert.Equal(t, "/home/user/proto", sanitize("/home/user/proto", ""))
+}
@@ -25,6 +25,15 @@ const (
 	//
 	// TODO FUTURE: Rename to something like "ExitCodeCompileError" as we use this for ImportNotExistErrors as well.
 	ExitCodeFileAnnotation = 100
+
+	// LogComponentCompile is the log component for compiling images.
+	LogComponentCompile = "compile"
+	// LogComponentFetch is the log component for fetching modules and plugins from the registry.
+	LogComponentFetch = "fetch"
+	// LogComponentPlugin is the log component for running check and generation plugins.
+	LogComponentPlugin = "plugin"
+	// LogComponentCache is the log component for the local caches of modules and plugins.
+	LogComponentCache = "cache"
 )
 
 var (
@@ -36,4 +45,12 @@ var (
 	//
 	// We also exit with 100 to be able to distinguish user-parsable errors from system errors.
 	ErrFileAnnotation = app.NewError(ExitCodeFileAnnotation, "")
+
+	// LogComponents are all the log components, for use with appext.BuilderWithLogComponents.
+	LogComponents = []string{
+		LogComponentCache,
+		LogComponentCompile,
+		LogComponentFetch,
+		LogComponentPlugin,
+	}
 )
@@ -47,6 +47,9 @@ import (
 	"github.com/bufbuild/buf/private/pkg/normalpath"
 	"github.com/bufbuild/buf/private/pkg/protoencoding"
 	"github.com/bufbuild/buf/private/pkg/slicesext"
+	"github.com/bufbuild/buf/private/pkg/slogext"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
 	"github.com/bufbuild/buf/private/pkg/syserror"
 	"github.com/bufbuild/buf/private/pkg/wasm"
@@ -61,6 +64,11 @@ type ImageWithConfig interface {
 	LintConfig() bufconfig.LintConfig
 	BreakingConfig() bufconfig.BreakingConfig
 	PluginConfigs() []bufconfig.PluginConfig
+	// SourceBucket returns a bucket containing the .proto files that the Image was
+	// built from, by path.
+	//
+	// Returns nil if the Image was not built from source, such as for image inputs.
+	SourceBucket() storage.ReadBucket
 
 	isImageWithConfig()
 }
@@ -199,10 +207,12 @@ type controller struct {
 	pluginDataProvider bufplugin.PluginDataProvider
 	wktStore           bufwktstore.Store
 
-	disableSymlinks           bool
-	fileAnnotationErrorFormat string
-	fileAnnotationsToStdout   bool
-	copyToInMemory            bool
+	disableSymlinks            bool
+	fileAnnotationErrorFormat  string
+	fileAnnotationsToStdout    bool
+	fileAnnotationSetsReturned bool
+	copyToInMemory             bool
+	buffetchReaderOptions      []buffetch.ReaderOption
 
 	storageosProvider           storageos.Provider
 	buffetchRefParser           buffetch.RefParser
@@ -256,6 +266,7 @@ func newController(
 			gitClonerOptions,
 		),
 		moduleKeyProvider,
+		controller.buffetchReaderOptions...,
 	)
 	controller.buffetchWriter = buffetch.NewWriter(logger)
 	controller.workspaceProvider = bufworkspace.NewWorkspaceProvider(
@@ -479,6 +490,7 @@ func (c *controller) GetTargetImageWithConfigsAndCheckClient(
 				lintConfig,
 				breakingConfig,
 				pluginConfigs,
+				nil,
 			),
 		}
 		pluginRunnerProvider := bufcheck.NewLocalRunnerProvider(
@@ -487,7 +499,7 @@ func (c *controller) GetTargetImageWithConfigsAndCheckClient(
 			c.pluginDataProvider,
 		)
 		checkClient, err := bufcheck.NewClient(
-			c.logger,
+			slogext.WithComponent(c.logger, LogComponentPlugin),
 			pluginRunnerProvider,
 			bufcheck.ClientWithStderr(c.container.Stderr()),
 		)
@@ -628,7 +640,7 @@ func (c *controller) PutImage(
 	if messageRef.IsNull() {
 		return nil
 	}
-	marshaler, err := newProtoencodingMarshaler(image, messageRef)
+	marshaler, err := newProtoencodingMarshaler(image, messageRef, functionOptions)
 	if err != nil {
 		return err
 	}
@@ -700,7 +712,10 @@ func (c *controller) GetMessage(
 	var unmarshaler protoencoding.Unmarshaler
 	switch messageEncoding {
 	case buffetch.MessageEncodingBinpb:
-		unmarshaler = protoencoding.NewWireUnmarshaler(schemaImage.Resolver())
+		unmarshaler = protoencoding.NewWireUnmarshaler(
+			schemaImage.Resolver(),
+			functionOptions.messageWireUnmarshalerOptions...,
+		)
 	case buffetch.MessageEncodingJSON:
 		unmarshaler = protoencoding.NewJSONUnmarshaler(schemaImage.Resolver())
 	case buffetch.MessageEncodingTxtpb:
@@ -771,7 +786,7 @@ func (c *controller) PutMessage(
 	if messageRef.IsNull() {
 		return nil
 	}
-	marshaler, err := newProtoencodingMarshaler(schemaImage, messageRef)
+	marshaler, err := newProtoencodingMarshaler(schemaImage, messageRef, functionOptions)
 	if err != nil {
 		return err
 	}
@@ -805,7 +820,7 @@ func (c *controller) GetCheckClientForWorkspace(
 		c.pluginDataProvider,
 	)
 	return bufcheck.NewClient(
-		c.logger,
+		slogext.WithComponent(c.logger, LogComponentPlugin),
 		pluginRunnerProvider,
 		bufcheck.ClientWithStderr(c.container.Stderr()),
 	)
@@ -917,6 +932,23 @@ func (c *controller) getWorkspaceForProtoFileRef(
 	defer func() {
 		retErr = errors.Join(retErr, readBucketCloser.Close())
 	}()
+	var readBucket storage.ReadBucket = readBucketCloser
+	if functionOptions.protoFileContent != nil {
+		targetPaths := bucketTargeting.TargetPaths()
+		if len(targetPaths) != 1 {
+			return nil, syserror.Newf("expected a single target path for .proto file reference, got %v", targetPaths)
+		}
+		readBucket, err = overlayProtoFileContent(
+			ctx,
+			readBucketCloser,
+			targetPaths[0],
+			normalpath.Unnormalize(protoFileRef.ProtoFilePath()),
+			functionOptions.protoFileContent,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
 	options := []bufworkspace.WorkspaceBucketOption{
 		bufworkspace.WithProtoFileTargetPath(
 			protoFileRef.ProtoFilePath(),
@@ -932,9 +964,15 @@ func (c *controller) getWorkspaceForProtoFileRef(
 			bufworkspace.WithIgnoreAndDisallowV1BufWorkYAMLs(),
 		)
 	}
+	if functionOptions.ignoreModuleOverrides {
+		options = append(
+			options,
+			bufworkspace.WithIgnoreModuleOverrides(),
+		)
+	}
 	return c.workspaceProvider.GetWorkspaceForBucket(
 		ctx,
-		readBucketCloser,
+		readBucket,
 		bucketTargeting,
 		options...,
 	)
@@ -968,6 +1006,12 @@ func (c *controller) getWorkspaceForSourceRef(
 			bufworkspace.WithIgnoreAndDisallowV1BufWorkYAMLs(),
 		)
 	}
+	if functionOptions.ignoreModuleOverrides {
+		options = append(
+			options,
+			bufworkspace.WithIgnoreModuleOverrides(),
+		)
+	}
 	return c.workspaceProvider.GetWorkspaceForBucket(
 		ctx,
 		readBucketCloser,
@@ -1108,7 +1152,7 @@ func (c *controller) buildImage(
 	}
 	image, err := bufimage.BuildImage(
 		ctx,
-		c.logger,
+		slogext.WithComponent(c.logger, LogComponentCompile),
 		moduleReadBucket,
 		options...,
 	)
@@ -1176,6 +1220,7 @@ func (c *controller) buildTargetImageWithConfigs(
 				workspace.GetLintConfigForOpaqueID(module.OpaqueID()),
 				workspace.GetBreakingConfigForOpaqueID(module.OpaqueID()),
 				workspace.PluginConfigs(),
+				bufmodule.ModuleReadBucketToStorageReadBucket(moduleReadBucket),
 			),
 		)
 	}
@@ -1267,9 +1312,9 @@ Declare %q in the deps key in your buf.yaml.`,
 // handleFileAnnotationSetError will attempt to handle the error as a FileAnnotationSet, and if so, print
 // the FileAnnotationSet to the writer with the given error format while returning ErrFileAnnotation.
 //
-// Otherwise, the original error is returned.
+// Otherwise, or if the controller returns FileAnnotationSets, the original error is returned.
 func (c *controller) handleFileAnnotationSetRetError(retErrAddr *error) {
-	if *retErrAddr == nil {
+	if *retErrAddr == nil || c.fileAnnotationSetsReturned {
 		return
 	}
 	var fileAnnotationSet bufanalysis.FileAnnotationSet
@@ -1290,6 +1335,51 @@ func (c *controller) handleFileAnnotationSetRetError(retErrAddr *error) {
 	}
 }
 
+// overlayProtoFileContent returns a ReadBucket with the content of the file at the path
+// replaced by protoFileContent.
+//
+// The external and local paths of the file are retained if the file exists, so that
+// FileAnnotations refer to the file on disk. Otherwise, the external path is set to
+// defaultExternalPath.
+func overlayProtoFileContent(
+	ctx context.Context,
+	readBucket storage.ReadBucket,
+	path string,
+	defaultExternalPath string,
+	protoFileContent []byte,
+) (_ storage.ReadBucket, retErr error) {
+	overlayBucket := storagemem.NewReadWriteBucket()
+	writeObjectCloser, err := overlayBucket.Put(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, writeObjectCloser.Close())
+	}()
+	if _, err := writeObjectCloser.Write(protoFileContent); err != nil {
+		return nil, err
+	}
+	objectInfo, err := readBucket.Stat(ctx, path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if err := writeObjectCloser.SetExternalPath(defaultExternalPath); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := writeObjectCloser.SetExternalPath(objectInfo.ExternalPath()); err != nil {
+			return nil, err
+		}
+		if localPath := objectInfo.LocalPath(); localPath != "" {
+			if err := writeObjectCloser.SetLocalPath(localPath); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return storage.OverlayReadBucket(overlayBucket, readBucket), nil
+}
+
 func getImageFileInfosForModuleSet(ctx context.Context, moduleSet bufmodule.ModuleSet) ([]bufimage.ImageFileInfo, error) {
 	// Sorted.
 	fileInfos, err := bufmodule.GetFileInfos(
@@ -1374,12 +1464,13 @@ func newStorageosProvider(disableSymlinks bool) storageos.Provider {
 func newProtoencodingMarshaler(
 	image bufimage.Image,
 	messageRef buffetch.MessageRef,
+	functionOptions *functionOptions,
 ) (protoencoding.Marshaler, error) {
 	switch messageEncoding := messageRef.MessageEncoding(); messageEncoding {
 	case buffetch.MessageEncodingBinpb:
 		return protoencoding.NewWireMarshaler(), nil
 	case buffetch.MessageEncodingJSON:
-		return newJSONMarshaler(image.Resolver(), messageRef), nil
+		return newJSONMarshaler(image.Resolver(), messageRef, functionOptions.messageJSONMarshalerOptions), nil
 	case buffetch.MessageEncodingTxtpb:
 		return protoencoding.NewTxtpbMarshaler(image.Resolver()), nil
 	case buffetch.MessageEncodingYAML:
@@ -1393,6 +1484,7 @@ func newProtoencodingMarshaler(
 func newJSONMarshaler(
 	resolver protoencoding.Resolver,
 	messageRef buffetch.MessageRef,
+	extraJSONMarshalerOptions []protoencoding.JSONMarshalerOption,
 ) protoencoding.Marshaler {
 	jsonMarshalerOptions := []protoencoding.JSONMarshalerOption{
 		//protoencoding.JSONMarshalerWithIndent(),
@@ -1409,6 +1501,7 @@ func newJSONMarshaler(
 			protoencoding.JSONMarshalerWithUseEnumNumbers(),
 		)
 	}
+	jsonMarshalerOptions = append(jsonMarshalerOptions, extraJSONMarshalerOptions...)
 	return protoencoding.NewJSONMarshaler(resolver, jsonMarshalerOptions...)
 }
 
@@ -17,6 +17,7 @@ package bufctl
 import (
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/storage"
 )
 
 type imageWithConfig struct {
@@ -25,6 +26,7 @@ type imageWithConfig struct {
 	lintConfig     bufconfig.LintConfig
 	breakingConfig bufconfig.BreakingConfig
 	pluginConfigs  []bufconfig.PluginConfig
+	sourceBucket   storage.ReadBucket
 }
 
 func newImageWithConfig(
@@ -32,12 +34,14 @@ func newImageWithConfig(
 	lintConfig bufconfig.LintConfig,
 	breakingConfig bufconfig.BreakingConfig,
 	pluginConfigs []bufconfig.PluginConfig,
+	sourceBucket storage.ReadBucket,
 ) *imageWithConfig {
 	return &imageWithConfig{
 		Image:          image,
 		lintConfig:     lintConfig,
 		breakingConfig: breakingConfig,
 		pluginConfigs:  pluginConfigs,
+		sourceBucket:   sourceBucket,
 	}
 }
 
@@ -53,4 +57,8 @@ func (i *imageWithConfig) PluginConfigs() []bufconfig.PluginConfig {
 	return i.pluginConfigs
 }
 
+func (i *imageWithConfig) SourceBucket() storage.ReadBucket {
+	return i.sourceBucket
+}
+
 func (*imageWithConfig) isImageWithConfig() {}
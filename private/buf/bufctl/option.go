@@ -16,6 +16,7 @@ package bufctl
 
 import (
 	"github.com/bufbuild/buf/private/buf/buffetch"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
 )
 
 type ControllerOption func(*controller)
@@ -38,12 +39,67 @@ func WithFileAnnotationsToStdout() ControllerOption {
 	}
 }
 
+// WithFileAnnotationSetsReturned says to return FileAnnotationSets as errors, instead of
+// printing them and returning ErrFileAnnotation.
+func WithFileAnnotationSetsReturned() ControllerOption {
+	return func(controller *controller) {
+		controller.fileAnnotationSetsReturned = true
+	}
+}
+
 func WithCopyToInMemory() ControllerOption {
 	return func(controller *controller) {
 		controller.copyToInMemory = true
 	}
 }
 
+// WithMaxArchiveSize sets the maximum total size of the files extracted from an archive input.
+//
+// A value of 0 means there is no limit.
+func WithMaxArchiveSize(maxArchiveSize int64) ControllerOption {
+	return func(controller *controller) {
+		controller.buffetchReaderOptions = append(
+			controller.buffetchReaderOptions,
+			buffetch.ReaderWithMaxArchiveSize(maxArchiveSize),
+		)
+	}
+}
+
+// WithMaxArchiveFileCount sets the maximum number of files in an archive input.
+//
+// A value of 0 means there is no limit.
+func WithMaxArchiveFileCount(maxArchiveFileCount int) ControllerOption {
+	return func(controller *controller) {
+		controller.buffetchReaderOptions = append(
+			controller.buffetchReaderOptions,
+			buffetch.ReaderWithMaxArchiveFileCount(maxArchiveFileCount),
+		)
+	}
+}
+
+// WithMaxArchivePathDepth sets the maximum number of components of a path in an archive input.
+//
+// A value of 0 means there is no limit.
+func WithMaxArchivePathDepth(maxArchivePathDepth int) ControllerOption {
+	return func(controller *controller) {
+		controller.buffetchReaderOptions = append(
+			controller.buffetchReaderOptions,
+			buffetch.ReaderWithMaxArchivePathDepth(maxArchivePathDepth),
+		)
+	}
+}
+
+// WithDisableGitAttributes says to not apply the .gitattributes files of git inputs,
+// such as the export-ignore attribute.
+func WithDisableGitAttributes(disableGitAttributes bool) ControllerOption {
+	return func(controller *controller) {
+		controller.buffetchReaderOptions = append(
+			controller.buffetchReaderOptions,
+			buffetch.ReaderWithDisableGitAttributes(disableGitAttributes),
+		)
+	}
+}
+
 // TODO FUTURE: split up to per-function.
 type FunctionOption func(*functionOptions)
 
@@ -113,6 +169,31 @@ func WithIgnoreAndDisallowV1BufWorkYAMLs() FunctionOption {
 	}
 }
 
+// WithIgnoreModuleOverrides returns a new FunctionOption that says to ignore
+// the overrides of v2 buf.yaml files, and use the dependencies from the buf.lock file.
+//
+// See bufworkspace.WithIgnoreModuleOverrides for more details.
+func WithIgnoreModuleOverrides() FunctionOption {
+	return func(functionOptions *functionOptions) {
+		functionOptions.ignoreModuleOverrides = true
+	}
+}
+
+// WithProtoFileContent returns a new FunctionOption that says to use the given content
+// for the .proto file of a .proto file reference, instead of the content of the file
+// in the bucket.
+//
+// The imports of the file are still resolved against the enclosing workspace or module of
+// the file. The file does not need to exist. This is used to check unsaved files, for example
+// from editors that pass the file on stdin.
+//
+// If used with any other reference, this has no effect.
+func WithProtoFileContent(protoFileContent []byte) FunctionOption {
+	return func(functionOptions *functionOptions) {
+		functionOptions.protoFileContent = protoFileContent
+	}
+}
+
 // WithMessageValidation returns a new FunctionOption that says to validate the
 // message as it is being read.
 //
@@ -124,6 +205,37 @@ func WithMessageValidation() FunctionOption {
 	}
 }
 
+// WithMessageWireUnmarshalerOptions returns a new FunctionOption that says to use
+// the given options when reading a binary message.
+func WithMessageWireUnmarshalerOptions(options ...protoencoding.WireUnmarshalerOption) FunctionOption {
+	return func(functionOptions *functionOptions) {
+		functionOptions.messageWireUnmarshalerOptions = options
+	}
+}
+
+// WithMessageJSONMarshalerOptions returns a new FunctionOption that says to use
+// the given options when writing a JSON message.
+//
+// These are applied after any options set on the message ref, such as use_proto_names.
+func WithMessageJSONMarshalerOptions(options ...protoencoding.JSONMarshalerOption) FunctionOption {
+	return func(functionOptions *functionOptions) {
+		functionOptions.messageJSONMarshalerOptions = options
+	}
+}
+
+// WithGitMergeBase returns a new FunctionOption that says to read a local git repository
+// with a branch at the merge base of the branch and HEAD, instead of at the tip of the branch.
+//
+// This is used by breaking change detection, so that changes made to the branch after HEAD
+// was forked from it are not reported as breaking changes.
+//
+// If used with any other reference, this has no effect.
+func WithGitMergeBase() FunctionOption {
+	return func(functionOptions *functionOptions) {
+		functionOptions.gitMergeBase = true
+	}
+}
+
 // *** PRIVATE ***
 
 type functionOptions struct {
@@ -137,7 +249,12 @@ type functionOptions struct {
 	imageAsFileDescriptorSet        bool
 	configOverride                  string
 	ignoreAndDisallowV1BufWorkYAMLs bool
+	ignoreModuleOverrides           bool
+	protoFileContent                []byte
 	messageValidation               bool
+	messageWireUnmarshalerOptions   []protoencoding.WireUnmarshalerOption
+	messageJSONMarshalerOptions     []protoencoding.JSONMarshalerOption
+	gitMergeBase                    bool
 }
 
 func newFunctionOptions(controller *controller) *functionOptions {
@@ -166,6 +283,12 @@ func (f *functionOptions) getGetReadBucketCloserOptions() []buffetch.GetReadBuck
 			buffetch.GetReadBucketCloserWithTargetExcludePaths(f.targetExcludePaths),
 		)
 	}
+	if f.gitMergeBase {
+		getReadBucketCloserOptions = append(
+			getReadBucketCloserOptions,
+			buffetch.GetReadBucketCloserWithGitMergeBase(),
+		)
+	}
 	if f.configOverride != "" {
 		// If we have a config override, we do not search for buf.yamls or buf.work.yamls,
 		// instead acting as if the config override was the only configuration file available.
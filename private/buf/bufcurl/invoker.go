@@ -70,33 +70,48 @@ func (p protoCodec) Unmarshal(bytes []byte, a any) error {
 type invokeClient = connect.Client[dynamicpb.Message, deferredMessage]
 
 type invoker struct {
-	md           protoreflect.MethodDescriptor
-	res          protoencoding.Resolver
-	emitDefaults bool
-	client       *invokeClient
-	output       io.Writer
-	errOutput    io.Writer
-	printer      verbose.Printer
+	md                     protoreflect.MethodDescriptor
+	res                    protoencoding.Resolver
+	jsonMarshalerOptions   []protoencoding.JSONMarshalerOption
+	wireUnmarshalerOptions []protoencoding.WireUnmarshalerOption
+	client                 *invokeClient
+	output                 io.Writer
+	errOutput              io.Writer
+	printer                verbose.Printer
 }
 
 // NewInvoker creates a new invoker for invoking the method described by the
 // given descriptor. The given writer is used to write the output response(s)
 // in JSON format. The given resolver is used to resolve Any messages and
-// extensions that appear in the input or output. Other parameters are used
+// extensions that appear in the input or output. The given JSON marshaler
+// options are used to format the output response(s), and the given wire
+// unmarshaler options are used to read them. Other parameters are used
 // to create a Connect client, for issuing the RPC.
-func NewInvoker(container appext.Container, verbosePrinter verbose.Printer, md protoreflect.MethodDescriptor, res protoencoding.Resolver, emitDefaults bool, httpClient connect.HTTPClient, opts []connect.ClientOption, url string, out io.Writer) Invoker {
+func NewInvoker(
+	container appext.Container,
+	verbosePrinter verbose.Printer,
+	md protoreflect.MethodDescriptor,
+	res protoencoding.Resolver,
+	jsonMarshalerOptions []protoencoding.JSONMarshalerOption,
+	wireUnmarshalerOptions []protoencoding.WireUnmarshalerOption,
+	httpClient connect.HTTPClient,
+	opts []connect.ClientOption,
+	url string,
+	out io.Writer,
+) Invoker {
 	opts = append(opts, connect.WithCodec(protoCodec{}))
 	// TODO: could also provide custom compressor implementations that could give us
 	//  optics into when request and response messages are compressed (which could be
 	//  useful to include in verbose output).
 	return &invoker{
-		md:           md,
-		res:          res,
-		emitDefaults: emitDefaults,
-		output:       out,
-		printer:      verbosePrinter,
-		errOutput:    container.Stderr(),
-		client:       connect.NewClient[dynamicpb.Message, deferredMessage](httpClient, url, opts...),
+		md:                     md,
+		res:                    res,
+		jsonMarshalerOptions:   jsonMarshalerOptions,
+		wireUnmarshalerOptions: wireUnmarshalerOptions,
+		output:                 out,
+		printer:                verbosePrinter,
+		errOutput:              container.Stderr(),
+		client:                 connect.NewClient[dynamicpb.Message, deferredMessage](httpClient, url, opts...),
 	}
 }
 
@@ -278,24 +293,15 @@ func (inv *invoker) handleResponse(data []byte, msg *dynamicpb.Message) error {
 	if msg == nil {
 		msg = dynamicpb.NewMessage(inv.md.Output())
 	}
-	if err := protoencoding.NewWireUnmarshaler(inv.res).Unmarshal(data, msg); err != nil {
+	if err := protoencoding.NewWireUnmarshaler(inv.res, inv.wireUnmarshalerOptions...).Unmarshal(data, msg); err != nil {
 		return err
 	}
-	jsonMarshalerOptions := []protoencoding.JSONMarshalerOption{
-		protoencoding.JSONMarshalerWithIndent(),
-	}
-	if inv.emitDefaults {
-		jsonMarshalerOptions = append(
-			jsonMarshalerOptions,
-			protoencoding.JSONMarshalerWithEmitUnpopulated(),
-		)
-	}
 	unrecognized := countUnrecognized(msg.ProtoReflect())
 	if unrecognized > 0 {
 		inv.printer.Printf("Response message (%s) contained %d bytes of unrecognized fields.",
 			msg.ProtoReflect().Descriptor().FullName(), unrecognized)
 	}
-	outputBytes, err := protoencoding.NewJSONMarshaler(inv.res, jsonMarshalerOptions...).Marshal(msg)
+	outputBytes, err := protoencoding.NewJSONMarshaler(inv.res, inv.jsonMarshalerOptions...).Marshal(msg)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,132 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// secretHeaderNames are the names of headers that are removed from shared requests,
+// as they usually contain credentials.
+var secretHeaderNames = map[string]struct{}{
+	"authorization":       {},
+	"proxy-authorization": {},
+	"cookie":              {},
+}
+
+// secretHeaderNameSubstrings are substrings of the names of headers that are removed
+// from shared requests, such as "X-Api-Key" and "X-Auth-Token".
+var secretHeaderNameSubstrings = []string{
+	"auth",
+	"key",
+	"password",
+	"secret",
+	"session",
+	"token",
+}
+
+// SharedRequest is a request composed by buf curl that is shared so that others can
+// reproduce it, either as a Studio request on the BSR or as a local JSON file.
+type SharedRequest struct {
+	// Schema is the schema the request was composed with, if any.
+	//
+	// This is empty if server reflection was used.
+	Schema string `json:"schema,omitempty"`
+	// TargetBaseURL is the URL of the server, without the service and method.
+	TargetBaseURL string `json:"targetBaseUrl"`
+	Service       string `json:"service"`
+	Method        string `json:"method"`
+	Protocol      string `json:"protocol"`
+	// Headers are the request headers, without the headers that contain secrets.
+	//
+	// Multiple values of the same header are joined with ", ".
+	Headers map[string]string `json:"headers,omitempty"`
+	// RedactedHeaders are the sorted names of the headers that were removed as they
+	// contain secrets, so that the recipient knows which headers to set themselves.
+	RedactedHeaders []string `json:"redactedHeaders,omitempty"`
+	// Body is the request data.
+	Body string `json:"body,omitempty"`
+}
+
+// NewSharedRequest returns a new SharedRequest.
+//
+// Headers that contain secrets are removed.
+func NewSharedRequest(
+	schema string,
+	targetBaseURL string,
+	service string,
+	method string,
+	protocol string,
+	headers http.Header,
+	body string,
+) *SharedRequest {
+	sharedRequest := &SharedRequest{
+		Schema:        schema,
+		TargetBaseURL: strings.TrimSuffix(targetBaseURL, "/"),
+		Service:       service,
+		Method:        method,
+		Protocol:      protocol,
+		Body:          body,
+	}
+	for name, values := range headers {
+		name = strings.ToLower(name)
+		if IsSecretHeader(name) {
+			sharedRequest.RedactedHeaders = append(sharedRequest.RedactedHeaders, name)
+			continue
+		}
+		if sharedRequest.Headers == nil {
+			sharedRequest.Headers = make(map[string]string)
+		}
+		sharedRequest.Headers[name] = strings.Join(values, ", ")
+	}
+	sort.Strings(sharedRequest.RedactedHeaders)
+	return sharedRequest
+}
+
+// IsSecretHeader returns true if the header with the given name likely contains
+// a secret, such as credentials or a session, and should not be shared.
+func IsSecretHeader(name string) bool {
+	name = strings.ToLower(name)
+	if _, ok := secretHeaderNames[name]; ok {
+		return true
+	}
+	for _, substring := range secretHeaderNameSubstrings {
+		if strings.Contains(name, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// StudioURL returns the URL of the Studio page on the BSR remote for the
+// SharedRequest, with the schema set to the module with the given owner and name.
+//
+// The requestID is the ID of the Studio request the SharedRequest was saved as.
+func StudioURL(remote string, owner string, name string, requestID string, sharedRequest *SharedRequest) string {
+	query := url.Values{
+		"target":           []string{sharedRequest.TargetBaseURL},
+		"selectedProtocol": []string{sharedRequest.Protocol},
+		"requestId":        []string{requestID},
+	}
+	return (&url.URL{
+		Scheme:   "https",
+		Host:     remote,
+		Path:     "/studio/" + owner + "/" + name + "/" + sharedRequest.Service + "/" + sharedRequest.Method,
+		RawQuery: query.Encode(),
+	}).String()
+}
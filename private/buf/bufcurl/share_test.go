@@ -0,0 +1,74 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcurl
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSharedRequest(t *testing.T) {
+	t.Parallel()
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer abc")
+	headers.Set("Cookie", "session=abc")
+	headers.Set("X-Api-Key", "abc")
+	headers.Set("User-Agent", "buf/1.0.0")
+	headers.Add("X-Custom", "foo")
+	headers.Add("X-Custom", "bar")
+	sharedRequest := NewSharedRequest(
+		"buf.build/connectrpc/eliza",
+		"https://demo.connectrpc.com/",
+		"connectrpc.eliza.v1.ElizaService",
+		"Say",
+		"connect",
+		headers,
+		`{"sentence": "Hello"}`,
+	)
+	assert.Equal(
+		t,
+		&SharedRequest{
+			Schema:        "buf.build/connectrpc/eliza",
+			TargetBaseURL: "https://demo.connectrpc.com",
+			Service:       "connectrpc.eliza.v1.ElizaService",
+			Method:        "Say",
+			Protocol:      "connect",
+			Headers: map[string]string{
+				"user-agent": "buf/1.0.0",
+				"x-custom":   "foo, bar",
+			},
+			RedactedHeaders: []string{"authorization", "cookie", "x-api-key"},
+			Body:            `{"sentence": "Hello"}`,
+		},
+		sharedRequest,
+	)
+	assert.Equal(
+		t,
+		"https://buf.build/studio/connectrpc/eliza/connectrpc.eliza.v1.ElizaService/Say?requestId=123&selectedProtocol=connect&target=https%3A%2F%2Fdemo.connectrpc.com",
+		StudioURL("buf.build", "connectrpc", "eliza", "123", sharedRequest),
+	)
+}
+
+func TestIsSecretHeader(t *testing.T) {
+	t.Parallel()
+	assert.True(t, IsSecretHeader("Authorization"))
+	assert.True(t, IsSecretHeader("proxy-authorization"))
+	assert.True(t, IsSecretHeader("X-Auth-Token"))
+	assert.True(t, IsSecretHeader("X-Client-Secret"))
+	assert.False(t, IsSecretHeader("X-Request-Id"))
+	assert.False(t, IsSecretHeader("Content-Language"))
+}
@@ -362,6 +362,14 @@ func GetReadBucketCloserWithTargetExcludePaths(targetExcludePaths []string) GetR
 	}
 }
 
+// GetReadBucketCloserWithGitMergeBase says to read a local git repository with a branch at
+// the merge base of the branch and HEAD, instead of at the tip of the branch.
+func GetReadBucketCloserWithGitMergeBase() GetReadBucketCloserOption {
+	return func(getReadBucketCloserOptions *getReadBucketCloserOptions) {
+		getReadBucketCloserOptions.gitMergeBase = true
+	}
+}
+
 // DirReader is a dir reader.
 type DirReader interface {
 	// GetDirReadWriteBucket gets the dir bucket.
@@ -429,6 +437,7 @@ func NewReader(
 	httpAuthenticator httpauth.Authenticator,
 	gitCloner git.Cloner,
 	moduleKeyProvider bufmodule.ModuleKeyProvider,
+	options ...ReaderOption,
 ) Reader {
 	return newReader(
 		logger,
@@ -437,9 +446,55 @@ func NewReader(
 		httpAuthenticator,
 		gitCloner,
 		moduleKeyProvider,
+		options...,
 	)
 }
 
+// ReaderOption is an option for a new Reader.
+type ReaderOption func(*readerOptions)
+
+// ReaderWithMaxArchiveSize returns a new ReaderOption that sets the maximum total size
+// of the files extracted from an archive.
+//
+// The default is 1 GiB. A value of 0 means there is no limit.
+func ReaderWithMaxArchiveSize(maxArchiveSize int64) ReaderOption {
+	return func(readerOptions *readerOptions) {
+		readerOptions.maxArchiveSize = maxArchiveSize
+	}
+}
+
+// ReaderWithMaxArchiveFileCount returns a new ReaderOption that sets the maximum number
+// of files in an archive.
+//
+// The default is 100,000. A value of 0 means there is no limit.
+func ReaderWithMaxArchiveFileCount(maxArchiveFileCount int) ReaderOption {
+	return func(readerOptions *readerOptions) {
+		readerOptions.maxArchiveFileCount = maxArchiveFileCount
+	}
+}
+
+// ReaderWithMaxArchivePathDepth returns a new ReaderOption that sets the maximum number
+// of components of a path in an archive.
+//
+// The default is 128. A value of 0 means there is no limit.
+func ReaderWithMaxArchivePathDepth(maxArchivePathDepth int) ReaderOption {
+	return func(readerOptions *readerOptions) {
+		readerOptions.maxArchivePathDepth = maxArchivePathDepth
+	}
+}
+
+// ReaderWithDisableGitAttributes returns a new ReaderOption that says to not apply the
+// .gitattributes files of git inputs.
+//
+// By default, git inputs are read as git archive would export them, that is files and
+// directories with the export-ignore attribute are excluded, and text files with the
+// eol=crlf attribute have CRLF line endings.
+func ReaderWithDisableGitAttributes(disableGitAttributes bool) ReaderOption {
+	return func(readerOptions *readerOptions) {
+		readerOptions.disableGitAttributes = disableGitAttributes
+	}
+}
+
 // NewMessageReader returns a new MessageReader.
 func NewMessageReader(
 	logger *slog.Logger,
@@ -581,6 +636,7 @@ type getReadBucketCloserOptions struct {
 	copyToInMemory     bool
 	targetPaths        []string
 	targetExcludePaths []string
+	gitMergeBase       bool
 }
 
 func newGetReadBucketCloserOptions() *getReadBucketCloserOptions {
@@ -596,3 +652,18 @@ type getReadWriteBucketOptions struct {
 func newGetReadWriteBucketOptions() *getReadWriteBucketOptions {
 	return &getReadWriteBucketOptions{}
 }
+
+type readerOptions struct {
+	maxArchiveSize       int64
+	maxArchiveFileCount  int
+	maxArchivePathDepth  int
+	disableGitAttributes bool
+}
+
+func newReaderOptions() *readerOptions {
+	return &readerOptions{
+		maxArchiveSize:      internal.DefaultMaxArchiveSize,
+		maxArchiveFileCount: internal.DefaultMaxArchiveFileCount,
+		maxArchivePathDepth: internal.DefaultMaxArchivePathDepth,
+	}
+}
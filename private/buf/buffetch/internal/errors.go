@@ -48,6 +48,16 @@ func NewCannotSpecifyCommitOrTagWithRefError() error {
 	return errors.New(`cannot specify "commit" or "tag" with "ref"`)
 }
 
+// NewCannotSpecifyForkPointWithGitNameError is a fetch error.
+func NewCannotSpecifyForkPointWithGitNameError() error {
+	return errors.New(`cannot specify "fork_point" with "branch", "commit", "tag", or "ref"`)
+}
+
+// NewForkPointNotLocalError is a fetch error.
+func NewForkPointNotLocalError() error {
+	return errors.New(`"fork_point" can only be specified for local git repositories`)
+}
+
 // NewDepthParseError is a fetch error.
 func NewDepthParseError(s string) error {
 	return fmt.Errorf(`could not parse "depth" value %q`, s)
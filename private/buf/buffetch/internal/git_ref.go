@@ -43,6 +43,8 @@ type gitRef struct {
 	recurseSubmodules bool
 	subDirPath        string
 	filter            string
+	branch            string
+	forkPoint         string
 }
 
 func newGitRef(
@@ -53,6 +55,8 @@ func newGitRef(
 	recurseSubmodules bool,
 	subDirPath string,
 	filter string,
+	branch string,
+	forkPoint string,
 ) (*gitRef, error) {
 	gitScheme, path, err := getGitSchemeAndPath(format, path)
 	if err != nil {
@@ -61,6 +65,9 @@ func newGitRef(
 	if depth == 0 {
 		return nil, NewDepthZeroError()
 	}
+	if forkPoint != "" && gitScheme != GitSchemeLocal {
+		return nil, NewForkPointNotLocalError()
+	}
 	subDirPath, err = normalpath.NormalizeAndValidate(subDirPath)
 	if err != nil {
 		return nil, err
@@ -77,6 +84,8 @@ func newGitRef(
 		depth,
 		subDirPath,
 		filter,
+		branch,
+		forkPoint,
 	), nil
 }
 
@@ -89,6 +98,8 @@ func newDirectGitRef(
 	depth uint32,
 	subDirPath string,
 	filter string,
+	branch string,
+	forkPoint string,
 ) *gitRef {
 	return &gitRef{
 		format:            format,
@@ -99,6 +110,8 @@ func newDirectGitRef(
 		recurseSubmodules: recurseSubmodules,
 		subDirPath:        subDirPath,
 		filter:            filter,
+		branch:            branch,
+		forkPoint:         forkPoint,
 	}
 }
 
@@ -134,6 +147,14 @@ func (r *gitRef) Filter() string {
 	return r.filter
 }
 
+func (r *gitRef) Branch() string {
+	return r.branch
+}
+
+func (r *gitRef) ForkPoint() string {
+	return r.forkPoint
+}
+
 func (*gitRef) ref()       {}
 func (*gitRef) bucketRef() {}
 func (*gitRef) gitRef()    {}
@@ -76,6 +76,15 @@ const (
 	CompressionTypeZstd
 )
 
+const (
+	// DefaultMaxArchiveSize is the default maximum total size of the files extracted from an archive.
+	DefaultMaxArchiveSize int64 = 1 << 30
+	// DefaultMaxArchiveFileCount is the default maximum number of files in an archive.
+	DefaultMaxArchiveFileCount = 100000
+	// DefaultMaxArchivePathDepth is the default maximum number of components of a path in an archive.
+	DefaultMaxArchivePathDepth = 128
+)
+
 // FileScheme is a file scheme.
 type FileScheme int
 
@@ -209,6 +218,15 @@ type GitRef interface {
 	SubDirPath() string
 	// Filter spec to use, see the --filter option in git rev-list.
 	Filter() string
+	// Branch is the branch that GitName was created for with git.NewBranchName.
+	//
+	// Empty if GitName is not a branch, including if GitName is a tag, commit, or ref.
+	Branch() string
+	// ForkPoint is the ref of a local git repository whose merge base with HEAD is read
+	// instead of GitName, such as main for the point at which HEAD was forked from main.
+	//
+	// Empty if not set. GitName is nil if ForkPoint is set.
+	ForkPoint() string
 	gitRef()
 }
 
@@ -220,8 +238,10 @@ func NewGitRef(
 	recurseSubmodules bool,
 	subDirPath string,
 	filter string,
+	branch string,
+	forkPoint string,
 ) (GitRef, error) {
-	return newGitRef("", path, gitName, depth, recurseSubmodules, subDirPath, filter)
+	return newGitRef("", path, gitName, depth, recurseSubmodules, subDirPath, filter, branch, forkPoint)
 }
 
 // ModuleRef is a module reference.
@@ -357,6 +377,8 @@ func NewDirectParsedGitRef(
 	depth uint32,
 	subDirPath string,
 	filter string,
+	branch string,
+	forkPoint string,
 ) ParsedGitRef {
 	return newDirectGitRef(
 		format,
@@ -367,6 +389,8 @@ func NewDirectParsedGitRef(
 		depth,
 		subDirPath,
 		filter,
+		branch,
+		forkPoint,
 	)
 }
 
@@ -555,6 +579,10 @@ type RawRef struct {
 	// relative commit, such as "HEAD^2".
 	GitRef string
 	// Only set for git formats.
+	// Specifies a ref of a local git repository, such as a branch, whose merge
+	// base with HEAD is used. Not allowed with GitBranch, GitCommitOrTag, or GitRef.
+	GitForkPoint string
+	// Only set for git formats.
 	GitRecurseSubmodules bool
 	// Only set for git formats.
 	// The depth to use when cloning a repository. Only allowed when GitRef
@@ -778,6 +806,41 @@ func WithReaderStdio() ReaderOption {
 	}
 }
 
+// WithReaderMaxArchiveSize sets the maximum total size of the files extracted from an archive.
+//
+// The default is DefaultMaxArchiveSize. A value of 0 means there is no limit.
+func WithReaderMaxArchiveSize(maxArchiveSize int64) ReaderOption {
+	return func(reader *reader) {
+		reader.maxArchiveSize = maxArchiveSize
+	}
+}
+
+// WithReaderMaxArchiveFileCount sets the maximum number of files in an archive.
+//
+// The default is DefaultMaxArchiveFileCount. A value of 0 means there is no limit.
+func WithReaderMaxArchiveFileCount(maxArchiveFileCount int) ReaderOption {
+	return func(reader *reader) {
+		reader.maxArchiveFileCount = maxArchiveFileCount
+	}
+}
+
+// WithReaderMaxArchivePathDepth sets the maximum number of components of a path in an archive.
+//
+// The default is DefaultMaxArchivePathDepth. A value of 0 means there is no limit.
+func WithReaderMaxArchivePathDepth(maxArchivePathDepth int) ReaderOption {
+	return func(reader *reader) {
+		reader.maxArchivePathDepth = maxArchivePathDepth
+	}
+}
+
+// WithReaderDisableGitAttributes says to not apply the .gitattributes files of git
+// repositories, such as the export-ignore attribute.
+func WithReaderDisableGitAttributes(disableGitAttributes bool) ReaderOption {
+	return func(reader *reader) {
+		reader.disableGitAttributes = disableGitAttributes
+	}
+}
+
 // WriterOption is an Writer option.
 type WriterOption func(*writer)
 
@@ -855,6 +918,18 @@ func WithGetReadBucketCloserTargetExcludePaths(targetExcludePaths []string) GetR
 	}
 }
 
+// WithGetReadBucketCloserGitMergeBase says to read a local git repository at the merge base
+// of its branch and HEAD, instead of at the tip of its branch.
+//
+// This matches git diff branch..., and is used to compare against the state of the branch at
+// the point that HEAD was forked from it. If the ref is not a local git repository with a
+// branch, this has no effect.
+func WithGetReadBucketCloserGitMergeBase() GetReadBucketCloserOption {
+	return func(getReadBucketCloserOptions *getReadBucketCloserOptions) {
+		getReadBucketCloserOptions.gitMergeBase = true
+	}
+}
+
 // GetReadWriteBucketOption is a GetReadWriteBucket option.
 type GetReadWriteBucketOption func(*getReadWriteBucketOptions)
 
@@ -38,6 +38,7 @@ import (
 	"github.com/bufbuild/buf/private/pkg/slicesext"
 	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/storage/storagearchive"
+	"github.com/bufbuild/buf/private/pkg/storage/storagegit"
 	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
 	"github.com/bufbuild/buf/private/pkg/syserror"
@@ -61,6 +62,12 @@ type reader struct {
 
 	moduleEnabled     bool
 	moduleKeyProvider bufmodule.ModuleKeyProvider
+
+	maxArchiveSize      int64
+	maxArchiveFileCount int
+	maxArchivePathDepth int
+
+	disableGitAttributes bool
 }
 
 func newReader(
@@ -69,8 +76,11 @@ func newReader(
 	options ...ReaderOption,
 ) *reader {
 	reader := &reader{
-		logger:            logger,
-		storageosProvider: storageosProvider,
+		logger:              logger,
+		storageosProvider:   storageosProvider,
+		maxArchiveSize:      DefaultMaxArchiveSize,
+		maxArchiveFileCount: DefaultMaxArchiveFileCount,
+		maxArchivePathDepth: DefaultMaxArchivePathDepth,
 	}
 	for _, option := range options {
 		option(reader)
@@ -166,6 +176,7 @@ func (r *reader) GetReadBucketCloser(
 			getReadBucketCloserOptions.targetPaths,
 			getReadBucketCloserOptions.targetExcludePaths,
 			getReadBucketCloserOptions.terminateFunc,
+			getReadBucketCloserOptions.gitMergeBase,
 		)
 	case ProtoFileRef:
 		return r.getProtoFileBucket(
@@ -259,6 +270,9 @@ func (r *reader) getArchiveBucket(
 			storagearchive.UntarWithStripComponentCount(
 				archiveRef.StripComponents(),
 			),
+			storagearchive.UntarWithMaxTotalSize(r.maxArchiveSize),
+			storagearchive.UntarWithMaxFileCount(r.maxArchiveFileCount),
+			storagearchive.UntarWithMaxPathDepth(r.maxArchivePathDepth),
 		); err != nil {
 			return nil, nil, err
 		}
@@ -285,6 +299,9 @@ func (r *reader) getArchiveBucket(
 			storagearchive.UnzipWithStripComponentCount(
 				archiveRef.StripComponents(),
 			),
+			storagearchive.UnzipWithMaxTotalSize(r.maxArchiveSize),
+			storagearchive.UnzipWithMaxFileCount(r.maxArchiveFileCount),
+			storagearchive.UnzipWithMaxPathDepth(r.maxArchivePathDepth),
 		); err != nil {
 			return nil, nil, err
 		}
@@ -349,6 +366,7 @@ func (r *reader) getGitBucket(
 	targetPaths []string,
 	targetExcludePaths []string,
 	terminateFunc buftarget.TerminateFunc,
+	gitMergeBase bool,
 ) (ReadBucketCloser, buftarget.BucketTargeting, error) {
 	if !r.gitEnabled {
 		return nil, nil, NewReadGitDisabledError()
@@ -360,6 +378,28 @@ func (r *reader) getGitBucket(
 	if err != nil {
 		return nil, nil, err
 	}
+	gitName := gitRef.GitName()
+	if forkPoint := gitRef.ForkPoint(); forkPoint != "" {
+		gitName, err = r.getGitForkPointName(ctx, container, gitRef, forkPoint)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if gitMergeBase {
+		gitName = r.getGitMergeBaseName(ctx, container, gitRef)
+	}
+	if gitRef.GitScheme() == GitSchemeLocal && !gitRef.RecurseSubmodules() {
+		// Local repositories are read directly from their objects, so that no
+		// temporary clone and checkout is needed.
+		return r.getLocalGitBucket(
+			ctx,
+			container,
+			gitRef,
+			gitName,
+			targetPaths,
+			targetExcludePaths,
+			terminateFunc,
+		)
+	}
 	readWriteBucket := storagemem.NewReadWriteBucket()
 	if err := r.gitCloner.CloneToBucket(
 		ctx,
@@ -368,7 +408,7 @@ func (r *reader) getGitBucket(
 		gitRef.Depth(),
 		readWriteBucket,
 		git.CloneToBucketOptions{
-			Name:              gitRef.GitName(),
+			Name:              gitName,
 			RecurseSubmodules: gitRef.RecurseSubmodules(),
 			SubDir:            gitRef.SubDirPath(),
 			Filter:            gitRef.Filter(),
@@ -376,15 +416,166 @@ func (r *reader) getGitBucket(
 	); err != nil {
 		return nil, nil, fmt.Errorf("could not clone %s: %v", gitURL, err)
 	}
+	readBucket, err := r.getGitExportReadBucket(ctx, readWriteBucket)
+	if err != nil {
+		return nil, nil, err
+	}
 	return getReadBucketCloserForBucket(
 		ctx,
 		r.logger,
-		storage.NopReadBucketCloser(readWriteBucket),
+		storage.NopReadBucketCloser(readBucket),
+		gitRef.SubDirPath(),
+		targetPaths,
+		targetExcludePaths,
+		terminateFunc,
+	)
+}
+
+// getLocalGitBucket returns a bucket for the tree of the commit of the Name within
+// the local git repository of the GitRef.
+//
+// Files are read directly from the objects of the repository instead of from a
+// temporary clone and checkout. Only the files within the controlling workspace, or
+// within the entire tree if there is none, are read.
+func (r *reader) getLocalGitBucket(
+	ctx context.Context,
+	container app.EnvStdinContainer,
+	gitRef GitRef,
+	gitName git.Name,
+	targetPaths []string,
+	targetExcludePaths []string,
+	terminateFunc buftarget.TerminateFunc,
+) (_ ReadBucketCloser, _ buftarget.BucketTargeting, retErr error) {
+	dirPath := normalpath.Unnormalize(gitRef.Path())
+	objectReader, err := git.NewObjectReader(ctx, container, dirPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read %s: %v", dirPath, err)
+	}
+	defer func() {
+		retErr = errors.Join(retErr, objectReader.Close())
+	}()
+	commit, err := objectReader.ResolveCommit(ctx, gitName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read %s: %v", dirPath, err)
+	}
+	r.logger.DebugContext(
+		ctx,
+		"buffetch reading local git commit",
+		slog.String("path", dirPath),
+		slog.String("commit", commit),
+	)
+	readBucket, err := storagegit.NewReadBucket(ctx, objectReader, commit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read %s: %v", dirPath, err)
+	}
+	readBucket, err = r.getGitExportReadBucket(ctx, readBucket)
+	if err != nil {
+		return nil, nil, err
+	}
+	gitReadBucketCloser, bucketTargeting, err := getReadBucketCloserForBucket(
+		ctx,
+		r.logger,
+		storage.NopReadBucketCloser(readBucket),
 		gitRef.SubDirPath(),
 		targetPaths,
 		targetExcludePaths,
 		terminateFunc,
 	)
+	if err != nil {
+		return nil, nil, err
+	}
+	castReadBucketCloser, ok := gitReadBucketCloser.(*readBucketCloser)
+	if !ok {
+		return nil, nil, syserror.Newf("expected *readBucketCloser but got %T", gitReadBucketCloser)
+	}
+	// Buckets may be read after they are closed, so the files are read into memory
+	// before the ObjectReader is closed.
+	inMemoryReadBucketCloser, err := castReadBucketCloser.copyToInMemory(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return inMemoryReadBucketCloser, bucketTargeting, nil
+}
+
+// getGitExportReadBucket returns the files of the tree of a git commit as git archive
+// exports them, so that files such as vendored test fixtures that are marked with the
+// export-ignore attribute are not built.
+//
+// The ReadBucket is returned as-is if .gitattributes files are disabled.
+func (r *reader) getGitExportReadBucket(
+	ctx context.Context,
+	readBucket storage.ReadBucket,
+) (storage.ReadBucket, error) {
+	if r.disableGitAttributes {
+		return readBucket, nil
+	}
+	exportReadBucket, err := git.NewExportReadBucket(ctx, readBucket)
+	if err != nil {
+		return nil, fmt.Errorf("could not read .gitattributes files: %w", err)
+	}
+	return exportReadBucket, nil
+}
+
+// getGitForkPointName returns the Name of the merge base of the fork point ref of the
+// local git repository and HEAD.
+func (r *reader) getGitForkPointName(
+	ctx context.Context,
+	container app.EnvStdinContainer,
+	gitRef GitRef,
+	forkPoint string,
+) (git.Name, error) {
+	dirPath := normalpath.Unnormalize(gitRef.Path())
+	mergeBase, err := git.GetMergeBase(ctx, container, dirPath, "HEAD", forkPoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not compute the fork point of HEAD and %q: %w", forkPoint, err)
+	}
+	r.logger.DebugContext(
+		ctx,
+		"buffetch using git fork point",
+		slog.String("forkPoint", forkPoint),
+		slog.String("mergeBase", mergeBase),
+	)
+	return git.NewRefName(mergeBase), nil
+}
+
+// getGitMergeBaseName returns the Name of the merge base of the branch of the local git
+// repository and HEAD, or the Name of the GitRef if it is not a local git repository with
+// a branch.
+//
+// If the merge base cannot be computed, for example if the branch and HEAD have no common
+// history, a warning is logged and the Name of the GitRef is returned.
+func (r *reader) getGitMergeBaseName(
+	ctx context.Context,
+	container app.EnvStdinContainer,
+	gitRef GitRef,
+) git.Name {
+	if gitRef.GitScheme() != GitSchemeLocal {
+		return gitRef.GitName()
+	}
+	branch := gitRef.Branch()
+	if branch == "" {
+		return gitRef.GitName()
+	}
+	dirPath := normalpath.Unnormalize(gitRef.Path())
+	if err := git.IsValidRef(ctx, container, dirPath, branch); err != nil {
+		// The clone reports that the branch does not exist.
+		return gitRef.GitName()
+	}
+	mergeBase, err := git.GetMergeBase(ctx, container, dirPath, "HEAD", branch)
+	if err != nil {
+		r.logger.Warn(
+			fmt.Sprintf("Could not compute the merge base of HEAD and branch %q, using the tip of the branch instead", branch),
+			slog.String("error", err.Error()),
+		)
+		return gitRef.GitName()
+	}
+	r.logger.DebugContext(
+		ctx,
+		"buffetch using git merge base",
+		slog.String("branch", branch),
+		slog.String("mergeBase", mergeBase),
+	)
+	return git.NewRefName(mergeBase)
 }
 
 func (r *reader) getModuleKey(
@@ -952,6 +1143,7 @@ type getReadBucketCloserOptions struct {
 	copyToInMemory     bool
 	targetPaths        []string
 	targetExcludePaths []string
+	gitMergeBase       bool
 }
 
 func newGetReadBucketCloserOptions() *getReadBucketCloserOptions {
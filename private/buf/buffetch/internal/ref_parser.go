@@ -142,6 +142,8 @@ func (a *refParser) getRawRef(
 			rawRef.GitCommitOrTag = value
 		case "ref":
 			rawRef.GitRef = value
+		case "fork_point":
+			rawRef.GitForkPoint = value
 		case "filter":
 			rawRef.GitFilter = value
 		case "depth":
@@ -347,8 +349,11 @@ func (a *refParser) validateRawRef(
 		if rawRef.GitRef != "" && rawRef.GitCommitOrTag != "" {
 			return NewCannotSpecifyCommitOrTagWithRefError()
 		}
+		if rawRef.GitForkPoint != "" && (rawRef.GitBranch != "" || rawRef.GitCommitOrTag != "" || rawRef.GitRef != "") {
+			return NewCannotSpecifyForkPointWithGitNameError()
+		}
 	} else {
-		if rawRef.GitBranch != "" || rawRef.GitCommitOrTag != "" || rawRef.GitRef != "" || rawRef.GitRecurseSubmodules || rawRef.GitDepth > 0 {
+		if rawRef.GitBranch != "" || rawRef.GitCommitOrTag != "" || rawRef.GitRef != "" || rawRef.GitForkPoint != "" || rawRef.GitRecurseSubmodules || rawRef.GitDepth > 0 {
 			return NewOptionsInvalidForFormatError(rawRef.Format, displayName, "git options set")
 		}
 	}
@@ -514,6 +519,11 @@ func getGitRef(
 	if err != nil {
 		return nil, err
 	}
+	var branch string
+	if rawRef.GitRef == "" {
+		// If a ref is set, the branch is only used as the clone target.
+		branch = rawRef.GitBranch
+	}
 	return newGitRef(
 		rawRef.Format,
 		rawRef.Path,
@@ -522,6 +532,8 @@ func getGitRef(
 		rawRef.GitRecurseSubmodules,
 		rawRef.SubDirPath,
 		rawRef.GitFilter,
+		branch,
+		rawRef.GitForkPoint,
 	)
 }
 
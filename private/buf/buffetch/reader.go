@@ -40,7 +40,12 @@ func newReader(
 	httpAuthenticator httpauth.Authenticator,
 	gitCloner git.Cloner,
 	moduleKeyProvider bufmodule.ModuleKeyProvider,
+	options ...ReaderOption,
 ) *reader {
+	readerOptions := newReaderOptions()
+	for _, option := range options {
+		option(readerOptions)
+	}
 	return &reader{
 		internalReader: internal.NewReader(
 			logger,
@@ -57,6 +62,10 @@ func newReader(
 			internal.WithReaderModule(
 				moduleKeyProvider,
 			),
+			internal.WithReaderMaxArchiveSize(readerOptions.maxArchiveSize),
+			internal.WithReaderMaxArchiveFileCount(readerOptions.maxArchiveFileCount),
+			internal.WithReaderMaxArchivePathDepth(readerOptions.maxArchivePathDepth),
+			internal.WithReaderDisableGitAttributes(readerOptions.disableGitAttributes),
 		),
 	}
 }
@@ -166,6 +175,12 @@ func (a *reader) GetSourceReadBucketCloser(
 			internal.WithGetReadBucketCloserCopyToInMemory(),
 		)
 	}
+	if getReadBucketCloserOptions.gitMergeBase {
+		internalGetReadBucketCloserOptions = append(
+			internalGetReadBucketCloserOptions,
+			internal.WithGetReadBucketCloserGitMergeBase(),
+		)
+	}
 	internalGetReadBucketCloserOptions = append(
 		internalGetReadBucketCloserOptions,
 		internal.WithGetReadBucketCloserTargetPaths(getReadBucketCloserOptions.targetPaths),
@@ -258,6 +258,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"",
+			"",
 		),
 		"path/to/dir.git",
 	)
@@ -272,6 +274,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			40,
 			"",
 			"",
+			"",
+			"",
 		),
 		"path/to/dir.git#depth=40",
 	)
@@ -286,9 +290,27 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"main",
+			"",
 		),
 		"path/to/dir.git#branch=main",
 	)
+	testGetParsedRefSuccess(
+		t,
+		internal.NewDirectParsedGitRef(
+			formatGit,
+			"path/to/dir.git",
+			internal.GitSchemeLocal,
+			nil,
+			false,
+			1,
+			"",
+			"",
+			"",
+			"main",
+		),
+		"path/to/dir.git#fork_point=main",
+	)
 	testGetParsedRefSuccess(
 		t,
 		internal.NewDirectParsedGitRef(
@@ -300,6 +322,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"main",
+			"",
 		),
 		"file:///path/to/dir.git#branch=main",
 	)
@@ -314,6 +338,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"",
+			"",
 		),
 		"path/to/dir.git#tag=v1.0.0",
 	)
@@ -328,6 +354,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"main",
+			"",
 		),
 		"http://hello.com/path/to/dir.git#branch=main",
 	)
@@ -342,6 +370,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"main",
+			"",
 		),
 		"https://hello.com/path/to/dir.git#branch=main",
 	)
@@ -356,6 +386,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"main",
+			"",
 		),
 		"ssh://user@hello.com:path/to/dir.git#branch=main",
 	)
@@ -370,6 +402,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			50,
 			"",
 			"",
+			"",
+			"",
 		),
 		"ssh://user@hello.com:path/to/dir.git#ref=refs/remotes/origin/HEAD",
 	)
@@ -384,6 +418,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			50,
 			"",
 			"",
+			"",
+			"",
 		),
 		"ssh://user@hello.com:path/to/dir.git#ref=refs/remotes/origin/HEAD,branch=main",
 	)
@@ -398,6 +434,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			10,
 			"",
 			"",
+			"",
+			"",
 		),
 		"ssh://user@hello.com:path/to/dir.git#ref=refs/remotes/origin/HEAD,depth=10",
 	)
@@ -412,6 +450,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			10,
 			"",
 			"",
+			"",
+			"",
 		),
 		"ssh://user@hello.com:path/to/dir.git#ref=refs/remotes/origin/HEAD,branch=main,depth=10",
 	)
@@ -426,6 +466,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"foo/bar",
 			"",
+			"",
+			"",
 		),
 		"path/to/dir.git#subdir=foo/bar",
 	)
@@ -440,6 +482,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"",
+			"",
 		),
 		"path/to/dir.git#subdir=.",
 	)
@@ -454,6 +498,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"",
+			"",
 		),
 		"path/to/dir.git#subdir=foo/..",
 	)
@@ -468,6 +514,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"main",
+			"",
 		),
 		"git://user@hello.com:path/to/dir.git#branch=main",
 	)
@@ -482,6 +530,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"main",
+			"",
 		),
 		"git://path/to/dir.git#branch=main",
 	)
@@ -496,6 +546,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"subdir",
 			"tree:0",
+			"main",
+			"",
 		),
 		"git://path/to/dir.git#branch=main,filter=tree:0,subdir=subdir",
 	)
@@ -843,6 +895,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"main",
+			"",
 		),
 		"/path/to/dir#branch=main,format=git",
 	)
@@ -857,6 +911,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"main/foo",
+			"",
 		),
 		"/path/to/dir#format=git,branch=main/foo",
 	)
@@ -871,6 +927,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"",
+			"",
 		),
 		"path/to/dir#tag=main/foo,format=git",
 	)
@@ -885,6 +943,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"",
+			"",
 		),
 		"path/to/dir#format=git,tag=main/foo",
 	)
@@ -899,6 +959,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"",
+			"",
 		),
 		"path/to/dir#format=git,tag=main/foo,recurse_submodules=true",
 	)
@@ -913,6 +975,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			1,
 			"",
 			"",
+			"",
+			"",
 		),
 		"path/to/dir#format=git,tag=main/foo,recurse_submodules=false",
 	)
@@ -927,6 +991,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			50,
 			"",
 			"",
+			"",
+			"",
 		),
 		"path/to/dir#format=git,ref=refs/remotes/origin/HEAD",
 	)
@@ -941,6 +1007,8 @@ func TestGetParsedRefSuccess(t *testing.T) {
 			10,
 			"",
 			"",
+			"",
+			"",
 		),
 		"path/to/dir#format=git,ref=refs/remotes/origin/HEAD,depth=10",
 	)
@@ -1312,6 +1380,16 @@ func TestGetParsedRefError(t *testing.T) {
 		internal.NewCannotSpecifyCommitOrTagWithRefError(),
 		"path/to/foo#format=git,tag=foo,ref=bar",
 	)
+	testGetParsedRefError(
+		t,
+		internal.NewCannotSpecifyForkPointWithGitNameError(),
+		"path/to/foo#format=git,fork_point=main,branch=foo",
+	)
+	testGetParsedRefError(
+		t,
+		internal.NewForkPointNotLocalError(),
+		"https://hello.com/path/to/dir.git#fork_point=main",
+	)
 	testGetParsedRefError(
 		t,
 		internal.NewDepthParseError("bar"),
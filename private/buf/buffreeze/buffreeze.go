@@ -0,0 +1,365 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package buffreeze provides buf.freeze.yaml files.
+//
+// A buf.freeze.yaml file pins the references in a buf.yaml and buf.gen.yaml file
+// that can resolve to different content over time, such as labels and remote
+// plugins without a version, to the commits and versions they resolved to when the
+// file was written. Runs with --frozen use the pinned references instead, so that
+// they behave identically to every other run with the same buf.freeze.yaml file.
+package buffreeze
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/pkg/encoding"
+	"github.com/bufbuild/buf/private/pkg/uuidutil"
+)
+
+const (
+	// FileName is the name of the buf.freeze.yaml file.
+	FileName = "buf.freeze.yaml"
+
+	fileVersionV1 = "v1"
+)
+
+var fileHeader = []byte("# Generated by buf. DO NOT EDIT.\n")
+
+// RemotePluginPin pins a remote plugin in a buf.gen.yaml file to a version and revision.
+type RemotePluginPin struct {
+	// Remote is the remote plugin as written in the buf.gen.yaml file, including
+	// the version if one was set.
+	Remote string
+	// Revision is the revision as written in the buf.gen.yaml file, or 0 if not set.
+	Revision int
+	// PinnedRemote is the remote plugin including the version it resolved to.
+	PinnedRemote string
+	// PinnedRevision is the revision the remote plugin resolved to.
+	PinnedRevision int
+}
+
+// ModuleInputPin pins a module input in a buf.gen.yaml file to a commit.
+type ModuleInputPin struct {
+	// Module is the module as written in the buf.gen.yaml file.
+	Module string
+	// PinnedModule is the module with the ID of the commit it resolved to as the ref.
+	PinnedModule string
+}
+
+// LockPin is an entry of the buf.lock file at the time the buf.freeze.yaml file was written.
+type LockPin struct {
+	// Name is the full name of the module or plugin.
+	Name string
+	// Commit is the dashless ID of the commit.
+	Commit string
+	// Digest is the digest of the content.
+	Digest string
+}
+
+// File is a buf.freeze.yaml file.
+type File interface {
+	// RemotePluginPins returns the pins of the remote plugins in the buf.gen.yaml file.
+	//
+	// Sorted by Remote and then Revision.
+	RemotePluginPins() []RemotePluginPin
+	// ModuleInputPins returns the pins of the module inputs in the buf.gen.yaml file.
+	//
+	// Sorted by Module.
+	ModuleInputPins() []ModuleInputPin
+	// DepPins returns the dependencies of the buf.lock file.
+	//
+	// Sorted by Name.
+	DepPins() []LockPin
+	// PluginPins returns the remote check plugins of the buf.lock file.
+	//
+	// Sorted by Name.
+	PluginPins() []LockPin
+
+	isFile()
+}
+
+// NewFile returns a new File.
+//
+// The pins are validated to be unique.
+func NewFile(
+	remotePluginPins []RemotePluginPin,
+	moduleInputPins []ModuleInputPin,
+	depPins []LockPin,
+	pluginPins []LockPin,
+) (File, error) {
+	return newFile(remotePluginPins, moduleInputPins, depPins, pluginPins)
+}
+
+// ReadFile reads the File from the io.Reader.
+func ReadFile(reader io.Reader) (File, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var externalFile externalFileV1
+	if err := encoding.UnmarshalYAMLStrict(data, &externalFile); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", FileName, err)
+	}
+	if externalFile.Version != fileVersionV1 {
+		return nil, fmt.Errorf("invalid %s: unknown version %q", FileName, externalFile.Version)
+	}
+	file, err := newFile(
+		externalRemotePluginPinsToRemotePluginPins(externalFile.RemotePlugins),
+		externalModuleInputPinsToModuleInputPins(externalFile.ModuleInputs),
+		externalLockPinsToLockPins(externalFile.Deps),
+		externalLockPinsToLockPins(externalFile.Plugins),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", FileName, err)
+	}
+	return file, nil
+}
+
+// WriteFile writes the File to the io.Writer.
+func WriteFile(writer io.Writer, file File) error {
+	externalFile := externalFileV1{
+		Version:       fileVersionV1,
+		RemotePlugins: remotePluginPinsToExternalRemotePluginPins(file.RemotePluginPins()),
+		ModuleInputs:  moduleInputPinsToExternalModuleInputPins(file.ModuleInputPins()),
+		Deps:          lockPinsToExternalLockPins(file.DepPins()),
+		Plugins:       lockPinsToExternalLockPins(file.PluginPins()),
+	}
+	data, err := encoding.MarshalYAML(&externalFile)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(fileHeader); err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
+
+// GetLockPinsForBufLockFile returns the dependency and remote check plugin LockPins for the BufLockFile.
+func GetLockPinsForBufLockFile(bufLockFile bufconfig.BufLockFile) ([]LockPin, []LockPin, error) {
+	depPins := make([]LockPin, len(bufLockFile.DepModuleKeys()))
+	for i, depModuleKey := range bufLockFile.DepModuleKeys() {
+		digest, err := depModuleKey.Digest()
+		if err != nil {
+			return nil, nil, err
+		}
+		depPins[i] = LockPin{
+			Name:   depModuleKey.FullName().String(),
+			Commit: uuidutil.ToDashless(depModuleKey.CommitID()),
+			Digest: digest.String(),
+		}
+	}
+	pluginPins := make([]LockPin, len(bufLockFile.RemotePluginKeys()))
+	for i, pluginKey := range bufLockFile.RemotePluginKeys() {
+		digest, err := pluginKey.Digest()
+		if err != nil {
+			return nil, nil, err
+		}
+		pluginPins[i] = LockPin{
+			Name:   pluginKey.FullName().String(),
+			Commit: uuidutil.ToDashless(pluginKey.CommitID()),
+			Digest: digest.String(),
+		}
+	}
+	sortLockPins(depPins)
+	sortLockPins(pluginPins)
+	return depPins, pluginPins, nil
+}
+
+// CheckBufLockFile checks that the BufLockFile has the same dependencies and remote
+// check plugins as when the File was written.
+//
+// The BufLockFile may be nil if there is no buf.lock file.
+func CheckBufLockFile(bufLockFile bufconfig.BufLockFile, file File) error {
+	var depPins []LockPin
+	var pluginPins []LockPin
+	if bufLockFile != nil {
+		var err error
+		depPins, pluginPins, err = GetLockPinsForBufLockFile(bufLockFile)
+		if err != nil {
+			return err
+		}
+	}
+	if !slices.Equal(depPins, file.DepPins()) || !slices.Equal(pluginPins, file.PluginPins()) {
+		return newOutdatedError(fmt.Sprintf("%s has changed since %s was written", bufconfig.DefaultBufLockFileName, FileName))
+	}
+	return nil
+}
+
+// PinBufGenYAMLFile returns a BufGenYAMLFile the same as the input, with the remote
+// plugins and module inputs replaced by their pins in the File.
+//
+// An error is returned if a remote plugin or module input is not pinned in the File.
+func PinBufGenYAMLFile(bufGenYAMLFile bufconfig.BufGenYAMLFile, file File) (bufconfig.BufGenYAMLFile, error) {
+	generateConfig := bufGenYAMLFile.GenerateConfig()
+	pluginConfigs := slices.Clone(generateConfig.GeneratePluginConfigs())
+	for i, pluginConfig := range pluginConfigs {
+		if pluginConfig.Type() != bufconfig.GeneratePluginConfigTypeRemote {
+			continue
+		}
+		index := slices.IndexFunc(
+			file.RemotePluginPins(),
+			func(remotePluginPin RemotePluginPin) bool {
+				return remotePluginPin.Remote == pluginConfig.Name() && remotePluginPin.Revision == pluginConfig.Revision()
+			},
+		)
+		if index < 0 {
+			return nil, newOutdatedError(fmt.Sprintf("remote plugin %q is not pinned in %s", pluginConfig.Name(), FileName))
+		}
+		remotePluginPin := file.RemotePluginPins()[index]
+		pinnedPluginConfig, err := bufconfig.NewGeneratePluginConfigWithNameAndRevision(
+			pluginConfig,
+			remotePluginPin.PinnedRemote,
+			remotePluginPin.PinnedRevision,
+		)
+		if err != nil {
+			return nil, err
+		}
+		pluginConfigs[i] = pinnedPluginConfig
+	}
+	pinnedGenerateConfig, err := bufconfig.NewGenerateConfig(
+		generateConfig.CleanPluginOuts(),
+		pluginConfigs,
+		generateConfig.GenerateManagedConfig(),
+		generateConfig.GenerateTypeConfig(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	inputConfigs := slices.Clone(bufGenYAMLFile.InputConfigs())
+	for i, inputConfig := range inputConfigs {
+		if inputConfig.Type() != bufconfig.InputConfigTypeModule {
+			continue
+		}
+		index := slices.IndexFunc(
+			file.ModuleInputPins(),
+			func(moduleInputPin ModuleInputPin) bool {
+				return moduleInputPin.Module == inputConfig.Location()
+			},
+		)
+		if index < 0 {
+			return nil, newOutdatedError(fmt.Sprintf("module input %q is not pinned in %s", inputConfig.Location(), FileName))
+		}
+		pinnedInputConfig, err := bufconfig.NewInputConfigWithLocation(
+			inputConfig,
+			file.ModuleInputPins()[index].PinnedModule,
+		)
+		if err != nil {
+			return nil, err
+		}
+		inputConfigs[i] = pinnedInputConfig
+	}
+	return bufconfig.NewBufGenYAMLFile(
+		bufGenYAMLFile.FileVersion(),
+		pinnedGenerateConfig,
+		inputConfigs,
+	), nil
+}
+
+// *** PRIVATE ***
+
+type file struct {
+	remotePluginPins []RemotePluginPin
+	moduleInputPins  []ModuleInputPin
+	depPins          []LockPin
+	pluginPins       []LockPin
+}
+
+func newFile(
+	remotePluginPins []RemotePluginPin,
+	moduleInputPins []ModuleInputPin,
+	depPins []LockPin,
+	pluginPins []LockPin,
+) (*file, error) {
+	remotePluginPins = slices.Clone(remotePluginPins)
+	slices.SortFunc(
+		remotePluginPins,
+		func(one RemotePluginPin, two RemotePluginPin) int {
+			if compare := strings.Compare(one.Remote, two.Remote); compare != 0 {
+				return compare
+			}
+			return one.Revision - two.Revision
+		},
+	)
+	for i, remotePluginPin := range remotePluginPins {
+		if remotePluginPin.Remote == "" || remotePluginPin.PinnedRemote == "" {
+			return nil, errors.New("remote plugin pins must have a remote and a pinned remote")
+		}
+		if i > 0 && remotePluginPins[i-1].Remote == remotePluginPin.Remote && remotePluginPins[i-1].Revision == remotePluginPin.Revision {
+			return nil, fmt.Errorf("remote plugin %q is pinned more than once", remotePluginPin.Remote)
+		}
+	}
+	moduleInputPins = slices.Clone(moduleInputPins)
+	slices.SortFunc(
+		moduleInputPins,
+		func(one ModuleInputPin, two ModuleInputPin) int {
+			return strings.Compare(one.Module, two.Module)
+		},
+	)
+	for i, moduleInputPin := range moduleInputPins {
+		if moduleInputPin.Module == "" || moduleInputPin.PinnedModule == "" {
+			return nil, errors.New("module input pins must have a module and a pinned module")
+		}
+		if i > 0 && moduleInputPins[i-1].Module == moduleInputPin.Module {
+			return nil, fmt.Errorf("module input %q is pinned more than once", moduleInputPin.Module)
+		}
+	}
+	depPins = slices.Clone(depPins)
+	sortLockPins(depPins)
+	pluginPins = slices.Clone(pluginPins)
+	sortLockPins(pluginPins)
+	return &file{
+		remotePluginPins: remotePluginPins,
+		moduleInputPins:  moduleInputPins,
+		depPins:          depPins,
+		pluginPins:       pluginPins,
+	}, nil
+}
+
+func (f *file) RemotePluginPins() []RemotePluginPin {
+	return slices.Clone(f.remotePluginPins)
+}
+
+func (f *file) ModuleInputPins() []ModuleInputPin {
+	return slices.Clone(f.moduleInputPins)
+}
+
+func (f *file) DepPins() []LockPin {
+	return slices.Clone(f.depPins)
+}
+
+func (f *file) PluginPins() []LockPin {
+	return slices.Clone(f.pluginPins)
+}
+
+func (*file) isFile() {}
+
+func sortLockPins(lockPins []LockPin) {
+	slices.SortFunc(
+		lockPins,
+		func(one LockPin, two LockPin) int {
+			return strings.Compare(one.Name, two.Name)
+		},
+	)
+}
+
+func newOutdatedError(message string) error {
+	return fmt.Errorf(`%s, run "buf beta freeze" to update it`, message)
+}
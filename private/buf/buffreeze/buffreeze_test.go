@@ -0,0 +1,141 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffreeze
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadWriteFileRoundTrip(t *testing.T) {
+	t.Parallel()
+	data := `# Generated by buf. DO NOT EDIT.
+version: v1
+remote_plugins:
+  - remote: buf.build/protocolbuffers/go
+    pinned_remote: buf.build/protocolbuffers/go:v1.36.0
+    pinned_revision: 1
+  - remote: buf.build/protocolbuffers/java:v29.0
+    revision: 2
+    pinned_remote: buf.build/protocolbuffers/java:v29.0
+    pinned_revision: 2
+module_inputs:
+  - module: buf.build/acme/weather:main
+    pinned_module: buf.build/acme/weather:0123456789abcdef0123456789abcdef
+deps:
+  - name: buf.build/acme/date
+    commit: fedcba9876543210fedcba9876543210
+    digest: b5:abc
+`
+	file, err := ReadFile(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]RemotePluginPin{
+			{
+				Remote:         "buf.build/protocolbuffers/go",
+				PinnedRemote:   "buf.build/protocolbuffers/go:v1.36.0",
+				PinnedRevision: 1,
+			},
+			{
+				Remote:         "buf.build/protocolbuffers/java:v29.0",
+				Revision:       2,
+				PinnedRemote:   "buf.build/protocolbuffers/java:v29.0",
+				PinnedRevision: 2,
+			},
+		},
+		file.RemotePluginPins(),
+	)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteFile(buffer, file))
+	require.Equal(t, data, buffer.String())
+
+	_, err = ReadFile(strings.NewReader("version: v2\n"))
+	require.ErrorContains(t, err, `unknown version "v2"`)
+	_, err = NewFile(
+		[]RemotePluginPin{
+			{Remote: "buf.build/protocolbuffers/go", PinnedRemote: "buf.build/protocolbuffers/go:v1.36.0"},
+			{Remote: "buf.build/protocolbuffers/go", PinnedRemote: "buf.build/protocolbuffers/go:v1.35.0"},
+		},
+		nil,
+		nil,
+		nil,
+	)
+	require.ErrorContains(t, err, `remote plugin "buf.build/protocolbuffers/go" is pinned more than once`)
+}
+
+func TestPinBufGenYAMLFile(t *testing.T) {
+	t.Parallel()
+	bufGenYAMLFile, err := bufconfig.ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+plugins:
+  - remote: buf.build/protocolbuffers/go
+    out: gen/go
+  - local: protoc-gen-foo
+    out: gen/foo
+inputs:
+  - module: buf.build/acme/weather:main
+  - directory: proto
+`,
+		),
+	)
+	require.NoError(t, err)
+	file, err := NewFile(
+		[]RemotePluginPin{
+			{
+				Remote:         "buf.build/protocolbuffers/go",
+				PinnedRemote:   "buf.build/protocolbuffers/go:v1.36.0",
+				PinnedRevision: 1,
+			},
+		},
+		[]ModuleInputPin{
+			{
+				Module:       "buf.build/acme/weather:main",
+				PinnedModule: "buf.build/acme/weather:0123456789abcdef0123456789abcdef",
+			},
+		},
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+	pinnedBufGenYAMLFile, err := PinBufGenYAMLFile(bufGenYAMLFile, file)
+	require.NoError(t, err)
+	pluginConfigs := pinnedBufGenYAMLFile.GenerateConfig().GeneratePluginConfigs()
+	require.Len(t, pluginConfigs, 2)
+	require.Equal(t, "buf.build/protocolbuffers/go:v1.36.0", pluginConfigs[0].Name())
+	require.Equal(t, 1, pluginConfigs[0].Revision())
+	require.Equal(t, "gen/go", pluginConfigs[0].Out())
+	require.Equal(t, "protoc-gen-foo", pluginConfigs[1].Name())
+	inputConfigs := pinnedBufGenYAMLFile.InputConfigs()
+	require.Len(t, inputConfigs, 2)
+	require.Equal(t, "buf.build/acme/weather:0123456789abcdef0123456789abcdef", inputConfigs[0].Location())
+	require.Equal(t, "proto", inputConfigs[1].Location())
+
+	// The template was changed since the file was written.
+	emptyFile, err := NewFile(nil, nil, nil, nil)
+	require.NoError(t, err)
+	_, err = PinBufGenYAMLFile(bufGenYAMLFile, emptyFile)
+	require.ErrorContains(t, err, `remote plugin "buf.build/protocolbuffers/go" is not pinned in buf.freeze.yaml`)
+
+	// There is no buf.lock, but there was when the file was written.
+	require.NoError(t, CheckBufLockFile(nil, emptyFile))
+	fileWithDeps, err := NewFile(nil, nil, []LockPin{{Name: "buf.build/acme/date", Commit: "fedcba9876543210fedcba9876543210", Digest: "b5:abc"}}, nil)
+	require.NoError(t, err)
+	require.ErrorContains(t, CheckBufLockFile(nil, fileWithDeps), "buf.lock has changed since buf.freeze.yaml was written")
+}
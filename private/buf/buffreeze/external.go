@@ -0,0 +1,107 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffreeze
+
+import (
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+)
+
+// externalFileV1 represents the v1 buf.freeze.yaml file.
+type externalFileV1 struct {
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// RemotePlugins are the pins of the remote plugins in buf.gen.yaml.
+	RemotePlugins []externalRemotePluginPinV1 `json:"remote_plugins,omitempty" yaml:"remote_plugins,omitempty"`
+	// ModuleInputs are the pins of the module inputs in buf.gen.yaml.
+	ModuleInputs []externalModuleInputPinV1 `json:"module_inputs,omitempty" yaml:"module_inputs,omitempty"`
+	// Deps are the dependencies in buf.lock.
+	Deps []externalLockPinV1 `json:"deps,omitempty" yaml:"deps,omitempty"`
+	// Plugins are the remote check plugins in buf.lock.
+	Plugins []externalLockPinV1 `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+}
+
+// externalRemotePluginPinV1 represents a single remote plugin pin in a v1 buf.freeze.yaml file.
+type externalRemotePluginPinV1 struct {
+	Remote         string `json:"remote,omitempty" yaml:"remote,omitempty"`
+	Revision       int    `json:"revision,omitempty" yaml:"revision,omitempty"`
+	PinnedRemote   string `json:"pinned_remote,omitempty" yaml:"pinned_remote,omitempty"`
+	PinnedRevision int    `json:"pinned_revision,omitempty" yaml:"pinned_revision,omitempty"`
+}
+
+// externalModuleInputPinV1 represents a single module input pin in a v1 buf.freeze.yaml file.
+type externalModuleInputPinV1 struct {
+	Module       string `json:"module,omitempty" yaml:"module,omitempty"`
+	PinnedModule string `json:"pinned_module,omitempty" yaml:"pinned_module,omitempty"`
+}
+
+// externalLockPinV1 represents a single buf.lock entry in a v1 buf.freeze.yaml file.
+type externalLockPinV1 struct {
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
+	Commit string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
+}
+
+func externalRemotePluginPinsToRemotePluginPins(externalRemotePluginPins []externalRemotePluginPinV1) []RemotePluginPin {
+	return slicesext.Map(
+		externalRemotePluginPins,
+		func(externalRemotePluginPin externalRemotePluginPinV1) RemotePluginPin {
+			return RemotePluginPin(externalRemotePluginPin)
+		},
+	)
+}
+
+func remotePluginPinsToExternalRemotePluginPins(remotePluginPins []RemotePluginPin) []externalRemotePluginPinV1 {
+	return slicesext.Map(
+		remotePluginPins,
+		func(remotePluginPin RemotePluginPin) externalRemotePluginPinV1 {
+			return externalRemotePluginPinV1(remotePluginPin)
+		},
+	)
+}
+
+func externalModuleInputPinsToModuleInputPins(externalModuleInputPins []externalModuleInputPinV1) []ModuleInputPin {
+	return slicesext.Map(
+		externalModuleInputPins,
+		func(externalModuleInputPin externalModuleInputPinV1) ModuleInputPin {
+			return ModuleInputPin(externalModuleInputPin)
+		},
+	)
+}
+
+func moduleInputPinsToExternalModuleInputPins(moduleInputPins []ModuleInputPin) []externalModuleInputPinV1 {
+	return slicesext.Map(
+		moduleInputPins,
+		func(moduleInputPin ModuleInputPin) externalModuleInputPinV1 {
+			return externalModuleInputPinV1(moduleInputPin)
+		},
+	)
+}
+
+func externalLockPinsToLockPins(externalLockPins []externalLockPinV1) []LockPin {
+	return slicesext.Map(
+		externalLockPins,
+		func(externalLockPin externalLockPinV1) LockPin {
+			return LockPin(externalLockPin)
+		},
+	)
+}
+
+func lockPinsToExternalLockPins(lockPins []LockPin) []externalLockPinV1 {
+	return slicesext.Map(
+		lockPins,
+		func(lockPin LockPin) externalLockPinV1 {
+			return externalLockPinV1(lockPin)
+		},
+	)
+}
@@ -20,14 +20,20 @@ package bufgen
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage"
 	"github.com/bufbuild/buf/private/pkg/app"
 	"github.com/bufbuild/buf/private/pkg/connectclient"
+	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
+	"github.com/bufbuild/buf/private/pkg/wasm"
 )
 
 const (
@@ -37,6 +43,10 @@ const (
 	StrategyDirectory Strategy = 1
 	// StrategyAll is the strategy that says to generate with all files at once.
 	StrategyAll Strategy = 2
+	// StrategyAllWithImports is the strategy that says to generate with all files at once,
+	// including all imports and the well-known types, regardless of the include imports and
+	// include well-known types settings.
+	StrategyAllWithImports Strategy = 3
 )
 
 // Strategy is a generation strategy.
@@ -51,6 +61,8 @@ func ParseStrategy(s string) (Strategy, error) {
 		return StrategyDirectory, nil
 	case "all":
 		return StrategyAll, nil
+	case "all_with_imports":
+		return StrategyAllWithImports, nil
 	default:
 		return 0, fmt.Errorf("unknown strategy: %s", s)
 	}
@@ -63,11 +75,77 @@ func (s Strategy) String() string {
 		return "directory"
 	case StrategyAll:
 		return "all"
+	case StrategyAllWithImports:
+		return "all_with_imports"
 	default:
 		return strconv.Itoa(int(s))
 	}
 }
 
+const (
+	// ProfileFormatText is the format that prints a Profile as a table.
+	ProfileFormatText ProfileFormat = iota + 1
+	// ProfileFormatJSON is the format that prints a Profile as JSON.
+	ProfileFormatJSON
+)
+
+var (
+	// AllProfileFormatStrings are all format strings.
+	AllProfileFormatStrings = []string{
+		"text",
+		"json",
+	}
+
+	profileFormatToString = map[ProfileFormat]string{
+		ProfileFormatText: "text",
+		ProfileFormatJSON: "json",
+	}
+	stringToProfileFormat = map[string]ProfileFormat{
+		"text": ProfileFormatText,
+		"json": ProfileFormatJSON,
+	}
+)
+
+// ProfileFormat is a format to print a Profile in.
+type ProfileFormat int
+
+// ParseProfileFormat parses the ProfileFormat.
+func ParseProfileFormat(s string) (ProfileFormat, error) {
+	if profileFormat, ok := stringToProfileFormat[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return profileFormat, nil
+	}
+	return 0, fmt.Errorf("unknown profile format: %q", s)
+}
+
+// String implements fmt.Stringer.
+func (p ProfileFormat) String() string {
+	if s, ok := profileFormatToString[p]; ok {
+		return s
+	}
+	return strconv.Itoa(int(p))
+}
+
+// Profile records the time spent in each phase of a generation.
+//
+// The time spent executing plugins and writing files is recorded by Generate
+// if the Profile is passed with GenerateWithProfile. The time spent compiling
+// the inputs happens before Generate is called, so it is added by the caller.
+type Profile interface {
+	// AddCompilation adds time spent compiling the inputs to images.
+	AddCompilation(duration time.Duration)
+	// Print prints the profile to the writer in the format.
+	//
+	// The total time is the time since the Profile was created.
+	Print(writer io.Writer, format ProfileFormat) error
+
+	isProfile()
+}
+
+// NewProfile returns a new Profile.
+func NewProfile() Profile {
+	return newProfileRecorder()
+}
+
 // Generator generates Protobuf stubs based on configurations.
 type Generator interface {
 	// Generate calls the generation logic.
@@ -91,11 +169,16 @@ func NewGenerator(
 	// plugins' remotes/registries is not known at this time, and remotes/registries
 	// may be different for different plugins.
 	clientConfig *connectclient.Config,
+	// wasmRuntime is used to run local plugins with a path that ends in .wasm.
+	//
+	// To disable support for Wasm plugins, set wasmRuntime to wasm.UnimplementedRuntime.
+	wasmRuntime wasm.Runtime,
 ) Generator {
 	return newGenerator(
 		logger,
 		storageosProvider,
 		clientConfig,
+		wasmRuntime,
 	)
 }
 
@@ -105,6 +188,10 @@ type GenerateOption func(*generateOptions)
 // GenerateWithBaseOutDirPath returns a new GenerateOption that uses the given
 // base directory as the output directory.
 //
+// If the path is an archive path as determined by bufprotopluginos.IsArchivePath,
+// all plugin outputs are written to a single archive at this path instead, with
+// the plugin outs interpreted relative to the root of the archive.
+//
 // The default is to use the current directory.
 func GenerateWithBaseOutDirPath(baseOutDirPath string) GenerateOption {
 	return func(generateOptions *generateOptions) {
@@ -113,7 +200,7 @@ func GenerateWithBaseOutDirPath(baseOutDirPath string) GenerateOption {
 }
 
 // GenerateWithDeleteOuts returns a new GenerateOption that results in the
-// output directories, zip files, or jar files being deleted before generation is run.
+// output directories or archive files being deleted before generation is run.
 func GenerateWithDeleteOuts(deleteOuts bool) GenerateOption {
 	return func(generateOptions *generateOptions) {
 		generateOptions.deleteOuts = &deleteOuts
@@ -147,3 +234,187 @@ func GenerateWithIncludeWellKnownTypesOverride(includeWellKnownTypes bool) Gener
 		generateOptions.includeWellKnownTypesOverride = &includeWellKnownTypes
 	}
 }
+
+// GenerateWithDryRun returns a new GenerateOption that results in nothing
+// being written to or deleted from disk.
+//
+// Instead, plugins are run as usual and the files that would have been created,
+// overwritten, or deleted are printed to stdout, one per line, sorted by path.
+// Post commands configured on plugins are not run.
+func GenerateWithDryRun() GenerateOption {
+	return func(generateOptions *generateOptions) {
+		generateOptions.dryRun = true
+	}
+}
+
+// GenerateWithRemotePluginResponseCache returns a new GenerateOption that caches the
+// CodeGeneratorResponses of remote plugins in the bucket, so that repeated generation
+// of the same image does not execute the remote plugins again.
+//
+// Responses are keyed by the image, the remote, the plugin reference, and the plugin
+// options. Only responses for remote plugins that specify a version are cached, as the
+// latest version of a plugin may change at any time. Cached responses older than ttl
+// are ignored. If ttl is zero, cached responses do not expire.
+//
+// The default is to not cache responses.
+func GenerateWithRemotePluginResponseCache(bucket storage.ReadWriteBucket, ttl time.Duration) GenerateOption {
+	return func(generateOptions *generateOptions) {
+		generateOptions.remotePluginResponseCacheBucket = bucket
+		generateOptions.remotePluginResponseCacheTTL = ttl
+	}
+}
+
+// GenerateWithRemotePluginRetries returns a new GenerateOption that retries requests
+// to the remotes of remote plugins that fail with transient errors, such as the remote
+// being unavailable, up to maxRetries times.
+//
+// The first retry waits for backoff, which is doubled after each retry. If backoff
+// is 0, the default of 1s is used.
+//
+// Once the requests to a remote have failed after all retries, no further requests
+// are made to the remote. Remote plugins with a local fallback are executed with the
+// local fallback instead, and all other remote plugins for the remote fail.
+//
+// The default is to not retry.
+func GenerateWithRemotePluginRetries(maxRetries int, backoff time.Duration) GenerateOption {
+	return func(generateOptions *generateOptions) {
+		generateOptions.remotePluginMaxRetries = maxRetries
+		if backoff > 0 {
+			generateOptions.remotePluginBackoff = backoff
+		}
+	}
+}
+
+// GenerateWithRemotePluginTimeout returns a new GenerateOption that sets the timeout
+// for each request to the remotes of remote plugins. Requests that time out are
+// retried as per GenerateWithRemotePluginRetries.
+//
+// The default is no timeout.
+func GenerateWithRemotePluginTimeout(timeout time.Duration) GenerateOption {
+	return func(generateOptions *generateOptions) {
+		generateOptions.remotePluginTimeout = timeout
+	}
+}
+
+// GenerateWithProvenanceFilePath returns a new GenerateOption that writes a JSON
+// provenance file to the OS path after generation.
+//
+// The provenance file records the digests of the inputs and the modules they
+// contain, the configuration of each plugin along with the version of remote
+// plugins and the digest of local plugin binaries, the digest of each file
+// generated by each plugin, and the given version of buf. The digests of generated
+// files are computed before any post commands are run. The path is not
+// interpreted relative to the base output directory.
+//
+// The default is to not write a provenance file.
+func GenerateWithProvenanceFilePath(provenanceFilePath string, bufVersion string) GenerateOption {
+	return func(generateOptions *generateOptions) {
+		generateOptions.provenanceFilePath = provenanceFilePath
+		generateOptions.bufVersion = bufVersion
+	}
+}
+
+// GenerateWithProfile returns a new GenerateOption that records the time spent
+// executing each plugin and writing files to the Profile.
+//
+// Plugins are executed concurrently, so the time spent executing all plugins is
+// recorded separately from the time spent executing each plugin. Remote plugins
+// that are executed in a single request to the same remote are each recorded with
+// the duration of the request.
+//
+// The default is to not record a profile.
+func GenerateWithProfile(profile Profile) GenerateOption {
+	return func(generateOptions *generateOptions) {
+		generateOptions.profile = profile
+	}
+}
+
+// GenerateWithInputConfigs returns a new GenerateOption that generates each
+// image with the plugins selected by the corresponding InputConfig, under the
+// InputConfig's out prefix.
+//
+// The InputConfigs must be in the same order as the images given to Generate.
+//
+// The default is to generate every image with every plugin.
+func GenerateWithInputConfigs(inputConfigs []bufconfig.InputConfig) GenerateOption {
+	return func(generateOptions *generateOptions) {
+		generateOptions.inputConfigs = inputConfigs
+	}
+}
+
+// GenerateWithResponseWriter returns a new GenerateOption that writes the
+// CodeGeneratorResponses of all plugins, merged into a single CodeGeneratorResponse
+// in the binary wire format, to the writer instead of writing the generated files.
+//
+// The name of each generated file in the merged response is the path the file
+// would have been written to, relative to the current directory. No post commands
+// are run, and no plugin outs are deleted or cleaned.
+//
+// This cannot be used when generating to an archive.
+func GenerateWithResponseWriter(writer io.Writer) GenerateOption {
+	return func(generateOptions *generateOptions) {
+		generateOptions.responseWriter = writer
+	}
+}
+
+// GenerateWithCapturePluginStderr returns a new GenerateOption that captures the
+// stderr of each local plugin instead of writing it to stderr as the plugin runs.
+//
+// The captured stderr of a plugin that fails is set on the returned PluginError. The
+// captured stderr of a plugin that succeeds is written to stderr once the plugin exits,
+// so that the stderr of plugins that are executed concurrently is not interleaved.
+func GenerateWithCapturePluginStderr() GenerateOption {
+	return func(generateOptions *generateOptions) {
+		generateOptions.capturePluginStderr = true
+	}
+}
+
+const (
+	// FileAnnotationTypePluginNotFound is the FileAnnotation type for a local plugin,
+	// or the protoc binary of a protoc built-in plugin, that cannot be found.
+	FileAnnotationTypePluginNotFound = "PLUGIN_NOT_FOUND"
+	// FileAnnotationTypePluginOutConflict is the FileAnnotation type for plugins that
+	// generate the same files to the same out.
+	FileAnnotationTypePluginOutConflict = "PLUGIN_OUT_CONFLICT"
+	// FileAnnotationTypeArchiveOutIncompatible is the FileAnnotation type for a plugin
+	// setting that cannot be used with an archive out.
+	FileAnnotationTypeArchiveOutIncompatible = "ARCHIVE_OUT_INCOMPATIBLE"
+	// FileAnnotationTypeStrategyIncompatible is the FileAnnotation type for a plugin
+	// setting that does not work as expected with the strategy of the plugin.
+	FileAnnotationTypeStrategyIncompatible = "STRATEGY_INCOMPATIBLE"
+	// FileAnnotationTypeOptionUnused is the FileAnnotation type for a plugin setting
+	// that has no effect.
+	FileAnnotationTypeOptionUnused = "OPTION_UNUSED"
+	// FileAnnotationTypeManagedRuleUnused is the FileAnnotation type for a managed mode
+	// disable or override rule that is never applied.
+	FileAnnotationTypeManagedRuleUnused = "MANAGED_RULE_UNUSED"
+)
+
+// VerifyConfig statically verifies the GenerateConfig read from the file, without
+// running any plugins.
+//
+// The following problems result in errors:
+//
+//   - Local plugins, and the protoc binary of protoc built-in plugins, that cannot be found.
+//   - Plugins that generate the same files to the same out.
+//   - Post commands or clean set on plugins with archive outs.
+//
+// The following problems result in warnings:
+//
+//   - Local fallbacks of remote plugins that cannot be found.
+//   - include_imports set on plugins with the directory strategy, which generates
+//     imported files once for every directory that imports them.
+//   - Plugin settings that have no effect, such as include_imports with the
+//     all_with_imports strategy, types that are also excluded, and routes that can
+//     never match.
+//   - Managed mode disable and override rules that are never applied, either because
+//     managed mode is not enabled, or because the override is disabled or replaced by
+//     a later override for all the files it applies to.
+//
+// Remote plugins are not resolved, as this requires a network connection.
+//
+// If any problems are found, a bufanalysis.FileAnnotationSet is returned as the error,
+// with one FileAnnotation per problem.
+func VerifyConfig(fileInfo bufanalysis.FileInfo, config bufconfig.GenerateConfig) error {
+	return verifyConfig(fileInfo, config)
+}
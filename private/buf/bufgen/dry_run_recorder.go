@@ -0,0 +1,118 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/osext"
+)
+
+const (
+	dryRunActionCreate    = "create"
+	dryRunActionOverwrite = "overwrite"
+	dryRunActionDelete    = "delete"
+)
+
+// dryRunRecorder records the files that a generation would write and delete.
+type dryRunRecorder struct {
+	writePaths  map[string]struct{}
+	deletePaths map[string]struct{}
+}
+
+func newDryRunRecorder() *dryRunRecorder {
+	return &dryRunRecorder{
+		writePaths:  make(map[string]struct{}),
+		deletePaths: make(map[string]struct{}),
+	}
+}
+
+// AddWrite records that the file at the OS path would be written.
+func (d *dryRunRecorder) AddWrite(path string) {
+	d.writePaths[filepath.Clean(path)] = struct{}{}
+}
+
+// AddDelete records that the file at the OS path would be deleted.
+func (d *dryRunRecorder) AddDelete(path string) {
+	d.deletePaths[filepath.Clean(path)] = struct{}{}
+}
+
+// Print prints the action for each recorded file, sorted by path.
+//
+// A file that is deleted and then written is reported as overwritten.
+func (d *dryRunRecorder) Print(writer io.Writer) error {
+	pwd, err := osext.Getwd()
+	if err != nil {
+		return err
+	}
+	actionForDisplayPath := make(map[string]string)
+	for path := range d.deletePaths {
+		displayPath, err := getDryRunDisplayPath(pwd, path)
+		if err != nil {
+			return err
+		}
+		actionForDisplayPath[displayPath] = dryRunActionDelete
+	}
+	for path := range d.writePaths {
+		displayPath, err := getDryRunDisplayPath(pwd, path)
+		if err != nil {
+			return err
+		}
+		action := dryRunActionCreate
+		if _, ok := actionForDisplayPath[displayPath]; ok {
+			action = dryRunActionOverwrite
+		} else {
+			// OK to use os.Stat instead of os.Lstat here.
+			if _, err := os.Stat(path); err == nil {
+				action = dryRunActionOverwrite
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+		actionForDisplayPath[displayPath] = action
+	}
+	displayPaths := make([]string, 0, len(actionForDisplayPath))
+	for displayPath := range actionForDisplayPath {
+		displayPaths = append(displayPaths, displayPath)
+	}
+	sort.Strings(displayPaths)
+	for _, displayPath := range displayPaths {
+		if _, err := fmt.Fprintf(writer, "%s %s\n", actionForDisplayPath[displayPath], displayPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDryRunDisplayPath returns the path relative to pwd if the path is
+// contained within pwd, otherwise the absolute path.
+func getDryRunDisplayPath(pwd string, path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	relPath, err := filepath.Rel(pwd, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return absPath, nil
+	}
+	return relPath, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// PluginError is the error returned by Generate when a plugin fails.
+//
+// PluginErrors are returned for local plugins that fail to execute, and for any
+// plugin whose response cannot be written.
+type PluginError struct {
+	// PluginName is the name of the plugin, as specified in the buf.gen.yaml file.
+	PluginName string
+	// ExitCode is the exit code of the plugin process. It is zero if the plugin process
+	// did not exit with a non-zero exit code, for example if the plugin reported the
+	// error in its response.
+	ExitCode int
+	// Stderr is what the plugin wrote to stderr. It is only set for local plugins if
+	// GenerateWithCapturePluginStderr is used.
+	Stderr string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (p *PluginError) Error() string {
+	if p == nil {
+		return ""
+	}
+	return fmt.Sprintf("plugin %s: %v", p.PluginName, p.Err)
+}
+
+// Unwrap returns the underlying error.
+func (p *PluginError) Unwrap() error {
+	if p == nil {
+		return nil
+	}
+	return p.Err
+}
+
+func newPluginError(pluginName string, err error, stderr string) *PluginError {
+	var exitCode int
+	exitError := &exec.ExitError{}
+	if errors.As(err, &exitError) {
+		exitCode = exitError.ExitCode()
+	}
+	return &PluginError{
+		PluginName: pluginName,
+		ExitCode:   exitCode,
+		Stderr:     stderr,
+		Err:        err,
+	}
+}
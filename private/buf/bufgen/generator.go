@@ -15,17 +15,24 @@
 package bufgen
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	connect "connectrpc.com/connect"
 	"github.com/bufbuild/buf/private/buf/bufprotopluginexec"
+	"github.com/bufbuild/buf/private/bufpkg/bufcas"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage/bufimagemodify"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage/bufimageutil"
 	"github.com/bufbuild/buf/private/bufpkg/bufprotoplugin"
 	"github.com/bufbuild/buf/private/bufpkg/bufprotoplugin/bufprotopluginos"
 	"github.com/bufbuild/buf/private/bufpkg/bufremoteplugin"
@@ -34,9 +41,15 @@ import (
 	registryv1alpha1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/registry/v1alpha1"
 	"github.com/bufbuild/buf/private/pkg/app"
 	"github.com/bufbuild/buf/private/pkg/connectclient"
+	"github.com/bufbuild/buf/private/pkg/execext"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
 	"github.com/bufbuild/buf/private/pkg/slicesext"
+	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
+	"github.com/bufbuild/buf/private/pkg/syserror"
 	"github.com/bufbuild/buf/private/pkg/thread"
+	"github.com/bufbuild/buf/private/pkg/tmp"
+	"github.com/bufbuild/buf/private/pkg/wasm"
 	"google.golang.org/protobuf/types/pluginpb"
 )
 
@@ -45,18 +58,21 @@ type generator struct {
 	storageosProvider   storageos.Provider
 	pluginexecGenerator bufprotopluginexec.Generator
 	clientConfig        *connectclient.Config
+	wasmRuntime         wasm.Runtime
 }
 
 func newGenerator(
 	logger *slog.Logger,
 	storageosProvider storageos.Provider,
 	clientConfig *connectclient.Config,
+	wasmRuntime wasm.Runtime,
 ) *generator {
 	return &generator{
 		logger:              logger,
 		storageosProvider:   storageosProvider,
 		pluginexecGenerator: bufprotopluginexec.NewGenerator(logger, storageosProvider),
 		clientConfig:        clientConfig,
+		wasmRuntime:         wasmRuntime,
 	}
 }
 
@@ -93,106 +109,483 @@ func (g *generator) Generate(
 			g.logger.Warn("managed mode configs are set but are not enabled")
 		}
 	}
+	var provenanceRecorder *provenanceRecorder
+	if generateOptions.provenanceFilePath != "" {
+		provenanceRecorder = newProvenanceRecorder(
+			generateOptions.bufVersion,
+			config.GeneratePluginConfigs(),
+			generateOptions.includeImportsOverride,
+			generateOptions.includeWellKnownTypesOverride,
+		)
+		for _, image := range images {
+			if err := provenanceRecorder.AddImage(image); err != nil {
+				return err
+			}
+		}
+	}
 	for _, image := range images {
 		if err := bufimagemodify.Modify(image, config.GenerateManagedConfig()); err != nil {
 			return err
 		}
 	}
+	for _, pluginConfig := range config.GeneratePluginConfigs() {
+		if len(pluginConfig.PostCommands()) > 0 && bufprotopluginos.IsArchivePath(pluginConfig.Out()) {
+			return fmt.Errorf("plugin %s: post commands cannot be used with archive out %s", pluginConfig.Name(), pluginConfig.Out())
+		}
+		for _, pluginOut := range getPluginOuts("", pluginConfig) {
+			if pluginConfig.Clean() && bufprotopluginos.IsArchivePath(pluginOut) {
+				return fmt.Errorf("plugin %s: clean cannot be used with archive out %s, archives are always overwritten", pluginConfig.Name(), pluginOut)
+			}
+		}
+	}
+	imageGenerations, err := getImageGenerations(images, config.GeneratePluginConfigs(), generateOptions.inputConfigs)
+	if err != nil {
+		return err
+	}
+	profileRecorder, err := getProfileRecorder(generateOptions.profile)
+	if err != nil {
+		return err
+	}
+	if profileRecorder != nil {
+		profileRecorder.setPluginConfigs(getAllPluginConfigs(imageGenerations))
+	}
 	shouldDeleteOuts := config.CleanPluginOuts()
 	if generateOptions.deleteOuts != nil {
 		shouldDeleteOuts = *generateOptions.deleteOuts
 	}
+	var dryRunRecorder *dryRunRecorder
+	if generateOptions.dryRun {
+		dryRunRecorder = newDryRunRecorder()
+	}
+	var stderrRecorder *pluginStderrRecorder
+	if generateOptions.capturePluginStderr {
+		stderrRecorder = newPluginStderrRecorder(container.Stderr())
+	}
+	var responseCache *remotePluginResponseCache
+	if generateOptions.remotePluginResponseCacheBucket != nil {
+		responseCache = newRemotePluginResponseCache(
+			g.logger,
+			generateOptions.remotePluginResponseCacheBucket,
+			generateOptions.remotePluginResponseCacheTTL,
+			// Nothing is written to disk during a dry run.
+			generateOptions.dryRun,
+		)
+	}
+	remotePluginRetrier := newRemotePluginRetrier(
+		g.logger,
+		generateOptions.remotePluginMaxRetries,
+		generateOptions.remotePluginBackoff,
+		generateOptions.remotePluginTimeout,
+	)
+	if generateOptions.responseWriter != nil {
+		if bufprotopluginos.IsArchivePath(generateOptions.baseOutDirPath) {
+			return fmt.Errorf("cannot write the CodeGeneratorResponse when generating to archive %s", generateOptions.baseOutDirPath)
+		}
+		// Nothing is written to the plugin outs, so there is nothing to delete, and no
+		// post commands are run.
+		responseRecorder := newResponseRecorder()
+		for _, imageGeneration := range imageGenerations {
+			if err := g.generateCode(
+				ctx,
+				container,
+				imageGeneration,
+				generateOptions.baseOutDirPath,
+				generateOptions.includeImportsOverride,
+				generateOptions.includeWellKnownTypesOverride,
+				responseCache,
+				remotePluginRetrier,
+				provenanceRecorder,
+				nil,
+				profileRecorder,
+				nil,
+				stderrRecorder,
+				responseRecorder,
+			); err != nil {
+				return err
+			}
+		}
+		if err := responseRecorder.Write(generateOptions.responseWriter); err != nil {
+			return err
+		}
+		return g.writeProvenance(container, generateOptions.provenanceFilePath, provenanceRecorder, dryRunRecorder)
+	}
+	if bufprotopluginos.IsArchivePath(generateOptions.baseOutDirPath) {
+		// The archive is always overwritten in its entirety, so there is nothing to delete.
+		if err := g.generateArchive(
+			ctx,
+			container,
+			imageGenerations,
+			generateOptions.baseOutDirPath,
+			config.GeneratePluginConfigs(),
+			generateOptions.includeImportsOverride,
+			generateOptions.includeWellKnownTypesOverride,
+			responseCache,
+			remotePluginRetrier,
+			provenanceRecorder,
+			profileRecorder,
+			dryRunRecorder,
+			stderrRecorder,
+		); err != nil {
+			return err
+		}
+		return g.writeProvenance(container, generateOptions.provenanceFilePath, provenanceRecorder, dryRunRecorder)
+	}
 	if shouldDeleteOuts {
 		if err := g.deleteOuts(
 			ctx,
 			generateOptions.baseOutDirPath,
-			config.GeneratePluginConfigs(),
+			imageGenerations,
+			dryRunRecorder,
 		); err != nil {
 			return err
 		}
 	}
-	for _, image := range images {
+	var pluginManifestRecorder *pluginManifestRecorder
+	if cleanPluginOuts := getCleanPluginOuts(generateOptions.baseOutDirPath, imageGenerations); len(cleanPluginOuts) > 0 {
+		for _, pluginOut := range cleanPluginOuts {
+			if err := cleanPluginOut(ctx, g.storageosProvider, pluginOut, dryRunRecorder); err != nil {
+				return err
+			}
+		}
+		pluginManifestRecorder = newPluginManifestRecorder()
+	}
+	for _, imageGeneration := range imageGenerations {
 		if err := g.generateCode(
 			ctx,
 			container,
-			image,
+			imageGeneration,
 			generateOptions.baseOutDirPath,
-			config.GeneratePluginConfigs(),
 			generateOptions.includeImportsOverride,
 			generateOptions.includeWellKnownTypesOverride,
+			responseCache,
+			remotePluginRetrier,
+			provenanceRecorder,
+			pluginManifestRecorder,
+			profileRecorder,
+			dryRunRecorder,
+			stderrRecorder,
+			nil,
+		); err != nil {
+			return err
+		}
+	}
+	if pluginManifestRecorder != nil {
+		if err := pluginManifestRecorder.Write(ctx, g.storageosProvider, dryRunRecorder); err != nil {
+			return err
+		}
+	}
+	if dryRunRecorder == nil {
+		if err := g.runPostCommands(
+			ctx,
+			container,
+			generateOptions.baseOutDirPath,
+			imageGenerations,
+		); err != nil {
+			return err
+		}
+	}
+	return g.writeProvenance(container, generateOptions.provenanceFilePath, provenanceRecorder, dryRunRecorder)
+}
+
+// writeProvenance writes the provenance file if provenanceRecorder is set, and
+// then prints the dry run if dryRunRecorder is set.
+func (g *generator) writeProvenance(
+	container app.EnvStdioContainer,
+	provenanceFilePath string,
+	// May be nil.
+	provenanceRecorder *provenanceRecorder,
+	// May be nil.
+	dryRunRecorder *dryRunRecorder,
+) error {
+	if dryRunRecorder != nil {
+		if provenanceRecorder != nil {
+			dryRunRecorder.AddWrite(provenanceFilePath)
+		}
+		return dryRunRecorder.Print(container.Stdout())
+	}
+	if provenanceRecorder != nil {
+		return provenanceRecorder.Write(provenanceFilePath)
+	}
+	return nil
+}
+
+// generateArchive generates all of the images into a temporary directory, and then
+// writes the contents of the directory to the archive at archivePath.
+//
+// Plugin outs are interpreted relative to the root of the archive.
+func (g *generator) generateArchive(
+	ctx context.Context,
+	container app.EnvStdioContainer,
+	imageGenerations []*imageGeneration,
+	archivePath string,
+	pluginConfigs []bufconfig.GeneratePluginConfig,
+	includeImportsOverride *bool,
+	includeWellKnownTypesOverride *bool,
+	// May be nil.
+	responseCache *remotePluginResponseCache,
+	remotePluginRetrier *remotePluginRetrier,
+	// May be nil.
+	provenanceRecorder *provenanceRecorder,
+	// May be nil.
+	profileRecorder *profileRecorder,
+	// May be nil.
+	dryRunRecorder *dryRunRecorder,
+	// May be nil.
+	stderrRecorder *pluginStderrRecorder,
+) (retErr error) {
+	for _, pluginConfig := range pluginConfigs {
+		for _, pluginOut := range getPluginOuts("", pluginConfig) {
+			if _, err := normalpath.NormalizeAndValidate(pluginOut); err != nil {
+				return fmt.Errorf("plugin %s: out must be a relative path within the archive %s: %w", pluginConfig.Name(), archivePath, err)
+			}
+		}
+	}
+	tmpDir, err := tmp.NewDir(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, tmpDir.Close())
+	}()
+	for _, imageGeneration := range imageGenerations {
+		if err := g.generateCode(
+			ctx,
+			container,
+			imageGeneration,
+			tmpDir.Path(),
+			includeImportsOverride,
+			includeWellKnownTypesOverride,
+			responseCache,
+			remotePluginRetrier,
+			provenanceRecorder,
+			nil,
+			profileRecorder,
+			nil,
+			stderrRecorder,
+			nil,
 		); err != nil {
 			return err
 		}
 	}
+	if dryRunRecorder != nil {
+		dryRunRecorder.AddWrite(archivePath)
+		return nil
+	}
+	if err := g.runPostCommands(ctx, container, tmpDir.Path(), imageGenerations); err != nil {
+		return err
+	}
+	readBucket, err := g.storageosProvider.NewReadWriteBucket(tmpDir.Path())
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	if err := bufprotopluginos.WriteArchive(ctx, readBucket, archivePath); err != nil {
+		return err
+	}
+	if profileRecorder != nil {
+		profileRecorder.addWriting(time.Since(start))
+	}
+	return nil
+}
+
+// runPostCommands runs the post commands of each plugin in the plugin's output
+// directory, in the order the plugins are specified.
+//
+// If a plugin generates to the same output directory for multiple images, its
+// post commands are only run once in that directory.
+func (g *generator) runPostCommands(
+	ctx context.Context,
+	container app.EnvStdioContainer,
+	baseOutDir string,
+	imageGenerations []*imageGeneration,
+) error {
+	type pluginOut struct {
+		pluginConfig bufconfig.GeneratePluginConfig
+		out          string
+	}
+	seenPluginOuts := make(map[pluginOut]struct{})
+	for _, pluginConfig := range getAllPluginConfigs(imageGenerations) {
+		for _, imageGeneration := range imageGenerations {
+			if !slices.Contains(imageGeneration.pluginConfigs, pluginConfig) {
+				continue
+			}
+			out := getPluginOut(imageGeneration.getBaseOutDir(baseOutDir), pluginConfig)
+			if _, ok := seenPluginOuts[pluginOut{pluginConfig: pluginConfig, out: out}]; ok {
+				continue
+			}
+			seenPluginOuts[pluginOut{pluginConfig: pluginConfig, out: out}] = struct{}{}
+			if err := g.runPluginPostCommands(ctx, container, pluginConfig, out); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runPluginPostCommands runs the post commands of the plugin in the output directory.
+func (g *generator) runPluginPostCommands(
+	ctx context.Context,
+	container app.EnvStdioContainer,
+	pluginConfig bufconfig.GeneratePluginConfig,
+	out string,
+) error {
+	for _, postCommand := range pluginConfig.PostCommands() {
+		postCommandString := strings.Join(postCommand, " ")
+		g.logger.DebugContext(
+			ctx,
+			"running post command",
+			slog.String("plugin", pluginConfig.Name()),
+			slog.String("command", postCommandString),
+			slog.String("dir", out),
+		)
+		// Output of post commands is written to stderr, as stdout is reserved
+		// for the output of buf generate itself, such as with --dry-run.
+		if err := execext.Run(
+			ctx,
+			postCommand[0],
+			execext.WithArgs(postCommand[1:]...),
+			execext.WithDir(out),
+			execext.WithEnv(app.Environ(container)),
+			execext.WithStdout(container.Stderr()),
+			execext.WithStderr(container.Stderr()),
+		); err != nil {
+			return fmt.Errorf("plugin %s: post command %q failed: %w", pluginConfig.Name(), postCommandString, err)
+		}
+	}
 	return nil
 }
 
 func (g *generator) deleteOuts(
 	ctx context.Context,
 	baseOutDir string,
-	pluginConfigs []bufconfig.GeneratePluginConfig,
+	imageGenerations []*imageGeneration,
+	// May be nil.
+	dryRunRecorder *dryRunRecorder,
 ) error {
-	return bufprotopluginos.NewCleaner(g.storageosProvider).DeleteOuts(
-		ctx,
-		slicesext.Map(
-			pluginConfigs,
-			func(pluginConfig bufconfig.GeneratePluginConfig) string {
-				out := pluginConfig.Out()
-				if baseOutDir != "" && baseOutDir != "." {
-					return filepath.Join(baseOutDir, out)
-				}
-				return out
-			},
-		),
-	)
+	var pluginOuts []string
+	for _, imageGeneration := range imageGenerations {
+		imageGenerationBaseOutDir := imageGeneration.getBaseOutDir(baseOutDir)
+		for _, pluginConfig := range imageGeneration.pluginConfigs {
+			pluginOuts = append(pluginOuts, getPluginOuts(imageGenerationBaseOutDir, pluginConfig)...)
+		}
+	}
+	pluginOuts = slicesext.ToUniqueSorted(pluginOuts)
+	cleaner := bufprotopluginos.NewCleaner(g.storageosProvider)
+	if dryRunRecorder != nil {
+		paths, err := cleaner.ListOuts(ctx, pluginOuts)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			dryRunRecorder.AddDelete(path)
+		}
+		return nil
+	}
+	return cleaner.DeleteOuts(ctx, pluginOuts)
 }
 
 func (g *generator) generateCode(
 	ctx context.Context,
 	container app.EnvStdioContainer,
-	inputImage bufimage.Image,
+	imageGeneration *imageGeneration,
 	baseOutDir string,
-	pluginConfigs []bufconfig.GeneratePluginConfig,
 	includeImportsOverride *bool,
 	includeWellKnownTypesOverride *bool,
+	// May be nil.
+	responseCache *remotePluginResponseCache,
+	remotePluginRetrier *remotePluginRetrier,
+	// May be nil.
+	provenanceRecorder *provenanceRecorder,
+	// May be nil.
+	pluginManifestRecorder *pluginManifestRecorder,
+	// May be nil.
+	profileRecorder *profileRecorder,
+	// May be nil.
+	dryRunRecorder *dryRunRecorder,
+	// May be nil.
+	stderrRecorder *pluginStderrRecorder,
+	// May be nil. If set, the responses are recorded instead of written.
+	responseRecorder *responseRecorder,
 ) error {
+	pluginConfigs := imageGeneration.pluginConfigs
+	start := time.Now()
 	responses, err := g.execPlugins(
 		ctx,
 		container,
 		pluginConfigs,
-		inputImage,
+		imageGeneration.image,
 		includeImportsOverride,
 		includeWellKnownTypesOverride,
+		responseCache,
+		remotePluginRetrier,
+		profileRecorder,
+		stderrRecorder,
 	)
 	if err != nil {
 		return err
 	}
+	if profileRecorder != nil {
+		profileRecorder.addPluginExecution(time.Since(start))
+	}
+	if provenanceRecorder != nil {
+		if err := provenanceRecorder.AddResponses(pluginConfigs, responses, imageGeneration.outPrefix); err != nil {
+			return err
+		}
+	}
+	imageGenerationBaseOutDir := imageGeneration.getBaseOutDir(baseOutDir)
+	outRouter := newOutRouter(imageGeneration.image)
+	if responseRecorder != nil {
+		for i, pluginConfig := range pluginConfigs {
+			response := responses[i]
+			if response == nil {
+				return fmt.Errorf("failed to get plugin response for %s", pluginConfig.Name())
+			}
+			for _, routedResponse := range outRouter.routeResponse(imageGenerationBaseOutDir, pluginConfig, response) {
+				responseRecorder.AddResponse(routedResponse.out, routedResponse.response)
+			}
+		}
+		return nil
+	}
+	start = time.Now()
 	// Apply the CodeGeneratorResponses in the order they were specified.
+	responseWriterOptions := []bufprotopluginos.ResponseWriterOption{
+		bufprotopluginos.ResponseWriterWithCreateOutDirIfNotExists(),
+	}
+	if dryRunRecorder != nil {
+		responseWriterOptions = append(
+			responseWriterOptions,
+			bufprotopluginos.ResponseWriterWithDryRun(dryRunRecorder.AddWrite),
+		)
+	}
 	responseWriter := bufprotopluginos.NewResponseWriter(
 		g.logger,
 		g.storageosProvider,
-		bufprotopluginos.ResponseWriterWithCreateOutDirIfNotExists(),
+		responseWriterOptions...,
 	)
 	for i, pluginConfig := range pluginConfigs {
-		out := pluginConfig.Out()
-		if baseOutDir != "" && baseOutDir != "." {
-			out = filepath.Join(baseOutDir, out)
-		}
 		response := responses[i]
 		if response == nil {
 			return fmt.Errorf("failed to get plugin response for %s", pluginConfig.Name())
 		}
-		if err := responseWriter.AddResponse(
-			ctx,
-			response,
-			out,
-		); err != nil {
-			return fmt.Errorf("plugin %s: %v", pluginConfig.Name(), err)
+		for _, routedResponse := range outRouter.routeResponse(imageGenerationBaseOutDir, pluginConfig, response) {
+			if err := responseWriter.AddResponse(
+				ctx,
+				routedResponse.response,
+				routedResponse.out,
+			); err != nil {
+				return newPluginError(pluginConfig.Name(), err, "")
+			}
+			if pluginManifestRecorder != nil && pluginConfig.Clean() {
+				pluginManifestRecorder.AddResponse(routedResponse.out, routedResponse.response)
+			}
 		}
 	}
 	if err := responseWriter.Close(); err != nil {
 		return err
 	}
+	if profileRecorder != nil {
+		profileRecorder.addWriting(time.Since(start))
+	}
 	return nil
 }
 
@@ -203,6 +596,13 @@ func (g *generator) execPlugins(
 	image bufimage.Image,
 	includeImportsOverride *bool,
 	includeWellKnownTypesOverride *bool,
+	// May be nil.
+	responseCache *remotePluginResponseCache,
+	remotePluginRetrier *remotePluginRetrier,
+	// May be nil.
+	profileRecorder *profileRecorder,
+	// May be nil.
+	stderrRecorder *pluginStderrRecorder,
 ) ([]*pluginpb.CodeGeneratorResponse, error) {
 	imageProvider := newImageProvider(image)
 	// Collect all of the plugin jobs so that they can be executed in parallel.
@@ -217,7 +617,45 @@ func (g *generator) execPlugins(
 		//
 		// We should be using the enum here.
 		remote := currentPluginConfig.RemoteHost()
-		if remote != "" {
+		pluginImage, err := getPluginImage(image, currentPluginConfig)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case remote != "" && pluginImage != image:
+			// Plugins with a filtered image cannot be batched with the other
+			// plugins for the remote, as the image is sent once per batch.
+			jobs = append(jobs, func(ctx context.Context) error {
+				start := time.Now()
+				results, err := g.execRemotePluginsWithLocalFallback(
+					ctx,
+					container,
+					pluginImage,
+					remote,
+					[]*remotePluginExecArgs{
+						{
+							Index:        index,
+							PluginConfig: currentPluginConfig,
+						},
+					},
+					includeImportsOverride,
+					includeWellKnownTypesOverride,
+					responseCache,
+					remotePluginRetrier,
+					stderrRecorder,
+				)
+				if err != nil {
+					return err
+				}
+				if profileRecorder != nil {
+					profileRecorder.addPlugin(currentPluginConfig, time.Since(start))
+				}
+				for _, result := range results {
+					responses[result.Index] = result.CodeGeneratorResponse
+				}
+				return nil
+			})
+		case remote != "":
 			remotePluginConfigTable[remote] = append(
 				remotePluginConfigTable[remote],
 				&remotePluginExecArgs{
@@ -225,7 +663,11 @@ func (g *generator) execPlugins(
 					PluginConfig: currentPluginConfig,
 				},
 			)
-		} else {
+		default:
+			pluginImageProvider := imageProvider
+			if pluginImage != image {
+				pluginImageProvider = newImageProvider(pluginImage)
+			}
 			jobs = append(jobs, func(ctx context.Context) error {
 				includeImports := currentPluginConfig.IncludeImports()
 				if includeImportsOverride != nil {
@@ -235,17 +677,26 @@ func (g *generator) execPlugins(
 				if includeWellKnownTypesOverride != nil {
 					includeWellKnownTypes = *includeWellKnownTypesOverride
 				}
+				if Strategy(currentPluginConfig.Strategy()) == StrategyAllWithImports {
+					includeImports = true
+					includeWellKnownTypes = true
+				}
+				start := time.Now()
 				response, err := g.execLocalPlugin(
 					ctx,
 					container,
-					imageProvider,
+					pluginImageProvider,
 					currentPluginConfig,
 					includeImports,
 					includeWellKnownTypes,
+					stderrRecorder,
 				)
 				if err != nil {
 					return err
 				}
+				if profileRecorder != nil {
+					profileRecorder.addPlugin(currentPluginConfig, time.Since(start))
+				}
 				responses[index] = response
 				return nil
 			})
@@ -255,7 +706,8 @@ func (g *generator) execPlugins(
 	for remote, indexedPluginConfigs := range remotePluginConfigTable {
 		if len(indexedPluginConfigs) > 0 {
 			jobs = append(jobs, func(ctx context.Context) error {
-				results, err := g.execRemotePluginsV2(
+				start := time.Now()
+				results, err := g.execRemotePluginsWithLocalFallback(
 					ctx,
 					container,
 					image,
@@ -263,10 +715,19 @@ func (g *generator) execPlugins(
 					indexedPluginConfigs,
 					includeImportsOverride,
 					includeWellKnownTypesOverride,
+					responseCache,
+					remotePluginRetrier,
+					stderrRecorder,
 				)
 				if err != nil {
 					return err
 				}
+				if profileRecorder != nil {
+					duration := time.Since(start)
+					for _, indexedPluginConfig := range indexedPluginConfigs {
+						profileRecorder.addPlugin(indexedPluginConfig.PluginConfig, duration)
+					}
+				}
 				for _, result := range results {
 					responses[result.Index] = result.CodeGeneratorResponse
 				}
@@ -302,6 +763,34 @@ func (g *generator) execPlugins(
 	return responses, nil
 }
 
+// getPluginImage returns the image to generate with for the plugin. If the plugin
+// filters by types, this is a filtered copy of the image, otherwise it is the
+// image itself.
+func getPluginImage(
+	image bufimage.Image,
+	pluginConfig bufconfig.GeneratePluginConfig,
+) (bufimage.Image, error) {
+	types := pluginConfig.Types()
+	excludeTypes := pluginConfig.ExcludeTypes()
+	if len(types) == 0 && len(excludeTypes) == 0 {
+		return image, nil
+	}
+	// Filtering mutates the image, and the image is shared between plugins.
+	clonedImage, err := bufimage.CloneImage(image)
+	if err != nil {
+		return nil, err
+	}
+	var options []bufimageutil.ImageFilterOption
+	if len(excludeTypes) > 0 {
+		options = append(options, bufimageutil.WithExcludeTypes(excludeTypes...))
+	}
+	filteredImage, err := bufimageutil.ImageFilteredByTypesWithOptions(clonedImage, types, options...)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", pluginConfig.Name(), err)
+	}
+	return filteredImage, nil
+}
+
 func (g *generator) execLocalPlugin(
 	ctx context.Context,
 	container app.EnvStdioContainer,
@@ -309,6 +798,8 @@ func (g *generator) execLocalPlugin(
 	pluginConfig bufconfig.GeneratePluginConfig,
 	includeImports bool,
 	includeWellKnownTypes bool,
+	// May be nil.
+	stderrRecorder *pluginStderrRecorder,
 ) (*pluginpb.CodeGeneratorResponse, error) {
 	pluginImages, err := imageProvider.GetImages(Strategy(pluginConfig.Strategy()))
 	if err != nil {
@@ -324,20 +815,116 @@ func (g *generator) execLocalPlugin(
 	if err != nil {
 		return nil, err
 	}
+	var pluginContainer app.EnvStderrContainer = container
+	var stderrBuffer *bytes.Buffer
+	if stderrRecorder != nil {
+		pluginContainer, stderrBuffer = stderrRecorder.newContainer(container)
+	}
 	response, err := g.pluginexecGenerator.Generate(
 		ctx,
-		container,
+		pluginContainer,
 		pluginConfig.Name(),
 		requests,
 		bufprotopluginexec.GenerateWithPluginPath(pluginConfig.Path()...),
 		bufprotopluginexec.GenerateWithProtocPath(pluginConfig.ProtocPath()...),
+		bufprotopluginexec.GenerateWithWasmRuntime(g.wasmRuntime),
 	)
+	var stderr string
+	if stderrBuffer != nil {
+		stderr = stderrBuffer.String()
+	}
 	if err != nil {
-		return nil, fmt.Errorf("plugin %s: %v", pluginConfig.Name(), err)
+		if ctx.Err() != nil {
+			// The plugin was cancelled, most likely because another plugin failed.
+			return nil, fmt.Errorf("plugin %s: %v", pluginConfig.Name(), err)
+		}
+		return nil, newPluginError(pluginConfig.Name(), err, stderr)
+	}
+	if stderrRecorder != nil {
+		if err := stderrRecorder.write([]byte(stderr)); err != nil {
+			return nil, err
+		}
 	}
 	return response, nil
 }
 
+// execRemotePluginsWithLocalFallback executes the remote plugins with execRemotePluginsV2.
+//
+// If the remote is unavailable, each plugin is executed with its local fallback
+// instead. If any of the plugins do not have a local fallback, the error is returned.
+func (g *generator) execRemotePluginsWithLocalFallback(
+	ctx context.Context,
+	container app.EnvStdioContainer,
+	image bufimage.Image,
+	remote string,
+	pluginConfigs []*remotePluginExecArgs,
+	includeImportsOverride *bool,
+	includeWellKnownTypesOverride *bool,
+	// May be nil.
+	responseCache *remotePluginResponseCache,
+	remotePluginRetrier *remotePluginRetrier,
+	// May be nil.
+	stderrRecorder *pluginStderrRecorder,
+) ([]*remotePluginExecutionResult, error) {
+	results, err := g.execRemotePluginsV2(
+		ctx,
+		container,
+		image,
+		remote,
+		pluginConfigs,
+		includeImportsOverride,
+		includeWellKnownTypesOverride,
+		responseCache,
+		remotePluginRetrier,
+	)
+	remoteUnavailableError := &remoteUnavailableError{}
+	if err == nil || !errors.As(err, &remoteUnavailableError) {
+		return results, err
+	}
+	for _, pluginConfig := range pluginConfigs {
+		if pluginConfig.PluginConfig.LocalFallback() == nil {
+			return nil, fmt.Errorf("plugin %s: %w", pluginConfig.PluginConfig.Name(), err)
+		}
+	}
+	imageProvider := newImageProvider(image)
+	results = make([]*remotePluginExecutionResult, 0, len(pluginConfigs))
+	for _, pluginConfig := range pluginConfigs {
+		localFallback := pluginConfig.PluginConfig.LocalFallback()
+		g.logger.WarnContext(
+			ctx,
+			"remote is unavailable, falling back to local plugin",
+			slog.String("plugin", pluginConfig.PluginConfig.Name()),
+			slog.String("local_fallback", localFallback.Name()),
+			slog.String("error", err.Error()),
+		)
+		includeImports := localFallback.IncludeImports()
+		if includeImportsOverride != nil {
+			includeImports = *includeImportsOverride
+		}
+		includeWellKnownTypes := localFallback.IncludeWKT()
+		if includeWellKnownTypesOverride != nil {
+			includeWellKnownTypes = *includeWellKnownTypesOverride
+		}
+		response, err := g.execLocalPlugin(
+			ctx,
+			container,
+			imageProvider,
+			localFallback,
+			includeImports,
+			includeWellKnownTypes,
+			stderrRecorder,
+		)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &remotePluginExecutionResult{
+			CodeGeneratorResponse: response,
+			Index:                 pluginConfig.Index,
+		})
+	}
+	return results, nil
+}
+
 type remotePluginExecArgs struct {
 	Index        int
 	PluginConfig bufconfig.GeneratePluginConfig
@@ -356,6 +943,9 @@ func (g *generator) execRemotePluginsV2(
 	pluginConfigs []*remotePluginExecArgs,
 	includeImportsOverride *bool,
 	includeWellKnownTypesOverride *bool,
+	// May be nil.
+	responseCache *remotePluginResponseCache,
+	remotePluginRetrier *remotePluginRetrier,
 ) ([]*remotePluginExecutionResult, error) {
 	requests := make([]*registryv1alpha1.PluginGenerationRequest, len(pluginConfigs))
 	for i, pluginConfig := range pluginConfigs {
@@ -377,36 +967,83 @@ func (g *generator) execRemotePluginsV2(
 		}
 		requests[i] = request
 	}
-	codeGenerationService := connectclient.Make(g.clientConfig, remote, registryv1alpha1connect.NewCodeGenerationServiceClient)
 	protoImage, err := bufimage.ImageToProtoImage(image)
 	if err != nil {
 		return nil, err
 	}
-	response, err := codeGenerationService.GenerateCode(
+	var imageDigest bufcas.Digest
+	if responseCache != nil {
+		imageDigest, err = getImageDigest(protoImage)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result := make([]*remotePluginExecutionResult, 0, len(requests))
+	// The indexes into requests of the requests that were not found in the cache,
+	// and the keys to cache their responses with, or empty if they are not cached.
+	var uncachedIndexes []int
+	var uncachedCacheKeys []string
+	for i, request := range requests {
+		var cacheKey string
+		if responseCache != nil {
+			key, ok, err := getRemotePluginResponseCacheKey(imageDigest, remote, request)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				if codeGeneratorResponse := responseCache.Get(ctx, key); codeGeneratorResponse != nil {
+					result = append(result, &remotePluginExecutionResult{
+						CodeGeneratorResponse: codeGeneratorResponse,
+						Index:                 pluginConfigs[i].Index,
+					})
+					continue
+				}
+				cacheKey = key
+			}
+		}
+		uncachedIndexes = append(uncachedIndexes, i)
+		uncachedCacheKeys = append(uncachedCacheKeys, cacheKey)
+	}
+	if len(uncachedIndexes) == 0 {
+		return result, nil
+	}
+	uncachedRequests := slicesext.Map(uncachedIndexes, func(i int) *registryv1alpha1.PluginGenerationRequest { return requests[i] })
+	codeGenerationService := connectclient.Make(g.clientConfig, remote, registryv1alpha1connect.NewCodeGenerationServiceClient)
+	var response *connect.Response[registryv1alpha1.GenerateCodeResponse]
+	if err := remotePluginRetrier.do(
 		ctx,
-		connect.NewRequest(
-			registryv1alpha1.GenerateCodeRequest_builder{
-				Image:    protoImage,
-				Requests: requests,
-			}.Build(),
-		),
-	)
-	if err != nil {
+		remote,
+		func(ctx context.Context) error {
+			var err error
+			response, err = codeGenerationService.GenerateCode(
+				ctx,
+				connect.NewRequest(
+					registryv1alpha1.GenerateCodeRequest_builder{
+						Image:    protoImage,
+						Requests: uncachedRequests,
+					}.Build(),
+				),
+			)
+			return err
+		},
+	); err != nil {
 		return nil, err
 	}
 	responses := response.Msg.GetResponses()
-	if len(responses) != len(requests) {
-		return nil, fmt.Errorf("unexpected number of responses received, got %d, wanted %d", len(responses), len(requests))
+	if len(responses) != len(uncachedRequests) {
+		return nil, fmt.Errorf("unexpected number of responses received, got %d, wanted %d", len(responses), len(uncachedRequests))
 	}
-	result := make([]*remotePluginExecutionResult, 0, len(responses))
-	for i := range requests {
+	for i, uncachedIndex := range uncachedIndexes {
 		codeGeneratorResponse := responses[i].GetResponse()
 		if codeGeneratorResponse == nil {
 			return nil, errors.New("expected code generator response")
 		}
+		if cacheKey := uncachedCacheKeys[i]; cacheKey != "" {
+			responseCache.Put(ctx, cacheKey, codeGeneratorResponse)
+		}
 		result = append(result, &remotePluginExecutionResult{
 			CodeGeneratorResponse: codeGeneratorResponse,
-			Index:                 pluginConfigs[i].Index,
+			Index:                 pluginConfigs[uncachedIndex].Index,
 		})
 	}
 	return result, nil
@@ -472,13 +1109,139 @@ func validateResponses(
 	return nil
 }
 
+// imageGeneration is the generation of a single Image with a subset of the plugins.
+type imageGeneration struct {
+	image         bufimage.Image
+	pluginConfigs []bufconfig.GeneratePluginConfig
+	// outPrefix is empty if the plugin outs are not prefixed.
+	outPrefix string
+}
+
+// getBaseOutDir returns the base output directory for the generation,
+// which is the baseOutDir joined with the out prefix, if any.
+func (i *imageGeneration) getBaseOutDir(baseOutDir string) string {
+	if i.outPrefix == "" {
+		return baseOutDir
+	}
+	if baseOutDir == "" || baseOutDir == "." {
+		return filepath.FromSlash(i.outPrefix)
+	}
+	return filepath.Join(baseOutDir, filepath.FromSlash(i.outPrefix))
+}
+
+// getImageGenerations pairs each image with the plugins to run on it.
+//
+// If inputConfigs is non-empty, it must be in the same order as the images,
+// and each image is generated with the plugins selected by its InputConfig,
+// under its out prefix.
+func getImageGenerations(
+	images []bufimage.Image,
+	pluginConfigs []bufconfig.GeneratePluginConfig,
+	inputConfigs []bufconfig.InputConfig,
+) ([]*imageGeneration, error) {
+	if len(inputConfigs) == 0 {
+		return slicesext.Map(
+			images,
+			func(image bufimage.Image) *imageGeneration {
+				return &imageGeneration{
+					image:         image,
+					pluginConfigs: pluginConfigs,
+				}
+			},
+		), nil
+	}
+	if len(inputConfigs) != len(images) {
+		return nil, syserror.Newf("expected %d input configs, got %d", len(images), len(inputConfigs))
+	}
+	imageGenerations := make([]*imageGeneration, len(images))
+	for i, image := range images {
+		inputConfig := inputConfigs[i]
+		imagePluginConfigs := pluginConfigs
+		if pluginNames := inputConfig.PluginNames(); len(pluginNames) > 0 {
+			imagePluginConfigs = slicesext.Filter(
+				pluginConfigs,
+				func(pluginConfig bufconfig.GeneratePluginConfig) bool {
+					return slices.Contains(pluginNames, pluginConfig.Name())
+				},
+			)
+		}
+		imageGenerations[i] = &imageGeneration{
+			image:         image,
+			pluginConfigs: imagePluginConfigs,
+			outPrefix:     inputConfig.OutPrefix(),
+		}
+	}
+	return imageGenerations, nil
+}
+
+// getAllPluginConfigs returns the plugins used by any of the imageGenerations,
+// in the order they are first used.
+func getAllPluginConfigs(imageGenerations []*imageGeneration) []bufconfig.GeneratePluginConfig {
+	var pluginConfigs []bufconfig.GeneratePluginConfig
+	for _, imageGeneration := range imageGenerations {
+		for _, pluginConfig := range imageGeneration.pluginConfigs {
+			if !slices.Contains(pluginConfigs, pluginConfig) {
+				pluginConfigs = append(pluginConfigs, pluginConfig)
+			}
+		}
+	}
+	return pluginConfigs
+}
+
+// getCleanPluginOuts returns the unique, sorted outs of the plugins that have clean set,
+// including the outs of their routes.
+func getCleanPluginOuts(baseOutDir string, imageGenerations []*imageGeneration) []string {
+	var pluginOuts []string
+	for _, imageGeneration := range imageGenerations {
+		imageGenerationBaseOutDir := imageGeneration.getBaseOutDir(baseOutDir)
+		for _, pluginConfig := range imageGeneration.pluginConfigs {
+			if !pluginConfig.Clean() {
+				continue
+			}
+			for _, pluginOut := range getPluginOuts(imageGenerationBaseOutDir, pluginConfig) {
+				pluginOuts = append(pluginOuts, filepath.Clean(pluginOut))
+			}
+		}
+	}
+	return slicesext.ToUniqueSorted(pluginOuts)
+}
+
+// getPluginOut returns the output path of the plugin relative to the baseOutDir.
+func getPluginOut(baseOutDir string, pluginConfig bufconfig.GeneratePluginConfig) string {
+	out := pluginConfig.Out()
+	if baseOutDir != "" && baseOutDir != "." {
+		return filepath.Join(baseOutDir, out)
+	}
+	return out
+}
+
 type generateOptions struct {
 	baseOutDirPath                string
 	deleteOuts                    *bool
 	includeImportsOverride        *bool
 	includeWellKnownTypesOverride *bool
+	dryRun                        bool
+	// remotePluginResponseCacheBucket is nil if remote plugin responses are not cached.
+	remotePluginResponseCacheBucket storage.ReadWriteBucket
+	remotePluginResponseCacheTTL    time.Duration
+	// provenanceFilePath is empty if no provenance file is written.
+	provenanceFilePath string
+	bufVersion         string
+	// profile is nil if no profile is recorded.
+	profile Profile
+	// inputConfigs is empty if all images are generated with all plugins.
+	inputConfigs        []bufconfig.InputConfig
+	capturePluginStderr bool
+	// responseWriter is nil if the generated files are written to the plugin outs.
+	responseWriter         io.Writer
+	remotePluginMaxRetries int
+	remotePluginBackoff    time.Duration
+	// remotePluginTimeout is 0 if requests to remotes have no timeout.
+	remotePluginTimeout time.Duration
 }
 
 func newGenerateOptions() *generateOptions {
-	return &generateOptions{}
+	return &generateOptions{
+		remotePluginBackoff: defaultRemotePluginBackoff,
+	}
 }
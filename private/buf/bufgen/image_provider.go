@@ -41,7 +41,7 @@ func newImageProvider(image bufimage.Image) *imageProvider {
 
 func (p *imageProvider) GetImages(strategy Strategy) ([]bufimage.Image, error) {
 	switch strategy {
-	case StrategyAll:
+	case StrategyAll, StrategyAllWithImports:
 		return []bufimage.Image{p.image}, nil
 	case StrategyDirectory:
 		p.lock.Lock()
@@ -0,0 +1,190 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// routedResponse is the part of a CodeGeneratorResponse that is written to a single out.
+type routedResponse struct {
+	out      string
+	response *pluginpb.CodeGeneratorResponse
+}
+
+// outRouter splits CodeGeneratorResponses across the routes of plugins.
+type outRouter struct {
+	// stemToPackage maps the path of each .proto file in the image, without the
+	// .proto extension, to its package.
+	stemToPackage map[string]string
+	// dirToPackages maps each directory of the .proto files in the image to the
+	// packages of the .proto files in the directory.
+	dirToPackages map[string]map[string]struct{}
+}
+
+func newOutRouter(image bufimage.Image) *outRouter {
+	stemToPackage := make(map[string]string)
+	dirToPackages := make(map[string]map[string]struct{})
+	for _, imageFile := range image.Files() {
+		path := imageFile.Path()
+		pkg := imageFile.FileDescriptorProto().GetPackage()
+		stemToPackage[strings.TrimSuffix(path, ".proto")] = pkg
+		dir := normalpath.Dir(path)
+		packages, ok := dirToPackages[dir]
+		if !ok {
+			packages = make(map[string]struct{})
+			dirToPackages[dir] = packages
+		}
+		packages[pkg] = struct{}{}
+	}
+	return &outRouter{
+		stemToPackage: stemToPackage,
+		dirToPackages: dirToPackages,
+	}
+}
+
+// routeResponse splits the response of the plugin across the out of the plugin and
+// the outs of its routes.
+//
+// The response for the out of the plugin is always first, and is the only response
+// with fields other than the generated files set. Routes that no files match are
+// not returned.
+func (r *outRouter) routeResponse(
+	baseOutDir string,
+	pluginConfig bufconfig.GeneratePluginConfig,
+	response *pluginpb.CodeGeneratorResponse,
+) []*routedResponse {
+	out := getPluginOut(baseOutDir, pluginConfig)
+	routes := pluginConfig.Routes()
+	if len(routes) == 0 {
+		return []*routedResponse{{out: out, response: response}}
+	}
+	routeFiles := make([][]*pluginpb.CodeGeneratorResponse_File, len(routes))
+	var outFiles []*pluginpb.CodeGeneratorResponse_File
+	for _, file := range response.GetFile() {
+		routeIndex := r.getRouteIndex(routes, file.GetName())
+		if routeIndex < 0 {
+			outFiles = append(outFiles, file)
+			continue
+		}
+		routeFiles[routeIndex] = append(routeFiles[routeIndex], file)
+	}
+	outResponse := &pluginpb.CodeGeneratorResponse{
+		Error:             response.Error,
+		SupportedFeatures: response.SupportedFeatures,
+		MinimumEdition:    response.MinimumEdition,
+		MaximumEdition:    response.MaximumEdition,
+		File:              outFiles,
+	}
+	routedResponses := []*routedResponse{{out: out, response: outResponse}}
+	for i, route := range routes {
+		if len(routeFiles[i]) == 0 {
+			continue
+		}
+		routedResponses = append(
+			routedResponses,
+			&routedResponse{
+				out: getRouteOut(baseOutDir, route),
+				response: &pluginpb.CodeGeneratorResponse{
+					File: routeFiles[i],
+				},
+			},
+		)
+	}
+	return routedResponses
+}
+
+// getRouteIndex returns the index of the first route that the generated file
+// matches, or -1 if the file matches no route.
+func (r *outRouter) getRouteIndex(routes []bufconfig.GenerateOutRoute, name string) int {
+	for i, route := range routes {
+		if suffix := route.Suffix(); suffix != "" && !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		if pkg := route.Package(); pkg != "" {
+			filePackage, ok := r.getPackage(name)
+			if !ok || !packageMatches(pkg, filePackage) {
+				continue
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// getPackage returns the package of the .proto file that the generated file was
+// generated from.
+//
+// A generated file is attributed to the .proto file with the longest path, without
+// the .proto extension, that its name starts with, such as a/v1/a_grpc.pb.go for
+// a/v1/a.proto. Otherwise, a generated file is attributed to the package of the
+// .proto files in its directory, if they all have the same package.
+func (r *outRouter) getPackage(name string) (string, bool) {
+	name = normalpath.Normalize(name)
+	var stem string
+	for candidateStem := range r.stemToPackage {
+		if len(candidateStem) <= len(stem) || !strings.HasPrefix(name, candidateStem) {
+			continue
+		}
+		if rest := name[len(candidateStem):]; rest == "" || strings.ContainsAny(rest[:1], "._") {
+			stem = candidateStem
+		}
+	}
+	if stem != "" {
+		return r.stemToPackage[stem], true
+	}
+	packages := r.dirToPackages[normalpath.Dir(name)]
+	if len(packages) != 1 {
+		return "", false
+	}
+	for pkg := range packages {
+		return pkg, true
+	}
+	return "", false
+}
+
+// packageMatches returns true if the package matches the package of the route.
+//
+// A package of a route ending in ".*" matches the package and all packages below it.
+func packageMatches(routePackage string, pkg string) bool {
+	if prefix, ok := strings.CutSuffix(routePackage, ".*"); ok {
+		return pkg == prefix || strings.HasPrefix(pkg, prefix+".")
+	}
+	return pkg == routePackage
+}
+
+// getPluginOuts returns the out of the plugin and the outs of its routes,
+// relative to the baseOutDir.
+func getPluginOuts(baseOutDir string, pluginConfig bufconfig.GeneratePluginConfig) []string {
+	pluginOuts := []string{getPluginOut(baseOutDir, pluginConfig)}
+	for _, route := range pluginConfig.Routes() {
+		pluginOuts = append(pluginOuts, getRouteOut(baseOutDir, route))
+	}
+	return pluginOuts
+}
+
+func getRouteOut(baseOutDir string, route bufconfig.GenerateOutRoute) string {
+	out := route.Out()
+	if baseOutDir != "" && baseOutDir != "." {
+		return filepath.Join(baseOutDir, out)
+	}
+	return out
+}
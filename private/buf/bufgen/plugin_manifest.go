@@ -0,0 +1,182 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storageos"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// pluginManifestFileName is the name of the manifest file written to the out
+// directory of plugins that have clean set.
+//
+// The manifest lists the paths of the files generated to the out directory,
+// relative to the out directory, one per line. Only the files listed in the
+// manifest are deleted when cleaning, so that files that were not generated,
+// such as handwritten files, are never deleted.
+const pluginManifestFileName = ".buf.gen.manifest"
+
+// pluginManifestRecorder records the files generated to the out directories of
+// plugins that have clean set.
+type pluginManifestRecorder struct {
+	pluginOutToPaths map[string]map[string]struct{}
+}
+
+func newPluginManifestRecorder() *pluginManifestRecorder {
+	return &pluginManifestRecorder{
+		pluginOutToPaths: make(map[string]map[string]struct{}),
+	}
+}
+
+// AddResponse records the files in the response as generated to the plugin out.
+//
+// Files that are only written to at an insertion point are not recorded, as
+// they are generated by another plugin.
+func (p *pluginManifestRecorder) AddResponse(pluginOut string, response *pluginpb.CodeGeneratorResponse) {
+	pluginOut = filepath.Clean(pluginOut)
+	paths, ok := p.pluginOutToPaths[pluginOut]
+	if !ok {
+		paths = make(map[string]struct{})
+		p.pluginOutToPaths[pluginOut] = paths
+	}
+	for _, file := range response.GetFile() {
+		if file.GetName() == "" || file.GetInsertionPoint() != "" {
+			continue
+		}
+		paths[normalpath.Normalize(file.GetName())] = struct{}{}
+	}
+}
+
+// Write writes the manifest to each recorded plugin out.
+func (p *pluginManifestRecorder) Write(
+	ctx context.Context,
+	storageosProvider storageos.Provider,
+	// May be nil.
+	dryRunRecorder *dryRunRecorder,
+) error {
+	pluginOuts := make([]string, 0, len(p.pluginOutToPaths))
+	for pluginOut := range p.pluginOutToPaths {
+		pluginOuts = append(pluginOuts, pluginOut)
+	}
+	sort.Strings(pluginOuts)
+	for _, pluginOut := range pluginOuts {
+		if dryRunRecorder != nil {
+			dryRunRecorder.AddWrite(filepath.Join(pluginOut, pluginManifestFileName))
+			continue
+		}
+		paths := make([]string, 0, len(p.pluginOutToPaths[pluginOut]))
+		for path := range p.pluginOutToPaths[pluginOut] {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		buffer := bytes.NewBuffer(nil)
+		for _, path := range paths {
+			buffer.WriteString(path)
+			buffer.WriteString("\n")
+		}
+		if err := os.MkdirAll(pluginOut, 0755); err != nil {
+			return err
+		}
+		bucket, err := storageosProvider.NewReadWriteBucket(pluginOut)
+		if err != nil {
+			return err
+		}
+		if err := storage.PutPath(ctx, bucket, pluginManifestFileName, buffer.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanPluginOut deletes the files listed in the manifest in the plugin out,
+// along with the manifest itself.
+//
+// If the plugin out or the manifest does not exist, this is a no-op.
+func cleanPluginOut(
+	ctx context.Context,
+	storageosProvider storageos.Provider,
+	pluginOut string,
+	// May be nil.
+	dryRunRecorder *dryRunRecorder,
+) error {
+	bucket, err := storageosProvider.NewReadWriteBucket(pluginOut)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	data, err := storage.ReadPath(ctx, bucket, pluginManifestFileName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var paths []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		path, err := normalpath.NormalizeAndValidate(line)
+		if err != nil {
+			return fmt.Errorf("invalid path %q in %s: %w", line, filepath.Join(pluginOut, pluginManifestFileName), err)
+		}
+		paths = append(paths, path)
+	}
+	paths = append(paths, pluginManifestFileName)
+	for _, path := range paths {
+		if dryRunRecorder != nil {
+			exists, err := storage.Exists(ctx, bucket, path)
+			if err != nil {
+				return err
+			}
+			if exists {
+				dryRunRecorder.AddDelete(filepath.Join(pluginOut, normalpath.Unnormalize(path)))
+			}
+			continue
+		}
+		if err := bucket.Delete(ctx, path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		removeEmptyParentDirs(pluginOut, path)
+	}
+	return nil
+}
+
+// removeEmptyParentDirs removes the parent directories of the path within the
+// plugin out that are empty, from the innermost outward, stopping at the first
+// directory that cannot be removed.
+func removeEmptyParentDirs(pluginOut string, path string) {
+	for dirPath := normalpath.Dir(path); dirPath != "."; dirPath = normalpath.Dir(dirPath) {
+		// os.Remove fails for non-empty directories.
+		if err := os.Remove(filepath.Join(pluginOut, normalpath.Unnormalize(dirPath))); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/bufbuild/buf/private/pkg/app"
+)
+
+// pluginStderrRecorder captures the stderr of each local plugin separately, so that
+// the stderr of plugins that are executed concurrently is not interleaved, and so that
+// it can be attached to the PluginError of a failed plugin.
+type pluginStderrRecorder struct {
+	stderr io.Writer
+	lock   sync.Mutex
+}
+
+func newPluginStderrRecorder(stderr io.Writer) *pluginStderrRecorder {
+	return &pluginStderrRecorder{
+		stderr: stderr,
+	}
+}
+
+// newContainer returns a container for executing a plugin whose stderr is captured
+// in the returned buffer.
+func (r *pluginStderrRecorder) newContainer(container app.EnvContainer) (app.EnvStderrContainer, *bytes.Buffer) {
+	buffer := bytes.NewBuffer(nil)
+	return &pluginStderrContainer{
+		EnvContainer:    container,
+		StderrContainer: app.NewStderrContainer(buffer),
+	}, buffer
+}
+
+// write writes the captured stderr of a plugin that succeeded.
+func (r *pluginStderrRecorder) write(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, err := r.stderr.Write(data)
+	return err
+}
+
+type pluginStderrContainer struct {
+	app.EnvContainer
+	app.StderrContainer
+}
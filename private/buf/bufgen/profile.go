@@ -0,0 +1,231 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/pkg/syserror"
+)
+
+// profileRecorder is the Profile implementation, which records the time spent
+// in each phase of a generation.
+type profileRecorder struct {
+	start time.Time
+	lock  sync.Mutex
+	// compilation is the time spent compiling the inputs.
+	compilation time.Duration
+	// pluginExecution is the wall time spent executing all plugins, which
+	// overlaps as plugins are executed concurrently.
+	pluginExecution time.Duration
+	// writing is the time spent writing the generated files.
+	writing time.Duration
+	// pluginProfiles are in the order the plugins were first executed.
+	pluginProfiles []*pluginProfile
+}
+
+type pluginProfile struct {
+	pluginConfig bufconfig.GeneratePluginConfig
+	remote       bool
+	invocations  int
+	duration     time.Duration
+}
+
+func newProfileRecorder() *profileRecorder {
+	return &profileRecorder{
+		start: time.Now(),
+	}
+}
+
+// getProfileRecorder returns the profileRecorder for the Profile, or nil if the
+// Profile is nil.
+func getProfileRecorder(profile Profile) (*profileRecorder, error) {
+	if profile == nil {
+		return nil, nil
+	}
+	profileRecorder, ok := profile.(*profileRecorder)
+	if !ok {
+		return nil, syserror.Newf("unknown Profile type: %T", profile)
+	}
+	return profileRecorder, nil
+}
+
+func (p *profileRecorder) AddCompilation(duration time.Duration) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.compilation += duration
+}
+
+func (p *profileRecorder) Print(writer io.Writer, format ProfileFormat) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	externalProfile := p.toExternal(time.Since(p.start))
+	switch format {
+	case ProfileFormatText:
+		return printProfileText(writer, externalProfile)
+	case ProfileFormatJSON:
+		data, err := json.MarshalIndent(externalProfile, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(append(data, '\n'))
+		return err
+	default:
+		return syserror.Newf("unknown ProfileFormat: %v", format)
+	}
+}
+
+// setPluginConfigs adds the plugins in the order they are listed, so that they
+// are printed in this order regardless of the order in which they finish.
+func (p *profileRecorder) setPluginConfigs(pluginConfigs []bufconfig.GeneratePluginConfig) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, pluginConfig := range pluginConfigs {
+		p.getPluginProfile(pluginConfig)
+	}
+}
+
+func (p *profileRecorder) addPluginExecution(duration time.Duration) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.pluginExecution += duration
+}
+
+func (p *profileRecorder) addPlugin(pluginConfig bufconfig.GeneratePluginConfig, duration time.Duration) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	pluginProfile := p.getPluginProfile(pluginConfig)
+	pluginProfile.invocations++
+	pluginProfile.duration += duration
+}
+
+func (p *profileRecorder) addWriting(duration time.Duration) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.writing += duration
+}
+
+// getPluginProfile must be called with the lock held.
+func (p *profileRecorder) getPluginProfile(pluginConfig bufconfig.GeneratePluginConfig) *pluginProfile {
+	index := slices.IndexFunc(
+		p.pluginProfiles,
+		func(pluginProfile *pluginProfile) bool {
+			return pluginProfile.pluginConfig == pluginConfig
+		},
+	)
+	if index >= 0 {
+		return p.pluginProfiles[index]
+	}
+	pluginProfile := &pluginProfile{
+		pluginConfig: pluginConfig,
+		// We're using this as a proxy for Type() == PluginConfigTypeRemote, as
+		// is done when executing plugins.
+		remote: pluginConfig.RemoteHost() != "",
+	}
+	p.pluginProfiles = append(p.pluginProfiles, pluginProfile)
+	return pluginProfile
+}
+
+func (p *profileRecorder) toExternal(total time.Duration) *externalProfile {
+	externalProfile := &externalProfile{
+		CompilationMs:     durationToMilliseconds(p.compilation),
+		PluginExecutionMs: durationToMilliseconds(p.pluginExecution),
+		WritingMs:         durationToMilliseconds(p.writing),
+		TotalMs:           durationToMilliseconds(total),
+		Plugins:           make([]*externalPluginProfile, 0, len(p.pluginProfiles)),
+	}
+	for _, pluginProfile := range p.pluginProfiles {
+		pluginType := "local"
+		if pluginProfile.remote {
+			pluginType = "remote"
+		}
+		externalProfile.Plugins = append(
+			externalProfile.Plugins,
+			&externalPluginProfile{
+				Name:        pluginProfile.pluginConfig.Name(),
+				Out:         pluginProfile.pluginConfig.Out(),
+				Type:        pluginType,
+				Invocations: pluginProfile.invocations,
+				DurationMs:  durationToMilliseconds(pluginProfile.duration),
+			},
+		)
+	}
+	return externalProfile
+}
+
+func (p *profileRecorder) isProfile() {}
+
+// externalProfile is the JSON representation of a profile.
+type externalProfile struct {
+	CompilationMs     float64                  `json:"compilation_ms"`
+	PluginExecutionMs float64                  `json:"plugin_execution_ms"`
+	WritingMs         float64                  `json:"writing_ms"`
+	TotalMs           float64                  `json:"total_ms"`
+	Plugins           []*externalPluginProfile `json:"plugins"`
+}
+
+type externalPluginProfile struct {
+	Name string `json:"name"`
+	Out  string `json:"out"`
+	// Type is either "local" or "remote".
+	Type        string  `json:"type"`
+	Invocations int     `json:"invocations"`
+	DurationMs  float64 `json:"duration_ms"`
+}
+
+func printProfileText(writer io.Writer, externalProfile *externalProfile) error {
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	lines := [][]string{
+		{"compilation", "", "", formatMilliseconds(externalProfile.CompilationMs)},
+		{"plugin execution", "", "", formatMilliseconds(externalProfile.PluginExecutionMs)},
+	}
+	for _, plugin := range externalProfile.Plugins {
+		lines = append(
+			lines,
+			[]string{
+				"  " + plugin.Name,
+				plugin.Type,
+				fmt.Sprintf("%dx", plugin.Invocations),
+				formatMilliseconds(plugin.DurationMs),
+			},
+		)
+	}
+	lines = append(
+		lines,
+		[]string{"writing", "", "", formatMilliseconds(externalProfile.WritingMs)},
+		[]string{"total", "", "", formatMilliseconds(externalProfile.TotalMs)},
+	)
+	for _, line := range lines {
+		if _, err := fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\n", line[0], line[1], line[2], line[3]); err != nil {
+			return err
+		}
+	}
+	return tabWriter.Flush()
+}
+
+func durationToMilliseconds(duration time.Duration) float64 {
+	return float64(duration.Microseconds()) / 1000
+}
+
+func formatMilliseconds(milliseconds float64) string {
+	return fmt.Sprintf("%.1fms", milliseconds)
+}
@@ -0,0 +1,395 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufcas"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufremoteplugin/bufremotepluginref"
+	"github.com/bufbuild/buf/private/pkg/syserror"
+	"github.com/bufbuild/buf/private/pkg/uuidutil"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// provenanceRecorder records the inputs, plugins, and outputs of a generation,
+// so that they can be written to a provenance file.
+type provenanceRecorder struct {
+	bufVersion                    string
+	pluginConfigs                 []bufconfig.GeneratePluginConfig
+	includeImportsOverride        *bool
+	includeWellKnownTypesOverride *bool
+	inputs                        []*externalProvenanceInput
+	// pluginIndexToFileToDigest contains the digests of the files generated by
+	// each plugin, indexed by the plugin's index in pluginConfigs.
+	pluginIndexToFileToDigest []map[provenanceFile]string
+}
+
+// provenanceFile is a file generated by a plugin.
+type provenanceFile struct {
+	// outPrefix is empty if the plugin out was not prefixed.
+	outPrefix string
+	path      string
+}
+
+func newProvenanceRecorder(
+	bufVersion string,
+	pluginConfigs []bufconfig.GeneratePluginConfig,
+	includeImportsOverride *bool,
+	includeWellKnownTypesOverride *bool,
+) *provenanceRecorder {
+	pluginIndexToFileToDigest := make([]map[provenanceFile]string, len(pluginConfigs))
+	for i := range pluginIndexToFileToDigest {
+		pluginIndexToFileToDigest[i] = make(map[provenanceFile]string)
+	}
+	return &provenanceRecorder{
+		bufVersion:                    bufVersion,
+		pluginConfigs:                 pluginConfigs,
+		includeImportsOverride:        includeImportsOverride,
+		includeWellKnownTypesOverride: includeWellKnownTypesOverride,
+		pluginIndexToFileToDigest:     pluginIndexToFileToDigest,
+	}
+}
+
+// AddImage records the Image as an input.
+//
+// This should be called before managed mode is applied to the Image, so that
+// the recorded digests are those of the input itself.
+func (p *provenanceRecorder) AddImage(image bufimage.Image) error {
+	protoImage, err := bufimage.ImageToProtoImage(image)
+	if err != nil {
+		return err
+	}
+	imageDigest, err := getImageDigest(protoImage)
+	if err != nil {
+		return err
+	}
+	modules, err := getExternalProvenanceModules(image)
+	if err != nil {
+		return err
+	}
+	p.inputs = append(
+		p.inputs,
+		&externalProvenanceInput{
+			Digest:  imageDigest.String(),
+			Modules: modules,
+		},
+	)
+	return nil
+}
+
+// AddResponses records the files generated by each plugin under the out prefix,
+// which is empty if the plugin outs were not prefixed.
+//
+// The responses must be in the same order as the pluginConfigs, which must be
+// a subset of the plugin configs the provenanceRecorder was created with.
+func (p *provenanceRecorder) AddResponses(
+	pluginConfigs []bufconfig.GeneratePluginConfig,
+	responses []*pluginpb.CodeGeneratorResponse,
+	outPrefix string,
+) error {
+	if len(responses) != len(pluginConfigs) {
+		return syserror.Newf("expected %d responses, got %d", len(pluginConfigs), len(responses))
+	}
+	for i, response := range responses {
+		pluginIndex := slices.Index(p.pluginConfigs, pluginConfigs[i])
+		if pluginIndex < 0 {
+			return syserror.Newf("unknown plugin %s", pluginConfigs[i].Name())
+		}
+		for _, file := range response.GetFile() {
+			// Insertion points modify files generated by other plugins, and do not
+			// result in files of their own.
+			if file.GetInsertionPoint() != "" {
+				continue
+			}
+			digest, err := bufcas.NewDigestForContent(bytes.NewReader([]byte(file.GetContent())))
+			if err != nil {
+				return err
+			}
+			p.pluginIndexToFileToDigest[pluginIndex][provenanceFile{outPrefix: outPrefix, path: file.GetName()}] = digest.String()
+		}
+	}
+	return nil
+}
+
+// Bytes returns the JSON content of the provenance file.
+func (p *provenanceRecorder) Bytes() ([]byte, error) {
+	externalPlugins := make([]*externalProvenancePlugin, len(p.pluginConfigs))
+	for i, pluginConfig := range p.pluginConfigs {
+		externalPlugin, err := getExternalProvenancePlugin(pluginConfig, p.pluginIndexToFileToDigest[i])
+		if err != nil {
+			return nil, err
+		}
+		if p.includeImportsOverride != nil {
+			externalPlugin.IncludeImports = *p.includeImportsOverride
+		}
+		if p.includeWellKnownTypesOverride != nil {
+			externalPlugin.IncludeWKT = *p.includeWellKnownTypesOverride
+		}
+		externalPlugins[i] = externalPlugin
+	}
+	data, err := json.MarshalIndent(
+		&externalProvenance{
+			BufVersion: p.bufVersion,
+			Inputs:     p.inputs,
+			Plugins:    externalPlugins,
+		},
+		"",
+		"  ",
+	)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Write writes the provenance file to the OS path, creating the parent
+// directory if it does not exist.
+func (p *provenanceRecorder) Write(path string) error {
+	data, err := p.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+type externalProvenance struct {
+	BufVersion string                      `json:"buf_version"`
+	Inputs     []*externalProvenanceInput  `json:"inputs"`
+	Plugins    []*externalProvenancePlugin `json:"plugins"`
+}
+
+type externalProvenanceInput struct {
+	Digest  string                      `json:"digest"`
+	Modules []*externalProvenanceModule `json:"modules"`
+}
+
+type externalProvenanceModule struct {
+	// Empty for files that do not belong to a named module.
+	Name string `json:"name,omitempty"`
+	// Empty if the commit is not known.
+	Commit string `json:"commit,omitempty"`
+	Digest string `json:"digest"`
+}
+
+type externalProvenancePlugin struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Only set for remote plugins.
+	Version string `json:"version,omitempty"`
+	// Only set for remote plugins.
+	Revision int `json:"revision,omitempty"`
+	// Only set for local and protoc built-in plugins.
+	Path []string `json:"path,omitempty"`
+	// The digest of the plugin binary, if it could be resolved. Only set for
+	// local and protoc built-in plugins.
+	BinaryDigest   string                    `json:"binary_digest,omitempty"`
+	Opt            string                    `json:"opt,omitempty"`
+	Strategy       string                    `json:"strategy,omitempty"`
+	IncludeImports bool                      `json:"include_imports,omitempty"`
+	IncludeWKT     bool                      `json:"include_wkt,omitempty"`
+	Types          []string                  `json:"types,omitempty"`
+	ExcludeTypes   []string                  `json:"exclude_types,omitempty"`
+	Out            string                    `json:"out"`
+	Files          []*externalProvenanceFile `json:"files"`
+}
+
+type externalProvenanceFile struct {
+	// OutPrefix is the out prefix of the input the file was generated for,
+	// if any. The file is written to the plugin out under this prefix.
+	OutPrefix string `json:"out_prefix,omitempty"`
+	Path      string `json:"path"`
+	Digest    string `json:"digest"`
+}
+
+// getExternalProvenanceModules returns the modules of the files in the Image,
+// sorted by name and commit.
+//
+// The digest of each module is the digest of a manifest of the files of the
+// module, where the digest of each file is that of its FileDescriptorProto.
+func getExternalProvenanceModules(image bufimage.Image) ([]*externalProvenanceModule, error) {
+	type moduleKey struct {
+		name     string
+		commitID uuid.UUID
+	}
+	moduleKeyToFileNodes := make(map[moduleKey][]bufcas.FileNode)
+	for _, imageFile := range image.Files() {
+		var key moduleKey
+		if fullName := imageFile.FullName(); fullName != nil {
+			key.name = fullName.String()
+		}
+		key.commitID = imageFile.CommitID()
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(imageFile.FileDescriptorProto())
+		if err != nil {
+			return nil, err
+		}
+		digest, err := bufcas.NewDigestForContent(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		fileNode, err := bufcas.NewFileNode(imageFile.Path(), digest)
+		if err != nil {
+			return nil, err
+		}
+		moduleKeyToFileNodes[key] = append(moduleKeyToFileNodes[key], fileNode)
+	}
+	modules := make([]*externalProvenanceModule, 0, len(moduleKeyToFileNodes))
+	for key, fileNodes := range moduleKeyToFileNodes {
+		manifest, err := bufcas.NewManifest(fileNodes)
+		if err != nil {
+			return nil, err
+		}
+		digest, err := bufcas.ManifestToDigest(manifest)
+		if err != nil {
+			return nil, err
+		}
+		module := &externalProvenanceModule{
+			Name:   key.name,
+			Digest: digest.String(),
+		}
+		if key.commitID != uuid.Nil {
+			module.Commit = uuidutil.ToDashless(key.commitID)
+		}
+		modules = append(modules, module)
+	}
+	sort.Slice(
+		modules,
+		func(i int, j int) bool {
+			if modules[i].Name != modules[j].Name {
+				return modules[i].Name < modules[j].Name
+			}
+			return modules[i].Commit < modules[j].Commit
+		},
+	)
+	return modules, nil
+}
+
+func getExternalProvenancePlugin(
+	pluginConfig bufconfig.GeneratePluginConfig,
+	fileToDigest map[provenanceFile]string,
+) (*externalProvenancePlugin, error) {
+	externalPlugin := &externalProvenancePlugin{
+		Name:           pluginConfig.Name(),
+		Opt:            pluginConfig.Opt(),
+		IncludeImports: pluginConfig.IncludeImports(),
+		IncludeWKT:     pluginConfig.IncludeWKT(),
+		Types:          pluginConfig.Types(),
+		ExcludeTypes:   pluginConfig.ExcludeTypes(),
+		Out:            pluginConfig.Out(),
+		Files:          make([]*externalProvenanceFile, 0, len(fileToDigest)),
+	}
+	switch pluginConfig.Type() {
+	case bufconfig.GeneratePluginConfigTypeRemote:
+		externalPlugin.Type = "remote"
+		externalPlugin.Revision = pluginConfig.Revision()
+		if reference, err := bufremotepluginref.PluginReferenceForString(pluginConfig.Name(), pluginConfig.Revision()); err == nil {
+			externalPlugin.Version = reference.Version()
+		}
+	case bufconfig.GeneratePluginConfigTypeLocal, bufconfig.GeneratePluginConfigTypeLocalOrProtocBuiltin:
+		externalPlugin.Type = "local"
+		externalPlugin.Path = pluginConfig.Path()
+		binaryDigest, err := getBinaryDigest(pluginConfig.Path(), "protoc-gen-"+pluginConfig.Name())
+		if err != nil {
+			return nil, err
+		}
+		externalPlugin.BinaryDigest = binaryDigest
+	case bufconfig.GeneratePluginConfigTypeProtocBuiltin:
+		externalPlugin.Type = "protoc_builtin"
+		externalPlugin.Path = pluginConfig.ProtocPath()
+		binaryDigest, err := getBinaryDigest(pluginConfig.ProtocPath(), "protoc")
+		if err != nil {
+			return nil, err
+		}
+		externalPlugin.BinaryDigest = binaryDigest
+	default:
+		return nil, fmt.Errorf("unknown plugin type: %v", pluginConfig.Type())
+	}
+	if pluginConfig.Type() != bufconfig.GeneratePluginConfigTypeRemote {
+		externalPlugin.Strategy = Strategy(pluginConfig.Strategy()).String()
+	}
+	for file, digest := range fileToDigest {
+		externalPlugin.Files = append(
+			externalPlugin.Files,
+			&externalProvenanceFile{
+				OutPrefix: file.outPrefix,
+				Path:      file.path,
+				Digest:    digest,
+			},
+		)
+	}
+	sort.Slice(
+		externalPlugin.Files,
+		func(i int, j int) bool {
+			if externalPlugin.Files[i].OutPrefix != externalPlugin.Files[j].OutPrefix {
+				return externalPlugin.Files[i].OutPrefix < externalPlugin.Files[j].OutPrefix
+			}
+			return externalPlugin.Files[i].Path < externalPlugin.Files[j].Path
+		},
+	)
+	return externalPlugin, nil
+}
+
+// getBinaryDigest returns the digest of the binary that is invoked by the path,
+// or the default binary if the path is empty.
+//
+// Returns empty if the binary cannot be found, for example when a local plugin
+// is not installed and is instead run through protoc.
+func getBinaryDigest(path []string, defaultBinary string) (string, error) {
+	binary := defaultBinary
+	if len(path) > 0 {
+		binary = path[0]
+	}
+	binaryPath, err := lookPluginPath(binary)
+	if err != nil {
+		return "", nil
+	}
+	file, err := os.Open(binaryPath)
+	if err != nil {
+		return "", err
+	}
+	digest, err := bufcas.NewDigestForContent(file)
+	if err := errors.Join(err, file.Close()); err != nil {
+		return "", err
+	}
+	return digest.String(), nil
+}
+
+// lookPluginPath returns the path to the plugin binary.
+//
+// Wasm plugins are run in the Wasm runtime and do not need to be executable,
+// so they are resolved relative to the current directory before "${PATH}".
+func lookPluginPath(binary string) (string, error) {
+	if filepath.Ext(binary) == ".wasm" {
+		if fileInfo, err := os.Stat(binary); err == nil && fileInfo.Mode().IsRegular() {
+			return binary, nil
+		}
+	}
+	return exec.LookPath(binary)
+}
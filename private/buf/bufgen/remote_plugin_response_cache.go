@@ -0,0 +1,163 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"time"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufcas"
+	imagev1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/image/v1"
+	registryv1alpha1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/registry/v1alpha1"
+	"github.com/bufbuild/buf/private/pkg/slogext"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// remotePluginResponseCache caches the CodeGeneratorResponses of remote plugins.
+//
+// Errors reading from or writing to the cache are logged and otherwise ignored,
+// as the cache is only an optimization.
+type remotePluginResponseCache struct {
+	logger *slog.Logger
+	bucket storage.ReadWriteBucket
+	// Entries older than ttl are ignored. If zero, entries do not expire.
+	ttl time.Duration
+	// If true, new entries are not written to the cache.
+	readOnly bool
+}
+
+func newRemotePluginResponseCache(
+	logger *slog.Logger,
+	bucket storage.ReadWriteBucket,
+	ttl time.Duration,
+	readOnly bool,
+) *remotePluginResponseCache {
+	return &remotePluginResponseCache{
+		logger:   logger,
+		bucket:   bucket,
+		ttl:      ttl,
+		readOnly: readOnly,
+	}
+}
+
+// Get gets the CodeGeneratorResponse for the key.
+//
+// Returns nil if there is no entry for the key, or the entry has expired.
+func (c *remotePluginResponseCache) Get(ctx context.Context, key string) *pluginpb.CodeGeneratorResponse {
+	data, err := storage.ReadPath(ctx, c.bucket, key)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			c.logger.DebugContext(ctx, "could not read remote plugin response from cache", slog.String("key", key), slogext.ErrorAttr(err))
+		}
+		return nil
+	}
+	var externalEntry externalRemotePluginResponseCacheEntry
+	if err := json.Unmarshal(data, &externalEntry); err != nil {
+		c.logger.DebugContext(ctx, "invalid remote plugin response in cache", slog.String("key", key), slogext.ErrorAttr(err))
+		return nil
+	}
+	if c.ttl > 0 && time.Since(externalEntry.CreateTime) > c.ttl {
+		c.logger.DebugContext(ctx, "remote plugin response in cache expired", slog.String("key", key), slog.Time("create_time", externalEntry.CreateTime))
+		return nil
+	}
+	response := &pluginpb.CodeGeneratorResponse{}
+	if err := proto.Unmarshal(externalEntry.Response, response); err != nil {
+		c.logger.DebugContext(ctx, "invalid remote plugin response in cache", slog.String("key", key), slogext.ErrorAttr(err))
+		return nil
+	}
+	c.logger.DebugContext(ctx, "using remote plugin response from cache", slog.String("key", key))
+	return response
+}
+
+// Put puts the CodeGeneratorResponse for the key, replacing any existing entry.
+func (c *remotePluginResponseCache) Put(ctx context.Context, key string, response *pluginpb.CodeGeneratorResponse) {
+	if c.readOnly {
+		return
+	}
+	responseData, err := proto.Marshal(response)
+	if err != nil {
+		c.logger.DebugContext(ctx, "could not marshal remote plugin response", slog.String("key", key), slogext.ErrorAttr(err))
+		return
+	}
+	data, err := json.Marshal(
+		&externalRemotePluginResponseCacheEntry{
+			CreateTime: time.Now().UTC(),
+			Response:   responseData,
+		},
+	)
+	if err != nil {
+		c.logger.DebugContext(ctx, "could not marshal remote plugin response", slog.String("key", key), slogext.ErrorAttr(err))
+		return
+	}
+	if err := storage.PutPath(ctx, c.bucket, key, data, storage.PutWithAtomic()); err != nil {
+		c.logger.DebugContext(ctx, "could not write remote plugin response to cache", slog.String("key", key), slogext.ErrorAttr(err))
+	}
+}
+
+// externalRemotePluginResponseCacheEntry is the on-disk representation of a cached
+// CodeGeneratorResponse.
+type externalRemotePluginResponseCacheEntry struct {
+	CreateTime time.Time `json:"create_time"`
+	// Response is the CodeGeneratorResponse, encoded with the wire format.
+	Response []byte `json:"response"`
+}
+
+// getImageDigest gets a Digest of the Image, to be used in cache keys.
+func getImageDigest(protoImage *imagev1.Image) (bufcas.Digest, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(protoImage)
+	if err != nil {
+		return nil, err
+	}
+	return bufcas.NewDigestForContent(bytes.NewReader(data))
+}
+
+// getRemotePluginResponseCacheKey gets the key for the response of the request to the
+// remote for the image with the given Digest.
+//
+// Returns false if the response for the request should not be cached. This is the case
+// if the request does not specify a plugin version, as the latest version of the plugin
+// may change at any time.
+func getRemotePluginResponseCacheKey(
+	imageDigest bufcas.Digest,
+	remote string,
+	request *registryv1alpha1.PluginGenerationRequest,
+) (string, bool, error) {
+	if request.GetPluginReference().GetVersion() == "" {
+		return "", false, nil
+	}
+	requestData, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+	if err != nil {
+		return "", false, err
+	}
+	buffer := bytes.NewBuffer(nil)
+	_, _ = buffer.WriteString(imageDigest.String())
+	_ = buffer.WriteByte(0)
+	_, _ = buffer.WriteString(remote)
+	_ = buffer.WriteByte(0)
+	_, _ = buffer.Write(requestData)
+	digest, err := bufcas.NewDigestForContent(buffer)
+	if err != nil {
+		return "", false, err
+	}
+	return digest.Type().String() + "-" + hex.EncodeToString(digest.Value()), true, nil
+}
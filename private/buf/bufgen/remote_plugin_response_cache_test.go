@@ -0,0 +1,139 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	imagev1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/image/v1"
+	registryv1alpha1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/registry/v1alpha1"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestRemotePluginResponseCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	bucket := storagemem.NewReadWriteBucket()
+	response := &pluginpb.CodeGeneratorResponse{
+		File: []*pluginpb.CodeGeneratorResponse_File{
+			{
+				Name:    proto.String("a.txt"),
+				Content: proto.String("a"),
+			},
+		},
+	}
+
+	readOnlyCache := newRemotePluginResponseCache(slogtestext.NewLogger(t), bucket, time.Hour, true)
+	readOnlyCache.Put(ctx, "key", response)
+	require.Nil(t, readOnlyCache.Get(ctx, "key"))
+
+	cache := newRemotePluginResponseCache(slogtestext.NewLogger(t), bucket, time.Hour, false)
+	require.Nil(t, cache.Get(ctx, "key"))
+	cache.Put(ctx, "key", response)
+	require.True(t, proto.Equal(response, cache.Get(ctx, "key")))
+	require.True(t, proto.Equal(response, readOnlyCache.Get(ctx, "key")))
+
+	// Entries older than the TTL are ignored, unless the TTL is zero.
+	responseData, err := proto.Marshal(response)
+	require.NoError(t, err)
+	data, err := json.Marshal(
+		&externalRemotePluginResponseCacheEntry{
+			CreateTime: time.Now().Add(-2 * time.Hour),
+			Response:   responseData,
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, storage.PutPath(ctx, bucket, "old", data))
+	require.Nil(t, cache.Get(ctx, "old"))
+	noTTLCache := newRemotePluginResponseCache(slogtestext.NewLogger(t), bucket, 0, false)
+	require.True(t, proto.Equal(response, noTTLCache.Get(ctx, "old")))
+
+	// Invalid entries are ignored.
+	require.NoError(t, storage.PutPath(ctx, bucket, "invalid", []byte("foo")))
+	require.Nil(t, cache.Get(ctx, "invalid"))
+}
+
+func TestGetRemotePluginResponseCacheKey(t *testing.T) {
+	t.Parallel()
+	imageDigest, err := getImageDigest(
+		imagev1.Image_builder{
+			File: []*imagev1.ImageFile{
+				imagev1.ImageFile_builder{
+					Name: proto.String("a.proto"),
+				}.Build(),
+			},
+		}.Build(),
+	)
+	require.NoError(t, err)
+	otherImageDigest, err := getImageDigest(
+		imagev1.Image_builder{
+			File: []*imagev1.ImageFile{
+				imagev1.ImageFile_builder{
+					Name: proto.String("b.proto"),
+				}.Build(),
+			},
+		}.Build(),
+	)
+	require.NoError(t, err)
+	newRequest := func(version string, options ...string) *registryv1alpha1.PluginGenerationRequest {
+		return registryv1alpha1.PluginGenerationRequest_builder{
+			PluginReference: registryv1alpha1.CuratedPluginReference_builder{
+				Owner:   "protocolbuffers",
+				Name:    "go",
+				Version: version,
+			}.Build(),
+			Options: options,
+		}.Build()
+	}
+
+	// Requests for the latest version of a plugin are not cached.
+	_, ok, err := getRemotePluginResponseCacheKey(imageDigest, "buf.build", newRequest(""))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	key, ok, err := getRemotePluginResponseCacheKey(imageDigest, "buf.build", newRequest("v1.0.0"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	sameKey, _, err := getRemotePluginResponseCacheKey(imageDigest, "buf.build", newRequest("v1.0.0"))
+	require.NoError(t, err)
+	require.Equal(t, key, sameKey)
+	for _, otherKey := range []func() (string, bool, error){
+		func() (string, bool, error) {
+			return getRemotePluginResponseCacheKey(otherImageDigest, "buf.build", newRequest("v1.0.0"))
+		},
+		func() (string, bool, error) {
+			return getRemotePluginResponseCacheKey(imageDigest, "buf.example.com", newRequest("v1.0.0"))
+		},
+		func() (string, bool, error) {
+			return getRemotePluginResponseCacheKey(imageDigest, "buf.build", newRequest("v1.1.0"))
+		},
+		func() (string, bool, error) {
+			return getRemotePluginResponseCacheKey(imageDigest, "buf.build", newRequest("v1.0.0", "paths=source_relative"))
+		},
+	} {
+		otherKey, ok, err := otherKey()
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.NotEqual(t, key, otherKey)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+const defaultRemotePluginBackoff = time.Second
+
+// remotePluginRetrier executes the requests to the remotes of remote plugins,
+// retrying requests that fail with transient errors.
+//
+// It also acts as a circuit breaker: once the requests to a remote have failed
+// after all retries, the remote is considered unavailable, and no further requests
+// are made to it for the rest of the generation.
+type remotePluginRetrier struct {
+	logger     *slog.Logger
+	maxRetries int
+	// backoff is the time to wait before the first retry. It is doubled after each retry.
+	backoff time.Duration
+	// timeout is the timeout of each request, or 0 if there is no timeout.
+	timeout time.Duration
+
+	lock                     sync.Mutex
+	unavailableRemoteToCause map[string]error
+}
+
+func newRemotePluginRetrier(
+	logger *slog.Logger,
+	maxRetries int,
+	backoff time.Duration,
+	timeout time.Duration,
+) *remotePluginRetrier {
+	return &remotePluginRetrier{
+		logger:                   logger,
+		maxRetries:               maxRetries,
+		backoff:                  backoff,
+		timeout:                  timeout,
+		unavailableRemoteToCause: make(map[string]error),
+	}
+}
+
+// do calls f with retries.
+//
+// A *remoteUnavailableError is returned if the remote is unavailable, either
+// because f failed with a transient error after all retries, or because requests
+// to the remote previously did.
+func (r *remotePluginRetrier) do(ctx context.Context, remote string, f func(context.Context) error) error {
+	if cause := r.getUnavailableCause(remote); cause != nil {
+		return &remoteUnavailableError{remote: remote, err: cause}
+	}
+	backoff := r.backoff
+	for attempt := 0; ; attempt++ {
+		err := r.doAttempt(ctx, f)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil || !isRetryableRemotePluginError(err) {
+			return err
+		}
+		if attempt >= r.maxRetries {
+			r.setUnavailableCause(remote, err)
+			return &remoteUnavailableError{remote: remote, err: err}
+		}
+		r.logger.WarnContext(
+			ctx,
+			"remote plugin request failed, retrying",
+			slog.String("remote", remote),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("backoff", backoff),
+			slog.String("error", err.Error()),
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (r *remotePluginRetrier) doAttempt(ctx context.Context, f func(context.Context) error) error {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+	return f(ctx)
+}
+
+func (r *remotePluginRetrier) getUnavailableCause(remote string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.unavailableRemoteToCause[remote]
+}
+
+func (r *remotePluginRetrier) setUnavailableCause(remote string, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.unavailableRemoteToCause[remote] = err
+}
+
+// remoteUnavailableError is returned by remotePluginRetrier.do if the remote
+// is unavailable.
+type remoteUnavailableError struct {
+	remote string
+	err    error
+}
+
+func (e *remoteUnavailableError) Error() string {
+	return fmt.Sprintf("remote %s is unavailable: %v", e.remote, e.err)
+}
+
+func (e *remoteUnavailableError) Unwrap() error {
+	return e.err
+}
+
+// isRetryableRemotePluginError returns true if the error is a transient error
+// that the request can be retried for.
+func isRetryableRemotePluginError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		// The per-request timeout was hit.
+		return true
+	}
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable,
+		connect.CodeDeadlineExceeded,
+		connect.CodeResourceExhausted,
+		connect.CodeAborted:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemotePluginRetrier(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	retrier := newRemotePluginRetrier(slogtestext.NewLogger(t), 2, time.Millisecond, 0)
+
+	// Transient errors are retried.
+	var attempts int
+	err := retrier.do(ctx, "a.example.com", func(context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return connect.NewError(connect.CodeUnavailable, errors.New("unavailable"))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+
+	// Other errors are not retried, and do not make the remote unavailable.
+	attempts = 0
+	err = retrier.do(ctx, "b.example.com", func(context.Context) error {
+		attempts++
+		return connect.NewError(connect.CodeNotFound, errors.New("not found"))
+	})
+	require.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	require.False(t, errors.As(err, new(*remoteUnavailableError)))
+	require.Equal(t, 1, attempts)
+
+	// Once the retries are exhausted, the remote is unavailable, and no further
+	// requests are made to it.
+	attempts = 0
+	err = retrier.do(ctx, "c.example.com", func(context.Context) error {
+		attempts++
+		return connect.NewError(connect.CodeUnavailable, errors.New("unavailable"))
+	})
+	require.True(t, errors.As(err, new(*remoteUnavailableError)))
+	require.Equal(t, connect.CodeUnavailable, connect.CodeOf(err))
+	require.Equal(t, 3, attempts)
+	err = retrier.do(ctx, "c.example.com", func(context.Context) error {
+		attempts++
+		return nil
+	})
+	require.True(t, errors.As(err, new(*remoteUnavailableError)))
+	require.Equal(t, 3, attempts)
+	// Other remotes are not affected.
+	require.NoError(t, retrier.do(ctx, "b.example.com", func(context.Context) error { return nil }))
+}
+
+func TestRemotePluginRetrierTimeout(t *testing.T) {
+	t.Parallel()
+	retrier := newRemotePluginRetrier(slogtestext.NewLogger(t), 1, time.Millisecond, 10*time.Millisecond)
+	var attempts int
+	err := retrier.do(context.Background(), "example.com", func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+}
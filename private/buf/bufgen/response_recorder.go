@@ -0,0 +1,89 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"io"
+	"path/filepath"
+
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// responseRecorder merges the CodeGeneratorResponses of all plugins into a single
+// CodeGeneratorResponse instead of writing the generated files.
+//
+// The name of each generated file is prefixed with the out of the plugin, so that
+// the merged response contains the paths that the files would have been written to.
+type responseRecorder struct {
+	response *pluginpb.CodeGeneratorResponse
+	// hasResponse is false until the first response is added.
+	hasResponse bool
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{
+		response: &pluginpb.CodeGeneratorResponse{},
+	}
+}
+
+// AddResponse merges the response of a plugin generated to the plugin out.
+//
+// The supported features of the merged response are the features supported by all
+// responses, and the supported editions are the editions supported by all responses.
+func (r *responseRecorder) AddResponse(pluginOut string, response *pluginpb.CodeGeneratorResponse) {
+	pluginOut = normalpath.Normalize(filepath.Clean(pluginOut))
+	for _, file := range response.GetFile() {
+		mergedFile := &pluginpb.CodeGeneratorResponse_File{
+			Name:              file.Name,
+			InsertionPoint:    file.InsertionPoint,
+			Content:           file.Content,
+			GeneratedCodeInfo: file.GeneratedCodeInfo,
+		}
+		if pluginOut != "." {
+			name := normalpath.Join(pluginOut, file.GetName())
+			mergedFile.Name = &name
+		}
+		r.response.File = append(r.response.File, mergedFile)
+	}
+	if !r.hasResponse {
+		r.hasResponse = true
+		r.response.SupportedFeatures = response.SupportedFeatures
+		r.response.MinimumEdition = response.MinimumEdition
+		r.response.MaximumEdition = response.MaximumEdition
+		return
+	}
+	if r.response.SupportedFeatures != nil {
+		supportedFeatures := r.response.GetSupportedFeatures() & response.GetSupportedFeatures()
+		r.response.SupportedFeatures = &supportedFeatures
+	}
+	if response.MinimumEdition != nil && (r.response.MinimumEdition == nil || response.GetMinimumEdition() > r.response.GetMinimumEdition()) {
+		r.response.MinimumEdition = response.MinimumEdition
+	}
+	if response.MaximumEdition != nil && (r.response.MaximumEdition == nil || response.GetMaximumEdition() < r.response.GetMaximumEdition()) {
+		r.response.MaximumEdition = response.MaximumEdition
+	}
+}
+
+// Write writes the merged response in the binary wire format.
+func (r *responseRecorder) Write(writer io.Writer) error {
+	data, err := protoencoding.NewWireMarshaler().Marshal(r.response)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
@@ -0,0 +1,82 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestResponseRecorderAddResponse(t *testing.T) {
+	t.Parallel()
+	allFeatures := uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL | pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS)
+	proto3OptionalFeature := uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	responseRecorder := newResponseRecorder()
+	responseRecorder.AddResponse(
+		"gen/go",
+		&pluginpb.CodeGeneratorResponse{
+			SupportedFeatures: proto.Uint64(allFeatures),
+			MinimumEdition:    proto.Int32(int32(descriptorpb.Edition_EDITION_PROTO2)),
+			MaximumEdition:    proto.Int32(int32(descriptorpb.Edition_EDITION_2024)),
+			File: []*pluginpb.CodeGeneratorResponse_File{
+				{
+					Name:    proto.String("a/v1/a.pb.go"),
+					Content: proto.String("a"),
+				},
+			},
+		},
+	)
+	responseRecorder.AddResponse(
+		".",
+		&pluginpb.CodeGeneratorResponse{
+			SupportedFeatures: proto.Uint64(proto3OptionalFeature),
+			MinimumEdition:    proto.Int32(int32(descriptorpb.Edition_EDITION_PROTO3)),
+			MaximumEdition:    proto.Int32(int32(descriptorpb.Edition_EDITION_2023)),
+			File: []*pluginpb.CodeGeneratorResponse_File{
+				{
+					Name:           proto.String("gen/go/a/v1/a.pb.go"),
+					InsertionPoint: proto.String("imports"),
+					Content:        proto.String("b"),
+				},
+			},
+		},
+	)
+	assert.True(
+		t,
+		proto.Equal(
+			&pluginpb.CodeGeneratorResponse{
+				SupportedFeatures: proto.Uint64(proto3OptionalFeature),
+				MinimumEdition:    proto.Int32(int32(descriptorpb.Edition_EDITION_PROTO3)),
+				MaximumEdition:    proto.Int32(int32(descriptorpb.Edition_EDITION_2023)),
+				File: []*pluginpb.CodeGeneratorResponse_File{
+					{
+						Name:    proto.String("gen/go/a/v1/a.pb.go"),
+						Content: proto.String("a"),
+					},
+					{
+						Name:           proto.String("gen/go/a/v1/a.pb.go"),
+						InsertionPoint: proto.String("imports"),
+						Content:        proto.String("b"),
+					},
+				},
+			},
+			responseRecorder.response,
+		),
+	)
+}
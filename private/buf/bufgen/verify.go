@@ -0,0 +1,406 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufprotoplugin/bufprotopluginos"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+)
+
+func verifyConfig(fileInfo bufanalysis.FileInfo, config bufconfig.GenerateConfig) error {
+	verifier := &configVerifier{
+		fileInfo: fileInfo,
+	}
+	pluginConfigs := config.GeneratePluginConfigs()
+	for i, pluginConfig := range pluginConfigs {
+		// Plugins are numbered from 1 in the order they are specified, as the names
+		// of plugins are not unique.
+		pluginLabel := fmt.Sprintf("Plugin %d (%s)", i+1, pluginConfig.Name())
+		verifier.verifyPluginFound(pluginLabel, pluginConfig)
+		verifier.verifyPluginArchiveOut(pluginLabel, pluginConfig)
+		verifier.verifyPluginStrategy(pluginLabel, pluginConfig)
+		verifier.verifyPluginTypes(pluginLabel, pluginConfig)
+		verifier.verifyPluginRoutes(pluginLabel, pluginConfig)
+		for j, previousPluginConfig := range pluginConfigs[:i] {
+			if pluginConfigsConflict(previousPluginConfig, pluginConfig) {
+				verifier.addError(
+					FileAnnotationTypePluginOutConflict,
+					"%s generates the same files as plugin %d, as they have the same out %q and options. Remove one of the plugins.",
+					pluginLabel,
+					j+1,
+					pluginConfig.Out(),
+				)
+				break
+			}
+		}
+	}
+	verifier.verifyManagedConfig(config.GenerateManagedConfig())
+	if len(verifier.fileAnnotations) > 0 {
+		return bufanalysis.NewFileAnnotationSet(verifier.fileAnnotations...)
+	}
+	return nil
+}
+
+type configVerifier struct {
+	fileInfo        bufanalysis.FileInfo
+	fileAnnotations []bufanalysis.FileAnnotation
+}
+
+func (v *configVerifier) verifyPluginFound(pluginLabel string, pluginConfig bufconfig.GeneratePluginConfig) {
+	switch pluginConfig.Type() {
+	case bufconfig.GeneratePluginConfigTypeLocal:
+		if !pluginBinaryExists(pluginConfig.Path()[0]) {
+			v.addError(
+				FileAnnotationTypePluginNotFound,
+				"%s was not found: %s. Install the plugin, or set local to the path of the plugin binary.",
+				pluginLabel,
+				binaryNotFoundString(pluginConfig.Path()[0]),
+			)
+		}
+	case bufconfig.GeneratePluginConfigTypeProtocBuiltin:
+		protocPath := getProtocPath(pluginConfig)
+		if !pluginBinaryExists(protocPath) {
+			v.addError(
+				FileAnnotationTypePluginNotFound,
+				"%s is a protoc built-in plugin, but protoc was not found: %s. Install protoc, or set protoc_path to the path of protoc.",
+				pluginLabel,
+				binaryNotFoundString(protocPath),
+			)
+		}
+	case bufconfig.GeneratePluginConfigTypeLocalOrProtocBuiltin:
+		binaryName := "protoc-gen-" + pluginConfig.Name()
+		if pluginBinaryExists(binaryName) {
+			return
+		}
+		if _, ok := bufconfig.ProtocProxyPluginNames[pluginConfig.Name()]; ok {
+			protocPath := getProtocPath(pluginConfig)
+			if !pluginBinaryExists(protocPath) {
+				v.addError(
+					FileAnnotationTypePluginNotFound,
+					"%s was not found: %s, and %s. Install the plugin or protoc, or set protoc_path to the path of protoc.",
+					pluginLabel,
+					binaryNotFoundString(binaryName),
+					binaryNotFoundString(protocPath),
+				)
+			}
+			return
+		}
+		v.addError(
+			FileAnnotationTypePluginNotFound,
+			"%s was not found: %s. Install the plugin, or set path to the path of the plugin binary.",
+			pluginLabel,
+			binaryNotFoundString(binaryName),
+		)
+	case bufconfig.GeneratePluginConfigTypeRemote:
+		localFallback := pluginConfig.LocalFallback()
+		if localFallback != nil && !pluginBinaryExists(localFallback.Path()[0]) {
+			v.addWarning(
+				FileAnnotationTypePluginNotFound,
+				"%s has a local_fallback that was not found: %s, so generation fails if the remote is unavailable.",
+				pluginLabel,
+				binaryNotFoundString(localFallback.Path()[0]),
+			)
+		}
+	}
+}
+
+func (v *configVerifier) verifyPluginArchiveOut(pluginLabel string, pluginConfig bufconfig.GeneratePluginConfig) {
+	if len(pluginConfig.PostCommands()) > 0 && bufprotopluginos.IsArchivePath(pluginConfig.Out()) {
+		v.addError(
+			FileAnnotationTypeArchiveOutIncompatible,
+			"%s has post commands, which cannot be used with archive out %q.",
+			pluginLabel,
+			pluginConfig.Out(),
+		)
+	}
+	if !pluginConfig.Clean() {
+		return
+	}
+	for _, pluginOut := range getPluginOuts("", pluginConfig) {
+		if bufprotopluginos.IsArchivePath(pluginOut) {
+			v.addError(
+				FileAnnotationTypeArchiveOutIncompatible,
+				"%s has clean set, which cannot be used with archive out %q, archives are always overwritten.",
+				pluginLabel,
+				pluginOut,
+			)
+		}
+	}
+}
+
+func (v *configVerifier) verifyPluginStrategy(pluginLabel string, pluginConfig bufconfig.GeneratePluginConfig) {
+	if pluginConfig.Type() == bufconfig.GeneratePluginConfigTypeRemote {
+		// Remote plugins always generate with all files at once.
+		return
+	}
+	switch Strategy(pluginConfig.Strategy()) {
+	case StrategyDirectory:
+		if pluginConfig.IncludeImports() {
+			v.addWarning(
+				FileAnnotationTypeStrategyIncompatible,
+				"%s has include_imports set with strategy directory, so imported files are generated once for every directory that imports them. Set strategy to all.",
+				pluginLabel,
+			)
+		}
+	case StrategyAllWithImports:
+		if pluginConfig.IncludeImports() {
+			v.addWarning(
+				FileAnnotationTypeOptionUnused,
+				"%s has include_imports set, which has no effect with strategy all_with_imports.",
+				pluginLabel,
+			)
+		}
+		if pluginConfig.IncludeWKT() {
+			v.addWarning(
+				FileAnnotationTypeOptionUnused,
+				"%s has include_wkt set, which has no effect with strategy all_with_imports.",
+				pluginLabel,
+			)
+		}
+	}
+}
+
+func (v *configVerifier) verifyPluginTypes(pluginLabel string, pluginConfig bufconfig.GeneratePluginConfig) {
+	for _, excludeType := range pluginConfig.ExcludeTypes() {
+		if slices.Contains(pluginConfig.Types(), excludeType) {
+			v.addWarning(
+				FileAnnotationTypeOptionUnused,
+				"%s has %q in both types and exclude_types, so it is excluded. Remove it from one of them.",
+				pluginLabel,
+				excludeType,
+			)
+		}
+	}
+}
+
+func (v *configVerifier) verifyPluginRoutes(pluginLabel string, pluginConfig bufconfig.GeneratePluginConfig) {
+	routes := pluginConfig.Routes()
+	for i, route := range routes {
+		// A route to the out of the plugin only has an effect if it keeps files from
+		// being routed by later routes.
+		if filepath.Clean(route.Out()) == filepath.Clean(pluginConfig.Out()) &&
+			!slices.ContainsFunc(
+				routes[i+1:],
+				func(laterRoute bufconfig.GenerateOutRoute) bool {
+					return routeCovers(route, laterRoute)
+				},
+			) {
+			v.addWarning(
+				FileAnnotationTypeOptionUnused,
+				"%s has a route to %q, which is the out of the plugin, so the route has no effect.",
+				pluginLabel,
+				route.Out(),
+			)
+			continue
+		}
+		for _, previousRoute := range routes[:i] {
+			if routeCovers(previousRoute, route) {
+				v.addWarning(
+					FileAnnotationTypeOptionUnused,
+					"%s has a route to %q that can never match, as all the files it matches are routed to %q by an earlier route.",
+					pluginLabel,
+					route.Out(),
+					previousRoute.Out(),
+				)
+				break
+			}
+		}
+	}
+}
+
+func (v *configVerifier) verifyManagedConfig(managedConfig bufconfig.GenerateManagedConfig) {
+	disables := managedConfig.Disables()
+	overrides := managedConfig.Overrides()
+	if !managedConfig.Enabled() {
+		if len(disables) > 0 || len(overrides) > 0 {
+			v.addWarning(
+				FileAnnotationTypeManagedRuleUnused,
+				"Managed mode is not enabled, so its disable and override rules are never applied. Set enabled to true in managed, or remove the rules.",
+			)
+		}
+		return
+	}
+	for i, override := range overrides {
+		optionName, ok := getManagedOverrideOptionName(override)
+		if !ok {
+			continue
+		}
+		if disableIndex := slices.IndexFunc(
+			disables,
+			func(disable bufconfig.ManagedDisableRule) bool {
+				return managedDisableCoversOverride(disable, override)
+			},
+		); disableIndex >= 0 {
+			v.addWarning(
+				FileAnnotationTypeManagedRuleUnused,
+				"Managed override %d for %s is never applied, as %s is disabled by disable %d for all the files it applies to.",
+				i+1,
+				optionName,
+				optionName,
+				disableIndex+1,
+			)
+			continue
+		}
+		for j, laterOverride := range overrides[i+1:] {
+			if managedOverrideCoversOverride(laterOverride, override) {
+				v.addWarning(
+					FileAnnotationTypeManagedRuleUnused,
+					"Managed override %d for %s is never applied, as it is replaced by override %d for all the files it applies to.",
+					i+1,
+					optionName,
+					i+j+2,
+				)
+				break
+			}
+		}
+	}
+}
+
+func (v *configVerifier) addError(typeString string, format string, args ...any) {
+	v.fileAnnotations = append(
+		v.fileAnnotations,
+		bufanalysis.NewFileAnnotation(v.fileInfo, 0, 0, 0, 0, typeString, fmt.Sprintf(format, args...), ""),
+	)
+}
+
+func (v *configVerifier) addWarning(typeString string, format string, args ...any) {
+	v.fileAnnotations = append(
+		v.fileAnnotations,
+		bufanalysis.NewFileAnnotation(
+			v.fileInfo,
+			0,
+			0,
+			0,
+			0,
+			typeString,
+			fmt.Sprintf(format, args...),
+			"",
+			bufanalysis.FileAnnotationWithSeverity(bufanalysis.SeverityWarning),
+		),
+	)
+}
+
+// pluginBinaryExists returns true if the plugin binary can be found in the same
+// way as when plugins are executed.
+func pluginBinaryExists(binary string) bool {
+	_, err := lookPluginPath(binary)
+	return err == nil || errors.Is(err, exec.ErrDot)
+}
+
+// binaryNotFoundString returns a description of why the binary was not found.
+func binaryNotFoundString(binary string) string {
+	if strings.ContainsRune(binary, '/') || strings.ContainsRune(binary, filepath.Separator) {
+		return fmt.Sprintf("%q does not exist or is not executable", binary)
+	}
+	return fmt.Sprintf("%q is not on ${PATH}", binary)
+}
+
+func getProtocPath(pluginConfig bufconfig.GeneratePluginConfig) string {
+	if protocPath := pluginConfig.ProtocPath(); len(protocPath) > 0 {
+		return protocPath[0]
+	}
+	return "protoc"
+}
+
+// pluginConfigsConflict returns true if the plugins generate the same files to the same out.
+func pluginConfigsConflict(one bufconfig.GeneratePluginConfig, two bufconfig.GeneratePluginConfig) bool {
+	return one.Name() == two.Name() &&
+		filepath.Clean(one.Out()) == filepath.Clean(two.Out()) &&
+		one.Opt() == two.Opt() &&
+		slices.Equal(one.Types(), two.Types()) &&
+		slices.Equal(one.ExcludeTypes(), two.ExcludeTypes())
+}
+
+// routeCovers returns true if every file that matches the route also matches the
+// covering route.
+func routeCovers(covering bufconfig.GenerateOutRoute, route bufconfig.GenerateOutRoute) bool {
+	if !strings.HasSuffix(route.Suffix(), covering.Suffix()) {
+		return false
+	}
+	coveringPackage := covering.Package()
+	routePackage := route.Package()
+	switch {
+	case coveringPackage == "":
+		return true
+	case routePackage == "":
+		return false
+	case strings.HasSuffix(routePackage, ".*"):
+		return strings.HasSuffix(coveringPackage, ".*") &&
+			packageMatches(coveringPackage, strings.TrimSuffix(routePackage, ".*"))
+	default:
+		return packageMatches(coveringPackage, routePackage)
+	}
+}
+
+// getManagedOverrideOptionName returns the name of the file or field option of the
+// override, or false if the override is for a custom option.
+func getManagedOverrideOptionName(override bufconfig.ManagedOverrideRule) (string, bool) {
+	if override.FileOption() != bufconfig.FileOptionUnspecified {
+		return override.FileOption().String(), true
+	}
+	if override.FieldOption() != bufconfig.FieldOptionUnspecified {
+		return override.FieldOption().String(), true
+	}
+	return "", false
+}
+
+// managedDisableCoversOverride returns true if the disable rule disables the option
+// of the override for every file and field the override applies to.
+func managedDisableCoversOverride(disable bufconfig.ManagedDisableRule, override bufconfig.ManagedOverrideRule) bool {
+	if !managedScopeCovers(disable.Path(), disable.FullName(), override.Path(), override.FullName()) {
+		return false
+	}
+	if override.FileOption() != bufconfig.FileOptionUnspecified {
+		return disable.FieldName() == "" &&
+			disable.FieldOption() == bufconfig.FieldOptionUnspecified &&
+			(disable.FileOption() == bufconfig.FileOptionUnspecified || disable.FileOption() == override.FileOption())
+	}
+	return (disable.FieldName() == "" || disable.FieldName() == override.FieldName()) &&
+		(disable.FieldOption() == override.FieldOption() ||
+			(disable.FieldOption() == bufconfig.FieldOptionUnspecified && disable.FileOption() == bufconfig.FileOptionUnspecified))
+}
+
+// managedOverrideCoversOverride returns true if the later override replaces the value
+// of the override for every file and field the override applies to.
+func managedOverrideCoversOverride(later bufconfig.ManagedOverrideRule, override bufconfig.ManagedOverrideRule) bool {
+	if later.Match() != nil {
+		return false
+	}
+	if later.FileOption() != override.FileOption() || later.FieldOption() != override.FieldOption() {
+		return false
+	}
+	if !managedScopeCovers(later.Path(), later.FullName(), override.Path(), override.FullName()) {
+		return false
+	}
+	return later.FieldName() == "" || later.FieldName() == override.FieldName()
+}
+
+// managedScopeCovers returns true if every file selected by path and fullName is also
+// selected by coveringPath and coveringFullName.
+func managedScopeCovers(coveringPath string, coveringFullName string, path string, fullName string) bool {
+	if coveringPath != "" && (path == "" || !normalpath.EqualsOrContainsPath(coveringPath, path, normalpath.Relative)) {
+		return false
+	}
+	return coveringFullName == "" || coveringFullName == fullName
+}
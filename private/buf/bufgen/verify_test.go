@@ -0,0 +1,207 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgen
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyConfigSuccess(t *testing.T) {
+	t.Parallel()
+	pluginPath := testNewPluginBinary(t)
+	testVerifyConfig(
+		t,
+		`version: v2
+managed:
+  enabled: true
+  disable:
+    - file_option: go_package
+      module: buf.build/acme/weather
+  override:
+    - file_option: java_package_prefix
+      value: com.acme
+    - file_option: java_package_prefix
+      path: acme/weather
+      value: com.acme.weather
+plugins:
+  - local: `+pluginPath+`
+    out: gen/a
+    strategy: all
+    include_imports: true
+    routes:
+      - out: gen/grpc
+        suffix: _grpc.pb.go
+      - out: gen/weather
+        package: acme.weather.*
+  - local: `+pluginPath+`
+    out: gen/a
+    opt: paths=source_relative
+  - remote: buf.build/protocolbuffers/go
+    out: gen.zip
+`,
+	)
+}
+
+func TestVerifyConfigFailure(t *testing.T) {
+	t.Parallel()
+	pluginPath := testNewPluginBinary(t)
+	missingPluginPath := filepath.Join(t.TempDir(), "protoc-gen-missing")
+	testVerifyConfig(
+		t,
+		`version: v2
+managed:
+  enabled: true
+  disable:
+    - file_option: java_package
+      path: acme
+  override:
+    - file_option: java_package
+      path: acme/weather
+      value: com.acme.weather
+    - file_option: go_package_prefix
+      path: acme/weather
+      value: example.com/weather
+    - file_option: go_package_prefix
+      path: acme
+      value: example.com
+plugins:
+  - local: `+missingPluginPath+`
+    out: gen/a
+  - protoc_builtin: java
+    protoc_path: `+missingPluginPath+`
+    out: gen/java
+  - local: `+pluginPath+`
+    out: gen/b
+    include_imports: true
+    types:
+      - acme.weather.v1.Weather
+    exclude_types:
+      - acme.weather.v1.Weather
+  - local: `+pluginPath+`
+    out: gen/b/
+    include_imports: true
+    types:
+      - acme.weather.v1.Weather
+    exclude_types:
+      - acme.weather.v1.Weather
+  - local: `+pluginPath+`
+    out: gen/c
+    strategy: all_with_imports
+    include_imports: true
+    routes:
+      - out: gen/weather
+        package: acme.weather.*
+      - out: gen/connect
+        suffix: _connect.pb.go
+        package: acme.weather.v1
+      - out: gen/c
+        suffix: .pb.go
+  - remote: buf.build/protocolbuffers/go
+    local_fallback: `+missingPluginPath+`
+    out: gen.zip
+    clean: true
+    post:
+      - gofmt -w .
+`,
+		FileAnnotationTypePluginNotFound+`: Plugin 1 (`+missingPluginPath+`) was not found`,
+		FileAnnotationTypePluginNotFound+`: Plugin 2 (java) is a protoc built-in plugin, but protoc was not found`,
+		FileAnnotationTypePluginNotFound+`: Plugin 6 (buf.build/protocolbuffers/go) has a local_fallback that was not found`,
+		FileAnnotationTypePluginOutConflict+`: Plugin 4 (`+pluginPath+`) generates the same files as plugin 3`,
+		FileAnnotationTypeArchiveOutIncompatible+`: Plugin 6 (buf.build/protocolbuffers/go) has post commands`,
+		FileAnnotationTypeArchiveOutIncompatible+`: Plugin 6 (buf.build/protocolbuffers/go) has clean set`,
+		FileAnnotationTypeStrategyIncompatible+`: Plugin 3 (`+pluginPath+`) has include_imports set with strategy directory`,
+		FileAnnotationTypeStrategyIncompatible+`: Plugin 4 (`+pluginPath+`) has include_imports set with strategy directory`,
+		FileAnnotationTypeOptionUnused+`: Plugin 3 (`+pluginPath+`) has "acme.weather.v1.Weather" in both types and exclude_types`,
+		FileAnnotationTypeOptionUnused+`: Plugin 4 (`+pluginPath+`) has "acme.weather.v1.Weather" in both types and exclude_types`,
+		FileAnnotationTypeOptionUnused+`: Plugin 5 (`+pluginPath+`) has include_imports set, which has no effect`,
+		FileAnnotationTypeOptionUnused+`: Plugin 5 (`+pluginPath+`) has a route to "gen/connect" that can never match`,
+		FileAnnotationTypeOptionUnused+`: Plugin 5 (`+pluginPath+`) has a route to "gen/c", which is the out of the plugin`,
+		FileAnnotationTypeManagedRuleUnused+`: Managed override 1 for java_package is never applied, as java_package is disabled by disable 1`,
+		FileAnnotationTypeManagedRuleUnused+`: Managed override 2 for go_package_prefix is never applied, as it is replaced by override 3`,
+	)
+}
+
+func TestVerifyConfigManagedNotEnabled(t *testing.T) {
+	t.Parallel()
+	pluginPath := testNewPluginBinary(t)
+	testVerifyConfig(
+		t,
+		`version: v2
+managed:
+  override:
+    - file_option: java_package_prefix
+      value: com.acme
+plugins:
+  - local: `+pluginPath+`
+    out: gen
+`,
+		FileAnnotationTypeManagedRuleUnused+`: Managed mode is not enabled`,
+	)
+}
+
+func testVerifyConfig(t *testing.T, bufGenYAML string, expectedAnnotationPrefixes ...string) {
+	bufGenYAMLFile, err := bufconfig.ReadBufGenYAMLFile(strings.NewReader(bufGenYAML))
+	require.NoError(t, err)
+	err = VerifyConfig(testFileInfo{}, bufGenYAMLFile.GenerateConfig())
+	if len(expectedAnnotationPrefixes) == 0 {
+		require.NoError(t, err)
+		return
+	}
+	var fileAnnotationSet bufanalysis.FileAnnotationSet
+	require.True(t, errors.As(err, &fileAnnotationSet), err)
+	fileAnnotations := fileAnnotationSet.FileAnnotations()
+	require.Len(t, fileAnnotations, len(expectedAnnotationPrefixes), fileAnnotationSet.String())
+	actualAnnotations := make([]string, len(fileAnnotations))
+	for i, fileAnnotation := range fileAnnotations {
+		actualAnnotations[i] = fileAnnotation.Type() + ": " + fileAnnotation.Message()
+	}
+	for _, expectedAnnotationPrefix := range expectedAnnotationPrefixes {
+		index := -1
+		for i, actualAnnotation := range actualAnnotations {
+			if strings.HasPrefix(actualAnnotation, expectedAnnotationPrefix) {
+				index = i
+				break
+			}
+		}
+		require.GreaterOrEqual(t, index, 0, "no annotation starting with %q in:\n%s", expectedAnnotationPrefix, strings.Join(actualAnnotations, "\n"))
+		actualAnnotations = append(actualAnnotations[:index], actualAnnotations[index+1:]...)
+	}
+}
+
+// testNewPluginBinary writes an executable file to a temporary directory, and returns
+// its path.
+func testNewPluginBinary(t *testing.T) string {
+	pluginPath := filepath.Join(t.TempDir(), "protoc-gen-test")
+	require.NoError(t, os.WriteFile(pluginPath, []byte("#!/bin/sh\n"), 0755))
+	return pluginPath
+}
+
+type testFileInfo struct{}
+
+func (testFileInfo) Path() string {
+	return "buf.gen.yaml"
+}
+
+func (testFileInfo) ExternalPath() string {
+	return "buf.gen.yaml"
+}
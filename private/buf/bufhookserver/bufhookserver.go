@@ -0,0 +1,220 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufhookserver implements the hook server, which evaluates pushes to the git
+// repositories of self-hosted git servers, such as from pre-receive hooks, so that
+// schema policy can be enforced centrally.
+package bufhookserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+)
+
+const (
+	// CheckLint is the check that runs lint on the pushed revision.
+	CheckLint = "lint"
+	// CheckBreaking is the check that runs breaking change detection on the pushed revision
+	// against the previous revision of the ref.
+	CheckBreaking = "breaking"
+	// CheckFormat is the check that checks that the files of the pushed revision are formatted.
+	CheckFormat = "format"
+
+	// EvaluatePath is the path of the HTTP endpoint that evaluates Requests.
+	EvaluatePath = "/v1/evaluate"
+	// MaxRequestSizeBytes is the maximum number of bytes to read from the body of a request.
+	MaxRequestSizeBytes = 1024 * 1024
+)
+
+var (
+	// AllChecks are all checks, in the order they are run.
+	AllChecks = []string{
+		CheckLint,
+		CheckBreaking,
+		CheckFormat,
+	}
+)
+
+// Request is a request to evaluate the push of a ref.
+//
+// The fields are the same as the lines that git passes to pre-receive hooks.
+type Request struct {
+	// Repository is the path of the repository, relative to the repositories directory
+	// of the hook server.
+	Repository string `json:"repository"`
+	// Ref is the pushed ref, such as refs/heads/main.
+	//
+	// Only used for logging.
+	Ref string `json:"ref,omitempty"`
+	// OldRevision is the revision of the ref before the push.
+	//
+	// Only zeros if the ref is created by the push.
+	OldRevision string `json:"old_revision"`
+	// NewRevision is the revision of the ref after the push.
+	//
+	// Only zeros if the ref is deleted by the push.
+	NewRevision string `json:"new_revision"`
+}
+
+// IsCreate returns true if the ref is created by the push.
+func (r *Request) IsCreate() bool {
+	return isZeroRevision(r.OldRevision)
+}
+
+// IsDelete returns true if the ref is deleted by the push.
+func (r *Request) IsDelete() bool {
+	return isZeroRevision(r.NewRevision)
+}
+
+// Decision is the decision for a push.
+type Decision struct {
+	// Allowed is true if the push should be accepted.
+	Allowed bool `json:"allowed"`
+	// Checks are the results of the checks, in the order they were run.
+	Checks []*CheckResult `json:"checks,omitempty"`
+}
+
+// CheckResult is the result of a check.
+type CheckResult struct {
+	// Check is the check, one of AllChecks.
+	Check string `json:"check"`
+	// Passed is true if the check produced no annotations with error severity.
+	Passed bool `json:"passed"`
+	// Annotations are the annotations produced by the check.
+	Annotations []*Annotation `json:"annotations,omitempty"`
+}
+
+// Annotation is an annotation produced by a check, such as a lint violation.
+type Annotation struct {
+	Path        string `json:"path,omitempty"`
+	StartLine   int    `json:"start_line,omitempty"`
+	StartColumn int    `json:"start_column,omitempty"`
+	EndLine     int    `json:"end_line,omitempty"`
+	EndColumn   int    `json:"end_column,omitempty"`
+	Type        string `json:"type"`
+	Message     string `json:"message"`
+	Severity    string `json:"severity"`
+}
+
+// Evaluator evaluates Requests.
+type Evaluator interface {
+	// Evaluate evaluates the Request.
+	//
+	// The Request is validated.
+	Evaluate(ctx context.Context, request *Request) (*Decision, error)
+}
+
+// EvaluatorFunc is a function that implements Evaluator.
+type EvaluatorFunc func(ctx context.Context, request *Request) (*Decision, error)
+
+// Evaluate implements Evaluator.
+func (e EvaluatorFunc) Evaluate(ctx context.Context, request *Request) (*Decision, error) {
+	return e(ctx, request)
+}
+
+// NewDecision returns a new Decision for the CheckResults.
+//
+// The push is allowed if all checks passed.
+func NewDecision(checkResults ...*CheckResult) *Decision {
+	decision := &Decision{
+		Allowed: true,
+		Checks:  checkResults,
+	}
+	for _, checkResult := range checkResults {
+		if !checkResult.Passed {
+			decision.Allowed = false
+		}
+	}
+	return decision
+}
+
+// NewCheckResult returns a new CheckResult for the FileAnnotations produced by the check.
+func NewCheckResult(check string, fileAnnotations []bufanalysis.FileAnnotation) *CheckResult {
+	checkResult := &CheckResult{
+		Check:  check,
+		Passed: true,
+	}
+	for _, fileAnnotation := range fileAnnotations {
+		if fileAnnotation.Severity() != bufanalysis.SeverityWarning {
+			checkResult.Passed = false
+		}
+		annotation := &Annotation{
+			StartLine:   fileAnnotation.StartLine(),
+			StartColumn: fileAnnotation.StartColumn(),
+			EndLine:     fileAnnotation.EndLine(),
+			EndColumn:   fileAnnotation.EndColumn(),
+			Type:        fileAnnotation.Type(),
+			Message:     fileAnnotation.Message(),
+			Severity:    fileAnnotation.Severity().String(),
+		}
+		if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+			annotation.Path = fileInfo.Path()
+		}
+		checkResult.Annotations = append(checkResult.Annotations, annotation)
+	}
+	return checkResult
+}
+
+// ValidateRequest validates the Request.
+func ValidateRequest(request *Request) error {
+	if request.Repository == "" {
+		return errors.New("repository is required")
+	}
+	repository, err := normalpath.NormalizeAndValidate(request.Repository)
+	if err != nil {
+		return fmt.Errorf("invalid repository %q: %w", request.Repository, err)
+	}
+	if repository == "." {
+		return fmt.Errorf("invalid repository %q: must be a repository within the repositories directory", request.Repository)
+	}
+	if err := validateRevision("old_revision", request.OldRevision); err != nil {
+		return err
+	}
+	return validateRevision("new_revision", request.NewRevision)
+}
+
+// NewHandler returns a new http.Handler that evaluates Requests POSTed as JSON to
+// EvaluatePath, and responds with the Decision as JSON.
+func NewHandler(logger *slog.Logger, evaluator Evaluator) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(EvaluatePath, newEvaluateHandler(logger, evaluator))
+	return mux
+}
+
+// *** PRIVATE ***
+
+func validateRevision(name string, revision string) error {
+	if revision == "" {
+		return fmt.Errorf("%s is required", name)
+	}
+	// SHA-1 object names are 40 characters and SHA-256 object names are 64 characters.
+	if len(revision) != 40 && len(revision) != 64 {
+		return fmt.Errorf("invalid %s %q: must be a full object name", name, revision)
+	}
+	if strings.Trim(revision, "0123456789abcdef") != "" {
+		return fmt.Errorf("invalid %s %q: must be a lowercase hexadecimal object name", name, revision)
+	}
+	return nil
+}
+
+func isZeroRevision(revision string) bool {
+	return revision != "" && strings.Trim(revision, "0") == ""
+}
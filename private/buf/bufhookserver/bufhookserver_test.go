@@ -0,0 +1,123 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufhookserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/slogext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	oldRevision := strings.Repeat("a", 40)
+	newRevision := strings.Repeat("b", 40)
+	server := httptest.NewServer(
+		NewHandler(
+			slogext.NopLogger,
+			EvaluatorFunc(
+				func(_ context.Context, request *Request) (*Decision, error) {
+					assert.Equal(
+						t,
+						&Request{
+							Repository:  "protos",
+							Ref:         "refs/heads/main",
+							OldRevision: oldRevision,
+							NewRevision: newRevision,
+						},
+						request,
+					)
+					return NewDecision(
+						NewCheckResult(
+							CheckLint,
+							[]bufanalysis.FileAnnotation{
+								bufanalysis.NewFileAnnotation(nil, 0, 0, 0, 0, "PACKAGE_DEFINED", "Files must have a package defined.", ""),
+							},
+						),
+						NewCheckResult(CheckFormat, nil),
+					), nil
+				},
+			),
+		),
+	)
+	t.Cleanup(server.Close)
+
+	statusCode, body := testPost(t, server.URL+EvaluatePath, `{"repository":"protos","ref":"refs/heads/main","old_revision":"`+oldRevision+`","new_revision":"`+newRevision+`"}`)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(
+		t,
+		`{"allowed":false,"checks":[{"check":"lint","passed":false,"annotations":[{"type":"PACKAGE_DEFINED","message":"Files must have a package defined.","severity":"error"}]},{"check":"format","passed":true}]}`,
+		body,
+	)
+
+	statusCode, body = testPost(t, server.URL+EvaluatePath, `{"repository":"protos","old_revision":"main","new_revision":"`+newRevision+`"}`)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "invalid request: invalid old_revision \"main\": must be a full object name\n", body)
+	statusCode, body = testPost(t, server.URL+EvaluatePath, `{"repository":"protos","branch":"main"}`)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "invalid request: json: unknown field \"branch\"\n", body)
+	response, err := http.Get(server.URL + EvaluatePath)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+}
+
+func TestNewCheckResultWarnings(t *testing.T) {
+	t.Parallel()
+	checkResult := NewCheckResult(
+		CheckLint,
+		[]bufanalysis.FileAnnotation{
+			bufanalysis.NewFileAnnotation(
+				nil, 0, 0, 0, 0, "PACKAGE_DEFINED", "Files must have a package defined.", "",
+				bufanalysis.FileAnnotationWithSeverity(bufanalysis.SeverityWarning),
+			),
+		},
+	)
+	assert.True(t, checkResult.Passed)
+	assert.True(t, NewDecision(checkResult).Allowed)
+}
+
+func TestValidateRequest(t *testing.T) {
+	t.Parallel()
+	revision := strings.Repeat("a", 40)
+	zeroRevision := strings.Repeat("0", 40)
+	request := &Request{Repository: "acme/protos.git", OldRevision: zeroRevision, NewRevision: revision}
+	require.NoError(t, ValidateRequest(request))
+	assert.True(t, request.IsCreate())
+	assert.False(t, request.IsDelete())
+	require.NoError(t, ValidateRequest(&Request{Repository: "protos", OldRevision: revision, NewRevision: strings.Repeat("b", 64)}))
+	require.EqualError(t, ValidateRequest(&Request{OldRevision: revision, NewRevision: revision}), "repository is required")
+	require.EqualError(t, ValidateRequest(&Request{Repository: ".", OldRevision: revision, NewRevision: revision}), `invalid repository ".": must be a repository within the repositories directory`)
+	require.ErrorContains(t, ValidateRequest(&Request{Repository: "../protos", OldRevision: revision, NewRevision: revision}), `invalid repository "../protos"`)
+	require.EqualError(t, ValidateRequest(&Request{Repository: "protos", OldRevision: revision}), "new_revision is required")
+	require.EqualError(t, ValidateRequest(&Request{Repository: "protos", OldRevision: revision, NewRevision: strings.Repeat("A", 40)}), `invalid new_revision "`+strings.Repeat("A", 40)+`": must be a lowercase hexadecimal object name`)
+}
+
+func testPost(t *testing.T, url string, body string) (int, string) {
+	response, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	return response.StatusCode, string(data)
+}
@@ -0,0 +1,88 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufhookserver
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/bufbuild/buf/private/pkg/slogext"
+)
+
+// evaluateHandler implements the POST handler for EvaluatePath.
+type evaluateHandler struct {
+	logger    *slog.Logger
+	evaluator Evaluator
+}
+
+func newEvaluateHandler(logger *slog.Logger, evaluator Evaluator) *evaluateHandler {
+	return &evaluateHandler{
+		logger:    logger,
+		evaluator: evaluator,
+	}
+}
+
+func (e *evaluateHandler) ServeHTTP(responseWriter http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(responseWriter, "", http.StatusMethodNotAllowed)
+		return
+	}
+	evaluateRequest := &Request{}
+	decoder := json.NewDecoder(http.MaxBytesReader(responseWriter, request.Body, MaxRequestSizeBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(evaluateRequest); err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			http.Error(responseWriter, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(responseWriter, "invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := ValidateRequest(evaluateRequest); err != nil {
+		http.Error(responseWriter, "invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	decision, err := e.evaluator.Evaluate(request.Context(), evaluateRequest)
+	if err != nil {
+		e.logger.ErrorContext(
+			request.Context(),
+			"evaluate failed",
+			slog.String("repository", evaluateRequest.Repository),
+			slog.String("ref", evaluateRequest.Ref),
+			slog.String("new_revision", evaluateRequest.NewRevision),
+			slogext.ErrorAttr(err),
+		)
+		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	e.logger.InfoContext(
+		request.Context(),
+		"evaluated",
+		slog.String("repository", evaluateRequest.Repository),
+		slog.String("ref", evaluateRequest.Ref),
+		slog.String("new_revision", evaluateRequest.NewRevision),
+		slog.Bool("allowed", decision.Allowed),
+	)
+	data, err := json.Marshal(decision)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	responseWriter.Header().Set("Content-Type", "application/json")
+	_, _ = responseWriter.Write(data)
+}
@@ -0,0 +1,272 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package buflintfix applies mechanical fixes for lint violations to .proto files.
+//
+// Fixes are applied by rewriting the source of the files, so that comments and
+// formatting are preserved. The following rules have fixes:
+//
+//   - ENUM_VALUE_PREFIX: the value is prefixed with the UPPER_SNAKE_CASE name of the enum.
+//   - ENUM_VALUE_UPPER_SNAKE_CASE: the value is renamed to UPPER_SNAKE_CASE.
+//   - ENUM_ZERO_VALUE_SUFFIX: the suffix is appended to the zero value, such as
+//     FOO_NONE_UNSPECIFIED for FOO_NONE.
+//   - FIELD_LOWER_SNAKE_CASE: the field is renamed to lower_snake_case, and the json_name
+//     option is set to the old JSON name, so that the JSON encoding does not change.
+//   - IMPORT_USED: the unused import is removed.
+//   - PACKAGE_VERSION_SUFFIX: the package is suffixed with ".v1", and references to the
+//     package in other files are updated. Files are not moved to match the new package.
+//
+// Renames are not applied if the new name conflicts with an existing name, or if the old
+// name may be referenced from an option value, as such references cannot be resolved from
+// the source alone. Package renames are not applied if the bucket does not contain every
+// file of the package with a violation for the package.
+package buflintfix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
+	"github.com/bufbuild/protocompile/parser"
+	"github.com/bufbuild/protocompile/reporter"
+)
+
+const (
+	enumValuePrefixRuleID         = "ENUM_VALUE_PREFIX"
+	enumValueUpperSnakeCaseRuleID = "ENUM_VALUE_UPPER_SNAKE_CASE"
+	enumZeroValueSuffixRuleID     = "ENUM_ZERO_VALUE_SUFFIX"
+	fieldLowerSnakeCaseRuleID     = "FIELD_LOWER_SNAKE_CASE"
+	importUsedRuleID              = "IMPORT_USED"
+	packageVersionSuffixRuleID    = "PACKAGE_VERSION_SUFFIX"
+	defaultEnumZeroValueSuffix    = "_UNSPECIFIED"
+	defaultPackageVersionSuffix   = ".v1"
+)
+
+var (
+	// AllFixableRuleIDs are the IDs of all rules that have fixes, in sorted order.
+	AllFixableRuleIDs = []string{
+		enumValuePrefixRuleID,
+		enumValueUpperSnakeCaseRuleID,
+		enumZeroValueSuffixRuleID,
+		fieldLowerSnakeCaseRuleID,
+		importUsedRuleID,
+		packageVersionSuffixRuleID,
+	}
+)
+
+// FixBucket applies the fixes for the FileAnnotations to the .proto files in the bucket.
+//
+// The bucket should contain all local files that may reference the files with
+// FileAnnotations, so that references can be updated. Paths of FileAnnotations are
+// relative to the root of the bucket.
+//
+// Returns a new bucket with all the .proto files of the bucket, whether or not they
+// were changed, and the FileAnnotations that could not be fixed.
+func FixBucket(
+	ctx context.Context,
+	bucket storage.ReadBucket,
+	fileAnnotations []bufanalysis.FileAnnotation,
+	options ...FixOption,
+) (storage.ReadBucket, []bufanalysis.FileAnnotation, error) {
+	fixOptions := newFixOptions()
+	for _, option := range options {
+		option(fixOptions)
+	}
+	paths, err := storage.AllPaths(ctx, storage.FilterReadBucket(bucket, storage.MatchPathExt(".proto")), "")
+	if err != nil {
+		return nil, nil, err
+	}
+	files := make([]*file, 0, len(paths))
+	for _, path := range paths {
+		file, err := readFile(ctx, bucket, path)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, file)
+	}
+	unfixedFileAnnotations := newFixer(files, fixOptions).fix(fileAnnotations)
+	readWriteBucket := storagemem.NewReadWriteBucket()
+	for _, file := range files {
+		if err := writeFile(ctx, readWriteBucket, file); err != nil {
+			return nil, nil, err
+		}
+	}
+	return readWriteBucket, unfixedFileAnnotations, nil
+}
+
+// SuggestEdits returns the edits that fix each of the FileAnnotations, for the
+// FileAnnotations that can be fixed.
+//
+// The edits of each FileAnnotation are computed as if only that FileAnnotation was fixed,
+// except that FileAnnotations that are fixed by the same rename share the same edits, as
+// with FixBucket. The offsets of the edits are relative to the files in the bucket.
+//
+// The bucket should contain the same files as for FixBucket.
+func SuggestEdits(
+	ctx context.Context,
+	bucket storage.ReadBucket,
+	fileAnnotations []bufanalysis.FileAnnotation,
+	options ...FixOption,
+) (map[bufanalysis.FileAnnotation][]bufanalysis.SuggestedEdit, error) {
+	fixOptions := newFixOptions()
+	for _, option := range options {
+		option(fixOptions)
+	}
+	paths, err := storage.AllPaths(ctx, storage.FilterReadBucket(bucket, storage.MatchPathExt(".proto")), "")
+	if err != nil {
+		return nil, err
+	}
+	files := make([]*file, 0, len(paths))
+	pathToFile := make(map[string]*file, len(paths))
+	for _, path := range paths {
+		file, err := readFile(ctx, bucket, path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+		pathToFile[path] = file
+	}
+	// The FileAnnotations are grouped by what they rename or remove, as FixBucket
+	// fixes the FileAnnotations of each group together.
+	var groupKeys []string
+	groupKeyToFileAnnotations := make(map[string][]bufanalysis.FileAnnotation)
+	for _, fileAnnotation := range fileAnnotations {
+		if !slices.Contains(AllFixableRuleIDs, fileAnnotation.Type()) || fileAnnotation.FileInfo() == nil {
+			continue
+		}
+		file, ok := pathToFile[fileAnnotation.FileInfo().Path()]
+		if !ok {
+			continue
+		}
+		groupKey := fmt.Sprintf("%s:%d:%d", file.path, fileAnnotation.StartLine(), fileAnnotation.StartColumn())
+		if fileAnnotation.Type() == packageVersionSuffixRuleID {
+			groupKey = "package:" + file.pkg
+		}
+		if _, ok := groupKeyToFileAnnotations[groupKey]; !ok {
+			groupKeys = append(groupKeys, groupKey)
+		}
+		groupKeyToFileAnnotations[groupKey] = append(groupKeyToFileAnnotations[groupKey], fileAnnotation)
+	}
+	fileAnnotationToSuggestedEdits := make(map[bufanalysis.FileAnnotation][]bufanalysis.SuggestedEdit)
+	for _, groupKey := range groupKeys {
+		groupFileAnnotations := groupKeyToFileAnnotations[groupKey]
+		// The files are created again for each group, so that the edits of each group
+		// are independent of the edits of the other groups.
+		groupFiles := make([]*file, len(files))
+		for i, file := range files {
+			groupFiles[i] = newFile(file.path, file.externalPath, file.data, file.fileNode)
+		}
+		unfixedFileAnnotations := newFixer(groupFiles, fixOptions).fix(groupFileAnnotations)
+		var suggestedEdits []bufanalysis.SuggestedEdit
+		for _, file := range groupFiles {
+			edits, err := file.getEdits()
+			if err != nil {
+				return nil, err
+			}
+			for _, edit := range edits {
+				suggestedEdits = append(
+					suggestedEdits,
+					bufanalysis.SuggestedEdit{
+						Path:        file.externalPath,
+						StartOffset: edit.start,
+						EndOffset:   edit.end,
+						Replacement: edit.text,
+					},
+				)
+			}
+		}
+		if len(suggestedEdits) == 0 {
+			continue
+		}
+		for _, fileAnnotation := range groupFileAnnotations {
+			if !slices.Contains(unfixedFileAnnotations, fileAnnotation) {
+				fileAnnotationToSuggestedEdits[fileAnnotation] = suggestedEdits
+			}
+		}
+	}
+	return fileAnnotationToSuggestedEdits, nil
+}
+
+// FixOption is an option for FixBucket and SuggestEdits.
+type FixOption func(*fixOptions)
+
+// FixWithPathToEnumZeroValueSuffix returns a new FixOption that sets the suffix for
+// ENUM_ZERO_VALUE_SUFFIX fixes of each path.
+//
+// Paths without a suffix use the default suffix "_UNSPECIFIED".
+func FixWithPathToEnumZeroValueSuffix(pathToEnumZeroValueSuffix map[string]string) FixOption {
+	return func(fixOptions *fixOptions) {
+		fixOptions.pathToEnumZeroValueSuffix = pathToEnumZeroValueSuffix
+	}
+}
+
+// *** PRIVATE ***
+
+type fixOptions struct {
+	pathToEnumZeroValueSuffix map[string]string
+}
+
+func newFixOptions() *fixOptions {
+	return &fixOptions{}
+}
+
+func (f *fixOptions) getEnumZeroValueSuffix(path string) string {
+	if suffix := f.pathToEnumZeroValueSuffix[path]; suffix != "" {
+		return suffix
+	}
+	return defaultEnumZeroValueSuffix
+}
+
+func readFile(ctx context.Context, bucket storage.ReadBucket, path string) (_ *file, retErr error) {
+	readObjectCloser, err := bucket.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, readObjectCloser.Close())
+	}()
+	data, err := io.ReadAll(readObjectCloser)
+	if err != nil {
+		return nil, err
+	}
+	fileNode, err := parser.Parse(readObjectCloser.ExternalPath(), bytes.NewReader(data), reporter.NewHandler(nil))
+	if err != nil {
+		return nil, err
+	}
+	return newFile(path, readObjectCloser.ExternalPath(), data, fileNode), nil
+}
+
+func writeFile(ctx context.Context, writeBucket storage.WriteBucket, file *file) (retErr error) {
+	data, err := file.applyEdits()
+	if err != nil {
+		return err
+	}
+	writeObjectCloser, err := writeBucket.Put(ctx, file.path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, writeObjectCloser.Close())
+	}()
+	if _, err := writeObjectCloser.Write(data); err != nil {
+		return err
+	}
+	return writeObjectCloser.SetExternalPath(file.externalPath)
+}
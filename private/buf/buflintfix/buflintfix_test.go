@@ -0,0 +1,262 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflintfix
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
+	"github.com/bufbuild/buf/private/pkg/storage/storageos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixBucket(t *testing.T) {
+	t.Parallel()
+	fieldFOOBAR := newFileAnnotation("acme/user/user.proto", 15, 10, fieldLowerSnakeCaseRuleID)
+	testFixBucket(
+		t,
+		"basic",
+		[]bufanalysis.FileAnnotation{
+			newFileAnnotation("acme/user/user.proto", 3, 1, packageVersionSuffixRuleID),
+			newFileAnnotation("acme/user/user.proto", 6, 1, importUsedRuleID),
+			newFileAnnotation("acme/user/user.proto", 10, 10, fieldLowerSnakeCaseRuleID),
+			newFileAnnotation("acme/user/user.proto", 13, 12, fieldLowerSnakeCaseRuleID),
+			fieldFOOBAR,
+			newFileAnnotation("acme/user/user.proto", 18, 10, fieldLowerSnakeCaseRuleID),
+			newFileAnnotation("acme/user/user.proto", 19, 10, fieldLowerSnakeCaseRuleID),
+			newFileAnnotation("acme/user/user.proto", 23, 3, enumValuePrefixRuleID),
+			newFileAnnotation("acme/user/user.proto", 23, 3, enumZeroValueSuffixRuleID),
+			newFileAnnotation("acme/user/user.proto", 24, 3, enumValuePrefixRuleID),
+			newFileAnnotation("acme/user/user.proto", 24, 3, enumValueUpperSnakeCaseRuleID),
+		},
+		// FOO_BAR conflicts with foo_bar.
+		[]bufanalysis.FileAnnotation{
+			fieldFOOBAR,
+		},
+	)
+}
+
+func TestFixBucketUnfixable(t *testing.T) {
+	t.Parallel()
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		// The package is also declared in b.proto, which has no annotation.
+		newFileAnnotation("acme/split/a.proto", 3, 1, packageVersionSuffixRuleID),
+		// The enum value is referenced by the default of a field.
+		newFileAnnotation("acme/split/a.proto", 12, 3, enumValueUpperSnakeCaseRuleID),
+		// Extensions are referenced by name in options.
+		newFileAnnotation("acme/split/a.proto", 16, 19, fieldLowerSnakeCaseRuleID),
+		// The rule has no fix.
+		newFileAnnotation("acme/split/b.proto", 5, 9, "MESSAGE_PASCAL_CASE"),
+		// The annotation is not at a field.
+		newFileAnnotation("acme/split/b.proto", 5, 1, fieldLowerSnakeCaseRuleID),
+		// The file does not exist.
+		newFileAnnotation("acme/split/c.proto", 1, 1, importUsedRuleID),
+	}
+	testFixBucket(t, "unfixable", fileAnnotations, fileAnnotations)
+}
+
+func TestFixBucketEnumZeroValueSuffix(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	bucket, err := storagemem.NewReadBucket(
+		map[string][]byte{
+			"a.proto": []byte(`syntax = "proto3";
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+}
+`),
+		},
+	)
+	require.NoError(t, err)
+	fixedBucket, unfixedFileAnnotations, err := FixBucket(
+		ctx,
+		bucket,
+		[]bufanalysis.FileAnnotation{
+			newFileAnnotation("a.proto", 3, 3, enumZeroValueSuffixRuleID),
+		},
+		FixWithPathToEnumZeroValueSuffix(map[string]string{"a.proto": "_NONE"}),
+	)
+	require.NoError(t, err)
+	assert.Empty(t, unfixedFileAnnotations)
+	data, err := storage.ReadPath(ctx, fixedBucket, "a.proto")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`syntax = "proto3";
+enum Status {
+  STATUS_UNSPECIFIED_NONE = 0;
+}
+`,
+		string(data),
+	)
+}
+
+func TestFixBucketRemoveImports(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	bucket, err := storagemem.NewReadBucket(
+		map[string][]byte{
+			"a.proto": []byte(`syntax = "proto3";
+
+import "google/protobuf/duration.proto";
+import "google/protobuf/empty.proto"; // trailing
+
+message Foo {}
+`),
+		},
+	)
+	require.NoError(t, err)
+	fixedBucket, unfixedFileAnnotations, err := FixBucket(
+		ctx,
+		bucket,
+		[]bufanalysis.FileAnnotation{
+			newFileAnnotation("a.proto", 3, 1, importUsedRuleID),
+			newFileAnnotation("a.proto", 4, 1, importUsedRuleID),
+		},
+	)
+	require.NoError(t, err)
+	assert.Empty(t, unfixedFileAnnotations)
+	data, err := storage.ReadPath(ctx, fixedBucket, "a.proto")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`syntax = "proto3";
+
+message Foo {}
+`,
+		string(data),
+	)
+}
+
+func TestSuggestEdits(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	bucket, err := storagemem.NewReadBucket(
+		map[string][]byte{
+			"a.proto": []byte(`syntax = "proto3";
+
+import "google/protobuf/empty.proto";
+
+message Foo {
+  string fooBar = 1;
+  string FOO_BAR = 2;
+}
+`),
+		},
+	)
+	require.NoError(t, err)
+	importUnused := newFileAnnotation("a.proto", 3, 1, importUsedRuleID)
+	fieldFooBar := newFileAnnotation("a.proto", 6, 10, fieldLowerSnakeCaseRuleID)
+	// foo_bar conflicts with fooBar.
+	fieldFOOBAR := newFileAnnotation("a.proto", 7, 10, fieldLowerSnakeCaseRuleID)
+	messageFoo := newFileAnnotation("a.proto", 5, 9, "MESSAGE_PASCAL_CASE")
+	fileAnnotationToSuggestedEdits, err := SuggestEdits(
+		ctx,
+		bucket,
+		[]bufanalysis.FileAnnotation{
+			importUnused,
+			fieldFooBar,
+			fieldFOOBAR,
+			messageFoo,
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		map[bufanalysis.FileAnnotation][]bufanalysis.SuggestedEdit{
+			importUnused: {
+				{
+					Path:        "a.proto",
+					StartOffset: 20,
+					EndOffset:   59,
+				},
+			},
+			fieldFooBar: {
+				{
+					Path:        "a.proto",
+					StartOffset: 82,
+					EndOffset:   88,
+					Replacement: "foo_bar",
+				},
+				{
+					Path:        "a.proto",
+					StartOffset: 92,
+					EndOffset:   92,
+					Replacement: ` [json_name = "fooBar"]`,
+				},
+			},
+		},
+		fileAnnotationToSuggestedEdits,
+	)
+}
+
+func testFixBucket(
+	t *testing.T,
+	dirName string,
+	fileAnnotations []bufanalysis.FileAnnotation,
+	expectedUnfixedFileAnnotations []bufanalysis.FileAnnotation,
+) {
+	ctx := context.Background()
+	dirPath := filepath.Join("testdata", dirName)
+	bucket, err := storageos.NewProvider().NewReadWriteBucket(filepath.Join(dirPath, "input"))
+	require.NoError(t, err)
+	fixedBucket, unfixedFileAnnotations, err := FixBucket(ctx, bucket, fileAnnotations)
+	require.NoError(t, err)
+	assert.Equal(t, expectedUnfixedFileAnnotations, unfixedFileAnnotations)
+	paths, err := storage.AllPaths(ctx, bucket, "")
+	require.NoError(t, err)
+	for _, path := range paths {
+		expectedFilePath := filepath.Join(dirPath, "output", path)
+		if _, err := os.Stat(expectedFilePath); os.IsNotExist(err) {
+			expectedFilePath = filepath.Join(dirPath, "input", path)
+		}
+		expectedData, err := os.ReadFile(expectedFilePath)
+		require.NoError(t, err)
+		data, err := storage.ReadPath(ctx, fixedBucket, path)
+		require.NoError(t, err)
+		assert.Equal(t, string(expectedData), string(data), path)
+	}
+}
+
+func newFileAnnotation(path string, line int, column int, ruleID string) bufanalysis.FileAnnotation {
+	return bufanalysis.NewFileAnnotation(
+		&fileInfo{path: path},
+		line,
+		column,
+		line,
+		column,
+		ruleID,
+		"",
+		"",
+	)
+}
+
+type fileInfo struct {
+	path string
+}
+
+func (f *fileInfo) Path() string {
+	return f.path
+}
+
+func (f *fileInfo) ExternalPath() string {
+	return f.path
+}
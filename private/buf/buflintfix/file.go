@@ -0,0 +1,395 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflintfix
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/syserror"
+	"github.com/bufbuild/protocompile/ast"
+)
+
+// file is a parsed .proto file and the edits to apply to it.
+type file struct {
+	path         string
+	externalPath string
+	data         []byte
+	fileNode     *ast.FileNode
+	pkg          string
+	packageNode  *ast.PackageNode
+	// positionToImportNode maps the start of import statements to the statements.
+	positionToImportNode map[position]*ast.ImportNode
+	// positionToDecl maps the start of the names of fields and enum values to their declarations.
+	positionToDecl map[position]*decl
+	// scopeToDecls maps each scope to the fields or enum values declared in the scope.
+	scopeToDecls map[ast.Node][]*decl
+	// typeReferences are all references to types and extensions.
+	typeReferences []*typeReference
+	// optionValueNames are all identifiers in option values, such as enum value names
+	// and the names of fields in message literals.
+	optionValueNames map[string]struct{}
+	edits            []*edit
+	removedNodes     []ast.Node
+}
+
+// position is a 1-indexed line and column, as used by FileAnnotations.
+type position struct {
+	line   int
+	column int
+}
+
+// decl is the declaration of a field or enum value.
+type decl struct {
+	file     *file
+	nameNode *ast.IdentNode
+	// scope is the message that the field is declared in, or the message or file that
+	// the enum of the enum value is declared in, as enum values are siblings of their enum.
+	scope ast.Node
+	// enumNode is only set for enum values.
+	enumNode *ast.EnumNode
+	// number is only set for enum values.
+	number int64
+	// isExtension is only set for fields.
+	isExtension bool
+	// options are the compact options of fields, and are nil if the field has no options.
+	options *ast.CompactOptionsNode
+	// semicolon is only set for fields.
+	semicolon *ast.RuneNode
+	// name is the current name of the declaration, which is updated as it is renamed.
+	name string
+}
+
+func (d *decl) isEnumValue() bool {
+	return d.enumNode != nil
+}
+
+// hasJSONName returns true if the field has the json_name option.
+func (d *decl) hasJSONName() bool {
+	if d.options == nil {
+		return false
+	}
+	for _, optionNode := range d.options.Options {
+		if parts := optionNode.Name.Parts; len(parts) == 1 && !parts[0].IsExtension() && string(parts[0].Name.AsIdentifier()) == "json_name" {
+			return true
+		}
+	}
+	return false
+}
+
+// typeReference is a reference to a type or extension.
+type typeReference struct {
+	node ast.IdentValueNode
+	// isAnyTypeURL is true if the reference is the type name of an Any type URL in a
+	// message literal, which is always fully qualified and never starts with a dot.
+	isAnyTypeURL bool
+}
+
+// edit replaces the bytes in [start, end) with text.
+type edit struct {
+	start int
+	end   int
+	text  string
+}
+
+func newFile(path string, externalPath string, data []byte, fileNode *ast.FileNode) *file {
+	file := &file{
+		path:                 path,
+		externalPath:         externalPath,
+		data:                 data,
+		fileNode:             fileNode,
+		positionToImportNode: make(map[position]*ast.ImportNode),
+		positionToDecl:       make(map[position]*decl),
+		scopeToDecls:         make(map[ast.Node][]*decl),
+		optionValueNames:     make(map[string]struct{}),
+	}
+	ancestorTracker := &ast.AncestorTracker{}
+	_ = ast.Walk(
+		fileNode,
+		&ast.SimpleVisitor{
+			DoVisitPackageNode: func(packageNode *ast.PackageNode) error {
+				file.packageNode = packageNode
+				file.pkg = string(packageNode.Name.AsIdentifier())
+				return nil
+			},
+			DoVisitImportNode: func(importNode *ast.ImportNode) error {
+				file.positionToImportNode[file.getPosition(importNode)] = importNode
+				return nil
+			},
+			DoVisitFieldNode: func(fieldNode *ast.FieldNode) error {
+				file.addTypeReference(fieldNode.FldType, false)
+				file.addFieldDecl(fieldNode.Name, fieldNode.Extendee != nil, fieldNode.Options, fieldNode.Semicolon, ancestorTracker.Path())
+				return nil
+			},
+			DoVisitMapFieldNode: func(mapFieldNode *ast.MapFieldNode) error {
+				file.addTypeReference(mapFieldNode.MapType.ValueType, false)
+				file.addFieldDecl(mapFieldNode.Name, false, mapFieldNode.Options, mapFieldNode.Semicolon, ancestorTracker.Path())
+				return nil
+			},
+			DoVisitEnumValueNode: func(enumValueNode *ast.EnumValueNode) error {
+				path := ancestorTracker.Path()
+				// The path ends with the enum value, preceded by the enum, preceded by the
+				// message or file the enum is declared in.
+				enumNode, ok := path[len(path)-2].(*ast.EnumNode)
+				if !ok {
+					return nil
+				}
+				number, _ := enumValueNode.Number.AsInt64()
+				file.addDecl(
+					&decl{
+						file:     file,
+						nameNode: enumValueNode.Name,
+						scope:    path[len(path)-3],
+						enumNode: enumNode,
+						number:   number,
+						name:     enumValueNode.Name.Val,
+					},
+				)
+				return nil
+			},
+			DoVisitRPCTypeNode: func(rpcTypeNode *ast.RPCTypeNode) error {
+				file.addTypeReference(rpcTypeNode.MessageType, false)
+				return nil
+			},
+			DoVisitExtendNode: func(extendNode *ast.ExtendNode) error {
+				file.addTypeReference(extendNode.Extendee, false)
+				return nil
+			},
+			DoVisitFieldReferenceNode: func(fieldReferenceNode *ast.FieldReferenceNode) error {
+				switch {
+				case fieldReferenceNode.IsAnyTypeReference():
+					file.addTypeReference(fieldReferenceNode.Name, true)
+				case fieldReferenceNode.IsExtension():
+					file.addTypeReference(fieldReferenceNode.Name, false)
+				}
+				return nil
+			},
+			DoVisitOptionNode: func(optionNode *ast.OptionNode) error {
+				return ast.Walk(
+					optionNode.Val,
+					&ast.SimpleVisitor{
+						DoVisitIdentNode: func(identNode *ast.IdentNode) error {
+							file.optionValueNames[identNode.Val] = struct{}{}
+							return nil
+						},
+					},
+				)
+			},
+		},
+		ancestorTracker.AsWalkOptions()...,
+	)
+	return file
+}
+
+func (f *file) addFieldDecl(
+	nameNode *ast.IdentNode,
+	isExtension bool,
+	options *ast.CompactOptionsNode,
+	semicolon *ast.RuneNode,
+	path []ast.Node,
+) {
+	f.addDecl(
+		&decl{
+			file:        f,
+			nameNode:    nameNode,
+			scope:       getFieldScope(path),
+			isExtension: isExtension,
+			options:     options,
+			semicolon:   semicolon,
+			name:        nameNode.Val,
+		},
+	)
+}
+
+func (f *file) addDecl(decl *decl) {
+	f.positionToDecl[f.getPosition(decl.nameNode)] = decl
+	f.scopeToDecls[decl.scope] = append(f.scopeToDecls[decl.scope], decl)
+}
+
+func (f *file) addTypeReference(node ast.IdentValueNode, isAnyTypeURL bool) {
+	f.typeReferences = append(
+		f.typeReferences,
+		&typeReference{
+			node:         node,
+			isAnyTypeURL: isAnyTypeURL,
+		},
+	)
+}
+
+func (f *file) getPosition(node ast.Node) position {
+	start := f.fileNode.NodeInfo(node).Start()
+	return position{
+		line:   start.Line,
+		column: start.Col,
+	}
+}
+
+// replaceNode adds an edit that replaces the node with the text.
+func (f *file) replaceNode(node ast.Node, text string) {
+	start, end := f.getSpan(node)
+	f.edits = append(
+		f.edits,
+		&edit{
+			start: start,
+			end:   end,
+			text:  text,
+		},
+	)
+}
+
+// insertAfter adds an edit that inserts the text after the node.
+func (f *file) insertAfter(node ast.Node, text string) {
+	_, end := f.getSpan(node)
+	f.edits = append(
+		f.edits,
+		&edit{
+			start: end,
+			end:   end,
+			text:  text,
+		},
+	)
+}
+
+// insertBefore adds an edit that inserts the text before the node.
+func (f *file) insertBefore(node ast.Node, text string) {
+	start, _ := f.getSpan(node)
+	f.edits = append(
+		f.edits,
+		&edit{
+			start: start,
+			end:   start,
+			text:  text,
+		},
+	)
+}
+
+// removeLines removes the node, and the lines that the node is on if the lines contain
+// nothing other than the node, whitespace, and a trailing comment.
+func (f *file) removeLines(node ast.Node) {
+	f.removedNodes = append(f.removedNodes, node)
+}
+
+// getRemovalEdits returns the edits for the removed nodes.
+//
+// If removed lines are between two blank lines, one of the blank lines is also removed,
+// so that removing a group of lines does not leave two blank lines.
+func (f *file) getRemovalEdits() []*edit {
+	var edits []*edit
+	var lineEdits []*edit
+	for _, node := range f.removedNodes {
+		start, end := f.getSpan(node)
+		lineStart := bytes.LastIndexByte(f.data[:start], '\n') + 1
+		lineEnd := getLineEnd(f.data, end)
+		rest := bytes.TrimSpace(f.data[end:lineEnd])
+		if len(bytes.TrimSpace(f.data[lineStart:start])) == 0 && (len(rest) == 0 || bytes.HasPrefix(rest, []byte("//"))) {
+			lineEdits = append(lineEdits, &edit{start: lineStart, end: lineEnd})
+		} else {
+			edits = append(edits, &edit{start: start, end: end})
+		}
+	}
+	slices.SortFunc(
+		lineEdits,
+		func(a *edit, b *edit) int {
+			return a.start - b.start
+		},
+	)
+	var mergedLineEdits []*edit
+	for _, lineEdit := range lineEdits {
+		if n := len(mergedLineEdits); n > 0 && mergedLineEdits[n-1].end == lineEdit.start {
+			mergedLineEdits[n-1].end = lineEdit.end
+			continue
+		}
+		mergedLineEdits = append(mergedLineEdits, lineEdit)
+	}
+	for _, lineEdit := range mergedLineEdits {
+		previousLineStart := bytes.LastIndexByte(f.data[:max(lineEdit.start-1, 0)], '\n') + 1
+		isPreviousLineBlank := lineEdit.start == 0 || len(bytes.TrimSpace(f.data[previousLineStart:lineEdit.start])) == 0
+		nextLineEnd := getLineEnd(f.data, lineEdit.end)
+		isNextLineBlank := nextLineEnd > lineEdit.end && len(bytes.TrimSpace(f.data[lineEdit.end:nextLineEnd])) == 0
+		if isPreviousLineBlank && isNextLineBlank {
+			lineEdit.end = nextLineEnd
+		}
+	}
+	return append(edits, mergedLineEdits...)
+}
+
+// getSpan returns the byte offsets [start, end) of the node.
+func (f *file) getSpan(node ast.Node) (int, int) {
+	nodeInfo := f.fileNode.NodeInfo(node)
+	// The end position is the position after the last character of the node, but the
+	// offset of the end position is the offset of the last character.
+	return nodeInfo.Start().Offset, nodeInfo.End().Offset + 1
+}
+
+// getEdits returns all edits of the file, including the removal edits, sorted by
+// their start offsets.
+func (f *file) getEdits() ([]*edit, error) {
+	edits := append(slices.Clone(f.edits), f.getRemovalEdits()...)
+	slices.SortStableFunc(
+		edits,
+		func(a *edit, b *edit) int {
+			return a.start - b.start
+		},
+	)
+	var offset int
+	for _, edit := range edits {
+		if edit.start < offset {
+			return nil, syserror.Newf("overlapping edits in %s at offset %d", f.path, edit.start)
+		}
+		offset = edit.end
+	}
+	return edits, nil
+}
+
+func (f *file) applyEdits() ([]byte, error) {
+	edits, err := f.getEdits()
+	if err != nil {
+		return nil, err
+	}
+	if len(edits) == 0 {
+		return f.data, nil
+	}
+	var builder strings.Builder
+	var offset int
+	for _, edit := range edits {
+		_, _ = builder.Write(f.data[offset:edit.start])
+		_, _ = builder.WriteString(edit.text)
+		offset = edit.end
+	}
+	_, _ = builder.Write(f.data[offset:])
+	return []byte(builder.String()), nil
+}
+
+// getFieldScope returns the closest message that encloses the field at the end of the
+// path, as the field may be declared in a oneof.
+func getFieldScope(path []ast.Node) ast.Node {
+	for i := len(path) - 2; i >= 0; i-- {
+		switch path[i].(type) {
+		case *ast.MessageNode, *ast.GroupNode, *ast.ExtendNode, *ast.FileNode:
+			return path[i]
+		}
+	}
+	return nil
+}
+
+// getLineEnd returns the offset after the end of the line that contains the offset,
+// including the newline.
+func getLineEnd(data []byte, offset int) int {
+	if i := bytes.IndexByte(data[offset:], '\n'); i >= 0 {
+		return offset + i + 1
+	}
+	return len(data)
+}
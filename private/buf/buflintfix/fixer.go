@@ -0,0 +1,334 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflintfix
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/bufbuild/protocompile/ast"
+)
+
+type fixer struct {
+	fixOptions     *fixOptions
+	files          []*file
+	pathToFile     map[string]*file
+	packageToFiles map[string][]*file
+	// namespaces are all packages and their prefixes, for example acme and acme.user
+	// for the package acme.user.
+	namespaces map[string]struct{}
+	// optionValueNames are the optionValueNames of all files.
+	optionValueNames map[string]struct{}
+}
+
+func newFixer(files []*file, fixOptions *fixOptions) *fixer {
+	fixer := &fixer{
+		fixOptions:       fixOptions,
+		files:            files,
+		pathToFile:       make(map[string]*file),
+		packageToFiles:   make(map[string][]*file),
+		namespaces:       make(map[string]struct{}),
+		optionValueNames: make(map[string]struct{}),
+	}
+	for _, file := range files {
+		fixer.pathToFile[file.path] = file
+		for name := range file.optionValueNames {
+			fixer.optionValueNames[name] = struct{}{}
+		}
+		if file.pkg == "" {
+			continue
+		}
+		fixer.packageToFiles[file.pkg] = append(fixer.packageToFiles[file.pkg], file)
+		for namespace := file.pkg; namespace != ""; namespace = getParentNamespace(namespace) {
+			fixer.namespaces[namespace] = struct{}{}
+		}
+	}
+	return fixer
+}
+
+// fix adds the edits for the FileAnnotations to the files, and returns the FileAnnotations
+// that could not be fixed, in their original order.
+func (f *fixer) fix(fileAnnotations []bufanalysis.FileAnnotation) []bufanalysis.FileAnnotation {
+	unfixedFileAnnotations := make(map[bufanalysis.FileAnnotation]struct{})
+	var decls []*decl
+	declToFileAnnotations := make(map[*decl][]bufanalysis.FileAnnotation)
+	var packages []string
+	packageToFileAnnotations := make(map[string][]bufanalysis.FileAnnotation)
+	removedImportNodes := make(map[*ast.ImportNode]struct{})
+	for _, fileAnnotation := range fileAnnotations {
+		var file *file
+		if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+			file = f.pathToFile[fileInfo.Path()]
+		}
+		if file == nil {
+			unfixedFileAnnotations[fileAnnotation] = struct{}{}
+			continue
+		}
+		position := position{
+			line:   fileAnnotation.StartLine(),
+			column: fileAnnotation.StartColumn(),
+		}
+		switch ruleID := fileAnnotation.Type(); ruleID {
+		case enumValuePrefixRuleID, enumValueUpperSnakeCaseRuleID, enumZeroValueSuffixRuleID, fieldLowerSnakeCaseRuleID:
+			decl, ok := file.positionToDecl[position]
+			if !ok || decl.isEnumValue() == (ruleID == fieldLowerSnakeCaseRuleID) {
+				unfixedFileAnnotations[fileAnnotation] = struct{}{}
+				continue
+			}
+			if _, ok := declToFileAnnotations[decl]; !ok {
+				decls = append(decls, decl)
+			}
+			declToFileAnnotations[decl] = append(declToFileAnnotations[decl], fileAnnotation)
+		case importUsedRuleID:
+			importNode, ok := file.positionToImportNode[position]
+			if !ok {
+				unfixedFileAnnotations[fileAnnotation] = struct{}{}
+				continue
+			}
+			if _, ok := removedImportNodes[importNode]; !ok {
+				removedImportNodes[importNode] = struct{}{}
+				file.removeLines(importNode)
+			}
+		case packageVersionSuffixRuleID:
+			if file.packageNode == nil || file.getPosition(file.packageNode) != position {
+				unfixedFileAnnotations[fileAnnotation] = struct{}{}
+				continue
+			}
+			if _, ok := packageToFileAnnotations[file.pkg]; !ok {
+				packages = append(packages, file.pkg)
+			}
+			packageToFileAnnotations[file.pkg] = append(packageToFileAnnotations[file.pkg], fileAnnotation)
+		default:
+			unfixedFileAnnotations[fileAnnotation] = struct{}{}
+		}
+	}
+	for _, decl := range decls {
+		if !f.renameDecl(decl, declToFileAnnotations[decl]) {
+			for _, fileAnnotation := range declToFileAnnotations[decl] {
+				unfixedFileAnnotations[fileAnnotation] = struct{}{}
+			}
+		}
+	}
+	oldPackageToNewPackage := make(map[string]string)
+	for _, pkg := range packages {
+		newPackage, ok := f.renamePackage(pkg, packageToFileAnnotations[pkg])
+		if !ok {
+			for _, fileAnnotation := range packageToFileAnnotations[pkg] {
+				unfixedFileAnnotations[fileAnnotation] = struct{}{}
+			}
+			continue
+		}
+		oldPackageToNewPackage[pkg] = newPackage
+	}
+	if len(oldPackageToNewPackage) > 0 {
+		f.updateTypeReferences(oldPackageToNewPackage)
+	}
+	var result []bufanalysis.FileAnnotation
+	for _, fileAnnotation := range fileAnnotations {
+		if _, ok := unfixedFileAnnotations[fileAnnotation]; ok {
+			result = append(result, fileAnnotation)
+		}
+	}
+	return result
+}
+
+// renameDecl renames the declaration to fix all of the FileAnnotations, and returns
+// false if the declaration cannot be renamed.
+func (f *fixer) renameDecl(decl *decl, fileAnnotations []bufanalysis.FileAnnotation) bool {
+	ruleIDs := make(map[string]struct{})
+	for _, fileAnnotation := range fileAnnotations {
+		ruleIDs[fileAnnotation.Type()] = struct{}{}
+	}
+	newName := decl.name
+	if decl.isEnumValue() {
+		enumValuePrefix := stringutil.ToUpperSnakeCase(decl.enumNode.Name.Val) + "_"
+		if _, ok := ruleIDs[enumValueUpperSnakeCaseRuleID]; ok {
+			newName = stringutil.ToUpperSnakeCase(newName)
+		}
+		if _, ok := ruleIDs[enumValuePrefixRuleID]; ok && !strings.HasPrefix(newName, enumValuePrefix) {
+			newName = enumValuePrefix + newName
+		}
+		if _, ok := ruleIDs[enumZeroValueSuffixRuleID]; ok && decl.number == 0 {
+			suffix := f.fixOptions.getEnumZeroValueSuffix(decl.file.path)
+			if !strings.HasSuffix(newName, suffix) {
+				// The suffix is appended, so that the meaning of the name is kept.
+				newName += suffix
+			}
+		}
+	} else {
+		if decl.isExtension {
+			// Extensions are referenced by name in options, which are not updated.
+			return false
+		}
+		newName = stringutil.ToLowerSnakeCase(newName)
+	}
+	if newName == decl.name {
+		return false
+	}
+	if _, ok := f.optionValueNames[decl.name]; ok {
+		// The declaration may be referenced from an option value, such as the default
+		// of a field or a message literal, which are not updated.
+		return false
+	}
+	for _, other := range decl.file.scopeToDecls[decl.scope] {
+		if other == decl {
+			continue
+		}
+		if other.name == newName {
+			return false
+		}
+		if !decl.isEnumValue() && !other.isEnumValue() && getJSONName(other.name) == getJSONName(newName) {
+			return false
+		}
+	}
+	if !decl.isEnumValue() && !decl.hasJSONName() {
+		// The JSON name of the field is kept, so that the rename does not change the
+		// JSON encoding of the message.
+		jsonNameOption := fmt.Sprintf("json_name = %q", getJSONName(decl.name))
+		if decl.options == nil {
+			decl.file.insertBefore(decl.semicolon, " ["+jsonNameOption+"]")
+		} else {
+			decl.file.insertAfter(decl.options.OpenBracket, jsonNameOption+", ")
+		}
+	}
+	decl.name = newName
+	decl.file.replaceNode(decl.nameNode, newName)
+	return true
+}
+
+// renamePackage suffixes the package with a version, and returns the new package, or
+// false if the package cannot be renamed.
+func (f *fixer) renamePackage(pkg string, fileAnnotations []bufanalysis.FileAnnotation) (string, bool) {
+	newPackage := pkg + defaultPackageVersionSuffix
+	if _, ok := f.packageToFiles[newPackage]; ok {
+		return "", false
+	}
+	annotatedPaths := make(map[string]struct{})
+	for _, fileAnnotation := range fileAnnotations {
+		annotatedPaths[fileAnnotation.FileInfo().Path()] = struct{}{}
+	}
+	files := f.packageToFiles[pkg]
+	for _, file := range files {
+		if _, ok := annotatedPaths[file.path]; !ok {
+			// Every file of the package must be renamed, or the package is split.
+			return "", false
+		}
+	}
+	for _, file := range files {
+		file.replaceNode(file.packageNode.Name, newPackage)
+	}
+	return newPackage, true
+}
+
+// updateTypeReferences updates the references to types in the renamed packages.
+func (f *fixer) updateTypeReferences(oldPackageToNewPackage map[string]string) {
+	for _, file := range f.files {
+		for _, typeReference := range file.typeReferences {
+			name := string(typeReference.node.AsIdentifier())
+			fullName, scope, ok := f.resolve(file.pkg, name, typeReference.isAnyTypeURL)
+			if !ok {
+				continue
+			}
+			pkg := f.getPackage(fullName)
+			newPackage, ok := oldPackageToNewPackage[pkg]
+			if !ok || scope == pkg || strings.HasPrefix(scope, pkg+".") {
+				// References relative to the package do not change, as the package is renamed.
+				continue
+			}
+			newFullName := newPackage + strings.TrimPrefix(fullName, pkg)
+			switch {
+			case strings.HasPrefix(name, "."):
+				file.replaceNode(typeReference.node, "."+newFullName)
+			case scope == "":
+				file.replaceNode(typeReference.node, newFullName)
+			default:
+				file.replaceNode(typeReference.node, strings.TrimPrefix(newFullName, scope+"."))
+			}
+		}
+	}
+}
+
+// resolve resolves the full name of a reference to a type or extension in a package,
+// and the scope the reference is relative to, or returns false if the reference is
+// relative to the declarations of the file.
+//
+// References are resolved the same way as by protoc: the first component of the
+// reference is searched for from the innermost scope outwards. Only packages are
+// searched for, as only references qualified with a package are updated.
+func (f *fixer) resolve(pkg string, name string, isAnyTypeURL bool) (string, string, bool) {
+	if isAnyTypeURL {
+		return name, "", true
+	}
+	if fullName, ok := strings.CutPrefix(name, "."); ok {
+		return fullName, "", true
+	}
+	firstComponent, _, ok := strings.Cut(name, ".")
+	if !ok {
+		return "", "", false
+	}
+	for scope := pkg; ; scope = getParentNamespace(scope) {
+		if _, ok := f.namespaces[joinNamespace(scope, firstComponent)]; ok {
+			return joinNamespace(scope, name), scope, true
+		}
+		if scope == "" {
+			return "", "", false
+		}
+	}
+}
+
+// getPackage returns the longest package that the full name is declared in, or empty
+// if there is no such package.
+func (f *fixer) getPackage(fullName string) string {
+	for namespace := getParentNamespace(fullName); namespace != ""; namespace = getParentNamespace(namespace) {
+		if _, ok := f.packageToFiles[namespace]; ok {
+			return namespace
+		}
+	}
+	return ""
+}
+
+func getParentNamespace(namespace string) string {
+	if i := strings.LastIndexByte(namespace, '.'); i >= 0 {
+		return namespace[:i]
+	}
+	return ""
+}
+
+func joinNamespace(namespace string, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// getJSONName returns the default JSON name of a field, as computed by protoc.
+func getJSONName(name string) string {
+	var builder strings.Builder
+	var capitalizeNext bool
+	for _, r := range name {
+		if r == '_' {
+			capitalizeNext = true
+			continue
+		}
+		if capitalizeNext && 'a' <= r && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		capitalizeNext = false
+		_, _ = builder.WriteRune(r)
+	}
+	return builder.String()
+}
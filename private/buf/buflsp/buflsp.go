@@ -14,6 +14,13 @@
 
 // Package buflsp implements a language server for Protobuf.
 //
+// Protobuf text format files, with the .txtpb and .textproto extensions, are also
+// supported, with diagnostics, completion of field names, and formatting.
+//
+// Changes to buf.yaml, buf.work.yaml, buf.gen.yaml, and buf.lock files are picked up
+// without a restart if the client supports watching files. Changes are validated
+// before open files are refreshed.
+//
 // The main entry-point of this package is the Serve() function, which creates a new LSP server.
 package buflsp
 
@@ -21,10 +28,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 
 	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/bufreload"
 	"github.com/bufbuild/buf/private/pkg/app/appext"
 	"github.com/bufbuild/buf/private/pkg/slogext"
 	"github.com/bufbuild/buf/private/pkg/storage"
@@ -71,6 +80,7 @@ func Serve(
 		wasmRuntime: wasmRuntime,
 		rootBucket:  bucket,
 		wktBucket:   wktBucket,
+		reloader:    bufreload.NewReloader(container.Logger(), container),
 	}
 	lsp.fileManager = newFileManager(lsp)
 	off := protocol.TraceOff
@@ -101,6 +111,9 @@ type lsp struct {
 	fileManager *fileManager
 
 	wktBucket storage.ReadBucket
+	// reloader validates changes to configuration files, such as buf.yaml, before
+	// open files are refreshed to use them.
+	reloader bufreload.Reloader
 
 	lock sync.Mutex
 
@@ -128,6 +141,28 @@ func (l *lsp) init(_ context.Context, params *protocol.InitializeParams) error {
 	return nil
 }
 
+// trackConfigFiles records the current content of the configuration files that may
+// apply to the file with the given URI, so that later changes to them can be described.
+//
+// These are the configuration files in the directory of the file and all of its
+// parent directories.
+func (l *lsp) trackConfigFiles(ctx context.Context, uri protocol.URI) {
+	dirPath := filepath.Dir(uri.Filename())
+	for {
+		for _, configFileName := range bufreload.ConfigFileNames() {
+			configFilePath := filepath.Join(dirPath, configFileName)
+			if err := l.reloader.Track(ctx, configFilePath); err != nil {
+				l.logger.Debug("could not track configuration file", slog.String("path", configFilePath), slogext.ErrorAttr(err))
+			}
+		}
+		parentDirPath := filepath.Dir(dirPath)
+		if parentDirPath == dirPath {
+			return
+		}
+		dirPath = parentDirPath
+	}
+}
+
 // newHandler constructs an RPC handler that wraps the default one from jsonrpc2. This allows us
 // to inject debug logging, tracing, and timeouts to requests.
 func (l *lsp) newHandler() jsonrpc2.Handler {
@@ -32,6 +32,15 @@ const (
 	// The Git revision to use for calculating the --against input for a
 	// breaking check when using the "git" strategy.
 	ConfigBreakingGitRef = "buf.checks.breaking.againstGitRef"
+	// A mapping of file path patterns to fully-qualified message names, used
+	// to determine the message type of text format files that do not have a
+	// "# proto-message:" header comment.
+	//
+	// Patterns are matched with filepath.Match against the same number of
+	// trailing path components as they contain, for example "*.txtpb" matches
+	// the file name and "testdata/*.txtpb" matches the file name and its
+	// parent directory.
+	ConfigTxtpbMessages = "buf.txtpb.messages"
 )
 
 const (
@@ -45,6 +54,22 @@ const (
 // --against for the purposes of breaking lints.
 type againstStrategy int
 
+// parseTxtpbMessages parses the text format message mapping from a config
+// setting sent by the client.
+//
+// Returns nil, false if any of the values are not strings.
+func parseTxtpbMessages(raw map[string]any) (map[string]string, bool) {
+	txtpbMessages := make(map[string]string, len(raw))
+	for pattern, value := range raw {
+		messageName, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		txtpbMessages[pattern] = messageName
+	}
+	return txtpbMessages, true
+}
+
 // parseAgainstStrategy parses an againstKind from a config setting sent by
 // the client.
 //
@@ -28,9 +28,11 @@ import (
 	"strings"
 	"time"
 
+	"buf.build/go/bufplugin/check"
 	"github.com/bufbuild/buf/private/buf/bufworkspace"
 	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
 	"github.com/bufbuild/buf/private/pkg/git"
@@ -42,11 +44,17 @@ import (
 	"github.com/bufbuild/protocompile/parser"
 	"github.com/bufbuild/protocompile/reporter"
 	"go.lsp.dev/protocol"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 const (
 	descriptorPath      = "google/protobuf/descriptor.proto"
 	refreshCheckStagger = 5 * time.Millisecond
+
+	// The sources of diagnostics produced by lint and breaking rules.
+	lintDiagnosticSource     = "buf lint"
+	breakingDiagnosticSource = "buf breaking"
 )
 
 // file is a file that has been opened by the client.
@@ -69,6 +77,8 @@ type file struct {
 
 	againstStrategy againstStrategy
 	againstGitRef   string
+	// The mapping of file path patterns to message names for text format files.
+	txtpbMessages map[string]string
 
 	objectInfo             storage.ObjectInfo
 	importablePathToObject map[string]storage.ObjectInfo
@@ -79,6 +89,10 @@ type file struct {
 	importToFile        map[string]*file
 	symbols             []*symbol
 	image, againstImage bufimage.Image
+
+	// Only set for text format files, see RefreshTxtpb.
+	txtpbMessage protoreflect.MessageDescriptor
+	txtpbTypes   *dynamicpb.Types
 }
 
 // IsWKT returns whether this file corresponds to a well-known type.
@@ -122,6 +136,8 @@ func (f *file) Reset(ctx context.Context) {
 	f.importToFile = nil
 	f.symbols = nil
 	f.image = nil
+	f.txtpbMessage = nil
+	f.txtpbTypes = nil
 
 	for _, imported := range f.importToFile {
 		imported.Close(ctx)
@@ -184,6 +200,7 @@ func (f *file) RefreshSettings(ctx context.Context) {
 		Items: []protocol.ConfigurationItem{
 			{ScopeURI: f.uri, Section: ConfigBreakingStrategy},
 			{ScopeURI: f.uri, Section: ConfigBreakingGitRef},
+			{ScopeURI: f.uri, Section: ConfigTxtpbMessages},
 		},
 	})
 	if err != nil {
@@ -194,6 +211,7 @@ func (f *file) RefreshSettings(ctx context.Context) {
 	// NOTE: indices here are those from the array in the call to Configuration above.
 	f.againstStrategy = getSetting(f, settings, ConfigBreakingStrategy, 0, parseAgainstStrategy)
 	f.againstGitRef = getSetting(f, settings, ConfigBreakingGitRef, 1, func(s string) (string, bool) { return s, true })
+	f.txtpbMessages = getSetting(f, settings, ConfigTxtpbMessages, 2, parseTxtpbMessages)
 
 	switch f.againstStrategy {
 	case againstDisk:
@@ -280,6 +298,16 @@ func (f *file) Refresh(ctx context.Context) {
 	}
 	progress.Begin(ctx, "Indexing")
 
+	if f.IsTxtpb() {
+		// Text format files have no AST, imports, or symbols, so we only need
+		// to resolve their message and validate them.
+		progress.Report(ctx, "Validating", 1.0/2)
+		f.RefreshTxtpb(ctx)
+		progress.Done(ctx)
+		f.PublishDiagnostics(ctx)
+		return
+	}
+
 	progress.Report(ctx, "Parsing AST", 1.0/6)
 	f.RefreshAST(ctx)
 
@@ -679,7 +707,7 @@ func (f *file) RunLints(ctx context.Context) bool {
 	}
 
 	f.lsp.logger.Debug(fmt.Sprintf("running lint for %q in %v", f.uri, f.module.FullName()))
-	return f.appendLintErrors("buf lint", f.checkClient.Lint(
+	return f.appendLintErrors(lintDiagnosticSource, f.checkClient.Lint(
 		ctx,
 		f.workspace.GetLintConfigForOpaqueID(f.module.OpaqueID()),
 		f.image,
@@ -706,7 +734,7 @@ func (f *file) RunBreaking(ctx context.Context) bool {
 	}
 
 	f.lsp.logger.Debug(fmt.Sprintf("running breaking for %q in %v", f.uri, f.module.FullName()))
-	return f.appendLintErrors("buf breaking", f.checkClient.Breaking(
+	return f.appendLintErrors(breakingDiagnosticSource, f.checkClient.Breaking(
 		ctx,
 		f.workspace.GetBreakingConfigForOpaqueID(f.module.OpaqueID()),
 		f.image,
@@ -732,6 +760,10 @@ func (f *file) appendLintErrors(source string, err error) bool {
 	}
 
 	for _, annotation := range annotations.FileAnnotations() {
+		severity := protocol.DiagnosticSeverityError
+		if annotation.Severity() == bufanalysis.SeverityWarning {
+			severity = protocol.DiagnosticSeverityWarning
+		}
 		f.diagnostics = append(f.diagnostics, protocol.Diagnostic{
 			Range: protocol.Range{
 				Start: protocol.Position{
@@ -744,7 +776,7 @@ func (f *file) appendLintErrors(source string, err error) bool {
 				},
 			},
 			Code:     annotation.Type(),
-			Severity: protocol.DiagnosticSeverityError,
+			Severity: severity,
 			Source:   source,
 			Message:  annotation.Message(),
 		})
@@ -753,6 +785,50 @@ func (f *file) appendLintErrors(source string, err error) bool {
 	return true
 }
 
+// CheckDiagnosticsAt returns the diagnostics produced by lint and breaking rules whose
+// range contains the given position.
+func (f *file) CheckDiagnosticsAt(cursor protocol.Position) []protocol.Diagnostic {
+	var diagnostics []protocol.Diagnostic
+	for _, diagnostic := range f.diagnostics {
+		if diagnostic.Source != lintDiagnosticSource && diagnostic.Source != breakingDiagnosticSource {
+			continue
+		}
+		if comparePositions(diagnostic.Range.Start, cursor) > 0 || comparePositions(diagnostic.Range.End, cursor) <= 0 {
+			continue
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// formatRuleExplanation formats the explanation of the builtin rule that produced the
+// diagnostic as Markdown, for display on hover. Returns "" if the rule is not builtin.
+func formatRuleExplanation(diagnostic protocol.Diagnostic) string {
+	ruleType := check.RuleTypeLint
+	if diagnostic.Source == breakingDiagnosticSource {
+		ruleType = check.RuleTypeBreaking
+	}
+	ruleID, _ := diagnostic.Code.(string)
+	explanation, ok := bufcheckserver.GetRuleExplanation(ruleType, ruleID)
+	if !ok {
+		return ""
+	}
+	var tooltip strings.Builder
+	fmt.Fprintf(&tooltip, "**%s** (%s)\n\n%s\n", ruleID, diagnostic.Source, explanation.Rationale)
+	fmt.Fprintln(&tooltip, "\n**Remediations:**")
+	for _, remediation := range explanation.Remediations {
+		fmt.Fprintf(&tooltip, "- %s\n", remediation)
+	}
+	if len(explanation.ConfigKeys) > 0 {
+		fmt.Fprintln(&tooltip, "\n**Config keys:**")
+		for _, configKey := range explanation.ConfigKeys {
+			fmt.Fprintf(&tooltip, "- `%s`\n", configKey)
+		}
+	}
+	fmt.Fprintf(&tooltip, "\nRun `buf explain %s` for more details.", ruleID)
+	return tooltip.String()
+}
+
 // IndexSymbols processes the AST of a file and generates symbols for each symbol in
 // the document.
 func (f *file) IndexSymbols(ctx context.Context) {
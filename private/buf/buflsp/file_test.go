@@ -0,0 +1,58 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflsp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.lsp.dev/protocol"
+)
+
+func TestCheckDiagnosticsAt(t *testing.T) {
+	t.Parallel()
+
+	lintDiagnostic := protocol.Diagnostic{
+		Range:  protocol.Range{Start: protocol.Position{Line: 2, Character: 4}, End: protocol.Position{Line: 2, Character: 10}},
+		Code:   "FIELD_LOWER_SNAKE_CASE",
+		Source: lintDiagnosticSource,
+	}
+	compileDiagnostic := protocol.Diagnostic{
+		Range:  lintDiagnostic.Range,
+		Source: serverName,
+	}
+	f := &file{diagnostics: []protocol.Diagnostic{lintDiagnostic, compileDiagnostic}}
+	assert.Equal(t, []protocol.Diagnostic{lintDiagnostic}, f.CheckDiagnosticsAt(protocol.Position{Line: 2, Character: 4}))
+	assert.Equal(t, []protocol.Diagnostic{lintDiagnostic}, f.CheckDiagnosticsAt(protocol.Position{Line: 2, Character: 9}))
+	assert.Empty(t, f.CheckDiagnosticsAt(protocol.Position{Line: 2, Character: 10}))
+	assert.Empty(t, f.CheckDiagnosticsAt(protocol.Position{Line: 1, Character: 5}))
+}
+
+func TestFormatRuleExplanation(t *testing.T) {
+	t.Parallel()
+
+	explanation := formatRuleExplanation(protocol.Diagnostic{
+		Code:   "FIELD_SAME_TYPE",
+		Source: breakingDiagnosticSource,
+	})
+	assert.Contains(t, explanation, "**FIELD_SAME_TYPE** (buf breaking)")
+	assert.Contains(t, explanation, "**Remediations:**")
+	assert.Contains(t, explanation, "buf explain FIELD_SAME_TYPE")
+	// Rules provided by plugins have no explanation.
+	assert.Empty(t, formatRuleExplanation(protocol.Diagnostic{
+		Code:   "PLUGIN_RULE",
+		Source: lintDiagnosticSource,
+	}))
+}
@@ -24,6 +24,8 @@ import (
 	"strings"
 
 	"github.com/bufbuild/buf/private/buf/bufformat"
+	"github.com/bufbuild/buf/private/buf/bufreload"
+	"github.com/bufbuild/buf/private/bufpkg/buftxtpb"
 	"github.com/bufbuild/protocompile/ast"
 	"go.lsp.dev/protocol"
 )
@@ -118,6 +120,8 @@ func (s *server) Initialize(
 					IncludeText: false,
 				},
 			},
+			// Completion is currently only supported for text format files.
+			CompletionProvider: &protocol.CompletionOptions{},
 			DefinitionProvider: &protocol.DefinitionOptions{
 				WorkDoneProgressOptions: protocol.WorkDoneProgressOptions{WorkDoneProgress: true},
 			},
@@ -155,6 +159,26 @@ func (s *server) Initialized(
 			},
 		})
 	}
+	didChangeWatchedFiles := workspaceCapabilities.DidChangeWatchedFiles
+	if didChangeWatchedFiles != nil && didChangeWatchedFiles.DynamicRegistration {
+		configFileNames := bufreload.ConfigFileNames()
+		watchers := make([]protocol.FileSystemWatcher, len(configFileNames))
+		for i, configFileName := range configFileNames {
+			watchers[i] = protocol.FileSystemWatcher{GlobPattern: "**/" + configFileName}
+		}
+		// The error is logged for us by the client wrapper.
+		_ = s.client.RegisterCapability(ctx, &protocol.RegistrationParams{
+			Registrations: []protocol.Registration{
+				{
+					ID:     protocol.MethodWorkspaceDidChangeWatchedFiles,
+					Method: protocol.MethodWorkspaceDidChangeWatchedFiles,
+					RegisterOptions: protocol.DidChangeWatchedFilesRegistrationOptions{
+						Watchers: watchers,
+					},
+				},
+			},
+		})
+	}
 
 	return nil
 }
@@ -201,6 +225,43 @@ func (s *server) DidChangeConfiguration(
 	return nil
 }
 
+// DidChangeWatchedFiles is sent whenever a file we registered a watcher for changes.
+//
+// We watch configuration files, such as buf.yaml, and refresh every open file
+// when one of them changes. Invalid changes are reported to the user, and files
+// are not refreshed for them.
+func (s *server) DidChangeWatchedFiles(
+	ctx context.Context,
+	params *protocol.DidChangeWatchedFilesParams,
+) error {
+	var reloaded bool
+	for _, fileEvent := range params.Changes {
+		path := fileEvent.URI.Filename()
+		if !bufreload.IsConfigFilePath(path) {
+			continue
+		}
+		if _, err := s.lsp.reloader.Reload(ctx, path); err != nil {
+			// The error is logged for us by the client wrapper.
+			_ = s.client.ShowMessage(ctx, &protocol.ShowMessageParams{
+				Type:    protocol.MessageTypeWarning,
+				Message: fmt.Sprintf("Not reloading invalid configuration %s: %s", path, err),
+			})
+			continue
+		}
+		reloaded = true
+	}
+	if !reloaded {
+		return nil
+	}
+	s.fileManager.uriToFile.Range(func(_ protocol.URI, file *file) bool {
+		if file.IsOpenInEditor() {
+			file.Refresh(ctx)
+		}
+		return true
+	})
+	return nil
+}
+
 // -- File synchronization methods.
 
 // DidOpen is called whenever the client opens a document. This is our signal to parse
@@ -210,6 +271,7 @@ func (s *server) DidOpen(
 	params *protocol.DidOpenTextDocumentParams,
 ) error {
 	file := s.fileManager.Open(ctx, params.TextDocument.URI)
+	s.lsp.trackConfigFiles(ctx, params.TextDocument.URI)
 	file.RefreshSettings(ctx)
 	file.Update(ctx, params.TextDocument.Version, params.TextDocument.Text)
 	file.Refresh(ctx)
@@ -260,27 +322,35 @@ func (s *server) Formatting(
 		return nil, fmt.Errorf("received update for file that was not open: %q", params.TextDocument.URI)
 	}
 
-	// We check the diagnostics on the file, if there are any build errors, we do not want
-	// to format an invalid AST, so we skip formatting and return an error for logging.
-	errorCount := 0
-	for _, diagnostic := range file.diagnostics {
-		if diagnostic.Severity == protocol.DiagnosticSeverityError {
-			errorCount += 1
-		}
-	}
-	if errorCount > 0 {
-		return nil, fmt.Errorf("cannot format file %q, %v error(s) found", file.uri.Filename(), errorCount)
-	}
-
 	// Currently we have no way to honor any of the parameters.
 	_ = params
-	if file.fileNode == nil {
-		return nil, nil
-	}
 
 	var out strings.Builder
-	if err := bufformat.FormatFileNode(&out, file.fileNode); err != nil {
-		return nil, err
+	if file.IsTxtpb() {
+		// Text format files are formatted as long as they are syntactically valid,
+		// which the formatter checks itself. Other errors, such as unknown fields,
+		// do not prevent formatting.
+		if err := buftxtpb.Format(&out, []byte(file.text)); err != nil {
+			return nil, fmt.Errorf("cannot format file %q: %w", file.uri.Filename(), err)
+		}
+	} else {
+		// We check the diagnostics on the file, if there are any build errors, we do not want
+		// to format an invalid AST, so we skip formatting and return an error for logging.
+		errorCount := 0
+		for _, diagnostic := range file.diagnostics {
+			if diagnostic.Severity == protocol.DiagnosticSeverityError {
+				errorCount += 1
+			}
+		}
+		if errorCount > 0 {
+			return nil, fmt.Errorf("cannot format file %q, %v error(s) found", file.uri.Filename(), errorCount)
+		}
+		if file.fileNode == nil {
+			return nil, nil
+		}
+		if err := bufformat.FormatFileNode(&out, file.fileNode); err != nil {
+			return nil, err
+		}
 	}
 
 	newText := out.String()
@@ -331,6 +401,25 @@ func (s *server) DidClose(
 
 // -- Language functionality methods.
 
+// Completion is the entry point for code completion.
+//
+// This is currently only supported for the field names of text format files.
+func (s *server) Completion(
+	ctx context.Context,
+	params *protocol.CompletionParams,
+) (*protocol.CompletionList, error) {
+	file := s.fileManager.Get(params.TextDocument.URI)
+	if file == nil || !file.IsTxtpb() {
+		return nil, nil
+	}
+
+	items := file.CompleteTxtpb(ctx, params.Position)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return &protocol.CompletionList{Items: items}, nil
+}
+
 // Hover is the entry point for hover inlays.
 func (s *server) Hover(
 	ctx context.Context,
@@ -341,25 +430,36 @@ func (s *server) Hover(
 		return nil, nil
 	}
 
-	symbol := file.SymbolAt(ctx, params.Position)
-	if symbol == nil {
-		return nil, nil
+	var (
+		sections []string
+		range_   protocol.Range // Need to spill this here because Hover.Range is a pointer.
+	)
+	if symbol := file.SymbolAt(ctx, params.Position); symbol != nil {
+		if docs := symbol.FormatDocs(ctx); docs != "" {
+			// Escape < and > occurrences in the docs.
+			replacer := strings.NewReplacer("<", "&lt;", ">", "&gt;")
+			sections = append(sections, replacer.Replace(docs))
+			range_ = symbol.Range()
+		}
 	}
-
-	docs := symbol.FormatDocs(ctx)
-	if docs == "" {
+	for _, diagnostic := range file.CheckDiagnosticsAt(params.Position) {
+		explanation := formatRuleExplanation(diagnostic)
+		if explanation == "" {
+			continue
+		}
+		if len(sections) == 0 {
+			range_ = diagnostic.Range
+		}
+		sections = append(sections, explanation)
+	}
+	if len(sections) == 0 {
 		return nil, nil
 	}
 
-	// Escape < and > occurrences in the docs.
-	replacer := strings.NewReplacer("<", "&lt;", ">", "&gt;")
-	docs = replacer.Replace(docs)
-
-	range_ := symbol.Range() // Need to spill this here because Hover.Range is a pointer.
 	return &protocol.Hover{
 		Contents: protocol.MarkupContent{
 			Kind:  protocol.Markdown,
-			Value: docs,
+			Value: strings.Join(sections, "\n\n---\n\n"),
 		},
 		Range: &range_,
 	}, nil
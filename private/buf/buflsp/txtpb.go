@@ -0,0 +1,357 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file defines support for Protobuf text format files.
+
+package buflsp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/buftxtpb"
+	"github.com/bufbuild/buf/private/pkg/slogext"
+	"github.com/bufbuild/protocompile"
+	"github.com/bufbuild/protocompile/linker"
+	"go.lsp.dev/protocol"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// txtpbErrorPositionRegexp matches the position in errors returned by prototext.
+var txtpbErrorPositionRegexp = regexp.MustCompile(`\(line (\d+):(\d+)\): `)
+
+// IsTxtpb returns whether this file is a Protobuf text format file.
+func (f *file) IsTxtpb() bool {
+	return buftxtpb.IsTxtpbPath(f.uri.Filename())
+}
+
+// RefreshTxtpb resolves the message type of this text format file, and
+// validates the file against it.
+//
+// The message type is determined by the header comments of the file, falling
+// back to the buf.txtpb.messages configuration setting.
+func (f *file) RefreshTxtpb(ctx context.Context) {
+	// NOTE: We intentionally use an empty slice, see RefreshAST.
+	f.diagnostics = []protocol.Diagnostic{}
+	f.txtpbMessage = nil
+	f.txtpbTypes = nil
+
+	header := buftxtpb.ParseHeader([]byte(f.text))
+	messageName := header.ProtoMessage
+	if messageName == "" {
+		messageName = f.txtpbMessageFromSettings()
+	}
+	if messageName == "" {
+		f.diagnostics = append(f.diagnostics, protocol.Diagnostic{
+			Severity: protocol.DiagnosticSeverityWarning,
+			Source:   serverName,
+			Message: fmt.Sprintf(
+				`could not determine the message type of this file, add a "# proto-message: <fully-qualified name>" header comment or configure %s`,
+				ConfigTxtpbMessages,
+			),
+		})
+		return
+	}
+
+	files, err := f.compileForTxtpb(ctx, header.ProtoFile)
+	if err != nil {
+		f.lsp.logger.Warn("could not compile files for text format file", slog.String("uri", string(f.uri)), slogext.ErrorAttr(err))
+	}
+	registryFiles := new(protoregistry.Files)
+	for _, file := range files {
+		registerFileWithDeps(registryFiles, file)
+	}
+	descriptor, err := registryFiles.FindDescriptorByName(protoreflect.FullName(messageName))
+	messageDescriptor, ok := descriptor.(protoreflect.MessageDescriptor)
+	if err != nil || !ok {
+		message := fmt.Sprintf("could not find message %q", messageName)
+		if header.ProtoFile != "" {
+			message = fmt.Sprintf("could not find message %q in %q", messageName, header.ProtoFile)
+		}
+		f.diagnostics = append(f.diagnostics, protocol.Diagnostic{
+			Severity: protocol.DiagnosticSeverityError,
+			Source:   serverName,
+			Message:  message,
+		})
+		return
+	}
+	f.txtpbMessage = messageDescriptor
+	f.txtpbTypes = dynamicpb.NewTypes(registryFiles)
+
+	unmarshalOptions := prototext.UnmarshalOptions{Resolver: f.txtpbTypes}
+	if err := unmarshalOptions.Unmarshal([]byte(f.text), dynamicpb.NewMessage(messageDescriptor)); err != nil {
+		f.diagnostics = append(f.diagnostics, f.newTxtpbDiagnostic(err))
+	}
+}
+
+// CompleteTxtpb returns the completion items for the field names of the message
+// enclosing the given position.
+//
+// This operation requires RefreshTxtpb().
+func (f *file) CompleteTxtpb(ctx context.Context, position protocol.Position) []protocol.CompletionItem {
+	if f.txtpbMessage == nil {
+		return nil
+	}
+	path, ok := buftxtpb.FieldPathAt([]byte(f.text), positionToOffset(f.text, position))
+	if !ok {
+		return nil
+	}
+	messageDescriptor := f.txtpbMessage
+	for _, name := range path {
+		messageDescriptor = f.txtpbFieldMessage(messageDescriptor, name)
+		if messageDescriptor == nil {
+			return nil
+		}
+	}
+	fields := messageDescriptor.Fields()
+	items := make([]protocol.CompletionItem, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		item := protocol.CompletionItem{
+			Label:  field.TextName(),
+			Kind:   protocol.CompletionItemKindField,
+			Detail: txtpbFieldDetail(field),
+		}
+		if comments := strings.TrimSpace(field.ParentFile().SourceLocations().ByDescriptor(field).LeadingComments); comments != "" {
+			item.Documentation = comments
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// txtpbFieldMessage returns the message type of the field with the given name
+// in the message, as named in a text format file.
+//
+// Returns nil if the field cannot be found or is not a message.
+func (f *file) txtpbFieldMessage(messageDescriptor protoreflect.MessageDescriptor, name string) protoreflect.MessageDescriptor {
+	if strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]") {
+		name = strings.TrimSuffix(strings.TrimPrefix(name, "["), "]")
+		if index := strings.LastIndex(name, "/"); index != -1 {
+			// This is the type URL of an expanded Any.
+			messageType, err := f.txtpbTypes.FindMessageByName(protoreflect.FullName(name[index+1:]))
+			if err != nil {
+				return nil
+			}
+			return messageType.Descriptor()
+		}
+		extensionType, err := f.txtpbTypes.FindExtensionByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil
+		}
+		return extensionType.TypeDescriptor().Message()
+	}
+	fields := messageDescriptor.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if field := fields.Get(i); field.TextName() == name {
+			return field.Message()
+		}
+	}
+	return nil
+}
+
+// txtpbMessageFromSettings returns the message name for this file from the
+// buf.txtpb.messages configuration setting, or the empty string.
+func (f *file) txtpbMessageFromSettings() string {
+	filePath := filepath.ToSlash(f.uri.Filename())
+	for pattern, messageName := range f.txtpbMessages {
+		// Patterns are matched against the same number of trailing path
+		// components as they contain.
+		components := strings.Split(filePath, "/")
+		if count := strings.Count(pattern, "/") + 1; count < len(components) {
+			components = components[len(components)-count:]
+		}
+		if matched, err := filepath.Match(pattern, strings.Join(components, "/")); err == nil && matched {
+			return messageName
+		}
+	}
+	return ""
+}
+
+// compileForTxtpb compiles the files needed to resolve the message of this text
+// format file.
+//
+// If protoFile is empty, all files in the local modules of the workspace are
+// compiled.
+func (f *file) compileForTxtpb(ctx context.Context, protoFile string) (linker.Files, error) {
+	workspaceDirPath := findWorkspaceDirPath(filepath.Dir(f.uri.Filename()))
+	importable, err := findImportable(ctx, protocol.URI("file://"+workspaceDirPath), f.lsp)
+	if err != nil {
+		return nil, err
+	}
+	f.importablePathToObject = importable
+	paths := []string{protoFile}
+	if protoFile == "" {
+		paths, err = f.localProtoFilePaths(ctx, workspaceDirPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var report report
+	compiler := protocompile.Compiler{
+		// Source info is needed for the documentation of completion items.
+		SourceInfoMode: protocompile.SourceInfoStandard,
+		Resolver:       &protocompile.SourceResolver{Accessor: f.newFileOpener()},
+		Reporter:       &report,
+	}
+	files, err := compiler.Compile(ctx, paths...)
+	// Partial results are still useful, so we only return the error if there are none.
+	var nonNilFiles linker.Files
+	for _, file := range files {
+		if file != nil {
+			nonNilFiles = append(nonNilFiles, file)
+		}
+	}
+	if len(nonNilFiles) == 0 {
+		return nil, err
+	}
+	return nonNilFiles, nil
+}
+
+// localProtoFilePaths returns the paths of all the .proto files in the local
+// modules of the workspace at the given directory.
+func (f *file) localProtoFilePaths(ctx context.Context, workspaceDirPath string) ([]string, error) {
+	workspace, err := f.lsp.controller.GetWorkspace(ctx, workspaceDirPath)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, module := range workspace.Modules() {
+		if !module.IsLocal() {
+			continue
+		}
+		if err := module.WalkFileInfos(ctx, func(fileInfo bufmodule.FileInfo) error {
+			if fileInfo.FileType() == bufmodule.FileTypeProto {
+				paths = append(paths, fileInfo.Path())
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// newTxtpbDiagnostic returns a new diagnostic for an error from prototext.
+//
+// If the error contains a position, the diagnostic spans the token at that
+// position, otherwise the diagnostic is placed at the start of the file.
+func (f *file) newTxtpbDiagnostic(err error) protocol.Diagnostic {
+	// The space after the prefix is randomly a non-breaking space, so we trim any space.
+	message := strings.TrimLeftFunc(strings.TrimPrefix(err.Error(), "proto:"), unicode.IsSpace)
+	var start, end protocol.Position
+	if match := txtpbErrorPositionRegexp.FindStringSubmatchIndex(message); match != nil {
+		// Both are 1-indexed, and the column is in runes.
+		line, _ := strconv.Atoi(message[match[2]:match[3]])
+		column, _ := strconv.Atoi(message[match[4]:match[5]])
+		message = message[:match[0]] + message[match[1]:]
+		start = protocol.Position{Line: uint32(line - 1), Character: uint32(column - 1)}
+		startOffset := positionToOffset(f.text, start)
+		endOffset := buftxtpb.TokenEndAt([]byte(f.text), startOffset)
+		end = start
+		end.Character += uint32(len([]rune(f.text[startOffset:endOffset])))
+	}
+	return protocol.Diagnostic{
+		Range:    protocol.Range{Start: start, End: end},
+		Severity: protocol.DiagnosticSeverityError,
+		Source:   serverName,
+		Message:  message,
+	}
+}
+
+// txtpbFieldDetail returns a short description of the type of the field.
+func txtpbFieldDetail(field protoreflect.FieldDescriptor) string {
+	var typeName string
+	switch {
+	case field.IsMap():
+		typeName = fmt.Sprintf("map<%s, %s>", txtpbFieldDetail(field.MapKey()), txtpbFieldDetail(field.MapValue()))
+	case field.Message() != nil:
+		typeName = string(field.Message().FullName())
+	case field.Enum() != nil:
+		typeName = string(field.Enum().FullName())
+	default:
+		typeName = field.Kind().String()
+	}
+	if field.IsList() {
+		return "repeated " + typeName
+	}
+	return typeName
+}
+
+// registerFileWithDeps registers the file and all of its transitive imports
+// with the registry, skipping any files that are already registered.
+func registerFileWithDeps(files *protoregistry.Files, file protoreflect.FileDescriptor) {
+	if _, err := files.FindFileByPath(file.Path()); err == nil {
+		return
+	}
+	imports := file.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		registerFileWithDeps(files, imports.Get(i).FileDescriptor)
+	}
+	// Errors for conflicting registrations are ignored, since the first registration wins.
+	_ = files.RegisterFile(file)
+}
+
+// findWorkspaceDirPath returns the closest directory to dirPath that contains
+// a buf.yaml or buf.work.yaml file, or dirPath if there is no such directory.
+func findWorkspaceDirPath(dirPath string) string {
+	for currentDirPath := dirPath; ; {
+		for _, fileName := range []string{bufconfig.DefaultBufYAMLFileName, bufconfig.DefaultBufWorkYAMLFileName} {
+			if _, err := os.Stat(filepath.Join(currentDirPath, fileName)); err == nil {
+				return currentDirPath
+			}
+		}
+		parentDirPath := filepath.Dir(currentDirPath)
+		if parentDirPath == currentDirPath {
+			return dirPath
+		}
+		currentDirPath = parentDirPath
+	}
+}
+
+// positionToOffset converts an LSP position to a byte offset in the text.
+//
+// FIXME: the LSP protocol defines positions in terms of UTF-16, but this treats
+// the character as a number of runes, consistent with the rest of this package.
+func positionToOffset(text string, position protocol.Position) int {
+	offset := 0
+	for line := uint32(0); line < position.Line; line++ {
+		index := strings.IndexByte(text[offset:], '\n')
+		if index == -1 {
+			return len(text)
+		}
+		offset += index + 1
+	}
+	for character := uint32(0); character < position.Character && offset < len(text); character++ {
+		if text[offset] == '\n' {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+	return offset
+}
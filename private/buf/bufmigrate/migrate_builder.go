@@ -196,6 +196,11 @@ func (m *migrateBuilder) addModule(ctx context.Context, moduleDirPath string) (r
 				false,
 				"",
 				false,
+				false,
+				nil,
+				nil,
+				nil,
+				nil,
 			),
 			bufconfig.NewBreakingConfig(
 				bufconfig.NewEnabledCheckConfigForUseIDsAndCategories(
@@ -204,6 +209,9 @@ func (m *migrateBuilder) addModule(ctx context.Context, moduleDirPath string) (r
 					false,
 				),
 				false,
+				nil,
+				nil,
+				nil,
 			),
 		)
 		if err != nil {
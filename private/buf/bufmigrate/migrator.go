@@ -663,6 +663,11 @@ func equivalentLintConfigInV2(
 		lintConfig.RPCAllowGoogleProtobufEmptyResponses(),
 		lintConfig.ServiceSuffix(),
 		lintConfig.AllowCommentIgnores(),
+		lintConfig.RequireCommentIgnoreJustification(),
+		lintConfig.WarnIDsAndCategories(),
+		lintConfig.NamingConfig(),
+		lintConfig.CommentsConfig(),
+		lintConfig.PackageDirectoryMappings(),
 	), nil
 }
 
@@ -683,6 +688,9 @@ func equivalentBreakingConfigInV2(
 	return bufconfig.NewBreakingConfig(
 		equivalentCheckConfigV2,
 		breakingConfig.IgnoreUnstablePackages(),
+		breakingConfig.IgnoreSymbols(),
+		breakingConfig.IgnoreIDOrCategoryToSymbols(),
+		breakingConfig.FailOnIDsAndCategories(),
 	), nil
 }
 
@@ -0,0 +1,343 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufperf records local statistics about invocations of the buf CLI.
+//
+// The statistics never leave the machine, they are only written to the cache directory
+// so that they can be summarized with buf beta perf report.
+package bufperf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bufbuild/buf/private/pkg/cachestats"
+	"github.com/bufbuild/buf/private/pkg/storage"
+)
+
+const (
+	// MaxRuns is the maximum number of Runs that are retained, older Runs are removed
+	// when new Runs are added.
+	MaxRuns = 1000
+
+	runsPath = "runs.jsonl"
+)
+
+// Run is a recorded invocation of a command.
+type Run struct {
+	// Command is the path of the command, for example "buf lint".
+	Command   string
+	StartTime time.Time
+	Duration  time.Duration
+	ExitCode  int
+	// CacheStats are the statistics of the caches used by the command.
+	CacheStats []cachestats.CacheStats
+}
+
+// ReadRuns reads the recorded Runs from the bucket, oldest first.
+//
+// Lines that are not valid Runs are skipped, as the file may have been written by a
+// different version of buf, or concurrently by another invocation.
+func ReadRuns(ctx context.Context, bucket storage.ReadBucket) ([]*Run, error) {
+	data, err := storage.ReadPath(ctx, bucket, runsPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var runs []*Run
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var externalRun externalRun
+		if err := json.Unmarshal(scanner.Bytes(), &externalRun); err != nil || externalRun.Command == "" {
+			continue
+		}
+		runs = append(runs, externalRun.toRun())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// AddRun adds the Run to the recorded Runs in the bucket, retaining at most MaxRuns Runs.
+func AddRun(ctx context.Context, bucket storage.ReadWriteBucket, run *Run) error {
+	runs, err := ReadRuns(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	runs = append(runs, run)
+	if len(runs) > MaxRuns {
+		runs = runs[len(runs)-MaxRuns:]
+	}
+	buffer := bytes.NewBuffer(nil)
+	for _, run := range runs {
+		data, err := json.Marshal(newExternalRun(run))
+		if err != nil {
+			return err
+		}
+		buffer.Write(data)
+		buffer.WriteByte('\n')
+	}
+	return storage.PutPath(ctx, bucket, runsPath, buffer.Bytes(), storage.PutWithAtomic())
+}
+
+// PrintReport prints a table that summarizes the Runs per command and per cache.
+func PrintReport(writer io.Writer, runs []*Run) error {
+	externalReport := newExternalReport(runs)
+	if _, err := fmt.Fprintf(
+		writer,
+		"%d runs, %s in total\n",
+		externalReport.Runs,
+		formatMilliseconds(externalReport.TotalMs),
+	); err != nil {
+		return err
+	}
+	if len(externalReport.Commands) == 0 {
+		return nil
+	}
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	lines := [][]string{
+		nil,
+		{"COMMAND", "RUNS", "FAILURES", "TOTAL", "MEDIAN", "P90", "MAX"},
+	}
+	for _, command := range externalReport.Commands {
+		lines = append(
+			lines,
+			[]string{
+				command.Command,
+				fmt.Sprintf("%d", command.Runs),
+				fmt.Sprintf("%d", command.Failures),
+				formatMilliseconds(command.TotalMs),
+				formatMilliseconds(command.MedianMs),
+				formatMilliseconds(command.P90Ms),
+				formatMilliseconds(command.MaxMs),
+			},
+		)
+	}
+	if len(externalReport.Caches) > 0 {
+		lines = append(lines, nil, []string{"CACHE", "LOOKUPS", "HITS", "HIT RATE"})
+		for _, cache := range externalReport.Caches {
+			lines = append(
+				lines,
+				[]string{
+					cache.Name,
+					fmt.Sprintf("%d", cache.Lookups),
+					fmt.Sprintf("%d", cache.Hits),
+					fmt.Sprintf("%.1f%%", cache.HitRate*100),
+				},
+			)
+		}
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(tabWriter, strings.Join(line, "\t")); err != nil {
+			return err
+		}
+	}
+	return tabWriter.Flush()
+}
+
+// PrintReportJSON prints the summary of the Runs per command and per cache as JSON.
+func PrintReportJSON(writer io.Writer, runs []*Run) error {
+	data, err := json.MarshalIndent(newExternalReport(runs), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(append(data, '\n'))
+	return err
+}
+
+// *** PRIVATE ***
+
+// externalRun is the JSON representation of a Run, as written to the runs file.
+type externalRun struct {
+	Command    string                `json:"command"`
+	StartTime  time.Time             `json:"start_time"`
+	DurationNs int64                 `json:"duration_ns"`
+	ExitCode   int                   `json:"exit_code"`
+	Caches     []*externalCacheStats `json:"caches,omitempty"`
+}
+
+type externalCacheStats struct {
+	Name    string `json:"name"`
+	Lookups int    `json:"lookups"`
+	Hits    int    `json:"hits"`
+}
+
+func newExternalRun(run *Run) *externalRun {
+	externalRun := &externalRun{
+		Command:    run.Command,
+		StartTime:  run.StartTime.UTC(),
+		DurationNs: run.Duration.Nanoseconds(),
+		ExitCode:   run.ExitCode,
+	}
+	for _, cacheStats := range run.CacheStats {
+		externalRun.Caches = append(
+			externalRun.Caches,
+			&externalCacheStats{
+				Name:    cacheStats.Name,
+				Lookups: cacheStats.Lookups,
+				Hits:    cacheStats.Hits,
+			},
+		)
+	}
+	return externalRun
+}
+
+func (e *externalRun) toRun() *Run {
+	run := &Run{
+		Command:   e.Command,
+		StartTime: e.StartTime,
+		Duration:  time.Duration(e.DurationNs),
+		ExitCode:  e.ExitCode,
+	}
+	for _, externalCacheStats := range e.Caches {
+		run.CacheStats = append(
+			run.CacheStats,
+			cachestats.CacheStats{
+				Name:    externalCacheStats.Name,
+				Lookups: externalCacheStats.Lookups,
+				Hits:    externalCacheStats.Hits,
+			},
+		)
+	}
+	return run
+}
+
+// externalReport is the JSON representation of a report.
+type externalReport struct {
+	Runs    int     `json:"runs"`
+	TotalMs float64 `json:"total_ms"`
+	// Commands are sorted by total duration, longest first.
+	Commands []*externalCommandReport `json:"commands"`
+	// Caches are sorted by name.
+	Caches []*externalCacheReport `json:"caches"`
+}
+
+type externalCommandReport struct {
+	Command  string  `json:"command"`
+	Runs     int     `json:"runs"`
+	Failures int     `json:"failures"`
+	TotalMs  float64 `json:"total_ms"`
+	MedianMs float64 `json:"median_ms"`
+	P90Ms    float64 `json:"p90_ms"`
+	MaxMs    float64 `json:"max_ms"`
+}
+
+type externalCacheReport struct {
+	Name    string  `json:"name"`
+	Lookups int     `json:"lookups"`
+	Hits    int     `json:"hits"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+func newExternalReport(runs []*Run) *externalReport {
+	externalReport := &externalReport{
+		Runs:     len(runs),
+		Commands: []*externalCommandReport{},
+		Caches:   []*externalCacheReport{},
+	}
+	commandToDurations := make(map[string][]time.Duration)
+	commandToFailures := make(map[string]int)
+	nameToCacheReport := make(map[string]*externalCacheReport)
+	var total time.Duration
+	for _, run := range runs {
+		total += run.Duration
+		commandToDurations[run.Command] = append(commandToDurations[run.Command], run.Duration)
+		if run.ExitCode != 0 {
+			commandToFailures[run.Command]++
+		}
+		for _, cacheStats := range run.CacheStats {
+			cacheReport, ok := nameToCacheReport[cacheStats.Name]
+			if !ok {
+				cacheReport = &externalCacheReport{Name: cacheStats.Name}
+				nameToCacheReport[cacheStats.Name] = cacheReport
+			}
+			cacheReport.Lookups += cacheStats.Lookups
+			cacheReport.Hits += cacheStats.Hits
+		}
+	}
+	externalReport.TotalMs = durationToMilliseconds(total)
+	for command, durations := range commandToDurations {
+		slices.Sort(durations)
+		var commandTotal time.Duration
+		for _, duration := range durations {
+			commandTotal += duration
+		}
+		externalReport.Commands = append(
+			externalReport.Commands,
+			&externalCommandReport{
+				Command:  command,
+				Runs:     len(durations),
+				Failures: commandToFailures[command],
+				TotalMs:  durationToMilliseconds(commandTotal),
+				MedianMs: durationToMilliseconds(getPercentile(durations, 50)),
+				P90Ms:    durationToMilliseconds(getPercentile(durations, 90)),
+				MaxMs:    durationToMilliseconds(durations[len(durations)-1]),
+			},
+		)
+	}
+	slices.SortFunc(
+		externalReport.Commands,
+		func(one *externalCommandReport, two *externalCommandReport) int {
+			if one.TotalMs != two.TotalMs {
+				if one.TotalMs > two.TotalMs {
+					return -1
+				}
+				return 1
+			}
+			return strings.Compare(one.Command, two.Command)
+		},
+	)
+	for _, cacheReport := range nameToCacheReport {
+		if cacheReport.Lookups > 0 {
+			cacheReport.HitRate = float64(cacheReport.Hits) / float64(cacheReport.Lookups)
+		}
+		externalReport.Caches = append(externalReport.Caches, cacheReport)
+	}
+	slices.SortFunc(
+		externalReport.Caches,
+		func(one *externalCacheReport, two *externalCacheReport) int {
+			return strings.Compare(one.Name, two.Name)
+		},
+	)
+	return externalReport
+}
+
+// getPercentile returns the nearest-rank percentile of the sorted durations.
+//
+// The durations must not be empty.
+func getPercentile(sortedDurations []time.Duration, percentile int) time.Duration {
+	index := (len(sortedDurations)*percentile+99)/100 - 1
+	return sortedDurations[max(index, 0)]
+}
+
+func durationToMilliseconds(duration time.Duration) float64 {
+	return float64(duration.Microseconds()) / 1000
+}
+
+func formatMilliseconds(milliseconds float64) string {
+	return fmt.Sprintf("%.1fms", milliseconds)
+}
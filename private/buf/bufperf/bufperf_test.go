@@ -0,0 +1,115 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufperf
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/buf/private/pkg/cachestats"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddRun(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	bucket := storagemem.NewReadWriteBucket()
+	runs, err := ReadRuns(ctx, bucket)
+	require.NoError(t, err)
+	require.Empty(t, runs)
+	startTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	expectedRuns := []*Run{
+		{
+			Command:   "buf lint",
+			StartTime: startTime,
+			Duration:  time.Second,
+			ExitCode:  100,
+			CacheStats: []cachestats.CacheStats{
+				{Name: "lint", Lookups: 1, Hits: 0},
+			},
+		},
+		{
+			Command:   "buf build",
+			StartTime: startTime.Add(time.Minute),
+			Duration:  time.Millisecond,
+		},
+	}
+	for _, run := range expectedRuns {
+		require.NoError(t, AddRun(ctx, bucket, run))
+	}
+	runs, err = ReadRuns(ctx, bucket)
+	require.NoError(t, err)
+	require.Equal(t, expectedRuns, runs)
+
+	// Invalid lines are skipped, and only the most recent MaxRuns runs are retained.
+	data, err := storage.ReadPath(ctx, bucket, runsPath)
+	require.NoError(t, err)
+	data = append([]byte("invalid\n"), bytes.Repeat(data, MaxRuns/2)...)
+	require.NoError(t, storage.PutPath(ctx, bucket, runsPath, data))
+	require.NoError(t, AddRun(ctx, bucket, &Run{Command: "buf format", StartTime: startTime}))
+	runs, err = ReadRuns(ctx, bucket)
+	require.NoError(t, err)
+	require.Len(t, runs, MaxRuns)
+	require.Equal(t, "buf build", runs[0].Command)
+	require.Equal(t, "buf format", runs[len(runs)-1].Command)
+}
+
+func TestPrintReport(t *testing.T) {
+	t.Parallel()
+	var runs []*Run
+	for i := 1; i <= 10; i++ {
+		runs = append(
+			runs,
+			&Run{
+				Command:  "buf lint",
+				Duration: time.Duration(i) * time.Millisecond,
+				CacheStats: []cachestats.CacheStats{
+					{Name: "lint", Lookups: 1, Hits: i % 2},
+				},
+			},
+		)
+	}
+	runs = append(
+		runs,
+		&Run{
+			Command:  "buf dep update",
+			Duration: 100 * time.Millisecond,
+			ExitCode: 1,
+			CacheStats: []cachestats.CacheStats{
+				{Name: "commits", Lookups: 4, Hits: 3},
+			},
+		},
+	)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, PrintReport(buffer, runs))
+	require.Equal(
+		t,
+		`11 runs, 155.0ms in total
+
+COMMAND         RUNS  FAILURES  TOTAL    MEDIAN   P90      MAX
+buf dep update  1     1         100.0ms  100.0ms  100.0ms  100.0ms
+buf lint        10    0         55.0ms   5.0ms    9.0ms    10.0ms
+
+CACHE    LOOKUPS  HITS  HIT RATE
+commits  4        3     75.0%
+lint     10       5     50.0%
+`,
+		buffer.String(),
+	)
+}
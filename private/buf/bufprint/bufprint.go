@@ -280,10 +280,27 @@ func NewUserEntity(user *registryv1alpha1.User) Entity {
 	}
 }
 
+// CuratedPluginLanguageString returns the user-facing name of the output language of a
+// curated plugin, such as "go" or "objective_c".
+func CuratedPluginLanguageString(pluginLanguage registryv1alpha1.PluginLanguage) string {
+	return strings.ToLower(strings.TrimPrefix(pluginLanguage.String(), "PLUGIN_LANGUAGE_"))
+}
+
 // CuratedPluginPrinter is a printer for curated plugins.
 type CuratedPluginPrinter interface {
 	PrintCuratedPlugin(ctx context.Context, format Format, plugin *registryv1alpha1.CuratedPlugin) error
 	PrintCuratedPlugins(ctx context.Context, format Format, nextPageToken string, plugins ...*registryv1alpha1.CuratedPlugin) error
+	// PrintCuratedPluginInfo prints the metadata of the curated plugin, such as its description,
+	// output languages and default options, along with the given versions of the plugin.
+	PrintCuratedPluginInfo(
+		ctx context.Context,
+		format Format,
+		remote string,
+		plugin *registryv1alpha1.CuratedPlugin,
+		versions ...*registryv1alpha1.CuratedPluginVersionRevisions,
+	) error
+	// PrintCuratedPluginSummaries prints a one-line summary of each curated plugin.
+	PrintCuratedPluginSummaries(ctx context.Context, format Format, remote string, plugins ...*registryv1alpha1.CuratedPlugin) error
 }
 
 // NewCuratedPluginPrinter returns a new CuratedPluginPrinter.
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
 	registryv1alpha1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/registry/v1alpha1"
 )
@@ -93,6 +94,181 @@ func (p *curatedPluginPrinter) printCuratedPluginsText(plugins ...*registryv1alp
 	)
 }
 
+func (p *curatedPluginPrinter) PrintCuratedPluginInfo(
+	_ context.Context,
+	format Format,
+	remote string,
+	plugin *registryv1alpha1.CuratedPlugin,
+	versions ...*registryv1alpha1.CuratedPluginVersionRevisions,
+) error {
+	outputPluginInfo := registryCuratedPluginToOutputCuratedPluginInfo(remote, plugin, versions)
+	switch format {
+	case FormatText:
+		return p.printCuratedPluginInfoText(outputPluginInfo)
+	case FormatJSON:
+		return json.NewEncoder(p.writer).Encode(outputPluginInfo)
+	default:
+		return fmt.Errorf("unknown format: %v", format)
+	}
+}
+
+func (p *curatedPluginPrinter) PrintCuratedPluginSummaries(
+	_ context.Context,
+	format Format,
+	remote string,
+	plugins ...*registryv1alpha1.CuratedPlugin,
+) error {
+	outputPluginSummaries := make([]outputCuratedPluginSummary, 0, len(plugins))
+	for _, plugin := range plugins {
+		outputPluginSummaries = append(outputPluginSummaries, registryCuratedPluginToOutputCuratedPluginSummary(remote, plugin))
+	}
+	switch format {
+	case FormatText:
+		if len(outputPluginSummaries) == 0 {
+			return nil
+		}
+		return WithTabWriter(
+			p.writer,
+			[]string{
+				"Name",
+				"Version",
+				"Languages",
+				"Description",
+			},
+			func(tabWriter TabWriter) error {
+				for _, outputPluginSummary := range outputPluginSummaries {
+					if err := tabWriter.Write(
+						outputPluginSummary.Name,
+						outputPluginSummary.Version,
+						strings.Join(outputPluginSummary.Languages, ", "),
+						outputPluginSummary.Description,
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		)
+	case FormatJSON:
+		return json.NewEncoder(p.writer).Encode(paginationWrapper{
+			Results: outputPluginSummaries,
+		})
+	default:
+		return fmt.Errorf("unknown format: %v", format)
+	}
+}
+
+func (p *curatedPluginPrinter) printCuratedPluginInfoText(outputPluginInfo outputCuratedPluginInfo) error {
+	versionStrings := make([]string, 0, len(outputPluginInfo.LatestVersions))
+	for _, outputVersion := range outputPluginInfo.LatestVersions {
+		versionStrings = append(versionStrings, outputVersion.Version)
+	}
+	tabWriter := newTabWriter(p.writer)
+	for _, row := range [][2]string{
+		{"Name", outputPluginInfo.Name},
+		{"Version", fmt.Sprintf("%s (revision %d)", outputPluginInfo.Version, outputPluginInfo.Revision)},
+		{"Description", outputPluginInfo.Description},
+		{"Languages", strings.Join(outputPluginInfo.Languages, ", ")},
+		{"Default Options", strings.Join(outputPluginInfo.DefaultOptions, ",")},
+		{"Collections", strings.Join(outputPluginInfo.Collections, ", ")},
+		{"License", outputPluginInfo.License},
+		{"Source", outputPluginInfo.SourceURL},
+		{"Integration Guide", outputPluginInfo.IntegrationGuideURL},
+		{"Deprecated", outputPluginInfo.DeprecationMessage},
+		{"Latest Versions", strings.Join(versionStrings, ", ")},
+	} {
+		if row[1] == "" {
+			continue
+		}
+		if err := tabWriter.Write(row[0]+":", row[1]); err != nil {
+			return err
+		}
+	}
+	return tabWriter.Flush()
+}
+
+type outputCuratedPluginSummary struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Revision    uint32   `json:"revision"`
+	Description string   `json:"description,omitempty"`
+	Languages   []string `json:"languages,omitempty"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+}
+
+type outputCuratedPluginInfo struct {
+	outputCuratedPluginSummary
+	DefaultOptions      []string                     `json:"default_options,omitempty"`
+	Collections         []string                     `json:"collections,omitempty"`
+	License             string                       `json:"license,omitempty"`
+	SourceURL           string                       `json:"source_url,omitempty"`
+	IntegrationGuideURL string                       `json:"integration_guide_url,omitempty"`
+	DeprecationMessage  string                       `json:"deprecation_message,omitempty"`
+	LatestVersions      []outputCuratedPluginVersion `json:"latest_versions,omitempty"`
+}
+
+type outputCuratedPluginVersion struct {
+	Version   string   `json:"version"`
+	Revisions []uint32 `json:"revisions,omitempty"`
+}
+
+func registryCuratedPluginToOutputCuratedPluginSummary(
+	remote string,
+	plugin *registryv1alpha1.CuratedPlugin,
+) outputCuratedPluginSummary {
+	languages := make([]string, 0, len(plugin.GetOutputLanguages()))
+	for _, outputLanguage := range plugin.GetOutputLanguages() {
+		languages = append(languages, CuratedPluginLanguageString(outputLanguage))
+	}
+	return outputCuratedPluginSummary{
+		Name:        remote + "/" + plugin.GetOwner() + "/" + plugin.GetName(),
+		Version:     plugin.GetVersion(),
+		Revision:    plugin.GetRevision(),
+		Description: plugin.GetDescription(),
+		Languages:   languages,
+		Deprecated:  plugin.GetDeprecated(),
+	}
+}
+
+func registryCuratedPluginToOutputCuratedPluginInfo(
+	remote string,
+	plugin *registryv1alpha1.CuratedPlugin,
+	versions []*registryv1alpha1.CuratedPluginVersionRevisions,
+) outputCuratedPluginInfo {
+	collections := make([]string, 0, len(plugin.GetCollections()))
+	for _, collection := range plugin.GetCollections() {
+		collections = append(collections, collection.GetName())
+	}
+	license := plugin.GetSpdxLicenseId()
+	if license == "" {
+		license = plugin.GetLicenseUrl()
+	}
+	deprecationMessage := plugin.GetDeprecationMessage()
+	if plugin.GetDeprecated() && deprecationMessage == "" {
+		deprecationMessage = "true"
+	}
+	outputVersions := make([]outputCuratedPluginVersion, 0, len(versions))
+	for _, version := range versions {
+		outputVersions = append(
+			outputVersions,
+			outputCuratedPluginVersion{
+				Version:   version.GetVersion(),
+				Revisions: version.GetRevisions(),
+			},
+		)
+	}
+	return outputCuratedPluginInfo{
+		outputCuratedPluginSummary: registryCuratedPluginToOutputCuratedPluginSummary(remote, plugin),
+		DefaultOptions:             plugin.GetRegistryConfig().GetOptions(),
+		Collections:                collections,
+		License:                    license,
+		SourceURL:                  plugin.GetSourceUrl(),
+		IntegrationGuideURL:        plugin.GetIntegrationGuideUrl(),
+		DeprecationMessage:         deprecationMessage,
+		LatestVersions:             outputVersions,
+	}
+}
+
 type outputCuratedPlugin struct {
 	Owner       string `json:"owner"`
 	Name        string `json:"name"`
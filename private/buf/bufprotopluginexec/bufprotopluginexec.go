@@ -24,10 +24,13 @@ import (
 	"fmt"
 	"log/slog"
 	"os/exec"
+	"path/filepath"
 
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
 	"github.com/bufbuild/buf/private/pkg/app"
+	"github.com/bufbuild/buf/private/pkg/pluginrpcutil"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
+	"github.com/bufbuild/buf/private/pkg/wasm"
 	"github.com/bufbuild/protoplugin"
 	"google.golang.org/protobuf/types/pluginpb"
 )
@@ -100,11 +103,21 @@ func GenerateWithProtocPath(protocPath ...string) GenerateOption {
 	}
 }
 
+// GenerateWithWasmRuntime returns a new GenerateOption that uses the given Wasm
+// runtime to run plugins with a plugin path that ends in .wasm.
+func GenerateWithWasmRuntime(wasmRuntime wasm.Runtime) GenerateOption {
+	return func(generateOptions *generateOptions) {
+		generateOptions.wasmRuntime = wasmRuntime
+	}
+}
+
 // NewHandler returns a new Handler based on the plugin name and optional path.
 //
 // protocPath and pluginPath are optional.
 //
-//   - If the plugin path is set, this returns a new binary handler for that path.
+//   - If the plugin path is set and ends in .wasm, this returns a new Wasm handler for that path,
+//     which runs the plugin with the Wasm runtime set with HandlerWithWasmRuntime.
+//   - If the plugin path is set otherwise, this returns a new binary handler for that path.
 //   - If the plugin path is unset, this does exec.LookPath for a binary named protoc-gen-pluginName,
 //     and if one is found, a new binary handler is returned for this.
 //   - Else, if the name is in ProtocProxyPluginNames, this returns a new protoc proxy handler.
@@ -120,6 +133,22 @@ func NewHandler(
 		option(handlerOptions)
 	}
 
+	// Initialize Wasm plugin handler when path is specified and is a Wasm file.
+	if len(handlerOptions.pluginPath) > 0 && filepath.Ext(handlerOptions.pluginPath[0]) == ".wasm" {
+		if handlerOptions.wasmRuntime == nil {
+			return nil, fmt.Errorf("plugin %s is a Wasm plugin, but Wasm plugins are not supported here", handlerOptions.pluginPath[0])
+		}
+		return newWasmHandler(
+			logger,
+			handlerOptions.pluginPath[0],
+			pluginrpcutil.NewLocalWasmRunner(
+				handlerOptions.wasmRuntime,
+				handlerOptions.pluginPath[0],
+				handlerOptions.pluginPath[1:]...,
+			),
+		), nil
+	}
+
 	// Initialize binary plugin handler when path is specified with optional args. Return
 	// on error as something is wrong with the supplied pluginPath option.
 	if len(handlerOptions.pluginPath) > 0 {
@@ -175,6 +204,16 @@ func HandlerWithPluginPath(pluginPath ...string) HandlerOption {
 	}
 }
 
+// HandlerWithWasmRuntime returns a new HandlerOption that sets the Wasm runtime
+// to run plugins with a plugin path that ends in .wasm.
+//
+// The default is to not support Wasm plugins.
+func HandlerWithWasmRuntime(wasmRuntime wasm.Runtime) HandlerOption {
+	return func(handlerOptions *handlerOptions) {
+		handlerOptions.wasmRuntime = wasmRuntime
+	}
+}
+
 // NewBinaryHandler returns a new Handler that invokes the specific plugin
 // specified by pluginPath.
 func NewBinaryHandler(logger *slog.Logger, pluginPath string, pluginArgs []string) (protoplugin.Handler, error) {
@@ -186,8 +225,9 @@ func NewBinaryHandler(logger *slog.Logger, pluginPath string, pluginArgs []strin
 }
 
 type handlerOptions struct {
-	pluginPath []string
-	protocPath []string
+	pluginPath  []string
+	protocPath  []string
+	wasmRuntime wasm.Runtime
 }
 
 func newHandlerOptions() *handlerOptions {
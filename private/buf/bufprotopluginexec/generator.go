@@ -21,6 +21,7 @@ import (
 	"github.com/bufbuild/buf/private/bufpkg/bufprotoplugin"
 	"github.com/bufbuild/buf/private/pkg/app"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
+	"github.com/bufbuild/buf/private/pkg/wasm"
 	"google.golang.org/protobuf/types/pluginpb"
 )
 
@@ -53,6 +54,7 @@ func (g *generator) Generate(
 	handlerOptions := []HandlerOption{
 		HandlerWithPluginPath(generateOptions.pluginPath...),
 		HandlerWithProtocPath(generateOptions.protocPath...),
+		HandlerWithWasmRuntime(generateOptions.wasmRuntime),
 	}
 	handler, err := NewHandler(
 		g.logger,
@@ -74,8 +76,9 @@ func (g *generator) Generate(
 }
 
 type generateOptions struct {
-	pluginPath []string
-	protocPath []string
+	pluginPath  []string
+	protocPath  []string
+	wasmRuntime wasm.Runtime
 }
 
 func newGenerateOptions() *generateOptions {
@@ -0,0 +1,83 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufprotopluginexec
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/bufbuild/buf/private/pkg/slogext"
+	"github.com/bufbuild/protoplugin"
+	"google.golang.org/protobuf/types/pluginpb"
+	"pluginrpc.com/pluginrpc"
+)
+
+type wasmHandler struct {
+	logger     *slog.Logger
+	pluginPath string
+	runner     pluginrpc.Runner
+}
+
+func newWasmHandler(
+	logger *slog.Logger,
+	pluginPath string,
+	runner pluginrpc.Runner,
+) *wasmHandler {
+	return &wasmHandler{
+		logger:     logger,
+		pluginPath: pluginPath,
+		runner:     runner,
+	}
+}
+
+func (h *wasmHandler) Handle(
+	ctx context.Context,
+	pluginEnv protoplugin.PluginEnv,
+	responseWriter protoplugin.ResponseWriter,
+	request protoplugin.Request,
+) error {
+	defer slogext.DebugProfile(h.logger, slog.String("plugin", filepath.Base(h.pluginPath)))()
+
+	requestData, err := protoencoding.NewWireMarshaler().Marshal(request.CodeGeneratorRequest())
+	if err != nil {
+		return err
+	}
+	responseBuffer := bytes.NewBuffer(nil)
+	// The environment of the plugin is not passed to Wasm plugins, which are
+	// sandboxed and only have access to stdin, stdout, and stderr.
+	if err := h.runner.Run(
+		ctx,
+		pluginrpc.Env{
+			Stdin:  bytes.NewReader(requestData),
+			Stdout: responseBuffer,
+			Stderr: pluginEnv.Stderr,
+		},
+	); err != nil {
+		return err
+	}
+	response := &pluginpb.CodeGeneratorResponse{}
+	if err := protoencoding.NewWireUnmarshaler(nil).Unmarshal(responseBuffer.Bytes(), response); err != nil {
+		return err
+	}
+	responseWriter.AddCodeGeneratorResponseFiles(response.GetFile()...)
+	responseWriter.AddError(response.GetError())
+	responseWriter.SetSupportedFeatures(response.GetSupportedFeatures())
+	responseWriter.SetMinimumEdition(response.GetMinimumEdition())
+	responseWriter.SetMaximumEdition(response.GetMaximumEdition())
+	return nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufreduce minimizes inputs while preserving a failure.
+//
+// Inputs are read into memory, and subsets of them are written out to temporary
+// directories to check if they still fail, using a simplified delta debugging algorithm.
+package bufreduce
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+)
+
+const (
+	// MaxInputFiles is the maximum number of files of an input that are read to be reduced.
+	MaxInputFiles = 10000
+	// MaxInputSize is the maximum total size in bytes of the files of an input that are
+	// read to be reduced.
+	MaxInputSize = 64 << 20
+)
+
+var (
+	// ErrInputTooLarge is returned by ReadInputDir if the input has more than MaxInputFiles
+	// files, or if the total size of the files is more than MaxInputSize.
+	ErrInputTooLarge = errors.New("input is too large to reduce")
+)
+
+// ReadInputDir reads all the files of the directory into memory, keyed by their
+// normalized paths relative to the directory.
+//
+// Hidden directories, such as .git, are skipped.
+func ReadInputDir(dirPath string) (map[string][]byte, error) {
+	pathToData := make(map[string][]byte)
+	var size int64
+	if err := filepath.WalkDir(dirPath, func(path string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if dirEntry.IsDir() {
+			if path != dirPath && strings.HasPrefix(dirEntry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !dirEntry.Type().IsRegular() {
+			return nil
+		}
+		fileInfo, err := dirEntry.Info()
+		if err != nil {
+			return err
+		}
+		size += fileInfo.Size()
+		if len(pathToData) >= MaxInputFiles || size > MaxInputSize {
+			return ErrInputTooLarge
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dirPath, path)
+		if err != nil {
+			return err
+		}
+		pathToData[normalpath.Normalize(relPath)] = data
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return pathToData, nil
+}
+
+// WriteInputDir writes the files to the directory, which is created if it does not exist.
+func WriteInputDir(dirPath string, pathToData map[string][]byte) error {
+	for path, data := range pathToData {
+		filePath := filepath.Join(dirPath, normalpath.Unnormalize(path))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filePath, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReduceProtoFiles returns a minimal subset of the files for which fails still returns true.
+//
+// Only .proto files are removed, all other files are always kept. The reduction removes
+// decreasing chunks of .proto files for as long as fails returns true, and stops early
+// if the context is done. The returned files are therefore not guaranteed to be the
+// smallest possible set, but no single .proto file can be removed from them.
+//
+// fails should return true for pathToData.
+func ReduceProtoFiles(
+	ctx context.Context,
+	pathToData map[string][]byte,
+	fails func(ctx context.Context, pathToData map[string][]byte) bool,
+) map[string][]byte {
+	var protoFilePaths []string
+	for path := range pathToData {
+		if normalpath.Ext(path) == ".proto" {
+			protoFilePaths = append(protoFilePaths, path)
+		}
+	}
+	slices.Sort(protoFilePaths)
+	keptProtoFilePaths := reduce(
+		ctx,
+		protoFilePaths,
+		func(ctx context.Context, protoFilePaths []string) bool {
+			return fails(ctx, filterProtoFiles(pathToData, protoFilePaths))
+		},
+	)
+	return filterProtoFiles(pathToData, keptProtoFilePaths)
+}
+
+// ReduceProtoFileLines returns the files with the lines of each .proto file reduced to
+// a minimal subset for which fails still returns true.
+//
+// The .proto files are reduced one at a time, in the same manner as ReduceProtoFiles,
+// and the reduction stops early if the context is done.
+//
+// fails should return true for pathToData.
+func ReduceProtoFileLines(
+	ctx context.Context,
+	pathToData map[string][]byte,
+	fails func(ctx context.Context, pathToData map[string][]byte) bool,
+) map[string][]byte {
+	pathToData = maps.Clone(pathToData)
+	for _, path := range slicesext.MapKeysToSortedSlice(pathToData) {
+		if normalpath.Ext(path) != ".proto" {
+			continue
+		}
+		lines := bytes.SplitAfter(pathToData[path], []byte("\n"))
+		keptLines := reduce(
+			ctx,
+			lines,
+			func(ctx context.Context, lines [][]byte) bool {
+				candidate := maps.Clone(pathToData)
+				candidate[path] = bytes.Join(lines, nil)
+				return fails(ctx, candidate)
+			},
+		)
+		pathToData[path] = bytes.Join(keptLines, nil)
+	}
+	return pathToData
+}
+
+// NumLines returns the total number of lines of the .proto files.
+func NumLines(pathToData map[string][]byte) int {
+	var numLines int
+	for path, data := range pathToData {
+		if normalpath.Ext(path) == ".proto" {
+			numLines += len(bytes.SplitAfter(data, []byte("\n")))
+			if bytes.HasSuffix(data, []byte("\n")) {
+				numLines--
+			}
+		}
+	}
+	return numLines
+}
+
+// *** PRIVATE ***
+
+// reduce is a simplified delta debugging algorithm.
+//
+// It tries to remove chunks of the elements, starting with halves and ending with
+// single elements, and keeps each removal for which fails still returns true.
+func reduce[T any](
+	ctx context.Context,
+	elements []T,
+	fails func(ctx context.Context, elements []T) bool,
+) []T {
+	for chunkSize := max(len(elements)/2, 1); len(elements) > 0; chunkSize /= 2 {
+		for start := 0; start < len(elements); {
+			if ctx.Err() != nil {
+				return elements
+			}
+			end := min(start+chunkSize, len(elements))
+			candidate := slices.Concat(elements[:start], elements[end:])
+			if fails(ctx, candidate) {
+				elements = candidate
+			} else {
+				start = end
+			}
+		}
+		if chunkSize == 1 {
+			break
+		}
+	}
+	return elements
+}
+
+func filterProtoFiles(pathToData map[string][]byte, protoFilePaths []string) map[string][]byte {
+	filtered := make(map[string][]byte)
+	for path, data := range pathToData {
+		if normalpath.Ext(path) != ".proto" || slices.Contains(protoFilePaths, path) {
+			filtered[path] = data
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,125 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufreduce
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReduce(t *testing.T) {
+	t.Parallel()
+	elements := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	// Fails if both 3 and 7 are present.
+	reduced := reduce(
+		context.Background(),
+		elements,
+		func(_ context.Context, elements []int) bool {
+			return slices.Contains(elements, 3) && slices.Contains(elements, 7)
+		},
+	)
+	assert.Equal(t, []int{3, 7}, reduced)
+	// Fails regardless of the elements.
+	reduced = reduce(
+		context.Background(),
+		elements,
+		func(context.Context, []int) bool {
+			return true
+		},
+	)
+	assert.Empty(t, reduced)
+	// The reduction stops once the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	reduced = reduce(
+		ctx,
+		elements,
+		func(context.Context, []int) bool {
+			return true
+		},
+	)
+	assert.Equal(t, elements, reduced)
+}
+
+func TestReduceProtoFiles(t *testing.T) {
+	t.Parallel()
+	inputDirPath := t.TempDir()
+	require.NoError(
+		t,
+		WriteInputDir(
+			inputDirPath,
+			map[string][]byte{
+				"buf.yaml":          []byte("version: v2\n"),
+				"README.md":         []byte("# README\n"),
+				"a/v1/a.proto":      []byte(`syntax = "proto3";`),
+				"b/v1/b.proto":      []byte(`syntax = "proto3"; // fail`),
+				"c/v1/c.proto":      []byte(`syntax = "proto3";`),
+				".git/HEAD":         []byte("ref: refs/heads/main\n"),
+				"d/v1/d_test.proto": []byte(`syntax = "proto3";`),
+			},
+		),
+	)
+	pathToData, err := ReadInputDir(inputDirPath)
+	require.NoError(t, err)
+	assert.Len(t, pathToData, 6)
+	assert.NotContains(t, pathToData, ".git/HEAD")
+	reducedPathToData := ReduceProtoFiles(
+		context.Background(),
+		pathToData,
+		func(_ context.Context, pathToData map[string][]byte) bool {
+			for _, data := range pathToData {
+				if strings.Contains(string(data), "fail") {
+					return true
+				}
+			}
+			return false
+		},
+	)
+	assert.ElementsMatch(t, []string{"buf.yaml", "README.md", "b/v1/b.proto"}, slicesext.MapKeysToSlice(reducedPathToData))
+}
+
+func TestReduceProtoFileLines(t *testing.T) {
+	t.Parallel()
+	pathToData := map[string][]byte{
+		"buf.yaml": []byte("version: v2\nlint:\n  use:\n    - STANDARD\n"),
+		"a.proto": []byte(`syntax = "proto3";
+package a;
+message Foo {
+  string one = 1;
+  string two = 2;
+}
+message bar {
+  string three = 3;
+}
+`),
+	}
+	// Fails as long as a lowercase message is declared within package a.
+	fails := func(_ context.Context, pathToData map[string][]byte) bool {
+		data := pathToData["a.proto"]
+		return bytes.Contains(data, []byte("package a;\n")) && bytes.Contains(data, []byte("message bar {\n"))
+	}
+	reducedPathToData := ReduceProtoFileLines(context.Background(), pathToData, fails)
+	assert.Equal(t, "package a;\nmessage bar {\n", string(reducedPathToData["a.proto"]))
+	assert.Equal(t, pathToData["buf.yaml"], reducedPathToData["buf.yaml"])
+	assert.Equal(t, 9, NumLines(pathToData))
+	assert.Equal(t, 2, NumLines(reducedPathToData))
+}
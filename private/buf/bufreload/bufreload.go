@@ -0,0 +1,89 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufreload reloads configuration files for long-running processes, such as
+// the language server, without requiring a restart.
+package bufreload
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/bufbuild/buf/private/pkg/app"
+)
+
+// IsConfigFilePath returns true if the path is the path of a configuration file
+// that can be reloaded.
+//
+// These are buf.yaml, buf.work.yaml, buf.gen.yaml, and buf.lock files.
+func IsConfigFilePath(path string) bool {
+	_, ok := fileNameToValidateFunc[filepath.Base(path)]
+	return ok
+}
+
+// ConfigFileNames returns the names of the configuration files that can be reloaded.
+//
+// The returned slice is sorted.
+func ConfigFileNames() []string {
+	return sortedConfigFileNames()
+}
+
+// Change is a change to a configuration file that was applied.
+type Change struct {
+	// Path is the path of the configuration file.
+	Path string
+	// Deleted is true if the configuration file was deleted.
+	Deleted bool
+	// ChangedKeys are the top-level keys of the configuration file whose values were
+	// added, changed, or removed, sorted.
+	//
+	// This is empty if the values of the file did not change, for example if only
+	// comments were edited.
+	ChangedKeys []string
+}
+
+// Reloader reloads configuration files.
+//
+// A Reloader records the last valid content of each configuration file it has
+// seen, so that it can describe what changed. Changes are validated before they
+// are applied, so that an invalid edit does not replace the last valid configuration.
+//
+// Reloaders are safe to use concurrently.
+type Reloader interface {
+	// Track records the current content of the configuration file at the path,
+	// if the content of the path has not already been recorded.
+	//
+	// Missing files are recorded as empty, so that creating the file later is
+	// reported as adding all of its keys. Track does not validate the file.
+	Track(ctx context.Context, path string) error
+	// Reload reads and validates the configuration file at the path.
+	//
+	// If the file is valid or was deleted, its new content is recorded, an event
+	// describing the Change is logged, and the Change is returned. If the content
+	// of the path was not previously recorded, all of its top-level keys are
+	// considered changed.
+	//
+	// If the file is invalid, an error is returned and the previously recorded
+	// content is kept.
+	Reload(ctx context.Context, path string) (*Change, error)
+}
+
+// NewReloader returns a new Reloader.
+//
+// The EnvContainer is used to interpolate environment variables in buf.gen.yaml files that
+// set "interpolate_env: true".
+func NewReloader(logger *slog.Logger, envContainer app.EnvContainer) Reloader {
+	return newReloader(logger, envContainer)
+}
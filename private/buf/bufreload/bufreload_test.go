@@ -0,0 +1,127 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufreload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufbuild/buf/private/pkg/app"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReload(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	reloader := NewReloader(slogtestext.NewLogger(t), app.NewEnvContainer(nil))
+	bufYAMLPath := filepath.Join(t.TempDir(), "buf.yaml")
+
+	// The file does not exist yet, creating it adds all of its keys.
+	require.NoError(t, reloader.Track(ctx, bufYAMLPath))
+	writeFile(t, bufYAMLPath, `version: v2
+lint:
+  use:
+    - STANDARD
+`)
+	change, err := reloader.Reload(ctx, bufYAMLPath)
+	require.NoError(t, err)
+	require.Equal(t, &Change{Path: bufYAMLPath, ChangedKeys: []string{"lint", "version"}}, change)
+
+	// Tracking again does not replace the recorded content.
+	require.NoError(t, reloader.Track(ctx, bufYAMLPath))
+	writeFile(t, bufYAMLPath, `version: v2
+# A comment.
+lint:
+  use:
+    - STANDARD
+breaking:
+  use:
+    - FILE
+`)
+	change, err = reloader.Reload(ctx, bufYAMLPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"breaking"}, change.ChangedKeys)
+
+	// Invalid changes are not applied.
+	writeFile(t, bufYAMLPath, `version: v2
+lint:
+  use:
+    - STANDARD
+breaking:
+  use: FILE
+  unknown: true
+`)
+	_, err = reloader.Reload(ctx, bufYAMLPath)
+	require.Error(t, err)
+	writeFile(t, bufYAMLPath, `version: v2
+lint:
+  use:
+    - MINIMAL
+breaking:
+  use:
+    - FILE
+`)
+	change, err = reloader.Reload(ctx, bufYAMLPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"lint"}, change.ChangedKeys)
+
+	require.NoError(t, os.Remove(bufYAMLPath))
+	change, err = reloader.Reload(ctx, bufYAMLPath)
+	require.NoError(t, err)
+	require.Equal(t, &Change{Path: bufYAMLPath, Deleted: true, ChangedKeys: []string{"breaking", "lint", "version"}}, change)
+}
+
+func TestReloadBufGenYAML(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	reloader := NewReloader(
+		slogtestext.NewLogger(t),
+		app.NewEnvContainer(map[string]string{"OUT": "gen"}),
+	)
+	bufGenYAMLPath := filepath.Join(t.TempDir(), "buf.gen.yaml")
+	writeFile(t, bufGenYAMLPath, `version: v2
+interpolate_env: true
+plugins:
+  - local: protoc-gen-go
+    out: ${OUT}
+`)
+	change, err := reloader.Reload(ctx, bufGenYAMLPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"interpolate_env", "plugins", "version"}, change.ChangedKeys)
+	writeFile(t, bufGenYAMLPath, `version: v2
+interpolate_env: true
+plugins:
+  - local: protoc-gen-go
+    out: ${MISSING:?must be set}
+`)
+	_, err = reloader.Reload(ctx, bufGenYAMLPath)
+	require.ErrorContains(t, err, "must be set")
+}
+
+func TestIsConfigFilePath(t *testing.T) {
+	t.Parallel()
+	require.True(t, IsConfigFilePath(filepath.Join("a", "buf.yaml")))
+	require.True(t, IsConfigFilePath("buf.gen.yaml"))
+	require.True(t, IsConfigFilePath("buf.work.yaml"))
+	require.True(t, IsConfigFilePath("buf.lock"))
+	require.False(t, IsConfigFilePath("a.proto"))
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
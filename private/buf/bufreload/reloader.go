@@ -0,0 +1,196 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufreload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/pkg/app"
+	"github.com/bufbuild/buf/private/pkg/encoding"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/slogext"
+)
+
+var fileNameToValidateFunc = map[string]func(ctx context.Context, envContainer app.EnvContainer, path string, data []byte) error{
+	bufconfig.DefaultBufYAMLFileName: func(_ context.Context, _ app.EnvContainer, path string, data []byte) error {
+		_, err := bufconfig.ReadBufYAMLFile(
+			bytes.NewReader(data),
+			filepath.Base(path),
+			bufconfig.BufYAMLFileWithExtendsReadFunc(
+				normalpath.Normalize(filepath.Dir(path)),
+				func(path string) ([]byte, error) {
+					return os.ReadFile(normalpath.Unnormalize(path))
+				},
+			),
+		)
+		return err
+	},
+	bufconfig.DefaultBufWorkYAMLFileName: func(_ context.Context, _ app.EnvContainer, path string, data []byte) error {
+		_, err := bufconfig.ReadBufWorkYAMLFile(bytes.NewReader(data), filepath.Base(path))
+		return err
+	},
+	"buf.gen.yaml": func(_ context.Context, envContainer app.EnvContainer, path string, data []byte) error {
+		_, err := bufconfig.ReadBufGenYAMLFile(
+			bytes.NewReader(data),
+			bufconfig.BufGenYAMLFileWithExtendsReadFunc(
+				normalpath.Normalize(filepath.Dir(path)),
+				func(path string) ([]byte, error) {
+					return os.ReadFile(normalpath.Unnormalize(path))
+				},
+			),
+			bufconfig.BufGenYAMLFileWithEnvFunc(envContainer.Env),
+		)
+		return err
+	},
+	bufconfig.DefaultBufLockFileName: func(ctx context.Context, _ app.EnvContainer, path string, data []byte) error {
+		_, err := bufconfig.ReadBufLockFile(ctx, bytes.NewReader(data), filepath.Base(path))
+		return err
+	},
+}
+
+type reloader struct {
+	logger       *slog.Logger
+	envContainer app.EnvContainer
+
+	lock sync.Mutex
+	// pathToKeyToValue records the top-level keys and values of the last valid
+	// content of each path. A nil map means the file does not exist.
+	pathToKeyToValue map[string]map[string]any
+}
+
+func newReloader(logger *slog.Logger, envContainer app.EnvContainer) *reloader {
+	return &reloader{
+		logger:           logger,
+		envContainer:     envContainer,
+		pathToKeyToValue: make(map[string]map[string]any),
+	}
+}
+
+func (r *reloader) Track(ctx context.Context, path string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.pathToKeyToValue[path]; ok {
+		return nil
+	}
+	keyToValue, _, err := readKeyToValue(path)
+	if err != nil {
+		return err
+	}
+	r.pathToKeyToValue[path] = keyToValue
+	return nil
+}
+
+func (r *reloader) Reload(ctx context.Context, path string) (*Change, error) {
+	validateFunc, ok := fileNameToValidateFunc[filepath.Base(path)]
+	if !ok {
+		return nil, fmt.Errorf("%q is not a configuration file", path)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	keyToValue, data, err := readKeyToValue(path)
+	if err != nil {
+		r.logConfigInvalid(ctx, path, err)
+		return nil, err
+	}
+	if data != nil {
+		if err := validateFunc(ctx, r.envContainer, path, data); err != nil {
+			r.logConfigInvalid(ctx, path, err)
+			return nil, err
+		}
+	}
+	change := &Change{
+		Path:        path,
+		Deleted:     data == nil,
+		ChangedKeys: getChangedKeys(r.pathToKeyToValue[path], keyToValue),
+	}
+	r.pathToKeyToValue[path] = keyToValue
+	r.logger.InfoContext(
+		ctx,
+		"configuration reloaded",
+		slog.String("path", change.Path),
+		slog.Bool("deleted", change.Deleted),
+		slog.Any("changed_keys", change.ChangedKeys),
+	)
+	return change, nil
+}
+
+func (r *reloader) logConfigInvalid(ctx context.Context, path string, err error) {
+	r.logger.WarnContext(
+		ctx,
+		"configuration is invalid, not reloading",
+		slog.String("path", path),
+		slogext.ErrorAttr(err),
+	)
+}
+
+// readKeyToValue reads the top-level keys and values of the file at the path.
+//
+// Returns a nil map and nil data if the file does not exist. The returned map is
+// non-nil if the file exists.
+func readKeyToValue(path string) (map[string]any, []byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	keyToValue := make(map[string]any)
+	if err := encoding.UnmarshalYAMLNonStrict(data, &keyToValue); err != nil {
+		return nil, nil, err
+	}
+	if keyToValue == nil {
+		// An empty file unmarshals to a nil map.
+		keyToValue = make(map[string]any)
+	}
+	return keyToValue, data, nil
+}
+
+func getChangedKeys(oldKeyToValue map[string]any, newKeyToValue map[string]any) []string {
+	var changedKeys []string
+	for key, oldValue := range oldKeyToValue {
+		newValue, ok := newKeyToValue[key]
+		if !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	for key := range newKeyToValue {
+		if _, ok := oldKeyToValue[key]; !ok {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	sort.Strings(changedKeys)
+	return changedKeys
+}
+
+func sortedConfigFileNames() []string {
+	fileNames := make([]string, 0, len(fileNameToValidateFunc))
+	for fileName := range fileNameToValidateFunc {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	return fileNames
+}
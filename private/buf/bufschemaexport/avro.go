@@ -0,0 +1,269 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufschemaexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const timestampFullName = "google.protobuf.Timestamp"
+
+type avroRecord struct {
+	Type      string       `json:"type"`
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace,omitempty"`
+	Doc       string       `json:"doc,omitempty"`
+	Fields    []*avroField `json:"fields"`
+}
+
+type avroField struct {
+	Name    string          `json:"name"`
+	Doc     string          `json:"doc,omitempty"`
+	Type    any             `json:"type"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+type avroEnum struct {
+	Type      string   `json:"type"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Doc       string   `json:"doc,omitempty"`
+	Symbols   []string `json:"symbols"`
+	Default   string   `json:"default"`
+}
+
+type avroArray struct {
+	Type  string `json:"type"`
+	Items any    `json:"items"`
+}
+
+type avroMap struct {
+	Type   string `json:"type"`
+	Values any    `json:"values"`
+}
+
+type avroLogicalType struct {
+	Type        string `json:"type"`
+	LogicalType string `json:"logicalType"`
+}
+
+// avroExporter exports Avro schemas. Each exporter is used for a single schema,
+// as named types are only defined the first time they are referenced.
+type avroExporter struct {
+	definedNames map[protoreflect.FullName]struct{}
+}
+
+func exportAvroSchema(messageDescriptor protoreflect.MessageDescriptor) ([]byte, error) {
+	exporter := &avroExporter{
+		definedNames: make(map[protoreflect.FullName]struct{}),
+	}
+	schema, err := exporter.messageSchema(messageDescriptor)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// messageSchema returns the schema of a message, without the null union
+// of fields with explicit presence.
+func (e *avroExporter) messageSchema(messageDescriptor protoreflect.MessageDescriptor) (any, error) {
+	switch fullName := messageDescriptor.FullName(); {
+	case fullName == timestampFullName:
+		return &avroLogicalType{Type: "long", LogicalType: "timestamp-micros"}, nil
+	case isWrapper(messageDescriptor):
+		return e.singularFieldSchema(messageDescriptor.Fields().ByName("value"))
+	}
+	if _, ok := e.definedNames[messageDescriptor.FullName()]; ok {
+		return string(messageDescriptor.FullName()), nil
+	}
+	// Defined before the fields are added, so that recursive references refer to it.
+	e.definedNames[messageDescriptor.FullName()] = struct{}{}
+	record := &avroRecord{
+		Type:      "record",
+		Name:      string(messageDescriptor.Name()),
+		Namespace: string(messageDescriptor.Parent().FullName()),
+		Doc:       getDoc(messageDescriptor),
+		Fields:    []*avroField{},
+	}
+	for _, fieldDescriptor := range getFieldsByNumber(messageDescriptor) {
+		field, err := e.field(fieldDescriptor)
+		if err != nil {
+			return nil, err
+		}
+		record.Fields = append(record.Fields, field)
+	}
+	return record, nil
+}
+
+func (e *avroExporter) field(fieldDescriptor protoreflect.FieldDescriptor) (*avroField, error) {
+	field := &avroField{
+		Name: string(fieldDescriptor.Name()),
+		Doc:  getDoc(fieldDescriptor),
+	}
+	switch {
+	case fieldDescriptor.IsMap():
+		valueSchema, err := e.singularFieldSchema(fieldDescriptor.MapValue())
+		if err != nil {
+			return nil, err
+		}
+		field.Type = &avroMap{Type: "map", Values: valueSchema}
+		field.Default = json.RawMessage(`{}`)
+	case fieldDescriptor.IsList():
+		itemSchema, err := e.singularFieldSchema(fieldDescriptor)
+		if err != nil {
+			return nil, err
+		}
+		field.Type = &avroArray{Type: "array", Items: itemSchema}
+		field.Default = json.RawMessage(`[]`)
+	case fieldDescriptor.Cardinality() == protoreflect.Required:
+		schema, err := e.singularFieldSchema(fieldDescriptor)
+		if err != nil {
+			return nil, err
+		}
+		field.Type = schema
+	case fieldDescriptor.HasPresence():
+		schema, err := e.singularFieldSchema(fieldDescriptor)
+		if err != nil {
+			return nil, err
+		}
+		field.Type = []any{"null", schema}
+		field.Default = json.RawMessage(`null`)
+	default:
+		schema, err := e.singularFieldSchema(fieldDescriptor)
+		if err != nil {
+			return nil, err
+		}
+		defaultValue, err := avroDefault(fieldDescriptor)
+		if err != nil {
+			return nil, err
+		}
+		field.Type = schema
+		field.Default = defaultValue
+	}
+	return field, nil
+}
+
+// singularFieldSchema returns the schema of a single value of the field.
+func (e *avroExporter) singularFieldSchema(fieldDescriptor protoreflect.FieldDescriptor) (any, error) {
+	switch fieldDescriptor.Kind() {
+	case protoreflect.BoolKind:
+		return "boolean", nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "int", nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "long", nil
+	case protoreflect.FloatKind:
+		return "float", nil
+	case protoreflect.DoubleKind:
+		return "double", nil
+	case protoreflect.StringKind:
+		return "string", nil
+	case protoreflect.BytesKind:
+		return "bytes", nil
+	case protoreflect.EnumKind:
+		return e.enumSchema(fieldDescriptor.Enum()), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return e.messageSchema(fieldDescriptor.Message())
+	default:
+		return nil, fmt.Errorf("%s: unknown kind %v", fieldDescriptor.FullName(), fieldDescriptor.Kind())
+	}
+}
+
+func (e *avroExporter) enumSchema(enumDescriptor protoreflect.EnumDescriptor) any {
+	if _, ok := e.definedNames[enumDescriptor.FullName()]; ok {
+		return string(enumDescriptor.FullName())
+	}
+	e.definedNames[enumDescriptor.FullName()] = struct{}{}
+	enumValueDescriptors := enumDescriptor.Values()
+	symbols := make([]string, enumValueDescriptors.Len())
+	for i := range enumValueDescriptors.Len() {
+		symbols[i] = string(enumValueDescriptors.Get(i).Name())
+	}
+	return &avroEnum{
+		Type:      "enum",
+		Name:      string(enumDescriptor.Name()),
+		Namespace: string(enumDescriptor.Parent().FullName()),
+		Doc:       getDoc(enumDescriptor),
+		Symbols:   symbols,
+		// The first value is the default value of proto3 enums, and of proto2
+		// enum fields without an explicit default.
+		Default: symbols[0],
+	}
+}
+
+// avroDefault returns the default of a singular field with implicit presence.
+func avroDefault(fieldDescriptor protoreflect.FieldDescriptor) (json.RawMessage, error) {
+	var value any
+	switch fieldDescriptor.Kind() {
+	case protoreflect.EnumKind:
+		enumValueDescriptor := fieldDescriptor.DefaultEnumValue()
+		if enumValueDescriptor == nil {
+			enumValueDescriptor = fieldDescriptor.Enum().Values().Get(0)
+		}
+		value = string(enumValueDescriptor.Name())
+	case protoreflect.BytesKind:
+		// Avro bytes defaults are strings with a code point for each byte.
+		bytesValue := fieldDescriptor.Default().Bytes()
+		runes := make([]rune, len(bytesValue))
+		for i, b := range bytesValue {
+			runes[i] = rune(b)
+		}
+		value = string(runes)
+	default:
+		value = fieldDescriptor.Default().Interface()
+	}
+	return json.Marshal(value)
+}
+
+// getFieldsByNumber returns the fields of the message ordered by field number.
+func getFieldsByNumber(messageDescriptor protoreflect.MessageDescriptor) []protoreflect.FieldDescriptor {
+	fieldDescriptors := messageDescriptor.Fields()
+	sortedFieldDescriptors := make([]protoreflect.FieldDescriptor, fieldDescriptors.Len())
+	for i := range fieldDescriptors.Len() {
+		sortedFieldDescriptors[i] = fieldDescriptors.Get(i)
+	}
+	slices.SortFunc(
+		sortedFieldDescriptors,
+		func(a protoreflect.FieldDescriptor, b protoreflect.FieldDescriptor) int {
+			return int(a.Number()) - int(b.Number())
+		},
+	)
+	return sortedFieldDescriptors
+}
+
+// isWrapper returns true if the message is one of the wrapper well-known types,
+// such as google.protobuf.StringValue.
+func isWrapper(messageDescriptor protoreflect.MessageDescriptor) bool {
+	switch messageDescriptor.FullName() {
+	case "google.protobuf.DoubleValue",
+		"google.protobuf.FloatValue",
+		"google.protobuf.Int64Value",
+		"google.protobuf.UInt64Value",
+		"google.protobuf.Int32Value",
+		"google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue",
+		"google.protobuf.StringValue",
+		"google.protobuf.BytesValue":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufschemaexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// bigQueryMaxRecordDepth is the maximum depth of nested RECORD columns in BigQuery.
+const bigQueryMaxRecordDepth = 15
+
+type bigQueryField struct {
+	Name        string           `json:"name"`
+	Type        string           `json:"type"`
+	Mode        string           `json:"mode"`
+	Description string           `json:"description,omitempty"`
+	Fields      []*bigQueryField `json:"fields,omitempty"`
+}
+
+func exportBigQuerySchema(messageDescriptor protoreflect.MessageDescriptor) ([]byte, error) {
+	fields, err := getBigQueryFields(messageDescriptor, []protoreflect.FullName{messageDescriptor.FullName()})
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(fields, "", "  ")
+}
+
+// getBigQueryFields returns the fields of the message. The path is the full names of
+// the message and the messages it is nested in, to detect recursion and enforce the
+// maximum depth of RECORD columns.
+func getBigQueryFields(messageDescriptor protoreflect.MessageDescriptor, path []protoreflect.FullName) ([]*bigQueryField, error) {
+	var fields []*bigQueryField
+	for _, fieldDescriptor := range getFieldsByNumber(messageDescriptor) {
+		field, err := getBigQueryField(fieldDescriptor, path)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func getBigQueryField(fieldDescriptor protoreflect.FieldDescriptor, path []protoreflect.FullName) (*bigQueryField, error) {
+	field := &bigQueryField{
+		Name:        string(fieldDescriptor.Name()),
+		Mode:        "NULLABLE",
+		Description: getDoc(fieldDescriptor),
+	}
+	switch {
+	case fieldDescriptor.Cardinality() == protoreflect.Repeated:
+		field.Mode = "REPEATED"
+	case fieldDescriptor.Cardinality() == protoreflect.Required:
+		field.Mode = "REQUIRED"
+	}
+	columnType, err := getColumnType(fieldDescriptor)
+	if err != nil {
+		return nil, err
+	}
+	if columnType == columnTypeRecord {
+		messageDescriptor := fieldDescriptor.Message()
+		if len(path) >= bigQueryMaxRecordDepth || slices.Contains(path, messageDescriptor.FullName()) {
+			// BigQuery does not support recursive or deeply nested records.
+			columnType = columnTypeJSON
+		} else {
+			fields, err := getBigQueryFields(messageDescriptor, append(path, messageDescriptor.FullName()))
+			if err != nil {
+				return nil, err
+			}
+			field.Fields = fields
+		}
+	}
+	field.Type, err = getBigQueryType(columnType)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fieldDescriptor.FullName(), err)
+	}
+	return field, nil
+}
+
+func getBigQueryType(columnType columnType) (string, error) {
+	switch columnType {
+	case columnTypeBool:
+		return "BOOL", nil
+	case columnTypeInt32, columnTypeInt64:
+		return "INT64", nil
+	case columnTypeUint64:
+		return "NUMERIC", nil
+	case columnTypeFloat, columnTypeDouble:
+		return "FLOAT64", nil
+	case columnTypeString:
+		return "STRING", nil
+	case columnTypeBytes:
+		return "BYTES", nil
+	case columnTypeTimestamp:
+		return "TIMESTAMP", nil
+	case columnTypeDuration:
+		return "INTERVAL", nil
+	case columnTypeJSON:
+		return "JSON", nil
+	case columnTypeRecord:
+		return "RECORD", nil
+	default:
+		return "", fmt.Errorf("unknown column type: %d", columnType)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufschemaexport converts messages to Avro schemas and JSON Schemas, for
+// integration with schema registries and event catalogs, and to BigQuery table schemas
+// and Snowflake and Redshift DDL, for integration with data warehouses.
+//
+// The mapping rules are stable: the schema of a message only changes if the message,
+// or a message or enum it references, changes.
+//
+// # Avro
+//
+// Each message is mapped to a record, with the name of the message as the name, and
+// the full name of the parent of the message (the package, or the enclosing message)
+// as the namespace. Each enum is mapped to an enum in the same way, with the names of
+// the values as the symbols. Each named type is defined the first time it is referenced,
+// and referred to by its full name thereafter, so recursive messages are supported.
+//
+// The fields of a record are ordered by field number. Fields are mapped as follows:
+//
+//   - bool is mapped to boolean, string to string, bytes to bytes, float to float,
+//     and double to double.
+//   - int32, sint32, and sfixed32 are mapped to int. uint32, fixed32, int64, sint64,
+//     and sfixed64 are mapped to long.
+//   - uint64 and fixed64 are mapped to long. Values greater than the maximum long are
+//     represented by their two's complement.
+//   - Repeated fields are mapped to an array, and map fields are mapped to a map. Map
+//     keys are always strings in Avro, so keys of other types are converted to strings.
+//   - google.protobuf.Timestamp is mapped to a long with the timestamp-micros logical
+//     type, and the wrapper types such as google.protobuf.StringValue are mapped to
+//     the type of their value. Other well-known types are mapped as regular messages.
+//   - Fields with explicit presence, such as message fields, proto3 optional fields,
+//     and the fields of oneofs, are mapped to a union of null and the type of the field,
+//     with a default of null.
+//   - proto2 required fields are mapped to the type of the field, without a default.
+//   - All other fields have the default value of the field as the default.
+//
+// Leading comments are added as the doc of records, enums, and fields, except for
+// the well-known types, so that schemas do not change between versions of the
+// well-known types.
+//
+// # JSON Schema
+//
+// Messages are mapped to JSON Schema draft 2020-12 schemas that describe the ProtoJSON
+// encoding of the message, as produced by ProtoJSON encoders with the default options.
+// The schema refers to the definition of the exported message, and each message and enum
+// is defined in $defs with its full name as the key, so recursive messages are supported.
+//
+// Each message is mapped to an object with a property for each field, keyed by the JSON
+// name of the field, and no additional properties. Properties are not required, as
+// ProtoJSON encoders omit fields with default values. Fields are mapped as follows:
+//
+//   - bool is mapped to boolean, and string to string. bytes is mapped to a string with
+//     the base64 content encoding.
+//   - 32-bit integers are mapped to an integer, with the minimum and maximum of the type.
+//   - 64-bit integers are mapped to an integer or a string of digits, as ProtoJSON
+//     encodes 64-bit integers as strings.
+//   - float and double are mapped to a number, or one of the strings "NaN", "Infinity",
+//     and "-Infinity".
+//   - Enums are mapped to one of the names of the values, or an integer for unknown values.
+//   - Repeated fields are mapped to an array, and map fields are mapped to an object.
+//   - The well-known types are mapped as per their ProtoJSON encoding, for example
+//     google.protobuf.Timestamp is mapped to a string with the date-time format.
+//
+// That at most one field of a oneof is set is not expressed in the schema.
+//
+// Leading comments are added as the description of messages, enums, and properties,
+// except for the well-known types.
+//
+// # Data warehouses
+//
+// Messages are mapped to a table with a column for each field, ordered by field number.
+// The types of the columns are as follows:
+//
+//   - bool, string, bytes, float, and double are mapped to the equivalent types.
+//   - Integers are mapped to integer types that fit all values of the field. uint64 and
+//     fixed64 are mapped to NUMERIC in BigQuery and DECIMAL(20, 0) in Redshift.
+//   - Enums are mapped to strings with the names of the values.
+//   - google.protobuf.Timestamp is mapped to a timestamp, google.protobuf.Duration to an
+//     interval, and the wrapper types to the type of their value. google.protobuf.Struct,
+//     Value, ListValue, and Any, and messages without fields, are mapped to a JSON type.
+//   - proto2 required fields are mapped to REQUIRED or NOT NULL columns.
+//
+// For BigQuery, the schema is the JSON array of the fields of the table, as accepted by
+// "bq mk --table". Messages are mapped to RECORD columns, and repeated fields and map
+// fields to REPEATED columns, with map fields as a RECORD of their key and value.
+// Recursive messages, and messages that would exceed the maximum depth of 15 nested
+// RECORD columns, are mapped to JSON instead.
+//
+// For Snowflake and Redshift, the schema is a CREATE TABLE statement, with the name of
+// the message in lower_snake_case as the name of the table. Messages, repeated fields,
+// and map fields are mapped to the semi-structured types OBJECT and ARRAY in Snowflake,
+// and SUPER in Redshift.
+//
+// Leading comments are added as the descriptions of fields in BigQuery, and as the
+// comments of tables and columns in Snowflake and Redshift.
+package bufschemaexport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// FormatAvro is the Avro schema format.
+	FormatAvro Format = iota + 1
+	// FormatJSONSchema is the JSON Schema format.
+	FormatJSONSchema
+	// FormatBigQuery is the BigQuery table schema format.
+	FormatBigQuery
+	// FormatSnowflake is the Snowflake DDL format.
+	FormatSnowflake
+	// FormatRedshift is the Redshift DDL format.
+	FormatRedshift
+
+	wellKnownTypesPackage protoreflect.FullName = "google.protobuf"
+)
+
+var (
+	// AllFormatStrings is all format strings.
+	AllFormatStrings = []string{
+		"avro",
+		"bigquery",
+		"jsonschema",
+		"redshift",
+		"snowflake",
+	}
+
+	formatToString = map[Format]string{
+		FormatAvro:       "avro",
+		FormatJSONSchema: "jsonschema",
+		FormatBigQuery:   "bigquery",
+		FormatSnowflake:  "snowflake",
+		FormatRedshift:   "redshift",
+	}
+	stringToFormat = map[string]Format{
+		"avro":       FormatAvro,
+		"jsonschema": FormatJSONSchema,
+		"bigquery":   FormatBigQuery,
+		"snowflake":  FormatSnowflake,
+		"redshift":   FormatRedshift,
+	}
+)
+
+// Format is a schema format.
+type Format int
+
+// String implements fmt.Stringer.
+func (f Format) String() string {
+	if s, ok := formatToString[f]; ok {
+		return s
+	}
+	return strconv.Itoa(int(f))
+}
+
+// ParseFormat parses the Format.
+func ParseFormat(s string) (Format, error) {
+	if format, ok := stringToFormat[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return format, nil
+	}
+	return 0, fmt.Errorf("unknown format: %q", s)
+}
+
+// ExportSchema returns the schema of the message in the format. Schemas are indented
+// JSON, except for the Snowflake and Redshift formats, which are SQL statements.
+func ExportSchema(messageDescriptor protoreflect.MessageDescriptor, format Format) ([]byte, error) {
+	switch format {
+	case FormatAvro:
+		return exportAvroSchema(messageDescriptor)
+	case FormatJSONSchema:
+		return exportJSONSchema(messageDescriptor)
+	case FormatBigQuery:
+		return exportBigQuerySchema(messageDescriptor)
+	case FormatSnowflake:
+		return exportWarehouseDDL(messageDescriptor, snowflakeDialect)
+	case FormatRedshift:
+		return exportWarehouseDDL(messageDescriptor, redshiftDialect)
+	default:
+		return nil, fmt.Errorf("unknown format: %v", format)
+	}
+}
+
+// getDoc returns the leading comments of the descriptor, or empty if there are none
+// or the descriptor is a well-known type.
+func getDoc(descriptor protoreflect.Descriptor) string {
+	if descriptor.ParentFile().Package() == wellKnownTypesPackage {
+		return ""
+	}
+	sourceLocation := descriptor.ParentFile().SourceLocations().ByDescriptor(descriptor)
+	lines := strings.Split(strings.TrimSpace(sourceLocation.LeadingComments), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,347 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufschemaexport
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduletesting"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestExportSchema(t *testing.T) {
+	t.Parallel()
+	messageDescriptor := newTestMessageDescriptor(t, "acme.v1.Event")
+	testExportSchemaGolden(t, messageDescriptor, FormatAvro, "acme.v1.Event.avsc")
+	testExportSchemaGolden(t, messageDescriptor, FormatJSONSchema, "acme.v1.Event.schema.json")
+	testExportSchemaGolden(t, messageDescriptor, FormatBigQuery, "acme.v1.Event.bigquery.json")
+	testExportSchemaGolden(t, messageDescriptor, FormatSnowflake, "acme.v1.Event.snowflake.sql")
+	testExportSchemaGolden(t, messageDescriptor, FormatRedshift, "acme.v1.Event.redshift.sql")
+}
+
+func TestExportSchemaRequired(t *testing.T) {
+	t.Parallel()
+	messageDescriptor := newTestMessageDescriptor(t, "acme.v1.Legacy")
+	testExportSchemaGolden(t, messageDescriptor, FormatBigQuery, "acme.v1.Legacy.bigquery.json")
+	testExportSchemaGolden(t, messageDescriptor, FormatSnowflake, "acme.v1.Legacy.snowflake.sql")
+	testExportSchemaGolden(t, messageDescriptor, FormatRedshift, "acme.v1.Legacy.redshift.sql")
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+	for _, formatString := range AllFormatStrings {
+		format, err := ParseFormat(formatString)
+		require.NoError(t, err)
+		require.Equal(t, formatString, format.String())
+	}
+	_, err := ParseFormat("protobuf")
+	require.Error(t, err)
+}
+
+func TestAvroSchemaIsValid(t *testing.T) {
+	t.Parallel()
+	data, err := ExportSchema(newTestMessageDescriptor(t, "acme.v1.Event"), FormatAvro)
+	require.NoError(t, err)
+	var schema any
+	require.NoError(t, json.Unmarshal(data, &schema))
+	testValidateAvroSchema(t, schema, "", make(map[string]any))
+}
+
+func TestJSONSchemaRoundTrip(t *testing.T) {
+	t.Parallel()
+	messageDescriptor := newTestMessageDescriptor(t, "acme.v1.Event")
+	data, err := ExportSchema(messageDescriptor, FormatJSONSchema)
+	require.NoError(t, err)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+	inputData, err := os.ReadFile(filepath.Join("testdata", "golden", "acme.v1.Event.json"))
+	require.NoError(t, err)
+	message := dynamicpb.NewMessage(messageDescriptor)
+	require.NoError(t, protojson.Unmarshal(inputData, message))
+	// Every field is set, so that the schema of every field is validated.
+	fieldDescriptors := messageDescriptor.Fields()
+	for i := range fieldDescriptors.Len() {
+		fieldDescriptor := fieldDescriptors.Get(i)
+		if fieldDescriptor.ContainingOneof() == nil {
+			require.True(t, message.Has(fieldDescriptor), fieldDescriptor.FullName())
+		}
+	}
+	encodedData, err := protojson.Marshal(message)
+	require.NoError(t, err)
+	var encoded any
+	require.NoError(t, json.Unmarshal(encodedData, &encoded))
+	require.NoError(t, validateJSONSchema(schema, schema, encoded, "$"))
+	roundTripMessage := dynamicpb.NewMessage(messageDescriptor)
+	require.NoError(t, protojson.Unmarshal(encodedData, roundTripMessage))
+	require.True(t, proto.Equal(message, roundTripMessage))
+}
+
+func testExportSchemaGolden(t *testing.T, messageDescriptor protoreflect.MessageDescriptor, format Format, goldenFileName string) {
+	data, err := ExportSchema(messageDescriptor, format)
+	require.NoError(t, err)
+	expected, err := os.ReadFile(filepath.Join("testdata", "golden", goldenFileName))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(data)+"\n")
+}
+
+func newTestMessageDescriptor(t *testing.T, fullName protoreflect.FullName) protoreflect.MessageDescriptor {
+	moduleSet, err := bufmoduletesting.NewModuleSetForDirPath(filepath.Join("testdata", "proto"))
+	require.NoError(t, err)
+	image, err := bufimage.BuildImage(
+		context.Background(),
+		slogtestext.NewLogger(t),
+		bufmodule.ModuleSetToModuleReadBucketWithOnlyProtoFiles(moduleSet),
+	)
+	require.NoError(t, err)
+	descriptor, err := image.Resolver().FindDescriptorByName(fullName)
+	require.NoError(t, err)
+	messageDescriptor, ok := descriptor.(protoreflect.MessageDescriptor)
+	require.True(t, ok)
+	return messageDescriptor
+}
+
+// validateJSONSchema validates the value against the schema, supporting the
+// keywords used by exported schemas.
+func validateJSONSchema(root map[string]any, schema map[string]any, value any, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		key, ok := strings.CutPrefix(ref, "#/$defs/")
+		if !ok {
+			return fmt.Errorf("%s: unsupported $ref %q", path, ref)
+		}
+		def, ok := root["$defs"].(map[string]any)[key].(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: %q not found", path, ref)
+		}
+		if err := validateJSONSchema(root, def, value, path); err != nil {
+			return err
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		var matched bool
+		for _, subschema := range anyOf {
+			if validateJSONSchema(root, subschema.(map[string]any), value, path) == nil {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: %v does not match any of %v", path, value, anyOf)
+		}
+	}
+	if schemaType, ok := schema["type"]; ok && !jsonTypeMatches(schemaType, value) {
+		return fmt.Errorf("%s: %v is not of type %v", path, value, schemaType)
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, value) {
+		return fmt.Errorf("%s: %v is not one of %v", path, value, enum)
+	}
+	if s, ok := value.(string); ok {
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(s) {
+			return fmt.Errorf("%s: %q does not match %q", path, s, pattern)
+		}
+		if contentEncoding, ok := schema["contentEncoding"].(string); ok {
+			if contentEncoding != "base64" {
+				return fmt.Errorf("%s: unsupported contentEncoding %q", path, contentEncoding)
+			}
+			if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+	if f, ok := value.(float64); ok {
+		if minimum, ok := schema["minimum"].(float64); ok && f < minimum {
+			return fmt.Errorf("%s: %v is less than %v", path, f, minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && f > maximum {
+			return fmt.Errorf("%s: %v is greater than %v", path, f, maximum)
+		}
+	}
+	if array, ok := value.([]any); ok {
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range array {
+				if err := validateJSONSchema(root, items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	for _, required := range asSlice(schema["required"]) {
+		if _, ok := object[required.(string)]; !ok {
+			return fmt.Errorf("%s: missing required property %q", path, required)
+		}
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	for key, propertyValue := range object {
+		propertyPath := path + "." + key
+		if propertySchema, ok := properties[key].(map[string]any); ok {
+			if err := validateJSONSchema(root, propertySchema, propertyValue, propertyPath); err != nil {
+				return err
+			}
+			continue
+		}
+		switch additionalProperties := schema["additionalProperties"].(type) {
+		case bool:
+			if !additionalProperties {
+				return fmt.Errorf("%s: unexpected property", propertyPath)
+			}
+		case map[string]any:
+			if err := validateJSONSchema(root, additionalProperties, propertyValue, propertyPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func jsonTypeMatches(schemaType any, value any) bool {
+	for _, typeName := range asSlice(schemaType) {
+		switch typeName {
+		case "object":
+			if _, ok := value.(map[string]any); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]any); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := value.(float64); ok && f == math.Trunc(f) {
+				return true
+			}
+		case "null":
+			if value == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// testValidateAvroSchema validates that named types are defined once before they are
+// referenced, and that the defaults of fields match their types.
+func testValidateAvroSchema(t *testing.T, schema any, namespace string, nameToSchema map[string]any) {
+	switch schema := schema.(type) {
+	case string:
+		switch schema {
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+		default:
+			require.Contains(t, nameToSchema, schema)
+		}
+	case []any:
+		for _, branch := range schema {
+			testValidateAvroSchema(t, branch, namespace, nameToSchema)
+		}
+	case map[string]any:
+		switch schema["type"] {
+		case "record", "enum":
+			fullName := schema["namespace"].(string) + "." + schema["name"].(string)
+			require.NotContains(t, nameToSchema, fullName)
+			nameToSchema[fullName] = schema
+			if schema["type"] == "enum" {
+				require.Contains(t, schema["symbols"], schema["default"])
+				return
+			}
+			for _, field := range schema["fields"].([]any) {
+				field := field.(map[string]any)
+				testValidateAvroSchema(t, field["type"], namespace, nameToSchema)
+				if defaultValue, ok := field["default"]; ok {
+					testValidateAvroDefault(t, field["type"], defaultValue, nameToSchema, field["name"].(string))
+				}
+			}
+		case "array":
+			testValidateAvroSchema(t, schema["items"], namespace, nameToSchema)
+		case "map":
+			testValidateAvroSchema(t, schema["values"], namespace, nameToSchema)
+		default:
+			testValidateAvroSchema(t, schema["type"], namespace, nameToSchema)
+		}
+	}
+}
+
+func testValidateAvroDefault(t *testing.T, schema any, defaultValue any, nameToSchema map[string]any, fieldName string) {
+	switch schema := schema.(type) {
+	case string:
+		switch schema {
+		case "boolean":
+			require.IsType(t, false, defaultValue, fieldName)
+		case "int", "long", "float", "double":
+			require.IsType(t, float64(0), defaultValue, fieldName)
+		case "bytes", "string":
+			require.IsType(t, "", defaultValue, fieldName)
+		default:
+			testValidateAvroDefault(t, nameToSchema[schema], defaultValue, nameToSchema, fieldName)
+		}
+	case []any:
+		// The default of a union must match the first branch.
+		testValidateAvroDefault(t, schema[0], defaultValue, nameToSchema, fieldName)
+	case map[string]any:
+		switch schema["type"] {
+		case "enum":
+			require.Contains(t, schema["symbols"], defaultValue, fieldName)
+		case "array":
+			require.IsType(t, []any{}, defaultValue, fieldName)
+		case "map", "record":
+			require.IsType(t, map[string]any{}, defaultValue, fieldName)
+		default:
+			testValidateAvroDefault(t, schema["type"], defaultValue, nameToSchema, fieldName)
+		}
+	}
+	if schema == "null" {
+		require.Nil(t, defaultValue, fieldName)
+	}
+}
+
+func asSlice(value any) []any {
+	switch value := value.(type) {
+	case []any:
+		return value
+	case nil:
+		return nil
+	default:
+		return []any{value}
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufschemaexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchemaExporter exports JSON Schemas. Each exporter is used for a single schema,
+// as the definitions of messages and enums are collected in defs.
+type jsonSchemaExporter struct {
+	defs map[string]any
+}
+
+func exportJSONSchema(messageDescriptor protoreflect.MessageDescriptor) ([]byte, error) {
+	exporter := &jsonSchemaExporter{
+		defs: make(map[string]any),
+	}
+	ref, err := exporter.messageSchema(messageDescriptor)
+	if err != nil {
+		return nil, err
+	}
+	schema := map[string]any{
+		"$schema": jsonSchemaDialect,
+		"$defs":   exporter.defs,
+	}
+	for key, value := range ref {
+		schema[key] = value
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// messageSchema returns a reference to the definition of the message, adding it
+// to the definitions if needed. Well-known types are returned inline.
+func (e *jsonSchemaExporter) messageSchema(messageDescriptor protoreflect.MessageDescriptor) (map[string]any, error) {
+	if schema, ok := getWellKnownTypeJSONSchema(messageDescriptor); ok {
+		return schema, nil
+	}
+	if isWrapper(messageDescriptor) {
+		return e.singularFieldSchema(messageDescriptor.Fields().ByName("value"))
+	}
+	key := string(messageDescriptor.FullName())
+	ref := map[string]any{"$ref": "#/$defs/" + key}
+	if _, ok := e.defs[key]; ok {
+		return ref, nil
+	}
+	properties := make(map[string]any)
+	def := map[string]any{
+		"title":                key,
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if doc := getDoc(messageDescriptor); doc != "" {
+		def["description"] = doc
+	}
+	// Added before the fields are added, so that recursive references refer to it.
+	e.defs[key] = def
+	for _, fieldDescriptor := range getFieldsByNumber(messageDescriptor) {
+		property, err := e.fieldSchema(fieldDescriptor)
+		if err != nil {
+			return nil, err
+		}
+		if doc := getDoc(fieldDescriptor); doc != "" {
+			property["description"] = doc
+		}
+		properties[fieldDescriptor.JSONName()] = property
+	}
+	return ref, nil
+}
+
+func (e *jsonSchemaExporter) fieldSchema(fieldDescriptor protoreflect.FieldDescriptor) (map[string]any, error) {
+	switch {
+	case fieldDescriptor.IsMap():
+		valueSchema, err := e.singularFieldSchema(fieldDescriptor.MapValue())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": valueSchema,
+		}, nil
+	case fieldDescriptor.IsList():
+		itemSchema, err := e.singularFieldSchema(fieldDescriptor)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"type":  "array",
+			"items": itemSchema,
+		}, nil
+	default:
+		return e.singularFieldSchema(fieldDescriptor)
+	}
+}
+
+// singularFieldSchema returns the schema of a single value of the field.
+func (e *jsonSchemaExporter) singularFieldSchema(fieldDescriptor protoreflect.FieldDescriptor) (map[string]any, error) {
+	switch fieldDescriptor.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}, nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]any{
+			"type":    "integer",
+			"minimum": math.MinInt32,
+			"maximum": math.MaxInt32,
+		}, nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{
+			"type":    "integer",
+			"minimum": 0,
+			"maximum": math.MaxUint32,
+		}, nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return map[string]any{
+			"type":    []string{"integer", "string"},
+			"pattern": "^-?[0-9]+$",
+		}, nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]any{
+			"type":    []string{"integer", "string"},
+			"minimum": 0,
+			"pattern": "^[0-9]+$",
+		}, nil
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return map[string]any{
+			"anyOf": []any{
+				map[string]any{"type": "number"},
+				map[string]any{"type": "string", "enum": []string{"NaN", "Infinity", "-Infinity"}},
+			},
+		}, nil
+	case protoreflect.StringKind:
+		return map[string]any{"type": "string"}, nil
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "contentEncoding": "base64"}, nil
+	case protoreflect.EnumKind:
+		return e.enumSchema(fieldDescriptor.Enum()), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return e.messageSchema(fieldDescriptor.Message())
+	default:
+		return nil, fmt.Errorf("%s: unknown kind %v", fieldDescriptor.FullName(), fieldDescriptor.Kind())
+	}
+}
+
+// enumSchema returns a reference to the definition of the enum, adding it
+// to the definitions if needed.
+func (e *jsonSchemaExporter) enumSchema(enumDescriptor protoreflect.EnumDescriptor) map[string]any {
+	if enumDescriptor.FullName() == "google.protobuf.NullValue" {
+		return map[string]any{"type": "null"}
+	}
+	key := string(enumDescriptor.FullName())
+	ref := map[string]any{"$ref": "#/$defs/" + key}
+	if _, ok := e.defs[key]; ok {
+		return ref
+	}
+	enumValueDescriptors := enumDescriptor.Values()
+	names := make([]string, enumValueDescriptors.Len())
+	for i := range enumValueDescriptors.Len() {
+		names[i] = string(enumValueDescriptors.Get(i).Name())
+	}
+	def := map[string]any{
+		"title": key,
+		"anyOf": []any{
+			map[string]any{"type": "string", "enum": names},
+			// Values that are not known to the encoder are encoded as numbers.
+			map[string]any{"type": "integer", "minimum": math.MinInt32, "maximum": math.MaxInt32},
+		},
+	}
+	if doc := getDoc(enumDescriptor); doc != "" {
+		def["description"] = doc
+	}
+	e.defs[key] = def
+	return ref
+}
+
+// getWellKnownTypeJSONSchema returns the schema of the ProtoJSON encoding of the
+// well-known types that have a special encoding.
+func getWellKnownTypeJSONSchema(messageDescriptor protoreflect.MessageDescriptor) (map[string]any, bool) {
+	switch messageDescriptor.FullName() {
+	case timestampFullName:
+		return map[string]any{"type": "string", "format": "date-time"}, true
+	case "google.protobuf.Duration":
+		return map[string]any{"type": "string", "pattern": `^-?[0-9]+(\.[0-9]{1,9})?s$`}, true
+	case "google.protobuf.FieldMask":
+		return map[string]any{"type": "string"}, true
+	case "google.protobuf.Struct":
+		return map[string]any{"type": "object"}, true
+	case "google.protobuf.ListValue":
+		return map[string]any{"type": "array"}, true
+	case "google.protobuf.Value":
+		// Any JSON value.
+		return map[string]any{}, true
+	case "google.protobuf.Any":
+		return map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"@type": map[string]any{"type": "string"},
+			},
+			"required": []string{"@type"},
+		}, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufschemaexport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// columnType is the type of a single value of a field in a data warehouse, from which
+// the types of each data warehouse are derived.
+type columnType int
+
+const (
+	// int32, sint32, and sfixed32.
+	columnTypeInt32 columnType = iota + 1
+	// uint32, fixed32, int64, sint64, and sfixed64.
+	columnTypeInt64
+	// uint64 and fixed64.
+	columnTypeUint64
+	columnTypeBool
+	columnTypeFloat
+	columnTypeDouble
+	// string, enums, and google.protobuf.FieldMask.
+	columnTypeString
+	columnTypeBytes
+	columnTypeTimestamp
+	columnTypeDuration
+	// Messages without fields, and google.protobuf.Struct, Value, ListValue, and Any.
+	columnTypeJSON
+	columnTypeRecord
+)
+
+// warehouseDialect is the SQL dialect of a data warehouse.
+type warehouseDialect struct {
+	// getType returns the type of the column of the field.
+	getType func(fieldDescriptor protoreflect.FieldDescriptor, columnType columnType) (string, error)
+	// inlineComments is true if comments are added to the CREATE TABLE statement,
+	// and false if they are added with COMMENT ON statements.
+	inlineComments bool
+}
+
+var (
+	snowflakeDialect = &warehouseDialect{
+		getType:        getSnowflakeType,
+		inlineComments: true,
+	}
+	redshiftDialect = &warehouseDialect{
+		getType: getRedshiftType,
+	}
+)
+
+func exportWarehouseDDL(messageDescriptor protoreflect.MessageDescriptor, dialect *warehouseDialect) ([]byte, error) {
+	tableName := quoteSQLIdentifier(stringutil.ToLowerSnakeCase(string(messageDescriptor.Name())))
+	fieldDescriptors := getFieldsByNumber(messageDescriptor)
+	var builder strings.Builder
+	var commentStatements []string
+	if doc := getDoc(messageDescriptor); doc != "" && !dialect.inlineComments {
+		commentStatements = append(
+			commentStatements,
+			fmt.Sprintf("COMMENT ON TABLE %s IS %s;", tableName, quoteSQLString(doc)),
+		)
+	}
+	fmt.Fprintf(&builder, "CREATE TABLE IF NOT EXISTS %s (\n", tableName)
+	for i, fieldDescriptor := range fieldDescriptors {
+		columnType, err := getColumnType(fieldDescriptor)
+		if err != nil {
+			return nil, err
+		}
+		sqlType, err := dialect.getType(fieldDescriptor, columnType)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fieldDescriptor.FullName(), err)
+		}
+		columnName := quoteSQLIdentifier(string(fieldDescriptor.Name()))
+		fmt.Fprintf(&builder, "  %s %s", columnName, sqlType)
+		if fieldDescriptor.Cardinality() == protoreflect.Required {
+			builder.WriteString(" NOT NULL")
+		}
+		if doc := getDoc(fieldDescriptor); doc != "" {
+			if dialect.inlineComments {
+				fmt.Fprintf(&builder, " COMMENT %s", quoteSQLString(doc))
+			} else {
+				commentStatements = append(
+					commentStatements,
+					fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", tableName, columnName, quoteSQLString(doc)),
+				)
+			}
+		}
+		if i < len(fieldDescriptors)-1 {
+			builder.WriteString(",")
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString(")")
+	if doc := getDoc(messageDescriptor); doc != "" && dialect.inlineComments {
+		fmt.Fprintf(&builder, " COMMENT = %s", quoteSQLString(doc))
+	}
+	builder.WriteString(";")
+	for _, commentStatement := range commentStatements {
+		builder.WriteString("\n")
+		builder.WriteString(commentStatement)
+	}
+	return []byte(builder.String()), nil
+}
+
+func getSnowflakeType(fieldDescriptor protoreflect.FieldDescriptor, columnType columnType) (string, error) {
+	switch {
+	case fieldDescriptor.IsMap():
+		return "OBJECT", nil
+	case fieldDescriptor.IsList():
+		return "ARRAY", nil
+	}
+	switch columnType {
+	case columnTypeInt32, columnTypeInt64, columnTypeUint64:
+		// INTEGER is NUMBER(38, 0), which fits all 64-bit integers.
+		return "INTEGER", nil
+	case columnTypeBool:
+		return "BOOLEAN", nil
+	case columnTypeFloat, columnTypeDouble:
+		return "FLOAT", nil
+	case columnTypeString, columnTypeDuration:
+		return "VARCHAR", nil
+	case columnTypeBytes:
+		return "BINARY", nil
+	case columnTypeTimestamp:
+		return "TIMESTAMP_TZ(9)", nil
+	case columnTypeJSON:
+		return "VARIANT", nil
+	case columnTypeRecord:
+		return "OBJECT", nil
+	default:
+		return "", fmt.Errorf("unknown column type: %d", columnType)
+	}
+}
+
+func getRedshiftType(fieldDescriptor protoreflect.FieldDescriptor, columnType columnType) (string, error) {
+	if fieldDescriptor.IsMap() || fieldDescriptor.IsList() {
+		return "SUPER", nil
+	}
+	switch columnType {
+	case columnTypeInt32:
+		return "INTEGER", nil
+	case columnTypeInt64:
+		return "BIGINT", nil
+	case columnTypeUint64:
+		return "DECIMAL(20, 0)", nil
+	case columnTypeBool:
+		return "BOOLEAN", nil
+	case columnTypeFloat:
+		return "REAL", nil
+	case columnTypeDouble:
+		return "DOUBLE PRECISION", nil
+	case columnTypeString:
+		return "VARCHAR(MAX)", nil
+	case columnTypeBytes:
+		return "VARBYTE(1024000)", nil
+	case columnTypeTimestamp:
+		return "TIMESTAMPTZ", nil
+	case columnTypeDuration:
+		return "INTERVAL DAY TO SECOND", nil
+	case columnTypeJSON, columnTypeRecord:
+		return "SUPER", nil
+	default:
+		return "", fmt.Errorf("unknown column type: %d", columnType)
+	}
+}
+
+// getColumnType returns the column type of a single value of the field.
+func getColumnType(fieldDescriptor protoreflect.FieldDescriptor) (columnType, error) {
+	switch fieldDescriptor.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return columnTypeInt32, nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return columnTypeInt64, nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return columnTypeUint64, nil
+	case protoreflect.BoolKind:
+		return columnTypeBool, nil
+	case protoreflect.FloatKind:
+		return columnTypeFloat, nil
+	case protoreflect.DoubleKind:
+		return columnTypeDouble, nil
+	case protoreflect.StringKind, protoreflect.EnumKind:
+		return columnTypeString, nil
+	case protoreflect.BytesKind:
+		return columnTypeBytes, nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		messageDescriptor := fieldDescriptor.Message()
+		switch messageDescriptor.FullName() {
+		case timestampFullName:
+			return columnTypeTimestamp, nil
+		case "google.protobuf.Duration":
+			return columnTypeDuration, nil
+		case "google.protobuf.FieldMask":
+			return columnTypeString, nil
+		case "google.protobuf.Struct",
+			"google.protobuf.Value",
+			"google.protobuf.ListValue",
+			"google.protobuf.Any":
+			return columnTypeJSON, nil
+		}
+		if isWrapper(messageDescriptor) {
+			return getColumnType(messageDescriptor.Fields().ByName("value"))
+		}
+		if messageDescriptor.Fields().Len() == 0 {
+			return columnTypeJSON, nil
+		}
+		return columnTypeRecord, nil
+	default:
+		return 0, fmt.Errorf("%s: unknown kind %v", fieldDescriptor.FullName(), fieldDescriptor.Kind())
+	}
+}
+
+func quoteSQLIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+func quoteSQLString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
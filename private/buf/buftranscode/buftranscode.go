@@ -0,0 +1,120 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package buftranscode maps HTTP requests to RPCs and RPCs to HTTP requests
+// according to the google.api.http annotations on methods, as done by gRPC
+// JSON transcoding proxies such as Envoy.
+//
+// See https://github.com/googleapis/googleapis/blob/master/google/api/http.proto
+// for the specification of the annotations.
+package buftranscode
+
+import (
+	"errors"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ErrNoMatchingBinding is returned when no binding matches an HTTP request
+// or a request message.
+var ErrNoMatchingBinding = errors.New("no matching binding")
+
+// Binding is a mapping of an HTTP method and path template to a method, as
+// specified by a google.api.http annotation or one of its additional bindings.
+type Binding interface {
+	// Method returns the method that the binding is for.
+	Method() protoreflect.MethodDescriptor
+	// HTTPMethod returns the HTTP method, such as GET or POST.
+	HTTPMethod() string
+	// PathTemplate returns the path template, such as /v1/{name=shelves/*/books/*}.
+	PathTemplate() string
+	// Body returns the name of the field of the request message that is mapped
+	// to the HTTP body, "*" if all fields not bound by the path are mapped to
+	// the body, or empty if there is no body.
+	Body() string
+	// ResponseBody returns the name of the field of the response message that is
+	// mapped to the HTTP response body, or empty if the entire response message is
+	// mapped to the HTTP response body.
+	ResponseBody() string
+	// IsAdditional returns true if the binding is an additional binding of the
+	// google.api.http annotation of its method.
+	IsAdditional() bool
+
+	isBinding()
+}
+
+// HTTPRequest is an HTTP request.
+type HTTPRequest struct {
+	// Method is the HTTP method, such as GET or POST.
+	Method string
+	// Target is the request target, that is the path followed by an optional
+	// query string, such as /v1/shelves/1/books?page_size=10.
+	Target string
+	// Body is the JSON body of the request.
+	//
+	// Empty if the request has no body.
+	Body []byte
+}
+
+// Transcoder maps HTTP requests to RPCs and RPCs to HTTP requests.
+type Transcoder interface {
+	// Bindings returns all bindings, in the order their methods are declared,
+	// with additional bindings following the binding they are declared on.
+	Bindings() []Binding
+	// HTTPToRPC returns the binding that the HTTP request matches, and the
+	// request message of the binding's method that the HTTP request maps to.
+	//
+	// Bindings are matched in order, and the first binding whose HTTP method
+	// and path template match the request is used. Returns an error that wraps
+	// ErrNoMatchingBinding if no binding matches.
+	HTTPToRPC(httpRequest *HTTPRequest) (Binding, proto.Message, error)
+	// RPCToHTTP returns the binding of the method, and the HTTP request that
+	// the request message maps to.
+	//
+	// The request message must be of the input type of the method. Bindings of the
+	// method are tried in order, and the first binding for which all fields bound
+	// by the path template are set is used. Returns an error that wraps
+	// ErrNoMatchingBinding if no binding can be used.
+	RPCToHTTP(methodFullName protoreflect.FullName, requestMessage proto.Message) (Binding, *HTTPRequest, error)
+
+	isTranscoder()
+}
+
+// EnvoyConfigForBindings returns the YAML configuration of an Envoy gRPC-JSON
+// transcoder filter for the services of the bindings, and of routes from these
+// services to the cluster.
+//
+// The configuration has the http_filters and route_config keys of an Envoy
+// HTTP connection manager. The filter reads the FileDescriptorSet of the services
+// from descriptorSetPath.
+func EnvoyConfigForBindings(bindings []Binding, descriptorSetPath string, clusterName string) ([]byte, error) {
+	return envoyConfigForBindings(bindings, descriptorSetPath, clusterName)
+}
+
+// GRPCGatewayConfigForBindings returns the YAML gRPC API configuration for the
+// bindings, as read by protoc-gen-grpc-gateway with the grpc_api_configuration
+// option.
+//
+// The bindings must be in the order returned by Transcoder.Bindings.
+func GRPCGatewayConfigForBindings(bindings []Binding) ([]byte, error) {
+	return grpcGatewayConfigForBindings(bindings)
+}
+
+// NewTranscoder returns a new Transcoder for the methods in the Image that
+// have google.api.http annotations.
+func NewTranscoder(image bufimage.Image) (Transcoder, error) {
+	return newTranscoder(image)
+}
@@ -0,0 +1,346 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buftranscode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduletesting"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestHTTPToRPC(t *testing.T) {
+	t.Parallel()
+	transcoder, image := newTestTranscoder(t)
+	testHTTPToRPC := func(
+		t *testing.T,
+		httpRequest *HTTPRequest,
+		expectedMethodFullName protoreflect.FullName,
+		expectedPathTemplate string,
+		expectedRequestJSON string,
+	) {
+		t.Helper()
+		binding, requestMessage, err := transcoder.HTTPToRPC(httpRequest)
+		require.NoError(t, err)
+		assert.Equal(t, expectedMethodFullName, binding.Method().FullName())
+		assert.Equal(t, expectedPathTemplate, binding.PathTemplate())
+		data, err := protoencoding.NewJSONMarshaler(image.Resolver()).Marshal(requestMessage)
+		require.NoError(t, err)
+		assert.JSONEq(t, expectedRequestJSON, string(data))
+	}
+	testHTTPToRPC(
+		t,
+		&HTTPRequest{Method: "GET", Target: "/v1/shelves/1/books/2"},
+		"acme.v1.BookService.GetBook",
+		"/v1/{name=shelves/*/books/*}",
+		`{"name":"shelves/1/books/2"}`,
+	)
+	testHTTPToRPC(
+		t,
+		&HTTPRequest{Method: "get", Target: "/v1/books/42"},
+		"acme.v1.BookService.GetBook",
+		"/v1/books/{id}",
+		`{"id":"42"}`,
+	)
+	testHTTPToRPC(
+		t,
+		&HTTPRequest{Method: "GET", Target: "/v1/shelves/1/books?page_size=10&filter.genres=GENRE_FICTION&filter.genres=2&filter.author=a%20b"},
+		"acme.v1.BookService.ListBooks",
+		"/v1/{parent=shelves/*}/books",
+		`{"parent":"shelves/1","pageSize":10,"filter":{"genres":["GENRE_FICTION","GENRE_HISTORY"],"author":"a b"}}`,
+	)
+	testHTTPToRPC(
+		t,
+		&HTTPRequest{Method: "POST", Target: "/v1/shelves/1/books?validate_only=true", Body: []byte(`{"title":"Dune","pages":412}`)},
+		"acme.v1.BookService.CreateBook",
+		"/v1/{parent=shelves/*}/books",
+		`{"parent":"shelves/1","book":{"title":"Dune","pages":"412"},"validateOnly":true}`,
+	)
+	testHTTPToRPC(
+		t,
+		&HTTPRequest{Method: "PATCH", Target: "/v1/shelves/1/books/2", Body: []byte(`{"book":{"title":"Dune"},"updateMask":"title"}`)},
+		"acme.v1.BookService.UpdateBook",
+		"/v1/{book.name=shelves/*/books/*}",
+		`{"book":{"name":"shelves/1/books/2","title":"Dune"},"updateMask":"title"}`,
+	)
+	testHTTPToRPC(
+		t,
+		&HTTPRequest{Method: "POST", Target: "/v1/shelves/1/books/2:archive", Body: []byte(`{"reason":"old"}`)},
+		"acme.v1.BookService.ArchiveBook",
+		"/v1/{name=shelves/*/books/*}:archive",
+		`{"name":"shelves/1/books/2","reason":"old"}`,
+	)
+	testHTTPToRPC(
+		t,
+		&HTTPRequest{Method: "GET", Target: "/v1/files/a/b%20c/d.txt"},
+		"acme.v1.BookService.GetFile",
+		"/v1/files/{path=**}",
+		`{"path":"a/b c/d.txt"}`,
+	)
+}
+
+func TestHTTPToRPCError(t *testing.T) {
+	t.Parallel()
+	transcoder, _ := newTestTranscoder(t)
+	_, _, err := transcoder.HTTPToRPC(&HTTPRequest{Method: "DELETE", Target: "/v1/shelves/1/books/2"})
+	require.ErrorIs(t, err, ErrNoMatchingBinding)
+	_, _, err = transcoder.HTTPToRPC(&HTTPRequest{Method: "GET", Target: "/v1/shelves/1/books/2/pages"})
+	require.ErrorIs(t, err, ErrNoMatchingBinding)
+	_, _, err = transcoder.HTTPToRPC(&HTTPRequest{Method: "GET", Target: "/v1/shelves/1/books?unknown=1"})
+	require.EqualError(t, err, `GET /v1/{parent=shelves/*}/books: query parameter "unknown": field "unknown" not found in acme.v1.ListBooksRequest`)
+	_, _, err = transcoder.HTTPToRPC(&HTTPRequest{Method: "GET", Target: "/v1/shelves/1/books?parent=shelves/2"})
+	require.EqualError(t, err, `GET /v1/{parent=shelves/*}/books: query parameter "parent" is bound by the path`)
+	_, _, err = transcoder.HTTPToRPC(&HTTPRequest{Method: "GET", Target: "/v1/books/abc"})
+	require.EqualError(t, err, `GET /v1/books/{id}: path variable id: invalid value "abc" for field id: strconv.ParseInt: parsing "abc": invalid syntax`)
+	_, _, err = transcoder.HTTPToRPC(&HTTPRequest{Method: "GET", Target: "/v1/shelves/1/books/2", Body: []byte(`{}`)})
+	require.EqualError(t, err, `GET /v1/{name=shelves/*/books/*}: binding has no body, but the request has a body`)
+	_, _, err = transcoder.HTTPToRPC(&HTTPRequest{Method: "PATCH", Target: "/v1/shelves/1/books/2?update_mask=title", Body: []byte(`{}`)})
+	require.EqualError(t, err, `PATCH /v1/{book.name=shelves/*/books/*}: query parameters cannot be used when all fields are mapped to the body`)
+}
+
+func TestRPCToHTTP(t *testing.T) {
+	t.Parallel()
+	transcoder, image := newTestTranscoder(t)
+	testRPCToHTTP := func(
+		t *testing.T,
+		methodFullName protoreflect.FullName,
+		requestJSON string,
+		expectedPathTemplate string,
+		expectedHTTPMethod string,
+		expectedTarget string,
+		expectedBody string,
+	) {
+		t.Helper()
+		method, err := image.Resolver().FindDescriptorByName(methodFullName)
+		require.NoError(t, err)
+		requestMessage := dynamicpb.NewMessage(method.(protoreflect.MethodDescriptor).Input())
+		require.NoError(t, protoencoding.NewJSONUnmarshaler(image.Resolver()).Unmarshal([]byte(requestJSON), requestMessage))
+		binding, httpRequest, err := transcoder.RPCToHTTP(methodFullName, requestMessage)
+		require.NoError(t, err)
+		assert.Equal(t, expectedPathTemplate, binding.PathTemplate())
+		assert.Equal(t, expectedHTTPMethod, httpRequest.Method)
+		assert.Equal(t, expectedTarget, httpRequest.Target)
+		if expectedBody == "" {
+			assert.Empty(t, httpRequest.Body)
+		} else {
+			assert.JSONEq(t, expectedBody, string(httpRequest.Body))
+		}
+	}
+	testRPCToHTTP(
+		t,
+		"acme.v1.BookService.GetBook",
+		`{"name":"shelves/1/books/2"}`,
+		"/v1/{name=shelves/*/books/*}",
+		"GET",
+		"/v1/shelves/1/books/2",
+		"",
+	)
+	testRPCToHTTP(
+		t,
+		"acme.v1.BookService.GetBook",
+		`{"id":"42"}`,
+		"/v1/books/{id}",
+		"GET",
+		"/v1/books/42",
+		"",
+	)
+	testRPCToHTTP(
+		t,
+		"acme.v1.BookService.ListBooks",
+		`{"parent":"shelves/1","pageSize":10,"filter":{"genres":["GENRE_FICTION","GENRE_HISTORY"],"author":"a b"}}`,
+		"/v1/{parent=shelves/*}/books",
+		"GET",
+		"/v1/shelves/1/books?filter.author=a+b&filter.genres=GENRE_FICTION&filter.genres=GENRE_HISTORY&page_size=10",
+		"",
+	)
+	testRPCToHTTP(
+		t,
+		"acme.v1.BookService.CreateBook",
+		`{"parent":"shelves/1","book":{"title":"Dune"},"validateOnly":true}`,
+		"/v1/{parent=shelves/*}/books",
+		"POST",
+		"/v1/shelves/1/books?validate_only=true",
+		`{"title":"Dune"}`,
+	)
+	testRPCToHTTP(
+		t,
+		"acme.v1.BookService.UpdateBook",
+		`{"book":{"name":"shelves/1/books/2","title":"Dune"},"updateMask":"title"}`,
+		"/v1/{book.name=shelves/*/books/*}",
+		"PATCH",
+		"/v1/shelves/1/books/2",
+		`{"book":{"title":"Dune"},"updateMask":"title"}`,
+	)
+	testRPCToHTTP(
+		t,
+		"acme.v1.BookService.ArchiveBook",
+		`{"name":"shelves/1/books/2"}`,
+		"/v1/{name=shelves/*/books/*}:archive",
+		"POST",
+		"/v1/shelves/1/books/2:archive",
+		`{}`,
+	)
+	testRPCToHTTP(
+		t,
+		"acme.v1.BookService.GetFile",
+		`{"path":"a/b c/d.txt"}`,
+		"/v1/files/{path=**}",
+		"GET",
+		"/v1/files/a/b%20c/d.txt",
+		"",
+	)
+}
+
+func TestRPCToHTTPError(t *testing.T) {
+	t.Parallel()
+	transcoder, image := newTestTranscoder(t)
+	newGetBookRequest := func(t *testing.T, requestJSON string) *dynamicpb.Message {
+		messageDescriptor, err := image.Resolver().FindDescriptorByName("acme.v1.GetBookRequest")
+		require.NoError(t, err)
+		requestMessage := dynamicpb.NewMessage(messageDescriptor.(protoreflect.MessageDescriptor))
+		require.NoError(t, protoencoding.NewJSONUnmarshaler(image.Resolver()).Unmarshal([]byte(requestJSON), requestMessage))
+		return requestMessage
+	}
+	_, _, err := transcoder.RPCToHTTP("acme.v1.BookService.GetBook", newGetBookRequest(t, `{}`))
+	require.ErrorIs(t, err, ErrNoMatchingBinding)
+	_, _, err = transcoder.RPCToHTTP("acme.v1.BookService.GetBook", newGetBookRequest(t, `{"name":"books/2"}`))
+	require.ErrorIs(t, err, ErrNoMatchingBinding)
+	require.ErrorContains(t, err, `value "books/2" of field name does not match shelves/*/books/*`)
+	_, _, err = transcoder.RPCToHTTP("acme.v1.BookService.Unannotated", newGetBookRequest(t, `{"name":"shelves/1/books/2"}`))
+	require.ErrorIs(t, err, ErrNoMatchingBinding)
+	_, _, err = transcoder.RPCToHTTP("acme.v1.BookService.Unknown", newGetBookRequest(t, `{}`))
+	require.EqualError(t, err, "method acme.v1.BookService.Unknown not found")
+	_, _, err = transcoder.RPCToHTTP("acme.v1.BookService.GetFile", newGetBookRequest(t, `{}`))
+	require.EqualError(t, err, "request message of type acme.v1.GetBookRequest is not of input type acme.v1.GetFileRequest of method acme.v1.BookService.GetFile")
+}
+
+func TestParsePathTemplate(t *testing.T) {
+	t.Parallel()
+	for _, invalid := range []string{
+		"v1/books",
+		"/v1//books",
+		"/v1/{name",
+		"/v1/{=books/*}",
+		"/v1/**/books",
+		"/v1/books:",
+		"/v1/a:b/c",
+		"/v1/{name=books/{id}}",
+	} {
+		_, err := parsePathTemplate(invalid)
+		assert.Error(t, err, invalid)
+	}
+	pathTemplate, err := parsePathTemplate("/v1/{name=shelves/*/books/*}:archive")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1", "shelves", "*", "books", "*"}, pathTemplate.segments)
+	assert.Equal(t, "archive", pathTemplate.verb)
+	require.Len(t, pathTemplate.variables, 1)
+	assert.Equal(t, []string{"name"}, pathTemplate.variables[0].fieldPath)
+	assert.Equal(t, 1, pathTemplate.variables[0].start)
+	assert.Equal(t, 5, pathTemplate.variables[0].end)
+	values, ok := pathTemplate.match("/v1/shelves/1/books/2:archive")
+	require.True(t, ok)
+	assert.Equal(t, []string{"shelves/1/books/2"}, values)
+	_, ok = pathTemplate.match("/v1/shelves/1/books/2")
+	assert.False(t, ok)
+	_, ok = pathTemplate.match("/v1/shelves//books/2:archive")
+	assert.False(t, ok)
+}
+
+func newTestTranscoder(t *testing.T) (Transcoder, bufimage.Image) {
+	moduleSet, err := bufmoduletesting.NewModuleSetForDirPath("testdata")
+	require.NoError(t, err)
+	image, err := bufimage.BuildImage(
+		context.Background(),
+		slogtestext.NewLogger(t),
+		bufmodule.ModuleSetToModuleReadBucketWithOnlyProtoFiles(moduleSet),
+	)
+	require.NoError(t, err)
+	transcoder, err := NewTranscoder(image)
+	require.NoError(t, err)
+	return transcoder, image
+}
+
+func TestEnvoyConfigForBindings(t *testing.T) {
+	t.Parallel()
+	transcoder, _ := newTestTranscoder(t)
+	data, err := EnvoyConfigForBindings(transcoder.Bindings(), "/etc/envoy/descriptor.binpb", "backend")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`http_filters:
+  - name: envoy.filters.http.grpc_json_transcoder
+    typed_config:
+      '@type': type.googleapis.com/envoy.extensions.filters.http.grpc_json_transcoder.v3.GrpcJsonTranscoder
+      proto_descriptor: /etc/envoy/descriptor.binpb
+      services:
+        - acme.v1.BookService
+route_config:
+  name: backend
+  virtual_hosts:
+    - name: backend
+      domains:
+        - '*'
+      routes:
+        - match:
+            prefix: /acme.v1.BookService/
+          route:
+            cluster: backend
+`,
+		string(data),
+	)
+	_, err = EnvoyConfigForBindings(nil, "descriptor.binpb", "backend")
+	require.EqualError(t, err, "no methods have google.api.http annotations")
+}
+
+func TestGRPCGatewayConfigForBindings(t *testing.T) {
+	t.Parallel()
+	transcoder, _ := newTestTranscoder(t)
+	data, err := GRPCGatewayConfigForBindings(transcoder.Bindings())
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`type: google.api.Service
+config_version: 3
+http:
+  rules:
+    - selector: acme.v1.BookService.GetBook
+      get: /v1/{name=shelves/*/books/*}
+      additional_bindings:
+        - get: /v1/books/{id}
+    - selector: acme.v1.BookService.ListBooks
+      get: /v1/{parent=shelves/*}/books
+    - selector: acme.v1.BookService.CreateBook
+      post: /v1/{parent=shelves/*}/books
+      body: book
+    - selector: acme.v1.BookService.UpdateBook
+      patch: /v1/{book.name=shelves/*/books/*}
+      body: '*'
+    - selector: acme.v1.BookService.ArchiveBook
+      post: /v1/{name=shelves/*/books/*}:archive
+      body: '*'
+    - selector: acme.v1.BookService.GetFile
+      get: /v1/files/{path=**}
+`,
+		string(data),
+	)
+}
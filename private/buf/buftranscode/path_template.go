@@ -0,0 +1,253 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buftranscode
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const (
+	pathTemplateSingleWildcard = "*"
+	pathTemplateDoubleWildcard = "**"
+)
+
+// pathTemplate is a parsed path template of a google.api.http rule.
+//
+//	Template = "/" Segments [ Verb ] ;
+//	Segments = Segment { "/" Segment } ;
+//	Segment  = "*" | "**" | LITERAL | Variable ;
+//	Variable = "{" FieldPath [ "=" Segments ] "}" ;
+//	FieldPath = IDENT { "." IDENT } ;
+//	Verb     = ":" LITERAL ;
+type pathTemplate struct {
+	// segments are the segments of the template, with the segments of
+	// variables inlined. Each segment is either "*", "**", or a literal.
+	segments  []string
+	variables []*pathVariable
+	// Empty if the template has no verb.
+	verb string
+}
+
+// pathVariable is a variable of a path template.
+type pathVariable struct {
+	fieldPath []string
+	// start is the index of the first segment of the variable in the template,
+	// and end is one past the index of the last segment of the variable.
+	start int
+	end   int
+}
+
+func parsePathTemplate(s string) (*pathTemplate, error) {
+	if !strings.HasPrefix(s, "/") {
+		return nil, fmt.Errorf("path template %q must start with /", s)
+	}
+	pathTemplate := &pathTemplate{}
+	rest := s[1:]
+	for {
+		if strings.HasPrefix(rest, "{") {
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return nil, fmt.Errorf("path template %q has an unterminated variable", s)
+			}
+			fieldPathString, pattern, ok := strings.Cut(rest[1:end], "=")
+			if !ok {
+				pattern = pathTemplateSingleWildcard
+			}
+			fieldPath := strings.Split(fieldPathString, ".")
+			for _, fieldName := range fieldPath {
+				if fieldName == "" {
+					return nil, fmt.Errorf("path template %q has an invalid variable field path %q", s, fieldPathString)
+				}
+			}
+			pathVariable := &pathVariable{
+				fieldPath: fieldPath,
+				start:     len(pathTemplate.segments),
+			}
+			for _, segment := range strings.Split(pattern, "/") {
+				if err := validatePathTemplateSegment(segment); err != nil {
+					return nil, fmt.Errorf("path template %q: %w", s, err)
+				}
+				pathTemplate.segments = append(pathTemplate.segments, segment)
+			}
+			pathVariable.end = len(pathTemplate.segments)
+			pathTemplate.variables = append(pathTemplate.variables, pathVariable)
+			rest = rest[end+1:]
+		} else {
+			end := strings.IndexAny(rest, "/:")
+			if end < 0 {
+				end = len(rest)
+			}
+			segment := rest[:end]
+			if err := validatePathTemplateSegment(segment); err != nil {
+				return nil, fmt.Errorf("path template %q: %w", s, err)
+			}
+			pathTemplate.segments = append(pathTemplate.segments, segment)
+			rest = rest[end:]
+		}
+		if rest == "" {
+			break
+		}
+		if rest[0] == ':' {
+			pathTemplate.verb = rest[1:]
+			if pathTemplate.verb == "" || strings.ContainsAny(pathTemplate.verb, "/{}*") {
+				return nil, fmt.Errorf("path template %q has an invalid verb %q", s, pathTemplate.verb)
+			}
+			break
+		}
+		if rest[0] != '/' {
+			return nil, fmt.Errorf("path template %q has an unexpected character %q", s, rest[0])
+		}
+		rest = rest[1:]
+	}
+	for i, segment := range pathTemplate.segments {
+		if segment == pathTemplateDoubleWildcard && i != len(pathTemplate.segments)-1 {
+			return nil, fmt.Errorf("path template %q may only have ** as the last segment", s)
+		}
+	}
+	return pathTemplate, nil
+}
+
+// match matches the escaped path against the template, and returns the
+// unescaped values of the variables, in the same order as the variables.
+//
+// Returns false if the path does not match.
+func (p *pathTemplate) match(escapedPath string) ([]string, bool) {
+	if !strings.HasPrefix(escapedPath, "/") {
+		return nil, false
+	}
+	escapedPath = escapedPath[1:]
+	if p.verb != "" {
+		var ok bool
+		escapedPath, ok = strings.CutSuffix(escapedPath, ":"+p.verb)
+		if !ok {
+			return nil, false
+		}
+	}
+	pathSegments := strings.Split(escapedPath, "/")
+	if !matchPathTemplateSegments(p.segments, pathSegments) {
+		return nil, false
+	}
+	values := make([]string, len(p.variables))
+	for i, pathVariable := range p.variables {
+		end := pathVariable.end
+		if end == len(p.segments) {
+			// The variable may contain a trailing **, which matches all remaining segments.
+			end = len(pathSegments)
+		}
+		value, err := url.PathUnescape(strings.Join(pathSegments[pathVariable.start:end], "/"))
+		if err != nil {
+			return nil, false
+		}
+		values[i] = value
+	}
+	return values, true
+}
+
+// expand returns the escaped path for the values of the variables, in the same
+// order as the variables.
+//
+// Returns an error if a value does not match the segments of its variable.
+func (p *pathTemplate) expand(values []string) (string, error) {
+	var builder strings.Builder
+	for i := 0; i < len(p.segments); {
+		builder.WriteString("/")
+		variableIndex := -1
+		for j, pathVariable := range p.variables {
+			if pathVariable.start == i {
+				variableIndex = j
+				break
+			}
+		}
+		if variableIndex < 0 {
+			segment := p.segments[i]
+			if segment == pathTemplateSingleWildcard || segment == pathTemplateDoubleWildcard {
+				return "", errors.New("path template has a wildcard that is not bound to a field")
+			}
+			builder.WriteString(segment)
+			i++
+			continue
+		}
+		pathVariable := p.variables[variableIndex]
+		value := values[variableIndex]
+		variableSegments := p.segments[pathVariable.start:pathVariable.end]
+		var valueSegments []string
+		if len(variableSegments) == 1 && variableSegments[0] == pathTemplateSingleWildcard {
+			// A value of a single segment variable may contain any character, including /.
+			valueSegments = []string{value}
+		} else {
+			valueSegments = strings.Split(value, "/")
+		}
+		if !matchPathTemplateSegments(variableSegments, valueSegments) {
+			return "", fmt.Errorf("value %q of field %s does not match %s", value, strings.Join(pathVariable.fieldPath, "."), strings.Join(variableSegments, "/"))
+		}
+		for j, valueSegment := range valueSegments {
+			if j > 0 {
+				builder.WriteString("/")
+			}
+			builder.WriteString(url.PathEscape(valueSegment))
+		}
+		i = pathVariable.end
+	}
+	if p.verb != "" {
+		builder.WriteString(":")
+		builder.WriteString(p.verb)
+	}
+	return builder.String(), nil
+}
+
+// isBoundFieldPath returns true if the field path is bound to a variable, or
+// is a prefix of or has a prefix that is bound to a variable.
+func (p *pathTemplate) isBoundFieldPath(fieldPath []string) bool {
+	for _, pathVariable := range p.variables {
+		n := min(len(fieldPath), len(pathVariable.fieldPath))
+		if slices.Equal(fieldPath[:n], pathVariable.fieldPath[:n]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPathTemplateSegments(templateSegments []string, pathSegments []string) bool {
+	for i, templateSegment := range templateSegments {
+		switch templateSegment {
+		case pathTemplateDoubleWildcard:
+			// ** is always the last segment, and matches zero or more segments.
+			return true
+		case pathTemplateSingleWildcard:
+			if i >= len(pathSegments) || pathSegments[i] == "" {
+				return false
+			}
+		default:
+			if i >= len(pathSegments) || pathSegments[i] != templateSegment {
+				return false
+			}
+		}
+	}
+	return len(templateSegments) == len(pathSegments)
+}
+
+func validatePathTemplateSegment(segment string) error {
+	if segment == "" {
+		return errors.New("empty segment")
+	}
+	if segment != pathTemplateSingleWildcard && segment != pathTemplateDoubleWildcard && strings.ContainsAny(segment, "*{}=") {
+		return fmt.Errorf("invalid segment %q", segment)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buftranscode
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/encoding"
+)
+
+const (
+	envoyTranscoderFilterName = "envoy.filters.http.grpc_json_transcoder"
+	envoyTranscoderFilterType = "type.googleapis.com/envoy.extensions.filters.http.grpc_json_transcoder.v3.GrpcJsonTranscoder"
+	grpcGatewayConfigType     = "google.api.Service"
+	grpcGatewayConfigVersion  = 3
+)
+
+func envoyConfigForBindings(bindings []Binding, descriptorSetPath string, clusterName string) ([]byte, error) {
+	if len(bindings) == 0 {
+		return nil, errors.New("no methods have google.api.http annotations")
+	}
+	var serviceNames []string
+	serviceNameToSeen := make(map[string]struct{})
+	for _, binding := range bindings {
+		serviceName := string(binding.Method().Parent().FullName())
+		if _, ok := serviceNameToSeen[serviceName]; ok {
+			continue
+		}
+		serviceNameToSeen[serviceName] = struct{}{}
+		serviceNames = append(serviceNames, serviceName)
+	}
+	externalRoutes := make([]externalEnvoyRoute, len(serviceNames))
+	for i, serviceName := range serviceNames {
+		// The transcoder filter rewrites the path of transcoded requests to the
+		// path of the gRPC method, so routes match the gRPC path.
+		externalRoutes[i] = externalEnvoyRoute{
+			Match: externalEnvoyRouteMatch{
+				Prefix: "/" + serviceName + "/",
+			},
+			Route: externalEnvoyRouteAction{
+				Cluster: clusterName,
+			},
+		}
+	}
+	return encoding.MarshalYAML(
+		&externalEnvoyConfig{
+			HTTPFilters: []externalEnvoyHTTPFilter{
+				{
+					Name: envoyTranscoderFilterName,
+					TypedConfig: externalEnvoyTranscoderConfig{
+						Type:            envoyTranscoderFilterType,
+						ProtoDescriptor: descriptorSetPath,
+						Services:        serviceNames,
+					},
+				},
+			},
+			RouteConfig: externalEnvoyRouteConfig{
+				Name: clusterName,
+				VirtualHosts: []externalEnvoyVirtualHost{
+					{
+						Name:    clusterName,
+						Domains: []string{"*"},
+						Routes:  externalRoutes,
+					},
+				},
+			},
+		},
+	)
+}
+
+func grpcGatewayConfigForBindings(bindings []Binding) ([]byte, error) {
+	if len(bindings) == 0 {
+		return nil, errors.New("no methods have google.api.http annotations")
+	}
+	var externalRules []*externalGRPCGatewayRule
+	for _, binding := range bindings {
+		externalRule := newExternalGRPCGatewayRule(binding)
+		if binding.IsAdditional() {
+			// Additional bindings always follow the binding they are declared on.
+			primaryExternalRule := externalRules[len(externalRules)-1]
+			primaryExternalRule.AdditionalBindings = append(primaryExternalRule.AdditionalBindings, externalRule)
+			continue
+		}
+		externalRule.Selector = string(binding.Method().FullName())
+		externalRules = append(externalRules, externalRule)
+	}
+	return encoding.MarshalYAML(
+		&externalGRPCGatewayConfig{
+			Type:          grpcGatewayConfigType,
+			ConfigVersion: grpcGatewayConfigVersion,
+			HTTP: externalGRPCGatewayHTTP{
+				Rules: externalRules,
+			},
+		},
+	)
+}
+
+type externalEnvoyConfig struct {
+	HTTPFilters []externalEnvoyHTTPFilter `yaml:"http_filters"`
+	RouteConfig externalEnvoyRouteConfig  `yaml:"route_config"`
+}
+
+type externalEnvoyHTTPFilter struct {
+	Name        string                        `yaml:"name"`
+	TypedConfig externalEnvoyTranscoderConfig `yaml:"typed_config"`
+}
+
+type externalEnvoyTranscoderConfig struct {
+	Type            string   `yaml:"@type"`
+	ProtoDescriptor string   `yaml:"proto_descriptor"`
+	Services        []string `yaml:"services"`
+}
+
+type externalEnvoyRouteConfig struct {
+	Name         string                     `yaml:"name"`
+	VirtualHosts []externalEnvoyVirtualHost `yaml:"virtual_hosts"`
+}
+
+type externalEnvoyVirtualHost struct {
+	Name    string               `yaml:"name"`
+	Domains []string             `yaml:"domains"`
+	Routes  []externalEnvoyRoute `yaml:"routes"`
+}
+
+type externalEnvoyRoute struct {
+	Match externalEnvoyRouteMatch  `yaml:"match"`
+	Route externalEnvoyRouteAction `yaml:"route"`
+}
+
+type externalEnvoyRouteMatch struct {
+	Prefix string `yaml:"prefix"`
+}
+
+type externalEnvoyRouteAction struct {
+	Cluster string `yaml:"cluster"`
+}
+
+type externalGRPCGatewayConfig struct {
+	Type          string                  `yaml:"type"`
+	ConfigVersion int                     `yaml:"config_version"`
+	HTTP          externalGRPCGatewayHTTP `yaml:"http"`
+}
+
+type externalGRPCGatewayHTTP struct {
+	Rules []*externalGRPCGatewayRule `yaml:"rules"`
+}
+
+type externalGRPCGatewayRule struct {
+	// Not set for additional bindings.
+	Selector           string                     `yaml:"selector,omitempty"`
+	Get                string                     `yaml:"get,omitempty"`
+	Put                string                     `yaml:"put,omitempty"`
+	Post               string                     `yaml:"post,omitempty"`
+	Delete             string                     `yaml:"delete,omitempty"`
+	Patch              string                     `yaml:"patch,omitempty"`
+	Custom             *externalGRPCGatewayCustom `yaml:"custom,omitempty"`
+	Body               string                     `yaml:"body,omitempty"`
+	ResponseBody       string                     `yaml:"response_body,omitempty"`
+	AdditionalBindings []*externalGRPCGatewayRule `yaml:"additional_bindings,omitempty"`
+}
+
+type externalGRPCGatewayCustom struct {
+	Kind string `yaml:"kind"`
+	Path string `yaml:"path"`
+}
+
+func newExternalGRPCGatewayRule(binding Binding) *externalGRPCGatewayRule {
+	externalRule := &externalGRPCGatewayRule{
+		Body:         binding.Body(),
+		ResponseBody: binding.ResponseBody(),
+	}
+	pathTemplate := binding.PathTemplate()
+	switch httpMethod := binding.HTTPMethod(); strings.ToUpper(httpMethod) {
+	case "GET":
+		externalRule.Get = pathTemplate
+	case "PUT":
+		externalRule.Put = pathTemplate
+	case "POST":
+		externalRule.Post = pathTemplate
+	case "DELETE":
+		externalRule.Delete = pathTemplate
+	case "PATCH":
+		externalRule.Patch = pathTemplate
+	default:
+		externalRule.Custom = &externalGRPCGatewayCustom{
+			Kind: httpMethod,
+			Path: pathTemplate,
+		}
+	}
+	return externalRule
+}
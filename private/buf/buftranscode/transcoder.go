@@ -0,0 +1,664 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buftranscode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const bodyAllFields = "*"
+
+type transcoder struct {
+	resolver                 protoencoding.Resolver
+	bindings                 []*binding
+	methodFullNameToBindings map[protoreflect.FullName][]*binding
+}
+
+func newTranscoder(image bufimage.Image) (*transcoder, error) {
+	transcoder := &transcoder{
+		resolver:                 image.Resolver(),
+		methodFullNameToBindings: make(map[protoreflect.FullName][]*binding),
+	}
+	for _, imageFile := range image.Files() {
+		fileDescriptor, err := transcoder.resolver.FindFileByPath(imageFile.Path())
+		if err != nil {
+			return nil, err
+		}
+		services := fileDescriptor.Services()
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				method := methods.Get(j)
+				bindings, err := getBindingsForMethod(method)
+				if err != nil {
+					return nil, err
+				}
+				transcoder.bindings = append(transcoder.bindings, bindings...)
+				transcoder.methodFullNameToBindings[method.FullName()] = bindings
+			}
+		}
+	}
+	return transcoder, nil
+}
+
+func (t *transcoder) Bindings() []Binding {
+	bindings := make([]Binding, len(t.bindings))
+	for i, binding := range t.bindings {
+		bindings[i] = binding
+	}
+	return bindings
+}
+
+func (t *transcoder) HTTPToRPC(httpRequest *HTTPRequest) (Binding, proto.Message, error) {
+	requestURL, err := url.ParseRequestURI(httpRequest.Target)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid request target %q: %w", httpRequest.Target, err)
+	}
+	query, err := url.ParseQuery(requestURL.RawQuery)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid query string %q: %w", requestURL.RawQuery, err)
+	}
+	for _, binding := range t.bindings {
+		if !strings.EqualFold(binding.httpMethod, httpRequest.Method) {
+			continue
+		}
+		values, ok := binding.pathTemplate.match(requestURL.EscapedPath())
+		if !ok {
+			continue
+		}
+		requestMessage, err := t.getRequestMessage(binding, values, query, httpRequest.Body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s %s: %w", binding.httpMethod, binding.pathTemplateString, err)
+		}
+		return binding, requestMessage, nil
+	}
+	return nil, nil, fmt.Errorf("%w for %s %s", ErrNoMatchingBinding, strings.ToUpper(httpRequest.Method), requestURL.EscapedPath())
+}
+
+func (t *transcoder) RPCToHTTP(methodFullName protoreflect.FullName, requestMessage proto.Message) (Binding, *HTTPRequest, error) {
+	bindings, ok := t.methodFullNameToBindings[methodFullName]
+	if !ok {
+		return nil, nil, fmt.Errorf("method %s not found", methodFullName)
+	}
+	if len(bindings) == 0 {
+		return nil, nil, fmt.Errorf("%w for %s: method has no google.api.http annotation", ErrNoMatchingBinding, methodFullName)
+	}
+	if requestMessage.ProtoReflect().Descriptor().FullName() != bindings[0].method.Input().FullName() {
+		return nil, nil, fmt.Errorf(
+			"request message of type %s is not of input type %s of method %s",
+			requestMessage.ProtoReflect().Descriptor().FullName(),
+			bindings[0].method.Input().FullName(),
+			methodFullName,
+		)
+	}
+	var bindingErrs []error
+	for _, binding := range bindings {
+		httpRequest, err := t.getHTTPRequest(binding, requestMessage.ProtoReflect())
+		if err != nil {
+			bindingErrs = append(bindingErrs, fmt.Errorf("%s %s: %w", binding.httpMethod, binding.pathTemplateString, err))
+			continue
+		}
+		return binding, httpRequest, nil
+	}
+	return nil, nil, fmt.Errorf("%w for %s: %w", ErrNoMatchingBinding, methodFullName, errors.Join(bindingErrs...))
+}
+
+func (*transcoder) isTranscoder() {}
+
+func (t *transcoder) getRequestMessage(
+	binding *binding,
+	values []string,
+	query url.Values,
+	body []byte,
+) (proto.Message, error) {
+	requestMessage := dynamicpb.NewMessage(binding.method.Input())
+	if len(bytes.TrimSpace(body)) > 0 {
+		if binding.body == "" {
+			return nil, errors.New("binding has no body, but the request has a body")
+		}
+		if err := t.unmarshalBody(binding, body, requestMessage); err != nil {
+			return nil, fmt.Errorf("invalid body: %w", err)
+		}
+	}
+	for i, pathVariable := range binding.pathTemplate.variables {
+		if err := setFieldPathValue(requestMessage, pathVariable.fieldPath, values[i], false); err != nil {
+			return nil, fmt.Errorf("path variable %s: %w", strings.Join(pathVariable.fieldPath, "."), err)
+		}
+	}
+	if len(query) > 0 && binding.body == bodyAllFields {
+		return nil, errors.New("query parameters cannot be used when all fields are mapped to the body")
+	}
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		fieldPath := strings.Split(key, ".")
+		if binding.pathTemplate.isBoundFieldPath(fieldPath) {
+			return nil, fmt.Errorf("query parameter %q is bound by the path", key)
+		}
+		if binding.body != "" && fieldPath[0] == binding.body {
+			return nil, fmt.Errorf("query parameter %q is mapped to the body", key)
+		}
+		for _, value := range query[key] {
+			if err := setFieldPathValue(requestMessage, fieldPath, value, true); err != nil {
+				return nil, fmt.Errorf("query parameter %q: %w", key, err)
+			}
+		}
+	}
+	return requestMessage, nil
+}
+
+func (t *transcoder) unmarshalBody(binding *binding, body []byte, requestMessage *dynamicpb.Message) error {
+	jsonUnmarshaler := protoencoding.NewJSONUnmarshaler(t.resolver, protoencoding.JSONUnmarshalerWithDisallowUnknown())
+	if binding.body == bodyAllFields {
+		return jsonUnmarshaler.Unmarshal(body, requestMessage)
+	}
+	// The body is the value of a single field. Wrap it in an object with that
+	// field so that it is unmarshaled with the JSON mapping of the field.
+	fieldDescriptor := binding.method.Input().Fields().ByName(protoreflect.Name(binding.body))
+	wrappedBody, err := json.Marshal(map[string]json.RawMessage{fieldDescriptor.JSONName(): body})
+	if err != nil {
+		return err
+	}
+	return jsonUnmarshaler.Unmarshal(wrappedBody, requestMessage)
+}
+
+func (t *transcoder) getHTTPRequest(binding *binding, requestMessage protoreflect.Message) (*HTTPRequest, error) {
+	values := make([]string, len(binding.pathTemplate.variables))
+	for i, pathVariable := range binding.pathTemplate.variables {
+		value, err := getFieldPathValue(requestMessage, pathVariable.fieldPath)
+		if err != nil {
+			return nil, fmt.Errorf("path variable %s: %w", strings.Join(pathVariable.fieldPath, "."), err)
+		}
+		values[i] = value
+	}
+	target, err := binding.pathTemplate.expand(values)
+	if err != nil {
+		return nil, err
+	}
+	// The fields that are not bound by the path are either in the body or in the query.
+	unboundMessage := proto.Clone(requestMessage.Interface()).ProtoReflect()
+	for _, pathVariable := range binding.pathTemplate.variables {
+		clearFieldPath(unboundMessage, pathVariable.fieldPath)
+	}
+	httpRequest := &HTTPRequest{
+		Method: binding.httpMethod,
+	}
+	switch binding.body {
+	case "":
+	case bodyAllFields:
+		httpRequest.Body, err = protoencoding.NewJSONMarshaler(t.resolver).Marshal(unboundMessage.Interface())
+		if err != nil {
+			return nil, err
+		}
+		// All fields are in the body, so there is no query.
+		httpRequest.Target = target
+		return httpRequest, nil
+	default:
+		fieldDescriptor := binding.method.Input().Fields().ByName(protoreflect.Name(binding.body))
+		httpRequest.Body, err = t.marshalField(requestMessage, fieldDescriptor)
+		if err != nil {
+			return nil, err
+		}
+		unboundMessage.Clear(fieldDescriptor)
+	}
+	query, err := t.getQuery(unboundMessage)
+	if err != nil {
+		return nil, err
+	}
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	httpRequest.Target = target
+	return httpRequest, nil
+}
+
+// marshalField marshals the value of the field of the message to JSON.
+func (t *transcoder) marshalField(message protoreflect.Message, fieldDescriptor protoreflect.FieldDescriptor) ([]byte, error) {
+	jsonMarshaler := protoencoding.NewJSONMarshaler(t.resolver)
+	if fieldDescriptor.Message() != nil && !fieldDescriptor.IsList() && !fieldDescriptor.IsMap() {
+		return jsonMarshaler.Marshal(message.Get(fieldDescriptor).Message().Interface())
+	}
+	if !message.Has(fieldDescriptor) {
+		return nil, nil
+	}
+	// Marshal a message with only the field set, and extract the value of the field.
+	fieldMessage := message.New()
+	fieldMessage.Set(fieldDescriptor, message.Get(fieldDescriptor))
+	data, err := jsonMarshaler.Marshal(fieldMessage.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	return object[fieldDescriptor.JSONName()], nil
+}
+
+// getQuery returns the query parameters for the set fields of the message.
+//
+// The names of query parameters are the dot-separated paths of the fields.
+func (t *transcoder) getQuery(message protoreflect.Message) (url.Values, error) {
+	data, err := protoencoding.NewJSONMarshaler(t.resolver, protoencoding.JSONMarshalerWithUseProtoNames()).Marshal(message.Interface())
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	query := make(url.Values)
+	if err := addQueryValues(query, "", object); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+func addQueryValues(query url.Values, key string, value any) error {
+	switch value := value.(type) {
+	case map[string]any:
+		for childKey, childValue := range value {
+			if key != "" {
+				childKey = key + "." + childKey
+			}
+			if err := addQueryValues(query, childKey, childValue); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, element := range value {
+			switch element.(type) {
+			case map[string]any, []any:
+				return fmt.Errorf("field %s cannot be mapped to query parameters, as it is a list of messages", key)
+			}
+			if err := addQueryValues(query, key, element); err != nil {
+				return err
+			}
+		}
+	case string:
+		query.Add(key, value)
+	case json.Number:
+		query.Add(key, value.String())
+	case bool:
+		query.Add(key, strconv.FormatBool(value))
+	case nil:
+	default:
+		return fmt.Errorf("unexpected JSON value of type %T for field %s", value, key)
+	}
+	return nil
+}
+
+type binding struct {
+	method             protoreflect.MethodDescriptor
+	httpMethod         string
+	pathTemplateString string
+	pathTemplate       *pathTemplate
+	body               string
+	responseBody       string
+	isAdditional       bool
+}
+
+func newBinding(method protoreflect.MethodDescriptor, httpRule *annotations.HttpRule, isAdditional bool) (*binding, error) {
+	var httpMethod string
+	var pathTemplateString string
+	switch pattern := httpRule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		httpMethod, pathTemplateString = "GET", pattern.Get
+	case *annotations.HttpRule_Put:
+		httpMethod, pathTemplateString = "PUT", pattern.Put
+	case *annotations.HttpRule_Post:
+		httpMethod, pathTemplateString = "POST", pattern.Post
+	case *annotations.HttpRule_Delete:
+		httpMethod, pathTemplateString = "DELETE", pattern.Delete
+	case *annotations.HttpRule_Patch:
+		httpMethod, pathTemplateString = "PATCH", pattern.Patch
+	case *annotations.HttpRule_Custom:
+		httpMethod, pathTemplateString = pattern.Custom.GetKind(), pattern.Custom.GetPath()
+	default:
+		return nil, fmt.Errorf("method %s: google.api.http rule has no pattern", method.FullName())
+	}
+	pathTemplate, err := parsePathTemplate(pathTemplateString)
+	if err != nil {
+		return nil, fmt.Errorf("method %s: %w", method.FullName(), err)
+	}
+	for _, pathVariable := range pathTemplate.variables {
+		fieldDescriptor, err := getFieldDescriptorForPath(method.Input(), pathVariable.fieldPath)
+		if err != nil {
+			return nil, fmt.Errorf("method %s: path template %q: %w", method.FullName(), pathTemplateString, err)
+		}
+		if fieldDescriptor.IsList() || fieldDescriptor.IsMap() {
+			return nil, fmt.Errorf("method %s: path template %q: field %s cannot be repeated", method.FullName(), pathTemplateString, fieldDescriptor.Name())
+		}
+	}
+	body := httpRule.GetBody()
+	if body != "" && body != bodyAllFields && method.Input().Fields().ByName(protoreflect.Name(body)) == nil {
+		return nil, fmt.Errorf("method %s: body field %q not found in %s", method.FullName(), body, method.Input().FullName())
+	}
+	return &binding{
+		method:             method,
+		httpMethod:         httpMethod,
+		pathTemplateString: pathTemplateString,
+		pathTemplate:       pathTemplate,
+		body:               body,
+		responseBody:       httpRule.GetResponseBody(),
+		isAdditional:       isAdditional,
+	}, nil
+}
+
+func (b *binding) Method() protoreflect.MethodDescriptor {
+	return b.method
+}
+
+func (b *binding) HTTPMethod() string {
+	return b.httpMethod
+}
+
+func (b *binding) PathTemplate() string {
+	return b.pathTemplateString
+}
+
+func (b *binding) Body() string {
+	return b.body
+}
+
+func (b *binding) ResponseBody() string {
+	return b.responseBody
+}
+
+func (b *binding) IsAdditional() bool {
+	return b.isAdditional
+}
+
+func (*binding) isBinding() {}
+
+// getBindingsForMethod returns the bindings of the google.api.http annotation of
+// the method, or nil if the method has no annotation.
+func getBindingsForMethod(method protoreflect.MethodDescriptor) ([]*binding, error) {
+	methodOptions, ok := method.Options().(*descriptorpb.MethodOptions)
+	if !ok || methodOptions == nil {
+		return nil, nil
+	}
+	// The options of descriptors from an Image do not use the generated type of
+	// the google.api.http extension, so we re-parse them to get an HttpRule.
+	data, err := proto.Marshal(methodOptions)
+	if err != nil {
+		return nil, err
+	}
+	reparsedMethodOptions := &descriptorpb.MethodOptions{}
+	if err := proto.Unmarshal(data, reparsedMethodOptions); err != nil {
+		return nil, err
+	}
+	if !proto.HasExtension(reparsedMethodOptions, annotations.E_Http) {
+		return nil, nil
+	}
+	httpRule, ok := proto.GetExtension(reparsedMethodOptions, annotations.E_Http).(*annotations.HttpRule)
+	if !ok {
+		return nil, fmt.Errorf("method %s: unexpected type for google.api.http option", method.FullName())
+	}
+	// Additional bindings may not have additional bindings themselves, so these are ignored.
+	httpRules := append([]*annotations.HttpRule{httpRule}, httpRule.GetAdditionalBindings()...)
+	bindings := make([]*binding, len(httpRules))
+	for i, httpRule := range httpRules {
+		binding, err := newBinding(method, httpRule, i > 0)
+		if err != nil {
+			return nil, err
+		}
+		bindings[i] = binding
+	}
+	return bindings, nil
+}
+
+// getFieldDescriptorForPath returns the descriptor of the last field in the field path.
+//
+// All fields but the last must be singular message fields.
+func getFieldDescriptorForPath(messageDescriptor protoreflect.MessageDescriptor, fieldPath []string) (protoreflect.FieldDescriptor, error) {
+	var fieldDescriptor protoreflect.FieldDescriptor
+	for i, fieldName := range fieldPath {
+		if i > 0 {
+			if fieldDescriptor.Message() == nil || fieldDescriptor.IsList() || fieldDescriptor.IsMap() {
+				return nil, fmt.Errorf("field %s is not a singular message field", fieldDescriptor.Name())
+			}
+			messageDescriptor = fieldDescriptor.Message()
+		}
+		fieldDescriptor = messageDescriptor.Fields().ByName(protoreflect.Name(fieldName))
+		if fieldDescriptor == nil {
+			fieldDescriptor = messageDescriptor.Fields().ByJSONName(fieldName)
+		}
+		if fieldDescriptor == nil {
+			return nil, fmt.Errorf("field %q not found in %s", fieldName, messageDescriptor.FullName())
+		}
+	}
+	return fieldDescriptor, nil
+}
+
+// setFieldPathValue parses the value and sets it on the field at the field path
+// of the message, creating intermediate messages as needed.
+//
+// If allowList is true and the field is repeated, the value is appended.
+func setFieldPathValue(message protoreflect.Message, fieldPath []string, value string, allowList bool) error {
+	fieldDescriptor, err := getFieldDescriptorForPath(message.Descriptor(), fieldPath)
+	if err != nil {
+		return err
+	}
+	for _, fieldName := range fieldPath[:len(fieldPath)-1] {
+		parentFieldDescriptor := message.Descriptor().Fields().ByName(protoreflect.Name(fieldName))
+		if parentFieldDescriptor == nil {
+			parentFieldDescriptor = message.Descriptor().Fields().ByJSONName(fieldName)
+		}
+		message = message.Mutable(parentFieldDescriptor).Message()
+	}
+	if fieldDescriptor.IsMap() || (fieldDescriptor.IsList() && !allowList) {
+		return fmt.Errorf("field %s cannot be set from a string", fieldDescriptor.Name())
+	}
+	protoValue, err := parseValue(message, fieldDescriptor, value)
+	if err != nil {
+		return fmt.Errorf("invalid value %q for field %s: %w", value, fieldDescriptor.Name(), err)
+	}
+	if fieldDescriptor.IsList() {
+		message.Mutable(fieldDescriptor).List().Append(protoValue)
+		return nil
+	}
+	message.Set(fieldDescriptor, protoValue)
+	return nil
+}
+
+// getFieldPathValue returns the formatted value of the field at the field path
+// of the message.
+//
+// Returns an error if the field is not set.
+func getFieldPathValue(message protoreflect.Message, fieldPath []string) (string, error) {
+	fieldDescriptor, err := getFieldDescriptorForPath(message.Descriptor(), fieldPath)
+	if err != nil {
+		return "", err
+	}
+	for _, fieldName := range fieldPath[:len(fieldPath)-1] {
+		parentFieldDescriptor := message.Descriptor().Fields().ByName(protoreflect.Name(fieldName))
+		if !message.Has(parentFieldDescriptor) {
+			return "", errors.New("field is not set")
+		}
+		message = message.Get(parentFieldDescriptor).Message()
+	}
+	if !message.Has(fieldDescriptor) {
+		return "", errors.New("field is not set")
+	}
+	return formatValue(fieldDescriptor, message.Get(fieldDescriptor))
+}
+
+// clearFieldPath clears the field at the field path of the message, and clears
+// any intermediate messages that are empty as a result.
+func clearFieldPath(message protoreflect.Message, fieldPath []string) {
+	fieldDescriptor := message.Descriptor().Fields().ByName(protoreflect.Name(fieldPath[0]))
+	if fieldDescriptor == nil || !message.Has(fieldDescriptor) {
+		return
+	}
+	if len(fieldPath) == 1 {
+		message.Clear(fieldDescriptor)
+		return
+	}
+	childMessage := message.Get(fieldDescriptor).Message()
+	clearFieldPath(childMessage, fieldPath[1:])
+	isEmpty := true
+	childMessage.Range(func(protoreflect.FieldDescriptor, protoreflect.Value) bool {
+		isEmpty = false
+		return false
+	})
+	if isEmpty {
+		message.Clear(fieldDescriptor)
+	}
+}
+
+// parseValue parses a path variable or query parameter value for a field of the message.
+func parseValue(message protoreflect.Message, fieldDescriptor protoreflect.FieldDescriptor, value string) (protoreflect.Value, error) {
+	switch fieldDescriptor.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(value), nil
+	case protoreflect.BytesKind:
+		for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+			if data, err := encoding.DecodeString(value); err == nil {
+				return protoreflect.ValueOfBytes(data), nil
+			}
+		}
+		return protoreflect.Value{}, errors.New("invalid base64")
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfBool(b), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		i, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfInt32(int32(i)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfInt64(i), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		u, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfUint32(uint32(u)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		u, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfUint64(u), nil
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfFloat32(float32(f)), nil
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfFloat64(f), nil
+	case protoreflect.EnumKind:
+		if enumValue := fieldDescriptor.Enum().Values().ByName(protoreflect.Name(value)); enumValue != nil {
+			return protoreflect.ValueOfEnum(enumValue.Number()), nil
+		}
+		i, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("unknown value for enum %s", fieldDescriptor.Enum().FullName())
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(i)), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		// Messages such as the well-known types google.protobuf.Timestamp and
+		// google.protobuf.FieldMask have a JSON string representation.
+		var fieldMessage protoreflect.Message
+		if fieldDescriptor.IsList() {
+			fieldMessage = message.NewField(fieldDescriptor).List().NewElement().Message()
+		} else {
+			fieldMessage = message.NewField(fieldDescriptor).Message()
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		if err := protoencoding.NewJSONUnmarshaler(nil).Unmarshal(data, fieldMessage.Interface()); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(fieldMessage), nil
+	default:
+		return protoreflect.Value{}, fmt.Errorf("unsupported field kind %v", fieldDescriptor.Kind())
+	}
+}
+
+// formatValue formats the value of a singular field as a path variable value.
+func formatValue(fieldDescriptor protoreflect.FieldDescriptor, value protoreflect.Value) (string, error) {
+	switch fieldDescriptor.Kind() {
+	case protoreflect.StringKind:
+		return value.String(), nil
+	case protoreflect.BytesKind:
+		return base64.URLEncoding.EncodeToString(value.Bytes()), nil
+	case protoreflect.BoolKind:
+		return strconv.FormatBool(value.Bool()), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 32), nil
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64), nil
+	case protoreflect.EnumKind:
+		if enumValue := fieldDescriptor.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name()), nil
+		}
+		return strconv.FormatInt(int64(value.Enum()), 10), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		data, err := protoencoding.NewJSONMarshaler(nil).Marshal(value.Message().Interface())
+		if err != nil {
+			return "", err
+		}
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return "", fmt.Errorf("message %s does not have a JSON string representation", fieldDescriptor.Message().FullName())
+		}
+		return s, nil
+	default:
+		return "", fmt.Errorf("unsupported field kind %v", fieldDescriptor.Kind())
+	}
+}
@@ -0,0 +1,459 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufui implements a local web UI that renders the package and type graph of a
+// workspace, the details of its types, and its lint and breaking findings.
+package bufui
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	// CheckBuild is the check for findings produced when the workspace fails to build.
+	CheckBuild = "build"
+	// CheckLint is the check for lint findings.
+	CheckLint = "lint"
+	// CheckBreaking is the check for breaking change findings.
+	CheckBreaking = "breaking"
+
+	// KindMessage is the kind of a Type that is a message.
+	KindMessage = "message"
+	// KindEnum is the kind of a Type that is an enum.
+	KindEnum = "enum"
+	// KindService is the kind of a Type that is a service.
+	KindService = "service"
+
+	// IndexPath is the path of the HTTP endpoint that serves the UI.
+	IndexPath = "/"
+	// SnapshotPath is the path of the HTTP endpoint that serves the current Snapshot as JSON.
+	SnapshotPath = "/api/snapshot"
+)
+
+// Snapshot is the state of a workspace rendered by the UI.
+type Snapshot struct {
+	// Packages are the packages of the target files of the workspace, sorted by name.
+	Packages []*Package `json:"packages"`
+	// Findings are the findings for the workspace, in the order they were added.
+	Findings []*Finding `json:"findings,omitempty"`
+}
+
+// Package is a package.
+type Package struct {
+	// Name is the name of the package.
+	//
+	// Empty for files without a package.
+	Name string `json:"name"`
+	// Files are the paths of the target files of the package, sorted.
+	Files []string `json:"files"`
+	// Dependencies are the names of the packages imported by the files of the package,
+	// sorted, not including the package itself.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// Types are the types declared in the package, including nested types, sorted by name.
+	Types []*Type `json:"types,omitempty"`
+}
+
+// Type is a message, enum, or service.
+type Type struct {
+	// Name is the fully-qualified name of the type, without a leading dot.
+	Name string `json:"name"`
+	// Kind is one of KindMessage, KindEnum, or KindService.
+	Kind string `json:"kind"`
+	// Path is the path of the file that declares the type.
+	Path string `json:"path"`
+	// StartLine is the 1-indexed line that the declaration of the type starts on.
+	//
+	// Zero if source code info is not available.
+	StartLine int `json:"start_line,omitempty"`
+	// EndLine is the 1-indexed line that the declaration of the type ends on.
+	//
+	// Zero if source code info is not available.
+	EndLine int `json:"end_line,omitempty"`
+	// Members are the fields of a message, the values of an enum, or the methods of a service.
+	Members []*Member `json:"members,omitempty"`
+	// References are the fully-qualified names of the types referenced by the members,
+	// sorted and deduplicated.
+	References []string `json:"references,omitempty"`
+}
+
+// Member is a field of a message, a value of an enum, or a method of a service.
+type Member struct {
+	// Name is the name of the member.
+	Name string `json:"name"`
+	// Number is the field or value number.
+	//
+	// Zero for methods.
+	Number int32 `json:"number,omitempty"`
+	// Type is the rendered type of a field, such as "repeated string" or
+	// "map<string, acme.v1.Foo>", or the rendered signature of a method, such
+	// as "(acme.v1.GetFooRequest) returns (stream acme.v1.GetFooResponse)".
+	//
+	// Empty for enum values.
+	Type string `json:"type,omitempty"`
+}
+
+// Finding is a finding for the workspace, such as a lint violation.
+type Finding struct {
+	// Check is one of CheckBuild, CheckLint, or CheckBreaking.
+	Check       string `json:"check"`
+	Path        string `json:"path,omitempty"`
+	StartLine   int    `json:"start_line,omitempty"`
+	StartColumn int    `json:"start_column,omitempty"`
+	EndLine     int    `json:"end_line,omitempty"`
+	EndColumn   int    `json:"end_column,omitempty"`
+	Type        string `json:"type"`
+	Message     string `json:"message"`
+	Severity    string `json:"severity"`
+	// TypeName is the fully-qualified name of the innermost Type whose declaration contains
+	// the start of the finding.
+	//
+	// Empty if the finding is not within the declaration of a Type.
+	TypeName string `json:"type_name,omitempty"`
+}
+
+// Snapshotter takes Snapshots.
+type Snapshotter interface {
+	// Snapshot takes a Snapshot of the current state of the workspace.
+	Snapshot(ctx context.Context) (*Snapshot, error)
+}
+
+// SnapshotterFunc is a function that implements Snapshotter.
+type SnapshotterFunc func(ctx context.Context) (*Snapshot, error)
+
+// Snapshot implements Snapshotter.
+func (s SnapshotterFunc) Snapshot(ctx context.Context) (*Snapshot, error) {
+	return s(ctx)
+}
+
+// NewSnapshot returns a new Snapshot for the Images.
+//
+// Packages and Types are only computed for the files that are not imports. A file
+// that is a target in one Image and an import in another is a target.
+func NewSnapshot(images ...bufimage.Image) *Snapshot {
+	pathToPackageName := make(map[string]string)
+	var targetImageFiles []bufimage.ImageFile
+	targetPaths := make(map[string]struct{})
+	for _, image := range images {
+		for _, imageFile := range image.Files() {
+			pathToPackageName[imageFile.Path()] = imageFile.FileDescriptorProto().GetPackage()
+			if imageFile.IsImport() {
+				continue
+			}
+			if _, ok := targetPaths[imageFile.Path()]; ok {
+				continue
+			}
+			targetPaths[imageFile.Path()] = struct{}{}
+			targetImageFiles = append(targetImageFiles, imageFile)
+		}
+	}
+	nameToPackage := make(map[string]*Package)
+	for _, imageFile := range targetImageFiles {
+		fileDescriptorProto := imageFile.FileDescriptorProto()
+		packageName := fileDescriptorProto.GetPackage()
+		pkg, ok := nameToPackage[packageName]
+		if !ok {
+			pkg = &Package{
+				Name: packageName,
+			}
+			nameToPackage[packageName] = pkg
+		}
+		pkg.Files = append(pkg.Files, imageFile.Path())
+		for _, dependency := range fileDescriptorProto.GetDependency() {
+			if dependencyPackageName, ok := pathToPackageName[dependency]; ok && dependencyPackageName != packageName {
+				pkg.Dependencies = append(pkg.Dependencies, dependencyPackageName)
+			}
+		}
+		pkg.Types = append(pkg.Types, getTypes(imageFile.Path(), fileDescriptorProto)...)
+	}
+	snapshot := &Snapshot{
+		Packages: make([]*Package, 0, len(nameToPackage)),
+	}
+	for _, pkg := range nameToPackage {
+		slices.Sort(pkg.Files)
+		slices.Sort(pkg.Dependencies)
+		pkg.Dependencies = slices.Compact(pkg.Dependencies)
+		slices.SortFunc(pkg.Types, func(a *Type, b *Type) int { return strings.Compare(a.Name, b.Name) })
+		snapshot.Packages = append(snapshot.Packages, pkg)
+	}
+	slices.SortFunc(snapshot.Packages, func(a *Package, b *Package) int { return strings.Compare(a.Name, b.Name) })
+	return snapshot
+}
+
+// AddFindings adds a Finding for each FileAnnotation produced by the check.
+//
+// Each Finding is associated with the innermost Type of the Snapshot whose declaration
+// contains the start of the FileAnnotation, if any.
+func (s *Snapshot) AddFindings(check string, fileAnnotations []bufanalysis.FileAnnotation) {
+	for _, fileAnnotation := range fileAnnotations {
+		finding := &Finding{
+			Check:       check,
+			StartLine:   fileAnnotation.StartLine(),
+			StartColumn: fileAnnotation.StartColumn(),
+			EndLine:     fileAnnotation.EndLine(),
+			EndColumn:   fileAnnotation.EndColumn(),
+			Type:        fileAnnotation.Type(),
+			Message:     fileAnnotation.Message(),
+			Severity:    fileAnnotation.Severity().String(),
+		}
+		if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+			finding.Path = fileInfo.Path()
+		}
+		finding.TypeName = s.getInnermostTypeName(finding.Path, finding.StartLine)
+		s.Findings = append(s.Findings, finding)
+	}
+}
+
+// NewHandler returns a new http.Handler that serves the UI at IndexPath, and the
+// Snapshot taken by the Snapshotter at SnapshotPath.
+//
+// A new Snapshot is taken for every request to SnapshotPath, so that reloading the UI
+// reflects the current state of the workspace.
+func NewHandler(logger *slog.Logger, snapshotter Snapshotter) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(SnapshotPath, newSnapshotHandler(logger, snapshotter))
+	mux.Handle(IndexPath, newIndexHandler())
+	return mux
+}
+
+// *** PRIVATE ***
+
+func (s *Snapshot) getInnermostTypeName(path string, line int) string {
+	if path == "" || line == 0 {
+		return ""
+	}
+	var innermostType *Type
+	for _, pkg := range s.Packages {
+		for _, t := range pkg.Types {
+			if t.Path != path || t.StartLine == 0 || line < t.StartLine || line > t.EndLine {
+				continue
+			}
+			if innermostType == nil || t.EndLine-t.StartLine < innermostType.EndLine-innermostType.StartLine {
+				innermostType = t
+			}
+		}
+	}
+	if innermostType == nil {
+		return ""
+	}
+	return innermostType.Name
+}
+
+func getTypes(path string, fileDescriptorProto *descriptorpb.FileDescriptorProto) []*Type {
+	typeNamePrefix := ""
+	if packageName := fileDescriptorProto.GetPackage(); packageName != "" {
+		typeNamePrefix = packageName + "."
+	}
+	typeBuilder := newTypeBuilder(path, fileDescriptorProto)
+	for i, descriptorProto := range fileDescriptorProto.GetMessageType() {
+		typeBuilder.addMessage(typeNamePrefix, descriptorProto, []int32{4, int32(i)})
+	}
+	for i, enumDescriptorProto := range fileDescriptorProto.GetEnumType() {
+		typeBuilder.addEnum(typeNamePrefix, enumDescriptorProto, []int32{5, int32(i)})
+	}
+	for i, serviceDescriptorProto := range fileDescriptorProto.GetService() {
+		typeBuilder.addService(typeNamePrefix, serviceDescriptorProto, []int32{6, int32(i)})
+	}
+	return typeBuilder.types
+}
+
+type typeBuilder struct {
+	path                string
+	sourcePathToSpan    map[string][]int32
+	mapEntryNameToField map[string]*field
+	types               []*Type
+}
+
+// field is a rendered field type and the fully-qualified names of the types it references.
+type field struct {
+	typeName   string
+	references []string
+}
+
+func newTypeBuilder(path string, fileDescriptorProto *descriptorpb.FileDescriptorProto) *typeBuilder {
+	sourcePathToSpan := make(map[string][]int32)
+	for _, location := range fileDescriptorProto.GetSourceCodeInfo().GetLocation() {
+		sourcePathToSpan[sourcePathKey(location.GetPath())] = location.GetSpan()
+	}
+	return &typeBuilder{
+		path:                path,
+		sourcePathToSpan:    sourcePathToSpan,
+		mapEntryNameToField: make(map[string]*field),
+	}
+}
+
+func (b *typeBuilder) addMessage(typeNamePrefix string, descriptorProto *descriptorpb.DescriptorProto, sourcePath []int32) {
+	name := typeNamePrefix + descriptorProto.GetName()
+	// Map entries are rendered as the map type of their field, so they are recorded
+	// before the fields are rendered, and are not added as Types.
+	for _, nestedDescriptorProto := range descriptorProto.GetNestedType() {
+		if nestedDescriptorProto.GetOptions().GetMapEntry() {
+			b.mapEntryNameToField[name+"."+nestedDescriptorProto.GetName()] = getMapField(nestedDescriptorProto)
+		}
+	}
+	t := b.newType(name, KindMessage, sourcePath)
+	var references []string
+	for _, fieldDescriptorProto := range descriptorProto.GetField() {
+		field := b.getField(fieldDescriptorProto)
+		t.Members = append(
+			t.Members,
+			&Member{
+				Name:   fieldDescriptorProto.GetName(),
+				Number: fieldDescriptorProto.GetNumber(),
+				Type:   field.typeName,
+			},
+		)
+		references = append(references, field.references...)
+	}
+	t.References = sortAndCompact(references)
+	b.types = append(b.types, t)
+	for i, nestedDescriptorProto := range descriptorProto.GetNestedType() {
+		if nestedDescriptorProto.GetOptions().GetMapEntry() {
+			continue
+		}
+		b.addMessage(name+".", nestedDescriptorProto, appendSourcePath(sourcePath, 3, int32(i)))
+	}
+	for i, enumDescriptorProto := range descriptorProto.GetEnumType() {
+		b.addEnum(name+".", enumDescriptorProto, appendSourcePath(sourcePath, 4, int32(i)))
+	}
+}
+
+func (b *typeBuilder) addEnum(typeNamePrefix string, enumDescriptorProto *descriptorpb.EnumDescriptorProto, sourcePath []int32) {
+	t := b.newType(typeNamePrefix+enumDescriptorProto.GetName(), KindEnum, sourcePath)
+	for _, enumValueDescriptorProto := range enumDescriptorProto.GetValue() {
+		t.Members = append(
+			t.Members,
+			&Member{
+				Name:   enumValueDescriptorProto.GetName(),
+				Number: enumValueDescriptorProto.GetNumber(),
+			},
+		)
+	}
+	b.types = append(b.types, t)
+}
+
+func (b *typeBuilder) addService(typeNamePrefix string, serviceDescriptorProto *descriptorpb.ServiceDescriptorProto, sourcePath []int32) {
+	t := b.newType(typeNamePrefix+serviceDescriptorProto.GetName(), KindService, sourcePath)
+	var references []string
+	for _, methodDescriptorProto := range serviceDescriptorProto.GetMethod() {
+		inputTypeName := strings.TrimPrefix(methodDescriptorProto.GetInputType(), ".")
+		outputTypeName := strings.TrimPrefix(methodDescriptorProto.GetOutputType(), ".")
+		t.Members = append(
+			t.Members,
+			&Member{
+				Name: methodDescriptorProto.GetName(),
+				Type: "(" + streamPrefix(methodDescriptorProto.GetClientStreaming()) + inputTypeName +
+					") returns (" + streamPrefix(methodDescriptorProto.GetServerStreaming()) + outputTypeName + ")",
+			},
+		)
+		references = append(references, inputTypeName, outputTypeName)
+	}
+	t.References = sortAndCompact(references)
+	b.types = append(b.types, t)
+}
+
+func (b *typeBuilder) newType(name string, kind string, sourcePath []int32) *Type {
+	t := &Type{
+		Name: name,
+		Kind: kind,
+		Path: b.path,
+	}
+	// Spans are [startLine, startColumn, endLine, endColumn] or [startLine, startColumn, endColumn],
+	// with 0-indexed lines.
+	switch span := b.sourcePathToSpan[sourcePathKey(sourcePath)]; len(span) {
+	case 3:
+		t.StartLine = int(span[0]) + 1
+		t.EndLine = int(span[0]) + 1
+	case 4:
+		t.StartLine = int(span[0]) + 1
+		t.EndLine = int(span[2]) + 1
+	}
+	return t
+}
+
+func (b *typeBuilder) getField(fieldDescriptorProto *descriptorpb.FieldDescriptorProto) *field {
+	typeName := strings.TrimPrefix(fieldDescriptorProto.GetTypeName(), ".")
+	if mapField, ok := b.mapEntryNameToField[typeName]; ok {
+		return mapField
+	}
+	field := getScalarOrTypeField(fieldDescriptorProto)
+	switch {
+	case fieldDescriptorProto.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED:
+		field.typeName = "repeated " + field.typeName
+	case fieldDescriptorProto.GetProto3Optional():
+		field.typeName = "optional " + field.typeName
+	}
+	return field
+}
+
+// getMapField returns the field for the map entry, rendered as map<key, value>.
+func getMapField(descriptorProto *descriptorpb.DescriptorProto) *field {
+	mapField := &field{}
+	var keyValueTypeNames []string
+	for _, fieldDescriptorProto := range descriptorProto.GetField() {
+		keyOrValueField := getScalarOrTypeField(fieldDescriptorProto)
+		keyValueTypeNames = append(keyValueTypeNames, keyOrValueField.typeName)
+		mapField.references = append(mapField.references, keyOrValueField.references...)
+	}
+	mapField.typeName = "map<" + strings.Join(keyValueTypeNames, ", ") + ">"
+	return mapField
+}
+
+// getScalarOrTypeField returns the field without its label.
+func getScalarOrTypeField(fieldDescriptorProto *descriptorpb.FieldDescriptorProto) *field {
+	if typeName := strings.TrimPrefix(fieldDescriptorProto.GetTypeName(), "."); typeName != "" {
+		return &field{
+			typeName:   typeName,
+			references: []string{typeName},
+		}
+	}
+	return &field{
+		typeName: strings.ToLower(strings.TrimPrefix(fieldDescriptorProto.GetType().String(), "TYPE_")),
+	}
+}
+
+func streamPrefix(streaming bool) string {
+	if streaming {
+		return "stream "
+	}
+	return ""
+}
+
+func sourcePathKey(sourcePath []int32) string {
+	var stringBuilder strings.Builder
+	for i, element := range sourcePath {
+		if i > 0 {
+			stringBuilder.WriteByte('.')
+		}
+		stringBuilder.WriteString(strconv.Itoa(int(element)))
+	}
+	return stringBuilder.String()
+}
+
+func appendSourcePath(sourcePath []int32, elements ...int32) []int32 {
+	return append(slices.Clone(sourcePath), elements...)
+}
+
+func sortAndCompact(values []string) []string {
+	slices.Sort(values)
+	return slices.Compact(values)
+}
@@ -0,0 +1,246 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduletesting"
+	"github.com/bufbuild/buf/private/pkg/slogext"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	t.Parallel()
+	snapshot := NewSnapshot(testBuildImage(t))
+	assert.Equal(
+		t,
+		[]*Package{
+			{
+				Name:  "acme.common.v1",
+				Files: []string{"acme/common/v1/common.proto"},
+				Types: []*Type{
+					{
+						Name:      "acme.common.v1.Money",
+						Kind:      KindMessage,
+						Path:      "acme/common/v1/common.proto",
+						StartLine: 5,
+						EndLine:   7,
+						Members: []*Member{
+							{Name: "units", Number: 1, Type: "int64"},
+						},
+					},
+				},
+			},
+			{
+				Name:         "acme.pet.v1",
+				Files:        []string{"acme/pet/v1/pet.proto"},
+				Dependencies: []string{"acme.common.v1"},
+				Types: []*Type{
+					{
+						Name:      "acme.pet.v1.GetPetRequest",
+						Kind:      KindMessage,
+						Path:      "acme/pet/v1/pet.proto",
+						StartLine: 22,
+						EndLine:   22,
+					},
+					{
+						Name:      "acme.pet.v1.Pet",
+						Kind:      KindMessage,
+						Path:      "acme/pet/v1/pet.proto",
+						StartLine: 7,
+						EndLine:   16,
+						Members: []*Member{
+							{Name: "name", Number: 1, Type: "string"},
+							{Name: "nicknames", Number: 2, Type: "repeated string"},
+							{Name: "prices", Number: 3, Type: "map<string, acme.common.v1.Money>"},
+							{Name: "type", Number: 4, Type: "acme.pet.v1.Pet.Type"},
+						},
+						References: []string{"acme.common.v1.Money", "acme.pet.v1.Pet.Type"},
+					},
+					{
+						Name:      "acme.pet.v1.Pet.Type",
+						Kind:      KindEnum,
+						Path:      "acme/pet/v1/pet.proto",
+						StartLine: 11,
+						EndLine:   14,
+						Members: []*Member{
+							{Name: "TYPE_UNSPECIFIED", Number: 0},
+							{Name: "TYPE_CAT", Number: 1},
+						},
+					},
+					{
+						Name:      "acme.pet.v1.PetService",
+						Kind:      KindService,
+						Path:      "acme/pet/v1/pet.proto",
+						StartLine: 18,
+						EndLine:   20,
+						Members: []*Member{
+							{Name: "GetPet", Type: "(acme.pet.v1.GetPetRequest) returns (stream acme.pet.v1.Pet)"},
+						},
+						References: []string{"acme.pet.v1.GetPetRequest", "acme.pet.v1.Pet"},
+					},
+				},
+			},
+		},
+		snapshot.Packages,
+	)
+
+	snapshot.AddFindings(
+		CheckLint,
+		[]bufanalysis.FileAnnotation{
+			bufanalysis.NewFileAnnotation(testFileInfo("acme/pet/v1/pet.proto"), 12, 5, 12, 21, "ENUM_VALUE_PREFIX", "Enum value name should be prefixed.", ""),
+			bufanalysis.NewFileAnnotation(testFileInfo("acme/pet/v1/pet.proto"), 3, 1, 3, 41, "IMPORT_USED", "Import is unused.", ""),
+			bufanalysis.NewFileAnnotation(nil, 0, 0, 0, 0, "PACKAGE_DEFINED", "Files must have a package defined.", ""),
+		},
+	)
+	require.Len(t, snapshot.Findings, 3)
+	assert.Equal(t, "acme.pet.v1.Pet.Type", snapshot.Findings[0].TypeName)
+	assert.Equal(t, CheckLint, snapshot.Findings[0].Check)
+	assert.Equal(t, "error", snapshot.Findings[0].Severity)
+	assert.Empty(t, snapshot.Findings[1].TypeName)
+	assert.Empty(t, snapshot.Findings[2].TypeName)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(
+		NewHandler(
+			slogext.NopLogger,
+			SnapshotterFunc(
+				func(context.Context) (*Snapshot, error) {
+					snapshot := NewSnapshot()
+					snapshot.AddFindings(
+						CheckBuild,
+						[]bufanalysis.FileAnnotation{
+							bufanalysis.NewFileAnnotation(testFileInfo("a.proto"), 1, 1, 1, 1, "COMPILE", "syntax error", ""),
+						},
+					)
+					return snapshot, nil
+				},
+			),
+		),
+	)
+	t.Cleanup(server.Close)
+
+	statusCode, body := testGet(t, server.URL+SnapshotPath)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(
+		t,
+		`{"packages":[],"findings":[{"check":"build","path":"a.proto","start_line":1,"start_column":1,"end_line":1,"end_column":1,"type":"COMPILE","message":"syntax error","severity":"error"}]}`,
+		body,
+	)
+	statusCode, body = testGet(t, server.URL+IndexPath)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Contains(t, body, SnapshotPath)
+	statusCode, _ = testGet(t, server.URL+"/unknown")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+	response, err := http.Post(server.URL+SnapshotPath, "application/json", nil)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+
+	errorServer := httptest.NewServer(
+		NewHandler(
+			slogext.NopLogger,
+			SnapshotterFunc(
+				func(context.Context) (*Snapshot, error) {
+					return nil, errors.New("failed")
+				},
+			),
+		),
+	)
+	t.Cleanup(errorServer.Close)
+	statusCode, body = testGet(t, errorServer.URL+SnapshotPath)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+	assert.Equal(t, "failed\n", body)
+}
+
+func testBuildImage(t *testing.T) bufimage.Image {
+	moduleSet, err := bufmoduletesting.NewModuleSetForPathToData(
+		map[string][]byte{
+			"acme/common/v1/common.proto": []byte(`syntax = "proto3";
+
+package acme.common.v1;
+
+message Money {
+  int64 units = 1;
+}
+`),
+			"acme/pet/v1/pet.proto": []byte(`syntax = "proto3";
+
+package acme.pet.v1;
+
+import "acme/common/v1/common.proto";
+
+message Pet {
+  string name = 1;
+  repeated string nicknames = 2;
+  map<string, acme.common.v1.Money> prices = 3;
+  enum Type {
+    TYPE_UNSPECIFIED = 0;
+    TYPE_CAT = 1;
+  }
+  Type type = 4;
+}
+
+service PetService {
+  rpc GetPet(GetPetRequest) returns (stream Pet);
+}
+
+message GetPetRequest {}
+`),
+		},
+	)
+	require.NoError(t, err)
+	image, err := bufimage.BuildImage(
+		context.Background(),
+		slogtestext.NewLogger(t),
+		bufmodule.ModuleSetToModuleReadBucketWithOnlyProtoFiles(moduleSet),
+	)
+	require.NoError(t, err)
+	return image
+}
+
+func testGet(t *testing.T, url string) (int, string) {
+	response, err := http.Get(url)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, response.Body.Close())
+	}()
+	data, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	return response.StatusCode, string(data)
+}
+
+type testFileInfo string
+
+func (f testFileInfo) Path() string {
+	return string(f)
+}
+
+func (f testFileInfo) ExternalPath() string {
+	return string(f)
+}
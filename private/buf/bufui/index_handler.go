@@ -0,0 +1,46 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufui
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// indexHTML is the UI. It is a single page without external dependencies, so that the
+// UI works without network access, and renders the Snapshot served at SnapshotPath.
+//
+//go:embed index.html
+var indexHTML []byte
+
+// indexHandler implements the GET handler for IndexPath.
+type indexHandler struct{}
+
+func newIndexHandler() *indexHandler {
+	return &indexHandler{}
+}
+
+func (*indexHandler) ServeHTTP(responseWriter http.ResponseWriter, request *http.Request) {
+	if request.URL.Path != IndexPath {
+		http.NotFound(responseWriter, request)
+		return
+	}
+	if request.Method != http.MethodGet {
+		http.Error(responseWriter, "", http.StatusMethodNotAllowed)
+		return
+	}
+	responseWriter.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = responseWriter.Write(indexHTML)
+}
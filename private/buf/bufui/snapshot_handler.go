@@ -0,0 +1,70 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufui
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/bufbuild/buf/private/pkg/slogext"
+)
+
+// snapshotHandler implements the GET handler for SnapshotPath.
+type snapshotHandler struct {
+	logger      *slog.Logger
+	snapshotter Snapshotter
+}
+
+func newSnapshotHandler(logger *slog.Logger, snapshotter Snapshotter) *snapshotHandler {
+	return &snapshotHandler{
+		logger:      logger,
+		snapshotter: snapshotter,
+	}
+}
+
+func (s *snapshotHandler) ServeHTTP(responseWriter http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		http.Error(responseWriter, "", http.StatusMethodNotAllowed)
+		return
+	}
+	start := time.Now()
+	snapshot, err := s.snapshotter.Snapshot(request.Context())
+	if err != nil {
+		s.logger.ErrorContext(
+			request.Context(),
+			"snapshot failed",
+			slogext.ErrorAttr(err),
+		)
+		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.logger.DebugContext(
+		request.Context(),
+		"snapshot",
+		slog.Int("packages", len(snapshot.Packages)),
+		slog.Int("findings", len(snapshot.Findings)),
+		slog.Duration("duration", time.Since(start)),
+	)
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.Header().Set("Cache-Control", "no-store")
+	_, _ = responseWriter.Write(data)
+}
@@ -0,0 +1,433 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufusage reports which elements of a module are referenced by the schemas of
+// the module's dependents, so that elements can be deprecated safely without analytics
+// from the BSR.
+package bufusage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// ElementTypeMessage is a message.
+	ElementTypeMessage ElementType = iota + 1
+	// ElementTypeField is a field of a message.
+	ElementTypeField
+	// ElementTypeEnum is an enum.
+	ElementTypeEnum
+	// ElementTypeExtension is an extension, such as a custom option.
+	ElementTypeExtension
+)
+
+var (
+	elementTypeToString = map[ElementType]string{
+		ElementTypeMessage:   "message",
+		ElementTypeField:     "field",
+		ElementTypeEnum:      "enum",
+		ElementTypeExtension: "extension",
+	}
+)
+
+// ElementType is the type of an element of a module.
+type ElementType int
+
+// String implements fmt.Stringer.
+func (e ElementType) String() string {
+	s, ok := elementTypeToString[e]
+	if !ok {
+		return strconv.Itoa(int(e))
+	}
+	return s
+}
+
+// Dependent is a dependent of a module.
+type Dependent struct {
+	// Name identifies the Dependent in reports, typically the input the Image was built from.
+	Name string
+	// Image is the Image of the Dependent, which must contain the files of the module
+	// that the Dependent imports.
+	Image bufimage.Image
+}
+
+// Usage is the usage of an element of a module by the module's Dependents.
+type Usage struct {
+	Name protoreflect.FullName
+	Type ElementType
+	// Dependents are the names of the Dependents that reference the element, sorted.
+	//
+	// This is empty if no Dependent references the element.
+	Dependents []string
+}
+
+// GetUsages returns the Usages of the messages, fields, enums, and extensions declared in
+// the non-import files of the module image by the non-import files of the Dependents.
+//
+// Services and methods are not included, as they cannot be referenced by schemas.
+//
+// A message or enum is referenced if it is used as the type of a field or extension, the
+// input or output of a method, or the extendee of an extension. The fields of a message
+// used as a type are referenced, as are the types of these fields, transitively. Messages,
+// fields, enums, and extensions are also referenced if they are set in option values,
+// such as a custom option of the module, in which case only the fields that are set are
+// referenced.
+//
+// Usages are in the order the elements are declared, with files ordered by path.
+func GetUsages(moduleImage bufimage.Image, dependents []*Dependent) ([]*Usage, error) {
+	var usages []*Usage
+	nameToUsage := make(map[protoreflect.FullName]*Usage)
+	modulePaths := make(map[string]struct{})
+	moduleResolver, err := protoencoding.NewResolver(bufimage.ImageToFileDescriptorProtos(moduleImage)...)
+	if err != nil {
+		return nil, err
+	}
+	for _, imageFile := range moduleImage.Files() {
+		if imageFile.IsImport() {
+			continue
+		}
+		modulePaths[imageFile.Path()] = struct{}{}
+		fileDescriptor, err := moduleResolver.FindFileByPath(imageFile.Path())
+		if err != nil {
+			return nil, err
+		}
+		forEachElement(fileDescriptor, func(name protoreflect.FullName, elementType ElementType) {
+			usage := &Usage{
+				Name: name,
+				Type: elementType,
+			}
+			usages = append(usages, usage)
+			nameToUsage[name] = usage
+		})
+	}
+	for _, dependent := range dependents {
+		referencedNames, err := getReferencedNames(dependent.Image, modulePaths)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", dependent.Name, err)
+		}
+		for name := range referencedNames {
+			if usage, ok := nameToUsage[name]; ok {
+				usage.Dependents = append(usage.Dependents, dependent.Name)
+			}
+		}
+	}
+	for _, usage := range usages {
+		slices.Sort(usage.Dependents)
+		usage.Dependents = slices.Compact(usage.Dependents)
+	}
+	return usages, nil
+}
+
+// PrintUsages prints a table of the Usages.
+//
+// Elements that are not referenced by any Dependent have "-" in the REFERENCED BY column.
+func PrintUsages(writer io.Writer, usages []*Usage) error {
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tabWriter, "NAME\tTYPE\tDEPENDENTS\tREFERENCED BY"); err != nil {
+		return err
+	}
+	for _, usage := range usages {
+		referencedBy := "-"
+		if len(usage.Dependents) > 0 {
+			referencedBy = strings.Join(usage.Dependents, ", ")
+		}
+		if _, err := fmt.Fprintf(
+			tabWriter,
+			"%s\t%s\t%d\t%s\n",
+			usage.Name,
+			usage.Type,
+			len(usage.Dependents),
+			referencedBy,
+		); err != nil {
+			return err
+		}
+	}
+	return tabWriter.Flush()
+}
+
+// PrintUsagesJSON prints the Usages as JSON.
+func PrintUsagesJSON(writer io.Writer, usages []*Usage) error {
+	externalUsages := make([]*externalUsage, len(usages))
+	for i, usage := range usages {
+		externalUsages[i] = &externalUsage{
+			Name:       string(usage.Name),
+			Type:       usage.Type.String(),
+			Dependents: usage.Dependents,
+		}
+		if externalUsages[i].Dependents == nil {
+			externalUsages[i].Dependents = []string{}
+		}
+	}
+	data, err := json.MarshalIndent(externalUsages, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(append(data, '\n'))
+	return err
+}
+
+// *** PRIVATE ***
+
+type externalUsage struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Dependents []string `json:"dependents"`
+}
+
+// elementContainer is a file or message, which can contain messages, enums, and extensions.
+type elementContainer interface {
+	Messages() protoreflect.MessageDescriptors
+	Enums() protoreflect.EnumDescriptors
+	Extensions() protoreflect.ExtensionDescriptors
+}
+
+// forEachElement calls f for each message, field, enum, and extension declared in the
+// file or message, in the order they are declared.
+func forEachElement(container elementContainer, f func(protoreflect.FullName, ElementType)) {
+	messages := container.Messages()
+	for i := range messages.Len() {
+		messageDescriptor := messages.Get(i)
+		if messageDescriptor.IsMapEntry() {
+			continue
+		}
+		f(messageDescriptor.FullName(), ElementTypeMessage)
+		fields := messageDescriptor.Fields()
+		for j := range fields.Len() {
+			f(fields.Get(j).FullName(), ElementTypeField)
+		}
+		forEachElement(messageDescriptor, f)
+	}
+	enums := container.Enums()
+	for i := range enums.Len() {
+		f(enums.Get(i).FullName(), ElementTypeEnum)
+	}
+	extensions := container.Extensions()
+	for i := range extensions.Len() {
+		f(extensions.Get(i).FullName(), ElementTypeExtension)
+	}
+}
+
+// referenceRecorder records the names referenced by the files of a Dependent.
+type referenceRecorder struct {
+	resolver protoencoding.Resolver
+	names    map[protoreflect.FullName]struct{}
+	// typeNames are the names of the messages that have been recorded as used as a
+	// type, which are recorded with all their fields.
+	typeNames map[protoreflect.FullName]struct{}
+}
+
+// getReferencedNames returns the names of the messages, fields, enums, and extensions
+// referenced by the non-import files of the image, excluding the files at modulePaths.
+//
+// Names of elements that are not in the module are also returned.
+func getReferencedNames(image bufimage.Image, modulePaths map[string]struct{}) (map[protoreflect.FullName]struct{}, error) {
+	resolver, err := protoencoding.NewResolver(bufimage.ImageToFileDescriptorProtos(image)...)
+	if err != nil {
+		return nil, err
+	}
+	recorder := &referenceRecorder{
+		resolver:  resolver,
+		names:     make(map[protoreflect.FullName]struct{}),
+		typeNames: make(map[protoreflect.FullName]struct{}),
+	}
+	for _, imageFile := range image.Files() {
+		if _, ok := modulePaths[imageFile.Path()]; ok || imageFile.IsImport() {
+			continue
+		}
+		fileDescriptor, err := resolver.FindFileByPath(imageFile.Path())
+		if err != nil {
+			return nil, err
+		}
+		if err := recorder.recordFile(fileDescriptor); err != nil {
+			return nil, err
+		}
+	}
+	return recorder.names, nil
+}
+
+func (r *referenceRecorder) recordFile(fileDescriptor protoreflect.FileDescriptor) error {
+	if err := r.recordOptions(fileDescriptor); err != nil {
+		return err
+	}
+	if err := r.recordContainer(fileDescriptor); err != nil {
+		return err
+	}
+	services := fileDescriptor.Services()
+	for i := range services.Len() {
+		serviceDescriptor := services.Get(i)
+		if err := r.recordOptions(serviceDescriptor); err != nil {
+			return err
+		}
+		methods := serviceDescriptor.Methods()
+		for j := range methods.Len() {
+			methodDescriptor := methods.Get(j)
+			if err := r.recordOptions(methodDescriptor); err != nil {
+				return err
+			}
+			r.recordMessageType(methodDescriptor.Input())
+			r.recordMessageType(methodDescriptor.Output())
+		}
+	}
+	return nil
+}
+
+func (r *referenceRecorder) recordContainer(container elementContainer) error {
+	messages := container.Messages()
+	for i := range messages.Len() {
+		messageDescriptor := messages.Get(i)
+		if err := r.recordOptions(messageDescriptor); err != nil {
+			return err
+		}
+		fields := messageDescriptor.Fields()
+		for j := range fields.Len() {
+			if err := r.recordField(fields.Get(j)); err != nil {
+				return err
+			}
+		}
+		oneofs := messageDescriptor.Oneofs()
+		for j := range oneofs.Len() {
+			if err := r.recordOptions(oneofs.Get(j)); err != nil {
+				return err
+			}
+		}
+		if err := r.recordContainer(messageDescriptor); err != nil {
+			return err
+		}
+	}
+	enums := container.Enums()
+	for i := range enums.Len() {
+		enumDescriptor := enums.Get(i)
+		if err := r.recordOptions(enumDescriptor); err != nil {
+			return err
+		}
+		values := enumDescriptor.Values()
+		for j := range values.Len() {
+			if err := r.recordOptions(values.Get(j)); err != nil {
+				return err
+			}
+		}
+	}
+	extensions := container.Extensions()
+	for i := range extensions.Len() {
+		extensionDescriptor := extensions.Get(i)
+		r.names[extensionDescriptor.ContainingMessage().FullName()] = struct{}{}
+		if err := r.recordField(extensionDescriptor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordField records the type of a field or extension declared by the Dependent,
+// and the names set in its options.
+func (r *referenceRecorder) recordField(fieldDescriptor protoreflect.FieldDescriptor) error {
+	if err := r.recordOptions(fieldDescriptor); err != nil {
+		return err
+	}
+	r.recordFieldType(fieldDescriptor)
+	return nil
+}
+
+func (r *referenceRecorder) recordFieldType(fieldDescriptor protoreflect.FieldDescriptor) {
+	switch fieldDescriptor.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		r.recordMessageType(fieldDescriptor.Message())
+	case protoreflect.EnumKind:
+		r.names[fieldDescriptor.Enum().FullName()] = struct{}{}
+	}
+}
+
+// recordMessageType records a message that is used as a type, along with all of its
+// fields and the types of its fields, transitively.
+func (r *referenceRecorder) recordMessageType(messageDescriptor protoreflect.MessageDescriptor) {
+	if _, ok := r.typeNames[messageDescriptor.FullName()]; ok {
+		return
+	}
+	r.typeNames[messageDescriptor.FullName()] = struct{}{}
+	r.names[messageDescriptor.FullName()] = struct{}{}
+	fields := messageDescriptor.Fields()
+	for i := range fields.Len() {
+		fieldDescriptor := fields.Get(i)
+		r.names[fieldDescriptor.FullName()] = struct{}{}
+		r.recordFieldType(fieldDescriptor)
+	}
+}
+
+// recordOptions records the extensions, messages, fields, and enums set in the options
+// of the descriptor.
+func (r *referenceRecorder) recordOptions(descriptor protoreflect.Descriptor) error {
+	options := descriptor.Options()
+	if options == nil || !options.ProtoReflect().IsValid() {
+		return nil
+	}
+	// Custom options are unknown fields of the options, so the options are reparsed
+	// with the resolver of the Dependent to resolve them as extensions.
+	data, err := proto.Marshal(options)
+	if err != nil {
+		return err
+	}
+	reparsedOptions := options.ProtoReflect().Type().New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: r.resolver}).Unmarshal(data, reparsedOptions); err != nil {
+		return fmt.Errorf("%s: %w", descriptor.FullName(), err)
+	}
+	r.recordMessageValue(reparsedOptions.ProtoReflect())
+	return nil
+}
+
+func (r *referenceRecorder) recordMessageValue(message protoreflect.Message) {
+	r.names[message.Descriptor().FullName()] = struct{}{}
+	message.Range(
+		func(fieldDescriptor protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+			r.names[fieldDescriptor.FullName()] = struct{}{}
+			switch {
+			case fieldDescriptor.IsMap():
+				valueDescriptor := fieldDescriptor.MapValue()
+				value.Map().Range(
+					func(_ protoreflect.MapKey, mapValue protoreflect.Value) bool {
+						r.recordSingularValue(valueDescriptor, mapValue)
+						return true
+					},
+				)
+			case fieldDescriptor.IsList():
+				list := value.List()
+				for i := range list.Len() {
+					r.recordSingularValue(fieldDescriptor, list.Get(i))
+				}
+			default:
+				r.recordSingularValue(fieldDescriptor, value)
+			}
+			return true
+		},
+	)
+}
+
+func (r *referenceRecorder) recordSingularValue(fieldDescriptor protoreflect.FieldDescriptor, value protoreflect.Value) {
+	switch fieldDescriptor.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		r.recordMessageValue(value.Message())
+	case protoreflect.EnumKind:
+		r.names[fieldDescriptor.Enum().FullName()] = struct{}{}
+	}
+}
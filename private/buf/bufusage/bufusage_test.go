@@ -0,0 +1,152 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufusage
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduletesting"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/stretchr/testify/require"
+)
+
+const testCommonProto = `syntax = "proto3";
+package acme.common.v1;
+import "google/protobuf/descriptor.proto";
+message Money {
+  string currency = 1;
+  int64 units = 2;
+  Precision precision = 3;
+}
+message Precision {
+  int32 digits = 1;
+}
+message Legacy {
+  string value = 1;
+}
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+}
+message Rule {
+  int32 min = 1;
+  int32 max = 2;
+}
+extend google.protobuf.FieldOptions {
+  Rule rule = 50000;
+}
+`
+
+func TestGetUsages(t *testing.T) {
+	t.Parallel()
+	moduleImage := testBuildImage(
+		t,
+		map[string][]byte{
+			"acme/common/v1/common.proto": []byte(testCommonProto),
+		},
+		nil,
+	)
+	orderImage := testBuildImage(
+		t,
+		map[string][]byte{
+			"acme/order/v1/order.proto": []byte(`syntax = "proto3";
+package acme.order.v1;
+import "acme/common/v1/common.proto";
+message Order {
+  acme.common.v1.Money total = 1 [(acme.common.v1.rule) = {max: 10}];
+}
+`),
+		},
+		map[string][]byte{
+			"acme/common/v1/common.proto": []byte(testCommonProto),
+		},
+	)
+	userImage := testBuildImage(
+		t,
+		map[string][]byte{
+			"acme/user/v1/user.proto": []byte(`syntax = "proto3";
+package acme.user.v1;
+import "acme/common/v1/common.proto";
+service UserService {
+  rpc GetStatus(acme.common.v1.Precision) returns (GetStatusResponse);
+}
+message GetStatusResponse {
+  acme.common.v1.Status status = 1;
+}
+`),
+		},
+		map[string][]byte{
+			"acme/common/v1/common.proto": []byte(testCommonProto),
+		},
+	)
+	usages, err := GetUsages(
+		moduleImage,
+		[]*Dependent{
+			{Name: "user", Image: userImage},
+			{Name: "order", Image: orderImage},
+		},
+	)
+	require.NoError(t, err)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, PrintUsages(buffer, usages))
+	require.Equal(
+		t,
+		`NAME                             TYPE       DEPENDENTS  REFERENCED BY
+acme.common.v1.Money             message    1           order
+acme.common.v1.Money.currency    field      1           order
+acme.common.v1.Money.units       field      1           order
+acme.common.v1.Money.precision   field      1           order
+acme.common.v1.Precision         message    2           order, user
+acme.common.v1.Precision.digits  field      2           order, user
+acme.common.v1.Legacy            message    0           -
+acme.common.v1.Legacy.value      field      0           -
+acme.common.v1.Rule              message    1           order
+acme.common.v1.Rule.min          field      0           -
+acme.common.v1.Rule.max          field      1           order
+acme.common.v1.Status            enum       1           user
+acme.common.v1.rule              extension  1           order
+`,
+		buffer.String(),
+	)
+}
+
+func testBuildImage(t *testing.T, pathToData map[string][]byte, importPathToData map[string][]byte) bufimage.Image {
+	moduleDatas := []bufmoduletesting.ModuleData{
+		{
+			PathToData: pathToData,
+		},
+	}
+	if importPathToData != nil {
+		moduleDatas = append(
+			moduleDatas,
+			bufmoduletesting.ModuleData{
+				PathToData:  importPathToData,
+				NotTargeted: true,
+			},
+		)
+	}
+	moduleSet, err := bufmoduletesting.NewModuleSet(moduleDatas...)
+	require.NoError(t, err)
+	image, err := bufimage.BuildImage(
+		context.Background(),
+		slogtestext.NewLogger(t),
+		bufmodule.ModuleSetToModuleReadBucketWithOnlyProtoFiles(moduleSet),
+	)
+	require.NoError(t, err)
+	return image
+}
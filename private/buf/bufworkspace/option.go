@@ -71,6 +71,15 @@ func WithIgnoreAndDisallowV1BufWorkYAMLs() WorkspaceBucketOption {
 	return &workspaceIgnoreAndDisallowV1BufWorkYAMLsOption{}
 }
 
+// WithIgnoreModuleOverrides returns a new WorkspaceBucketOption that says to ignore
+// the overrides of v2 buf.yaml files, and use the dependencies from the buf.lock file.
+//
+// This is used when updating buf.lock files and pushing, which must only use published
+// dependencies.
+func WithIgnoreModuleOverrides() WorkspaceBucketOption {
+	return &workspaceIgnoreModuleOverridesOption{}
+}
+
 // Note these paths need to have the path/to/module stripped, and then each new path
 // filtered to the specific module it applies to. If some modules do not have any
 // target paths, but we specified WorkspaceWithTargetPaths, then those modules
@@ -148,11 +157,18 @@ func (c *workspaceIgnoreAndDisallowV1BufWorkYAMLsOption) applyToWorkspaceBucketC
 	config.ignoreAndDisallowV1BufWorkYAMLs = true
 }
 
+type workspaceIgnoreModuleOverridesOption struct{}
+
+func (c *workspaceIgnoreModuleOverridesOption) applyToWorkspaceBucketConfig(config *workspaceBucketConfig) {
+	config.ignoreModuleOverrides = true
+}
+
 type workspaceBucketConfig struct {
 	protoFileTargetPath             string
 	includePackageFiles             bool
 	configOverride                  string
 	ignoreAndDisallowV1BufWorkYAMLs bool
+	ignoreModuleOverrides           bool
 }
 
 func newWorkspaceBucketConfig(options []WorkspaceBucketOption) (*workspaceBucketConfig, error) {
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"maps"
+	"path/filepath"
 
 	"github.com/bufbuild/buf/private/buf/buftarget"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
@@ -30,6 +32,7 @@ import (
 	"github.com/bufbuild/buf/private/pkg/slicesext"
 	"github.com/bufbuild/buf/private/pkg/slogext"
 	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storageos"
 	"github.com/bufbuild/buf/private/pkg/stringutil"
 	"github.com/bufbuild/buf/private/pkg/syserror"
 	"github.com/google/uuid"
@@ -252,14 +255,11 @@ func (w *workspaceProvider) getWorkspaceTargetingForBucket(
 	ctx context.Context,
 	bucket storage.ReadBucket,
 	bucketTargeting buftarget.BucketTargeting,
-	options ...WorkspaceBucketOption,
+	config *workspaceBucketConfig,
 ) (*workspaceTargeting, error) {
-	config, err := newWorkspaceBucketConfig(options)
-	if err != nil {
-		return nil, err
-	}
 	var overrideBufYAMLFile bufconfig.BufYAMLFile
 	if config.configOverride != "" {
+		var err error
 		overrideBufYAMLFile, err = bufconfig.GetBufYAMLFileForOverride(config.configOverride)
 		if err != nil {
 			return nil, err
@@ -283,11 +283,15 @@ func (w *workspaceProvider) GetWorkspaceForBucket(
 	options ...WorkspaceBucketOption,
 ) (Workspace, error) {
 	defer slogext.DebugProfile(w.logger)()
+	config, err := newWorkspaceBucketConfig(options)
+	if err != nil {
+		return nil, err
+	}
 	workspaceTargeting, err := w.getWorkspaceTargetingForBucket(
 		ctx,
 		bucket,
 		bucketTargeting,
-		options...,
+		config,
 	)
 	if err != nil {
 		return nil, err
@@ -297,6 +301,7 @@ func (w *workspaceProvider) GetWorkspaceForBucket(
 			ctx,
 			bucket,
 			workspaceTargeting.v2,
+			config.ignoreModuleOverrides,
 		)
 	}
 	return w.getWorkspaceForBucketAndModuleDirPathsV1Beta1OrV1(
@@ -417,6 +422,7 @@ func (w *workspaceProvider) getWorkspaceForBucketBufYAMLV2(
 	ctx context.Context,
 	bucket storage.ReadBucket,
 	v2Targeting *v2Targeting,
+	ignoreModuleOverrides bool,
 ) (*workspace, error) {
 	moduleSetBuilder := bufmodule.NewModuleSetBuilder(ctx, w.logger, w.moduleDataProvider, w.commitProvider)
 	var remotePluginKeys []bufplugin.PluginKey
@@ -498,13 +504,20 @@ func (w *workspaceProvider) getWorkspaceForBucketBufYAMLV2(
 			bufmodule.LocalModuleWithDescription(moduleDescription),
 		)
 	}
+	bucketIDToModuleConfig := v2Targeting.bucketIDToModuleConfig
+	if moduleOverrides := v2Targeting.bufYAMLFile.ModuleOverrides(); len(moduleOverrides) > 0 && !ignoreModuleOverrides {
+		bucketIDToModuleConfig = maps.Clone(bucketIDToModuleConfig)
+		if err := w.addModuleOverrides(ctx, moduleSetBuilder, bucket, moduleOverrides, bucketIDToModuleConfig); err != nil {
+			return nil, err
+		}
+	}
 	moduleSet, err := moduleSetBuilder.Build()
 	if err != nil {
 		return nil, err
 	}
 	return w.getWorkspaceForBucketModuleSet(
 		moduleSet,
-		v2Targeting.bucketIDToModuleConfig,
+		bucketIDToModuleConfig,
 		v2Targeting.bufYAMLFile.PluginConfigs(),
 		remotePluginKeys,
 		v2Targeting.bufYAMLFile.ConfiguredDepModuleRefs(),
@@ -512,6 +525,63 @@ func (w *workspaceProvider) getWorkspaceForBucketBufYAMLV2(
 	)
 }
 
+// addModuleOverrides adds the local directories of the ModuleOverrides as local Modules.
+//
+// Local Modules take precedence over remote Modules with the same FullName, so these
+// replace the dependencies from the buf.lock file. The ModuleConfigs of the added Modules
+// are added to bucketIDToModuleConfig.
+func (w *workspaceProvider) addModuleOverrides(
+	ctx context.Context,
+	moduleSetBuilder bufmodule.ModuleSetBuilder,
+	bucket storage.ReadBucket,
+	moduleOverrides []bufconfig.ModuleOverride,
+	bucketIDToModuleConfig map[string]bufconfig.ModuleConfig,
+) error {
+	// The paths of overrides are relative to the buf.yaml file, which is at the root of the
+	// bucket for v2 workspaces. Overrides can point outside of the bucket, so we need the
+	// location of the buf.yaml file on disk.
+	objectInfo, err := bucket.Stat(ctx, bufconfig.DefaultBufYAMLFileName)
+	if err != nil {
+		return err
+	}
+	if objectInfo.LocalPath() == "" {
+		return errors.New("overrides in buf.yaml can only be used when building from a local directory")
+	}
+	bufYAMLDirPath := filepath.Dir(objectInfo.LocalPath())
+	storageosProvider := storageos.NewProvider(storageos.ProviderWithSymlinks())
+	for _, moduleOverride := range moduleOverrides {
+		dirPath := normalpath.Unnormalize(moduleOverride.DirPath())
+		if !filepath.IsAbs(dirPath) {
+			dirPath = filepath.Join(bufYAMLDirPath, dirPath)
+		}
+		overrideBucket, err := storageosProvider.NewReadWriteBucket(
+			dirPath,
+			storageos.ReadWriteBucketWithSymlinksIfSupported(),
+		)
+		if err != nil {
+			return fmt.Errorf("override of %s: %w", moduleOverride.FullName(), err)
+		}
+		w.logger.Warn(fmt.Sprintf(
+			"Dependency %s is overridden with local directory %q from the overrides in your buf.yaml. Remove the override once your changes to %s are pushed.",
+			moduleOverride.FullName(),
+			moduleOverride.DirPath(),
+			moduleOverride.FullName(),
+		))
+		bucketID := "override: " + moduleOverride.DirPath()
+		moduleSetBuilder.AddLocalModule(
+			overrideBucket,
+			bucketID,
+			false,
+			bufmodule.LocalModuleWithFullName(moduleOverride.FullName()),
+			bufmodule.LocalModuleWithDescription(
+				fmt.Sprintf("override: %q", moduleOverride.DirPath()),
+			),
+		)
+		bucketIDToModuleConfig[bucketID] = bufconfig.DefaultModuleConfigV2
+	}
+	return nil
+}
+
 // only use for workspaces created from buckets
 func (w *workspaceProvider) getWorkspaceForBucketModuleSet(
 	moduleSet bufmodule.ModuleSet,
@@ -19,29 +19,58 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net"
 	"net/http"
+	"os"
+	"runtime/debug"
+	"slices"
+	"strings"
 	"time"
 
 	"connectrpc.com/connect"
 	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufcrash"
 	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/bufperf"
+	"github.com/bufbuild/buf/private/buf/bufreduce"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/alpha/pluginfuzz/pluginfuzzgenerate"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/alpha/pluginfuzz/pluginfuzzrun"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/alpha/protoc"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/alpha/registry/token/tokendelete"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/alpha/registry/token/tokenget"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/alpha/registry/token/tokenlist"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/artifact/artifactverify"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/bufpluginv1"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/bufpluginv1beta1"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/bufpluginv2"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/compatreport"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/exportschema"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/extension/extensionsync"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/freeze"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/genroutes"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/hookserver"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/interactive"
+	betalint "github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/lint"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/lsp"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/numbers/numbersallocate"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/perf/perfreport"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/price"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/reduce"
 	betaplugindelete "github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/registry/plugin/plugindelete"
 	betapluginpush "github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/registry/plugin/pluginpush"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/registry/webhook/webhookcreate"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/registry/webhook/webhookdelete"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/registry/webhook/webhooklist"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/reserved/reservedcheck"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/reserved/reservedsync"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/stats"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/studioagent"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/transcode"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/ui"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/usage"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/beta/verifygenerateconfig"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/breaking"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/build"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/config/configinit"
@@ -54,6 +83,7 @@ import (
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/dep/depgraph"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/dep/depprune"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/dep/depupdate"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/explain"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/export"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/format"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/generate"
@@ -95,8 +125,10 @@ import (
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/registry/plugin/pluginlabel/pluginlabelinfo"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/registry/plugin/pluginlabel/pluginlabellist"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/registry/plugin/pluginlabel/pluginlabelunarchive"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/registry/plugin/pluginsearch"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/registry/plugin/pluginsettings/pluginsettingsupdate"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/registry/registrycc"
+	"github.com/bufbuild/buf/private/buf/cmd/buf/command/registry/registryexportstate"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/registry/registrylogin"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/registry/registrylogout"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/command/registry/sdk/version"
@@ -107,7 +139,9 @@ import (
 	"github.com/bufbuild/buf/private/pkg/app"
 	"github.com/bufbuild/buf/private/pkg/app/appcmd"
 	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/cachestats"
 	"github.com/bufbuild/buf/private/pkg/slogapp"
+	"github.com/bufbuild/buf/private/pkg/slogext"
 	"github.com/bufbuild/buf/private/pkg/syserror"
 	"github.com/spf13/cobra"
 )
@@ -125,7 +159,11 @@ func NewRootCommand(name string) *appcmd.Command {
 		name,
 		appext.BuilderWithTimeout(120*time.Second),
 		appext.BuilderWithInterceptor(newErrorInterceptor()),
+		appext.BuilderWithInterceptor(newPerfStatsInterceptor()),
+		// The crash interceptor must be last, as it re-runs the command to reduce the input.
+		appext.BuilderWithInterceptor(newCrashInterceptor()),
 		appext.BuilderWithLoggerProvider(slogapp.LoggerProvider),
+		appext.BuilderWithLogComponents(bufctl.LogComponents...),
 	)
 	return &appcmd.Command{
 		Use:                 name,
@@ -139,6 +177,7 @@ func NewRootCommand(name string) *appcmd.Command {
 			format.NewCommand("format", builder),
 			lint.NewCommand("lint", builder),
 			breaking.NewCommand("breaking", builder),
+			explain.NewCommand("explain", builder),
 			generate.NewCommand("generate", builder),
 			lsfiles.NewCommand("ls-files", builder),
 			push.NewCommand("push", builder),
@@ -203,6 +242,7 @@ func NewRootCommand(name string) *appcmd.Command {
 					registrylogout.NewCommand("logout", builder),
 					whoami.NewCommand("whoami", builder),
 					registrycc.NewCommand("cc", builder, ``, false),
+					registryexportstate.NewCommand("export-state", builder),
 					{
 						Use:        "commit",
 						Short:      `Manage a module's commits, all commands are deprecated and have moved to the "buf registry module commit" subcommands`,
@@ -317,6 +357,7 @@ func NewRootCommand(name string) *appcmd.Command {
 							plugincreate.NewCommand("create", builder),
 							plugininfo.NewCommand("info", builder),
 							plugindelete.NewCommand("delete", builder),
+							pluginsearch.NewCommand("search", builder),
 						},
 					},
 				},
@@ -332,6 +373,54 @@ func NewRootCommand(name string) *appcmd.Command {
 					bufpluginv1.NewCommand("buf-plugin-v1", builder),
 					bufpluginv2.NewCommand("buf-plugin-v2", builder),
 					studioagent.NewCommand("studio-agent", builder),
+					transcode.NewCommand("transcode", builder),
+					genroutes.NewCommand("gen-routes", builder),
+					compatreport.NewCommand("compat-report", builder),
+					exportschema.NewCommand("export-schema", builder),
+					freeze.NewCommand("freeze", builder),
+					interactive.NewCommand("interactive", builder, NewRootCommand),
+					reduce.NewCommand("reduce", builder, NewRootCommand),
+					betalint.NewCommand("lint", builder),
+					hookserver.NewCommand("hook-server", builder),
+					ui.NewCommand("ui", builder),
+					verifygenerateconfig.NewCommand("verify-generate-config", builder),
+					usage.NewCommand("usage", builder),
+					{
+						Use:   "artifact",
+						Short: "Work with build artifacts",
+						SubCommands: []*appcmd.Command{
+							artifactverify.NewCommand("verify", builder),
+						},
+					},
+					{
+						Use:   "extension",
+						Short: "Work with the extension registry",
+						SubCommands: []*appcmd.Command{
+							extensionsync.NewCommand("sync", builder),
+						},
+					},
+					{
+						Use:   "numbers",
+						Short: "Work with the number ledger",
+						SubCommands: []*appcmd.Command{
+							numbersallocate.NewCommand("allocate", builder),
+						},
+					},
+					{
+						Use:   "perf",
+						Short: "Work with local performance statistics",
+						SubCommands: []*appcmd.Command{
+							perfreport.NewCommand("report", builder),
+						},
+					},
+					{
+						Use:   "reserved",
+						Short: "Work with the reserved registry",
+						SubCommands: []*appcmd.Command{
+							reservedcheck.NewCommand("check", builder),
+							reservedsync.NewCommand("sync", builder),
+						},
+					},
 					{
 						Use:   "registry",
 						Short: "Manage assets on the Buf Schema Registry",
@@ -363,6 +452,14 @@ func NewRootCommand(name string) *appcmd.Command {
 				Hidden: true,
 				SubCommands: []*appcmd.Command{
 					protoc.NewCommand("protoc", builder),
+					{
+						Use:   "plugin-fuzz",
+						Short: "Fuzz-test plugins with adversarial CodeGeneratorRequests",
+						SubCommands: []*appcmd.Command{
+							pluginfuzzgenerate.NewCommand("generate", builder),
+							pluginfuzzrun.NewCommand("run", builder),
+						},
+					},
 					{
 						Use:   "registry",
 						Short: "Manage assets on the Buf Schema Registry",
@@ -397,6 +494,211 @@ func newErrorInterceptor() appext.Interceptor {
 	}
 }
 
+// newPerfStatsInterceptor returns a CLI interceptor that records the duration and cache
+// statistics of commands for buf beta perf report, if enabled with bufcli.IsPerfStatsEnabled.
+func newPerfStatsInterceptor() appext.Interceptor {
+	return func(next func(context.Context, appext.Container) error) func(context.Context, appext.Container) error {
+		return func(ctx context.Context, container appext.Container) error {
+			if !bufcli.IsPerfStatsEnabled(container) {
+				return next(ctx, container)
+			}
+			recorder := cachestats.NewRecorder()
+			startTime := time.Now()
+			err := next(cachestats.WithRecorder(ctx, recorder), container)
+			run := &bufperf.Run{
+				Command:    appcmd.CommandPath(ctx),
+				StartTime:  startTime,
+				Duration:   time.Since(startTime),
+				ExitCode:   app.GetExitCode(err),
+				CacheStats: recorder.CacheStats(),
+			}
+			// Failing to record the statistics should never fail the command.
+			if recordErr := recordPerfStatsRun(context.WithoutCancel(ctx), container, run); recordErr != nil {
+				container.Logger().Debug("could not record performance statistics", slogext.ErrorAttr(recordErr))
+			}
+			return err
+		}
+	}
+}
+
+func recordPerfStatsRun(ctx context.Context, container appext.Container, run *bufperf.Run) error {
+	bucket, err := bufcli.NewPerfCacheBucket(container)
+	if err != nil {
+		return err
+	}
+	return bufperf.AddRun(ctx, bucket, run)
+}
+
+const (
+	// crashReduceTimeout is the maximum duration of reducing the input of a crashed command.
+	crashReduceTimeout = time.Minute
+)
+
+var (
+	// crashReducibleCommands are the commands whose input is reduced when they crash.
+	//
+	// These commands do not modify their input, so that they are safe to re-run on copies of it.
+	crashReducibleCommands = []string{
+		"build",
+		"lint",
+	}
+)
+
+// newCrashInterceptor returns a CLI interceptor that writes a crash bundle if a command
+// panics, if enabled with bufcli.GetCrashDirPath. The panic is re-raised once the bundle
+// is written.
+//
+// For buf build and buf lint with a local directory input, the .proto files of the input
+// are reduced to a minimal set that still results in the same panic, by re-running the
+// command on copies of the input in temporary directories.
+func newCrashInterceptor() appext.Interceptor {
+	return func(next func(context.Context, appext.Container) error) func(context.Context, appext.Container) error {
+		return func(ctx context.Context, container appext.Container) error {
+			crashDirPath := bufcli.GetCrashDirPath(container)
+			if crashDirPath == "" {
+				return next(ctx, container)
+			}
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				report := &bufcrash.Report{
+					Version: bufcli.Version,
+					Command: appcmd.CommandPath(ctx),
+					Args:    app.Args(container),
+					Panic:   fmt.Sprint(recovered),
+					Stack:   debug.Stack(),
+				}
+				bundleDirPath, err := writeCrashBundle(context.WithoutCancel(ctx), container, next, crashDirPath, report)
+				if err != nil {
+					_, _ = fmt.Fprintf(container.Stderr(), "Failure: could not write crash bundle: %v\n", err)
+				} else {
+					_, _ = fmt.Fprintf(
+						container.Stderr(),
+						`buf crashed. A crash bundle was written to %s.
+Review its contents, which may include .proto files of your input, and attach it to an issue at https://github.com/bufbuild/buf/issues/new.
+`,
+						bundleDirPath,
+					)
+				}
+				panic(recovered)
+			}()
+			return next(ctx, container)
+		}
+	}
+}
+
+func writeCrashBundle(
+	ctx context.Context,
+	container appext.Container,
+	next func(context.Context, appext.Container) error,
+	crashDirPath string,
+	report *bufcrash.Report,
+) (string, error) {
+	inputDirPath := "."
+	if container.NumArgs() > 0 {
+		inputDirPath = container.Arg(0)
+	}
+	if fileInfo, err := os.Stat(inputDirPath); err != nil || !fileInfo.IsDir() {
+		report.Reduction = "not reduced, the input is not a local directory"
+		return bufcrash.WriteBundle(crashDirPath, report, nil)
+	}
+	pathToData, err := bufreduce.ReadInputDir(inputDirPath)
+	if err != nil {
+		report.Reduction = fmt.Sprintf("not reduced, %v", err)
+		return bufcrash.WriteBundle(crashDirPath, report, nil)
+	}
+	if !slices.Contains(crashReducibleCommands, strings.TrimPrefix(report.Command, container.AppName()+" ")) {
+		report.Reduction = "not reduced, only buf build and buf lint are reduced"
+		return bufcrash.WriteBundle(crashDirPath, report, bufcrash.ConfigFiles(pathToData))
+	}
+	ctx, cancel := context.WithTimeout(ctx, crashReduceTimeout)
+	defer cancel()
+	var numAttempts int
+	crashes := func(ctx context.Context, pathToData map[string][]byte) bool {
+		numAttempts++
+		tempDirPath, err := os.MkdirTemp("", "buf-crash-")
+		if err != nil {
+			return false
+		}
+		defer func() {
+			_ = os.RemoveAll(tempDirPath)
+		}()
+		if err := bufreduce.WriteInputDir(tempDirPath, pathToData); err != nil {
+			return false
+		}
+		panicString, ok := getPanicString(
+			ctx,
+			next,
+			&crashReproContainer{
+				Container: container,
+				args:      []string{tempDirPath},
+			},
+		)
+		return ok && strings.ReplaceAll(panicString, tempDirPath, inputDirPath) == report.Panic
+	}
+	if !crashes(ctx, pathToData) {
+		report.Reduction = "not reduced, the crash could not be reproduced with a copy of the input"
+		return bufcrash.WriteBundle(crashDirPath, report, bufcrash.ConfigFiles(pathToData))
+	}
+	reducedPathToData := bufreduce.ReduceProtoFiles(ctx, pathToData, crashes)
+	report.Reduction = fmt.Sprintf(
+		"reduced from %d to %d files in %d attempts",
+		len(pathToData),
+		len(reducedPathToData),
+		numAttempts,
+	)
+	return bufcrash.WriteBundle(crashDirPath, report, reducedPathToData)
+}
+
+// getPanicString runs the function and returns the value recovered from its panic, if any.
+func getPanicString(
+	ctx context.Context,
+	f func(context.Context, appext.Container) error,
+	container appext.Container,
+) (panicString string, ok bool) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			panicString, ok = fmt.Sprint(recovered), true
+		}
+	}()
+	_ = f(ctx, container)
+	return "", false
+}
+
+// crashReproContainer is a Container for re-running a command on a copy of its input
+// while reducing the input, with no stdio and logging.
+type crashReproContainer struct {
+	appext.Container
+
+	args []string
+}
+
+func (c *crashReproContainer) Stdin() io.Reader {
+	return strings.NewReader("")
+}
+
+func (c *crashReproContainer) Stdout() io.Writer {
+	return io.Discard
+}
+
+func (c *crashReproContainer) Stderr() io.Writer {
+	return io.Discard
+}
+
+func (c *crashReproContainer) Logger() *slog.Logger {
+	return slogext.NopLogger
+}
+
+func (c *crashReproContainer) NumArgs() int {
+	return len(c.args)
+}
+
+func (c *crashReproContainer) Arg(i int) string {
+	return c.args[i]
+}
+
 // wrapError is used when a CLI command fails, regardless of its error code.
 // Note that this function will wrap the error so that the underlying error
 // can be recovered via 'errors.Is'.
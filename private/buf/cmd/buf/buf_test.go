@@ -15,6 +15,7 @@
 package buf
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -22,16 +23,20 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"buf.build/go/bufplugin/check"
 	"github.com/bufbuild/buf/private/buf/bufcli"
 	"github.com/bufbuild/buf/private/buf/bufctl"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/internal/internaltesting"
+	"github.com/bufbuild/buf/private/bufpkg/bufbaseline"
+	"github.com/bufbuild/buf/private/bufpkg/bufbreakingexception"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage"
@@ -138,11 +143,11 @@ var (
 		{ID: "MESSAGE_NO_REMOVE_STANDARD_DESCRIPTOR_ACCESSOR", Categories: []string{"FILE", "PACKAGE"}, Default: true, Purpose: "Checks that messages do not change the no_standard_descriptor_accessor option from false or unset to true."},
 		{ID: "ONEOF_NO_DELETE", Categories: []string{"FILE", "PACKAGE"}, Default: true, Purpose: "Checks that oneofs are not deleted from a given message."},
 		{ID: "RPC_NO_DELETE", Categories: []string{"FILE", "PACKAGE"}, Default: true, Purpose: "Checks that rpcs are not deleted from a given service."},
-		{ID: "ENUM_SAME_JSON_FORMAT", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON"}, Default: true, Purpose: "Checks that enums have the same JSON format support."},
-		{ID: "ENUM_VALUE_SAME_NAME", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON"}, Default: true, Purpose: "Checks that enum values have the same name."},
-		{ID: "FIELD_SAME_JSON_NAME", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON"}, Default: true, Purpose: "Checks that fields have the same value for the json_name option."},
-		{ID: "FIELD_SAME_NAME", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON"}, Default: true, Purpose: "Checks that fields have the same names in a given message."},
-		{ID: "MESSAGE_SAME_JSON_FORMAT", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON"}, Default: true, Purpose: "Checks that messages have the same JSON format support."},
+		{ID: "ENUM_SAME_JSON_FORMAT", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}, Default: true, Purpose: "Checks that enums have the same JSON format support."},
+		{ID: "ENUM_VALUE_SAME_NAME", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}, Default: true, Purpose: "Checks that enum values have the same name."},
+		{ID: "FIELD_SAME_JSON_NAME", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}, Default: true, Purpose: "Checks that fields have the same value for the json_name option."},
+		{ID: "FIELD_SAME_NAME", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}, Default: true, Purpose: "Checks that fields have the same names in a given message."},
+		{ID: "MESSAGE_SAME_JSON_FORMAT", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}, Default: true, Purpose: "Checks that messages have the same JSON format support."},
 		{ID: "FIELD_SAME_DEFAULT", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON", "WIRE"}, Default: true, Purpose: "Checks that fields have the same default value, if a default is specified."},
 		{ID: "FIELD_SAME_ONEOF", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON", "WIRE"}, Default: true, Purpose: "Checks that fields have the same oneofs in a given message."},
 		{ID: "FILE_SAME_PACKAGE", Categories: []string{"FILE", "PACKAGE", "WIRE_JSON", "WIRE"}, Default: true, Purpose: "Checks that files have the same package."},
@@ -159,14 +164,19 @@ var (
 		{ID: "PACKAGE_MESSAGE_NO_DELETE", Categories: []string{"PACKAGE"}, Default: false, Purpose: "Checks that messages are not deleted from a given package."},
 		{ID: "PACKAGE_NO_DELETE", Categories: []string{"PACKAGE"}, Default: false, Purpose: "Checks that packages are not deleted."},
 		{ID: "PACKAGE_SERVICE_NO_DELETE", Categories: []string{"PACKAGE"}, Default: false, Purpose: "Checks that services are not deleted from a given package."},
-		{ID: "ENUM_VALUE_NO_DELETE_UNLESS_NAME_RESERVED", Categories: []string{"WIRE_JSON"}, Default: false, Purpose: "Checks that enum values are not deleted from a given enum unless the name is reserved."},
-		{ID: "FIELD_NO_DELETE_UNLESS_NAME_RESERVED", Categories: []string{"WIRE_JSON"}, Default: false, Purpose: "Checks that fields are not deleted from a given message unless the name is reserved."},
-		{ID: "FIELD_WIRE_JSON_COMPATIBLE_CARDINALITY", Categories: []string{"WIRE_JSON"}, Default: false, Purpose: "Checks that fields have wire and JSON compatible cardinalities in a given message."},
-		{ID: "FIELD_WIRE_JSON_COMPATIBLE_TYPE", Categories: []string{"WIRE_JSON"}, Default: false, Purpose: "Checks that fields have wire and JSON compatible types in a given message."},
+		{ID: "ENUM_VALUE_NO_DELETE_UNLESS_NAME_RESERVED", Categories: []string{"WIRE_JSON", "JSON"}, Default: false, Purpose: "Checks that enum values are not deleted from a given enum unless the name is reserved."},
+		{ID: "FIELD_NO_DELETE_UNLESS_NAME_RESERVED", Categories: []string{"WIRE_JSON", "JSON"}, Default: false, Purpose: "Checks that fields are not deleted from a given message unless the name is reserved."},
+		{ID: "FIELD_WIRE_JSON_COMPATIBLE_CARDINALITY", Categories: []string{"WIRE_JSON", "JSON"}, Default: false, Purpose: "Checks that fields have wire and JSON compatible cardinalities in a given message."},
+		{ID: "FIELD_WIRE_JSON_COMPATIBLE_TYPE", Categories: []string{"WIRE_JSON", "JSON"}, Default: false, Purpose: "Checks that fields have wire and JSON compatible types in a given message."},
 		{ID: "ENUM_VALUE_NO_DELETE_UNLESS_NUMBER_RESERVED", Categories: []string{"WIRE_JSON", "WIRE"}, Default: false, Purpose: "Checks that enum values are not deleted from a given enum unless the number is reserved."},
 		{ID: "FIELD_NO_DELETE_UNLESS_NUMBER_RESERVED", Categories: []string{"WIRE_JSON", "WIRE"}, Default: false, Purpose: "Checks that fields are not deleted from a given message unless the number is reserved."},
 		{ID: "FIELD_WIRE_COMPATIBLE_CARDINALITY", Categories: []string{"WIRE"}, Default: false, Purpose: "Checks that fields have wire-compatible cardinalities in a given message."},
 		{ID: "FIELD_WIRE_COMPATIBLE_TYPE", Categories: []string{"WIRE"}, Default: false, Purpose: "Checks that fields have wire-compatible types in a given message."},
+		{ID: "PROTOVALIDATE_FIELD_NO_ADD_CEL", Categories: []string{"PROTOVALIDATE_CONSTRAINTS"}, Default: false, Purpose: "Checks that fields do not have protovalidate CEL constraints added."},
+		{ID: "PROTOVALIDATE_FIELD_NO_ADD_REQUIRED", Categories: []string{"PROTOVALIDATE_CONSTRAINTS"}, Default: false, Purpose: "Checks that fields do not have the protovalidate required constraint added."},
+		{ID: "PROTOVALIDATE_FIELD_NO_NARROW_RANGE", Categories: []string{"PROTOVALIDATE_CONSTRAINTS"}, Default: false, Purpose: "Checks that fields do not have the range of values allowed by protovalidate constraints narrowed."},
+		{ID: "PROTOVALIDATE_FIELD_NO_REMOVE_ENUM_VALUE", Categories: []string{"PROTOVALIDATE_CONSTRAINTS"}, Default: false, Purpose: "Checks that enum fields do not have enum values allowed by protovalidate constraints removed."},
+		{ID: "PROTOVALIDATE_MESSAGE_NO_ADD_CEL", Categories: []string{"PROTOVALIDATE_CONSTRAINTS"}, Default: false, Purpose: "Checks that messages do not have protovalidate CEL constraints added."},
 	}
 )
 
@@ -584,11 +594,11 @@ func TestFailCheckBreaking1(t *testing.T) {
 		nil,
 		bufctl.ExitCodeFileAnnotation,
 		filepath.FromSlash(`
-		../../../bufpkg/bufcheck/testdata/breaking/current/breaking_field_no_delete/1.proto:5:1:Previously present field "3" with name "three" on message "Two" was deleted.
-		../../../bufpkg/bufcheck/testdata/breaking/current/breaking_field_no_delete/1.proto:10:1:Previously present field "3" with name "three" on message "Three" was deleted.
-		../../../bufpkg/bufcheck/testdata/breaking/current/breaking_field_no_delete/1.proto:12:5:Previously present field "3" with name "three" on message "Five" was deleted.
-		../../../bufpkg/bufcheck/testdata/breaking/current/breaking_field_no_delete/1.proto:22:3:Previously present field "3" with name "three" on message "Seven" was deleted.
-		../../../bufpkg/bufcheck/testdata/breaking/current/breaking_field_no_delete/2.proto:57:1:Previously present field "3" with name "three" on message "Nine" was deleted.
+		../../../bufpkg/bufcheck/testdata/breaking/current/breaking_field_no_delete/1.proto:5:1:Previously present field "3" with name "three" on message "Two" was deleted. [impact: wire]
+		../../../bufpkg/bufcheck/testdata/breaking/current/breaking_field_no_delete/1.proto:10:1:Previously present field "3" with name "three" on message "Three" was deleted. [impact: wire]
+		../../../bufpkg/bufcheck/testdata/breaking/current/breaking_field_no_delete/1.proto:12:5:Previously present field "3" with name "three" on message "Five" was deleted. [impact: wire]
+		../../../bufpkg/bufcheck/testdata/breaking/current/breaking_field_no_delete/1.proto:22:3:Previously present field "3" with name "three" on message "Seven" was deleted. [impact: wire]
+		../../../bufpkg/bufcheck/testdata/breaking/current/breaking_field_no_delete/2.proto:57:1:Previously present field "3" with name "three" on message "Nine" was deleted. [impact: wire]
 		`),
 		"", // stderr should be empty
 		"breaking",
@@ -605,7 +615,7 @@ func TestFailCheckBreaking2(t *testing.T) {
 		t,
 		nil,
 		bufctl.ExitCodeFileAnnotation,
-		filepath.FromSlash(`testdata/protofileref/breaking/a/foo.proto:7:3:Field "2" with name "world" on message "Foo" changed type from "int32" to "string".`),
+		filepath.FromSlash(`testdata/protofileref/breaking/a/foo.proto:7:3:Field "2" with name "world" on message "Foo" changed type from "int32" to "string". [impact: wire]`),
 		"breaking",
 		filepath.Join("testdata", "protofileref", "breaking", "a", "foo.proto"),
 		"--against",
@@ -620,8 +630,8 @@ func TestFailCheckBreaking3(t *testing.T) {
 		nil,
 		bufctl.ExitCodeFileAnnotation,
 		filepath.FromSlash(`
-		<input>:1:1:Previously present file "bar.proto" was deleted.
-		testdata/protofileref/breaking/a/foo.proto:7:3:Field "2" with name "world" on message "Foo" changed type from "int32" to "string".
+		<input>:1:1:Previously present file "bar.proto" was deleted. [impact: source]
+		testdata/protofileref/breaking/a/foo.proto:7:3:Field "2" with name "world" on message "Foo" changed type from "int32" to "string". [impact: wire]
 		`),
 		"breaking",
 		filepath.Join("testdata", "protofileref", "breaking", "a", "foo.proto"),
@@ -637,8 +647,8 @@ func TestFailCheckBreaking4(t *testing.T) {
 		nil,
 		bufctl.ExitCodeFileAnnotation,
 		filepath.FromSlash(`
-		testdata/protofileref/breaking/a/bar.proto:5:1:Previously present field "2" with name "value" on message "Bar" was deleted.
-		testdata/protofileref/breaking/a/foo.proto:7:3:Field "2" with name "world" on message "Foo" changed type from "int32" to "string".
+		testdata/protofileref/breaking/a/bar.proto:5:1:Previously present field "2" with name "value" on message "Bar" was deleted. [impact: wire]
+		testdata/protofileref/breaking/a/foo.proto:7:3:Field "2" with name "world" on message "Foo" changed type from "int32" to "string". [impact: wire]
 		`),
 		"breaking",
 		fmt.Sprintf("%s#include_package_files=true", filepath.Join("testdata", "protofileref", "breaking", "a", "foo.proto")),
@@ -654,8 +664,8 @@ func TestFailCheckBreaking5(t *testing.T) {
 		nil,
 		bufctl.ExitCodeFileAnnotation,
 		filepath.FromSlash(`
-    <input>:1:1:Previously present file "bar.proto" was deleted.
-		testdata/protofileref/breaking/a/foo.proto:7:3:Field "2" with name "world" on message "Foo" changed type from "int32" to "string".
+    <input>:1:1:Previously present file "bar.proto" was deleted. [impact: source]
+		testdata/protofileref/breaking/a/foo.proto:7:3:Field "2" with name "world" on message "Foo" changed type from "int32" to "string". [impact: wire]
 		`),
 		"breaking",
 		filepath.Join("testdata", "protofileref", "breaking", "a", "foo.proto"),
@@ -965,6 +975,18 @@ COMMENT_RPC                        COMMENTS                           Checks tha
 COMMENT_SERVICE                    COMMENTS                           Checks that services have non-empty comments.
 RPC_NO_CLIENT_STREAMING            UNARY_RPC                          Checks that RPCs are not client streaming.
 RPC_NO_SERVER_STREAMING            UNARY_RPC                          Checks that RPCs are not server streaming.
+GENERATED_NAMES_CSHARP             GENERATED_NAMES                    Checks that identifiers generated for C# do not collide with each other or with names reserved by the generated code.
+GENERATED_NAMES_GO                 GENERATED_NAMES                    Checks that identifiers generated for Go do not collide with each other or with names reserved by the generated code.
+GENERATED_NAMES_JAVA               GENERATED_NAMES                    Checks that identifiers generated for Java do not collide with each other or with names reserved by the generated code.
+GENERATED_NAMES_PYTHON             GENERATED_NAMES                    Checks that identifiers generated for Python do not collide with each other or with keywords or names reserved by the generated code.
+NAMING_ENUM                        NAMING                             Checks that enum names match the pattern set with lint.naming.enum.
+NAMING_ENUM_VALUE                  NAMING                             Checks that enum value names match the pattern set with lint.naming.enum_value.
+NAMING_FIELD                       NAMING                             Checks that field names match the pattern set with lint.naming.field.
+NAMING_MESSAGE                     NAMING                             Checks that message names match the pattern set with lint.naming.message.
+NAMING_RPC                         NAMING                             Checks that RPC names match the pattern set with lint.naming.rpc.
+NAMING_SERVICE                     NAMING                             Checks that service names match the pattern set with lint.naming.service.
+EXTENSION_REGISTRY_NO_CONFLICT                                        Checks that extensions do not use a number allocated to another extension of the same message in the extension registry.
+RESERVED_REGISTRY_NO_REUSE                                            Checks that fields and enum values do not reuse a number or name recorded in the reserved registry.
 STABLE_PACKAGE_NO_IMPORT_UNSTABLE                                     Checks that all files that have stable versioned packages do not import packages with unstable version packages.
 		`
 	testRunStdout(
@@ -1017,11 +1039,11 @@ FILE_SAME_SYNTAX                                FILE, PACKAGE
 MESSAGE_NO_REMOVE_STANDARD_DESCRIPTOR_ACCESSOR  FILE, PACKAGE                   *        Checks that messages do not change the no_standard_descriptor_accessor option from false or unset to true.
 ONEOF_NO_DELETE                                 FILE, PACKAGE                   *        Checks that oneofs are not deleted from a given message.
 RPC_NO_DELETE                                   FILE, PACKAGE                   *        Checks that rpcs are not deleted from a given service.
-ENUM_SAME_JSON_FORMAT                           FILE, PACKAGE, WIRE_JSON        *        Checks that enums have the same JSON format support.
-ENUM_VALUE_SAME_NAME                            FILE, PACKAGE, WIRE_JSON        *        Checks that enum values have the same name.
-FIELD_SAME_JSON_NAME                            FILE, PACKAGE, WIRE_JSON        *        Checks that fields have the same value for the json_name option.
-FIELD_SAME_NAME                                 FILE, PACKAGE, WIRE_JSON        *        Checks that fields have the same names in a given message.
-MESSAGE_SAME_JSON_FORMAT                        FILE, PACKAGE, WIRE_JSON        *        Checks that messages have the same JSON format support.
+ENUM_SAME_JSON_FORMAT                           FILE, PACKAGE, WIRE_JSON, JSON  *        Checks that enums have the same JSON format support.
+ENUM_VALUE_SAME_NAME                            FILE, PACKAGE, WIRE_JSON, JSON  *        Checks that enum values have the same name.
+FIELD_SAME_JSON_NAME                            FILE, PACKAGE, WIRE_JSON, JSON  *        Checks that fields have the same value for the json_name option.
+FIELD_SAME_NAME                                 FILE, PACKAGE, WIRE_JSON, JSON  *        Checks that fields have the same names in a given message.
+MESSAGE_SAME_JSON_FORMAT                        FILE, PACKAGE, WIRE_JSON, JSON  *        Checks that messages have the same JSON format support.
 FIELD_SAME_ONEOF                                FILE, PACKAGE, WIRE_JSON, WIRE  *        Checks that fields have the same oneofs in a given message.
 FILE_SAME_PACKAGE                               FILE, PACKAGE, WIRE_JSON, WIRE  *        Checks that files have the same package.
 MESSAGE_SAME_REQUIRED_FIELDS                    FILE, PACKAGE, WIRE_JSON, WIRE  *        Checks that messages have no added or deleted required fields.
@@ -1036,14 +1058,19 @@ PACKAGE_ENUM_NO_DELETE                          PACKAGE
 PACKAGE_MESSAGE_NO_DELETE                       PACKAGE                                  Checks that messages are not deleted from a given package.
 PACKAGE_NO_DELETE                               PACKAGE                                  Checks that packages are not deleted.
 PACKAGE_SERVICE_NO_DELETE                       PACKAGE                                  Checks that services are not deleted from a given package.
-ENUM_VALUE_NO_DELETE_UNLESS_NAME_RESERVED       WIRE_JSON                                Checks that enum values are not deleted from a given enum unless the name is reserved.
-FIELD_NO_DELETE_UNLESS_NAME_RESERVED            WIRE_JSON                                Checks that fields are not deleted from a given message unless the name is reserved.
-FIELD_WIRE_JSON_COMPATIBLE_CARDINALITY          WIRE_JSON                                Checks that fields have wire and JSON compatible cardinalities in a given message.
-FIELD_WIRE_JSON_COMPATIBLE_TYPE                 WIRE_JSON                                Checks that fields have wire and JSON compatible types in a given message.
+ENUM_VALUE_NO_DELETE_UNLESS_NAME_RESERVED       WIRE_JSON, JSON                          Checks that enum values are not deleted from a given enum unless the name is reserved.
+FIELD_NO_DELETE_UNLESS_NAME_RESERVED            WIRE_JSON, JSON                          Checks that fields are not deleted from a given message unless the name is reserved.
+FIELD_WIRE_JSON_COMPATIBLE_CARDINALITY          WIRE_JSON, JSON                          Checks that fields have wire and JSON compatible cardinalities in a given message.
+FIELD_WIRE_JSON_COMPATIBLE_TYPE                 WIRE_JSON, JSON                          Checks that fields have wire and JSON compatible types in a given message.
 ENUM_VALUE_NO_DELETE_UNLESS_NUMBER_RESERVED     WIRE_JSON, WIRE                          Checks that enum values are not deleted from a given enum unless the number is reserved.
 FIELD_NO_DELETE_UNLESS_NUMBER_RESERVED          WIRE_JSON, WIRE                          Checks that fields are not deleted from a given message unless the number is reserved.
 FIELD_WIRE_COMPATIBLE_CARDINALITY               WIRE                                     Checks that fields have wire-compatible cardinalities in a given message.
 FIELD_WIRE_COMPATIBLE_TYPE                      WIRE                                     Checks that fields have wire-compatible types in a given message.
+PROTOVALIDATE_FIELD_NO_ADD_CEL                  PROTOVALIDATE_CONSTRAINTS                Checks that fields do not have protovalidate CEL constraints added.
+PROTOVALIDATE_FIELD_NO_ADD_REQUIRED             PROTOVALIDATE_CONSTRAINTS                Checks that fields do not have the protovalidate required constraint added.
+PROTOVALIDATE_FIELD_NO_NARROW_RANGE             PROTOVALIDATE_CONSTRAINTS                Checks that fields do not have the range of values allowed by protovalidate constraints narrowed.
+PROTOVALIDATE_FIELD_NO_REMOVE_ENUM_VALUE        PROTOVALIDATE_CONSTRAINTS                Checks that enum fields do not have enum values allowed by protovalidate constraints removed.
+PROTOVALIDATE_MESSAGE_NO_ADD_CEL                PROTOVALIDATE_CONSTRAINTS                Checks that messages do not have protovalidate CEL constraints added.
 		`
 	testRunStdout(
 		t,
@@ -1172,11 +1199,11 @@ FILE_SAME_SYNTAX                                FILE, PACKAGE
 MESSAGE_NO_REMOVE_STANDARD_DESCRIPTOR_ACCESSOR  FILE, PACKAGE                   *        Checks that messages do not change the no_standard_descriptor_accessor option from false or unset to true.
 ONEOF_NO_DELETE                                 FILE, PACKAGE                   *        Checks that oneofs are not deleted from a given message.
 RPC_NO_DELETE                                   FILE, PACKAGE                   *        Checks that rpcs are not deleted from a given service.
-ENUM_SAME_JSON_FORMAT                           FILE, PACKAGE, WIRE_JSON        *        Checks that enums have the same JSON format support.
-ENUM_VALUE_SAME_NAME                            FILE, PACKAGE, WIRE_JSON        *        Checks that enum values have the same name.
-FIELD_SAME_JSON_NAME                            FILE, PACKAGE, WIRE_JSON        *        Checks that fields have the same value for the json_name option.
-FIELD_SAME_NAME                                 FILE, PACKAGE, WIRE_JSON        *        Checks that fields have the same names in a given message.
-MESSAGE_SAME_JSON_FORMAT                        FILE, PACKAGE, WIRE_JSON        *        Checks that messages have the same JSON format support.
+ENUM_SAME_JSON_FORMAT                           FILE, PACKAGE, WIRE_JSON, JSON  *        Checks that enums have the same JSON format support.
+ENUM_VALUE_SAME_NAME                            FILE, PACKAGE, WIRE_JSON, JSON  *        Checks that enum values have the same name.
+FIELD_SAME_JSON_NAME                            FILE, PACKAGE, WIRE_JSON, JSON  *        Checks that fields have the same value for the json_name option.
+FIELD_SAME_NAME                                 FILE, PACKAGE, WIRE_JSON, JSON  *        Checks that fields have the same names in a given message.
+MESSAGE_SAME_JSON_FORMAT                        FILE, PACKAGE, WIRE_JSON, JSON  *        Checks that messages have the same JSON format support.
 FIELD_SAME_DEFAULT                              FILE, PACKAGE, WIRE_JSON, WIRE  *        Checks that fields have the same default value, if a default is specified.
 FIELD_SAME_ONEOF                                FILE, PACKAGE, WIRE_JSON, WIRE  *        Checks that fields have the same oneofs in a given message.
 FILE_SAME_PACKAGE                               FILE, PACKAGE, WIRE_JSON, WIRE  *        Checks that files have the same package.
@@ -1193,14 +1220,19 @@ PACKAGE_EXTENSION_NO_DELETE                     PACKAGE
 PACKAGE_MESSAGE_NO_DELETE                       PACKAGE                                  Checks that messages are not deleted from a given package.
 PACKAGE_NO_DELETE                               PACKAGE                                  Checks that packages are not deleted.
 PACKAGE_SERVICE_NO_DELETE                       PACKAGE                                  Checks that services are not deleted from a given package.
-ENUM_VALUE_NO_DELETE_UNLESS_NAME_RESERVED       WIRE_JSON                                Checks that enum values are not deleted from a given enum unless the name is reserved.
-FIELD_NO_DELETE_UNLESS_NAME_RESERVED            WIRE_JSON                                Checks that fields are not deleted from a given message unless the name is reserved.
-FIELD_WIRE_JSON_COMPATIBLE_CARDINALITY          WIRE_JSON                                Checks that fields have wire and JSON compatible cardinalities in a given message.
-FIELD_WIRE_JSON_COMPATIBLE_TYPE                 WIRE_JSON                                Checks that fields have wire and JSON compatible types in a given message.
+ENUM_VALUE_NO_DELETE_UNLESS_NAME_RESERVED       WIRE_JSON, JSON                          Checks that enum values are not deleted from a given enum unless the name is reserved.
+FIELD_NO_DELETE_UNLESS_NAME_RESERVED            WIRE_JSON, JSON                          Checks that fields are not deleted from a given message unless the name is reserved.
+FIELD_WIRE_JSON_COMPATIBLE_CARDINALITY          WIRE_JSON, JSON                          Checks that fields have wire and JSON compatible cardinalities in a given message.
+FIELD_WIRE_JSON_COMPATIBLE_TYPE                 WIRE_JSON, JSON                          Checks that fields have wire and JSON compatible types in a given message.
 ENUM_VALUE_NO_DELETE_UNLESS_NUMBER_RESERVED     WIRE_JSON, WIRE                          Checks that enum values are not deleted from a given enum unless the number is reserved.
 FIELD_NO_DELETE_UNLESS_NUMBER_RESERVED          WIRE_JSON, WIRE                          Checks that fields are not deleted from a given message unless the number is reserved.
 FIELD_WIRE_COMPATIBLE_CARDINALITY               WIRE                                     Checks that fields have wire-compatible cardinalities in a given message.
 FIELD_WIRE_COMPATIBLE_TYPE                      WIRE                                     Checks that fields have wire-compatible types in a given message.
+PROTOVALIDATE_FIELD_NO_ADD_CEL                  PROTOVALIDATE_CONSTRAINTS                Checks that fields do not have protovalidate CEL constraints added.
+PROTOVALIDATE_FIELD_NO_ADD_REQUIRED             PROTOVALIDATE_CONSTRAINTS                Checks that fields do not have the protovalidate required constraint added.
+PROTOVALIDATE_FIELD_NO_NARROW_RANGE             PROTOVALIDATE_CONSTRAINTS                Checks that fields do not have the range of values allowed by protovalidate constraints narrowed.
+PROTOVALIDATE_FIELD_NO_REMOVE_ENUM_VALUE        PROTOVALIDATE_CONSTRAINTS                Checks that enum fields do not have enum values allowed by protovalidate constraints removed.
+PROTOVALIDATE_MESSAGE_NO_ADD_CEL                PROTOVALIDATE_CONSTRAINTS                Checks that messages do not have protovalidate CEL constraints added.
 		`
 	testRunStdout(
 		t,
@@ -1341,6 +1373,64 @@ func TestCheckLsBreakingRulesFromConfigNotNamedBufYAML(t *testing.T) {
 	)
 }
 
+func TestCheckLsRulesMarkdown(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		nil,
+		0,
+		`
+		# Builtin rules
+
+		## ENUM_VALUE_NO_DELETE
+
+		Checks that enum values are not deleted from a given enum.
+
+		- **Categories:** `+"`FILE`, `PACKAGE`"+`
+		- **Default:** yes
+		- **Documentation:** https://buf.build/docs/breaking/rules/#enum_value_no_delete
+
+		## FIELD_SAME_JSTYPE
+
+		Checks that fields have the same value for the jstype option.
+
+		- **Categories:** `+"`FILE`, `PACKAGE`"+`
+		- **Default:** yes
+		- **Documentation:** https://buf.build/docs/breaking/rules/#field_same_jstype
+		`,
+		"mod",
+		"ls-breaking-rules",
+		"--config",
+		filepath.Join("testdata", "small_list_rules_yml", "config.yml"),
+		"--format",
+		"markdown",
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		`
+		# Builtin rules
+
+		## SERVICE_SUFFIX
+
+		Checks that services have a consistent suffix (configurable, default suffix is "Service").
+
+		- **Categories:** `+"`STANDARD`"+`
+		- **Default:** yes
+		- **Configuration:** `+"`lint.service_suffix`"+`
+		- **Documentation:** https://buf.build/docs/lint/rules/#service_suffix
+		`,
+		"config",
+		"ls-lint-rules",
+		"--configured-only",
+		"--config",
+		`{"version":"v2","lint":{"use":["SERVICE_SUFFIX"]}}`,
+		"--format",
+		"markdown",
+	)
+}
+
 func TestCheckLsBreakingRulesFromConfigExceptDeprecated(t *testing.T) {
 	t.Parallel()
 
@@ -2138,6 +2228,108 @@ func TestLsBreakingRulesDeprecated(t *testing.T) {
 	assert.NotContains(t, stdout.String(), "FILE_SAME_PHP_GENERIC_SERVICES")
 }
 
+func TestExplain(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		nil,
+		0,
+		`
+ENUM_PASCAL_CASE (lint)
+
+Checks that enums are PascalCase.
+
+Categories: BASIC, STANDARD
+Default: true
+
+Rationale:
+  Enum names are used to generate type names in many languages. Plugins assume enum names are PascalCase to generate idiomatic names.
+
+Triggers:
+  - An enum name is not PascalCase.
+
+Remediations:
+  - Rename the enum to PascalCase. This is a breaking change for generated code.
+
+Config keys:
+  - lint.use
+  - lint.except
+  - lint.ignore
+  - lint.ignore_only
+  - lint.disallow_comment_ignores
+
+Examples:
+  Renaming a snake_case enum.
+
+    -enum order_status {
+    +enum OrderStatus {
+       ORDER_STATUS_UNSPECIFIED = 0;
+     }
+        `,
+		"explain",
+		"enum_pascal_case",
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		`
+# SERVICE_SUFFIX
+
+Checks that services have a consistent suffix (configurable, default suffix is "Service").
+
+- **Type:** lint
+- **Categories:** `+"`STANDARD`"+`
+- **Default:** true
+
+## Rationale
+
+A consistent suffix makes services easy to distinguish from messages and enums in generated code.
+
+## Triggers
+
+- A service name does not end with the configured suffix.
+
+## Remediations
+
+- Rename the service to end with the configured suffix, by default Service.
+- Change the suffix with lint.service_suffix.
+
+## Config keys
+
+- `+"`lint.service_suffix`"+`
+- `+"`lint.use`"+`
+- `+"`lint.except`"+`
+- `+"`lint.ignore`"+`
+- `+"`lint.ignore_only`"+`
+- `+"`lint.disallow_comment_ignores`"+`
+
+## Examples
+
+Renaming a service to use the default suffix.
+
+`+"```diff"+`
+-service Users {
++service UserService {
+   rpc GetUser(GetUserRequest) returns (GetUserResponse);
+ }
+`+"```"+`
+        `,
+		"explain",
+		"SERVICE_SUFFIX",
+		"--format",
+		"markdown",
+	)
+	testRunStderr(
+		t,
+		nil,
+		1,
+		`Failure: unknown rule "NOT_A_RULE"`,
+		"explain",
+		"NOT_A_RULE",
+	)
+}
+
 func TestLsFiles(t *testing.T) {
 	t.Parallel()
 	testRunStdout(
@@ -2545,6 +2737,174 @@ testdata/paths/a/v3/foo/foo.proto`),
 	)
 }
 
+func TestLsFilesGitExportIgnore(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "testdata"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "buf.yaml"), []byte("version: v2\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "a.proto"), []byte("syntax = \"proto3\";\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "testdata", "invalid.proto"), []byte("invalid\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".gitattributes"), []byte("testdata export-ignore\n"), 0600))
+	testRunGit(t, tempDir, "init")
+	testRunGit(t, tempDir, "checkout", "-b", "main")
+	testRunGit(t, tempDir, "add", ".")
+	testRunGit(t, tempDir, "commit", "-m", "commit 0")
+	input := filepath.Join(tempDir, ".git") + "#branch=main"
+	// Files with the export-ignore attribute are excluded, as with git archive.
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"a.proto",
+		"ls-files",
+		input,
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"a.proto\ntestdata/invalid.proto",
+		"ls-files",
+		input,
+		"--disable-git-attributes",
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"build",
+		input,
+	)
+}
+
+func TestBreakingGitMergeBase(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "buf.yaml"), []byte("version: v2\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "a.proto"), []byte("syntax = \"proto3\";\npackage a;\nmessage M {\n  string one = 1;\n}\n"), 0600))
+	testRunGit(t, tempDir, "init")
+	testRunGit(t, tempDir, "checkout", "-b", "main")
+	testRunGit(t, tempDir, "add", ".")
+	testRunGit(t, tempDir, "commit", "-m", "commit 0")
+	testRunGit(t, tempDir, "branch", "feature")
+	// Advance main after feature was forked from it.
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "a.proto"), []byte("syntax = \"proto3\";\npackage a;\nmessage M {\n  string one = 1;\n  string two = 2;\n}\n"), 0600))
+	testRunGit(t, tempDir, "commit", "-a", "-m", "commit 1")
+	testRunGit(t, tempDir, "tag", "v1")
+	testRunGit(t, tempDir, "checkout", "feature")
+	gitDirPath := filepath.Join(tempDir, ".git")
+	breakingChange := filepath.Join(tempDir, "a.proto") + `:3:1:Previously present field "2" with name "two" on message "M" was deleted. [impact: wire]`
+	// The branch is compared against at its merge base with HEAD.
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"breaking",
+		tempDir,
+		"--against",
+		gitDirPath+"#branch=main",
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		breakingChange,
+		"breaking",
+		tempDir,
+		"--against",
+		gitDirPath+"#branch=main",
+		"--disable-merge-base",
+	)
+	// Tags are compared against as given.
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		breakingChange,
+		"breaking",
+		tempDir,
+		"--against",
+		gitDirPath+"#tag=v1",
+	)
+	// --from is an alias of <input>, and the branch is compared against in the same way.
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"breaking",
+		"--from",
+		tempDir,
+		"--against",
+		gitDirPath+"#branch=main",
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		breakingChange,
+		"breaking",
+		"--from",
+		tempDir,
+		"--against",
+		gitDirPath+"#branch=main",
+		"--disable-merge-base",
+	)
+}
+
+func TestBreakingGitForkPoint(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "buf.yaml"), []byte("version: v2\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "a.proto"), []byte("syntax = \"proto3\";\npackage a;\nmessage M {\n  string one = 1;\n}\n"), 0600))
+	testRunGit(t, tempDir, "init")
+	testRunGit(t, tempDir, "checkout", "-b", "main")
+	testRunGit(t, tempDir, "add", ".")
+	testRunGit(t, tempDir, "commit", "-m", "commit 0")
+	testRunGit(t, tempDir, "branch", "feature")
+	// Advance main after feature was forked from it.
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "a.proto"), []byte("syntax = \"proto3\";\npackage a;\nmessage M {\n  string one = 1;\n  string two = 2;\n}\n"), 0600))
+	testRunGit(t, tempDir, "commit", "-a", "-m", "commit 1")
+	// Delete a field on feature, so that HEAD differs from the fork point.
+	testRunGit(t, tempDir, "checkout", "feature")
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "a.proto"), []byte("syntax = \"proto3\";\npackage a;\nmessage M {}\n"), 0600))
+	testRunGit(t, tempDir, "commit", "-a", "-m", "commit 2")
+	gitDirPath := filepath.Join(tempDir, ".git")
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.Join(tempDir, "a.proto")+`:3:1:Previously present field "1" with name "one" on message "M" was deleted. [impact: wire]`,
+		"breaking",
+		tempDir,
+		"--against",
+		gitDirPath+"#fork_point=main",
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.Join(tempDir, "a.proto")+`:3:1:Previously present field "1" with name "one" on message "M" was deleted. [impact: wire]`,
+		"breaking",
+		tempDir,
+		"--against",
+		gitDirPath+"#fork_point=main",
+		"--disable-merge-base",
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"breaking",
+		tempDir,
+		"--against",
+		gitDirPath,
+	)
+}
+
 func TestBuildOverlappingPaths(t *testing.T) {
 	t.Parallel()
 	// This may differ from LsFilesOverlappingPaths as we do a build of an image here.
@@ -2666,6 +3026,86 @@ func TestExportExcludeImports(t *testing.T) {
 	)
 }
 
+func TestExportArchive(t *testing.T) {
+	t.Parallel()
+	for _, extension := range []string{".zip", ".tar", ".tar.gz"} {
+		t.Run(extension, func(t *testing.T) {
+			t.Parallel()
+			var archiveDatas [][]byte
+			for range 2 {
+				archivePath := filepath.Join(t.TempDir(), "out"+extension)
+				testRunStdout(
+					t,
+					nil,
+					0,
+					``,
+					"export",
+					"-o",
+					archivePath,
+					"--archive-timestamp",
+					"2024-01-02T03:04:05Z",
+					filepath.Join("testdata", "export"),
+				)
+				archiveData, err := os.ReadFile(archivePath)
+				require.NoError(t, err)
+				archiveDatas = append(archiveDatas, archiveData)
+			}
+			// Exporting the same input results in byte-identical archives.
+			require.Equal(t, archiveDatas[0], archiveDatas[1])
+		})
+	}
+	tarPath := filepath.Join(t.TempDir(), "out.tar")
+	testRunStdout(
+		t,
+		nil,
+		0,
+		``,
+		"export",
+		"-o",
+		tarPath,
+		"--archive-timestamp",
+		"1700000000",
+		filepath.Join("testdata", "export"),
+	)
+	tarFile, err := os.Open(tarPath)
+	require.NoError(t, err)
+	defer tarFile.Close()
+	tarReader := tar.NewReader(tarFile)
+	var paths []string
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, int64(0644), header.Mode)
+		assert.Equal(t, time.Unix(1700000000, 0).UTC(), header.ModTime.UTC())
+		paths = append(paths, header.Name)
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"another.proto",
+			"request.proto",
+			"rpc.proto",
+			"unimported.proto",
+		},
+		paths,
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{`Failure: --archive-timestamp can only be set when --output is an archive`},
+		"export",
+		"-o",
+		t.TempDir(),
+		"--archive-timestamp",
+		"epoch",
+		filepath.Join("testdata", "export"),
+	)
+}
+
 func TestExportPaths(t *testing.T) {
 	t.Parallel()
 	tempDir := t.TempDir()
@@ -3105,45 +3545,260 @@ testdata/check_plugins/current/proto/common/v1/common.proto:8:5:Field name "comm
 	)
 }
 
-func TestBreakingWithPaths(t *testing.T) {
+func TestBreakingImpact(t *testing.T) {
 	t.Parallel()
-	tempDir := t.TempDir()
-	testRunStdout(t, nil, 0, ``, "build", filepath.Join("command", "generate", "testdata", "paths"), "-o", filepath.Join(tempDir, "previous.binpb"))
-	testRunStdout(t, nil, 0, ``, "build", filepath.Join("testdata", "paths"), "-o", filepath.Join(tempDir, "current.binpb"))
-	readWriteBucket, err := storageos.NewProvider().NewReadWriteBucket(tempDir)
-	require.NoError(t, err)
-	storagetesting.AssertPaths(
-		t,
-		readWriteBucket,
-		"",
-		"previous.binpb",
-		"current.binpb",
-	)
-	testRunStdoutStderrNoWarn(
+	// FIELD_NO_DELETE is only in FILE and PACKAGE, but the deleted field number
+	// was not reserved, which also breaks the wire format.
+	testRunStdout(
 		t,
 		nil,
 		bufctl.ExitCodeFileAnnotation,
-		`a/v3/a.proto:6:3:Field "1" with name "key" on message "Foo" changed type from "string" to "int32".
-a/v3/a.proto:7:3:Field "2" with name "Value" on message "Foo" changed option "json_name" from "value" to "Value".
-a/v3/a.proto:7:10:Field "2" on message "Foo" changed name from "value" to "Value".`,
-		"",
+		filepath.FromSlash(`{"path":"testdata/workspace/success/breaking/other/proto/request.proto","start_line":5,"start_column":1,"end_line":5,"end_column":19,"type":"FIELD_NO_DELETE","message":"Previously present field \"1\" with name \"name\" on message \"Request\" was deleted.","impact":"wire"}
+{"path":"testdata/workspace/success/breaking/proto/rpc.proto","start_line":8,"start_column":5,"end_line":8,"end_column":33,"type":"FIELD_SAME_JSON_NAME","message":"Field \"1\" with name \"request\" on message \"RPC\" changed option \"json_name\" from \"req\" to \"request\".","impact":"json"}
+{"path":"testdata/workspace/success/breaking/proto/rpc.proto","start_line":8,"start_column":21,"end_line":8,"end_column":28,"type":"FIELD_SAME_NAME","message":"Field \"1\" on message \"RPC\" changed name from \"req\" to \"request\".","impact":"json"}`),
 		"breaking",
-		filepath.Join(tempDir, "current.binpb"),
+		filepath.Join("testdata", "workspace", "success", "breaking"),
 		"--against",
-		filepath.Join(tempDir, "previous.binpb"),
-		"--path",
-		filepath.Join("a", "v3"),
-		"--exclude-path",
-		filepath.Join("a", "v3", "foo"),
+		filepath.Join("testdata", "workspace", "success", "dir"),
+		"--error-format",
+		"json",
 	)
-	testRunStdoutStderrNoWarn(
+	testRunStdout(
 		t,
 		nil,
 		bufctl.ExitCodeFileAnnotation,
-		`a/v3/a.proto:6:3:Field "1" with name "key" on message "Foo" changed type from "string" to "int32". See https://developers.google.com/protocol-buffers/docs/proto3#updating for wire compatibility rules.`,
-		"",
+		filepath.FromSlash(`testdata/workspace/success/breaking/other/proto/request.proto(5,1) : error FIELD_NO_DELETE : Previously present field "1" with name "name" on message "Request" was deleted. [impact: wire]
+testdata/workspace/success/breaking/proto/rpc.proto(8,5) : error FIELD_SAME_JSON_NAME : Field "1" with name "request" on message "RPC" changed option "json_name" from "req" to "request". [impact: json]
+testdata/workspace/success/breaking/proto/rpc.proto(8,21) : error FIELD_SAME_NAME : Field "1" on message "RPC" changed name from "req" to "request". [impact: json]`),
 		"breaking",
-		filepath.Join(tempDir, "current.binpb"),
+		filepath.Join("testdata", "workspace", "success", "breaking"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+		"--error-format",
+		"msvs",
+	)
+}
+
+func TestBreakingFailOn(t *testing.T) {
+	t.Parallel()
+	// FIELD_NO_DELETE is not in WIRE, but FIELD_NO_DELETE_UNLESS_NUMBER_RESERVED, which
+	// is in WIRE, reports the deleted field at the same location.
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`testdata/workspace/success/breaking/other/proto/request.proto(5,1) : error FIELD_NO_DELETE : Previously present field "1" with name "name" on message "Request" was deleted. [impact: wire]
+testdata/workspace/success/breaking/proto/rpc.proto(8,5) : warning FIELD_SAME_JSON_NAME : Field "1" with name "request" on message "RPC" changed option "json_name" from "req" to "request". [impact: json]
+testdata/workspace/success/breaking/proto/rpc.proto(8,21) : warning FIELD_SAME_NAME : Field "1" on message "RPC" changed name from "req" to "request". [impact: json]`),
+		"breaking",
+		filepath.Join("testdata", "workspace", "success", "breaking"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+		"--error-format",
+		"msvs",
+		"--fail-on",
+		"WIRE",
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		filepath.FromSlash(`testdata/workspace/success/breaking/other/proto/request.proto(5,1) : warning FIELD_NO_DELETE : Previously present field "1" with name "name" on message "Request" was deleted. [impact: wire]
+testdata/workspace/success/breaking/proto/rpc.proto(8,5) : warning FIELD_SAME_JSON_NAME : Field "1" with name "request" on message "RPC" changed option "json_name" from "req" to "request". [impact: json]
+testdata/workspace/success/breaking/proto/rpc.proto(8,21) : warning FIELD_SAME_NAME : Field "1" on message "RPC" changed name from "req" to "request". [impact: json]`),
+		"breaking",
+		filepath.Join("testdata", "workspace", "success", "breaking"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+		"--error-format",
+		"msvs",
+		"--fail-on",
+		"ENUM_NO_DELETE",
+	)
+}
+
+func TestBreakingShowSource(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`{"path":"testdata/workspace/success/breaking/other/proto/request.proto","start_line":5,"start_column":1,"end_line":5,"end_column":19,"type":"FIELD_NO_DELETE","message":"Previously present field \"1\" with name \"name\" on message \"Request\" was deleted.","impact":"wire","code_frame":{"path":"testdata/workspace/success/breaking/other/proto/request.proto","start_line":5,"lines":["message Request {}"]},"against_code_frame":{"path":"testdata/workspace/success/dir/other/proto/request.proto","start_line":6,"lines":["  string name = 1;"]}}
+{"path":"testdata/workspace/success/breaking/proto/rpc.proto","start_line":8,"start_column":5,"end_line":8,"end_column":33,"type":"FIELD_SAME_JSON_NAME","message":"Field \"1\" with name \"request\" on message \"RPC\" changed option \"json_name\" from \"req\" to \"request\".","impact":"json","code_frame":{"path":"testdata/workspace/success/breaking/proto/rpc.proto","start_line":8,"lines":["    request.Request request = 1;"]},"against_code_frame":{"path":"testdata/workspace/success/dir/proto/rpc.proto","start_line":8,"lines":["    request.Request req = 1;"]}}
+{"path":"testdata/workspace/success/breaking/proto/rpc.proto","start_line":8,"start_column":21,"end_line":8,"end_column":28,"type":"FIELD_SAME_NAME","message":"Field \"1\" on message \"RPC\" changed name from \"req\" to \"request\".","impact":"json","code_frame":{"path":"testdata/workspace/success/breaking/proto/rpc.proto","start_line":8,"lines":["    request.Request request = 1;"]},"against_code_frame":{"path":"testdata/workspace/success/dir/proto/rpc.proto","start_line":8,"lines":["    request.Request req = 1;"]}}`),
+		"breaking",
+		filepath.Join("testdata", "workspace", "success", "breaking"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+		"--error-format",
+		"json",
+		"--show-source",
+	)
+}
+
+func TestBreakingMarkdown(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`Found 3 issues in 2 files.
+
+### FIELD_NO_DELETE (1)
+
+| Location | Impact | Before | After | Message |
+| --- | --- | --- | --- | --- |
+| `+"`testdata/workspace/success/breaking/other/proto/request.proto:5:1`"+` | wire |  |  | Previously present field "1" with name "name" on message "Request" was deleted. |
+
+### FIELD_SAME_JSON_NAME (1)
+
+| Location | Impact | Before | After | Message |
+| --- | --- | --- | --- | --- |
+| `+"`testdata/workspace/success/breaking/proto/rpc.proto:8:5`"+` | json | `+"`req`"+` | `+"`request`"+` | Field "1" with name "request" on message "RPC" changed option "json_name" from "req" to "request". |
+
+### FIELD_SAME_NAME (1)
+
+| Location | Impact | Before | After | Message |
+| --- | --- | --- | --- | --- |
+| `+"`testdata/workspace/success/breaking/proto/rpc.proto:8:21`"+` | json | `+"`req`"+` | `+"`request`"+` | Field "1" on message "RPC" changed name from "req" to "request". |`),
+		"breaking",
+		filepath.Join("testdata", "workspace", "success", "breaking"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+		"--error-format",
+		"markdown",
+	)
+	// A report is printed even if there are no breaking changes.
+	testRunStdout(
+		t,
+		nil,
+		0,
+		`No issues found.`,
+		"breaking",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+		"--error-format",
+		"markdown",
+	)
+}
+
+func TestBreakingFrom(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`testdata/workspace/success/breaking/other/proto/request.proto:5:1:Previously present field "1" with name "name" on message "Request" was deleted. [impact: wire]
+testdata/workspace/success/breaking/proto/rpc.proto:8:5:Field "1" with name "request" on message "RPC" changed option "json_name" from "req" to "request". [impact: json]
+testdata/workspace/success/breaking/proto/rpc.proto:8:21:Field "1" on message "RPC" changed name from "req" to "request". [impact: json]`),
+		"breaking",
+		"--from",
+		filepath.Join("testdata", "workspace", "success", "breaking"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{"<input> cannot be specified with --from"},
+		"breaking",
+		filepath.Join("testdata", "workspace", "success", "breaking"),
+		"--from",
+		filepath.Join("testdata", "workspace", "success", "breaking"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+	)
+}
+
+func TestBreakingMultipleAgainst(t *testing.T) {
+	t.Parallel()
+	// There are no breaking changes against the input itself, so each breaking
+	// change is only found against the dir input.
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`testdata/workspace/success/breaking/other/proto/request.proto:5:1:Previously present field "1" with name "name" on message "Request" was deleted. [impact: wire] [against: testdata/workspace/success/dir]
+testdata/workspace/success/breaking/proto/rpc.proto:8:5:Field "1" with name "request" on message "RPC" changed option "json_name" from "req" to "request". [impact: json] [against: testdata/workspace/success/dir]
+testdata/workspace/success/breaking/proto/rpc.proto:8:21:Field "1" on message "RPC" changed name from "req" to "request". [impact: json] [against: testdata/workspace/success/dir]`),
+		"breaking",
+		filepath.Join("testdata", "workspace", "success", "breaking"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "breaking"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`testdata/workspace/success/breaking/other/proto/request.proto(5,1) : error FIELD_NO_DELETE : Previously present field "1" with name "name" on message "Request" was deleted. [impact: wire] [against: testdata/workspace/success/dir, testdata/workspace/success/dir_buf_work]
+testdata/workspace/success/breaking/proto/rpc.proto(8,5) : error FIELD_SAME_JSON_NAME : Field "1" with name "request" on message "RPC" changed option "json_name" from "req" to "request". [impact: json] [against: testdata/workspace/success/dir, testdata/workspace/success/dir_buf_work]
+testdata/workspace/success/breaking/proto/rpc.proto(8,21) : error FIELD_SAME_NAME : Field "1" on message "RPC" changed name from "req" to "request". [impact: json] [against: testdata/workspace/success/dir, testdata/workspace/success/dir_buf_work]`),
+		"breaking",
+		filepath.Join("testdata", "workspace", "success", "breaking"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir_buf_work"),
+		"--error-format",
+		"msvs",
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		``,
+		"breaking",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+		"--against",
+		filepath.Join("testdata", "workspace", "success", "dir"),
+	)
+}
+
+func TestBreakingWithPaths(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	testRunStdout(t, nil, 0, ``, "build", filepath.Join("command", "generate", "testdata", "paths"), "-o", filepath.Join(tempDir, "previous.binpb"))
+	testRunStdout(t, nil, 0, ``, "build", filepath.Join("testdata", "paths"), "-o", filepath.Join(tempDir, "current.binpb"))
+	readWriteBucket, err := storageos.NewProvider().NewReadWriteBucket(tempDir)
+	require.NoError(t, err)
+	storagetesting.AssertPaths(
+		t,
+		readWriteBucket,
+		"",
+		"previous.binpb",
+		"current.binpb",
+	)
+	testRunStdoutStderrNoWarn(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		`a/v3/a.proto:6:3:Field "1" with name "key" on message "Foo" changed type from "string" to "int32". [impact: wire]
+a/v3/a.proto:7:3:Field "2" with name "Value" on message "Foo" changed option "json_name" from "value" to "Value". [impact: json]
+a/v3/a.proto:7:10:Field "2" on message "Foo" changed name from "value" to "Value". [impact: json]`,
+		"",
+		"breaking",
+		filepath.Join(tempDir, "current.binpb"),
+		"--against",
+		filepath.Join(tempDir, "previous.binpb"),
+		"--path",
+		filepath.Join("a", "v3"),
+		"--exclude-path",
+		filepath.Join("a", "v3", "foo"),
+	)
+	testRunStdoutStderrNoWarn(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		`a/v3/a.proto:6:3:Field "1" with name "key" on message "Foo" changed type from "string" to "int32". See https://developers.google.com/protocol-buffers/docs/proto3#updating for wire compatibility rules. [impact: wire]`,
+		"",
+		"breaking",
+		filepath.Join(tempDir, "current.binpb"),
 		"--against",
 		filepath.Join(tempDir, "previous.binpb"),
 		"--path",
@@ -3193,8 +3848,8 @@ func TestBreakingWithPlugins(t *testing.T) {
 		nil,
 		bufctl.ExitCodeFileAnnotation,
 		filepath.FromSlash(`
-testdata/check_plugins/current/proto/common/v1/breaking.proto:10:5:max len requirement reduced from 10 to 5 (buf-plugin-protovalidate-ext)
-testdata/check_plugins/current/proto/common/v1alpha1/breaking.proto:10:5:max len requirement reduced from 10 to 5 (buf-plugin-protovalidate-ext)
+testdata/check_plugins/current/proto/common/v1/breaking.proto:10:5:max len requirement reduced from 10 to 5 (buf-plugin-protovalidate-ext) [impact: semantic]
+testdata/check_plugins/current/proto/common/v1alpha1/breaking.proto:10:5:max len requirement reduced from 10 to 5 (buf-plugin-protovalidate-ext) [impact: semantic]
 		`),
 		"breaking",
 		filepath.Join("testdata", "check_plugins", "current", "proto"),
@@ -3244,7 +3899,7 @@ testdata/check_plugins/current/proto/common/v1alpha1/breaking.proto:10:5:max len
 		nil,
 		bufctl.ExitCodeFileAnnotation,
 		filepath.FromSlash(`
-testdata/check_plugins/current/proto/common/v1/breaking.proto:10:5:max len requirement reduced from 10 to 5 (buf-plugin-protovalidate-ext)
+testdata/check_plugins/current/proto/common/v1/breaking.proto:10:5:max len requirement reduced from 10 to 5 (buf-plugin-protovalidate-ext) [impact: semantic]
 	`),
 		"breaking",
 		filepath.Join("testdata", "check_plugins", "current", "proto"),
@@ -3286,8 +3941,8 @@ testdata/check_plugins/current/proto/common/v1/breaking.proto:10:5:max len requi
 		nil,
 		bufctl.ExitCodeFileAnnotation,
 		filepath.FromSlash(`
-testdata/check_plugins/current/proto/common/v1/breaking.proto:14:1:Message "common.v1.MSG_DONT_CHANGE" has a suffix configured for no changes has different fields, previously [], currently [common.v1.MSG_DONT_CHANGE.new_field]. (buf-plugin-suffix)
-testdata/check_plugins/current/proto/common/v1/breaking.proto:18:1:Enum "common.v1.E_DO_NOT_CHANGE" has a suffix configured for no changes has different enum values, previously [common.v1.ZERO], currently [common.v1.ONE common.v1.ZERO]. (buf-plugin-suffix)
+testdata/check_plugins/current/proto/common/v1/breaking.proto:14:1:Message "common.v1.MSG_DONT_CHANGE" has a suffix configured for no changes has different fields, previously [], currently [common.v1.MSG_DONT_CHANGE.new_field]. (buf-plugin-suffix) [impact: semantic]
+testdata/check_plugins/current/proto/common/v1/breaking.proto:18:1:Enum "common.v1.E_DO_NOT_CHANGE" has a suffix configured for no changes has different enum values, previously [common.v1.ZERO], currently [common.v1.ONE common.v1.ZERO]. (buf-plugin-suffix) [impact: semantic]
 	`),
 		"breaking",
 		filepath.Join("testdata", "check_plugins", "current", "proto"),
@@ -3329,8 +3984,8 @@ testdata/check_plugins/current/proto/common/v1/breaking.proto:18:1:Enum "common.
 		nil,
 		bufctl.ExitCodeFileAnnotation,
 		filepath.FromSlash(`
-testdata/check_plugins/current/proto/common/v1/breaking.proto:14:1:Message "common.v1.MSG_DONT_CHANGE" has a suffix configured for no changes has different fields, previously [], currently [common.v1.MSG_DONT_CHANGE.new_field]. (buf-plugin-suffix)
-testdata/check_plugins/current/proto/common/v1/breaking.proto:18:1:Enum "common.v1.E_DO_NOT_CHANGE" has a suffix configured for no changes has different enum values, previously [common.v1.ZERO], currently [common.v1.ONE common.v1.ZERO]. (buf-plugin-suffix)
+testdata/check_plugins/current/proto/common/v1/breaking.proto:14:1:Message "common.v1.MSG_DONT_CHANGE" has a suffix configured for no changes has different fields, previously [], currently [common.v1.MSG_DONT_CHANGE.new_field]. (buf-plugin-suffix) [impact: semantic]
+testdata/check_plugins/current/proto/common/v1/breaking.proto:18:1:Enum "common.v1.E_DO_NOT_CHANGE" has a suffix configured for no changes has different enum values, previously [common.v1.ZERO], currently [common.v1.ONE common.v1.ZERO]. (buf-plugin-suffix) [impact: semantic]
 	`),
 		"breaking",
 		filepath.Join("testdata", "check_plugins", "current", "proto"),
@@ -3373,7 +4028,7 @@ testdata/check_plugins/current/proto/common/v1/breaking.proto:18:1:Enum "common.
 		nil,
 		bufctl.ExitCodeFileAnnotation,
 		filepath.FromSlash(`
-testdata/check_plugins/current/proto/common/v1/breaking.proto:14:1:Message "common.v1.MSG_DONT_CHANGE" has a suffix configured for no changes has different fields, previously [], currently [common.v1.MSG_DONT_CHANGE.new_field]. (buf-plugin-suffix)
+testdata/check_plugins/current/proto/common/v1/breaking.proto:14:1:Message "common.v1.MSG_DONT_CHANGE" has a suffix configured for no changes has different fields, previously [], currently [common.v1.MSG_DONT_CHANGE.new_field]. (buf-plugin-suffix) [impact: semantic]
 	`),
 		"breaking",
 		filepath.Join("testdata", "check_plugins", "current", "proto"),
@@ -3498,7 +4153,7 @@ testdata/check_plugins/current/proto/common/v1/breaking.proto:14:1:Message "comm
 		nil,
 		bufctl.ExitCodeFileAnnotation,
 		filepath.FromSlash(`
-testdata/check_plugins/current/proto/common/v1/breaking.proto:18:1:Enum "common.v1.E_DO_NOT_CHANGE" has a suffix configured for no changes has different enum values, previously [common.v1.ZERO], currently [common.v1.ONE common.v1.ZERO]. (buf-plugin-suffix)
+testdata/check_plugins/current/proto/common/v1/breaking.proto:18:1:Enum "common.v1.E_DO_NOT_CHANGE" has a suffix configured for no changes has different enum values, previously [common.v1.ZERO], currently [common.v1.ONE common.v1.ZERO]. (buf-plugin-suffix) [impact: semantic]
 	`),
 		"breaking",
 		filepath.Join("testdata", "check_plugins", "current", "proto"),
@@ -3547,8 +4202,8 @@ testdata/check_plugins/current/proto/common/v1/breaking.proto:18:1:Enum "common.
 		nil,
 		bufctl.ExitCodeFileAnnotation,
 		filepath.FromSlash(`
-testdata/check_plugins/current/proto/common/v1/breaking.proto:14:1:Message "common.v1.MSG_DONT_CHANGE" has a suffix configured for no changes has different fields, previously [], currently [common.v1.MSG_DONT_CHANGE.new_field]. (buf-plugin-suffix)
-testdata/check_plugins/current/proto/common/v1/breaking.proto:18:1:Enum "common.v1.E_DO_NOT_CHANGE" has a suffix configured for no changes has different enum values, previously [common.v1.ZERO], currently [common.v1.ONE common.v1.ZERO]. (buf-plugin-suffix)
+testdata/check_plugins/current/proto/common/v1/breaking.proto:14:1:Message "common.v1.MSG_DONT_CHANGE" has a suffix configured for no changes has different fields, previously [], currently [common.v1.MSG_DONT_CHANGE.new_field]. (buf-plugin-suffix) [impact: semantic]
+testdata/check_plugins/current/proto/common/v1/breaking.proto:18:1:Enum "common.v1.E_DO_NOT_CHANGE" has a suffix configured for no changes has different enum values, previously [common.v1.ZERO], currently [common.v1.ONE common.v1.ZERO]. (buf-plugin-suffix) [impact: semantic]
 	`),
 		"breaking",
 		filepath.Join("testdata", "check_plugins", "current", "proto"),
@@ -4030,118 +4685,1414 @@ func TestFormatDiff(t *testing.T) {
 	)
 }
 
-// Tests if the exit code is set for common invocations of buf format
-// with the --exit-code flag.
-func TestFormatExitCode(t *testing.T) {
+func TestLintFix(t *testing.T) {
 	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(t, os.CopyFS(tempDir, os.DirFS(filepath.Join("testdata", "lint_fix"))))
+	filePath := filepath.Join(tempDir, "acme", "pet", "v1", "pet.proto")
 	stdout := bytes.NewBuffer(nil)
 	testRun(
 		t,
 		bufctl.ExitCodeFileAnnotation,
 		nil,
 		stdout,
-		"format",
-		filepath.Join("testdata", "format", "diff"),
-		"--exit-code",
-	)
-	assert.NotEmpty(t, stdout.String())
-	stdout = bytes.NewBuffer(nil)
-	testRun(
-		t,
-		bufctl.ExitCodeFileAnnotation,
-		nil,
-		stdout,
-		"format",
-		filepath.Join("testdata", "format", "diff"),
-		"-d",
-		"--exit-code",
+		"lint",
+		tempDir,
+		"--diff",
 	)
-	assert.NotEmpty(t, stdout.String())
-}
-
-// Tests if the image produced by the formatted result is
-// equivalent to the original result.
-func TestFormatEquivalence(t *testing.T) {
-	t.Parallel()
-	tempDir := t.TempDir()
-	testRunStdout(
+	assert.Contains(
 		t,
-		nil,
-		0,
-		``,
-		"build",
-		filepath.Join("testdata", "format", "complex"),
-		"-o",
-		filepath.Join(tempDir, "image.binpb"),
-		"--exclude-source-info",
+		stdout.String(),
+		`
+ package acme.pet.v1;
+ 
+-import "google/protobuf/timestamp.proto";
+-
+ message Pet {
+-  string petName = 1;
++  string pet_name = 1 [json_name = "petName"];
+`,
 	)
+	// PET_NAME cannot be fixed, as pet_name already exists after the fix for petName.
 	testRunStdout(
 		t,
 		nil,
-		0,
-		``,
-		"format",
-		filepath.Join("testdata", "format", "complex"),
-		"-o",
-		filepath.Join(tempDir, "formatted"),
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(tempDir+`/acme/pet/v1/pet.proto:8:10:Field name "PET_NAME" should be lower_snake_case, such as "pet_name".`),
+		"lint",
+		tempDir,
+		"--fix",
 	)
-	testRunStdout(
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(
 		t,
-		nil,
-		0,
-		``,
-		"build",
-		filepath.Join(tempDir, "formatted"),
-		"-o",
-		filepath.Join(tempDir, "formatted.binpb"),
-		"--exclude-source-info",
+		`syntax = "proto3";
+
+package acme.pet.v1;
+
+message Pet {
+  string pet_name = 1 [json_name = "petName"];
+  Kind kind = 2;
+  string PET_NAME = 3;
+}
+
+enum Kind {
+  KIND_UNKNOWN_UNSPECIFIED = 0;
+  KIND_DOG = 1;
+}
+`,
+		string(data),
 	)
-	originalImageData, err := os.ReadFile(filepath.Join(tempDir, "image.binpb"))
-	require.NoError(t, err)
-	formattedImageData, err := os.ReadFile(filepath.Join(tempDir, "formatted.binpb"))
-	require.NoError(t, err)
-	require.Equal(t, originalImageData, formattedImageData)
 }
 
-func TestFormatInvalidFlagCombination(t *testing.T) {
+func TestLintFixInvalidInput(t *testing.T) {
 	t.Parallel()
-	tempDir := t.TempDir()
 	testRunStderrContainsNoWarn(
 		t,
 		nil,
 		1,
 		[]string{
-			`Failure: cannot use --output when using --write`,
+			`Failure: invalid input "buf.build/acme/weather" when using --fix: must be a directory or proto file`,
 		},
-		"format",
-		filepath.Join("testdata", "format", "diff"),
-		"-w",
-		"-o",
-		filepath.Join(tempDir, "formatted"),
+		"lint",
+		"buf.build/acme/weather",
+		"--fix",
 	)
 }
 
-func TestFormatInvalidWriteWithModuleReference(t *testing.T) {
+func TestLintSuggestedEdits(t *testing.T) {
 	t.Parallel()
+	filePath := filepath.Join("testdata", "lint_fix", "acme", "pet", "v1", "pet.proto")
+	stdout := bytes.NewBuffer(nil)
+	testRun(
+		t,
+		bufctl.ExitCodeFileAnnotation,
+		nil,
+		stdout,
+		"lint",
+		filepath.Join("testdata", "lint_fix"),
+		"--error-format",
+		"json",
+		"--suggested-edits",
+	)
+	assert.Contains(
+		t,
+		stdout.String(),
+		fmt.Sprintf(
+			`"type":"FIELD_LOWER_SNAKE_CASE","message":"Field name \"petName\" should be lower_snake_case, such as \"pet_name\".","suggested_edits":[{"path":%q,"start_offset":108,"end_offset":115,"replacement":"pet_name"},{"path":%q,"start_offset":119,"end_offset":119,"replacement":" [json_name = \"petName\"]"}]}`,
+			filePath,
+			filePath,
+		),
+	)
+	// There are no suggested edits for PET_NAME, as its fix conflicts with petName.
+	assert.Contains(
+		t,
+		stdout.String(),
+		`"type":"FIELD_LOWER_SNAKE_CASE","message":"Field name \"PET_NAME\" should be lower_snake_case, such as \"pet_name\"."}`,
+	)
 	testRunStderrContainsNoWarn(
 		t,
 		nil,
 		1,
 		[]string{
-			`Failure: invalid input "buf.build/acme/weather" when using --write: must be a directory or proto file`,
+			`Failure: cannot use --suggested-edits with --error-format=text`,
 		},
-		"format",
-		"buf.build/acme/weather",
-		"-w",
+		"lint",
+		filepath.Join("testdata", "lint_fix"),
+		"--suggested-edits",
 	)
 }
 
-func TestFormatInvalidIncludePackageFiles(t *testing.T) {
+func TestLintStdinWithPathHint(t *testing.T) {
 	t.Parallel()
-	testRunStderrContainsNoWarn(
+	// The file from stdin replaces pet.proto, and its imports are resolved against the module.
+	testRunStdout(
 		t,
-		nil,
-		1,
+		strings.NewReader(`syntax = "proto3";
+
+package acme.pet.v1;
+
+message Pet {
+  string pet_name = 1;
+  Kind kind = 2;
+}
+
+enum Kind {
+  KIND_UNSPECIFIED = 0;
+}
+`),
+		0,
+		``,
+		"lint",
+		"-",
+		"--path-hint",
+		filepath.Join("testdata", "lint_fix", "acme", "pet", "v1", "pet.proto"),
+	)
+	// The file does not need to exist.
+	testRunStdout(
+		t,
+		strings.NewReader(`syntax = "proto3";
+
+package acme.pet.v1;
+
+import "acme/pet/v1/pet.proto";
+
+message Owner {
+  Pet pet = 1;
+  string firstName = 2;
+}
+`),
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`testdata/lint_fix/acme/pet/v1/owner.proto:9:10:Field name "firstName" should be lower_snake_case, such as "first_name".`),
+		"lint",
+		"-",
+		"--path-hint",
+		filepath.Join("testdata", "lint_fix", "acme", "pet", "v1", "owner.proto"),
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{
+			`Failure: --path-hint must be the path of a .proto file, got "testdata"`,
+		},
+		"lint",
+		"-",
+		"--path-hint",
+		"testdata",
+	)
+}
+
+func TestBetaInteractive(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		strings.NewReader("lnt\n1\n"+filepath.Join("testdata", "fail", "buf", "buf.proto")+"\n\n"),
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`testdata/fail/buf/buf.proto:3:1:Files with package "other" must be within a directory "other" relative to root but were in directory "buf".
+        testdata/fail/buf/buf.proto:6:9:Field name "oneTwo" should be lower_snake_case, such as "one_two".`),
+		"beta",
+		"interactive",
+	)
+	// The command is not run if it is not confirmed.
+	testRunStdout(
+		t,
+		strings.NewReader("lint\n1\n"+filepath.Join("testdata", "fail", "buf", "buf.proto")+"\nn\n"),
+		0,
+		``,
+		"beta",
+		"i",
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		strings.NewReader("lint\n"),
+		1,
+		[]string{"no input, stdin was closed"},
+		"beta",
+		"interactive",
+	)
+}
+
+func TestBetaPerfReport(t *testing.T) {
+	t.Parallel()
+	envFunc := internaltesting.NewEnvFunc(t)
+	perfStatsEnvFunc := func(use string) map[string]string {
+		env := envFunc(use)
+		env["BUF_BETA_PERF_STATS"] = "1"
+		return env
+	}
+	for i := 0; i < 2; i++ {
+		appcmdtesting.RunCommandExitCode(
+			t,
+			func(use string) *appcmd.Command { return NewRootCommand(use) },
+			bufctl.ExitCodeFileAnnotation,
+			perfStatsEnvFunc,
+			nil,
+			bytes.NewBuffer(nil),
+			bytes.NewBuffer(nil),
+			"lint",
+			filepath.Join("testdata", "fail", "buf", "buf.proto"),
+		)
+	}
+	// Commands are not recorded without the environment variable.
+	testRun(t, 0, nil, nil, "build", filepath.Join("testdata", "success"))
+	stdout := bytes.NewBuffer(nil)
+	appcmdtesting.RunCommandExitCode(
+		t,
+		func(use string) *appcmd.Command { return NewRootCommand(use) },
+		0,
+		envFunc,
+		nil,
+		stdout,
+		bytes.NewBuffer(nil),
+		"beta",
+		"perf",
+		"report",
+		"--format",
+		"json",
+	)
+	var report struct {
+		Runs     int `json:"runs"`
+		Commands []struct {
+			Command  string `json:"command"`
+			Runs     int    `json:"runs"`
+			Failures int    `json:"failures"`
+		} `json:"commands"`
+		Caches []struct {
+			Name    string `json:"name"`
+			Lookups int    `json:"lookups"`
+			Hits    int    `json:"hits"`
+		} `json:"caches"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	require.Equal(t, 2, report.Runs)
+	require.Len(t, report.Commands, 1)
+	require.Equal(t, "test lint", report.Commands[0].Command)
+	require.Equal(t, 2, report.Commands[0].Runs)
+	require.Equal(t, 2, report.Commands[0].Failures)
+	require.Len(t, report.Caches, 1)
+	require.Equal(t, "lint", report.Caches[0].Name)
+	require.Equal(t, 2, report.Caches[0].Lookups)
+	require.Equal(t, 1, report.Caches[0].Hits)
+}
+
+func TestBetaLintChangedSince(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(t, os.CopyFS(tempDir, os.DirFS(filepath.Join("testdata", "lint_fix"))))
+	// An unchanged file that does not compile, which is only built if it is sampled.
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "acme", "other", "v1"), 0755))
+	require.NoError(
+		t,
+		os.WriteFile(
+			filepath.Join(tempDir, "acme", "other", "v1", "other.proto"),
+			[]byte("syntax = \"proto3\";\n\npackage acme.other.v1;\n\nmessage Other {\n  Unknown unknown = 1;\n}\n"),
+			0600,
+		),
+	)
+	testRunGit(t, tempDir, "init")
+	testRunGit(t, tempDir, "checkout", "-b", "main")
+	testRunGit(t, tempDir, "add", ".")
+	testRunGit(t, tempDir, "commit", "-m", "commit 0")
+	testRunGit(t, tempDir, "checkout", "-b", "feature")
+	filePath := filepath.Join(tempDir, "acme", "pet", "v1", "pet.proto")
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.NoError(
+		t,
+		os.WriteFile(
+			filePath,
+			[]byte(strings.Replace(string(data), "message Pet {\n", "message Pet {\n  string ownerName = 4;\n", 1)),
+			0600,
+		),
+	)
+	// Only the violation on the changed line is new. The violations on the other lines
+	// of the changed file are legacy violations, and do not fail the command.
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	appcmdtesting.RunCommandExitCode(
+		t,
+		func(use string) *appcmd.Command { return NewRootCommand(use) },
+		bufctl.ExitCodeFileAnnotation,
+		internaltesting.NewEnvFunc(t),
+		nil,
+		stdout,
+		stderr,
+		"beta",
+		"lint",
+		tempDir,
+		"--changed-since",
+		"main",
+	)
+	assert.Equal(
+		t,
+		filepath.FromSlash(tempDir+`/acme/pet/v1/pet.proto:8:10:Field name "ownerName" should be lower_snake_case, such as "owner_name".`),
+		strings.TrimSpace(stdout.String()),
+	)
+	assert.Contains(
+		t,
+		stderr.String(),
+		filepath.FromSlash(tempDir+`/acme/pet/v1/pet.proto:9:10:Field name "petName" should be lower_snake_case, such as "pet_name".`),
+	)
+	assert.NotContains(t, stderr.String(), "other.proto")
+	// The sampled unchanged file is built, and fails to compile.
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(tempDir+`/acme/other/v1/other.proto:6:3:field acme.other.v1.Other.unknown: unknown type Unknown`),
+		"beta",
+		"lint",
+		tempDir,
+		"--changed-since",
+		"main",
+		"--sample",
+		"1",
+	)
+	// Without changes, there are no violations.
+	require.NoError(t, os.RemoveAll(filepath.Join(tempDir, "acme", "other")))
+	testRunGit(t, tempDir, "commit", "-a", "-m", "commit 1")
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"beta",
+		"lint",
+		tempDir,
+		"--changed-since",
+		"HEAD",
+		"--sample",
+		"1",
+	)
+}
+
+func TestLintOnlyChangedLines(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(t, os.CopyFS(tempDir, os.DirFS(filepath.Join("testdata", "lint_fix"))))
+	testRunGit(t, tempDir, "init")
+	testRunGit(t, tempDir, "checkout", "-b", "main")
+	testRunGit(t, tempDir, "add", ".")
+	testRunGit(t, tempDir, "commit", "-m", "commit 0")
+	filePath := filepath.Join(tempDir, "acme", "pet", "v1", "pet.proto")
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.NoError(
+		t,
+		os.WriteFile(
+			filePath,
+			[]byte(strings.Replace(string(data), "message Pet {\n", "message Pet {\n  string ownerName = 4;\n", 1)),
+			0600,
+		),
+	)
+	// The violations on the lines that did not change are not reported.
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(tempDir+`/acme/pet/v1/pet.proto:8:10:Field name "ownerName" should be lower_snake_case, such as "owner_name".`),
+		"lint",
+		tempDir,
+		"--only-changed-lines",
+		"main",
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{"cannot use --write-baseline with --only-changed-lines"},
+		"lint",
+		tempDir,
+		"--only-changed-lines",
+		"main",
+		"--write-baseline",
+	)
+}
+
+func TestLintOnlyChangedFiles(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(t, os.CopyFS(tempDir, os.DirFS(filepath.Join("testdata", "lint_fix"))))
+	testRunGit(t, tempDir, "init")
+	testRunGit(t, tempDir, "checkout", "-b", "main")
+	testRunGit(t, tempDir, "add", ".")
+	testRunGit(t, tempDir, "commit", "-m", "commit 0")
+	// Without changes, there are no violations.
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"lint",
+		tempDir,
+		"--only-changed-files",
+		"main",
+	)
+	// The new file imports the unchanged file, which is still built, but its violations
+	// are not reported.
+	require.NoError(
+		t,
+		os.WriteFile(
+			filepath.Join(tempDir, "acme", "pet", "v1", "owner.proto"),
+			[]byte(`syntax = "proto3";
+
+package acme.pet.v1;
+
+import "acme/pet/v1/pet.proto";
+
+message Owner {
+  repeated Pet pets = 1;
+  string ownerName = 2;
+}
+`),
+			0600,
+		),
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(tempDir+`/acme/pet/v1/owner.proto:9:10:Field name "ownerName" should be lower_snake_case, such as "owner_name".`),
+		"lint",
+		tempDir,
+		"--only-changed-files",
+		"main",
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{"cannot use --only-changed-lines with --only-changed-files"},
+		"lint",
+		tempDir,
+		"--only-changed-lines",
+		"main",
+		"--only-changed-files",
+		"main",
+	)
+}
+
+func TestBuildWithModuleOverrides(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	for path, data := range map[string]string{
+		"shared/acme/shared/v1/shared.proto": `syntax = "proto3";
+
+package acme.shared.v1;
+
+message Shared {}
+`,
+		"workspace/buf.yaml": `version: v2
+modules:
+  - path: proto
+overrides:
+  - module: buf.build/acme/shared
+    path: ../shared
+`,
+		"workspace/proto/acme/app/v1/app.proto": `syntax = "proto3";
+
+package acme.app.v1;
+
+import "acme/shared/v1/shared.proto";
+
+message App {
+  acme.shared.v1.Shared shared = 1;
+}
+`,
+	} {
+		path = filepath.Join(tempDir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+	}
+	workspaceDirPath := filepath.Join(tempDir, "workspace")
+	appcmdtesting.RunCommandExitCodeStderrContains(
+		t,
+		func(use string) *appcmd.Command { return NewRootCommand(use) },
+		0,
+		[]string{`Dependency buf.build/acme/shared is overridden with local directory "../shared"`},
+		internaltesting.NewEnvFunc(t),
+		nil,
+		"build",
+		workspaceDirPath,
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		filepath.FromSlash(workspaceDirPath+"/proto/acme/app/v1/app.proto"),
+		"ls-files",
+		workspaceDirPath,
+	)
+	require.NoError(
+		t,
+		os.WriteFile(
+			filepath.Join(workspaceDirPath, "buf.yaml"),
+			[]byte(`version: v2
+modules:
+  - path: proto
+overrides:
+  - module: buf.build/acme/shared
+    path: ../missing
+`),
+			0600,
+		),
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{"override of buf.build/acme/shared"},
+		"build",
+		workspaceDirPath,
+	)
+}
+
+func TestBreakingOnlyChangedLines(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(t, os.CopyFS(tempDir, os.DirFS(filepath.Join("testdata", "lint_fix"))))
+	testRunGit(t, tempDir, "init")
+	testRunGit(t, tempDir, "checkout", "-b", "main")
+	testRunGit(t, tempDir, "add", ".")
+	testRunGit(t, tempDir, "commit", "-m", "commit 0")
+	filePath := filepath.Join(tempDir, "acme", "pet", "v1", "pet.proto")
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	// The against input also has an enum value that was deleted before the changes.
+	againstDir := t.TempDir()
+	require.NoError(t, os.CopyFS(againstDir, os.DirFS(filepath.Join("testdata", "lint_fix"))))
+	require.NoError(
+		t,
+		os.WriteFile(
+			filepath.Join(againstDir, "acme", "pet", "v1", "pet.proto"),
+			[]byte(strings.Replace(string(data), "  KIND_DOG = 1;\n", "  KIND_DOG = 1;\n  KIND_CAT = 2;\n", 1)),
+			0600,
+		),
+	)
+	require.NoError(
+		t,
+		os.WriteFile(
+			filePath,
+			[]byte(strings.Replace(string(data), "  Kind kind = 2;\n", "", 1)),
+			0600,
+		),
+	)
+	// Only the deleted field is within the changes.
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(tempDir+`/acme/pet/v1/pet.proto:7:1:Previously present field "2" with name "kind" on message "Pet" was deleted. [impact: wire]`),
+		"breaking",
+		tempDir,
+		"--against",
+		againstDir,
+		"--only-changed-lines",
+		"main",
+	)
+}
+
+func TestBreakingExceptionsDefaultFile(t *testing.T) {
+	t.Parallel()
+	againstDir := t.TempDir()
+	require.NoError(t, os.CopyFS(againstDir, os.DirFS(filepath.Join("testdata", "lint_fix"))))
+	tempDir := t.TempDir()
+	require.NoError(t, os.CopyFS(tempDir, os.DirFS(filepath.Join("testdata", "lint_fix"))))
+	filePath := filepath.Join(tempDir, "acme", "pet", "v1", "pet.proto")
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.NoError(
+		t,
+		os.WriteFile(
+			filePath,
+			[]byte(strings.Replace(string(data), "  Kind kind = 2;\n", "", 1)),
+			0600,
+		),
+	)
+	// Without --exceptions, the exceptions are written to and read from the directory of
+	// the workspace of the input, not the current directory.
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"breaking",
+		tempDir,
+		"--against",
+		againstDir,
+		"--update-exceptions",
+	)
+	_, err = os.Stat(filepath.Join(tempDir, bufbreakingexception.DefaultFileName))
+	require.NoError(t, err)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"breaking",
+		tempDir,
+		"--against",
+		againstDir,
+	)
+}
+
+func TestBreakingExceptions(t *testing.T) {
+	t.Parallel()
+	againstDir := t.TempDir()
+	require.NoError(t, os.CopyFS(againstDir, os.DirFS(filepath.Join("testdata", "lint_fix"))))
+	tempDir := t.TempDir()
+	require.NoError(t, os.CopyFS(tempDir, os.DirFS(filepath.Join("testdata", "lint_fix"))))
+	filePath := filepath.Join(tempDir, "acme", "pet", "v1", "pet.proto")
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.NoError(
+		t,
+		os.WriteFile(
+			filePath,
+			[]byte(strings.Replace(string(data), "  Kind kind = 2;\n", "", 1)),
+			0600,
+		),
+	)
+	exceptionsPath := filepath.Join(tempDir, "exceptions.yaml")
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"breaking",
+		tempDir,
+		"--against",
+		againstDir,
+		"--exceptions",
+		exceptionsPath,
+		"--update-exceptions",
+		"--exception-reason",
+		"The field was never populated.",
+	)
+	data, err = os.ReadFile(exceptionsPath)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`version: v1
+exceptions:
+  - rule: FIELD_NO_DELETE
+    symbol: acme.pet.v1.Pet
+    message: Previously present field "2" with name "kind" on message "Pet" was deleted.
+    reason: The field was never populated.
+`,
+		string(data),
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"breaking",
+		tempDir,
+		"--against",
+		againstDir,
+		"--exceptions",
+		exceptionsPath,
+	)
+	// A different breaking change in the same message is still reported.
+	data, err = os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.NoError(
+		t,
+		os.WriteFile(
+			filePath,
+			[]byte(strings.Replace(string(data), "  string petName = 1;\n", "", 1)),
+			0600,
+		),
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(tempDir+`/acme/pet/v1/pet.proto:7:1:Previously present field "1" with name "petName" on message "Pet" was deleted. [impact: wire]`),
+		"breaking",
+		tempDir,
+		"--against",
+		againstDir,
+		"--exceptions",
+		exceptionsPath,
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{"--exception-reason requires --update-exceptions"},
+		"breaking",
+		tempDir,
+		"--against",
+		againstDir,
+		"--exception-reason",
+		"reason",
+	)
+}
+
+func TestLintSARIF(t *testing.T) {
+	t.Parallel()
+	stdout := bytes.NewBuffer(nil)
+	testRun(
+		t,
+		bufctl.ExitCodeFileAnnotation,
+		nil,
+		stdout,
+		"lint",
+		filepath.Join("testdata", "fail"),
+		"--error-format",
+		"sarif",
+	)
+	var sarifLog struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID               string `json:"id"`
+						ShortDescription struct {
+							Text string `json:"text"`
+						} `json:"shortDescription"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &sarifLog))
+	assert.Equal(t, "2.1.0", sarifLog.Version)
+	require.Len(t, sarifLog.Runs, 1)
+	run := sarifLog.Runs[0]
+	require.Len(t, run.Results, 2)
+	result := run.Results[1]
+	assert.Equal(t, "FIELD_LOWER_SNAKE_CASE", result.RuleID)
+	rule := run.Tool.Driver.Rules[result.RuleIndex]
+	assert.Equal(t, "FIELD_LOWER_SNAKE_CASE", rule.ID)
+	assert.NotEmpty(t, rule.ShortDescription.Text)
+	require.Len(t, result.Locations, 1)
+	assert.Equal(t, "testdata/fail/buf/buf.proto", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	// A log with no results is printed if there are no violations.
+	stdout.Reset()
+	testRun(
+		t,
+		0,
+		nil,
+		stdout,
+		"lint",
+		filepath.Join("testdata", "success"),
+		"--error-format",
+		"sarif",
+	)
+	assert.Contains(t, stdout.String(), `"results": []`)
+}
+
+func TestLintSARIFWarn(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "a"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "buf.yaml"), []byte("version: v2\nlint:\n  use:\n    - BASIC\n  warn:\n    - FIELD_LOWER_SNAKE_CASE\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "a", "a.proto"), []byte("syntax = \"proto3\";\npackage a;\nmessage M {\n  string fooBar = 1;\n}\n"), 0600))
+	stdout := bytes.NewBuffer(nil)
+	testRun(
+		t,
+		0,
+		nil,
+		stdout,
+		"lint",
+		tempDir,
+		"--error-format",
+		"sarif",
+	)
+	var sarifLog struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID                   string `json:"id"`
+						DefaultConfiguration struct {
+							Level string `json:"level"`
+						} `json:"defaultConfiguration"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &sarifLog))
+	require.Len(t, sarifLog.Runs, 1)
+	run := sarifLog.Runs[0]
+	require.Len(t, run.Results, 1)
+	result := run.Results[0]
+	assert.Equal(t, "FIELD_LOWER_SNAKE_CASE", result.RuleID)
+	assert.Equal(t, "warning", result.Level)
+	// The rule descriptor has the configured severity of the rule.
+	rule := run.Tool.Driver.Rules[result.RuleIndex]
+	assert.Equal(t, "FIELD_LOWER_SNAKE_CASE", rule.ID)
+	assert.Equal(t, "warning", rule.DefaultConfiguration.Level)
+	for _, rule := range run.Tool.Driver.Rules {
+		if rule.ID != "FIELD_LOWER_SNAKE_CASE" {
+			assert.Equal(t, "error", rule.DefaultConfiguration.Level, rule.ID)
+		}
+	}
+}
+
+func TestLintJUnit(t *testing.T) {
+	t.Parallel()
+	stdout := bytes.NewBuffer(nil)
+	testRun(
+		t,
+		bufctl.ExitCodeFileAnnotation,
+		nil,
+		stdout,
+		"lint",
+		filepath.Join("testdata", "fail"),
+		"--error-format",
+		"junit",
+	)
+	assert.Contains(
+		t,
+		stdout.String(),
+		`<testcase name="FIELD_LOWER_SNAKE_CASE_6_9" classname="testdata/fail/buf/buf" file="testdata/fail/buf/buf.proto" line="6">`,
+	)
+	// A report with no test suites is printed if there are no violations.
+	stdout.Reset()
+	testRun(
+		t,
+		0,
+		nil,
+		stdout,
+		"lint",
+		filepath.Join("testdata", "success"),
+		"--error-format",
+		"junit",
+	)
+	assert.Equal(t, "<testsuites></testsuites>", strings.TrimSpace(stdout.String()))
+}
+
+func TestLintWarn(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(
+		t,
+		os.WriteFile(
+			filepath.Join(tempDir, "buf.yaml"),
+			[]byte(`version: v2
+lint:
+  use:
+    - STANDARD
+  except:
+    - PACKAGE_DIRECTORY_MATCH
+  warn:
+    - FIELD_LOWER_SNAKE_CASE
+`),
+			0600,
+		),
+	)
+	require.NoError(
+		t,
+		os.WriteFile(
+			filepath.Join(tempDir, "a.proto"),
+			[]byte(`syntax = "proto3";
+
+package a.v1;
+
+message Foo {
+  int64 oneTwo = 1;
+}
+`),
+			0600,
+		),
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		filepath.FromSlash(tempDir+`/a.proto:6:9:Field name "oneTwo" should be lower_snake_case, such as "one_two". [warning]`),
+		"lint",
+		tempDir,
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		filepath.FromSlash(tempDir+`/a.proto(6,9) : warning FIELD_LOWER_SNAKE_CASE : Field name "oneTwo" should be lower_snake_case, such as "one_two".`),
+		"lint",
+		tempDir,
+		"--error-format",
+		"msvs",
+		"--max-warnings",
+		"1",
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(tempDir+`/a.proto:6:9:Field name "oneTwo" should be lower_snake_case, such as "one_two". [warning]`),
+		"lint",
+		tempDir,
+		"--max-warnings",
+		"0",
+	)
+}
+
+func TestLintListIgnores(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`testdata/lint_list_ignores/a.proto:7:9:Field name "fieldOne" should be lower_snake_case, such as "field_one".
+testdata/lint_list_ignores/a.proto:11:9:Field name "fieldThree" should be lower_snake_case, such as "field_three".
+testdata/lint_list_ignores/a.proto:15:9:Field name "fieldFive" should be lower_snake_case, such as "field_five".`),
+		"lint",
+		filepath.Join("testdata", "lint_list_ignores"),
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		filepath.FromSlash(`testdata/lint_list_ignores/a.proto:9:3:FIELD_LOWER_SNAKE_CASE:required by legacy clients
+testdata/lint_list_ignores/a.proto:13:3:FIELD_LOWER_SNAKE_CASE:required by legacy clients (expires 2999-12-31)`),
+		"lint",
+		filepath.Join("testdata", "lint_list_ignores"),
+		"--list-ignores",
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		filepath.FromSlash(`{"path":"testdata/lint_list_ignores/a.proto","start_line":9,"start_column":3,"rule":"FIELD_LOWER_SNAKE_CASE","justification":"required by legacy clients"}
+{"path":"testdata/lint_list_ignores/a.proto","start_line":13,"start_column":3,"rule":"FIELD_LOWER_SNAKE_CASE","justification":"required by legacy clients","expires":"2999-12-31"}`),
+		"lint",
+		filepath.Join("testdata", "lint_list_ignores"),
+		"--list-ignores",
+		"--error-format",
+		"json",
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{"cannot use --list-ignores with --error-format=msvs"},
+		"lint",
+		filepath.Join("testdata", "lint_list_ignores"),
+		"--list-ignores",
+		"--error-format",
+		"msvs",
+	)
+}
+
+func TestLogFilter(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"lint",
+		filepath.Join("testdata", "success"),
+		"--log-filter",
+		"compile=debug,fetch=info",
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{`unknown log component [cache,compile,fetch,plugin]: "bogus"`},
+		"lint",
+		filepath.Join("testdata", "success"),
+		"--log-filter",
+		"bogus=debug",
+	)
+}
+
+func TestLintSummary(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`RULE                    FILE                                COUNT
+FIELD_LOWER_SNAKE_CASE  testdata/lint_list_ignores/a.proto  3
+
+RULE                    FILES  COUNT
+FIELD_LOWER_SNAKE_CASE  1      3
+TOTAL                   1      3`),
+		"lint",
+		filepath.Join("testdata", "lint_list_ignores"),
+		"--summary",
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`{"total":3,"rules":[{"rule":"FIELD_LOWER_SNAKE_CASE","count":3,"files":[{"path":"testdata/lint_list_ignores/a.proto","count":3}]}]}`),
+		"lint",
+		filepath.Join("testdata", "lint_list_ignores"),
+		"--summary",
+		"--error-format",
+		"json",
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{"cannot use --summary with --error-format=sarif"},
+		"lint",
+		filepath.Join("testdata", "lint_list_ignores"),
+		"--summary",
+		"--error-format",
+		"sarif",
+	)
+}
+
+func TestBetaReduce(t *testing.T) {
+	t.Parallel()
+	outputDirPath := filepath.Join(t.TempDir(), "reduced")
+	testRunStdout(
+		t,
+		nil,
+		0,
+		fmt.Sprintf(
+			`Reduced from 1 to 1 .proto files and from 16 to 4 lines in 23 runs.
+Wrote the reduced input to %s.`,
+			outputDirPath,
+		),
+		"beta",
+		"reduce",
+		filepath.Join("testdata", "lint_list_ignores"),
+		"--output",
+		outputDirPath,
+		"--",
+		"lint",
+	)
+	data, err := os.ReadFile(filepath.Join(outputDirPath, "a.proto"))
+	require.NoError(t, err)
+	assert.Equal(t, "syntax = \"proto3\";\nmessage Foo {\n  int64 fieldOne = 1;\n}\n", string(data))
+	outputDirPath = filepath.Join(t.TempDir(), "reduced")
+	testRunStdout(
+		t,
+		nil,
+		0,
+		fmt.Sprintf(
+			`Reduced from 1 to 1 .proto files and from 16 to 3 lines in 22 runs.
+Wrote the reduced input to %s.`,
+			outputDirPath,
+		),
+		"beta",
+		"reduce",
+		filepath.Join("testdata", "lint_list_ignores"),
+		"--output",
+		outputDirPath,
+		"--match",
+		"fieldThree",
+		"--",
+		"lint",
+		"{}",
+		"--error-format",
+		"json",
+	)
+	data, err = os.ReadFile(filepath.Join(outputDirPath, "a.proto"))
+	require.NoError(t, err)
+	assert.Equal(t, "message Foo {\n  int64 fieldThree = 3;\n}\n", string(data))
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{"did not fail on a copy of the input"},
+		"beta",
+		"reduce",
+		filepath.Join("testdata", "lint_list_ignores"),
+		"--output",
+		filepath.Join(t.TempDir(), "reduced"),
+		"--",
+		"build",
+	)
+}
+
+func TestBetaVerifyGenerateConfig(t *testing.T) {
+	t.Parallel()
+	tempDirPath := t.TempDir()
+	missingPluginPath := filepath.Join(tempDirPath, "protoc-gen-missing")
+	bufGenYAMLFilePath := filepath.Join(tempDirPath, "buf.gen.yaml")
+	require.NoError(
+		t,
+		os.WriteFile(
+			bufGenYAMLFilePath,
+			[]byte(`version: v2
+managed:
+  override:
+    - file_option: java_package_prefix
+      value: com.acme
+plugins:
+  - local: `+missingPluginPath+`
+    out: gen
+`),
+			0600,
+		),
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		fmt.Sprintf(
+			`%s:1:1:Managed mode is not enabled, so its disable and override rules are never applied. Set enabled to true in managed, or remove the rules. [warning]
+%s:1:1:Plugin 1 (%s) was not found: %q does not exist or is not executable. Install the plugin, or set local to the path of the plugin binary.`,
+			bufGenYAMLFilePath,
+			bufGenYAMLFilePath,
+			missingPluginPath,
+			missingPluginPath,
+		),
+		"beta",
+		"verify-generate-config",
+		tempDirPath,
+	)
+	require.NoError(
+		t,
+		os.WriteFile(
+			bufGenYAMLFilePath,
+			[]byte(`version: v2
+plugins:
+  - remote: buf.build/protocolbuffers/go
+    out: gen
+    include_imports: true
+    routes:
+      - out: gen
+        suffix: .pb.go
+`),
+			0600,
+		),
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		fmt.Sprintf(
+			`%s:1:1:Plugin 1 (buf.build/protocolbuffers/go) has a route to "gen", which is the out of the plugin, so the route has no effect. [warning]`,
+			bufGenYAMLFilePath,
+		),
+		"beta",
+		"verify-generate-config",
+		bufGenYAMLFilePath,
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{"no buf.gen.yaml file found"},
+		"beta",
+		"verify-generate-config",
+		filepath.Join("testdata", "success"),
+	)
+}
+
+func TestLintBaseline(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(t, os.CopyFS(tempDir, os.DirFS(filepath.Join("testdata", "lint_fix"))))
+	baselinePath := filepath.Join(tempDir, "baseline.yaml")
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"lint",
+		tempDir,
+		"--baseline",
+		baselinePath,
+		"--write-baseline",
+	)
+	data, err := os.ReadFile(baselinePath)
+	require.NoError(t, err)
+	assert.Contains(
+		t,
+		string(data),
+		`  - path: acme/pet/v1/pet.proto
+    type: FIELD_LOWER_SNAKE_CASE
+    message: Field name "petName" should be lower_snake_case, such as "pet_name".
+    count: 1
+`,
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"lint",
+		tempDir,
+		"--baseline",
+		baselinePath,
+	)
+	// The existing violations move to other lines, and only the new violation is reported.
+	filePath := filepath.Join(tempDir, "acme", "pet", "v1", "pet.proto")
+	data, err = os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.NoError(
+		t,
+		os.WriteFile(
+			filePath,
+			[]byte(strings.Replace(string(data), "message Pet {\n", "message Pet {\n  string ownerName = 4;\n", 1)),
+			0600,
+		),
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(tempDir+`/acme/pet/v1/pet.proto:8:10:Field name "ownerName" should be lower_snake_case, such as "owner_name".`),
+		"lint",
+		tempDir,
+		"--baseline",
+		baselinePath,
+	)
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{"cannot use --write-baseline with --diff"},
+		"lint",
+		tempDir,
+		"--write-baseline",
+		"--diff",
+	)
+}
+
+func TestLintBaselineDefaultFile(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	require.NoError(t, os.CopyFS(tempDir, os.DirFS(filepath.Join("testdata", "lint_fix"))))
+	// Without --baseline, the baseline is written to and read from the directory of the
+	// workspace of the input, not the current directory.
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"lint",
+		tempDir,
+		"--write-baseline",
+	)
+	_, err := os.Stat(filepath.Join(tempDir, bufbaseline.DefaultFileName))
+	require.NoError(t, err)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		"",
+		"lint",
+		filepath.Join(tempDir, "acme", "pet", "v1", "pet.proto"),
+	)
+}
+
+// Tests if the exit code is set for common invocations of buf format
+// with the --exit-code flag.
+func TestFormatExitCode(t *testing.T) {
+	t.Parallel()
+	stdout := bytes.NewBuffer(nil)
+	testRun(
+		t,
+		bufctl.ExitCodeFileAnnotation,
+		nil,
+		stdout,
+		"format",
+		filepath.Join("testdata", "format", "diff"),
+		"--exit-code",
+	)
+	assert.NotEmpty(t, stdout.String())
+	stdout = bytes.NewBuffer(nil)
+	testRun(
+		t,
+		bufctl.ExitCodeFileAnnotation,
+		nil,
+		stdout,
+		"format",
+		filepath.Join("testdata", "format", "diff"),
+		"-d",
+		"--exit-code",
+	)
+	assert.NotEmpty(t, stdout.String())
+}
+
+// Tests if the image produced by the formatted result is
+// equivalent to the original result.
+func TestFormatEquivalence(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	testRunStdout(
+		t,
+		nil,
+		0,
+		``,
+		"build",
+		filepath.Join("testdata", "format", "complex"),
+		"-o",
+		filepath.Join(tempDir, "image.binpb"),
+		"--exclude-source-info",
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		``,
+		"format",
+		filepath.Join("testdata", "format", "complex"),
+		"-o",
+		filepath.Join(tempDir, "formatted"),
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		``,
+		"build",
+		filepath.Join(tempDir, "formatted"),
+		"-o",
+		filepath.Join(tempDir, "formatted.binpb"),
+		"--exclude-source-info",
+	)
+	originalImageData, err := os.ReadFile(filepath.Join(tempDir, "image.binpb"))
+	require.NoError(t, err)
+	formattedImageData, err := os.ReadFile(filepath.Join(tempDir, "formatted.binpb"))
+	require.NoError(t, err)
+	require.Equal(t, originalImageData, formattedImageData)
+}
+
+func TestFormatInvalidFlagCombination(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{
+			`Failure: cannot use --output when using --write`,
+		},
+		"format",
+		filepath.Join("testdata", "format", "diff"),
+		"-w",
+		"-o",
+		filepath.Join(tempDir, "formatted"),
+	)
+}
+
+func TestFormatInvalidWriteWithModuleReference(t *testing.T) {
+	t.Parallel()
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
+		[]string{
+			`Failure: invalid input "buf.build/acme/weather" when using --write: must be a directory or proto file`,
+		},
+		"format",
+		"buf.build/acme/weather",
+		"-w",
+	)
+}
+
+func TestFormatInvalidIncludePackageFiles(t *testing.T) {
+	t.Parallel()
+	testRunStderrContainsNoWarn(
+		t,
+		nil,
+		1,
 		[]string{
 			"Failure: cannot specify include_package_files=true with format",
 		},
@@ -4336,6 +6287,46 @@ fail/buf/buf.proto:6:9:Field name "oneTwo" should be lower_snake_case, such as "
 	)
 }
 
+func TestArchiveLimits(t *testing.T) {
+	t.Parallel()
+	zipDir := createZipFromDir(
+		t,
+		filepath.Join("testdata", "failarchive"),
+		"archive.zip",
+	)
+	envFunc := internaltesting.NewEnvFunc(t)
+	archiveLimitEnvFunc := func(use string) map[string]string {
+		env := envFunc(use)
+		env["BUF_INPUT_ARCHIVE_MAX_FILES"] = "1"
+		return env
+	}
+	appcmdtesting.RunCommandExitCodeStderrContains(
+		t,
+		func(use string) *appcmd.Command { return NewRootCommand(use) },
+		1,
+		[]string{"number of files exceeded limit: archive contains more than 1 files"},
+		archiveLimitEnvFunc,
+		nil,
+		"build",
+		filepath.Join(zipDir, "archive.zip#subdir=fail"),
+	)
+	invalidArchiveLimitEnvFunc := func(use string) map[string]string {
+		env := envFunc(use)
+		env["BUF_INPUT_ARCHIVE_MAX_SIZE"] = "-1"
+		return env
+	}
+	appcmdtesting.RunCommandExitCodeStderrContains(
+		t,
+		func(use string) *appcmd.Command { return NewRootCommand(use) },
+		1,
+		[]string{`invalid value for BUF_INPUT_ARCHIVE_MAX_SIZE, must be a non-negative integer: "-1"`},
+		invalidArchiveLimitEnvFunc,
+		nil,
+		"build",
+		filepath.Join(zipDir, "archive.zip#subdir=fail"),
+	)
+}
+
 func TestLintDisabledForModuleInWorkspace(t *testing.T) {
 	t.Parallel()
 	testRunStdout(
@@ -4489,6 +6480,26 @@ func testRun(
 	)
 }
 
+func testGetGitRevision(t *testing.T, dir string) string {
+	t.Helper()
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	require.NoError(t, err)
+	return strings.TrimSpace(string(output))
+}
+
+func testRunGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command(
+		"git",
+		append(
+			[]string{"-C", dir, "-c", "user.email=tests@buf.build", "-c", "user.name=Buf go tests"},
+			args...,
+		)...,
+	)
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
 func getRuleIDsFromLsBreaking(t *testing.T, fileVersion string, useIDs []string, exceptIDs []string) []string {
 	t.Helper()
 	var stdout bytes.Buffer
@@ -4580,3 +6591,284 @@ func testLsRuleOutputJSON(
 		)
 	require.Equal(t, expectedRules, outputRules)
 }
+
+func TestReservedSyncAndLint(t *testing.T) {
+	t.Parallel()
+	reservedRegistryFilePath := filepath.Join(t.TempDir(), "buf.reserved.yaml")
+	testRunStdout(
+		t,
+		nil,
+		0,
+		``,
+		"beta",
+		"reserved",
+		"sync",
+		filepath.Join("testdata", "reserved", "before"),
+		"--reserved-registry",
+		reservedRegistryFilePath,
+	)
+	data, err := os.ReadFile(reservedRegistryFilePath)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`version: v1
+messages:
+  - name: a.v1.Foo
+    entries:
+      - number: 1
+        name: one
+      - number: 2
+        name: two
+`,
+		string(data),
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		``,
+		"lint",
+		filepath.Join("testdata", "reserved", "before"),
+		"--reserved-registry",
+		reservedRegistryFilePath,
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`testdata/reserved/after/a/v1/a.proto:7:17:Field "three" on message "a.v1.Foo" uses number 2, which was previously used by "two" and is recorded in the reserved registry.`),
+		"lint",
+		filepath.Join("testdata", "reserved", "after"),
+		"--reserved-registry",
+		reservedRegistryFilePath,
+	)
+	// Without --reserved-registry, the registry is read from the directory of the
+	// workspace, even if the input is a file within the workspace.
+	workspaceDirPath := t.TempDir()
+	require.NoError(t, os.CopyFS(workspaceDirPath, os.DirFS(filepath.Join("testdata", "reserved", "after"))))
+	require.NoError(t, os.WriteFile(filepath.Join(workspaceDirPath, "buf.reserved.yaml"), data, 0600))
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.Join(workspaceDirPath, "a", "v1", "a.proto")+`:7:17:Field "three" on message "a.v1.Foo" uses number 2, which was previously used by "two" and is recorded in the reserved registry.`,
+		"lint",
+		filepath.Join(workspaceDirPath, "a", "v1", "a.proto"),
+	)
+}
+
+func TestExtensionSyncAndLint(t *testing.T) {
+	t.Parallel()
+	extensionRegistryFilePath := filepath.Join(t.TempDir(), "buf.extensions.yaml")
+	testRunStdout(
+		t,
+		nil,
+		0,
+		``,
+		"beta",
+		"extension",
+		"sync",
+		filepath.Join("testdata", "extension", "a"),
+		"--extension-registry",
+		extensionRegistryFilePath,
+	)
+	data, err := os.ReadFile(extensionRegistryFilePath)
+	require.NoError(t, err)
+	expectedData := `version: v1
+extendees:
+  - name: google.protobuf.FieldOptions
+    entries:
+      - number: 50000
+        name: a.v1.foo
+`
+	assert.Equal(t, expectedData, string(data))
+	testRunStdoutStderrNoWarn(
+		t,
+		nil,
+		1,
+		``,
+		`Failure: extension numbers are allocated to more than one extension, `+extensionRegistryFilePath+` was not updated:
+  google.protobuf.FieldOptions number 50000 is allocated to a.v1.foo, b.v1.bar`,
+		"beta",
+		"extension",
+		"sync",
+		filepath.Join("testdata", "extension", "b"),
+		"--extension-registry",
+		extensionRegistryFilePath,
+	)
+	data, err = os.ReadFile(extensionRegistryFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, expectedData, string(data))
+	testRunStdout(
+		t,
+		nil,
+		0,
+		``,
+		"lint",
+		filepath.Join("testdata", "extension", "a"),
+		"--extension-registry",
+		extensionRegistryFilePath,
+	)
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.FromSlash(`testdata/extension/b/b/v1/b.proto:8:16:Extension "b.v1.bar" uses number 50000 of "google.protobuf.FieldOptions", which is allocated to "a.v1.foo" in the extension registry.`),
+		"lint",
+		filepath.Join("testdata", "extension", "b"),
+		"--extension-registry",
+		extensionRegistryFilePath,
+	)
+	// Without --extension-registry, the registry is read from the directory of the
+	// workspace, even if the input is a file within the workspace.
+	workspaceDirPath := t.TempDir()
+	require.NoError(t, os.CopyFS(workspaceDirPath, os.DirFS(filepath.Join("testdata", "extension", "b"))))
+	require.NoError(t, os.WriteFile(filepath.Join(workspaceDirPath, "buf.extensions.yaml"), data, 0600))
+	testRunStdout(
+		t,
+		nil,
+		bufctl.ExitCodeFileAnnotation,
+		filepath.Join(workspaceDirPath, "b", "v1", "b.proto")+`:8:16:Extension "b.v1.bar" uses number 50000 of "google.protobuf.FieldOptions", which is allocated to "a.v1.foo" in the extension registry.`,
+		"lint",
+		filepath.Join(workspaceDirPath, "b", "v1", "b.proto"),
+	)
+}
+
+func TestHookServerStdio(t *testing.T) {
+	t.Parallel()
+	repositoriesDirPath := t.TempDir()
+	repositoryDirPath := filepath.Join(repositoriesDirPath, "protos")
+	require.NoError(t, os.CopyFS(repositoryDirPath, os.DirFS(filepath.Join("testdata", "hook_server", "old"))))
+	testRunGit(t, repositoryDirPath, "init")
+	testRunGit(t, repositoryDirPath, "add", ".")
+	testRunGit(t, repositoryDirPath, "commit", "-m", "commit 0")
+	oldRevision := testGetGitRevision(t, repositoryDirPath)
+	data, err := os.ReadFile(filepath.Join("testdata", "hook_server", "new", "a", "v1", "a.proto"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(repositoryDirPath, "a", "v1", "a.proto"), data, 0600))
+	testRunGit(t, repositoryDirPath, "commit", "-a", "-m", "commit 1")
+	newRevision := testGetGitRevision(t, repositoryDirPath)
+	zeroRevision := strings.Repeat("0", 40)
+
+	testRunStdout(
+		t,
+		strings.NewReader(`{"repository":"protos","ref":"refs/heads/main","old_revision":"`+oldRevision+`","new_revision":"`+newRevision+`"}`),
+		bufctl.ExitCodeFileAnnotation,
+		`{"allowed":false,"checks":[`+
+			`{"check":"lint","passed":false,"annotations":[{"path":"a/v1/a.proto","start_line":7,"start_column":10,"end_line":7,"end_column":18,"type":"FIELD_LOWER_SNAKE_CASE","message":"Field name \"twoThree\" should be lower_snake_case, such as \"two_three\".","severity":"error"}]},`+
+			`{"check":"breaking","passed":false,"annotations":[{"path":"a/v1/a.proto","start_line":5,"start_column":1,"end_line":8,"end_column":2,"type":"FIELD_NO_DELETE","message":"Previously present field \"2\" with name \"two\" on message \"Foo\" was deleted.","severity":"error"}]},`+
+			`{"check":"format","passed":false,"annotations":[{"path":"a/v1/a.proto","type":"FORMAT","message":"File is not formatted.","severity":"error"}]}`+
+			`]}`,
+		"beta",
+		"hook-server",
+		"--repositories-dir",
+		repositoriesDirPath,
+		"--stdio",
+	)
+	// Breaking change detection is skipped when the ref is created.
+	testRunStdout(
+		t,
+		strings.NewReader(`{"repository":"protos","ref":"refs/heads/main","old_revision":"`+zeroRevision+`","new_revision":"`+oldRevision+`"}`),
+		0,
+		`{"allowed":true,"checks":[{"check":"lint","passed":true},{"check":"format","passed":true}]}`,
+		"beta",
+		"hook-server",
+		"--repositories-dir",
+		repositoriesDirPath,
+		"--stdio",
+	)
+	testRunStdout(
+		t,
+		strings.NewReader(`{"repository":"protos","ref":"refs/heads/main","old_revision":"`+oldRevision+`","new_revision":"`+newRevision+`"}`),
+		bufctl.ExitCodeFileAnnotation,
+		`{"allowed":false,"checks":[{"check":"format","passed":false,"annotations":[{"path":"a/v1/a.proto","type":"FORMAT","message":"File is not formatted.","severity":"error"}]}]}`,
+		"beta",
+		"hook-server",
+		"--repositories-dir",
+		repositoriesDirPath,
+		"--stdio",
+		"--check",
+		"format",
+	)
+	testRunStdoutStderrNoWarn(
+		t,
+		strings.NewReader(`{"repository":"../protos","old_revision":"`+oldRevision+`","new_revision":"`+newRevision+`"}`),
+		1,
+		``,
+		`Failure: invalid request: invalid repository "../protos": ../protos: is outside the context directory`,
+		"beta",
+		"hook-server",
+		"--repositories-dir",
+		repositoriesDirPath,
+		"--stdio",
+	)
+}
+
+func TestNumbersAllocate(t *testing.T) {
+	t.Parallel()
+	ledgerFilePath := filepath.Join(t.TempDir(), "buf.numbers.yaml")
+	testRunStdout(
+		t,
+		nil,
+		0,
+		`10`,
+		"beta",
+		"numbers",
+		"allocate",
+		filepath.Join("testdata", "numbers"),
+		"--message",
+		"a.v1.Foo",
+		"--field",
+		"three",
+		"--ledger",
+		ledgerFilePath,
+	)
+	testRunStdout(
+		t,
+		nil,
+		0,
+		`11`,
+		"beta",
+		"numbers",
+		"allocate",
+		filepath.Join("testdata", "numbers"),
+		"--message",
+		"a.v1.Foo",
+		"--field",
+		"four",
+		"--ledger",
+		ledgerFilePath,
+	)
+	expectedData := `version: v1
+messages:
+  - name: a.v1.Foo
+    allocations:
+      - number: 10
+        name: three
+      - number: 11
+        name: four
+`
+	data, err := os.ReadFile(ledgerFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, expectedData, string(data))
+	testRunStdoutStderrNoWarn(
+		t,
+		nil,
+		1,
+		``,
+		`Failure: field "three" of message "a.v1.Foo" is already allocated number 10`,
+		"beta",
+		"numbers",
+		"allocate",
+		filepath.Join("testdata", "numbers"),
+		"--message",
+		"a.v1.Foo",
+		"--field",
+		"three",
+		"--ledger",
+		ledgerFilePath,
+	)
+	data, err = os.ReadFile(ledgerFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, expectedData, string(data))
+}
@@ -0,0 +1,135 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pluginfuzzgenerate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/bufpkg/bufprotoplugin/bufprotopluginfuzz"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/spf13/pflag"
+)
+
+const (
+	seedFlagName        = "seed"
+	countFlagName       = "count"
+	outputFlagName      = "output"
+	outputFlagShortName = "o"
+	optFlagName         = "opt"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name,
+		Short: "Generate a corpus of adversarial CodeGeneratorRequests",
+		Long: `One binary CodeGeneratorRequest is written to the output directory for each seed, named <seed>.binpb.
+
+The same seed always results in the same request, so a corpus can be regenerated from its seeds.`,
+		Args: appcmd.NoArgs,
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	Seed   uint64
+	Count  int
+	Output string
+	Opt    string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	flagSet.Uint64Var(
+		&f.Seed,
+		seedFlagName,
+		0,
+		"The first seed to generate a request for",
+	)
+	flagSet.IntVar(
+		&f.Count,
+		countFlagName,
+		100,
+		"The number of requests to generate, for consecutive seeds",
+	)
+	flagSet.StringVarP(
+		&f.Output,
+		outputFlagName,
+		outputFlagShortName,
+		"",
+		"The directory to write the requests to. Required",
+	)
+	flagSet.StringVar(
+		&f.Opt,
+		optFlagName,
+		"",
+		"The parameter to set on the requests",
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	if err := bufcli.ValidateRequiredFlag(outputFlagName, flags.Output); err != nil {
+		return err
+	}
+	if flags.Count < 1 {
+		return appcmd.NewInvalidArgumentErrorf("--%s must be at least 1", countFlagName)
+	}
+	if err := os.MkdirAll(flags.Output, 0755); err != nil {
+		return err
+	}
+	for i := 0; i < flags.Count; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		seed := flags.Seed + uint64(i)
+		request, err := bufprotopluginfuzz.NewRequest(
+			seed,
+			bufprotopluginfuzz.NewRequestWithParameter(flags.Opt),
+		)
+		if err != nil {
+			return err
+		}
+		data, err := protoencoding.NewWireMarshaler().Marshal(request)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(flags.Output, fmt.Sprintf("%d.binpb", seed)), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
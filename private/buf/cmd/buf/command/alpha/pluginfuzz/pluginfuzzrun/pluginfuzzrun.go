@@ -0,0 +1,244 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pluginfuzzrun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufprotoplugin/bufprotopluginfuzz"
+	"github.com/bufbuild/buf/private/pkg/app"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/execext"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+const (
+	seedFlagName        = "seed"
+	countFlagName       = "count"
+	outputFlagName      = "output"
+	outputFlagShortName = "o"
+	optFlagName         = "opt"
+	timeoutFlagName     = "timeout"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <plugin> [plugin-args...]",
+		Short: "Run a plugin against adversarial CodeGeneratorRequests",
+		Long: `The plugin is run once for each seed, with the request generated for the seed on stdin.
+
+A run fails if the plugin exits with a non-zero exit code, does not exit within the timeout, or
+writes a response that cannot be parsed. A response with the error field set is not a failure,
+as plugins are expected to reject some valid requests.
+
+For every failing seed, the request is minimized to the smallest request for which the plugin still
+fails. If --output is set, the minimized request is written to the output directory as <seed>.binpb.
+Use "--" to pass flags to the plugin.`,
+		Args: appcmd.MinimumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	Seed    uint64
+	Count   int
+	Output  string
+	Opt     string
+	Timeout time.Duration
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	flagSet.Uint64Var(
+		&f.Seed,
+		seedFlagName,
+		0,
+		"The first seed to run the plugin with",
+	)
+	flagSet.IntVar(
+		&f.Count,
+		countFlagName,
+		100,
+		"The number of requests to run the plugin with, for consecutive seeds",
+	)
+	flagSet.StringVarP(
+		&f.Output,
+		outputFlagName,
+		outputFlagShortName,
+		"",
+		"The directory to write minimized failing requests to",
+	)
+	flagSet.StringVar(
+		&f.Opt,
+		optFlagName,
+		"",
+		"The parameter to set on the requests",
+	)
+	flagSet.DurationVar(
+		&f.Timeout,
+		timeoutFlagName,
+		10*time.Second,
+		"The maximum time the plugin may run for a single request",
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	if flags.Count < 1 {
+		return appcmd.NewInvalidArgumentErrorf("--%s must be at least 1", countFlagName)
+	}
+	if flags.Timeout <= 0 {
+		return appcmd.NewInvalidArgumentErrorf("--%s must be positive", timeoutFlagName)
+	}
+	args := app.Args(container)
+	runner := &pluginRunner{
+		pluginPath: args[0],
+		pluginArgs: args[1:],
+		environ:    app.Environ(container),
+		timeout:    flags.Timeout,
+	}
+	if flags.Output != "" {
+		if err := os.MkdirAll(flags.Output, 0755); err != nil {
+			return err
+		}
+	}
+	var numFailures int
+	for i := 0; i < flags.Count; i++ {
+		seed := flags.Seed + uint64(i)
+		request, err := bufprotopluginfuzz.NewRequest(
+			seed,
+			bufprotopluginfuzz.NewRequestWithParameter(flags.Opt),
+		)
+		if err != nil {
+			return err
+		}
+		failure, err := runner.run(ctx, request)
+		if err != nil {
+			return err
+		}
+		if failure == "" {
+			continue
+		}
+		numFailures++
+		if _, err := fmt.Fprintf(container.Stdout(), "seed %d: %s\n", seed, failure); err != nil {
+			return err
+		}
+		minimizedRequest, err := bufprotopluginfuzz.Minimize(
+			ctx,
+			request,
+			func(ctx context.Context, request *pluginpb.CodeGeneratorRequest) (bool, error) {
+				failure, err := runner.run(ctx, request)
+				return failure != "", err
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if flags.Output != "" {
+			data, err := protoencoding.NewWireMarshaler().Marshal(minimizedRequest)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(flags.Output, fmt.Sprintf("%d.binpb", seed)), data, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	if numFailures > 0 {
+		return fmt.Errorf("plugin failed for %d of %d requests", numFailures, flags.Count)
+	}
+	return nil
+}
+
+type pluginRunner struct {
+	pluginPath string
+	pluginArgs []string
+	environ    []string
+	timeout    time.Duration
+}
+
+// run runs the plugin with the request, returning a description of the failure,
+// or an empty string if the plugin did not fail.
+//
+// An error is only returned if the plugin could not be run at all.
+func (p *pluginRunner) run(ctx context.Context, request *pluginpb.CodeGeneratorRequest) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	requestData, err := protoencoding.NewWireMarshaler().Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	runOptions := []execext.RunOption{
+		execext.WithEnv(p.environ),
+		execext.WithStdin(bytes.NewReader(requestData)),
+		execext.WithStdout(stdout),
+		execext.WithStderr(stderr),
+	}
+	if len(p.pluginArgs) > 0 {
+		runOptions = append(runOptions, execext.WithArgs(p.pluginArgs...))
+	}
+	if err := execext.Run(timeoutCtx, p.pluginPath, runOptions...); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Sprintf("timed out after %v", p.timeout), nil
+		}
+		var exitError *exec.ExitError
+		if !errors.As(err, &exitError) {
+			return "", err
+		}
+		if stderrData := bytes.TrimSpace(stderr.Bytes()); len(stderrData) > 0 {
+			return fmt.Sprintf("%v: %s", err, stderrData), nil
+		}
+		return err.Error(), nil
+	}
+	response := &pluginpb.CodeGeneratorResponse{}
+	if err := protoencoding.NewWireUnmarshaler(nil).Unmarshal(stdout.Bytes(), response); err != nil {
+		return fmt.Sprintf("invalid response: %v", err), nil
+	}
+	return "", nil
+}
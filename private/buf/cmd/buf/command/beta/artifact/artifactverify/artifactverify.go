@@ -0,0 +1,127 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifactverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufartifact"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/storage/storageos"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/spf13/pflag"
+)
+
+const (
+	errorFormatFlagName = "error-format"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <directory>",
+		Short: "Verify the integrity of a directory of build artifacts",
+		Long: `Verify the internal consistency of a directory of build artifacts, such as before
+artifacts are promoted between environments.
+
+The first argument is the directory to verify. It defaults to "." if not specified.
+
+The following files are verified:
+
+  - Files with the .manifest extension are manifests, with one "<digest>  <path>" line per
+    file, sorted by path. Every file in the manifest must exist relative to the directory
+    of the manifest, and its content must match its digest.
+  - Files with the .digest extension contain a single digest, such as "shake256:<hex>". The
+    file at the same path without the .digest extension must exist, and its content must
+    match the digest.
+  - Files with the .binpb or .bin extension, optionally followed by .gz or .zst, must be
+    valid binary Buf images.
+
+All other files are ignored. Any problems found are printed to stdout, and the command
+exits with a non-zero exit code.`,
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	ErrorFormat string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(
+		&f.ErrorFormat,
+		errorFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			"The format for problems printed to stdout. Must be one of %s",
+			stringutil.SliceToString(bufanalysis.AllFormatStrings),
+		),
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	if _, err := bufanalysis.ParseFormat(flags.ErrorFormat); err != nil {
+		return appcmd.WrapInvalidArgumentError(err)
+	}
+	dirPath := "."
+	if container.NumArgs() > 0 {
+		dirPath = container.Arg(0)
+	}
+	readBucket, err := storageos.NewProvider(storageos.ProviderWithSymlinks()).NewReadWriteBucket(
+		dirPath,
+		storageos.ReadWriteBucketWithSymlinksIfSupported(),
+	)
+	if err != nil {
+		return err
+	}
+	if err := bufartifact.Verify(ctx, readBucket); err != nil {
+		var fileAnnotationSet bufanalysis.FileAnnotationSet
+		if errors.As(err, &fileAnnotationSet) {
+			if err := bufanalysis.PrintFileAnnotationSet(
+				container.Stdout(),
+				fileAnnotationSet,
+				flags.ErrorFormat,
+			); err != nil {
+				return err
+			}
+			return bufctl.ErrFileAnnotation
+		}
+		return err
+	}
+	return nil
+}
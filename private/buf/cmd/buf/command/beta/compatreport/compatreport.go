@@ -0,0 +1,170 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compatreport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufcompat"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/spf13/pflag"
+)
+
+const (
+	errorFormatFlagName     = "error-format"
+	configFlagName          = "config"
+	pathsFlagName           = "path"
+	excludePathsFlagName    = "exclude-path"
+	disableSymlinksFlagName = "disable-symlinks"
+	maxOneofFieldsFlagName  = "max-oneof-fields"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <input>",
+		Short: "Report constructs that behave inconsistently across languages",
+		Long: fmt.Sprintf(`Report constructs in the input that are known to be problematic or inconsistent across the runtimes of different languages.
+
+The following are reported:
+
+  - %s: Fields with a 64-bit integer type. These are encoded as strings in JSON, and lose
+    precision if parsed as numbers in JavaScript.
+  - %s: Fields whose names collide with another field of the same message
+    after case conversion, such as foo_bar and FooBar. Many code generators produce code that does
+    not compile for these fields.
+  - %s: Oneofs with more fields than --%s. Large oneofs are slow to compile in Swift.
+  - %s: Fields that use group encoding, which is deprecated and not supported consistently
+    across runtimes.
+
+Only the files of the input are reported on, not their imports. If anything is reported, the
+exit code is non-zero.
+
+`, bufcompat.TypeInt64JSON, bufcompat.TypeFieldNameCaseCollision, bufcompat.TypeLargeOneof, maxOneofFieldsFlagName, bufcompat.TypeGroup) +
+			bufcli.GetInputLong(`the source, module, or image to report on`),
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	ErrorFormat     string
+	Config          string
+	Paths           []string
+	ExcludePaths    []string
+	DisableSymlinks bool
+	MaxOneofFields  int
+	// special
+	InputHashtag string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
+	bufcli.BindPaths(flagSet, &f.Paths, pathsFlagName)
+	bufcli.BindExcludePaths(flagSet, &f.ExcludePaths, excludePathsFlagName)
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	flagSet.StringVar(
+		&f.ErrorFormat,
+		errorFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			"The format for build errors and reported constructs printed to stdout. Must be one of %s",
+			stringutil.SliceToString(bufanalysis.AllFormatStrings),
+		),
+	)
+	flagSet.StringVar(
+		&f.Config,
+		configFlagName,
+		"",
+		`The buf.yaml file or data to use for configuration`,
+	)
+	flagSet.IntVar(
+		&f.MaxOneofFields,
+		maxOneofFieldsFlagName,
+		bufcompat.DefaultMaxOneofFields,
+		fmt.Sprintf(
+			`The maximum number of fields of a oneof before it is reported as %s`,
+			bufcompat.TypeLargeOneof,
+		),
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	if flags.MaxOneofFields < 0 {
+		return appcmd.NewInvalidArgumentErrorf("--%s must not be negative", maxOneofFieldsFlagName)
+	}
+	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	if err != nil {
+		return err
+	}
+	controller, err := bufcli.NewController(
+		container,
+		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
+	)
+	if err != nil {
+		return err
+	}
+	image, err := controller.GetImage(
+		ctx,
+		input,
+		bufctl.WithTargetPaths(flags.Paths, flags.ExcludePaths),
+		bufctl.WithConfigOverride(flags.Config),
+	)
+	if err != nil {
+		return err
+	}
+	fileAnnotations, err := bufcompat.Report(
+		image,
+		bufcompat.ReportWithMaxOneofFields(flags.MaxOneofFields),
+	)
+	if err != nil {
+		return err
+	}
+	if len(fileAnnotations) > 0 {
+		if err := bufanalysis.PrintFileAnnotationSet(
+			container.Stdout(),
+			bufanalysis.NewFileAnnotationSet(fileAnnotations...),
+			flags.ErrorFormat,
+		); err != nil {
+			return err
+		}
+		return bufctl.ErrFileAnnotation
+	}
+	return nil
+}
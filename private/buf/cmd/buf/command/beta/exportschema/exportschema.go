@@ -0,0 +1,215 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exportschema
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/bufschemaexport"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	errorFormatFlagName     = "error-format"
+	disableSymlinksFlagName = "disable-symlinks"
+	formatFlagName          = "format"
+	typeFlagName            = "type"
+	outputFlagName          = "output"
+	outputFlagShortName     = "o"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <input>",
+		Short: "Export messages as Avro schemas, JSON Schemas, or data warehouse table schemas",
+		Long: `Export the messages set by --type as Avro schemas or JSON Schemas, for integration with
+schema registries and event catalogs, or as table schemas, for integration with data warehouses.
+
+With --format avro, each message is exported as an Avro record schema. With --format jsonschema,
+each message is exported as a JSON Schema (draft 2020-12) that describes the ProtoJSON encoding
+of the message. With --format bigquery, each message is exported as a BigQuery table schema,
+as accepted by "bq mk --table", with messages mapped to RECORD columns. With --format snowflake
+and --format redshift, each message is exported as a CREATE TABLE statement, with messages mapped
+to semi-structured columns. The mapping rules are stable: the schema of a message only changes if the
+message, or a message or enum it references, changes.
+
+If a single type is exported and --output is not set, the schema is printed to stdout:
+
+    $ buf beta export-schema --format avro --type acme.v1.Event
+
+Otherwise, --output must be set to a directory, and a schema file is written to it for each type,
+named after the full name of the type, such as acme.v1.Event.avsc for Avro,
+acme.v1.Event.schema.json for JSON Schema, acme.v1.Event.bigquery.json for BigQuery, and
+acme.v1.Event.snowflake.sql and acme.v1.Event.redshift.sql for Snowflake and Redshift.
+Existing files are overwritten:
+
+    $ buf beta export-schema --format jsonschema --type acme.v1.Event --type acme.v1.Order -o schemas
+
+` + bufcli.GetInputLong(`the source, module, or image with the messages to export`),
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	ErrorFormat     string
+	DisableSymlinks bool
+	Format          string
+	Types           []string
+	Output          string
+	// special
+	InputHashtag string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	flagSet.StringVar(
+		&f.ErrorFormat,
+		errorFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			"The format for build errors printed to stderr. Must be one of %s",
+			stringutil.SliceToString(bufanalysis.AllFormatStrings),
+		),
+	)
+	flagSet.StringVar(
+		&f.Format,
+		formatFlagName,
+		"",
+		fmt.Sprintf(
+			"The format of the schemas to export. Must be one of %s",
+			stringutil.SliceToString(bufschemaexport.AllFormatStrings),
+		),
+	)
+	_ = appcmd.MarkFlagRequired(flagSet, formatFlagName)
+	flagSet.StringSliceVar(
+		&f.Types,
+		typeFlagName,
+		nil,
+		`The fully-qualified names of the messages to export, such as acme.v1.Event. May be provided multiple times`,
+	)
+	_ = appcmd.MarkFlagRequired(flagSet, typeFlagName)
+	flagSet.StringVarP(
+		&f.Output,
+		outputFlagName,
+		outputFlagShortName,
+		"",
+		`The directory to write a schema file for each type to. Required if more than one type is exported. If not set, the schema is printed to stdout`,
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	format, err := bufschemaexport.ParseFormat(flags.Format)
+	if err != nil {
+		return appcmd.WrapInvalidArgumentError(err)
+	}
+	if len(flags.Types) > 1 && flags.Output == "" {
+		return appcmd.NewInvalidArgumentErrorf("--%s must be set when exporting more than one type", outputFlagName)
+	}
+	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	if err != nil {
+		return err
+	}
+	controller, err := bufcli.NewController(
+		container,
+		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
+	)
+	if err != nil {
+		return err
+	}
+	// Source code info is needed for the comments that are added to the schemas.
+	image, err := controller.GetImage(ctx, input)
+	if err != nil {
+		return err
+	}
+	schemas := make([][]byte, len(flags.Types))
+	for i, typeName := range flags.Types {
+		descriptor, err := image.Resolver().FindDescriptorByName(protoreflect.FullName(typeName))
+		if err != nil {
+			return fmt.Errorf("--%s: type %q not found", typeFlagName, typeName)
+		}
+		messageDescriptor, ok := descriptor.(protoreflect.MessageDescriptor)
+		if !ok {
+			return fmt.Errorf("--%s: %q is not a message", typeFlagName, typeName)
+		}
+		schema, err := bufschemaexport.ExportSchema(messageDescriptor, format)
+		if err != nil {
+			return err
+		}
+		schemas[i] = append(schema, '\n')
+	}
+	if flags.Output == "" {
+		_, err := container.Stdout().Write(schemas[0])
+		return err
+	}
+	if err := os.MkdirAll(flags.Output, 0755); err != nil {
+		return err
+	}
+	for i, typeName := range flags.Types {
+		if err := os.WriteFile(
+			filepath.Join(flags.Output, typeName+getFileExtension(format)),
+			schemas[i],
+			0644,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getFileExtension(format bufschemaexport.Format) string {
+	switch format {
+	case bufschemaexport.FormatAvro:
+		return ".avsc"
+	case bufschemaexport.FormatBigQuery:
+		return ".bigquery.json"
+	case bufschemaexport.FormatSnowflake:
+		return ".snowflake.sql"
+	case bufschemaexport.FormatRedshift:
+		return ".redshift.sql"
+	default:
+		return ".schema.json"
+	}
+}
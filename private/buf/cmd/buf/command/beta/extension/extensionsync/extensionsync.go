@@ -0,0 +1,174 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extensionsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufextension"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/spf13/pflag"
+)
+
+const (
+	extensionRegistryFlagName = "extension-registry"
+	errorFormatFlagName       = "error-format"
+	disableSymlinksFlagName   = "disable-symlinks"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <input>",
+		Short: "Record all extension numbers in the extension registry",
+		Long: `Every extension number of every extendee in the input is added to the extension registry.
+Entries already recorded in the registry are never removed, so the registry is an allocation map of
+every extension number that has ever been used, across all the modules that are synced to it.
+
+If a number would be allocated to more than one extension of the same extendee, such as two modules
+that extend google.protobuf.FieldOptions with the same number, the conflicts are printed, the
+registry is not updated, and the command fails.
+
+The EXTENSION_REGISTRY_NO_CONFLICT lint rule uses the registry to check that extensions do not use
+numbers allocated to other extensions.
+
+` + bufcli.GetInputLong(`the source, module, or Image to record`),
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	ExtensionRegistry string
+	ErrorFormat       string
+	DisableSymlinks   bool
+	// special
+	InputHashtag string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	flagSet.StringVar(
+		&f.ExtensionRegistry,
+		extensionRegistryFlagName,
+		bufextension.DefaultFileName,
+		`The extension registry file to update. The file is created if it does not exist`,
+	)
+	flagSet.StringVar(
+		&f.ErrorFormat,
+		errorFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			"The format for build errors printed to stderr. Must be one of %s",
+			stringutil.SliceToString(bufanalysis.AllFormatStrings),
+		),
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	if err := bufcli.ValidateRequiredFlag(extensionRegistryFlagName, flags.ExtensionRegistry); err != nil {
+		return err
+	}
+	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	if err != nil {
+		return err
+	}
+	controller, err := bufcli.NewController(
+		container,
+		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
+	)
+	if err != nil {
+		return err
+	}
+	image, err := controller.GetImage(
+		ctx,
+		input,
+		bufctl.WithImageExcludeSourceInfo(true),
+	)
+	if err != nil {
+		return err
+	}
+	registry := bufextension.NewRegistryForImage(image)
+	existingRegistry, err := readRegistryIfExists(flags.ExtensionRegistry)
+	if err != nil {
+		return err
+	}
+	if existingRegistry != nil {
+		registry = bufextension.MergeRegistries(existingRegistry, registry)
+	}
+	if conflicts := bufextension.Conflicts(registry); len(conflicts) > 0 {
+		lines := make([]string, len(conflicts))
+		for i, conflict := range conflicts {
+			lines[i] = fmt.Sprintf(
+				"  %s number %d is allocated to %s",
+				conflict.Extendee,
+				conflict.Number,
+				strings.Join(conflict.Names, ", "),
+			)
+		}
+		return fmt.Errorf(
+			"extension numbers are allocated to more than one extension, %s was not updated:\n%s",
+			flags.ExtensionRegistry,
+			strings.Join(lines, "\n"),
+		)
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := bufextension.WriteRegistry(buffer, registry); err != nil {
+		return err
+	}
+	return os.WriteFile(flags.ExtensionRegistry, buffer.Bytes(), 0644)
+}
+
+func readRegistryIfExists(path string) (bufextension.Registry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+	return bufextension.ReadRegistry(file)
+}
@@ -0,0 +1,250 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package freeze
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/buffreeze"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
+	"github.com/bufbuild/buf/private/bufpkg/bufremoteplugin/bufremotepluginref"
+	"github.com/bufbuild/buf/private/gen/proto/connect/buf/alpha/registry/v1alpha1/registryv1alpha1connect"
+	registryv1alpha1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/registry/v1alpha1"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/connectclient"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storageos"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	return &appcmd.Command{
+		Use:   name + " <directory>",
+		Short: "Pin the references in buf.gen.yaml and buf.lock to a " + buffreeze.FileName + " file",
+		Long: `Resolve the references in the buf.gen.yaml and buf.lock files in the directory that can change over time, and write them to a ` + buffreeze.FileName + ` file in the directory.
+
+Remote plugins are pinned to the version and revision they currently resolve to, module inputs are pinned to the commit their label currently resolves to, and the dependencies and plugins of the buf.lock file are recorded as-is.
+
+When "buf generate --frozen" is run, the pinned references are used instead of the references in buf.gen.yaml, and an error is returned if buf.gen.yaml or buf.lock have changed since ` + buffreeze.FileName + ` was written. This makes sure that release builds behave identically to the build that the ` + buffreeze.FileName + ` file was written for.
+
+The directory defaults to the current directory.`,
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container)
+			},
+		),
+	}
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+) error {
+	dirPath := "."
+	if container.NumArgs() > 0 {
+		dirPath = container.Arg(0)
+	}
+	bucket, err := storageos.NewProvider(storageos.ProviderWithSymlinks()).NewReadWriteBucket(
+		dirPath,
+		storageos.ReadWriteBucketWithSymlinksIfSupported(),
+	)
+	if err != nil {
+		return err
+	}
+	bufGenYAMLFile, err := bufconfig.GetBufGenYAMLFileForPrefix(
+		ctx,
+		bucket,
+		".",
+		bufconfig.BufGenYAMLFileWithExtendsFromOS(dirPath),
+		bufconfig.BufGenYAMLFileWithEnvFunc(container.Env),
+	)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	bufLockFile, err := bufconfig.GetBufLockFileForPrefix(ctx, bucket, ".")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if bufGenYAMLFile == nil && bufLockFile == nil {
+		return fmt.Errorf("no buf.gen.yaml or %s file found in %q", bufconfig.DefaultBufLockFileName, dirPath)
+	}
+	var remotePluginPins []buffreeze.RemotePluginPin
+	var moduleInputPins []buffreeze.ModuleInputPin
+	if bufGenYAMLFile != nil {
+		remotePluginPins, err = getRemotePluginPins(ctx, container, bufGenYAMLFile)
+		if err != nil {
+			return err
+		}
+		moduleInputPins, err = getModuleInputPins(ctx, container, bufGenYAMLFile)
+		if err != nil {
+			return err
+		}
+	}
+	var depPins []buffreeze.LockPin
+	var pluginPins []buffreeze.LockPin
+	if bufLockFile != nil {
+		depPins, pluginPins, err = buffreeze.GetLockPinsForBufLockFile(bufLockFile)
+		if err != nil {
+			return err
+		}
+	}
+	file, err := buffreeze.NewFile(remotePluginPins, moduleInputPins, depPins, pluginPins)
+	if err != nil {
+		return err
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := buffreeze.WriteFile(buffer, file); err != nil {
+		return err
+	}
+	return storage.PutPath(ctx, bucket, buffreeze.FileName, buffer.Bytes())
+}
+
+func getRemotePluginPins(
+	ctx context.Context,
+	container appext.Container,
+	bufGenYAMLFile bufconfig.BufGenYAMLFile,
+) ([]buffreeze.RemotePluginPin, error) {
+	var clientConfig *connectclient.Config
+	var remotePluginPins []buffreeze.RemotePluginPin
+	for _, pluginConfig := range bufGenYAMLFile.GenerateConfig().GeneratePluginConfigs() {
+		if pluginConfig.Type() != bufconfig.GeneratePluginConfigTypeRemote {
+			continue
+		}
+		if containsRemotePluginPin(remotePluginPins, pluginConfig.Name(), pluginConfig.Revision()) {
+			continue
+		}
+		identity, version, err := bufremotepluginref.ParsePluginIdentityOptionalVersion(pluginConfig.Name())
+		if err != nil {
+			return nil, err
+		}
+		if clientConfig == nil {
+			clientConfig, err = bufcli.NewPluginConnectClientConfig(container)
+			if err != nil {
+				return nil, err
+			}
+		}
+		pluginCurationServiceClient := connectclient.Make(
+			clientConfig,
+			identity.Remote(),
+			registryv1alpha1connect.NewPluginCurationServiceClient,
+		)
+		response, err := pluginCurationServiceClient.GetLatestCuratedPlugin(
+			ctx,
+			connect.NewRequest(
+				registryv1alpha1.GetLatestCuratedPluginRequest_builder{
+					Owner:    identity.Owner(),
+					Name:     identity.Plugin(),
+					Version:  version,
+					Revision: uint32(pluginConfig.Revision()),
+				}.Build(),
+			),
+		)
+		if err != nil {
+			if connect.CodeOf(err) == connect.CodeNotFound {
+				return nil, bufcli.NewPluginNotFoundError(pluginConfig.Name())
+			}
+			return nil, err
+		}
+		plugin := response.Msg.GetPlugin()
+		remotePluginPins = append(
+			remotePluginPins,
+			buffreeze.RemotePluginPin{
+				Remote:         pluginConfig.Name(),
+				Revision:       pluginConfig.Revision(),
+				PinnedRemote:   identity.IdentityString() + ":" + plugin.GetVersion(),
+				PinnedRevision: int(plugin.GetRevision()),
+			},
+		)
+	}
+	return remotePluginPins, nil
+}
+
+func getModuleInputPins(
+	ctx context.Context,
+	container appext.Container,
+	bufGenYAMLFile bufconfig.BufGenYAMLFile,
+) ([]buffreeze.ModuleInputPin, error) {
+	var moduleKeyProvider bufmodule.ModuleKeyProvider
+	var moduleInputPins []buffreeze.ModuleInputPin
+	for _, inputConfig := range bufGenYAMLFile.InputConfigs() {
+		if inputConfig.Type() != bufconfig.InputConfigTypeModule {
+			continue
+		}
+		if containsModuleInputPin(moduleInputPins, inputConfig.Location()) {
+			continue
+		}
+		moduleRef, err := bufparse.ParseRef(inputConfig.Location())
+		if err != nil {
+			return nil, err
+		}
+		if moduleKeyProvider == nil {
+			moduleKeyProvider, err = bufcli.NewModuleKeyProvider(container)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// Resolved one at a time, as the same module may be used as an input at different refs.
+		moduleKeys, err := moduleKeyProvider.GetModuleKeysForModuleRefs(
+			ctx,
+			[]bufparse.Ref{moduleRef},
+			bufmodule.DigestTypeB5,
+		)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, bufcli.NewRefNotFoundError(moduleRef)
+			}
+			return nil, err
+		}
+		moduleInputPins = append(
+			moduleInputPins,
+			buffreeze.ModuleInputPin{
+				Module:       inputConfig.Location(),
+				PinnedModule: moduleKeys[0].String(),
+			},
+		)
+	}
+	return moduleInputPins, nil
+}
+
+func containsRemotePluginPin(remotePluginPins []buffreeze.RemotePluginPin, remote string, revision int) bool {
+	for _, remotePluginPin := range remotePluginPins {
+		if remotePluginPin.Remote == remote && remotePluginPin.Revision == revision {
+			return true
+		}
+	}
+	return false
+}
+
+func containsModuleInputPin(moduleInputPins []buffreeze.ModuleInputPin, module string) bool {
+	for _, moduleInputPin := range moduleInputPins {
+		if moduleInputPin.Module == module {
+			return true
+		}
+	}
+	return false
+}
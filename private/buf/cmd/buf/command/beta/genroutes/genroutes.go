@@ -0,0 +1,235 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genroutes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/buftranscode"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	errorFormatFlagName        = "error-format"
+	disableSymlinksFlagName    = "disable-symlinks"
+	formatFlagName             = "format"
+	outputFlagName             = "output"
+	outputFlagShortName        = "o"
+	envoyClusterFlagName       = "envoy-cluster"
+	envoyDescriptorSetFlagName = "envoy-descriptor-set-path"
+
+	formatEnvoy       = "envoy"
+	formatGRPCGateway = "grpc-gateway"
+
+	envoyConfigFileName        = "envoy.yaml"
+	envoyDescriptorSetFileName = "descriptor.binpb"
+	grpcGatewayConfigFileName  = "grpc_api_configuration.yaml"
+)
+
+var allFormats = []string{
+	formatEnvoy,
+	formatGRPCGateway,
+}
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <input>",
+		Short: "Generate Envoy or gRPC-Gateway route configuration from google.api.http annotations",
+		Long: `Generate route configuration for gRPC JSON transcoding from the google.api.http annotations
+of the methods of the input.
+
+With --format envoy, the following files are written to the output directory:
+
+  - envoy.yaml contains the http_filters and route_config of an Envoy HTTP connection manager,
+    with a gRPC-JSON transcoder filter for the services with annotations, and routes from these
+    services to the cluster set by --envoy-cluster.
+  - descriptor.binpb is the FileDescriptorSet of the input, including imports, that the
+    transcoder filter reads. The path the filter reads it from is set by
+    --envoy-descriptor-set-path, and defaults to the path the file is written to.
+
+With --format grpc-gateway, grpc_api_configuration.yaml is written to the output directory. This
+is a gRPC API configuration file that can be passed to protoc-gen-grpc-gateway with the
+grpc_api_configuration option.
+
+Existing files are overwritten.
+
+` + bufcli.GetInputLong(`the source, module, or image to generate routes for`),
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	ErrorFormat            string
+	DisableSymlinks        bool
+	Format                 string
+	Output                 string
+	EnvoyCluster           string
+	EnvoyDescriptorSetPath string
+	// special
+	InputHashtag string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	flagSet.StringVar(
+		&f.ErrorFormat,
+		errorFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			"The format for build errors printed to stderr. Must be one of %s",
+			stringutil.SliceToString(bufanalysis.AllFormatStrings),
+		),
+	)
+	flagSet.StringVar(
+		&f.Format,
+		formatFlagName,
+		formatEnvoy,
+		fmt.Sprintf(
+			"The format of the route configuration to generate. Must be one of %s",
+			stringutil.SliceToString(allFormats),
+		),
+	)
+	flagSet.StringVarP(
+		&f.Output,
+		outputFlagName,
+		outputFlagShortName,
+		".",
+		`The directory to write the route configuration to`,
+	)
+	flagSet.StringVar(
+		&f.EnvoyCluster,
+		envoyClusterFlagName,
+		"grpc",
+		fmt.Sprintf(
+			`The name of the Envoy cluster to route requests to. Only used with --%s %s`,
+			formatFlagName,
+			formatEnvoy,
+		),
+	)
+	flagSet.StringVar(
+		&f.EnvoyDescriptorSetPath,
+		envoyDescriptorSetFlagName,
+		"",
+		fmt.Sprintf(
+			`The path that the Envoy transcoder filter reads the descriptor set from, such as the path it is mounted at in a container. Defaults to the path the descriptor set is written to. Only used with --%s %s`,
+			formatFlagName,
+			formatEnvoy,
+		),
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	switch flags.Format {
+	case formatEnvoy, formatGRPCGateway:
+	default:
+		return appcmd.NewInvalidArgumentErrorf("--%s must be one of %s", formatFlagName, stringutil.SliceToString(allFormats))
+	}
+	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	if err != nil {
+		return err
+	}
+	controller, err := bufcli.NewController(
+		container,
+		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
+	)
+	if err != nil {
+		return err
+	}
+	// The transcoder filter does not need source code info, which can be large.
+	image, err := controller.GetImage(
+		ctx,
+		input,
+		bufctl.WithImageExcludeSourceInfo(true),
+	)
+	if err != nil {
+		return err
+	}
+	transcoder, err := buftranscode.NewTranscoder(image)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(flags.Output, 0755); err != nil {
+		return err
+	}
+	switch flags.Format {
+	case formatEnvoy:
+		return writeEnvoyConfig(image, transcoder, flags.Output, flags.EnvoyCluster, flags.EnvoyDescriptorSetPath)
+	case formatGRPCGateway:
+		data, err := buftranscode.GRPCGatewayConfigForBindings(transcoder.Bindings())
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(flags.Output, grpcGatewayConfigFileName), data, 0644)
+	default:
+		return fmt.Errorf("unknown format: %q", flags.Format)
+	}
+}
+
+func writeEnvoyConfig(
+	image bufimage.Image,
+	transcoder buftranscode.Transcoder,
+	outputDirPath string,
+	clusterName string,
+	descriptorSetPath string,
+) error {
+	descriptorSetFilePath := filepath.Join(outputDirPath, envoyDescriptorSetFileName)
+	if descriptorSetPath == "" {
+		descriptorSetPath = descriptorSetFilePath
+	}
+	envoyConfigData, err := buftranscode.EnvoyConfigForBindings(transcoder.Bindings(), descriptorSetPath, clusterName)
+	if err != nil {
+		return err
+	}
+	descriptorSetData, err := proto.MarshalOptions{Deterministic: true}.Marshal(bufimage.ImageToFileDescriptorSet(image))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(descriptorSetFilePath, descriptorSetData, 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputDirPath, envoyConfigFileName), envoyConfigData, 0644)
+}
@@ -0,0 +1,472 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hookserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"slices"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/bufformat"
+	"github.com/bufbuild/buf/private/buf/bufhookserver"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/slogext"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/bufbuild/buf/private/pkg/transport/http/httpserver"
+	"github.com/bufbuild/buf/private/pkg/wasm"
+	"github.com/spf13/pflag"
+)
+
+const (
+	repositoriesDirFlagName = "repositories-dir"
+	checkFlagName           = "check"
+	bindFlagName            = "bind"
+	portFlagName            = "port"
+	stdioFlagName           = "stdio"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name,
+		Short: "Run an HTTP server that evaluates pushes to git repositories",
+		Long: `The hook server evaluates pushes to the git repositories in the directory set with
+--` + repositoriesDirFlagName + `, so that self-hosted git servers can enforce schema policy centrally from
+pre-receive hooks, without running CI for every repository.
+
+Requests are POSTed as JSON to ` + bufhookserver.EvaluatePath + `, with the fields that git passes to
+pre-receive hooks:
+
+    {
+      "repository": "acme/protos.git",
+      "ref": "refs/heads/main",
+      "old_revision": "<the revision of the ref before the push>",
+      "new_revision": "<the revision of the ref after the push>"
+    }
+
+The repository is relative to the repositories directory. The response is the decision as JSON,
+with the result and annotations of each check:
+
+    {
+      "allowed": false,
+      "checks": [
+        {
+          "check": "lint",
+          "passed": false,
+          "annotations": [...]
+        }
+      ]
+    }
+
+The checks are set with --` + checkFlagName + `, and are lint, breaking, and format by default. The lint
+and breaking configuration is read from the buf.yaml of the new revision. Breaking change detection
+is against the old revision, and is skipped if the push creates the ref. Pushes that delete a ref
+are always allowed.
+
+With --` + stdioFlagName + `, a single request is read from stdin and the decision is written to stdout
+instead, and the command fails if the push is not allowed. This allows pre-receive hooks to invoke
+the hook server over SSH.`,
+		Args: appcmd.NoArgs,
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	RepositoriesDir string
+	Checks          []string
+	BindAddress     string
+	Port            string
+	Stdio           bool
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(
+		&f.RepositoriesDir,
+		repositoriesDirFlagName,
+		"",
+		"The directory that contains the git repositories to evaluate pushes to",
+	)
+	flagSet.StringSliceVar(
+		&f.Checks,
+		checkFlagName,
+		bufhookserver.AllChecks,
+		fmt.Sprintf(
+			"The checks to run. Must be one of %s. May be provided multiple times",
+			stringutil.SliceToString(bufhookserver.AllChecks),
+		),
+	)
+	flagSet.StringVar(
+		&f.BindAddress,
+		bindFlagName,
+		"127.0.0.1",
+		"The address to be exposed to accept HTTP requests",
+	)
+	flagSet.StringVar(
+		&f.Port,
+		portFlagName,
+		"8080",
+		"The port to be exposed to accept HTTP requests",
+	)
+	flagSet.BoolVar(
+		&f.Stdio,
+		stdioFlagName,
+		false,
+		"Read a single request from stdin and write the decision to stdout instead of running an HTTP server",
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) (retErr error) {
+	if err := bufcli.ValidateRequiredFlag(repositoriesDirFlagName, flags.RepositoriesDir); err != nil {
+		return err
+	}
+	for _, check := range flags.Checks {
+		if !slices.Contains(bufhookserver.AllChecks, check) {
+			return appcmd.NewInvalidArgumentErrorf(
+				"--%s: unknown check %q, must be one of %s",
+				checkFlagName,
+				check,
+				stringutil.SliceToString(bufhookserver.AllChecks),
+			)
+		}
+	}
+	wasmRuntimeCacheDir, err := bufcli.CreateWasmRuntimeCacheDir(container)
+	if err != nil {
+		return err
+	}
+	wasmRuntime, err := wasm.NewRuntime(ctx, wasm.WithLocalCacheDir(wasmRuntimeCacheDir))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, wasmRuntime.Close(ctx))
+	}()
+	evaluator := newEvaluator(container, wasmRuntime, flags.RepositoriesDir, flags.Checks)
+	if flags.Stdio {
+		return runStdio(ctx, container, evaluator)
+	}
+	var httpListenConfig net.ListenConfig
+	httpListener, err := httpListenConfig.Listen(ctx, "tcp", fmt.Sprintf("%s:%s", flags.BindAddress, flags.Port))
+	if err != nil {
+		return err
+	}
+	return httpserver.Run(
+		ctx,
+		container.Logger(),
+		httpListener,
+		bufhookserver.NewHandler(container.Logger(), evaluator),
+	)
+}
+
+func runStdio(
+	ctx context.Context,
+	container appext.Container,
+	evaluator bufhookserver.Evaluator,
+) error {
+	data, err := io.ReadAll(container.Stdin())
+	if err != nil {
+		return err
+	}
+	request := &bufhookserver.Request{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(request); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	if err := bufhookserver.ValidateRequest(request); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	decision, err := evaluator.Evaluate(ctx, request)
+	if err != nil {
+		return err
+	}
+	data, err = json.Marshal(decision)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(container.Stdout(), string(data)); err != nil {
+		return err
+	}
+	if !decision.Allowed {
+		return bufctl.ErrFileAnnotation
+	}
+	return nil
+}
+
+type evaluator struct {
+	container           appext.Container
+	wasmRuntime         wasm.Runtime
+	repositoriesDirPath string
+	checks              []string
+}
+
+func newEvaluator(
+	container appext.Container,
+	wasmRuntime wasm.Runtime,
+	repositoriesDirPath string,
+	checks []string,
+) *evaluator {
+	return &evaluator{
+		container:           container,
+		wasmRuntime:         wasmRuntime,
+		repositoriesDirPath: repositoriesDirPath,
+		checks:              checks,
+	}
+}
+
+func (e *evaluator) Evaluate(ctx context.Context, request *bufhookserver.Request) (*bufhookserver.Decision, error) {
+	if request.IsDelete() {
+		return bufhookserver.NewDecision(), nil
+	}
+	// The Request is validated, so the repository is a relative path within the
+	// repositories directory.
+	repositoryPath := filepath.Join(e.repositoriesDirPath, normalpath.Unnormalize(normalpath.Normalize(request.Repository)))
+	input := gitInput(repositoryPath, request.NewRevision)
+	controller, err := bufcli.NewController(
+		e.container,
+		bufctl.WithFileAnnotationSetsReturned(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var checkResults []*bufhookserver.CheckResult
+	for _, check := range e.checks {
+		var fileAnnotations []bufanalysis.FileAnnotation
+		var err error
+		switch check {
+		case bufhookserver.CheckLint:
+			fileAnnotations, err = e.lint(ctx, controller, input)
+		case bufhookserver.CheckBreaking:
+			if request.IsCreate() {
+				// There is no previous revision to check against.
+				continue
+			}
+			fileAnnotations, err = e.breaking(ctx, controller, input, gitInput(repositoryPath, request.OldRevision))
+		case bufhookserver.CheckFormat:
+			fileAnnotations, err = format(ctx, controller, input)
+		default:
+			return nil, fmt.Errorf("unknown check %q", check)
+		}
+		if err != nil {
+			// Failures to build the new revision are reported as annotations of the check.
+			var fileAnnotationSet bufanalysis.FileAnnotationSet
+			if !errors.As(err, &fileAnnotationSet) {
+				return nil, err
+			}
+			fileAnnotations = fileAnnotationSet.FileAnnotations()
+		}
+		checkResults = append(checkResults, bufhookserver.NewCheckResult(check, fileAnnotations))
+	}
+	return bufhookserver.NewDecision(checkResults...), nil
+}
+
+func (e *evaluator) lint(
+	ctx context.Context,
+	controller bufctl.Controller,
+	input string,
+) ([]bufanalysis.FileAnnotation, error) {
+	imageWithConfigs, checkClient, err := controller.GetTargetImageWithConfigsAndCheckClient(
+		ctx,
+		input,
+		e.wasmRuntime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	allCheckConfigs := getAllCheckConfigs(imageWithConfigs)
+	var allFileAnnotations []bufanalysis.FileAnnotation
+	for _, imageWithConfig := range imageWithConfigs {
+		if err := checkClient.Lint(
+			ctx,
+			imageWithConfig.LintConfig(),
+			imageWithConfig,
+			bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
+			bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
+		); err != nil {
+			var fileAnnotationSet bufanalysis.FileAnnotationSet
+			if !errors.As(err, &fileAnnotationSet) {
+				return nil, err
+			}
+			allFileAnnotations = append(allFileAnnotations, fileAnnotationSet.FileAnnotations()...)
+		}
+	}
+	return allFileAnnotations, nil
+}
+
+func (e *evaluator) breaking(
+	ctx context.Context,
+	controller bufctl.Controller,
+	input string,
+	againstInput string,
+) ([]bufanalysis.FileAnnotation, error) {
+	imageWithConfigs, checkClient, err := controller.GetTargetImageWithConfigsAndCheckClient(
+		ctx,
+		input,
+		e.wasmRuntime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	againstImageWithConfigs, _, err := controller.GetTargetImageWithConfigsAndCheckClient(
+		ctx,
+		againstInput,
+		wasm.UnimplementedRuntime,
+	)
+	if err != nil {
+		var fileAnnotationSet bufanalysis.FileAnnotationSet
+		if errors.As(err, &fileAnnotationSet) {
+			// Pushes that fix a revision that does not build are not blocked.
+			e.container.Logger().WarnContext(
+				ctx,
+				"skipping breaking change detection as the old revision does not build",
+				slogext.ErrorAttr(err),
+			)
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(imageWithConfigs) != len(againstImageWithConfigs) {
+		return nil, fmt.Errorf(
+			"input contained %d images, whereas against contained %d images",
+			len(imageWithConfigs),
+			len(againstImageWithConfigs),
+		)
+	}
+	allCheckConfigs := getAllCheckConfigs(imageWithConfigs)
+	var allFileAnnotations []bufanalysis.FileAnnotation
+	for i, imageWithConfig := range imageWithConfigs {
+		if err := checkClient.Breaking(
+			ctx,
+			imageWithConfig.BreakingConfig(),
+			imageWithConfig,
+			againstImageWithConfigs[i],
+			bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
+			bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
+		); err != nil {
+			var fileAnnotationSet bufanalysis.FileAnnotationSet
+			if !errors.As(err, &fileAnnotationSet) {
+				return nil, err
+			}
+			allFileAnnotations = append(allFileAnnotations, fileAnnotationSet.FileAnnotations()...)
+		}
+	}
+	return allFileAnnotations, nil
+}
+
+// format returns a FileAnnotation for each file that is not formatted.
+func format(
+	ctx context.Context,
+	controller bufctl.Controller,
+	input string,
+) ([]bufanalysis.FileAnnotation, error) {
+	workspace, err := controller.GetWorkspace(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	originalReadBucket := bufmodule.ModuleReadBucketToStorageReadBucket(
+		bufmodule.ModuleReadBucketWithOnlyTargetFiles(
+			bufmodule.ModuleSetToModuleReadBucketWithOnlyProtoFilesForTargetModules(workspace),
+		),
+	)
+	formattedReadBucket, err := bufformat.FormatBucket(ctx, originalReadBucket)
+	if err != nil {
+		return nil, err
+	}
+	changedPaths, err := storage.DiffWithFilenames(
+		ctx,
+		io.Discard,
+		originalReadBucket,
+		formattedReadBucket,
+	)
+	if err != nil {
+		return nil, err
+	}
+	fileAnnotations := make([]bufanalysis.FileAnnotation, len(changedPaths))
+	for i, changedPath := range changedPaths {
+		fileAnnotations[i] = bufanalysis.NewFileAnnotation(
+			newFileInfo(changedPath),
+			0,
+			0,
+			0,
+			0,
+			"FORMAT",
+			"File is not formatted.",
+			"",
+		)
+	}
+	return fileAnnotations, nil
+}
+
+func getAllCheckConfigs(imageWithConfigs []bufctl.ImageWithConfig) []bufconfig.CheckConfig {
+	allCheckConfigs := make([]bufconfig.CheckConfig, 0, len(imageWithConfigs)*2)
+	for _, imageWithConfig := range imageWithConfigs {
+		allCheckConfigs = append(allCheckConfigs, imageWithConfig.LintConfig())
+		allCheckConfigs = append(allCheckConfigs, imageWithConfig.BreakingConfig())
+	}
+	return allCheckConfigs
+}
+
+func gitInput(repositoryPath string, revision string) string {
+	return repositoryPath + "#format=git,ref=" + revision
+}
+
+type fileInfo struct {
+	path string
+}
+
+func newFileInfo(path string) *fileInfo {
+	return &fileInfo{
+		path: path,
+	}
+}
+
+func (f *fileInfo) Path() string {
+	return f.path
+}
+
+func (f *fileInfo) ExternalPath() string {
+	return f.path
+}
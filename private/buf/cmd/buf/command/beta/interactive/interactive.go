@@ -0,0 +1,276 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interactive
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/pkg/app"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/storage"
+)
+
+const (
+	short = "Interactively search for a command, and build and run its command line"
+
+	// maxShownCommandEntries is the maximum number of matching commands shown.
+	maxShownCommandEntries = 10
+	// maxShownSuggestions is the maximum number of suggested inputs shown.
+	maxShownSuggestions = 10
+	// maxRecentInputs is the maximum number of recently used inputs recorded.
+	maxRecentInputs = 10
+	// recentInputsPath is the path of the recently used inputs within the cache bucket.
+	recentInputsPath = "recent_inputs"
+)
+
+// NewCommand returns a new Command.
+//
+// newRootCommand returns the root command, which is searched for commands, and which runs
+// the command line that is built.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+	newRootCommand func(use string) *appcmd.Command,
+) *appcmd.Command {
+	return &appcmd.Command{
+		Use:     name,
+		Aliases: []string{"i"},
+		Short:   short,
+		Long: `Commands are searched by name and description, and do not need to match exactly,
+for example "brk" finds "buf breaking". Once a command is selected, its usage and flags are
+printed, with the recently used inputs and the modules in the module cache as suggestions for
+its arguments. The command line is printed before it is run.
+
+Prompts are printed to stderr and read from stdin, so that the output of the command can be
+redirected.`,
+		Args: appcmd.NoArgs,
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, newRootCommand)
+			},
+		),
+	}
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	newRootCommand func(use string) *appcmd.Command,
+) error {
+	appName := container.AppName()
+	commandEntries := getCommandEntries(newRootCommand(appName))
+	reader := bufio.NewReader(container.Stdin())
+	stderr := container.Stderr()
+	commandEntry, err := selectCommandEntry(reader, stderr, appName, commandEntries)
+	if err != nil {
+		return err
+	}
+	cacheBucket, err := bufcli.NewInteractiveCacheBucket(container)
+	if err != nil {
+		return err
+	}
+	recentInputs, err := readRecentInputs(ctx, cacheBucket)
+	if err != nil {
+		return err
+	}
+	commandLine := appName + " " + strings.Join(commandEntry.path, " ")
+	if _, err := fmt.Fprintf(stderr, "\nUsage: %s %s\n", appName, commandEntry.usage()); err != nil {
+		return err
+	}
+	if flagNames := commandEntry.flagNames(); len(flagNames) > 0 {
+		if _, err := fmt.Fprintf(stderr, "Flags: %s\n", strings.Join(flagNames, ", ")); err != nil {
+			return err
+		}
+	}
+	var suggestions []string
+	if commandEntry.takesArgs() {
+		cachedModuleFullNames, err := bufcli.GetCachedModuleFullNames(container)
+		if err != nil {
+			return err
+		}
+		suggestions = getSuggestions(recentInputs, cachedModuleFullNames)
+		if len(suggestions) > 0 {
+			if _, err := fmt.Fprintln(stderr, "\nRecently used inputs and cached modules:"); err != nil {
+				return err
+			}
+			for i, suggestion := range suggestions {
+				if _, err := fmt.Fprintf(stderr, "  %d. %s\n", i+1, suggestion); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	answer, err := prompt(
+		reader,
+		stderr,
+		fmt.Sprintf("\nArguments and flags for %q, starting with a number to use a suggestion: ", commandLine),
+	)
+	if err != nil {
+		return err
+	}
+	args := getArgs(answer, suggestions)
+	commandLine = strings.Join(append([]string{commandLine}, args...), " ")
+	answer, err = prompt(reader, stderr, fmt.Sprintf("Run %q? [Y/n]: ", commandLine))
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(answer) {
+	case "", "y", "yes":
+	default:
+		_, err := fmt.Fprintln(stderr, "Not running the command.")
+		return err
+	}
+	if commandEntry.takesArgs() && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		if err := writeRecentInputs(ctx, cacheBucket, addRecentInput(recentInputs, args[0])); err != nil {
+			return err
+		}
+	}
+	if err := appcmd.Run(
+		ctx,
+		app.NewContainerForArgs(container, append(append([]string{appName}, commandEntry.path...), args...)...),
+		newRootCommand(appName),
+	); err != nil {
+		// The error was already printed by the command, we only retain the exit code.
+		return app.NewError(app.GetExitCode(err), "")
+	}
+	return nil
+}
+
+// selectCommandEntry prompts for a search until a matching command is selected.
+func selectCommandEntry(
+	reader *bufio.Reader,
+	writer io.Writer,
+	appName string,
+	commandEntries []*commandEntry,
+) (*commandEntry, error) {
+	query, err := prompt(reader, writer, "Search commands: ")
+	if err != nil {
+		return nil, err
+	}
+	for {
+		matchingCommandEntries := searchCommandEntries(commandEntries, query)
+		if len(matchingCommandEntries) > maxShownCommandEntries {
+			matchingCommandEntries = matchingCommandEntries[:maxShownCommandEntries]
+		}
+		if len(matchingCommandEntries) == 0 {
+			query, err = prompt(reader, writer, fmt.Sprintf("No commands match %q. Search commands: ", query))
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for i, commandEntry := range matchingCommandEntries {
+			if _, err := fmt.Fprintf(
+				writer,
+				"  %d. %s %s - %s\n",
+				i+1,
+				appName,
+				strings.Join(commandEntry.path, " "),
+				commandEntry.short,
+			); err != nil {
+				return nil, err
+			}
+		}
+		answer, err := prompt(reader, writer, "Select a command by number, or search again: ")
+		if err != nil {
+			return nil, err
+		}
+		if index, err := strconv.Atoi(answer); err == nil && index >= 1 && index <= len(matchingCommandEntries) {
+			return matchingCommandEntries[index-1], nil
+		}
+		query = answer
+	}
+}
+
+// prompt prints the message and reads a line, with surrounding whitespace removed.
+func prompt(reader *bufio.Reader, writer io.Writer, message string) (string, error) {
+	if _, err := fmt.Fprint(writer, message); err != nil {
+		return "", err
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		if line == "" {
+			return "", errors.New("no input, stdin was closed")
+		}
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// getSuggestions returns the suggested inputs, with the recent inputs first.
+func getSuggestions(recentInputs []string, cachedModuleFullNames []string) []string {
+	suggestions := append([]string{}, recentInputs...)
+	for _, cachedModuleFullName := range cachedModuleFullNames {
+		if !slices.Contains(suggestions, cachedModuleFullName) {
+			suggestions = append(suggestions, cachedModuleFullName)
+		}
+	}
+	if len(suggestions) > maxShownSuggestions {
+		suggestions = suggestions[:maxShownSuggestions]
+	}
+	return suggestions
+}
+
+// getArgs splits the answer into arguments, replacing a leading number with the
+// corresponding suggestion.
+//
+// Arguments are separated by whitespace, quoting is not supported.
+func getArgs(answer string, suggestions []string) []string {
+	args := strings.Fields(answer)
+	if len(args) > 0 {
+		if index, err := strconv.Atoi(args[0]); err == nil && index >= 1 && index <= len(suggestions) {
+			args[0] = suggestions[index-1]
+		}
+	}
+	return args
+}
+
+func readRecentInputs(ctx context.Context, bucket storage.ReadBucket) ([]string, error) {
+	data, err := storage.ReadPath(ctx, bucket, recentInputsPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+func writeRecentInputs(ctx context.Context, bucket storage.WriteBucket, recentInputs []string) error {
+	return storage.PutPath(ctx, bucket, recentInputsPath, []byte(strings.Join(recentInputs, "\n")+"\n"))
+}
+
+// addRecentInput returns the recent inputs with the input first.
+func addRecentInput(recentInputs []string, input string) []string {
+	newRecentInputs := []string{input}
+	for _, recentInput := range recentInputs {
+		if recentInput != input && len(newRecentInputs) < maxRecentInputs {
+			newRecentInputs = append(newRecentInputs, recentInput)
+		}
+	}
+	return newRecentInputs
+}
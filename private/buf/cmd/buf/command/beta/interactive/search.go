@@ -0,0 +1,169 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interactive
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/spf13/pflag"
+)
+
+type commandEntry struct {
+	// path is the path of the command from the root command, for example ["mod", "prune"].
+	path  []string
+	use   string
+	short string
+	// bindFlags may be nil.
+	bindFlags func(*pflag.FlagSet)
+}
+
+// usage returns the usage of the command, for example "lint <input>".
+func (c *commandEntry) usage() string {
+	usage := strings.Join(c.path, " ")
+	if _, args, ok := strings.Cut(c.use, " "); ok {
+		usage += " " + args
+	}
+	return usage
+}
+
+// takesArgs returns true if the usage of the command includes an argument.
+func (c *commandEntry) takesArgs() bool {
+	return strings.Contains(c.use, "<")
+}
+
+// flagNames returns the sorted names of the flags of the command, for example
+// ["--config", "--error-format"].
+func (c *commandEntry) flagNames() []string {
+	if c.bindFlags == nil {
+		return nil
+	}
+	flagSet := pflag.NewFlagSet(strings.Join(c.path, " "), pflag.ContinueOnError)
+	c.bindFlags(flagSet)
+	var flagNames []string
+	flagSet.VisitAll(func(flag *pflag.Flag) {
+		if !flag.Hidden && flag.Deprecated == "" {
+			flagNames = append(flagNames, "--"+flag.Name)
+		}
+	})
+	slices.Sort(flagNames)
+	return flagNames
+}
+
+// getCommandEntries returns the runnable commands below the root command, excluding
+// hidden, deprecated, and interactive commands.
+func getCommandEntries(rootCommand *appcmd.Command) []*commandEntry {
+	var commandEntries []*commandEntry
+	var walk func(command *appcmd.Command, parentPath []string)
+	walk = func(command *appcmd.Command, parentPath []string) {
+		for _, subCommand := range command.SubCommands {
+			if subCommand.Hidden || subCommand.Deprecated != "" || subCommand.Short == short {
+				continue
+			}
+			name, _, _ := strings.Cut(subCommand.Use, " ")
+			path := append(slices.Clone(parentPath), name)
+			if subCommand.Run != nil {
+				commandEntries = append(
+					commandEntries,
+					&commandEntry{
+						path:      path,
+						use:       subCommand.Use,
+						short:     subCommand.Short,
+						bindFlags: subCommand.BindFlags,
+					},
+				)
+			}
+			walk(subCommand, path)
+		}
+	}
+	walk(rootCommand, nil)
+	return commandEntries
+}
+
+// searchCommandEntries returns the command entries matching the query, best match first.
+func searchCommandEntries(commandEntries []*commandEntry, query string) []*commandEntry {
+	type scoredCommandEntry struct {
+		commandEntry *commandEntry
+		score        int
+	}
+	var scoredCommandEntries []scoredCommandEntry
+	for _, commandEntry := range commandEntries {
+		path := strings.Join(commandEntry.path, " ")
+		score, ok := fuzzyScore(query, path)
+		if ok && strings.EqualFold(query, path) {
+			score += 1000
+		}
+		if shortScore, shortOK := fuzzyScore(query, commandEntry.short); shortOK && (!ok || shortScore/2 > score) {
+			// Matches of the description are less relevant than matches of the path.
+			score, ok = shortScore/2, true
+		}
+		if ok {
+			scoredCommandEntries = append(scoredCommandEntries, scoredCommandEntry{commandEntry: commandEntry, score: score})
+		}
+	}
+	slices.SortStableFunc(
+		scoredCommandEntries,
+		func(one scoredCommandEntry, two scoredCommandEntry) int {
+			if one.score != two.score {
+				return two.score - one.score
+			}
+			if len(one.commandEntry.path) != len(two.commandEntry.path) {
+				return len(one.commandEntry.path) - len(two.commandEntry.path)
+			}
+			return strings.Compare(
+				strings.Join(one.commandEntry.path, " "),
+				strings.Join(two.commandEntry.path, " "),
+			)
+		},
+	)
+	matchingCommandEntries := make([]*commandEntry, len(scoredCommandEntries))
+	for i, scoredCommandEntry := range scoredCommandEntries {
+		matchingCommandEntries[i] = scoredCommandEntry.commandEntry
+	}
+	return matchingCommandEntries
+}
+
+// fuzzyScore returns the score of the text for the query, and false if the runes of the
+// query are not a subsequence of the text, ignoring case and whitespace in the query.
+//
+// Consecutive matching runes and matches at the start of words score higher.
+func fuzzyScore(query string, text string) (int, bool) {
+	queryRunes := []rune(strings.ToLower(strings.Join(strings.Fields(query), "")))
+	textRunes := []rune(strings.ToLower(text))
+	score := 0
+	queryIndex := 0
+	previousMatched := false
+	for i, textRune := range textRunes {
+		if queryIndex == len(queryRunes) {
+			break
+		}
+		if textRune != queryRunes[queryIndex] {
+			previousMatched = false
+			continue
+		}
+		score++
+		if previousMatched {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(textRunes[i-1]) && !unicode.IsDigit(textRunes[i-1]) {
+			score += 3
+		}
+		previousMatched = true
+		queryIndex++
+	}
+	return score, queryIndex == len(queryRunes)
+}
@@ -0,0 +1,326 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/bufbuild/buf/private/pkg/wasm"
+	"github.com/spf13/pflag"
+)
+
+const (
+	errorFormatFlagName     = "error-format"
+	configFlagName          = "config"
+	pathsFlagName           = "path"
+	excludePathsFlagName    = "exclude-path"
+	disableSymlinksFlagName = "disable-symlinks"
+	changedSinceFlagName    = "changed-since"
+	sampleFlagName          = "sample"
+	sampleSeedFlagName      = "sample-seed"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <input>",
+		Short: "Run linting on the Protobuf files changed since a git ref",
+		Long: `Run linting on the Protobuf files changed since a git ref, for incremental adoption of lint
+rules in large repositories.
+
+The input must be a directory or proto file in a git repository. The files that changed since
+the merge base of --changed-since and HEAD are linted, including uncommitted and untracked
+files, that is the files that a pull request against --changed-since would change:
+
+    $ buf beta lint --changed-since origin/main
+
+Violations on lines that changed are new violations. They are printed to stdout, and fail
+the command. Violations on lines that did not change are legacy violations. They are printed
+to stderr, and do not fail the command. All lines of files that were added or renamed are
+considered changed.
+
+With --sample, a random sample of the files that did not change is also linted, and all
+their violations are legacy violations. This gives an estimate of the legacy violations in the
+repository without linting every file. Set --sample-seed to lint the same sample on every run.
+
+Only the changed and sampled files are built and linted. This differs from
+"buf lint --only-changed-lines" and "buf lint --only-changed-files", which lint every file of
+the input and then suppress the violations outside of the changes. This command is meant for
+large repositories where linting every file on every change is too slow, and prints the legacy
+violations instead of suppressing them, so that they can be tracked while they are fixed.
+
+` + bufcli.GetInputLong(`the source to lint`),
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	ErrorFormat     string
+	Config          string
+	Paths           []string
+	ExcludePaths    []string
+	DisableSymlinks bool
+	ChangedSince    string
+	Sample          int
+	SampleSeed      uint64
+	// special
+	InputHashtag string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
+	bufcli.BindPaths(flagSet, &f.Paths, pathsFlagName)
+	bufcli.BindExcludePaths(flagSet, &f.ExcludePaths, excludePathsFlagName)
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	flagSet.StringVar(
+		&f.ErrorFormat,
+		errorFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			"The format for build errors or check violations printed to stdout and stderr. Must be one of %s",
+			stringutil.SliceToString(bufanalysis.AllFormatStrings),
+		),
+	)
+	flagSet.StringVar(
+		&f.Config,
+		configFlagName,
+		"",
+		`The buf.yaml file or data to use for configuration`,
+	)
+	flagSet.StringVar(
+		&f.ChangedSince,
+		changedSinceFlagName,
+		"",
+		`The git ref to lint the changes since, such as origin/main`,
+	)
+	_ = appcmd.MarkFlagRequired(flagSet, changedSinceFlagName)
+	flagSet.IntVar(
+		&f.Sample,
+		sampleFlagName,
+		0,
+		`The number of files that did not change to also lint, chosen at random`,
+	)
+	flagSet.Uint64Var(
+		&f.SampleSeed,
+		sampleSeedFlagName,
+		0,
+		fmt.Sprintf(
+			`The seed for choosing the files for --%s. If not set, a different sample is chosen on every run`,
+			sampleFlagName,
+		),
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) (retErr error) {
+	if _, err := bufanalysis.ParseFormat(flags.ErrorFormat); err != nil {
+		return appcmd.WrapInvalidArgumentError(err)
+	}
+	if flags.Sample < 0 {
+		return appcmd.NewInvalidArgumentErrorf("--%s must not be negative", sampleFlagName)
+	}
+	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	if err != nil {
+		return err
+	}
+	changedLines, err := bufcli.NewChangedLinesForInput(ctx, container, input, flags.ChangedSince, changedSinceFlagName)
+	if err != nil {
+		return err
+	}
+	controller, err := bufcli.NewController(
+		container,
+		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
+		bufctl.WithFileAnnotationsToStdout(),
+	)
+	if err != nil {
+		return err
+	}
+	// The files of the input are listed without building them, so that only the changed
+	// and sampled files are built and linted.
+	imageFileInfos, err := controller.GetImportableImageFileInfos(
+		ctx,
+		input,
+		bufctl.WithTargetPaths(flags.Paths, flags.ExcludePaths),
+		bufctl.WithConfigOverride(flags.Config),
+	)
+	if err != nil {
+		return err
+	}
+	var numTargetFiles int
+	var changedFilePaths []string
+	// The paths of the files that did not change, to sample from.
+	var unchangedFilePaths []string
+	for _, imageFileInfo := range imageFileInfos {
+		if imageFileInfo.IsImport() {
+			continue
+		}
+		numTargetFiles++
+		if changedLines.ContainsFile(imageFileInfo.ExternalPath()) {
+			changedFilePaths = append(changedFilePaths, imageFileInfo.ExternalPath())
+		} else {
+			unchangedFilePaths = append(unchangedFilePaths, imageFileInfo.ExternalPath())
+		}
+	}
+	targetPaths := append(changedFilePaths, getSampledFilePaths(unchangedFilePaths, flags.Sample, flags.SampleSeed)...)
+	var newFileAnnotations []bufanalysis.FileAnnotation
+	var legacyFileAnnotations []bufanalysis.FileAnnotation
+	if len(targetPaths) > 0 {
+		if len(targetPaths) == numTargetFiles {
+			// All files are linted, so the paths are left as is. This is always the case for
+			// .proto file inputs, which do not accept target paths.
+			targetPaths = flags.Paths
+		}
+		fileAnnotations, err := lint(ctx, container, controller, input, targetPaths, flags)
+		if err != nil {
+			return err
+		}
+		for _, fileAnnotation := range fileAnnotations {
+			// Violations on the changed lines are new violations. All other violations
+			// are in the sampled files, or on lines of changed files that did not change.
+			if changedLines.ContainsFileAnnotation(fileAnnotation) {
+				newFileAnnotations = append(newFileAnnotations, fileAnnotation)
+			} else {
+				legacyFileAnnotations = append(legacyFileAnnotations, fileAnnotation)
+			}
+		}
+	}
+	if len(legacyFileAnnotations) > 0 {
+		if err := bufanalysis.PrintFileAnnotationSet(
+			container.Stderr(),
+			bufanalysis.NewFileAnnotationSet(legacyFileAnnotations...),
+			flags.ErrorFormat,
+		); err != nil {
+			return err
+		}
+	}
+	if len(newFileAnnotations) > 0 || flags.ErrorFormat == "sarif" {
+		if err := bufanalysis.PrintFileAnnotationSet(
+			container.Stdout(),
+			bufanalysis.NewFileAnnotationSet(newFileAnnotations...),
+			flags.ErrorFormat,
+		); err != nil {
+			return err
+		}
+	}
+	// Warnings are printed, but do not fail the command.
+	if len(newFileAnnotations) > bufanalysis.CountFileAnnotationsWithSeverity(newFileAnnotations, bufanalysis.SeverityWarning) {
+		return bufctl.ErrFileAnnotation
+	}
+	return nil
+}
+
+// lint builds and lints the target paths of the input, and returns the violations.
+func lint(
+	ctx context.Context,
+	container appext.Container,
+	controller bufctl.Controller,
+	input string,
+	targetPaths []string,
+	flags *flags,
+) (_ []bufanalysis.FileAnnotation, retErr error) {
+	wasmRuntimeCacheDir, err := bufcli.CreateWasmRuntimeCacheDir(container)
+	if err != nil {
+		return nil, err
+	}
+	wasmRuntime, err := wasm.NewRuntime(ctx, wasm.WithLocalCacheDir(wasmRuntimeCacheDir))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, wasmRuntime.Close(ctx))
+	}()
+	imageWithConfigs, checkClient, err := controller.GetTargetImageWithConfigsAndCheckClient(
+		ctx,
+		input,
+		wasmRuntime,
+		bufctl.WithTargetPaths(targetPaths, flags.ExcludePaths),
+		bufctl.WithConfigOverride(flags.Config),
+	)
+	if err != nil {
+		return nil, err
+	}
+	// We add all check configs (both lint and breaking) as related configs to check if plugins
+	// have rules configured.
+	// We allocated twice the size of imageWithConfigs for both lint and breaking configs.
+	allCheckConfigs := make([]bufconfig.CheckConfig, 0, len(imageWithConfigs)*2)
+	for _, imageWithConfig := range imageWithConfigs {
+		allCheckConfigs = append(allCheckConfigs, imageWithConfig.LintConfig())
+		allCheckConfigs = append(allCheckConfigs, imageWithConfig.BreakingConfig())
+	}
+	var fileAnnotations []bufanalysis.FileAnnotation
+	for _, imageWithConfig := range imageWithConfigs {
+		if err := checkClient.Lint(
+			ctx,
+			imageWithConfig.LintConfig(),
+			imageWithConfig,
+			bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
+			bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
+		); err != nil {
+			var fileAnnotationSet bufanalysis.FileAnnotationSet
+			if !errors.As(err, &fileAnnotationSet) {
+				return nil, err
+			}
+			fileAnnotations = append(fileAnnotations, fileAnnotationSet.FileAnnotations()...)
+		}
+	}
+	return fileAnnotations, nil
+}
+
+// getSampledFilePaths returns a random sample of up to size of the paths.
+//
+// If seed is 0, a random seed is used.
+func getSampledFilePaths(paths []string, size int, seed uint64) []string {
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	paths = slices.Clone(paths)
+	// Sorted so that the sample only depends on the seed.
+	slices.Sort(paths)
+	random := rand.New(rand.NewPCG(seed, seed))
+	random.Shuffle(len(paths), func(i int, j int) {
+		paths[i], paths[j] = paths[j], paths[i]
+	})
+	return paths[:min(size, len(paths))]
+}
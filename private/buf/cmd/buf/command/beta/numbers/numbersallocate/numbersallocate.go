@@ -0,0 +1,190 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package numbersallocate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufnumber"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/spf13/pflag"
+)
+
+const (
+	messageFlagName         = "message"
+	fieldFlagName           = "field"
+	ledgerFlagName          = "ledger"
+	errorFormatFlagName     = "error-format"
+	disableSymlinksFlagName = "disable-symlinks"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <input>",
+		Short: "Allocate the next safe field number of a message",
+		Long: `The next safe field number of the message set with --message is allocated to the field set
+with --field, recorded in the number ledger, and printed.
+
+The next safe field number is the number after the largest number that is used by a field of the
+message, reserved by the message, or allocated in the ledger, skipping the extension ranges of the
+message and the numbers 19000 to 19999 that are reserved for the Protobuf implementation. Lower
+unused numbers are never allocated, as they may have been used by deleted fields that were not
+reserved.
+
+Check in the ledger with the change that adds the field. Changes that allocate numbers for the
+same message concurrently then conflict in the ledger, instead of using the same number:
+
+    $ buf beta numbers allocate --message acme.pet.v1.Pet --field owner_name
+    12
+
+` + bufcli.GetInputLong(`the source, module, or Image that contains the message`),
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	Message         string
+	Field           string
+	Ledger          string
+	ErrorFormat     string
+	DisableSymlinks bool
+	// special
+	InputHashtag string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	flagSet.StringVar(
+		&f.Message,
+		messageFlagName,
+		"",
+		`The fully-qualified name of the message to allocate a field number of, such as "acme.pet.v1.Pet"`,
+	)
+	flagSet.StringVar(
+		&f.Field,
+		fieldFlagName,
+		"",
+		`The name of the field to allocate the field number to`,
+	)
+	flagSet.StringVar(
+		&f.Ledger,
+		ledgerFlagName,
+		bufnumber.DefaultFileName,
+		`The number ledger file to update. The file is created if it does not exist`,
+	)
+	flagSet.StringVar(
+		&f.ErrorFormat,
+		errorFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			"The format for build errors printed to stderr. Must be one of %s",
+			stringutil.SliceToString(bufanalysis.AllFormatStrings),
+		),
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	if err := bufcli.ValidateRequiredFlag(messageFlagName, flags.Message); err != nil {
+		return err
+	}
+	if err := bufcli.ValidateRequiredFlag(fieldFlagName, flags.Field); err != nil {
+		return err
+	}
+	if err := bufcli.ValidateRequiredFlag(ledgerFlagName, flags.Ledger); err != nil {
+		return err
+	}
+	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	if err != nil {
+		return err
+	}
+	controller, err := bufcli.NewController(
+		container,
+		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
+	)
+	if err != nil {
+		return err
+	}
+	image, err := controller.GetImage(
+		ctx,
+		input,
+		bufctl.WithImageExcludeSourceInfo(true),
+	)
+	if err != nil {
+		return err
+	}
+	ledger, err := readLedgerIfExists(flags.Ledger)
+	if err != nil {
+		return err
+	}
+	if ledger == nil {
+		ledger = bufnumber.NewLedger()
+	}
+	ledger, number, err := bufnumber.Allocate(ledger, image, flags.Message, flags.Field)
+	if err != nil {
+		return err
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := bufnumber.WriteLedger(buffer, ledger); err != nil {
+		return err
+	}
+	if err := os.WriteFile(flags.Ledger, buffer.Bytes(), 0644); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(container.Stdout(), number)
+	return err
+}
+
+func readLedgerIfExists(path string) (bufnumber.Ledger, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+	return bufnumber.ReadLedger(file)
+}
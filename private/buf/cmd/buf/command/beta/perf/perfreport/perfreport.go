@@ -0,0 +1,126 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perfreport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufperf"
+	"github.com/bufbuild/buf/private/buf/bufprint"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/syserror"
+	"github.com/spf13/pflag"
+)
+
+const (
+	runsFlagName   = "runs"
+	formatFlagName = "format"
+
+	defaultRuns = 100
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name,
+		Short: "Summarize the durations and cache hit rates of recent commands",
+		Long: fmt.Sprintf(
+			`The durations, exit codes, and cache statistics of commands are only recorded if the %s
+environment variable is set, for example to 1. They are written to the cache directory, and
+never leave the machine. At most %d runs are retained.
+
+The summary includes the number of runs and failures, and the total, median, 90th percentile,
+and maximum durations per command, as well as the lookups and hits per cache.`,
+			bufcli.PerfStatsEnvKey(),
+			bufperf.MaxRuns,
+		),
+		Args: appcmd.NoArgs,
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	Runs   int
+	Format string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	flagSet.IntVar(
+		&f.Runs,
+		runsFlagName,
+		defaultRuns,
+		"The number of most recent runs to summarize",
+	)
+	flagSet.StringVar(
+		&f.Format,
+		formatFlagName,
+		bufprint.FormatText.String(),
+		fmt.Sprintf(`The output format to use. Must be one of %s`, bufprint.AllFormatsString),
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	if flags.Runs <= 0 {
+		return appcmd.NewInvalidArgumentErrorf("--%s must be positive", runsFlagName)
+	}
+	format, err := bufprint.ParseFormat(flags.Format)
+	if err != nil {
+		return appcmd.WrapInvalidArgumentError(err)
+	}
+	bucket, err := bufcli.NewPerfCacheBucket(container)
+	if err != nil {
+		return err
+	}
+	runs, err := bufperf.ReadRuns(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if len(runs) > flags.Runs {
+		runs = runs[len(runs)-flags.Runs:]
+	}
+	if len(runs) == 0 && !bufcli.IsPerfStatsEnabled(container) {
+		container.Logger().Warn(
+			fmt.Sprintf("No runs were recorded. Set %s=1 to record the statistics of commands.", bufcli.PerfStatsEnvKey()),
+		)
+	}
+	switch format {
+	case bufprint.FormatText:
+		return bufperf.PrintReport(container.Stdout(), runs)
+	case bufprint.FormatJSON:
+		return bufperf.PrintReportJSON(container.Stdout(), runs)
+	default:
+		return syserror.Newf("unknown format: %v", format)
+	}
+}
@@ -0,0 +1,363 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reduce
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufreduce"
+	"github.com/bufbuild/buf/private/pkg/app"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/spf13/pflag"
+)
+
+const (
+	outputFlagName      = "output"
+	outputFlagShortName = "o"
+	matchFlagName       = "match"
+	timeoutFlagName     = "timeout"
+
+	// inputPlaceholder is replaced with the path of the copy of the input in the arguments
+	// of the command that is run.
+	inputPlaceholder = "{}"
+)
+
+var (
+	// locationPrefixRegexp matches the location prefix of a file annotation or compiler
+	// error, for example "foo/v1/foo.proto:3:1:".
+	locationPrefixRegexp = regexp.MustCompile(`^[^\s:]+:\d+:\d+:\s*`)
+)
+
+// NewCommand returns a new Command.
+//
+// newRootCommand returns the root command, which runs the command that is reduced.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+	newRootCommand func(use string) *appcmd.Command,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <input> -- <command>",
+		Short: "Reduce an input to a minimal set of files and lines that still fails a command",
+		Long: `The first argument is a local directory that contains the input, and the remaining arguments
+are the buf command that fails on it, without the leading "buf". Each occurrence of "` + inputPlaceholder + `"
+in the command is replaced with the path of a copy of the input, otherwise the path is appended to
+the command. For example:
+
+    $ buf beta reduce proto --output reduced -- build ` + inputPlaceholder + ` --error-format json
+
+The command is first run on a copy of the input to record how it fails: its exit code, the value of
+its panic if it panics, and the first line of its output, without the file location. The .proto
+files of the input are then removed in decreasing chunks, and the lines of the remaining .proto
+files are removed in decreasing chunks, for as long as the command still fails in the same way.
+All other files, such as buf.yaml, are kept as-is. Use --match to instead require that the output
+of the command matches a regular expression.
+
+The command is run in-process on copies of the input in temporary directories, so that the input
+itself is never modified. The reduced input is written to the directory given by --output, which
+must not exist.`,
+		Args: appcmd.MinimumNArgs(2),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags, newRootCommand)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	Output  string
+	Match   string
+	Timeout time.Duration
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	flagSet.StringVarP(
+		&f.Output,
+		outputFlagName,
+		outputFlagShortName,
+		"",
+		`The directory to write the reduced input to. Must not exist`,
+	)
+	_ = appcmd.MarkFlagRequired(flagSet, outputFlagName)
+	flagSet.StringVar(
+		&f.Match,
+		matchFlagName,
+		"",
+		`A regular expression that the combined stdout and stderr of the command must match for a reduced input to be kept.
+By default, the first line of the output of the command on the original input must be preserved`,
+	)
+	flagSet.DurationVar(
+		&f.Timeout,
+		timeoutFlagName,
+		10*time.Minute,
+		`The maximum duration of the reduction. Once reached, the input reduced so far is written`,
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+	newRootCommand func(use string) *appcmd.Command,
+) error {
+	bufcli.WarnBetaCommand(ctx, container)
+	inputDirPath := container.Arg(0)
+	commandArgs := app.Args(container)[1:]
+	var matchRegexp *regexp.Regexp
+	if flags.Match != "" {
+		var err error
+		matchRegexp, err = regexp.Compile(flags.Match)
+		if err != nil {
+			return appcmd.NewInvalidArgumentErrorf("--%s: %v", matchFlagName, err)
+		}
+	}
+	if _, err := os.Stat(flags.Output); err == nil {
+		return appcmd.NewInvalidArgumentErrorf("--%s: %q already exists", outputFlagName, flags.Output)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if fileInfo, err := os.Stat(inputDirPath); err != nil {
+		return err
+	} else if !fileInfo.IsDir() {
+		return appcmd.NewInvalidArgumentErrorf("input %q is not a directory", inputDirPath)
+	}
+	pathToData, err := bufreduce.ReadInputDir(inputDirPath)
+	if err != nil {
+		return err
+	}
+	runner := &runner{
+		container:      container,
+		newRootCommand: newRootCommand,
+		inputDirPath:   inputDirPath,
+		commandArgs:    commandArgs,
+	}
+	original, err := runner.run(ctx, pathToData)
+	if err != nil {
+		return err
+	}
+	if original.exitCode == 0 && original.panicString == "" {
+		return fmt.Errorf("%s %s did not fail on a copy of the input", container.AppName(), strings.Join(commandArgs, " "))
+	}
+	container.Logger().Debug(
+		"recorded failure",
+		"exit_code", original.exitCode,
+		"panic", original.panicString,
+		"message", original.message(),
+	)
+	fails := func(ctx context.Context, pathToData map[string][]byte) bool {
+		result, err := runner.run(ctx, pathToData)
+		if err != nil {
+			return false
+		}
+		return result.matches(original, matchRegexp)
+	}
+	if matchRegexp != nil && !fails(ctx, pathToData) {
+		return fmt.Errorf("the output of %s %s on a copy of the input does not match --%s", container.AppName(), strings.Join(commandArgs, " "), matchFlagName)
+	}
+	reduceCtx, cancel := context.WithTimeout(ctx, flags.Timeout)
+	defer cancel()
+	reducedPathToData := bufreduce.ReduceProtoFiles(reduceCtx, pathToData, fails)
+	reducedPathToData = bufreduce.ReduceProtoFileLines(reduceCtx, reducedPathToData, fails)
+	timedOut := reduceCtx.Err() != nil && ctx.Err() == nil
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := bufreduce.WriteInputDir(flags.Output, reducedPathToData); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(
+		container.Stdout(),
+		"Reduced from %d to %d .proto files and from %d to %d lines in %d runs.\n",
+		numProtoFiles(pathToData),
+		numProtoFiles(reducedPathToData),
+		bufreduce.NumLines(pathToData),
+		bufreduce.NumLines(reducedPathToData),
+		runner.numRuns,
+	); err != nil {
+		return err
+	}
+	if timedOut {
+		if _, err := fmt.Fprintf(container.Stdout(), "The reduction timed out after %v, the reduced input may not be minimal.\n", flags.Timeout); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(container.Stdout(), "Wrote the reduced input to %s.\n", flags.Output)
+	return err
+}
+
+// runner runs the command on copies of the input.
+type runner struct {
+	container      appext.Container
+	newRootCommand func(use string) *appcmd.Command
+	inputDirPath   string
+	commandArgs    []string
+
+	numRuns int
+}
+
+// run runs the command on a copy of the input in a temporary directory.
+//
+// The returned error is only non-nil if the copy could not be written, errors of the
+// command are part of the result.
+func (r *runner) run(ctx context.Context, pathToData map[string][]byte) (*result, error) {
+	r.numRuns++
+	tempDirPath, err := os.MkdirTemp("", "buf-reduce-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDirPath)
+	}()
+	if err := bufreduce.WriteInputDir(tempDirPath, pathToData); err != nil {
+		return nil, err
+	}
+	// The context is canceled once the command returns, which releases its interrupt handling.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	output := bytes.NewBuffer(nil)
+	result := &result{}
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				result.panicString = fmt.Sprint(recovered)
+			}
+		}()
+		result.exitCode = app.GetExitCode(
+			appcmd.Run(
+				ctx,
+				&runContainer{
+					Container: app.NewContainerForArgs(
+						r.container,
+						append([]string{r.container.AppName()}, getArgs(r.commandArgs, tempDirPath)...)...,
+					),
+					output: output,
+				},
+				r.newRootCommand(r.container.AppName()),
+			),
+		)
+	}()
+	result.panicString = strings.ReplaceAll(result.panicString, tempDirPath, r.inputDirPath)
+	result.output = strings.ReplaceAll(output.String(), tempDirPath, r.inputDirPath)
+	return result, nil
+}
+
+// result is the result of running the command.
+type result struct {
+	exitCode    int
+	panicString string
+	output      string
+}
+
+// message returns the first non-empty line of the output, without its location prefix.
+func (r *result) message() string {
+	for _, line := range strings.Split(r.output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return locationPrefixRegexp.ReplaceAllString(line, "")
+		}
+	}
+	return ""
+}
+
+// matches returns true if the result fails in the same way as the original result.
+//
+// If matchRegexp is non-nil, the output must match it instead of containing the message
+// of the original result.
+func (r *result) matches(original *result, matchRegexp *regexp.Regexp) bool {
+	if r.exitCode != original.exitCode || r.panicString != original.panicString {
+		return false
+	}
+	if matchRegexp != nil {
+		return matchRegexp.MatchString(r.output)
+	}
+	return strings.Contains(r.output, original.message())
+}
+
+// runContainer is a Container for running the command, with no stdin, and with stdout and
+// stderr captured.
+//
+// Crash bundles and performance statistics are disabled for the command.
+type runContainer struct {
+	app.Container
+
+	output io.Writer
+}
+
+func (c *runContainer) Env(key string) string {
+	switch key {
+	case bufcli.CrashDirEnvKey(), bufcli.PerfStatsEnvKey():
+		return ""
+	default:
+		return c.Container.Env(key)
+	}
+}
+
+func (c *runContainer) Stdin() io.Reader {
+	return strings.NewReader("")
+}
+
+func (c *runContainer) Stdout() io.Writer {
+	return c.output
+}
+
+func (c *runContainer) Stderr() io.Writer {
+	return c.output
+}
+
+// getArgs returns the arguments of the command with the input placeholder replaced with the
+// directory path, or with the directory path appended if there is no placeholder.
+func getArgs(commandArgs []string, dirPath string) []string {
+	args := make([]string, len(commandArgs))
+	var replaced bool
+	for i, arg := range commandArgs {
+		if strings.Contains(arg, inputPlaceholder) {
+			arg = strings.ReplaceAll(arg, inputPlaceholder, dirPath)
+			replaced = true
+		}
+		args[i] = arg
+	}
+	if !replaced {
+		args = append(args, dirPath)
+	}
+	return args
+}
+
+func numProtoFiles(pathToData map[string][]byte) int {
+	var numProtoFiles int
+	for path := range pathToData {
+		if normalpath.Ext(path) == ".proto" {
+			numProtoFiles++
+		}
+	}
+	return numProtoFiles
+}
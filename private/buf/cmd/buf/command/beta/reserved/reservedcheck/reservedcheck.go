@@ -0,0 +1,224 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reservedcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufreserved"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/bufbuild/buf/private/pkg/wasm"
+	"github.com/spf13/pflag"
+)
+
+const (
+	againstFlagName         = "against"
+	gitHistoryFlagName      = "git-history"
+	errorFormatFlagName     = "error-format"
+	disableSymlinksFlagName = "disable-symlinks"
+
+	reservedRegistryNoReuseRuleID = "RESERVED_REGISTRY_NO_REUSE"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <input>",
+		Short: "Check that no previously used field or enum value numbers and names are reused",
+		Long: `Every field number and name of every message, and every enum value number and name of every enum,
+that was previously used is collected, and the input is checked to not reuse any of them for a different
+field or enum value. This catches a field that was deleted without reserving its number or name, and later
+reused, even if the deletion happened many releases ago, which comparing two versions with buf breaking misses.
+
+The previously used numbers and names are collected from the inputs given with --against, such as the images
+of prior releases, and with --git-history, from every commit of the git repository containing the input:
+
+    $ buf beta reserved check --git-history
+    $ buf beta reserved check --against v1.binpb --against v2.binpb
+
+With --git-history, the input must be a directory or proto file, and the .proto files within its directory are
+read from every commit reachable from HEAD that changed them. Each file is parsed on its own, so commits that
+do not build are still included.
+
+Reuse is reported as violations of the RESERVED_REGISTRY_NO_REUSE lint rule, regardless of the lint
+configuration of the input. To check against a reserved registry maintained with buf beta reserved sync
+instead, enable this rule for buf lint.
+
+` + bufcli.GetInputLong(`the source, module, or Image to check`),
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	Against         []string
+	GitHistory      bool
+	ErrorFormat     string
+	DisableSymlinks bool
+	// special
+	InputHashtag string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	flagSet.StringArrayVar(
+		&f.Against,
+		againstFlagName,
+		nil,
+		`A prior source, module, or Image whose field and enum value numbers and names must not be reused
+May be provided multiple times`,
+	)
+	flagSet.BoolVar(
+		&f.GitHistory,
+		gitHistoryFlagName,
+		false,
+		`Collect the field and enum value numbers and names used in every commit of the git repository containing the input`,
+	)
+	flagSet.StringVar(
+		&f.ErrorFormat,
+		errorFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			"The format for build errors or check violations printed to stdout. Must be one of %s",
+			stringutil.SliceToString(bufanalysis.AllFormatStrings),
+		),
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	if len(flags.Against) == 0 && !flags.GitHistory {
+		return appcmd.NewInvalidArgumentErrorf("at least one of --%s or --%s is required", againstFlagName, gitHistoryFlagName)
+	}
+	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	if err != nil {
+		return err
+	}
+	controller, err := bufcli.NewController(
+		container,
+		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
+		bufctl.WithFileAnnotationsToStdout(),
+	)
+	if err != nil {
+		return err
+	}
+	var registries []bufreserved.Registry
+	for _, againstInput := range flags.Against {
+		againstImage, err := controller.GetImage(
+			ctx,
+			againstInput,
+			bufctl.WithImageExcludeSourceInfo(true),
+		)
+		if err != nil {
+			return err
+		}
+		registries = append(registries, bufreserved.NewRegistryForImage(againstImage))
+	}
+	if flags.GitHistory {
+		registry, err := bufcli.NewReservedRegistryForGitHistory(ctx, container, input, gitHistoryFlagName)
+		if err != nil {
+			return err
+		}
+		registries = append(registries, registry)
+	}
+	// Only the builtin RESERVED_REGISTRY_NO_REUSE rule is run, so no plugins are needed.
+	imageWithConfigs, checkClient, err := controller.GetTargetImageWithConfigsAndCheckClient(
+		ctx,
+		input,
+		wasm.UnimplementedRuntime,
+	)
+	if err != nil {
+		return err
+	}
+	lintConfig := bufconfig.NewLintConfig(
+		bufconfig.NewEnabledCheckConfigForUseIDsAndCategories(
+			bufconfig.FileVersionV2,
+			[]string{reservedRegistryNoReuseRuleID},
+			false,
+		),
+		"",
+		false,
+		false,
+		false,
+		"",
+		true, // Reuse can be ignored with comment ignores, as with buf lint.
+		false,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	previousRegistry := bufreserved.MergeRegistries(registries...)
+	var allFileAnnotations []bufanalysis.FileAnnotation
+	for _, imageWithConfig := range imageWithConfigs {
+		// The prior inputs and the git history may include the numbers and names of the
+		// input itself, including any reuse, so the entries currently in use are excluded.
+		// Otherwise, a reuse that was already recorded would be accepted as-is.
+		reservedRegistry := bufreserved.ExcludeRegistry(
+			previousRegistry,
+			bufreserved.NewRegistryForImage(imageWithConfig),
+		)
+		if err := checkClient.Lint(
+			ctx,
+			lintConfig,
+			imageWithConfig,
+			bufcheck.LintWithReservedRegistry(reservedRegistry),
+		); err != nil {
+			var fileAnnotationSet bufanalysis.FileAnnotationSet
+			if !errors.As(err, &fileAnnotationSet) {
+				return err
+			}
+			allFileAnnotations = append(allFileAnnotations, fileAnnotationSet.FileAnnotations()...)
+		}
+	}
+	if len(allFileAnnotations) == 0 {
+		return nil
+	}
+	if err := bufanalysis.PrintFileAnnotationSet(
+		container.Stdout(),
+		bufanalysis.NewFileAnnotationSet(allFileAnnotations...),
+		flags.ErrorFormat,
+	); err != nil {
+		return err
+	}
+	return bufctl.ErrFileAnnotation
+}
@@ -0,0 +1,152 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reservedsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufreserved"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/spf13/pflag"
+)
+
+const (
+	reservedRegistryFlagName = "reserved-registry"
+	errorFormatFlagName      = "error-format"
+	disableSymlinksFlagName  = "disable-symlinks"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <input>",
+		Short: "Record all field and enum value numbers and names in the reserved registry",
+		Long: `Every field number and name of every message, and every enum value number and name of every enum,
+in the input is added to the reserved registry. Entries already recorded in the registry are never removed,
+so the registry accumulates every number and name that has ever been used.
+
+The RESERVED_REGISTRY_NO_REUSE lint rule uses the registry to check that numbers and names are not reused.
+
+` + bufcli.GetInputLong(`the source, module, or Image to record`),
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	ReservedRegistry string
+	ErrorFormat      string
+	DisableSymlinks  bool
+	// special
+	InputHashtag string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	flagSet.StringVar(
+		&f.ReservedRegistry,
+		reservedRegistryFlagName,
+		bufreserved.DefaultFileName,
+		`The reserved registry file to update. The file is created if it does not exist`,
+	)
+	flagSet.StringVar(
+		&f.ErrorFormat,
+		errorFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			"The format for build errors printed to stderr. Must be one of %s",
+			stringutil.SliceToString(bufanalysis.AllFormatStrings),
+		),
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	if err := bufcli.ValidateRequiredFlag(reservedRegistryFlagName, flags.ReservedRegistry); err != nil {
+		return err
+	}
+	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	if err != nil {
+		return err
+	}
+	controller, err := bufcli.NewController(
+		container,
+		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
+	)
+	if err != nil {
+		return err
+	}
+	image, err := controller.GetImage(
+		ctx,
+		input,
+		bufctl.WithImageExcludeSourceInfo(true),
+	)
+	if err != nil {
+		return err
+	}
+	registry := bufreserved.NewRegistryForImage(image)
+	existingRegistry, err := readRegistryIfExists(flags.ReservedRegistry)
+	if err != nil {
+		return err
+	}
+	if existingRegistry != nil {
+		registry = bufreserved.MergeRegistries(existingRegistry, registry)
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := bufreserved.WriteRegistry(buffer, registry); err != nil {
+		return err
+	}
+	return os.WriteFile(flags.ReservedRegistry, buffer.Bytes(), 0644)
+}
+
+func readRegistryIfExists(path string) (bufreserved.Registry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+	return bufreserved.ReadRegistry(file)
+}
@@ -0,0 +1,324 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transcode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/buftranscode"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	errorFormatFlagName     = "error-format"
+	disableSymlinksFlagName = "disable-symlinks"
+	rpcFlagName             = "rpc"
+	httpMethodFlagName      = "http-method"
+	httpPathFlagName        = "http-path"
+	dataFlagName            = "data"
+	dataFlagShortName       = "d"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <input>",
+		Short: "Show how an HTTP request maps to an RPC, or an RPC to an HTTP request, with gRPC JSON transcoding",
+		Long: `Show how an HTTP request maps to an RPC and its request message, or how an RPC request
+maps to an HTTP request, according to the google.api.http annotations of the methods of the input.
+This is the mapping used by gRPC JSON transcoding proxies such as Envoy, and can be used to debug
+transcoding without deploying a proxy.
+
+To map an HTTP request to an RPC, set --http-method and --http-path, and set --data to the JSON
+body of the HTTP request, if any. The first binding whose HTTP method and path template match is
+used, and the RPC and request message are printed:
+
+    $ buf beta transcode --http-method PATCH --http-path /v1/shelves/1/books/2 \
+        --data '{"title": "Dune"}'
+
+To map an RPC to an HTTP request, set --rpc to the method, and set --data to the JSON request
+message. The first binding of the method for which all fields bound by the path template are
+set is used, and the HTTP request is printed:
+
+    $ buf beta transcode --rpc acme.v1.BookService/GetBook --data '{"name": "shelves/1/books/2"}'
+
+The output is a JSON object with the binding that was used, and either the RPC or the HTTP request.
+
+` + bufcli.GetInputLong(`the source, module, or image with the methods to transcode`),
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	ErrorFormat     string
+	DisableSymlinks bool
+	RPC             string
+	HTTPMethod      string
+	HTTPPath        string
+	Data            string
+	// special
+	InputHashtag string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	flagSet.StringVar(
+		&f.ErrorFormat,
+		errorFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			"The format for build errors printed to stderr. Must be one of %s",
+			stringutil.SliceToString(bufanalysis.AllFormatStrings),
+		),
+	)
+	flagSet.StringVar(
+		&f.RPC,
+		rpcFlagName,
+		"",
+		fmt.Sprintf(
+			`The fully-qualified name of the method to map to an HTTP request, such as acme.v1.BookService/GetBook. Cannot be set with --%s`,
+			httpPathFlagName,
+		),
+	)
+	flagSet.StringVar(
+		&f.HTTPMethod,
+		httpMethodFlagName,
+		"GET",
+		`The method of the HTTP request to map to an RPC`,
+	)
+	flagSet.StringVar(
+		&f.HTTPPath,
+		httpPathFlagName,
+		"",
+		fmt.Sprintf(
+			`The path of the HTTP request to map to an RPC, including the query string, such as /v1/shelves/1/books?page_size=10. Cannot be set with --%s`,
+			rpcFlagName,
+		),
+	)
+	flagSet.StringVarP(
+		&f.Data,
+		dataFlagName,
+		dataFlagShortName,
+		"",
+		`The JSON body of the HTTP request, or the JSON request message of the RPC. If the value starts with "@", the rest of the value is a path to read the data from, with "@-" reading from stdin`,
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	if (flags.RPC == "") == (flags.HTTPPath == "") {
+		return appcmd.NewInvalidArgumentErrorf("exactly one of --%s or --%s must be set", rpcFlagName, httpPathFlagName)
+	}
+	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	if err != nil {
+		return err
+	}
+	data, err := readData(container, flags.Data)
+	if err != nil {
+		return fmt.Errorf("--%s: %w", dataFlagName, err)
+	}
+	controller, err := bufcli.NewController(
+		container,
+		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
+	)
+	if err != nil {
+		return err
+	}
+	image, err := controller.GetImage(ctx, input)
+	if err != nil {
+		return err
+	}
+	transcoder, err := buftranscode.NewTranscoder(image)
+	if err != nil {
+		return err
+	}
+	var result *externalResult
+	if flags.RPC != "" {
+		result, err = rpcToHTTP(image, transcoder, flags.RPC, data)
+	} else {
+		result, err = httpToRPC(image, transcoder, flags.HTTPMethod, flags.HTTPPath, data)
+	}
+	if err != nil {
+		return err
+	}
+	output, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(container.Stdout(), string(output))
+	return err
+}
+
+func httpToRPC(
+	image bufimage.Image,
+	transcoder buftranscode.Transcoder,
+	httpMethod string,
+	httpPath string,
+	data []byte,
+) (*externalResult, error) {
+	binding, requestMessage, err := transcoder.HTTPToRPC(
+		&buftranscode.HTTPRequest{
+			Method: strings.ToUpper(httpMethod),
+			Target: httpPath,
+			Body:   data,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	requestData, err := protoencoding.NewJSONMarshaler(image.Resolver()).Marshal(requestMessage)
+	if err != nil {
+		return nil, err
+	}
+	return &externalResult{
+		Binding: newExternalBinding(binding),
+		RPC: &externalRPC{
+			Method:  getRPCName(binding.Method()),
+			Request: requestData,
+		},
+	}, nil
+}
+
+func rpcToHTTP(
+	image bufimage.Image,
+	transcoder buftranscode.Transcoder,
+	rpc string,
+	data []byte,
+) (*externalResult, error) {
+	// Accept both acme.v1.BookService/GetBook and acme.v1.BookService.GetBook.
+	methodFullName := protoreflect.FullName(strings.Replace(strings.TrimPrefix(rpc, "/"), "/", ".", 1))
+	descriptor, err := image.Resolver().FindDescriptorByName(methodFullName)
+	if err != nil {
+		return nil, fmt.Errorf("--%s: method %q not found", rpcFlagName, rpc)
+	}
+	methodDescriptor, ok := descriptor.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("--%s: %q is not a method", rpcFlagName, rpc)
+	}
+	requestMessage := dynamicpb.NewMessage(methodDescriptor.Input())
+	if len(data) > 0 {
+		if err := protoencoding.NewJSONUnmarshaler(
+			image.Resolver(),
+			protoencoding.JSONUnmarshalerWithDisallowUnknown(),
+		).Unmarshal(data, requestMessage); err != nil {
+			return nil, fmt.Errorf("--%s: invalid %s: %w", dataFlagName, methodDescriptor.Input().FullName(), err)
+		}
+	}
+	binding, httpRequest, err := transcoder.RPCToHTTP(methodFullName, requestMessage)
+	if err != nil {
+		return nil, err
+	}
+	return &externalResult{
+		Binding: newExternalBinding(binding),
+		HTTP: &externalHTTP{
+			Method: httpRequest.Method,
+			Path:   httpRequest.Target,
+			Body:   httpRequest.Body,
+		},
+	}, nil
+}
+
+// readData reads the value of the data flag.
+func readData(container appext.Container, data string) ([]byte, error) {
+	path, ok := strings.CutPrefix(data, "@")
+	if !ok {
+		return []byte(data), nil
+	}
+	if path == "-" {
+		return io.ReadAll(container.Stdin())
+	}
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("file %q does not exist", path)
+		}
+		return nil, err
+	}
+	return fileData, nil
+}
+
+func getRPCName(methodDescriptor protoreflect.MethodDescriptor) string {
+	return string(methodDescriptor.Parent().FullName()) + "/" + string(methodDescriptor.Name())
+}
+
+type externalResult struct {
+	Binding *externalBinding `json:"binding"`
+	// Only set when mapping an HTTP request to an RPC.
+	RPC *externalRPC `json:"rpc,omitempty"`
+	// Only set when mapping an RPC to an HTTP request.
+	HTTP *externalHTTP `json:"http,omitempty"`
+}
+
+type externalBinding struct {
+	RPC    string `json:"rpc"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Body   string `json:"body,omitempty"`
+}
+
+func newExternalBinding(binding buftranscode.Binding) *externalBinding {
+	return &externalBinding{
+		RPC:    getRPCName(binding.Method()),
+		Method: binding.HTTPMethod(),
+		Path:   binding.PathTemplate(),
+		Body:   binding.Body(),
+	}
+}
+
+type externalRPC struct {
+	Method  string          `json:"method"`
+	Request json.RawMessage `json:"request"`
+}
+
+type externalHTTP struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
@@ -0,0 +1,282 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/buffetch"
+	"github.com/bufbuild/buf/private/buf/bufui"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/transport/http/httpserver"
+	"github.com/bufbuild/buf/private/pkg/wasm"
+	"github.com/spf13/pflag"
+)
+
+const (
+	againstFlagName         = "against"
+	againstConfigFlagName   = "against-config"
+	configFlagName          = "config"
+	disableSymlinksFlagName = "disable-symlinks"
+	bindFlagName            = "bind"
+	portFlagName            = "port"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <input>",
+		Short: "Run a local web UI for the package and type graph of an input",
+		Long: `The UI renders the package graph of the input, the fields, values, and methods of its
+messages, enums, and services, the types that reference each type, and the lint findings for the input.
+If --` + againstFlagName + ` is set, the breaking changes against it are also rendered, as with buf breaking.
+If the input does not build, the compiler errors are rendered instead.
+
+The UI is served at http://<bind>:<port>, and the input is rebuilt every time the page is loaded, so that
+unpushed changes to a local workspace are rendered without restarting the command. The UI does not
+require network access.
+
+` + bufcli.GetInputLong(`the source, module, or image to render`),
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	Against         string
+	AgainstConfig   string
+	Config          string
+	DisableSymlinks bool
+	BindAddress     string
+	Port            string
+	// special
+	InputHashtag string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	flagSet.StringVar(
+		&f.Against,
+		againstFlagName,
+		"",
+		fmt.Sprintf(
+			`The source, module, or image to check against for breaking changes. Must be one of format %s`,
+			buffetch.AllFormatsString,
+		),
+	)
+	flagSet.StringVar(
+		&f.AgainstConfig,
+		againstConfigFlagName,
+		"",
+		`The buf.yaml file or data to use to configure the against source, module, or image`,
+	)
+	flagSet.StringVar(
+		&f.Config,
+		configFlagName,
+		"",
+		`The buf.yaml file or data to use for configuration`,
+	)
+	flagSet.StringVar(
+		&f.BindAddress,
+		bindFlagName,
+		"127.0.0.1",
+		"The address to be exposed to accept HTTP requests",
+	)
+	flagSet.StringVar(
+		&f.Port,
+		portFlagName,
+		"8080",
+		"The port to be exposed to accept HTTP requests",
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) (retErr error) {
+	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	if err != nil {
+		return err
+	}
+	wasmRuntimeCacheDir, err := bufcli.CreateWasmRuntimeCacheDir(container)
+	if err != nil {
+		return err
+	}
+	wasmRuntime, err := wasm.NewRuntime(ctx, wasm.WithLocalCacheDir(wasmRuntimeCacheDir))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, wasmRuntime.Close(ctx))
+	}()
+	snapshotter := newSnapshotter(container, wasmRuntime, input, flags)
+	var httpListenConfig net.ListenConfig
+	httpListener, err := httpListenConfig.Listen(ctx, "tcp", fmt.Sprintf("%s:%s", flags.BindAddress, flags.Port))
+	if err != nil {
+		return err
+	}
+	container.Logger().InfoContext(ctx, fmt.Sprintf("serving the UI at http://%s", httpListener.Addr().String()))
+	return httpserver.Run(
+		ctx,
+		container.Logger(),
+		httpListener,
+		bufui.NewHandler(container.Logger(), snapshotter),
+	)
+}
+
+type snapshotter struct {
+	container   appext.Container
+	wasmRuntime wasm.Runtime
+	input       string
+	flags       *flags
+}
+
+func newSnapshotter(
+	container appext.Container,
+	wasmRuntime wasm.Runtime,
+	input string,
+	flags *flags,
+) *snapshotter {
+	return &snapshotter{
+		container:   container,
+		wasmRuntime: wasmRuntime,
+		input:       input,
+		flags:       flags,
+	}
+}
+
+func (s *snapshotter) Snapshot(ctx context.Context) (*bufui.Snapshot, error) {
+	// A new controller is created for every Snapshot, so that changes to the input
+	// and its configuration are picked up.
+	controller, err := bufcli.NewController(
+		s.container,
+		bufctl.WithDisableSymlinks(s.flags.DisableSymlinks),
+		bufctl.WithFileAnnotationSetsReturned(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	imageWithConfigs, checkClient, err := controller.GetTargetImageWithConfigsAndCheckClient(
+		ctx,
+		s.input,
+		s.wasmRuntime,
+		bufctl.WithConfigOverride(s.flags.Config),
+	)
+	if err != nil {
+		var fileAnnotationSet bufanalysis.FileAnnotationSet
+		if !errors.As(err, &fileAnnotationSet) {
+			return nil, err
+		}
+		snapshot := bufui.NewSnapshot()
+		snapshot.AddFindings(bufui.CheckBuild, fileAnnotationSet.FileAnnotations())
+		return snapshot, nil
+	}
+	images := make([]bufimage.Image, len(imageWithConfigs))
+	allCheckConfigs := make([]bufconfig.CheckConfig, 0, len(imageWithConfigs)*2)
+	for i, imageWithConfig := range imageWithConfigs {
+		images[i] = imageWithConfig
+		allCheckConfigs = append(allCheckConfigs, imageWithConfig.LintConfig())
+		allCheckConfigs = append(allCheckConfigs, imageWithConfig.BreakingConfig())
+	}
+	snapshot := bufui.NewSnapshot(images...)
+	for _, imageWithConfig := range imageWithConfigs {
+		if err := checkClient.Lint(
+			ctx,
+			imageWithConfig.LintConfig(),
+			imageWithConfig,
+			bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
+			bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
+		); err != nil {
+			fileAnnotations, err := getFileAnnotations(err)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.AddFindings(bufui.CheckLint, fileAnnotations)
+		}
+	}
+	if s.flags.Against == "" {
+		return snapshot, nil
+	}
+	// Do not exclude imports here. bufcheck's Client requires all imports.
+	againstImageWithConfigs, _, err := controller.GetTargetImageWithConfigsAndCheckClient(
+		ctx,
+		s.flags.Against,
+		wasm.UnimplementedRuntime,
+		bufctl.WithConfigOverride(s.flags.AgainstConfig),
+		bufctl.WithGitMergeBase(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(imageWithConfigs) != len(againstImageWithConfigs) {
+		return nil, fmt.Errorf(
+			"input contained %d images, whereas against contained %d images",
+			len(imageWithConfigs),
+			len(againstImageWithConfigs),
+		)
+	}
+	for i, imageWithConfig := range imageWithConfigs {
+		if err := checkClient.Breaking(
+			ctx,
+			imageWithConfig.BreakingConfig(),
+			imageWithConfig,
+			againstImageWithConfigs[i],
+			bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
+			bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
+		); err != nil {
+			fileAnnotations, err := getFileAnnotations(err)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.AddFindings(bufui.CheckBreaking, fileAnnotations)
+		}
+	}
+	return snapshot, nil
+}
+
+// getFileAnnotations returns the FileAnnotations of the error if it is a
+// bufanalysis.FileAnnotationSet, and the error otherwise.
+func getFileAnnotations(err error) ([]bufanalysis.FileAnnotation, error) {
+	var fileAnnotationSet bufanalysis.FileAnnotationSet
+	if !errors.As(err, &fileAnnotationSet) {
+		return nil, err
+	}
+	return fileAnnotationSet.FileAnnotations(), nil
+}
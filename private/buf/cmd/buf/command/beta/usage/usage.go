@@ -0,0 +1,166 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/buffetch"
+	"github.com/bufbuild/buf/private/buf/bufprint"
+	"github.com/bufbuild/buf/private/buf/bufusage"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/syserror"
+	"github.com/spf13/pflag"
+)
+
+const (
+	ofFlagName              = "of"
+	formatFlagName          = "format"
+	unusedFlagName          = "unused"
+	disableSymlinksFlagName = "disable-symlinks"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <dependent-input>... --of <input>",
+		Short: "Report which elements of a module are referenced by its dependents",
+		Long: `Report which messages, fields, enums, and extensions of the module given to --of are referenced by the schemas of its dependents, to guide safe deprecations.
+
+Each <dependent-input> is a source, module, or image of a dependent, such as a local workspace
+or a descriptor set built with "buf build". The dependents are only read locally, so no analytics
+of the Buf Schema Registry are needed. For example, to report on a module for a directory that
+contains a checkout of each dependent:
+
+    $ buf beta usage --of buf.build/acme/common dependents/*
+
+A message or enum is referenced if it is used as the type of a field or extension, the input or
+output of a method, or the extendee of an extension. The fields of a message used as a type are
+referenced, as are the types of these fields, transitively. Messages, fields, enums, and
+extensions are also referenced if they are set in option values, such as a custom option declared
+by the module, in which case only the fields that are set are referenced.
+
+Services and methods are not reported, as they cannot be referenced by schemas.`,
+		Args: appcmd.MinimumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	Of              string
+	Format          string
+	Unused          bool
+	DisableSymlinks bool
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(
+		&f.Of,
+		ofFlagName,
+		"",
+		fmt.Sprintf(
+			`Required. The source, module, or image of the module to report on, such as a module on the BSR. Must be one of format %s`,
+			buffetch.AllFormatsString,
+		),
+	)
+	flagSet.StringVar(
+		&f.Format,
+		formatFlagName,
+		bufprint.FormatText.String(),
+		fmt.Sprintf(`The output format to use. Must be one of %s`, bufprint.AllFormatsString),
+	)
+	flagSet.BoolVar(
+		&f.Unused,
+		unusedFlagName,
+		false,
+		"Only report the elements that are not referenced by any dependent",
+	)
+	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	if err := bufcli.ValidateRequiredFlag(ofFlagName, flags.Of); err != nil {
+		return err
+	}
+	format, err := bufprint.ParseFormat(flags.Format)
+	if err != nil {
+		return appcmd.WrapInvalidArgumentError(err)
+	}
+	controller, err := bufcli.NewController(
+		container,
+		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+	)
+	if err != nil {
+		return err
+	}
+	moduleImage, err := controller.GetImage(ctx, flags.Of)
+	if err != nil {
+		return err
+	}
+	dependents := make([]*bufusage.Dependent, container.NumArgs())
+	for i := range container.NumArgs() {
+		dependentInput := container.Arg(i)
+		dependentImage, err := controller.GetImage(ctx, dependentInput)
+		if err != nil {
+			return err
+		}
+		dependents[i] = &bufusage.Dependent{
+			Name:  dependentInput,
+			Image: dependentImage,
+		}
+	}
+	usages, err := bufusage.GetUsages(moduleImage, dependents)
+	if err != nil {
+		return err
+	}
+	if flags.Unused {
+		var unusedUsages []*bufusage.Usage
+		for _, usage := range usages {
+			if len(usage.Dependents) == 0 {
+				unusedUsages = append(unusedUsages, usage)
+			}
+		}
+		usages = unusedUsages
+	}
+	switch format {
+	case bufprint.FormatText:
+		return bufusage.PrintUsages(container.Stdout(), usages)
+	case bufprint.FormatJSON:
+		return bufusage.PrintUsagesJSON(container.Stdout(), usages)
+	default:
+		return syserror.Newf("unknown format: %v", format)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifygenerateconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/bufgen"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/storage/storageos"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/spf13/pflag"
+)
+
+const (
+	errorFormatFlagName = "error-format"
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <template>",
+		Short: "Verify a buf.gen.yaml file without running any plugins",
+		Long: `Verify a buf.gen.yaml file beyond its schema, without running any plugins.
+
+The template is either a buf.gen.yaml file, or a directory that contains a buf.gen.yaml file,
+and defaults to the current directory.
+
+The following problems fail the command:
+
+  - Local plugins, and the protoc binary of protoc built-in plugins, that cannot be found.
+  - Plugins that generate the same files to the same out.
+  - Post commands or clean set on plugins with archive outs.
+
+The following problems are printed as warnings:
+
+  - Local fallbacks of remote plugins that cannot be found.
+  - include_imports set on plugins with the directory strategy, which generates imported files
+    once for every directory that imports them.
+  - Plugin settings that have no effect, such as include_imports with the all_with_imports
+    strategy, types that are also excluded, and routes that can never match.
+  - Managed mode disable and override rules that are never applied, either because managed
+    mode is not enabled, or because the override is disabled or replaced by a later override
+    for all the files it applies to.
+
+Plugins are numbered from 1 in the order they are specified in the template, as are managed
+mode disable and override rules. Remote plugins are not resolved.`,
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	ErrorFormat string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(
+		&f.ErrorFormat,
+		errorFormatFlagName,
+		"text",
+		fmt.Sprintf(
+			"The format for problems printed to stdout. Must be one of %s",
+			stringutil.SliceToString(bufanalysis.AllFormatStrings),
+		),
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	templatePath := "."
+	if container.NumArgs() > 0 {
+		templatePath = container.Arg(0)
+	}
+	bufGenYAMLFile, filePath, err := readBufGenYAMLFile(ctx, container, templatePath)
+	if err != nil {
+		return err
+	}
+	if err := bufgen.VerifyConfig(newFileInfo(filePath), bufGenYAMLFile.GenerateConfig()); err != nil {
+		var fileAnnotationSet bufanalysis.FileAnnotationSet
+		if !errors.As(err, &fileAnnotationSet) {
+			return err
+		}
+		if err := bufanalysis.PrintFileAnnotationSet(
+			container.Stdout(),
+			fileAnnotationSet,
+			flags.ErrorFormat,
+		); err != nil {
+			return err
+		}
+		// Warnings are printed, but do not fail the command.
+		fileAnnotations := fileAnnotationSet.FileAnnotations()
+		if len(fileAnnotations) > bufanalysis.CountFileAnnotationsWithSeverity(fileAnnotations, bufanalysis.SeverityWarning) {
+			return bufctl.ErrFileAnnotation
+		}
+	}
+	return nil
+}
+
+// readBufGenYAMLFile reads the buf.gen.yaml file at the path, or in the directory at
+// the path, and returns it along with the path of the file.
+func readBufGenYAMLFile(
+	ctx context.Context,
+	container appext.Container,
+	templatePath string,
+) (bufconfig.BufGenYAMLFile, string, error) {
+	envOption := bufconfig.BufGenYAMLFileWithEnvFunc(container.Env)
+	switch filepath.Ext(templatePath) {
+	case ".yaml", ".yml", ".json":
+		file, err := os.Open(templatePath)
+		if err != nil {
+			return nil, "", err
+		}
+		defer file.Close()
+		bufGenYAMLFile, err := bufconfig.ReadBufGenYAMLFile(
+			file,
+			bufconfig.BufGenYAMLFileWithExtendsFromOS(filepath.Dir(templatePath)),
+			envOption,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		return bufGenYAMLFile, templatePath, nil
+	}
+	bucket, err := storageos.NewProvider(storageos.ProviderWithSymlinks()).NewReadWriteBucket(
+		templatePath,
+		storageos.ReadWriteBucketWithSymlinksIfSupported(),
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	bufGenYAMLFile, err := bufconfig.GetBufGenYAMLFileForPrefix(
+		ctx,
+		bucket,
+		".",
+		bufconfig.BufGenYAMLFileWithExtendsFromOS(templatePath),
+		envOption,
+	)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, "", fmt.Errorf("no buf.gen.yaml file found in %q", templatePath)
+		}
+		return nil, "", err
+	}
+	return bufGenYAMLFile, filepath.Join(templatePath, "buf.gen.yaml"), nil
+}
+
+type fileInfo struct {
+	path string
+}
+
+func newFileInfo(path string) *fileInfo {
+	return &fileInfo{
+		path: path,
+	}
+}
+
+func (f *fileInfo) Path() string {
+	return f.path
+}
+
+func (f *fileInfo) ExternalPath() string {
+	return f.path
+}
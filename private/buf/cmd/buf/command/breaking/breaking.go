@@ -18,11 +18,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 
+	"buf.build/go/bufplugin/check"
+	"github.com/bufbuild/buf/private/buf/bufchanged"
 	"github.com/bufbuild/buf/private/buf/bufcli"
 	"github.com/bufbuild/buf/private/buf/bufctl"
 	"github.com/bufbuild/buf/private/buf/buffetch"
 	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufbreakingexception"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage"
@@ -35,15 +39,24 @@ import (
 )
 
 const (
-	errorFormatFlagName       = "error-format"
-	excludeImportsFlagName    = "exclude-imports"
-	pathsFlagName             = "path"
-	limitToInputFilesFlagName = "limit-to-input-files"
-	configFlagName            = "config"
-	againstFlagName           = "against"
-	againstConfigFlagName     = "against-config"
-	excludePathsFlagName      = "exclude-path"
-	disableSymlinksFlagName   = "disable-symlinks"
+	errorFormatFlagName          = "error-format"
+	excludeImportsFlagName       = "exclude-imports"
+	pathsFlagName                = "path"
+	limitToInputFilesFlagName    = "limit-to-input-files"
+	configFlagName               = "config"
+	againstFlagName              = "against"
+	againstConfigFlagName        = "against-config"
+	excludePathsFlagName         = "exclude-path"
+	disableSymlinksFlagName      = "disable-symlinks"
+	disableGitAttributesFlagName = "disable-git-attributes"
+	onlyChangedLinesFlagName     = "only-changed-lines"
+	disableMergeBaseFlagName     = "disable-merge-base"
+	exceptionsFlagName           = "exceptions"
+	updateExceptionsFlagName     = "update-exceptions"
+	exceptionReasonFlagName      = "exception-reason"
+	fromFlagName                 = "from"
+	showSourceFlagName           = "show-source"
+	failOnFlagName               = "fail-on"
 )
 
 // NewCommand returns a new Command.
@@ -57,6 +70,79 @@ func NewCommand(
 		Short: "Verify no breaking changes have been made",
 		Long: `This command makes sure that the <input> location has no breaking changes compared to the <against-input> location.
 
+Breaking changes can be limited to the lines changed since a git ref with --only-changed-lines,
+so that only the breaking changes made by a pull request are reported:
+
+    $ buf breaking --against '.git#branch=main' --only-changed-lines origin/main
+
+A breaking change is reported if any of its lines were added or modified, or if lines were
+removed within it, such as for a field removed from a message. Breaking changes for deleted
+files are always reported.
+
+If <against-input> is a branch of a local git repository, such as '.git#branch=main', the
+comparison is made against the merge base of the branch and HEAD, in the manner of
+"git diff main...", instead of against the tip of the branch. This ensures that changes made to
+the branch after HEAD was forked from it are not reported as breaking changes. Use
+--disable-merge-base to compare against the tip of the branch instead.
+
+The merge base of HEAD and any ref of a local git repository, such as a remote-tracking branch,
+can also be compared against with the fork_point option, which is not affected by
+--disable-merge-base:
+
+    $ buf breaking --against '.git#fork_point=origin/main'
+
+--from is an alias of <input>, which reads more naturally when two arbitrary inputs are compared,
+such as two labels of a module on the BSR, or two tags of a git repository:
+
+    $ buf breaking --from buf.build/acme/weather:v2 --against buf.build/acme/weather:v1
+    $ buf breaking --from '.git#tag=v2.0.0' --against '.git#tag=v1.0.0'
+
+The <against-input> is resolved in the same way for --from as for <input>, including the merge
+base of a branch of a local git repository.
+
+--against can be repeated to check for breaking changes against multiple inputs at once, such as
+the last few release tags and the main branch, for APIs that have clients pinned to several
+historical releases:
+
+    $ buf breaking --against '.git#tag=v1.0.0' --against '.git#tag=v2.0.0' --against '.git#branch=main'
+
+Each breaking change is reported once, with the against inputs it was found against. The command
+fails if there are breaking changes against any of the against inputs.
+
+Intentional breaking changes can be approved by recording them as exceptions with
+--update-exceptions, so that subsequent runs do not report them:
+
+    $ buf breaking --against '.git#branch=main' --update-exceptions --exception-reason "Field was never used"
+
+The exceptions file is ` + bufbreakingexception.DefaultFileName + ` in the directory of the workspace or
+module of the input, unless set with --exceptions, and is read by subsequent runs if it exists.
+Each exception records the rule, the fully-qualified name of the innermost declaration that
+contains the breaking change, the message of the breaking change, and the reason it was
+approved, which can be edited. Running with
+--update-exceptions again removes the exceptions for breaking changes that are no longer detected,
+and keeps the reasons of the remaining exceptions.
+
+The source before and after each breaking change can be printed with --show-source, so that the
+change can be reviewed without opening both versions. This applies to the text and json error
+formats, and requires the source of the input or against input, which is not available for images:
+
+    $ buf breaking --against '.git#branch=main' --show-source
+
+If --against is repeated, the source before a breaking change is taken from the first against input
+that the breaking change was found against.
+
+The command can be set to only fail for the breaking changes of specific rules or categories with
+--fail-on, or with the fail_on key of the breaking configuration. All other breaking changes are
+still printed, but as warnings that do not fail the command. For example, to fail for breaking
+changes to the wire format, but only warn for breaking changes to the JSON format:
+
+    $ buf breaking --against '.git#branch=main' --fail-on WIRE
+
+A breaking change is also a failure if one of the rules in --fail-on reports a breaking change at
+the same location, even if that rule is not configured. For example, with --fail-on WIRE, a field
+type change reported by FIELD_SAME_TYPE is a failure if the change is not wire compatible.
+--fail-on overrides the fail_on key of the breaking configuration.
+
 ` +
 			bufcli.GetInputLong(`the source, module, or image to check for breaking changes`),
 		Args: appcmd.MaximumNArgs(1),
@@ -70,15 +156,24 @@ func NewCommand(
 }
 
 type flags struct {
-	ErrorFormat       string
-	ExcludeImports    bool
-	LimitToInputFiles bool
-	Paths             []string
-	Config            string
-	Against           string
-	AgainstConfig     string
-	ExcludePaths      []string
-	DisableSymlinks   bool
+	ErrorFormat          string
+	ExcludeImports       bool
+	LimitToInputFiles    bool
+	Paths                []string
+	Config               string
+	Against              []string
+	AgainstConfig        string
+	ExcludePaths         []string
+	DisableSymlinks      bool
+	DisableGitAttributes bool
+	OnlyChangedLines     string
+	DisableMergeBase     bool
+	Exceptions           string
+	UpdateExceptions     bool
+	ExceptionReason      string
+	From                 string
+	ShowSource           bool
+	FailOn               []string
 	// special
 	InputHashtag string
 }
@@ -92,6 +187,9 @@ func (f *flags) Bind(flagSet *pflag.FlagSet) {
 	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
 	bufcli.BindExcludePaths(flagSet, &f.ExcludePaths, excludePathsFlagName)
 	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	bufcli.BindDisableGitAttributes(flagSet, &f.DisableGitAttributes, disableGitAttributesFlagName)
+	bufcli.BindOnlyChangedLines(flagSet, &f.OnlyChangedLines, onlyChangedLinesFlagName)
+	bufcli.BindBreakingExceptions(flagSet, &f.Exceptions, exceptionsFlagName)
 	flagSet.StringVar(
 		&f.ErrorFormat,
 		errorFormatFlagName,
@@ -124,12 +222,13 @@ Overrides --%s`,
 		"",
 		`The buf.yaml file or data to use for configuration`,
 	)
-	flagSet.StringVar(
+	flagSet.StringArrayVar(
 		&f.Against,
 		againstFlagName,
-		"",
+		nil,
 		fmt.Sprintf(
-			`Required. The source, module, or image to check against. Must be one of format %s`,
+			`Required. The source, module, or image to check against. Must be one of format %s
+May be provided multiple times to check against multiple inputs`,
 			buffetch.AllFormatsString,
 		),
 	)
@@ -139,6 +238,57 @@ Overrides --%s`,
 		"",
 		`The buf.yaml file or data to use to configure the against source, module, or image`,
 	)
+	flagSet.BoolVar(
+		&f.DisableMergeBase,
+		disableMergeBaseFlagName,
+		false,
+		fmt.Sprintf(
+			`Compare against the tip of the branch of a local git repository given to --%s, instead of the merge base of the branch and HEAD`,
+			againstFlagName,
+		),
+	)
+	flagSet.BoolVar(
+		&f.UpdateExceptions,
+		updateExceptionsFlagName,
+		false,
+		"Write the breaking changes to the exceptions file as approved exceptions instead of printing them",
+	)
+	flagSet.StringVar(
+		&f.ExceptionReason,
+		exceptionReasonFlagName,
+		"",
+		fmt.Sprintf(
+			"The reason to record for new exceptions. Requires --%s",
+			updateExceptionsFlagName,
+		),
+	)
+	flagSet.BoolVar(
+		&f.ShowSource,
+		showSourceFlagName,
+		false,
+		fmt.Sprintf(
+			`Print excerpts of the source before and after each breaking change. Applies to the text and json values of --%s`,
+			errorFormatFlagName,
+		),
+	)
+	flagSet.StringSliceVar(
+		&f.FailOn,
+		failOnFlagName,
+		nil,
+		`The rule IDs or categories whose breaking changes fail the command, such as WIRE
+The breaking changes of all other rules are printed as warnings
+May be provided multiple times
+Overrides the fail_on key of the breaking configuration`,
+	)
+	flagSet.StringVar(
+		&f.From,
+		fromFlagName,
+		"",
+		fmt.Sprintf(
+			`An alias of <input>, the source, module, or image to check for breaking changes. Must be one of format %s`,
+			buffetch.AllFormatsString,
+		),
+	)
 }
 
 func run(
@@ -146,16 +296,34 @@ func run(
 	container appext.Container,
 	flags *flags,
 ) (retErr error) {
-	if err := bufcli.ValidateRequiredFlag(againstFlagName, flags.Against); err != nil {
-		return err
+	if len(flags.Against) == 0 {
+		return appcmd.NewInvalidArgumentErrorf("--%s is required", againstFlagName)
 	}
-	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	if flags.ExceptionReason != "" && !flags.UpdateExceptions {
+		return appcmd.NewInvalidArgumentErrorf("--%s requires --%s", exceptionReasonFlagName, updateExceptionsFlagName)
+	}
+	input, err := getInput(container, flags)
 	if err != nil {
 		return err
 	}
+	exceptionSet, err := bufcli.ReadBreakingExceptions(ctx, container, input, flags.Exceptions)
+	if err != nil {
+		// The exceptions file is created when updating exceptions.
+		if !flags.UpdateExceptions || !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	var changedLines bufchanged.ChangedLines
+	if flags.OnlyChangedLines != "" {
+		changedLines, err = bufcli.NewChangedLinesForInput(ctx, container, input, flags.OnlyChangedLines, onlyChangedLinesFlagName)
+		if err != nil {
+			return err
+		}
+	}
 	controller, err := bufcli.NewController(
 		container,
 		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithDisableGitAttributes(flags.DisableGitAttributes),
 		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
 		bufctl.WithFileAnnotationsToStdout(),
 	)
@@ -194,30 +362,12 @@ func run(
 			return err
 		}
 	}
-	// Do not exclude imports here. bufcheck's Client requires all imports.
-	// Use bufcheck's BreakingWithExcludeImports.
-	againstImageWithConfigs, _, err := controller.GetTargetImageWithConfigsAndCheckClient(
-		ctx,
-		flags.Against,
-		wasm.UnimplementedRuntime,
+	againstFunctionOptions := []bufctl.FunctionOption{
 		bufctl.WithTargetPaths(externalPaths, flags.ExcludePaths),
 		bufctl.WithConfigOverride(flags.AgainstConfig),
-	)
-	if err != nil {
-		return err
 	}
-	if len(imageWithConfigs) != len(againstImageWithConfigs) {
-		// If workspaces are being used as input, the number
-		// of images MUST match. Otherwise the results will
-		// be meaningless and yield false positives.
-		//
-		// And similar to the note above, if the roots change,
-		// we're torched.
-		return fmt.Errorf(
-			"input contained %d images, whereas against contained %d images",
-			len(imageWithConfigs),
-			len(againstImageWithConfigs),
-		)
+	if !flags.DisableMergeBase {
+		againstFunctionOptions = append(againstFunctionOptions, bufctl.WithGitMergeBase())
 	}
 	// We add all check configs (both lint and breaking) as related configs to check if plugins
 	// have rules configured.
@@ -227,44 +377,185 @@ func run(
 		allCheckConfigs = append(allCheckConfigs, imageWithConfig.LintConfig())
 		allCheckConfigs = append(allCheckConfigs, imageWithConfig.BreakingConfig())
 	}
-	var allFileAnnotations []bufanalysis.FileAnnotation
-	for i, imageWithConfig := range imageWithConfigs {
-		breakingOptions := []bufcheck.BreakingOption{
-			bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
-			bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
+	againstInputToFileAnnotations := make([][]bufanalysis.FileAnnotation, len(flags.Against))
+	for againstIndex, againstInput := range flags.Against {
+		// Do not exclude imports here. bufcheck's Client requires all imports.
+		// Use bufcheck's BreakingWithExcludeImports.
+		againstImageWithConfigs, _, err := controller.GetTargetImageWithConfigsAndCheckClient(
+			ctx,
+			againstInput,
+			wasm.UnimplementedRuntime,
+			againstFunctionOptions...,
+		)
+		if err != nil {
+			return err
 		}
-		if flags.ExcludeImports {
-			breakingOptions = append(breakingOptions, bufcheck.BreakingWithExcludeImports())
+		if len(imageWithConfigs) != len(againstImageWithConfigs) {
+			// If workspaces are being used as input, the number
+			// of images MUST match. Otherwise the results will
+			// be meaningless and yield false positives.
+			//
+			// And similar to the note above, if the roots change,
+			// we're torched.
+			return fmt.Errorf(
+				"input contained %d images, whereas against contained %d images",
+				len(imageWithConfigs),
+				len(againstImageWithConfigs),
+			)
 		}
-		if err := checkClient.Breaking(
-			ctx,
-			imageWithConfig.BreakingConfig(),
-			imageWithConfig,
-			againstImageWithConfigs[i],
-			breakingOptions...,
-		); err != nil {
-			var fileAnnotationSet bufanalysis.FileAnnotationSet
-			if errors.As(err, &fileAnnotationSet) {
-				allFileAnnotations = append(allFileAnnotations, fileAnnotationSet.FileAnnotations()...)
-			} else {
+		for i, imageWithConfig := range imageWithConfigs {
+			breakingOptions := []bufcheck.BreakingOption{
+				bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
+				bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
+			}
+			if flags.ExcludeImports {
+				breakingOptions = append(breakingOptions, bufcheck.BreakingWithExcludeImports())
+			}
+			if len(flags.FailOn) > 0 {
+				breakingOptions = append(breakingOptions, bufcheck.BreakingWithFailOn(flags.FailOn...))
+			}
+			if flags.ShowSource {
+				breakingOptions = append(
+					breakingOptions,
+					bufcheck.BreakingWithCodeFrames(
+						imageWithConfig.SourceBucket(),
+						againstImageWithConfigs[i].SourceBucket(),
+					),
+				)
+			}
+			if err := checkClient.Breaking(
+				ctx,
+				imageWithConfig.BreakingConfig(),
+				imageWithConfig,
+				againstImageWithConfigs[i],
+				breakingOptions...,
+			); err != nil {
+				var fileAnnotationSet bufanalysis.FileAnnotationSet
+				if errors.As(err, &fileAnnotationSet) {
+					againstInputToFileAnnotations[againstIndex] = append(
+						againstInputToFileAnnotations[againstIndex],
+						fileAnnotationSet.FileAnnotations()...,
+					)
+				} else {
+					return err
+				}
+			}
+		}
+	}
+	allFileAnnotations := mergeAgainstInputFileAnnotations(flags.Against, againstInputToFileAnnotations)
+	var ruleInfos []bufanalysis.RuleInfo
+	for _, imageWithConfig := range imageWithConfigs {
+		if flags.ErrorFormat == "sarif" {
+			configuredRules, err := checkClient.ConfiguredRules(
+				ctx,
+				check.RuleTypeBreaking,
+				imageWithConfig.BreakingConfig(),
+				bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
+				bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
+			)
+			if err != nil {
 				return err
 			}
+			failOn := flags.FailOn
+			if len(failOn) == 0 {
+				failOn = imageWithConfig.BreakingConfig().FailOnIDsAndCategories()
+			}
+			ruleInfos = append(ruleInfos, bufcli.RulesToRuleInfos(configuredRules, nil, failOn)...)
 		}
 	}
-	if len(allFileAnnotations) > 0 {
+	if changedLines != nil {
+		allFileAnnotations = bufchanged.FilterFileAnnotations(changedLines, allFileAnnotations)
+	}
+	images := make([]bufimage.Image, len(imageWithConfigs))
+	for i, imageWithConfig := range imageWithConfigs {
+		images[i] = imageWithConfig
+	}
+	symbolResolver := bufbreakingexception.NewSymbolResolver(images...)
+	if flags.UpdateExceptions {
+		return bufcli.WriteBreakingExceptions(
+			ctx,
+			container,
+			input,
+			flags.Exceptions,
+			bufbreakingexception.NewExceptionSet(symbolResolver, allFileAnnotations, exceptionSet, flags.ExceptionReason),
+		)
+	}
+	if exceptionSet != nil {
+		allFileAnnotations = bufbreakingexception.FilterFileAnnotations(exceptionSet, symbolResolver, allFileAnnotations)
+	}
+	// SARIF logs, JUnit reports, and Markdown reports are printed even if there are no breaking
+	// changes, as consumers expect a log or report.
+	if len(allFileAnnotations) > 0 || flags.ErrorFormat == "sarif" || flags.ErrorFormat == "junit" || flags.ErrorFormat == "markdown" {
 		allFileAnnotationSet := bufanalysis.NewFileAnnotationSet(allFileAnnotations...)
 		if err := bufanalysis.PrintFileAnnotationSet(
 			container.Stdout(),
 			allFileAnnotationSet,
 			flags.ErrorFormat,
+			bufanalysis.PrintWithRuleInfos(ruleInfos...),
+			bufanalysis.PrintWithFileLinkBaseURL(bufcli.GetFileLinkBaseURL(container)),
 		); err != nil {
 			return err
 		}
-		return bufctl.ErrFileAnnotation
+		// Breaking changes that are warnings are printed, but do not fail the command.
+		numWarnings := bufanalysis.CountFileAnnotationsWithSeverity(allFileAnnotations, bufanalysis.SeverityWarning)
+		if len(allFileAnnotations) > numWarnings {
+			return bufctl.ErrFileAnnotation
+		}
 	}
 	return nil
 }
 
+func getInput(container appext.Container, flags *flags) (string, error) {
+	if flags.From == "" {
+		return bufcli.GetInputValue(container, flags.InputHashtag, ".")
+	}
+	if container.NumArgs() > 0 || flags.InputHashtag != "" {
+		return "", appcmd.NewInvalidArgumentErrorf("<input> cannot be specified with --%s", fromFlagName)
+	}
+	return flags.From, nil
+}
+
+// mergeAgainstInputFileAnnotations merges the FileAnnotations found against each of the
+// against inputs, where againstInputToFileAnnotations[i] are the FileAnnotations found
+// against againstInputs[i].
+//
+// If there are multiple against inputs, each breaking change is returned once, with the
+// against inputs it was found against set, in the order the against inputs were given.
+func mergeAgainstInputFileAnnotations(
+	againstInputs []string,
+	againstInputToFileAnnotations [][]bufanalysis.FileAnnotation,
+) []bufanalysis.FileAnnotation {
+	if len(againstInputs) == 1 {
+		return againstInputToFileAnnotations[0]
+	}
+	var keys []string
+	keyToFileAnnotation := make(map[string]bufanalysis.FileAnnotation)
+	keyToAgainstInputs := make(map[string][]string)
+	for i, fileAnnotations := range againstInputToFileAnnotations {
+		for _, fileAnnotation := range fileAnnotations {
+			key := fileAnnotation.String()
+			if _, ok := keyToFileAnnotation[key]; !ok {
+				keys = append(keys, key)
+				keyToFileAnnotation[key] = fileAnnotation
+			}
+			keyAgainstInputs := keyToAgainstInputs[key]
+			// The same breaking change may be found more than once against an against input
+			// if the input contains multiple images.
+			if len(keyAgainstInputs) == 0 || keyAgainstInputs[len(keyAgainstInputs)-1] != againstInputs[i] {
+				keyToAgainstInputs[key] = append(keyAgainstInputs, againstInputs[i])
+			}
+		}
+	}
+	mergedFileAnnotations := make([]bufanalysis.FileAnnotation, len(keys))
+	for i, key := range keys {
+		mergedFileAnnotations[i] = bufanalysis.NewFileAnnotationWithOptions(
+			keyToFileAnnotation[key],
+			bufanalysis.FileAnnotationWithAgainstInputs(keyToAgainstInputs[key]...),
+		)
+	}
+	return mergedFileAnnotations
+}
+
 func getExternalPathsForImages[I bufimage.Image, S ~[]I](images S) ([]string, error) {
 	externalPaths := make(map[string]struct{})
 	for _, image := range images {
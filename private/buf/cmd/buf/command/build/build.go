@@ -42,6 +42,7 @@ const (
 	configFlagName                        = "config"
 	excludePathsFlagName                  = "exclude-path"
 	disableSymlinksFlagName               = "disable-symlinks"
+	disableGitAttributesFlagName          = "disable-git-attributes"
 	typeFlagName                          = "type"
 )
 
@@ -76,6 +77,7 @@ type flags struct {
 	Config                        string
 	ExcludePaths                  []string
 	DisableSymlinks               bool
+	DisableGitAttributes          bool
 	Types                         []string
 	// special
 	InputHashtag string
@@ -93,6 +95,7 @@ func (f *flags) Bind(flagSet *pflag.FlagSet) {
 	bufcli.BindPaths(flagSet, &f.Paths, pathsFlagName)
 	bufcli.BindExcludePaths(flagSet, &f.ExcludePaths, excludePathsFlagName)
 	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	bufcli.BindDisableGitAttributes(flagSet, &f.DisableGitAttributes, disableGitAttributesFlagName)
 	flagSet.BoolVar(
 		&f.ExcludeSourceRetentionOptions,
 		excludeSourceRetentionOptionsFlagName,
@@ -147,6 +150,7 @@ func run(
 	controller, err := bufcli.NewController(
 		container,
 		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithDisableGitAttributes(flags.DisableGitAttributes),
 		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
 	)
 	if err != nil {
@@ -155,6 +155,11 @@ func run(
 			"",
 			// We actually want comment ignores enabled by default
 			true,
+			false,
+			nil,
+			nil,
+			nil,
+			nil,
 		),
 		bufconfig.NewBreakingConfig(
 			bufconfig.NewEnabledCheckConfigForUseIDsAndCategories(
@@ -163,6 +168,9 @@ func run(
 				false,
 			),
 			false,
+			nil,
+			nil,
+			nil,
 		),
 	)
 	if err != nil {
@@ -101,6 +101,7 @@ type flags struct {
 	To              string
 	Validate        bool
 	DisableSymlinks bool
+	JSONOutput      bufcli.JSONOutputFlags
 
 	// special
 	InputHashtag string
@@ -155,6 +156,7 @@ func (f *flags) Bind(flagSet *pflag.FlagSet) {
 			fromFlagName,
 		),
 	)
+	bufcli.BindJSONOutputFlags(flagSet, &f.JSONOutput, 0)
 }
 
 func run(
@@ -162,6 +164,9 @@ func run(
 	container appext.Container,
 	flags *flags,
 ) error {
+	if err := flags.JSONOutput.Validate(); err != nil {
+		return appcmd.WrapInvalidArgumentError(err)
+	}
 	input, err := bufcli.GetInputValue(container, flags.InputHashtag, ".")
 	if err != nil {
 		return err
@@ -211,7 +216,9 @@ func run(
 	// format. So we prevent that by having the resolver return an error
 	// if asked to resolve any type that uses it.
 	schemaImage = bufconvert.ImageWithoutMessageSetWireFormatResolution(schemaImage)
-	var fromFunctionOptions []bufctl.FunctionOption
+	fromFunctionOptions := []bufctl.FunctionOption{
+		bufctl.WithMessageWireUnmarshalerOptions(flags.JSONOutput.WireUnmarshalerOptions()...),
+	}
 	if flags.Validate {
 		fromFunctionOptions = append(fromFunctionOptions, bufctl.WithMessageValidation())
 	}
@@ -236,6 +243,7 @@ func run(
 		flags.To,
 		fromMessage,
 		defaultToMessageEncoding,
+		bufctl.WithMessageJSONMarshalerOptions(flags.JSONOutput.JSONMarshalerOptions()...),
 	); err != nil {
 		return fmt.Errorf("--%s: %w", toFlagName, err)
 	}
@@ -400,6 +400,95 @@ func TestConvertWKTImport(t *testing.T) {
 	)
 }
 
+func TestConvertJSONOutputFlags(t *testing.T) {
+	t.Parallel()
+	stdin := strings.NewReader(`{"syntax":"SYNTAX_PROTO3","sourceContext":{"fileName":"a.proto"}}`)
+	appcmdtesting.RunCommandExitCodeStdout(
+		t,
+		testNewCommand,
+		0,
+		`{
+  "source_context": {
+    "file_name": "a.proto"
+  },
+  "syntax": 1
+}`,
+		nil,
+		stdin,
+		"--type=google.protobuf.Type",
+		"--from=-#format=json",
+		"--to=-#format=json",
+		"--use-proto-names",
+		"--emit-enum-numbers",
+		"--indent=2",
+	)
+}
+
+func TestConvertJSONOutputEmitDefaults(t *testing.T) {
+	t.Parallel()
+	appcmdtesting.RunCommandExitCodeStdout(
+		t,
+		testNewCommand,
+		0,
+		`{"one":"0"}`,
+		nil,
+		strings.NewReader(`{}`),
+		"testdata/convert/bin_json/buf.proto",
+		"--type=buf.Foo",
+		"--from=-#format=json",
+		"--to=-#format=json",
+		"--emit-defaults",
+	)
+}
+
+func TestConvertDiscardUnknown(t *testing.T) {
+	t.Parallel()
+	// Field 1 is 55, and field 2 is not in the schema.
+	payload := "\x08\x37\x10\x01"
+	appcmdtesting.RunCommandExitCodeStdout(
+		t,
+		testNewCommand,
+		0,
+		payload,
+		nil,
+		strings.NewReader(payload),
+		"testdata/convert/bin_json/buf.proto",
+		"--type=buf.Foo",
+		"--from=-#format=binpb",
+		"--to=-#format=binpb",
+	)
+	appcmdtesting.RunCommandExitCodeStdout(
+		t,
+		testNewCommand,
+		0,
+		"\x08\x37",
+		nil,
+		strings.NewReader(payload),
+		"testdata/convert/bin_json/buf.proto",
+		"--type=buf.Foo",
+		"--from=-#format=binpb",
+		"--to=-#format=binpb",
+		"--discard-unknown",
+	)
+}
+
+func TestConvertNegativeIndent(t *testing.T) {
+	t.Parallel()
+	appcmdtesting.RunCommandExitCode(
+		t,
+		testNewCommand,
+		1,
+		nil,
+		strings.NewReader(`{}`),
+		nil,
+		nil,
+		"testdata/convert/bin_json/buf.proto",
+		"--type=buf.Foo",
+		"--from=-#format=json",
+		"--indent=-1",
+	)
+}
+
 func testNewCommand(use string) *appcmd.Command {
 	return NewCommand("convert", appext.NewBuilder("convert"))
 }
@@ -95,12 +95,15 @@ const (
 	dataFlagShortName      = "d"
 
 	// Output flags
-	outputFlagName       = "output"
-	outputFlagShortName  = "o"
-	emitDefaultsFlagName = "emit-defaults"
+	outputFlagName      = "output"
+	outputFlagShortName = "o"
 
 	verboseFlagName      = "verbose"
 	verboseFlagShortName = "v"
+
+	// Sharing flags
+	shareFlagName     = "share"
+	shareFileFlagName = "share-file"
 )
 
 // NewCommand returns a new Command.
@@ -236,11 +239,15 @@ type flags struct {
 	Data      string
 
 	// Output options
-	Output       string
-	EmitDefaults bool
+	Output     string
+	JSONOutput bufcli.JSONOutputFlags
 
 	Verbose bool
 
+	// Sharing
+	Share     bool
+	ShareFile string
+
 	// so we can inquire about which flags present on command-line
 	// TODO: ideally we'd use cobra directly instead of having the appcmd wrapper,
 	//  which prevents a lot of basic functionality by not exposing many cobra features
@@ -517,12 +524,7 @@ provided via stdin as a file descriptor set or image`,
 		"",
 		`Path to output file to create with response data. If absent, response is printed to stdout`,
 	)
-	flagSet.BoolVar(
-		&f.EmitDefaults,
-		emitDefaultsFlagName,
-		false,
-		`Emit default values for JSON-encoded responses.`,
-	)
+	bufcli.BindJSONOutputFlags(flagSet, &f.JSONOutput, 2)
 
 	flagSet.BoolVarP(
 		&f.Verbose,
@@ -531,6 +533,28 @@ provided via stdin as a file descriptor set or image`,
 		false,
 		"Turn on verbose mode",
 	)
+	flagSet.BoolVar(
+		&f.Share,
+		shareFlagName,
+		false,
+		fmt.Sprintf(`Save the composed request as a Studio request on the Buf Schema Registry, and print a
+link to it in Studio, so that others can reproduce the request. The schema, method, request
+data, and headers are saved, except for headers that contain secrets such as 'Authorization'.
+This requires a --%s flag that is a module on the Buf Schema Registry, and being logged in
+to the Buf Schema Registry. The request is still invoked after it is saved`,
+			schemaFlagName,
+		),
+	)
+	flagSet.StringVar(
+		&f.ShareFile,
+		shareFileFlagName,
+		"",
+		fmt.Sprintf(`Path to a file to write the composed request to as JSON, for sharing the request without
+the Buf Schema Registry, such as when offline. The same request information as with --%s is
+written, and any schema may be used. The request is still invoked after it is written`,
+			shareFlagName,
+		),
+	)
 }
 
 func (f *flags) validate(hasURL, isSecure bool) error {
@@ -655,7 +679,20 @@ func (f *flags) validate(hasURL, isSecure bool) error {
 		}
 	}
 
-	return nil
+	if (f.Share || f.ShareFile != "") && (f.ListServices || f.ListMethods) {
+		return fmt.Errorf(
+			"sharing flags (--%s, --%s) should not be used with --%s or --%s",
+			shareFlagName, shareFileFlagName, listServicesFlagName, listMethodsFlagName,
+		)
+	}
+	if f.Share && getShareModuleRef(f.Schemas) == nil {
+		return fmt.Errorf(
+			"--%s requires a --%s flag that is a module on the Buf Schema Registry, use --%s to write the request to a local file instead",
+			shareFlagName, schemaFlagName, shareFileFlagName,
+		)
+	}
+
+	return f.JSONOutput.Validate()
 }
 
 func (f *flags) determineCredentials(
@@ -1079,7 +1116,35 @@ func run(ctx context.Context, container appext.Container, f *flags) (err error)
 		if err != nil {
 			return err
 		}
-		invoker := bufcurl.NewInvoker(container, verbosePrinter, methodDescriptor, res, f.EmitDefaults, transport, clientOptions, urlArg, output)
+		if f.Share || f.ShareFile != "" {
+			var data []byte
+			if dataReader != nil {
+				// The request data is read fully so that it can be both shared and sent.
+				data, err = io.ReadAll(dataReader)
+				if err != nil {
+					return err
+				}
+				if err := dataReader.Close(); err != nil {
+					return err
+				}
+				dataReader = io.NopCloser(bytes.NewReader(data))
+			}
+			if err := share(ctx, container, f, baseURL, service, method, requestHeaders, string(data)); err != nil {
+				return err
+			}
+		}
+		invoker := bufcurl.NewInvoker(
+			container,
+			verbosePrinter,
+			methodDescriptor,
+			res,
+			f.JSONOutput.JSONMarshalerOptions(),
+			f.JSONOutput.WireUnmarshalerOptions(),
+			transport,
+			clientOptions,
+			urlArg,
+			output,
+		)
 		return invoker.Invoke(ctx, dataSource, dataReader, requestHeaders)
 	}
 }
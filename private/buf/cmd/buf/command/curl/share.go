@@ -0,0 +1,131 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package curl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufcurl"
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
+	"github.com/bufbuild/buf/private/gen/proto/connect/buf/alpha/registry/v1alpha1/registryv1alpha1connect"
+	registryv1alpha1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/registry/v1alpha1"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/connectclient"
+)
+
+var protocolToStudioProtocol = map[string]registryv1alpha1.StudioProtocol{
+	connect.ProtocolConnect: registryv1alpha1.StudioProtocol_STUDIO_PROTOCOL_CONNECT,
+	connect.ProtocolGRPC:    registryv1alpha1.StudioProtocol_STUDIO_PROTOCOL_GRPC,
+	connect.ProtocolGRPCWeb: registryv1alpha1.StudioProtocol_STUDIO_PROTOCOL_GRPC_WEB,
+}
+
+// share shares the composed request as a Studio request on the BSR if --share is set,
+// and writes it to a local JSON file if --share-file is set.
+func share(
+	ctx context.Context,
+	container appext.Container,
+	f *flags,
+	baseURL string,
+	service string,
+	method string,
+	requestHeaders http.Header,
+	body string,
+) error {
+	moduleRef := getShareModuleRef(f.Schemas)
+	var schema string
+	switch {
+	case moduleRef != nil:
+		schema = moduleRef.String()
+	case len(f.Schemas) > 0:
+		schema = f.Schemas[0]
+	}
+	sharedRequest := bufcurl.NewSharedRequest(schema, baseURL, service, method, f.Protocol, requestHeaders, body)
+	if f.ShareFile != "" {
+		data, err := json.MarshalIndent(sharedRequest, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.ShareFile, append(data, '\n'), 0644); err != nil {
+			return bufcurl.ErrorHasFilename(err, f.ShareFile)
+		}
+		if _, err := fmt.Fprintf(container.Stderr(), "Wrote shared request to %s\n", f.ShareFile); err != nil {
+			return err
+		}
+	}
+	if !f.Share {
+		return nil
+	}
+	// This is validated in flags.validate, but just in case.
+	if moduleRef == nil {
+		return fmt.Errorf("--%s requires a --%s flag that is a module on the Buf Schema Registry", shareFlagName, schemaFlagName)
+	}
+	clientConfig, err := bufcli.NewConnectClientConfig(container)
+	if err != nil {
+		return err
+	}
+	fullName := moduleRef.FullName()
+	studioRequestService := connectclient.Make(
+		clientConfig,
+		fullName.Registry(),
+		registryv1alpha1connect.NewStudioRequestServiceClient,
+	)
+	response, err := studioRequestService.CreateStudioRequest(
+		ctx,
+		connect.NewRequest(
+			registryv1alpha1.CreateStudioRequestRequest_builder{
+				RepositoryOwner: fullName.Owner(),
+				RepositoryName:  fullName.Name(),
+				Name:            service + "/" + method,
+				TargetBaseUrl:   sharedRequest.TargetBaseURL,
+				Service:         service,
+				Method:          method,
+				Body:            body,
+				Headers:         sharedRequest.Headers,
+				Protocol:        protocolToStudioProtocol[f.Protocol],
+			}.Build(),
+		),
+	)
+	if err != nil {
+		return err
+	}
+	studioURL := bufcurl.StudioURL(
+		fullName.Registry(),
+		fullName.Owner(),
+		fullName.Name(),
+		response.Msg.GetCreatedRequest().GetId(),
+		sharedRequest,
+	)
+	_, err = fmt.Fprintf(container.Stderr(), "Shared request: %s\n", studioURL)
+	return err
+}
+
+// getShareModuleRef returns the first schema that is a module on the BSR, which is
+// the schema used for Studio, as Studio can only load schemas from the BSR.
+//
+// Returns nil if no schema is a module on the BSR.
+func getShareModuleRef(schemas []string) bufparse.Ref {
+	for _, schema := range schemas {
+		if moduleRef, err := bufparse.ParseRef(schema); err == nil {
+			return moduleRef
+		}
+	}
+	return nil
+}
@@ -159,7 +159,13 @@ func run(
 	if err := workspaceDepManager.UpdateBufLockFile(ctx, configuredDepModuleKeys, existingRemotePluginKeys); err != nil {
 		return err
 	}
-	workspace, err := controller.GetWorkspace(ctx, dirPath, bufctl.WithIgnoreAndDisallowV1BufWorkYAMLs())
+	workspace, err := controller.GetWorkspace(
+		ctx,
+		dirPath,
+		bufctl.WithIgnoreAndDisallowV1BufWorkYAMLs(),
+		// The buf.lock file must only contain published dependencies.
+		bufctl.WithIgnoreModuleOverrides(),
+	)
 	if err != nil {
 		return err
 	}
@@ -71,7 +71,13 @@ func Prune(
 	workspaceDepManager bufworkspace.WorkspaceDepManager,
 	dirPath string,
 ) error {
-	workspace, err := controller.GetWorkspace(ctx, dirPath, bufctl.WithIgnoreAndDisallowV1BufWorkYAMLs())
+	workspace, err := controller.GetWorkspace(
+		ctx,
+		dirPath,
+		bufctl.WithIgnoreAndDisallowV1BufWorkYAMLs(),
+		// The buf.lock file must only contain published dependencies.
+		bufctl.WithIgnoreModuleOverrides(),
+	)
 	if err != nil {
 		return err
 	}
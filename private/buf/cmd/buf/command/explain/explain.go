@@ -0,0 +1,326 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package explain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"buf.build/go/bufplugin/check"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufplugin"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/bufbuild/buf/private/pkg/wasm"
+	"github.com/spf13/pflag"
+)
+
+const (
+	formatFlagName = "format"
+
+	formatText     = "text"
+	formatJSON     = "json"
+	formatMarkdown = "markdown"
+)
+
+var (
+	allFormats = []string{formatText, formatJSON, formatMarkdown}
+	// The versions to look for rules in, in order. Rules that were removed in later
+	// versions can still be explained.
+	fileVersions = []bufconfig.FileVersion{
+		bufconfig.FileVersionV2,
+		bufconfig.FileVersionV1,
+		bufconfig.FileVersionV1Beta1,
+	}
+	ruleTypeToConfigKeys = map[check.RuleType][]string{
+		check.RuleTypeLint: {
+			"lint.use",
+			"lint.except",
+			"lint.ignore",
+			"lint.ignore_only",
+			"lint.disallow_comment_ignores",
+		},
+		check.RuleTypeBreaking: {
+			"breaking.use",
+			"breaking.except",
+			"breaking.ignore",
+			"breaking.ignore_only",
+			"breaking.ignore_unstable_packages",
+			"breaking.ignore_symbols",
+		},
+	}
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <rule-id>",
+		Short: "Explain a builtin lint or breaking rule",
+		Long: `Print why a builtin lint or breaking rule exists, what triggers it, how to safely resolve failures,
+the buf.yaml keys that affect it, and examples.
+
+For example:
+
+    $ buf explain FIELD_SAME_TYPE
+
+Rules provided by plugins cannot be explained, use "buf config ls-lint-rules" or
+"buf config ls-breaking-rules" to print their purpose.`,
+		Args: appcmd.ExactArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	Format string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(
+		&f.Format,
+		formatFlagName,
+		formatText,
+		fmt.Sprintf(
+			`The format to print the explanation as. Must be one of %s`,
+			stringutil.SliceToString(allFormats),
+		),
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	var printFunc func(io.Writer, *externalRuleExplanation) error
+	switch flags.Format {
+	case formatText:
+		printFunc = printText
+	case formatJSON:
+		printFunc = printJSON
+	case formatMarkdown:
+		printFunc = printMarkdown
+	default:
+		return appcmd.NewInvalidArgumentErrorf("--%s must be one of %s", formatFlagName, strings.Join(allFormats, ", "))
+	}
+	ruleID := strings.ToUpper(container.Arg(0))
+	client, err := bufcheck.NewClient(
+		container.Logger(),
+		bufcheck.NewLocalRunnerProvider(
+			wasm.UnimplementedRuntime,
+			bufplugin.NopPluginKeyProvider,
+			bufplugin.NopPluginDataProvider,
+		),
+		bufcheck.ClientWithStderr(container.Stderr()),
+	)
+	if err != nil {
+		return err
+	}
+	rule, err := getRule(ctx, client, ruleID)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		return fmt.Errorf("unknown rule %q", ruleID)
+	}
+	return printFunc(container.Stdout(), newExternalRuleExplanation(rule))
+}
+
+// getRule returns the builtin Rule with the ID, or nil if no such Rule exists.
+func getRule(ctx context.Context, client bufcheck.Client, ruleID string) (bufcheck.Rule, error) {
+	for _, fileVersion := range fileVersions {
+		for _, ruleType := range []check.RuleType{check.RuleTypeLint, check.RuleTypeBreaking} {
+			rules, err := client.AllRules(ctx, ruleType, fileVersion)
+			if err != nil {
+				return nil, err
+			}
+			for _, rule := range rules {
+				if rule.ID() == ruleID {
+					return rule, nil
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+func printText(writer io.Writer, externalRuleExplanation *externalRuleExplanation) error {
+	var builder strings.Builder
+	_, _ = fmt.Fprintf(&builder, "%s (%s)\n\n%s\n\n", externalRuleExplanation.ID, externalRuleExplanation.Type, externalRuleExplanation.Purpose)
+	if len(externalRuleExplanation.Categories) > 0 {
+		_, _ = fmt.Fprintf(&builder, "Categories: %s\n", strings.Join(externalRuleExplanation.Categories, ", "))
+	}
+	_, _ = fmt.Fprintf(&builder, "Default: %t\n", externalRuleExplanation.Default)
+	if externalRuleExplanation.Deprecated {
+		_, _ = fmt.Fprintf(&builder, "Deprecated: %s\n", getDeprecatedString(externalRuleExplanation.Replacements))
+	}
+	if externalRuleExplanation.Rationale != "" {
+		_, _ = fmt.Fprintf(&builder, "\nRationale:\n  %s\n", externalRuleExplanation.Rationale)
+	}
+	writeTextList(&builder, "Triggers", externalRuleExplanation.Triggers)
+	writeTextList(&builder, "Remediations", externalRuleExplanation.Remediations)
+	writeTextList(&builder, "Config keys", externalRuleExplanation.ConfigKeys)
+	if len(externalRuleExplanation.Examples) > 0 {
+		builder.WriteString("\nExamples:\n")
+		for _, example := range externalRuleExplanation.Examples {
+			_, _ = fmt.Fprintf(&builder, "  %s\n\n", example.Description)
+			for _, line := range strings.Split(example.Diff, "\n") {
+				_, _ = fmt.Fprintf(&builder, "    %s\n", line)
+			}
+			builder.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(writer, strings.TrimSuffix(builder.String(), "\n")+"\n")
+	return err
+}
+
+func writeTextList(builder *strings.Builder, title string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(builder, "\n%s:\n", title)
+	for _, value := range values {
+		_, _ = fmt.Fprintf(builder, "  - %s\n", value)
+	}
+}
+
+func printJSON(writer io.Writer, externalRuleExplanation *externalRuleExplanation) error {
+	data, err := json.Marshal(externalRuleExplanation)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(writer, string(data))
+	return err
+}
+
+func printMarkdown(writer io.Writer, externalRuleExplanation *externalRuleExplanation) error {
+	var builder strings.Builder
+	_, _ = fmt.Fprintf(&builder, "# %s\n\n%s\n\n", externalRuleExplanation.ID, externalRuleExplanation.Purpose)
+	_, _ = fmt.Fprintf(&builder, "- **Type:** %s\n", externalRuleExplanation.Type)
+	if len(externalRuleExplanation.Categories) > 0 {
+		_, _ = fmt.Fprintf(&builder, "- **Categories:** %s\n", strings.Join(slicesext.Map(externalRuleExplanation.Categories, markdownCode), ", "))
+	}
+	_, _ = fmt.Fprintf(&builder, "- **Default:** %t\n", externalRuleExplanation.Default)
+	if externalRuleExplanation.Deprecated {
+		_, _ = fmt.Fprintf(&builder, "- **Deprecated:** %s\n", getDeprecatedString(slicesext.Map(externalRuleExplanation.Replacements, markdownCode)))
+	}
+	if externalRuleExplanation.Rationale != "" {
+		_, _ = fmt.Fprintf(&builder, "\n## Rationale\n\n%s\n", externalRuleExplanation.Rationale)
+	}
+	writeMarkdownList(&builder, "Triggers", externalRuleExplanation.Triggers)
+	writeMarkdownList(&builder, "Remediations", externalRuleExplanation.Remediations)
+	writeMarkdownList(&builder, "Config keys", slicesext.Map(externalRuleExplanation.ConfigKeys, markdownCode))
+	if len(externalRuleExplanation.Examples) > 0 {
+		builder.WriteString("\n## Examples\n")
+		for _, example := range externalRuleExplanation.Examples {
+			_, _ = fmt.Fprintf(&builder, "\n%s\n\n```diff\n%s\n```\n", example.Description, example.Diff)
+		}
+	}
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+func writeMarkdownList(builder *strings.Builder, title string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(builder, "\n## %s\n\n", title)
+	for _, value := range values {
+		_, _ = fmt.Fprintf(builder, "- %s\n", value)
+	}
+}
+
+func getDeprecatedString(replacements []string) string {
+	if len(replacements) == 0 {
+		return "true"
+	}
+	return "replaced by " + strings.Join(replacements, ", ")
+}
+
+func markdownCode(value string) string {
+	return "`" + value + "`"
+}
+
+type externalRuleExplanation struct {
+	ID           string                 `json:"id" yaml:"id"`
+	Type         string                 `json:"type" yaml:"type"`
+	Purpose      string                 `json:"purpose" yaml:"purpose"`
+	Categories   []string               `json:"categories" yaml:"categories"`
+	Default      bool                   `json:"default" yaml:"default"`
+	Deprecated   bool                   `json:"deprecated" yaml:"deprecated"`
+	Replacements []string               `json:"replacements" yaml:"replacements"`
+	Rationale    string                 `json:"rationale" yaml:"rationale"`
+	Triggers     []string               `json:"triggers" yaml:"triggers"`
+	Remediations []string               `json:"remediations" yaml:"remediations"`
+	ConfigKeys   []string               `json:"config_keys" yaml:"config_keys"`
+	Examples     []*externalRuleExample `json:"examples" yaml:"examples"`
+}
+
+type externalRuleExample struct {
+	Description string `json:"description" yaml:"description"`
+	Diff        string `json:"diff" yaml:"diff"`
+}
+
+func newExternalRuleExplanation(rule bufcheck.Rule) *externalRuleExplanation {
+	externalRuleExplanation := &externalRuleExplanation{
+		ID:      rule.ID(),
+		Type:    rule.Type().String(),
+		Purpose: rule.Purpose(),
+		Categories: slicesext.Map(
+			slicesext.Filter(rule.Categories(), func(category check.Category) bool { return !category.Deprecated() }),
+			check.Category.ID,
+		),
+		Default:      rule.Default(),
+		Deprecated:   rule.Deprecated(),
+		Replacements: rule.ReplacementIDs(),
+		ConfigKeys:   ruleTypeToConfigKeys[rule.Type()],
+	}
+	if ruleExplanation, ok := bufcheckserver.GetRuleExplanation(rule.Type(), rule.ID()); ok {
+		externalRuleExplanation.Rationale = ruleExplanation.Rationale
+		externalRuleExplanation.Triggers = ruleExplanation.Triggers
+		externalRuleExplanation.Remediations = ruleExplanation.Remediations
+		externalRuleExplanation.ConfigKeys = slices.Concat(ruleExplanation.ConfigKeys, externalRuleExplanation.ConfigKeys)
+		externalRuleExplanation.Examples = slicesext.Map(
+			ruleExplanation.Examples,
+			func(ruleExample *bufcheckserver.RuleExample) *externalRuleExample {
+				return &externalRuleExample{
+					Description: ruleExample.Description,
+					Diff:        ruleExample.Diff,
+				}
+			},
+		)
+	}
+	return externalRuleExplanation
+}
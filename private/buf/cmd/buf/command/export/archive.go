@@ -0,0 +1,130 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/git"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storagearchive"
+)
+
+const (
+	archiveTypeZip archiveType = iota + 1
+	archiveTypeTar
+	archiveTypeTarGz
+)
+
+// archiveType is a type of archive output.
+type archiveType int
+
+// getArchiveType returns the archiveType for the path based on its extension.
+//
+// Returns false if the path is not an archive output.
+func getArchiveType(path string) (archiveType, bool) {
+	switch {
+	case filepath.Ext(path) == ".zip":
+		return archiveTypeZip, true
+	case filepath.Ext(path) == ".tar":
+		return archiveTypeTar, true
+	case filepath.Ext(path) == ".tgz", strings.HasSuffix(path, ".tar.gz"):
+		return archiveTypeTarGz, true
+	default:
+		return 0, false
+	}
+}
+
+// getArchiveModTime returns the modification time to use for the files of an archive
+// for the value of the --archive-timestamp flag.
+func getArchiveModTime(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	archiveTimestamp string,
+) (time.Time, error) {
+	switch archiveTimestamp {
+	case "", archiveTimestampEpoch:
+		return time.Unix(0, 0).UTC(), nil
+	case archiveTimestampCommit:
+		fileInfo, err := os.Stat(input)
+		if err != nil || !fileInfo.IsDir() {
+			return time.Time{}, appcmd.NewInvalidArgumentErrorf(
+				"--%s=%s can only be used with a local directory input",
+				archiveTimestampFlagName,
+				archiveTimestampCommit,
+			)
+		}
+		return git.GetCommitTime(ctx, container, input, "HEAD")
+	}
+	if unixSeconds, err := strconv.ParseInt(archiveTimestamp, 10, 64); err == nil {
+		return time.Unix(unixSeconds, 0).UTC(), nil
+	}
+	modTime, err := time.Parse(time.RFC3339, archiveTimestamp)
+	if err != nil {
+		return time.Time{}, appcmd.NewInvalidArgumentErrorf(
+			"--%s must be %q, %q, a time in RFC 3339 format, or seconds since the Unix epoch, but was %q",
+			archiveTimestampFlagName,
+			archiveTimestampEpoch,
+			archiveTimestampCommit,
+			archiveTimestamp,
+		)
+	}
+	return modTime.UTC(), nil
+}
+
+func writeArchiveFile(
+	ctx context.Context,
+	readBucket storage.ReadBucket,
+	archiveType archiveType,
+	outFilePath string,
+	modTime time.Time,
+) (retErr error) {
+	if err := os.MkdirAll(filepath.Dir(outFilePath), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(outFilePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, file.Close())
+	}()
+	switch archiveType {
+	case archiveTypeZip:
+		return storagearchive.Zip(ctx, readBucket, file, true, storagearchive.ZipWithModTime(modTime))
+	case archiveTypeTar:
+		return storagearchive.Tar(ctx, readBucket, file, storagearchive.TarWithModTime(modTime))
+	case archiveTypeTarGz:
+		// The gzip header has no modification time or name by default, so this is reproducible as well.
+		gzipWriter := gzip.NewWriter(file)
+		defer func() {
+			retErr = errors.Join(retErr, gzipWriter.Close())
+		}()
+		return storagearchive.Tar(ctx, readBucket, gzipWriter, storagearchive.TarWithModTime(modTime))
+	default:
+		return fmt.Errorf("unknown archiveType: %v", archiveType)
+	}
+}
@@ -17,29 +17,37 @@ package export
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 
 	"github.com/bufbuild/buf/private/buf/bufcli"
 	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/bufworkspace"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
 	"github.com/bufbuild/buf/private/gen/data/datawkt"
 	"github.com/bufbuild/buf/private/pkg/app/appcmd"
 	"github.com/bufbuild/buf/private/pkg/app/appext"
 	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
 	"github.com/bufbuild/buf/private/pkg/syserror"
 	"github.com/spf13/pflag"
 )
 
 const (
-	excludeImportsFlagName  = "exclude-imports"
-	pathsFlagName           = "path"
-	outputFlagName          = "output"
-	outputFlagShortName     = "o"
-	configFlagName          = "config"
-	excludePathsFlagName    = "exclude-path"
-	disableSymlinksFlagName = "disable-symlinks"
+	excludeImportsFlagName       = "exclude-imports"
+	pathsFlagName                = "path"
+	outputFlagName               = "output"
+	outputFlagShortName          = "o"
+	configFlagName               = "config"
+	excludePathsFlagName         = "exclude-path"
+	disableSymlinksFlagName      = "disable-symlinks"
+	disableGitAttributesFlagName = "disable-git-attributes"
+	archiveTimestampFlagName     = "archive-timestamp"
+
+	archiveTimestampEpoch  = "epoch"
+	archiveTimestampCommit = "commit"
 )
 
 // NewCommand returns a new Command.
@@ -74,6 +82,11 @@ Export a specific version of a remote module to a local directory.
 Export a git repo to a local directory.
 
     $ buf export https://github.com/owner/repository.git --output=<output-dir>
+
+Export proto files in <source> to a reproducible archive, with the files timestamped
+with the time of the current git commit.
+
+    $ buf export <source> --output=<output>.tar.gz --archive-timestamp=commit
 `,
 		Args: appcmd.MaximumNArgs(1),
 		Run: builder.NewRunFunc(
@@ -86,12 +99,14 @@ Export a git repo to a local directory.
 }
 
 type flags struct {
-	ExcludeImports  bool
-	Paths           []string
-	Output          string
-	Config          string
-	ExcludePaths    []string
-	DisableSymlinks bool
+	ExcludeImports       bool
+	Paths                []string
+	Output               string
+	Config               string
+	ExcludePaths         []string
+	DisableSymlinks      bool
+	DisableGitAttributes bool
+	ArchiveTimestamp     string
 
 	// special
 	InputHashtag string
@@ -103,6 +118,7 @@ func newFlags() *flags {
 
 func (f *flags) Bind(flagSet *pflag.FlagSet) {
 	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	bufcli.BindDisableGitAttributes(flagSet, &f.DisableGitAttributes, disableGitAttributesFlagName)
 	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
 	bufcli.BindExcludeImports(flagSet, &f.ExcludeImports, excludeImportsFlagName)
 	bufcli.BindPaths(flagSet, &f.Paths, pathsFlagName)
@@ -112,9 +128,20 @@ func (f *flags) Bind(flagSet *pflag.FlagSet) {
 		outputFlagName,
 		outputFlagShortName,
 		"",
-		`The output directory for exported files`,
+		`The output directory or archive for exported files. Archives must end in .zip, .tar, .tar.gz, or .tgz`,
 	)
 	_ = appcmd.MarkFlagRequired(flagSet, outputFlagName)
+	flagSet.StringVar(
+		&f.ArchiveTimestamp,
+		archiveTimestampFlagName,
+		"",
+		fmt.Sprintf(
+			`The modification time of the files in an archive output. Either %q for the Unix epoch, %q for the time of the HEAD commit of the git repository that contains the input, or a time in RFC 3339 format or as seconds since the Unix epoch. Defaults to %q`,
+			archiveTimestampEpoch,
+			archiveTimestampCommit,
+			archiveTimestampEpoch,
+		),
+	)
 	flagSet.StringVar(
 		&f.Config,
 		configFlagName,
@@ -135,6 +162,7 @@ func run(
 	controller, err := bufcli.NewController(
 		container,
 		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithDisableGitAttributes(flags.DisableGitAttributes),
 	)
 	if err != nil {
 		return err
@@ -150,26 +178,51 @@ func run(
 	}
 	moduleReadBucket := bufmodule.ModuleSetToModuleReadBucketWithOnlyProtoFiles(workspace)
 
-	if err := os.MkdirAll(flags.Output, 0755); err != nil {
-		return err
-	}
-	var options []storageos.ProviderOption
-	if !flags.DisableSymlinks {
-		options = append(options, storageos.ProviderWithSymlinks())
+	archiveType, isArchive := getArchiveType(flags.Output)
+	if !isArchive {
+		if flags.ArchiveTimestamp != "" {
+			return appcmd.NewInvalidArgumentErrorf("--%s can only be set when --%s is an archive", archiveTimestampFlagName, outputFlagName)
+		}
+		if err := os.MkdirAll(flags.Output, 0755); err != nil {
+			return err
+		}
+		var options []storageos.ProviderOption
+		if !flags.DisableSymlinks {
+			options = append(options, storageos.ProviderWithSymlinks())
+		}
+		readWriteBucket, err := storageos.NewProvider(options...).NewReadWriteBucket(
+			flags.Output,
+			storageos.ReadWriteBucketWithSymlinksIfSupported(),
+		)
+		if err != nil {
+			return err
+		}
+		return exportFiles(ctx, controller, workspace, moduleReadBucket, readWriteBucket, flags.ExcludeImports)
 	}
-	readWriteBucket, err := storageos.NewProvider(options...).NewReadWriteBucket(
-		flags.Output,
-		storageos.ReadWriteBucketWithSymlinksIfSupported(),
-	)
+	modTime, err := getArchiveModTime(ctx, container, input, flags.ArchiveTimestamp)
 	if err != nil {
 		return err
 	}
+	readWriteBucket := storagemem.NewReadWriteBucket()
+	if err := exportFiles(ctx, controller, workspace, moduleReadBucket, readWriteBucket, flags.ExcludeImports); err != nil {
+		return err
+	}
+	return writeArchiveFile(ctx, readWriteBucket, archiveType, flags.Output, modTime)
+}
 
+func exportFiles(
+	ctx context.Context,
+	controller bufctl.Controller,
+	workspace bufworkspace.Workspace,
+	moduleReadBucket bufmodule.ModuleReadBucket,
+	readWriteBucket storage.ReadWriteBucket,
+	excludeImports bool,
+) error {
 	// In the case where we are excluding imports, we are allowing users to specify an input
 	// that may not have resolved imports (https://github.com/bufbuild/buf/issues/3002).
 	// Thus we do not need to build the image, and instead we can return the non-import files
 	// from the workspace.
-	if flags.ExcludeImports {
+	if excludeImports {
 		if err := moduleReadBucket.WalkFileInfos(
 			ctx,
 			func(fileInfo bufmodule.FileInfo) error {
@@ -193,7 +246,7 @@ func run(
 		ctx,
 		workspace,
 		bufctl.WithImageExcludeSourceInfo(true),
-		bufctl.WithImageExcludeImports(flags.ExcludeImports),
+		bufctl.WithImageExcludeImports(excludeImports),
 	)
 	if err != nil {
 		return err
@@ -15,41 +15,66 @@
 package generate
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bufbuild/buf/private/buf/bufcli"
 	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/buffreeze"
 	"github.com/bufbuild/buf/private/buf/bufgen"
 	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/app"
 	"github.com/bufbuild/buf/private/pkg/app/appcmd"
 	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/slogext"
+	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
 	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"github.com/bufbuild/buf/private/pkg/wasm"
 	"github.com/spf13/pflag"
 )
 
 const (
-	templateFlagName            = "template"
-	baseOutDirPathFlagName      = "output"
-	baseOutDirPathFlagShortName = "o"
-	deleteOutsFlagName          = "clean"
-	errorFormatFlagName         = "error-format"
-	configFlagName              = "config"
-	pathsFlagName               = "path"
-	includeImportsFlagName      = "include-imports"
-	includeWKTFlagName          = "include-wkt"
-	excludePathsFlagName        = "exclude-path"
-	disableSymlinksFlagName     = "disable-symlinks"
-	typeFlagName                = "type"
-	typeDeprecatedFlagName      = "include-types"
+	templateFlagName               = "template"
+	baseOutDirPathFlagName         = "output"
+	baseOutDirPathFlagShortName    = "o"
+	deleteOutsFlagName             = "clean"
+	errorFormatFlagName            = "error-format"
+	configFlagName                 = "config"
+	pathsFlagName                  = "path"
+	includeImportsFlagName         = "include-imports"
+	includeWKTFlagName             = "include-wkt"
+	excludePathsFlagName           = "exclude-path"
+	disableSymlinksFlagName        = "disable-symlinks"
+	disableGitAttributesFlagName   = "disable-git-attributes"
+	typeFlagName                   = "type"
+	typeDeprecatedFlagName         = "include-types"
+	dryRunFlagName                 = "dry-run"
+	noCacheFlagName                = "no-cache"
+	cacheTTLFlagName               = "cache-ttl"
+	provenanceFlagName             = "provenance"
+	frozenFlagName                 = "frozen"
+	profileFlagName                = "profile"
+	profileFormatFlagName          = "profile-format"
+	outputResponseFlagName         = "output-descriptor-response"
+	remotePluginMaxRetriesFlagName = "remote-plugin-max-retries"
+	remotePluginBackoffFlagName    = "remote-plugin-backoff"
+	remotePluginTimeoutFlagName    = "remote-plugin-timeout"
+
+	defaultCacheTTL = 24 * time.Hour
 )
 
 // NewCommand returns a new Command.
@@ -68,18 +93,28 @@ func NewCommand(
     # The valid values are v1beta1, v1 and v2.
     # Required.
     version: v2
-    # When clean is set to true, delete the directories, zip files, and/or jar files specified in the
+    # When clean is set to true, delete the directories and/or archive files specified in the
     # "out" field for all plugins before running code generation. Defaults to false.
     # Optional.
     clean: true
+    # The path to another v2 template that this template extends, relative to this template.
+    # Plugins in this template replace the plugins in the extended template with the same plugin
+    # name and "out", and all other plugins are added after the extended plugins. Managed mode
+    # "disable" and "override" rules are added after the extended rules, so they take precedence.
+    # Inputs, "clean", and managed mode "enabled" are inherited only if this template does not set them.
+    # Templates on the BSR cannot be extended yet.
+    # Optional.
+    extends: ../shared/buf.gen.yaml
     # The plugins to run.
-    # Required.
+    # Required unless the extended template has plugins.
     plugins:
         # Use the plugin hosted at buf.build/protocolbuffers/go at version v1.28.1.
         # If version is omitted, uses the latest version of the plugin.
         # One of "remote", "local" and "protoc_builtin" is required.
       - remote: buf.build/protocolbuffers/go:v1.28.1
         # The relative output directory.
+        # If this ends in .zip, .jar, .tar, .tar.gz, or .tgz, the generated files
+        # are written to an archive at this path instead.
         # Required.
         out: gen/go
         # The revision of the remote plugin to use, a sequence number that Buf
@@ -95,17 +130,70 @@ func NewCommand(
         # Whether to generate code for the well-known types.
         # Optional.
         include_wkt: false
+        # The local plugin to run instead if the remote is unavailable, for example
+        # if requests to the remote still fail after --remote-plugin-max-retries retries.
+        # This has the same form as "local", and uses the same settings as the remote plugin.
+        # Only valid for remote plugins.
+        # Optional.
+        local_fallback: protoc-gen-go
 
         # The name of a local plugin if discoverable in "${PATH}" or its path in the file system.
       - local: protoc-gen-es
         out: gen/es
         include_imports: true
         include_wkt: true
+        # Commands to run in the "out" directory after generation is complete, in order.
+        # Each command can be one string, which is split on whitespace, or a list of strings.
+        # Commands are not run with --dry-run, and cannot be used if "out" is an archive.
+        # Optional.
+        post:
+          - npx prettier --write .
+        # Only generate code for these types, and the types they depend on. Types can be
+        # fully-qualified type names or package names.
+        # Optional.
+        types:
+          - "acme.api"
+        # Do not generate code for these types, or any types nested within them, unless
+        # they are required by another type. Types can be fully-qualified type names or
+        # package names. If "types" is not set, all other types are included.
+        # Optional.
+        exclude_types:
+          - "acme.api.v1.InternalService"
+        # When clean is set to true, delete the files this plugin generated in the previous
+        # generation before generating. The generated files are tracked in a .buf.gen.manifest
+        # file in the out directory, so files that were not generated are never deleted.
+        # Cannot be used when out is an archive.
+        # Optional.
+        clean: true
+
+        # A plugin can write some of the files it generates to other outs.
+      - local: protoc-gen-go-grpc
+        out: gen/go
+        # A file matches a route if it matches all of the conditions set on the route,
+        # and is written to the "out" of the first route it matches. Files that match no
+        # route are written to the "out" of the plugin. Post commands are only run in the
+        # "out" of the plugin.
+        # Optional.
+        routes:
+          # Route the generated files with names ending in this suffix.
+          - suffix: _grpc.pb.go
+            out: gen/grpc
+          # Route the files generated for the .proto files in this package. A package
+          # ending in ".*" matches the package and all packages below it. A generated file
+          # is attributed to the .proto file it is named after, or otherwise to the package
+          # of the .proto files in its directory.
+          - package: acme.internal.*
+            out: gen/internal
+
+        # A local plugin with a path that ends in .wasm is a WebAssembly plugin compiled for WASI,
+        # and is run in buf's built-in Wasm runtime instead of being executed directly.
+      - local: path/to/protoc-gen-plugin.wasm
+        out: gen/wasm
 
         # The full invocation of a local plugin can be specified as a list.
       - local: ["go", "run", "path/to/plugin.go"]
         out: gen/plugin
-        # The generation strategy to use. There are two options:
+        # The generation strategy to use. There are three options:
         #
         # 1. "directory"
         #
@@ -129,6 +217,14 @@ func NewCommand(
         #   This is needed for certain plugins that expect all files to be given at once.
         #   This is also the only strategy for remote plugins.
         #
+        # 3. "all_with_imports"
+        #
+        #   This will result in buf making a single plugin invocation with all input files, and all
+        #   of their imports, including the well-known types, as files to generate. This is needed
+        #   for plugins that document or validate the whole graph of files, and is the same as
+        #   "all" with include_imports and include_wkt set, regardless of --include-imports
+        #   and --include-wkt.
+        #
         # If omitted, "directory" is used. Most users should not need to set this option.
         # Optional.
         strategy: directory
@@ -202,6 +298,45 @@ func NewCommand(
           value: JS_STRING
           field: foo.v1.Bar.baz
 
+          # Sets "java_multiple_files" to false only for files that match all
+          # of the conditions under "match". All conditions are optional, but at
+          # least one must be set. Option names are either the name of a field
+          # of the options message, or the full name of a custom option in
+          # parentheses. If "value" is omitted, the option only needs to be set.
+        - file_option: java_multiple_files
+          value: false
+          match:
+            # The file path, relative to its module, must match this regular expression.
+            path_regex: ^acme/.+/v1/
+            # The file must have this file option set.
+            file_option:
+              name: (acme.options.v1.legacy_java)
+              value: true
+            # At least one message in the file must have this message option set.
+            message_option:
+              name: deprecated
+            # At least one field in the file must have this field option set.
+            # Enum values are matched by name.
+            field_option:
+              name: (acme.options.v1.sensitivity)
+              value: SENSITIVITY_HIGH
+
+          # Sets the custom file option "(acme.options.v1.owner)" for all files in
+          # directory "acme/weather". The value is interpreted according to the JSON
+          # mapping of the type of the option, so it can also be a list or a map.
+          # The file that defines the custom option must be part of the input or its
+          # dependencies.
+        - file_option: (acme.options.v1.owner)
+          value: platform-team
+          path: acme/weather
+
+          # Sets the custom message option "(acme.options.v1.cache_ttl_seconds)" for
+          # all messages in the files of module "buf.build/acme/weather". Only custom
+          # message options can be set.
+        - message_option: (acme.options.v1.cache_ttl_seconds)
+          value: 60
+          module: buf.build/acme/weather
+
       # Disables managed mode under certain conditions.
       # Takes precedence over "overrides".
       # Optional.
@@ -257,6 +392,16 @@ func NewCommand(
         exclude_paths:
           - a/b/c/x.proto
           - a/b/d/y.proto
+        # Only run these plugins for this input, referenced by the value of
+        # their remote, local or protoc_builtin key.
+        # If empty, run all plugins.
+        # Optional.
+        plugins:
+          - protoc-gen-es
+        # Write the output of the plugins for this input under this directory,
+        # relative to the output base directory. Plugin outs are relative to it.
+        # Optional.
+        out: gen/weather
 
         # The URL or path to a tarball.
       - tarball: a/b/x.tar.gz
@@ -308,6 +453,25 @@ As an example, here's a typical "buf.gen.yaml" go and grpc, assuming
           - paths=source_relative
           - require_unimplemented_servers=false
 
+Values in the template may reference environment variables if the template sets
+"interpolate_env: true", for example to parameterize output directories or plugin
+options per environment:
+
+    # buf.gen.yaml
+    version: v2
+    interpolate_env: true
+    plugins:
+      - remote: buf.build/protocolbuffers/go
+        # The value of GO_PLUGIN_REVISION, or 1 if unset or empty.
+        revision: ${GO_PLUGIN_REVISION:-1}
+        # Fails if GEN_DIR is unset or empty.
+        out: ${GEN_DIR:?GEN_DIR must be set}/go
+        # Fails if GO_OPT is unset or empty.
+        opt: ${GO_OPT}
+
+Use "${VAR:-}" to default to the empty string, and "$${" to write a literal "${".
+Environment variables can only be referenced in values on a single line.
+
 By default, buf generate will look for a file of this shape named
 "buf.gen.yaml" in your current directory. This can be thought of as a template
 for the set of plugins you want to invoke.
@@ -356,6 +520,11 @@ Note that all paths must be contained within the same module. For example, if yo
 module in "proto", you cannot specify "--path proto", however "--path proto/foo" is allowed
 as "proto/foo" is contained within "proto".
 
+To see which files would be created, overwritten, or deleted without touching the filesystem,
+use the --dry-run flag. Plugins are still invoked:
+
+    $ buf generate --dry-run --clean
+
 Plugins are invoked in the order they are specified in the template, but each plugin
 has a per-directory parallel invocation, with results from each invocation combined
 before writing the result.
@@ -384,6 +553,18 @@ type flags struct {
 	IncludeWKTOverride     *bool
 	ExcludePaths           []string
 	DisableSymlinks        bool
+	DisableGitAttributes   bool
+	DryRun                 bool
+	NoCache                bool
+	CacheTTL               time.Duration
+	Provenance             string
+	Frozen                 bool
+	Profile                bool
+	ProfileFormat          string
+	OutputResponse         string
+	RemotePluginMaxRetries int
+	RemotePluginBackoff    time.Duration
+	RemotePluginTimeout    time.Duration
 	// We may be able to bind two flags to one string slice but I don't
 	// want to find out what will break if we do.
 	Types           []string
@@ -398,6 +579,7 @@ func newFlags() *flags {
 
 func (f *flags) Bind(flagSet *pflag.FlagSet) {
 	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	bufcli.BindDisableGitAttributes(flagSet, &f.DisableGitAttributes, disableGitAttributesFlagName)
 	bufcli.BindInputHashtag(flagSet, &f.InputHashtag)
 	bufcli.BindPaths(flagSet, &f.Paths, pathsFlagName)
 	bufcli.BindExcludePaths(flagSet, &f.ExcludePaths, excludePathsFlagName)
@@ -427,20 +609,93 @@ func (f *flags) Bind(flagSet *pflag.FlagSet) {
 		baseOutDirPathFlagName,
 		baseOutDirPathFlagShortName,
 		".",
-		`The base directory to generate to. This is prepended to the out directories in the generation template`,
+		`The base directory to generate to. This is prepended to the out directories in the generation template. If this ends in .zip, .jar, .tar, .tar.gz, or .tgz, all plugin outputs are written to a single archive at this path, with the out directories relative to the root of the archive`,
 	)
 	bindBoolPointer(
 		flagSet,
 		deleteOutsFlagName,
 		&f.DeleteOuts,
-		`Prior to generation, delete the directories, jar files, or zip files that the plugins will write to. Allows cleaning of existing assets without having to call rm -rf`,
+		`Prior to generation, delete the directories and archive files that the plugins will write to. Allows cleaning of existing assets without having to call rm -rf`,
+	)
+	flagSet.BoolVar(
+		&f.DryRun,
+		dryRunFlagName,
+		false,
+		`Run the plugins, but instead of writing or deleting any files, print the files that would be created, overwritten, or deleted to stdout`,
+	)
+	flagSet.BoolVar(
+		&f.NoCache,
+		noCacheFlagName,
+		false,
+		`Do not use or update the cache of responses from remote plugins. By default, the responses of remote plugins that specify a version are cached, keyed by the input, plugin, and plugin options`,
+	)
+	flagSet.DurationVar(
+		&f.CacheTTL,
+		cacheTTLFlagName,
+		defaultCacheTTL,
+		`The maximum age of cached responses from remote plugins to use. Set to 0 to use cached responses regardless of age`,
+	)
+	flagSet.IntVar(
+		&f.RemotePluginMaxRetries,
+		remotePluginMaxRetriesFlagName,
+		0,
+		`The maximum number of times to retry requests to the remotes of remote plugins that fail with transient errors, such as the remote being unavailable. If requests to a remote still fail after all retries, remote plugins with a local_fallback are run locally instead, and no further requests are made to the remote`,
+	)
+	flagSet.DurationVar(
+		&f.RemotePluginBackoff,
+		remotePluginBackoffFlagName,
+		time.Second,
+		fmt.Sprintf(`The time to wait before the first retry of a request to a remote. This is doubled after each retry. Only used if --%s is set`, remotePluginMaxRetriesFlagName),
+	)
+	flagSet.DurationVar(
+		&f.RemotePluginTimeout,
+		remotePluginTimeoutFlagName,
+		0,
+		fmt.Sprintf(`The timeout for each request to the remote of a remote plugin. Requests that time out are retried as per --%s. Set to 0 for no timeout`, remotePluginMaxRetriesFlagName),
+	)
+	flagSet.StringVar(
+		&f.Provenance,
+		provenanceFlagName,
+		"",
+		`The path to write a JSON provenance file to after generation. The file records the digests of the input modules, the plugins and their versions, options, and digests, the digests of the generated files, and the version of buf used. This path is not relative to --output`,
+	)
+	flagSet.BoolVar(
+		&f.Frozen,
+		frozenFlagName,
+		false,
+		fmt.Sprintf(
+			`Use the remote plugin versions and module input commits pinned in the %s file next to the template, as written by "buf beta freeze". Fails if the template or buf.lock file have changed since the %s file was written`,
+			buffreeze.FileName,
+			buffreeze.FileName,
+		),
+	)
+	flagSet.StringVar(
+		&f.OutputResponse,
+		outputResponseFlagName,
+		"",
+		`The path to write the CodeGeneratorResponses of all plugins to, merged into a single CodeGeneratorResponse in the binary wire format, instead of writing the generated files. The name of each generated file is the path it would have been written to. Use "-" to write to stdout. No files are deleted and no post commands are run. This path is not relative to --output`,
+	)
+	flagSet.BoolVar(
+		&f.Profile,
+		profileFlagName,
+		false,
+		`After generation, print the time spent compiling the input, executing each plugin, and writing files to stderr. Plugins are executed concurrently, so the time spent executing each plugin may add up to more than the time spent executing all plugins`,
+	)
+	flagSet.StringVar(
+		&f.ProfileFormat,
+		profileFormatFlagName,
+		bufgen.ProfileFormatText.String(),
+		fmt.Sprintf(
+			"The format to print the profile in. Must be one of %s",
+			stringutil.SliceToString(bufgen.AllProfileFormatStrings),
+		),
 	)
 	flagSet.StringVar(
 		&f.ErrorFormat,
 		errorFormatFlagName,
 		"text",
 		fmt.Sprintf(
-			"The format for build errors, printed to stderr. Must be one of %s",
+			"The format for build and plugin errors, printed to stderr. Must be one of %s. If json, the stderr of failed local plugins is included in the printed errors",
 			stringutil.SliceToString(bufanalysis.AllFormatStrings),
 		),
 	)
@@ -481,6 +736,29 @@ func run(
 		// only makes sense in the context of including imports.
 		return appcmd.NewInvalidArgumentErrorf("Cannot set --%s to true without setting --%s to true", includeWKTFlagName, includeImportsFlagName)
 	}
+	if flags.CacheTTL < 0 {
+		return appcmd.NewInvalidArgumentErrorf("--%s must not be negative", cacheTTLFlagName)
+	}
+	if flags.RemotePluginMaxRetries < 0 {
+		return appcmd.NewInvalidArgumentErrorf("--%s must not be negative", remotePluginMaxRetriesFlagName)
+	}
+	if flags.RemotePluginBackoff < 0 {
+		return appcmd.NewInvalidArgumentErrorf("--%s must not be negative", remotePluginBackoffFlagName)
+	}
+	if flags.RemotePluginTimeout < 0 {
+		return appcmd.NewInvalidArgumentErrorf("--%s must not be negative", remotePluginTimeoutFlagName)
+	}
+	if flags.OutputResponse != "" && flags.DryRun {
+		return appcmd.NewInvalidArgumentErrorf("Cannot set both --%s and --%s", outputResponseFlagName, dryRunFlagName)
+	}
+	profileFormat, err := bufgen.ParseProfileFormat(flags.ProfileFormat)
+	if err != nil {
+		return err
+	}
+	var profile bufgen.Profile
+	if flags.Profile {
+		profile = bufgen.NewProfile()
+	}
 	input, err := bufcli.GetInputValue(container, flags.InputHashtag, "")
 	if err != nil {
 		return err
@@ -494,20 +772,28 @@ func run(
 	controller, err := bufcli.NewController(
 		container,
 		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithDisableGitAttributes(flags.DisableGitAttributes),
 		bufctl.WithFileAnnotationErrorFormat(flags.ErrorFormat),
 	)
 	if err != nil {
 		return err
 	}
-	clientConfig, err := bufcli.NewConnectClientConfig(container)
+	clientConfig, err := bufcli.NewPluginConnectClientConfig(container)
 	if err != nil {
 		return err
 	}
-	bufGenYAMLFile, err := readBufGenYAMLFile(ctx, storageosProvider, flags.Template)
+	bufGenYAMLFile, err := readBufGenYAMLFile(ctx, container, storageosProvider, flags.Template)
 	if err != nil {
 		return err
 	}
-	images, err := getInputImages(
+	if flags.Frozen {
+		bufGenYAMLFile, err = pinBufGenYAMLFile(ctx, storageosProvider, flags.Template, bufGenYAMLFile)
+		if err != nil {
+			return err
+		}
+	}
+	compilationStart := time.Now()
+	images, inputConfigs, err := getInputImages(
 		ctx,
 		logger,
 		controller,
@@ -521,9 +807,18 @@ func run(
 	if err != nil {
 		return err
 	}
+	if profile != nil {
+		profile.AddCompilation(time.Since(compilationStart))
+	}
 	generateOptions := []bufgen.GenerateOption{
 		bufgen.GenerateWithBaseOutDirPath(flags.BaseOutDirPath),
 	}
+	if len(inputConfigs) > 0 {
+		generateOptions = append(
+			generateOptions,
+			bufgen.GenerateWithInputConfigs(inputConfigs),
+		)
+	}
 	if flags.DeleteOuts != nil {
 		generateOptions = append(
 			generateOptions,
@@ -542,24 +837,179 @@ func run(
 			bufgen.GenerateWithIncludeWellKnownTypesOverride(*flags.IncludeWKTOverride),
 		)
 	}
-	return bufgen.NewGenerator(
-		logger,
+	if flags.DryRun {
+		generateOptions = append(
+			generateOptions,
+			bufgen.GenerateWithDryRun(),
+		)
+	}
+	if flags.Provenance != "" {
+		generateOptions = append(
+			generateOptions,
+			bufgen.GenerateWithProvenanceFilePath(flags.Provenance, bufcli.Version),
+		)
+	}
+	hasRemotePlugin := slices.ContainsFunc(
+		bufGenYAMLFile.GenerateConfig().GeneratePluginConfigs(),
+		func(pluginConfig bufconfig.GeneratePluginConfig) bool {
+			return pluginConfig.Type() == bufconfig.GeneratePluginConfigTypeRemote
+		},
+	)
+	if hasRemotePlugin && !flags.NoCache {
+		remotePluginResponseCacheBucket, err := bufcli.NewRemotePluginResponseCacheBucket(container)
+		if err != nil {
+			return err
+		}
+		generateOptions = append(
+			generateOptions,
+			bufgen.GenerateWithRemotePluginResponseCache(remotePluginResponseCacheBucket, flags.CacheTTL),
+		)
+	}
+	if hasRemotePlugin {
+		generateOptions = append(
+			generateOptions,
+			bufgen.GenerateWithRemotePluginRetries(flags.RemotePluginMaxRetries, flags.RemotePluginBackoff),
+			bufgen.GenerateWithRemotePluginTimeout(flags.RemotePluginTimeout),
+		)
+	}
+	if profile != nil {
+		generateOptions = append(
+			generateOptions,
+			bufgen.GenerateWithProfile(profile),
+		)
+	}
+	if flags.ErrorFormat == "json" {
+		generateOptions = append(
+			generateOptions,
+			bufgen.GenerateWithCapturePluginStderr(),
+		)
+	}
+	// The response is buffered so that nothing is written if generation fails.
+	var responseBuffer *bytes.Buffer
+	if flags.OutputResponse != "" {
+		responseBuffer = bytes.NewBuffer(nil)
+		generateOptions = append(
+			generateOptions,
+			bufgen.GenerateWithResponseWriter(responseBuffer),
+		)
+	}
+	var wasmRuntime wasm.Runtime = wasm.UnimplementedRuntime
+	hasLocalWasmPlugin := slices.ContainsFunc(
+		bufGenYAMLFile.GenerateConfig().GeneratePluginConfigs(),
+		func(pluginConfig bufconfig.GeneratePluginConfig) bool {
+			path := pluginConfig.Path()
+			return len(path) > 0 && filepath.Ext(path[0]) == ".wasm"
+		},
+	)
+	if hasLocalWasmPlugin {
+		wasmRuntimeCacheDir, err := bufcli.CreateWasmRuntimeCacheDir(container)
+		if err != nil {
+			return err
+		}
+		wasmRuntime, err = wasm.NewRuntime(ctx, wasm.WithLocalCacheDir(wasmRuntimeCacheDir))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			retErr = errors.Join(retErr, wasmRuntime.Close(ctx))
+		}()
+	}
+	if err := bufgen.NewGenerator(
+		slogext.WithComponent(logger, bufctl.LogComponentPlugin),
 		storageosProvider,
 		clientConfig,
+		wasmRuntime,
 	).Generate(
 		ctx,
 		container,
 		bufGenYAMLFile.GenerateConfig(),
 		images,
 		generateOptions...,
-	)
+	); err != nil {
+		if flags.ErrorFormat == "json" {
+			return printPluginErrorsJSON(container, err)
+		}
+		return err
+	}
+	if responseBuffer != nil {
+		if err := writeOutputResponse(container, flags.OutputResponse, responseBuffer.Bytes()); err != nil {
+			return err
+		}
+	}
+	if profile != nil {
+		// The profile is printed to stderr, as stdout is reserved for the
+		// output of --dry-run.
+		return profile.Print(container.Stderr(), profileFormat)
+	}
+	return nil
+}
+
+// writeOutputResponse writes the merged CodeGeneratorResponse to the path, or to
+// stdout if the path is "-".
+func writeOutputResponse(container app.StdoutContainer, path string, data []byte) error {
+	if path == "-" {
+		_, err := container.Stdout().Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// printPluginErrorsJSON prints the PluginErrors within err to stderr as JSON, one
+// object per line.
+//
+// If err does not contain any PluginErrors, err is returned as-is.
+func printPluginErrorsJSON(container app.StderrContainer, err error) error {
+	pluginErrors := getPluginErrors(err)
+	if len(pluginErrors) == 0 {
+		return err
+	}
+	encoder := json.NewEncoder(container.Stderr())
+	for _, pluginError := range pluginErrors {
+		if err := encoder.Encode(
+			&externalPluginError{
+				Plugin:   pluginError.PluginName,
+				ExitCode: pluginError.ExitCode,
+				Stderr:   pluginError.Stderr,
+				Message:  pluginError.Err.Error(),
+			},
+		); err != nil {
+			return err
+		}
+	}
+	// The errors have already been printed.
+	return app.NewError(1, "")
+}
+
+// getPluginErrors returns all PluginErrors within err, descending into joined errors.
+func getPluginErrors(err error) []*bufgen.PluginError {
+	if joinedErr, ok := err.(interface{ Unwrap() []error }); ok {
+		var pluginErrors []*bufgen.PluginError
+		for _, err := range joinedErr.Unwrap() {
+			pluginErrors = append(pluginErrors, getPluginErrors(err)...)
+		}
+		return pluginErrors
+	}
+	pluginError := &bufgen.PluginError{}
+	if errors.As(err, &pluginError) {
+		return []*bufgen.PluginError{pluginError}
+	}
+	return nil
+}
+
+type externalPluginError struct {
+	Plugin   string `json:"plugin"`
+	ExitCode int    `json:"exit_code"`
+	Stderr   string `json:"stderr,omitempty"`
+	Message  string `json:"message"`
 }
 
 func readBufGenYAMLFile(
 	ctx context.Context,
+	container app.EnvContainer,
 	storageosProvider storageos.Provider,
 	templatePath string,
 ) (bufconfig.BufGenYAMLFile, error) {
+	envOption := bufconfig.BufGenYAMLFileWithEnvFunc(container.Env)
 	templatePathExtension := filepath.Ext(templatePath)
 	switch {
 	case templatePath == "":
@@ -567,7 +1017,13 @@ func readBufGenYAMLFile(
 		if err != nil {
 			return nil, err
 		}
-		return bufconfig.GetBufGenYAMLFileForPrefix(ctx, bucket, ".")
+		return bufconfig.GetBufGenYAMLFileForPrefix(
+			ctx,
+			bucket,
+			".",
+			bufconfig.BufGenYAMLFileWithExtendsFromOS("."),
+			envOption,
+		)
 	case templatePathExtension == ".yaml" || templatePathExtension == ".yml" || templatePathExtension == ".json":
 		// We should not read from a bucket at "." because this path can jump context.
 		configFile, err := os.Open(templatePath)
@@ -575,10 +1031,59 @@ func readBufGenYAMLFile(
 			return nil, err
 		}
 		defer configFile.Close()
-		return bufconfig.ReadBufGenYAMLFile(configFile)
+		return bufconfig.ReadBufGenYAMLFile(
+			configFile,
+			bufconfig.BufGenYAMLFileWithExtendsFromOS(filepath.Dir(templatePath)),
+			envOption,
+		)
 	default:
-		return bufconfig.ReadBufGenYAMLFile(strings.NewReader(templatePath))
+		// Templates given as data extend templates relative to the current directory.
+		return bufconfig.ReadBufGenYAMLFile(
+			strings.NewReader(templatePath),
+			bufconfig.BufGenYAMLFileWithExtendsFromOS("."),
+			envOption,
+		)
+	}
+}
+
+// pinBufGenYAMLFile pins the BufGenYAMLFile with the buf.freeze.yaml file in the
+// directory of the template, or the current directory if the template is not a file.
+//
+// The buf.lock file in the same directory is checked to not have changed.
+func pinBufGenYAMLFile(
+	ctx context.Context,
+	storageosProvider storageos.Provider,
+	templatePath string,
+	bufGenYAMLFile bufconfig.BufGenYAMLFile,
+) (bufconfig.BufGenYAMLFile, error) {
+	dirPath := "."
+	switch filepath.Ext(templatePath) {
+	case ".yaml", ".yml", ".json":
+		dirPath = filepath.Dir(templatePath)
+	}
+	bucket, err := storageosProvider.NewReadWriteBucket(dirPath, storageos.ReadWriteBucketWithSymlinksIfSupported())
+	if err != nil {
+		return nil, err
 	}
+	data, err := storage.ReadPath(ctx, bucket, buffreeze.FileName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf(`--%s is set but there is no %s file in %q, run "buf beta freeze" to create one`, frozenFlagName, buffreeze.FileName, dirPath)
+		}
+		return nil, err
+	}
+	freezeFile, err := buffreeze.ReadFile(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	bufLockFile, err := bufconfig.GetBufLockFileForPrefix(ctx, bucket, ".")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err := buffreeze.CheckBufLockFile(bufLockFile, freezeFile); err != nil {
+		return nil, err
+	}
+	return buffreeze.PinBufGenYAMLFile(bufGenYAMLFile, freezeFile)
 }
 
 func getInputImages(
@@ -591,7 +1096,7 @@ func getInputImages(
 	targetPathsOverride []string,
 	excludePathsOverride []string,
 	includeTypesOverride []string,
-) ([]bufimage.Image, error) {
+) ([]bufimage.Image, []bufconfig.InputConfig, error) {
 	// If input is specified on the command line, we use that. If input is not
 	// specified on the command line, use the default input.
 	if inputSpecified != "" || len(bufGenYAMLFile.InputConfigs()) == 0 {
@@ -614,9 +1119,10 @@ func getInputImages(
 			bufctl.WithImageTypes(includeTypes),
 		)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		return []bufimage.Image{inputImage}, nil
+		// The input on the command line is generated with all plugins.
+		return []bufimage.Image{inputImage}, nil, nil
 	}
 	var inputImages []bufimage.Image
 	for _, inputConfig := range bufGenYAMLFile.InputConfigs() {
@@ -642,11 +1148,11 @@ func getInputImages(
 			bufctl.WithImageTypes(includeTypes),
 		)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		inputImages = append(inputImages, inputImage)
 	}
-	return inputImages, nil
+	return inputImages, bufGenYAMLFile.InputConfigs(), nil
 }
 
 // TODO FUTURE: where does this belong? A flagsext package?
@@ -16,6 +16,7 @@ package generate
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -26,12 +27,15 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/bufbuild/buf/private/buf/bufcli"
 	"github.com/bufbuild/buf/private/buf/buftesting"
 	"github.com/bufbuild/buf/private/buf/cmd/buf/internal/internaltesting"
+	"github.com/bufbuild/buf/private/bufpkg/bufcas"
 	"github.com/bufbuild/buf/private/pkg/app/appcmd"
 	"github.com/bufbuild/buf/private/pkg/app/appcmd/appcmdtesting"
 	"github.com/bufbuild/buf/private/pkg/app/appext"
 	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
 	"github.com/bufbuild/buf/private/pkg/slicesext"
 	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/storage/storagearchive"
@@ -41,6 +45,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/pluginpb"
 )
 
 // TODO FUTURE: this has to change if we split up this repository
@@ -170,6 +175,830 @@ func TestGenerateV2LocalPluginBasic(t *testing.T) {
 	require.Empty(t, string(diff))
 }
 
+func TestGenerateV2LocalWasmPlugin(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	tempDirPath := t.TempDir()
+	input := filepath.Join("testdata", "v2", "local_plugin")
+
+	testRunSuccess(
+		t,
+		"--output",
+		tempDirPath,
+		"--template",
+		`version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml-wasm.wasm
+    out: gen
+`,
+		input,
+	)
+
+	expected, err := storagemem.NewReadBucket(
+		map[string][]byte{
+			filepath.Join("gen", "a", "v1", "a.top-level-type-names.yaml"): []byte(`messages:
+    - a.v1.Bar
+    - a.v1.Foo
+`),
+			filepath.Join("gen", "b", "v1", "b.top-level-type-names.yaml"): []byte(`messages:
+    - b.v1.Bar
+    - b.v1.Foo
+`),
+		},
+	)
+	require.NoError(t, err)
+	actual, err := storageos.NewProvider().NewReadWriteBucket(tempDirPath)
+	require.NoError(t, err)
+
+	diff, err := storage.DiffBytes(context.Background(), expected, actual)
+	require.NoError(t, err)
+	require.Empty(t, string(diff))
+}
+
+func TestGenerateV2LocalPluginDryRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tempDirPath := t.TempDir()
+	input := filepath.Join("testdata", "v2", "local_plugin")
+	template := filepath.Join("testdata", "v2", "local_plugin", "buf.basic.gen.yaml")
+	readWriteBucket, err := storageos.NewProvider().NewReadWriteBucket(tempDirPath)
+	require.NoError(t, err)
+	require.NoError(t, storage.PutPath(ctx, readWriteBucket, "gen/a/v1/a.top-level-type-names.yaml", []byte(`1`)))
+	require.NoError(t, storage.PutPath(ctx, readWriteBucket, "gen/foo.txt", []byte(`1`)))
+
+	testRunStdoutStderr(
+		t,
+		nil,
+		0,
+		fmt.Sprintf(
+			"overwrite %s\ncreate %s\n",
+			filepath.Join(tempDirPath, "gen", "a", "v1", "a.top-level-type-names.yaml"),
+			filepath.Join(tempDirPath, "gen", "b", "v1", "b.top-level-type-names.yaml"),
+		),
+		``,
+		"--output",
+		tempDirPath,
+		"--template",
+		template,
+		"--dry-run",
+		input,
+	)
+	testRunStdoutStderr(
+		t,
+		nil,
+		0,
+		fmt.Sprintf(
+			"overwrite %s\ncreate %s\ndelete %s\n",
+			filepath.Join(tempDirPath, "gen", "a", "v1", "a.top-level-type-names.yaml"),
+			filepath.Join(tempDirPath, "gen", "b", "v1", "b.top-level-type-names.yaml"),
+			filepath.Join(tempDirPath, "gen", "foo.txt"),
+		),
+		``,
+		"--output",
+		tempDirPath,
+		"--template",
+		template,
+		"--dry-run",
+		"--clean",
+		input,
+	)
+
+	// Nothing should have been written or deleted.
+	expected, err := storagemem.NewReadBucket(
+		map[string][]byte{
+			"gen/a/v1/a.top-level-type-names.yaml": []byte(`1`),
+			"gen/foo.txt":                          []byte(`1`),
+		},
+	)
+	require.NoError(t, err)
+	diff, err := storage.DiffBytes(ctx, expected, readWriteBucket)
+	require.NoError(t, err)
+	require.Empty(t, string(diff))
+}
+
+func TestGenerateV2LocalPluginArchiveOutput(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	input := filepath.Join("testdata", "v2", "local_plugin")
+	template := filepath.Join("testdata", "v2", "local_plugin", "buf.basic.gen.yaml")
+	expected, err := storagemem.NewReadBucket(
+		map[string][]byte{
+			"gen/a/v1/a.top-level-type-names.yaml": []byte(`messages:
+    - a.v1.Bar
+    - a.v1.Foo
+`),
+			"gen/b/v1/b.top-level-type-names.yaml": []byte(`messages:
+    - b.v1.Bar
+    - b.v1.Foo
+`),
+		},
+	)
+	require.NoError(t, err)
+	tempDirPath := t.TempDir()
+
+	tarGzPath := filepath.Join(tempDirPath, "out", "gen.tar.gz")
+	testRunStdoutStderr(
+		t,
+		nil,
+		0,
+		"create "+tarGzPath+"\n",
+		``,
+		"--output",
+		tarGzPath,
+		"--template",
+		template,
+		"--dry-run",
+		input,
+	)
+	_, err = os.Stat(tarGzPath)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	testRunSuccess(
+		t,
+		"--output",
+		tarGzPath,
+		"--template",
+		template,
+		input,
+	)
+	tarGzFile, err := os.Open(tarGzPath)
+	require.NoError(t, err)
+	defer tarGzFile.Close()
+	gzipReader, err := gzip.NewReader(tarGzFile)
+	require.NoError(t, err)
+	actual := storagemem.NewReadWriteBucket()
+	require.NoError(t, storagearchive.Untar(ctx, gzipReader, actual))
+	diff, err := storage.DiffBytes(ctx, expected, actual)
+	require.NoError(t, err)
+	require.Empty(t, string(diff))
+
+	zipPath := filepath.Join(tempDirPath, "gen.zip")
+	testRunSuccess(
+		t,
+		"--output",
+		zipPath,
+		"--template",
+		template,
+		input,
+	)
+	zipData, err := os.ReadFile(zipPath)
+	require.NoError(t, err)
+	actual = storagemem.NewReadWriteBucket()
+	require.NoError(t, storagearchive.Unzip(ctx, bytes.NewReader(zipData), int64(len(zipData)), actual))
+	diff, err = storage.DiffBytes(ctx, expected, actual)
+	require.NoError(t, err)
+	require.Empty(t, string(diff))
+}
+
+func TestGenerateV2LocalPluginPostCommands(t *testing.T) {
+	t.Parallel()
+
+	input := filepath.Join("testdata", "v2", "local_plugin")
+	template := `version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml
+    out: gen
+    post:
+      - touch post.txt
+      - [cp, post.txt, post_copy.txt]
+`
+	tempDirPath := t.TempDir()
+	testRunSuccess(
+		t,
+		"--output",
+		tempDirPath,
+		"--template",
+		template,
+		input,
+	)
+	_, err := os.Stat(filepath.Join(tempDirPath, "gen", "a", "v1", "a.top-level-type-names.yaml"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(tempDirPath, "gen", "post.txt"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(tempDirPath, "gen", "post_copy.txt"))
+	require.NoError(t, err)
+
+	dryRunTempDirPath := t.TempDir()
+	testRunSuccess(
+		t,
+		"--output",
+		dryRunTempDirPath,
+		"--template",
+		template,
+		"--dry-run",
+		input,
+	)
+	_, err = os.Stat(filepath.Join(dryRunTempDirPath, "gen", "post.txt"))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	testRunStdoutStderr(
+		t,
+		nil,
+		1,
+		``,
+		`Failure: plugin protoc-gen-top-level-type-names-yaml: post commands cannot be used with archive out gen.zip`,
+		"--output",
+		t.TempDir(),
+		"--template",
+		strings.Replace(template, "out: gen", "out: gen.zip", 1),
+		input,
+	)
+}
+
+func TestGenerateV2LocalPluginInputPluginsAndOut(t *testing.T) {
+	t.Parallel()
+
+	// protoc-gen-nonexistent is not selected by any input, and generation
+	// fails if it is run.
+	template := `version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml
+    out: gen
+  - local: protoc-gen-nonexistent
+    out: gen
+inputs:
+  - directory: testdata/v2/local_plugin
+    paths:
+      - testdata/v2/local_plugin/a
+    plugins:
+      - protoc-gen-top-level-type-names-yaml
+    out: a_out
+  - directory: testdata/v2/local_plugin
+    paths:
+      - testdata/v2/local_plugin/b
+    plugins:
+      - protoc-gen-top-level-type-names-yaml
+    out: b_out/nested
+`
+	tempDirPath := t.TempDir()
+	testRunSuccess(
+		t,
+		"--output",
+		tempDirPath,
+		"--template",
+		template,
+	)
+	_, err := os.Stat(filepath.Join(tempDirPath, "a_out", "gen", "a", "v1", "a.top-level-type-names.yaml"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(tempDirPath, "a_out", "gen", "b"))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = os.Stat(filepath.Join(tempDirPath, "b_out", "nested", "gen", "b", "v1", "b.top-level-type-names.yaml"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(tempDirPath, "b_out", "nested", "gen", "a"))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	testRunStdoutStderr(
+		t,
+		nil,
+		1,
+		``,
+		`Failure: decode config file: input testdata/v2/local_plugin: plugin "protoc-gen-other" is not configured in plugins`,
+		"--output",
+		t.TempDir(),
+		"--template",
+		strings.Replace(template, "    plugins:\n      - protoc-gen-top-level-type-names-yaml\n    out: a_out", "    plugins:\n      - protoc-gen-other\n    out: a_out", 1),
+	)
+}
+
+func TestGenerateV2LocalPluginPerPluginTypes(t *testing.T) {
+	t.Parallel()
+
+	tempDirPath := t.TempDir()
+	testRunSuccess(
+		t,
+		"--output",
+		tempDirPath,
+		"--template",
+		`version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml
+    out: types
+    types:
+      - a.v1.Foo
+  - local: protoc-gen-top-level-type-names-yaml
+    out: exclude_types
+    exclude_types:
+      - a.v1
+      - b.v1.Bar
+`,
+		filepath.Join("testdata", "v2", "local_plugin"),
+	)
+	expected, err := storagemem.NewReadBucket(map[string][]byte{
+		filepath.Join("types", "a", "v1", "a.top-level-type-names.yaml"): []byte(`messages:
+    - a.v1.Foo
+`),
+		filepath.Join("exclude_types", "b", "v1", "b.top-level-type-names.yaml"): []byte(`messages:
+    - b.v1.Foo
+`),
+	})
+	require.NoError(t, err)
+	actual, err := storageos.NewProvider().NewReadWriteBucket(tempDirPath)
+	require.NoError(t, err)
+	diff, err := storage.DiffBytes(context.Background(), expected, actual)
+	require.NoError(t, err)
+	require.Empty(t, string(diff))
+
+	testRunStdoutStderr(
+		t,
+		nil,
+		1,
+		``,
+		`Failure: plugin protoc-gen-top-level-type-names-yaml: excluding type "a.v1.Missing": not found`,
+		"--output",
+		t.TempDir(),
+		"--template",
+		`version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml
+    out: gen
+    exclude_types:
+      - a.v1.Missing
+`,
+		filepath.Join("testdata", "v2", "local_plugin"),
+	)
+}
+
+func TestGenerateV2LocalPluginRoutes(t *testing.T) {
+	t.Parallel()
+
+	tempDirPath := t.TempDir()
+	testRunSuccess(
+		t,
+		"--output",
+		tempDirPath,
+		"--template",
+		`version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml
+    out: gen
+    routes:
+      - package: b.*
+        out: gen_b
+      - suffix: a.top-level-type-names.yaml
+        package: c.v1
+        out: gen_unused
+      - suffix: a.top-level-type-names.yaml
+        out: gen_a
+`,
+		filepath.Join("testdata", "v2", "local_plugin"),
+	)
+	expected, err := storagemem.NewReadBucket(map[string][]byte{
+		filepath.Join("gen_a", "a", "v1", "a.top-level-type-names.yaml"): []byte(`messages:
+    - a.v1.Bar
+    - a.v1.Foo
+`),
+		filepath.Join("gen_b", "b", "v1", "b.top-level-type-names.yaml"): []byte(`messages:
+    - b.v1.Bar
+    - b.v1.Foo
+`),
+	})
+	require.NoError(t, err)
+	actual, err := storageos.NewProvider().NewReadWriteBucket(tempDirPath)
+	require.NoError(t, err)
+	diff, err := storage.DiffBytes(context.Background(), expected, actual)
+	require.NoError(t, err)
+	require.Empty(t, string(diff))
+}
+
+func TestGenerateV1LocalPluginPerPluginIncludeImports(t *testing.T) {
+	t.Parallel()
+
+	tempDirPath := t.TempDir()
+	testRunSuccess(
+		t,
+		"--output",
+		tempDirPath,
+		"--template",
+		`version: v1
+plugins:
+  - plugin: top-level-type-names-yaml
+    out: none
+  - plugin: top-level-type-names-yaml
+    out: imports
+    include_imports: true
+  - plugin: top-level-type-names-yaml
+    out: wkt
+    include_imports: true
+    include_wkt: true
+`,
+		filepath.Join("testdata", "include_imports"),
+		"--path",
+		filepath.Join("testdata", "include_imports", "a"),
+	)
+	aData := []byte(`messages:
+    - a.v1.Foo
+`)
+	bData := []byte(`messages:
+    - b.v1.Bar
+`)
+	expected, err := storagemem.NewReadBucket(map[string][]byte{
+		filepath.Join("none", "a", "v1", "a.top-level-type-names.yaml"):    aData,
+		filepath.Join("imports", "a", "v1", "a.top-level-type-names.yaml"): aData,
+		filepath.Join("imports", "b", "v1", "b.top-level-type-names.yaml"): bData,
+		filepath.Join("wkt", "a", "v1", "a.top-level-type-names.yaml"):     aData,
+		filepath.Join("wkt", "b", "v1", "b.top-level-type-names.yaml"):     bData,
+		filepath.Join("wkt", "google", "protobuf", "empty.top-level-type-names.yaml"): []byte(`messages:
+    - google.protobuf.Empty
+`),
+	})
+	require.NoError(t, err)
+	actual, err := storageos.NewProvider().NewReadWriteBucket(tempDirPath)
+	require.NoError(t, err)
+	diff, err := storage.DiffBytes(context.Background(), expected, actual)
+	require.NoError(t, err)
+	require.Empty(t, string(diff))
+}
+
+func TestGenerateV2LocalPluginStrategyAllWithImports(t *testing.T) {
+	t.Parallel()
+
+	tempDirPath := t.TempDir()
+	testRunSuccess(
+		t,
+		"--output",
+		tempDirPath,
+		"--template",
+		`version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml
+    out: module
+  - local: protoc-gen-top-level-type-names-yaml
+    out: graph
+    strategy: all_with_imports
+`,
+		filepath.Join("testdata", "include_imports"),
+		"--path",
+		filepath.Join("testdata", "include_imports", "a"),
+		// The strategy takes precedence over the flag.
+		"--include-imports=false",
+	)
+	aData := []byte(`messages:
+    - a.v1.Foo
+`)
+	expected, err := storagemem.NewReadBucket(map[string][]byte{
+		filepath.Join("module", "a", "v1", "a.top-level-type-names.yaml"): aData,
+		filepath.Join("graph", "a", "v1", "a.top-level-type-names.yaml"):  aData,
+		filepath.Join("graph", "b", "v1", "b.top-level-type-names.yaml"): []byte(`messages:
+    - b.v1.Bar
+`),
+		filepath.Join("graph", "google", "protobuf", "empty.top-level-type-names.yaml"): []byte(`messages:
+    - google.protobuf.Empty
+`),
+	})
+	require.NoError(t, err)
+	actual, err := storageos.NewProvider().NewReadWriteBucket(tempDirPath)
+	require.NoError(t, err)
+	diff, err := storage.DiffBytes(context.Background(), expected, actual)
+	require.NoError(t, err)
+	require.Empty(t, string(diff))
+}
+
+func TestGenerateV2LocalPluginOutputResponse(t *testing.T) {
+	t.Parallel()
+
+	tempDirPath := t.TempDir()
+	responseFilePath := filepath.Join(t.TempDir(), "response.binpb")
+	testRunSuccess(
+		t,
+		"--output",
+		tempDirPath,
+		"--template",
+		`version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml
+    out: gen
+    routes:
+      - package: b.v1
+        out: gen_b
+`,
+		"--output-descriptor-response",
+		responseFilePath,
+		filepath.Join("testdata", "v2", "local_plugin"),
+	)
+	// Nothing is written to the plugin outs.
+	entries, err := os.ReadDir(tempDirPath)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	data, err := os.ReadFile(responseFilePath)
+	require.NoError(t, err)
+	response := &pluginpb.CodeGeneratorResponse{}
+	require.NoError(t, protoencoding.NewWireUnmarshaler(nil).Unmarshal(data, response))
+	nameToContent := make(map[string]string)
+	for _, file := range response.GetFile() {
+		nameToContent[file.GetName()] = file.GetContent()
+	}
+	assert.Equal(
+		t,
+		map[string]string{
+			normalpath.Join(normalpath.Normalize(tempDirPath), "gen", "a", "v1", "a.top-level-type-names.yaml"): `messages:
+    - a.v1.Bar
+    - a.v1.Foo
+`,
+			normalpath.Join(normalpath.Normalize(tempDirPath), "gen_b", "b", "v1", "b.top-level-type-names.yaml"): `messages:
+    - b.v1.Bar
+    - b.v1.Foo
+`,
+		},
+		nameToContent,
+	)
+
+	appcmdtesting.RunCommandExitCodeStderrContains(
+		t,
+		func(name string) *appcmd.Command {
+			return NewCommand(
+				name,
+				appext.NewBuilder(name),
+			)
+		},
+		1,
+		[]string{"Cannot set both --output-descriptor-response and --dry-run"},
+		internaltesting.NewEnvFunc(t),
+		nil,
+		"--template",
+		filepath.Join("testdata", "v2", "local_plugin", "buf.basic.gen.yaml"),
+		"--output-descriptor-response",
+		"-",
+		"--dry-run",
+		filepath.Join("testdata", "v2", "local_plugin"),
+	)
+}
+
+func TestGenerateV2LocalPluginClean(t *testing.T) {
+	t.Parallel()
+
+	tempDirPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDirPath, "gen"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDirPath, "gen", "handwritten.txt"), []byte("handwritten\n"), 0600))
+	testRunSuccess(
+		t,
+		"--output",
+		tempDirPath,
+		"--template",
+		`version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml
+    out: gen
+    types:
+      - a.v1.Foo
+    clean: true
+`,
+		filepath.Join("testdata", "v2", "local_plugin"),
+	)
+	expected, err := storagemem.NewReadBucket(map[string][]byte{
+		filepath.Join("gen", "handwritten.txt"): []byte("handwritten\n"),
+		filepath.Join("gen", ".buf.gen.manifest"): []byte(`a/v1/a.top-level-type-names.yaml
+`),
+		filepath.Join("gen", "a", "v1", "a.top-level-type-names.yaml"): []byte(`messages:
+    - a.v1.Foo
+`),
+	})
+	require.NoError(t, err)
+	actual, err := storageos.NewProvider().NewReadWriteBucket(tempDirPath)
+	require.NoError(t, err)
+	diff, err := storage.DiffBytes(context.Background(), expected, actual)
+	require.NoError(t, err)
+	require.Empty(t, string(diff))
+
+	// The file generated for a.v1.Foo is stale after restricting the plugin to
+	// b.v1.Bar, and is deleted. The handwritten file is kept.
+	testRunSuccess(
+		t,
+		"--output",
+		tempDirPath,
+		"--template",
+		`version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml
+    out: gen
+    types:
+      - b.v1.Bar
+    clean: true
+`,
+		filepath.Join("testdata", "v2", "local_plugin"),
+	)
+	expected, err = storagemem.NewReadBucket(map[string][]byte{
+		filepath.Join("gen", "handwritten.txt"): []byte("handwritten\n"),
+		filepath.Join("gen", ".buf.gen.manifest"): []byte(`b/v1/b.top-level-type-names.yaml
+`),
+		filepath.Join("gen", "b", "v1", "b.top-level-type-names.yaml"): []byte(`messages:
+    - b.v1.Bar
+`),
+	})
+	require.NoError(t, err)
+	diff, err = storage.DiffBytes(context.Background(), expected, actual)
+	require.NoError(t, err)
+	require.Empty(t, string(diff))
+	_, err = os.Stat(filepath.Join(tempDirPath, "gen", "a"))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	testRunStdoutStderr(
+		t,
+		nil,
+		1,
+		``,
+		`Failure: plugin protoc-gen-top-level-type-names-yaml: clean cannot be used with archive out gen.zip, archives are always overwritten`,
+		"--output",
+		t.TempDir(),
+		"--template",
+		`version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml
+    out: gen.zip
+    clean: true
+`,
+		filepath.Join("testdata", "v2", "local_plugin"),
+	)
+}
+
+func TestGenerateV2LocalPluginProfile(t *testing.T) {
+	t.Parallel()
+
+	type profile struct {
+		CompilationMs     float64 `json:"compilation_ms"`
+		PluginExecutionMs float64 `json:"plugin_execution_ms"`
+		WritingMs         float64 `json:"writing_ms"`
+		TotalMs           float64 `json:"total_ms"`
+		Plugins           []struct {
+			Name        string  `json:"name"`
+			Out         string  `json:"out"`
+			Type        string  `json:"type"`
+			Invocations int     `json:"invocations"`
+			DurationMs  float64 `json:"duration_ms"`
+		} `json:"plugins"`
+	}
+	stderr := bytes.NewBuffer(nil)
+	appcmdtesting.RunCommandExitCode(
+		t,
+		func(name string) *appcmd.Command {
+			return NewCommand(
+				name,
+				appext.NewBuilder(name),
+			)
+		},
+		0,
+		internaltesting.NewEnvFunc(t),
+		nil,
+		nil,
+		stderr,
+		"--output",
+		t.TempDir(),
+		"--profile",
+		"--profile-format",
+		"json",
+		"--template",
+		`version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml
+    out: gen/a
+  - local: protoc-gen-top-level-type-names-yaml
+    out: gen/b
+    strategy: all
+`,
+		filepath.Join("testdata", "v2", "local_plugin"),
+	)
+	var actual profile
+	require.NoError(t, json.Unmarshal(stderr.Bytes(), &actual))
+	assert.Greater(t, actual.CompilationMs, float64(0))
+	assert.Greater(t, actual.PluginExecutionMs, float64(0))
+	assert.GreaterOrEqual(t, actual.TotalMs, actual.CompilationMs+actual.PluginExecutionMs+actual.WritingMs)
+	require.Len(t, actual.Plugins, 2)
+	for i, out := range []string{"gen/a", "gen/b"} {
+		plugin := actual.Plugins[i]
+		assert.Equal(t, "protoc-gen-top-level-type-names-yaml", plugin.Name)
+		assert.Equal(t, out, plugin.Out)
+		assert.Equal(t, "local", plugin.Type)
+		assert.Equal(t, 1, plugin.Invocations)
+		assert.Greater(t, plugin.DurationMs, float64(0))
+	}
+
+	testRunStdoutStderr(
+		t,
+		nil,
+		1,
+		``,
+		`Failure: unknown profile format: "yaml"`,
+		"--profile",
+		"--profile-format",
+		"yaml",
+		filepath.Join("testdata", "v2", "local_plugin"),
+	)
+}
+
+func TestGenerateV2LocalPluginProvenance(t *testing.T) {
+	t.Parallel()
+
+	input := filepath.Join("testdata", "v2", "local_plugin")
+	template := `version: v2
+plugins:
+  - local: protoc-gen-top-level-type-names-yaml
+    out: gen
+    opt: foo=bar
+`
+	type provenance struct {
+		BufVersion string `json:"buf_version"`
+		Inputs     []struct {
+			Digest  string `json:"digest"`
+			Modules []struct {
+				Digest string `json:"digest"`
+			} `json:"modules"`
+		} `json:"inputs"`
+		Plugins []struct {
+			Name  string `json:"name"`
+			Type  string `json:"type"`
+			Opt   string `json:"opt"`
+			Out   string `json:"out"`
+			Files []struct {
+				Path   string `json:"path"`
+				Digest string `json:"digest"`
+			} `json:"files"`
+		} `json:"plugins"`
+	}
+	tempDirPath := t.TempDir()
+	provenancePath := filepath.Join(tempDirPath, "provenance", "buf.provenance.json")
+	testRunSuccess(
+		t,
+		"--output",
+		tempDirPath,
+		"--template",
+		template,
+		"--provenance",
+		provenancePath,
+		input,
+	)
+	data, err := os.ReadFile(provenancePath)
+	require.NoError(t, err)
+	var actual provenance
+	require.NoError(t, json.Unmarshal(data, &actual))
+	assert.Equal(t, bufcli.Version, actual.BufVersion)
+	require.Len(t, actual.Inputs, 1)
+	assert.True(t, strings.HasPrefix(actual.Inputs[0].Digest, "shake256:"))
+	require.Len(t, actual.Inputs[0].Modules, 1)
+	assert.True(t, strings.HasPrefix(actual.Inputs[0].Modules[0].Digest, "shake256:"))
+	require.Len(t, actual.Plugins, 1)
+	plugin := actual.Plugins[0]
+	assert.Equal(t, "protoc-gen-top-level-type-names-yaml", plugin.Name)
+	assert.Equal(t, "local", plugin.Type)
+	assert.Equal(t, "foo=bar", plugin.Opt)
+	assert.Equal(t, "gen", plugin.Out)
+	require.Len(t, plugin.Files, 2)
+	for _, file := range plugin.Files {
+		content, err := os.ReadFile(filepath.Join(tempDirPath, "gen", filepath.FromSlash(file.Path)))
+		require.NoError(t, err)
+		digest, err := bufcas.NewDigestForContent(bytes.NewReader(content))
+		require.NoError(t, err)
+		assert.Equal(t, digest.String(), file.Digest)
+	}
+
+	// Generating the same input with the same plugins results in the same provenance file.
+	secondTempDirPath := t.TempDir()
+	secondProvenancePath := filepath.Join(secondTempDirPath, "buf.provenance.json")
+	testRunSuccess(
+		t,
+		"--output",
+		secondTempDirPath,
+		"--template",
+		template,
+		"--provenance",
+		secondProvenancePath,
+		input,
+	)
+	secondData, err := os.ReadFile(secondProvenancePath)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(secondData))
+
+	dryRunTempDirPath := t.TempDir()
+	testRunStdoutStderr(
+		t,
+		nil,
+		0,
+		fmt.Sprintf(
+			`
+			create %s
+			create %s
+			create %s
+			`,
+			filepath.Join(dryRunTempDirPath, "buf.provenance.json"),
+			filepath.Join(dryRunTempDirPath, "gen", "a", "v1", "a.top-level-type-names.yaml"),
+			filepath.Join(dryRunTempDirPath, "gen", "b", "v1", "b.top-level-type-names.yaml"),
+		),
+		``,
+		"--output",
+		dryRunTempDirPath,
+		"--template",
+		template,
+		"--provenance",
+		filepath.Join(dryRunTempDirPath, "buf.provenance.json"),
+		"--dry-run",
+		input,
+	)
+	_, err = os.Stat(filepath.Join(dryRunTempDirPath, "buf.provenance.json"))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
 func TestGenerateV2LocalPluginTypes(t *testing.T) {
 	t.Parallel()
 	testRunTypeArgs := func(t *testing.T, expect map[string][]byte, args ...string) {
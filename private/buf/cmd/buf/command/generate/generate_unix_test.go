@@ -22,6 +22,10 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/bufbuild/buf/private/buf/cmd/buf/internal/internaltesting"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd/appcmdtesting"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
 	"github.com/stretchr/testify/require"
 )
 
@@ -175,3 +179,33 @@ func TestWorkspaceGenerateWithExcludeAndTargetPaths(t *testing.T) {
 		filepath.Join("testdata", "workspace"),
 	)
 }
+
+func TestGenerateV2LocalPluginErrorFormatJSON(t *testing.T) {
+	t.Parallel()
+	template := `
+version: v2
+plugins:
+  - local: ["sh", "-c", "echo 'something went wrong' >&2; exit 3"]
+    out: gen
+`
+	appcmdtesting.RunCommandExitCodeStderr(
+		t,
+		func(name string) *appcmd.Command {
+			return NewCommand(
+				name,
+				appext.NewBuilder(name),
+			)
+		},
+		1,
+		`{"plugin":"sh -c echo 'something went wrong' \u003e\u00262; exit 3","exit_code":3,"stderr":"something went wrong\n","message":"exit status 3"}`,
+		internaltesting.NewEnvFunc(t),
+		nil,
+		filepath.Join("testdata", "v2", "simple"),
+		"--template",
+		template,
+		"--error-format",
+		"json",
+		"-o",
+		t.TempDir(),
+	)
+}
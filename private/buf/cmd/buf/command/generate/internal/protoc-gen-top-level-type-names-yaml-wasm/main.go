@@ -0,0 +1,102 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main implements the same plugin as protoc-gen-top-level-type-names-yaml
+// without depending on protoplugin, so that it can be compiled to Wasm with
+// GOOS=wasip1 GOARCH=wasm.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+	"gopkg.in/yaml.v3"
+)
+
+const fileExt = ".top-level-type-names.yaml"
+
+func main() {
+	if err := run(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+	request := &pluginpb.CodeGeneratorRequest{}
+	if err := proto.Unmarshal(data, request); err != nil {
+		return err
+	}
+	response := &pluginpb.CodeGeneratorResponse{}
+	for _, fileDescriptorProto := range request.GetProtoFile() {
+		if !slices.Contains(request.GetFileToGenerate(), fileDescriptorProto.GetName()) {
+			continue
+		}
+		externalFile := &externalFile{}
+		for _, enumDescriptorProto := range fileDescriptorProto.GetEnumType() {
+			externalFile.Enums = append(externalFile.Enums, fullName(fileDescriptorProto.GetPackage(), enumDescriptorProto.GetName()))
+		}
+		for _, messageDescriptorProto := range fileDescriptorProto.GetMessageType() {
+			externalFile.Messages = append(externalFile.Messages, fullName(fileDescriptorProto.GetPackage(), messageDescriptorProto.GetName()))
+		}
+		for _, serviceDescriptorProto := range fileDescriptorProto.GetService() {
+			externalFile.Services = append(externalFile.Services, fullName(fileDescriptorProto.GetPackage(), serviceDescriptorProto.GetName()))
+		}
+		sort.Strings(externalFile.Enums)
+		sort.Strings(externalFile.Messages)
+		sort.Strings(externalFile.Services)
+		data, err := yaml.Marshal(externalFile)
+		if err != nil {
+			return err
+		}
+		path := fileDescriptorProto.GetName()
+		response.File = append(
+			response.File,
+			&pluginpb.CodeGeneratorResponse_File{
+				Name:    proto.String(strings.TrimSuffix(path, filepath.Ext(filepath.FromSlash(path))) + fileExt),
+				Content: proto.String(string(data)),
+			},
+		)
+	}
+	data, err = proto.Marshal(response)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func fullName(packageName string, name string) string {
+	if packageName == "" {
+		return name
+	}
+	return packageName + "." + name
+}
+
+type externalFile struct {
+	Enums    []string `json:"enums,omitempty" yaml:"enums,omitempty"`
+	Messages []string `json:"messages,omitempty" yaml:"messages,omitempty"`
+	Services []string `json:"services,omitempty" yaml:"services,omitempty"`
+}
@@ -16,27 +16,57 @@ package lint
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"time"
 
+	"buf.build/go/bufplugin/check"
+	"github.com/bufbuild/buf/private/buf/bufchanged"
 	"github.com/bufbuild/buf/private/buf/bufcli"
 	"github.com/bufbuild/buf/private/buf/bufctl"
+	"github.com/bufbuild/buf/private/buf/buffetch"
+	"github.com/bufbuild/buf/private/buf/buflintfix"
 	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufbaseline"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufextension"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufreserved"
+	"github.com/bufbuild/buf/private/pkg/app"
 	"github.com/bufbuild/buf/private/pkg/app/appcmd"
 	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/stringutil"
 	"github.com/bufbuild/buf/private/pkg/wasm"
 	"github.com/spf13/pflag"
 )
 
 const (
-	errorFormatFlagName     = "error-format"
-	configFlagName          = "config"
-	pathsFlagName           = "path"
-	excludePathsFlagName    = "exclude-path"
-	disableSymlinksFlagName = "disable-symlinks"
+	errorFormatFlagName          = "error-format"
+	configFlagName               = "config"
+	pathsFlagName                = "path"
+	excludePathsFlagName         = "exclude-path"
+	disableSymlinksFlagName      = "disable-symlinks"
+	disableGitAttributesFlagName = "disable-git-attributes"
+	reservedRegistryFlagName     = "reserved-registry"
+	extensionRegistryFlagName    = "extension-registry"
+	fixFlagName                  = "fix"
+	diffFlagName                 = "diff"
+	baselineFlagName             = "baseline"
+	writeBaselineFlagName        = "write-baseline"
+	onlyChangedLinesFlagName     = "only-changed-lines"
+	onlyChangedFilesFlagName     = "only-changed-files"
+	maxWarningsFlagName          = "max-warnings"
+	listIgnoresFlagName          = "list-ignores"
+	noCacheFlagName              = "no-cache"
+	suggestedEditsFlagName       = "suggested-edits"
+	pathHintFlagName             = "path-hint"
+	summaryFlagName              = "summary"
 )
 
 // NewCommand returns a new Command.
@@ -48,8 +78,80 @@ func NewCommand(
 	return &appcmd.Command{
 		Use:   name + " <input>",
 		Short: "Run linting on Protobuf files",
-		Long:  bufcli.GetInputLong(`the source, module, or Image to lint`),
-		Args:  appcmd.MaximumNArgs(1),
+		Long: bufcli.GetInputLong(`the source, module, or Image to lint`) + `
+
+Violations of rules with mechanical fixes can be fixed in-place with --fix, and the fixes
+can be previewed as a diff with --diff. The input must be a directory or proto file to use
+--fix or --diff. The rules with fixes are ` + stringutil.SliceToHumanString(buflintfix.AllFixableRuleIDs) + `.
+
+Fixes that rename fields, enum values, or packages are breaking changes for generated code
+and for the JSON and text formats. Fixes are not applied if a new name would conflict with an
+existing name, or if the old name may be referenced from an option value. Packages are
+renamed by adding the suffix ".v1", and references in the input are updated, but files are
+not moved to directories that match the new package.
+
+Existing violations can be recorded in a baseline file with --write-baseline, so that
+subsequent runs only report new violations. This allows adopting lint rules in large
+repositories without fixing all existing violations first. The baseline file is
+` + bufbaseline.DefaultFileName + ` in the directory of the workspace or module of the input,
+unless set with --baseline, and is read by subsequent runs if it exists. Violations are
+recorded by file, rule, and message, so editing a file does not invalidate the baseline.
+To shrink the baseline as violations are fixed, run with --write-baseline again.
+
+Violations can also be limited to the lines changed since a git ref with --only-changed-lines,
+so that changes do not add violations without having to record the existing violations:
+
+    $ buf lint --only-changed-lines origin/main
+
+The changes are the changes since the merge base of the ref and HEAD, including uncommitted
+and untracked files, that is the changes that a pull request against the ref would contain.
+A violation is reported if any of its lines were added or modified, or if lines were removed
+within it, such as for a field removed from a message. All lines of added or renamed files
+are changed.
+
+To report all violations in the files changed since a git ref, use --only-changed-files instead:
+
+    $ buf lint --only-changed-files origin/main
+
+The whole input is still built, so that violations that depend on other files are correct.
+
+Rules and categories can be configured to produce warnings instead of errors with the warn key
+of the lint configuration in buf.yaml, so that rules can be adopted gradually:
+
+    lint:
+      use:
+        - STANDARD
+      warn:
+        - FIELD_LOWER_SNAKE_CASE
+
+Warnings are printed, but do not fail the command, unless there are more warnings than set
+with --max-warnings.
+
+Comment ignores such as "// buf:lint:ignore FIELD_LOWER_SNAKE_CASE" can be required to include
+a justification after the rule ID with the require_comment_ignore_justification key of the lint
+configuration in buf.yaml. Comment ignores can also include the last day on which they take
+effect, so that they can be revisited:
+
+    // buf:lint:ignore FIELD_LOWER_SNAKE_CASE required by legacy clients expires=2026-12-31
+
+The comment ignores that are in effect can be listed with --list-ignores, which lists them
+instead of linting.
+
+The results of each module are cached, so that modules that did not change since the last run
+are not linted again. The results are keyed by the contents of the module and its dependencies,
+the lint configuration, the plugins, and the version of buf. Results are not cached if a remote
+plugin is configured. Use --no-cache to neither use nor update the cache.
+
+A single .proto file can be read from stdin with the input "-" and --path-hint, which sets the
+path of the file. The file is linted as if it were at this path, and its imports are resolved
+against the enclosing workspace or module. This allows editors to lint unsaved files:
+
+    $ buf lint - --path-hint proto/acme/pet/v1/pet.proto < pet.proto
+
+When enabling lint on an existing codebase, --summary prints the number of violations of each
+rule in each file, followed by the totals for each rule, instead of the violations themselves.
+The exit code is the same as without --summary.`,
+		Args: appcmd.MaximumNArgs(1),
 		Run: builder.NewRunFunc(
 			func(ctx context.Context, container appext.Container) error {
 				return run(ctx, container, flags)
@@ -60,11 +162,26 @@ func NewCommand(
 }
 
 type flags struct {
-	ErrorFormat     string
-	Config          string
-	Paths           []string
-	ExcludePaths    []string
-	DisableSymlinks bool
+	ErrorFormat          string
+	Config               string
+	Paths                []string
+	ExcludePaths         []string
+	DisableSymlinks      bool
+	DisableGitAttributes bool
+	ReservedRegistry     string
+	ExtensionRegistry    string
+	Fix                  bool
+	Diff                 bool
+	Baseline             string
+	WriteBaseline        bool
+	OnlyChangedLines     string
+	OnlyChangedFiles     string
+	MaxWarnings          int
+	ListIgnores          bool
+	NoCache              bool
+	SuggestedEdits       bool
+	PathHint             string
+	Summary              bool
 	// special
 	InputHashtag string
 }
@@ -78,6 +195,12 @@ func (f *flags) Bind(flagSet *pflag.FlagSet) {
 	bufcli.BindPaths(flagSet, &f.Paths, pathsFlagName)
 	bufcli.BindExcludePaths(flagSet, &f.ExcludePaths, excludePathsFlagName)
 	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	bufcli.BindDisableGitAttributes(flagSet, &f.DisableGitAttributes, disableGitAttributesFlagName)
+	bufcli.BindReservedRegistry(flagSet, &f.ReservedRegistry, reservedRegistryFlagName)
+	bufcli.BindExtensionRegistry(flagSet, &f.ExtensionRegistry, extensionRegistryFlagName)
+	bufcli.BindBaseline(flagSet, &f.Baseline, baselineFlagName)
+	bufcli.BindOnlyChangedLines(flagSet, &f.OnlyChangedLines, onlyChangedLinesFlagName)
+	bufcli.BindOnlyChangedFiles(flagSet, &f.OnlyChangedFiles, onlyChangedFilesFlagName)
 	flagSet.StringVar(
 		&f.ErrorFormat,
 		errorFormatFlagName,
@@ -93,6 +216,77 @@ func (f *flags) Bind(flagSet *pflag.FlagSet) {
 		"",
 		`The buf.yaml file or data to use for configuration`,
 	)
+	flagSet.BoolVar(
+		&f.Fix,
+		fixFlagName,
+		false,
+		"Fix violations in-place for rules with mechanical fixes, and print the remaining violations",
+	)
+	flagSet.BoolVar(
+		&f.Diff,
+		diffFlagName,
+		false,
+		fmt.Sprintf(
+			"Display a diff of the fixes for violations instead of the violations. Combine with --%s to also fix the violations",
+			fixFlagName,
+		),
+	)
+	flagSet.BoolVar(
+		&f.WriteBaseline,
+		writeBaselineFlagName,
+		false,
+		fmt.Sprintf(
+			"Write the violations to the baseline file instead of printing them. Combine with --%s to only record the violations that cannot be fixed",
+			fixFlagName,
+		),
+	)
+	flagSet.IntVar(
+		&f.MaxWarnings,
+		maxWarningsFlagName,
+		-1,
+		"The maximum number of warnings before the command fails. A negative value allows any number of warnings",
+	)
+	flagSet.BoolVar(
+		&f.ListIgnores,
+		listIgnoresFlagName,
+		false,
+		fmt.Sprintf(
+			"List the comment ignores that are in effect instead of linting. Must be used with --%s=text or --%s=json",
+			errorFormatFlagName,
+			errorFormatFlagName,
+		),
+	)
+	flagSet.BoolVar(
+		&f.NoCache,
+		noCacheFlagName,
+		false,
+		`Do not use or update the cache of lint results. By default, the results of each module are cached, keyed by the module and its dependencies, the lint configuration, and the plugins`,
+	)
+	flagSet.BoolVar(
+		&f.SuggestedEdits,
+		suggestedEditsFlagName,
+		false,
+		fmt.Sprintf(
+			"Include the edits that fix each violation in the output, for rules with mechanical fixes. Must be used with --%s=json",
+			errorFormatFlagName,
+		),
+	)
+	flagSet.StringVar(
+		&f.PathHint,
+		pathHintFlagName,
+		"",
+		`The path of the .proto file read from stdin with the input "-". The file is linted as if it were at this path, with its imports resolved against the enclosing workspace or module`,
+	)
+	flagSet.BoolVar(
+		&f.Summary,
+		summaryFlagName,
+		false,
+		fmt.Sprintf(
+			"Print the number of violations of each rule in each file instead of the violations. Must be used with --%s=text or --%s=json",
+			errorFormatFlagName,
+			errorFormatFlagName,
+		),
+	)
 }
 
 func run(
@@ -113,9 +307,97 @@ func run(
 	if err != nil {
 		return err
 	}
+	if flags.WriteBaseline {
+		if flags.Diff {
+			return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s", writeBaselineFlagName, diffFlagName)
+		}
+		if flags.ErrorFormat == "config-ignore-yaml" {
+			return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s=config-ignore-yaml", writeBaselineFlagName, errorFormatFlagName)
+		}
+		if flags.OnlyChangedLines != "" {
+			// The baseline would only record the violations on the changed lines.
+			return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s", writeBaselineFlagName, onlyChangedLinesFlagName)
+		}
+		if flags.OnlyChangedFiles != "" {
+			return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s", writeBaselineFlagName, onlyChangedFilesFlagName)
+		}
+	}
+	if flags.OnlyChangedLines != "" && flags.OnlyChangedFiles != "" {
+		return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s", onlyChangedLinesFlagName, onlyChangedFilesFlagName)
+	}
+	if flags.ListIgnores {
+		if flags.Fix || flags.Diff || flags.WriteBaseline {
+			return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s, --%s, or --%s", listIgnoresFlagName, fixFlagName, diffFlagName, writeBaselineFlagName)
+		}
+		if flags.ErrorFormat != "text" && flags.ErrorFormat != "json" {
+			return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s=%s", listIgnoresFlagName, errorFormatFlagName, flags.ErrorFormat)
+		}
+	}
+	if flags.SuggestedEdits {
+		if flags.ErrorFormat != "json" {
+			return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s=%s", suggestedEditsFlagName, errorFormatFlagName, flags.ErrorFormat)
+		}
+		if flags.Diff || flags.WriteBaseline || flags.ListIgnores {
+			return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s, --%s, or --%s", suggestedEditsFlagName, diffFlagName, writeBaselineFlagName, listIgnoresFlagName)
+		}
+	}
+	if flags.Summary {
+		if flags.ErrorFormat != "text" && flags.ErrorFormat != "json" {
+			return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s=%s", summaryFlagName, errorFormatFlagName, flags.ErrorFormat)
+		}
+		if flags.Fix || flags.Diff || flags.WriteBaseline || flags.ListIgnores || flags.SuggestedEdits {
+			return appcmd.NewInvalidArgumentErrorf(
+				"cannot use --%s with --%s, --%s, --%s, --%s, or --%s",
+				summaryFlagName,
+				fixFlagName,
+				diffFlagName,
+				writeBaselineFlagName,
+				listIgnoresFlagName,
+				suggestedEditsFlagName,
+			)
+		}
+	}
+	var protoFileContent []byte
+	if flags.PathHint != "" {
+		if err := validatePathHint(input, flags); err != nil {
+			return err
+		}
+		protoFileContent, err = io.ReadAll(container.Stdin())
+		if err != nil {
+			return err
+		}
+		input = flags.PathHint
+	}
+	if flags.Fix || flags.Diff {
+		if err := validateFixInput(ctx, container, input, flags); err != nil {
+			return err
+		}
+	}
+	reservedRegistry, err := bufcli.ReadReservedRegistry(ctx, container, input, flags.ReservedRegistry)
+	if err != nil {
+		return err
+	}
+	extensionRegistry, err := bufcli.ReadExtensionRegistry(ctx, container, input, flags.ExtensionRegistry)
+	if err != nil {
+		return err
+	}
+	var baseline bufbaseline.Baseline
+	if !flags.WriteBaseline {
+		// The existing baseline is not used when writing a baseline, so that violations
+		// that were fixed are removed from the baseline.
+		baseline, err = bufcli.ReadBaseline(ctx, container, input, flags.Baseline)
+		if err != nil {
+			return err
+		}
+	}
+	changedLines, err := newChangedLines(ctx, container, input, flags)
+	if err != nil {
+		return err
+	}
 	controller, err := bufcli.NewController(
 		container,
 		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithDisableGitAttributes(flags.DisableGitAttributes),
 		bufctl.WithFileAnnotationErrorFormat(controllerErrorFormat),
 		bufctl.WithFileAnnotationsToStdout(),
 	)
@@ -133,7 +415,295 @@ func run(
 	defer func() {
 		retErr = errors.Join(retErr, wasmRuntime.Close(ctx))
 	}()
-	imageWithConfigs, checkClient, err := controller.GetTargetImageWithConfigsAndCheckClient(
+	if flags.ListIgnores {
+		return listIgnores(ctx, container, controller, wasmRuntime, input, flags)
+	}
+	var cacheBucket storage.ReadWriteBucket
+	if !flags.NoCache {
+		cacheBucket, err = bufcli.NewLintCacheBucket(container)
+		if err != nil {
+			return err
+		}
+	}
+	imageWithConfigs, allFileAnnotations, ruleInfos, err := lint(ctx, controller, wasmRuntime, input, flags, protoFileContent, reservedRegistry, extensionRegistry, baseline, changedLines, cacheBucket)
+	if err != nil {
+		return err
+	}
+	if (flags.Fix || flags.Diff) && len(allFileAnnotations) > 0 {
+		changed, err := fix(ctx, container, controller, input, flags, imageWithConfigs, allFileAnnotations)
+		if err != nil {
+			return err
+		}
+		if changed && flags.Fix {
+			// We lint again, as the fixes change the locations of the remaining violations,
+			// and may result in new violations, for example for PACKAGE_DIRECTORY_MATCH.
+			// The changed lines are read again for the same reason.
+			changedLines, err = newChangedLines(ctx, container, input, flags)
+			if err != nil {
+				return err
+			}
+			imageWithConfigs, allFileAnnotations, _, err = lint(ctx, controller, wasmRuntime, input, flags, protoFileContent, reservedRegistry, extensionRegistry, baseline, changedLines, cacheBucket)
+			if err != nil {
+				return err
+			}
+		}
+		if flags.Diff {
+			// The diff is printed instead of the violations.
+			if len(allFileAnnotations) > 0 {
+				return bufctl.ErrFileAnnotation
+			}
+			return nil
+		}
+	}
+	if flags.WriteBaseline {
+		return bufcli.WriteBaseline(ctx, container, input, flags.Baseline, bufbaseline.NewBaseline(allFileAnnotations))
+	}
+	// SARIF logs and JUnit reports are printed even if there are no violations, as consumers
+	// expect a log or report.
+	if len(allFileAnnotations) > 0 || flags.ErrorFormat == "sarif" || flags.ErrorFormat == "junit" {
+		allFileAnnotationSet := bufanalysis.NewFileAnnotationSet(allFileAnnotations...)
+		if flags.ErrorFormat == "config-ignore-yaml" {
+			if err := bufcli.PrintFileAnnotationSetLintConfigIgnoreYAMLV1(
+				container.Stdout(),
+				allFileAnnotationSet,
+			); err != nil {
+				return err
+			}
+		} else if flags.Summary {
+			if err := bufanalysis.PrintFileAnnotationSetSummary(
+				container.Stdout(),
+				allFileAnnotationSet,
+				flags.ErrorFormat,
+			); err != nil {
+				return err
+			}
+		} else {
+			printOptions := []bufanalysis.PrintOption{
+				bufanalysis.PrintWithRuleInfos(ruleInfos...),
+				bufanalysis.PrintWithFileLinkBaseURL(bufcli.GetFileLinkBaseURL(container)),
+			}
+			if flags.SuggestedEdits {
+				fileAnnotationToSuggestedEdits, err := suggestEdits(ctx, controller, input, flags, protoFileContent, imageWithConfigs, allFileAnnotations)
+				if err != nil {
+					return err
+				}
+				printOptions = append(printOptions, bufanalysis.PrintWithSuggestedEdits(fileAnnotationToSuggestedEdits))
+			}
+			if err := bufanalysis.PrintFileAnnotationSet(
+				container.Stdout(),
+				allFileAnnotationSet,
+				flags.ErrorFormat,
+				printOptions...,
+			); err != nil {
+				return err
+			}
+		}
+		// Warnings are printed, but do not fail the command unless there are more than
+		// the maximum number of warnings.
+		numWarnings := bufanalysis.CountFileAnnotationsWithSeverity(allFileAnnotations, bufanalysis.SeverityWarning)
+		if len(allFileAnnotations) > numWarnings {
+			return bufctl.ErrFileAnnotation
+		}
+		if flags.MaxWarnings >= 0 && numWarnings > flags.MaxWarnings {
+			return app.NewError(
+				bufctl.ExitCodeFileAnnotation,
+				fmt.Sprintf("found %d warnings, which is more than the maximum of %d set with --%s", numWarnings, flags.MaxWarnings, maxWarningsFlagName),
+			)
+		}
+	}
+	return nil
+}
+
+// newChangedLines returns the ChangedLines to filter the violations by, or nil if
+// neither --only-changed-lines nor --only-changed-files is set.
+func newChangedLines(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	flags *flags,
+) (bufchanged.ChangedLines, error) {
+	switch {
+	case flags.OnlyChangedLines != "":
+		return bufcli.NewChangedLinesForInput(ctx, container, input, flags.OnlyChangedLines, onlyChangedLinesFlagName)
+	case flags.OnlyChangedFiles != "":
+		return bufcli.NewChangedFilesForInput(ctx, container, input, flags.OnlyChangedFiles, onlyChangedFilesFlagName)
+	default:
+		return nil, nil
+	}
+}
+
+// fix applies the fixes for the FileAnnotations to the files of the input, and returns
+// true if any files were changed.
+func fix(
+	ctx context.Context,
+	container appext.Container,
+	controller bufctl.Controller,
+	input string,
+	flags *flags,
+	imageWithConfigs []bufctl.ImageWithConfig,
+	fileAnnotations []bufanalysis.FileAnnotation,
+) (bool, error) {
+	originalReadBucket, fixOptions, err := getFixReadBucketAndOptions(ctx, controller, input, flags, nil, imageWithConfigs)
+	if err != nil {
+		return false, err
+	}
+	fixedReadBucket, _, err := buflintfix.FixBucket(
+		ctx,
+		originalReadBucket,
+		fileAnnotations,
+		fixOptions...,
+	)
+	if err != nil {
+		return false, err
+	}
+	diffWriter := io.Discard
+	if flags.Diff {
+		diffWriter = container.Stdout()
+	}
+	changedPaths, err := storage.DiffWithFilenames(
+		ctx,
+		diffWriter,
+		originalReadBucket,
+		fixedReadBucket,
+		storage.DiffWithExternalPaths(), // No need to set prefixes as the buckets are from the same location.
+	)
+	if err != nil {
+		return false, err
+	}
+	if flags.Fix {
+		for _, changedPath := range changedPaths {
+			if err := writeFixedFile(ctx, fixedReadBucket, changedPath); err != nil {
+				return false, err
+			}
+		}
+	}
+	return len(changedPaths) > 0, nil
+}
+
+// suggestEdits returns the edits that fix each of the FileAnnotations, without applying them.
+func suggestEdits(
+	ctx context.Context,
+	controller bufctl.Controller,
+	input string,
+	flags *flags,
+	protoFileContent []byte,
+	imageWithConfigs []bufctl.ImageWithConfig,
+	fileAnnotations []bufanalysis.FileAnnotation,
+) (map[bufanalysis.FileAnnotation][]bufanalysis.SuggestedEdit, error) {
+	readBucket, fixOptions, err := getFixReadBucketAndOptions(ctx, controller, input, flags, protoFileContent, imageWithConfigs)
+	if err != nil {
+		return nil, err
+	}
+	return buflintfix.SuggestEdits(ctx, readBucket, fileAnnotations, fixOptions...)
+}
+
+// getFixReadBucketAndOptions returns the files of the input to fix, and the FixOptions
+// derived from the lint configurations of the ImageWithConfigs.
+func getFixReadBucketAndOptions(
+	ctx context.Context,
+	controller bufctl.Controller,
+	input string,
+	flags *flags,
+	protoFileContent []byte,
+	imageWithConfigs []bufctl.ImageWithConfig,
+) (storage.ReadBucket, []buflintfix.FixOption, error) {
+	workspace, err := controller.GetWorkspace(
+		ctx,
+		input,
+		getFunctionOptions(flags, protoFileContent)...,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	// All files of the target modules are fixed, not just the target files, so that
+	// references to renamed packages are updated in all local files.
+	readBucket := bufmodule.ModuleReadBucketToStorageReadBucket(
+		bufmodule.ModuleSetToModuleReadBucketWithOnlyProtoFilesForTargetModules(workspace),
+	)
+	pathToEnumZeroValueSuffix := make(map[string]string)
+	for _, imageWithConfig := range imageWithConfigs {
+		for _, imageFile := range imageWithConfig.Files() {
+			if !imageFile.IsImport() {
+				pathToEnumZeroValueSuffix[imageFile.Path()] = imageWithConfig.LintConfig().EnumZeroValueSuffix()
+			}
+		}
+	}
+	return readBucket, []buflintfix.FixOption{
+		buflintfix.FixWithPathToEnumZeroValueSuffix(pathToEnumZeroValueSuffix),
+	}, nil
+}
+
+// getFunctionOptions returns the FunctionOptions to read the input with.
+//
+// If protoFileContent is not nil, it is used as the content of the .proto file input.
+func getFunctionOptions(flags *flags, protoFileContent []byte) []bufctl.FunctionOption {
+	functionOptions := []bufctl.FunctionOption{
+		bufctl.WithTargetPaths(flags.Paths, flags.ExcludePaths),
+		bufctl.WithConfigOverride(flags.Config),
+	}
+	if protoFileContent != nil {
+		functionOptions = append(functionOptions, bufctl.WithProtoFileContent(protoFileContent))
+	}
+	return functionOptions
+}
+
+func writeFixedFile(ctx context.Context, fixedReadBucket storage.ReadBucket, path string) (retErr error) {
+	readObjectCloser, err := fixedReadBucket.Get(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, readObjectCloser.Close())
+	}()
+	// We validate that the input is a directory or proto file, so the external paths
+	// are paths on the local filesystem, as with buf format --write.
+	file, err := os.OpenFile(readObjectCloser.ExternalPath(), os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, file.Close())
+	}()
+	_, err = file.ReadFrom(readObjectCloser)
+	return err
+}
+
+func validateFixInput(
+	ctx context.Context,
+	container appext.Container,
+	input string,
+	flags *flags,
+) error {
+	flagName := fixFlagName
+	if !flags.Fix {
+		flagName = diffFlagName
+	}
+	if flags.ErrorFormat == "config-ignore-yaml" {
+		return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s=config-ignore-yaml", flagName, errorFormatFlagName)
+	}
+	dirOrProtoFileRef, err := buffetch.NewDirOrProtoFileRefParser(container.Logger()).GetDirOrProtoFileRef(ctx, input)
+	if err != nil {
+		if errors.Is(err, buffetch.ErrModuleFormatDetectedForDirOrProtoFileRef) {
+			return appcmd.NewInvalidArgumentErrorf("invalid input %q when using --%s: must be a directory or proto file", input, flagName)
+		}
+		return appcmd.NewInvalidArgumentErrorf("invalid input %q when using --%s: %v", input, flagName, err)
+	}
+	if protoFileRef, ok := dirOrProtoFileRef.(buffetch.ProtoFileRef); ok && protoFileRef.IncludePackageFiles() {
+		return appcmd.NewInvalidArgumentErrorf("cannot specify include_package_files=true with --%s", flagName)
+	}
+	return nil
+}
+
+// listIgnores prints the comment ignores that are in effect for the input.
+func listIgnores(
+	ctx context.Context,
+	container appext.Container,
+	controller bufctl.Controller,
+	wasmRuntime wasm.Runtime,
+	input string,
+	flags *flags,
+) error {
+	imageWithConfigs, _, err := controller.GetTargetImageWithConfigsAndCheckClient(
 		ctx,
 		input,
 		wasmRuntime,
@@ -143,7 +713,115 @@ func run(
 	if err != nil {
 		return err
 	}
+	now := time.Now()
+	for _, imageWithConfig := range imageWithConfigs {
+		commentIgnores, err := bufcheck.ActiveCommentIgnores(imageWithConfig.LintConfig(), imageWithConfig, now)
+		if err != nil {
+			return err
+		}
+		for _, commentIgnore := range commentIgnores {
+			if err := printCommentIgnore(container.Stdout(), commentIgnore, flags.ErrorFormat); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func printCommentIgnore(writer io.Writer, commentIgnore bufcheck.CommentIgnore, format string) error {
+	var expires string
+	if expiry := commentIgnore.Expiry(); !expiry.IsZero() {
+		expires = expiry.Format(time.DateOnly)
+	}
+	if format == "json" {
+		data, err := json.Marshal(
+			externalCommentIgnore{
+				Path:          commentIgnore.FileInfo().ExternalPath(),
+				StartLine:     commentIgnore.StartLine(),
+				StartColumn:   commentIgnore.StartColumn(),
+				Rule:          commentIgnore.RuleID(),
+				Justification: commentIgnore.Justification(),
+				Expires:       expires,
+			},
+		)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(writer, string(data))
+		return err
+	}
+	line := fmt.Sprintf(
+		"%s:%d:%d:%s",
+		commentIgnore.FileInfo().ExternalPath(),
+		commentIgnore.StartLine(),
+		commentIgnore.StartColumn(),
+		commentIgnore.RuleID(),
+	)
+	if justification := commentIgnore.Justification(); justification != "" {
+		line += ":" + justification
+	}
+	if expires != "" {
+		line += " (expires " + expires + ")"
+	}
+	_, err := fmt.Fprintln(writer, line)
+	return err
+}
+
+type externalCommentIgnore struct {
+	Path          string `json:"path"`
+	StartLine     int    `json:"start_line"`
+	StartColumn   int    `json:"start_column"`
+	Rule          string `json:"rule"`
+	Justification string `json:"justification,omitempty"`
+	Expires       string `json:"expires,omitempty"`
+}
+
+func validatePathHint(input string, flags *flags) error {
+	if input != "-" {
+		return appcmd.NewInvalidArgumentErrorf("--%s can only be used with the input \"-\", got %q", pathHintFlagName, input)
+	}
+	if normalpath.Ext(flags.PathHint) != ".proto" {
+		return appcmd.NewInvalidArgumentErrorf("--%s must be the path of a .proto file, got %q", pathHintFlagName, flags.PathHint)
+	}
+	if flags.Fix || flags.Diff || flags.ListIgnores {
+		return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s, --%s, or --%s", pathHintFlagName, fixFlagName, diffFlagName, listIgnoresFlagName)
+	}
+	if flags.OnlyChangedLines != "" || flags.OnlyChangedFiles != "" {
+		return appcmd.NewInvalidArgumentErrorf("cannot use --%s with --%s or --%s", pathHintFlagName, onlyChangedLinesFlagName, onlyChangedFilesFlagName)
+	}
+	return nil
+}
+
+// lint returns the ImageWithConfigs of the input, and the violations of the lint rules
+// for the ImageWithConfigs that are not in the baseline, if set, and that are within the
+// ChangedLines, if set.
+//
+// If the error format includes rule metadata, the RuleInfos of the configured rules are also
+// returned.
+func lint(
+	ctx context.Context,
+	controller bufctl.Controller,
+	wasmRuntime wasm.Runtime,
+	input string,
+	flags *flags,
+	protoFileContent []byte,
+	reservedRegistry bufreserved.Registry,
+	extensionRegistry bufextension.Registry,
+	baseline bufbaseline.Baseline,
+	changedLines bufchanged.ChangedLines,
+	cacheBucket storage.ReadWriteBucket,
+) ([]bufctl.ImageWithConfig, []bufanalysis.FileAnnotation, []bufanalysis.RuleInfo, error) {
+	imageWithConfigs, checkClient, err := controller.GetTargetImageWithConfigsAndCheckClient(
+		ctx,
+		input,
+		wasmRuntime,
+		getFunctionOptions(flags, protoFileContent)...,
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	var allFileAnnotations []bufanalysis.FileAnnotation
+	var ruleInfos []bufanalysis.RuleInfo
 	// We add all check configs (both lint and breaking) as related configs to check if plugins
 	// have rules configured.
 	// We allocated twice the size of imageWithConfigs for both lint and breaking configs.
@@ -157,6 +835,15 @@ func run(
 			bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
 			bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
 		}
+		if reservedRegistry != nil {
+			lintOptions = append(lintOptions, bufcheck.LintWithReservedRegistry(reservedRegistry))
+		}
+		if extensionRegistry != nil {
+			lintOptions = append(lintOptions, bufcheck.LintWithExtensionRegistry(extensionRegistry))
+		}
+		if cacheBucket != nil {
+			lintOptions = append(lintOptions, bufcheck.LintWithCache(cacheBucket, bufcli.Version))
+		}
 		if err := checkClient.Lint(
 			ctx,
 			imageWithConfig.LintConfig(),
@@ -167,29 +854,31 @@ func run(
 			if errors.As(err, &fileAnnotationSet) {
 				allFileAnnotations = append(allFileAnnotations, fileAnnotationSet.FileAnnotations()...)
 			} else {
-				return err
+				return nil, nil, nil, err
 			}
 		}
-	}
-	if len(allFileAnnotations) > 0 {
-		allFileAnnotationSet := bufanalysis.NewFileAnnotationSet(allFileAnnotations...)
-		if flags.ErrorFormat == "config-ignore-yaml" {
-			if err := bufcli.PrintFileAnnotationSetLintConfigIgnoreYAMLV1(
-				container.Stdout(),
-				allFileAnnotationSet,
-			); err != nil {
-				return err
-			}
-		} else {
-			if err := bufanalysis.PrintFileAnnotationSet(
-				container.Stdout(),
-				allFileAnnotationSet,
-				flags.ErrorFormat,
-			); err != nil {
-				return err
+		if flags.ErrorFormat == "sarif" {
+			configuredRules, err := checkClient.ConfiguredRules(
+				ctx,
+				check.RuleTypeLint,
+				imageWithConfig.LintConfig(),
+				bufcheck.WithPluginConfigs(imageWithConfig.PluginConfigs()...),
+				bufcheck.WithRelatedCheckConfigs(allCheckConfigs...),
+			)
+			if err != nil {
+				return nil, nil, nil, err
 			}
+			ruleInfos = append(
+				ruleInfos,
+				bufcli.RulesToRuleInfos(configuredRules, imageWithConfig.LintConfig().WarnIDsAndCategories(), nil)...,
+			)
 		}
-		return bufctl.ErrFileAnnotation
 	}
-	return nil
+	if baseline != nil {
+		allFileAnnotations = bufbaseline.FilterFileAnnotations(baseline, allFileAnnotations)
+	}
+	if changedLines != nil {
+		allFileAnnotations = bufchanged.FilterFileAnnotations(changedLines, allFileAnnotations)
+	}
+	return imageWithConfigs, allFileAnnotations, ruleInfos, nil
 }
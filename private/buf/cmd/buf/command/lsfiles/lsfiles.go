@@ -36,15 +36,16 @@ import (
 )
 
 const (
-	formatFlagName            = "format"
-	configFlagName            = "config"
-	errorFormatFlagName       = "error-format"
-	includeImportsFlagName    = "include-imports"
-	includeImportableFlagName = "include-importable"
-	pathsFlagName             = "path"
-	excludePathsFlagName      = "exclude-path"
-	disableSymlinksFlagName   = "disable-symlinks"
-	asImportPathsFlagName     = "as-import-paths"
+	formatFlagName               = "format"
+	configFlagName               = "config"
+	errorFormatFlagName          = "error-format"
+	includeImportsFlagName       = "include-imports"
+	includeImportableFlagName    = "include-importable"
+	pathsFlagName                = "path"
+	excludePathsFlagName         = "exclude-path"
+	disableSymlinksFlagName      = "disable-symlinks"
+	disableGitAttributesFlagName = "disable-git-attributes"
+	asImportPathsFlagName        = "as-import-paths"
 
 	formatText   = "text"
 	formatJSON   = "json"
@@ -76,13 +77,14 @@ func NewCommand(
 }
 
 type flags struct {
-	Format            string
-	Config            string
-	IncludeImports    bool
-	IncludeImportable bool
-	Paths             []string
-	ExcludePaths      []string
-	DisableSymlinks   bool
+	Format               string
+	Config               string
+	IncludeImports       bool
+	IncludeImportable    bool
+	Paths                []string
+	ExcludePaths         []string
+	DisableSymlinks      bool
+	DisableGitAttributes bool
 	// Deprecated. This flag no longer has any effect as we don't build images anymore.
 	ErrorFormat string
 	// Deprecated
@@ -100,6 +102,7 @@ func (f *flags) Bind(flagSet *pflag.FlagSet) {
 	bufcli.BindPaths(flagSet, &f.Paths, pathsFlagName)
 	bufcli.BindExcludePaths(flagSet, &f.ExcludePaths, excludePathsFlagName)
 	bufcli.BindDisableSymlinks(flagSet, &f.DisableSymlinks, disableSymlinksFlagName)
+	bufcli.BindDisableGitAttributes(flagSet, &f.DisableGitAttributes, disableGitAttributesFlagName)
 	flagSet.StringVar(
 		&f.Config,
 		configFlagName,
@@ -166,6 +169,7 @@ func run(
 	controller, err := bufcli.NewController(
 		container,
 		bufctl.WithDisableSymlinks(flags.DisableSymlinks),
+		bufctl.WithDisableGitAttributes(flags.DisableGitAttributes),
 	)
 	if err != nil {
 		return err
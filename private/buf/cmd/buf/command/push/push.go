@@ -47,6 +47,8 @@ const (
 	sourceControlURLFlagName   = "source-control-url"
 	gitMetadataFlagName        = "git-metadata"
 	excludeUnnamedFlagName     = "exclude-unnamed"
+	quietFlagName              = "quiet"
+	jsonFlagName               = "json"
 
 	// All deprecated.
 	tagFlagName      = "tag"
@@ -94,6 +96,8 @@ type flags struct {
 	SourceControlURL   string
 	ExcludeUnnamed     bool
 	GitMetadata        bool
+	Quiet              bool
+	JSON               bool
 	// special
 	InputHashtag string
 }
@@ -172,6 +176,18 @@ If you set the --%s flag and/or --%s flag yourself, then the value(s) will be us
 		false,
 		"Only push named modules to the BSR. Named modules must not have any unnamed dependencies.",
 	)
+	flagSet.BoolVar(
+		&f.Quiet,
+		quietFlagName,
+		false,
+		"Do not print the progress of the upload or the transfer summary to stderr.",
+	)
+	flagSet.BoolVar(
+		&f.JSON,
+		jsonFlagName,
+		false,
+		"Print the pushed commits and transfer statistics as JSON to stdout, instead of the pushed commits.",
+	)
 
 	flagSet.StringSliceVarP(&f.Tags, tagFlagName, tagFlagShortName, nil, useLabelInstead)
 	_ = flagSet.MarkHidden(tagFlagName)
@@ -257,10 +273,17 @@ func run(
 		uploadOptions = append(uploadOptions, bufmodule.UploadWithExcludeUnnamed())
 	}
 
+	observer := newUploadObserver(container.Stderr(), flags.Quiet)
+	uploadOptions = append(uploadOptions, bufmodule.UploadWithObserver(observer))
+
 	commits, err := uploader.Upload(ctx, workspace, uploadOptions...)
 	if err != nil {
 		return err
 	}
+	observer.printSummary()
+	if flags.JSON {
+		return observer.printJSON(container.Stdout())
+	}
 	if len(commits) == 0 {
 		return nil
 	}
@@ -315,6 +338,8 @@ func getBuildableWorkspace(
 		// that we don't want to deal with. If we have a v1 workspace, just outlaw pushing the whole
 		// workspace, and force people into the pre-refactor behavior.
 		bufctl.WithIgnoreAndDisallowV1BufWorkYAMLs(),
+		// Pushed modules must build against the published dependencies in the buf.lock file.
+		bufctl.WithIgnoreModuleOverrides(),
 	)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,214 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
+	"golang.org/x/term"
+)
+
+// uploadObserver is a bufmodule.UploadObserver that records the transfer statistics of
+// an upload, and prints the progress of the upload to stderr.
+type uploadObserver struct {
+	stderr io.Writer
+	// quiet is true if nothing should be printed to stderr.
+	quiet bool
+	// live is true if stderr is a terminal, in which case the progress of reading each
+	// file is printed on a single line that is continually overwritten.
+	live          bool
+	startTime     time.Time
+	moduleStats   []*moduleStats
+	nameToStats   map[string]*moduleStats
+	liveLineShown bool
+}
+
+func newUploadObserver(stderr io.Writer, quiet bool) *uploadObserver {
+	var live bool
+	if file, ok := stderr.(*os.File); ok {
+		live = term.IsTerminal(int(file.Fd()))
+	}
+	return &uploadObserver{
+		stderr:      stderr,
+		quiet:       quiet,
+		live:        live,
+		startTime:   time.Now(),
+		nameToStats: make(map[string]*moduleStats),
+	}
+}
+
+func (u *uploadObserver) FileRead(moduleFullName bufparse.FullName, path string, size int) {
+	moduleStats := u.getModuleStats(moduleFullName)
+	moduleStats.Files++
+	moduleStats.Bytes += size
+	if u.live && !u.quiet {
+		u.liveLineShown = true
+		u.printf(
+			"\r\033[KReading %s: %s (%d files, %s)",
+			moduleFullName.String(),
+			path,
+			moduleStats.Files,
+			formatBytes(moduleStats.Bytes),
+		)
+	}
+}
+
+func (u *uploadObserver) UploadStarted(registry string) {
+	if u.liveLineShown {
+		u.printf("\r\033[K")
+		u.liveLineShown = false
+	}
+	files, bytes, _ := u.totals()
+	u.printf(
+		"Uploading %d module(s) (%d files, %s) to %s\n",
+		len(u.moduleStats),
+		files,
+		formatBytes(bytes),
+		registry,
+	)
+}
+
+func (u *uploadObserver) CommitReceived(moduleFullName bufparse.FullName, commit bufmodule.Commit, existing bool) {
+	moduleStats := u.getModuleStats(moduleFullName)
+	moduleStats.Commit = commit.ModuleKey().String()
+	moduleStats.Existing = existing
+	status := "new commit"
+	if existing {
+		status = "unchanged, content already on the registry"
+	}
+	u.printf(
+		"%s: %s (%d files, %s)\n",
+		moduleStats.Commit,
+		status,
+		moduleStats.Files,
+		formatBytes(moduleStats.Bytes),
+	)
+}
+
+// printSummary prints the total transfer statistics of the upload to stderr.
+func (u *uploadObserver) printSummary() {
+	if len(u.moduleStats) == 0 {
+		return
+	}
+	_, bytes, existingBytes := u.totals()
+	var existingCommits int
+	for _, moduleStats := range u.moduleStats {
+		if moduleStats.Existing {
+			existingCommits++
+		}
+	}
+	u.printf(
+		"Pushed %d module(s) in %v: %d new, %d unchanged. Transferred %s, of which %s was already on the registry\n",
+		len(u.moduleStats),
+		time.Since(u.startTime).Round(time.Millisecond),
+		len(u.moduleStats)-existingCommits,
+		existingCommits,
+		formatBytes(bytes),
+		formatBytes(existingBytes),
+	)
+}
+
+// printJSON prints the transfer statistics of the upload as JSON to the writer.
+func (u *uploadObserver) printJSON(writer io.Writer) error {
+	files, bytes, existingBytes := u.totals()
+	modules := make([]moduleStats, len(u.moduleStats))
+	for i, moduleStats := range u.moduleStats {
+		modules[i] = *moduleStats
+	}
+	data, err := json.Marshal(
+		externalSummary{
+			Modules:       modules,
+			Files:         files,
+			Bytes:         bytes,
+			ExistingBytes: existingBytes,
+			DurationMS:    time.Since(u.startTime).Milliseconds(),
+		},
+	)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(writer, string(data))
+	return err
+}
+
+func (u *uploadObserver) getModuleStats(moduleFullName bufparse.FullName) *moduleStats {
+	name := moduleFullName.String()
+	stats, ok := u.nameToStats[name]
+	if !ok {
+		stats = &moduleStats{
+			Name: name,
+		}
+		u.nameToStats[name] = stats
+		u.moduleStats = append(u.moduleStats, stats)
+	}
+	return stats
+}
+
+func (u *uploadObserver) totals() (files int, bytes int, existingBytes int) {
+	for _, moduleStats := range u.moduleStats {
+		files += moduleStats.Files
+		bytes += moduleStats.Bytes
+		if moduleStats.Existing {
+			existingBytes += moduleStats.Bytes
+		}
+	}
+	return files, bytes, existingBytes
+}
+
+func (u *uploadObserver) printf(format string, args ...any) {
+	if u.quiet {
+		return
+	}
+	// Progress is best-effort, errors writing to stderr are ignored.
+	_, _ = fmt.Fprintf(u.stderr, format, args...)
+}
+
+type moduleStats struct {
+	Name     string `json:"name"`
+	Commit   string `json:"commit,omitempty"`
+	Files    int    `json:"files"`
+	Bytes    int    `json:"bytes"`
+	Existing bool   `json:"existing"`
+}
+
+type externalSummary struct {
+	Modules       []moduleStats `json:"modules"`
+	Files         int           `json:"files"`
+	Bytes         int           `json:"bytes"`
+	ExistingBytes int           `json:"existing_bytes"`
+	DurationMS    int64         `json:"duration_ms"`
+}
+
+// formatBytes formats the number of bytes for display, such as "1.5 KiB".
+func formatBytes(bytes int) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value := float64(bytes) / unit
+	for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+		if value < unit || suffix == "GiB" {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%d B", bytes)
+}
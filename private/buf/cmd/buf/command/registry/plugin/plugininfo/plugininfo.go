@@ -24,13 +24,22 @@ import (
 	"github.com/bufbuild/buf/private/buf/bufprint"
 	"github.com/bufbuild/buf/private/bufpkg/bufparse"
 	"github.com/bufbuild/buf/private/bufpkg/bufregistryapi/bufregistryapiplugin"
+	"github.com/bufbuild/buf/private/gen/proto/connect/buf/alpha/registry/v1alpha1/registryv1alpha1connect"
+	registryv1alpha1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/registry/v1alpha1"
 	"github.com/bufbuild/buf/private/pkg/app/appcmd"
 	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/connectclient"
 	"github.com/bufbuild/buf/private/pkg/syserror"
 	"github.com/spf13/pflag"
 )
 
-const formatFlagName = "format"
+const (
+	formatFlagName = "format"
+
+	// maxCuratedPluginVersions is the maximum number of versions printed for a code
+	// generation plugin.
+	maxCuratedPluginVersions = 10
+)
 
 // NewCommand returns a new Command.
 func NewCommand(
@@ -41,7 +50,12 @@ func NewCommand(
 	return &appcmd.Command{
 		Use:   name + " <remote/owner/plugin>",
 		Short: "Get a BSR plugin",
-		Args:  appcmd.ExactArgs(1),
+		Long: `This command prints information about a BSR plugin.
+
+For code generation plugins, such as buf.build/protocolbuffers/go, this includes the description,
+output languages, default options and latest versions of the plugin.
+Use "buf registry plugin search" to find code generation plugins.`,
+		Args: appcmd.ExactArgs(1),
 		Run: builder.NewRunFunc(
 			func(ctx context.Context, container appext.Container) error {
 				return run(ctx, container, flags)
@@ -105,7 +119,9 @@ func run(
 	))
 	if err != nil {
 		if connect.CodeOf(err) == connect.CodeNotFound {
-			return bufcli.NewPluginNotFoundError(container.Arg(0))
+			// The plugin may be a code generation plugin, which is managed by the
+			// plugin curation service instead.
+			return printCuratedPluginInfo(ctx, container, clientConfig, pluginFullName, format)
 		}
 		return err
 	}
@@ -119,3 +135,43 @@ func run(
 		bufprint.NewPluginEntity(plugins[0], pluginFullName),
 	)
 }
+
+func printCuratedPluginInfo(
+	ctx context.Context,
+	container appext.Container,
+	clientConfig *connectclient.Config,
+	pluginFullName bufparse.FullName,
+	format bufprint.Format,
+) error {
+	pluginCurationServiceClient := connectclient.Make(
+		clientConfig,
+		pluginFullName.Registry(),
+		registryv1alpha1connect.NewPluginCurationServiceClient,
+	)
+	getLatestCuratedPluginResponse, err := pluginCurationServiceClient.GetLatestCuratedPlugin(
+		ctx,
+		connect.NewRequest(
+			registryv1alpha1.GetLatestCuratedPluginRequest_builder{
+				Owner: pluginFullName.Owner(),
+				Name:  pluginFullName.Name(),
+			}.Build(),
+		),
+	)
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			return bufcli.NewPluginNotFoundError(container.Arg(0))
+		}
+		return err
+	}
+	versions := getLatestCuratedPluginResponse.Msg.GetVersions()
+	if len(versions) > maxCuratedPluginVersions {
+		versions = versions[:maxCuratedPluginVersions]
+	}
+	return bufprint.NewCuratedPluginPrinter(container.Stdout()).PrintCuratedPluginInfo(
+		ctx,
+		format,
+		pluginFullName.Registry(),
+		getLatestCuratedPluginResponse.Msg.GetPlugin(),
+		versions...,
+	)
+}
@@ -0,0 +1,260 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pluginsearch
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/buf/bufprint"
+	"github.com/bufbuild/buf/private/bufpkg/bufconnect"
+	"github.com/bufbuild/buf/private/gen/proto/connect/buf/alpha/registry/v1alpha1/registryv1alpha1connect"
+	registryv1alpha1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/registry/v1alpha1"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/connectclient"
+	"github.com/bufbuild/buf/private/pkg/netext"
+	"github.com/spf13/pflag"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	formatFlagName            = "format"
+	languageFlagName          = "language"
+	includeDeprecatedFlagName = "include-deprecated"
+	remoteFlagName            = "remote"
+
+	listPageSize = 250
+)
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <term>...",
+		Short: "Search BSR code generation plugins",
+		Long: `This command searches the code generation plugins of the Buf Schema Registry.
+
+A plugin matches if every term is found in its name, description, output languages or collections.
+The latest version of each matching plugin is printed along with its output languages and description.
+Use "buf registry plugin info" to show the details of a plugin.
+
+Examples:
+
+Search for plugins that generate gRPC documentation:
+
+    $ buf registry plugin search grpc docs
+
+Search for gRPC plugins that generate Go:
+
+    $ buf registry plugin search grpc --language go`,
+		Args: appcmd.MinimumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	Format            string
+	Language          string
+	IncludeDeprecated bool
+	Remote            string
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(
+		&f.Format,
+		formatFlagName,
+		bufprint.FormatText.String(),
+		fmt.Sprintf(`The output format to use. Must be one of %s`, bufprint.AllFormatsString),
+	)
+	flagSet.StringVar(
+		&f.Language,
+		languageFlagName,
+		"",
+		`Only show plugins that generate code for this language, such as "go" or "typescript"`,
+	)
+	flagSet.BoolVar(
+		&f.IncludeDeprecated,
+		includeDeprecatedFlagName,
+		false,
+		"Include deprecated plugins",
+	)
+	flagSet.StringVar(
+		&f.Remote,
+		remoteFlagName,
+		bufconnect.DefaultRemote,
+		"The BSR instance to search",
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	format, err := bufprint.ParseFormat(flags.Format)
+	if err != nil {
+		return appcmd.WrapInvalidArgumentError(err)
+	}
+	if _, err := netext.ValidateHostname(flags.Remote); err != nil {
+		return appcmd.NewInvalidArgumentErrorf("invalid value for --%s: %v", remoteFlagName, err)
+	}
+	var language registryv1alpha1.PluginLanguage
+	if flags.Language != "" {
+		language, err = parsePluginLanguage(flags.Language)
+		if err != nil {
+			return appcmd.WrapInvalidArgumentError(err)
+		}
+	}
+	terms := make([]string, 0, container.NumArgs())
+	for i := 0; i < container.NumArgs(); i++ {
+		terms = append(terms, strings.ToLower(container.Arg(i)))
+	}
+	clientConfig, err := bufcli.NewConnectClientConfig(container)
+	if err != nil {
+		return err
+	}
+	pluginCurationServiceClient := connectclient.Make(
+		clientConfig,
+		flags.Remote,
+		registryv1alpha1connect.NewPluginCurationServiceClient,
+	)
+	var plugins []*registryv1alpha1.CuratedPlugin
+	var pageToken string
+	for {
+		listCuratedPluginsResponse, err := pluginCurationServiceClient.ListCuratedPlugins(
+			ctx,
+			connect.NewRequest(
+				registryv1alpha1.ListCuratedPluginsRequest_builder{
+					PageSize:          listPageSize,
+					PageToken:         pageToken,
+					IncludeDeprecated: flags.IncludeDeprecated,
+				}.Build(),
+			),
+		)
+		if err != nil {
+			return err
+		}
+		plugins = append(plugins, listCuratedPluginsResponse.Msg.GetPlugins()...)
+		pageToken = listCuratedPluginsResponse.Msg.GetNextPageToken()
+		if pageToken == "" {
+			break
+		}
+	}
+	return bufprint.NewCuratedPluginPrinter(container.Stdout()).PrintCuratedPluginSummaries(
+		ctx,
+		format,
+		flags.Remote,
+		searchCuratedPlugins(plugins, terms, language)...,
+	)
+}
+
+// searchCuratedPlugins returns the latest version of each plugin that matches all the
+// lowercase terms and generates code for the language, if specified. The plugins are
+// sorted by owner and name.
+func searchCuratedPlugins(
+	plugins []*registryv1alpha1.CuratedPlugin,
+	terms []string,
+	language registryv1alpha1.PluginLanguage,
+) []*registryv1alpha1.CuratedPlugin {
+	nameToLatestPlugin := make(map[string]*registryv1alpha1.CuratedPlugin)
+	for _, plugin := range plugins {
+		name := plugin.GetOwner() + "/" + plugin.GetName()
+		if latestPlugin, ok := nameToLatestPlugin[name]; ok && !isLaterVersion(plugin, latestPlugin) {
+			continue
+		}
+		nameToLatestPlugin[name] = plugin
+	}
+	var matchingPlugins []*registryv1alpha1.CuratedPlugin
+	for name, plugin := range nameToLatestPlugin {
+		if language != registryv1alpha1.PluginLanguage_PLUGIN_LANGUAGE_UNSPECIFIED &&
+			!slices.Contains(plugin.GetOutputLanguages(), language) {
+			continue
+		}
+		searchableValues := []string{name, plugin.GetDescription()}
+		for _, outputLanguage := range plugin.GetOutputLanguages() {
+			searchableValues = append(searchableValues, bufprint.CuratedPluginLanguageString(outputLanguage))
+		}
+		for _, collection := range plugin.GetCollections() {
+			searchableValues = append(searchableValues, collection.GetName())
+		}
+		searchableText := strings.ToLower(strings.Join(searchableValues, "\n"))
+		if !allTermsContained(searchableText, terms) {
+			continue
+		}
+		matchingPlugins = append(matchingPlugins, plugin)
+	}
+	slices.SortFunc(
+		matchingPlugins,
+		func(a *registryv1alpha1.CuratedPlugin, b *registryv1alpha1.CuratedPlugin) int {
+			if c := strings.Compare(a.GetOwner(), b.GetOwner()); c != 0 {
+				return c
+			}
+			return strings.Compare(a.GetName(), b.GetName())
+		},
+	)
+	return matchingPlugins
+}
+
+// isLaterVersion returns true if the plugin has a later version, or a later revision
+// of the same version, than the other plugin.
+func isLaterVersion(plugin *registryv1alpha1.CuratedPlugin, other *registryv1alpha1.CuratedPlugin) bool {
+	if c := semver.Compare(plugin.GetVersion(), other.GetVersion()); c != 0 {
+		return c > 0
+	}
+	return plugin.GetRevision() > other.GetRevision()
+}
+
+func allTermsContained(text string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func parsePluginLanguage(value string) (registryv1alpha1.PluginLanguage, error) {
+	var languageStrings []string
+	for number := range registryv1alpha1.PluginLanguage_name {
+		pluginLanguage := registryv1alpha1.PluginLanguage(number)
+		if pluginLanguage == registryv1alpha1.PluginLanguage_PLUGIN_LANGUAGE_UNSPECIFIED {
+			continue
+		}
+		languageString := bufprint.CuratedPluginLanguageString(pluginLanguage)
+		if languageString == strings.ToLower(value) {
+			return pluginLanguage, nil
+		}
+		languageStrings = append(languageStrings, languageString)
+	}
+	slices.Sort(languageStrings)
+	return 0, fmt.Errorf("unknown language %q, must be one of %s", value, strings.Join(languageStrings, ", "))
+}
@@ -0,0 +1,419 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registryexportstate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"buf.build/gen/go/bufbuild/registry/connectrpc/go/buf/registry/owner/v1/ownerv1connect"
+	modulev1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/module/v1"
+	ownerv1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/owner/v1"
+	"connectrpc.com/connect"
+	"github.com/bufbuild/buf/private/buf/bufcli"
+	"github.com/bufbuild/buf/private/bufpkg/bufconnect"
+	"github.com/bufbuild/buf/private/bufpkg/bufregistryapi/bufregistryapimodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufregistryapi/bufregistryapiowner"
+	"github.com/bufbuild/buf/private/pkg/app/appcmd"
+	"github.com/bufbuild/buf/private/pkg/app/appext"
+	"github.com/bufbuild/buf/private/pkg/netext"
+	"github.com/bufbuild/buf/private/pkg/syserror"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	organizationFlagName = "organization"
+	formatFlagName       = "format"
+	sinceFlagName        = "since"
+	pageSizeFlagName     = "page-size"
+
+	// stateVersion is the version of the schema of the exported state.
+	//
+	// Fields may be added to the schema without changing the version. Fields are
+	// never removed or changed without changing the version.
+	stateVersion = "v1"
+
+	formatJSON      = "json"
+	defaultPageSize = 100
+)
+
+var allFormats = []string{formatJSON}
+
+// NewCommand returns a new Command.
+func NewCommand(
+	name string,
+	builder appext.SubCommandBuilder,
+) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   name + " <domain>",
+		Short: "Export the state of BSR organizations for import into infrastructure as code tooling",
+		Long: `Export the organizations, modules, labels, and module settings of BSR organizations in a
+stable, machine-readable schema, for import into infrastructure as code tooling.
+
+This command takes an argument for the domain of the BSR instance, which defaults to ` + bufconnect.DefaultRemote + `.
+
+    $ buf registry export-state --organization acme --format json
+
+All pages of modules and labels are fetched, and the results are sorted by name, so that the
+output of two exports of the same state is the same. The output has a "version" key for the
+version of the schema, which is ` + stateVersion + `. Keys may be added to the schema without changing
+the version.
+
+The output has an "export_time" key for the time at which the export started. To export only
+what changed since a previous export, pass its export time to --since:
+
+    $ buf registry export-state --organization acme --since 2025-01-02T15:04:05Z
+
+With --since, only the modules that were created or updated since the time are exported, with
+only the labels that were created, updated, or archived since the time. Modules with labels that
+changed are exported even if the module itself did not change. Deleted modules and labels are not
+exported, so a full export is required to detect them.`,
+		Args: appcmd.MaximumNArgs(1),
+		Run: builder.NewRunFunc(
+			func(ctx context.Context, container appext.Container) error {
+				return run(ctx, container, flags)
+			},
+		),
+		BindFlags: flags.Bind,
+	}
+}
+
+type flags struct {
+	Organizations []string
+	Format        string
+	Since         string
+	PageSize      uint32
+}
+
+func newFlags() *flags {
+	return &flags{}
+}
+
+func (f *flags) Bind(flagSet *pflag.FlagSet) {
+	flagSet.StringSliceVar(
+		&f.Organizations,
+		organizationFlagName,
+		nil,
+		`The name of an organization to export. May be provided multiple times`,
+	)
+	_ = appcmd.MarkFlagRequired(flagSet, organizationFlagName)
+	flagSet.StringVar(
+		&f.Format,
+		formatFlagName,
+		formatJSON,
+		fmt.Sprintf(`The output format to use. Must be one of %s`, strings.Join(allFormats, ", ")),
+	)
+	flagSet.StringVar(
+		&f.Since,
+		sinceFlagName,
+		"",
+		`Only export what was created or updated since the time, in RFC 3339 format. Use the export_time of a previous export for an incremental export`,
+	)
+	flagSet.Uint32Var(
+		&f.PageSize,
+		pageSizeFlagName,
+		defaultPageSize,
+		`The number of modules or labels to request at a time`,
+	)
+}
+
+func run(
+	ctx context.Context,
+	container appext.Container,
+	flags *flags,
+) error {
+	remote := bufconnect.DefaultRemote
+	if container.NumArgs() == 1 {
+		remote = container.Arg(0)
+		if _, err := netext.ValidateHostname(remote); err != nil {
+			return appcmd.WrapInvalidArgumentError(err)
+		}
+	}
+	if !slices.Contains(allFormats, flags.Format) {
+		return appcmd.NewInvalidArgumentErrorf("--%s must be one of %s", formatFlagName, strings.Join(allFormats, ", "))
+	}
+	if flags.PageSize == 0 {
+		return appcmd.NewInvalidArgumentErrorf("--%s must be greater than 0", pageSizeFlagName)
+	}
+	var since time.Time
+	if flags.Since != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339, flags.Since)
+		if err != nil {
+			return appcmd.NewInvalidArgumentErrorf("--%s must be a time in RFC 3339 format: %v", sinceFlagName, err)
+		}
+	}
+	clientConfig, err := bufcli.NewConnectClientConfig(container)
+	if err != nil {
+		return err
+	}
+	exporter := &stateExporter{
+		organizationServiceClient: bufregistryapiowner.NewClientProvider(clientConfig).V1OrganizationServiceClient(remote),
+		moduleClientProvider:      bufregistryapimodule.NewClientProvider(clientConfig),
+		remote:                    remote,
+		since:                     since,
+		pageSize:                  flags.PageSize,
+	}
+	state := &externalState{
+		Version:    stateVersion,
+		Remote:     remote,
+		ExportTime: time.Now().UTC(),
+	}
+	if !since.IsZero() {
+		state.Since = &since
+	}
+	for _, organizationName := range flags.Organizations {
+		organization, err := exporter.exportOrganization(ctx, organizationName)
+		if err != nil {
+			return err
+		}
+		state.Organizations = append(state.Organizations, organization)
+	}
+	slices.SortFunc(state.Organizations, func(a *externalOrganization, b *externalOrganization) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	encoder := json.NewEncoder(container.Stdout())
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(state)
+}
+
+type stateExporter struct {
+	organizationServiceClient ownerv1connect.OrganizationServiceClient
+	moduleClientProvider      bufregistryapimodule.ClientProvider
+	remote                    string
+	// since is zero if the export is not incremental.
+	since    time.Time
+	pageSize uint32
+}
+
+func (e *stateExporter) exportOrganization(ctx context.Context, organizationName string) (*externalOrganization, error) {
+	response, err := e.organizationServiceClient.GetOrganizations(
+		ctx,
+		connect.NewRequest(
+			&ownerv1.GetOrganizationsRequest{
+				OrganizationRefs: []*ownerv1.OrganizationRef{
+					{
+						Value: &ownerv1.OrganizationRef_Name{
+							Name: organizationName,
+						},
+					},
+				},
+			},
+		),
+	)
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			return nil, bufcli.NewOrganizationNotFoundError(e.remote + "/" + organizationName)
+		}
+		return nil, err
+	}
+	organizations := response.Msg.GetOrganizations()
+	if len(organizations) != 1 {
+		return nil, syserror.Newf("unexpected number of organizations returned from server: %d", len(organizations))
+	}
+	organization := organizations[0]
+	modules, err := e.exportModules(ctx, organization)
+	if err != nil {
+		return nil, err
+	}
+	return &externalOrganization{
+		ID:                 organization.GetId(),
+		Name:               organization.GetName(),
+		Description:        organization.GetDescription(),
+		URL:                organization.GetUrl(),
+		VerificationStatus: enumToString(organization.GetVerificationStatus().String(), "ORGANIZATION_VERIFICATION_STATUS_"),
+		CreateTime:         organization.GetCreateTime().AsTime(),
+		UpdateTime:         organization.GetUpdateTime().AsTime(),
+		Modules:            modules,
+	}, nil
+}
+
+func (e *stateExporter) exportModules(ctx context.Context, organization *ownerv1.Organization) ([]*externalModule, error) {
+	moduleServiceClient := e.moduleClientProvider.V1ModuleServiceClient(e.remote)
+	externalModules := []*externalModule{}
+	var pageToken string
+	for {
+		response, err := moduleServiceClient.ListModules(
+			ctx,
+			connect.NewRequest(
+				&modulev1.ListModulesRequest{
+					PageSize:  e.pageSize,
+					PageToken: pageToken,
+					OwnerRefs: []*ownerv1.OwnerRef{
+						{
+							Value: &ownerv1.OwnerRef_Id{
+								Id: organization.GetId(),
+							},
+						},
+					},
+					Order: modulev1.ListModulesRequest_ORDER_CREATE_TIME_ASC,
+				},
+			),
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, module := range response.Msg.GetModules() {
+			labels, err := e.exportLabels(ctx, module)
+			if err != nil {
+				return nil, err
+			}
+			if !e.since.IsZero() && !isAfter(module.GetUpdateTime(), e.since) && len(labels) == 0 {
+				continue
+			}
+			externalModules = append(
+				externalModules,
+				&externalModule{
+					ID:               module.GetId(),
+					Name:             module.GetName(),
+					FullName:         fmt.Sprintf("%s/%s/%s", e.remote, organization.GetName(), module.GetName()),
+					Visibility:       enumToString(module.GetVisibility().String(), "MODULE_VISIBILITY_"),
+					State:            enumToString(module.GetState().String(), "MODULE_STATE_"),
+					Description:      module.GetDescription(),
+					URL:              module.GetUrl(),
+					DefaultLabelName: module.GetDefaultLabelName(),
+					CreateTime:       module.GetCreateTime().AsTime(),
+					UpdateTime:       module.GetUpdateTime().AsTime(),
+					Labels:           labels,
+				},
+			)
+		}
+		pageToken = response.Msg.GetNextPageToken()
+		if pageToken == "" {
+			break
+		}
+	}
+	slices.SortFunc(externalModules, func(a *externalModule, b *externalModule) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return externalModules, nil
+}
+
+func (e *stateExporter) exportLabels(ctx context.Context, module *modulev1.Module) ([]*externalLabel, error) {
+	labelServiceClient := e.moduleClientProvider.V1LabelServiceClient(e.remote)
+	externalLabels := []*externalLabel{}
+	var pageToken string
+	for {
+		response, err := labelServiceClient.ListLabels(
+			ctx,
+			connect.NewRequest(
+				&modulev1.ListLabelsRequest{
+					PageSize:  e.pageSize,
+					PageToken: pageToken,
+					ResourceRef: &modulev1.ResourceRef{
+						Value: &modulev1.ResourceRef_Id{
+							Id: module.GetId(),
+						},
+					},
+					// Labels are listed from the most recently updated, so that listing can
+					// stop at the first label that was not updated for incremental exports.
+					Order:         modulev1.ListLabelsRequest_ORDER_UPDATE_TIME_DESC,
+					ArchiveFilter: modulev1.ListLabelsRequest_ARCHIVE_FILTER_ALL,
+				},
+			),
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range response.Msg.GetLabels() {
+			if !e.since.IsZero() && !isAfter(label.GetUpdateTime(), e.since) {
+				return sortLabels(externalLabels), nil
+			}
+			externalLabel := &externalLabel{
+				ID:         label.GetId(),
+				Name:       label.GetName(),
+				CommitID:   label.GetCommitId(),
+				Archived:   label.GetArchiveTime() != nil,
+				CreateTime: label.GetCreateTime().AsTime(),
+				UpdateTime: label.GetUpdateTime().AsTime(),
+			}
+			if label.GetArchiveTime() != nil {
+				archiveTime := label.GetArchiveTime().AsTime()
+				externalLabel.ArchiveTime = &archiveTime
+			}
+			externalLabels = append(externalLabels, externalLabel)
+		}
+		pageToken = response.Msg.GetNextPageToken()
+		if pageToken == "" {
+			return sortLabels(externalLabels), nil
+		}
+	}
+}
+
+type externalState struct {
+	Version       string                  `json:"version"`
+	Remote        string                  `json:"remote"`
+	ExportTime    time.Time               `json:"export_time"`
+	Since         *time.Time              `json:"since,omitempty"`
+	Organizations []*externalOrganization `json:"organizations"`
+}
+
+type externalOrganization struct {
+	ID                 string            `json:"id"`
+	Name               string            `json:"name"`
+	Description        string            `json:"description"`
+	URL                string            `json:"url"`
+	VerificationStatus string            `json:"verification_status"`
+	CreateTime         time.Time         `json:"create_time"`
+	UpdateTime         time.Time         `json:"update_time"`
+	Modules            []*externalModule `json:"modules"`
+}
+
+type externalModule struct {
+	ID               string           `json:"id"`
+	Name             string           `json:"name"`
+	FullName         string           `json:"full_name"`
+	Visibility       string           `json:"visibility"`
+	State            string           `json:"state"`
+	Description      string           `json:"description"`
+	URL              string           `json:"url"`
+	DefaultLabelName string           `json:"default_label_name"`
+	CreateTime       time.Time        `json:"create_time"`
+	UpdateTime       time.Time        `json:"update_time"`
+	Labels           []*externalLabel `json:"labels"`
+}
+
+type externalLabel struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	CommitID    string     `json:"commit_id"`
+	Archived    bool       `json:"archived"`
+	ArchiveTime *time.Time `json:"archive_time,omitempty"`
+	CreateTime  time.Time  `json:"create_time"`
+	UpdateTime  time.Time  `json:"update_time"`
+}
+
+func sortLabels(externalLabels []*externalLabel) []*externalLabel {
+	slices.SortFunc(externalLabels, func(a *externalLabel, b *externalLabel) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return externalLabels
+}
+
+// enumToString returns the lowercase name of the enum value without the prefix,
+// such as "public" for MODULE_VISIBILITY_PUBLIC.
+func enumToString(enumValueName string, prefix string) string {
+	return strings.ToLower(strings.TrimPrefix(enumValueName, prefix))
+}
+
+func isAfter(timestamp *timestamppb.Timestamp, since time.Time) bool {
+	return timestamp.AsTime().After(since)
+}
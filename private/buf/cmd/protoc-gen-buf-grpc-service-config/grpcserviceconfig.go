@@ -0,0 +1,76 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcserviceconfig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufgrpcserviceconfig"
+	"github.com/bufbuild/buf/private/pkg/protodescriptor"
+	"github.com/bufbuild/protoplugin"
+)
+
+// Main is the main.
+func Main() {
+	protoplugin.Main(protoplugin.HandlerFunc(handle))
+}
+
+func handle(
+	_ context.Context,
+	_ protoplugin.PluginEnv,
+	responseWriter protoplugin.ResponseWriter,
+	request protoplugin.Request,
+) error {
+	responseWriter.SetFeatureProto3Optional()
+	responseWriter.SetFeatureSupportsEditions(protodescriptor.MinSupportedEdition, protodescriptor.MaxSupportedEdition)
+	if err := validateParameter(request.Parameter()); err != nil {
+		return err
+	}
+	fileDescriptors, err := request.FileDescriptorsToGenerate()
+	if err != nil {
+		return err
+	}
+	for _, fileDescriptor := range fileDescriptors {
+		serviceConfig, err := bufgrpcserviceconfig.GenerateServiceConfig(fileDescriptor)
+		if err != nil {
+			return err
+		}
+		if serviceConfig == "" {
+			continue
+		}
+		responseWriter.AddFile(strings.TrimSuffix(fileDescriptor.Path(), ".proto")+".service_config.json", serviceConfig)
+	}
+	return nil
+}
+
+// validateParameter validates the parameter, which is a comma-separated list of
+// key=value pairs.
+//
+// There are currently no parameter keys.
+func validateParameter(parameter string) error {
+	for _, pair := range strings.Split(parameter, ",") {
+		if pair == "" {
+			continue
+		}
+		key, _, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid parameter %q, expected key=value", pair)
+		}
+		return fmt.Errorf("unknown parameter key %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcserviceconfig
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduletesting"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/bufbuild/protoplugin"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestServiceConfig(t *testing.T) {
+	t.Parallel()
+	response, err := testRun(t, filepath.Join("testdata", "valid", "input"), "")
+	require.NoError(t, err)
+	files := response.GetFile()
+	// empty.proto does not contain any method configs.
+	require.Len(t, files, 1)
+	require.Equal(t, "acme/pet/v1/pet.service_config.json", files[0].GetName())
+	expected, err := os.ReadFile(filepath.Join("testdata", "valid", "output", "acme", "pet", "v1", "pet.service_config.json"))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), files[0].GetContent())
+}
+
+func TestUnknownParameter(t *testing.T) {
+	t.Parallel()
+	_, err := testRun(t, filepath.Join("testdata", "valid", "input"), "foo=bar")
+	require.EqualError(t, err, `unknown parameter key "foo"`)
+}
+
+func TestInvalidMethodConfig(t *testing.T) {
+	t.Parallel()
+	_, err := testRun(t, filepath.Join("testdata", "invalid"), "")
+	require.EqualError(t, err, `acme.pet.v1.PetService.GetPet: retry_policy.max_attempts must be greater than 1`)
+}
+
+func testRun(t *testing.T, dirPath string, parameter string) (*pluginpb.CodeGeneratorResponse, error) {
+	ctx := context.Background()
+	serviceConfigProtoData, err := os.ReadFile(
+		filepath.Join("..", "..", "..", "..", "proto", "buf", "alpha", "grpc", "v1alpha1", "service_config.proto"),
+	)
+	require.NoError(t, err)
+	moduleSet, err := bufmoduletesting.NewModuleSet(
+		bufmoduletesting.ModuleData{
+			DirPath: dirPath,
+		},
+		bufmoduletesting.ModuleData{
+			PathToData: map[string][]byte{
+				"buf/alpha/grpc/v1alpha1/service_config.proto": serviceConfigProtoData,
+			},
+			NotTargeted: true,
+		},
+	)
+	require.NoError(t, err)
+	image, err := bufimage.BuildImage(
+		ctx,
+		slogtestext.NewLogger(t),
+		bufmodule.ModuleSetToModuleReadBucketWithOnlyProtoFiles(moduleSet),
+	)
+	require.NoError(t, err)
+	request, err := bufimage.ImageToCodeGeneratorRequest(image, parameter, nil, false, false)
+	require.NoError(t, err)
+	requestData, err := proto.Marshal(request)
+	require.NoError(t, err)
+	stdout := bytes.NewBuffer(nil)
+	if err := protoplugin.Run(
+		ctx,
+		protoplugin.Env{
+			Stdin:  bytes.NewReader(requestData),
+			Stdout: stdout,
+			Stderr: bytes.NewBuffer(nil),
+		},
+		protoplugin.HandlerFunc(handle),
+	); err != nil {
+		return nil, err
+	}
+	response := &pluginpb.CodeGeneratorResponse{}
+	require.NoError(t, proto.Unmarshal(stdout.Bytes(), response))
+	return response, nil
+}
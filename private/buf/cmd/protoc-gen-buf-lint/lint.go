@@ -135,6 +135,12 @@ func handle(
 					return err
 				}
 			}
+			fileAnnotations := fileAnnotationSet.FileAnnotations()
+			if bufanalysis.CountFileAnnotationsWithSeverity(fileAnnotations, bufanalysis.SeverityWarning) == len(fileAnnotations) {
+				// Warnings do not fail the plugin, so they are printed to stderr instead.
+				_, err := pluginEnv.Stderr.Write(buffer.Bytes())
+				return err
+			}
 			responseWriter.AddError(strings.TrimSpace(buffer.String()))
 			return nil
 		}
@@ -0,0 +1,139 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduletesting"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/bufbuild/protoplugin"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestGo(t *testing.T) {
+	t.Parallel()
+	testGenerate(t, "lang=go", "acme/pet/v1/pet_otel.go")
+}
+
+func TestTypeScript(t *testing.T) {
+	t.Parallel()
+	testGenerate(t, "lang=typescript", "acme/pet/v1/pet_otel.ts")
+}
+
+func TestPython(t *testing.T) {
+	t.Parallel()
+	testGenerate(t, "lang=python", "acme/pet/v1/pet_otel.py")
+}
+
+func TestDefaultLanguage(t *testing.T) {
+	t.Parallel()
+	testGenerate(t, "", "acme/pet/v1/pet_otel.go")
+}
+
+func TestUnknownParameter(t *testing.T) {
+	t.Parallel()
+	_, err := testRun(t, map[string][]byte{"a.proto": []byte(`syntax = "proto3";`)}, "lang=rust")
+	require.EqualError(t, err, `unknown language: "rust"`)
+	_, err = testRun(t, map[string][]byte{"a.proto": []byte(`syntax = "proto3";`)}, "foo=bar")
+	require.EqualError(t, err, `unknown parameter key "foo"`)
+}
+
+func TestGoPackageNotSet(t *testing.T) {
+	t.Parallel()
+	_, err := testRun(
+		t,
+		map[string][]byte{
+			"a.proto": []byte(`syntax = "proto3";
+service FooService {}
+`),
+		},
+		"lang=go",
+	)
+	require.EqualError(t, err, `a.proto: go_package must be set to generate Go constants`)
+	response, err := testRun(
+		t,
+		map[string][]byte{
+			"a.proto": []byte(`syntax = "proto3";
+service FooService {}
+`),
+		},
+		"lang=typescript",
+	)
+	require.NoError(t, err)
+	require.Len(t, response.GetFile(), 1)
+}
+
+func testGenerate(t *testing.T, parameter string, expectedPath string) {
+	inputDirPath := filepath.Join("testdata", "input")
+	pathToData := make(map[string][]byte)
+	for _, path := range []string{"acme/pet/v1/pet.proto", "acme/pet/v1/empty.proto"} {
+		data, err := os.ReadFile(filepath.Join(inputDirPath, filepath.FromSlash(path)))
+		require.NoError(t, err)
+		pathToData[path] = data
+	}
+	response, err := testRun(t, pathToData, parameter)
+	require.NoError(t, err)
+	files := response.GetFile()
+	// empty.proto does not contain any services.
+	require.Len(t, files, 1)
+	require.Equal(t, expectedPath, files[0].GetName())
+	expected, err := os.ReadFile(filepath.Join("testdata", "output", filepath.FromSlash(expectedPath)))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), files[0].GetContent())
+}
+
+func testRun(t *testing.T, pathToData map[string][]byte, parameter string) (*pluginpb.CodeGeneratorResponse, error) {
+	ctx := context.Background()
+	moduleSet, err := bufmoduletesting.NewModuleSet(
+		bufmoduletesting.ModuleData{
+			PathToData: pathToData,
+		},
+	)
+	require.NoError(t, err)
+	image, err := bufimage.BuildImage(
+		ctx,
+		slogtestext.NewLogger(t),
+		bufmodule.ModuleSetToModuleReadBucketWithOnlyProtoFiles(moduleSet),
+	)
+	require.NoError(t, err)
+	request, err := bufimage.ImageToCodeGeneratorRequest(image, parameter, nil, false, false)
+	require.NoError(t, err)
+	requestData, err := proto.Marshal(request)
+	require.NoError(t, err)
+	stdout := bytes.NewBuffer(nil)
+	if err := protoplugin.Run(
+		ctx,
+		protoplugin.Env{
+			Stdin:  bytes.NewReader(requestData),
+			Stdout: stdout,
+			Stderr: bytes.NewBuffer(nil),
+		},
+		protoplugin.HandlerFunc(handle),
+	); err != nil {
+		return nil, err
+	}
+	response := &pluginpb.CodeGeneratorResponse{}
+	require.NoError(t, proto.Unmarshal(stdout.Bytes(), response))
+	return response, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufsql"
+	"github.com/bufbuild/buf/private/pkg/protodescriptor"
+	"github.com/bufbuild/protoplugin"
+)
+
+const dialectParameterKey = "dialect"
+
+// Main is the main.
+func Main() {
+	protoplugin.Main(protoplugin.HandlerFunc(handle))
+}
+
+func handle(
+	_ context.Context,
+	_ protoplugin.PluginEnv,
+	responseWriter protoplugin.ResponseWriter,
+	request protoplugin.Request,
+) error {
+	responseWriter.SetFeatureProto3Optional()
+	responseWriter.SetFeatureSupportsEditions(protodescriptor.MinSupportedEdition, protodescriptor.MaxSupportedEdition)
+	dialect, err := parseParameter(request.Parameter())
+	if err != nil {
+		return err
+	}
+	fileDescriptors, err := request.FileDescriptorsToGenerate()
+	if err != nil {
+		return err
+	}
+	for _, fileDescriptor := range fileDescriptors {
+		ddl, err := bufsql.GenerateDDL(fileDescriptor, dialect)
+		if err != nil {
+			return err
+		}
+		if ddl == "" {
+			continue
+		}
+		responseWriter.AddFile(strings.TrimSuffix(fileDescriptor.Path(), ".proto")+".sql", ddl)
+	}
+	return nil
+}
+
+// parseParameter parses the parameter, which is a comma-separated list of
+// key=value pairs.
+func parseParameter(parameter string) (bufsql.Dialect, error) {
+	var dialectString string
+	for _, pair := range strings.Split(parameter, ",") {
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return 0, fmt.Errorf("invalid parameter %q, expected key=value", pair)
+		}
+		switch key {
+		case dialectParameterKey:
+			dialectString = value
+		default:
+			return 0, fmt.Errorf("unknown parameter key %q", key)
+		}
+	}
+	return bufsql.ParseDialect(dialectString)
+}
@@ -0,0 +1,115 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduletesting"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/bufbuild/protoplugin"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestPostgres(t *testing.T) {
+	t.Parallel()
+	response, err := testRun(t, filepath.Join("testdata", "postgres", "input"), "dialect=postgres")
+	require.NoError(t, err)
+	files := response.GetFile()
+	// empty.proto does not contain any tables.
+	require.Len(t, files, 1)
+	require.Equal(t, "acme/user/v1/user.sql", files[0].GetName())
+	expected, err := os.ReadFile(filepath.Join("testdata", "postgres", "output", "acme", "user", "v1", "user.sql"))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), files[0].GetContent())
+}
+
+func TestDefaultDialect(t *testing.T) {
+	t.Parallel()
+	response, err := testRun(t, filepath.Join("testdata", "postgres", "input"), "")
+	require.NoError(t, err)
+	require.Len(t, response.GetFile(), 1)
+}
+
+func TestUnknownParameter(t *testing.T) {
+	t.Parallel()
+	_, err := testRun(t, filepath.Join("testdata", "postgres", "input"), "dialect=oracle")
+	require.EqualError(t, err, `unknown dialect: "oracle"`)
+	_, err = testRun(t, filepath.Join("testdata", "postgres", "input"), "foo=bar")
+	require.EqualError(t, err, `unknown parameter key "foo"`)
+}
+
+func TestPrimaryKeyNotFound(t *testing.T) {
+	t.Parallel()
+	_, err := testRun(t, filepath.Join("testdata", "invalid"), "")
+	require.EqualError(t, err, `acme.user.v1.User: primary key column "user_id" not found in table "user"`)
+}
+
+func testRun(t *testing.T, dirPath string, parameter string) (*pluginpb.CodeGeneratorResponse, error) {
+	ctx := context.Background()
+	sqlProtoData, err := os.ReadFile(
+		filepath.Join("..", "..", "..", "..", "proto", "buf", "alpha", "sql", "v1alpha1", "sql.proto"),
+	)
+	require.NoError(t, err)
+	moduleSet, err := bufmoduletesting.NewModuleSet(
+		bufmoduletesting.ModuleData{
+			DirPath: dirPath,
+		},
+		bufmoduletesting.ModuleData{
+			PathToData: map[string][]byte{
+				"buf/alpha/sql/v1alpha1/sql.proto": sqlProtoData,
+			},
+			NotTargeted: true,
+		},
+		bufmoduletesting.ModuleData{
+			DirPath:     filepath.Join("testdata", "vendor", "protovalidate"),
+			NotTargeted: true,
+		},
+	)
+	require.NoError(t, err)
+	image, err := bufimage.BuildImage(
+		ctx,
+		slogtestext.NewLogger(t),
+		bufmodule.ModuleSetToModuleReadBucketWithOnlyProtoFiles(moduleSet),
+	)
+	require.NoError(t, err)
+	request, err := bufimage.ImageToCodeGeneratorRequest(image, parameter, nil, false, false)
+	require.NoError(t, err)
+	requestData, err := proto.Marshal(request)
+	require.NoError(t, err)
+	stdout := bytes.NewBuffer(nil)
+	if err := protoplugin.Run(
+		ctx,
+		protoplugin.Env{
+			Stdin:  bytes.NewReader(requestData),
+			Stdout: stdout,
+			Stderr: bytes.NewBuffer(nil),
+		},
+		protoplugin.HandlerFunc(handle),
+	); err != nil {
+		return nil, err
+	}
+	response := &pluginpb.CodeGeneratorResponse{}
+	require.NoError(t, proto.Unmarshal(stdout.Bytes(), response))
+	return response, nil
+}
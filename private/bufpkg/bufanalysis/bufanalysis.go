@@ -34,6 +34,13 @@ const (
 	//
 	// See https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-error-message.
 	FormatGithubActions
+	// FormatSARIF is the SARIF 2.1.0 format for FileAnnotations.
+	//
+	// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+	FormatSARIF
+	// FormatMarkdown is a Markdown report of FileAnnotations grouped by type, suitable
+	// for posting as a pull request comment.
+	FormatMarkdown
 )
 
 var (
@@ -46,6 +53,8 @@ var (
 		"msvs",
 		"junit",
 		"github-actions",
+		"sarif",
+		"markdown",
 	}
 	// AllFormatStringsWithAliases is all format strings with aliases.
 	//
@@ -57,6 +66,8 @@ var (
 		"msvs",
 		"junit",
 		"github-actions",
+		"sarif",
+		"markdown",
 	}
 
 	stringToFormat = map[string]Format{
@@ -67,6 +78,8 @@ var (
 		"msvs":           FormatMSVS,
 		"junit":          FormatJUnit,
 		"github-actions": FormatGithubActions,
+		"sarif":          FormatSARIF,
+		"markdown":       FormatMarkdown,
 	}
 	formatToString = map[Format]string{
 		FormatText:          "text",
@@ -74,6 +87,8 @@ var (
 		FormatMSVS:          "msvs",
 		FormatJUnit:         "junit",
 		FormatGithubActions: "github-actions",
+		FormatSARIF:         "sarif",
+		FormatMarkdown:      "markdown",
 	}
 )
 
@@ -104,6 +119,95 @@ func ParseFormat(s string) (Format, error) {
 	return 0, fmt.Errorf("unknown format: %q", s)
 }
 
+const (
+	// ImpactWire is the impact of a breaking change that breaks the binary wire format.
+	ImpactWire Impact = iota + 1
+	// ImpactJSON is the impact of a breaking change that breaks the JSON format,
+	// but not the binary wire format.
+	ImpactJSON
+	// ImpactSource is the impact of a breaking change that breaks generated source
+	// code, but neither the binary wire format nor the JSON format.
+	ImpactSource
+	// ImpactSemantic is the impact of a breaking change that does not break the
+	// wire formats or generated source code, but may change behavior.
+	ImpactSemantic
+)
+
+var (
+	// AllImpactStrings is all impact strings.
+	//
+	// Sorted from most to least severe.
+	AllImpactStrings = []string{
+		"wire",
+		"json",
+		"source",
+		"semantic",
+	}
+
+	impactToString = map[Impact]string{
+		ImpactWire:     "wire",
+		ImpactJSON:     "json",
+		ImpactSource:   "source",
+		ImpactSemantic: "semantic",
+	}
+	stringToImpact = map[string]Impact{
+		"wire":     ImpactWire,
+		"json":     ImpactJSON,
+		"source":   ImpactSource,
+		"semantic": ImpactSemantic,
+	}
+)
+
+// Impact is the impact classification of a breaking change FileAnnotation.
+type Impact int
+
+// String implements fmt.Stringer.
+func (i Impact) String() string {
+	s, ok := impactToString[i]
+	if !ok {
+		return strconv.Itoa(int(i))
+	}
+	return s
+}
+
+// ParseImpact parses the Impact.
+func ParseImpact(s string) (Impact, error) {
+	i, ok := stringToImpact[strings.ToLower(strings.TrimSpace(s))]
+	if ok {
+		return i, nil
+	}
+	return 0, fmt.Errorf("unknown impact: %q", s)
+}
+
+const (
+	// SeverityError is the severity of a FileAnnotation that results in a failure.
+	//
+	// This is the default severity.
+	SeverityError Severity = iota + 1
+	// SeverityWarning is the severity of a FileAnnotation that is printed, but
+	// does not result in a failure.
+	SeverityWarning
+)
+
+var (
+	severityToString = map[Severity]string{
+		SeverityError:   "error",
+		SeverityWarning: "warning",
+	}
+)
+
+// Severity is the severity of a FileAnnotation.
+type Severity int
+
+// String implements fmt.Stringer.
+func (s Severity) String() string {
+	str, ok := severityToString[s]
+	if !ok {
+		return strconv.Itoa(int(s))
+	}
+	return str
+}
+
 // FileInfo is a minimal FileInfo interface.
 type FileInfo interface {
 	Path() string
@@ -149,6 +253,32 @@ type FileAnnotation interface {
 	// May be empty if this annotation did not originate from a plugin.
 	// This may be added to the printed message field for certain printers.
 	PluginName() string
+	// Impact is the impact classification of a breaking change.
+	//
+	// May be 0 if this annotation is not for a breaking change, such as for lint
+	// annotations.
+	Impact() Impact
+	// AgainstInputs are the against inputs that a breaking change was found against,
+	// when checking for breaking changes against multiple against inputs.
+	//
+	// May be empty if this annotation is not for a breaking change, or if breaking
+	// changes were checked against a single against input.
+	AgainstInputs() []string
+	// Severity is the severity of the annotation.
+	//
+	// This will be SeverityError unless set otherwise.
+	Severity() Severity
+	// CodeFrame is the excerpt of the source of the input at the location of the annotation.
+	//
+	// May be nil if code frames were not requested, or if the source is not available,
+	// such as for image inputs.
+	CodeFrame() CodeFrame
+	// AgainstCodeFrame is the excerpt of the source of the against input at the
+	// location of the annotation in the against input.
+	//
+	// May be nil if this annotation is not for a breaking change, if code frames were
+	// not requested, or if the source of the against input is not available.
+	AgainstCodeFrame() CodeFrame
 
 	isFileAnnotation()
 }
@@ -163,6 +293,7 @@ func NewFileAnnotation(
 	typeString string,
 	message string,
 	pluginName string,
+	options ...FileAnnotationOption,
 ) FileAnnotation {
 	return newFileAnnotation(
 		fileInfo,
@@ -173,9 +304,104 @@ func NewFileAnnotation(
 		typeString,
 		message,
 		pluginName,
+		options...,
+	)
+}
+
+// FileAnnotationOption is an option for a new FileAnnotation.
+type FileAnnotationOption func(*fileAnnotation)
+
+// FileAnnotationWithImpact returns a new FileAnnotationOption that sets the
+// impact classification of a breaking change.
+//
+// The default is to not classify the impact.
+func FileAnnotationWithImpact(impact Impact) FileAnnotationOption {
+	return func(fileAnnotation *fileAnnotation) {
+		fileAnnotation.impact = impact
+	}
+}
+
+// FileAnnotationWithAgainstInputs returns a new FileAnnotationOption that sets the
+// against inputs that a breaking change was found against.
+//
+// The default is to not set any against inputs.
+func FileAnnotationWithAgainstInputs(againstInputs ...string) FileAnnotationOption {
+	return func(fileAnnotation *fileAnnotation) {
+		fileAnnotation.againstInputs = againstInputs
+	}
+}
+
+// FileAnnotationWithSeverity returns a new FileAnnotationOption that sets the
+// severity of the annotation.
+//
+// The default is SeverityError.
+func FileAnnotationWithSeverity(severity Severity) FileAnnotationOption {
+	return func(fileAnnotation *fileAnnotation) {
+		fileAnnotation.severity = severity
+	}
+}
+
+// FileAnnotationWithCodeFrame returns a new FileAnnotationOption that sets the
+// excerpt of the source of the input at the location of the annotation.
+//
+// The default is to not set a code frame.
+func FileAnnotationWithCodeFrame(codeFrame CodeFrame) FileAnnotationOption {
+	return func(fileAnnotation *fileAnnotation) {
+		fileAnnotation.codeFrame = codeFrame
+	}
+}
+
+// FileAnnotationWithAgainstCodeFrame returns a new FileAnnotationOption that sets the
+// excerpt of the source of the against input at the location of a breaking change.
+//
+// The default is to not set an against code frame.
+func FileAnnotationWithAgainstCodeFrame(againstCodeFrame CodeFrame) FileAnnotationOption {
+	return func(fileAnnotation *fileAnnotation) {
+		fileAnnotation.againstCodeFrame = againstCodeFrame
+	}
+}
+
+// NewFileAnnotationWithOptions returns a copy of the FileAnnotation with the options applied.
+func NewFileAnnotationWithOptions(fileAnnotation FileAnnotation, options ...FileAnnotationOption) FileAnnotation {
+	return newFileAnnotation(
+		fileAnnotation.FileInfo(),
+		fileAnnotation.StartLine(),
+		fileAnnotation.StartColumn(),
+		fileAnnotation.EndLine(),
+		fileAnnotation.EndColumn(),
+		fileAnnotation.Type(),
+		fileAnnotation.Message(),
+		fileAnnotation.PluginName(),
+		append(
+			[]FileAnnotationOption{
+				FileAnnotationWithImpact(fileAnnotation.Impact()),
+				FileAnnotationWithAgainstInputs(fileAnnotation.AgainstInputs()...),
+				FileAnnotationWithSeverity(fileAnnotation.Severity()),
+				FileAnnotationWithCodeFrame(fileAnnotation.CodeFrame()),
+				FileAnnotationWithAgainstCodeFrame(fileAnnotation.AgainstCodeFrame()),
+			},
+			options...,
+		)...,
 	)
 }
 
+// CodeFrame is an excerpt of the source of a file.
+type CodeFrame interface {
+	// Path is the external path of the file that the excerpt is from.
+	Path() string
+	// StartLine is the line number of the first line of the excerpt.
+	StartLine() int
+	// Lines are the lines of the excerpt, without line endings.
+	Lines() []string
+
+	isCodeFrame()
+}
+
+// NewCodeFrame returns a new CodeFrame.
+func NewCodeFrame(path string, startLine int, lines []string) CodeFrame {
+	return newCodeFrame(path, startLine, lines)
+}
+
 // FileAnnotationSet is a set of FileAnnotations.
 type FileAnnotationSet interface {
 	// Stringer returns the string representation for this FileAnnotationSet.
@@ -200,25 +426,173 @@ func NewFileAnnotationSet(fileAnnotations ...FileAnnotation) FileAnnotationSet {
 	return newFileAnnotationSet(fileAnnotations)
 }
 
+// CountFileAnnotationsWithSeverity returns the number of FileAnnotations with
+// the given Severity.
+func CountFileAnnotationsWithSeverity(fileAnnotations []FileAnnotation, severity Severity) int {
+	var count int
+	for _, fileAnnotation := range fileAnnotations {
+		if fileAnnotation.Severity() == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// RuleInfo is the metadata of the rule that produces FileAnnotations of a given type.
+type RuleInfo struct {
+	// ID is the ID of the rule, which is the type of the FileAnnotations it produces.
+	ID string
+	// Purpose is the purpose of the rule.
+	Purpose string
+	// Categories are the IDs of the categories of the rule.
+	Categories []string
+	// Severity is the configured severity of the FileAnnotations the rule produces.
+	//
+	// SeverityError if not set.
+	Severity Severity
+}
+
+// SuggestedEdit is an edit of a file that is suggested to fix a FileAnnotation.
+type SuggestedEdit struct {
+	// Path is the external path of the file to edit.
+	Path string
+	// StartOffset is the 0-indexed byte offset of the start of the range to replace.
+	StartOffset int
+	// EndOffset is the 0-indexed byte offset of the end of the range to replace, exclusive.
+	EndOffset int
+	// Replacement is the text to replace the range with, or empty if the range is removed.
+	Replacement string
+}
+
 // PrintFileAnnotationSet prints the file annotations separated by newlines.
-func PrintFileAnnotationSet(writer io.Writer, fileAnnotationSet FileAnnotationSet, formatString string) error {
+//
+// For FormatSARIF, the file annotations are printed as a single SARIF log, and a log
+// with no results is printed if fileAnnotationSet is nil. Likewise, for FormatJUnit,
+// a report with no test suites is printed if fileAnnotationSet is nil, and for FormatMarkdown,
+// a report stating that no issues were found is printed if fileAnnotationSet is nil.
+func PrintFileAnnotationSet(
+	writer io.Writer,
+	fileAnnotationSet FileAnnotationSet,
+	formatString string,
+	options ...PrintOption,
+) error {
 	format, err := ParseFormat(formatString)
 	if err != nil {
 		return err
 	}
+	printOptions := newPrintOptions()
+	for _, option := range options {
+		option(printOptions)
+	}
+	if format == FormatSARIF {
+		var fileAnnotations []FileAnnotation
+		if fileAnnotationSet != nil {
+			fileAnnotations = fileAnnotationSet.FileAnnotations()
+		}
+		return printAsSARIF(writer, fileAnnotations, printOptions.ruleInfos)
+	}
+	if format == FormatJUnit {
+		var fileAnnotations []FileAnnotation
+		if fileAnnotationSet != nil {
+			fileAnnotations = fileAnnotationSet.FileAnnotations()
+		}
+		return printAsJUnit(writer, fileAnnotations)
+	}
+	if format == FormatMarkdown {
+		var fileAnnotations []FileAnnotation
+		if fileAnnotationSet != nil {
+			fileAnnotations = fileAnnotationSet.FileAnnotations()
+		}
+		return printAsMarkdown(writer, fileAnnotations, printOptions.fileLinkBaseURL)
+	}
 
 	switch format {
 	case FormatText:
 		return printAsText(writer, fileAnnotationSet.FileAnnotations())
 	case FormatJSON:
-		return printAsJSON(writer, fileAnnotationSet.FileAnnotations())
+		return printAsJSON(writer, fileAnnotationSet.FileAnnotations(), printOptions.fileAnnotationToSuggestedEdits)
 	case FormatMSVS:
 		return printAsMSVS(writer, fileAnnotationSet.FileAnnotations())
-	case FormatJUnit:
-		return printAsJUnit(writer, fileAnnotationSet.FileAnnotations())
 	case FormatGithubActions:
 		return printAsGithubActions(writer, fileAnnotationSet.FileAnnotations())
 	default:
 		return fmt.Errorf("unknown FileAnnotation Format: %v", format)
 	}
 }
+
+// PrintFileAnnotationSetSummary prints a summary of the file annotations, aggregated
+// by rule and by file, with the counts of each.
+//
+// Only FormatText and FormatJSON are supported. Nothing is printed for FormatText if
+// fileAnnotationSet is nil.
+func PrintFileAnnotationSetSummary(
+	writer io.Writer,
+	fileAnnotationSet FileAnnotationSet,
+	formatString string,
+) error {
+	format, err := ParseFormat(formatString)
+	if err != nil {
+		return err
+	}
+	var fileAnnotations []FileAnnotation
+	if fileAnnotationSet != nil {
+		fileAnnotations = fileAnnotationSet.FileAnnotations()
+	}
+	switch format {
+	case FormatText:
+		return printSummaryAsText(writer, fileAnnotations)
+	case FormatJSON:
+		return printSummaryAsJSON(writer, fileAnnotations)
+	default:
+		return fmt.Errorf("summaries are not supported for FileAnnotation Format: %v", format)
+	}
+}
+
+// PrintOption is an option for PrintFileAnnotationSet.
+type PrintOption func(*printOptions)
+
+// PrintWithRuleInfos returns a new PrintOption that adds the metadata of the rules
+// that produce the file annotations, for formats that include rule metadata.
+//
+// This only affects FormatSARIF. The default is to only include the IDs of the rules,
+// as derived from the types of the file annotations.
+func PrintWithRuleInfos(ruleInfos ...RuleInfo) PrintOption {
+	return func(printOptions *printOptions) {
+		printOptions.ruleInfos = append(printOptions.ruleInfos, ruleInfos...)
+	}
+}
+
+// PrintWithSuggestedEdits returns a new PrintOption that adds the edits suggested
+// to fix each file annotation, for formats that include suggested edits.
+//
+// All edits of a file annotation should be applied together, and the offsets of
+// the edits are relative to the files before any edits are applied.
+//
+// This only affects FormatJSON. The default is to not include suggested edits.
+func PrintWithSuggestedEdits(fileAnnotationToSuggestedEdits map[FileAnnotation][]SuggestedEdit) PrintOption {
+	return func(printOptions *printOptions) {
+		printOptions.fileAnnotationToSuggestedEdits = fileAnnotationToSuggestedEdits
+	}
+}
+
+// PrintWithFileLinkBaseURL returns a new PrintOption that links the locations of the
+// file annotations to baseURL/path#Lline, for formats that include links.
+//
+// This only affects FormatMarkdown. The default is to not link locations.
+func PrintWithFileLinkBaseURL(baseURL string) PrintOption {
+	return func(printOptions *printOptions) {
+		printOptions.fileLinkBaseURL = baseURL
+	}
+}
+
+// *** PRIVATE ***
+
+type printOptions struct {
+	ruleInfos                      []RuleInfo
+	fileAnnotationToSuggestedEdits map[FileAnnotation][]SuggestedEdit
+	fileLinkBaseURL                string
+}
+
+func newPrintOptions() *printOptions {
+	return &printOptions{}
+}
@@ -89,6 +89,7 @@ func newFileAnnotation(
 	typeString string,
 	message string,
 	pluginName string,
+	options ...bufanalysis.FileAnnotationOption,
 ) bufanalysis.FileAnnotation {
 	var fileInfo bufanalysis.FileInfo
 	if path != "" {
@@ -103,6 +104,7 @@ func newFileAnnotation(
 		typeString,
 		message,
 		pluginName,
+		options...,
 	)
 }
 
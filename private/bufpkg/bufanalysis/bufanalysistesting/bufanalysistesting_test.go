@@ -84,10 +84,10 @@ path/to/file.proto(2,1) : error FOO : Hello. (buf-plugin-foo)
 	assert.Equal(t,
 		`<testsuites>
   <testsuite name="path/to/file" tests="2" failures="2" errors="0">
-    <testcase name="FOO_1">
+    <testcase name="FOO_1" classname="path/to/file" file="path/to/file.proto" line="1">
       <failure message="path/to/file.proto:1:1:Hello." type="FOO"></failure>
     </testcase>
-    <testcase name="FOO_2_1">
+    <testcase name="FOO_2_1" classname="path/to/file" file="path/to/file.proto" line="2">
       <failure message="path/to/file.proto:2:1:Hello. (buf-plugin-foo)" type="FOO"></failure>
     </testcase>
   </testsuite>
@@ -96,6 +96,10 @@ path/to/file.proto(2,1) : error FOO : Hello. (buf-plugin-foo)
 		sb.String(),
 	)
 	sb.Reset()
+	err = bufanalysis.PrintFileAnnotationSet(sb, nil, "junit")
+	require.NoError(t, err)
+	assert.Equal(t, "<testsuites></testsuites>\n", sb.String())
+	sb.Reset()
 	err = bufanalysis.PrintFileAnnotationSet(
 		sb,
 		bufanalysis.NewFileAnnotationSet(
@@ -137,3 +141,385 @@ path/to/file.proto(2,1) : error FOO : Hello. (buf-plugin-foo)
 		sb.String(),
 	)
 }
+
+func TestPrintGithubActionsEscaping(t *testing.T) {
+	t.Parallel()
+	sb := &strings.Builder{}
+	err := bufanalysis.PrintFileAnnotationSet(
+		sb,
+		bufanalysis.NewFileAnnotationSet(
+			newFileAnnotation(
+				t,
+				"path/to/a,b:c.proto",
+				1,
+				2,
+				1,
+				5,
+				"FOO",
+				"Hello, 100%.\nGoodbye.",
+				"",
+			),
+		),
+		"github-actions",
+	)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"::error file=path/to/a%2Cb%3Ac.proto,line=1,col=2,endLine=1,endColumn=5::Hello, 100%25.%0AGoodbye.\n",
+		sb.String(),
+	)
+}
+
+func TestSARIF(t *testing.T) {
+	t.Parallel()
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		newFileAnnotation(
+			t,
+			"path/to/file.proto",
+			2,
+			3,
+			2,
+			10,
+			"FOO",
+			"Hello.",
+			"",
+		),
+		newFileAnnotation(
+			t,
+			"",
+			0,
+			0,
+			0,
+			0,
+			"BAR",
+			"Goodbye.",
+			"buf-plugin-foo",
+		),
+	}
+	sb := &strings.Builder{}
+	err := bufanalysis.PrintFileAnnotationSet(
+		sb,
+		bufanalysis.NewFileAnnotationSet(fileAnnotations...),
+		"sarif",
+		bufanalysis.PrintWithRuleInfos(
+			bufanalysis.RuleInfo{
+				ID:         "FOO",
+				Purpose:    "Checks foo.",
+				Categories: []string{"BASIC"},
+			},
+			bufanalysis.RuleInfo{
+				ID:       "BAZ",
+				Severity: bufanalysis.SeverityWarning,
+			},
+		),
+	)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "buf",
+          "informationUri": "https://github.com/bufbuild/buf",
+          "rules": [
+            {
+              "id": "FOO",
+              "shortDescription": {
+                "text": "Checks foo."
+              },
+              "defaultConfiguration": {
+                "level": "error"
+              },
+              "properties": {
+                "tags": [
+                  "BASIC"
+                ]
+              }
+            },
+            {
+              "id": "BAZ",
+              "defaultConfiguration": {
+                "level": "warning"
+              }
+            },
+            {
+              "id": "BAR",
+              "defaultConfiguration": {
+                "level": "error"
+              }
+            }
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "BAR",
+          "ruleIndex": 2,
+          "level": "error",
+          "message": {
+            "text": "Goodbye."
+          },
+          "properties": {
+            "plugin": "buf-plugin-foo"
+          }
+        },
+        {
+          "ruleId": "FOO",
+          "ruleIndex": 0,
+          "level": "error",
+          "message": {
+            "text": "Hello."
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "path/to/file.proto"
+                },
+                "region": {
+                  "startLine": 2,
+                  "startColumn": 3,
+                  "endLine": 2,
+                  "endColumn": 10
+                }
+              }
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`,
+		sb.String(),
+	)
+	// A log with no results is printed if there are no FileAnnotations.
+	sb.Reset()
+	err = bufanalysis.PrintFileAnnotationSet(sb, nil, "sarif")
+	require.NoError(t, err)
+	assert.Contains(t, sb.String(), `"results": []`)
+}
+
+func TestMarkdown(t *testing.T) {
+	t.Parallel()
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		newFileAnnotation(
+			t,
+			"path/to/file.proto",
+			5,
+			3,
+			5,
+			20,
+			"FIELD_SAME_TYPE",
+			`Field "1" with name "id" on message "Foo" changed type from "int32" to "string".`,
+			"",
+			bufanalysis.FileAnnotationWithImpact(bufanalysis.ImpactWire),
+		),
+		newFileAnnotation(
+			t,
+			"path/to/file.proto",
+			10,
+			1,
+			12,
+			2,
+			"FIELD_NO_DELETE",
+			`Previously present field "2" with name "a|b" on message "Bar" was deleted.`,
+			"",
+			bufanalysis.FileAnnotationWithImpact(bufanalysis.ImpactWire),
+		),
+		newFileAnnotation(
+			t,
+			"path/to/other.proto",
+			1,
+			1,
+			1,
+			1,
+			"FIELD_NO_DELETE",
+			`Previously present field "3" with name "baz" on message "Baz" was deleted.`,
+			"",
+			bufanalysis.FileAnnotationWithImpact(bufanalysis.ImpactWire),
+		),
+	}
+	sb := &strings.Builder{}
+	err := bufanalysis.PrintFileAnnotationSet(
+		sb,
+		bufanalysis.NewFileAnnotationSet(fileAnnotations...),
+		"markdown",
+		bufanalysis.PrintWithFileLinkBaseURL("https://github.com/acme/api/blob/abc"),
+	)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`Found 3 issues in 2 files.
+
+### FIELD_NO_DELETE (2)
+
+| Location | Impact | Before | After | Message |
+| --- | --- | --- | --- | --- |
+| [`+"`path/to/file.proto:10:1`"+`](https://github.com/acme/api/blob/abc/path/to/file.proto#L10-L12) | wire |  |  | Previously present field "2" with name "a\|b" on message "Bar" was deleted. |
+| [`+"`path/to/other.proto:1:1`"+`](https://github.com/acme/api/blob/abc/path/to/other.proto#L1) | wire |  |  | Previously present field "3" with name "baz" on message "Baz" was deleted. |
+
+### FIELD_SAME_TYPE (1)
+
+| Location | Impact | Before | After | Message |
+| --- | --- | --- | --- | --- |
+| [`+"`path/to/file.proto:5:3`"+`](https://github.com/acme/api/blob/abc/path/to/file.proto#L5) | wire | `+"`int32`"+` | `+"`string`"+` | Field "1" with name "id" on message "Foo" changed type from "int32" to "string". |
+`,
+		sb.String(),
+	)
+	// Locations are not linked without a base URL, and columns without values are omitted.
+	sb.Reset()
+	err = bufanalysis.PrintFileAnnotationSet(
+		sb,
+		bufanalysis.NewFileAnnotationSet(
+			newFileAnnotation(t, "a.proto", 1, 1, 1, 1, "FOO", "Hello.", ""),
+		),
+		"markdown",
+	)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`Found 1 issue in 1 file.
+
+### FOO (1)
+
+| Location | Message |
+| --- | --- |
+| `+"`a.proto:1:1`"+` | Hello. |
+`,
+		sb.String(),
+	)
+	sb.Reset()
+	err = bufanalysis.PrintFileAnnotationSet(sb, nil, "markdown")
+	require.NoError(t, err)
+	assert.Equal(t, "No issues found.\n", sb.String())
+}
+
+func TestSeverity(t *testing.T) {
+	t.Parallel()
+	fileAnnotation := newFileAnnotation(
+		t,
+		"path/to/file.proto",
+		2,
+		3,
+		2,
+		10,
+		"FOO",
+		"Hello.",
+		"",
+		bufanalysis.FileAnnotationWithSeverity(bufanalysis.SeverityWarning),
+	)
+	assert.Equal(t, bufanalysis.SeverityWarning, fileAnnotation.Severity())
+	for format, expected := range map[string]string{
+		"text":           "path/to/file.proto:2:3:Hello. [warning]\n",
+		"msvs":           "path/to/file.proto(2,3) : warning FOO : Hello.\n",
+		"github-actions": "::warning file=path/to/file.proto,line=2,col=3,endLine=2,endColumn=10::Hello.\n",
+		"json":           `{"path":"path/to/file.proto","start_line":2,"start_column":3,"end_line":2,"end_column":10,"type":"FOO","message":"Hello.","severity":"warning"}` + "\n",
+	} {
+		sb := &strings.Builder{}
+		err := bufanalysis.PrintFileAnnotationSet(
+			sb,
+			bufanalysis.NewFileAnnotationSet(fileAnnotation),
+			format,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, expected, sb.String(), format)
+	}
+}
+
+func TestCodeFrame(t *testing.T) {
+	t.Parallel()
+	fileAnnotation := newFileAnnotation(
+		t,
+		"path/to/file.proto",
+		9,
+		3,
+		9,
+		10,
+		"FIELD_SAME_TYPE",
+		"Hello.",
+		"",
+		bufanalysis.FileAnnotationWithCodeFrame(
+			bufanalysis.NewCodeFrame("path/to/file.proto", 9, []string{"  int64 one = 1;", "  int64 two = 2;"}),
+		),
+		bufanalysis.FileAnnotationWithAgainstCodeFrame(
+			bufanalysis.NewCodeFrame("against/path/to/file.proto", 10, []string{"  int32 one = 1;"}),
+		),
+	)
+	for format, expected := range map[string]string{
+		"text": `path/to/file.proto:9:3:Hello.
+  before (against/path/to/file.proto:10):
+    10 |   int32 one = 1;
+  after (path/to/file.proto:9):
+     9 |   int64 one = 1;
+    10 |   int64 two = 2;
+`,
+		"json": `{"path":"path/to/file.proto","start_line":9,"start_column":3,"end_line":9,"end_column":10,"type":"FIELD_SAME_TYPE","message":"Hello.","code_frame":{"path":"path/to/file.proto","start_line":9,"lines":["  int64 one = 1;","  int64 two = 2;"]},"against_code_frame":{"path":"against/path/to/file.proto","start_line":10,"lines":["  int32 one = 1;"]}}` + "\n",
+		"msvs": "path/to/file.proto(9,3) : error FIELD_SAME_TYPE : Hello.\n",
+	} {
+		sb := &strings.Builder{}
+		err := bufanalysis.PrintFileAnnotationSet(
+			sb,
+			bufanalysis.NewFileAnnotationSet(fileAnnotation),
+			format,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, expected, sb.String(), format)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+	fileAnnotationSet := bufanalysis.NewFileAnnotationSet(
+		newFileAnnotation(t, "a.proto", 1, 1, 1, 1, "FOO", "Hello.", ""),
+		newFileAnnotation(t, "b.proto", 1, 1, 1, 1, "FOO", "Hello.", ""),
+		newFileAnnotation(t, "b.proto", 2, 1, 2, 1, "FOO", "Hello.", ""),
+		newFileAnnotation(
+			t,
+			"a.proto",
+			3,
+			1,
+			3,
+			1,
+			"BAR",
+			"Hello.",
+			"",
+			bufanalysis.FileAnnotationWithSeverity(bufanalysis.SeverityWarning),
+		),
+	)
+	sb := &strings.Builder{}
+	err := bufanalysis.PrintFileAnnotationSetSummary(sb, fileAnnotationSet, "text")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`RULE  FILE     COUNT
+FOO   b.proto  2
+FOO   a.proto  1
+BAR   a.proto  1
+
+RULE   FILES  COUNT
+FOO    2      3
+BAR    1      1 (1 warning)
+TOTAL  2      4
+`,
+		sb.String(),
+	)
+	sb.Reset()
+	err = bufanalysis.PrintFileAnnotationSetSummary(sb, fileAnnotationSet, "json")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`{"total":4,"rules":[{"rule":"FOO","count":3,"files":[{"path":"b.proto","count":2},{"path":"a.proto","count":1}]},{"rule":"BAR","count":1,"warnings":1,"files":[{"path":"a.proto","count":1}]}]}`+"\n",
+		sb.String(),
+	)
+	sb.Reset()
+	err = bufanalysis.PrintFileAnnotationSetSummary(sb, nil, "text")
+	require.NoError(t, err)
+	assert.Empty(t, sb.String())
+	err = bufanalysis.PrintFileAnnotationSetSummary(sb, fileAnnotationSet, "sarif")
+	require.Error(t, err)
+}
@@ -0,0 +1,43 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufanalysis
+
+type codeFrame struct {
+	path      string
+	startLine int
+	lines     []string
+}
+
+func newCodeFrame(path string, startLine int, lines []string) *codeFrame {
+	return &codeFrame{
+		path:      path,
+		startLine: startLine,
+		lines:     lines,
+	}
+}
+
+func (c *codeFrame) Path() string {
+	return c.path
+}
+
+func (c *codeFrame) StartLine() int {
+	return c.startLine
+}
+
+func (c *codeFrame) Lines() []string {
+	return c.lines
+}
+
+func (*codeFrame) isCodeFrame() {}
@@ -28,6 +28,12 @@ type fileAnnotation struct {
 	typeString  string
 	message     string
 	pluginName  string
+	impact      Impact
+	// againstInputs are the against inputs that a breaking change was found against.
+	againstInputs    []string
+	severity         Severity
+	codeFrame        CodeFrame
+	againstCodeFrame CodeFrame
 }
 
 func newFileAnnotation(
@@ -39,8 +45,9 @@ func newFileAnnotation(
 	typeString string,
 	message string,
 	pluginName string,
+	options ...FileAnnotationOption,
 ) *fileAnnotation {
-	return &fileAnnotation{
+	fileAnnotation := &fileAnnotation{
 		fileInfo:    fileInfo,
 		startLine:   startLine,
 		startColumn: startColumn,
@@ -49,7 +56,12 @@ func newFileAnnotation(
 		typeString:  typeString,
 		message:     message,
 		pluginName:  pluginName,
+		severity:    SeverityError,
 	}
+	for _, option := range options {
+		option(fileAnnotation)
+	}
+	return fileAnnotation
 }
 
 func (f *fileAnnotation) FileInfo() FileInfo {
@@ -84,6 +96,26 @@ func (f *fileAnnotation) PluginName() string {
 	return f.pluginName
 }
 
+func (f *fileAnnotation) Impact() Impact {
+	return f.impact
+}
+
+func (f *fileAnnotation) AgainstInputs() []string {
+	return f.againstInputs
+}
+
+func (f *fileAnnotation) Severity() Severity {
+	return f.severity
+}
+
+func (f *fileAnnotation) CodeFrame() CodeFrame {
+	return f.codeFrame
+}
+
+func (f *fileAnnotation) AgainstCodeFrame() CodeFrame {
+	return f.againstCodeFrame
+}
+
 func (f *fileAnnotation) String() string {
 	if f == nil {
 		return ""
@@ -115,6 +147,9 @@ func (f *fileAnnotation) String() string {
 		_, _ = buffer.WriteString(f.pluginName)
 		_, _ = buffer.WriteRune(')')
 	}
+	writeImpact(buffer, f.impact)
+	writeAgainstInputs(buffer, f.againstInputs)
+	writeSeverity(buffer, f.severity)
 	return buffer.String()
 }
 
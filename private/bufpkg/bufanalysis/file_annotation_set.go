@@ -35,6 +35,9 @@ func newFileAnnotationSet(fileAnnotations []FileAnnotation) *fileAnnotationSet {
 }
 
 func (f *fileAnnotationSet) FileAnnotations() []FileAnnotation {
+	if f == nil {
+		return nil
+	}
 	return f.fileAnnotations
 }
 
@@ -0,0 +1,178 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufanalysis
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// markdownBeforeAfterRegexp matches the `from "before" to "after"` phrasing of the messages
+	// of most breaking change rules.
+	markdownBeforeAfterRegexp = regexp.MustCompile(`from "([^"]*)" to "([^"]*)"`)
+	// markdownTableCellEscaper escapes the content of a Markdown table cell.
+	markdownTableCellEscaper = strings.NewReplacer(
+		"|", `\|`,
+		"\r\n", " ",
+		"\n", " ",
+	)
+)
+
+// printAsMarkdown prints the file annotations as a Markdown report, grouped by type, suitable
+// for a pull request comment.
+//
+// Columns for the impact, the values before and after, and the against inputs of a breaking
+// change are only printed if any file annotation has them. If fileLinkBaseURL is not empty, locations are linked to
+// fileLinkBaseURL/path#Lline.
+func printAsMarkdown(writer io.Writer, fileAnnotations []FileAnnotation, fileLinkBaseURL string) error {
+	buffer := bytes.NewBuffer(nil)
+	if len(fileAnnotations) == 0 {
+		buffer.WriteString("No issues found.\n")
+		_, err := writer.Write(buffer.Bytes())
+		return err
+	}
+	paths := make(map[string]struct{})
+	typeToFileAnnotations := make(map[string][]FileAnnotation)
+	var hasImpact bool
+	var hasBeforeAfter bool
+	var hasAgainstInputs bool
+	for _, fileAnnotation := range fileAnnotations {
+		if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+			paths[fileInfo.ExternalPath()] = struct{}{}
+		}
+		typeToFileAnnotations[fileAnnotation.Type()] = append(typeToFileAnnotations[fileAnnotation.Type()], fileAnnotation)
+		if fileAnnotation.Impact() != 0 {
+			hasImpact = true
+		}
+		if markdownBeforeAfterRegexp.MatchString(fileAnnotation.Message()) {
+			hasBeforeAfter = true
+		}
+		if len(fileAnnotation.AgainstInputs()) > 0 {
+			hasAgainstInputs = true
+		}
+	}
+	_, _ = fmt.Fprintf(
+		buffer,
+		"Found %d %s in %d %s.\n",
+		len(fileAnnotations),
+		pluralize("issue", len(fileAnnotations)),
+		len(paths),
+		pluralize("file", len(paths)),
+	)
+	types := make([]string, 0, len(typeToFileAnnotations))
+	for typeString := range typeToFileAnnotations {
+		types = append(types, typeString)
+	}
+	sort.Strings(types)
+	columns := []string{"Location"}
+	if hasImpact {
+		columns = append(columns, "Impact")
+	}
+	if hasBeforeAfter {
+		columns = append(columns, "Before", "After")
+	}
+	if hasAgainstInputs {
+		columns = append(columns, "Against")
+	}
+	columns = append(columns, "Message")
+	for _, typeString := range types {
+		typeFileAnnotations := typeToFileAnnotations[typeString]
+		heading := typeString
+		if heading == "" {
+			heading = "Other"
+		}
+		_, _ = fmt.Fprintf(buffer, "\n### %s (%d)\n\n", heading, len(typeFileAnnotations))
+		buffer.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+		buffer.WriteString(strings.Repeat("| --- ", len(columns)) + "|\n")
+		for _, fileAnnotation := range typeFileAnnotations {
+			cells := []string{getMarkdownLocation(fileAnnotation, fileLinkBaseURL)}
+			if hasImpact {
+				var impact string
+				if fileAnnotation.Impact() != 0 {
+					impact = fileAnnotation.Impact().String()
+				}
+				cells = append(cells, impact)
+			}
+			if hasBeforeAfter {
+				var before string
+				var after string
+				if submatches := markdownBeforeAfterRegexp.FindStringSubmatch(fileAnnotation.Message()); submatches != nil {
+					before = getMarkdownCode(submatches[1])
+					after = getMarkdownCode(submatches[2])
+				}
+				cells = append(cells, before, after)
+			}
+			if hasAgainstInputs {
+				againstInputs := make([]string, len(fileAnnotation.AgainstInputs()))
+				for i, againstInput := range fileAnnotation.AgainstInputs() {
+					againstInputs[i] = getMarkdownCode(againstInput)
+				}
+				cells = append(cells, strings.Join(againstInputs, ", "))
+			}
+			message := fileAnnotation.Message()
+			if fileAnnotation.Severity() == SeverityWarning {
+				message = "**Warning:** " + message
+			}
+			cells = append(cells, markdownTableCellEscaper.Replace(message))
+			buffer.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+	}
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
+
+func getMarkdownLocation(fileAnnotation FileAnnotation, fileLinkBaseURL string) string {
+	fileInfo := fileAnnotation.FileInfo()
+	if fileInfo == nil {
+		return ""
+	}
+	location := fileInfo.ExternalPath()
+	if fileAnnotation.StartLine() != 0 {
+		location += ":" + strconv.Itoa(fileAnnotation.StartLine())
+		if fileAnnotation.StartColumn() != 0 {
+			location += ":" + strconv.Itoa(fileAnnotation.StartColumn())
+		}
+	}
+	location = getMarkdownCode(location)
+	if fileLinkBaseURL == "" {
+		return location
+	}
+	link := strings.TrimSuffix(fileLinkBaseURL, "/") + "/" + strings.TrimPrefix(fileInfo.ExternalPath(), "./")
+	if fileAnnotation.StartLine() != 0 {
+		link += "#L" + strconv.Itoa(fileAnnotation.StartLine())
+		if fileAnnotation.EndLine() > fileAnnotation.StartLine() {
+			link += "-L" + strconv.Itoa(fileAnnotation.EndLine())
+		}
+	}
+	return "[" + location + "](" + link + ")"
+}
+
+// getMarkdownCode returns the value as an inline code span for a table cell.
+func getMarkdownCode(value string) string {
+	if value == "" {
+		return ""
+	}
+	value = markdownTableCellEscaper.Replace(value)
+	if strings.Contains(value, "`") {
+		return "`` " + value + " ``"
+	}
+	return "`" + value + "`"
+}
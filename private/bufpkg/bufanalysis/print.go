@@ -24,6 +24,25 @@ import (
 	"strings"
 )
 
+var (
+	// githubActionsDataEscaper escapes the message of a GitHub Actions workflow command.
+	//
+	// See https://github.com/actions/toolkit/blob/main/packages/core/src/command.ts.
+	githubActionsDataEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+	)
+	// githubActionsPropertyEscaper escapes the property values of a GitHub Actions workflow command.
+	githubActionsPropertyEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+		":", "%3A",
+		",", "%2C",
+	)
+)
+
 func printAsText(writer io.Writer, fileAnnotations []FileAnnotation) error {
 	return printEachAnnotationOnNewLine(
 		writer,
@@ -40,11 +59,18 @@ func printAsMSVS(writer io.Writer, fileAnnotations []FileAnnotation) error {
 	)
 }
 
-func printAsJSON(writer io.Writer, fileAnnotations []FileAnnotation) error {
+func printAsJSON(
+	writer io.Writer,
+	fileAnnotations []FileAnnotation,
+	// May be nil.
+	fileAnnotationToSuggestedEdits map[FileAnnotation][]SuggestedEdit,
+) error {
 	return printEachAnnotationOnNewLine(
 		writer,
 		fileAnnotations,
-		printFileAnnotationAsJSON,
+		func(buffer *bytes.Buffer, fileAnnotation FileAnnotation) error {
+			return printFileAnnotationAsJSON(buffer, fileAnnotation, fileAnnotationToSuggestedEdits[fileAnnotation])
+		},
 	)
 }
 
@@ -104,6 +130,10 @@ func printAsJUnit(writer io.Writer, fileAnnotations []FileAnnotation) error {
 	return nil
 }
 
+// printFileAnnotationAsJUnit prints the FileAnnotation as a test case.
+//
+// The file and line attributes are not part of the JUnit schema, but are used by CI systems
+// such as GitLab and Buildkite to link test cases to the source.
 func printFileAnnotationAsJUnit(encoder *xml.Encoder, annotation FileAnnotation) error {
 	testcase := xml.StartElement{Name: xml.Name{Local: "testcase"}}
 	name := annotation.Type()
@@ -113,6 +143,16 @@ func printFileAnnotationAsJUnit(encoder *xml.Encoder, annotation FileAnnotation)
 		name += fmt.Sprintf("_%d", annotation.StartLine())
 	}
 	testcase.Attr = append(testcase.Attr, xml.Attr{Name: xml.Name{Local: "name"}, Value: name})
+	if fileInfo := annotation.FileInfo(); fileInfo != nil {
+		testcase.Attr = append(
+			testcase.Attr,
+			xml.Attr{Name: xml.Name{Local: "classname"}, Value: strings.TrimSuffix(fileInfo.ExternalPath(), ".proto")},
+			xml.Attr{Name: xml.Name{Local: "file"}, Value: fileInfo.ExternalPath()},
+		)
+	}
+	if annotation.StartLine() != 0 {
+		testcase.Attr = append(testcase.Attr, xml.Attr{Name: xml.Name{Local: "line"}, Value: strconv.Itoa(annotation.StartLine())})
+	}
 	if err := encoder.EncodeToken(testcase); err != nil {
 		return err
 	}
@@ -123,6 +163,15 @@ func printFileAnnotationAsJUnit(encoder *xml.Encoder, annotation FileAnnotation)
 			{Name: xml.Name{Local: "type"}, Value: annotation.Type()},
 		},
 	}
+	if impact := annotation.Impact(); impact != 0 {
+		failure.Attr = append(failure.Attr, xml.Attr{Name: xml.Name{Local: "impact"}, Value: impact.String()})
+	}
+	if againstInputs := annotation.AgainstInputs(); len(againstInputs) > 0 {
+		failure.Attr = append(failure.Attr, xml.Attr{Name: xml.Name{Local: "against"}, Value: strings.Join(againstInputs, ", ")})
+	}
+	if severity := annotation.Severity(); severity == SeverityWarning {
+		failure.Attr = append(failure.Attr, xml.Attr{Name: xml.Name{Local: "severity"}, Value: severity.String()})
+	}
 	if err := encoder.EncodeToken(failure); err != nil {
 		return err
 	}
@@ -156,6 +205,9 @@ func groupAnnotationsByPath(annotations []FileAnnotation) [][]FileAnnotation {
 
 func printFileAnnotationAsText(buffer *bytes.Buffer, f FileAnnotation) error {
 	_, _ = buffer.WriteString(f.String())
+	if f != nil {
+		writeCodeFrames(buffer, f.AgainstCodeFrame(), f.CodeFrame())
+	}
 	return nil
 }
 
@@ -190,7 +242,9 @@ func printFileAnnotationAsMSVS(buffer *bytes.Buffer, f FileAnnotation) error {
 		_, _ = buffer.WriteRune(',')
 		_, _ = buffer.WriteString(strconv.Itoa(column))
 	}
-	_, _ = buffer.WriteString(") : error ")
+	_, _ = buffer.WriteString(") : ")
+	_, _ = buffer.WriteString(f.Severity().String())
+	_, _ = buffer.WriteString(" ")
 	_, _ = buffer.WriteString(typeString)
 	_, _ = buffer.WriteString(" : ")
 	_, _ = buffer.WriteString(message)
@@ -199,11 +253,20 @@ func printFileAnnotationAsMSVS(buffer *bytes.Buffer, f FileAnnotation) error {
 		_, _ = buffer.WriteString(pluginName)
 		_, _ = buffer.WriteRune(')')
 	}
+	writeImpact(buffer, f.Impact())
+	writeAgainstInputs(buffer, f.AgainstInputs())
 	return nil
 }
 
-func printFileAnnotationAsJSON(buffer *bytes.Buffer, f FileAnnotation) error {
-	data, err := json.Marshal(newExternalFileAnnotation(f))
+func printFileAnnotationAsJSON(buffer *bytes.Buffer, f FileAnnotation, suggestedEdits []SuggestedEdit) error {
+	externalFileAnnotation := newExternalFileAnnotation(f)
+	for _, suggestedEdit := range suggestedEdits {
+		externalFileAnnotation.SuggestedEdits = append(
+			externalFileAnnotation.SuggestedEdits,
+			externalSuggestedEdit(suggestedEdit),
+		)
+	}
+	data, err := json.Marshal(externalFileAnnotation)
 	if err != nil {
 		return err
 	}
@@ -215,7 +278,9 @@ func printFileAnnotationAsGithubActions(buffer *bytes.Buffer, f FileAnnotation)
 	if f == nil {
 		return nil
 	}
-	_, _ = buffer.WriteString("::error ")
+	_, _ = buffer.WriteString("::")
+	_, _ = buffer.WriteString(f.Severity().String())
+	_, _ = buffer.WriteString(" ")
 
 	// file= is required for GitHub Actions, however it is possible to not have
 	// a path for a FileAnnotation. We still print something, however we need
@@ -225,7 +290,7 @@ func printFileAnnotationAsGithubActions(buffer *bytes.Buffer, f FileAnnotation)
 		path = f.FileInfo().ExternalPath()
 	}
 	_, _ = buffer.WriteString("file=")
-	_, _ = buffer.WriteString(path)
+	_, _ = buffer.WriteString(githubActionsPropertyEscaper.Replace(path))
 
 	// Everything else is optional.
 	if startLine := f.StartLine(); startLine > 0 {
@@ -250,12 +315,18 @@ func printFileAnnotationAsGithubActions(buffer *bytes.Buffer, f FileAnnotation)
 	}
 
 	_, _ = buffer.WriteString("::")
-	_, _ = buffer.WriteString(f.Message())
+	// The message is escaped as a whole, as messages may span multiple lines, for
+	// example for compilation errors, which would otherwise end the workflow command.
+	messageBuffer := bytes.NewBuffer(nil)
+	_, _ = messageBuffer.WriteString(f.Message())
 	if pluginName := f.PluginName(); pluginName != "" {
-		_, _ = buffer.WriteString(" (")
-		_, _ = buffer.WriteString(pluginName)
-		_, _ = buffer.WriteRune(')')
+		_, _ = messageBuffer.WriteString(" (")
+		_, _ = messageBuffer.WriteString(pluginName)
+		_, _ = messageBuffer.WriteRune(')')
 	}
+	writeImpact(messageBuffer, f.Impact())
+	writeAgainstInputs(messageBuffer, f.AgainstInputs())
+	_, _ = buffer.WriteString(githubActionsDataEscaper.Replace(messageBuffer.String()))
 	return nil
 }
 
@@ -268,6 +339,39 @@ type externalFileAnnotation struct {
 	Type        string `json:"type,omitempty" yaml:"type,omitempty"`
 	Message     string `json:"message,omitempty" yaml:"message,omitempty"`
 	Plugin      string `json:"plugin,omitempty" yaml:"plugin,omitempty"`
+	Impact      string `json:"impact,omitempty" yaml:"impact,omitempty"`
+	// Against is only set if breaking changes were checked against multiple against inputs.
+	Against  []string `json:"against,omitempty" yaml:"against,omitempty"`
+	Severity string   `json:"severity,omitempty" yaml:"severity,omitempty"`
+	// SuggestedEdits are only set if suggested edits were requested.
+	SuggestedEdits []externalSuggestedEdit `json:"suggested_edits,omitempty" yaml:"suggested_edits,omitempty"`
+	// CodeFrame and AgainstCodeFrame are only set if code frames were requested.
+	CodeFrame        *externalCodeFrame `json:"code_frame,omitempty" yaml:"code_frame,omitempty"`
+	AgainstCodeFrame *externalCodeFrame `json:"against_code_frame,omitempty" yaml:"against_code_frame,omitempty"`
+}
+
+type externalSuggestedEdit struct {
+	Path        string `json:"path" yaml:"path"`
+	StartOffset int    `json:"start_offset" yaml:"start_offset"`
+	EndOffset   int    `json:"end_offset" yaml:"end_offset"`
+	Replacement string `json:"replacement" yaml:"replacement"`
+}
+
+type externalCodeFrame struct {
+	Path      string   `json:"path" yaml:"path"`
+	StartLine int      `json:"start_line" yaml:"start_line"`
+	Lines     []string `json:"lines" yaml:"lines"`
+}
+
+func newExternalCodeFrame(codeFrame CodeFrame) *externalCodeFrame {
+	if codeFrame == nil {
+		return nil
+	}
+	return &externalCodeFrame{
+		Path:      codeFrame.Path(),
+		StartLine: codeFrame.StartLine(),
+		Lines:     codeFrame.Lines(),
+	}
 }
 
 func newExternalFileAnnotation(f FileAnnotation) externalFileAnnotation {
@@ -275,15 +379,30 @@ func newExternalFileAnnotation(f FileAnnotation) externalFileAnnotation {
 	if f.FileInfo() != nil {
 		path = f.FileInfo().ExternalPath()
 	}
+	var impact string
+	if f.Impact() != 0 {
+		impact = f.Impact().String()
+	}
+	// The severity is only printed for warnings, so that the output for errors
+	// is unchanged.
+	var severity string
+	if f.Severity() == SeverityWarning {
+		severity = f.Severity().String()
+	}
 	return externalFileAnnotation{
-		Path:        path,
-		StartLine:   atLeast1(f.StartLine()),
-		StartColumn: atLeast1(f.StartColumn()),
-		EndLine:     atLeast1(f.EndLine()),
-		EndColumn:   atLeast1(f.EndColumn()),
-		Type:        f.Type(),
-		Message:     f.Message(),
-		Plugin:      f.PluginName(),
+		Path:             path,
+		StartLine:        atLeast1(f.StartLine()),
+		StartColumn:      atLeast1(f.StartColumn()),
+		EndLine:          atLeast1(f.EndLine()),
+		EndColumn:        atLeast1(f.EndColumn()),
+		Type:             f.Type(),
+		Message:          f.Message(),
+		Plugin:           f.PluginName(),
+		Impact:           impact,
+		Against:          f.AgainstInputs(),
+		Severity:         severity,
+		CodeFrame:        newExternalCodeFrame(f.CodeFrame()),
+		AgainstCodeFrame: newExternalCodeFrame(f.AgainstCodeFrame()),
 	}
 }
 
@@ -0,0 +1,212 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufanalysis
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+)
+
+const (
+	sarifSchema         = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion        = "2.1.0"
+	sarifToolName       = "buf"
+	sarifInformationURI = "https://github.com/bufbuild/buf"
+	// FileAnnotations fail the command unless they are warnings, so rules default
+	// to errors, and results have the level of their FileAnnotation.
+	sarifLevel = "error"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool      `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string                    `json:"id"`
+	ShortDescription     *sarifMessage             `json:"shortDescription,omitempty"`
+	DefaultConfiguration sarifDefaultConfiguration `json:"defaultConfiguration"`
+	Properties           *sarifRuleProperties      `json:"properties,omitempty"`
+}
+
+type sarifDefaultConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProperties struct {
+	Tags []string `json:"tags,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties *sarifResultProperties `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifResultProperties struct {
+	Plugin  string   `json:"plugin,omitempty"`
+	Impact  string   `json:"impact,omitempty"`
+	Against []string `json:"against,omitempty"`
+}
+
+func printAsSARIF(writer io.Writer, fileAnnotations []FileAnnotation, ruleInfos []RuleInfo) error {
+	rules := make([]*sarifRule, 0, len(ruleInfos))
+	ruleIDToIndex := make(map[string]int, len(ruleInfos))
+	addRule := func(ruleInfo RuleInfo) int {
+		if index, ok := ruleIDToIndex[ruleInfo.ID]; ok {
+			return index
+		}
+		level := sarifLevel
+		if ruleInfo.Severity != 0 {
+			level = ruleInfo.Severity.String()
+		}
+		rule := &sarifRule{
+			ID: ruleInfo.ID,
+			DefaultConfiguration: sarifDefaultConfiguration{
+				Level: level,
+			},
+		}
+		if ruleInfo.Purpose != "" {
+			rule.ShortDescription = &sarifMessage{Text: ruleInfo.Purpose}
+		}
+		if len(ruleInfo.Categories) > 0 {
+			rule.Properties = &sarifRuleProperties{Tags: ruleInfo.Categories}
+		}
+		index := len(rules)
+		ruleIDToIndex[ruleInfo.ID] = index
+		rules = append(rules, rule)
+		return index
+	}
+	for _, ruleInfo := range ruleInfos {
+		addRule(ruleInfo)
+	}
+	results := make([]*sarifResult, 0, len(fileAnnotations))
+	for _, fileAnnotation := range fileAnnotations {
+		typeString := fileAnnotation.Type()
+		if typeString == "" {
+			// should never happen but just in case
+			typeString = "FAILURE"
+		}
+		result := &sarifResult{
+			RuleID:    typeString,
+			RuleIndex: addRule(RuleInfo{ID: typeString}),
+			Level:     fileAnnotation.Severity().String(),
+			Message:   sarifMessage{Text: fileAnnotation.Message()},
+		}
+		if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+			location := sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{
+						URI: filepath.ToSlash(fileInfo.ExternalPath()),
+					},
+				},
+			}
+			// Regions are only valid with a start line.
+			if startLine := fileAnnotation.StartLine(); startLine > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{
+					StartLine:   startLine,
+					StartColumn: fileAnnotation.StartColumn(),
+					EndLine:     fileAnnotation.EndLine(),
+					EndColumn:   fileAnnotation.EndColumn(),
+				}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		var impact string
+		if fileAnnotation.Impact() != 0 {
+			impact = fileAnnotation.Impact().String()
+		}
+		againstInputs := fileAnnotation.AgainstInputs()
+		if pluginName := fileAnnotation.PluginName(); pluginName != "" || impact != "" || len(againstInputs) > 0 {
+			result.Properties = &sarifResultProperties{
+				Plugin:  pluginName,
+				Impact:  impact,
+				Against: againstInputs,
+			}
+		}
+		results = append(results, result)
+	}
+	data, err := json.MarshalIndent(
+		&sarifLog{
+			Schema:  sarifSchema,
+			Version: sarifVersion,
+			Runs: []sarifRun{
+				{
+					Tool: sarifTool{
+						Driver: sarifDriver{
+							Name:           sarifToolName,
+							InformationURI: sarifInformationURI,
+							Rules:          rules,
+						},
+					},
+					Results: results,
+				},
+			},
+		},
+		"",
+		"  ",
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
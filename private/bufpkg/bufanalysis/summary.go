@@ -0,0 +1,170 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufanalysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+type externalSummary struct {
+	Total int                    `json:"total"`
+	Rules []*externalRuleSummary `json:"rules"`
+}
+
+type externalRuleSummary struct {
+	Rule     string                 `json:"rule"`
+	Count    int                    `json:"count"`
+	Warnings int                    `json:"warnings,omitempty"`
+	Files    []*externalFileSummary `json:"files"`
+}
+
+type externalFileSummary struct {
+	Path  string `json:"path"`
+	Count int    `json:"count"`
+}
+
+func printSummaryAsText(writer io.Writer, fileAnnotations []FileAnnotation) error {
+	summary := newExternalSummary(fileAnnotations)
+	if summary.Total == 0 {
+		return nil
+	}
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tabWriter, "RULE\tFILE\tCOUNT"); err != nil {
+		return err
+	}
+	for _, ruleSummary := range summary.Rules {
+		for _, fileSummary := range ruleSummary.Files {
+			if _, err := fmt.Fprintf(
+				tabWriter,
+				"%s\t%s\t%d\n",
+				ruleSummary.Rule,
+				fileSummary.Path,
+				fileSummary.Count,
+			); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := fmt.Fprintln(tabWriter); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(tabWriter, "RULE\tFILES\tCOUNT"); err != nil {
+		return err
+	}
+	files := make(map[string]struct{})
+	for _, ruleSummary := range summary.Rules {
+		for _, fileSummary := range ruleSummary.Files {
+			files[fileSummary.Path] = struct{}{}
+		}
+		count := strconv.Itoa(ruleSummary.Count)
+		if ruleSummary.Warnings > 0 {
+			count = fmt.Sprintf("%d (%d %s)", ruleSummary.Count, ruleSummary.Warnings, pluralize("warning", ruleSummary.Warnings))
+		}
+		if _, err := fmt.Fprintf(
+			tabWriter,
+			"%s\t%d\t%s\n",
+			ruleSummary.Rule,
+			len(ruleSummary.Files),
+			count,
+		); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(tabWriter, "TOTAL\t%d\t%d\n", len(files), summary.Total); err != nil {
+		return err
+	}
+	return tabWriter.Flush()
+}
+
+func printSummaryAsJSON(writer io.Writer, fileAnnotations []FileAnnotation) error {
+	data, err := json.Marshal(newExternalSummary(fileAnnotations))
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+// newExternalSummary aggregates the file annotations by rule and then by file.
+//
+// Rules are sorted by descending count and then by ID, and the files of each rule
+// are sorted by descending count and then by path.
+func newExternalSummary(fileAnnotations []FileAnnotation) *externalSummary {
+	ruleToSummary := make(map[string]*externalRuleSummary)
+	ruleToPathToSummary := make(map[string]map[string]*externalFileSummary)
+	for _, fileAnnotation := range fileAnnotations {
+		rule := fileAnnotation.Type()
+		path := "<input>"
+		if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+			path = fileInfo.ExternalPath()
+		}
+		ruleSummary, ok := ruleToSummary[rule]
+		if !ok {
+			ruleSummary = &externalRuleSummary{
+				Rule: rule,
+			}
+			ruleToSummary[rule] = ruleSummary
+			ruleToPathToSummary[rule] = make(map[string]*externalFileSummary)
+		}
+		ruleSummary.Count++
+		if fileAnnotation.Severity() == SeverityWarning {
+			ruleSummary.Warnings++
+		}
+		fileSummary, ok := ruleToPathToSummary[rule][path]
+		if !ok {
+			fileSummary = &externalFileSummary{
+				Path: path,
+			}
+			ruleToPathToSummary[rule][path] = fileSummary
+			ruleSummary.Files = append(ruleSummary.Files, fileSummary)
+		}
+		fileSummary.Count++
+	}
+	summary := &externalSummary{
+		Total: len(fileAnnotations),
+		Rules: make([]*externalRuleSummary, 0, len(ruleToSummary)),
+	}
+	for _, ruleSummary := range ruleToSummary {
+		slices.SortFunc(ruleSummary.Files, func(one *externalFileSummary, two *externalFileSummary) int {
+			if one.Count != two.Count {
+				return two.Count - one.Count
+			}
+			return strings.Compare(one.Path, two.Path)
+		})
+		summary.Rules = append(summary.Rules, ruleSummary)
+	}
+	slices.SortFunc(summary.Rules, func(one *externalRuleSummary, two *externalRuleSummary) int {
+		if one.Count != two.Count {
+			return two.Count - one.Count
+		}
+		return strings.Compare(one.Rule, two.Rule)
+	})
+	return summary
+}
+
+func pluralize(noun string, count int) string {
+	if count == 1 {
+		return noun
+	}
+	return noun + "s"
+}
@@ -14,6 +14,76 @@
 
 package bufanalysis
 
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// writeImpact writes the impact suffix of a printed FileAnnotation, if the
+// impact is set.
+func writeImpact(buffer *bytes.Buffer, impact Impact) {
+	if impact == 0 {
+		return
+	}
+	_, _ = buffer.WriteString(" [impact: ")
+	_, _ = buffer.WriteString(impact.String())
+	_, _ = buffer.WriteRune(']')
+}
+
+// writeAgainstInputs writes the against inputs suffix of a printed FileAnnotation, if
+// the annotation has against inputs.
+func writeAgainstInputs(buffer *bytes.Buffer, againstInputs []string) {
+	if len(againstInputs) == 0 {
+		return
+	}
+	_, _ = buffer.WriteString(" [against: ")
+	_, _ = buffer.WriteString(strings.Join(againstInputs, ", "))
+	_, _ = buffer.WriteRune(']')
+}
+
+// writeSeverity writes the severity suffix of a printed FileAnnotation, if the
+// annotation is a warning.
+func writeSeverity(buffer *bytes.Buffer, severity Severity) {
+	if severity != SeverityWarning {
+		return
+	}
+	_, _ = buffer.WriteString(" [warning]")
+}
+
+// writeCodeFrames writes the excerpts of the source before and after a breaking change
+// on the lines following a printed FileAnnotation, if the annotation has code frames.
+func writeCodeFrames(buffer *bytes.Buffer, againstCodeFrame CodeFrame, codeFrame CodeFrame) {
+	writeCodeFrame(buffer, "before", againstCodeFrame)
+	writeCodeFrame(buffer, "after", codeFrame)
+}
+
+// writeCodeFrame writes a code frame as a header line followed by the numbered lines
+// of the excerpt, all indented below the printed FileAnnotation.
+func writeCodeFrame(buffer *bytes.Buffer, label string, codeFrame CodeFrame) {
+	if codeFrame == nil || len(codeFrame.Lines()) == 0 {
+		return
+	}
+	startLine := atLeast1(codeFrame.StartLine())
+	lines := codeFrame.Lines()
+	lineNumberWidth := len(strconv.Itoa(startLine + len(lines) - 1))
+	_, _ = buffer.WriteString("\n  ")
+	_, _ = buffer.WriteString(label)
+	_, _ = buffer.WriteString(" (")
+	_, _ = buffer.WriteString(codeFrame.Path())
+	_, _ = buffer.WriteRune(':')
+	_, _ = buffer.WriteString(strconv.Itoa(startLine))
+	_, _ = buffer.WriteString("):")
+	for i, line := range lines {
+		lineNumber := strconv.Itoa(startLine + i)
+		_, _ = buffer.WriteString("\n    ")
+		_, _ = buffer.WriteString(strings.Repeat(" ", lineNumberWidth-len(lineNumber)))
+		_, _ = buffer.WriteString(lineNumber)
+		_, _ = buffer.WriteString(" | ")
+		_, _ = buffer.WriteString(line)
+	}
+}
+
 func atLeast1(i int) int {
 	if i <= 0 {
 		return 1
@@ -0,0 +1,61 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufartifact verifies the integrity of directories of build artifacts.
+package bufartifact
+
+import (
+	"context"
+
+	"github.com/bufbuild/buf/private/pkg/storage"
+)
+
+const (
+	// FileAnnotationTypeManifestInvalid is the FileAnnotation type for a manifest
+	// that could not be parsed.
+	FileAnnotationTypeManifestInvalid = "MANIFEST_INVALID"
+	// FileAnnotationTypeFileMissing is the FileAnnotation type for a file referenced
+	// by a manifest or digest file that does not exist.
+	FileAnnotationTypeFileMissing = "FILE_MISSING"
+	// FileAnnotationTypeDigestInvalid is the FileAnnotation type for a digest file
+	// that could not be parsed.
+	FileAnnotationTypeDigestInvalid = "DIGEST_INVALID"
+	// FileAnnotationTypeDigestMismatch is the FileAnnotation type for a file whose
+	// content does not match the digest recorded for it.
+	FileAnnotationTypeDigestMismatch = "DIGEST_MISMATCH"
+	// FileAnnotationTypeImageInvalid is the FileAnnotation type for an image that
+	// could not be parsed.
+	FileAnnotationTypeImageInvalid = "IMAGE_INVALID"
+)
+
+// Verify verifies the internal consistency of the artifacts in the ReadBucket.
+//
+// The following files are verified:
+//
+//   - Files with the .manifest extension are parsed as manifests in the form produced
+//     by bufcas.Manifest. Every file referenced by a manifest must exist, relative to
+//     the directory of the manifest, and its content must match the digest in the manifest.
+//   - Files with the .digest extension must contain a single digest in the form produced
+//     by bufcas.Digest. The file at the same path without the .digest extension must
+//     exist, and its content must match the digest.
+//   - Files with the .binpb or .bin extension, optionally followed by .gz or .zst, are
+//     parsed as binary Buf images.
+//
+// All other files are ignored.
+//
+// If any problems are found, a bufanalysis.FileAnnotationSet is returned as the error,
+// with one FileAnnotation per problem.
+func Verify(ctx context.Context, readBucket storage.ReadBucket) error {
+	return verify(ctx, readBucket)
+}
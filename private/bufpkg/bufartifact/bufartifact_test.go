@@ -0,0 +1,147 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufartifact
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufcas"
+	imagev1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/image/v1"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestVerifySuccess(t *testing.T) {
+	t.Parallel()
+	imageData := testNewImageData(t)
+	testVerify(
+		t,
+		map[string][]byte{
+			"README.md":          []byte("ignored"),
+			"gen/a.txt":          []byte("a"),
+			"gen/b/b.txt":        []byte("b"),
+			"gen/gen.manifest":   []byte(testNewManifestString(t, map[string]string{"a.txt": "a", "b/b.txt": "b"})),
+			"image.binpb":        imageData,
+			"image.binpb.digest": []byte(testNewDigestString(t, string(imageData)) + "\n"),
+			"image.binpb.gz":     testGzip(t, imageData),
+			"empty.manifest":     nil,
+			// Digest files can reference manifests.
+			"empty.manifest.digest": []byte(testNewDigestString(t, "")),
+		},
+	)
+}
+
+func TestVerifyFailure(t *testing.T) {
+	t.Parallel()
+	testVerify(
+		t,
+		map[string][]byte{
+			"gen/a.txt":        []byte("a"),
+			"gen/gen.manifest": []byte(testNewManifestString(t, map[string]string{"a.txt": "b", "c.txt": "c"})),
+			"bad.manifest":     []byte("foo\n"),
+			"image.bin":        []byte("not an image"),
+			"x.txt.digest":     []byte("foo"),
+			"y.txt.digest":     []byte(testNewDigestString(t, "y")),
+		},
+		FileAnnotationTypeManifestInvalid+" bad.manifest",
+		FileAnnotationTypeDigestMismatch+" gen/gen.manifest",
+		FileAnnotationTypeFileMissing+" gen/gen.manifest",
+		FileAnnotationTypeImageInvalid+" image.bin",
+		FileAnnotationTypeDigestInvalid+" x.txt.digest",
+		FileAnnotationTypeFileMissing+" y.txt.digest",
+	)
+}
+
+func testVerify(
+	t *testing.T,
+	pathToData map[string][]byte,
+	expectedTypeAndPaths ...string,
+) {
+	readBucket, err := storagemem.NewReadBucket(pathToData)
+	require.NoError(t, err)
+	err = Verify(context.Background(), readBucket)
+	if len(expectedTypeAndPaths) == 0 {
+		require.NoError(t, err)
+		return
+	}
+	var fileAnnotationSet bufanalysis.FileAnnotationSet
+	require.True(t, errors.As(err, &fileAnnotationSet), err)
+	var actualTypeAndPaths []string
+	for _, fileAnnotation := range fileAnnotationSet.FileAnnotations() {
+		actualTypeAndPaths = append(
+			actualTypeAndPaths,
+			fileAnnotation.Type()+" "+fileAnnotation.FileInfo().Path(),
+		)
+	}
+	require.ElementsMatch(t, expectedTypeAndPaths, actualTypeAndPaths)
+}
+
+func testNewDigestString(t *testing.T, content string) string {
+	digest, err := bufcas.NewDigestForContent(strings.NewReader(content))
+	require.NoError(t, err)
+	return digest.String()
+}
+
+func testNewManifestString(t *testing.T, pathToContent map[string]string) string {
+	var fileNodes []bufcas.FileNode
+	for path, content := range pathToContent {
+		digest, err := bufcas.NewDigestForContent(strings.NewReader(content))
+		require.NoError(t, err)
+		fileNode, err := bufcas.NewFileNode(path, digest)
+		require.NoError(t, err)
+		fileNodes = append(fileNodes, fileNode)
+	}
+	manifest, err := bufcas.NewManifest(fileNodes)
+	require.NoError(t, err)
+	return manifest.String()
+}
+
+func testNewImageData(t *testing.T) []byte {
+	protoImage := imagev1.Image_builder{
+		File: []*imagev1.ImageFile{
+			imagev1.ImageFile_builder{
+				Name:    proto.String("a/v1/a.proto"),
+				Package: proto.String("a.v1"),
+				Syntax:  proto.String("proto3"),
+				MessageType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("Foo"),
+					},
+				},
+			}.Build(),
+		},
+	}.Build()
+	data, err := protoencoding.NewWireMarshaler().Marshal(protoImage)
+	require.NoError(t, err)
+	return data
+}
+
+func testGzip(t *testing.T, data []byte) []byte {
+	buffer := bytes.NewBuffer(nil)
+	gzipWriter := gzip.NewWriter(buffer)
+	_, err := gzipWriter.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+	return buffer.Bytes()
+}
@@ -0,0 +1,256 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufartifact
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufcas"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
+	imagev1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/image/v1"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	manifestExt = ".manifest"
+	digestExt   = ".digest"
+)
+
+var imageExts = []string{
+	".binpb",
+	".bin",
+}
+
+func verify(ctx context.Context, readBucket storage.ReadBucket) error {
+	objectInfos, err := storage.AllObjectInfos(ctx, readBucket, "")
+	if err != nil {
+		return err
+	}
+	var fileAnnotations []bufanalysis.FileAnnotation
+	for _, objectInfo := range objectInfos {
+		var objectFileAnnotations []bufanalysis.FileAnnotation
+		path := objectInfo.Path()
+		switch {
+		case normalpath.Ext(path) == manifestExt:
+			objectFileAnnotations, err = verifyManifest(ctx, readBucket, objectInfo)
+		case normalpath.Ext(path) == digestExt:
+			objectFileAnnotations, err = verifyDigestFile(ctx, readBucket, objectInfo)
+		case isImagePath(path):
+			objectFileAnnotations, err = verifyImage(ctx, readBucket, objectInfo)
+		}
+		if err != nil {
+			return err
+		}
+		fileAnnotations = append(fileAnnotations, objectFileAnnotations...)
+	}
+	if len(fileAnnotations) > 0 {
+		return bufanalysis.NewFileAnnotationSet(fileAnnotations...)
+	}
+	return nil
+}
+
+func verifyManifest(
+	ctx context.Context,
+	readBucket storage.ReadBucket,
+	objectInfo storage.ObjectInfo,
+) ([]bufanalysis.FileAnnotation, error) {
+	data, err := storage.ReadPath(ctx, readBucket, objectInfo.Path())
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := bufcas.ParseManifest(string(data))
+	if err != nil {
+		// The ParseError includes the entire content of the manifest, only use the cause.
+		var parseError *bufparse.ParseError
+		if errors.As(err, &parseError) {
+			err = parseError.Unwrap()
+		}
+		return []bufanalysis.FileAnnotation{
+			newFileAnnotation(objectInfo, FileAnnotationTypeManifestInvalid, fmt.Sprintf("Invalid manifest: %v.", err)),
+		}, nil
+	}
+	dirPath := normalpath.Dir(objectInfo.Path())
+	var fileAnnotations []bufanalysis.FileAnnotation
+	for _, fileNode := range manifest.FileNodes() {
+		fileAnnotation, err := verifyDigest(
+			ctx,
+			readBucket,
+			objectInfo,
+			normalpath.Join(dirPath, fileNode.Path()),
+			fileNode.Digest(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if fileAnnotation != nil {
+			fileAnnotations = append(fileAnnotations, fileAnnotation)
+		}
+	}
+	return fileAnnotations, nil
+}
+
+func verifyDigestFile(
+	ctx context.Context,
+	readBucket storage.ReadBucket,
+	objectInfo storage.ObjectInfo,
+) ([]bufanalysis.FileAnnotation, error) {
+	data, err := storage.ReadPath(ctx, readBucket, objectInfo.Path())
+	if err != nil {
+		return nil, err
+	}
+	digest, err := bufcas.ParseDigest(strings.TrimSpace(string(data)))
+	if err != nil {
+		return []bufanalysis.FileAnnotation{
+			newFileAnnotation(objectInfo, FileAnnotationTypeDigestInvalid, fmt.Sprintf("Invalid digest: %v.", err)),
+		}, nil
+	}
+	fileAnnotation, err := verifyDigest(
+		ctx,
+		readBucket,
+		objectInfo,
+		strings.TrimSuffix(objectInfo.Path(), digestExt),
+		digest,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if fileAnnotation != nil {
+		return []bufanalysis.FileAnnotation{fileAnnotation}, nil
+	}
+	return nil, nil
+}
+
+// verifyDigest verifies that the content at path matches the expected Digest.
+//
+// The returned FileAnnotation is attached to the file that recorded the Digest.
+// Returns nil if the content matches.
+func verifyDigest(
+	ctx context.Context,
+	readBucket storage.ReadBucket,
+	objectInfo storage.ObjectInfo,
+	path string,
+	expectedDigest bufcas.Digest,
+) (bufanalysis.FileAnnotation, error) {
+	var actualDigest bufcas.Digest
+	if err := storage.ForReadObject(
+		ctx,
+		readBucket,
+		path,
+		func(readObject storage.ReadObject) error {
+			var err error
+			actualDigest, err = bufcas.NewDigestForContent(
+				readObject,
+				bufcas.DigestWithDigestType(expectedDigest.Type()),
+			)
+			return err
+		},
+	); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return newFileAnnotation(
+				objectInfo,
+				FileAnnotationTypeFileMissing,
+				fmt.Sprintf("File %q does not exist.", path),
+			), nil
+		}
+		return nil, err
+	}
+	if !bufcas.DigestEqual(expectedDigest, actualDigest) {
+		return newFileAnnotation(
+			objectInfo,
+			FileAnnotationTypeDigestMismatch,
+			fmt.Sprintf("File %q has digest %s, expected %s.", path, actualDigest.String(), expectedDigest.String()),
+		), nil
+	}
+	return nil, nil
+}
+
+func verifyImage(
+	ctx context.Context,
+	readBucket storage.ReadBucket,
+	objectInfo storage.ObjectInfo,
+) ([]bufanalysis.FileAnnotation, error) {
+	data, err := storage.ReadPath(ctx, readBucket, objectInfo.Path())
+	if err != nil {
+		return nil, err
+	}
+	if err := parseImage(objectInfo.Path(), data); err != nil {
+		return []bufanalysis.FileAnnotation{
+			newFileAnnotation(objectInfo, FileAnnotationTypeImageInvalid, fmt.Sprintf("Invalid image: %v.", err)),
+		}, nil
+	}
+	return nil, nil
+}
+
+func parseImage(path string, data []byte) error {
+	switch normalpath.Ext(path) {
+	case ".gz":
+		gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		if data, err = io.ReadAll(gzipReader); err != nil {
+			return err
+		}
+	case ".zst":
+		zstdDecoder, err := zstd.NewReader(nil)
+		if err != nil {
+			return err
+		}
+		defer zstdDecoder.Close()
+		if data, err = zstdDecoder.DecodeAll(data, nil); err != nil {
+			return err
+		}
+	}
+	protoImage := &imagev1.Image{}
+	if err := protoencoding.NewWireUnmarshaler(nil).Unmarshal(data, protoImage); err != nil {
+		return err
+	}
+	_, err := bufimage.NewImageForProto(protoImage)
+	return err
+}
+
+func isImagePath(path string) bool {
+	switch normalpath.Ext(path) {
+	case ".gz", ".zst":
+		path = strings.TrimSuffix(path, normalpath.Ext(path))
+	}
+	for _, imageExt := range imageExts {
+		if normalpath.Ext(path) == imageExt {
+			return true
+		}
+	}
+	return false
+}
+
+func newFileAnnotation(
+	fileInfo bufanalysis.FileInfo,
+	typeString string,
+	message string,
+) bufanalysis.FileAnnotation {
+	return bufanalysis.NewFileAnnotation(fileInfo, 0, 0, 0, 0, typeString, message, "")
+}
@@ -0,0 +1,186 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufbaseline
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/encoding"
+	"github.com/bufbuild/buf/private/pkg/syserror"
+)
+
+const baselineVersion = "v1"
+
+type baseline struct {
+	violations []Violation
+}
+
+func newBaseline(keyToCount map[violationKey]int) *baseline {
+	violations := make([]Violation, 0, len(keyToCount))
+	for key, count := range keyToCount {
+		violations = append(
+			violations,
+			Violation{
+				Path:    key.path,
+				Type:    key.typeString,
+				Message: key.message,
+				Count:   count,
+			},
+		)
+	}
+	slices.SortFunc(violations, compareViolations)
+	return &baseline{
+		violations: violations,
+	}
+}
+
+func newBaselineForFileAnnotations(fileAnnotations []bufanalysis.FileAnnotation) *baseline {
+	keyToCount := make(map[violationKey]int)
+	for _, fileAnnotation := range fileAnnotations {
+		keyToCount[getViolationKey(fileAnnotation)]++
+	}
+	return newBaseline(keyToCount)
+}
+
+func filterFileAnnotations(baseline Baseline, fileAnnotations []bufanalysis.FileAnnotation) []bufanalysis.FileAnnotation {
+	keyToRemainingCount := make(map[violationKey]int)
+	for _, violation := range baseline.Violations() {
+		keyToRemainingCount[getViolationKeyForViolation(violation)] += violation.Count
+	}
+	var filteredFileAnnotations []bufanalysis.FileAnnotation
+	for _, fileAnnotation := range fileAnnotations {
+		key := getViolationKey(fileAnnotation)
+		if keyToRemainingCount[key] > 0 {
+			keyToRemainingCount[key]--
+			continue
+		}
+		filteredFileAnnotations = append(filteredFileAnnotations, fileAnnotation)
+	}
+	return filteredFileAnnotations
+}
+
+func readBaseline(reader io.Reader) (*baseline, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var externalBaseline externalBaselineV1
+	if err := encoding.UnmarshalYAMLStrict(data, &externalBaseline); err != nil {
+		return nil, err
+	}
+	if externalBaseline.Version != baselineVersion {
+		return nil, fmt.Errorf("unknown baseline version %q, expected %q", externalBaseline.Version, baselineVersion)
+	}
+	keyToCount := make(map[violationKey]int, len(externalBaseline.Violations))
+	for _, externalViolation := range externalBaseline.Violations {
+		if externalViolation.Type == "" {
+			return nil, fmt.Errorf("type is required for violation in %q", externalViolation.Path)
+		}
+		if externalViolation.Count < 1 {
+			return nil, fmt.Errorf("count must be at least 1 for %s violation in %q", externalViolation.Type, externalViolation.Path)
+		}
+		keyToCount[violationKey{
+			path:       externalViolation.Path,
+			typeString: externalViolation.Type,
+			message:    externalViolation.Message,
+		}] += externalViolation.Count
+	}
+	return newBaseline(keyToCount), nil
+}
+
+func writeBaseline(writer io.Writer, baseline Baseline) error {
+	if baseline == nil {
+		return syserror.New("nil Baseline")
+	}
+	violations := baseline.Violations()
+	externalBaseline := externalBaselineV1{
+		Version:    baselineVersion,
+		Violations: make([]externalViolationV1, len(violations)),
+	}
+	for i, violation := range violations {
+		externalBaseline.Violations[i] = externalViolationV1{
+			Path:    violation.Path,
+			Type:    violation.Type,
+			Message: violation.Message,
+			Count:   violation.Count,
+		}
+	}
+	data, err := encoding.MarshalYAML(&externalBaseline)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
+
+func (b *baseline) Violations() []Violation {
+	return slices.Clone(b.violations)
+}
+
+func (*baseline) isBaseline() {}
+
+type violationKey struct {
+	path       string
+	typeString string
+	message    string
+}
+
+func getViolationKey(fileAnnotation bufanalysis.FileAnnotation) violationKey {
+	var path string
+	if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+		path = fileInfo.Path()
+	}
+	return violationKey{
+		path:       path,
+		typeString: fileAnnotation.Type(),
+		message:    fileAnnotation.Message(),
+	}
+}
+
+func getViolationKeyForViolation(violation Violation) violationKey {
+	return violationKey{
+		path:       violation.Path,
+		typeString: violation.Type,
+		message:    violation.Message,
+	}
+}
+
+func compareViolations(a Violation, b Violation) int {
+	if c := cmp.Compare(a.Path, b.Path); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Type, b.Type); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Message, b.Message)
+}
+
+// externalBaselineV1 represents a v1 baseline file.
+type externalBaselineV1 struct {
+	Version    string                `json:"version,omitempty" yaml:"version,omitempty"`
+	Violations []externalViolationV1 `json:"violations,omitempty" yaml:"violations,omitempty"`
+}
+
+// externalViolationV1 represents a violation in a v1 baseline file.
+type externalViolationV1 struct {
+	Path    string `json:"path,omitempty" yaml:"path,omitempty"`
+	Type    string `json:"type,omitempty" yaml:"type,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	Count   int    `json:"count" yaml:"count"`
+}
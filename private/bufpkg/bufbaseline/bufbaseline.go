@@ -0,0 +1,76 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufbaseline provides baselines, which record the existing violations of
+// lint rules so that only new violations are reported.
+//
+// A baseline is written with buf lint --write-baseline, and allows large repositories
+// to adopt lint rules without fixing all existing violations first.
+//
+// Violations are recorded by path, type, and message, and not by location, so that
+// editing a file does not invalidate the violations recorded for it. If a file has
+// more violations with the same type and message than were recorded, only the
+// additional violations are reported.
+package bufbaseline
+
+import (
+	"io"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+)
+
+// DefaultFileName is the default file name of the baseline.
+const DefaultFileName = "buf.lint-baseline.yaml"
+
+// Baseline is a baseline of violations.
+type Baseline interface {
+	// Violations returns the Violations, sorted by path, then type, then message.
+	Violations() []Violation
+
+	isBaseline()
+}
+
+// Violation is a recorded violation.
+type Violation struct {
+	// Path is the path of the file with the violation.
+	Path string
+	// Type is the ID of the rule that was violated.
+	Type string
+	// Message is the message of the violation.
+	Message string
+	// Count is the number of violations with the same path, type, and message.
+	Count int
+}
+
+// NewBaseline returns a new Baseline that records the FileAnnotations.
+func NewBaseline(fileAnnotations []bufanalysis.FileAnnotation) Baseline {
+	return newBaselineForFileAnnotations(fileAnnotations)
+}
+
+// FilterFileAnnotations returns the FileAnnotations that are not recorded in the Baseline.
+//
+// The order of the FileAnnotations is preserved.
+func FilterFileAnnotations(baseline Baseline, fileAnnotations []bufanalysis.FileAnnotation) []bufanalysis.FileAnnotation {
+	return filterFileAnnotations(baseline, fileAnnotations)
+}
+
+// ReadBaseline reads a Baseline from the io.Reader.
+func ReadBaseline(reader io.Reader) (Baseline, error) {
+	return readBaseline(reader)
+}
+
+// WriteBaseline writes the Baseline to the io.Writer.
+func WriteBaseline(writer io.Writer, baseline Baseline) error {
+	return writeBaseline(writer, baseline)
+}
@@ -0,0 +1,142 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufbaseline
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBaseline(t *testing.T) {
+	t.Parallel()
+	baseline := NewBaseline(
+		[]bufanalysis.FileAnnotation{
+			newFileAnnotation("b.proto", 1, "FIELD_LOWER_SNAKE_CASE", `Field name "fooBar" should be lower_snake_case, such as "foo_bar".`),
+			newFileAnnotation("a.proto", 2, "PACKAGE_VERSION_SUFFIX", `Package name "a" should be suffixed with a correctly formed version, such as "a.v1".`),
+			newFileAnnotation("b.proto", 3, "FIELD_LOWER_SNAKE_CASE", `Field name "fooBar" should be lower_snake_case, such as "foo_bar".`),
+		},
+	)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteBaseline(buffer, baseline))
+	require.Equal(
+		t,
+		`version: v1
+violations:
+  - path: a.proto
+    type: PACKAGE_VERSION_SUFFIX
+    message: Package name "a" should be suffixed with a correctly formed version, such as "a.v1".
+    count: 1
+  - path: b.proto
+    type: FIELD_LOWER_SNAKE_CASE
+    message: Field name "fooBar" should be lower_snake_case, such as "foo_bar".
+    count: 2
+`,
+		buffer.String(),
+	)
+	readBaseline, err := ReadBaseline(buffer)
+	require.NoError(t, err)
+	require.Equal(t, baseline.Violations(), readBaseline.Violations())
+}
+
+func TestFilterFileAnnotations(t *testing.T) {
+	t.Parallel()
+	baseline, err := ReadBaseline(
+		strings.NewReader(`version: v1
+violations:
+  - path: a.proto
+    type: FIELD_LOWER_SNAKE_CASE
+    message: Field name "fooBar" should be lower_snake_case, such as "foo_bar".
+    count: 2
+  - path: a.proto
+    type: ENUM_PASCAL_CASE
+    message: Enum name "foo_bar" should be PascalCase, such as "FooBar".
+    count: 1
+`),
+	)
+	require.NoError(t, err)
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		// Recorded, on a different line than when the baseline was written.
+		newFileAnnotation("a.proto", 10, "FIELD_LOWER_SNAKE_CASE", `Field name "fooBar" should be lower_snake_case, such as "foo_bar".`),
+		// Same message in a different file.
+		newFileAnnotation("b.proto", 11, "FIELD_LOWER_SNAKE_CASE", `Field name "fooBar" should be lower_snake_case, such as "foo_bar".`),
+		newFileAnnotation("a.proto", 12, "FIELD_LOWER_SNAKE_CASE", `Field name "fooBar" should be lower_snake_case, such as "foo_bar".`),
+		// More violations than were recorded.
+		newFileAnnotation("a.proto", 13, "FIELD_LOWER_SNAKE_CASE", `Field name "fooBar" should be lower_snake_case, such as "foo_bar".`),
+		newFileAnnotation("a.proto", 14, "FIELD_LOWER_SNAKE_CASE", `Field name "bazQux" should be lower_snake_case, such as "baz_qux".`),
+	}
+	require.Equal(
+		t,
+		[]bufanalysis.FileAnnotation{
+			fileAnnotations[1],
+			fileAnnotations[3],
+			fileAnnotations[4],
+		},
+		FilterFileAnnotations(baseline, fileAnnotations),
+	)
+}
+
+func TestReadBaselineInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := ReadBaseline(strings.NewReader("version: v2\n"))
+	require.ErrorContains(t, err, `unknown baseline version "v2"`)
+	_, err = ReadBaseline(
+		strings.NewReader(`version: v1
+violations:
+  - path: a.proto
+    message: Field name "fooBar" should be lower_snake_case, such as "foo_bar".
+    count: 1
+`),
+	)
+	require.ErrorContains(t, err, "type is required")
+	_, err = ReadBaseline(
+		strings.NewReader(`version: v1
+violations:
+  - path: a.proto
+    type: FIELD_LOWER_SNAKE_CASE
+    message: Field name "fooBar" should be lower_snake_case, such as "foo_bar".
+    count: 0
+`),
+	)
+	require.ErrorContains(t, err, "count must be at least 1")
+}
+
+func newFileAnnotation(path string, line int, typeString string, message string) bufanalysis.FileAnnotation {
+	return bufanalysis.NewFileAnnotation(
+		&fileInfo{path: path},
+		line,
+		1,
+		line,
+		10,
+		typeString,
+		message,
+		"",
+	)
+}
+
+type fileInfo struct {
+	path string
+}
+
+func (f *fileInfo) Path() string {
+	return f.path
+}
+
+func (f *fileInfo) ExternalPath() string {
+	return f.path
+}
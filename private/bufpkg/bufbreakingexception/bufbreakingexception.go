@@ -0,0 +1,118 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufbreakingexception provides breaking change exceptions, which record breaking
+// changes that were approved so that they are not reported.
+//
+// Exceptions are written with buf breaking --update-exceptions, and allow intentional
+// breaking changes to be approved without disabling the rules that detect them.
+//
+// Exceptions are recorded by rule, symbol, and message, where the symbol is the fully-qualified
+// name of the innermost declaration that contains the breaking change, such as the message that
+// a field was deleted from. The message is included so that an exception approves a single
+// breaking change, and not every breaking change for the rule within the declaration. Breaking
+// changes that are not within a declaration, such as for the options of a file, are also recorded
+// by path. Exceptions are not recorded by location, so that editing a file does not invalidate them.
+package bufbreakingexception
+
+import (
+	"io"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+)
+
+// DefaultFileName is the default file name of the exceptions.
+const DefaultFileName = "buf.breaking-exceptions.yaml"
+
+// ExceptionSet is a set of exceptions.
+type ExceptionSet interface {
+	// Exceptions returns the Exceptions, sorted by rule, then symbol, then path, then message.
+	Exceptions() []Exception
+
+	isExceptionSet()
+}
+
+// Exception is an approved breaking change.
+type Exception struct {
+	// Rule is the ID of the rule that detected the breaking change.
+	Rule string
+	// Symbol is the fully-qualified name of the innermost declaration that contains
+	// the breaking change.
+	//
+	// Empty if the breaking change is not within a declaration.
+	Symbol string
+	// Path is the path of the file with the breaking change.
+	//
+	// Only set if Symbol is empty.
+	Path string
+	// Message is the message of the breaking change.
+	Message string
+	// Reason is the reason the breaking change was approved.
+	Reason string
+}
+
+// SymbolResolver resolves the symbols of FileAnnotations.
+type SymbolResolver interface {
+	// Symbol returns the fully-qualified name of the innermost declaration that contains
+	// the start of the FileAnnotation.
+	//
+	// Returns empty if the FileAnnotation is not within a declaration.
+	Symbol(fileAnnotation bufanalysis.FileAnnotation) string
+
+	isSymbolResolver()
+}
+
+// NewSymbolResolver returns a new SymbolResolver for the declarations of the Images.
+//
+// These should be the Images that breaking change detection was run on, not the
+// Images that were checked against.
+func NewSymbolResolver(images ...bufimage.Image) SymbolResolver {
+	return newSymbolResolver(images)
+}
+
+// NewExceptionSet returns a new ExceptionSet that records the FileAnnotations as Exceptions.
+//
+// The Reason of each Exception is copied from the matching Exception of existing if there
+// is one, so that updating the ExceptionSet preserves reasons. Otherwise, the Reason is
+// the given reason. existing may be nil.
+func NewExceptionSet(
+	symbolResolver SymbolResolver,
+	fileAnnotations []bufanalysis.FileAnnotation,
+	existing ExceptionSet,
+	reason string,
+) ExceptionSet {
+	return newExceptionSetForFileAnnotations(symbolResolver, fileAnnotations, existing, reason)
+}
+
+// FilterFileAnnotations returns the FileAnnotations that do not match an Exception.
+//
+// The order of the FileAnnotations is preserved.
+func FilterFileAnnotations(
+	exceptionSet ExceptionSet,
+	symbolResolver SymbolResolver,
+	fileAnnotations []bufanalysis.FileAnnotation,
+) []bufanalysis.FileAnnotation {
+	return filterFileAnnotations(exceptionSet, symbolResolver, fileAnnotations)
+}
+
+// ReadExceptionSet reads an ExceptionSet from the io.Reader.
+func ReadExceptionSet(reader io.Reader) (ExceptionSet, error) {
+	return readExceptionSet(reader)
+}
+
+// WriteExceptionSet writes the ExceptionSet to the io.Writer.
+func WriteExceptionSet(writer io.Writer, exceptionSet ExceptionSet) error {
+	return writeExceptionSet(writer, exceptionSet)
+}
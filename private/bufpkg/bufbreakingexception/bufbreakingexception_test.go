@@ -0,0 +1,231 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufbreakingexception
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduletesting"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/stretchr/testify/require"
+)
+
+const testProto = `syntax = "proto3";
+
+package acme.pet.v1;
+
+option go_package = "acme/pet/v1";
+
+message Pet {
+  string name = 1;
+  enum Type {
+    TYPE_UNSPECIFIED = 0;
+  }
+  oneof kind {
+    string breed = 3;
+  }
+}
+
+service PetService {
+  rpc GetPet(Pet) returns (Pet);
+}
+`
+
+func TestSymbolResolver(t *testing.T) {
+	t.Parallel()
+	symbolResolver := NewSymbolResolver(testBuildImage(t))
+	testSymbol := func(expected string, line int, column int) {
+		t.Helper()
+		require.Equal(
+			t,
+			expected,
+			symbolResolver.Symbol(bufanalysis.NewFileAnnotation(&fileInfo{path: "acme/pet/v1/pet.proto"}, line, column, line, column, "", "", "")),
+		)
+	}
+	testSymbol("", 5, 1)
+	testSymbol("acme.pet.v1.Pet", 7, 1)
+	testSymbol("acme.pet.v1.Pet.name", 8, 3)
+	testSymbol("acme.pet.v1.Pet.name", 8, 10)
+	testSymbol("acme.pet.v1.Pet", 8, 19)
+	testSymbol("acme.pet.v1.Pet.Type", 9, 3)
+	// Enum values are siblings of their enum.
+	testSymbol("acme.pet.v1.Pet.TYPE_UNSPECIFIED", 10, 5)
+	testSymbol("acme.pet.v1.Pet.kind", 12, 3)
+	testSymbol("acme.pet.v1.Pet.breed", 13, 12)
+	testSymbol("acme.pet.v1.PetService.GetPet", 18, 14)
+	testSymbol("", 20, 1)
+	require.Empty(t, symbolResolver.Symbol(bufanalysis.NewFileAnnotation(&fileInfo{path: "other.proto"}, 7, 1, 7, 1, "", "", "")))
+	require.Empty(t, symbolResolver.Symbol(bufanalysis.NewFileAnnotation(nil, 0, 0, 0, 0, "FILE_NO_DELETE", "", "")))
+}
+
+func TestWriteExceptionSet(t *testing.T) {
+	t.Parallel()
+	symbolResolver := NewSymbolResolver(testBuildImage(t))
+	existing, err := ReadExceptionSet(
+		strings.NewReader(`version: v1
+exceptions:
+  - rule: FIELD_SAME_TYPE
+    symbol: acme.pet.v1.Pet.name
+    message: Field "1" with name "name" on message "Pet" changed type from "int64" to "string".
+    reason: The field was never populated.
+  - rule: FIELD_NO_DELETE
+    symbol: acme.pet.v1.Pet
+    message: Previously present field "2" with name "age" on message "Pet" was deleted.
+    reason: No longer detected.
+`),
+	)
+	require.NoError(t, err)
+	exceptionSet := NewExceptionSet(
+		symbolResolver,
+		[]bufanalysis.FileAnnotation{
+			newFileAnnotation("acme/pet/v1/pet.proto", 8, 3, "FIELD_SAME_TYPE", `Field "1" with name "name" on message "Pet" changed type from "int64" to "string".`),
+			newFileAnnotation("acme/pet/v1/pet.proto", 7, 1, "FIELD_NO_DELETE", `Previously present field "4" with name "color" on message "Pet" was deleted.`),
+			newFileAnnotation("acme/pet/v1/pet.proto", 5, 1, "FILE_SAME_GO_PACKAGE", `File option "go_package" changed from "pet" to "acme/pet/v1".`),
+		},
+		existing,
+		"Approved by the API review.",
+	)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteExceptionSet(buffer, exceptionSet))
+	require.Equal(
+		t,
+		`version: v1
+exceptions:
+  - rule: FIELD_NO_DELETE
+    symbol: acme.pet.v1.Pet
+    message: Previously present field "4" with name "color" on message "Pet" was deleted.
+    reason: Approved by the API review.
+  - rule: FIELD_SAME_TYPE
+    symbol: acme.pet.v1.Pet.name
+    message: Field "1" with name "name" on message "Pet" changed type from "int64" to "string".
+    reason: The field was never populated.
+  - rule: FILE_SAME_GO_PACKAGE
+    path: acme/pet/v1/pet.proto
+    message: File option "go_package" changed from "pet" to "acme/pet/v1".
+    reason: Approved by the API review.
+`,
+		buffer.String(),
+	)
+	readExceptionSet, err := ReadExceptionSet(buffer)
+	require.NoError(t, err)
+	require.Equal(t, exceptionSet.Exceptions(), readExceptionSet.Exceptions())
+}
+
+func TestFilterFileAnnotations(t *testing.T) {
+	t.Parallel()
+	symbolResolver := NewSymbolResolver(testBuildImage(t))
+	exceptionSet, err := ReadExceptionSet(
+		strings.NewReader(`version: v1
+exceptions:
+  - rule: FIELD_NO_DELETE
+    symbol: acme.pet.v1.Pet
+    message: Previously present field "2" with name "age" on message "Pet" was deleted.
+    reason: ""
+  - rule: FILE_NO_DELETE
+    message: Previously present file "acme/pet/v1/old.proto" was deleted.
+    reason: Moved to pet.proto.
+`),
+	)
+	require.NoError(t, err)
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		// Recorded, on a different line than when the exception was written.
+		newFileAnnotation("acme/pet/v1/pet.proto", 7, 1, "FIELD_NO_DELETE", `Previously present field "2" with name "age" on message "Pet" was deleted.`),
+		// Same rule and symbol, but a different breaking change.
+		newFileAnnotation("acme/pet/v1/pet.proto", 7, 1, "FIELD_NO_DELETE", `Previously present field "5" with name "owner" on message "Pet" was deleted.`),
+		// Same message, but within a different symbol.
+		newFileAnnotation("acme/pet/v1/pet.proto", 17, 1, "FIELD_NO_DELETE", `Previously present field "2" with name "age" on message "Pet" was deleted.`),
+		bufanalysis.NewFileAnnotation(nil, 0, 0, 0, 0, "FILE_NO_DELETE", `Previously present file "acme/pet/v1/old.proto" was deleted.`, ""),
+	}
+	require.Equal(
+		t,
+		[]bufanalysis.FileAnnotation{
+			fileAnnotations[1],
+			fileAnnotations[2],
+		},
+		FilterFileAnnotations(exceptionSet, symbolResolver, fileAnnotations),
+	)
+}
+
+func TestReadExceptionSetInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := ReadExceptionSet(strings.NewReader("version: v2\n"))
+	require.ErrorContains(t, err, `unknown breaking exceptions version "v2"`)
+	_, err = ReadExceptionSet(
+		strings.NewReader(`version: v1
+exceptions:
+  - symbol: acme.pet.v1.Pet
+    message: Previously present field "2" with name "age" on message "Pet" was deleted.
+    reason: ""
+`),
+	)
+	require.ErrorContains(t, err, "rule is required")
+	_, err = ReadExceptionSet(
+		strings.NewReader(`version: v1
+exceptions:
+  - rule: FIELD_NO_DELETE
+    symbol: acme.pet.v1.Pet
+    path: acme/pet/v1/pet.proto
+    reason: ""
+`),
+	)
+	require.ErrorContains(t, err, "cannot have both a symbol and a path")
+}
+
+func testBuildImage(t *testing.T) bufimage.Image {
+	moduleSet, err := bufmoduletesting.NewModuleSetForPathToData(
+		map[string][]byte{
+			"acme/pet/v1/pet.proto": []byte(testProto),
+		},
+	)
+	require.NoError(t, err)
+	image, err := bufimage.BuildImage(
+		context.Background(),
+		slogtestext.NewLogger(t),
+		bufmodule.ModuleSetToModuleReadBucketWithOnlyProtoFiles(moduleSet),
+	)
+	require.NoError(t, err)
+	return image
+}
+
+func newFileAnnotation(path string, line int, column int, typeString string, message string) bufanalysis.FileAnnotation {
+	return bufanalysis.NewFileAnnotation(
+		&fileInfo{path: path},
+		line,
+		column,
+		line,
+		column+1,
+		typeString,
+		message,
+		"",
+	)
+}
+
+type fileInfo struct {
+	path string
+}
+
+func (f *fileInfo) Path() string {
+	return f.path
+}
+
+func (f *fileInfo) ExternalPath() string {
+	return f.path
+}
@@ -0,0 +1,224 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufbreakingexception
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/encoding"
+	"github.com/bufbuild/buf/private/pkg/syserror"
+)
+
+const exceptionSetVersion = "v1"
+
+type exceptionSet struct {
+	exceptions []Exception
+}
+
+func newExceptionSet(keyToReason map[exceptionKey]string) *exceptionSet {
+	exceptions := make([]Exception, 0, len(keyToReason))
+	for key, reason := range keyToReason {
+		exceptions = append(
+			exceptions,
+			Exception{
+				Rule:    key.rule,
+				Symbol:  key.symbol,
+				Path:    key.path,
+				Message: key.message,
+				Reason:  reason,
+			},
+		)
+	}
+	slices.SortFunc(exceptions, compareExceptions)
+	return &exceptionSet{
+		exceptions: exceptions,
+	}
+}
+
+func newExceptionSetForFileAnnotations(
+	symbolResolver SymbolResolver,
+	fileAnnotations []bufanalysis.FileAnnotation,
+	existing ExceptionSet,
+	reason string,
+) *exceptionSet {
+	existingKeyToReason := make(map[exceptionKey]string)
+	if existing != nil {
+		for _, exception := range existing.Exceptions() {
+			existingKeyToReason[getExceptionKeyForException(exception)] = exception.Reason
+		}
+	}
+	keyToReason := make(map[exceptionKey]string)
+	for _, fileAnnotation := range fileAnnotations {
+		key := getExceptionKey(symbolResolver, fileAnnotation)
+		if existingReason, ok := existingKeyToReason[key]; ok {
+			keyToReason[key] = existingReason
+		} else {
+			keyToReason[key] = reason
+		}
+	}
+	return newExceptionSet(keyToReason)
+}
+
+func filterFileAnnotations(
+	exceptionSet ExceptionSet,
+	symbolResolver SymbolResolver,
+	fileAnnotations []bufanalysis.FileAnnotation,
+) []bufanalysis.FileAnnotation {
+	keys := make(map[exceptionKey]struct{})
+	for _, exception := range exceptionSet.Exceptions() {
+		keys[getExceptionKeyForException(exception)] = struct{}{}
+	}
+	var filteredFileAnnotations []bufanalysis.FileAnnotation
+	for _, fileAnnotation := range fileAnnotations {
+		if _, ok := keys[getExceptionKey(symbolResolver, fileAnnotation)]; ok {
+			continue
+		}
+		filteredFileAnnotations = append(filteredFileAnnotations, fileAnnotation)
+	}
+	return filteredFileAnnotations
+}
+
+func readExceptionSet(reader io.Reader) (*exceptionSet, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var externalExceptionSet externalExceptionSetV1
+	if err := encoding.UnmarshalYAMLStrict(data, &externalExceptionSet); err != nil {
+		return nil, err
+	}
+	if externalExceptionSet.Version != exceptionSetVersion {
+		return nil, fmt.Errorf("unknown breaking exceptions version %q, expected %q", externalExceptionSet.Version, exceptionSetVersion)
+	}
+	keyToReason := make(map[exceptionKey]string, len(externalExceptionSet.Exceptions))
+	for _, externalException := range externalExceptionSet.Exceptions {
+		if externalException.Rule == "" {
+			return nil, fmt.Errorf("rule is required for exception for %q", externalException.Message)
+		}
+		if externalException.Symbol != "" && externalException.Path != "" {
+			return nil, fmt.Errorf("%s exception for %q cannot have both a symbol and a path", externalException.Rule, externalException.Symbol)
+		}
+		keyToReason[getExceptionKeyForException(
+			Exception{
+				Rule:    externalException.Rule,
+				Symbol:  externalException.Symbol,
+				Path:    externalException.Path,
+				Message: externalException.Message,
+			},
+		)] = externalException.Reason
+	}
+	return newExceptionSet(keyToReason), nil
+}
+
+func writeExceptionSet(writer io.Writer, exceptionSet ExceptionSet) error {
+	if exceptionSet == nil {
+		return syserror.New("nil ExceptionSet")
+	}
+	exceptions := exceptionSet.Exceptions()
+	externalExceptionSet := externalExceptionSetV1{
+		Version:    exceptionSetVersion,
+		Exceptions: make([]externalExceptionV1, len(exceptions)),
+	}
+	for i, exception := range exceptions {
+		externalExceptionSet.Exceptions[i] = externalExceptionV1{
+			Rule:    exception.Rule,
+			Symbol:  exception.Symbol,
+			Path:    exception.Path,
+			Message: exception.Message,
+			Reason:  exception.Reason,
+		}
+	}
+	data, err := encoding.MarshalYAML(&externalExceptionSet)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
+
+func (e *exceptionSet) Exceptions() []Exception {
+	return slices.Clone(e.exceptions)
+}
+
+func (*exceptionSet) isExceptionSet() {}
+
+type exceptionKey struct {
+	rule    string
+	symbol  string
+	path    string
+	message string
+}
+
+func getExceptionKey(symbolResolver SymbolResolver, fileAnnotation bufanalysis.FileAnnotation) exceptionKey {
+	var path string
+	if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+		path = fileInfo.Path()
+	}
+	return getExceptionKeyForException(
+		Exception{
+			Rule:    fileAnnotation.Type(),
+			Symbol:  symbolResolver.Symbol(fileAnnotation),
+			Path:    path,
+			Message: fileAnnotation.Message(),
+		},
+	)
+}
+
+// getExceptionKeyForException returns the key of the Exception.
+//
+// The path is only part of the key if the symbol is empty.
+func getExceptionKeyForException(exception Exception) exceptionKey {
+	key := exceptionKey{
+		rule:    exception.Rule,
+		symbol:  exception.Symbol,
+		message: exception.Message,
+	}
+	if key.symbol == "" {
+		key.path = exception.Path
+	}
+	return key
+}
+
+func compareExceptions(a Exception, b Exception) int {
+	if c := cmp.Compare(a.Rule, b.Rule); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Symbol, b.Symbol); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Path, b.Path); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Message, b.Message)
+}
+
+// externalExceptionSetV1 represents a v1 breaking exceptions file.
+type externalExceptionSetV1 struct {
+	Version    string                `json:"version,omitempty" yaml:"version,omitempty"`
+	Exceptions []externalExceptionV1 `json:"exceptions,omitempty" yaml:"exceptions,omitempty"`
+}
+
+// externalExceptionV1 represents an exception in a v1 breaking exceptions file.
+type externalExceptionV1 struct {
+	Rule    string `json:"rule,omitempty" yaml:"rule,omitempty"`
+	Symbol  string `json:"symbol,omitempty" yaml:"symbol,omitempty"`
+	Path    string `json:"path,omitempty" yaml:"path,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	Reason  string `json:"reason" yaml:"reason"`
+}
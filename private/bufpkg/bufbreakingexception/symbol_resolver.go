@@ -0,0 +1,210 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufbreakingexception
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+type symbolResolver struct {
+	pathToDeclarations map[string][]*declaration
+}
+
+func newSymbolResolver(images []bufimage.Image) *symbolResolver {
+	pathToDeclarations := make(map[string][]*declaration)
+	for _, image := range images {
+		for _, imageFile := range image.Files() {
+			if _, ok := pathToDeclarations[imageFile.Path()]; ok {
+				continue
+			}
+			pathToDeclarations[imageFile.Path()] = getDeclarations(imageFile.FileDescriptorProto())
+		}
+	}
+	return &symbolResolver{
+		pathToDeclarations: pathToDeclarations,
+	}
+}
+
+func (s *symbolResolver) Symbol(fileAnnotation bufanalysis.FileAnnotation) string {
+	fileInfo := fileAnnotation.FileInfo()
+	if fileInfo == nil || fileAnnotation.StartLine() == 0 {
+		return ""
+	}
+	// FileAnnotations are 1-indexed, spans are 0-indexed.
+	line := fileAnnotation.StartLine() - 1
+	column := fileAnnotation.StartColumn() - 1
+	var innermostDeclaration *declaration
+	for _, declaration := range s.pathToDeclarations[fileInfo.Path()] {
+		if !declaration.contains(line, column) {
+			continue
+		}
+		// Nested declarations start after the declarations that contain them.
+		if innermostDeclaration == nil || declaration.startsAfter(innermostDeclaration) {
+			innermostDeclaration = declaration
+		}
+	}
+	if innermostDeclaration == nil {
+		return ""
+	}
+	return innermostDeclaration.fullName
+}
+
+func (*symbolResolver) isSymbolResolver() {}
+
+// declaration is a declaration within a file, with its 0-indexed span.
+type declaration struct {
+	fullName    string
+	startLine   int
+	startColumn int
+	endLine     int
+	endColumn   int
+}
+
+func (d *declaration) contains(line int, column int) bool {
+	if line < d.startLine || line > d.endLine {
+		return false
+	}
+	if line == d.startLine && column < d.startColumn {
+		return false
+	}
+	if line == d.endLine && column >= d.endColumn {
+		return false
+	}
+	return true
+}
+
+func (d *declaration) startsAfter(other *declaration) bool {
+	if d.startLine != other.startLine {
+		return d.startLine > other.startLine
+	}
+	return d.startColumn > other.startColumn
+}
+
+// getDeclarations returns the declarations of the file that have a span.
+//
+// The full names are the same as the full names of protoreflect, so that the values of
+// an enum are siblings of the enum.
+func getDeclarations(fileDescriptorProto *descriptorpb.FileDescriptorProto) []*declaration {
+	declarationBuilder := newDeclarationBuilder(fileDescriptorProto)
+	scope := fileDescriptorProto.GetPackage()
+	for i, descriptorProto := range fileDescriptorProto.GetMessageType() {
+		declarationBuilder.addMessage(scope, descriptorProto, []int32{4, int32(i)})
+	}
+	for i, enumDescriptorProto := range fileDescriptorProto.GetEnumType() {
+		declarationBuilder.addEnum(scope, enumDescriptorProto, []int32{5, int32(i)})
+	}
+	for i, serviceDescriptorProto := range fileDescriptorProto.GetService() {
+		serviceSourcePath := []int32{6, int32(i)}
+		serviceFullName := joinFullName(scope, serviceDescriptorProto.GetName())
+		declarationBuilder.add(serviceFullName, serviceSourcePath)
+		for j, methodDescriptorProto := range serviceDescriptorProto.GetMethod() {
+			declarationBuilder.add(joinFullName(serviceFullName, methodDescriptorProto.GetName()), appendSourcePath(serviceSourcePath, 2, int32(j)))
+		}
+	}
+	for i, fieldDescriptorProto := range fileDescriptorProto.GetExtension() {
+		declarationBuilder.add(joinFullName(scope, fieldDescriptorProto.GetName()), []int32{7, int32(i)})
+	}
+	return declarationBuilder.declarations
+}
+
+type declarationBuilder struct {
+	sourcePathToSpan map[string][]int32
+	declarations     []*declaration
+}
+
+func newDeclarationBuilder(fileDescriptorProto *descriptorpb.FileDescriptorProto) *declarationBuilder {
+	sourcePathToSpan := make(map[string][]int32)
+	for _, location := range fileDescriptorProto.GetSourceCodeInfo().GetLocation() {
+		sourcePathToSpan[sourcePathKey(location.GetPath())] = location.GetSpan()
+	}
+	return &declarationBuilder{
+		sourcePathToSpan: sourcePathToSpan,
+	}
+}
+
+func (b *declarationBuilder) addMessage(scope string, descriptorProto *descriptorpb.DescriptorProto, sourcePath []int32) {
+	fullName := joinFullName(scope, descriptorProto.GetName())
+	b.add(fullName, sourcePath)
+	for i, fieldDescriptorProto := range descriptorProto.GetField() {
+		b.add(joinFullName(fullName, fieldDescriptorProto.GetName()), appendSourcePath(sourcePath, 2, int32(i)))
+	}
+	for i, nestedDescriptorProto := range descriptorProto.GetNestedType() {
+		b.addMessage(fullName, nestedDescriptorProto, appendSourcePath(sourcePath, 3, int32(i)))
+	}
+	for i, enumDescriptorProto := range descriptorProto.GetEnumType() {
+		b.addEnum(fullName, enumDescriptorProto, appendSourcePath(sourcePath, 4, int32(i)))
+	}
+	for i, fieldDescriptorProto := range descriptorProto.GetExtension() {
+		b.add(joinFullName(fullName, fieldDescriptorProto.GetName()), appendSourcePath(sourcePath, 6, int32(i)))
+	}
+	for i, oneofDescriptorProto := range descriptorProto.GetOneofDecl() {
+		b.add(joinFullName(fullName, oneofDescriptorProto.GetName()), appendSourcePath(sourcePath, 8, int32(i)))
+	}
+}
+
+func (b *declarationBuilder) addEnum(scope string, enumDescriptorProto *descriptorpb.EnumDescriptorProto, sourcePath []int32) {
+	b.add(joinFullName(scope, enumDescriptorProto.GetName()), sourcePath)
+	for i, enumValueDescriptorProto := range enumDescriptorProto.GetValue() {
+		b.add(joinFullName(scope, enumValueDescriptorProto.GetName()), appendSourcePath(sourcePath, 2, int32(i)))
+	}
+}
+
+func (b *declarationBuilder) add(fullName string, sourcePath []int32) {
+	declaration := &declaration{
+		fullName: fullName,
+	}
+	// Spans are [startLine, startColumn, endLine, endColumn], or [startLine, startColumn, endColumn]
+	// if the declaration starts and ends on the same line.
+	switch span := b.sourcePathToSpan[sourcePathKey(sourcePath)]; len(span) {
+	case 3:
+		declaration.startLine = int(span[0])
+		declaration.startColumn = int(span[1])
+		declaration.endLine = int(span[0])
+		declaration.endColumn = int(span[2])
+	case 4:
+		declaration.startLine = int(span[0])
+		declaration.startColumn = int(span[1])
+		declaration.endLine = int(span[2])
+		declaration.endColumn = int(span[3])
+	default:
+		return
+	}
+	b.declarations = append(b.declarations, declaration)
+}
+
+func joinFullName(scope string, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+func sourcePathKey(sourcePath []int32) string {
+	elements := make([]string, len(sourcePath))
+	for i, element := range sourcePath {
+		elements[i] = strconv.Itoa(int(element))
+	}
+	return strings.Join(elements, ".")
+}
+
+func appendSourcePath(sourcePath []int32, elements ...int32) []int32 {
+	return append(slices.Clone(sourcePath), elements...)
+}
@@ -46,9 +46,15 @@ func NewBlobForContent(reader io.Reader, options ...BlobOption) (Blob, error) {
 	for _, option := range options {
 		option(blobOptions)
 	}
+	digestType := blobOptions.digestType
+	if digestType == 0 && blobOptions.knownDigest != nil {
+		// Verify with the algorithm the known Digest was computed with, so that Digests
+		// of any known DigestType can be verified, not just the default DigestType.
+		digestType = blobOptions.knownDigest.Type()
+	}
 	buffer := bytes.NewBuffer(nil)
 	teeReader := io.TeeReader(reader, buffer)
-	digest, err := NewDigestForContent(teeReader, DigestWithDigestType(blobOptions.digestType))
+	digest, err := NewDigestForContent(teeReader, DigestWithDigestType(digestType))
 	if err != nil {
 		return nil, err
 	}
@@ -64,6 +70,9 @@ type BlobOption func(*blobOptions)
 
 // BlobWithKnownDigest returns a new BlobOption that results in validation that the
 // Digest for the new Blob matches an existing known Digest.
+//
+// If BlobWithDigestType is not also specified, the Digest for the new Blob is computed
+// with the DigestType of the known Digest.
 func BlobWithKnownDigest(knownDigest Digest) BlobOption {
 	return func(blobOptions *blobOptions) {
 		blobOptions.knownDigest = knownDigest
@@ -72,7 +81,8 @@ func BlobWithKnownDigest(knownDigest Digest) BlobOption {
 
 // BlobWithDigestType returns a new BlobOption sets the DigestType to be used.
 //
-// The default is DigestTypeShake256.
+// The default is the DigestType of the Digest given to BlobWithKnownDigest if specified,
+// and DigestTypeShake256 otherwise.
 func BlobWithDigestType(digestType DigestType) BlobOption {
 	return func(blobOptions *blobOptions) {
 		blobOptions.digestType = digestType
@@ -24,14 +24,12 @@ import (
 	"strings"
 
 	"github.com/bufbuild/buf/private/bufpkg/bufparse"
-	"github.com/bufbuild/buf/private/pkg/shake256"
-	"github.com/bufbuild/buf/private/pkg/syserror"
 )
 
 const (
 	// DigestTypeShake256 represents the shake256 digest type.
 	//
-	// This is both the default and the only currently-known value for DigestType.
+	// This is the default DigestType.
 	DigestTypeShake256 DigestType = iota + 1
 )
 
@@ -95,24 +93,14 @@ type Digest interface {
 
 // NewDigest returns a new Digest for the value.
 func NewDigest(value []byte, options ...DigestOption) (Digest, error) {
-	digestOptions := newDigestOptions()
-	for _, option := range options {
-		option(digestOptions)
-	}
-	if digestOptions.digestType == 0 {
-		digestOptions.digestType = DigestTypeShake256
+	digestAlgorithm, err := getDigestAlgorithmForOptions(options)
+	if err != nil {
+		return nil, err
 	}
-	switch digestOptions.digestType {
-	case DigestTypeShake256:
-		shake256Digest, err := shake256.NewDigest(value)
-		if err != nil {
-			return nil, err
-		}
-		return newDigest(DigestTypeShake256, shake256Digest.Value()), nil
-	default:
-		// This is a system error.
-		return nil, syserror.Newf("unknown DigestType: %v", digestOptions.digestType)
+	if err := digestAlgorithm.ValidateValue(value); err != nil {
+		return nil, err
 	}
+	return newDigest(digestAlgorithm.Type(), value), nil
 }
 
 // NewDigestForContent creates a new Digest based on the given content read from the Reader.
@@ -121,24 +109,15 @@ func NewDigest(value []byte, options ...DigestOption) (Digest, error) {
 //
 // The Reader is read until io.EOF.
 func NewDigestForContent(reader io.Reader, options ...DigestOption) (Digest, error) {
-	digestOptions := newDigestOptions()
-	for _, option := range options {
-		option(digestOptions)
-	}
-	if digestOptions.digestType == 0 {
-		digestOptions.digestType = DigestTypeShake256
+	digestAlgorithm, err := getDigestAlgorithmForOptions(options)
+	if err != nil {
+		return nil, err
 	}
-	switch digestOptions.digestType {
-	case DigestTypeShake256:
-		shake256Digest, err := shake256.NewDigestForContent(reader)
-		if err != nil {
-			return nil, err
-		}
-		return newDigest(DigestTypeShake256, shake256Digest.Value()), nil
-	default:
-		// This is a system error.
-		return nil, syserror.Newf("unknown DigestType: %v", digestOptions.digestType)
+	value, err := digestAlgorithm.ComputeValue(reader)
+	if err != nil {
+		return nil, err
 	}
+	return newDigest(digestAlgorithm.Type(), value), nil
 }
 
 // DigestOption is an option for a new Digest.
@@ -190,7 +169,15 @@ func ParseDigest(s string) (Digest, error) {
 			errors.New(`could not parse hex: must in the form "digest_type:digest_hex_value"`),
 		)
 	}
-	if err := validateDigestParameters(digestType, value); err != nil {
+	digestAlgorithm, err := GetDigestAlgorithm(digestType)
+	if err != nil {
+		return nil, bufparse.NewParseError(
+			"digest",
+			s,
+			err,
+		)
+	}
+	if err := digestAlgorithm.ValidateValue(value); err != nil {
 		return nil, bufparse.NewParseError(
 			"digest",
 			s,
@@ -259,17 +246,13 @@ func newDigestOptions() *digestOptions {
 	return &digestOptions{}
 }
 
-func validateDigestParameters(digestType DigestType, value []byte) error {
-	switch digestType {
-	case DigestTypeShake256:
-		_, err := shake256.NewDigest(value)
-		if err != nil {
-			return err
-		}
-	default:
-		// This is really always a system error, but little harm in including it here, even
-		// though it'll get converted into a bufparse.ParseError in parse.
-		return syserror.Newf(`unknown digest type: %q`, digestType.String())
+func getDigestAlgorithmForOptions(options []DigestOption) (DigestAlgorithm, error) {
+	digestOptions := newDigestOptions()
+	for _, option := range options {
+		option(digestOptions)
+	}
+	if digestOptions.digestType == 0 {
+		digestOptions.digestType = DigestTypeShake256
 	}
-	return nil
+	return GetDigestAlgorithm(digestOptions.digestType)
 }
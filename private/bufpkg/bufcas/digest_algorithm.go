@@ -0,0 +1,88 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcas
+
+import (
+	"io"
+
+	"github.com/bufbuild/buf/private/pkg/shake256"
+	"github.com/bufbuild/buf/private/pkg/syserror"
+)
+
+var (
+	// AllDigestTypes are all known DigestTypes.
+	//
+	// Each DigestType has a DigestAlgorithm that can be retrieved with GetDigestAlgorithm.
+	AllDigestTypes = []DigestType{
+		DigestTypeShake256,
+	}
+
+	digestTypeToDigestAlgorithm = map[DigestType]DigestAlgorithm{
+		DigestTypeShake256: shake256DigestAlgorithm{},
+	}
+)
+
+// DigestAlgorithm computes and validates digest values for a single DigestType.
+//
+// Adding support for a new digest algorithm consists of adding a DigestType, and a
+// DigestAlgorithm for it. All of NewDigest, NewDigestForContent, and ParseDigest then
+// support the new DigestType.
+type DigestAlgorithm interface {
+	// Type returns the DigestType of the digest values computed by this DigestAlgorithm.
+	Type() DigestType
+	// ComputeValue computes the digest value for the content read from the Reader.
+	//
+	// The Reader is read until io.EOF.
+	ComputeValue(reader io.Reader) ([]byte, error)
+	// ValidateValue returns an error if the value is not a valid digest value for this DigestAlgorithm.
+	ValidateValue(value []byte) error
+
+	isDigestAlgorithm()
+}
+
+// GetDigestAlgorithm returns the DigestAlgorithm for the DigestType.
+//
+// Returns an error if the DigestType is not known.
+func GetDigestAlgorithm(digestType DigestType) (DigestAlgorithm, error) {
+	digestAlgorithm, ok := digestTypeToDigestAlgorithm[digestType]
+	if !ok {
+		// This is a system error.
+		return nil, syserror.Newf("unknown DigestType: %v", digestType)
+	}
+	return digestAlgorithm, nil
+}
+
+// *** PRIVATE ***
+
+type shake256DigestAlgorithm struct{}
+
+func (shake256DigestAlgorithm) Type() DigestType {
+	return DigestTypeShake256
+}
+
+func (shake256DigestAlgorithm) ComputeValue(reader io.Reader) ([]byte, error) {
+	shake256Digest, err := shake256.NewDigestForContent(reader)
+	if err != nil {
+		return nil, err
+	}
+	return shake256Digest.Value(), nil
+}
+
+func (shake256DigestAlgorithm) ValidateValue(value []byte) error {
+	_, err := shake256.NewDigest(value)
+	return err
+}
+
+func (shake256DigestAlgorithm) isDigestAlgorithm() {}
@@ -76,6 +76,41 @@ func TestDigestEqual(t *testing.T) {
 	assert.False(t, bufcas.DigestEqual(d2, d3))
 }
 
+func TestDigestAlgorithms(t *testing.T) {
+	t.Parallel()
+	for _, digestType := range bufcas.AllDigestTypes {
+		t.Run(digestType.String(), func(t *testing.T) {
+			t.Parallel()
+			digestAlgorithm, err := bufcas.GetDigestAlgorithm(digestType)
+			require.NoError(t, err)
+			assert.Equal(t, digestType, digestAlgorithm.Type())
+			parsedDigestType, err := bufcas.ParseDigestType(digestType.String())
+			require.NoError(t, err)
+			assert.Equal(t, digestType, parsedDigestType)
+			digest, err := bufcas.NewDigestForContent(
+				strings.NewReader("some content"),
+				bufcas.DigestWithDigestType(digestType),
+			)
+			require.NoError(t, err)
+			assert.Equal(t, digestType, digest.Type())
+			require.NoError(t, digestAlgorithm.ValidateValue(digest.Value()))
+			parsedDigest, err := bufcas.ParseDigest(digest.String())
+			require.NoError(t, err)
+			assert.True(t, bufcas.DigestEqual(digest, parsedDigest))
+			newDigest, err := bufcas.NewDigest(digest.Value(), bufcas.DigestWithDigestType(digestType))
+			require.NoError(t, err)
+			assert.True(t, bufcas.DigestEqual(digest, newDigest))
+			blob, err := bufcas.NewBlobForContent(strings.NewReader("some content"), bufcas.BlobWithKnownDigest(digest))
+			require.NoError(t, err)
+			assert.True(t, bufcas.DigestEqual(digest, blob.Digest()))
+		})
+	}
+	_, err := bufcas.GetDigestAlgorithm(bufcas.DigestType(0))
+	require.Error(t, err)
+	_, err = bufcas.NewDigestForContent(strings.NewReader("some content"), bufcas.DigestWithDigestType(bufcas.DigestType(1000)))
+	require.Error(t, err)
+}
+
 func testParseDigestError(t *testing.T, digestString string, expectParseError bool) {
 	_, err := bufcas.ParseDigest(digestString)
 	assert.Error(t, err)
@@ -37,14 +37,25 @@ func (a *annotation) PluginName() string {
 	return a.pluginName
 }
 
+// impactClassifier may be nil, in which case the FileAnnotations are not
+// classified by impact.
+//
+// codeFrameProvider may be nil, in which case the FileAnnotations do not have
+// code frames.
+//
+// The FileAnnotations for the Rule IDs in warnRuleIDs are warnings.
 func annotationsToFileAnnotations(
 	pathToExternalPath map[string]string,
 	annotations []*annotation,
+	impactClassifier *impactClassifier,
+	failOnClassifier *failOnClassifier,
+	codeFrameProvider *codeFrameProvider,
+	warnRuleIDs map[string]struct{},
 ) []bufanalysis.FileAnnotation {
 	return slicesext.Map(
 		annotations,
 		func(annotation *annotation) bufanalysis.FileAnnotation {
-			return annotationToFileAnnotation(pathToExternalPath, annotation)
+			return annotationToFileAnnotation(pathToExternalPath, annotation, impactClassifier, failOnClassifier, codeFrameProvider, warnRuleIDs)
 		},
 	)
 }
@@ -52,7 +63,26 @@ func annotationsToFileAnnotations(
 func annotationToFileAnnotation(
 	pathToExternalPath map[string]string,
 	annotation *annotation,
+	impactClassifier *impactClassifier,
+	failOnClassifier *failOnClassifier,
+	codeFrameProvider *codeFrameProvider,
+	warnRuleIDs map[string]struct{},
 ) bufanalysis.FileAnnotation {
+	var options []bufanalysis.FileAnnotationOption
+	if impactClassifier != nil {
+		options = append(options, bufanalysis.FileAnnotationWithImpact(impactClassifier.Impact(annotation)))
+	}
+	if codeFrameProvider != nil {
+		if codeFrame := codeFrameProvider.CodeFrame(annotation); codeFrame != nil {
+			options = append(options, bufanalysis.FileAnnotationWithCodeFrame(codeFrame))
+		}
+		if againstCodeFrame := codeFrameProvider.AgainstCodeFrame(annotation); againstCodeFrame != nil {
+			options = append(options, bufanalysis.FileAnnotationWithAgainstCodeFrame(againstCodeFrame))
+		}
+	}
+	if _, ok := warnRuleIDs[annotation.RuleID()]; ok || (failOnClassifier != nil && failOnClassifier.IsWarning(annotation)) {
+		options = append(options, bufanalysis.FileAnnotationWithSeverity(bufanalysis.SeverityWarning))
+	}
 	fileLocation := annotation.FileLocation()
 	if fileLocation == nil {
 		// We have to do this or we get a weird fileInfo != nil but it is nil thing.
@@ -65,6 +95,7 @@ func annotationToFileAnnotation(
 			annotation.RuleID(),
 			annotation.Message(),
 			annotation.PluginName(),
+			options...,
 		)
 	}
 	path := fileLocation.FileDescriptor().ProtoreflectFileDescriptor().Path()
@@ -84,5 +115,6 @@ func annotationToFileAnnotation(
 		annotation.RuleID(),
 		annotation.Message(),
 		annotation.PluginName(),
+		options...,
 	)
 }
@@ -19,15 +19,19 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"buf.build/go/bufplugin/check"
 	"github.com/bufbuild/buf/private/buf/buftarget"
 	"github.com/bufbuild/buf/private/buf/bufworkspace"
 	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/bufpkg/bufanalysis/bufanalysistesting"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
 	"github.com/bufbuild/buf/private/bufpkg/bufplugin"
@@ -943,6 +947,117 @@ func TestRunBreakingFileSameValues(t *testing.T) {
 	)
 }
 
+func TestRunBreakingJSON(t *testing.T) {
+	t.Parallel()
+	// The JSON category includes Rules for changes to JSON names, and for changes to
+	// field types and cardinalities that the JSON encoding does not accept, but not
+	// for changes that only affect the binary encoding.
+	testBreaking(
+		t,
+		"breaking_json",
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 5, 1, 13, 2, "FIELD_NO_DELETE_UNLESS_NAME_RESERVED"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 8, 3, 8, 25, "FIELD_SAME_JSON_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 8, 9, 8, 20, "FIELD_SAME_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 9, 18, 9, 35, "FIELD_SAME_JSON_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 10, 3, 10, 9, "FIELD_WIRE_JSON_COMPATIBLE_TYPE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 11, 3, 11, 8, "FIELD_WIRE_JSON_COMPATIBLE_TYPE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 12, 3, 12, 26, "FIELD_WIRE_JSON_COMPATIBLE_CARDINALITY"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 17, 21, 17, 22, "ENUM_VALUE_SAME_NAME"),
+	)
+}
+
+func TestRunBreakingProtovalidate(t *testing.T) {
+	t.Parallel()
+	// Widened ranges, exclusive ranges, changed field types, ignored fields, and fields
+	// on messages with validation disabled are not breaking.
+	testBreaking(
+		t,
+		"breaking_protovalidate",
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 9, 29, 9, 62, "PROTOVALIDATE_FIELD_NO_NARROW_RANGE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 10, 29, 10, 64, "PROTOVALIDATE_FIELD_NO_NARROW_RANGE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 15, 26, 15, 60, "PROTOVALIDATE_FIELD_NO_NARROW_RANGE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 20, 26, 25, 4, "PROTOVALIDATE_FIELD_NO_NARROW_RANGE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 26, 28, 31, 4, "PROTOVALIDATE_FIELD_NO_NARROW_RANGE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 37, 23, 37, 62, "PROTOVALIDATE_FIELD_NO_NARROW_RANGE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 39, 18, 39, 52, "PROTOVALIDATE_FIELD_NO_NARROW_RANGE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 40, 30, 46, 4, "PROTOVALIDATE_FIELD_NO_NARROW_RANGE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 40, 30, 46, 4, "PROTOVALIDATE_FIELD_NO_NARROW_RANGE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 47, 33, 47, 78, "PROTOVALIDATE_FIELD_NO_NARROW_RANGE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 48, 42, 48, 89, "PROTOVALIDATE_FIELD_NO_NARROW_RANGE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 49, 24, 49, 65, "PROTOVALIDATE_FIELD_NO_NARROW_RANGE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 53, 21, 53, 57, "PROTOVALIDATE_FIELD_NO_ADD_REQUIRED"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 67, 3, 70, 5, "PROTOVALIDATE_MESSAGE_NO_ADD_CEL"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 76, 5, 79, 6, "PROTOVALIDATE_FIELD_NO_ADD_CEL"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 95, 25, 100, 4, "PROTOVALIDATE_FIELD_NO_REMOVE_ENUM_VALUE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 101, 27, 106, 4, "PROTOVALIDATE_FIELD_NO_REMOVE_ENUM_VALUE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 107, 33, 107, 78, "PROTOVALIDATE_FIELD_NO_REMOVE_ENUM_VALUE"),
+	)
+}
+
+func TestBreakingJSONCategoryRules(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client, err := bufcheck.NewClient(slogtestext.NewLogger(t), bufcheck.NewLocalRunnerProvider(
+		wasm.UnimplementedRuntime,
+		bufplugin.NopPluginKeyProvider,
+		bufplugin.NopPluginDataProvider,
+	))
+	require.NoError(t, err)
+	for _, fileVersion := range []bufconfig.FileVersion{bufconfig.FileVersionV1, bufconfig.FileVersionV2} {
+		rules, err := client.AllRules(ctx, check.RuleTypeBreaking, fileVersion)
+		require.NoError(t, err)
+		var jsonRuleIDs []string
+		for _, rule := range rules {
+			for _, category := range rule.BufcheckCategories() {
+				if category.ID() == "JSON" {
+					jsonRuleIDs = append(jsonRuleIDs, rule.ID())
+				}
+			}
+		}
+		slices.Sort(jsonRuleIDs)
+		assert.Equal(
+			t,
+			[]string{
+				"ENUM_SAME_JSON_FORMAT",
+				"ENUM_VALUE_NO_DELETE_UNLESS_NAME_RESERVED",
+				"ENUM_VALUE_SAME_NAME",
+				"FIELD_NO_DELETE_UNLESS_NAME_RESERVED",
+				"FIELD_SAME_JSON_NAME",
+				"FIELD_SAME_NAME",
+				"FIELD_WIRE_JSON_COMPATIBLE_CARDINALITY",
+				"FIELD_WIRE_JSON_COMPATIBLE_TYPE",
+				"MESSAGE_SAME_JSON_FORMAT",
+			},
+			jsonRuleIDs,
+			fileVersion.String(),
+		)
+	}
+}
+
+func TestRuleExplanations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client, err := bufcheck.NewClient(slogtestext.NewLogger(t), bufcheck.NewLocalRunnerProvider(
+		wasm.UnimplementedRuntime,
+		bufplugin.NopPluginKeyProvider,
+		bufplugin.NopPluginDataProvider,
+	))
+	require.NoError(t, err)
+	for _, fileVersion := range []bufconfig.FileVersion{bufconfig.FileVersionV1Beta1, bufconfig.FileVersionV1, bufconfig.FileVersionV2} {
+		for _, ruleType := range []check.RuleType{check.RuleTypeLint, check.RuleTypeBreaking} {
+			rules, err := client.AllRules(ctx, ruleType, fileVersion)
+			require.NoError(t, err)
+			for _, rule := range rules {
+				explanation, ok := bufcheckserver.GetRuleExplanation(rule.Type(), rule.ID())
+				require.True(t, ok, "%s rule %s has no explanation", fileVersion, rule.ID())
+				assert.NotEmpty(t, explanation.Rationale, rule.ID())
+				assert.NotEmpty(t, explanation.Triggers, rule.ID())
+				assert.NotEmpty(t, explanation.Remediations, rule.ID())
+			}
+		}
+	}
+}
+
 func TestRunBreakingMessageNoDelete(t *testing.T) {
 	t.Parallel()
 	testBreaking(
@@ -1204,6 +1319,38 @@ func TestRunBreakingIgnoreUnstablePackagesDeleteFile(t *testing.T) {
 	)
 }
 
+func TestRunBreakingCommentIgnores(t *testing.T) {
+	t.Parallel()
+	testBreaking(
+		t,
+		"breaking_comment_ignores",
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 11, 1, 13, 2, "FIELD_NO_DELETE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 17, 3, 17, 8, "FIELD_SAME_TYPE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 21, 3, 21, 8, "FIELD_SAME_TYPE"),
+		bufanalysistesting.NewFileAnnotation(t, "1.proto", 24, 1, 26, 2, "FIELD_NO_DELETE"),
+	)
+}
+
+func TestRunBreakingIgnoreSymbols(t *testing.T) {
+	t.Parallel()
+	testBreaking(
+		t,
+		"breaking_ignore_symbols",
+		bufanalysistesting.NewFileAnnotation(t, "acme/v1/acme.proto", 5, 1, 7, 2, "FIELD_NO_DELETE"),
+		bufanalysistesting.NewFileAnnotation(t, "acme/v1/acme.proto", 9, 1, 9, 25, "RPC_NO_DELETE"),
+	)
+}
+
+func TestRunBreakingIgnoreOnlySymbols(t *testing.T) {
+	t.Parallel()
+	testBreaking(
+		t,
+		"breaking_ignore_only_symbols",
+		bufanalysistesting.NewFileAnnotation(t, "acme/internal/v1/internal.proto", 9, 1, 9, 27, "RPC_NO_DELETE"),
+		bufanalysistesting.NewFileAnnotation(t, "acme/v1/acme.proto", 5, 1, 7, 2, "FIELD_NO_DELETE"),
+	)
+}
+
 func TestRunBreakingIntEnum(t *testing.T) {
 	t.Parallel()
 	testBreaking(
@@ -18,12 +18,17 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"time"
 
 	"buf.build/go/bufplugin/check"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufextension"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage"
 	"github.com/bufbuild/buf/private/bufpkg/bufplugin"
+	"github.com/bufbuild/buf/private/bufpkg/bufreserved"
 	"github.com/bufbuild/buf/private/pkg/slicesext"
+	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/syserror"
 	"github.com/bufbuild/buf/private/pkg/wasm"
 	"pluginrpc.com/pluginrpc"
@@ -38,6 +43,9 @@ type Client interface {
 	// Images should *not* be filtered with regards to imports before passing to this function.
 	//
 	// An error of type bufanalysis.FileAnnotationSet will be returned lint failure.
+	// The FileAnnotations for the rules configured as warnings in the LintConfig have
+	// bufanalysis.SeverityWarning, and the FileAnnotationSet is returned even if all
+	// FileAnnotations are warnings.
 	Lint(ctx context.Context, config bufconfig.LintConfig, image bufimage.Image, options ...LintOption) error
 	// Breaking checks the given Images for breaking changes with the given BreakingConfig.
 	//
@@ -76,6 +84,49 @@ type Rule interface {
 	isRuleOrCategory()
 }
 
+// CommentIgnore is a comment that ignores a lint Rule for the element it is attached to,
+// such as:
+//
+//	// buf:lint:ignore FIELD_LOWER_SNAKE_CASE required by legacy clients expires=2026-12-31
+//
+// The text after the Rule ID is the justification, and "expires=YYYY-MM-DD" sets the
+// last day on which the comment ignore takes effect.
+type CommentIgnore interface {
+	// FileInfo returns the file that contains the comment ignore.
+	FileInfo() bufanalysis.FileInfo
+	// StartLine returns the 1-indexed line of the element the comment ignore is attached to.
+	StartLine() int
+	// StartColumn returns the 1-indexed column of the element the comment ignore is attached to.
+	StartColumn() int
+	// RuleID returns the ID of the ignored Rule.
+	RuleID() string
+	// Justification returns the justification for the comment ignore.
+	//
+	// May be empty.
+	Justification() string
+	// Expiry returns the last day on which the comment ignore takes effect.
+	//
+	// Zero if the comment ignore does not expire.
+	Expiry() time.Time
+
+	isCommentIgnore()
+}
+
+// ActiveCommentIgnores returns the comment ignores in the non-import files of the Image
+// that take effect at the given time for the given LintConfig.
+//
+// Comment ignores do not take effect if they are not allowed by the LintConfig, if they
+// have expired, or if they have no justification and the LintConfig requires one.
+//
+// CommentIgnores are returned sorted by path, line, column, and Rule ID.
+func ActiveCommentIgnores(
+	lintConfig bufconfig.LintConfig,
+	image bufimage.Image,
+	now time.Time,
+) ([]CommentIgnore, error) {
+	return activeCommentIgnores(lintConfig, image, now)
+}
+
 // Category is an individual line or breaking Category.
 //
 // It wraps check.Category and adds the name of the plugin that implements the Category.
@@ -122,6 +173,73 @@ func BreakingWithExcludeImports() BreakingOption {
 	return &excludeImportsOption{}
 }
 
+// BreakingWithCodeFrames returns a new BreakingOption that says to attach excerpts of
+// the source of the input and the against input at the location of each breaking change
+// to the FileAnnotations.
+//
+// The buckets contain the .proto files that the Image and the against Image were built
+// from, by path. Either bucket may be nil if the source is not available, such as for
+// image inputs, in which case the FileAnnotations have no code frames for that input.
+//
+// The default is to not attach code frames.
+func BreakingWithCodeFrames(sourceBucket storage.ReadBucket, againstSourceBucket storage.ReadBucket) BreakingOption {
+	return &codeFramesOption{
+		sourceBucket:        sourceBucket,
+		againstSourceBucket: againstSourceBucket,
+	}
+}
+
+// BreakingWithFailOn returns a new BreakingOption that says to only fail on the breaking
+// changes of the given rule IDs and category IDs. The breaking changes of all other rules
+// are warnings, unless one of the given rules reports a breaking change at the same location.
+//
+// This overrides the FailOnIDsAndCategories of the BreakingConfig.
+//
+// The default is to use the FailOnIDsAndCategories of the BreakingConfig.
+func BreakingWithFailOn(failOnIDsAndCategories ...string) BreakingOption {
+	return &failOnOption{
+		failOnIDsAndCategories: failOnIDsAndCategories,
+	}
+}
+
+// LintWithReservedRegistry returns a new LintOption that says to check for reuse of the
+// numbers and names recorded in the given reserved registry.
+//
+// The default is to not check against a reserved registry, in which case the
+// RESERVED_REGISTRY_NO_REUSE Rule never produces annotations.
+func LintWithReservedRegistry(reservedRegistry bufreserved.Registry) LintOption {
+	return &reservedRegistryOption{
+		reservedRegistry: reservedRegistry,
+	}
+}
+
+// LintWithExtensionRegistry returns a new LintOption that says to check that extensions
+// do not use numbers allocated to other extensions in the given extension registry.
+//
+// The default is to not check against an extension registry, in which case the
+// EXTENSION_REGISTRY_NO_CONFLICT Rule never produces annotations.
+func LintWithExtensionRegistry(extensionRegistry bufextension.Registry) LintOption {
+	return &extensionRegistryOption{
+		extensionRegistry: extensionRegistry,
+	}
+}
+
+// LintWithCache returns a new LintOption that says to cache the results of Lint in the
+// given bucket, and to use the cached results if nothing that the results depend on
+// has changed.
+//
+// The key of the cached results is the digest of the Image, the LintConfig, the plugins,
+// the registries, the buf version, and the current date, as comment ignores can expire.
+// Results are not cached if a remote plugin is configured.
+//
+// The default is to not cache the results.
+func LintWithCache(bucket storage.ReadWriteBucket, bufVersion string) LintOption {
+	return &lintCacheOption{
+		bucket:     bucket,
+		bufVersion: bufVersion,
+	}
+}
+
 // ConfiguredRulesOption is an option for ConfiguredRules.
 type ConfiguredRulesOption interface {
 	applyToConfiguredRules(*configuredRulesOptions)
@@ -256,6 +374,16 @@ func PrintRulesWithJSON() PrintRulesOption {
 	}
 }
 
+// PrintRulesWithMarkdown returns a new PrintRulesOption that says to print the rules as
+// Markdown documentation, with a section per plugin.
+//
+// The default is to print as text.
+func PrintRulesWithMarkdown() PrintRulesOption {
+	return func(printRulesOptions *printRulesOptions) {
+		printRulesOptions.asMarkdown = true
+	}
+}
+
 // PrintRulesWithDeprecated returns a new PrintRulesOption that results in deprecated rules  being printed.
 func PrintRulesWithDeprecated() PrintRulesOption {
 	return func(printRulesOptions *printRulesOptions) {
@@ -189,11 +189,11 @@ var (
 			bufcheckserverbuild.BreakingMessageNoRemoveStandardDescriptorAccessorRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE"}),
 			bufcheckserverbuild.BreakingOneofNoDeleteRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE"}),
 			bufcheckserverbuild.BreakingRPCNoDeleteRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE"}),
-			bufcheckserverbuild.BreakingEnumSameJSONFormatRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON"}),
-			bufcheckserverbuild.BreakingEnumValueSameNameRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON"}),
-			bufcheckserverbuild.BreakingFieldSameJSONNameRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON"}),
-			bufcheckserverbuild.BreakingFieldSameNameRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON"}),
-			bufcheckserverbuild.BreakingMessageSameJSONFormatRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON"}),
+			bufcheckserverbuild.BreakingEnumSameJSONFormatRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingEnumValueSameNameRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingFieldSameJSONNameRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingFieldSameNameRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingMessageSameJSONFormatRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}),
 			bufcheckserverbuild.BreakingFieldSameOneofRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "WIRE"}),
 			bufcheckserverbuild.BreakingFileSamePackageRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "WIRE"}),
 			bufcheckserverbuild.BreakingMessageSameRequiredFieldsRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "WIRE"}),
@@ -208,14 +208,19 @@ var (
 			bufcheckserverbuild.BreakingPackageMessageNoDeleteRuleSpecBuilder.Build(false, []string{"PACKAGE"}),
 			bufcheckserverbuild.BreakingPackageNoDeleteRuleSpecBuilder.Build(false, []string{"PACKAGE"}),
 			bufcheckserverbuild.BreakingPackageServiceNoDeleteRuleSpecBuilder.Build(false, []string{"PACKAGE"}),
-			bufcheckserverbuild.BreakingEnumValueNoDeleteUnlessNameReservedRuleSpecBuilder.Build(false, []string{"WIRE_JSON"}),
-			bufcheckserverbuild.BreakingFieldNoDeleteUnlessNameReservedRuleSpecBuilder.Build(false, []string{"WIRE_JSON"}),
-			bufcheckserverbuild.BreakingFieldWireJSONCompatibleCardinalityRuleSpecBuilder.Build(false, []string{"WIRE_JSON"}),
-			bufcheckserverbuild.BreakingFieldWireJSONCompatibleTypeRuleSpecBuilder.Build(false, []string{"WIRE_JSON"}),
+			bufcheckserverbuild.BreakingEnumValueNoDeleteUnlessNameReservedRuleSpecBuilder.Build(false, []string{"WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingFieldNoDeleteUnlessNameReservedRuleSpecBuilder.Build(false, []string{"WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingFieldWireJSONCompatibleCardinalityRuleSpecBuilder.Build(false, []string{"WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingFieldWireJSONCompatibleTypeRuleSpecBuilder.Build(false, []string{"WIRE_JSON", "JSON"}),
 			bufcheckserverbuild.BreakingEnumValueNoDeleteUnlessNumberReservedRuleSpecBuilder.Build(false, []string{"WIRE_JSON", "WIRE"}),
 			bufcheckserverbuild.BreakingFieldNoDeleteUnlessNumberReservedRuleSpecBuilder.Build(false, []string{"WIRE_JSON", "WIRE"}),
 			bufcheckserverbuild.BreakingFieldWireCompatibleCardinalityRuleSpecBuilder.Build(false, []string{"WIRE"}),
 			bufcheckserverbuild.BreakingFieldWireCompatibleTypeRuleSpecBuilder.Build(false, []string{"WIRE"}),
+			bufcheckserverbuild.BreakingProtovalidateFieldNoAddCELRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_CONSTRAINTS"}),
+			bufcheckserverbuild.BreakingProtovalidateFieldNoAddRequiredRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_CONSTRAINTS"}),
+			bufcheckserverbuild.BreakingProtovalidateFieldNoNarrowRangeRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_CONSTRAINTS"}),
+			bufcheckserverbuild.BreakingProtovalidateFieldNoRemoveEnumValueRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_CONSTRAINTS"}),
+			bufcheckserverbuild.BreakingProtovalidateMessageNoAddCELRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_CONSTRAINTS"}),
 			bufcheckserverbuild.BreakingFieldSameCTypeRuleSpecBuilder.Build(false, []string{}),
 			bufcheckserverbuild.BreakingFieldSameLabelRuleSpecBuilder.Build(false, []string{}),
 			bufcheckserverbuild.BreakingMessageSameMessageSetWireFormatRuleSpecBuilder.Build(false, []string{}),
@@ -271,6 +276,8 @@ var (
 			bufcheckserverbuild.PackageCategorySpec,
 			bufcheckserverbuild.WireCategorySpec,
 			bufcheckserverbuild.WireJSONCategorySpec,
+			bufcheckserverbuild.JSONCategorySpec,
+			bufcheckserverbuild.ProtovalidateCategorySpec,
 			bufcheckserverbuild.BasicCategorySpec,
 			bufcheckserverbuild.CommentsCategorySpec,
 			bufcheckserverbuild.DefaultCategorySpec,
@@ -319,11 +326,11 @@ var (
 			bufcheckserverbuild.BreakingMessageNoRemoveStandardDescriptorAccessorRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE"}),
 			bufcheckserverbuild.BreakingOneofNoDeleteRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE"}),
 			bufcheckserverbuild.BreakingRPCNoDeleteRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE"}),
-			bufcheckserverbuild.BreakingEnumSameJSONFormatRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON"}),
-			bufcheckserverbuild.BreakingEnumValueSameNameRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON"}),
-			bufcheckserverbuild.BreakingFieldSameJSONNameRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON"}),
-			bufcheckserverbuild.BreakingFieldSameNameRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON"}),
-			bufcheckserverbuild.BreakingMessageSameJSONFormatRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON"}),
+			bufcheckserverbuild.BreakingEnumSameJSONFormatRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingEnumValueSameNameRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingFieldSameJSONNameRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingFieldSameNameRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingMessageSameJSONFormatRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "JSON"}),
 			bufcheckserverbuild.BreakingFieldSameDefaultRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "WIRE"}),
 			bufcheckserverbuild.BreakingFieldSameOneofRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "WIRE"}),
 			bufcheckserverbuild.BreakingFileSamePackageRuleSpecBuilder.Build(true, []string{"FILE", "PACKAGE", "WIRE_JSON", "WIRE"}),
@@ -340,14 +347,19 @@ var (
 			bufcheckserverbuild.BreakingPackageMessageNoDeleteRuleSpecBuilder.Build(false, []string{"PACKAGE"}),
 			bufcheckserverbuild.BreakingPackageNoDeleteRuleSpecBuilder.Build(false, []string{"PACKAGE"}),
 			bufcheckserverbuild.BreakingPackageServiceNoDeleteRuleSpecBuilder.Build(false, []string{"PACKAGE"}),
-			bufcheckserverbuild.BreakingEnumValueNoDeleteUnlessNameReservedRuleSpecBuilder.Build(false, []string{"WIRE_JSON"}),
-			bufcheckserverbuild.BreakingFieldNoDeleteUnlessNameReservedRuleSpecBuilder.Build(false, []string{"WIRE_JSON"}),
-			bufcheckserverbuild.BreakingFieldWireJSONCompatibleCardinalityRuleSpecBuilder.Build(false, []string{"WIRE_JSON"}),
-			bufcheckserverbuild.BreakingFieldWireJSONCompatibleTypeRuleSpecBuilder.Build(false, []string{"WIRE_JSON"}),
+			bufcheckserverbuild.BreakingEnumValueNoDeleteUnlessNameReservedRuleSpecBuilder.Build(false, []string{"WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingFieldNoDeleteUnlessNameReservedRuleSpecBuilder.Build(false, []string{"WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingFieldWireJSONCompatibleCardinalityRuleSpecBuilder.Build(false, []string{"WIRE_JSON", "JSON"}),
+			bufcheckserverbuild.BreakingFieldWireJSONCompatibleTypeRuleSpecBuilder.Build(false, []string{"WIRE_JSON", "JSON"}),
 			bufcheckserverbuild.BreakingEnumValueNoDeleteUnlessNumberReservedRuleSpecBuilder.Build(false, []string{"WIRE_JSON", "WIRE"}),
 			bufcheckserverbuild.BreakingFieldNoDeleteUnlessNumberReservedRuleSpecBuilder.Build(false, []string{"WIRE_JSON", "WIRE"}),
 			bufcheckserverbuild.BreakingFieldWireCompatibleCardinalityRuleSpecBuilder.Build(false, []string{"WIRE"}),
 			bufcheckserverbuild.BreakingFieldWireCompatibleTypeRuleSpecBuilder.Build(false, []string{"WIRE"}),
+			bufcheckserverbuild.BreakingProtovalidateFieldNoAddCELRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_CONSTRAINTS"}),
+			bufcheckserverbuild.BreakingProtovalidateFieldNoAddRequiredRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_CONSTRAINTS"}),
+			bufcheckserverbuild.BreakingProtovalidateFieldNoNarrowRangeRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_CONSTRAINTS"}),
+			bufcheckserverbuild.BreakingProtovalidateFieldNoRemoveEnumValueRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_CONSTRAINTS"}),
+			bufcheckserverbuild.BreakingProtovalidateMessageNoAddCELRuleSpecBuilder.Build(false, []string{"PROTOVALIDATE_CONSTRAINTS"}),
 			bufcheckserverbuild.BreakingMessageSameMessageSetWireFormatRuleSpecBuilder.Build(false, []string{}),
 			bufcheckserverbuild.LintCommentEnumRuleSpecBuilder.Build(false, []string{"COMMENTS"}),
 			bufcheckserverbuild.LintCommentEnumValueRuleSpecBuilder.Build(false, []string{"COMMENTS"}),
@@ -363,13 +375,24 @@ var (
 			bufcheckserverbuild.LintEnumValuePrefixRuleSpecBuilder.Build(true, []string{"DEFAULT", "STANDARD"}),
 			bufcheckserverbuild.LintEnumValueUpperSnakeCaseRuleSpecBuilder.Build(true, []string{"BASIC", "DEFAULT", "STANDARD"}),
 			bufcheckserverbuild.LintEnumZeroValueSuffixRuleSpecBuilder.Build(true, []string{"DEFAULT", "STANDARD"}),
+			bufcheckserverbuild.LintExtensionRegistryNoConflictRuleSpecBuilder.Build(false, []string{}),
 			bufcheckserverbuild.LintFieldLowerSnakeCaseRuleSpecBuilder.Build(true, []string{"BASIC", "DEFAULT", "STANDARD"}),
 			bufcheckserverbuild.LintFieldNotRequiredRuleSpecBuilder.Build(true, []string{"BASIC", "DEFAULT", "STANDARD"}),
 			bufcheckserverbuild.LintFileLowerSnakeCaseRuleSpecBuilder.Build(true, []string{"DEFAULT", "STANDARD"}),
+			bufcheckserverbuild.LintGeneratedNamesCsharpRuleSpecBuilder.Build(false, []string{"GENERATED_NAMES"}),
+			bufcheckserverbuild.LintGeneratedNamesGoRuleSpecBuilder.Build(false, []string{"GENERATED_NAMES"}),
+			bufcheckserverbuild.LintGeneratedNamesJavaRuleSpecBuilder.Build(false, []string{"GENERATED_NAMES"}),
+			bufcheckserverbuild.LintGeneratedNamesPythonRuleSpecBuilder.Build(false, []string{"GENERATED_NAMES"}),
 			bufcheckserverbuild.LintImportNoPublicRuleSpecBuilder.Build(true, []string{"BASIC", "DEFAULT", "STANDARD"}),
 			bufcheckserverbuild.LintImportNoWeakRuleSpecBuilder.Build(true, []string{"BASIC", "DEFAULT", "STANDARD"}),
 			bufcheckserverbuild.LintImportUsedRuleSpecBuilder.Build(true, []string{"BASIC", "DEFAULT", "STANDARD"}),
 			bufcheckserverbuild.LintMessagePascalCaseRuleSpecBuilder.Build(true, []string{"BASIC", "DEFAULT", "STANDARD"}),
+			bufcheckserverbuild.LintNamingEnumRuleSpecBuilder.Build(false, []string{"NAMING"}),
+			bufcheckserverbuild.LintNamingEnumValueRuleSpecBuilder.Build(false, []string{"NAMING"}),
+			bufcheckserverbuild.LintNamingFieldRuleSpecBuilder.Build(false, []string{"NAMING"}),
+			bufcheckserverbuild.LintNamingMessageRuleSpecBuilder.Build(false, []string{"NAMING"}),
+			bufcheckserverbuild.LintNamingRPCRuleSpecBuilder.Build(false, []string{"NAMING"}),
+			bufcheckserverbuild.LintNamingServiceRuleSpecBuilder.Build(false, []string{"NAMING"}),
 			bufcheckserverbuild.LintOneofLowerSnakeCaseRuleSpecBuilder.Build(true, []string{"BASIC", "DEFAULT", "STANDARD"}),
 			bufcheckserverbuild.LintPackageDefinedRuleSpecBuilder.Build(true, []string{"MINIMAL", "BASIC", "DEFAULT", "STANDARD"}),
 			bufcheckserverbuild.LintPackageDirectoryMatchRuleSpecBuilder.Build(true, []string{"MINIMAL", "BASIC", "DEFAULT", "STANDARD"}),
@@ -385,6 +408,7 @@ var (
 			bufcheckserverbuild.LintPackageSameSwiftPrefixRuleSpecBuilder.Build(true, []string{"BASIC", "DEFAULT", "STANDARD"}),
 			bufcheckserverbuild.LintPackageVersionSuffixRuleSpecBuilder.Build(true, []string{"DEFAULT", "STANDARD"}),
 			bufcheckserverbuild.LintProtovalidateRuleSpecBuilder.Build(true, []string{"DEFAULT", "STANDARD"}),
+			bufcheckserverbuild.LintReservedRegistryNoReuseRuleSpecBuilder.Build(false, []string{}),
 			bufcheckserverbuild.LintRPCNoClientStreamingRuleSpecBuilder.Build(false, []string{"UNARY_RPC"}),
 			bufcheckserverbuild.LintRPCNoServerStreamingRuleSpecBuilder.Build(false, []string{"UNARY_RPC"}),
 			bufcheckserverbuild.LintRPCPascalCaseRuleSpecBuilder.Build(true, []string{"BASIC", "DEFAULT", "STANDARD"}),
@@ -401,13 +425,31 @@ var (
 			bufcheckserverbuild.PackageCategorySpec,
 			bufcheckserverbuild.WireCategorySpec,
 			bufcheckserverbuild.WireJSONCategorySpec,
+			bufcheckserverbuild.JSONCategorySpec,
+			bufcheckserverbuild.ProtovalidateCategorySpec,
 			bufcheckserverbuild.BasicCategorySpec,
 			bufcheckserverbuild.CommentsCategorySpec,
 			bufcheckserverbuild.DefaultCategorySpec,
+			bufcheckserverbuild.GeneratedNamesCategorySpec,
 			bufcheckserverbuild.MinimalCategorySpec,
+			bufcheckserverbuild.NamingCategorySpec,
 			bufcheckserverbuild.StandardCategorySpec,
 			bufcheckserverbuild.UnaryRPCCategorySpec,
 		},
 		Before: bufcheckserverutil.Before,
 	}
 )
+
+// RuleExplanation is structured documentation for a builtin rule, beyond its purpose.
+type RuleExplanation = bufcheckserverutil.RuleExplanation
+
+// RuleExample is an example of a builtin rule failure.
+type RuleExample = bufcheckserverutil.RuleExample
+
+// GetRuleExplanation returns the RuleExplanation for the builtin rule with the given type and ID.
+//
+// Returns false if the rule does not exist or has no RuleExplanation. RuleExplanations
+// are shared across all versions of the specs.
+func GetRuleExplanation(ruleType check.RuleType, ruleID string) (*RuleExplanation, bool) {
+	return bufcheckserverbuild.GetRuleExplanation(ruleType, ruleID)
+}
@@ -0,0 +1,129 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufbreakingvalidate compares protovalidate constraints between two versions of a
+// schema to find constraints that became more restrictive.
+//
+// Each function returns descriptions of the changes that narrow the values that are valid,
+// suitable to be appended to a description of the field or message.
+package bufbreakingvalidate
+
+import (
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"github.com/bufbuild/protovalidate-go/resolver"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GetFieldConstraints returns the FieldConstraints that apply to the field.
+//
+// Returns nil if no constraints apply to the field, because the field is ignored or
+// the containing message has (buf.validate.message).disabled set.
+func GetFieldConstraints(fieldDescriptor protoreflect.FieldDescriptor) *validate.FieldConstraints {
+	if !fieldDescriptor.IsExtension() {
+		if (resolver.DefaultResolver{}).ResolveMessageConstraints(fieldDescriptor.ContainingMessage()).GetDisabled() {
+			return nil
+		}
+	}
+	fieldConstraints := resolver.DefaultResolver{}.ResolveFieldConstraints(fieldDescriptor)
+	if isIgnored(fieldConstraints) {
+		return nil
+	}
+	return fieldConstraints
+}
+
+// GetMessageConstraints returns the MessageConstraints that apply to the message.
+//
+// Returns nil if the message has (buf.validate.message).disabled set.
+func GetMessageConstraints(messageDescriptor protoreflect.MessageDescriptor) *validate.MessageConstraints {
+	messageConstraints := resolver.DefaultResolver{}.ResolveMessageConstraints(messageDescriptor)
+	if messageConstraints.GetDisabled() {
+		return nil
+	}
+	return messageConstraints
+}
+
+// FieldAddedRequired returns true if the current constraints require the field
+// and the previous constraints did not.
+func FieldAddedRequired(previous *validate.FieldConstraints, current *validate.FieldConstraints) bool {
+	return !previous.GetRequired() && current.GetRequired()
+}
+
+// FieldNarrowedRanges returns descriptions of the changes that narrow the range of values
+// that are valid for a field, such as raised lower bounds, lowered upper bounds, added
+// or changed const values, values removed from in, values added to not_in, and stricter
+// length, size, and item count limits.
+//
+// Constraints on enums are not included, see FieldRemovedEnumValues.
+func FieldNarrowedRanges(previous *validate.FieldConstraints, current *validate.FieldConstraints) []string {
+	var descriptions []string
+	forEachFieldConstraintsPair(
+		fieldConstraintsPath,
+		previous,
+		current,
+		func(path string, previous *validate.FieldConstraints, current *validate.FieldConstraints) {
+			typeName, previousRules, currentRules, ok := getTypeRulesPair(previous, current)
+			if !ok || typeName == enumTypeName {
+				return
+			}
+			path = path + "." + typeName
+			descriptions = append(descriptions, narrowedBounds(path, previousRules, currentRules)...)
+			descriptions = append(descriptions, narrowedValues(path, previousRules, currentRules)...)
+			descriptions = append(descriptions, narrowedLimits(path, previousRules, currentRules)...)
+		},
+	)
+	return descriptions
+}
+
+// FieldRemovedEnumValues returns descriptions of the changes that remove enum values
+// that were valid for a field, such as values removed from in, values added to
+// not_in, added or changed const values, and adding defined_only.
+func FieldRemovedEnumValues(previous *validate.FieldConstraints, current *validate.FieldConstraints) []string {
+	var descriptions []string
+	forEachFieldConstraintsPair(
+		fieldConstraintsPath,
+		previous,
+		current,
+		func(path string, previous *validate.FieldConstraints, current *validate.FieldConstraints) {
+			typeName, previousRules, currentRules, ok := getTypeRulesPair(previous, current)
+			if !ok || typeName != enumTypeName {
+				return
+			}
+			path = path + "." + typeName
+			descriptions = append(descriptions, narrowedValues(path, previousRules, currentRules)...)
+			if description := addedFlag(path, previousRules, currentRules, currentRules.Descriptor().Fields().ByName("defined_only")); description != "" {
+				descriptions = append(descriptions, description)
+			}
+		},
+	)
+	return descriptions
+}
+
+// FieldAddedCEL returns descriptions of the CEL constraints that were added to a field.
+func FieldAddedCEL(previous *validate.FieldConstraints, current *validate.FieldConstraints) []string {
+	var descriptions []string
+	forEachFieldConstraintsPair(
+		fieldConstraintsPath,
+		previous,
+		current,
+		func(path string, previous *validate.FieldConstraints, current *validate.FieldConstraints) {
+			descriptions = append(descriptions, addedCEL(path+".cel", previous.GetCel(), current.GetCel())...)
+		},
+	)
+	return descriptions
+}
+
+// MessageAddedCEL returns descriptions of the CEL constraints that were added to a message.
+func MessageAddedCEL(previous *validate.MessageConstraints, current *validate.MessageConstraints) []string {
+	return addedCEL(messageConstraintsPath+".cel", previous.GetCel(), current.GetCel())
+}
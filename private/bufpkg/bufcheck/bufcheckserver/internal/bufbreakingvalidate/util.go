@@ -0,0 +1,420 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufbreakingvalidate
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	fieldConstraintsPath   = "(buf.validate.field)"
+	messageConstraintsPath = "(buf.validate.message)"
+	enumTypeName           = "enum"
+)
+
+var (
+	typeOneofDescriptor = validate.File_buf_validate_validate_proto.Messages().ByName("FieldConstraints").Oneofs().ByName("type")
+
+	// minLimitRuleNames are the rules that set a minimum length, size, or count.
+	minLimitRuleNames = map[protoreflect.Name]struct{}{
+		"min_len":   {},
+		"min_bytes": {},
+		"min_items": {},
+		"min_pairs": {},
+	}
+	// maxLimitRuleNames are the rules that set a maximum length, size, count, or duration.
+	maxLimitRuleNames = map[protoreflect.Name]struct{}{
+		"max_len":   {},
+		"max_bytes": {},
+		"max_items": {},
+		"max_pairs": {},
+		"within":    {},
+	}
+	// exactLimitRuleNames are the rules that set an exact length or size.
+	exactLimitRuleNames = map[protoreflect.Name]struct{}{
+		"len":       {},
+		"len_bytes": {},
+	}
+	// flagRuleNames are the boolean rules that restrict values when set to true.
+	flagRuleNames = map[protoreflect.Name]struct{}{
+		"gt_now": {},
+		"lt_now": {},
+		"unique": {},
+	}
+)
+
+// forEachFieldConstraintsPair calls f for the FieldConstraints, and for the FieldConstraints
+// of repeated items and map keys and values, with the path to the FieldConstraints.
+//
+// Ignored FieldConstraints are treated as if no constraints are set.
+func forEachFieldConstraintsPair(
+	path string,
+	previous *validate.FieldConstraints,
+	current *validate.FieldConstraints,
+	f func(string, *validate.FieldConstraints, *validate.FieldConstraints),
+) {
+	if current == nil || isIgnored(current) {
+		return
+	}
+	if isIgnored(previous) {
+		previous = nil
+	}
+	f(path, previous, current)
+	forEachFieldConstraintsPair(
+		path+".repeated.items",
+		previous.GetRepeated().GetItems(),
+		current.GetRepeated().GetItems(),
+		f,
+	)
+	forEachFieldConstraintsPair(
+		path+".map.keys",
+		previous.GetMap().GetKeys(),
+		current.GetMap().GetKeys(),
+		f,
+	)
+	forEachFieldConstraintsPair(
+		path+".map.values",
+		previous.GetMap().GetValues(),
+		current.GetMap().GetValues(),
+		f,
+	)
+}
+
+// getTypeRulesPair returns the name of the type rules set on the current FieldConstraints,
+// such as "int32" or "string", and the previous and current rules messages.
+//
+// If the previous FieldConstraints has no type rules, an empty rules message is returned
+// for the previous rules. Returns false if the current FieldConstraints has no type rules,
+// or the type rules differ, in which case the type of the field changed and the constraints
+// are not comparable.
+func getTypeRulesPair(
+	previous *validate.FieldConstraints,
+	current *validate.FieldConstraints,
+) (string, protoreflect.Message, protoreflect.Message, bool) {
+	currentFieldDescriptor, currentRules := getTypeRules(current)
+	if currentFieldDescriptor == nil {
+		return "", nil, nil, false
+	}
+	previousFieldDescriptor, previousRules := getTypeRules(previous)
+	if previousFieldDescriptor == nil {
+		previousRules = currentRules.Type().New()
+	} else if previousFieldDescriptor.Name() != currentFieldDescriptor.Name() {
+		return "", nil, nil, false
+	}
+	return string(currentFieldDescriptor.Name()), previousRules, currentRules, true
+}
+
+func getTypeRules(fieldConstraints *validate.FieldConstraints) (protoreflect.FieldDescriptor, protoreflect.Message) {
+	if fieldConstraints == nil {
+		return nil, nil
+	}
+	message := fieldConstraints.ProtoReflect()
+	fieldDescriptor := message.WhichOneof(typeOneofDescriptor)
+	if fieldDescriptor == nil {
+		return nil, nil
+	}
+	return fieldDescriptor, message.Get(fieldDescriptor).Message()
+}
+
+// narrowedBounds returns descriptions of raised lower bounds (gt and gte) and lowered
+// upper bounds (lt and lte).
+//
+// If the lower bound is greater than the upper bound in either the previous or current
+// rules, the rules define an exclusive range, which is not compared.
+func narrowedBounds(path string, previousRules protoreflect.Message, currentRules protoreflect.Message) []string {
+	previousLowerBound := getBound(previousRules, "gt", "gte")
+	previousUpperBound := getBound(previousRules, "lt", "lte")
+	currentLowerBound := getBound(currentRules, "gt", "gte")
+	currentUpperBound := getBound(currentRules, "lt", "lte")
+	if isExclusiveRange(previousLowerBound, previousUpperBound) || isExclusiveRange(currentLowerBound, currentUpperBound) {
+		return nil
+	}
+	var descriptions []string
+	if description := narrowedBound(path, "lower", previousLowerBound, currentLowerBound, 1); description != "" {
+		descriptions = append(descriptions, description)
+	}
+	if description := narrowedBound(path, "upper", previousUpperBound, currentUpperBound, -1); description != "" {
+		descriptions = append(descriptions, description)
+	}
+	return descriptions
+}
+
+// narrowedBound returns a description of the change to the bound if the current bound is
+// narrower than the previous bound, or empty otherwise.
+//
+// The direction is 1 for lower bounds and -1 for upper bounds.
+func narrowedBound(path string, boundName string, previous *bound, current *bound, direction int) string {
+	if current == nil {
+		return ""
+	}
+	if previous == nil {
+		return fmt.Sprintf("added the %s bound %s to %s", boundName, current, path)
+	}
+	compare := compareValues(current.fieldDescriptor, current.value, previous.value) * direction
+	if compare > 0 || (compare == 0 && current.exclusive && !previous.exclusive) {
+		return fmt.Sprintf("narrowed the %s bound of %s from %s to %s", boundName, path, previous, current)
+	}
+	return ""
+}
+
+// narrowedValues returns descriptions of added or changed const values, values removed
+// from in, and values added to not_in.
+func narrowedValues(path string, previousRules protoreflect.Message, currentRules protoreflect.Message) []string {
+	var descriptions []string
+	fields := currentRules.Descriptor().Fields()
+	if fieldDescriptor := fields.ByName("const"); fieldDescriptor != nil && currentRules.Has(fieldDescriptor) {
+		currentValue := valueString(fieldDescriptor, currentRules.Get(fieldDescriptor))
+		if !previousRules.Has(fieldDescriptor) {
+			descriptions = append(descriptions, fmt.Sprintf("added %s.const with value %s", path, currentValue))
+		} else if previousValue := valueString(fieldDescriptor, previousRules.Get(fieldDescriptor)); previousValue != currentValue {
+			descriptions = append(descriptions, fmt.Sprintf("changed %s.const from %s to %s", path, previousValue, currentValue))
+		}
+	}
+	if fieldDescriptor := fields.ByName("in"); fieldDescriptor != nil {
+		currentValues := listValueStrings(fieldDescriptor, currentRules.Get(fieldDescriptor).List())
+		previousValues := listValueStrings(fieldDescriptor, previousRules.Get(fieldDescriptor).List())
+		if len(currentValues) > 0 {
+			if len(previousValues) == 0 {
+				descriptions = append(descriptions, fmt.Sprintf("added %s.in with values [%s]", path, strings.Join(currentValues, ", ")))
+			} else {
+				for _, previousValue := range previousValues {
+					if !slices.Contains(currentValues, previousValue) {
+						descriptions = append(descriptions, fmt.Sprintf("removed value %s from %s.in", previousValue, path))
+					}
+				}
+			}
+		}
+	}
+	if fieldDescriptor := fields.ByName("not_in"); fieldDescriptor != nil {
+		currentValues := listValueStrings(fieldDescriptor, currentRules.Get(fieldDescriptor).List())
+		previousValues := listValueStrings(fieldDescriptor, previousRules.Get(fieldDescriptor).List())
+		for _, currentValue := range currentValues {
+			if !slices.Contains(previousValues, currentValue) {
+				descriptions = append(descriptions, fmt.Sprintf("added value %s to %s.not_in", currentValue, path))
+			}
+		}
+	}
+	return descriptions
+}
+
+// narrowedLimits returns descriptions of stricter length, size, count, and duration limits,
+// and added boolean rules such as unique.
+func narrowedLimits(path string, previousRules protoreflect.Message, currentRules protoreflect.Message) []string {
+	var descriptions []string
+	fields := currentRules.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fieldDescriptor := fields.Get(i)
+		if !currentRules.Has(fieldDescriptor) {
+			continue
+		}
+		currentValue := currentRules.Get(fieldDescriptor)
+		previousValue := previousRules.Get(fieldDescriptor)
+		var narrowed bool
+		if _, ok := minLimitRuleNames[fieldDescriptor.Name()]; ok {
+			narrowed = compareValues(fieldDescriptor, currentValue, previousValue) > 0
+		} else if _, ok := maxLimitRuleNames[fieldDescriptor.Name()]; ok {
+			narrowed = !previousRules.Has(fieldDescriptor) || compareValues(fieldDescriptor, currentValue, previousValue) < 0
+		} else if _, ok := exactLimitRuleNames[fieldDescriptor.Name()]; ok {
+			narrowed = !previousRules.Has(fieldDescriptor) || compareValues(fieldDescriptor, currentValue, previousValue) != 0
+		} else if _, ok := flagRuleNames[fieldDescriptor.Name()]; ok {
+			if description := addedFlag(path, previousRules, currentRules, fieldDescriptor); description != "" {
+				descriptions = append(descriptions, description)
+			}
+			continue
+		} else {
+			continue
+		}
+		if !narrowed {
+			continue
+		}
+		if !previousRules.Has(fieldDescriptor) {
+			descriptions = append(
+				descriptions,
+				fmt.Sprintf(
+					"added %s.%s with value %s",
+					path,
+					fieldDescriptor.Name(),
+					valueString(fieldDescriptor, currentValue),
+				),
+			)
+		} else {
+			descriptions = append(
+				descriptions,
+				fmt.Sprintf(
+					"changed %s.%s from %s to %s",
+					path,
+					fieldDescriptor.Name(),
+					valueString(fieldDescriptor, previousValue),
+					valueString(fieldDescriptor, currentValue),
+				),
+			)
+		}
+	}
+	return descriptions
+}
+
+// addedFlag returns a description if the boolean rule is true in the current rules and
+// false in the previous rules, or empty otherwise.
+func addedFlag(
+	path string,
+	previousRules protoreflect.Message,
+	currentRules protoreflect.Message,
+	fieldDescriptor protoreflect.FieldDescriptor,
+) string {
+	if fieldDescriptor == nil || !currentRules.Get(fieldDescriptor).Bool() || previousRules.Get(fieldDescriptor).Bool() {
+		return ""
+	}
+	return fmt.Sprintf("added %s.%s", path, fieldDescriptor.Name())
+}
+
+// addedCEL returns descriptions of the CEL constraints in current that are not in previous.
+//
+// Constraints are matched by ID, or by expression if they have no ID.
+func addedCEL(path string, previous []*validate.Constraint, current []*validate.Constraint) []string {
+	previousKeys := make(map[string]struct{}, len(previous))
+	for _, constraint := range previous {
+		previousKeys[celKey(constraint)] = struct{}{}
+	}
+	var descriptions []string
+	for _, constraint := range current {
+		if _, ok := previousKeys[celKey(constraint)]; ok {
+			continue
+		}
+		if id := constraint.GetId(); id != "" {
+			descriptions = append(descriptions, fmt.Sprintf("added CEL constraint %q to %s", id, path))
+		} else {
+			descriptions = append(descriptions, fmt.Sprintf("added CEL constraint with expression %q to %s", constraint.GetExpression(), path))
+		}
+	}
+	return descriptions
+}
+
+func celKey(constraint *validate.Constraint) string {
+	if id := constraint.GetId(); id != "" {
+		return "id:" + id
+	}
+	return "expression:" + constraint.GetExpression()
+}
+
+func isIgnored(fieldConstraints *validate.FieldConstraints) bool {
+	return fieldConstraints.GetIgnore() == validate.Ignore_IGNORE_ALWAYS || fieldConstraints.GetSkipped()
+}
+
+type bound struct {
+	fieldDescriptor protoreflect.FieldDescriptor
+	value           protoreflect.Value
+	exclusive       bool
+}
+
+// getBound returns the bound set by either the exclusive or inclusive rule, or nil if
+// neither is set.
+func getBound(rules protoreflect.Message, exclusiveName protoreflect.Name, inclusiveName protoreflect.Name) *bound {
+	fields := rules.Descriptor().Fields()
+	for _, name := range []protoreflect.Name{exclusiveName, inclusiveName} {
+		if fieldDescriptor := fields.ByName(name); fieldDescriptor != nil && rules.Has(fieldDescriptor) {
+			return &bound{
+				fieldDescriptor: fieldDescriptor,
+				value:           rules.Get(fieldDescriptor),
+				exclusive:       name == exclusiveName,
+			}
+		}
+	}
+	return nil
+}
+
+func (b *bound) String() string {
+	return string(b.fieldDescriptor.Name()) + " " + valueString(b.fieldDescriptor, b.value)
+}
+
+func isExclusiveRange(lowerBound *bound, upperBound *bound) bool {
+	return lowerBound != nil && upperBound != nil &&
+		compareValues(lowerBound.fieldDescriptor, lowerBound.value, upperBound.value) > 0
+}
+
+// compareValues compares two values of the given rule field, which is a number or a
+// google.protobuf.Duration or google.protobuf.Timestamp.
+func compareValues(fieldDescriptor protoreflect.FieldDescriptor, a protoreflect.Value, b protoreflect.Value) int {
+	switch fieldDescriptor.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return cmp.Compare(a.Int(), b.Int())
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return cmp.Compare(a.Float(), b.Float())
+	case protoreflect.MessageKind:
+		aSeconds, aNanos := getSecondsAndNanos(a.Message())
+		bSeconds, bNanos := getSecondsAndNanos(b.Message())
+		if compare := cmp.Compare(aSeconds, bSeconds); compare != 0 {
+			return compare
+		}
+		return cmp.Compare(aNanos, bNanos)
+	default:
+		return 0
+	}
+}
+
+// getSecondsAndNanos returns the seconds and nanos of a google.protobuf.Duration or
+// google.protobuf.Timestamp.
+func getSecondsAndNanos(message protoreflect.Message) (int64, int64) {
+	fields := message.Descriptor().Fields()
+	var seconds, nanos int64
+	if fieldDescriptor := fields.ByName("seconds"); fieldDescriptor != nil {
+		seconds = message.Get(fieldDescriptor).Int()
+	}
+	if fieldDescriptor := fields.ByName("nanos"); fieldDescriptor != nil {
+		nanos = message.Get(fieldDescriptor).Int()
+	}
+	return seconds, nanos
+}
+
+func listValueStrings(fieldDescriptor protoreflect.FieldDescriptor, list protoreflect.List) []string {
+	valueStrings := make([]string, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		valueStrings[i] = valueString(fieldDescriptor, list.Get(i))
+	}
+	return valueStrings
+}
+
+func valueString(fieldDescriptor protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch fieldDescriptor.Kind() {
+	case protoreflect.StringKind:
+		return strconv.Quote(value.String())
+	case protoreflect.BytesKind:
+		return strconv.Quote(string(value.Bytes()))
+	case protoreflect.MessageKind:
+		switch message := value.Message().Interface().(type) {
+		case *durationpb.Duration:
+			return message.AsDuration().String()
+		case *timestamppb.Timestamp:
+			return message.AsTime().Format(time.RFC3339Nano)
+		}
+		seconds, nanos := getSecondsAndNanos(value.Message())
+		return fmt.Sprintf("%d.%09ds", seconds, nanos)
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
@@ -25,101 +25,115 @@ import (
 var (
 	// BreakingEnumNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingEnumNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_NO_DELETE",
-		Purpose: "Checks that enums are not deleted from a given file.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingEnumNoDelete,
+		ID:          "ENUM_NO_DELETE",
+		Purpose:     "Checks that enums are not deleted from a given file.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingEnumNoDelete,
+		Explanation: breakingEnumNoDeleteRuleExplanation,
 	}
 	// BreakingEnumSameJSONFormatRuleSpecBuilder is a rule spec builder.
 	BreakingEnumSameJSONFormatRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_SAME_JSON_FORMAT",
-		Purpose: "Checks that enums have the same JSON format support.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingEnumSameJSONFormat,
+		ID:          "ENUM_SAME_JSON_FORMAT",
+		Purpose:     "Checks that enums have the same JSON format support.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingEnumSameJSONFormat,
+		Explanation: breakingEnumSameJSONFormatRuleExplanation,
 	}
 	// BreakingEnumSameTypeRuleSpecBuilder is a rule spec builder.
 	BreakingEnumSameTypeRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_SAME_TYPE",
-		Purpose: "Checks that enums have the same type (open vs closed).",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingEnumSameType,
+		ID:          "ENUM_SAME_TYPE",
+		Purpose:     "Checks that enums have the same type (open vs closed).",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingEnumSameType,
+		Explanation: breakingEnumSameTypeRuleExplanation,
 	}
 	// BreakingEnumValueNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingEnumValueNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_VALUE_NO_DELETE",
-		Purpose: "Checks that enum values are not deleted from a given enum.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingEnumValueNoDelete,
+		ID:          "ENUM_VALUE_NO_DELETE",
+		Purpose:     "Checks that enum values are not deleted from a given enum.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingEnumValueNoDelete,
+		Explanation: breakingEnumValueNoDeleteRuleExplanation,
 	}
 	// BreakingEnumValueNoDeleteUnlessNameReservedRuleSpecBuilder is a rule spec builder.
 	BreakingEnumValueNoDeleteUnlessNameReservedRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_VALUE_NO_DELETE_UNLESS_NAME_RESERVED",
-		Purpose: "Checks that enum values are not deleted from a given enum unless the name is reserved.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingEnumValueNoDeleteUnlessNameReserved,
+		ID:          "ENUM_VALUE_NO_DELETE_UNLESS_NAME_RESERVED",
+		Purpose:     "Checks that enum values are not deleted from a given enum unless the name is reserved.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingEnumValueNoDeleteUnlessNameReserved,
+		Explanation: breakingEnumValueNoDeleteUnlessNameReservedRuleExplanation,
 	}
 	// BreakingEnumValueNoDeleteUnlessNumberReservedRuleSpecBuilder is a rule spec builder.
 	BreakingEnumValueNoDeleteUnlessNumberReservedRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_VALUE_NO_DELETE_UNLESS_NUMBER_RESERVED",
-		Purpose: "Checks that enum values are not deleted from a given enum unless the number is reserved.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingEnumValueNoDeleteUnlessNumberReserved,
+		ID:          "ENUM_VALUE_NO_DELETE_UNLESS_NUMBER_RESERVED",
+		Purpose:     "Checks that enum values are not deleted from a given enum unless the number is reserved.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingEnumValueNoDeleteUnlessNumberReserved,
+		Explanation: breakingEnumValueNoDeleteUnlessNumberReservedRuleExplanation,
 	}
 	// BreakingEnumValueSameNameRuleSpecBuilder is a rule spec builder.
 	BreakingEnumValueSameNameRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_VALUE_SAME_NAME",
-		Purpose: "Checks that enum values have the same name.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingEnumValueSameName,
+		ID:          "ENUM_VALUE_SAME_NAME",
+		Purpose:     "Checks that enum values have the same name.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingEnumValueSameName,
+		Explanation: breakingEnumValueSameNameRuleExplanation,
 	}
 	// BreakingExtensionMessageNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingExtensionMessageNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "EXTENSION_MESSAGE_NO_DELETE",
-		Purpose: "Checks that extension ranges are not deleted from a given message.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingExtensionMessageNoDelete,
+		ID:          "EXTENSION_MESSAGE_NO_DELETE",
+		Purpose:     "Checks that extension ranges are not deleted from a given message.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingExtensionMessageNoDelete,
+		Explanation: breakingExtensionMessageNoDeleteRuleExplanation,
 	}
 	// BreakingExtensionNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingExtensionNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "EXTENSION_NO_DELETE",
-		Purpose: "Checks that extensions are not deleted from a given file.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingExtensionNoDelete,
+		ID:          "EXTENSION_NO_DELETE",
+		Purpose:     "Checks that extensions are not deleted from a given file.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingExtensionNoDelete,
+		Explanation: breakingExtensionNoDeleteRuleExplanation,
 	}
 	// BreakingFieldNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingFieldNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_NO_DELETE",
-		Purpose: "Checks that fields are not deleted from a given message.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldNoDelete,
+		ID:          "FIELD_NO_DELETE",
+		Purpose:     "Checks that fields are not deleted from a given message.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldNoDelete,
+		Explanation: breakingFieldNoDeleteRuleExplanation,
 	}
 	// BreakingFieldNoDeleteUnlessNameReservedRuleSpecBuilder is a rule spec builder.
 	BreakingFieldNoDeleteUnlessNameReservedRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_NO_DELETE_UNLESS_NAME_RESERVED",
-		Purpose: "Checks that fields are not deleted from a given message unless the name is reserved.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldNoDeleteUnlessNameReserved,
+		ID:          "FIELD_NO_DELETE_UNLESS_NAME_RESERVED",
+		Purpose:     "Checks that fields are not deleted from a given message unless the name is reserved.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldNoDeleteUnlessNameReserved,
+		Explanation: breakingFieldNoDeleteUnlessNameReservedRuleExplanation,
 	}
 	// BreakingFieldNoDeleteUnlessNumberReservedRuleSpecBuilder is a rule spec builder.
 	BreakingFieldNoDeleteUnlessNumberReservedRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_NO_DELETE_UNLESS_NUMBER_RESERVED",
-		Purpose: "Checks that fields are not deleted from a given message unless the number is reserved.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldNoDeleteUnlessNumberReserved,
+		ID:          "FIELD_NO_DELETE_UNLESS_NUMBER_RESERVED",
+		Purpose:     "Checks that fields are not deleted from a given message unless the number is reserved.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldNoDeleteUnlessNumberReserved,
+		Explanation: breakingFieldNoDeleteUnlessNumberReservedRuleExplanation,
 	}
 	// BreakingFieldSameCardinalityRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameCardinalityRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_SAME_CARDINALITY",
-		Purpose: "Checks that fields have the same cardinalities in a given message.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldSameCardinality,
+		ID:          "FIELD_SAME_CARDINALITY",
+		Purpose:     "Checks that fields have the same cardinalities in a given message.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldSameCardinality,
+		Explanation: breakingFieldSameCardinalityRuleExplanation,
 	}
 	// BreakingFieldSameCppStringTypeRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameCppStringTypeRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_SAME_CPP_STRING_TYPE",
-		Purpose: "Checks that fields have the same C++ string type, based on ctype field option or (pb.cpp).string_type feature.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldSameCppStringType,
+		ID:          "FIELD_SAME_CPP_STRING_TYPE",
+		Purpose:     "Checks that fields have the same C++ string type, based on ctype field option or (pb.cpp).string_type feature.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldSameCppStringType,
+		Explanation: breakingFieldSameCppStringTypeRuleExplanation,
 	}
 	// BreakingFieldSameCTypeRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameCTypeRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
@@ -133,34 +147,39 @@ var (
 				return nil
 			},
 		),
+		Explanation: breakingFieldSameCTypeRuleExplanation,
 	}
 	// BreakingFieldSameJavaUTF8ValidationRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameJavaUTF8ValidationRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_SAME_JAVA_UTF8_VALIDATION",
-		Purpose: "Checks that fields have the same Java string UTF8 validation, based on java_string_check_utf8 file option or (pb.java).utf8_validation feature.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldSameJavaUTF8Validation,
+		ID:          "FIELD_SAME_JAVA_UTF8_VALIDATION",
+		Purpose:     "Checks that fields have the same Java string UTF8 validation, based on java_string_check_utf8 file option or (pb.java).utf8_validation feature.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldSameJavaUTF8Validation,
+		Explanation: breakingFieldSameJavaUTF8ValidationRuleExplanation,
 	}
 	// BreakingFieldSameDefaultRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameDefaultRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_SAME_DEFAULT",
-		Purpose: "Checks that fields have the same default value, if a default is specified.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldSameDefault,
+		ID:          "FIELD_SAME_DEFAULT",
+		Purpose:     "Checks that fields have the same default value, if a default is specified.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldSameDefault,
+		Explanation: breakingFieldSameDefaultRuleExplanation,
 	}
 	// BreakingFieldSameJSONNameRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameJSONNameRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_SAME_JSON_NAME",
-		Purpose: "Checks that fields have the same value for the json_name option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldSameJSONName,
+		ID:          "FIELD_SAME_JSON_NAME",
+		Purpose:     "Checks that fields have the same value for the json_name option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldSameJSONName,
+		Explanation: breakingFieldSameJSONNameRuleExplanation,
 	}
 	// BreakingFieldSameJSTypeRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameJSTypeRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_SAME_JSTYPE",
-		Purpose: "Checks that fields have the same value for the jstype option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldSameJSType,
+		ID:          "FIELD_SAME_JSTYPE",
+		Purpose:     "Checks that fields have the same value for the jstype option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldSameJSType,
+		Explanation: breakingFieldSameJSTypeRuleExplanation,
 	}
 	// BreakingFieldSameLabelRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameLabelRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
@@ -178,6 +197,7 @@ var (
 				return nil
 			},
 		),
+		Explanation: breakingFieldSameLabelRuleExplanation,
 	}
 	// BreakingFieldSameLabelV1Beta1RuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameLabelV1Beta1RuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
@@ -191,104 +211,119 @@ var (
 				return nil
 			},
 		),
+		Explanation: breakingFieldSameLabelRuleExplanation,
 	}
 	// BreakingFieldSameNameRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameNameRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_SAME_NAME",
-		Purpose: "Checks that fields have the same names in a given message.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldSameName,
+		ID:          "FIELD_SAME_NAME",
+		Purpose:     "Checks that fields have the same names in a given message.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldSameName,
+		Explanation: breakingFieldSameNameRuleExplanation,
 	}
 	// BreakingFieldSameOneofRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameOneofRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_SAME_ONEOF",
-		Purpose: "Checks that fields have the same oneofs in a given message.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldSameOneof,
+		ID:          "FIELD_SAME_ONEOF",
+		Purpose:     "Checks that fields have the same oneofs in a given message.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldSameOneof,
+		Explanation: breakingFieldSameOneofRuleExplanation,
 	}
 	// BreakingFieldSameUTF8ValidationRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameUTF8ValidationRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_SAME_UTF8_VALIDATION",
-		Purpose: "Checks that string fields have the same UTF8 validation mode.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldSameUTF8Validation,
+		ID:          "FIELD_SAME_UTF8_VALIDATION",
+		Purpose:     "Checks that string fields have the same UTF8 validation mode.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldSameUTF8Validation,
+		Explanation: breakingFieldSameUTF8ValidationRuleExplanation,
 	}
 	// BreakingFieldSameTypeRuleSpecBuilder is a rule spec builder.
 	BreakingFieldSameTypeRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_SAME_TYPE",
-		Purpose: "Checks that fields have the same types in a given message.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldSameType,
+		ID:          "FIELD_SAME_TYPE",
+		Purpose:     "Checks that fields have the same types in a given message.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldSameType,
+		Explanation: breakingFieldSameTypeRuleExplanation,
 	}
 	// BreakingFieldWireCompatibleCardinalityRuleSpecBuilder is a rule spec builder.
 	BreakingFieldWireCompatibleCardinalityRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_WIRE_COMPATIBLE_CARDINALITY",
-		Purpose: "Checks that fields have wire-compatible cardinalities in a given message.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldWireCompatibleCardinality,
+		ID:          "FIELD_WIRE_COMPATIBLE_CARDINALITY",
+		Purpose:     "Checks that fields have wire-compatible cardinalities in a given message.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldWireCompatibleCardinality,
+		Explanation: breakingFieldWireCompatibleCardinalityRuleExplanation,
 	}
 	// BreakingFieldWireCompatibleTypeRuleSpecBuilder  is a rule spec builder.
 	BreakingFieldWireCompatibleTypeRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_WIRE_COMPATIBLE_TYPE",
-		Purpose: "Checks that fields have wire-compatible types in a given message.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldWireCompatibleType,
+		ID:          "FIELD_WIRE_COMPATIBLE_TYPE",
+		Purpose:     "Checks that fields have wire-compatible types in a given message.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldWireCompatibleType,
+		Explanation: breakingFieldWireCompatibleTypeRuleExplanation,
 	}
 	// BreakingFieldWireJSONCompatibleCardinalityRuleSpecBuilder is a rule spec builder.
 	BreakingFieldWireJSONCompatibleCardinalityRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_WIRE_JSON_COMPATIBLE_CARDINALITY",
-		Purpose: "Checks that fields have wire and JSON compatible cardinalities in a given message.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldWireJSONCompatibleCardinality,
+		ID:          "FIELD_WIRE_JSON_COMPATIBLE_CARDINALITY",
+		Purpose:     "Checks that fields have wire and JSON compatible cardinalities in a given message.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldWireJSONCompatibleCardinality,
+		Explanation: breakingFieldWireJSONCompatibleCardinalityRuleExplanation,
 	}
 	// BreakingFieldWireJSONCompatibleTypeRuleSpecBuilder is a rule spec builder.
 	BreakingFieldWireJSONCompatibleTypeRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_WIRE_JSON_COMPATIBLE_TYPE",
-		Purpose: "Checks that fields have wire and JSON compatible types in a given message.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFieldWireJSONCompatibleType,
+		ID:          "FIELD_WIRE_JSON_COMPATIBLE_TYPE",
+		Purpose:     "Checks that fields have wire and JSON compatible types in a given message.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFieldWireJSONCompatibleType,
+		Explanation: breakingFieldWireJSONCompatibleTypeRuleExplanation,
 	}
 	// BreakingFileNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingFileNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_NO_DELETE",
-		Purpose: "Checks that files are not deleted.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileNoDelete,
+		ID:          "FILE_NO_DELETE",
+		Purpose:     "Checks that files are not deleted.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileNoDelete,
+		Explanation: breakingFileNoDeleteRuleExplanation,
 	}
 	// BreakingFileSameCsharpNamespaceRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameCsharpNamespaceRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_CSHARP_NAMESPACE",
-		Purpose: "Checks that files have the same value for the csharp_namespace option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameCsharpNamespace,
+		ID:          "FILE_SAME_CSHARP_NAMESPACE",
+		Purpose:     "Checks that files have the same value for the csharp_namespace option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameCsharpNamespace,
+		Explanation: breakingFileSameCsharpNamespaceRuleExplanation,
 	}
 	// BreakingFileSameGoPackageRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameGoPackageRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_GO_PACKAGE",
-		Purpose: "Checks that files have the same value for the go_package option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameGoPackage,
+		ID:          "FILE_SAME_GO_PACKAGE",
+		Purpose:     "Checks that files have the same value for the go_package option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameGoPackage,
+		Explanation: breakingFileSameGoPackageRuleExplanation,
 	}
 	// BreakingFileSameJavaMultipleFilesRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameJavaMultipleFilesRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_JAVA_MULTIPLE_FILES",
-		Purpose: "Checks that files have the same value for the java_multiple_files option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameJavaMultipleFiles,
+		ID:          "FILE_SAME_JAVA_MULTIPLE_FILES",
+		Purpose:     "Checks that files have the same value for the java_multiple_files option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameJavaMultipleFiles,
+		Explanation: breakingFileSameJavaMultipleFilesRuleExplanation,
 	}
 	// BreakingFileSameJavaOuterClassnameRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameJavaOuterClassnameRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_JAVA_OUTER_CLASSNAME",
-		Purpose: "Checks that files have the same value for the java_outer_classname option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameJavaOuterClassname,
+		ID:          "FILE_SAME_JAVA_OUTER_CLASSNAME",
+		Purpose:     "Checks that files have the same value for the java_outer_classname option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameJavaOuterClassname,
+		Explanation: breakingFileSameJavaOuterClassnameRuleExplanation,
 	}
 	// BreakingFileSameJavaPackageRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameJavaPackageRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_JAVA_PACKAGE",
-		Purpose: "Checks that files have the same value for the java_package option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameJavaPackage,
+		ID:          "FILE_SAME_JAVA_PACKAGE",
+		Purpose:     "Checks that files have the same value for the java_package option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameJavaPackage,
+		Explanation: breakingFileSameJavaPackageRuleExplanation,
 	}
 	// BreakingFileSameJavaStringCheckUtf8RuleSpecBuilder is a rule spec builder.
 	BreakingFileSameJavaStringCheckUtf8RuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
@@ -302,83 +337,95 @@ var (
 				return nil
 			},
 		),
+		Explanation: breakingFileSameJavaStringCheckUtf8RuleExplanation,
 	}
 	// BreakingFileSameObjcClassPrefixRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameObjcClassPrefixRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_OBJC_CLASS_PREFIX",
-		Purpose: "Checks that files have the same value for the objc_class_prefix option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameObjcClassPrefix,
+		ID:          "FILE_SAME_OBJC_CLASS_PREFIX",
+		Purpose:     "Checks that files have the same value for the objc_class_prefix option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameObjcClassPrefix,
+		Explanation: breakingFileSameObjcClassPrefixRuleExplanation,
 	}
 	// BreakingFileSamePackageRuleSpecBuilder is a rule spec builder.
 	BreakingFileSamePackageRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_PACKAGE",
-		Purpose: "Checks that files have the same package.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSamePackage,
+		ID:          "FILE_SAME_PACKAGE",
+		Purpose:     "Checks that files have the same package.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSamePackage,
+		Explanation: breakingFileSamePackageRuleExplanation,
 	}
 	// BreakingFileSamePhpClassPrefixRuleSpecBuilder is a rule spec builder.
 	BreakingFileSamePhpClassPrefixRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_PHP_CLASS_PREFIX",
-		Purpose: "Checks that files have the same value for the php_class_prefix option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSamePhpClassPrefix,
+		ID:          "FILE_SAME_PHP_CLASS_PREFIX",
+		Purpose:     "Checks that files have the same value for the php_class_prefix option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSamePhpClassPrefix,
+		Explanation: breakingFileSamePhpClassPrefixRuleExplanation,
 	}
 	// BreakingFileSamePhpMetadataNamespaceRuleSpecBuilder is a rule spec builder.
 	BreakingFileSamePhpMetadataNamespaceRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_PHP_METADATA_NAMESPACE",
-		Purpose: "Checks that files have the same value for the php_metadata_namespace option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSamePhpMetadataNamespace,
+		ID:          "FILE_SAME_PHP_METADATA_NAMESPACE",
+		Purpose:     "Checks that files have the same value for the php_metadata_namespace option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSamePhpMetadataNamespace,
+		Explanation: breakingFileSamePhpMetadataNamespaceRuleExplanation,
 	}
 	// BreakingFileSamePhpNamespaceRuleSpecBuilder is a rule spec builder.
 	BreakingFileSamePhpNamespaceRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_PHP_NAMESPACE",
-		Purpose: "Checks that files have the same value for the php_namespace option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSamePhpNamespace,
+		ID:          "FILE_SAME_PHP_NAMESPACE",
+		Purpose:     "Checks that files have the same value for the php_namespace option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSamePhpNamespace,
+		Explanation: breakingFileSamePhpNamespaceRuleExplanation,
 	}
 	// BreakingFileSameRubyPackageRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameRubyPackageRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_RUBY_PACKAGE",
-		Purpose: "Checks that files have the same value for the ruby_package option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameRubyPackage,
+		ID:          "FILE_SAME_RUBY_PACKAGE",
+		Purpose:     "Checks that files have the same value for the ruby_package option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameRubyPackage,
+		Explanation: breakingFileSameRubyPackageRuleExplanation,
 	}
 	// BreakingFileSameSwiftPrefixRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameSwiftPrefixRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_SWIFT_PREFIX",
-		Purpose: "Checks that files have the same value for the swift_prefix option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameSwiftPrefix,
+		ID:          "FILE_SAME_SWIFT_PREFIX",
+		Purpose:     "Checks that files have the same value for the swift_prefix option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameSwiftPrefix,
+		Explanation: breakingFileSameSwiftPrefixRuleExplanation,
 	}
 	// BreakingFileSameOptimizeForRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameOptimizeForRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_OPTIMIZE_FOR",
-		Purpose: "Checks that files have the same value for the optimize_for option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameOptimizeFor,
+		ID:          "FILE_SAME_OPTIMIZE_FOR",
+		Purpose:     "Checks that files have the same value for the optimize_for option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameOptimizeFor,
+		Explanation: breakingFileSameOptimizeForRuleExplanation,
 	}
 	// BreakingFileSameCcGenericServicesRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameCcGenericServicesRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_CC_GENERIC_SERVICES",
-		Purpose: "Checks that files have the same value for the cc_generic_services option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameCcGenericServices,
+		ID:          "FILE_SAME_CC_GENERIC_SERVICES",
+		Purpose:     "Checks that files have the same value for the cc_generic_services option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameCcGenericServices,
+		Explanation: breakingFileSameCcGenericServicesRuleExplanation,
 	}
 	// BreakingFileSameJavaGenericServicesRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameJavaGenericServicesRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_JAVA_GENERIC_SERVICES",
-		Purpose: "Checks that files have the same value for the java_generic_services option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameJavaGenericServices,
+		ID:          "FILE_SAME_JAVA_GENERIC_SERVICES",
+		Purpose:     "Checks that files have the same value for the java_generic_services option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameJavaGenericServices,
+		Explanation: breakingFileSameJavaGenericServicesRuleExplanation,
 	}
 	// BreakingFileSamePyGenericServicesRuleSpecBuilder is a rule spec builder.
 	BreakingFileSamePyGenericServicesRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_PY_GENERIC_SERVICES",
-		Purpose: "Checks that files have the same value for the py_generic_services option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSamePyGenericServices,
+		ID:          "FILE_SAME_PY_GENERIC_SERVICES",
+		Purpose:     "Checks that files have the same value for the py_generic_services option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSamePyGenericServices,
+		Explanation: breakingFileSamePyGenericServicesRuleExplanation,
 	}
 	// BreakingFileSamePhpGenericServicesRuleSpecBuilder is a rule spec builder.
 	BreakingFileSamePhpGenericServicesRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
@@ -391,41 +438,47 @@ var (
 				return nil
 			},
 		),
+		Explanation: breakingFileSamePhpGenericServicesRuleExplanation,
 	}
 	// BreakingFileSameCcEnableArenasRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameCcEnableArenasRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_CC_ENABLE_ARENAS",
-		Purpose: "Checks that files have the same value for the cc_enable_arenas option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameCcEnableArenas,
+		ID:          "FILE_SAME_CC_ENABLE_ARENAS",
+		Purpose:     "Checks that files have the same value for the cc_enable_arenas option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameCcEnableArenas,
+		Explanation: breakingFileSameCcEnableArenasRuleExplanation,
 	}
 	// BreakingFileSameSyntaxRuleSpecBuilder is a rule spec builder.
 	BreakingFileSameSyntaxRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_SAME_SYNTAX",
-		Purpose: "Checks that files have the same syntax.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingFileSameSyntax,
+		ID:          "FILE_SAME_SYNTAX",
+		Purpose:     "Checks that files have the same syntax.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingFileSameSyntax,
+		Explanation: breakingFileSameSyntaxRuleExplanation,
 	}
 	// BreakingMessageNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingMessageNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "MESSAGE_NO_DELETE",
-		Purpose: "Checks that messages are not deleted from a given file.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingMessageNoDelete,
+		ID:          "MESSAGE_NO_DELETE",
+		Purpose:     "Checks that messages are not deleted from a given file.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingMessageNoDelete,
+		Explanation: breakingMessageNoDeleteRuleExplanation,
 	}
 	// BreakingMessageNoRemoveStandardDescriptorAccessorRuleSpecBuilder is a rule spec builder.
 	BreakingMessageNoRemoveStandardDescriptorAccessorRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "MESSAGE_NO_REMOVE_STANDARD_DESCRIPTOR_ACCESSOR",
-		Purpose: "Checks that messages do not change the no_standard_descriptor_accessor option from false or unset to true.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingMessageNoRemoveStandardDescriptorAccessor,
+		ID:          "MESSAGE_NO_REMOVE_STANDARD_DESCRIPTOR_ACCESSOR",
+		Purpose:     "Checks that messages do not change the no_standard_descriptor_accessor option from false or unset to true.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingMessageNoRemoveStandardDescriptorAccessor,
+		Explanation: breakingMessageNoRemoveStandardDescriptorAccessorRuleExplanation,
 	}
 	// BreakingMessageSameJSONFormatRuleSpecBuilder is a rule spec builder.
 	BreakingMessageSameJSONFormatRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "MESSAGE_SAME_JSON_FORMAT",
-		Purpose: "Checks that messages have the same JSON format support.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingMessageSameJSONFormat,
+		ID:          "MESSAGE_SAME_JSON_FORMAT",
+		Purpose:     "Checks that messages have the same JSON format support.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingMessageSameJSONFormat,
+		Explanation: breakingMessageSameJSONFormatRuleExplanation,
 	}
 	// BreakingMessageSameMessageSetWireFormatRuleSpecBuilder is a rule spec builder.
 	//
@@ -441,447 +494,647 @@ var (
 				return nil
 			},
 		),
+		Explanation: breakingMessageSameMessageSetWireFormatRuleExplanation,
 	}
 	// BreakingMessageSameRequiredFieldsRuleSpecBuilder is a rule spec builder.
 	BreakingMessageSameRequiredFieldsRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "MESSAGE_SAME_REQUIRED_FIELDS",
-		Purpose: "Checks that messages have no added or deleted required fields.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingMessageSameRequiredFields,
+		ID:          "MESSAGE_SAME_REQUIRED_FIELDS",
+		Purpose:     "Checks that messages have no added or deleted required fields.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingMessageSameRequiredFields,
+		Explanation: breakingMessageSameRequiredFieldsRuleExplanation,
 	}
 	// BreakingOneofNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingOneofNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ONEOF_NO_DELETE",
-		Purpose: "Checks that oneofs are not deleted from a given message.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingOneofNoDelete,
+		ID:          "ONEOF_NO_DELETE",
+		Purpose:     "Checks that oneofs are not deleted from a given message.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingOneofNoDelete,
+		Explanation: breakingOneofNoDeleteRuleExplanation,
 	}
 	// BreakingPackageEnumNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingPackageEnumNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_ENUM_NO_DELETE",
-		Purpose: "Checks that enums are not deleted from a given package.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingPackageEnumNoDelete,
+		ID:          "PACKAGE_ENUM_NO_DELETE",
+		Purpose:     "Checks that enums are not deleted from a given package.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingPackageEnumNoDelete,
+		Explanation: breakingPackageEnumNoDeleteRuleExplanation,
 	}
 	// BreakingPackageExtensionNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingPackageExtensionNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_EXTENSION_NO_DELETE",
-		Purpose: "Checks that extensions are not deleted from a given package.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingPackageExtensionNoDelete,
+		ID:          "PACKAGE_EXTENSION_NO_DELETE",
+		Purpose:     "Checks that extensions are not deleted from a given package.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingPackageExtensionNoDelete,
+		Explanation: breakingPackageExtensionNoDeleteRuleExplanation,
 	}
 	// BreakingPackageMessageNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingPackageMessageNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_MESSAGE_NO_DELETE",
-		Purpose: "Checks that messages are not deleted from a given package.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingPackageMessageNoDelete,
+		ID:          "PACKAGE_MESSAGE_NO_DELETE",
+		Purpose:     "Checks that messages are not deleted from a given package.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingPackageMessageNoDelete,
+		Explanation: breakingPackageMessageNoDeleteRuleExplanation,
 	}
 	// BreakingPackageNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingPackageNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_NO_DELETE",
-		Purpose: "Checks that packages are not deleted.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingPackageNoDelete,
+		ID:          "PACKAGE_NO_DELETE",
+		Purpose:     "Checks that packages are not deleted.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingPackageNoDelete,
+		Explanation: breakingPackageNoDeleteRuleExplanation,
 	}
 	// BreakingPackageServiceNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingPackageServiceNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_SERVICE_NO_DELETE",
-		Purpose: "Checks that services are not deleted from a given package.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingPackageServiceNoDelete,
+		ID:          "PACKAGE_SERVICE_NO_DELETE",
+		Purpose:     "Checks that services are not deleted from a given package.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingPackageServiceNoDelete,
+		Explanation: breakingPackageServiceNoDeleteRuleExplanation,
+	}
+	// BreakingProtovalidateFieldNoAddCELRuleSpecBuilder is a rule spec builder.
+	BreakingProtovalidateFieldNoAddCELRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "PROTOVALIDATE_FIELD_NO_ADD_CEL",
+		Purpose:     "Checks that fields do not have protovalidate CEL constraints added.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingProtovalidateFieldNoAddCEL,
+		Explanation: breakingProtovalidateFieldNoAddCELRuleExplanation,
+	}
+	// BreakingProtovalidateFieldNoAddRequiredRuleSpecBuilder is a rule spec builder.
+	BreakingProtovalidateFieldNoAddRequiredRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "PROTOVALIDATE_FIELD_NO_ADD_REQUIRED",
+		Purpose:     "Checks that fields do not have the protovalidate required constraint added.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingProtovalidateFieldNoAddRequired,
+		Explanation: breakingProtovalidateFieldNoAddRequiredRuleExplanation,
+	}
+	// BreakingProtovalidateFieldNoNarrowRangeRuleSpecBuilder is a rule spec builder.
+	BreakingProtovalidateFieldNoNarrowRangeRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "PROTOVALIDATE_FIELD_NO_NARROW_RANGE",
+		Purpose:     "Checks that fields do not have the range of values allowed by protovalidate constraints narrowed.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingProtovalidateFieldNoNarrowRange,
+		Explanation: breakingProtovalidateFieldNoNarrowRangeRuleExplanation,
+	}
+	// BreakingProtovalidateFieldNoRemoveEnumValueRuleSpecBuilder is a rule spec builder.
+	BreakingProtovalidateFieldNoRemoveEnumValueRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "PROTOVALIDATE_FIELD_NO_REMOVE_ENUM_VALUE",
+		Purpose:     "Checks that enum fields do not have enum values allowed by protovalidate constraints removed.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingProtovalidateFieldNoRemoveEnumValue,
+		Explanation: breakingProtovalidateFieldNoRemoveEnumValueRuleExplanation,
+	}
+	// BreakingProtovalidateMessageNoAddCELRuleSpecBuilder is a rule spec builder.
+	BreakingProtovalidateMessageNoAddCELRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "PROTOVALIDATE_MESSAGE_NO_ADD_CEL",
+		Purpose:     "Checks that messages do not have protovalidate CEL constraints added.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingProtovalidateMessageNoAddCEL,
+		Explanation: breakingProtovalidateMessageNoAddCELRuleExplanation,
 	}
 	// BreakingReservedEnumNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingReservedEnumNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RESERVED_ENUM_NO_DELETE",
-		Purpose: "Checks that reserved ranges and names are not deleted from a given enum.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingReservedEnumNoDelete,
+		ID:          "RESERVED_ENUM_NO_DELETE",
+		Purpose:     "Checks that reserved ranges and names are not deleted from a given enum.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingReservedEnumNoDelete,
+		Explanation: breakingReservedEnumNoDeleteRuleExplanation,
 	}
 	// BreakingReservedMessageNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingReservedMessageNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RESERVED_MESSAGE_NO_DELETE",
-		Purpose: "Checks that reserved ranges and names are not deleted from a given message.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingReservedMessageNoDelete,
+		ID:          "RESERVED_MESSAGE_NO_DELETE",
+		Purpose:     "Checks that reserved ranges and names are not deleted from a given message.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingReservedMessageNoDelete,
+		Explanation: breakingReservedMessageNoDeleteRuleExplanation,
 	}
 	// BreakingRPCNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingRPCNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RPC_NO_DELETE",
-		Purpose: "Checks that rpcs are not deleted from a given service.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingRPCNoDelete,
+		ID:          "RPC_NO_DELETE",
+		Purpose:     "Checks that rpcs are not deleted from a given service.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingRPCNoDelete,
+		Explanation: breakingRPCNoDeleteRuleExplanation,
 	}
 	// BreakingRPCSameClientStreamingRuleSpecBuilder is a rule spec builder.
 	BreakingRPCSameClientStreamingRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RPC_SAME_CLIENT_STREAMING",
-		Purpose: "Checks that rpcs have the same client streaming value.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingRPCSameClientStreaming,
+		ID:          "RPC_SAME_CLIENT_STREAMING",
+		Purpose:     "Checks that rpcs have the same client streaming value.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingRPCSameClientStreaming,
+		Explanation: breakingRPCSameClientStreamingRuleExplanation,
 	}
 	// BreakingRPCSameIdempotencyLevelRuleSpecBuilder is a rule spec builder.
 	BreakingRPCSameIdempotencyLevelRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RPC_SAME_IDEMPOTENCY_LEVEL",
-		Purpose: "Checks that rpcs have the same value for the idempotency_level option.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingRPCSameIdempotencyLevel,
+		ID:          "RPC_SAME_IDEMPOTENCY_LEVEL",
+		Purpose:     "Checks that rpcs have the same value for the idempotency_level option.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingRPCSameIdempotencyLevel,
+		Explanation: breakingRPCSameIdempotencyLevelRuleExplanation,
 	}
 	// BreakingRPCSameRequestTypeRuleSpecBuilder is a rule spec builder.
 	BreakingRPCSameRequestTypeRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RPC_SAME_REQUEST_TYPE",
-		Purpose: "Checks that rpcs are have the same request type.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingRPCSameRequestType,
+		ID:          "RPC_SAME_REQUEST_TYPE",
+		Purpose:     "Checks that rpcs are have the same request type.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingRPCSameRequestType,
+		Explanation: breakingRPCSameRequestTypeRuleExplanation,
 	}
 	// BreakingRPCSameResponseTypeRuleSpecBuilder is a rule spec builder.
 	BreakingRPCSameResponseTypeRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RPC_SAME_RESPONSE_TYPE",
-		Purpose: "Checks that rpcs are have the same response type.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingRPCSameResponseType,
+		ID:          "RPC_SAME_RESPONSE_TYPE",
+		Purpose:     "Checks that rpcs are have the same response type.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingRPCSameResponseType,
+		Explanation: breakingRPCSameResponseTypeRuleExplanation,
 	}
 	// BreakingRPCSameServerStreamingRuleSpecBuilder is a rule spec builder.
 	BreakingRPCSameServerStreamingRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RPC_SAME_SERVER_STREAMING",
-		Purpose: "Checks that rpcs have the same server streaming value.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingRPCSameServerStreaming,
+		ID:          "RPC_SAME_SERVER_STREAMING",
+		Purpose:     "Checks that rpcs have the same server streaming value.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingRPCSameServerStreaming,
+		Explanation: breakingRPCSameServerStreamingRuleExplanation,
 	}
 	// BreakingServiceNoDeleteRuleSpecBuilder is a rule spec builder.
 	BreakingServiceNoDeleteRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "SERVICE_NO_DELETE",
-		Purpose: "Checks that services are not deleted from a given file.",
-		Type:    check.RuleTypeBreaking,
-		Handler: bufcheckserverhandle.HandleBreakingServiceNoDelete,
+		ID:          "SERVICE_NO_DELETE",
+		Purpose:     "Checks that services are not deleted from a given file.",
+		Type:        check.RuleTypeBreaking,
+		Handler:     bufcheckserverhandle.HandleBreakingServiceNoDelete,
+		Explanation: breakingServiceNoDeleteRuleExplanation,
 	}
 	// LintCommentEnumRuleSpecBuilder is a rule spec builder.
 	LintCommentEnumRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "COMMENT_ENUM",
-		Purpose: "Checks that enums have non-empty comments.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintCommentEnum,
+		ID:          "COMMENT_ENUM",
+		Purpose:     "Checks that enums have non-empty comments.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintCommentEnum,
+		Explanation: lintCommentEnumRuleExplanation,
 	}
 	// LintCommentEnumValueRuleSpecBuilder is a rule spec builder.
 	LintCommentEnumValueRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "COMMENT_ENUM_VALUE",
-		Purpose: "Checks that enum values have non-empty comments.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintCommentEnumValue,
+		ID:          "COMMENT_ENUM_VALUE",
+		Purpose:     "Checks that enum values have non-empty comments.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintCommentEnumValue,
+		Explanation: lintCommentEnumValueRuleExplanation,
 	}
 	// LintCommentFieldRuleSpecBuilder is a rule spec builder.
 	LintCommentFieldRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "COMMENT_FIELD",
-		Purpose: "Checks that fields have non-empty comments.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintCommentField,
+		ID:          "COMMENT_FIELD",
+		Purpose:     "Checks that fields have non-empty comments.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintCommentField,
+		Explanation: lintCommentFieldRuleExplanation,
 	}
 	// LintCommentMessageRuleSpecBuilder is a rule spec builder.
 	LintCommentMessageRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "COMMENT_MESSAGE",
-		Purpose: "Checks that messages have non-empty comments.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintCommentMessage,
+		ID:          "COMMENT_MESSAGE",
+		Purpose:     "Checks that messages have non-empty comments.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintCommentMessage,
+		Explanation: lintCommentMessageRuleExplanation,
 	}
 	// LintCommentOneofRuleSpecBuilder is a rule spec builder.
 	LintCommentOneofRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "COMMENT_ONEOF",
-		Purpose: "Checks that oneofs have non-empty comments.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintCommentOneof,
+		ID:          "COMMENT_ONEOF",
+		Purpose:     "Checks that oneofs have non-empty comments.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintCommentOneof,
+		Explanation: lintCommentOneofRuleExplanation,
 	}
 	// LintCommentRPCRuleSpecBuilder is a rule spec builder.
 	LintCommentRPCRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "COMMENT_RPC",
-		Purpose: "Checks that RPCs have non-empty comments.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintCommentRPC,
+		ID:          "COMMENT_RPC",
+		Purpose:     "Checks that RPCs have non-empty comments.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintCommentRPC,
+		Explanation: lintCommentRPCRuleExplanation,
 	}
 	// LintCommentServiceRuleSpecBuilder is a rule spec builder.
 	LintCommentServiceRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "COMMENT_SERVICE",
-		Purpose: "Checks that services have non-empty comments.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintCommentService,
+		ID:          "COMMENT_SERVICE",
+		Purpose:     "Checks that services have non-empty comments.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintCommentService,
+		Explanation: lintCommentServiceRuleExplanation,
 	}
 	// LintDirectorySamePackageRuleSpecBuilder is a rule spec builder.
 	LintDirectorySamePackageRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "DIRECTORY_SAME_PACKAGE",
-		Purpose: "Checks that all files in a given directory are in the same package.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintDirectorySamePackage,
+		ID:          "DIRECTORY_SAME_PACKAGE",
+		Purpose:     "Checks that all files in a given directory are in the same package.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintDirectorySamePackage,
+		Explanation: lintDirectorySamePackageRuleExplanation,
 	}
 	// LintEnumFirstValueZeroRuleSpecBuilder is a rule spec builder.
 	LintEnumFirstValueZeroRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_FIRST_VALUE_ZERO",
-		Purpose: "Checks that all first values of enums have a numeric value of 0.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintEnumFirstValueZero,
+		ID:          "ENUM_FIRST_VALUE_ZERO",
+		Purpose:     "Checks that all first values of enums have a numeric value of 0.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintEnumFirstValueZero,
+		Explanation: lintEnumFirstValueZeroRuleExplanation,
 	}
 	// LintEnumNoAllowAliasRuleSpecBuilder is a rule spec builder.
 	LintEnumNoAllowAliasRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_NO_ALLOW_ALIAS",
-		Purpose: "Checks that enums do not have the allow_alias option set.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintEnumNoAllowAlias,
+		ID:          "ENUM_NO_ALLOW_ALIAS",
+		Purpose:     "Checks that enums do not have the allow_alias option set.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintEnumNoAllowAlias,
+		Explanation: lintEnumNoAllowAliasRuleExplanation,
 	}
 	// LintEnumPascalCaseRuleSpecBuilder is a rule spec builder.
 	LintEnumPascalCaseRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_PASCAL_CASE",
-		Purpose: "Checks that enums are PascalCase.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintEnumPascalCase,
+		ID:          "ENUM_PASCAL_CASE",
+		Purpose:     "Checks that enums are PascalCase.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintEnumPascalCase,
+		Explanation: lintEnumPascalCaseRuleExplanation,
 	}
 	// LintEnumValuePrefixRuleSpecBuilder is a rule spec builder.
 	LintEnumValuePrefixRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_VALUE_PREFIX",
-		Purpose: "Checks that enum values are prefixed with ENUM_NAME_UPPER_SNAKE_CASE.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintEnumValuePrefix,
+		ID:          "ENUM_VALUE_PREFIX",
+		Purpose:     "Checks that enum values are prefixed with ENUM_NAME_UPPER_SNAKE_CASE.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintEnumValuePrefix,
+		Explanation: lintEnumValuePrefixRuleExplanation,
 	}
 	// LintEnumValueUpperSnakeCaseRuleSpecBuilder is a rule spec builder.
 	LintEnumValueUpperSnakeCaseRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_VALUE_UPPER_SNAKE_CASE",
-		Purpose: "Checks that enum values are UPPER_SNAKE_CASE.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintEnumValueUpperSnakeCase,
+		ID:          "ENUM_VALUE_UPPER_SNAKE_CASE",
+		Purpose:     "Checks that enum values are UPPER_SNAKE_CASE.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintEnumValueUpperSnakeCase,
+		Explanation: lintEnumValueUpperSnakeCaseRuleExplanation,
 	}
 	// LintEnumZeroValueSuffixRuleSpecBuilder is a rule spec builder.
 	LintEnumZeroValueSuffixRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ENUM_ZERO_VALUE_SUFFIX",
-		Purpose: `Checks that enum zero values have a consistent suffix (configurable, default suffix is "_UNSPECIFIED").`,
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintEnumZeroValueSuffix,
+		ID:          "ENUM_ZERO_VALUE_SUFFIX",
+		Purpose:     `Checks that enum zero values have a consistent suffix (configurable, default suffix is "_UNSPECIFIED").`,
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintEnumZeroValueSuffix,
+		Explanation: lintEnumZeroValueSuffixRuleExplanation,
+	}
+	// LintExtensionRegistryNoConflictRuleSpecBuilder is a rule spec builder.
+	LintExtensionRegistryNoConflictRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "EXTENSION_REGISTRY_NO_CONFLICT",
+		Purpose:     "Checks that extensions do not use a number allocated to another extension of the same message in the extension registry.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintExtensionRegistryNoConflict,
+		Explanation: lintExtensionRegistryNoConflictRuleExplanation,
 	}
 	// LintFieldLowerSnakeCaseRuleSpecBuilder is a rule spec builder.
 	LintFieldLowerSnakeCaseRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_LOWER_SNAKE_CASE",
-		Purpose: "Checks that field names are lower_snake_case.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintFieldLowerSnakeCase,
+		ID:          "FIELD_LOWER_SNAKE_CASE",
+		Purpose:     "Checks that field names are lower_snake_case.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintFieldLowerSnakeCase,
+		Explanation: lintFieldLowerSnakeCaseRuleExplanation,
 	}
 	// LintFieldNoDescriptorRuleSpecBuilder is a rule spec builder.
 	LintFieldNoDescriptorRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_NO_DESCRIPTOR",
-		Purpose: `Checks that field names are not any capitalization of "descriptor" with any number of prefix or suffix underscores.`,
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintFieldNoDescriptor,
+		ID:          "FIELD_NO_DESCRIPTOR",
+		Purpose:     `Checks that field names are not any capitalization of "descriptor" with any number of prefix or suffix underscores.`,
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintFieldNoDescriptor,
+		Explanation: lintFieldNoDescriptorRuleExplanation,
 	}
 	// LintFieldNotRequiredRuleSpecBuilder is a rule spec builder.
 	LintFieldNotRequiredRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FIELD_NOT_REQUIRED",
-		Purpose: `Checks that fields are not configured to be required.`,
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintFieldNotRequired,
+		ID:          "FIELD_NOT_REQUIRED",
+		Purpose:     `Checks that fields are not configured to be required.`,
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintFieldNotRequired,
+		Explanation: lintFieldNotRequiredRuleExplanation,
 	}
 	// LintFileLowerSnakeCaseRuleSpecBuilder is a rule spec builder.
 	LintFileLowerSnakeCaseRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "FILE_LOWER_SNAKE_CASE",
-		Purpose: "Checks that filenames are lower_snake_case.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintFileLowerSnakeCase,
+		ID:          "FILE_LOWER_SNAKE_CASE",
+		Purpose:     "Checks that filenames are lower_snake_case.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintFileLowerSnakeCase,
+		Explanation: lintFileLowerSnakeCaseRuleExplanation,
+	}
+	// LintGeneratedNamesCsharpRuleSpecBuilder is a rule spec builder.
+	LintGeneratedNamesCsharpRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "GENERATED_NAMES_CSHARP",
+		Purpose:     "Checks that identifiers generated for C# do not collide with each other or with names reserved by the generated code.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintGeneratedNamesCsharp,
+		Explanation: lintGeneratedNamesCsharpRuleExplanation,
+	}
+	// LintGeneratedNamesGoRuleSpecBuilder is a rule spec builder.
+	LintGeneratedNamesGoRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "GENERATED_NAMES_GO",
+		Purpose:     "Checks that identifiers generated for Go do not collide with each other or with names reserved by the generated code.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintGeneratedNamesGo,
+		Explanation: lintGeneratedNamesGoRuleExplanation,
+	}
+	// LintGeneratedNamesJavaRuleSpecBuilder is a rule spec builder.
+	LintGeneratedNamesJavaRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "GENERATED_NAMES_JAVA",
+		Purpose:     "Checks that identifiers generated for Java do not collide with each other or with names reserved by the generated code.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintGeneratedNamesJava,
+		Explanation: lintGeneratedNamesJavaRuleExplanation,
+	}
+	// LintGeneratedNamesPythonRuleSpecBuilder is a rule spec builder.
+	LintGeneratedNamesPythonRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "GENERATED_NAMES_PYTHON",
+		Purpose:     "Checks that identifiers generated for Python do not collide with each other or with keywords or names reserved by the generated code.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintGeneratedNamesPython,
+		Explanation: lintGeneratedNamesPythonRuleExplanation,
 	}
 	// LintImportNoPublicRuleSpecBuilder is a rule spec builder.
 	LintImportNoPublicRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "IMPORT_NO_PUBLIC",
-		Purpose: "Checks that imports are not public.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintImportNoPublic,
+		ID:          "IMPORT_NO_PUBLIC",
+		Purpose:     "Checks that imports are not public.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintImportNoPublic,
+		Explanation: lintImportNoPublicRuleExplanation,
 	}
 	// LintImportNoWeakRuleSpecBuilder is a rule spec builder.
 	LintImportNoWeakRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "IMPORT_NO_WEAK",
-		Purpose: "Checks that imports are not weak.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintImportNoWeak,
+		ID:          "IMPORT_NO_WEAK",
+		Purpose:     "Checks that imports are not weak.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintImportNoWeak,
+		Explanation: lintImportNoWeakRuleExplanation,
 	}
 	// LintImportUsedRuleSpecBuilder is a rule spec builder.
 	LintImportUsedRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "IMPORT_USED",
-		Purpose: "Checks that imports are used.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintImportUsed,
+		ID:          "IMPORT_USED",
+		Purpose:     "Checks that imports are used.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintImportUsed,
+		Explanation: lintImportUsedRuleExplanation,
 	}
 	// LintMessagePascalCaseRuleSpecBuilder is a rule spec builder.
 	LintMessagePascalCaseRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "MESSAGE_PASCAL_CASE",
-		Purpose: "Checks that messages are PascalCase.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintMessagePascalCase,
+		ID:          "MESSAGE_PASCAL_CASE",
+		Purpose:     "Checks that messages are PascalCase.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintMessagePascalCase,
+		Explanation: lintMessagePascalCaseRuleExplanation,
+	}
+	// LintNamingEnumRuleSpecBuilder is a rule spec builder.
+	LintNamingEnumRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "NAMING_ENUM",
+		Purpose:     "Checks that enum names match the pattern set with lint.naming.enum.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintNamingEnum,
+		Explanation: lintNamingEnumRuleExplanation,
+	}
+	// LintNamingEnumValueRuleSpecBuilder is a rule spec builder.
+	LintNamingEnumValueRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "NAMING_ENUM_VALUE",
+		Purpose:     "Checks that enum value names match the pattern set with lint.naming.enum_value.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintNamingEnumValue,
+		Explanation: lintNamingEnumValueRuleExplanation,
+	}
+	// LintNamingFieldRuleSpecBuilder is a rule spec builder.
+	LintNamingFieldRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "NAMING_FIELD",
+		Purpose:     "Checks that field names match the pattern set with lint.naming.field.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintNamingField,
+		Explanation: lintNamingFieldRuleExplanation,
+	}
+	// LintNamingMessageRuleSpecBuilder is a rule spec builder.
+	LintNamingMessageRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "NAMING_MESSAGE",
+		Purpose:     "Checks that message names match the pattern set with lint.naming.message.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintNamingMessage,
+		Explanation: lintNamingMessageRuleExplanation,
+	}
+	// LintNamingRPCRuleSpecBuilder is a rule spec builder.
+	LintNamingRPCRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "NAMING_RPC",
+		Purpose:     "Checks that RPC names match the pattern set with lint.naming.rpc.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintNamingRPC,
+		Explanation: lintNamingRPCRuleExplanation,
+	}
+	// LintNamingServiceRuleSpecBuilder is a rule spec builder.
+	LintNamingServiceRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "NAMING_SERVICE",
+		Purpose:     "Checks that service names match the pattern set with lint.naming.service.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintNamingService,
+		Explanation: lintNamingServiceRuleExplanation,
 	}
 	// LintOneofLowerSnakeCaseRuleSpecBuilder is a rule spec builder.
 	LintOneofLowerSnakeCaseRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "ONEOF_LOWER_SNAKE_CASE",
-		Purpose: "Checks that oneof names are lower_snake_case.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintOneofLowerSnakeCase,
+		ID:          "ONEOF_LOWER_SNAKE_CASE",
+		Purpose:     "Checks that oneof names are lower_snake_case.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintOneofLowerSnakeCase,
+		Explanation: lintOneofLowerSnakeCaseRuleExplanation,
 	}
 	// LintPackageDefinedRuleSpecBuilder is a rule spec builder.
 	LintPackageDefinedRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_DEFINED",
-		Purpose: "Checks that all files have a package defined.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageDefined,
+		ID:          "PACKAGE_DEFINED",
+		Purpose:     "Checks that all files have a package defined.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageDefined,
+		Explanation: lintPackageDefinedRuleExplanation,
 	}
 	// LintPackageDirectoryMatchRuleSpecBuilder is a rule spec builder.
 	LintPackageDirectoryMatchRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_DIRECTORY_MATCH",
-		Purpose: "Checks that all files are in a directory that matches their package name.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageDirectoryMatch,
+		ID:          "PACKAGE_DIRECTORY_MATCH",
+		Purpose:     "Checks that all files are in a directory that matches their package name.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageDirectoryMatch,
+		Explanation: lintPackageDirectoryMatchRuleExplanation,
 	}
 	// LintPackageLowerSnakeCaseRuleSpecBuilder is a rule spec builder.
 	LintPackageLowerSnakeCaseRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_LOWER_SNAKE_CASE",
-		Purpose: "Checks that packages are lower_snake.case.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageLowerSnakeCase,
+		ID:          "PACKAGE_LOWER_SNAKE_CASE",
+		Purpose:     "Checks that packages are lower_snake.case.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageLowerSnakeCase,
+		Explanation: lintPackageLowerSnakeCaseRuleExplanation,
 	}
 	// LintPackageNoImportCycleRuleSpecBuilder is a rule spec builder.
 	LintPackageNoImportCycleRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_NO_IMPORT_CYCLE",
-		Purpose: "Checks that packages do not have import cycles.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageNoImportCycle,
+		ID:          "PACKAGE_NO_IMPORT_CYCLE",
+		Purpose:     "Checks that packages do not have import cycles.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageNoImportCycle,
+		Explanation: lintPackageNoImportCycleRuleExplanation,
 	}
 	// LintPackageSameCsharpNamespaceRuleSpecBuilder is a rule spec builder.
 	LintPackageSameCsharpNamespaceRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_SAME_CSHARP_NAMESPACE",
-		Purpose: "Checks that all files with a given package have the same value for the csharp_namespace option.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageSameCsharpNamespace,
+		ID:          "PACKAGE_SAME_CSHARP_NAMESPACE",
+		Purpose:     "Checks that all files with a given package have the same value for the csharp_namespace option.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageSameCsharpNamespace,
+		Explanation: lintPackageSameCsharpNamespaceRuleExplanation,
 	}
 	// LintPackageSameDirectoryRuleSpecBuilder is a rule spec builder.
 	LintPackageSameDirectoryRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_SAME_DIRECTORY",
-		Purpose: "Checks that all files with a given package are in the same directory.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageSameDirectory,
+		ID:          "PACKAGE_SAME_DIRECTORY",
+		Purpose:     "Checks that all files with a given package are in the same directory.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageSameDirectory,
+		Explanation: lintPackageSameDirectoryRuleExplanation,
 	}
 	// LintPackageSameGoPackageRuleSpecBuilder is a rule spec builder.
 	LintPackageSameGoPackageRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_SAME_GO_PACKAGE",
-		Purpose: "Checks that all files with a given package have the same value for the go_package option.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageSameGoPackage,
+		ID:          "PACKAGE_SAME_GO_PACKAGE",
+		Purpose:     "Checks that all files with a given package have the same value for the go_package option.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageSameGoPackage,
+		Explanation: lintPackageSameGoPackageRuleExplanation,
 	}
 	// LintPackageSameJavaMultipleFilesRuleSpecBuilder is a rule spec builder.
 	LintPackageSameJavaMultipleFilesRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_SAME_JAVA_MULTIPLE_FILES",
-		Purpose: "Checks that all files with a given package have the same value for the java_multiple_files option.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageSameJavaMultipleFiles,
+		ID:          "PACKAGE_SAME_JAVA_MULTIPLE_FILES",
+		Purpose:     "Checks that all files with a given package have the same value for the java_multiple_files option.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageSameJavaMultipleFiles,
+		Explanation: lintPackageSameJavaMultipleFilesRuleExplanation,
 	}
 	// LintPackageSameJavaPackageRuleSpecBuilder is a rule spec builder.
 	LintPackageSameJavaPackageRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_SAME_JAVA_PACKAGE",
-		Purpose: "Checks that all files with a given package have the same value for the java_package option.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageSameJavaPackage,
+		ID:          "PACKAGE_SAME_JAVA_PACKAGE",
+		Purpose:     "Checks that all files with a given package have the same value for the java_package option.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageSameJavaPackage,
+		Explanation: lintPackageSameJavaPackageRuleExplanation,
 	}
 	// LintPackageSamePhpNamespaceRuleSpecBuilder is a rule spec builder.
 	LintPackageSamePhpNamespaceRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_SAME_PHP_NAMESPACE",
-		Purpose: "Checks that all files with a given package have the same value for the php_namespace option.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageSamePhpNamespace,
+		ID:          "PACKAGE_SAME_PHP_NAMESPACE",
+		Purpose:     "Checks that all files with a given package have the same value for the php_namespace option.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageSamePhpNamespace,
+		Explanation: lintPackageSamePhpNamespaceRuleExplanation,
 	}
 	// LintPackageSameRubyPackageRuleSpecBuilder is a rule spec builder.
 	LintPackageSameRubyPackageRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_SAME_RUBY_PACKAGE",
-		Purpose: "Checks that all files with a given package have the same value for the ruby_package option.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageSameRubyPackage,
+		ID:          "PACKAGE_SAME_RUBY_PACKAGE",
+		Purpose:     "Checks that all files with a given package have the same value for the ruby_package option.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageSameRubyPackage,
+		Explanation: lintPackageSameRubyPackageRuleExplanation,
 	}
 	// LintPackageSameSwiftPrefixRuleSpecBuilder is a rule spec builder.
 	LintPackageSameSwiftPrefixRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_SAME_SWIFT_PREFIX",
-		Purpose: "Checks that all files with a given package have the same value for the swift_prefix option.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageSameSwiftPrefix,
+		ID:          "PACKAGE_SAME_SWIFT_PREFIX",
+		Purpose:     "Checks that all files with a given package have the same value for the swift_prefix option.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageSameSwiftPrefix,
+		Explanation: lintPackageSameSwiftPrefixRuleExplanation,
 	}
 	// LintPackageVersionSuffixRuleSpecBuilder is a rule spec builder.
 	LintPackageVersionSuffixRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PACKAGE_VERSION_SUFFIX",
-		Purpose: `Checks that the last component of all packages is a version of the form v\d+, v\d+test.*, v\d+(alpha|beta)\d+, or v\d+p\d+(alpha|beta)\d+, where numbers are >=1.`,
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintPackageVersionSuffix,
+		ID:          "PACKAGE_VERSION_SUFFIX",
+		Purpose:     `Checks that the last component of all packages is a version of the form v\d+, v\d+test.*, v\d+(alpha|beta)\d+, or v\d+p\d+(alpha|beta)\d+, where numbers are >=1.`,
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintPackageVersionSuffix,
+		Explanation: lintPackageVersionSuffixRuleExplanation,
 	}
 	// LintProtovalidateRuleSpecBuilder is a rule spec builder.
 	LintProtovalidateRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "PROTOVALIDATE",
-		Purpose: "Checks that protovalidate rules are valid and all CEL expressions compile.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintProtovalidate,
+		ID:          "PROTOVALIDATE",
+		Purpose:     "Checks that protovalidate rules are valid and all CEL expressions compile.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintProtovalidate,
+		Explanation: lintProtovalidateRuleExplanation,
+	}
+	// LintReservedRegistryNoReuseRuleSpecBuilder is a rule spec builder.
+	LintReservedRegistryNoReuseRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
+		ID:          "RESERVED_REGISTRY_NO_REUSE",
+		Purpose:     "Checks that fields and enum values do not reuse a number or name recorded in the reserved registry.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintReservedRegistryNoReuse,
+		Explanation: lintReservedRegistryNoReuseRuleExplanation,
 	}
 	// LintRPCNoClientStreamingRuleSpecBuilder is a rule spec builder.
 	LintRPCNoClientStreamingRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RPC_NO_CLIENT_STREAMING",
-		Purpose: "Checks that RPCs are not client streaming.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintRPCNoClientStreaming,
+		ID:          "RPC_NO_CLIENT_STREAMING",
+		Purpose:     "Checks that RPCs are not client streaming.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintRPCNoClientStreaming,
+		Explanation: lintRPCNoClientStreamingRuleExplanation,
 	}
 	// LintRPCNoServerStreamingRuleSpecBuilder is a rule spec builder.
 	LintRPCNoServerStreamingRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RPC_NO_SERVER_STREAMING",
-		Purpose: "Checks that RPCs are not server streaming.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintRPCNoServerStreaming,
+		ID:          "RPC_NO_SERVER_STREAMING",
+		Purpose:     "Checks that RPCs are not server streaming.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintRPCNoServerStreaming,
+		Explanation: lintRPCNoServerStreamingRuleExplanation,
 	}
 	// LintRPCPascalCaseRuleSpecBuilder is a rule spec builder.
 	LintRPCPascalCaseRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RPC_PASCAL_CASE",
-		Purpose: "Checks that RPCs are PascalCase.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintRPCPascalCase,
+		ID:          "RPC_PASCAL_CASE",
+		Purpose:     "Checks that RPCs are PascalCase.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintRPCPascalCase,
+		Explanation: lintRPCPascalCaseRuleExplanation,
 	}
 	// LintRPCRequestResponseUniqueRuleSpecBuilder is a rule spec builder.
 	LintRPCRequestResponseUniqueRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RPC_REQUEST_RESPONSE_UNIQUE",
-		Purpose: "Checks that RPC request and response types are only used in one RPC (configurable).",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintRPCRequestResponseUnique,
+		ID:          "RPC_REQUEST_RESPONSE_UNIQUE",
+		Purpose:     "Checks that RPC request and response types are only used in one RPC (configurable).",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintRPCRequestResponseUnique,
+		Explanation: lintRPCRequestResponseUniqueRuleExplanation,
 	}
 	// LintRPCRequestStandardNameRuleSpecBuilder is a rule spec builder.
 	LintRPCRequestStandardNameRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RPC_REQUEST_STANDARD_NAME",
-		Purpose: "Checks that RPC request type names are RPCNameRequest or ServiceNameRPCNameRequest (configurable).",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintRPCRequestStandardName,
+		ID:          "RPC_REQUEST_STANDARD_NAME",
+		Purpose:     "Checks that RPC request type names are RPCNameRequest or ServiceNameRPCNameRequest (configurable).",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintRPCRequestStandardName,
+		Explanation: lintRPCRequestStandardNameRuleExplanation,
 	}
 	// LintRPCResponseStandardNameRuleSpecBuilder is a rule spec builder.
 	LintRPCResponseStandardNameRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "RPC_RESPONSE_STANDARD_NAME",
-		Purpose: "Checks that RPC response type names are RPCNameResponse or ServiceNameRPCNameResponse (configurable).",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintRPCResponseStandardName,
+		ID:          "RPC_RESPONSE_STANDARD_NAME",
+		Purpose:     "Checks that RPC response type names are RPCNameResponse or ServiceNameRPCNameResponse (configurable).",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintRPCResponseStandardName,
+		Explanation: lintRPCResponseStandardNameRuleExplanation,
 	}
 	// LintServicePascalCaseRuleSpecBuilder is a rule spec builder.
 	LintServicePascalCaseRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "SERVICE_PASCAL_CASE",
-		Purpose: "Checks that services are PascalCase.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintServicePascalCase,
+		ID:          "SERVICE_PASCAL_CASE",
+		Purpose:     "Checks that services are PascalCase.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintServicePascalCase,
+		Explanation: lintServicePascalCaseRuleExplanation,
 	}
 	// LintServiceSuffixRuleSpecBuilder is a rule spec builder.
 	LintServiceSuffixRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "SERVICE_SUFFIX",
-		Purpose: `Checks that services have a consistent suffix (configurable, default suffix is "Service").`,
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintServiceSuffix,
+		ID:          "SERVICE_SUFFIX",
+		Purpose:     `Checks that services have a consistent suffix (configurable, default suffix is "Service").`,
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintServiceSuffix,
+		Explanation: lintServiceSuffixRuleExplanation,
 	}
 	// LintStablePackageNoImportUnstableRuleSpecBuilder is a rule spec builder.
 	LintStablePackageNoImportUnstableRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "STABLE_PACKAGE_NO_IMPORT_UNSTABLE",
-		Purpose: "Checks that all files that have stable versioned packages do not import packages with unstable version packages.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintStablePackageNoImportUnstable,
+		ID:          "STABLE_PACKAGE_NO_IMPORT_UNSTABLE",
+		Purpose:     "Checks that all files that have stable versioned packages do not import packages with unstable version packages.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintStablePackageNoImportUnstable,
+		Explanation: lintStablePackageNoImportUnstableRuleExplanation,
 	}
 	// LintSyntaxSpecifiedRuleSpecBuilder is a rule spec builder.
 	LintSyntaxSpecifiedRuleSpecBuilder = &bufcheckserverutil.RuleSpecBuilder{
-		ID:      "SYNTAX_SPECIFIED",
-		Purpose: "Checks that all files have a syntax specified.",
-		Type:    check.RuleTypeLint,
-		Handler: bufcheckserverhandle.HandleLintSyntaxSpecified,
+		ID:          "SYNTAX_SPECIFIED",
+		Purpose:     "Checks that all files have a syntax specified.",
+		Type:        check.RuleTypeLint,
+		Handler:     bufcheckserverhandle.HandleLintSyntaxSpecified,
+		Explanation: lintSyntaxSpecifiedRuleExplanation,
 	}
 
 	// TODO: Improve purposes. These are in buf.build/docs. Perhaps we can abandon the "checks that" prefix.
@@ -906,6 +1159,16 @@ var (
 		ID:      "WIRE_JSON",
 		Purpose: "Checks that there are no wire breaking changes for the binary or JSON encodings.",
 	}
+	// JSONCategorySpec is a category spec.
+	JSONCategorySpec = &check.CategorySpec{
+		ID:      "JSON",
+		Purpose: "Checks that there are no breaking changes for the JSON encoding, such as changes to field or enum value names.",
+	}
+	// ProtovalidateCategorySpec is a category spec.
+	ProtovalidateCategorySpec = &check.CategorySpec{
+		ID:      "PROTOVALIDATE_CONSTRAINTS",
+		Purpose: "Checks that protovalidate constraints do not become more restrictive, such that values valid for the previous schema are no longer valid.",
+	}
 
 	// BasicCategorySpec is a category spec.
 	BasicCategorySpec = &check.CategorySpec{
@@ -929,6 +1192,16 @@ var (
 		ID:      "FILE_LAYOUT",
 		Purpose: "Checks the file layout.",
 	}
+	// GeneratedNamesCategorySpec is a category spec.
+	GeneratedNamesCategorySpec = &check.CategorySpec{
+		ID:      "GENERATED_NAMES",
+		Purpose: "Checks that identifiers generated for supported languages do not collide.",
+	}
+	// NamingCategorySpec is a category spec.
+	NamingCategorySpec = &check.CategorySpec{
+		ID:      "NAMING",
+		Purpose: "Checks that names match the patterns set with lint.naming.",
+	}
 	// MinimalCategorySpec is a category spec.
 	MinimalCategorySpec = &check.CategorySpec{
 		ID:      "MINIMAL",
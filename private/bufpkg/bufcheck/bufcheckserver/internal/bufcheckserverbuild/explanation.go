@@ -0,0 +1,2289 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcheckserverbuild
+
+import (
+	"buf.build/go/bufplugin/check"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver/internal/bufcheckserverutil"
+)
+
+// GetRuleExplanation returns the RuleExplanation for the builtin rule with the given type and ID.
+//
+// Returns false if the rule does not exist.
+func GetRuleExplanation(ruleType check.RuleType, ruleID string) (*bufcheckserverutil.RuleExplanation, bool) {
+	for _, ruleSpecBuilder := range allRuleSpecBuilders {
+		if ruleSpecBuilder.Type == ruleType && ruleSpecBuilder.ID == ruleID {
+			return ruleSpecBuilder.Explanation, ruleSpecBuilder.Explanation != nil
+		}
+	}
+	return nil, false
+}
+
+// allRuleSpecBuilders are all builtin RuleSpecBuilders.
+//
+// When adding a RuleSpecBuilder, add it here and give it an Explanation.
+var allRuleSpecBuilders = []*bufcheckserverutil.RuleSpecBuilder{
+	BreakingEnumNoDeleteRuleSpecBuilder,
+	BreakingEnumSameJSONFormatRuleSpecBuilder,
+	BreakingEnumSameTypeRuleSpecBuilder,
+	BreakingEnumValueNoDeleteRuleSpecBuilder,
+	BreakingEnumValueNoDeleteUnlessNameReservedRuleSpecBuilder,
+	BreakingEnumValueNoDeleteUnlessNumberReservedRuleSpecBuilder,
+	BreakingEnumValueSameNameRuleSpecBuilder,
+	BreakingExtensionMessageNoDeleteRuleSpecBuilder,
+	BreakingExtensionNoDeleteRuleSpecBuilder,
+	BreakingFieldNoDeleteRuleSpecBuilder,
+	BreakingFieldNoDeleteUnlessNameReservedRuleSpecBuilder,
+	BreakingFieldNoDeleteUnlessNumberReservedRuleSpecBuilder,
+	BreakingFieldSameCTypeRuleSpecBuilder,
+	BreakingFieldSameCardinalityRuleSpecBuilder,
+	BreakingFieldSameCppStringTypeRuleSpecBuilder,
+	BreakingFieldSameDefaultRuleSpecBuilder,
+	BreakingFieldSameJSONNameRuleSpecBuilder,
+	BreakingFieldSameJSTypeRuleSpecBuilder,
+	BreakingFieldSameJavaUTF8ValidationRuleSpecBuilder,
+	BreakingFieldSameLabelRuleSpecBuilder,
+	BreakingFieldSameLabelV1Beta1RuleSpecBuilder,
+	BreakingFieldSameNameRuleSpecBuilder,
+	BreakingFieldSameOneofRuleSpecBuilder,
+	BreakingFieldSameTypeRuleSpecBuilder,
+	BreakingFieldSameUTF8ValidationRuleSpecBuilder,
+	BreakingFieldWireCompatibleCardinalityRuleSpecBuilder,
+	BreakingFieldWireCompatibleTypeRuleSpecBuilder,
+	BreakingFieldWireJSONCompatibleCardinalityRuleSpecBuilder,
+	BreakingFieldWireJSONCompatibleTypeRuleSpecBuilder,
+	BreakingFileNoDeleteRuleSpecBuilder,
+	BreakingFileSameCcEnableArenasRuleSpecBuilder,
+	BreakingFileSameCcGenericServicesRuleSpecBuilder,
+	BreakingFileSameCsharpNamespaceRuleSpecBuilder,
+	BreakingFileSameGoPackageRuleSpecBuilder,
+	BreakingFileSameJavaGenericServicesRuleSpecBuilder,
+	BreakingFileSameJavaMultipleFilesRuleSpecBuilder,
+	BreakingFileSameJavaOuterClassnameRuleSpecBuilder,
+	BreakingFileSameJavaPackageRuleSpecBuilder,
+	BreakingFileSameJavaStringCheckUtf8RuleSpecBuilder,
+	BreakingFileSameObjcClassPrefixRuleSpecBuilder,
+	BreakingFileSameOptimizeForRuleSpecBuilder,
+	BreakingFileSamePackageRuleSpecBuilder,
+	BreakingFileSamePhpClassPrefixRuleSpecBuilder,
+	BreakingFileSamePhpGenericServicesRuleSpecBuilder,
+	BreakingFileSamePhpMetadataNamespaceRuleSpecBuilder,
+	BreakingFileSamePhpNamespaceRuleSpecBuilder,
+	BreakingFileSamePyGenericServicesRuleSpecBuilder,
+	BreakingFileSameRubyPackageRuleSpecBuilder,
+	BreakingFileSameSwiftPrefixRuleSpecBuilder,
+	BreakingFileSameSyntaxRuleSpecBuilder,
+	BreakingMessageNoDeleteRuleSpecBuilder,
+	BreakingMessageNoRemoveStandardDescriptorAccessorRuleSpecBuilder,
+	BreakingMessageSameJSONFormatRuleSpecBuilder,
+	BreakingMessageSameMessageSetWireFormatRuleSpecBuilder,
+	BreakingMessageSameRequiredFieldsRuleSpecBuilder,
+	BreakingOneofNoDeleteRuleSpecBuilder,
+	BreakingPackageEnumNoDeleteRuleSpecBuilder,
+	BreakingPackageExtensionNoDeleteRuleSpecBuilder,
+	BreakingPackageMessageNoDeleteRuleSpecBuilder,
+	BreakingPackageNoDeleteRuleSpecBuilder,
+	BreakingPackageServiceNoDeleteRuleSpecBuilder,
+	BreakingProtovalidateFieldNoAddCELRuleSpecBuilder,
+	BreakingProtovalidateFieldNoAddRequiredRuleSpecBuilder,
+	BreakingProtovalidateFieldNoNarrowRangeRuleSpecBuilder,
+	BreakingProtovalidateFieldNoRemoveEnumValueRuleSpecBuilder,
+	BreakingProtovalidateMessageNoAddCELRuleSpecBuilder,
+	BreakingRPCNoDeleteRuleSpecBuilder,
+	BreakingRPCSameClientStreamingRuleSpecBuilder,
+	BreakingRPCSameIdempotencyLevelRuleSpecBuilder,
+	BreakingRPCSameRequestTypeRuleSpecBuilder,
+	BreakingRPCSameResponseTypeRuleSpecBuilder,
+	BreakingRPCSameServerStreamingRuleSpecBuilder,
+	BreakingReservedEnumNoDeleteRuleSpecBuilder,
+	BreakingReservedMessageNoDeleteRuleSpecBuilder,
+	BreakingServiceNoDeleteRuleSpecBuilder,
+	LintCommentEnumRuleSpecBuilder,
+	LintCommentEnumValueRuleSpecBuilder,
+	LintCommentFieldRuleSpecBuilder,
+	LintCommentMessageRuleSpecBuilder,
+	LintCommentOneofRuleSpecBuilder,
+	LintCommentRPCRuleSpecBuilder,
+	LintCommentServiceRuleSpecBuilder,
+	LintDirectorySamePackageRuleSpecBuilder,
+	LintEnumFirstValueZeroRuleSpecBuilder,
+	LintEnumNoAllowAliasRuleSpecBuilder,
+	LintEnumPascalCaseRuleSpecBuilder,
+	LintEnumValuePrefixRuleSpecBuilder,
+	LintEnumValueUpperSnakeCaseRuleSpecBuilder,
+	LintEnumZeroValueSuffixRuleSpecBuilder,
+	LintExtensionRegistryNoConflictRuleSpecBuilder,
+	LintFieldLowerSnakeCaseRuleSpecBuilder,
+	LintFieldNoDescriptorRuleSpecBuilder,
+	LintFieldNotRequiredRuleSpecBuilder,
+	LintFileLowerSnakeCaseRuleSpecBuilder,
+	LintGeneratedNamesCsharpRuleSpecBuilder,
+	LintGeneratedNamesGoRuleSpecBuilder,
+	LintGeneratedNamesJavaRuleSpecBuilder,
+	LintGeneratedNamesPythonRuleSpecBuilder,
+	LintImportNoPublicRuleSpecBuilder,
+	LintImportNoWeakRuleSpecBuilder,
+	LintImportUsedRuleSpecBuilder,
+	LintMessagePascalCaseRuleSpecBuilder,
+	LintNamingEnumRuleSpecBuilder,
+	LintNamingEnumValueRuleSpecBuilder,
+	LintNamingFieldRuleSpecBuilder,
+	LintNamingMessageRuleSpecBuilder,
+	LintNamingRPCRuleSpecBuilder,
+	LintNamingServiceRuleSpecBuilder,
+	LintOneofLowerSnakeCaseRuleSpecBuilder,
+	LintPackageDefinedRuleSpecBuilder,
+	LintPackageDirectoryMatchRuleSpecBuilder,
+	LintPackageLowerSnakeCaseRuleSpecBuilder,
+	LintPackageNoImportCycleRuleSpecBuilder,
+	LintPackageSameCsharpNamespaceRuleSpecBuilder,
+	LintPackageSameDirectoryRuleSpecBuilder,
+	LintPackageSameGoPackageRuleSpecBuilder,
+	LintPackageSameJavaMultipleFilesRuleSpecBuilder,
+	LintPackageSameJavaPackageRuleSpecBuilder,
+	LintPackageSamePhpNamespaceRuleSpecBuilder,
+	LintPackageSameRubyPackageRuleSpecBuilder,
+	LintPackageSameSwiftPrefixRuleSpecBuilder,
+	LintPackageVersionSuffixRuleSpecBuilder,
+	LintProtovalidateRuleSpecBuilder,
+	LintRPCNoClientStreamingRuleSpecBuilder,
+	LintRPCNoServerStreamingRuleSpecBuilder,
+	LintRPCPascalCaseRuleSpecBuilder,
+	LintRPCRequestResponseUniqueRuleSpecBuilder,
+	LintRPCRequestStandardNameRuleSpecBuilder,
+	LintRPCResponseStandardNameRuleSpecBuilder,
+	LintReservedRegistryNoReuseRuleSpecBuilder,
+	LintServicePascalCaseRuleSpecBuilder,
+	LintServiceSuffixRuleSpecBuilder,
+	LintStablePackageNoImportUnstableRuleSpecBuilder,
+	LintSyntaxSpecifiedRuleSpecBuilder,
+}
+
+var (
+	breakingEnumNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting an enum breaks generated code that references the enum, and any fields or " +
+			"definitions that use it.",
+		Triggers: []string{
+			"An enum present in a file in the previous schema is not present in the same file in the current schema.",
+		},
+		Remediations: []string{
+			"Keep the enum and mark it with the deprecated option.",
+			"If the enum was moved to another file in the same package, use the PACKAGE category instead of the FILE category.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting the enum LegacyStatus.",
+				Diff: `-enum LegacyStatus {
+-  LEGACY_STATUS_UNSPECIFIED = 0;
+-}`,
+			},
+		},
+	}
+	breakingEnumSameJSONFormatRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The json_format feature controls whether an enum supports the JSON encoding. Disabling it " +
+			"means that JSON produced with the previous schema may no longer be handled by the current schema.",
+		Triggers: []string{
+			"The json_format feature of an enum changes from ALLOW to LEGACY_BEST_EFFORT.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the json_format feature.",
+			"If the JSON encoding is not used, use the WIRE category instead of the WIRE_JSON category.",
+		},
+	}
+	breakingEnumSameTypeRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "An open enum accepts unknown values, while a closed enum treats them as unknown fields. " +
+			"Changing between open and closed changes how existing values are parsed and how generated code behaves.",
+		Triggers: []string{
+			"An enum changes from open to closed or from closed to open, for example by changing the syntax " +
+				"of the file or the enum_type feature.",
+		},
+		Remediations: []string{
+			"Restore the previous enum_type feature or syntax for the enum.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Making an open enum closed in an editions file.",
+				Diff: ` enum Status {
++  option features.enum_type = CLOSED;
+   STATUS_UNSPECIFIED = 0;
+ }`,
+			},
+		},
+	}
+	breakingEnumValueNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting an enum value breaks generated code that references the value. If the number is " +
+			"later reused for a different value, existing clients will interpret it with the wrong meaning.",
+		Triggers: []string{
+			"An enum value present in the previous schema is not present in the current schema.",
+		},
+		Remediations: []string{
+			"Keep the enum value and mark it with the deprecated option.",
+			"If source code compatibility is not a concern, delete the value and reserve its number and name, " +
+				"then use ENUM_VALUE_NO_DELETE_UNLESS_NUMBER_RESERVED or ENUM_VALUE_NO_DELETE_UNLESS_NAME_RESERVED instead.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting the enum value STATUS_PENDING.",
+				Diff: ` enum Status {
+   STATUS_UNSPECIFIED = 0;
+-  STATUS_PENDING = 1;
+   STATUS_DONE = 2;
+ }`,
+			},
+		},
+	}
+	breakingEnumValueNoDeleteUnlessNameReservedRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The JSON and text encodings refer to enum values by name. Reserving the name of a deleted " +
+			"value prevents it from being reused for a value with a different meaning.",
+		Triggers: []string{
+			"An enum value present in the previous schema is not present in the current schema, " +
+				"and its name is not reserved on the enum.",
+		},
+		Remediations: []string{
+			"Reserve the name of the deleted enum value.",
+			"Restore the deleted enum value.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting the enum value STATUS_PENDING and reserving its name.",
+				Diff: ` enum Status {
+   STATUS_UNSPECIFIED = 0;
+-  STATUS_PENDING = 1;
++  reserved "STATUS_PENDING";
+ }`,
+			},
+		},
+	}
+	breakingEnumValueNoDeleteUnlessNumberReservedRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The binary encoding refers to enum values by number. Reserving the number of a deleted " +
+			"value prevents it from being reused for a value with a different meaning.",
+		Triggers: []string{
+			"An enum value present in the previous schema is not present in the current schema, " +
+				"and its number is not reserved on the enum.",
+		},
+		Remediations: []string{
+			"Reserve the number of the deleted enum value.",
+			"Restore the deleted enum value.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting the enum value STATUS_PENDING and reserving its number.",
+				Diff: ` enum Status {
+   STATUS_UNSPECIFIED = 0;
+-  STATUS_PENDING = 1;
++  reserved 1;
+ }`,
+			},
+		},
+	}
+	breakingEnumValueSameNameRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Renaming an enum value breaks generated code that references the value, as well as the " +
+			"JSON and text encodings, which use the name of the value.",
+		Triggers: []string{
+			"The enum value with a given number has a different name than in the previous schema.",
+		},
+		Remediations: []string{
+			"Restore the previous name of the enum value.",
+			"Add the new name as an alias with the allow_alias option, and keep the previous name.",
+			"If only the binary encoding is used and generated code may break, use the WIRE category instead.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming the enum value STATUS_DONE to STATUS_COMPLETE.",
+				Diff: ` enum Status {
+   STATUS_UNSPECIFIED = 0;
+-  STATUS_DONE = 1;
++  STATUS_COMPLETE = 1;
+ }`,
+			},
+		},
+	}
+	breakingExtensionMessageNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting an extension range breaks any extensions that use numbers in the range, " +
+			"including extensions defined in other modules.",
+		Triggers: []string{
+			"An extension range present on a message in the previous schema is not present in the current schema.",
+		},
+		Remediations: []string{
+			"Restore the deleted extension range.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting an extension range.",
+				Diff: ` message Options {
+   string name = 1;
+-  extensions 100 to 199;
+ }`,
+			},
+		},
+	}
+	breakingExtensionNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting an extension breaks generated code that references the extension, and options " +
+			"that set it no longer compile.",
+		Triggers: []string{
+			"An extension present in a file in the previous schema is not present in the same file in the current schema.",
+		},
+		Remediations: []string{
+			"Keep the extension and mark it with the deprecated option.",
+			"If the extension was moved to another file in the same package, use the PACKAGE category instead of the FILE category.",
+		},
+	}
+	breakingFieldNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting a field breaks generated code that references the field. If the field number " +
+			"is later reused with a different type, existing clients will fail to parse or misinterpret messages.",
+		Triggers: []string{
+			"A field number present on a message in the previous schema is not present in the current schema.",
+		},
+		Remediations: []string{
+			"Keep the field and mark it with the deprecated option.",
+			"If source code compatibility is not a concern, delete the field and reserve its number and name, " +
+				"then use FIELD_NO_DELETE_UNLESS_NUMBER_RESERVED or FIELD_NO_DELETE_UNLESS_NAME_RESERVED instead.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting the field email.",
+				Diff: ` message User {
+   string id = 1;
+-  string email = 2;
+ }`,
+			},
+		},
+	}
+	breakingFieldNoDeleteUnlessNameReservedRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The JSON and text encodings refer to fields by name. Reserving the name of a deleted field " +
+			"prevents it from being reused for a field with a different type or meaning.",
+		Triggers: []string{
+			"A field present on a message in the previous schema is not present in the current schema, " +
+				"and its name is not reserved on the message.",
+		},
+		Remediations: []string{
+			"Reserve the name of the deleted field.",
+			"Restore the deleted field.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting the field email and reserving its name.",
+				Diff: ` message User {
+   string id = 1;
+-  string email = 2;
++  reserved "email";
+ }`,
+			},
+		},
+	}
+	breakingFieldNoDeleteUnlessNumberReservedRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Reusing the number of a deleted field for a field with a different type breaks the " +
+			"binary encoding. Reserving the number prevents it from being reused by accident.",
+		Triggers: []string{
+			"A field number present on a message in the previous schema is not present in the current schema, " +
+				"and is not reserved on the message.",
+		},
+		Remediations: []string{
+			"Reserve the number of the deleted field.",
+			"Restore the deleted field.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting the field email and reserving its number.",
+				Diff: ` message User {
+   string id = 1;
+-  string email = 2;
++  reserved 2;
+ }`,
+			},
+		},
+	}
+	breakingFieldSameCTypeRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The ctype option determines the type used for string fields in generated C++ code. " +
+			"Changing it breaks C++ code that uses the field.",
+		Triggers: []string{
+			"The ctype option of a field is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous ctype option.",
+			"Use FIELD_SAME_CPP_STRING_TYPE instead, which replaces this rule and also checks the (pb.cpp).string_type feature.",
+		},
+	}
+	breakingFieldSameCardinalityRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The cardinality of a field determines whether it is optional, required, or repeated, and " +
+			"whether it tracks presence. Changing it changes the generated code and how values are encoded.",
+		Triggers: []string{
+			"A field changes between optional, required, repeated, and map, or between explicit and implicit presence.",
+		},
+		Remediations: []string{
+			"Restore the previous cardinality of the field.",
+			"Add a new field with the new cardinality and a new number, and deprecate the previous field.",
+			"If only wire compatibility matters, use the WIRE category, which checks FIELD_WIRE_COMPATIBLE_CARDINALITY instead.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Making a singular field repeated.",
+				Diff: ` message User {
+-  string email = 1;
++  repeated string email = 1;
+ }`,
+			},
+		},
+	}
+	breakingFieldSameCppStringTypeRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The C++ string type of a field determines the type used in generated C++ code. Changing " +
+			"it breaks C++ code that uses the field.",
+		Triggers: []string{
+			"The ctype option or the (pb.cpp).string_type feature of a field results in a different C++ string type.",
+		},
+		Remediations: []string{
+			"Restore the previous ctype option or (pb.cpp).string_type feature.",
+		},
+	}
+	breakingFieldSameDefaultRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The default value of a field is the value readers see when the field is not set. " +
+			"Changing it changes the meaning of every message that does not set the field.",
+		Triggers: []string{
+			"The default value of a field is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous default value.",
+			"Add a new field with the new default value and a new number, and deprecate the previous field.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Changing the default value of the field page_size.",
+				Diff: ` message ListUsersRequest {
+-  optional int32 page_size = 1 [default = 10];
++  optional int32 page_size = 1 [default = 50];
+ }`,
+			},
+		},
+	}
+	breakingFieldSameJSONNameRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The JSON name of a field is the key used for the field in the JSON encoding. Changing it " +
+			"means that JSON produced with the previous schema can no longer be parsed with the current schema.",
+		Triggers: []string{
+			"The json_name option of a field is added, changed, or removed such that the JSON name differs.",
+			"A field is renamed without setting the json_name option to its previous JSON name.",
+		},
+		Remediations: []string{
+			"Set the json_name option of the field to its previous JSON name.",
+			"If the JSON encoding is not used, use the WIRE category instead of the WIRE_JSON category.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Changing the JSON name of the field display_name.",
+				Diff: ` message User {
+-  string display_name = 1;
++  string display_name = 1 [json_name = "name"];
+ }`,
+			},
+		},
+	}
+	breakingFieldSameJSTypeRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The jstype option determines the type used for 64-bit integer fields in generated " +
+			"JavaScript code. Changing it breaks JavaScript code that uses the field.",
+		Triggers: []string{
+			"The jstype option of a field is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous jstype option.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Changing the JavaScript type of the field id.",
+				Diff: ` message User {
+-  int64 id = 1;
++  int64 id = 1 [jstype = JS_STRING];
+ }`,
+			},
+		},
+	}
+	breakingFieldSameJavaUTF8ValidationRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Java generated code validates UTF-8 in string fields based on this setting. Enabling it " +
+			"causes previously accepted messages to fail to parse in Java.",
+		Triggers: []string{
+			"The java_string_check_utf8 file option or the (pb.java).utf8_validation feature results in " +
+				"different UTF-8 validation for a field in Java.",
+		},
+		Remediations: []string{
+			"Restore the previous java_string_check_utf8 option or (pb.java).utf8_validation feature.",
+		},
+	}
+	breakingFieldSameLabelRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The label of a field determines whether it is optional, required, or repeated. Changing it " +
+			"changes the generated code and how values are encoded.",
+		Triggers: []string{
+			"A field changes between optional, required, and repeated.",
+		},
+		Remediations: []string{
+			"Restore the previous label of the field.",
+			"Use FIELD_SAME_CARDINALITY instead, which replaces this rule and also checks field presence.",
+		},
+	}
+	breakingFieldSameNameRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Renaming a field breaks generated code that references the field, as well as the JSON " +
+			"and text encodings, which use the field name.",
+		Triggers: []string{
+			"The field with a given number has a different name than in the previous schema.",
+		},
+		Remediations: []string{
+			"Restore the previous name of the field.",
+			"Add a new field with the new name and a new number, and deprecate the previous field.",
+			"If only the binary encoding is used and generated code may break, use the WIRE category instead.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming the field email to email_address.",
+				Diff: ` message User {
+-  string email = 1;
++  string email_address = 1;
+ }`,
+			},
+		},
+	}
+	breakingFieldSameOneofRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Fields in a oneof share presence, so setting one clears the others. Moving a field into or " +
+			"out of a oneof changes the generated code and can cause data to be silently dropped.",
+		Triggers: []string{
+			"A field is moved into a oneof, out of a oneof, or into a different oneof.",
+		},
+		Remediations: []string{
+			"Restore the previous oneof of the field.",
+			"Add a new field in the new oneof with a new number, and deprecate the previous field.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Moving the field email into a oneof.",
+				Diff: ` message Contact {
+-  string email = 1;
++  oneof method {
++    string email = 1;
++  }
+ }`,
+			},
+		},
+	}
+	breakingFieldSameTypeRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Changing the type of a field changes how it is encoded. Messages written with the " +
+			"previous type will fail to parse or be misinterpreted by readers using the current type, " +
+			"and generated code that references the field will no longer compile.",
+		Triggers: []string{
+			"The field with a given number has a different type than in the previous schema.",
+			"The field with a given number refers to a different message or enum than in the previous schema.",
+		},
+		Remediations: []string{
+			"Restore the previous type of the field.",
+			"Add a new field with the new type and a new number, and deprecate the previous field.",
+			"If only wire compatibility matters, use the WIRE category, which checks FIELD_WIRE_COMPATIBLE_TYPE instead.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Changing the type of the field id from int32 to string.",
+				Diff: ` message User {
+-  int32 id = 1;
++  string id = 1;
+ }`,
+			},
+			{
+				Description: "Adding a new field instead of changing the type of the existing field.",
+				Diff: ` message User {
+-  int32 id = 1;
++  int32 id = 1 [deprecated = true];
++  string uid = 2;
+ }`,
+			},
+		},
+	}
+	breakingFieldSameUTF8ValidationRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "String fields are validated as UTF-8 based on their utf8_validation feature. Enabling " +
+			"validation causes previously accepted messages to fail to parse.",
+		Triggers: []string{
+			"The utf8_validation feature of a string field changes, for example by changing the syntax of the file.",
+		},
+		Remediations: []string{
+			"Restore the previous utf8_validation feature for the field.",
+		},
+	}
+	breakingFieldWireCompatibleCardinalityRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Some changes to the cardinality of a field are compatible with the binary encoding, such " +
+			"as from optional to repeated for strings. Other changes cause messages to fail to parse or lose data.",
+		Triggers: []string{
+			"A field changes cardinality in a way that is not compatible with the binary encoding, such as " +
+				"from repeated to a map or from a scalar to a packed repeated field.",
+		},
+		Remediations: []string{
+			"Restore the previous cardinality of the field.",
+			"Add a new field with the new cardinality and a new number, and deprecate the previous field.",
+		},
+	}
+	breakingFieldWireCompatibleTypeRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Some changes to the type of a field are compatible with the binary encoding, such as from " +
+			"int32 to int64. Other changes cause messages to fail to parse or be misinterpreted.",
+		Triggers: []string{
+			"A field changes type in a way that is not compatible with the binary encoding, such as from int32 to string.",
+		},
+		Remediations: []string{
+			"Restore the previous type of the field.",
+			"Add a new field with the new type and a new number, and deprecate the previous field.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Changing the type of the field count from int32 to string.",
+				Diff: ` message Page {
+-  int32 count = 1;
++  string count = 1;
+ }`,
+			},
+		},
+	}
+	breakingFieldWireJSONCompatibleCardinalityRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Some changes to the cardinality of a field are compatible with the binary encoding but not " +
+			"with the JSON encoding, which represents repeated fields as arrays.",
+		Triggers: []string{
+			"A field changes cardinality in a way that is not compatible with both the binary and the JSON " +
+				"encodings, such as from optional to repeated.",
+		},
+		Remediations: []string{
+			"Restore the previous cardinality of the field.",
+			"If the JSON encoding is not used, use the WIRE category instead of the WIRE_JSON category.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Making a singular field repeated, which changes its JSON value to an array.",
+				Diff: ` message User {
+-  string email = 1;
++  repeated string email = 1;
+ }`,
+			},
+		},
+	}
+	breakingFieldWireJSONCompatibleTypeRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Some changes to the type of a field are compatible with the binary encoding but not with the " +
+			"JSON encoding, such as from int32 to int64, which is encoded as a JSON string.",
+		Triggers: []string{
+			"A field changes type in a way that is not compatible with both the binary and the JSON encodings.",
+		},
+		Remediations: []string{
+			"Restore the previous type of the field.",
+			"If the JSON encoding is not used, use the WIRE category instead of the WIRE_JSON category.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Changing the type of the field count from int32 to int64.",
+				Diff: ` message Page {
+-  int32 count = 1;
++  int64 count = 1;
+ }`,
+			},
+		},
+	}
+	breakingFileNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting a file breaks any file that imports it, and deletes all definitions in the file.",
+		Triggers: []string{
+			"A file present in the previous schema is not present in the current schema.",
+		},
+		Remediations: []string{
+			"Restore the deleted file.",
+			"If definitions were moved to another file in the same package, use the PACKAGE category instead of the FILE category.",
+		},
+	}
+	breakingFileSameCcEnableArenasRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The cc_enable_arenas option changes the generated C++ code. Changing it can break C++ code " +
+			"that allocates messages on arenas.",
+		Triggers: []string{
+			"The cc_enable_arenas option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the cc_enable_arenas option.",
+		},
+	}
+	breakingFileSameCcGenericServicesRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The cc_generic_services option controls whether generic services are generated in C++. " +
+			"Changing it adds or removes generated code.",
+		Triggers: []string{
+			"The cc_generic_services option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the cc_generic_services option.",
+		},
+	}
+	breakingFileSameCsharpNamespaceRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The csharp_namespace option determines the namespace of the generated C# code. Changing it " +
+			"breaks C# code that references the generated types.",
+		Triggers: []string{
+			"The csharp_namespace option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the csharp_namespace option.",
+			"If the option is managed with managed mode in buf.gen.yaml, remove it from the file.",
+		},
+	}
+	breakingFileSameGoPackageRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The go_package option determines the import path and package name of the generated Go code. " +
+			"Changing it breaks Go code that imports the generated package.",
+		Triggers: []string{
+			"The go_package option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the go_package option.",
+			"If the option is managed with managed mode in buf.gen.yaml, remove it from the file.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Changing the go_package option.",
+				Diff: `-option go_package = "github.com/acme/weather/gen/weatherv1";
++option go_package = "github.com/acme/weather/gen/go/weatherv1";`,
+			},
+		},
+	}
+	breakingFileSameJavaGenericServicesRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The java_generic_services option controls whether generic services are generated in Java. " +
+			"Changing it adds or removes generated code.",
+		Triggers: []string{
+			"The java_generic_services option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the java_generic_services option.",
+		},
+	}
+	breakingFileSameJavaMultipleFilesRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The java_multiple_files option determines whether generated Java types are nested in an outer " +
+			"class or in their own files. Changing it changes the fully-qualified names of the generated classes.",
+		Triggers: []string{
+			"The java_multiple_files option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the java_multiple_files option.",
+			"If the option is managed with managed mode in buf.gen.yaml, remove it from the file.",
+		},
+	}
+	breakingFileSameJavaOuterClassnameRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The java_outer_classname option determines the name of the generated Java outer class. " +
+			"Changing it breaks Java code that references the class.",
+		Triggers: []string{
+			"The java_outer_classname option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the java_outer_classname option.",
+			"If the option is managed with managed mode in buf.gen.yaml, remove it from the file.",
+		},
+	}
+	breakingFileSameJavaPackageRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The java_package option determines the package of the generated Java code. Changing it " +
+			"breaks Java code that imports the generated classes.",
+		Triggers: []string{
+			"The java_package option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the java_package option.",
+			"If the option is managed with managed mode in buf.gen.yaml, remove it from the file.",
+		},
+	}
+	breakingFileSameJavaStringCheckUtf8RuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The java_string_check_utf8 option determines whether generated Java code validates UTF-8 " +
+			"in string fields. Enabling it causes previously accepted messages to fail to parse in Java.",
+		Triggers: []string{
+			"The java_string_check_utf8 option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the java_string_check_utf8 option.",
+			"Use FIELD_SAME_JAVA_UTF8_VALIDATION instead, which replaces this rule and also checks the (pb.java).utf8_validation feature.",
+		},
+	}
+	breakingFileSameObjcClassPrefixRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The objc_class_prefix option determines the prefix of the generated Objective-C classes. " +
+			"Changing it breaks Objective-C code that references the classes.",
+		Triggers: []string{
+			"The objc_class_prefix option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the objc_class_prefix option.",
+			"If the option is managed with managed mode in buf.gen.yaml, remove it from the file.",
+		},
+	}
+	breakingFileSameOptimizeForRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The optimize_for option determines whether full or lite runtime code is generated. " +
+			"Changing it changes the generated code and the runtime it depends on.",
+		Triggers: []string{
+			"The optimize_for option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the optimize_for option.",
+			"If the option is managed with managed mode in buf.gen.yaml, remove it from the file.",
+		},
+	}
+	breakingFileSamePackageRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The package is part of the fully-qualified name of every definition in a file. Changing it " +
+			"renames all definitions, which breaks generated code, references from other files, and Any messages.",
+		Triggers: []string{
+			"The package of a file is different than in the previous schema.",
+		},
+		Remediations: []string{
+			"Restore the previous package of the file.",
+			"Copy the definitions to a new file in the new package, and deprecate the previous file.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Changing the package of a file.",
+				Diff: `-package acme.weather.v1;
++package acme.forecast.v1;`,
+			},
+		},
+	}
+	breakingFileSamePhpClassPrefixRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The php_class_prefix option determines the prefix of the generated PHP classes. Changing it " +
+			"breaks PHP code that references the classes.",
+		Triggers: []string{
+			"The php_class_prefix option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the php_class_prefix option.",
+		},
+	}
+	breakingFileSamePhpGenericServicesRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The php_generic_services option controlled whether generic services were generated in PHP. " +
+			"The option has been removed from protobuf.",
+		Triggers: []string{
+			"The php_generic_services option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the php_generic_services option.",
+		},
+	}
+	breakingFileSamePhpMetadataNamespaceRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The php_metadata_namespace option determines the namespace of the generated PHP metadata " +
+			"classes. Changing it breaks PHP code that references the classes.",
+		Triggers: []string{
+			"The php_metadata_namespace option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the php_metadata_namespace option.",
+		},
+	}
+	breakingFileSamePhpNamespaceRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The php_namespace option determines the namespace of the generated PHP classes. Changing it " +
+			"breaks PHP code that references the classes.",
+		Triggers: []string{
+			"The php_namespace option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the php_namespace option.",
+			"If the option is managed with managed mode in buf.gen.yaml, remove it from the file.",
+		},
+	}
+	breakingFileSamePyGenericServicesRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The py_generic_services option controls whether generic services are generated in Python. " +
+			"Changing it adds or removes generated code.",
+		Triggers: []string{
+			"The py_generic_services option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the py_generic_services option.",
+		},
+	}
+	breakingFileSameRubyPackageRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The ruby_package option determines the module of the generated Ruby code. Changing it " +
+			"breaks Ruby code that references the generated classes.",
+		Triggers: []string{
+			"The ruby_package option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the ruby_package option.",
+			"If the option is managed with managed mode in buf.gen.yaml, remove it from the file.",
+		},
+	}
+	breakingFileSameSwiftPrefixRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The swift_prefix option determines the prefix of the generated Swift types. Changing it " +
+			"breaks Swift code that references the types.",
+		Triggers: []string{
+			"The swift_prefix option of a file is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the swift_prefix option.",
+		},
+	}
+	breakingFileSameSyntaxRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The syntax or edition of a file determines the default features of its definitions, such as " +
+			"field presence, enum openness, and UTF-8 validation. Changing it can change all of these at once.",
+		Triggers: []string{
+			"The syntax or edition of a file is different than in the previous schema.",
+		},
+		Remediations: []string{
+			"Restore the previous syntax of the file.",
+			"When migrating to editions, set features so that the behavior of the previous syntax is kept, " +
+				"as buf format and protoc migration tools do, and ignore this rule for the migration.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Changing the syntax of a file from proto2 to proto3.",
+				Diff: `-syntax = "proto2";
++syntax = "proto3";`,
+			},
+		},
+	}
+	breakingMessageNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting a message breaks generated code and any definitions that reference the message.",
+		Triggers: []string{
+			"A message present in a file in the previous schema is not present in the same file in the current schema.",
+		},
+		Remediations: []string{
+			"Keep the message and mark it with the deprecated option.",
+			"If the message was moved to another file in the same package, use the PACKAGE category instead of the FILE category.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting the message LegacyUser.",
+				Diff: `-message LegacyUser {
+-  string id = 1;
+-}`,
+			},
+		},
+	}
+	breakingMessageNoRemoveStandardDescriptorAccessorRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Setting no_standard_descriptor_accessor removes the descriptor accessor from the generated " +
+			"code, which breaks code that calls it.",
+		Triggers: []string{
+			"The no_standard_descriptor_accessor option of a message changes from false or unset to true.",
+		},
+		Remediations: []string{
+			"Remove the no_standard_descriptor_accessor option, or set it to false.",
+		},
+	}
+	breakingMessageSameJSONFormatRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The json_format feature controls whether a message supports the JSON encoding. Disabling it " +
+			"means that JSON produced with the previous schema may no longer be handled by the current schema.",
+		Triggers: []string{
+			"The json_format feature of a message changes from ALLOW to LEGACY_BEST_EFFORT.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the json_format feature.",
+			"If the JSON encoding is not used, use the WIRE category instead of the WIRE_JSON category.",
+		},
+	}
+	breakingMessageSameMessageSetWireFormatRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The message_set_wire_format option changes the binary encoding of a message. Changing it " +
+			"means messages written with the previous schema cannot be parsed with the current schema.",
+		Triggers: []string{
+			"The message_set_wire_format option of a message is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the message_set_wire_format option.",
+		},
+	}
+	breakingMessageSameRequiredFieldsRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Messages fail to parse if a required field is not set. Adding a required field breaks " +
+			"readers of messages written without it, and deleting one breaks previous readers of new messages.",
+		Triggers: []string{
+			"A required field is added to a message.",
+			"A required field is deleted from a message.",
+		},
+		Remediations: []string{
+			"Add the field as an optional field instead.",
+			"Restore the deleted required field.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Adding a required field.",
+				Diff: ` message User {
+   optional string id = 1;
++  required string email = 2;
+ }`,
+			},
+		},
+	}
+	breakingOneofNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting a oneof breaks generated code that references the oneof, such as code that checks " +
+			"which field of the oneof is set.",
+		Triggers: []string{
+			"A oneof present on a message in the previous schema is not present in the current schema.",
+		},
+		Remediations: []string{
+			"Restore the deleted oneof.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting the oneof method.",
+				Diff: ` message Contact {
+-  oneof method {
+-    string email = 1;
+-  }
+ }`,
+			},
+		},
+	}
+	breakingPackageEnumNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting an enum from a package breaks generated code that references the enum, and any " +
+			"fields or definitions that use it. Moving an enum to another file in the same package is allowed.",
+		Triggers: []string{
+			"An enum present in a package in the previous schema is not present in the same package in the current schema.",
+		},
+		Remediations: []string{
+			"Keep the enum and mark it with the deprecated option.",
+		},
+	}
+	breakingPackageExtensionNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting an extension from a package breaks generated code that references the extension, " +
+			"and options that set it no longer compile. Moving an extension to another file in the same package is allowed.",
+		Triggers: []string{
+			"An extension present in a package in the previous schema is not present in the same package in the current schema.",
+		},
+		Remediations: []string{
+			"Keep the extension and mark it with the deprecated option.",
+		},
+	}
+	breakingPackageMessageNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting a message from a package breaks generated code and any definitions that reference " +
+			"the message. Moving a message to another file in the same package is allowed.",
+		Triggers: []string{
+			"A message present in a package in the previous schema is not present in the same package in the current schema.",
+		},
+		Remediations: []string{
+			"Keep the message and mark it with the deprecated option.",
+		},
+	}
+	breakingPackageNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting a package deletes all of its definitions, which breaks generated code and any " +
+			"files that import the files of the package.",
+		Triggers: []string{
+			"A package present in the previous schema is not present in the current schema.",
+		},
+		Remediations: []string{
+			"Restore the deleted package.",
+			"If the package is known to be unused, ignore the files of the package with breaking.ignore.",
+		},
+	}
+	breakingPackageServiceNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting a service from a package breaks clients that call it and generated code that " +
+			"references it. Moving a service to another file in the same package is allowed.",
+		Triggers: []string{
+			"A service present in a package in the previous schema is not present in the same package in the current schema.",
+		},
+		Remediations: []string{
+			"Keep the service and mark it with the deprecated option.",
+		},
+	}
+	breakingProtovalidateFieldNoAddCELRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A new CEL constraint can reject values that were previously valid, so existing clients " +
+			"that send such values start to fail validation.",
+		Triggers: []string{
+			"A field has a protovalidate CEL constraint that it did not have in the previous schema.",
+		},
+		Remediations: []string{
+			"Remove the added CEL constraint.",
+			"Add a new field with the constraint and a new number, and deprecate the previous field.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Adding a CEL constraint to the field email.",
+				Diff: ` message User {
+-  string email = 1;
++  string email = 1 [(buf.validate.field).cel = {
++    id: "email_domain"
++    expression: "this.endsWith('@acme.com')"
++  }];
+ }`,
+			},
+		},
+	}
+	breakingProtovalidateFieldNoAddRequiredRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Making a field required rejects messages that do not set it, so existing clients that " +
+			"omit the field start to fail validation.",
+		Triggers: []string{
+			"A field has the protovalidate required constraint, and did not have it in the previous schema.",
+		},
+		Remediations: []string{
+			"Remove the added required constraint.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Making the field email required.",
+				Diff: ` message User {
+-  string email = 1;
++  string email = 1 [(buf.validate.field).required = true];
+ }`,
+			},
+		},
+	}
+	breakingProtovalidateFieldNoNarrowRangeRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Narrowing the range of allowed values rejects values that were previously valid, so existing " +
+			"clients that send such values start to fail validation.",
+		Triggers: []string{
+			"A protovalidate bound of a field, such as gte, lte, min_len, or max_items, allows fewer values than in the previous schema.",
+			"A protovalidate bound is added to a field that did not have one.",
+		},
+		Remediations: []string{
+			"Restore the previous bound, or widen it.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Lowering the maximum page size.",
+				Diff: ` message ListUsersRequest {
+-  int32 page_size = 1 [(buf.validate.field).int32.lte = 100];
++  int32 page_size = 1 [(buf.validate.field).int32.lte = 50];
+ }`,
+			},
+		},
+	}
+	breakingProtovalidateFieldNoRemoveEnumValueRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Removing an enum value from the values allowed by protovalidate rejects values that were " +
+			"previously valid, so existing clients that send such values start to fail validation.",
+		Triggers: []string{
+			"A value allowed by the in constraint of an enum field is no longer allowed.",
+			"A value is added to the not_in constraint of an enum field.",
+			"The defined_only constraint is added to an enum field.",
+		},
+		Remediations: []string{
+			"Restore the previous enum constraints of the field.",
+		},
+	}
+	breakingProtovalidateMessageNoAddCELRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A new CEL constraint can reject messages that were previously valid, so existing clients " +
+			"that send such messages start to fail validation.",
+		Triggers: []string{
+			"A message has a protovalidate CEL constraint that it did not have in the previous schema.",
+		},
+		Remediations: []string{
+			"Remove the added CEL constraint.",
+		},
+	}
+	breakingRPCNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting an RPC breaks clients that call it and generated code that references it.",
+		Triggers: []string{
+			"An RPC present on a service in the previous schema is not present in the current schema.",
+		},
+		Remediations: []string{
+			"Keep the RPC and mark it with the deprecated option.",
+			"If the RPC is known to be unused, ignore it with breaking.ignore_symbols.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting the RPC GetLegacyUser.",
+				Diff: ` service UserService {
+   rpc GetUser(GetUserRequest) returns (GetUserResponse);
+-  rpc GetLegacyUser(GetLegacyUserRequest) returns (GetLegacyUserResponse);
+ }`,
+			},
+		},
+	}
+	breakingRPCSameClientStreamingRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Changing whether an RPC is client streaming changes the protocol used to call it, which " +
+			"breaks existing clients and servers and the generated code.",
+		Triggers: []string{
+			"An RPC changes from unary requests to streaming requests, or from streaming requests to unary requests.",
+		},
+		Remediations: []string{
+			"Restore the previous client streaming value of the RPC.",
+			"Add a new RPC with the new streaming value, and deprecate the previous RPC.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Making the requests of an RPC streaming.",
+				Diff: ` service UserService {
+-  rpc CreateUsers(CreateUsersRequest) returns (CreateUsersResponse);
++  rpc CreateUsers(stream CreateUsersRequest) returns (CreateUsersResponse);
+ }`,
+			},
+		},
+	}
+	breakingRPCSameIdempotencyLevelRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The idempotency_level option determines whether clients may retry an RPC or use HTTP GET " +
+			"to call it. Changing it can break clients that rely on the previous level.",
+		Triggers: []string{
+			"The idempotency_level option of an RPC is added, changed, or removed.",
+		},
+		Remediations: []string{
+			"Restore the previous value of the idempotency_level option.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Removing the idempotency level of an RPC.",
+				Diff: ` service UserService {
+-  rpc GetUser(GetUserRequest) returns (GetUserResponse) {
+-    option idempotency_level = NO_SIDE_EFFECTS;
+-  }
++  rpc GetUser(GetUserRequest) returns (GetUserResponse);
+ }`,
+			},
+		},
+	}
+	breakingRPCSameRequestTypeRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Changing the request type of an RPC breaks generated code and causes servers to parse " +
+			"requests from existing clients with the wrong message.",
+		Triggers: []string{
+			"The request type of an RPC is different than in the previous schema.",
+		},
+		Remediations: []string{
+			"Restore the previous request type of the RPC.",
+			"Add a new RPC with the new request type, and deprecate the previous RPC.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Changing the request type of the RPC GetUser.",
+				Diff: ` service UserService {
+-  rpc GetUser(GetUserRequest) returns (GetUserResponse);
++  rpc GetUser(UserID) returns (GetUserResponse);
+ }`,
+			},
+		},
+	}
+	breakingRPCSameResponseTypeRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Changing the response type of an RPC breaks generated code and causes existing clients " +
+			"to parse responses with the wrong message.",
+		Triggers: []string{
+			"The response type of an RPC is different than in the previous schema.",
+		},
+		Remediations: []string{
+			"Restore the previous response type of the RPC.",
+			"Add a new RPC with the new response type, and deprecate the previous RPC.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Changing the response type of the RPC GetUser.",
+				Diff: ` service UserService {
+-  rpc GetUser(GetUserRequest) returns (GetUserResponse);
++  rpc GetUser(GetUserRequest) returns (User);
+ }`,
+			},
+		},
+	}
+	breakingRPCSameServerStreamingRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Changing whether an RPC is server streaming changes the protocol used to call it, which " +
+			"breaks existing clients and servers and the generated code.",
+		Triggers: []string{
+			"An RPC changes from unary responses to streaming responses, or from streaming responses to unary responses.",
+		},
+		Remediations: []string{
+			"Restore the previous server streaming value of the RPC.",
+			"Add a new RPC with the new streaming value, and deprecate the previous RPC.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Making the responses of an RPC streaming.",
+				Diff: ` service UserService {
+-  rpc ListUsers(ListUsersRequest) returns (ListUsersResponse);
++  rpc ListUsers(ListUsersRequest) returns (stream ListUsersResponse);
+ }`,
+			},
+		},
+	}
+	breakingReservedEnumNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Reserved numbers and names prevent enum values from being reused with a different meaning. " +
+			"Deleting a reservation allows a previously used number or name to be reused.",
+		Triggers: []string{
+			"A reserved range or name present on an enum in the previous schema is not present in the current schema.",
+		},
+		Remediations: []string{
+			"Restore the deleted reserved range or name.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting a reserved number.",
+				Diff: ` enum Status {
+   STATUS_UNSPECIFIED = 0;
+-  reserved 1;
+ }`,
+			},
+		},
+	}
+	breakingReservedMessageNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Reserved numbers and names prevent fields from being reused with a different type or meaning. " +
+			"Deleting a reservation allows a previously used number or name to be reused.",
+		Triggers: []string{
+			"A reserved range or name present on a message in the previous schema is not present in the current schema.",
+		},
+		Remediations: []string{
+			"Restore the deleted reserved range or name.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting a reserved number.",
+				Diff: ` message User {
+   string id = 1;
+-  reserved 2;
+ }`,
+			},
+		},
+	}
+	breakingServiceNoDeleteRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Deleting a service breaks clients that call it and generated code that references it.",
+		Triggers: []string{
+			"A service present in a file in the previous schema is not present in the same file in the current schema.",
+		},
+		Remediations: []string{
+			"Keep the service and mark it with the deprecated option.",
+			"If the service was moved to another file in the same package, use the PACKAGE category instead of the FILE category.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Deleting the service LegacyUserService.",
+				Diff: `-service LegacyUserService {
+-  rpc GetUser(GetUserRequest) returns (GetUserResponse);
+-}`,
+			},
+		},
+	}
+	lintCommentEnumRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Comments on definitions are copied to the generated code and shown in generated " +
+			"documentation, so an undocumented enum is undocumented for every consumer of the schema.",
+		Triggers: []string{
+			"An enum does not have a leading comment, or the comment does not meet the configured requirements.",
+		},
+		Remediations: []string{
+			"Add a leading comment to the enum that describes what it represents.",
+		},
+		ConfigKeys: []string{
+			"lint.comments",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Adding a comment to the enum Status.",
+				Diff: `+// Status is the status of an order.
+ enum Status {
+   STATUS_UNSPECIFIED = 0;
+ }`,
+			},
+		},
+	}
+	lintCommentEnumValueRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Comments on definitions are copied to the generated code and shown in generated " +
+			"documentation, so an undocumented enum value is undocumented for every consumer of the schema.",
+		Triggers: []string{
+			"An enum value does not have a leading comment, or the comment does not meet the configured requirements.",
+		},
+		Remediations: []string{
+			"Add a leading comment to the enum value that describes what it means.",
+		},
+		ConfigKeys: []string{
+			"lint.comments",
+		},
+	}
+	lintCommentFieldRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Comments on definitions are copied to the generated code and shown in generated " +
+			"documentation, so an undocumented field is undocumented for every consumer of the schema.",
+		Triggers: []string{
+			"A field does not have a leading comment, or the comment does not meet the configured requirements.",
+		},
+		Remediations: []string{
+			"Add a leading comment to the field that describes what it contains.",
+		},
+		ConfigKeys: []string{
+			"lint.comments",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Adding a comment to the field email.",
+				Diff: ` message User {
++  // The primary email address of the user.
+   string email = 1;
+ }`,
+			},
+		},
+	}
+	lintCommentMessageRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Comments on definitions are copied to the generated code and shown in generated " +
+			"documentation, so an undocumented message is undocumented for every consumer of the schema.",
+		Triggers: []string{
+			"A message does not have a leading comment, or the comment does not meet the configured requirements.",
+		},
+		Remediations: []string{
+			"Add a leading comment to the message that describes what it represents.",
+		},
+		ConfigKeys: []string{
+			"lint.comments",
+		},
+	}
+	lintCommentOneofRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Comments on definitions are copied to the generated code and shown in generated " +
+			"documentation, so an undocumented oneof is undocumented for every consumer of the schema.",
+		Triggers: []string{
+			"A oneof does not have a leading comment, or the comment does not meet the configured requirements.",
+		},
+		Remediations: []string{
+			"Add a leading comment to the oneof that describes what its fields represent.",
+		},
+		ConfigKeys: []string{
+			"lint.comments",
+		},
+	}
+	lintCommentRPCRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Comments on definitions are copied to the generated code and shown in generated " +
+			"documentation, so an undocumented RPC is undocumented for every client of the service.",
+		Triggers: []string{
+			"An RPC does not have a leading comment, or the comment does not meet the configured requirements.",
+		},
+		Remediations: []string{
+			"Add a leading comment to the RPC that describes what it does.",
+		},
+		ConfigKeys: []string{
+			"lint.comments",
+		},
+	}
+	lintCommentServiceRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Comments on definitions are copied to the generated code and shown in generated " +
+			"documentation, so an undocumented service is undocumented for every client of the service.",
+		Triggers: []string{
+			"A service does not have a leading comment, or the comment does not meet the configured requirements.",
+		},
+		Remediations: []string{
+			"Add a leading comment to the service that describes what it does.",
+		},
+		ConfigKeys: []string{
+			"lint.comments",
+		},
+	}
+	lintDirectorySamePackageRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Many languages generate one package per directory. Files in the same directory with " +
+			"different packages generate code that conflicts or does not compile.",
+		Triggers: []string{
+			"A directory contains files with different packages.",
+		},
+		Remediations: []string{
+			"Move the files of each package to their own directory.",
+		},
+	}
+	lintEnumFirstValueZeroRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The first value of an enum is its default value in proto2, and proto3 and open enums " +
+			"require the first value to be zero. Using zero for the first value keeps the default value consistent.",
+		Triggers: []string{
+			"The first value of an enum does not have the number 0.",
+		},
+		Remediations: []string{
+			"Add a value with the number 0 as the first value of the enum, such as ENUM_NAME_UNSPECIFIED.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Adding a zero value to the enum Status.",
+				Diff: ` enum Status {
++  STATUS_UNSPECIFIED = 0;
+   STATUS_ACTIVE = 1;
+ }`,
+			},
+		},
+	}
+	lintEnumNoAllowAliasRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Aliases give one number several names. The JSON and text encodings only use one of them, " +
+			"and some languages generate code that does not handle aliases well.",
+		Triggers: []string{
+			"An enum sets the allow_alias option to true.",
+		},
+		Remediations: []string{
+			"Remove the allow_alias option and the aliased values. Renaming values this way is a breaking change " +
+				"for the JSON encoding.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Removing an alias.",
+				Diff: ` enum Status {
+-  option allow_alias = true;
+   STATUS_UNSPECIFIED = 0;
+   STATUS_DONE = 1;
+-  STATUS_COMPLETE = 1;
+ }`,
+			},
+		},
+	}
+	lintEnumPascalCaseRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Enum names are used to generate type names in many languages. Plugins assume enum names are " +
+			"PascalCase to generate idiomatic names.",
+		Triggers: []string{
+			"An enum name is not PascalCase.",
+		},
+		Remediations: []string{
+			"Rename the enum to PascalCase. This is a breaking change for generated code.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming a snake_case enum.",
+				Diff: `-enum order_status {
++enum OrderStatus {
+   ORDER_STATUS_UNSPECIFIED = 0;
+ }`,
+			},
+		},
+	}
+	lintEnumValuePrefixRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Enum values are scoped to the enclosing package rather than to the enum, as in C++. Prefixing " +
+			"values with the name of the enum prevents conflicts between enums in the same package.",
+		Triggers: []string{
+			"An enum value name does not start with the UPPER_SNAKE_CASE name of its enum.",
+		},
+		Remediations: []string{
+			"Prefix the enum value with the UPPER_SNAKE_CASE name of the enum. This is a breaking change for " +
+				"generated code and the JSON encoding.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Prefixing the values of the enum Status.",
+				Diff: ` enum Status {
+-  UNSPECIFIED = 0;
+-  ACTIVE = 1;
++  STATUS_UNSPECIFIED = 0;
++  STATUS_ACTIVE = 1;
+ }`,
+			},
+		},
+	}
+	lintEnumValueUpperSnakeCaseRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Enum value names are used to generate constant names in many languages. Plugins assume enum " +
+			"value names are UPPER_SNAKE_CASE to generate idiomatic names.",
+		Triggers: []string{
+			"An enum value name is not UPPER_SNAKE_CASE.",
+		},
+		Remediations: []string{
+			"Rename the enum value to UPPER_SNAKE_CASE. This is a breaking change for generated code and the JSON encoding.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming a PascalCase enum value.",
+				Diff: ` enum Status {
+-  StatusUnspecified = 0;
++  STATUS_UNSPECIFIED = 0;
+ }`,
+			},
+		},
+	}
+	lintEnumZeroValueSuffixRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The zero value of an enum is the default value, and is used when the value is not set. " +
+			"A consistent suffix such as _UNSPECIFIED makes it clear that the zero value should not be " +
+			"used to mean anything else.",
+		Triggers: []string{
+			"The enum value with number 0 does not have the configured suffix.",
+		},
+		Remediations: []string{
+			"Rename the zero value to end with the configured suffix, by default _UNSPECIFIED.",
+			"Change the suffix with lint.enum_zero_value_suffix.",
+		},
+		ConfigKeys: []string{
+			"lint.enum_zero_value_suffix",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming the zero value to use the default suffix.",
+				Diff: ` enum Status {
+-  STATUS_NONE = 0;
++  STATUS_UNSPECIFIED = 0;
+ }`,
+			},
+		},
+	}
+	lintExtensionRegistryNoConflictRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Extensions of the same message that use the same number conflict when they are used " +
+			"together. The extension registry records which numbers are allocated to which extensions, " +
+			"including extensions defined in other modules.",
+		Triggers: []string{
+			"An extension uses a number that the extension registry allocates to a different extension of the same message.",
+		},
+		Remediations: []string{
+			"Use a number that is not allocated to another extension of the message.",
+			"If the extension registry is out of date, update the file passed with --extension-registry to allocate the number to this extension.",
+		},
+	}
+	lintFieldLowerSnakeCaseRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Field names are used to generate names in many languages, and for the JSON name of the " +
+			"field. Plugins assume field names are lower_snake_case to generate idiomatic names.",
+		Triggers: []string{
+			"A field name is not lower_snake_case.",
+		},
+		Remediations: []string{
+			"Rename the field to lower_snake_case. This is a breaking change for generated code and the JSON encoding.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming a camelCase field.",
+				Diff: ` message User {
+-  string displayName = 1;
++  string display_name = 1;
+ }`,
+			},
+		},
+	}
+	lintFieldNoDescriptorRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Generated code in some languages has a descriptor accessor on every message. A field " +
+			"named descriptor generates an accessor that conflicts with it.",
+		Triggers: []string{
+			`A field name is any capitalization of "descriptor", with any number of leading or trailing underscores.`,
+		},
+		Remediations: []string{
+			"Rename the field to describe what the descriptor is for, such as file_descriptor.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming the field descriptor.",
+				Diff: ` message Schema {
+-  bytes descriptor = 1;
++  bytes file_descriptor_set = 1;
+ }`,
+			},
+		},
+	}
+	lintFieldNotRequiredRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Messages fail to parse if a required field is not set. Required fields can never be safely " +
+			"removed or made optional, so they make the schema hard to evolve.",
+		Triggers: []string{
+			"A field has the required label, or the LEGACY_REQUIRED field_presence feature.",
+		},
+		Remediations: []string{
+			"Make the field optional, and validate that it is set in application code or with protovalidate.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Making the field id optional.",
+				Diff: ` message User {
+-  required string id = 1;
++  optional string id = 1;
+ }`,
+			},
+		},
+	}
+	lintFileLowerSnakeCaseRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "File names are used to generate file names and import paths in many languages. " +
+			"Plugins assume file names are lower_snake_case to generate idiomatic names.",
+		Triggers: []string{
+			"The name of a file is not lower_snake_case.",
+		},
+		Remediations: []string{
+			"Rename the file to lower_snake_case, and update the imports of the file. This is a breaking change for " +
+				"generated code and files that import it.",
+		},
+	}
+	lintGeneratedNamesCsharpRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The C# plugin generates names from the names of definitions. If two generated names " +
+			"collide, or a name collides with a name reserved by the generated code, the generated code does not compile.",
+		Triggers: []string{
+			"Two definitions generate the same C# identifier.",
+			"A definition generates a C# identifier reserved by the generated code.",
+		},
+		Remediations: []string{
+			"Rename one of the colliding definitions.",
+		},
+	}
+	lintGeneratedNamesGoRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The Go plugin generates names from the names of definitions. If two generated names " +
+			"collide, or a name collides with a name reserved by the generated code, the generated code does not compile.",
+		Triggers: []string{
+			"Two definitions generate the same Go identifier, such as a field foo_bar and a field foo__bar.",
+			"A definition generates a Go identifier reserved by the generated code, such as a field named reset.",
+		},
+		Remediations: []string{
+			"Rename one of the colliding definitions.",
+		},
+	}
+	lintGeneratedNamesJavaRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The Java plugin generates names from the names of definitions. If two generated names " +
+			"collide, or a name collides with a name reserved by the generated code, the generated code does not compile.",
+		Triggers: []string{
+			"Two definitions generate the same Java identifier.",
+			"A definition generates a Java identifier reserved by the generated code, such as a field named class.",
+		},
+		Remediations: []string{
+			"Rename one of the colliding definitions.",
+		},
+	}
+	lintGeneratedNamesPythonRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The Python plugin generates names from the names of definitions. If a generated name is a " +
+			"Python keyword or collides with a name reserved by the generated code, the generated code cannot be used.",
+		Triggers: []string{
+			"Two definitions generate the same Python identifier.",
+			"A definition generates a Python keyword or a name reserved by the generated code, such as a field named from.",
+		},
+		Remediations: []string{
+			"Rename the definition.",
+		},
+	}
+	lintImportNoPublicRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Public imports make the definitions of one file available through another. Many languages " +
+			"do not support public imports, and they make it unclear where a definition comes from.",
+		Triggers: []string{
+			"A file has a public import.",
+		},
+		Remediations: []string{
+			"Make the import a regular import, and import the file directly in the files that use it.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Making a public import a regular import.",
+				Diff: `-import public "acme/weather/v1/weather.proto";
++import "acme/weather/v1/weather.proto";`,
+			},
+		},
+	}
+	lintImportNoWeakRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Weak imports allow a file to be missing at runtime. They are only supported by some " +
+			"languages and are considered deprecated.",
+		Triggers: []string{
+			"A file has a weak import.",
+		},
+		Remediations: []string{
+			"Make the import a regular import.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Making a weak import a regular import.",
+				Diff: `-import weak "acme/weather/v1/weather.proto";
++import "acme/weather/v1/weather.proto";`,
+			},
+		},
+	}
+	lintImportUsedRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Unused imports add dependencies between files that are not needed, and generate unused " +
+			"imports in languages such as Go, where they cause compilation errors.",
+		Triggers: []string{
+			"A file imports a file that none of its definitions use.",
+		},
+		Remediations: []string{
+			"Remove the unused import.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Removing an unused import.",
+				Diff: ` import "acme/weather/v1/weather.proto";
+-import "google/protobuf/timestamp.proto";`,
+			},
+		},
+	}
+	lintMessagePascalCaseRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Message names are used to generate type names in many languages. Plugins assume message " +
+			"names are PascalCase to generate idiomatic names.",
+		Triggers: []string{
+			"A message name is not PascalCase.",
+		},
+		Remediations: []string{
+			"Rename the message to PascalCase. This is a breaking change for generated code.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming a snake_case message.",
+				Diff: `-message user_profile {
++message UserProfile {
+   string id = 1;
+ }`,
+			},
+		},
+	}
+	lintNamingEnumRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A naming pattern enforces conventions that the builtin case rules do not cover, such as " +
+			"a required prefix.",
+		Triggers: []string{
+			"An enum name does not match the pattern set with lint.naming.enum.",
+		},
+		Remediations: []string{
+			"Rename the enum to match the pattern.",
+			"Change the pattern with lint.naming.enum.",
+		},
+		ConfigKeys: []string{
+			"lint.naming.enum",
+		},
+	}
+	lintNamingEnumValueRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A naming pattern enforces conventions that the builtin case rules do not cover, such as " +
+			"a required prefix.",
+		Triggers: []string{
+			"An enum value name does not match the pattern set with lint.naming.enum_value.",
+		},
+		Remediations: []string{
+			"Rename the enum value to match the pattern.",
+			"Change the pattern with lint.naming.enum_value.",
+		},
+		ConfigKeys: []string{
+			"lint.naming.enum_value",
+		},
+	}
+	lintNamingFieldRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A naming pattern enforces conventions that the builtin case rules do not cover, such as " +
+			"a required prefix.",
+		Triggers: []string{
+			"A field name does not match the pattern set with lint.naming.field.",
+		},
+		Remediations: []string{
+			"Rename the field to match the pattern.",
+			"Change the pattern with lint.naming.field.",
+		},
+		ConfigKeys: []string{
+			"lint.naming.field",
+		},
+	}
+	lintNamingMessageRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A naming pattern enforces conventions that the builtin case rules do not cover, such as " +
+			"a required prefix.",
+		Triggers: []string{
+			"A message name does not match the pattern set with lint.naming.message.",
+		},
+		Remediations: []string{
+			"Rename the message to match the pattern.",
+			"Change the pattern with lint.naming.message.",
+		},
+		ConfigKeys: []string{
+			"lint.naming.message",
+		},
+	}
+	lintNamingRPCRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A naming pattern enforces conventions that the builtin case rules do not cover, such as " +
+			"a required verb prefix.",
+		Triggers: []string{
+			"An RPC name does not match the pattern set with lint.naming.rpc.",
+		},
+		Remediations: []string{
+			"Rename the RPC to match the pattern.",
+			"Change the pattern with lint.naming.rpc.",
+		},
+		ConfigKeys: []string{
+			"lint.naming.rpc",
+		},
+	}
+	lintNamingServiceRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A naming pattern enforces conventions that the builtin case rules do not cover, such as " +
+			"a required prefix.",
+		Triggers: []string{
+			"A service name does not match the pattern set with lint.naming.service.",
+		},
+		Remediations: []string{
+			"Rename the service to match the pattern.",
+			"Change the pattern with lint.naming.service.",
+		},
+		ConfigKeys: []string{
+			"lint.naming.service",
+		},
+	}
+	lintOneofLowerSnakeCaseRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Oneof names are used to generate names in many languages. Plugins assume oneof names are " +
+			"lower_snake_case to generate idiomatic names.",
+		Triggers: []string{
+			"A oneof name is not lower_snake_case.",
+		},
+		Remediations: []string{
+			"Rename the oneof to lower_snake_case. This is a breaking change for generated code.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming a camelCase oneof.",
+				Diff: ` message Contact {
+-  oneof contactMethod {
++  oneof contact_method {
+     string email = 1;
+   }
+ }`,
+			},
+		},
+	}
+	lintPackageDefinedRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Definitions in files without a package share a single global namespace, so they can " +
+			"conflict with definitions from any other file without a package.",
+		Triggers: []string{
+			"A file does not have a package declaration.",
+		},
+		Remediations: []string{
+			"Add a package declaration to the file.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Adding a package declaration.",
+				Diff: ` syntax = "proto3";
++
++package acme.weather.v1;`,
+			},
+		},
+	}
+	lintPackageDirectoryMatchRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Many languages generate code in a directory that matches the package. Keeping files in a " +
+			"directory that matches their package makes the schema and the generated code easy to navigate.",
+		Triggers: []string{
+			"A file with package a.b.c is not in the directory a/b/c relative to the root of its module.",
+		},
+		Remediations: []string{
+			"Move the file to the directory that matches its package, and update the imports of the file.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Moving weather.proto with package acme.weather.v1 to a matching directory.",
+				Diff: `-weather/weather.proto
++acme/weather/v1/weather.proto`,
+			},
+		},
+	}
+	lintPackageLowerSnakeCaseRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Packages are used to generate package and namespace names in many languages. Plugins assume " +
+			"the components of packages are lower_snake_case to generate idiomatic names.",
+		Triggers: []string{
+			"A component of a package is not lower_snake_case.",
+		},
+		Remediations: []string{
+			"Rename the package to lower_snake.case. This is a breaking change for generated code.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming a PascalCase package.",
+				Diff: `-package Acme.Weather.v1;
++package acme.weather.v1;`,
+			},
+		},
+	}
+	lintPackageNoImportCycleRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Protobuf does not allow import cycles between files, but does allow them between packages. " +
+			"Languages that generate one package per protobuf package, such as Go, cannot compile such cycles.",
+		Triggers: []string{
+			"A file in one package imports a file in another package that imports the first package, directly or indirectly.",
+		},
+		Remediations: []string{
+			"Move the definitions that both packages use to a separate package.",
+		},
+	}
+	lintPackageSameCsharpNamespaceRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "All files in a package should generate code in the same C# namespace. Different values " +
+			"split the package across namespaces.",
+		Triggers: []string{
+			"Files in the same package have different values for the csharp_namespace option.",
+		},
+		Remediations: []string{
+			"Use the same csharp_namespace option in all files of the package.",
+			"Remove the option from the files, and set it with managed mode in buf.gen.yaml.",
+		},
+	}
+	lintPackageSameDirectoryRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Many languages generate one package per directory. Files in the same package in " +
+			"different directories generate code in different packages.",
+		Triggers: []string{
+			"Files in the same package are in different directories.",
+		},
+		Remediations: []string{
+			"Move all files of the package to the same directory.",
+		},
+	}
+	lintPackageSameGoPackageRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "All files in a package should generate code in the same Go package. Different values " +
+			"split the package across Go packages.",
+		Triggers: []string{
+			"Files in the same package have different values for the go_package option.",
+		},
+		Remediations: []string{
+			"Use the same go_package option in all files of the package.",
+			"Remove the option from the files, and set it with managed mode in buf.gen.yaml.",
+		},
+	}
+	lintPackageSameJavaMultipleFilesRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "All files in a package should generate Java code in the same way. Different values make " +
+			"some types nested in outer classes and others not.",
+		Triggers: []string{
+			"Files in the same package have different values for the java_multiple_files option.",
+		},
+		Remediations: []string{
+			"Use the same java_multiple_files option in all files of the package.",
+			"Remove the option from the files, and set it with managed mode in buf.gen.yaml.",
+		},
+	}
+	lintPackageSameJavaPackageRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "All files in a package should generate code in the same Java package. Different values " +
+			"split the package across Java packages.",
+		Triggers: []string{
+			"Files in the same package have different values for the java_package option.",
+		},
+		Remediations: []string{
+			"Use the same java_package option in all files of the package.",
+			"Remove the option from the files, and set it with managed mode in buf.gen.yaml.",
+		},
+	}
+	lintPackageSamePhpNamespaceRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "All files in a package should generate code in the same PHP namespace. Different values " +
+			"split the package across namespaces.",
+		Triggers: []string{
+			"Files in the same package have different values for the php_namespace option.",
+		},
+		Remediations: []string{
+			"Use the same php_namespace option in all files of the package.",
+			"Remove the option from the files, and set it with managed mode in buf.gen.yaml.",
+		},
+	}
+	lintPackageSameRubyPackageRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "All files in a package should generate code in the same Ruby module. Different values " +
+			"split the package across modules.",
+		Triggers: []string{
+			"Files in the same package have different values for the ruby_package option.",
+		},
+		Remediations: []string{
+			"Use the same ruby_package option in all files of the package.",
+			"Remove the option from the files, and set it with managed mode in buf.gen.yaml.",
+		},
+	}
+	lintPackageSameSwiftPrefixRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "All files in a package should generate Swift types with the same prefix. Different values " +
+			"make the names of types in the same package inconsistent.",
+		Triggers: []string{
+			"Files in the same package have different values for the swift_prefix option.",
+		},
+		Remediations: []string{
+			"Use the same swift_prefix option in all files of the package.",
+		},
+	}
+	lintPackageVersionSuffixRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A version suffix such as v1 lets you make breaking changes in a new package, such as v2, " +
+			"while continuing to support the previous package.",
+		Triggers: []string{
+			"The last component of a package is not a version such as v1, v1beta1, or v1test.",
+		},
+		Remediations: []string{
+			"Add a version suffix to the package. If PACKAGE_DIRECTORY_MATCH is used, also move the files to a matching directory.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Adding a version suffix to the package.",
+				Diff: `-package acme.weather;
++package acme.weather.v1;`,
+			},
+		},
+	}
+	lintProtovalidateRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Invalid protovalidate rules fail at runtime, when messages are validated. Checking them " +
+			"at lint time finds these errors before the schema is used.",
+		Triggers: []string{
+			"A protovalidate rule does not apply to the type of the field, such as a string rule on an int32 field.",
+			"A protovalidate rule has invalid bounds, such as a min_len greater than its max_len.",
+			"A protovalidate CEL expression does not compile.",
+		},
+		Remediations: []string{
+			"Fix the rule as described in the error message.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Fixing bounds where the minimum is greater than the maximum.",
+				Diff: ` message User {
+-  string name = 1 [(buf.validate.field).string = {min_len: 10, max_len: 5}];
++  string name = 1 [(buf.validate.field).string = {min_len: 5, max_len: 10}];
+ }`,
+			},
+		},
+	}
+	lintRPCNoClientStreamingRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Client streaming RPCs are not supported by all clients and transports, such as browsers " +
+			"and HTTP/1.1, and are harder to load balance and retry.",
+		Triggers: []string{
+			"An RPC has a streaming request.",
+		},
+		Remediations: []string{
+			"Use a unary request, for example with a repeated field for batches.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Replacing a client stream with a batch request.",
+				Diff: ` service UserService {
+-  rpc CreateUsers(stream CreateUsersRequest) returns (CreateUsersResponse);
++  rpc BatchCreateUsers(BatchCreateUsersRequest) returns (BatchCreateUsersResponse);
+ }`,
+			},
+		},
+	}
+	lintRPCNoServerStreamingRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Server streaming RPCs are not supported by all clients and transports, and are harder to " +
+			"load balance and retry.",
+		Triggers: []string{
+			"An RPC has a streaming response.",
+		},
+		Remediations: []string{
+			"Use a unary response, for example with pagination.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Replacing a server stream with a paginated response.",
+				Diff: ` service UserService {
+-  rpc ListUsers(ListUsersRequest) returns (stream ListUsersResponse);
++  rpc ListUsers(ListUsersRequest) returns (ListUsersResponse);
+ }`,
+			},
+		},
+	}
+	lintRPCPascalCaseRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "RPC names are used to generate method names in many languages and are part of the path " +
+			"used to call the RPC. Plugins assume RPC names are PascalCase to generate idiomatic names.",
+		Triggers: []string{
+			"An RPC name is not PascalCase.",
+		},
+		Remediations: []string{
+			"Rename the RPC to PascalCase. This is a breaking change for generated code and clients.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming a snake_case RPC.",
+				Diff: ` service UserService {
+-  rpc get_user(GetUserRequest) returns (GetUserResponse);
++  rpc GetUser(GetUserRequest) returns (GetUserResponse);
+ }`,
+			},
+		},
+	}
+	lintRPCRequestResponseUniqueRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A request or response message shared between RPCs cannot have fields added for one RPC " +
+			"without affecting the others.",
+		Triggers: []string{
+			"A message is used as the request or response type of more than one RPC.",
+			"A message is used as both the request and the response type of an RPC.",
+		},
+		Remediations: []string{
+			"Add separate request and response messages for each RPC.",
+			"Allow the same request and response type with lint.rpc_allow_same_request_response.",
+			"Allow google.protobuf.Empty with lint.rpc_allow_google_protobuf_empty_requests and " +
+				"lint.rpc_allow_google_protobuf_empty_responses.",
+		},
+		ConfigKeys: []string{
+			"lint.rpc_allow_same_request_response",
+			"lint.rpc_allow_google_protobuf_empty_requests",
+			"lint.rpc_allow_google_protobuf_empty_responses",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Using separate request messages for each RPC.",
+				Diff: ` service UserService {
+-  rpc GetUser(UserRequest) returns (GetUserResponse);
+-  rpc DeleteUser(UserRequest) returns (DeleteUserResponse);
++  rpc GetUser(GetUserRequest) returns (GetUserResponse);
++  rpc DeleteUser(DeleteUserRequest) returns (DeleteUserResponse);
+ }`,
+			},
+		},
+	}
+	lintRPCRequestStandardNameRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A request message that is specific to each RPC can have fields added to it without " +
+			"affecting other RPCs. Naming it after the RPC makes this relationship clear.",
+		Triggers: []string{
+			"The request type of an RPC is not named MethodNameRequest or ServiceNameMethodNameRequest.",
+		},
+		Remediations: []string{
+			"Add a request message named after the RPC.",
+			"Allow google.protobuf.Empty requests with lint.rpc_allow_google_protobuf_empty_requests.",
+		},
+		ConfigKeys: []string{
+			"lint.rpc_allow_google_protobuf_empty_requests",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Using a request message named after the RPC.",
+				Diff: ` service UserService {
+-  rpc GetUser(UserID) returns (GetUserResponse);
++  rpc GetUser(GetUserRequest) returns (GetUserResponse);
+ }`,
+			},
+		},
+	}
+	lintRPCResponseStandardNameRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A response message that is specific to each RPC can have fields added to it without " +
+			"affecting other RPCs. Naming it after the RPC makes this relationship clear.",
+		Triggers: []string{
+			"The response type of an RPC is not named MethodNameResponse or ServiceNameMethodNameResponse.",
+		},
+		Remediations: []string{
+			"Add a response message named after the RPC.",
+			"Allow google.protobuf.Empty responses with lint.rpc_allow_google_protobuf_empty_responses.",
+		},
+		ConfigKeys: []string{
+			"lint.rpc_allow_google_protobuf_empty_responses",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Using a response message named after the RPC.",
+				Diff: ` service UserService {
+-  rpc GetUser(GetUserRequest) returns (User);
++  rpc GetUser(GetUserRequest) returns (GetUserResponse);
+ }`,
+			},
+		},
+	}
+	lintReservedRegistryNoReuseRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "The reserved registry records the numbers and names of deleted fields and enum values, " +
+			"including those whose reserved statements were later removed. Reusing them with a different " +
+			"meaning causes existing data to be misinterpreted.",
+		Triggers: []string{
+			"A field or enum value uses a number or name recorded in the reserved registry for its message or enum.",
+		},
+		Remediations: []string{
+			"Use a number or name that is not recorded in the reserved registry.",
+			"Add a reserved statement for the recorded number or name.",
+			"If the reserved registry is out of date, update the file passed with --reserved-registry.",
+		},
+	}
+	lintServicePascalCaseRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Service names are used to generate type names in many languages and are part of the path " +
+			"used to call RPCs. Plugins assume service names are PascalCase to generate idiomatic names.",
+		Triggers: []string{
+			"A service name is not PascalCase.",
+		},
+		Remediations: []string{
+			"Rename the service to PascalCase. This is a breaking change for generated code and clients.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming a snake_case service.",
+				Diff: `-service user_service {
++service UserService {
+   rpc GetUser(GetUserRequest) returns (GetUserResponse);
+ }`,
+			},
+		},
+	}
+	lintServiceSuffixRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "A consistent suffix makes services easy to distinguish from messages and enums in " +
+			"generated code.",
+		Triggers: []string{
+			"A service name does not end with the configured suffix.",
+		},
+		Remediations: []string{
+			"Rename the service to end with the configured suffix, by default Service.",
+			"Change the suffix with lint.service_suffix.",
+		},
+		ConfigKeys: []string{
+			"lint.service_suffix",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Renaming a service to use the default suffix.",
+				Diff: `-service Users {
++service UserService {
+   rpc GetUser(GetUserRequest) returns (GetUserResponse);
+ }`,
+			},
+		},
+	}
+	lintStablePackageNoImportUnstableRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Stable packages such as v1 promise not to make breaking changes, while unstable packages " +
+			"such as v1beta1 may. A stable package that imports an unstable package inherits its breaking changes.",
+		Triggers: []string{
+			"A file in a stable versioned package imports a file in an unstable versioned package.",
+		},
+		Remediations: []string{
+			"Move the imported definitions to a stable package.",
+			"Make the importing package unstable.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Importing a stable package instead of an unstable one.",
+				Diff: ` package acme.weather.v1;
+
+-import "acme/location/v1beta1/location.proto";
++import "acme/location/v1/location.proto";`,
+			},
+		},
+	}
+	lintSyntaxSpecifiedRuleExplanation = &bufcheckserverutil.RuleExplanation{
+		Rationale: "Files without a syntax declaration are parsed as proto2, which is rarely what is intended " +
+			"and makes the behavior of the file implicit.",
+		Triggers: []string{
+			"A file does not have a syntax or edition declaration.",
+		},
+		Remediations: []string{
+			"Add a syntax or edition declaration to the top of the file.",
+		},
+		Examples: []*bufcheckserverutil.RuleExample{
+			{
+				Description: "Adding a syntax declaration.",
+				Diff: `+syntax = "proto3";
++
+ package acme.weather.v1;`,
+			},
+		},
+	}
+)
@@ -22,7 +22,9 @@ import (
 	"strconv"
 	"strings"
 
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	"buf.build/go/bufplugin/check"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver/internal/bufbreakingvalidate"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver/internal/bufcheckserverutil"
 	"github.com/bufbuild/buf/private/bufpkg/bufprotosource"
 	"github.com/bufbuild/buf/private/gen/proto/go/google/protobuf"
@@ -514,7 +516,7 @@ func checkFieldNoDeleteWithRules(
 				description = strings.ToLower(description[:1]) + description[1:]
 				responseWriter.AddProtosourceAnnotation(
 					message.Location(),
-					previousMessage.Location(),
+					previousField.Location(),
 					`Previously present %s was deleted%s.`,
 					description,
 					suffix,
@@ -1448,7 +1450,7 @@ func handleBreakingOneofNoDelete(
 			}
 			responseWriter.AddProtosourceAnnotation(
 				message.Location(),
-				previousMessage.Location(),
+				previousOneof.Location(),
 				`Previously present oneof %q on message %q was deleted.`,
 				previousName, message.Name(),
 			)
@@ -1474,11 +1476,11 @@ func handleBreakingRPCNoDelete(
 	if err != nil {
 		return err
 	}
-	for previousName := range previousNameToMethod {
+	for previousName, previousMethod := range previousNameToMethod {
 		if _, ok := nameToMethod[previousName]; !ok {
 			responseWriter.AddProtosourceAnnotation(
 				service.Location(),
-				previousService.Location(),
+				previousMethod.Location(),
 				`Previously present RPC %q on service %q was deleted.`,
 				previousName,
 				service.Name(),
@@ -1838,6 +1840,128 @@ func handleBreakingMessageSameRequiredFields(
 	return nil
 }
 
+// HandleBreakingProtovalidateFieldNoAddCEL is a check function.
+var HandleBreakingProtovalidateFieldNoAddCEL = bufcheckserverutil.NewBreakingFieldPairRuleHandler(handleBreakingProtovalidateFieldNoAddCEL)
+
+func handleBreakingProtovalidateFieldNoAddCEL(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	_ bufcheckserverutil.Request,
+	field bufprotosource.Field,
+	previousField bufprotosource.Field,
+) error {
+	fieldConstraints, previousFieldConstraints, err := getProtovalidateFieldConstraintsPair(field, previousField)
+	if err != nil {
+		return err
+	}
+	addProtovalidateFieldAnnotations(
+		responseWriter,
+		field,
+		previousField,
+		bufbreakingvalidate.FieldAddedCEL(previousFieldConstraints, fieldConstraints),
+	)
+	return nil
+}
+
+// HandleBreakingProtovalidateFieldNoAddRequired is a check function.
+var HandleBreakingProtovalidateFieldNoAddRequired = bufcheckserverutil.NewBreakingFieldPairRuleHandler(handleBreakingProtovalidateFieldNoAddRequired)
+
+func handleBreakingProtovalidateFieldNoAddRequired(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	_ bufcheckserverutil.Request,
+	field bufprotosource.Field,
+	previousField bufprotosource.Field,
+) error {
+	fieldConstraints, previousFieldConstraints, err := getProtovalidateFieldConstraintsPair(field, previousField)
+	if err != nil {
+		return err
+	}
+	if bufbreakingvalidate.FieldAddedRequired(previousFieldConstraints, fieldConstraints) {
+		addProtovalidateFieldAnnotations(
+			responseWriter,
+			field,
+			previousField,
+			[]string{"added (buf.validate.field).required"},
+		)
+	}
+	return nil
+}
+
+// HandleBreakingProtovalidateFieldNoNarrowRange is a check function.
+var HandleBreakingProtovalidateFieldNoNarrowRange = bufcheckserverutil.NewBreakingFieldPairRuleHandler(handleBreakingProtovalidateFieldNoNarrowRange)
+
+func handleBreakingProtovalidateFieldNoNarrowRange(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	_ bufcheckserverutil.Request,
+	field bufprotosource.Field,
+	previousField bufprotosource.Field,
+) error {
+	fieldConstraints, previousFieldConstraints, err := getProtovalidateFieldConstraintsPair(field, previousField)
+	if err != nil {
+		return err
+	}
+	addProtovalidateFieldAnnotations(
+		responseWriter,
+		field,
+		previousField,
+		bufbreakingvalidate.FieldNarrowedRanges(previousFieldConstraints, fieldConstraints),
+	)
+	return nil
+}
+
+// HandleBreakingProtovalidateFieldNoRemoveEnumValue is a check function.
+var HandleBreakingProtovalidateFieldNoRemoveEnumValue = bufcheckserverutil.NewBreakingFieldPairRuleHandler(handleBreakingProtovalidateFieldNoRemoveEnumValue)
+
+func handleBreakingProtovalidateFieldNoRemoveEnumValue(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	_ bufcheckserverutil.Request,
+	field bufprotosource.Field,
+	previousField bufprotosource.Field,
+) error {
+	fieldConstraints, previousFieldConstraints, err := getProtovalidateFieldConstraintsPair(field, previousField)
+	if err != nil {
+		return err
+	}
+	addProtovalidateFieldAnnotations(
+		responseWriter,
+		field,
+		previousField,
+		bufbreakingvalidate.FieldRemovedEnumValues(previousFieldConstraints, fieldConstraints),
+	)
+	return nil
+}
+
+// HandleBreakingProtovalidateMessageNoAddCEL is a check function.
+var HandleBreakingProtovalidateMessageNoAddCEL = bufcheckserverutil.NewBreakingMessagePairRuleHandler(handleBreakingProtovalidateMessageNoAddCEL)
+
+func handleBreakingProtovalidateMessageNoAddCEL(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	_ bufcheckserverutil.Request,
+	message bufprotosource.Message,
+	previousMessage bufprotosource.Message,
+) error {
+	messageDescriptor, err := message.AsDescriptor()
+	if err != nil {
+		return err
+	}
+	previousMessageDescriptor, err := previousMessage.AsDescriptor()
+	if err != nil {
+		return err
+	}
+	for _, description := range bufbreakingvalidate.MessageAddedCEL(
+		bufbreakingvalidate.GetMessageConstraints(previousMessageDescriptor),
+		bufbreakingvalidate.GetMessageConstraints(messageDescriptor),
+	) {
+		responseWriter.AddProtosourceAnnotation(
+			withBackupLocation(message.OptionExtensionLocation(validate.E_Message), message.Location()),
+			withBackupLocation(previousMessage.OptionExtensionLocation(validate.E_Message), previousMessage.Location()),
+			`Message %q %s.`,
+			message.Name(),
+			description,
+		)
+	}
+	return nil
+}
+
 // HandleBreakingReservedEnumNoDelete is a check function.
 var HandleBreakingReservedEnumNoDelete = bufcheckserverutil.NewBreakingEnumPairRuleHandler(handleBreakingReservedEnumNoDelete)
 
@@ -20,6 +20,9 @@ import (
 	"strconv"
 	"strings"
 
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver/internal/bufbreakingvalidate"
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver/internal/bufcheckserverutil"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver/internal/bufcheckserverutil/customfeatures/customfeatures"
 	"github.com/bufbuild/buf/private/bufpkg/bufprotosource"
 	"github.com/bufbuild/buf/private/gen/proto/go/google/protobuf"
@@ -301,6 +304,42 @@ func fieldDescriptionWithName(field bufprotosource.Field, name string) string {
 	return fmt.Sprintf("%s %q%s on message %q", kind, numberString, name, message)
 }
 
+// getProtovalidateFieldConstraintsPair returns the protovalidate constraints that apply to
+// the current and previous field.
+func getProtovalidateFieldConstraintsPair(
+	field bufprotosource.Field,
+	previousField bufprotosource.Field,
+) (*validate.FieldConstraints, *validate.FieldConstraints, error) {
+	fieldDescriptor, err := field.AsDescriptor()
+	if err != nil {
+		return nil, nil, err
+	}
+	previousFieldDescriptor, err := previousField.AsDescriptor()
+	if err != nil {
+		return nil, nil, err
+	}
+	return bufbreakingvalidate.GetFieldConstraints(fieldDescriptor), bufbreakingvalidate.GetFieldConstraints(previousFieldDescriptor), nil
+}
+
+// addProtovalidateFieldAnnotations adds an annotation for each description of a change
+// to the protovalidate constraints of the field.
+func addProtovalidateFieldAnnotations(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	field bufprotosource.Field,
+	previousField bufprotosource.Field,
+	descriptions []string,
+) {
+	for _, description := range descriptions {
+		responseWriter.AddProtosourceAnnotation(
+			withBackupLocation(field.OptionExtensionLocation(validate.E_Field), field.Location()),
+			withBackupLocation(previousField.OptionExtensionLocation(validate.E_Field), previousField.Location()),
+			`%s %s.`,
+			fieldDescription(field),
+			description,
+		)
+	}
+}
+
 func is64bitInteger(fieldType descriptorpb.FieldDescriptorProto_Type) bool {
 	switch fieldType {
 	case descriptorpb.FieldDescriptorProto_TYPE_INT64,
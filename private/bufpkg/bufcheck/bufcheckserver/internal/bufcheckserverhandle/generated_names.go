@@ -0,0 +1,610 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcheckserverhandle
+
+import (
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver/internal/bufcheckserverutil"
+	"github.com/bufbuild/buf/private/bufpkg/bufprotosource"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var (
+	// goReservedMessageMemberNames are the names of the methods protoc-gen-go generates
+	// for every message. protoc-gen-go appends "_" to fields and getters that conflict.
+	//
+	// https://github.com/protocolbuffers/protobuf-go/blob/v1.36.0/compiler/protogen/protogen.go#L1050
+	goReservedMessageMemberNames = map[string]struct{}{
+		"Reset":               {},
+		"String":              {},
+		"ProtoMessage":        {},
+		"ProtoReflect":        {},
+		"Marshal":             {},
+		"Unmarshal":           {},
+		"ExtensionRangeArray": {},
+		"ExtensionMap":        {},
+		"Descriptor":          {},
+	}
+	// javaReservedFieldAccessorNames are the accessor names that conflict with methods of
+	// generated Java messages or java.lang.Object. protoc appends "_" to the accessors of
+	// fields that conflict.
+	//
+	// https://github.com/protocolbuffers/protobuf/blob/v29.0/src/google/protobuf/compiler/java/names.cc
+	javaReservedFieldAccessorNames = map[string]struct{}{
+		"AllFields":                 {},
+		"CachedSize":                {},
+		"Class":                     {},
+		"DefaultInstanceForType":    {},
+		"DescriptorForType":         {},
+		"InitializationErrorString": {},
+		"ParserForType":             {},
+		"SerializedSize":            {},
+		"UnknownFields":             {},
+	}
+	// pythonKeywords are the Python keywords. Generated attributes with these names
+	// can only be accessed with getattr.
+	pythonKeywords = map[string]struct{}{
+		"False":    {},
+		"None":     {},
+		"True":     {},
+		"and":      {},
+		"as":       {},
+		"assert":   {},
+		"async":    {},
+		"await":    {},
+		"break":    {},
+		"class":    {},
+		"continue": {},
+		"def":      {},
+		"del":      {},
+		"elif":     {},
+		"else":     {},
+		"except":   {},
+		"finally":  {},
+		"for":      {},
+		"from":     {},
+		"global":   {},
+		"if":       {},
+		"import":   {},
+		"in":       {},
+		"is":       {},
+		"lambda":   {},
+		"nonlocal": {},
+		"not":      {},
+		"or":       {},
+		"pass":     {},
+		"raise":    {},
+		"return":   {},
+		"try":      {},
+		"while":    {},
+		"with":     {},
+		"yield":    {},
+	}
+	// pythonReservedMessageMemberNames are the attributes of every generated Python
+	// message class, which fields must not shadow.
+	pythonReservedMessageMemberNames = map[string]struct{}{
+		"ByteSize":                 {},
+		"Clear":                    {},
+		"ClearExtension":           {},
+		"ClearField":               {},
+		"CopyFrom":                 {},
+		"DESCRIPTOR":               {},
+		"DiscardUnknownFields":     {},
+		"Extensions":               {},
+		"FindInitializationErrors": {},
+		"FromString":               {},
+		"HasExtension":             {},
+		"HasField":                 {},
+		"IsInitialized":            {},
+		"ListFields":               {},
+		"MergeFrom":                {},
+		"MergeFromString":          {},
+		"ParseFromString":          {},
+		"RegisterExtension":        {},
+		"SerializePartialToString": {},
+		"SerializeToString":        {},
+		"SetInParent":              {},
+		"UnknownFields":            {},
+		"WhichOneof":               {},
+	}
+	// csharpReservedPropertyNames are the property names that conflict with members of
+	// every generated C# message class. protoc appends "_" to properties that conflict, as
+	// well as to properties with the same name as their containing message.
+	//
+	// https://github.com/protocolbuffers/protobuf/blob/v29.0/src/google/protobuf/compiler/csharp/names.cc
+	csharpReservedPropertyNames = map[string]struct{}{
+		"Descriptor": {},
+		"Types":      {},
+	}
+)
+
+// generatedName is an identifier that a code generator generates for a descriptor.
+type generatedName struct {
+	// name is the generated identifier.
+	name string
+	// descriptor is the descriptor the identifier is generated for.
+	descriptor bufprotosource.NamedDescriptor
+	// descriptorType is the type of the descriptor, such as "field".
+	descriptorType string
+}
+
+// checkGeneratedNames adds an annotation for each generated name that collides with the
+// generated name of a different descriptor that comes earlier in generatedNames, or that
+// is one of keywords or reservedNames.
+//
+// At most one annotation is added per pair of colliding descriptors.
+func checkGeneratedNames(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	language string,
+	generatedNames []generatedName,
+	keywords map[string]struct{},
+	reservedNames map[string]struct{},
+) {
+	nameToFirstGeneratedName := make(map[string]generatedName, len(generatedNames))
+	seenCollisions := make(map[[2]string]struct{})
+	for _, generatedName := range generatedNames {
+		if _, ok := keywords[generatedName.name]; ok {
+			collision := [2]string{generatedName.descriptor.FullName(), generatedName.name}
+			if _, ok := seenCollisions[collision]; !ok {
+				seenCollisions[collision] = struct{}{}
+				responseWriter.AddProtosourceAnnotation(
+					generatedName.descriptor.NameLocation(),
+					nil,
+					`%s identifier %q generated for %s %q is a %s keyword.`,
+					language,
+					generatedName.name,
+					generatedName.descriptorType,
+					generatedName.descriptor.FullName(),
+					language,
+				)
+			}
+			continue
+		}
+		if _, ok := reservedNames[generatedName.name]; ok {
+			collision := [2]string{generatedName.descriptor.FullName(), generatedName.name}
+			if _, ok := seenCollisions[collision]; !ok {
+				seenCollisions[collision] = struct{}{}
+				responseWriter.AddProtosourceAnnotation(
+					generatedName.descriptor.NameLocation(),
+					nil,
+					`%s identifier %q generated for %s %q conflicts with a name reserved by the generated code.`,
+					language,
+					generatedName.name,
+					generatedName.descriptorType,
+					generatedName.descriptor.FullName(),
+				)
+			}
+			continue
+		}
+		firstGeneratedName, ok := nameToFirstGeneratedName[generatedName.name]
+		if !ok {
+			nameToFirstGeneratedName[generatedName.name] = generatedName
+			continue
+		}
+		if firstGeneratedName.descriptor.FullName() == generatedName.descriptor.FullName() {
+			continue
+		}
+		collision := [2]string{firstGeneratedName.descriptor.FullName(), generatedName.descriptor.FullName()}
+		if _, ok := seenCollisions[collision]; ok {
+			continue
+		}
+		seenCollisions[collision] = struct{}{}
+		responseWriter.AddProtosourceAnnotation(
+			generatedName.descriptor.NameLocation(),
+			nil,
+			`%s identifier %q generated for %s %q collides with the identifier generated for %s %q.`,
+			language,
+			generatedName.name,
+			generatedName.descriptorType,
+			generatedName.descriptor.FullName(),
+			firstGeneratedName.descriptorType,
+			firstGeneratedName.descriptor.FullName(),
+		)
+	}
+}
+
+// goMessageMemberNames returns the names of the struct fields and getters that
+// protoc-gen-go generates for the fields and oneofs of the message.
+func goMessageMemberNames(message bufprotosource.Message) []generatedName {
+	var generatedNames []generatedName
+	seenOneofNames := make(map[string]struct{})
+	for _, field := range message.Fields() {
+		goName := goCamelCase(field.Name())
+		if oneof := field.Oneof(); oneof != nil && !field.Proto3Optional() {
+			// Fields in a oneof are stored in a single struct field for the oneof.
+			if _, ok := seenOneofNames[oneof.Name()]; !ok {
+				seenOneofNames[oneof.Name()] = struct{}{}
+				oneofGoName := goCamelCase(oneof.Name())
+				generatedNames = append(
+					generatedNames,
+					generatedName{name: oneofGoName, descriptor: oneof, descriptorType: "oneof"},
+					generatedName{name: "Get" + oneofGoName, descriptor: oneof, descriptorType: "oneof"},
+				)
+			}
+		} else {
+			generatedNames = append(generatedNames, generatedName{name: goName, descriptor: field, descriptorType: "field"})
+		}
+		generatedNames = append(generatedNames, generatedName{name: "Get" + goName, descriptor: field, descriptorType: "field"})
+	}
+	return generatedNames
+}
+
+// goPackageLevelNames returns the names of the package-level types, constants and
+// variables that protoc-gen-go generates for the messages and enums in the files.
+func goPackageLevelNames(files []bufprotosource.File) ([]generatedName, error) {
+	var generatedNames []generatedName
+	for _, file := range files {
+		if err := bufprotosource.ForEachMessage(
+			func(message bufprotosource.Message) error {
+				if message.IsMapEntry() {
+					return nil
+				}
+				messageGoName := goCamelCase(message.NestedName())
+				generatedNames = append(generatedNames, generatedName{name: messageGoName, descriptor: message, descriptorType: "message"})
+				for _, field := range message.Fields() {
+					if field.Oneof() != nil && !field.Proto3Optional() {
+						// The wrapper type for a field in a oneof.
+						generatedNames = append(
+							generatedNames,
+							generatedName{name: messageGoName + "_" + goCamelCase(field.Name()), descriptor: field, descriptorType: "field"},
+						)
+					}
+				}
+				return nil
+			},
+			file,
+		); err != nil {
+			return nil, err
+		}
+		if err := bufprotosource.ForEachEnum(
+			func(enum bufprotosource.Enum) error {
+				enumGoName := goCamelCase(enum.NestedName())
+				generatedNames = append(
+					generatedNames,
+					generatedName{name: enumGoName, descriptor: enum, descriptorType: "enum"},
+					generatedName{name: enumGoName + "_name", descriptor: enum, descriptorType: "enum"},
+					generatedName{name: enumGoName + "_value", descriptor: enum, descriptorType: "enum"},
+				)
+				// Values of nested enums are prefixed with the name of the parent message
+				// instead of the name of the enum.
+				valuePrefix := enumGoName
+				if parent := enum.Parent(); parent != nil {
+					valuePrefix = goCamelCase(parent.NestedName())
+				}
+				for _, enumValue := range enum.Values() {
+					generatedNames = append(
+						generatedNames,
+						generatedName{name: valuePrefix + "_" + enumValue.Name(), descriptor: enumValue, descriptorType: "enum value"},
+					)
+				}
+				return nil
+			},
+			file,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return generatedNames, nil
+}
+
+// javaMessageMemberNames returns the accessor base names that protoc generates for the
+// fields and oneofs of the message in Java, for example "FooList" for getFooList().
+func javaMessageMemberNames(message bufprotosource.Message) []generatedName {
+	var generatedNames []generatedName
+	seenOneofNames := make(map[string]struct{})
+	for _, field := range message.Fields() {
+		javaName := underscoresToCamelCase(field.Name(), true)
+		generatedNames = append(generatedNames, generatedName{name: javaName, descriptor: field, descriptorType: "field"})
+		var suffixes []string
+		switch {
+		case isMapField(field):
+			suffixes = []string{"Map", "Count"}
+		case field.Label() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED:
+			suffixes = []string{"List", "Count"}
+			if isMessageField(field) {
+				suffixes = append(suffixes, "OrBuilder", "OrBuilderList")
+			}
+		case field.Type() == descriptorpb.FieldDescriptorProto_TYPE_STRING:
+			suffixes = []string{"Bytes"}
+		case isMessageField(field):
+			suffixes = []string{"OrBuilder"}
+		}
+		for _, suffix := range suffixes {
+			generatedNames = append(generatedNames, generatedName{name: javaName + suffix, descriptor: field, descriptorType: "field"})
+		}
+		if oneof := field.Oneof(); oneof != nil && !field.Proto3Optional() {
+			if _, ok := seenOneofNames[oneof.Name()]; !ok {
+				seenOneofNames[oneof.Name()] = struct{}{}
+				oneofJavaName := underscoresToCamelCase(oneof.Name(), true)
+				generatedNames = append(
+					generatedNames,
+					generatedName{name: oneofJavaName, descriptor: oneof, descriptorType: "oneof"},
+					generatedName{name: oneofJavaName + "Case", descriptor: oneof, descriptorType: "oneof"},
+				)
+			}
+		}
+	}
+	return generatedNames
+}
+
+// javaDefaultOuterClassname returns the outer class name protoc generates in Java
+// for the file if java_outer_classname is not set, before resolving conflicts.
+func javaDefaultOuterClassname(file bufprotosource.File) string {
+	return underscoresToCamelCase(strings.TrimSuffix(normalpath.Base(file.Path()), ".proto"), true)
+}
+
+// pythonModuleLevelNames returns the names of the module-level attributes protoc
+// generates for the top-level descriptors of the file in Python.
+func pythonModuleLevelNames(file bufprotosource.File) []generatedName {
+	return pythonContainerNames(file)
+}
+
+// pythonMessageMemberNames returns the names of the class attributes protoc generates
+// for the fields and nested descriptors of the message in Python.
+func pythonMessageMemberNames(message bufprotosource.Message) []generatedName {
+	var generatedNames []generatedName
+	for _, field := range message.Fields() {
+		generatedNames = append(
+			generatedNames,
+			generatedName{name: field.Name(), descriptor: field, descriptorType: "field"},
+			generatedName{name: strings.ToUpper(field.Name()) + "_FIELD_NUMBER", descriptor: field, descriptorType: "field"},
+		)
+	}
+	return append(generatedNames, pythonContainerNames(message)...)
+}
+
+// pythonContainerNames returns the names of the attributes protoc generates in Python
+// for the messages, enums, enum values and extensions directly within the container.
+func pythonContainerNames(containerDescriptor bufprotosource.ContainerDescriptor) []generatedName {
+	var generatedNames []generatedName
+	for _, message := range containerDescriptor.Messages() {
+		if message.IsMapEntry() {
+			continue
+		}
+		generatedNames = append(generatedNames, generatedName{name: message.Name(), descriptor: message, descriptorType: "message"})
+	}
+	for _, enum := range containerDescriptor.Enums() {
+		generatedNames = append(generatedNames, generatedName{name: enum.Name(), descriptor: enum, descriptorType: "enum"})
+		// Enum values are also attributes of the module or class containing the enum.
+		for _, enumValue := range enum.Values() {
+			generatedNames = append(generatedNames, generatedName{name: enumValue.Name(), descriptor: enumValue, descriptorType: "enum value"})
+		}
+	}
+	for _, extension := range containerDescriptor.Extensions() {
+		generatedNames = append(
+			generatedNames,
+			generatedName{name: extension.Name(), descriptor: extension, descriptorType: "extension"},
+			generatedName{name: strings.ToUpper(extension.Name()) + "_FIELD_NUMBER", descriptor: extension, descriptorType: "extension"},
+		)
+	}
+	return generatedNames
+}
+
+// csharpMessageMemberNames returns the names of the properties, constants and nested
+// enums protoc generates for the fields and oneofs of the message in C#.
+func csharpMessageMemberNames(message bufprotosource.Message) []generatedName {
+	var generatedNames []generatedName
+	seenOneofNames := make(map[string]struct{})
+	for _, field := range message.Fields() {
+		csharpName := underscoresToCamelCase(field.Name(), true)
+		generatedNames = append(
+			generatedNames,
+			generatedName{name: csharpName, descriptor: field, descriptorType: "field"},
+			generatedName{name: csharpName + "FieldNumber", descriptor: field, descriptorType: "field"},
+		)
+		if oneof := field.Oneof(); oneof != nil && !field.Proto3Optional() {
+			if _, ok := seenOneofNames[oneof.Name()]; !ok {
+				seenOneofNames[oneof.Name()] = struct{}{}
+				oneofCsharpName := underscoresToCamelCase(oneof.Name(), true)
+				generatedNames = append(
+					generatedNames,
+					generatedName{name: oneofCsharpName + "Case", descriptor: oneof, descriptorType: "oneof"},
+					generatedName{name: oneofCsharpName + "OneofCase", descriptor: oneof, descriptorType: "oneof"},
+				)
+			}
+		}
+	}
+	return generatedNames
+}
+
+// csharpEnumValueNames returns the names protoc generates for the values of the enum
+// in C#, which strip the enum name as a prefix and convert to PascalCase.
+func csharpEnumValueNames(enum bufprotosource.Enum) []generatedName {
+	generatedNames := make([]generatedName, 0, len(enum.Values()))
+	for _, enumValue := range enum.Values() {
+		generatedNames = append(
+			generatedNames,
+			generatedName{name: csharpEnumValueName(enum.Name(), enumValue.Name()), descriptor: enumValue, descriptorType: "enum value"},
+		)
+	}
+	return generatedNames
+}
+
+// goCamelCase converts the name to a Go identifier the same way protoc-gen-go does.
+//
+// https://github.com/protocolbuffers/protobuf-go/blob/v1.36.0/internal/strs/strings.go#L30
+func goCamelCase(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// Skip over '.' in ".{{lowercase}}".
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			// Convert initial '_' to ensure we start with a capital letter.
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// Skip over '_' in "_{{lowercase}}".
+		case isASCIIDigit(c):
+			b = append(b, c)
+		default:
+			// The next word is a sequence of characters that must start upper case.
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			// Accept lower case sequence that follows.
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+// underscoresToCamelCase converts the name to camel case the same way protoc does
+// for Java and C#.
+//
+// https://github.com/protocolbuffers/protobuf/blob/v29.0/src/google/protobuf/compiler/java/names.cc
+func underscoresToCamelCase(s string, capitalizeNextLetter bool) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isASCIILower(c):
+			if capitalizeNextLetter {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			capitalizeNextLetter = false
+		case isASCIIUpper(c):
+			if i == 0 && !capitalizeNextLetter {
+				// Force first letter to lower case unless explicitly told to capitalize it.
+				c += 'a' - 'A'
+			}
+			b = append(b, c)
+			capitalizeNextLetter = false
+		case isASCIIDigit(c):
+			b = append(b, c)
+			capitalizeNextLetter = true
+		default:
+			capitalizeNextLetter = true
+		}
+	}
+	return string(b)
+}
+
+// csharpEnumValueName returns the name protoc generates for the enum value in C#.
+//
+// https://github.com/protocolbuffers/protobuf/blob/v29.0/src/google/protobuf/compiler/csharp/names.cc
+func csharpEnumValueName(enumName string, enumValueName string) string {
+	name := shoutyToPascalCase(tryRemovePrefix(enumName, enumValueName))
+	if name != "" && isASCIIDigit(name[0]) {
+		name = "_" + name
+	}
+	return name
+}
+
+// tryRemovePrefix removes the prefix from the value, ignoring case and underscores.
+// If the value does not have the prefix, or nothing would remain, the value is returned as-is.
+func tryRemovePrefix(prefix string, value string) string {
+	prefixToMatch := strings.ToLower(strings.ReplaceAll(prefix, "_", ""))
+	prefixIndex, valueIndex := 0, 0
+	for ; prefixIndex < len(prefixToMatch) && valueIndex < len(value); valueIndex++ {
+		if value[valueIndex] == '_' {
+			continue
+		}
+		if toASCIILower(value[valueIndex]) != prefixToMatch[prefixIndex] {
+			return value
+		}
+		prefixIndex++
+	}
+	if prefixIndex < len(prefixToMatch) {
+		return value
+	}
+	for valueIndex < len(value) && value[valueIndex] == '_' {
+		valueIndex++
+	}
+	if valueIndex == len(value) {
+		return value
+	}
+	return value[valueIndex:]
+}
+
+// shoutyToPascalCase converts a SHOUTY_CASE name to PascalCase.
+func shoutyToPascalCase(s string) string {
+	var b []byte
+	previous := byte('_')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !isASCIIAlphanumeric(c) {
+			previous = c
+			continue
+		}
+		switch {
+		case !isASCIIAlphanumeric(previous), isASCIIDigit(previous):
+			b = append(b, toASCIIUpper(c))
+		case isASCIILower(previous):
+			b = append(b, c)
+		default:
+			b = append(b, toASCIILower(c))
+		}
+		previous = c
+	}
+	return string(b)
+}
+
+func isMapField(field bufprotosource.Field) bool {
+	parentMessage := field.ParentMessage()
+	if parentMessage == nil || field.Label() != descriptorpb.FieldDescriptorProto_LABEL_REPEATED || !isMessageField(field) {
+		return false
+	}
+	typeName := strings.TrimPrefix(field.TypeName(), ".")
+	for _, nestedMessage := range parentMessage.Messages() {
+		if nestedMessage.IsMapEntry() && nestedMessage.FullName() == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+func isMessageField(field bufprotosource.Field) bool {
+	return field.Type() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE ||
+		field.Type() == descriptorpb.FieldDescriptorProto_TYPE_GROUP
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isASCIIUpper(c byte) bool {
+	return 'A' <= c && c <= 'Z'
+}
+
+func isASCIIDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isASCIIAlphanumeric(c byte) bool {
+	return isASCIILower(c) || isASCIIUpper(c) || isASCIIDigit(c)
+}
+
+func toASCIILower(c byte) byte {
+	if isASCIIUpper(c) {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+func toASCIIUpper(c byte) byte {
+	if isASCIILower(c) {
+		return c - ('a' - 'A')
+	}
+	return c
+}
@@ -18,8 +18,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"buf.build/go/bufplugin/check"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver/internal/bufcheckserverutil"
@@ -148,13 +150,42 @@ func handleLintCommentNamedDescriptor(
 	if err != nil {
 		return err
 	}
-	if !validLeadingComment(commentExcludes, location.LeadingComments()) {
+	commentMinLength, err := bufcheckopt.GetCommentMinLength(request.Options())
+	if err != nil {
+		return err
+	}
+	commentRequireNamePrefix, err := bufcheckopt.GetCommentRequireNamePrefix(request.Options())
+	if err != nil {
+		return err
+	}
+	name := namedDescriptor.Name()
+	commentText := leadingCommentText(commentExcludes, location.LeadingComments())
+	switch {
+	case commentText == "":
 		responseWriter.AddProtosourceAnnotation(
 			location,
 			nil,
 			"%s %q should have a non-empty comment for documentation.",
 			typeName,
-			namedDescriptor.Name(),
+			name,
+		)
+	case utf8.RuneCountInString(commentText) < commentMinLength:
+		responseWriter.AddProtosourceAnnotation(
+			location,
+			nil,
+			"%s %q should have a comment of at least %d characters for documentation.",
+			typeName,
+			name,
+			commentMinLength,
+		)
+	case commentRequireNamePrefix && !strings.HasPrefix(commentText, name):
+		responseWriter.AddProtosourceAnnotation(
+			location,
+			nil,
+			"%s %q should have a comment that starts with %q for documentation.",
+			typeName,
+			name,
+			name,
 		)
 	}
 	return nil
@@ -341,6 +372,51 @@ func handleLintEnumZeroValueSuffix(
 	return nil
 }
 
+// HandleLintExtensionRegistryNoConflict is a handle function.
+var HandleLintExtensionRegistryNoConflict = bufcheckserverutil.NewLintFilesRuleHandler(handleLintExtensionRegistryNoConflict)
+
+func handleLintExtensionRegistryNoConflict(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	files []bufprotosource.File,
+) error {
+	extensionRegistry, err := bufcheckopt.GetExtensionRegistry(request.Options())
+	if err != nil {
+		return err
+	}
+	if extensionRegistry == nil {
+		return nil
+	}
+	extendeeToNumberToNames := newExtendeeToNumberToNames(extensionRegistry.Extendees())
+	for _, file := range files {
+		if err := bufprotosource.ForEachExtension(
+			func(extension bufprotosource.Field) error {
+				// Extendees are fully-qualified with a leading dot.
+				extendee := strings.TrimPrefix(extension.Extendee(), ".")
+				fullName := extension.FullName()
+				allocatedNames := extendeeToNumberToNames[extendee][int32(extension.Number())]
+				if len(allocatedNames) == 0 || slices.Contains(allocatedNames, fullName) {
+					return nil
+				}
+				responseWriter.AddProtosourceAnnotation(
+					extension.NumberLocation(),
+					nil,
+					"Extension %q uses number %d of %q, which is allocated to %s in the extension registry.",
+					fullName,
+					extension.Number(),
+					extendee,
+					stringutil.SliceToHumanStringQuoted(allocatedNames),
+				)
+				return nil
+			},
+			file,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // HandleLintFieldLowerSnakeCase is a handle function.
 var HandleLintFieldLowerSnakeCase = bufcheckserverutil.NewLintFieldRuleHandler(handleLintFieldLowerSnakeCase)
 
@@ -441,6 +517,148 @@ func handleLintFileLowerSnakeCase(
 	return nil
 }
 
+// HandleLintGeneratedNamesCsharp is a handle function.
+var HandleLintGeneratedNamesCsharp = bufcheckserverutil.NewLintFileRuleHandler(handleLintGeneratedNamesCsharp)
+
+func handleLintGeneratedNamesCsharp(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	_ bufcheckserverutil.Request,
+	file bufprotosource.File,
+) error {
+	if err := bufprotosource.ForEachMessage(
+		func(message bufprotosource.Message) error {
+			if message.IsMapEntry() {
+				return nil
+			}
+			// Properties with the same name as their containing message are renamed as well.
+			reservedNames := map[string]struct{}{
+				message.Name(): {},
+			}
+			for reservedName := range csharpReservedPropertyNames {
+				reservedNames[reservedName] = struct{}{}
+			}
+			checkGeneratedNames(responseWriter, "C#", csharpMessageMemberNames(message), nil, reservedNames)
+			return nil
+		},
+		file,
+	); err != nil {
+		return err
+	}
+	return bufprotosource.ForEachEnum(
+		func(enum bufprotosource.Enum) error {
+			checkGeneratedNames(responseWriter, "C#", csharpEnumValueNames(enum), nil, nil)
+			return nil
+		},
+		file,
+	)
+}
+
+// HandleLintGeneratedNamesGo is a handle function.
+var HandleLintGeneratedNamesGo = bufcheckserverutil.NewLintFilesRuleHandler(handleLintGeneratedNamesGo)
+
+func handleLintGeneratedNamesGo(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	_ bufcheckserverutil.Request,
+	files []bufprotosource.File,
+) error {
+	// Files with the same package are generated into the same Go package, assuming
+	// PACKAGE_SAME_GO_PACKAGE is satisfied.
+	pkgToFiles := slicesext.ToValuesMap(
+		files,
+		func(file bufprotosource.File) string {
+			return file.Package()
+		},
+	)
+	for _, pkg := range slicesext.MapKeysToSortedSlice(pkgToFiles) {
+		generatedNames, err := goPackageLevelNames(pkgToFiles[pkg])
+		if err != nil {
+			return err
+		}
+		checkGeneratedNames(responseWriter, "Go", generatedNames, nil, nil)
+	}
+	for _, file := range files {
+		if err := bufprotosource.ForEachMessage(
+			func(message bufprotosource.Message) error {
+				if message.IsMapEntry() {
+					return nil
+				}
+				checkGeneratedNames(responseWriter, "Go", goMessageMemberNames(message), nil, goReservedMessageMemberNames)
+				return nil
+			},
+			file,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleLintGeneratedNamesJava is a handle function.
+var HandleLintGeneratedNamesJava = bufcheckserverutil.NewLintFileRuleHandler(handleLintGeneratedNamesJava)
+
+func handleLintGeneratedNamesJava(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	_ bufcheckserverutil.Request,
+	file bufprotosource.File,
+) error {
+	if file.JavaOuterClassname() == "" {
+		outerClassname := javaDefaultOuterClassname(file)
+		var topLevelDescriptors []bufprotosource.NamedDescriptor
+		for _, message := range file.Messages() {
+			topLevelDescriptors = append(topLevelDescriptors, message)
+		}
+		for _, enum := range file.Enums() {
+			topLevelDescriptors = append(topLevelDescriptors, enum)
+		}
+		for _, service := range file.Services() {
+			topLevelDescriptors = append(topLevelDescriptors, service)
+		}
+		for _, topLevelDescriptor := range topLevelDescriptors {
+			if topLevelDescriptor.Name() == outerClassname {
+				responseWriter.AddProtosourceAnnotation(
+					topLevelDescriptor.NameLocation(),
+					nil,
+					`Java outer class name %q generated for file %q collides with the class generated for %q. Set the java_outer_classname option to avoid the generated outer class name being changed.`,
+					outerClassname,
+					file.Path(),
+					topLevelDescriptor.FullName(),
+				)
+			}
+		}
+	}
+	return bufprotosource.ForEachMessage(
+		func(message bufprotosource.Message) error {
+			if message.IsMapEntry() {
+				return nil
+			}
+			checkGeneratedNames(responseWriter, "Java", javaMessageMemberNames(message), nil, javaReservedFieldAccessorNames)
+			return nil
+		},
+		file,
+	)
+}
+
+// HandleLintGeneratedNamesPython is a handle function.
+var HandleLintGeneratedNamesPython = bufcheckserverutil.NewLintFileRuleHandler(handleLintGeneratedNamesPython)
+
+func handleLintGeneratedNamesPython(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	_ bufcheckserverutil.Request,
+	file bufprotosource.File,
+) error {
+	checkGeneratedNames(responseWriter, "Python", pythonModuleLevelNames(file), pythonKeywords, nil)
+	return bufprotosource.ForEachMessage(
+		func(message bufprotosource.Message) error {
+			if message.IsMapEntry() {
+				return nil
+			}
+			checkGeneratedNames(responseWriter, "Python", pythonMessageMemberNames(message), pythonKeywords, pythonReservedMessageMemberNames)
+			return nil
+		},
+		file,
+	)
+}
+
 // HandleLintImportNoPublic is a handle function.
 var HandleLintImportNoPublic = bufcheckserverutil.NewLintFileImportRuleHandler(handleLintImportNoPublic)
 
@@ -524,6 +742,110 @@ func handleLintMessagePascalCase(
 	return nil
 }
 
+// HandleLintNamingEnum is a handle function.
+var HandleLintNamingEnum = bufcheckserverutil.NewLintEnumRuleHandler(handleLintNamingEnum)
+
+func handleLintNamingEnum(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	enum bufprotosource.Enum,
+) error {
+	pattern, err := bufcheckopt.GetNamingEnumPattern(request.Options())
+	if err != nil {
+		return err
+	}
+	checkNamingPattern(responseWriter, pattern, enum.NameLocation(), "Enum", enum.Name(), "enum")
+	return nil
+}
+
+// HandleLintNamingEnumValue is a handle function.
+var HandleLintNamingEnumValue = bufcheckserverutil.NewLintEnumValueRuleHandler(handleLintNamingEnumValue)
+
+func handleLintNamingEnumValue(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	enumValue bufprotosource.EnumValue,
+) error {
+	pattern, err := bufcheckopt.GetNamingEnumValuePattern(request.Options())
+	if err != nil {
+		return err
+	}
+	checkNamingPattern(responseWriter, pattern, enumValue.NameLocation(), "Enum value", enumValue.Name(), "enum_value")
+	return nil
+}
+
+// HandleLintNamingField is a handle function.
+var HandleLintNamingField = bufcheckserverutil.NewLintFieldRuleHandler(handleLintNamingField)
+
+func handleLintNamingField(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	field bufprotosource.Field,
+) error {
+	if message := field.ParentMessage(); message != nil && message.IsMapEntry() {
+		// map entry fields are generated by the compiler and are not named by the user
+		return nil
+	}
+	pattern, err := bufcheckopt.GetNamingFieldPattern(request.Options())
+	if err != nil {
+		return err
+	}
+	checkNamingPattern(responseWriter, pattern, field.NameLocation(), "Field", field.Name(), "field")
+	return nil
+}
+
+// HandleLintNamingMessage is a handle function.
+var HandleLintNamingMessage = bufcheckserverutil.NewLintMessageRuleHandler(handleLintNamingMessage)
+
+func handleLintNamingMessage(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	message bufprotosource.Message,
+) error {
+	if message.IsMapEntry() {
+		// map entries are generated by the compiler and are not named by the user
+		return nil
+	}
+	pattern, err := bufcheckopt.GetNamingMessagePattern(request.Options())
+	if err != nil {
+		return err
+	}
+	checkNamingPattern(responseWriter, pattern, message.NameLocation(), "Message", message.Name(), "message")
+	return nil
+}
+
+// HandleLintNamingRPC is a handle function.
+var HandleLintNamingRPC = bufcheckserverutil.NewLintMethodRuleHandler(handleLintNamingRPC)
+
+func handleLintNamingRPC(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	method bufprotosource.Method,
+) error {
+	pattern, err := bufcheckopt.GetNamingRPCPattern(request.Options())
+	if err != nil {
+		return err
+	}
+	checkNamingPattern(responseWriter, pattern, method.NameLocation(), "RPC", method.Name(), "rpc")
+	return nil
+}
+
+// HandleLintNamingService is a handle function.
+var HandleLintNamingService = bufcheckserverutil.NewLintServiceRuleHandler(handleLintNamingService)
+
+func handleLintNamingService(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	service bufprotosource.Service,
+) error {
+	pattern, err := bufcheckopt.GetNamingServicePattern(request.Options())
+	if err != nil {
+		return err
+	}
+	checkNamingPattern(responseWriter, pattern, service.NameLocation(), "Service", service.Name(), "service")
+	return nil
+}
+
 // HandleLintOneofLowerSnakeCase is a handle function.
 var HandleLintOneofLowerSnakeCase = bufcheckserverutil.NewLintOneofRuleHandler(handleLintOneofLowerSnakeCase)
 
@@ -575,14 +897,18 @@ var HandleLintPackageDirectoryMatch = bufcheckserverutil.NewLintFileRuleHandler(
 
 func handleLintPackageDirectoryMatch(
 	responseWriter bufcheckserverutil.ResponseWriter,
-	_ bufcheckserverutil.Request,
+	request bufcheckserverutil.Request,
 	file bufprotosource.File,
 ) error {
 	pkg := file.Package()
 	if pkg == "" {
 		return nil
 	}
-	expectedDirPath := strings.ReplaceAll(pkg, ".", "/")
+	packageDirectoryMappings, err := bufcheckopt.GetPackageDirectoryMappings(request.Options())
+	if err != nil {
+		return err
+	}
+	expectedDirPath := getPackageDirPath(pkg, packageDirectoryMappings)
 	dirPath := normalpath.Dir(file.Path())
 	// need to check case where in root relative directory and no package defined
 	// this should be valid although if SENSIBLE is turned on this will be invalid
@@ -599,6 +925,30 @@ func handleLintPackageDirectoryMatch(
 	return nil
 }
 
+// getPackageDirPath returns the directory that the files of the package must be within.
+//
+// The components of the package matching the longest package prefix in packageDirectoryMappings
+// are replaced with the corresponding directory.
+func getPackageDirPath(pkg string, packageDirectoryMappings map[string]string) string {
+	matchingPackagePrefix, matchingDirPath, matched := "", "", false
+	for packagePrefix, dirPath := range packageDirectoryMappings {
+		if packagePrefix != "" && pkg != packagePrefix && !strings.HasPrefix(pkg, packagePrefix+".") {
+			continue
+		}
+		if !matched || len(packagePrefix) > len(matchingPackagePrefix) {
+			matchingPackagePrefix, matchingDirPath, matched = packagePrefix, dirPath, true
+		}
+	}
+	dirPath := strings.ReplaceAll(pkg, ".", "/")
+	if !matched {
+		return dirPath
+	}
+	if matchingPackagePrefix != "" {
+		dirPath = strings.TrimPrefix(strings.TrimPrefix(dirPath, strings.ReplaceAll(matchingPackagePrefix, ".", "/")), "/")
+	}
+	return normalpath.Join(matchingDirPath, dirPath)
+}
+
 // HandleLintPackageLowerSnakeCase is a handle function.
 var HandleLintPackageLowerSnakeCase = bufcheckserverutil.NewLintFileRuleHandler(handleLintPackageLowerSnakeCase)
 
@@ -1009,6 +1359,76 @@ func handleLintProtovalidate(
 	).Handle(ctx, nil, request)
 }
 
+// HandleLintReservedRegistryNoReuse is a handle function.
+var HandleLintReservedRegistryNoReuse = bufcheckserverutil.NewLintFilesRuleHandler(handleLintReservedRegistryNoReuse)
+
+func handleLintReservedRegistryNoReuse(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	request bufcheckserverutil.Request,
+	files []bufprotosource.File,
+) error {
+	reservedRegistry, err := bufcheckopt.GetReservedRegistry(request.Options())
+	if err != nil {
+		return err
+	}
+	if reservedRegistry == nil {
+		return nil
+	}
+	messageFullNameToReservedRecord := newFullNameToReservedRecord(reservedRegistry.Messages())
+	enumFullNameToReservedRecord := newFullNameToReservedRecord(reservedRegistry.Enums())
+	for _, file := range files {
+		if err := bufprotosource.ForEachMessage(
+			func(message bufprotosource.Message) error {
+				reservedRecord, ok := messageFullNameToReservedRecord[message.FullName()]
+				if !ok || message.IsMapEntry() {
+					return nil
+				}
+				for _, field := range message.Fields() {
+					checkReservedRecordNoReuse(
+						responseWriter,
+						reservedRecord,
+						"Field",
+						field,
+						field.Number(),
+						field.NumberLocation(),
+						"message",
+						message.FullName(),
+					)
+				}
+				return nil
+			},
+			file,
+		); err != nil {
+			return err
+		}
+		if err := bufprotosource.ForEachEnum(
+			func(enum bufprotosource.Enum) error {
+				reservedRecord, ok := enumFullNameToReservedRecord[enum.FullName()]
+				if !ok {
+					return nil
+				}
+				for _, enumValue := range enum.Values() {
+					checkReservedRecordNoReuse(
+						responseWriter,
+						reservedRecord,
+						"Enum value",
+						enumValue,
+						enumValue.Number(),
+						enumValue.NumberLocation(),
+						"enum",
+						enum.FullName(),
+					)
+				}
+				return nil
+			},
+			file,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // HandleLintRPCNoClientStreaming is a handle function.
 var HandleLintRPCNoClientStreaming = bufcheckserverutil.NewLintMethodRuleHandler(handleLintRPCNoClientStreaming)
 
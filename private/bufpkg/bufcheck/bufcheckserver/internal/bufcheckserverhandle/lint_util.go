@@ -15,9 +15,16 @@
 package bufcheckserverhandle
 
 import (
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver/internal/bufcheckserverutil"
+	"github.com/bufbuild/buf/private/bufpkg/bufextension"
 	"github.com/bufbuild/buf/private/bufpkg/bufprotosource"
+	"github.com/bufbuild/buf/private/bufpkg/bufreserved"
+	"github.com/bufbuild/buf/private/pkg/slicesext"
 	"github.com/bufbuild/buf/private/pkg/stringutil"
 )
 
@@ -35,18 +42,25 @@ func fieldToUpperSnakeCase(s string) string {
 	return stringutil.ToUpperSnakeCase(s)
 }
 
-// validLeadingComment returns true if comment has at least one line that isn't empty
-// and doesn't start with one of the comment excludes.
-func validLeadingComment(commentExcludes []string, comment string) bool {
+// leadingCommentText returns the lines of comment that aren't empty and don't start
+// with one of the comment excludes, trimmed and joined by spaces.
+//
+// Returns the empty string if there are no such lines.
+func leadingCommentText(commentExcludes []string, comment string) string {
+	var lines []string
 	for _, line := range strings.Split(comment, "\n") {
 		line = strings.TrimSpace(line)
-		for _, commentExclude := range commentExcludes {
-			if line != "" && !strings.HasPrefix(line, commentExclude) {
-				return true
-			}
+		if line == "" || slices.ContainsFunc(
+			commentExcludes,
+			func(commentExclude string) bool {
+				return strings.HasPrefix(line, commentExclude)
+			},
+		) {
+			continue
 		}
+		lines = append(lines, line)
 	}
-	return false
+	return strings.Join(lines, " ")
 }
 
 // Returns the usedPackageList if there is an import cycle.
@@ -90,3 +104,115 @@ func getImportCycleIfExists(
 	delete(usedPackageMap, pkg)
 	return nil
 }
+
+// newExtendeeToNumberToNames indexes the bufextension.Records by extendee and number.
+func newExtendeeToNumberToNames(records []bufextension.Record) map[string]map[int32][]string {
+	extendeeToNumberToNames := make(map[string]map[int32][]string, len(records))
+	for _, record := range records {
+		numberToNames := make(map[int32][]string)
+		for _, entry := range record.Entries() {
+			numberToNames[entry.Number] = append(numberToNames[entry.Number], entry.Name)
+		}
+		extendeeToNumberToNames[record.Extendee()] = numberToNames
+	}
+	return extendeeToNumberToNames
+}
+
+// reservedRecord is a bufreserved.Record indexed by number and name.
+type reservedRecord struct {
+	entries       map[bufreserved.Entry]struct{}
+	numberToNames map[int32][]string
+	nameToNumbers map[string][]int32
+}
+
+func newFullNameToReservedRecord(records []bufreserved.Record) map[string]*reservedRecord {
+	fullNameToReservedRecord := make(map[string]*reservedRecord, len(records))
+	for _, record := range records {
+		reservedRecord := &reservedRecord{
+			entries:       make(map[bufreserved.Entry]struct{}),
+			numberToNames: make(map[int32][]string),
+			nameToNumbers: make(map[string][]int32),
+		}
+		// Entries are sorted by number and then by name, so the resulting
+		// slices are sorted as well.
+		for _, entry := range record.Entries() {
+			reservedRecord.entries[entry] = struct{}{}
+			reservedRecord.numberToNames[entry.Number] = append(reservedRecord.numberToNames[entry.Number], entry.Name)
+			reservedRecord.nameToNumbers[entry.Name] = append(reservedRecord.nameToNumbers[entry.Name], entry.Number)
+		}
+		fullNameToReservedRecord[record.FullName()] = reservedRecord
+	}
+	return fullNameToReservedRecord
+}
+
+// checkReservedRecordNoReuse adds an annotation if the number or name of the
+// descriptor was previously used with a different name or number.
+func checkReservedRecordNoReuse(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	reservedRecord *reservedRecord,
+	descriptorType string,
+	descriptor bufprotosource.NamedDescriptor,
+	number int,
+	numberLocation bufprotosource.Location,
+	parentType string,
+	parentFullName string,
+) {
+	name := descriptor.Name()
+	if _, ok := reservedRecord.entries[bufreserved.Entry{Number: int32(number), Name: name}]; ok {
+		return
+	}
+	if previousNames := reservedRecord.numberToNames[int32(number)]; len(previousNames) > 0 {
+		responseWriter.AddProtosourceAnnotation(
+			numberLocation,
+			nil,
+			`%s %q on %s %q uses number %d, which was previously used by %q and is recorded in the reserved registry.`,
+			descriptorType,
+			name,
+			parentType,
+			parentFullName,
+			number,
+			strings.Join(previousNames, `", "`),
+		)
+	}
+	if previousNumbers := reservedRecord.nameToNumbers[name]; len(previousNumbers) > 0 {
+		responseWriter.AddProtosourceAnnotation(
+			descriptor.NameLocation(),
+			nil,
+			`%s %q on %s %q uses number %d, but the name was previously used with number %s and is recorded in the reserved registry.`,
+			descriptorType,
+			name,
+			parentType,
+			parentFullName,
+			number,
+			strings.Join(slicesext.Map(previousNumbers, func(previousNumber int32) string {
+				return strconv.Itoa(int(previousNumber))
+			}), ", "),
+		)
+	}
+}
+
+// checkNamingPattern adds an annotation if the name does not match the pattern
+// set with lint.naming.<key>.
+//
+// If the pattern is nil, no pattern was set, and nothing is checked.
+func checkNamingPattern(
+	responseWriter bufcheckserverutil.ResponseWriter,
+	pattern *regexp.Regexp,
+	nameLocation bufprotosource.Location,
+	kind string,
+	name string,
+	key string,
+) {
+	if pattern == nil || pattern.MatchString(name) {
+		return
+	}
+	responseWriter.AddProtosourceAnnotation(
+		nameLocation,
+		nil,
+		"%s name %q does not match the pattern %q set with lint.naming.%s.",
+		kind,
+		name,
+		pattern.String(),
+		key,
+	)
+}
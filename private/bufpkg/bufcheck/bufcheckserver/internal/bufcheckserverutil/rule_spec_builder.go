@@ -33,6 +33,35 @@ type RuleSpecBuilder struct {
 	ReplacementIDs []string
 	// Required.
 	Handler check.RuleHandler
+	// Explanation is optional.
+	Explanation *RuleExplanation
+}
+
+// RuleExplanation is structured documentation for a rule, beyond its purpose.
+//
+// This is not part of check.RuleSpec and is never sent over the wire. It is used
+// to explain builtin rules to users, for example in buf explain.
+type RuleExplanation struct {
+	// Rationale is why the rule exists.
+	Rationale string
+	// Triggers are the changes or definitions that result in the rule reporting a failure.
+	Triggers []string
+	// Remediations are safe ways to resolve a failure.
+	Remediations []string
+	// ConfigKeys are the buf.yaml keys that affect the rule, beyond the keys that
+	// apply to all rules of the same type such as "use" and "except".
+	ConfigKeys []string
+	// Examples are examples of changes or definitions that result in a failure.
+	Examples []*RuleExample
+}
+
+// RuleExample is an example of a rule failure.
+type RuleExample struct {
+	// Description describes the example.
+	Description string
+	// Diff is a unified diff of a change that results in a failure for breaking rules,
+	// or of a change that fixes a failure for lint rules.
+	Diff string
 }
 
 // Build builds the RuleSpec for the categories.
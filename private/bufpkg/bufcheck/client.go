@@ -16,10 +16,13 @@ package bufcheck
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"slices"
 	"strings"
+	"time"
 
 	"buf.build/go/bufplugin/check"
 	"buf.build/go/bufplugin/descriptor"
@@ -27,12 +30,15 @@ import (
 	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck/bufcheckserver"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufextension"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufreserved"
 	"github.com/bufbuild/buf/private/pkg/normalpath"
 	"github.com/bufbuild/buf/private/pkg/protosourcepath"
 	"github.com/bufbuild/buf/private/pkg/protoversion"
 	"github.com/bufbuild/buf/private/pkg/slicesext"
 	"github.com/bufbuild/buf/private/pkg/slogext"
+	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/stringutil"
 	"github.com/bufbuild/buf/private/pkg/syserror"
 	"pluginrpc.com/pluginrpc"
@@ -95,6 +101,32 @@ func (c *client) Lint(
 	for _, option := range options {
 		option.applyToLint(lintOptions)
 	}
+	var cache *lintCache
+	var cacheKey string
+	if lintOptions.cacheBucket != nil {
+		key, ok, err := getLintCacheKey(
+			lintOptions.cacheBufVersion,
+			time.Now(),
+			lintConfig,
+			image,
+			lintOptions.pluginConfigs,
+			lintOptions.reservedRegistry,
+			lintOptions.extensionRegistry,
+		)
+		if err != nil {
+			return err
+		}
+		if ok {
+			cache = newLintCache(c.logger, lintOptions.cacheBucket)
+			cacheKey = key
+			if fileAnnotations, ok := cache.Get(ctx, cacheKey, image); ok {
+				if len(fileAnnotations) == 0 {
+					return nil
+				}
+				return bufanalysis.NewFileAnnotationSet(fileAnnotations...)
+			}
+		}
+	}
 	allRules, allCategories, err := c.allRulesAndCategories(
 		ctx,
 		lintConfig.FileVersion(),
@@ -104,7 +136,14 @@ func (c *client) Lint(
 	if err != nil {
 		return err
 	}
-	config, err := configForLintConfig(lintConfig, allRules, allCategories, lintOptions.relatedCheckConfigs)
+	config, err := configForLintConfig(
+		lintConfig,
+		allRules,
+		allCategories,
+		lintOptions.relatedCheckConfigs,
+		lintOptions.reservedRegistry,
+		lintOptions.extensionRegistry,
+	)
 	if err != nil {
 		return err
 	}
@@ -135,7 +174,17 @@ func (c *client) Lint(
 	if err != nil {
 		return err
 	}
-	return annotationsToFilteredFileAnnotationSetOrError(config, image, annotations)
+	err = annotationsToFilteredFileAnnotationSetOrError(config, image, annotations, nil, nil, nil)
+	if cache != nil {
+		var fileAnnotationSet bufanalysis.FileAnnotationSet
+		switch {
+		case err == nil:
+			cache.Put(ctx, cacheKey, nil)
+		case errors.As(err, &fileAnnotationSet):
+			cache.Put(ctx, cacheKey, fileAnnotationSet.FileAnnotations())
+		}
+	}
+	return err
 }
 
 func (c *client) Breaking(
@@ -154,6 +203,15 @@ func (c *client) Breaking(
 	for _, option := range options {
 		option.applyToBreaking(breakingOptions)
 	}
+	if len(breakingOptions.failOnIDsAndCategories) > 0 {
+		breakingConfig = bufconfig.NewBreakingConfig(
+			breakingConfig,
+			breakingConfig.IgnoreUnstablePackages(),
+			breakingConfig.IgnoreSymbols(),
+			breakingConfig.IgnoreIDOrCategoryToSymbols(),
+			breakingOptions.failOnIDsAndCategories,
+		)
+	}
 	allRules, allCategories, err := c.allRulesAndCategories(
 		ctx,
 		breakingConfig.FileVersion(),
@@ -206,7 +264,73 @@ func (c *client) Breaking(
 	if err != nil {
 		return err
 	}
-	return annotationsToFilteredFileAnnotationSetOrError(config, image, annotations)
+	breakingRules := rulesForType(allRules, check.RuleTypeBreaking)
+	var wireAnnotations []*annotation
+	if wireRuleIDs := getWireRuleIDs(breakingRules, config.RuleIDs); len(annotations) > 0 && len(wireRuleIDs) > 0 {
+		wireRequest, err := check.NewRequest(
+			fileDescriptors,
+			check.WithRuleIDs(wireRuleIDs...),
+			check.WithAgainstFileDescriptors(againstFileDescriptors),
+			check.WithOptions(config.DefaultOptions),
+		)
+		if err != nil {
+			return err
+		}
+		wireAnnotations, err = multiClient.Check(ctx, wireRequest)
+		if err != nil {
+			return err
+		}
+	}
+	var failOnClassifier *failOnClassifier
+	if failOnRuleIDs := config.FailOnRuleIDs; len(annotations) > 0 && len(failOnRuleIDs) > 0 {
+		failOnRuleIDMap := slicesext.ToStructMap(failOnRuleIDs)
+		failOnAnnotations := slicesext.Filter(
+			annotations,
+			func(annotation *annotation) bool {
+				_, ok := failOnRuleIDMap[annotation.RuleID()]
+				return ok
+			},
+		)
+		if unconfiguredFailOnRuleIDs := getUnconfiguredFailOnRuleIDs(failOnRuleIDs, config.RuleIDs); len(unconfiguredFailOnRuleIDs) > 0 {
+			failOnRequest, err := check.NewRequest(
+				fileDescriptors,
+				check.WithRuleIDs(unconfiguredFailOnRuleIDs...),
+				check.WithAgainstFileDescriptors(againstFileDescriptors),
+				check.WithOptions(config.DefaultOptions),
+			)
+			if err != nil {
+				return err
+			}
+			unconfiguredFailOnAnnotations, err := multiClient.Check(ctx, failOnRequest)
+			if err != nil {
+				return err
+			}
+			failOnAnnotations = append(failOnAnnotations, unconfiguredFailOnAnnotations...)
+		}
+		failOnClassifier = newFailOnClassifier(failOnRuleIDs, failOnAnnotations)
+	}
+	var codeFrameProvider *codeFrameProvider
+	if breakingOptions.sourceBucket != nil || breakingOptions.againstSourceBucket != nil {
+		codeFrameProvider, err = newCodeFrameProvider(
+			ctx,
+			breakingOptions.sourceBucket,
+			breakingOptions.againstSourceBucket,
+			image,
+			againstImage,
+			annotations,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return annotationsToFilteredFileAnnotationSetOrError(
+		config,
+		image,
+		annotations,
+		newImpactClassifier(breakingRules, wireAnnotations),
+		failOnClassifier,
+		codeFrameProvider,
+	)
 }
 
 func (c *client) ConfiguredRules(
@@ -336,10 +460,14 @@ func (c *client) getMultiClient(
 	return newMultiClient(c.logger, checkClientSpecs), nil
 }
 
+// impactClassifier is nil if the annotations are not classified by impact.
 func annotationsToFilteredFileAnnotationSetOrError(
 	config *config,
 	image bufimage.Image,
 	annotations []*annotation,
+	impactClassifier *impactClassifier,
+	failOnClassifier *failOnClassifier,
+	codeFrameProvider *codeFrameProvider,
 ) error {
 	if len(annotations) == 0 {
 		return nil
@@ -360,6 +488,10 @@ func annotationsToFilteredFileAnnotationSetOrError(
 				image,
 			),
 			annotations,
+			impactClassifier,
+			failOnClassifier,
+			codeFrameProvider,
+			config.WarnRuleIDs,
 		)...,
 	)
 }
@@ -385,7 +517,7 @@ func ignoreAnnotation(
 	annotation *annotation,
 ) (bool, error) {
 	if fileLocation := annotation.FileLocation(); fileLocation != nil {
-		ignore, err := ignoreFileLocation(config, annotation.RuleID(), fileLocation)
+		ignore, err := ignoreFileLocation(config, annotation.RuleID(), fileLocation, true)
 		if err != nil {
 			return false, err
 		}
@@ -394,15 +526,22 @@ func ignoreAnnotation(
 		}
 	}
 	if againstFileLocation := annotation.AgainstFileLocation(); againstFileLocation != nil {
-		return ignoreFileLocation(config, annotation.RuleID(), againstFileLocation)
+		// Comment ignores in the against file are not considered, so that a breaking change
+		// that is allowed by a comment ignore is only allowed until the file with the comment
+		// ignore is the against file, that is, for one release cycle.
+		return ignoreFileLocation(config, annotation.RuleID(), againstFileLocation, false)
 	}
 	return false, nil
 }
 
+// ignoreFileLocation returns true if the Rule should be ignored for the FileLocation.
+//
+// Comment ignores are only considered if checkCommentIgnores is true.
 func ignoreFileLocation(
 	config *config,
 	ruleID string,
 	fileLocation descriptor.FileLocation,
+	checkCommentIgnores bool,
 ) (bool, error) {
 	fileDescriptor := fileLocation.FileDescriptor()
 	if config.ExcludeImports && fileDescriptor.IsImport() {
@@ -411,14 +550,28 @@ func ignoreFileLocation(
 
 	protoreflectFileDescriptor := fileDescriptor.ProtoreflectFileDescriptor()
 	path := protoreflectFileDescriptor.Path()
-	if normalpath.MapHasEqualOrContainingPath(config.IgnoreRootPaths, path, normalpath.Relative) {
+	if ignoreRootPathsMatch(config.IgnoreRootPaths, path) {
 		return true, nil
 	}
 	// If the config says to ignore this specific rule for this path, ignore this location, otherwise we look for other forms of ignores.
-	if ignoreRootPaths, ok := config.IgnoreRuleIDToRootPaths[ruleID]; ok && normalpath.MapHasEqualOrContainingPath(ignoreRootPaths, path, normalpath.Relative) {
+	if ignoreRootPaths, ok := config.IgnoreRuleIDToRootPaths[ruleID]; ok && ignoreRootPathsMatch(ignoreRootPaths, path) {
 		return true, nil
 	}
 
+	// Will never be triggered by lint since these are never set.
+	if ignoreSymbolMatchers := slices.Concat(
+		config.IgnoreSymbolMatchers,
+		config.IgnoreRuleIDToSymbolMatchers[ruleID],
+	); len(ignoreSymbolMatchers) > 0 {
+		for _, fullName := range getFullNamesForSourcePath(protoreflectFileDescriptor, fileLocation.SourcePath()) {
+			for _, ignoreSymbolMatcher := range ignoreSymbolMatchers {
+				if ignoreSymbolMatcher.Matches(fullName) {
+					return true, nil
+				}
+			}
+		}
+	}
+
 	// Not a great design, but will never be triggered by lint since this is never set.
 	if config.IgnoreUnstablePackages {
 		if packageVersion, ok := protoversion.NewPackageVersionForPackage(string(protoreflectFileDescriptor.Package())); ok {
@@ -428,9 +581,9 @@ func ignoreFileLocation(
 		}
 	}
 
-	// Not a great design, but will never be triggered by breaking since this is never set.
-	// Therefore, never called for an againstLocation  (since lint never has againstLocations).
-	if config.AllowCommentIgnores && config.CommentIgnorePrefix != "" {
+	// For lint, these are the buf:lint:ignore comments, and for breaking, these are the
+	// buf:breaking:allow comments.
+	if checkCommentIgnores && config.AllowCommentIgnores && config.CommentIgnorePrefix != "" {
 		sourcePath := fileLocation.SourcePath()
 		if len(sourcePath) == 0 {
 			return false, nil
@@ -444,7 +597,20 @@ func ignoreFileLocation(
 			sourceLocation := sourceLocations.ByPath(associatedSourcePath)
 			if leadingComments := sourceLocation.LeadingComments; leadingComments != "" {
 				for _, line := range stringutil.SplitTrimLinesNoEmpty(leadingComments) {
-					if checkCommentLineForCheckIgnore(line, config.CommentIgnorePrefix, ruleID) {
+					if !checkCommentLineForCheckIgnore(line, config.CommentIgnorePrefix, ruleID) {
+						continue
+					}
+					commentIgnore, err := newCommentIgnore(
+						newFileInfo(path, ""),
+						sourceLocation.StartLine+1,
+						sourceLocation.StartColumn+1,
+						ruleID,
+						strings.TrimPrefix(line, config.CommentIgnorePrefix+" "+ruleID),
+					)
+					if err != nil {
+						return false, err
+					}
+					if commentIgnore.takesEffect(config.RequireCommentIgnoreJustification, time.Now()) {
 						return true, nil
 					}
 				}
@@ -454,6 +620,20 @@ func ignoreFileLocation(
 	return false, nil
 }
 
+// ignoreRootPathsMatch returns true if the path is equal to or contained within any of the
+// ignore root paths, or matches any of the ignore root paths that are glob patterns.
+func ignoreRootPathsMatch(ignoreRootPaths map[string]struct{}, path string) bool {
+	if normalpath.MapHasEqualOrContainingPath(ignoreRootPaths, path, normalpath.Relative) {
+		return true
+	}
+	for ignoreRootPath := range ignoreRootPaths {
+		if bufconfig.IsIgnorePathPattern(ignoreRootPath) && bufconfig.IgnorePathPatternMatches(ignoreRootPath, path) {
+			return true
+		}
+	}
+	return false
+}
+
 // checkCommentLineForCheckIgnore checks that the comment line starts with the configured
 // comment ignore prefix, a space and the ruleID of the check.
 //
@@ -467,6 +647,8 @@ func ignoreFileLocation(
 // While the following is invalid and a nop
 //
 //	// buf:lint:ignoreSERVICE_PASCAL_CASE
+//
+// The text after the ruleID is the justification of the comment ignore, see newCommentIgnore.
 func checkCommentLineForCheckIgnore(
 	commentLine string,
 	commentIgnorePrefix string,
@@ -479,6 +661,10 @@ func checkCommentLineForCheckIgnore(
 type lintOptions struct {
 	pluginConfigs       []bufconfig.PluginConfig
 	relatedCheckConfigs []bufconfig.CheckConfig
+	reservedRegistry    bufreserved.Registry
+	extensionRegistry   bufextension.Registry
+	cacheBucket         storage.ReadWriteBucket
+	cacheBufVersion     string
 }
 
 func newLintOptions() *lintOptions {
@@ -489,6 +675,10 @@ type breakingOptions struct {
 	pluginConfigs       []bufconfig.PluginConfig
 	excludeImports      bool
 	relatedCheckConfigs []bufconfig.CheckConfig
+	sourceBucket        storage.ReadBucket
+	againstSourceBucket storage.ReadBucket
+	// Only set if BreakingWithFailOn was used.
+	failOnIDsAndCategories []string
 }
 
 func newBreakingOptions() *breakingOptions {
@@ -534,6 +724,50 @@ func (e *excludeImportsOption) applyToBreaking(breakingOptions *breakingOptions)
 	breakingOptions.excludeImports = true
 }
 
+type codeFramesOption struct {
+	sourceBucket        storage.ReadBucket
+	againstSourceBucket storage.ReadBucket
+}
+
+func (c *codeFramesOption) applyToBreaking(breakingOptions *breakingOptions) {
+	breakingOptions.sourceBucket = c.sourceBucket
+	breakingOptions.againstSourceBucket = c.againstSourceBucket
+}
+
+type failOnOption struct {
+	failOnIDsAndCategories []string
+}
+
+func (f *failOnOption) applyToBreaking(breakingOptions *breakingOptions) {
+	breakingOptions.failOnIDsAndCategories = f.failOnIDsAndCategories
+}
+
+type reservedRegistryOption struct {
+	reservedRegistry bufreserved.Registry
+}
+
+func (r *reservedRegistryOption) applyToLint(lintOptions *lintOptions) {
+	lintOptions.reservedRegistry = r.reservedRegistry
+}
+
+type extensionRegistryOption struct {
+	extensionRegistry bufextension.Registry
+}
+
+func (e *extensionRegistryOption) applyToLint(lintOptions *lintOptions) {
+	lintOptions.extensionRegistry = e.extensionRegistry
+}
+
+type lintCacheOption struct {
+	bucket     storage.ReadWriteBucket
+	bufVersion string
+}
+
+func (l *lintCacheOption) applyToLint(lintOptions *lintOptions) {
+	lintOptions.cacheBucket = l.bucket
+	lintOptions.cacheBufVersion = l.bufVersion
+}
+
 type pluginConfigsOption struct {
 	pluginConfigs []bufconfig.PluginConfig
 }
@@ -0,0 +1,152 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcheck
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+
+	"buf.build/go/bufplugin/check"
+	"buf.build/go/bufplugin/descriptor"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/storage"
+)
+
+// maxCodeFrameLines is the maximum number of lines of a code frame. Locations that
+// span more lines, such as entire messages, are cut off after their first lines.
+const maxCodeFrameLines = 5
+
+// codeFrameProvider provides the excerpts of the source of the input and the against
+// input at the locations of breaking change annotations.
+type codeFrameProvider struct {
+	pathToExternalPath        map[string]string
+	againstPathToExternalPath map[string]string
+	pathToLines               map[string][]string
+	againstPathToLines        map[string][]string
+}
+
+// newCodeFrameProvider returns a new codeFrameProvider that has read the files of the
+// annotations from the source buckets.
+//
+// Either bucket may be nil, in which case no code frames are provided for that side.
+// Files that do not exist in a bucket do not have code frames.
+func newCodeFrameProvider(
+	ctx context.Context,
+	sourceBucket storage.ReadBucket,
+	againstSourceBucket storage.ReadBucket,
+	image bufimage.Image,
+	againstImage bufimage.Image,
+	annotations []*annotation,
+) (*codeFrameProvider, error) {
+	pathToLines, err := readPathToLines(ctx, sourceBucket, annotations, check.Annotation.FileLocation)
+	if err != nil {
+		return nil, err
+	}
+	againstPathToLines, err := readPathToLines(ctx, againstSourceBucket, annotations, check.Annotation.AgainstFileLocation)
+	if err != nil {
+		return nil, err
+	}
+	return &codeFrameProvider{
+		pathToExternalPath:        imageToPathToExternalPath(image),
+		againstPathToExternalPath: imageToPathToExternalPath(againstImage),
+		pathToLines:               pathToLines,
+		againstPathToLines:        againstPathToLines,
+	}, nil
+}
+
+// CodeFrame returns the excerpt of the source of the input at the location of the
+// annotation, or nil if it is not available.
+func (c *codeFrameProvider) CodeFrame(annotation *annotation) bufanalysis.CodeFrame {
+	return getCodeFrame(annotation.FileLocation(), c.pathToExternalPath, c.pathToLines)
+}
+
+// AgainstCodeFrame returns the excerpt of the source of the against input at the
+// against location of the annotation, or nil if it is not available.
+func (c *codeFrameProvider) AgainstCodeFrame(annotation *annotation) bufanalysis.CodeFrame {
+	return getCodeFrame(annotation.AgainstFileLocation(), c.againstPathToExternalPath, c.againstPathToLines)
+}
+
+func readPathToLines(
+	ctx context.Context,
+	bucket storage.ReadBucket,
+	annotations []*annotation,
+	getFileLocation func(check.Annotation) descriptor.FileLocation,
+) (map[string][]string, error) {
+	pathToLines := make(map[string][]string)
+	if bucket == nil {
+		return pathToLines, nil
+	}
+	for _, annotation := range annotations {
+		fileLocation := getFileLocation(annotation)
+		if fileLocation == nil {
+			continue
+		}
+		path := fileLocation.FileDescriptor().ProtoreflectFileDescriptor().Path()
+		if _, ok := pathToLines[path]; ok {
+			continue
+		}
+		data, err := storage.ReadPath(ctx, bucket, path)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+			// Record the file as having no lines, so that we do not try to read it again.
+			pathToLines[path] = nil
+			continue
+		}
+		lines := strings.Split(string(data), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimSuffix(line, "\r")
+		}
+		pathToLines[path] = lines
+	}
+	return pathToLines, nil
+}
+
+func getCodeFrame(
+	fileLocation descriptor.FileLocation,
+	pathToExternalPath map[string]string,
+	pathToLines map[string][]string,
+) bufanalysis.CodeFrame {
+	// A FileLocation without a SourcePath is the location of the entire file,
+	// which we do not excerpt.
+	if fileLocation == nil || len(fileLocation.SourcePath()) == 0 {
+		return nil
+	}
+	path := fileLocation.FileDescriptor().ProtoreflectFileDescriptor().Path()
+	lines := pathToLines[path]
+	startLine := fileLocation.StartLine()
+	if startLine < 0 || startLine >= len(lines) {
+		return nil
+	}
+	endLine := min(
+		max(fileLocation.EndLine(), startLine),
+		startLine+maxCodeFrameLines-1,
+		len(lines)-1,
+	)
+	externalPath := pathToExternalPath[path]
+	if externalPath == "" {
+		externalPath = path
+	}
+	return bufanalysis.NewCodeFrame(
+		externalPath,
+		// FileLocations are zero-indexed, while line numbers start at 1.
+		startLine+1,
+		lines[startLine:endLine+1],
+	)
+}
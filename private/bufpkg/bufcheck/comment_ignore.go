@@ -0,0 +1,206 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcheck
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+)
+
+const (
+	commentIgnoreExpiryPrefix = "expires="
+	commentIgnoreReasonPrefix = "reason="
+)
+
+type commentIgnore struct {
+	fileInfo      bufanalysis.FileInfo
+	startLine     int
+	startColumn   int
+	ruleID        string
+	justification string
+	expiry        time.Time
+}
+
+// newCommentIgnore parses the justification and expiry from the text that follows
+// the Rule ID in a comment ignore.
+func newCommentIgnore(
+	fileInfo bufanalysis.FileInfo,
+	startLine int,
+	startColumn int,
+	ruleID string,
+	textAfterRuleID string,
+) (*commentIgnore, error) {
+	var justificationFields []string
+	var expiry time.Time
+	for _, field := range strings.Fields(textAfterRuleID) {
+		value, ok := strings.CutPrefix(field, commentIgnoreExpiryPrefix)
+		if !ok {
+			justificationFields = append(justificationFields, field)
+			continue
+		}
+		var err error
+		expiry, err = time.ParseInLocation(time.DateOnly, value, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%s:%d:%d: invalid expiry %q for comment ignore of %s, expiry must be of the form %sYYYY-MM-DD",
+				fileInfo.ExternalPath(),
+				startLine,
+				startColumn,
+				value,
+				ruleID,
+				commentIgnoreExpiryPrefix,
+			)
+		}
+	}
+	// Separators between the Rule ID and the justification are not part of the justification,
+	// for example "buf:lint:ignore FIELD_LOWER_SNAKE_CASE: required by legacy clients", and
+	// neither is a "reason=" prefix, for example "buf:breaking:allow FIELD_NO_DELETE reason=unused".
+	justification := strings.TrimLeft(strings.Join(justificationFields, " "), ",:- ")
+	justification = strings.TrimPrefix(justification, commentIgnoreReasonPrefix)
+	return &commentIgnore{
+		fileInfo:      fileInfo,
+		startLine:     startLine,
+		startColumn:   startColumn,
+		ruleID:        ruleID,
+		justification: justification,
+		expiry:        expiry,
+	}, nil
+}
+
+func (c *commentIgnore) FileInfo() bufanalysis.FileInfo {
+	return c.fileInfo
+}
+
+func (c *commentIgnore) StartLine() int {
+	return c.startLine
+}
+
+func (c *commentIgnore) StartColumn() int {
+	return c.startColumn
+}
+
+func (c *commentIgnore) RuleID() string {
+	return c.ruleID
+}
+
+func (c *commentIgnore) Justification() string {
+	return c.justification
+}
+
+func (c *commentIgnore) Expiry() time.Time {
+	return c.expiry
+}
+
+// takesEffect returns true if the comment ignore ignores its Rule at the given time.
+func (c *commentIgnore) takesEffect(requireJustification bool, now time.Time) bool {
+	if requireJustification && c.justification == "" {
+		return false
+	}
+	// The comment ignore takes effect until the end of the day it expires.
+	return c.expiry.IsZero() || now.Before(c.expiry.AddDate(0, 0, 1))
+}
+
+func (*commentIgnore) isCommentIgnore() {}
+
+func activeCommentIgnores(
+	lintConfig bufconfig.LintConfig,
+	image bufimage.Image,
+	now time.Time,
+) ([]CommentIgnore, error) {
+	if lintConfig.Disabled() || !lintConfig.AllowCommentIgnores() {
+		return nil, nil
+	}
+	var commentIgnores []CommentIgnore
+	for _, imageFile := range image.Files() {
+		if imageFile.IsImport() {
+			continue
+		}
+		fileInfo := newFileInfo(imageFile.Path(), imageFile.ExternalPath())
+		for _, location := range imageFile.FileDescriptorProto().GetSourceCodeInfo().GetLocation() {
+			leadingComments := location.GetLeadingComments()
+			if leadingComments == "" || len(location.GetSpan()) < 2 {
+				continue
+			}
+			for _, line := range stringutil.SplitTrimLinesNoEmpty(leadingComments) {
+				ruleID, textAfterRuleID, ok := getCommentIgnoreRuleID(line, lintCommentIgnorePrefix)
+				if !ok {
+					continue
+				}
+				commentIgnore, err := newCommentIgnore(
+					fileInfo,
+					int(location.GetSpan()[0])+1,
+					int(location.GetSpan()[1])+1,
+					ruleID,
+					textAfterRuleID,
+				)
+				if err != nil {
+					return nil, err
+				}
+				if commentIgnore.takesEffect(lintConfig.RequireCommentIgnoreJustification(), now) {
+					commentIgnores = append(commentIgnores, commentIgnore)
+				}
+			}
+		}
+	}
+	sort.SliceStable(
+		commentIgnores,
+		func(i int, j int) bool {
+			one := commentIgnores[i]
+			two := commentIgnores[j]
+			if one.FileInfo().Path() != two.FileInfo().Path() {
+				return one.FileInfo().Path() < two.FileInfo().Path()
+			}
+			if one.StartLine() != two.StartLine() {
+				return one.StartLine() < two.StartLine()
+			}
+			if one.StartColumn() != two.StartColumn() {
+				return one.StartColumn() < two.StartColumn()
+			}
+			return one.RuleID() < two.RuleID()
+		},
+	)
+	return commentIgnores, nil
+}
+
+// getCommentIgnoreRuleID returns the Rule ID of the comment ignore on the comment line,
+// and the text that follows the Rule ID.
+//
+// Returns false if the comment line is not a comment ignore.
+func getCommentIgnoreRuleID(commentLine string, commentIgnorePrefix string) (string, string, bool) {
+	text, ok := strings.CutPrefix(commentLine, commentIgnorePrefix+" ")
+	if !ok {
+		return "", "", false
+	}
+	ruleIDLength := strings.IndexFunc(
+		text,
+		func(r rune) bool {
+			return !(r == '_' || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9'))
+		},
+	)
+	if ruleIDLength == -1 {
+		ruleIDLength = len(text)
+	}
+	if ruleIDLength == 0 {
+		return "", "", false
+	}
+	return text[:ruleIDLength], text[ruleIDLength:], true
+}
@@ -17,6 +17,8 @@ package bufcheck
 import (
 	"buf.build/go/bufplugin/check"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufextension"
+	"github.com/bufbuild/buf/private/bufpkg/bufreserved"
 )
 
 type config struct {
@@ -29,12 +31,14 @@ func configForLintConfig(
 	allRules []Rule,
 	allCategories []Category,
 	relatedCheckConfigs []bufconfig.CheckConfig,
+	reservedRegistry bufreserved.Registry,
+	extensionRegistry bufextension.Registry,
 ) (*config, error) {
 	rulesConfig, err := rulesConfigForCheckConfig(lintConfig, allRules, allCategories, check.RuleTypeLint, relatedCheckConfigs)
 	if err != nil {
 		return nil, err
 	}
-	optionsConfig, err := optionsConfigForLintConfig(lintConfig)
+	optionsConfig, err := optionsConfigForLintConfig(lintConfig, reservedRegistry, extensionRegistry)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,80 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcheck
+
+import (
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+)
+
+// failOnClassifier classifies which breaking change annotations are failures when
+// only the breaking changes of some Rules fail.
+//
+// An annotation is a failure if its Rule is a fail-on Rule, or if a fail-on Rule
+// reports a breaking change at the same location. The latter is needed because the
+// breaking categories replace Rules instead of adding to them, for example WIRE_JSON
+// has FIELD_WIRE_JSON_COMPATIBLE_TYPE in place of the FIELD_WIRE_COMPATIBLE_TYPE
+// Rule in WIRE. A wire-incompatible type change reported by the former is a failure
+// when failing on WIRE. All other annotations are warnings.
+type failOnClassifier struct {
+	failOnRuleIDs map[string]struct{}
+	// failOnLocations contains the locations of the fail-on annotations.
+	failOnLocations map[impactLocation]struct{}
+}
+
+// newFailOnClassifier returns a new failOnClassifier for the fail-on Rules.
+//
+// The failOnAnnotations are the annotations of the fail-on Rules, including those of
+// fail-on Rules that are not otherwise configured.
+func newFailOnClassifier(failOnRuleIDs []string, failOnAnnotations []*annotation) *failOnClassifier {
+	failOnLocations := make(map[impactLocation]struct{})
+	for _, failOnAnnotation := range failOnAnnotations {
+		if location, ok := getImpactLocation(failOnAnnotation); ok {
+			failOnLocations[location] = struct{}{}
+		}
+	}
+	return &failOnClassifier{
+		failOnRuleIDs:   slicesext.ToStructMap(failOnRuleIDs),
+		failOnLocations: failOnLocations,
+	}
+}
+
+// IsWarning returns true if the annotation is a warning instead of a failure.
+func (c *failOnClassifier) IsWarning(annotation *annotation) bool {
+	if _, ok := c.failOnRuleIDs[annotation.RuleID()]; ok {
+		return false
+	}
+	if location, ok := getImpactLocation(annotation); ok {
+		if _, ok := c.failOnLocations[location]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// getUnconfiguredFailOnRuleIDs returns the IDs of the fail-on Rules that are not in
+// configuredRuleIDs.
+//
+// These Rules are run in addition to the configured Rules to classify failures, and
+// their annotations are not otherwise reported.
+func getUnconfiguredFailOnRuleIDs(failOnRuleIDs []string, configuredRuleIDs []string) []string {
+	configuredRuleIDMap := slicesext.ToStructMap(configuredRuleIDs)
+	return slicesext.Filter(
+		failOnRuleIDs,
+		func(failOnRuleID string) bool {
+			_, ok := configuredRuleIDMap[failOnRuleID]
+			return !ok
+		},
+	)
+}
@@ -0,0 +1,150 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcheck
+
+import (
+	"slices"
+
+	"buf.build/go/bufplugin/check"
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+)
+
+// impactClassifier classifies the impact of breaking change annotations.
+//
+// The impact of an annotation is derived from the builtin breaking categories
+// its Rule is in: Rules in WIRE break the binary wire format, Rules in WIRE_JSON
+// but not WIRE break the JSON format, and Rules in FILE or PACKAGE but in neither
+// of the wire categories break generated source code. All other Rules are
+// classified as semantic.
+//
+// Rules in FILE and PACKAGE are stricter than their wire counterparts, for example
+// FIELD_SAME_TYPE flags both wire-compatible and wire-incompatible type changes.
+// To not classify a wire-incompatible change as only breaking source code, the
+// impact of an annotation is raised to the impact of any annotation of a WIRE or
+// WIRE_JSON Rule at the same location.
+type impactClassifier struct {
+	ruleIDToImpact map[string]bufanalysis.Impact
+	// locationToImpact contains the most severe impact of the wire annotations
+	// at each location.
+	locationToImpact map[impactLocation]bufanalysis.Impact
+}
+
+// newImpactClassifier returns a new impactClassifier for the breaking Rules.
+//
+// The wireAnnotations are the annotations of the Rules returned by getWireRuleIDs.
+func newImpactClassifier(breakingRules []Rule, wireAnnotations []*annotation) *impactClassifier {
+	ruleIDToImpact := make(map[string]bufanalysis.Impact, len(breakingRules))
+	for _, breakingRule := range breakingRules {
+		ruleIDToImpact[breakingRule.ID()] = getImpactForRule(breakingRule)
+	}
+	locationToImpact := make(map[impactLocation]bufanalysis.Impact)
+	for _, wireAnnotation := range wireAnnotations {
+		location, ok := getImpactLocation(wireAnnotation)
+		if !ok {
+			continue
+		}
+		locationToImpact[location] = moreSevereImpact(
+			locationToImpact[location],
+			ruleIDToImpact[wireAnnotation.RuleID()],
+		)
+	}
+	return &impactClassifier{
+		ruleIDToImpact:   ruleIDToImpact,
+		locationToImpact: locationToImpact,
+	}
+}
+
+// Impact returns the impact of the annotation.
+func (c *impactClassifier) Impact(annotation *annotation) bufanalysis.Impact {
+	impact, ok := c.ruleIDToImpact[annotation.RuleID()]
+	if !ok {
+		// Should never happen, all annotations are for known Rules.
+		impact = bufanalysis.ImpactSemantic
+	}
+	if location, ok := getImpactLocation(annotation); ok {
+		impact = moreSevereImpact(impact, c.locationToImpact[location])
+	}
+	return impact
+}
+
+// getWireRuleIDs returns the IDs of the builtin breaking Rules in WIRE or
+// WIRE_JSON that are not in configuredRuleIDs.
+//
+// These Rules are run in addition to the configured Rules to classify impact,
+// and their annotations are not otherwise reported.
+func getWireRuleIDs(breakingRules []Rule, configuredRuleIDs []string) []string {
+	configuredRuleIDMap := slicesext.ToStructMap(configuredRuleIDs)
+	var wireRuleIDs []string
+	for _, breakingRule := range breakingRules {
+		if breakingRule.PluginName() != "" || breakingRule.Deprecated() {
+			continue
+		}
+		if _, ok := configuredRuleIDMap[breakingRule.ID()]; ok {
+			continue
+		}
+		switch getImpactForRule(breakingRule) {
+		case bufanalysis.ImpactWire, bufanalysis.ImpactJSON:
+			wireRuleIDs = append(wireRuleIDs, breakingRule.ID())
+		}
+	}
+	return wireRuleIDs
+}
+
+// impactLocation is the location of an annotation.
+type impactLocation struct {
+	path        string
+	startLine   int
+	startColumn int
+}
+
+func getImpactLocation(annotation *annotation) (impactLocation, bool) {
+	fileLocation := annotation.FileLocation()
+	if fileLocation == nil {
+		return impactLocation{}, false
+	}
+	return impactLocation{
+		path:        fileLocation.FileDescriptor().ProtoreflectFileDescriptor().Path(),
+		startLine:   fileLocation.StartLine(),
+		startColumn: fileLocation.StartColumn(),
+	}, true
+}
+
+func getImpactForRule(rule Rule) bufanalysis.Impact {
+	categoryIDs := slicesext.Map(rule.Categories(), check.Category.ID)
+	switch {
+	case slices.Contains(categoryIDs, "WIRE"):
+		return bufanalysis.ImpactWire
+	case slices.Contains(categoryIDs, "WIRE_JSON"):
+		return bufanalysis.ImpactJSON
+	case slices.Contains(categoryIDs, "FILE"), slices.Contains(categoryIDs, "PACKAGE"):
+		return bufanalysis.ImpactSource
+	default:
+		return bufanalysis.ImpactSemantic
+	}
+}
+
+// moreSevereImpact returns the more severe of the two impacts.
+//
+// An impact of 0 is less severe than any other impact.
+func moreSevereImpact(one bufanalysis.Impact, two bufanalysis.Impact) bufanalysis.Impact {
+	if one == 0 {
+		return two
+	}
+	if two == 0 {
+		return one
+	}
+	return min(one, two)
+}
@@ -15,7 +15,15 @@
 package bufcheckopt
 
 import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
 	"buf.build/go/bufplugin/option"
+	"github.com/bufbuild/buf/private/bufpkg/bufextension"
+	"github.com/bufbuild/buf/private/bufpkg/bufreserved"
 )
 
 const (
@@ -25,6 +33,18 @@ const (
 	rpcAllowGoogleProtobufEmptyResponsesKey = "rpc_allow_google_protobuf_empty_responses"
 	serviceSuffixKey                        = "service_suffix"
 	commentExcludesKey                      = "comment_excludes"
+	commentMinLengthKey                     = "comment_min_length"
+	commentRequireNamePrefixKey             = "comment_require_name_prefix"
+	reservedRegistryMessagesKey             = "reserved_registry_messages"
+	reservedRegistryEnumsKey                = "reserved_registry_enums"
+	extensionRegistryKey                    = "extension_registry_entries"
+	namingServicePatternKey                 = "naming_service_pattern"
+	namingRPCPatternKey                     = "naming_rpc_pattern"
+	namingMessagePatternKey                 = "naming_message_pattern"
+	namingFieldPatternKey                   = "naming_field_pattern"
+	namingEnumPatternKey                    = "naming_enum_pattern"
+	namingEnumValuePatternKey               = "naming_enum_value_pattern"
+	packageDirectoryMappingsKey             = "package_directory_mappings"
 
 	defaultEnumZeroValueSuffix = "_UNSPECIFIED"
 	defaultServiceSuffix       = "Service"
@@ -63,6 +83,57 @@ type OptionsSpec struct {
 	//
 	// All elements must be non-empty.
 	CommentExcludes []string
+	// CommentMinLength is the minimum number of characters of comments for the COMMENT.* Rules.
+	//
+	// Lines excluded by CommentExcludes do not count towards the length. If 0, the length
+	// of comments is not checked.
+	CommentMinLength int
+	// CommentRequireNamePrefix says that comments must start with the name of the element
+	// they document for the COMMENT.* Rules.
+	CommentRequireNamePrefix bool
+	// ReservedRegistry is the reserved registry to check for reuse of numbers and names.
+	//
+	// May be nil.
+	ReservedRegistry bufreserved.Registry
+	// ExtensionRegistry is the extension registry to check for conflicting extension numbers.
+	//
+	// May be nil.
+	ExtensionRegistry bufextension.Registry
+	// NamingServicePattern is the regular expression that the names of services must
+	// match for the NAMING_SERVICE Rule.
+	//
+	// May be empty.
+	NamingServicePattern string
+	// NamingRPCPattern is the regular expression that the names of RPCs must
+	// match for the NAMING_RPC Rule.
+	//
+	// May be empty.
+	NamingRPCPattern string
+	// NamingMessagePattern is the regular expression that the names of messages must
+	// match for the NAMING_MESSAGE Rule.
+	//
+	// May be empty.
+	NamingMessagePattern string
+	// NamingFieldPattern is the regular expression that the names of fields must
+	// match for the NAMING_FIELD Rule.
+	//
+	// May be empty.
+	NamingFieldPattern string
+	// NamingEnumPattern is the regular expression that the names of enums must
+	// match for the NAMING_ENUM Rule.
+	//
+	// May be empty.
+	NamingEnumPattern string
+	// NamingEnumValuePattern is the regular expression that the names of enum values
+	// must match for the NAMING_ENUM_VALUE Rule.
+	//
+	// May be empty.
+	NamingEnumValuePattern string
+	// PackageDirectoryMappings are the package prefixes to the directories that the files
+	// of packages with the prefix must be within for the PACKAGE_DIRECTORY_MATCH Rule.
+	//
+	// May be empty.
+	PackageDirectoryMappings map[string]string
 }
 
 // ToOptions builds a option.Options.
@@ -86,6 +157,49 @@ func (o *OptionsSpec) ToOptions() (option.Options, error) {
 	if value := o.CommentExcludes; len(value) > 0 {
 		keyToValue[commentExcludesKey] = value
 	}
+	if value := o.CommentMinLength; value > 0 {
+		keyToValue[commentMinLengthKey] = int64(value)
+	}
+	if o.CommentRequireNamePrefix {
+		keyToValue[commentRequireNamePrefixKey] = true
+	}
+	if o.ReservedRegistry != nil {
+		// Options do not support nested values, so each entry of a message or enum is
+		// sent as "full.name.entry_name=number".
+		if value := getReservedRecordStrings(o.ReservedRegistry.Messages()); len(value) > 0 {
+			keyToValue[reservedRegistryMessagesKey] = value
+		}
+		if value := getReservedRecordStrings(o.ReservedRegistry.Enums()); len(value) > 0 {
+			keyToValue[reservedRegistryEnumsKey] = value
+		}
+	}
+	if o.ExtensionRegistry != nil {
+		// Each entry is sent as "extendee.(extension_name)=number", as in option names.
+		if value := getExtensionRecordStrings(o.ExtensionRegistry.Extendees()); len(value) > 0 {
+			keyToValue[extensionRegistryKey] = value
+		}
+	}
+	for key, value := range map[string]string{
+		namingServicePatternKey:   o.NamingServicePattern,
+		namingRPCPatternKey:       o.NamingRPCPattern,
+		namingMessagePatternKey:   o.NamingMessagePattern,
+		namingFieldPatternKey:     o.NamingFieldPattern,
+		namingEnumPatternKey:      o.NamingEnumPattern,
+		namingEnumValuePatternKey: o.NamingEnumValuePattern,
+	} {
+		if len(value) > 0 {
+			keyToValue[key] = value
+		}
+	}
+	if value := o.PackageDirectoryMappings; len(value) > 0 {
+		// Options do not support maps, so each mapping is sent as "prefix=directory".
+		packageDirectoryMappings := make([]string, 0, len(value))
+		for packagePrefix, dirPath := range value {
+			packageDirectoryMappings = append(packageDirectoryMappings, packagePrefix+"="+dirPath)
+		}
+		sort.Strings(packageDirectoryMappings)
+		keyToValue[packageDirectoryMappingsKey] = packageDirectoryMappings
+	}
 	return option.NewOptions(keyToValue)
 }
 
@@ -148,3 +262,219 @@ func GetServiceSuffix(options option.Options) (string, error) {
 func GetCommentExcludes(options option.Options) ([]string, error) {
 	return option.GetStringSliceValue(options, commentExcludesKey)
 }
+
+// GetCommentMinLength gets the minimum number of characters of comments for the COMMENT.* Rules.
+//
+// Returns 0 if the option is not set.
+func GetCommentMinLength(options option.Options) (int, error) {
+	value, err := option.GetInt64Value(options, commentMinLengthKey)
+	if err != nil {
+		return 0, err
+	}
+	return int(value), nil
+}
+
+// GetCommentRequireNamePrefix gets whether comments must start with the name of the element
+// they document for the COMMENT.* Rules.
+func GetCommentRequireNamePrefix(options option.Options) (bool, error) {
+	return option.GetBoolValue(options, commentRequireNamePrefixKey)
+}
+
+// GetReservedRegistry gets the reserved registry.
+//
+// Returns nil if the option is not set.
+func GetReservedRegistry(options option.Options) (bufreserved.Registry, error) {
+	messages, err := getReservedRecords(options, reservedRegistryMessagesKey)
+	if err != nil {
+		return nil, err
+	}
+	enums, err := getReservedRecords(options, reservedRegistryEnumsKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 && len(enums) == 0 {
+		return nil, nil
+	}
+	return bufreserved.NewRegistry(messages, enums), nil
+}
+
+// GetExtensionRegistry gets the extension registry.
+//
+// Returns nil if the option is not set.
+func GetExtensionRegistry(options option.Options) (bufextension.Registry, error) {
+	value, err := option.GetStringSliceValue(options, extensionRegistryKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, nil
+	}
+	records := make([]bufextension.Record, 0, len(value))
+	for _, extensionRecordString := range value {
+		extendee, entry, err := parseExtensionRecordString(extensionRecordString)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, bufextension.NewRecord(extendee, entry))
+	}
+	return bufextension.NewRegistry(records...), nil
+}
+
+// GetNamingServicePattern gets the regular expression that the names of services must match.
+//
+// Returns nil if the option is not set.
+func GetNamingServicePattern(options option.Options) (*regexp.Regexp, error) {
+	return getRegexpValue(options, namingServicePatternKey)
+}
+
+// GetNamingRPCPattern gets the regular expression that the names of RPCs must match.
+//
+// Returns nil if the option is not set.
+func GetNamingRPCPattern(options option.Options) (*regexp.Regexp, error) {
+	return getRegexpValue(options, namingRPCPatternKey)
+}
+
+// GetNamingMessagePattern gets the regular expression that the names of messages must match.
+//
+// Returns nil if the option is not set.
+func GetNamingMessagePattern(options option.Options) (*regexp.Regexp, error) {
+	return getRegexpValue(options, namingMessagePatternKey)
+}
+
+// GetNamingFieldPattern gets the regular expression that the names of fields must match.
+//
+// Returns nil if the option is not set.
+func GetNamingFieldPattern(options option.Options) (*regexp.Regexp, error) {
+	return getRegexpValue(options, namingFieldPatternKey)
+}
+
+// GetNamingEnumPattern gets the regular expression that the names of enums must match.
+//
+// Returns nil if the option is not set.
+func GetNamingEnumPattern(options option.Options) (*regexp.Regexp, error) {
+	return getRegexpValue(options, namingEnumPatternKey)
+}
+
+// GetNamingEnumValuePattern gets the regular expression that the names of enum values must match.
+//
+// Returns nil if the option is not set.
+func GetNamingEnumValuePattern(options option.Options) (*regexp.Regexp, error) {
+	return getRegexpValue(options, namingEnumValuePatternKey)
+}
+
+// GetPackageDirectoryMappings gets the package prefixes to the directories that the files
+// of packages with the prefix must be within.
+//
+// Returns nil if the option is not set.
+func GetPackageDirectoryMappings(options option.Options) (map[string]string, error) {
+	value, err := option.GetStringSliceValue(options, packageDirectoryMappingsKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, nil
+	}
+	packageDirectoryMappings := make(map[string]string, len(value))
+	for _, packageDirectoryMapping := range value {
+		packagePrefix, dirPath, ok := strings.Cut(packageDirectoryMapping, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value %q", packageDirectoryMappingsKey, packageDirectoryMapping)
+		}
+		packageDirectoryMappings[packagePrefix] = dirPath
+	}
+	return packageDirectoryMappings, nil
+}
+
+// *** PRIVATE ***
+
+func getRegexpValue(options option.Options, key string) (*regexp.Regexp, error) {
+	value, err := option.GetStringValue(options, key)
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return nil, nil
+	}
+	return regexp.Compile(value)
+}
+
+func getReservedRecordStrings(records []bufreserved.Record) []string {
+	var reservedRecordStrings []string
+	for _, record := range records {
+		for _, entry := range record.Entries() {
+			reservedRecordStrings = append(
+				reservedRecordStrings,
+				record.FullName()+"."+entry.Name+"="+strconv.FormatInt(int64(entry.Number), 10),
+			)
+		}
+	}
+	return reservedRecordStrings
+}
+
+func getReservedRecords(options option.Options, key string) ([]bufreserved.Record, error) {
+	value, err := option.GetStringSliceValue(options, key)
+	if err != nil {
+		return nil, err
+	}
+	fullNameToEntries := make(map[string][]bufreserved.Entry)
+	var fullNames []string
+	for _, reservedRecordString := range value {
+		fullEntryName, numberString, ok := strings.Cut(reservedRecordString, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value %q", key, reservedRecordString)
+		}
+		lastDotIndex := strings.LastIndexByte(fullEntryName, '.')
+		if lastDotIndex <= 0 || lastDotIndex == len(fullEntryName)-1 {
+			return nil, fmt.Errorf("invalid %s value %q", key, reservedRecordString)
+		}
+		number, err := strconv.ParseInt(numberString, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q: %w", key, reservedRecordString, err)
+		}
+		fullName := fullEntryName[:lastDotIndex]
+		if _, ok := fullNameToEntries[fullName]; !ok {
+			fullNames = append(fullNames, fullName)
+		}
+		fullNameToEntries[fullName] = append(
+			fullNameToEntries[fullName],
+			bufreserved.Entry{
+				Number: int32(number),
+				Name:   fullEntryName[lastDotIndex+1:],
+			},
+		)
+	}
+	records := make([]bufreserved.Record, len(fullNames))
+	for i, fullName := range fullNames {
+		records[i] = bufreserved.NewRecord(fullName, fullNameToEntries[fullName]...)
+	}
+	return records, nil
+}
+
+func getExtensionRecordStrings(records []bufextension.Record) []string {
+	var extensionRecordStrings []string
+	for _, record := range records {
+		for _, entry := range record.Entries() {
+			extensionRecordStrings = append(
+				extensionRecordStrings,
+				record.Extendee()+".("+entry.Name+")="+strconv.FormatInt(int64(entry.Number), 10),
+			)
+		}
+	}
+	return extensionRecordStrings
+}
+
+func parseExtensionRecordString(extensionRecordString string) (string, bufextension.Entry, error) {
+	optionName, numberString, ok := strings.Cut(extensionRecordString, "=")
+	if !ok {
+		return "", bufextension.Entry{}, fmt.Errorf("invalid %s value %q", extensionRegistryKey, extensionRecordString)
+	}
+	extendee, name, ok := strings.Cut(optionName, ".(")
+	if !ok || extendee == "" || !strings.HasSuffix(name, ")") || len(name) == 1 {
+		return "", bufextension.Entry{}, fmt.Errorf("invalid %s value %q", extensionRegistryKey, extensionRecordString)
+	}
+	number, err := strconv.ParseInt(numberString, 10, 32)
+	if err != nil {
+		return "", bufextension.Entry{}, fmt.Errorf("invalid %s value %q: %w", extensionRegistryKey, extensionRecordString, err)
+	}
+	return extendee, bufextension.Entry{Number: int32(number), Name: strings.TrimSuffix(name, ")")}, nil
+}
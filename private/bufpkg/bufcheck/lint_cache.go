@@ -0,0 +1,346 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufcas"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufextension"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufreserved"
+	"github.com/bufbuild/buf/private/pkg/cachestats"
+	"github.com/bufbuild/buf/private/pkg/slogext"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+// lintCache caches the FileAnnotations produced by Lint.
+//
+// Errors reading from or writing to the cache are logged and otherwise ignored,
+// as the cache is only an optimization.
+type lintCache struct {
+	logger *slog.Logger
+	bucket storage.ReadWriteBucket
+}
+
+func newLintCache(
+	logger *slog.Logger,
+	bucket storage.ReadWriteBucket,
+) *lintCache {
+	return &lintCache{
+		logger: logger,
+		bucket: bucket,
+	}
+}
+
+// Get gets the FileAnnotations for the key, with the external paths of the files
+// in the Image.
+//
+// Returns false if there is no entry for the key.
+func (c *lintCache) Get(ctx context.Context, key string, image bufimage.Image) ([]bufanalysis.FileAnnotation, bool) {
+	fileAnnotations, ok := c.get(ctx, key, image)
+	hits := 0
+	if ok {
+		hits = 1
+	}
+	cachestats.Record(ctx, "lint", 1, hits)
+	return fileAnnotations, ok
+}
+
+func (c *lintCache) get(ctx context.Context, key string, image bufimage.Image) ([]bufanalysis.FileAnnotation, bool) {
+	data, err := storage.ReadPath(ctx, c.bucket, key)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			c.logger.DebugContext(ctx, "could not read lint results from cache", slog.String("key", key), slogext.ErrorAttr(err))
+		}
+		return nil, false
+	}
+	var externalEntry externalLintCacheEntry
+	if err := json.Unmarshal(data, &externalEntry); err != nil {
+		c.logger.DebugContext(ctx, "invalid lint results in cache", slog.String("key", key), slogext.ErrorAttr(err))
+		return nil, false
+	}
+	pathToExternalPath := imageToPathToExternalPath(image)
+	fileAnnotations := make([]bufanalysis.FileAnnotation, 0, len(externalEntry.FileAnnotations))
+	for _, externalFileAnnotation := range externalEntry.FileAnnotations {
+		var fileInfo bufanalysis.FileInfo
+		if externalFileAnnotation.Path != "" {
+			fileInfo = newFileInfo(externalFileAnnotation.Path, pathToExternalPath[externalFileAnnotation.Path])
+		}
+		var options []bufanalysis.FileAnnotationOption
+		if externalFileAnnotation.Severity == bufanalysis.SeverityWarning.String() {
+			options = append(options, bufanalysis.FileAnnotationWithSeverity(bufanalysis.SeverityWarning))
+		}
+		fileAnnotations = append(
+			fileAnnotations,
+			bufanalysis.NewFileAnnotation(
+				fileInfo,
+				externalFileAnnotation.StartLine,
+				externalFileAnnotation.StartColumn,
+				externalFileAnnotation.EndLine,
+				externalFileAnnotation.EndColumn,
+				externalFileAnnotation.Type,
+				externalFileAnnotation.Message,
+				externalFileAnnotation.PluginName,
+				options...,
+			),
+		)
+	}
+	c.logger.DebugContext(ctx, "using lint results from cache", slog.String("key", key))
+	return fileAnnotations, true
+}
+
+// Put puts the FileAnnotations for the key, replacing any existing entry.
+func (c *lintCache) Put(ctx context.Context, key string, fileAnnotations []bufanalysis.FileAnnotation) {
+	externalEntry := &externalLintCacheEntry{
+		CreateTime:      time.Now().UTC(),
+		FileAnnotations: make([]*externalLintCacheFileAnnotation, 0, len(fileAnnotations)),
+	}
+	for _, fileAnnotation := range fileAnnotations {
+		var path string
+		if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+			path = fileInfo.Path()
+		}
+		externalEntry.FileAnnotations = append(
+			externalEntry.FileAnnotations,
+			&externalLintCacheFileAnnotation{
+				Path:        path,
+				StartLine:   fileAnnotation.StartLine(),
+				StartColumn: fileAnnotation.StartColumn(),
+				EndLine:     fileAnnotation.EndLine(),
+				EndColumn:   fileAnnotation.EndColumn(),
+				Type:        fileAnnotation.Type(),
+				Message:     fileAnnotation.Message(),
+				PluginName:  fileAnnotation.PluginName(),
+				Severity:    fileAnnotation.Severity().String(),
+			},
+		)
+	}
+	data, err := json.Marshal(externalEntry)
+	if err != nil {
+		c.logger.DebugContext(ctx, "could not marshal lint results", slog.String("key", key), slogext.ErrorAttr(err))
+		return
+	}
+	if err := storage.PutPath(ctx, c.bucket, key, data, storage.PutWithAtomic()); err != nil {
+		c.logger.DebugContext(ctx, "could not write lint results to cache", slog.String("key", key), slogext.ErrorAttr(err))
+	}
+}
+
+// externalLintCacheEntry is the on-disk representation of cached lint results.
+type externalLintCacheEntry struct {
+	CreateTime      time.Time                          `json:"create_time"`
+	FileAnnotations []*externalLintCacheFileAnnotation `json:"file_annotations"`
+}
+
+// externalLintCacheFileAnnotation is the on-disk representation of a FileAnnotation.
+//
+// The external path is not stored, as it depends on the location of the input,
+// and not on the contents of the input.
+type externalLintCacheFileAnnotation struct {
+	Path        string `json:"path,omitempty"`
+	StartLine   int    `json:"start_line,omitempty"`
+	StartColumn int    `json:"start_column,omitempty"`
+	EndLine     int    `json:"end_line,omitempty"`
+	EndColumn   int    `json:"end_column,omitempty"`
+	Type        string `json:"type"`
+	Message     string `json:"message"`
+	PluginName  string `json:"plugin_name,omitempty"`
+	Severity    string `json:"severity"`
+}
+
+// externalLintCacheKey is everything that the results of Lint depend on.
+//
+// This is marshaled to JSON and digested to get the key of the cache entry.
+type externalLintCacheKey struct {
+	// BufVersion is the version of buf, as the builtin rules change between versions.
+	BufVersion string `json:"buf_version"`
+	// Date is the current date, as comment ignores can expire.
+	Date string `json:"date"`
+	// ImageDigest is the digest of the Image, including its imports.
+	ImageDigest string `json:"image_digest"`
+	// LintConfig is the LintConfig.
+	LintConfig *externalLintCacheKeyLintConfig `json:"lint_config"`
+	// Plugins are the plugins configured.
+	Plugins []*externalLintCacheKeyPlugin `json:"plugins,omitempty"`
+	// ReservedRegistry is the reserved registry, as written by bufreserved.WriteRegistry.
+	ReservedRegistry string `json:"reserved_registry,omitempty"`
+	// ExtensionRegistry is the extension registry, as written by bufextension.WriteRegistry.
+	ExtensionRegistry string `json:"extension_registry,omitempty"`
+}
+
+type externalLintCacheKeyLintConfig struct {
+	FileVersion                          string              `json:"file_version"`
+	Use                                  []string            `json:"use,omitempty"`
+	Except                               []string            `json:"except,omitempty"`
+	Ignore                               []string            `json:"ignore,omitempty"`
+	IgnoreOnly                           map[string][]string `json:"ignore_only,omitempty"`
+	DisableBuiltin                       bool                `json:"disable_builtin,omitempty"`
+	EnumZeroValueSuffix                  string              `json:"enum_zero_value_suffix,omitempty"`
+	RPCAllowSameRequestResponse          bool                `json:"rpc_allow_same_request_response,omitempty"`
+	RPCAllowGoogleProtobufEmptyRequests  bool                `json:"rpc_allow_google_protobuf_empty_requests,omitempty"`
+	RPCAllowGoogleProtobufEmptyResponses bool                `json:"rpc_allow_google_protobuf_empty_responses,omitempty"`
+	ServiceSuffix                        string              `json:"service_suffix,omitempty"`
+	AllowCommentIgnores                  bool                `json:"allow_comment_ignores,omitempty"`
+	RequireCommentIgnoreJustification    bool                `json:"require_comment_ignore_justification,omitempty"`
+	Warn                                 []string            `json:"warn,omitempty"`
+	NamingPatterns                       []string            `json:"naming_patterns,omitempty"`
+	CommentsMinLength                    int                 `json:"comments_min_length,omitempty"`
+	CommentsRequireNamePrefix            bool                `json:"comments_require_name_prefix,omitempty"`
+	CommentsExcludePrefixes              []string            `json:"comments_exclude_prefixes,omitempty"`
+	PackageDirectoryMappings             map[string]string   `json:"package_directory_mappings,omitempty"`
+}
+
+type externalLintCacheKeyPlugin struct {
+	Name    string         `json:"name"`
+	Options map[string]any `json:"options,omitempty"`
+	Args    []string       `json:"args,omitempty"`
+	// Size and ModTime identify the version of the plugin, as the name is a path
+	// or program name.
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// getLintCacheKey gets the key for the results of linting the Image.
+//
+// Returns false if the results should not be cached. This is the case if a remote
+// plugin is configured, as the version of a remote plugin is not known before it is
+// resolved, or if a local plugin cannot be found.
+func getLintCacheKey(
+	bufVersion string,
+	now time.Time,
+	lintConfig bufconfig.LintConfig,
+	image bufimage.Image,
+	pluginConfigs []bufconfig.PluginConfig,
+	reservedRegistry bufreserved.Registry,
+	extensionRegistry bufextension.Registry,
+) (string, bool, error) {
+	protoImage, err := bufimage.ImageToProtoImage(image)
+	if err != nil {
+		return "", false, err
+	}
+	imageData, err := proto.MarshalOptions{Deterministic: true}.Marshal(protoImage)
+	if err != nil {
+		return "", false, err
+	}
+	imageDigest, err := bufcas.NewDigestForContent(bytes.NewReader(imageData))
+	if err != nil {
+		return "", false, err
+	}
+	externalKey := &externalLintCacheKey{
+		BufVersion:  bufVersion,
+		Date:        now.Format(time.DateOnly),
+		ImageDigest: imageDigest.String(),
+		LintConfig:  lintConfigToExternalLintCacheKeyLintConfig(lintConfig),
+	}
+	for _, pluginConfig := range pluginConfigs {
+		var path string
+		switch pluginConfig.Type() {
+		case bufconfig.PluginConfigTypeLocal:
+			path, err = exec.LookPath(pluginConfig.Name())
+			if err != nil {
+				return "", false, nil
+			}
+		case bufconfig.PluginConfigTypeLocalWasm:
+			path = pluginConfig.Name()
+		default:
+			return "", false, nil
+		}
+		fileInfo, err := os.Stat(path)
+		if err != nil {
+			return "", false, nil
+		}
+		externalKey.Plugins = append(
+			externalKey.Plugins,
+			&externalLintCacheKeyPlugin{
+				Name:    pluginConfig.Name(),
+				Options: pluginConfig.Options(),
+				Args:    pluginConfig.Args(),
+				Size:    fileInfo.Size(),
+				ModTime: fileInfo.ModTime().UTC(),
+			},
+		)
+	}
+	if reservedRegistry != nil {
+		buffer := bytes.NewBuffer(nil)
+		if err := bufreserved.WriteRegistry(buffer, reservedRegistry); err != nil {
+			return "", false, err
+		}
+		externalKey.ReservedRegistry = buffer.String()
+	}
+	if extensionRegistry != nil {
+		buffer := bytes.NewBuffer(nil)
+		if err := bufextension.WriteRegistry(buffer, extensionRegistry); err != nil {
+			return "", false, err
+		}
+		externalKey.ExtensionRegistry = buffer.String()
+	}
+	keyData, err := json.Marshal(externalKey)
+	if err != nil {
+		return "", false, err
+	}
+	digest, err := bufcas.NewDigestForContent(bytes.NewReader(keyData))
+	if err != nil {
+		return "", false, err
+	}
+	return digest.Type().String() + "-" + hex.EncodeToString(digest.Value()), true, nil
+}
+
+func lintConfigToExternalLintCacheKeyLintConfig(lintConfig bufconfig.LintConfig) *externalLintCacheKeyLintConfig {
+	externalLintConfig := &externalLintCacheKeyLintConfig{
+		FileVersion:                          lintConfig.FileVersion().String(),
+		Use:                                  lintConfig.UseIDsAndCategories(),
+		Except:                               lintConfig.ExceptIDsAndCategories(),
+		Ignore:                               lintConfig.IgnorePaths(),
+		IgnoreOnly:                           lintConfig.IgnoreIDOrCategoryToPaths(),
+		DisableBuiltin:                       lintConfig.DisableBuiltin(),
+		EnumZeroValueSuffix:                  lintConfig.EnumZeroValueSuffix(),
+		RPCAllowSameRequestResponse:          lintConfig.RPCAllowSameRequestResponse(),
+		RPCAllowGoogleProtobufEmptyRequests:  lintConfig.RPCAllowGoogleProtobufEmptyRequests(),
+		RPCAllowGoogleProtobufEmptyResponses: lintConfig.RPCAllowGoogleProtobufEmptyResponses(),
+		ServiceSuffix:                        lintConfig.ServiceSuffix(),
+		AllowCommentIgnores:                  lintConfig.AllowCommentIgnores(),
+		RequireCommentIgnoreJustification:    lintConfig.RequireCommentIgnoreJustification(),
+		Warn:                                 lintConfig.WarnIDsAndCategories(),
+		PackageDirectoryMappings:             lintConfig.PackageDirectoryMappings(),
+	}
+	if namingConfig := lintConfig.NamingConfig(); namingConfig != nil {
+		externalLintConfig.NamingPatterns = []string{
+			namingConfig.ServicePattern(),
+			namingConfig.RPCPattern(),
+			namingConfig.MessagePattern(),
+			namingConfig.FieldPattern(),
+			namingConfig.EnumPattern(),
+			namingConfig.EnumValuePattern(),
+		}
+	}
+	if commentsConfig := lintConfig.CommentsConfig(); commentsConfig != nil {
+		externalLintConfig.CommentsMinLength = commentsConfig.MinLength()
+		externalLintConfig.CommentsRequireNamePrefix = commentsConfig.RequireNamePrefix()
+		externalLintConfig.CommentsExcludePrefixes = commentsConfig.ExcludePrefixes()
+	}
+	return externalLintConfig
+}
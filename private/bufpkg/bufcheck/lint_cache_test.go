@@ -0,0 +1,147 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
+	"github.com/bufbuild/buf/private/pkg/slogtestext"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestLintCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	bucket := storagemem.NewReadWriteBucket()
+	image := testNewLintCacheImage(t, "acme.v1")
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		bufanalysis.NewFileAnnotation(
+			newFileInfo("a.proto", "a.proto"),
+			1,
+			2,
+			1,
+			10,
+			"PACKAGE_DIRECTORY_MATCH",
+			"message",
+			"",
+		),
+		bufanalysis.NewFileAnnotation(
+			newFileInfo("a.proto", "a.proto"),
+			3,
+			1,
+			3,
+			5,
+			"FIELD_LOWER_SNAKE_CASE",
+			"message",
+			"buf-plugin-foo",
+			bufanalysis.FileAnnotationWithSeverity(bufanalysis.SeverityWarning),
+		),
+		bufanalysis.NewFileAnnotation(nil, 0, 0, 0, 0, "PACKAGE_DEFINED", "message", ""),
+	}
+
+	cache := newLintCache(slogtestext.NewLogger(t), bucket)
+	_, ok := cache.Get(ctx, "key", image)
+	require.False(t, ok)
+	cache.Put(ctx, "key", fileAnnotations)
+	cachedFileAnnotations, ok := cache.Get(ctx, "key", image)
+	require.True(t, ok)
+	require.Equal(t, fileAnnotations, cachedFileAnnotations)
+
+	// Entries without FileAnnotations are distinct from missing entries.
+	cache.Put(ctx, "empty", nil)
+	cachedFileAnnotations, ok = cache.Get(ctx, "empty", image)
+	require.True(t, ok)
+	require.Empty(t, cachedFileAnnotations)
+
+	// Invalid entries are ignored.
+	require.NoError(t, storage.PutPath(ctx, bucket, "invalid", []byte("foo")))
+	_, ok = cache.Get(ctx, "invalid", image)
+	require.False(t, ok)
+}
+
+func TestGetLintCacheKey(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	image := testNewLintCacheImage(t, "acme.v1")
+	key := testGetLintCacheKey(t, "1.0.0", now, bufconfig.DefaultLintConfigV2, image)
+	require.Equal(t, key, testGetLintCacheKey(t, "1.0.0", now.Add(time.Hour), bufconfig.DefaultLintConfigV2, image))
+	require.Equal(t, key, testGetLintCacheKey(t, "1.0.0", now, bufconfig.DefaultLintConfigV2, testNewLintCacheImage(t, "acme.v1")))
+	require.NotEqual(t, key, testGetLintCacheKey(t, "1.0.1", now, bufconfig.DefaultLintConfigV2, image))
+	require.NotEqual(t, key, testGetLintCacheKey(t, "1.0.0", now.AddDate(0, 0, 1), bufconfig.DefaultLintConfigV2, image))
+	require.NotEqual(t, key, testGetLintCacheKey(t, "1.0.0", now, bufconfig.DefaultLintConfigV1, image))
+	require.NotEqual(t, key, testGetLintCacheKey(t, "1.0.0", now, bufconfig.DefaultLintConfigV2, testNewLintCacheImage(t, "acme.v2")))
+
+	// Results are not cached with remote plugins.
+	pluginRef, err := bufparse.ParseRef("buf.build/acme/plugin")
+	require.NoError(t, err)
+	pluginConfig, err := bufconfig.NewRemoteWasmPluginConfig(pluginRef, nil, nil)
+	require.NoError(t, err)
+	_, ok, err := getLintCacheKey(
+		"1.0.0",
+		now,
+		bufconfig.DefaultLintConfigV2,
+		image,
+		[]bufconfig.PluginConfig{pluginConfig},
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func testGetLintCacheKey(
+	t *testing.T,
+	bufVersion string,
+	now time.Time,
+	lintConfig bufconfig.LintConfig,
+	image bufimage.Image,
+) string {
+	key, ok, err := getLintCacheKey(bufVersion, now, lintConfig, image, nil, nil, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	return key
+}
+
+func testNewLintCacheImage(t *testing.T, packageName string) bufimage.Image {
+	imageFile, err := bufimage.NewImageFile(
+		&descriptorpb.FileDescriptorProto{
+			Name:    proto.String("a.proto"),
+			Package: proto.String(packageName),
+			Syntax:  proto.String("proto3"),
+		},
+		nil,
+		uuid.Nil,
+		"",
+		"",
+		false,
+		false,
+		nil,
+	)
+	require.NoError(t, err)
+	image, err := bufimage.NewImage([]bufimage.ImageFile{imageFile})
+	require.NoError(t, err)
+	return image
+}
@@ -16,6 +16,8 @@ package bufcheck_test
 
 import (
 	"context"
+	"io/fs"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -25,9 +27,11 @@ import (
 	"github.com/bufbuild/buf/private/bufpkg/bufanalysis"
 	"github.com/bufbuild/buf/private/bufpkg/bufanalysis/bufanalysistesting"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck"
+	"github.com/bufbuild/buf/private/bufpkg/bufextension"
 	"github.com/bufbuild/buf/private/bufpkg/bufimage"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
 	"github.com/bufbuild/buf/private/bufpkg/bufplugin"
+	"github.com/bufbuild/buf/private/bufpkg/bufreserved"
 	"github.com/bufbuild/buf/private/pkg/slogtestext"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
 	"github.com/bufbuild/buf/private/pkg/wasm"
@@ -149,6 +153,20 @@ func TestRunComments(t *testing.T) {
 	)
 }
 
+func TestRunCommentsConfig(t *testing.T) {
+	t.Parallel()
+	testLint(
+		t,
+		"comments_config",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 3, 8, 18, "COMMENT_FIELD"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 3, 12, 20, "COMMENT_FIELD"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 14, 3, 14, 19, "COMMENT_FIELD"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 21, 1, 21, 15, "COMMENT_MESSAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 24, 1, 24, 15, "COMMENT_MESSAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 27, 1, 27, 15, "COMMENT_MESSAGE"),
+	)
+}
+
 func TestRunDirectorySamePackage(t *testing.T) {
 	t.Parallel()
 	testLint(
@@ -476,6 +494,16 @@ func TestRunPackageDirectoryMatch(t *testing.T) {
 	)
 }
 
+func TestRunPackageDirectoryMappings(t *testing.T) {
+	t.Parallel()
+	testLint(
+		t,
+		"package_directory_mappings",
+		bufanalysistesting.NewFileAnnotation(t, "acme/foo/v1/foo.proto", 3, 1, 3, 21, "PACKAGE_DIRECTORY_MATCH"),
+		bufanalysistesting.NewFileAnnotation(t, "protos/bar/v1/bar.proto", 3, 1, 3, 21, "PACKAGE_DIRECTORY_MATCH"),
+	)
+}
+
 func TestRunPackageLowerSnakeCase(t *testing.T) {
 	t.Parallel()
 	testLint(
@@ -976,6 +1004,63 @@ func TestRunStablePackageNoImportUnstable(t *testing.T) {
 	)
 }
 
+func TestRunReservedRegistryNoReuse(t *testing.T) {
+	t.Parallel()
+	testLint(
+		t,
+		"reserved_registry_no_reuse",
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 7, 16, 7, 17, "RESERVED_REGISTRY_NO_REUSE"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 8, 10, 8, 15, "RESERVED_REGISTRY_NO_REUSE"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 18, 15, 18, 16, "RESERVED_REGISTRY_NO_REUSE"),
+	)
+}
+
+func TestRunExtensionRegistryNoConflict(t *testing.T) {
+	t.Parallel()
+	testLint(
+		t,
+		"extension_registry_no_conflict",
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 9, 18, 9, 23, "EXTENSION_REGISTRY_NO_CONFLICT"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 15, 21, 15, 26, "EXTENSION_REGISTRY_NO_CONFLICT"),
+	)
+}
+
+func TestRunGeneratedNames(t *testing.T) {
+	t.Parallel()
+	testLint(
+		t,
+		"generated_names",
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 5, 9, 5, 10, "GENERATED_NAMES_JAVA"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 9, 10, 9, 16, "GENERATED_NAMES_CSHARP"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 9, 10, 9, 16, "GENERATED_NAMES_GO"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 9, 10, 9, 16, "GENERATED_NAMES_JAVA"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 13, 10, 13, 20, "GENERATED_NAMES_CSHARP"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 13, 10, 13, 20, "GENERATED_NAMES_GO"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 20, 9, 20, 16, "GENERATED_NAMES_GO"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 24, 3, 24, 7, "GENERATED_NAMES_GO"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 29, 9, 29, 19, "GENERATED_NAMES_JAVA"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 33, 10, 33, 15, "GENERATED_NAMES_JAVA"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 33, 10, 33, 15, "GENERATED_NAMES_PYTHON"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 37, 10, 37, 14, "GENERATED_NAMES_PYTHON"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 38, 10, 38, 18, "GENERATED_NAMES_PYTHON"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/b.proto", 8, 3, 8, 6, "GENERATED_NAMES_CSHARP"),
+	)
+}
+
+func TestRunNaming(t *testing.T) {
+	t.Parallel()
+	testLint(
+		t,
+		"naming",
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 7, 7, 7, 15, "NAMING_RPC"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 10, 9, 10, 19, "NAMING_SERVICE"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 14, 10, 14, 17, "NAMING_FIELD"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 20, 9, 20, 16, "NAMING_MESSAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 24, 3, 24, 12, "NAMING_ENUM_VALUE"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 27, 6, 27, 16, "NAMING_ENUM"),
+	)
+}
+
 func TestRunIgnores1(t *testing.T) {
 	t.Parallel()
 	testLint(
@@ -1058,6 +1143,20 @@ func TestRunIgnores4(t *testing.T) {
 	)
 }
 
+func TestRunIgnorePatterns(t *testing.T) {
+	t.Parallel()
+	testLint(
+		t,
+		"ignores_patterns",
+		bufanalysistesting.NewFileAnnotation(t, "buf/bar/bar.proto", 6, 9, 6, 15, "FIELD_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "buf/bar/bar.proto", 13, 6, 13, 9, "ENUM_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "buf/bar/bar2.proto", 6, 9, 6, 15, "FIELD_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "buf/buf.proto", 6, 9, 6, 15, "FIELD_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "buf/buf.proto", 9, 9, 9, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "buf/buf.proto", 13, 6, 13, 9, "ENUM_PASCAL_CASE"),
+	)
+}
+
 func TestRunV2WorkspaceIgnores(t *testing.T) {
 	t.Parallel()
 	testLintWithOptions(
@@ -1234,6 +1333,26 @@ func TestCommentIgnoresWithTrailingComment(t *testing.T) {
 	)
 }
 
+func TestCommentIgnoresRequireJustification(t *testing.T) {
+	t.Parallel()
+	testLint(
+		t,
+		"comment_ignores_require_justification",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 9, 7, 17, "FIELD_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 9, 11, 19, "FIELD_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 15, 9, 15, 18, "FIELD_LOWER_SNAKE_CASE"),
+	)
+}
+
+func TestCommentIgnoresExpires(t *testing.T) {
+	t.Parallel()
+	testLint(
+		t,
+		"comment_ignores_expires",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 9, 11, 19, "FIELD_LOWER_SNAKE_CASE"),
+	)
+}
+
 func TestRunLintCustomPlugins(t *testing.T) {
 	t.Parallel()
 	testLint(
@@ -1373,11 +1492,32 @@ func testLintWithOptions(
 		),
 	)
 	require.NoError(t, err)
+	lintOptions := []bufcheck.LintOption{
+		bufcheck.WithPluginConfigs(workspace.PluginConfigs()...),
+	}
+	reservedRegistryFile, err := os.Open(filepath.Join(dirPath, bufreserved.DefaultFileName))
+	if err == nil {
+		reservedRegistry, err := bufreserved.ReadRegistry(reservedRegistryFile)
+		require.NoError(t, reservedRegistryFile.Close())
+		require.NoError(t, err)
+		lintOptions = append(lintOptions, bufcheck.LintWithReservedRegistry(reservedRegistry))
+	} else {
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	}
+	extensionRegistryFile, err := os.Open(filepath.Join(dirPath, bufextension.DefaultFileName))
+	if err == nil {
+		extensionRegistry, err := bufextension.ReadRegistry(extensionRegistryFile)
+		require.NoError(t, extensionRegistryFile.Close())
+		require.NoError(t, err)
+		lintOptions = append(lintOptions, bufcheck.LintWithExtensionRegistry(extensionRegistry))
+	} else {
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	}
 	err = client.Lint(
 		ctx,
 		lintConfig,
 		image,
-		bufcheck.WithPluginConfigs(workspace.PluginConfigs()...),
+		lintOptions...,
 	)
 	if len(expectedFileAnnotations) == 0 {
 		assert.NoError(t, err)
@@ -19,9 +19,18 @@ import (
 	"buf.build/go/bufplugin/option"
 	"github.com/bufbuild/buf/private/bufpkg/bufcheck/internal/bufcheckopt"
 	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufextension"
+	"github.com/bufbuild/buf/private/bufpkg/bufreserved"
+	"github.com/bufbuild/buf/private/pkg/slicesext"
 )
 
-const lintCommentIgnorePrefix = "buf:lint:ignore"
+const (
+	lintCommentIgnorePrefix = "buf:lint:ignore"
+	// breakingCommentIgnorePrefix is the prefix of comments that allow a breaking change.
+	//
+	// Breaking changes must be allowed deliberately, so these always require a justification.
+	breakingCommentIgnorePrefix = "buf:breaking:allow"
+)
 
 type optionsConfig struct {
 	// DefaultOptions are the options that should be passed to the default check.Client.
@@ -31,14 +40,21 @@ type optionsConfig struct {
 	DefaultOptions         option.Options
 	AllowCommentIgnores    bool
 	IgnoreUnstablePackages bool
-	CommentIgnorePrefix    string
-	ExcludeImports         bool
+	// IgnoreSymbolMatchers are the compiled symbol patterns to ignore.
+	IgnoreSymbolMatchers []*symbolMatcher
+	CommentIgnorePrefix  string
+	// RequireCommentIgnoreJustification says that comment ignores without a justification
+	// do not take effect.
+	RequireCommentIgnoreJustification bool
+	ExcludeImports                    bool
 }
 
 func optionsConfigForLintConfig(
 	lintConfig bufconfig.LintConfig,
+	reservedRegistry bufreserved.Registry,
+	extensionRegistry bufextension.Registry,
 ) (*optionsConfig, error) {
-	return optionsConfigSpecForLintConfig(lintConfig).newOptionsConfig(
+	return optionsConfigSpecForLintConfig(lintConfig, reservedRegistry, extensionRegistry).newOptionsConfig(
 		check.RuleTypeLint,
 	)
 }
@@ -57,16 +73,27 @@ func optionsConfigForBreakingConfig(
 type optionsConfigSpec struct {
 	AllowCommentIgnores                  bool
 	IgnoreUnstablePackages               bool
+	IgnoreSymbols                        []string
 	EnumZeroValueSuffix                  string
 	RPCAllowSameRequestResponse          bool
 	RPCAllowGoogleProtobufEmptyRequests  bool
 	RPCAllowGoogleProtobufEmptyResponses bool
 	ServiceSuffix                        string
 	CommentIgnorePrefix                  string
+	RequireCommentIgnoreJustification    bool
 	ExcludeImports                       bool
+	ReservedRegistry                     bufreserved.Registry
+	ExtensionRegistry                    bufextension.Registry
+	NamingConfig                         bufconfig.LintNamingConfig
+	CommentsConfig                       bufconfig.LintCommentsConfig
+	PackageDirectoryMappings             map[string]string
 }
 
-func optionsConfigSpecForLintConfig(lintConfig bufconfig.LintConfig) *optionsConfigSpec {
+func optionsConfigSpecForLintConfig(
+	lintConfig bufconfig.LintConfig,
+	reservedRegistry bufreserved.Registry,
+	extensionRegistry bufextension.Registry,
+) *optionsConfigSpec {
 	return &optionsConfigSpec{
 		AllowCommentIgnores:                  lintConfig.AllowCommentIgnores(),
 		IgnoreUnstablePackages:               false,
@@ -76,7 +103,13 @@ func optionsConfigSpecForLintConfig(lintConfig bufconfig.LintConfig) *optionsCon
 		RPCAllowGoogleProtobufEmptyResponses: lintConfig.RPCAllowGoogleProtobufEmptyResponses(),
 		ServiceSuffix:                        lintConfig.ServiceSuffix(),
 		CommentIgnorePrefix:                  lintCommentIgnorePrefix,
+		RequireCommentIgnoreJustification:    lintConfig.RequireCommentIgnoreJustification(),
 		ExcludeImports:                       false,
+		ReservedRegistry:                     reservedRegistry,
+		ExtensionRegistry:                    extensionRegistry,
+		NamingConfig:                         lintConfig.NamingConfig(),
+		CommentsConfig:                       lintConfig.CommentsConfig(),
+		PackageDirectoryMappings:             lintConfig.PackageDirectoryMappings(),
 	}
 }
 
@@ -85,15 +118,22 @@ func optionsConfigSpecForBreakingConfig(
 	excludeImports bool,
 ) *optionsConfigSpec {
 	return &optionsConfigSpec{
-		AllowCommentIgnores:                  false,
+		AllowCommentIgnores:                  true,
 		IgnoreUnstablePackages:               breakingConfig.IgnoreUnstablePackages(),
+		IgnoreSymbols:                        breakingConfig.IgnoreSymbols(),
 		EnumZeroValueSuffix:                  "",
 		RPCAllowSameRequestResponse:          false,
 		RPCAllowGoogleProtobufEmptyRequests:  false,
 		RPCAllowGoogleProtobufEmptyResponses: false,
 		ServiceSuffix:                        "",
-		CommentIgnorePrefix:                  "",
+		CommentIgnorePrefix:                  breakingCommentIgnorePrefix,
+		RequireCommentIgnoreJustification:    true,
 		ExcludeImports:                       excludeImports,
+		ReservedRegistry:                     nil,
+		ExtensionRegistry:                    nil,
+		NamingConfig:                         nil,
+		CommentsConfig:                       nil,
+		PackageDirectoryMappings:             nil,
 	}
 }
 
@@ -104,19 +144,38 @@ func (b *optionsConfigSpec) newOptionsConfig(ruleType check.RuleType) (*optionsC
 		RPCAllowGoogleProtobufEmptyRequests:  b.RPCAllowGoogleProtobufEmptyRequests,
 		RPCAllowGoogleProtobufEmptyResponses: b.RPCAllowGoogleProtobufEmptyResponses,
 		ServiceSuffix:                        b.ServiceSuffix,
+		ReservedRegistry:                     b.ReservedRegistry,
+		ExtensionRegistry:                    b.ExtensionRegistry,
+		PackageDirectoryMappings:             b.PackageDirectoryMappings,
+	}
+	if b.NamingConfig != nil {
+		optionsSpec.NamingServicePattern = b.NamingConfig.ServicePattern()
+		optionsSpec.NamingRPCPattern = b.NamingConfig.RPCPattern()
+		optionsSpec.NamingMessagePattern = b.NamingConfig.MessagePattern()
+		optionsSpec.NamingFieldPattern = b.NamingConfig.FieldPattern()
+		optionsSpec.NamingEnumPattern = b.NamingConfig.EnumPattern()
+		optionsSpec.NamingEnumValuePattern = b.NamingConfig.EnumValuePattern()
 	}
-	if b.CommentIgnorePrefix != "" {
+	// The COMMENT.* Rules are lint Rules, so the breaking comment ignore prefix is not passed.
+	if b.CommentIgnorePrefix != "" && ruleType == check.RuleTypeLint {
 		optionsSpec.CommentExcludes = []string{b.CommentIgnorePrefix}
 	}
+	if b.CommentsConfig != nil {
+		optionsSpec.CommentExcludes = append(optionsSpec.CommentExcludes, b.CommentsConfig.ExcludePrefixes()...)
+		optionsSpec.CommentMinLength = b.CommentsConfig.MinLength()
+		optionsSpec.CommentRequireNamePrefix = b.CommentsConfig.RequireNamePrefix()
+	}
 	options, err := optionsSpec.ToOptions()
 	if err != nil {
 		return nil, err
 	}
 	return &optionsConfig{
-		DefaultOptions:         options,
-		AllowCommentIgnores:    b.AllowCommentIgnores,
-		IgnoreUnstablePackages: b.IgnoreUnstablePackages,
-		CommentIgnorePrefix:    b.CommentIgnorePrefix,
-		ExcludeImports:         b.ExcludeImports,
+		DefaultOptions:                    options,
+		AllowCommentIgnores:               b.AllowCommentIgnores,
+		IgnoreUnstablePackages:            b.IgnoreUnstablePackages,
+		IgnoreSymbolMatchers:              slicesext.Map(b.IgnoreSymbols, newSymbolMatcher),
+		CommentIgnorePrefix:               b.CommentIgnorePrefix,
+		RequireCommentIgnoreJustification: b.RequireCommentIgnoreJustification,
+		ExcludeImports:                    b.ExcludeImports,
 	}, nil
 }
@@ -36,6 +36,9 @@ const (
 	purposeHeader    = "PURPOSE"
 
 	textHeader = idHeader + "\t" + categoriesHeader + "\t" + defaultHeader + "\t" + purposeHeader
+
+	lintRulesDocumentationURLPrefix     = "https://buf.build/docs/lint/rules/#"
+	breakingRulesDocumentationURLPrefix = "https://buf.build/docs/breaking/rules/#"
 )
 
 // topLevelCategoryIDToPriority is a map from builtin Category ID to the
@@ -45,17 +48,59 @@ const (
 //
 // priority 1 should be printed before priority 2.
 var topLevelCategoryIDToPriority = map[string]int{
-	"MINIMAL":   1,
-	"BASIC":     2,
-	"STANDARD":  3,
-	"DEFAULT":   4,
-	"COMMENTS":  5,
-	"UNARY_RPC": 6,
-	"OTHER":     7,
-	"FILE":      1,
-	"PACKAGE":   2,
-	"WIRE_JSON": 3,
-	"WIRE":      4,
+	"MINIMAL":                   1,
+	"BASIC":                     2,
+	"STANDARD":                  3,
+	"DEFAULT":                   4,
+	"COMMENTS":                  5,
+	"UNARY_RPC":                 6,
+	"GENERATED_NAMES":           7,
+	"NAMING":                    8,
+	"OTHER":                     9,
+	"FILE":                      1,
+	"PACKAGE":                   2,
+	"WIRE_JSON":                 3,
+	"WIRE":                      4,
+	"JSON":                      5,
+	"PROTOVALIDATE_CONSTRAINTS": 6,
+}
+
+// builtinRuleIDToConfigOptions is a map from builtin Rule ID to the configuration
+// options that affect the behavior of the Rule.
+//
+// Options are either buf.yaml keys or flags prefixed with "--".
+var builtinRuleIDToConfigOptions = map[string][]string{
+	"COMMENT_ENUM":                   commentsConfigOptions,
+	"COMMENT_ENUM_VALUE":             commentsConfigOptions,
+	"COMMENT_FIELD":                  commentsConfigOptions,
+	"COMMENT_MESSAGE":                commentsConfigOptions,
+	"COMMENT_ONEOF":                  commentsConfigOptions,
+	"COMMENT_RPC":                    commentsConfigOptions,
+	"COMMENT_SERVICE":                commentsConfigOptions,
+	"ENUM_ZERO_VALUE_SUFFIX":         {"lint.enum_zero_value_suffix"},
+	"EXTENSION_REGISTRY_NO_CONFLICT": {"--extension-registry"},
+	"NAMING_ENUM":                    {"lint.naming.enum"},
+	"NAMING_ENUM_VALUE":              {"lint.naming.enum_value"},
+	"NAMING_FIELD":                   {"lint.naming.field"},
+	"NAMING_MESSAGE":                 {"lint.naming.message"},
+	"NAMING_RPC":                     {"lint.naming.rpc"},
+	"NAMING_SERVICE":                 {"lint.naming.service"},
+	"PACKAGE_DIRECTORY_MATCH":        {"lint.package_directory_mappings"},
+	"RESERVED_REGISTRY_NO_REUSE":     {"--reserved-registry"},
+	"RPC_REQUEST_RESPONSE_UNIQUE": {
+		"lint.rpc_allow_same_request_response",
+		"lint.rpc_allow_google_protobuf_empty_requests",
+		"lint.rpc_allow_google_protobuf_empty_responses",
+	},
+	"RPC_REQUEST_STANDARD_NAME":  {"lint.rpc_allow_google_protobuf_empty_requests"},
+	"RPC_RESPONSE_STANDARD_NAME": {"lint.rpc_allow_google_protobuf_empty_responses"},
+	"SERVICE_SUFFIX":             {"lint.service_suffix"},
+}
+
+var commentsConfigOptions = []string{
+	"lint.comments.min_length",
+	"lint.comments.require_name_prefix",
+	"lint.comments.exclude_prefixes",
 }
 
 func printRules(writer io.Writer, rules []Rule, options ...PrintRulesOption) (retErr error) {
@@ -77,6 +122,9 @@ func printRules(writer io.Writer, rules []Rule, options ...PrintRulesOption) (re
 	if printRulesOptions.asJSON {
 		return printRulesJSON(writer, rules, categoriesFunc)
 	}
+	if printRulesOptions.asMarkdown {
+		return printRulesMarkdown(writer, rules, categoriesFunc)
+	}
 	return printRulesText(writer, rules, categoriesFunc)
 }
 
@@ -95,6 +143,75 @@ func printRulesJSON(writer io.Writer, rules []Rule, categoriesFunc func(Rule) []
 	return nil
 }
 
+// Rules already sorted in correct order.
+// Rules already filtered for deprecated.
+func printRulesMarkdown(writer io.Writer, rules []Rule, categoriesFunc func(Rule) []check.Category) error {
+	var sectionPluginNames []string
+	pluginNameToRules := make(map[string][]Rule)
+	for _, rule := range rules {
+		pluginName := rule.PluginName()
+		if _, ok := pluginNameToRules[pluginName]; !ok {
+			// Rules are sorted with builtin rules first, then by plugin name.
+			sectionPluginNames = append(sectionPluginNames, pluginName)
+		}
+		pluginNameToRules[pluginName] = append(pluginNameToRules[pluginName], rule)
+	}
+	for i, pluginName := range sectionPluginNames {
+		if i > 0 {
+			if _, err := fmt.Fprintln(writer); err != nil {
+				return err
+			}
+		}
+		heading := "Builtin rules"
+		if pluginName != "" {
+			heading = "Rules from plugin " + pluginName
+		}
+		if _, err := fmt.Fprintf(writer, "# %s\n", heading); err != nil {
+			return err
+		}
+		for _, rule := range pluginNameToRules[pluginName] {
+			if err := printRuleMarkdown(writer, rule, categoriesFunc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func printRuleMarkdown(writer io.Writer, rule Rule, categoriesFunc func(Rule) []check.Category) error {
+	externalRule := newExternalRule(rule, categoriesFunc)
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "\n## %s\n\n", externalRule.ID)
+	if externalRule.Purpose != "" {
+		_, _ = fmt.Fprintf(&sb, "%s\n\n", externalRule.Purpose)
+	}
+	categories := "none"
+	if len(externalRule.Categories) > 0 {
+		categories = "`" + strings.Join(externalRule.Categories, "`, `") + "`"
+	}
+	_, _ = fmt.Fprintf(&sb, "- **Categories:** %s\n", categories)
+	defaultString := "no"
+	if externalRule.Default {
+		defaultString = "yes"
+	}
+	_, _ = fmt.Fprintf(&sb, "- **Default:** %s\n", defaultString)
+	if externalRule.Deprecated {
+		if len(externalRule.Replacements) > 0 {
+			_, _ = fmt.Fprintf(&sb, "- **Deprecated:** replaced by `%s`\n", strings.Join(externalRule.Replacements, "`, `"))
+		} else {
+			_, _ = fmt.Fprintln(&sb, "- **Deprecated:** yes")
+		}
+	}
+	if len(externalRule.Options) > 0 {
+		_, _ = fmt.Fprintf(&sb, "- **Configuration:** `%s`\n", strings.Join(externalRule.Options, "`, `"))
+	}
+	if externalRule.DocumentationURL != "" {
+		_, _ = fmt.Fprintf(&sb, "- **Documentation:** %s\n", externalRule.DocumentationURL)
+	}
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
+
 // Rules already sorted in correct order.
 // Rules already filtered for deprecated.
 func printRulesText(writer io.Writer, rules []Rule, categoriesFunc func(Rule) []check.Category) (retErr error) {
@@ -335,6 +452,15 @@ type externalRule struct {
 	Plugin       string   `json:"plugin" yaml:"plugin"`
 	Deprecated   bool     `json:"deprecated" yaml:"deprecated"`
 	Replacements []string `json:"replacements" yaml:"replacements"`
+	// Options are the configuration options that affect the behavior of the rule.
+	//
+	// Only set for builtin rules.
+	Options []string `json:"options,omitempty" yaml:"options,omitempty"`
+	// DocumentationURL is the URL of the full documentation of the rule, including
+	// examples of violations and how to fix them.
+	//
+	// Only set for builtin rules.
+	DocumentationURL string `json:"documentation_url,omitempty" yaml:"documentation_url,omitempty"`
 }
 
 func newExternalRule(
@@ -342,18 +468,42 @@ func newExternalRule(
 	categoriesFunc func(Rule) []check.Category,
 ) *externalRule {
 	return &externalRule{
-		ID:           rule.ID(),
-		Categories:   slicesext.Map(categoriesFunc(rule), check.Category.ID),
-		Default:      rule.Default(),
-		Purpose:      rule.Purpose(),
-		Plugin:       rule.PluginName(),
-		Deprecated:   rule.Deprecated(),
-		Replacements: rule.ReplacementIDs(),
+		ID:               rule.ID(),
+		Categories:       slicesext.Map(categoriesFunc(rule), check.Category.ID),
+		Default:          rule.Default(),
+		Purpose:          rule.Purpose(),
+		Plugin:           rule.PluginName(),
+		Deprecated:       rule.Deprecated(),
+		Replacements:     rule.ReplacementIDs(),
+		Options:          getBuiltinRuleConfigOptions(rule),
+		DocumentationURL: getBuiltinRuleDocumentationURL(rule),
+	}
+}
+
+func getBuiltinRuleConfigOptions(rule Rule) []string {
+	if rule.PluginName() != "" || rule.Type() != check.RuleTypeLint {
+		return nil
+	}
+	return slices.Clone(builtinRuleIDToConfigOptions[rule.ID()])
+}
+
+func getBuiltinRuleDocumentationURL(rule Rule) string {
+	if rule.PluginName() != "" {
+		return ""
+	}
+	switch rule.Type() {
+	case check.RuleTypeLint:
+		return lintRulesDocumentationURLPrefix + strings.ToLower(rule.ID())
+	case check.RuleTypeBreaking:
+		return breakingRulesDocumentationURLPrefix + strings.ToLower(rule.ID())
+	default:
+		return ""
 	}
 }
 
 type printRulesOptions struct {
 	asJSON            bool
+	asMarkdown        bool
 	includeDeprecated bool
 }
 
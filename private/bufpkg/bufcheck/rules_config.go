@@ -35,11 +35,24 @@ func rulesConfigForCheckConfig(
 	ruleType check.RuleType,
 	relatedCheckConfigs []bufconfig.CheckConfig,
 ) (*rulesConfig, error) {
+	var warnRuleIDsAndCategoryIDs []string
+	if lintConfig, ok := checkConfig.(bufconfig.LintConfig); ok {
+		warnRuleIDsAndCategoryIDs = lintConfig.WarnIDsAndCategories()
+	}
+	var ignoreRuleIDOrCategoryIDToSymbols map[string][]string
+	var failOnRuleIDsAndCategoryIDs []string
+	if breakingConfig, ok := checkConfig.(bufconfig.BreakingConfig); ok {
+		ignoreRuleIDOrCategoryIDToSymbols = breakingConfig.IgnoreIDOrCategoryToSymbols()
+		failOnRuleIDsAndCategoryIDs = breakingConfig.FailOnIDsAndCategories()
+	}
 	return newRulesConfig(
 		checkConfig.UseIDsAndCategories(),
 		checkConfig.ExceptIDsAndCategories(),
+		warnRuleIDsAndCategoryIDs,
+		failOnRuleIDsAndCategoryIDs,
 		checkConfig.IgnorePaths(),
 		checkConfig.IgnoreIDOrCategoryToPaths(),
+		ignoreRuleIDOrCategoryIDToSymbols,
 		allRules,
 		allCategories,
 		ruleType,
@@ -75,6 +88,26 @@ type rulesConfig struct {
 	// Will only contain non-deprecated RuleIDs.
 	// This will only contain RuleIDs of the given RuleType.
 	IgnoreRuleIDToRootPaths map[string]map[string]struct{}
+	// IgnoreRuleIDToSymbolMatchers contains the compiled symbol patterns to ignore for
+	// specific RuleIDs.
+	//
+	// Will only contain non-deprecated RuleIDs.
+	// This will only contain RuleIDs of the given RuleType.
+	IgnoreRuleIDToSymbolMatchers map[string][]*symbolMatcher
+	// WarnRuleIDs contains the RuleIDs whose violations are warnings instead of errors.
+	//
+	// Will only contain non-deprecated RuleIDs.
+	// This will only contain RuleIDs of the given RuleType, but may contain RuleIDs
+	// that are not in RuleIDs.
+	WarnRuleIDs map[string]struct{}
+	// FailOnRuleIDs contains the RuleIDs whose breaking changes are failures. If non-empty,
+	// all other breaking changes are warnings, unless one of these Rules reports a breaking
+	// change at the same location.
+	//
+	// Will only contain non-deprecated RuleIDs.
+	// This will only contain RuleIDs of the given RuleType, but may contain RuleIDs
+	// that are not in RuleIDs.
+	FailOnRuleIDs []string
 	// ReferencedDeprecatedRuleIDToReplacementIDs contains a map from a Rule ID
 	// that was used in the configuration, to a map of the IDs that
 	// replace this Rule ID.
@@ -114,9 +147,15 @@ func newRulesConfig(
 	useRuleIDsAndCategoryIDs []string,
 	// May contain deprecated IDs.
 	exceptRuleIDsAndCategoryIDs []string,
+	// May contain deprecated IDs.
+	warnRuleIDsAndCategoryIDs []string,
+	// May contain deprecated IDs.
+	failOnRuleIDsAndCategoryIDs []string,
 	ignoreRootPaths []string,
 	// May contain deprecated IDs.
 	ignoreRuleIDOrCategoryIDToRootPaths map[string][]string,
+	// May contain deprecated IDs.
+	ignoreRuleIDOrCategoryIDToSymbols map[string][]string,
 	// Rules and Categories are guaranteed to be unique by ID at this point,
 	// including across each other.
 	allRules []Rule,
@@ -131,10 +170,12 @@ func newRulesConfig(
 		// We return here so that we can do some syserror checking below for expectations
 		// that certain variables are non-empty at certain points.
 		return &rulesConfig{
-			RuleType:                ruleType,
-			RuleIDs:                 make([]string, 0),
-			IgnoreRootPaths:         make(map[string]struct{}),
-			IgnoreRuleIDToRootPaths: make(map[string]map[string]struct{}),
+			RuleType:                     ruleType,
+			RuleIDs:                      make([]string, 0),
+			IgnoreRootPaths:              make(map[string]struct{}),
+			IgnoreRuleIDToRootPaths:      make(map[string]map[string]struct{}),
+			IgnoreRuleIDToSymbolMatchers: make(map[string][]*symbolMatcher),
+			WarnRuleIDs:                  make(map[string]struct{}),
 			ReferencedDeprecatedRuleIDToReplacementIDs:     make(map[string]map[string]struct{}),
 			ReferencedDeprecatedCategoryIDToReplacementIDs: make(map[string]map[string]struct{}),
 			UnusedPluginNameToRuleIDs:                      make(map[string][]string),
@@ -150,6 +191,13 @@ func newRulesConfig(
 	for ruleIDOrCategoryID, rootPaths := range ignoreRuleIDOrCategoryIDToRootPaths {
 		ignoreRuleIDOrCategoryIDToRootPathMap[ruleIDOrCategoryID] = slicesext.ToStructMap(rootPaths)
 	}
+	ignoreRuleIDOrCategoryIDToSymbolMap := make(
+		map[string]map[string]struct{},
+		len(ignoreRuleIDOrCategoryIDToSymbols),
+	)
+	for ruleIDOrCategoryID, symbols := range ignoreRuleIDOrCategoryIDToSymbols {
+		ignoreRuleIDOrCategoryIDToSymbolMap[ruleIDOrCategoryID] = slicesext.ToStructMap(symbols)
+	}
 
 	ruleIDToRule, err := getIDToRuleOrCategory(allRulesForType)
 	if err != nil {
@@ -172,7 +220,10 @@ func newRulesConfig(
 	for _, ids := range [][]string{
 		useRuleIDsAndCategoryIDs,
 		exceptRuleIDsAndCategoryIDs,
+		warnRuleIDsAndCategoryIDs,
+		failOnRuleIDsAndCategoryIDs,
 		slicesext.MapKeysToSlice(ignoreRuleIDOrCategoryIDToRootPathMap),
+		slicesext.MapKeysToSlice(ignoreRuleIDOrCategoryIDToSymbolMap),
 	} {
 		for _, id := range ids {
 			replacementRuleIDs, ok := deprecatedRuleIDToReplacementRuleIDs[id]
@@ -232,6 +283,22 @@ func newRulesConfig(
 	if err != nil {
 		return nil, err
 	}
+	warnRuleIDs, err := transformRuleOrCategoryIDsToRuleIDs(
+		warnRuleIDsAndCategoryIDs,
+		ruleIDToCategoryIDs,
+		categoryIDToRuleIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	failOnRuleIDs, err := transformRuleOrCategoryIDsToRuleIDs(
+		failOnRuleIDsAndCategoryIDs,
+		ruleIDToCategoryIDs,
+		categoryIDToRuleIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
 	ignoreRuleIDToRootPathMap, err := transformRuleOrCategoryIDToIgnoreRootPathsToRuleIDs(
 		ignoreRuleIDOrCategoryIDToRootPathMap,
 		ruleIDToCategoryIDs,
@@ -240,6 +307,15 @@ func newRulesConfig(
 	if err != nil {
 		return nil, err
 	}
+	// Symbols are sets of strings just like root paths, so the same transformation applies.
+	ignoreRuleIDToSymbolMap, err := transformRuleOrCategoryIDToIgnoreRootPathsToRuleIDs(
+		ignoreRuleIDOrCategoryIDToSymbolMap,
+		ruleIDToCategoryIDs,
+		categoryIDToRuleIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
 
 	// Replace deprecated rules.
 	useRuleIDs = transformRuleIDsToUndeprecated(
@@ -250,10 +326,22 @@ func newRulesConfig(
 		exceptRuleIDs,
 		deprecatedRuleIDToReplacementRuleIDs,
 	)
+	warnRuleIDs = transformRuleIDsToUndeprecated(
+		warnRuleIDs,
+		deprecatedRuleIDToReplacementRuleIDs,
+	)
+	failOnRuleIDs = transformRuleIDsToUndeprecated(
+		failOnRuleIDs,
+		deprecatedRuleIDToReplacementRuleIDs,
+	)
 	ignoreRuleIDToRootPathMap = transformRuleIDToIgnoreRootPathsToUndeprecated(
 		ignoreRuleIDToRootPathMap,
 		deprecatedRuleIDToReplacementRuleIDs,
 	)
+	ignoreRuleIDToSymbolMap = transformRuleIDToIgnoreRootPathsToUndeprecated(
+		ignoreRuleIDToSymbolMap,
+		deprecatedRuleIDToReplacementRuleIDs,
+	)
 
 	// Figure out result rules.
 	resultRuleIDToRule := make(map[string]Rule)
@@ -332,10 +420,13 @@ func newRulesConfig(
 	}
 
 	return &rulesConfig{
-		RuleType:                ruleType,
-		RuleIDs:                 slicesext.Map(resultRules, Rule.ID),
-		IgnoreRootPaths:         slicesext.ToStructMap(ignoreRootPaths),
-		IgnoreRuleIDToRootPaths: ignoreRuleIDToRootPathMap,
+		RuleType:                     ruleType,
+		RuleIDs:                      slicesext.Map(resultRules, Rule.ID),
+		IgnoreRootPaths:              slicesext.ToStructMap(ignoreRootPaths),
+		IgnoreRuleIDToRootPaths:      ignoreRuleIDToRootPathMap,
+		IgnoreRuleIDToSymbolMatchers: getRuleIDToSymbolMatchers(ignoreRuleIDToSymbolMap),
+		WarnRuleIDs:                  slicesext.ToStructMap(warnRuleIDs),
+		FailOnRuleIDs:                failOnRuleIDs,
 		ReferencedDeprecatedRuleIDToReplacementIDs:     referencedDeprecatedRuleIDToReplacementIDs,
 		ReferencedDeprecatedCategoryIDToReplacementIDs: referencedDeprecatedCategoryIDToReplacementIDs,
 		UnusedPluginNameToRuleIDs:                      unusedPluginNameToRuleIDs,
@@ -540,6 +631,14 @@ func transformRuleOrCategoryIDToIgnoreRootPathsToRuleIDs(
 	return ruleIDToIgnoreRootPaths, nil
 }
 
+func getRuleIDToSymbolMatchers(ruleIDToSymbols map[string]map[string]struct{}) map[string][]*symbolMatcher {
+	ruleIDToSymbolMatchers := make(map[string][]*symbolMatcher, len(ruleIDToSymbols))
+	for ruleID, symbolMap := range ruleIDToSymbols {
+		ruleIDToSymbolMatchers[ruleID] = slicesext.Map(slicesext.MapKeysToSortedSlice(symbolMap), newSymbolMatcher)
+	}
+	return ruleIDToSymbolMatchers
+}
+
 func transformRuleIDsToUndeprecated(
 	ruleIDs []string,
 	deprecatedRuleIDToReplacementIDs map[string][]string,
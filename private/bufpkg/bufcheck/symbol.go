@@ -0,0 +1,178 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcheck
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// These are the field numbers of the descriptors that have names
+	// in google.protobuf.FileDescriptorProto and google.protobuf.DescriptorProto,
+	// google.protobuf.EnumDescriptorProto, and google.protobuf.ServiceDescriptorProto.
+	fileMessageTypeTag   = 4
+	fileEnumTypeTag      = 5
+	fileServiceTag       = 6
+	fileExtensionTag     = 7
+	messageFieldTag      = 2
+	messageNestedTypeTag = 3
+	messageEnumTypeTag   = 4
+	messageExtensionTag  = 6
+	messageOneofDeclTag  = 8
+	enumValueTag         = 2
+	serviceMethodTag     = 2
+)
+
+// symbolMatcher matches fully-qualified names against a symbol pattern.
+//
+// Within a pattern, "*" matches a single name component and "**" matches
+// zero or more name components.
+type symbolMatcher struct {
+	components []string
+}
+
+func newSymbolMatcher(pattern string) *symbolMatcher {
+	return &symbolMatcher{
+		components: strings.Split(pattern, "."),
+	}
+}
+
+// Matches returns true if the fully-qualified name matches the pattern.
+func (s *symbolMatcher) Matches(fullName string) bool {
+	return matchSymbolComponents(s.components, strings.Split(fullName, "."))
+}
+
+func matchSymbolComponents(patternComponents []string, nameComponents []string) bool {
+	if len(patternComponents) == 0 {
+		return len(nameComponents) == 0
+	}
+	switch patternComponent := patternComponents[0]; patternComponent {
+	case "**":
+		for i := 0; i <= len(nameComponents); i++ {
+			if matchSymbolComponents(patternComponents[1:], nameComponents[i:]) {
+				return true
+			}
+		}
+		return false
+	default:
+		if len(nameComponents) == 0 {
+			return false
+		}
+		if patternComponent != "*" && patternComponent != nameComponents[0] {
+			return false
+		}
+		return matchSymbolComponents(patternComponents[1:], nameComponents[1:])
+	}
+}
+
+// getFullNamesForSourcePath returns the fully-qualified names of the descriptor at
+// the source path, all of its parent descriptors, and the package of the file.
+//
+// Enum values are returned both in their Protobuf scope, which is a sibling of the
+// enum, and nested within the enum.
+//
+// Source paths that do not point into a named descriptor only return the package.
+func getFullNamesForSourcePath(
+	fileDescriptor protoreflect.FileDescriptor,
+	sourcePath protoreflect.SourcePath,
+) []string {
+	var fullNames []string
+	if packageName := fileDescriptor.Package(); packageName != "" {
+		fullNames = append(fullNames, string(packageName))
+	}
+	if len(sourcePath) < 2 {
+		return fullNames
+	}
+	index := int(sourcePath[1])
+	switch sourcePath[0] {
+	case fileMessageTypeTag:
+		if messages := fileDescriptor.Messages(); index < messages.Len() {
+			return append(fullNames, getFullNamesForMessageSourcePath(messages.Get(index), sourcePath[2:])...)
+		}
+	case fileEnumTypeTag:
+		if enums := fileDescriptor.Enums(); index < enums.Len() {
+			return append(fullNames, getFullNamesForEnumSourcePath(enums.Get(index), sourcePath[2:])...)
+		}
+	case fileServiceTag:
+		if services := fileDescriptor.Services(); index < services.Len() {
+			service := services.Get(index)
+			fullNames = append(fullNames, string(service.FullName()))
+			if len(sourcePath) >= 4 && sourcePath[2] == serviceMethodTag {
+				if methods := service.Methods(); int(sourcePath[3]) < methods.Len() {
+					fullNames = append(fullNames, string(methods.Get(int(sourcePath[3])).FullName()))
+				}
+			}
+		}
+	case fileExtensionTag:
+		if extensions := fileDescriptor.Extensions(); index < extensions.Len() {
+			fullNames = append(fullNames, string(extensions.Get(index).FullName()))
+		}
+	}
+	return fullNames
+}
+
+func getFullNamesForMessageSourcePath(
+	messageDescriptor protoreflect.MessageDescriptor,
+	sourcePath protoreflect.SourcePath,
+) []string {
+	fullNames := []string{string(messageDescriptor.FullName())}
+	if len(sourcePath) < 2 {
+		return fullNames
+	}
+	index := int(sourcePath[1])
+	switch sourcePath[0] {
+	case messageFieldTag:
+		if fields := messageDescriptor.Fields(); index < fields.Len() {
+			fullNames = append(fullNames, string(fields.Get(index).FullName()))
+		}
+	case messageNestedTypeTag:
+		if messages := messageDescriptor.Messages(); index < messages.Len() {
+			fullNames = append(fullNames, getFullNamesForMessageSourcePath(messages.Get(index), sourcePath[2:])...)
+		}
+	case messageEnumTypeTag:
+		if enums := messageDescriptor.Enums(); index < enums.Len() {
+			fullNames = append(fullNames, getFullNamesForEnumSourcePath(enums.Get(index), sourcePath[2:])...)
+		}
+	case messageExtensionTag:
+		if extensions := messageDescriptor.Extensions(); index < extensions.Len() {
+			fullNames = append(fullNames, string(extensions.Get(index).FullName()))
+		}
+	case messageOneofDeclTag:
+		if oneofs := messageDescriptor.Oneofs(); index < oneofs.Len() {
+			fullNames = append(fullNames, string(oneofs.Get(index).FullName()))
+		}
+	}
+	return fullNames
+}
+
+func getFullNamesForEnumSourcePath(
+	enumDescriptor protoreflect.EnumDescriptor,
+	sourcePath protoreflect.SourcePath,
+) []string {
+	fullNames := []string{string(enumDescriptor.FullName())}
+	if len(sourcePath) >= 2 && sourcePath[0] == enumValueTag {
+		if values := enumDescriptor.Values(); int(sourcePath[1]) < values.Len() {
+			value := values.Get(int(sourcePath[1]))
+			fullNames = append(
+				fullNames,
+				string(value.FullName()),
+				string(enumDescriptor.FullName().Append(value.Name())),
+			)
+		}
+	}
+	return fullNames
+}
@@ -0,0 +1,42 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolMatcher(t *testing.T) {
+	t.Parallel()
+	testSymbolMatcher(t, "acme.v1.Foo", "acme.v1.Foo", true)
+	testSymbolMatcher(t, "acme.v1.Foo", "acme.v1.Bar", false)
+	testSymbolMatcher(t, "acme.v1.Foo", "acme.v1.Foo.bar", false)
+	testSymbolMatcher(t, "acme.*.Foo", "acme.v1.Foo", true)
+	testSymbolMatcher(t, "acme.*.Foo", "acme.Foo", false)
+	testSymbolMatcher(t, "acme.*.Foo", "acme.internal.v1.Foo", false)
+	testSymbolMatcher(t, "acme.internal.**", "acme.internal", true)
+	testSymbolMatcher(t, "acme.internal.**", "acme.internal.v1.Foo.bar", true)
+	testSymbolMatcher(t, "acme.internal.**", "acme.v1.Foo", false)
+	testSymbolMatcher(t, "**.Foo", "Foo", true)
+	testSymbolMatcher(t, "**.Foo", "acme.v1.Foo", true)
+	testSymbolMatcher(t, "**.Foo", "acme.v1.Foo.bar", false)
+	testSymbolMatcher(t, "acme.**.bar", "acme.v1.Foo.bar", true)
+}
+
+func testSymbolMatcher(t *testing.T, pattern string, fullName string, expected bool) {
+	assert.Equal(t, expected, newSymbolMatcher(pattern).Matches(fullName), "pattern %q, full name %q", pattern, fullName)
+}
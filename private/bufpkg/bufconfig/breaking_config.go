@@ -14,17 +14,27 @@
 
 package bufconfig
 
+import (
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+)
+
 var (
 	// DefaultBreakingConfigV1 is the default breaking config for v1.
-	DefaultBreakingConfigV1 BreakingConfig = NewBreakingConfig(
+	DefaultBreakingConfigV1 BreakingConfig = newBreakingConfig(
 		defaultCheckConfigV1,
 		false,
+		nil,
+		nil,
+		nil,
 	)
 
 	// DefaultBreakingConfigV2 is the default breaking config for v1.
-	DefaultBreakingConfigV2 BreakingConfig = NewBreakingConfig(
+	DefaultBreakingConfigV2 BreakingConfig = newBreakingConfig(
 		defaultCheckConfigV2,
 		false,
+		nil,
+		nil,
+		nil,
 	)
 )
 
@@ -33,6 +43,32 @@ type BreakingConfig interface {
 	CheckConfig
 
 	IgnoreUnstablePackages() bool
+	// IgnoreSymbols returns the fully-qualified symbol patterns to ignore.
+	//
+	// An annotation is ignored if the descriptor it refers to, any of its parent
+	// descriptors, or its package matches one of these patterns. Within a pattern,
+	// "*" matches a single name component and "**" matches zero or more name
+	// components, for example "acme.internal.**" or "acme.v1.*.OldMethod".
+	//
+	// Sorted.
+	IgnoreSymbols() []string
+	// IgnoreIDOrCategoryToSymbols returns the fully-qualified symbol patterns to ignore
+	// for specific IDs and categories.
+	//
+	// These are the values of breaking.ignore_only that are symbol patterns instead of
+	// paths, see IsIgnoreOnlySymbolPattern. Patterns are matched as with IgnoreSymbols.
+	//
+	// Patterns are sorted.
+	IgnoreIDOrCategoryToSymbols() map[string][]string
+	// FailOnIDsAndCategories returns the rule IDs and category IDs whose breaking changes
+	// result in a failure.
+	//
+	// If empty, all breaking changes result in a failure. Otherwise, the breaking changes
+	// of all other rules are warnings, which are printed but do not result in a failure,
+	// unless one of these rules reports a breaking change at the same location.
+	//
+	// Sorted.
+	FailOnIDsAndCategories() []string
 
 	isBreakingConfig()
 }
@@ -41,10 +77,23 @@ type BreakingConfig interface {
 func NewBreakingConfig(
 	checkConfig CheckConfig,
 	ignoreUnstablePackages bool,
+	ignoreSymbols []string,
+	ignoreIDOrCategoryToSymbols map[string][]string,
+	failOnIDsAndCategories []string,
 ) BreakingConfig {
+	var sortedIgnoreIDOrCategoryToSymbols map[string][]string
+	if len(ignoreIDOrCategoryToSymbols) > 0 {
+		sortedIgnoreIDOrCategoryToSymbols = make(map[string][]string, len(ignoreIDOrCategoryToSymbols))
+		for idOrCategory, symbols := range ignoreIDOrCategoryToSymbols {
+			sortedIgnoreIDOrCategoryToSymbols[idOrCategory] = slicesext.ToUniqueSorted(symbols)
+		}
+	}
 	return newBreakingConfig(
 		checkConfig,
 		ignoreUnstablePackages,
+		slicesext.ToUniqueSorted(ignoreSymbols),
+		sortedIgnoreIDOrCategoryToSymbols,
+		slicesext.ToUniqueSorted(failOnIDsAndCategories),
 	)
 }
 
@@ -53,16 +102,25 @@ func NewBreakingConfig(
 type breakingConfig struct {
 	CheckConfig
 
-	ignoreUnstablePackages bool
+	ignoreUnstablePackages      bool
+	ignoreSymbols               []string
+	ignoreIDOrCategoryToSymbols map[string][]string
+	failOnIDsAndCategories      []string
 }
 
 func newBreakingConfig(
 	checkConfig CheckConfig,
 	ignoreUnstablePackages bool,
+	ignoreSymbols []string,
+	ignoreIDOrCategoryToSymbols map[string][]string,
+	failOnIDsAndCategories []string,
 ) *breakingConfig {
 	return &breakingConfig{
-		CheckConfig:            checkConfig,
-		ignoreUnstablePackages: ignoreUnstablePackages,
+		CheckConfig:                 checkConfig,
+		ignoreUnstablePackages:      ignoreUnstablePackages,
+		ignoreSymbols:               ignoreSymbols,
+		ignoreIDOrCategoryToSymbols: ignoreIDOrCategoryToSymbols,
+		failOnIDsAndCategories:      failOnIDsAndCategories,
 	}
 }
 
@@ -70,4 +128,16 @@ func (b *breakingConfig) IgnoreUnstablePackages() bool {
 	return b.ignoreUnstablePackages
 }
 
+func (b *breakingConfig) IgnoreSymbols() []string {
+	return slicesext.Copy(b.ignoreSymbols)
+}
+
+func (b *breakingConfig) IgnoreIDOrCategoryToSymbols() map[string][]string {
+	return copyStringToStringSliceMap(b.ignoreIDOrCategoryToSymbols)
+}
+
+func (b *breakingConfig) FailOnIDsAndCategories() []string {
+	return slicesext.Copy(b.failOnIDsAndCategories)
+}
+
 func (*breakingConfig) isBreakingConfig() {}
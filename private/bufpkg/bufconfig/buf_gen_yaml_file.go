@@ -15,12 +15,19 @@
 package bufconfig
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
 	"github.com/bufbuild/buf/private/pkg/encoding"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
 	"github.com/bufbuild/buf/private/pkg/slicesext"
 	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/syserror"
@@ -75,12 +82,28 @@ func NewBufGenYAMLFile(
 // GetBufGenYAMLFileForPrefix gets the buf.gen.yaml file at the given bucket prefix.
 //
 // The buf.gen.yaml file will be attempted to be read at prefix/buf.gen.yaml.
+//
+// By default, templates referenced by "extends" are read from the same bucket,
+// relative to prefix. This can be changed with BufGenYAMLFileWithExtendsReadFunc.
 func GetBufGenYAMLFileForPrefix(
 	ctx context.Context,
 	bucket storage.ReadBucket,
 	prefix string,
+	options ...BufGenYAMLFileOption,
 ) (BufGenYAMLFile, error) {
-	return getFileForPrefix(ctx, bucket, prefix, bufGenYAMLFileNames, bufGenYAMLFileNameToSupportedFileVersions, readBufGenYAMLFile)
+	bufGenYAMLFileOptions := newBufGenYAMLFileOptions()
+	bufGenYAMLFileOptions.extendsDirPath = prefix
+	bufGenYAMLFileOptions.extendsReadFunc = func(path string) ([]byte, error) {
+		path, err := normalpath.NormalizeAndValidate(path)
+		if err != nil {
+			return nil, err
+		}
+		return storage.ReadPath(ctx, bucket, path)
+	}
+	for _, option := range options {
+		option(bufGenYAMLFileOptions)
+	}
+	return getFileForPrefix(ctx, bucket, prefix, bufGenYAMLFileNames, bufGenYAMLFileNameToSupportedFileVersions, newReadBufGenYAMLFileFunc(bufGenYAMLFileOptions))
 }
 
 // GetBufGenYAMLFileVersionForPrefix gets the buf.gen.yaml file version at the given bucket prefix.
@@ -108,15 +131,79 @@ func PutBufGenYAMLFileForPrefix(
 }
 
 // ReadBufGenYAMLFile reads the BufGenYAMLFile from the io.Reader.
-func ReadBufGenYAMLFile(reader io.Reader) (BufGenYAMLFile, error) {
-	return readFile(reader, "", readBufGenYAMLFile)
+//
+// By default, templates that set "extends" cannot be read, use
+// BufGenYAMLFileWithExtendsReadFunc to allow this.
+func ReadBufGenYAMLFile(reader io.Reader, options ...BufGenYAMLFileOption) (BufGenYAMLFile, error) {
+	bufGenYAMLFileOptions := newBufGenYAMLFileOptions()
+	for _, option := range options {
+		option(bufGenYAMLFileOptions)
+	}
+	return readFile(reader, "", newReadBufGenYAMLFileFunc(bufGenYAMLFileOptions))
 }
 
 // WriteBufGenYAMLFile writes the BufGenYAMLFile to the io.Writer.
+//
+// If the BufGenYAMLFile was read from a template that set "extends", the merged
+// result is written, and "extends" is not set.
 func WriteBufGenYAMLFile(writer io.Writer, bufGenYAMLFile BufGenYAMLFile) error {
 	return writeFile(writer, bufGenYAMLFile, writeBufGenYAMLFile)
 }
 
+// BufGenYAMLFileOption is an option for reading a BufGenYAMLFile.
+type BufGenYAMLFileOption func(*bufGenYAMLFileOptions)
+
+// BufGenYAMLFileWithExtendsReadFunc returns a new BufGenYAMLFileOption that reads
+// the templates referenced by the "extends" key of v2 buf.gen.yaml files with readFunc.
+//
+// The path given to readFunc is normalized. Relative paths in "extends" are joined to
+// the directory of the template that references them, where dirPath is the directory
+// of the template being read. Absolute paths are passed as-is.
+func BufGenYAMLFileWithExtendsReadFunc(
+	dirPath string,
+	readFunc func(path string) ([]byte, error),
+) BufGenYAMLFileOption {
+	return func(bufGenYAMLFileOptions *bufGenYAMLFileOptions) {
+		bufGenYAMLFileOptions.extendsDirPath = dirPath
+		bufGenYAMLFileOptions.extendsReadFunc = readFunc
+	}
+}
+
+// BufGenYAMLFileWithExtendsFromOS returns a new BufGenYAMLFileOption that reads the
+// templates referenced by the "extends" key of v2 buf.gen.yaml files from the OS
+// filesystem, where dirPath is the directory of the template being read.
+//
+// Templates may extend templates outside of the directory of the template, for example
+// a template shared between repositories.
+func BufGenYAMLFileWithExtendsFromOS(dirPath string) BufGenYAMLFileOption {
+	return BufGenYAMLFileWithExtendsReadFunc(
+		normalpath.Normalize(dirPath),
+		func(path string) ([]byte, error) {
+			return os.ReadFile(normalpath.Unnormalize(path))
+		},
+	)
+}
+
+// BufGenYAMLFileWithEnvFunc returns a new BufGenYAMLFileOption that interpolates
+// environment variables in the values of the buf.gen.yaml file, including templates
+// referenced by "extends", with the values returned by envFunc.
+//
+// Only v2 files that set "interpolate_env: true" are interpolated, so that values of
+// existing files that contain a literal "${" are not changed. Each template referenced
+// by "extends" must set "interpolate_env" itself.
+//
+// The forms "${VAR}", "${VAR:-default}", and "${VAR:?message}" are supported, where
+// "${VAR}" and "${VAR:?message}" result in an error if the variable is unset or empty.
+// Use "$${" to write a literal "${". Values are interpolated in place, so that errors
+// refer to the lines of the file.
+//
+// The default is to not interpolate environment variables.
+func BufGenYAMLFileWithEnvFunc(envFunc func(string) string) BufGenYAMLFileOption {
+	return func(bufGenYAMLFileOptions *bufGenYAMLFileOptions) {
+		bufGenYAMLFileOptions.envFunc = envFunc
+	}
+}
+
 // *** PRIVATE ***
 
 type bufGenYAMLFile struct {
@@ -165,11 +252,37 @@ func (*bufGenYAMLFile) isBufGenYAMLFile() {}
 func (*bufGenYAMLFile) isFile()           {}
 func (*bufGenYAMLFile) isFileInfo()       {}
 
+func newReadBufGenYAMLFileFunc(
+	bufGenYAMLFileOptions *bufGenYAMLFileOptions,
+) func([]byte, ObjectData, bool) (BufGenYAMLFile, error) {
+	return func(data []byte, objectData ObjectData, allowJSON bool) (BufGenYAMLFile, error) {
+		return readBufGenYAMLFile(data, objectData, allowJSON, bufGenYAMLFileOptions)
+	}
+}
+
 func readBufGenYAMLFile(
 	data []byte,
 	objectData ObjectData,
 	allowJSON bool,
+	bufGenYAMLFileOptions *bufGenYAMLFileOptions,
 ) (BufGenYAMLFile, error) {
+	extendsReadFunc := bufGenYAMLFileOptions.extendsReadFunc
+	if envFunc := bufGenYAMLFileOptions.envFunc; envFunc != nil {
+		var err error
+		data, err = interpolateEnvForDataIfEnabled(data, envFunc)
+		if err != nil {
+			return nil, err
+		}
+		if extendsReadFunc != nil {
+			extendsReadFunc = func(path string) ([]byte, error) {
+				data, err := bufGenYAMLFileOptions.extendsReadFunc(path)
+				if err != nil {
+					return nil, err
+				}
+				return interpolateEnvForDataIfEnabled(data, envFunc)
+			}
+		}
+	}
 	// We have always enforced that buf.gen.yamls have file versions.
 	fileVersion, err := getFileVersionForData(data, allowJSON, true, bufGenYAMLFileNameToSupportedFileVersions, FileVersionV2, defaultBufGenYAMLFileVersion)
 	if err != nil {
@@ -211,6 +324,15 @@ func readBufGenYAMLFile(
 		if err := getUnmarshalStrict(allowJSON)(data, &externalGenYAMLFile); err != nil {
 			return nil, fmt.Errorf("invalid as version %v: %w", fileVersion, err)
 		}
+		externalGenYAMLFile, err = resolveExternalBufGenYAMLFileV2Extends(
+			externalGenYAMLFile,
+			bufGenYAMLFileOptions.extendsDirPath,
+			extendsReadFunc,
+			make(map[string]struct{}),
+		)
+		if err != nil {
+			return nil, err
+		}
 		generateConfig, err := newGenerateConfigFromExternalFileV2(externalGenYAMLFile)
 		if err != nil {
 			return nil, err
@@ -222,6 +344,9 @@ func readBufGenYAMLFile(
 		if err != nil {
 			return nil, err
 		}
+		if err := validateInputConfigPluginNames(inputConfigs, generateConfig.GeneratePluginConfigs()); err != nil {
+			return nil, err
+		}
 		return newBufGenYAMLFile(
 			fileVersion,
 			objectData,
@@ -258,7 +383,7 @@ func writeBufGenYAMLFile(writer io.Writer, bufGenYAMLFile BufGenYAMLFile) error
 	}
 	externalBufGenYAMLFileV2 := externalBufGenYAMLFileV2{
 		Version: FileVersionV2.String(),
-		Clean:   bufGenYAMLFile.GenerateConfig().CleanPluginOuts(),
+		Clean:   getBoolPointerIfTrue(bufGenYAMLFile.GenerateConfig().CleanPluginOuts()),
 		Plugins: externalPluginConfigsV2,
 		Managed: externalManagedConfigV2,
 		Inputs:  externalInputConfigsV2,
@@ -271,6 +396,184 @@ func writeBufGenYAMLFile(writer io.Writer, bufGenYAMLFile BufGenYAMLFile) error
 	return err
 }
 
+// resolveExternalBufGenYAMLFileV2Extends reads the template referenced by "extends",
+// recursively, and merges the given file on top of it.
+//
+// A plugin in the extending file replaces the plugin in the base file with the same
+// plugin name and out, and all other plugins are appended in order. Managed mode
+// disable and override rules are appended to those of the base file, so that rules
+// in the extending file take precedence. Inputs are inherited only if the extending
+// file does not specify any. Clean and managed mode are inherited only if the extending
+// file does not set them.
+func resolveExternalBufGenYAMLFileV2Extends(
+	externalFile externalBufGenYAMLFileV2,
+	dirPath string,
+	extendsReadFunc func(string) ([]byte, error),
+	seenPaths map[string]struct{},
+) (externalBufGenYAMLFileV2, error) {
+	if externalFile.Extends == "" {
+		return externalFile, nil
+	}
+	if extendsReadFunc == nil {
+		return externalFile, fmt.Errorf("extends %q: extending templates is not supported in this context", externalFile.Extends)
+	}
+	if isBSRExtends(externalFile.Extends) {
+		return externalFile, fmt.Errorf(
+			"extends %q: extending templates on the BSR is not supported, extends must be the path to a local buf.gen.yaml file",
+			externalFile.Extends,
+		)
+	}
+	path := normalpath.Normalize(externalFile.Extends)
+	if !filepath.IsAbs(externalFile.Extends) {
+		path = normalpath.Join(dirPath, path)
+	}
+	if _, ok := seenPaths[path]; ok {
+		return externalFile, fmt.Errorf("extends %q: cycle detected", externalFile.Extends)
+	}
+	seenPaths[path] = struct{}{}
+	data, err := extendsReadFunc(path)
+	if err != nil {
+		return externalFile, fmt.Errorf("extends %q: %w", externalFile.Extends, err)
+	}
+	fileVersion, err := getFileVersionForData(data, true, true, bufGenYAMLFileNameToSupportedFileVersions, FileVersionV2, defaultBufGenYAMLFileVersion)
+	if err != nil {
+		return externalFile, fmt.Errorf("extends %q: %w", externalFile.Extends, err)
+	}
+	if fileVersion != FileVersionV2 {
+		return externalFile, fmt.Errorf("extends %q: can only extend templates of version %v, got %v", externalFile.Extends, FileVersionV2, fileVersion)
+	}
+	var baseExternalFile externalBufGenYAMLFileV2
+	if err := getUnmarshalStrict(true)(data, &baseExternalFile); err != nil {
+		return externalFile, fmt.Errorf("extends %q: invalid as version %v: %w", externalFile.Extends, fileVersion, err)
+	}
+	baseExternalFile, err = resolveExternalBufGenYAMLFileV2Extends(
+		baseExternalFile,
+		normalpath.Dir(path),
+		extendsReadFunc,
+		seenPaths,
+	)
+	if err != nil {
+		return externalFile, err
+	}
+	return mergeExternalBufGenYAMLFileV2s(baseExternalFile, externalFile), nil
+}
+
+func mergeExternalBufGenYAMLFileV2s(
+	baseExternalFile externalBufGenYAMLFileV2,
+	externalFile externalBufGenYAMLFileV2,
+) externalBufGenYAMLFileV2 {
+	plugins := slices.Clone(baseExternalFile.Plugins)
+	for _, plugin := range externalFile.Plugins {
+		key := getExternalGeneratePluginConfigV2Key(plugin)
+		index := slices.IndexFunc(
+			plugins,
+			func(basePlugin externalGeneratePluginConfigV2) bool {
+				return getExternalGeneratePluginConfigV2Key(basePlugin) == key
+			},
+		)
+		if index >= 0 {
+			plugins[index] = plugin
+		} else {
+			plugins = append(plugins, plugin)
+		}
+	}
+	inputs := externalFile.Inputs
+	if len(inputs) == 0 {
+		inputs = baseExternalFile.Inputs
+	}
+	return externalBufGenYAMLFileV2{
+		Version: externalFile.Version,
+		Managed: externalGenerateManagedConfigV2{
+			Enabled:  cmp.Or(externalFile.Managed.Enabled, baseExternalFile.Managed.Enabled),
+			Disable:  append(slices.Clone(baseExternalFile.Managed.Disable), externalFile.Managed.Disable...),
+			Override: append(slices.Clone(baseExternalFile.Managed.Override), externalFile.Managed.Override...),
+		},
+		Clean:   cmp.Or(externalFile.Clean, baseExternalFile.Clean),
+		Plugins: plugins,
+		Inputs:  inputs,
+	}
+}
+
+// isBSRExtends returns true if the value of "extends" is a reference to the BSR, such as
+// buf.build/acme/templates, rather than a local path.
+//
+// TODO FUTURE: resolve templates on the BSR. Modules on the BSR only contain .proto,
+// documentation, and license files, so there is no template to read for a reference yet.
+func isBSRExtends(extends string) bool {
+	if filepath.IsAbs(extends) {
+		return false
+	}
+	switch filepath.Ext(extends) {
+	case ".yaml", ".yml", ".json":
+		return false
+	}
+	ref, err := bufparse.ParseRef(extends)
+	if err != nil {
+		return false
+	}
+	// Local paths such as shared/gen/base parse as references, but registries are hostnames.
+	return strings.Contains(ref.FullName().Registry(), ".")
+}
+
+// getBoolPointerIfTrue returns a pointer to true if the value is true, and nil otherwise,
+// so that false values are omitted when written.
+func getBoolPointerIfTrue(value bool) *bool {
+	if !value {
+		return nil
+	}
+	return &value
+}
+
+// getExternalGeneratePluginConfigV2Key returns the key used to match plugins
+// when merging templates. The version of a remote plugin is not part of the key.
+func getExternalGeneratePluginConfigV2Key(externalConfig externalGeneratePluginConfigV2) string {
+	var name string
+	switch {
+	case externalConfig.Remote != nil:
+		name = "remote:" + *externalConfig.Remote
+		if versionIndex := strings.LastIndex(name, ":"); versionIndex > strings.LastIndex(name, "/") {
+			name = name[:versionIndex]
+		}
+	case externalConfig.Local != nil:
+		name = fmt.Sprintf("local:%v", externalConfig.Local)
+	case externalConfig.ProtocBuiltin != nil:
+		name = "protoc_builtin:" + *externalConfig.ProtocBuiltin
+	}
+	return name + "@" + normalpath.Normalize(externalConfig.Out)
+}
+
+// validateInputConfigPluginNames validates that the plugin names of each input
+// config are the names of configured plugins.
+func validateInputConfigPluginNames(
+	inputConfigs []InputConfig,
+	pluginConfigs []GeneratePluginConfig,
+) error {
+	pluginNames := make(map[string]struct{}, len(pluginConfigs))
+	for _, pluginConfig := range pluginConfigs {
+		pluginNames[pluginConfig.Name()] = struct{}{}
+	}
+	for _, inputConfig := range inputConfigs {
+		for _, pluginName := range inputConfig.PluginNames() {
+			if _, ok := pluginNames[pluginName]; !ok {
+				return fmt.Errorf("input %s: plugin %q is not configured in plugins", inputConfig.Location(), pluginName)
+			}
+		}
+	}
+	return nil
+}
+
+type bufGenYAMLFileOptions struct {
+	extendsDirPath  string
+	extendsReadFunc func(string) ([]byte, error)
+	envFunc         func(string) string
+}
+
+func newBufGenYAMLFileOptions() *bufGenYAMLFileOptions {
+	return &bufGenYAMLFileOptions{
+		extendsDirPath: ".",
+	}
+}
+
 // externalBufGenYAMLFileV1Beta1 represents the v1beta buf.gen.yaml file.
 type externalBufGenYAMLFileV1Beta1 struct {
 	Version string `json:"version,omitempty" yaml:"version,omitempty"`
@@ -320,6 +623,10 @@ type externalGeneratePluginConfigV1 struct {
 	Path       any    `json:"path,omitempty" yaml:"path,omitempty"`
 	ProtocPath any    `json:"protoc_path,omitempty" yaml:"protoc_path,omitempty"`
 	Strategy   string `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+	// IncludeImports and IncludeWKT are set per plugin, as some plugins need to generate
+	// code for the full import closure while others must only see the target files.
+	IncludeImports bool `json:"include_imports,omitempty" yaml:"include_imports,omitempty"`
+	IncludeWKT     bool `json:"include_wkt,omitempty" yaml:"include_wkt,omitempty"`
 }
 
 // externalGenerateManagedConfigV1 represents the managed mode config in a v1 buf.gen.yaml file.
@@ -482,13 +789,23 @@ type externalTypesConfigV1 struct {
 
 // externalBufGenYAMLFileV2 represents the v2 buf.gen.yaml file.
 type externalBufGenYAMLFileV2 struct {
-	Version string                          `json:"version,omitempty" yaml:"version,omitempty"`
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// Extends is the path to another v2 buf.gen.yaml file that this file is merged on top of.
+	Extends string                          `json:"extends,omitempty" yaml:"extends,omitempty"`
 	Managed externalGenerateManagedConfigV2 `json:"managed,omitempty" yaml:"managed,omitempty"`
 	// Clean, if set to true, will delete the output directories, zip files, or jar files
 	// before generation is run.
-	Clean   bool                             `json:"clean,omitempty" yaml:"clean,omitempty"`
-	Plugins []externalGeneratePluginConfigV2 `json:"plugins,omitempty" yaml:"plugins,omitempty"`
-	Inputs  []externalInputConfigV2          `json:"inputs,omitempty" yaml:"inputs,omitempty"`
+	//
+	// This is a pointer so that a template can set it to false to override the template it extends.
+	Clean *bool `json:"clean,omitempty" yaml:"clean,omitempty"`
+	// InterpolateEnv, if set to true, interpolates environment variables in the values
+	// of the file when it is read.
+	//
+	// This is only read before the file is unmarshalled, and is not written, as the values
+	// of the file are interpolated by then.
+	InterpolateEnv bool                             `json:"interpolate_env,omitempty" yaml:"interpolate_env,omitempty"`
+	Plugins        []externalGeneratePluginConfigV2 `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+	Inputs         []externalInputConfigV2          `json:"inputs,omitempty" yaml:"inputs,omitempty"`
 }
 
 // externalGeneratePluginConfigV2 represents a single plugin config in a v2 buf.gen.yaml file.
@@ -514,11 +831,36 @@ type externalGeneratePluginConfigV2 struct {
 	IncludeWKT     bool `json:"include_wkt,omitempty" yaml:"include_wkt,omitempty"`
 	// Strategy is only valid with ProtoBuiltin and Local.
 	Strategy *string `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+	// Post is the list of commands to run in the output directory after generation. Each
+	// command can be one string (split on whitespace) or multiple strings.
+	Post []any `json:"post,omitempty" yaml:"post,omitempty"`
+	// Types restricts the types, or packages, the plugin generates code for.
+	Types []string `json:"types,omitempty" yaml:"types,omitempty"`
+	// ExcludeTypes excludes types, or packages, from the types the plugin generates code for.
+	ExcludeTypes []string `json:"exclude_types,omitempty" yaml:"exclude_types,omitempty"`
+	// Clean, if set to true, will delete the files generated by the previous generation
+	// of the plugin, as recorded in a manifest in the output directory, before generating.
+	Clean bool `json:"clean,omitempty" yaml:"clean,omitempty"`
+	// Routes routes generated files to output locations other than Out.
+	Routes []externalGenerateOutRouteV2 `json:"routes,omitempty" yaml:"routes,omitempty"`
+	// LocalFallback is only valid with Remote set. It is the local plugin to run if the
+	// remote is unavailable, in the same form as Local.
+	LocalFallback any `json:"local_fallback,omitempty" yaml:"local_fallback,omitempty"`
+}
+
+// externalGenerateOutRouteV2 represents a route of generated files in a v2 buf.gen.yaml file.
+type externalGenerateOutRouteV2 struct {
+	// Out is required.
+	Out string `json:"out,omitempty" yaml:"out,omitempty"`
+	// At least one of Suffix and Package is required.
+	Suffix  string `json:"suffix,omitempty" yaml:"suffix,omitempty"`
+	Package string `json:"package,omitempty" yaml:"package,omitempty"`
 }
 
 // externalGenerateManagedConfigV2 represents the managed mode config in a v2 buf.gen.yaml file.
 type externalGenerateManagedConfigV2 struct {
-	Enabled  bool                              `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// Enabled is a pointer so that a template can set it to false to override the template it extends.
+	Enabled  *bool                             `json:"enabled,omitempty" yaml:"enabled,omitempty"`
 	Disable  []externalManagedDisableConfigV2  `json:"disable,omitempty" yaml:"disable,omitempty"`
 	Override []externalManagedOverrideConfigV2 `json:"override,omitempty" yaml:"override,omitempty"`
 }
@@ -538,15 +880,43 @@ type externalManagedDisableConfigV2 struct {
 
 // externalManagedOverrideConfigV2 represents an override rule in managed mode in a v2 buf.gen.yaml file.
 type externalManagedOverrideConfigV2 struct {
-	// Exactly one of FileOption and FieldOption must be set.
-	FileOption  string `json:"file_option,omitempty" yaml:"file_option,omitempty"`
-	FieldOption string `json:"field_option,omitempty" yaml:"field_option,omitempty"`
-	Module      string `json:"module,omitempty" yaml:"module,omitempty"`
+	// Exactly one of FileOption, FieldOption and MessageOption must be set.
+	//
+	// FileOption may be a custom option in parentheses, such as "(acme.v1.owner)".
+	// MessageOption must be a custom option in parentheses.
+	FileOption    string `json:"file_option,omitempty" yaml:"file_option,omitempty"`
+	FieldOption   string `json:"field_option,omitempty" yaml:"field_option,omitempty"`
+	MessageOption string `json:"message_option,omitempty" yaml:"message_option,omitempty"`
+	Module        string `json:"module,omitempty" yaml:"module,omitempty"`
 	// Path must be normalized.
 	Path string `json:"path,omitempty" yaml:"path,omitempty"`
 	// Field must not be set if FileOption is set.
 	Field string `json:"field,omitempty" yaml:"field,omitempty"`
-	// Value is required
+	// Exactly one of Value and ValueExpression must be set.
+	Value interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+	// ValueExpression is a CEL expression that computes the value for each file.
+	ValueExpression string `json:"value_expression,omitempty" yaml:"value_expression,omitempty"`
+	// Match is optional. If set, the override only applies to files that
+	// satisfy all of its conditions.
+	Match *externalManagedOverrideMatchConfigV2 `json:"match,omitempty" yaml:"match,omitempty"`
+}
+
+// externalManagedOverrideMatchConfigV2 is an external configuration for the
+// conditions of an override.
+type externalManagedOverrideMatchConfigV2 struct {
+	// PathRegex is matched against the file path relative to its module.
+	PathRegex     string                              `json:"path_regex,omitempty" yaml:"path_regex,omitempty"`
+	FileOption    *externalManagedOptionMatchConfigV2 `json:"file_option,omitempty" yaml:"file_option,omitempty"`
+	MessageOption *externalManagedOptionMatchConfigV2 `json:"message_option,omitempty" yaml:"message_option,omitempty"`
+	FieldOption   *externalManagedOptionMatchConfigV2 `json:"field_option,omitempty" yaml:"field_option,omitempty"`
+}
+
+// externalManagedOptionMatchConfigV2 is an external configuration for matching
+// an option set on a descriptor.
+type externalManagedOptionMatchConfigV2 struct {
+	// Name is required.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Value is optional. If not set, the option only needs to be set.
 	Value interface{} `json:"value,omitempty" yaml:"value,omitempty"`
 }
 
@@ -568,6 +938,12 @@ type externalInputConfigV2 struct {
 	Types        []string `json:"types,omitempty" yaml:"types,omitempty"`
 	TargetPaths  []string `json:"paths,omitempty" yaml:"paths,omitempty"`
 	ExcludePaths []string `json:"exclude_paths,omitempty" yaml:"exclude_paths,omitempty"`
+	// Plugins and Out are available for all formats.
+	//
+	// Plugins are the names of the plugins to generate with, and Out is prefixed to
+	// the out of each plugin.
+	Plugins []string `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+	Out     string   `json:"out,omitempty" yaml:"out,omitempty"`
 	// The following options are available depending on input format.
 	Compression         *string `json:"compression,omitempty" yaml:"compression,omitempty"`
 	StripComponents     *uint32 `json:"strip_components,omitempty" yaml:"strip_components,omitempty"`
@@ -16,9 +16,11 @@ package bufconfig
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 
+	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -52,6 +54,106 @@ plugins:
     out: gen/java
   - remote: buf.build/protocolbuffers/python:v21.9
     out: gen/python
+`,
+	)
+	testReadWriteBufGenYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v1
+plugins:
+  - plugin: es
+    out: gen/es
+    path: protoc-gen-es
+    include_imports: true
+    include_wkt: true
+  - plugin: java
+    out: gen/java
+    include_imports: true
+  - plugin: buf.build/protocolbuffers/python:v21.9
+    out: gen/python
+    include_imports: true
+`,
+		// expected output
+		`version: v2
+plugins:
+  - local: protoc-gen-es
+    out: gen/es
+    include_imports: true
+    include_wkt: true
+  - protoc_builtin: java
+    out: gen/java
+    include_imports: true
+  - remote: buf.build/protocolbuffers/python:v21.9
+    out: gen/python
+    include_imports: true
+`,
+	)
+	testReadWriteBufGenYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+plugins:
+  - local: protoc-gen-doc
+    out: gen/doc
+    strategy: all_with_imports
+  - local: protoc-gen-go-grpc
+    out: gen/go
+    routes:
+      - out: gen/grpc
+        suffix: _grpc.pb.go
+      - out: gen/internal
+        package: acme.internal.*
+      - out: gen/internal_grpc
+        suffix: _grpc.pb.go
+        package: acme.internal.v1
+`,
+		// expected output
+		`version: v2
+plugins:
+  - local: protoc-gen-doc
+    out: gen/doc
+    strategy: all_with_imports
+  - local: protoc-gen-go-grpc
+    out: gen/go
+    routes:
+      - out: gen/grpc
+        suffix: _grpc.pb.go
+      - out: gen/internal
+        package: acme.internal.*
+      - out: gen/internal_grpc
+        suffix: _grpc.pb.go
+        package: acme.internal.v1
+`,
+	)
+	testReadWriteBufGenYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+plugins:
+  - remote: buf.build/protocolbuffers/go
+    out: gen/go
+    opt: paths=source_relative
+    local_fallback: protoc-gen-go
+  - remote: buf.build/connectrpc/go
+    out: gen/go
+    local_fallback:
+      - go
+      - run
+      - connectrpc.com/connect/cmd/protoc-gen-connect-go
+`,
+		// expected output
+		`version: v2
+plugins:
+  - remote: buf.build/protocolbuffers/go
+    out: gen/go
+    opt: paths=source_relative
+    local_fallback: protoc-gen-go
+  - remote: buf.build/connectrpc/go
+    out: gen/go
+    local_fallback:
+      - go
+      - run
+      - connectrpc.com/connect/cmd/protoc-gen-connect-go
 `,
 	)
 	testReadWriteBufGenYAMLFileRoundTrip(
@@ -162,6 +264,9 @@ plugins:
   - remote: buf.build/protocolbuffers/go
     revision: 1
     out: gen/proto
+    exclude_types:
+      - foo.v1.Internal
+    clean: true
   - protoc_builtin: cpp
     protoc_path: /path/to/protoc
     out: gen/proto
@@ -178,6 +283,13 @@ plugins:
       - foo=bar
       - baz
     strategy: all
+    post:
+      - gofmt -w .
+      - [npx, prettier, --write, .]
+    types:
+      - foo.v1
+    exclude_types:
+      - foo.v1.UserService
     include_imports: true
     include_wkt: true
 inputs:
@@ -196,6 +308,10 @@ inputs:
       - a/b/c/x.proto
       - a/b/d/y.proto
   - directory: x/y/z
+    plugins:
+      - buf.build/protocolbuffers/go
+      - protoc-gen-validate
+    out: gen/xyz
   - tarball: a/b/x.tar.gz
   - tarball: c/d/x.tar.zst
     compression: zstd
@@ -245,6 +361,9 @@ plugins:
   - remote: buf.build/protocolbuffers/go
     revision: 1
     out: gen/proto
+    exclude_types:
+      - foo.v1.Internal
+    clean: true
   - protoc_builtin: cpp
     protoc_path: /path/to/protoc
     out: gen/proto
@@ -266,6 +385,18 @@ plugins:
     include_imports: true
     include_wkt: true
     strategy: all
+    post:
+      - - gofmt
+        - -w
+        - .
+      - - npx
+        - prettier
+        - --write
+        - .
+    types:
+      - foo.v1
+    exclude_types:
+      - foo.v1.UserService
 inputs:
   - git_repo: github.com/acme/weather
     subdir: proto
@@ -282,6 +413,10 @@ inputs:
       - a/b/c/x.proto
       - a/b/d/y.proto
   - directory: x/y/z
+    plugins:
+      - buf.build/protocolbuffers/go
+      - protoc-gen-validate
+    out: gen/xyz
   - tarball: a/b/x.tar.gz
   - tarball: c/d/x.tar.zst
     compression: zstd
@@ -297,6 +432,160 @@ inputs:
 	)
 }
 
+func TestReadWriteBufGenYAMLFileManagedOverrideMatchRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	testReadWriteBufGenYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: java_multiple_files
+      value: false
+      match:
+        path_regex: ^acme/.+/v1/
+        file_option:
+          name: (acme.options.v1.legacy_java)
+          value: true
+    - file_option: go_package_prefix
+      module: buf.build/acme/petapis
+      value: github.com/acme/gen
+      match:
+        message_option:
+          name: deprecated
+    - field_option: jstype
+      value: JS_STRING
+      match:
+        field_option:
+          name: (acme.options.v1.sensitivity)
+          value: HIGH
+plugins:
+  - local: protoc-gen-go
+    out: gen/go
+`,
+		// expected output
+		`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: java_multiple_files
+      value: false
+      match:
+        path_regex: ^acme/.+/v1/
+        file_option:
+          name: (acme.options.v1.legacy_java)
+          value: true
+    - file_option: go_package_prefix
+      module: buf.build/acme/petapis
+      value: github.com/acme/gen
+      match:
+        message_option:
+          name: deprecated
+    - field_option: jstype
+      value: JS_STRING
+      match:
+        field_option:
+          name: (acme.options.v1.sensitivity)
+          value: HIGH
+plugins:
+  - local: protoc-gen-go
+    out: gen/go
+`,
+	)
+}
+
+func TestReadWriteBufGenYAMLFileManagedValueExpressionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	testReadWriteBufGenYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: go_package
+      value_expression: "'github.com/acme/gen/' + file.package.replace('.', '/')"
+    - file_option: (acme.options.v1.owner)
+      module: buf.build/acme/weather
+      value_expression: file.module.split('/')[1]
+      match:
+        path_regex: ^acme/weather/
+plugins:
+  - local: protoc-gen-go
+    out: gen/go
+`,
+		// expected output
+		`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: go_package
+      value_expression: '''github.com/acme/gen/'' + file.package.replace(''.'', ''/'')'
+    - file_option: (acme.options.v1.owner)
+      module: buf.build/acme/weather
+      value_expression: file.module.split('/')[1]
+      match:
+        path_regex: ^acme/weather/
+plugins:
+  - local: protoc-gen-go
+    out: gen/go
+`,
+	)
+}
+
+func TestReadWriteBufGenYAMLFileManagedCustomOptionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	testReadWriteBufGenYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: (acme.options.v1.owner)
+      value: platform
+    - file_option: (acme.options.v1.team)
+      path: acme/weather
+      value:
+        name: weather
+        size: 3
+    - message_option: (acme.options.v1.cache_ttl_seconds)
+      module: buf.build/acme/weather
+      value: 60
+      match:
+        path_regex: ^acme/weather/
+plugins:
+  - local: protoc-gen-go
+    out: gen/go
+`,
+		// expected output
+		`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: (acme.options.v1.owner)
+      value: platform
+    - file_option: (acme.options.v1.team)
+      path: acme/weather
+      value:
+        name: weather
+        size: 3
+    - message_option: (acme.options.v1.cache_ttl_seconds)
+      module: buf.build/acme/weather
+      value: 60
+      match:
+        path_regex: ^acme/weather/
+plugins:
+  - local: protoc-gen-go
+    out: gen/go
+`,
+	)
+}
+
 func TestBufGenYAMLFileManagedErrors(t *testing.T) {
 	t.Parallel()
 
@@ -311,7 +600,50 @@ plugins:
     out: gen
 `),
 	)
-	require.ErrorContains(t, err, "must set value for an override")
+	require.ErrorContains(t, err, "must set value or value_expression for an override")
+
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: go_package
+      value: github.com/acme/gen
+      value_expression: "'github.com/acme/gen/' + file.package"
+plugins:
+  - local: protoc-gen-go
+    out: gen
+`),
+	)
+	require.ErrorContains(t, err, "at most one of value and value_expression can be set for an override")
+
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: go_package
+      value_expression: "'github.com/acme/gen/' + file.package +"
+plugins:
+  - local: protoc-gen-go
+    out: gen
+`),
+	)
+	require.ErrorContains(t, err, "invalid value_expression")
+
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: go_package
+      value_expression: "file.package.size()"
+plugins:
+  - local: protoc-gen-go
+    out: gen
+`),
+	)
+	require.ErrorContains(t, err, "must evaluate to a string or a bool")
 
 	_, err = ReadBufGenYAMLFile(
 		strings.NewReader(`version: v2
@@ -324,7 +656,7 @@ plugins:
     out: gen
 `),
 	)
-	require.ErrorContains(t, err, "must set file_option or field_option for an override")
+	require.ErrorContains(t, err, "must set file_option, field_option or message_option for an override")
 
 	_, err = ReadBufGenYAMLFile(
 		strings.NewReader(`version: v2
@@ -339,7 +671,7 @@ plugins:
     out: gen
 `),
 	)
-	require.ErrorContains(t, err, "exactly one of file_option and field_option must be set for an override")
+	require.ErrorContains(t, err, "exactly one of file_option, field_option and message_option must be set for an override")
 
 	_, err = ReadBufGenYAMLFile(
 		strings.NewReader(`version: v2
@@ -367,6 +699,133 @@ plugins:
 `),
 	)
 	require.ErrorContains(t, err, "at most one of file_option and field_option can be specified")
+
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: java_multiple_files
+      value: true
+      match: {}
+plugins:
+  - local: protoc-gen-java
+    out: gen
+`),
+	)
+	require.ErrorContains(t, err, "empty match is not allowed for an override")
+
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: java_multiple_files
+      value: true
+      match:
+        path_regex: "acme/(v1"
+plugins:
+  - local: protoc-gen-java
+    out: gen
+`),
+	)
+	require.ErrorContains(t, err, "invalid path_regex")
+
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: java_multiple_files
+      value: true
+      match:
+        message_option:
+          value: true
+plugins:
+  - local: protoc-gen-java
+    out: gen
+`),
+	)
+	require.ErrorContains(t, err, "invalid message_option for override match: option name must be specified")
+
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: java_multiple_files
+      value: true
+      match:
+        file_option:
+          name: (acme.v1.foo
+plugins:
+  - local: protoc-gen-java
+    out: gen
+`),
+	)
+	require.ErrorContains(t, err, `invalid option name "(acme.v1.foo"`)
+
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: java_multiple_files
+      value: true
+      match:
+        field_option:
+          name: deprecated
+          value:
+            - true
+plugins:
+  - local: protoc-gen-java
+    out: gen
+`),
+	)
+	require.ErrorContains(t, err, "must be a string, bool, or number")
+
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+managed:
+  enabled: true
+  override:
+    - message_option: deprecated
+      value: true
+plugins:
+  - local: protoc-gen-java
+    out: gen
+`),
+	)
+	require.ErrorContains(t, err, `invalid message_option "deprecated": only custom options`)
+
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+managed:
+  enabled: true
+  override:
+    - message_option: (acme.v1.cache_ttl)
+      field: acme.v1.Foo.bar
+      value: 60
+plugins:
+  - local: protoc-gen-java
+    out: gen
+`),
+	)
+	require.ErrorContains(t, err, "must not set field for a message_option override")
+
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: (acme.v1..owner)
+      value: platform
+plugins:
+  - local: protoc-gen-java
+    out: gen
+`),
+	)
+	require.ErrorContains(t, err, `invalid custom option name "(acme.v1..owner)"`)
 }
 
 func TestBufGenYAMLFilePluginConfigErrors(t *testing.T) {
@@ -460,6 +919,393 @@ plugins:
 `),
 	)
 	require.ErrorContains(t, err, "only one of remote, local or protoc_builtin")
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+plugins:
+  - local: protoc-gen-go
+    out: .
+    post:
+      - ""
+`),
+	)
+	require.ErrorContains(t, err, "post commands must not be empty")
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+plugins:
+  - local: protoc-gen-go
+    out: .
+    exclude_types:
+      - ""
+`),
+	)
+	require.ErrorContains(t, err, "exclude_types must not be empty")
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v1
+plugins:
+  - plugin: go
+    out: .
+    include_wkt: true
+`),
+	)
+	require.ErrorContains(t, err, "cannot include well-known types without including imports")
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+plugins:
+  - local: protoc-gen-go
+    out: .
+    routes:
+      - suffix: _grpc.pb.go
+`),
+	)
+	require.ErrorContains(t, err, "must specify out for a route")
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+plugins:
+  - local: protoc-gen-go
+    out: .
+    routes:
+      - out: gen
+`),
+	)
+	require.ErrorContains(t, err, "must specify suffix or package for route to gen")
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+plugins:
+  - local: protoc-gen-go
+    out: .
+    routes:
+      - out: gen
+        package: acme.*.v1
+`),
+	)
+	require.ErrorContains(t, err, `invalid package "acme.*.v1" for route to gen`)
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+plugins:
+  - local: protoc-gen-go
+    out: .
+    local_fallback: protoc-gen-go
+`),
+	)
+	require.ErrorContains(t, err, "cannot specify local_fallback for local plugin protoc-gen-go")
+}
+
+func TestBufGenYAMLFileInputConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+plugins:
+  - local: protoc-gen-go
+    out: .
+inputs:
+  - directory: proto
+    plugins:
+      - protoc-gen-validate
+`),
+	)
+	require.ErrorContains(t, err, `plugin "protoc-gen-validate" is not configured in plugins`)
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+plugins:
+  - local: protoc-gen-go
+    out: .
+inputs:
+  - directory: proto
+    plugins:
+      - ""
+`),
+	)
+	require.ErrorContains(t, err, "plugins must not be empty")
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+plugins:
+  - local: protoc-gen-go
+    out: .
+inputs:
+  - directory: proto
+    out: ../gen
+`),
+	)
+	require.ErrorContains(t, err, "invalid out")
+}
+
+func TestBufGenYAMLFileExtends(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bucket, err := storagemem.NewReadBucket(
+		map[string][]byte{
+			"shared/base.gen.yaml": []byte(`version: v2
+extends: root.gen.yaml
+plugins:
+  - remote: buf.build/protocolbuffers/go:v1.28.1
+    out: gen/go
+    opt: paths=source_relative
+  - local: protoc-gen-es
+    out: gen/es
+managed:
+  enabled: true
+  override:
+    - file_option: go_package_prefix
+      value: example.com/base
+`),
+			"shared/root.gen.yaml": []byte(`version: v2
+clean: true
+plugins:
+  - protoc_builtin: java
+    out: gen/java
+inputs:
+  - directory: proto
+`),
+			"proto/buf.gen.yaml": []byte(`version: v2
+extends: ../shared/base.gen.yaml
+plugins:
+  - remote: buf.build/protocolbuffers/go:v1.31.0
+    out: gen/go
+    opt: paths=import
+  - local: protoc-gen-connect-go
+    out: gen/go
+managed:
+  override:
+    - file_option: go_package_prefix
+      value: example.com/child
+`),
+			"cycle/buf.gen.yaml": []byte(`version: v2
+extends: other.gen.yaml
+`),
+			"cycle/other.gen.yaml": []byte(`version: v2
+extends: buf.gen.yaml
+`),
+			"override/buf.gen.yaml": []byte(`version: v2
+extends: ../shared/base.gen.yaml
+managed:
+  enabled: false
+clean: false
+`),
+			"bsr/buf.gen.yaml": []byte(`version: v2
+extends: buf.build/acme/templates
+`),
+			"v1/buf.gen.yaml": []byte(`version: v2
+extends: base.gen.yaml
+`),
+			"v1/base.gen.yaml": []byte(`version: v1
+plugins:
+  - plugin: go
+    out: gen/go
+`),
+		},
+	)
+	require.NoError(t, err)
+
+	bufGenYAMLFile, err := GetBufGenYAMLFileForPrefix(ctx, bucket, "proto")
+	require.NoError(t, err)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteBufGenYAMLFile(buffer, bufGenYAMLFile))
+	assert.Equal(
+		t,
+		testCleanYAMLData(`version: v2
+managed:
+  enabled: true
+  override:
+    - file_option: go_package_prefix
+      value: example.com/base
+    - file_option: go_package_prefix
+      value: example.com/child
+clean: true
+plugins:
+  - protoc_builtin: java
+    out: gen/java
+  - remote: buf.build/protocolbuffers/go:v1.31.0
+    out: gen/go
+    opt: paths=import
+  - local: protoc-gen-es
+    out: gen/es
+  - local: protoc-gen-connect-go
+    out: gen/go
+inputs:
+  - directory: proto
+`),
+		testCleanYAMLData(buffer.String()),
+	)
+
+	// Setting clean and managed mode to false overrides the templates extended.
+	bufGenYAMLFile, err = GetBufGenYAMLFileForPrefix(ctx, bucket, "override")
+	require.NoError(t, err)
+	assert.False(t, bufGenYAMLFile.GenerateConfig().CleanPluginOuts())
+	assert.False(t, bufGenYAMLFile.GenerateConfig().GenerateManagedConfig().Enabled())
+
+	_, err = GetBufGenYAMLFileForPrefix(ctx, bucket, "cycle")
+	require.ErrorContains(t, err, "cycle detected")
+	_, err = GetBufGenYAMLFileForPrefix(ctx, bucket, "bsr")
+	require.ErrorContains(t, err, `extends "buf.build/acme/templates": extending templates on the BSR is not supported`)
+	_, err = GetBufGenYAMLFileForPrefix(ctx, bucket, "v1")
+	require.ErrorContains(t, err, "can only extend templates of version v2")
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+extends: base.gen.yaml
+`),
+	)
+	require.ErrorContains(t, err, "not supported")
+}
+
+func TestBufGenYAMLFileEnv(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"GEN_DIR":      "gen/prod",
+		"GO_REVISION":  "2",
+		"EMPTY":        "",
+		"MODULE_PATHS": "paths=source_relative",
+	}
+	envFunc := func(key string) string {
+		return env[key]
+	}
+	bufGenYAMLFile, err := ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+interpolate_env: true
+plugins:
+  - remote: buf.build/protocolbuffers/go:v1.28.1
+    revision: ${GO_REVISION}
+    out: ${GEN_DIR}/go
+    opt:
+      - ${MODULE_PATHS}
+      - ${EMPTY:-module=example.com}
+      - literal=$${GEN_DIR}
+  - local: protoc-gen-es
+    out: ${UNSET:-}gen/es
+    opt: ["${GEN_DIR}", '${EMPTY:-it''s}', "${UNSET:-a: b}"]
+`),
+		BufGenYAMLFileWithEnvFunc(envFunc),
+	)
+	require.NoError(t, err)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteBufGenYAMLFile(buffer, bufGenYAMLFile))
+	assert.Equal(
+		t,
+		testCleanYAMLData(`version: v2
+plugins:
+  - remote: buf.build/protocolbuffers/go:v1.28.1
+    revision: 2
+    out: gen/prod/go
+    opt:
+      - paths=source_relative
+      - module=example.com
+      - literal=${GEN_DIR}
+  - local: protoc-gen-es
+    out: gen/es
+    opt:
+      - gen/prod
+      - it's
+      - 'a: b'
+`),
+		testCleanYAMLData(buffer.String()),
+	)
+
+	// Without an env func, values are not interpolated.
+	bufGenYAMLFile, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+interpolate_env: true
+plugins:
+  - local: protoc-gen-es
+    out: ${GEN_DIR}
+`),
+	)
+	require.NoError(t, err)
+	require.Len(t, bufGenYAMLFile.GenerateConfig().GeneratePluginConfigs(), 1)
+	assert.Equal(t, "${GEN_DIR}", bufGenYAMLFile.GenerateConfig().GeneratePluginConfigs()[0].Out())
+	// Without interpolate_env, values are not interpolated, even with an env func.
+	bufGenYAMLFile, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+plugins:
+  - local: protoc-gen-es
+    out: ${GEN_DIR}
+    opt: ${UNSET}
+`),
+		BufGenYAMLFileWithEnvFunc(envFunc),
+	)
+	require.NoError(t, err)
+	require.Len(t, bufGenYAMLFile.GenerateConfig().GeneratePluginConfigs(), 1)
+	assert.Equal(t, "${GEN_DIR}", bufGenYAMLFile.GenerateConfig().GeneratePluginConfigs()[0].Out())
+	assert.Equal(t, "${UNSET}", bufGenYAMLFile.GenerateConfig().GeneratePluginConfigs()[0].Opt())
+	// interpolate_env is only valid in v2 files.
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v1
+interpolate_env: true
+plugins:
+  - plugin: es
+    out: gen/es
+`),
+		BufGenYAMLFileWithEnvFunc(envFunc),
+	)
+	require.ErrorContains(t, err, "field interpolate_env not found")
+
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+interpolate_env: true
+plugins:
+  - local: protoc-gen-es
+    out: ${OUT_DIR:?set OUT_DIR to the output directory}
+`),
+		BufGenYAMLFileWithEnvFunc(envFunc),
+	)
+	require.ErrorContains(t, err, `line 5: environment variable "OUT_DIR" is required: set OUT_DIR to the output directory`)
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+interpolate_env: true
+plugins:
+  - local: protoc-gen-es
+    out: ${GEN_DIR
+`),
+		BufGenYAMLFileWithEnvFunc(envFunc),
+	)
+	require.ErrorContains(t, err, "unterminated environment variable reference")
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+interpolate_env: true
+plugins:
+  - local: protoc-gen-es
+    out: ${GEN-DIR}
+`),
+		BufGenYAMLFileWithEnvFunc(envFunc),
+	)
+	require.ErrorContains(t, err, "invalid environment variable name")
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+interpolate_env: true
+plugins:
+  - local: protoc-gen-es
+    out: ${UNSET}/es
+`),
+		BufGenYAMLFileWithEnvFunc(envFunc),
+	)
+	require.ErrorContains(t, err, `line 5: environment variable "UNSET" is not set`)
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+interpolate_env: true
+plugins:
+  - local: protoc-gen-es
+    out: |
+      ${GEN_DIR}
+`),
+		BufGenYAMLFileWithEnvFunc(envFunc),
+	)
+	require.ErrorContains(t, err, "line 5: environment variables can only be referenced in values on a single line")
+	// Values are interpolated in place, so that errors refer to the lines of the file.
+	_, err = ReadBufGenYAMLFile(
+		strings.NewReader(`version: v2
+interpolate_env: true
+
+# The plugins.
+plugins:
+  - local: protoc-gen-es
+    out: ${GEN_DIR}
+
+    unknown: value
+`),
+		BufGenYAMLFileWithEnvFunc(envFunc),
+	)
+	require.ErrorContains(t, err, "line 9: field unknown not found")
 }
 
 func testReadBufGenYAMLFile(
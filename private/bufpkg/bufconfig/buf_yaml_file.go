@@ -16,14 +16,18 @@ package bufconfig
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/bufbuild/buf/private/bufpkg/bufparse"
 	"github.com/bufbuild/buf/private/pkg/encoding"
@@ -111,6 +115,14 @@ type BufYAMLFile interface {
 	// The ModuleRefs in this list will be unique by FullName.
 	// Sorted by FullName.
 	ConfiguredDepModuleRefs() []bufparse.Ref
+	// ModuleOverrides returns the dependencies that are replaced with local directories.
+	//
+	// These come from the overrides key of v2 buf.yaml files. For v1 buf.yaml files, this
+	// will always return nil.
+	//
+	// The ModuleOverrides in this list will be unique by FullName.
+	// Sorted by FullName.
+	ModuleOverrides() []ModuleOverride
 	//IncludeDocsLink specifies whether a top-level comment with a link to our public docs
 	// should be included at the top of the buf.yaml file.
 	IncludeDocsLink() bool
@@ -140,11 +152,12 @@ func NewBufYAMLFile(
 		nil, // Do not set top-level breaking config, use only module configs
 		pluginConfigs,
 		configuredDepModuleRefs,
+		nil,
 		bufYAMLFileOptions.includeDocsLink,
 	)
 }
 
-// BufYAMLFileOption is an option for a new BufYAMLFile
+// BufYAMLFileOption is an option for a new or read BufYAMLFile.
 type BufYAMLFileOption func(*bufYAMLFileOptions)
 
 // BufYAMLFileWithIncludeDocsLink returns a new BufYAMLFileOption that specifies including
@@ -156,15 +169,48 @@ func BufYAMLFileWithIncludeDocsLink() BufYAMLFileOption {
 	}
 }
 
+// BufYAMLFileWithExtendsReadFunc returns a new BufYAMLFileOption that reads the
+// buf.yaml files referenced by the "extends" key of the lint and breaking sections
+// of v2 buf.yaml files with readFunc.
+//
+// The path given to readFunc is normalized. Relative paths in "extends" are joined to
+// the directory of the buf.yaml file that references them, where dirPath is the directory
+// of the buf.yaml file being read. Absolute paths are passed as-is.
+func BufYAMLFileWithExtendsReadFunc(
+	dirPath string,
+	readFunc func(path string) ([]byte, error),
+) BufYAMLFileOption {
+	return func(bufYAMLFileOptions *bufYAMLFileOptions) {
+		bufYAMLFileOptions.extendsDirPath = dirPath
+		bufYAMLFileOptions.extendsReadFunc = readFunc
+	}
+}
+
 // GetBufYAMLFileForPrefix gets the buf.yaml file at the given bucket prefix.
 //
 // The buf.yaml file will be attempted to be read at prefix/buf.yaml.
+//
+// By default, buf.yaml files referenced by "extends" are read from the same bucket,
+// relative to prefix. This can be changed with BufYAMLFileWithExtendsReadFunc.
 func GetBufYAMLFileForPrefix(
 	ctx context.Context,
 	bucket storage.ReadBucket,
 	prefix string,
+	options ...BufYAMLFileOption,
 ) (BufYAMLFile, error) {
-	return getFileForPrefix(ctx, bucket, prefix, bufYAMLFileNames, bufYAMLFileNameToSupportedFileVersions, readBufYAMLFile)
+	bufYAMLFileOptions := newBufYAMLFileOptions()
+	bufYAMLFileOptions.extendsDirPath = prefix
+	bufYAMLFileOptions.extendsReadFunc = func(path string) ([]byte, error) {
+		path, err := normalpath.NormalizeAndValidate(path)
+		if err != nil {
+			return nil, err
+		}
+		return storage.ReadPath(ctx, bucket, path)
+	}
+	for _, option := range options {
+		option(bufYAMLFileOptions)
+	}
+	return getFileForPrefix(ctx, bucket, prefix, bufYAMLFileNames, bufYAMLFileNameToSupportedFileVersions, newReadBufYAMLFileFunc(bufYAMLFileOptions))
 }
 
 // GetBufYAMLFileForOverride get the buf.yaml file for either the usually-flag-based override.
@@ -173,11 +219,16 @@ func GetBufYAMLFileForPrefix(
 //     **direct file path on disk** and read (ie not via buckets).
 //   - If the override is otherwise non-empty, it is treated as raw data.
 //
+// If the override is a file path, buf.yaml files referenced by "extends" are read from
+// disk, relative to the directory of the file. Otherwise, "extends" cannot be used by
+// default, use BufYAMLFileWithExtendsReadFunc to allow this.
+//
 // This function is the result of the endlessly annoying and shortsighted design decision that the
 // original author of this repository made to allow overriding configuration files on the command line.
 // Of course, the original author never envisioned buf.work.yamls, merging buf.work.yamls into buf.yamls,
 // buf.gen.yamls, or anything of the like, and was very concentrated on "because Bazel."
-func GetBufYAMLFileForOverride(override string) (BufYAMLFile, error) {
+func GetBufYAMLFileForOverride(override string, options ...BufYAMLFileOption) (BufYAMLFile, error) {
+	bufYAMLFileOptions := newBufYAMLFileOptions()
 	var data []byte
 	var fileName string
 	var err error
@@ -188,10 +239,17 @@ func GetBufYAMLFileForOverride(override string) (BufYAMLFile, error) {
 			return nil, fmt.Errorf("could not read file: %v", err)
 		}
 		fileName = filepath.Base(fileName)
+		bufYAMLFileOptions.extendsDirPath = normalpath.Normalize(filepath.Dir(override))
+		bufYAMLFileOptions.extendsReadFunc = func(path string) ([]byte, error) {
+			return os.ReadFile(normalpath.Unnormalize(path))
+		}
 	default:
 		data = []byte(override)
 	}
-	return readFile(bytes.NewReader(data), fileName, readBufYAMLFile)
+	for _, option := range options {
+		option(bufYAMLFileOptions)
+	}
+	return readFile(bytes.NewReader(data), fileName, newReadBufYAMLFileFunc(bufYAMLFileOptions))
 }
 
 // GetBufYAMLFileForPrefixOrOverride get the buf.yaml file for either the usually-flag-based override,
@@ -201,11 +259,12 @@ func GetBufYAMLFileForPrefixOrOverride(
 	bucket storage.ReadBucket,
 	prefix string,
 	override string,
+	options ...BufYAMLFileOption,
 ) (BufYAMLFile, error) {
 	if override != "" {
-		return GetBufYAMLFileForOverride(override)
+		return GetBufYAMLFileForOverride(override, options...)
 	}
-	return GetBufYAMLFileForPrefix(ctx, bucket, prefix)
+	return GetBufYAMLFileForPrefix(ctx, bucket, prefix, options...)
 }
 
 // GetBufYAMLFileVersionForPrefix gets the buf.yaml file version at the given bucket prefix.
@@ -235,11 +294,21 @@ func PutBufYAMLFileForPrefix(
 // ReadBufYAMLFile reads the BufYAMLFile from the io.Reader.
 //
 // fileName may be empty.
-func ReadBufYAMLFile(reader io.Reader, fileName string) (BufYAMLFile, error) {
-	return readFile(reader, fileName, readBufYAMLFile)
+//
+// By default, buf.yaml files that set "extends" cannot be read, use
+// BufYAMLFileWithExtendsReadFunc to allow this.
+func ReadBufYAMLFile(reader io.Reader, fileName string, options ...BufYAMLFileOption) (BufYAMLFile, error) {
+	bufYAMLFileOptions := newBufYAMLFileOptions()
+	for _, option := range options {
+		option(bufYAMLFileOptions)
+	}
+	return readFile(reader, fileName, newReadBufYAMLFileFunc(bufYAMLFileOptions))
 }
 
 // WriteBufYAMLFile writes the BufYAMLFile to the io.Writer.
+//
+// If the BufYAMLFile was read from a file with lint or breaking sections that set
+// "extends", the merged configuration is written, and "extends" is not set.
 func WriteBufYAMLFile(writer io.Writer, bufYAMLFile BufYAMLFile) error {
 	return writeFile(writer, bufYAMLFile, writeBufYAMLFile)
 }
@@ -254,6 +323,7 @@ type bufYAMLFile struct {
 	topLevelBreakingConfig  BreakingConfig
 	pluginConfigs           []PluginConfig
 	configuredDepModuleRefs []bufparse.Ref
+	moduleOverrides         []ModuleOverride
 	includeDocsLink         bool
 }
 
@@ -265,6 +335,7 @@ func newBufYAMLFile(
 	topLevelBreakingConfig BreakingConfig,
 	pluginConfigs []PluginConfig,
 	configuredDepModuleRefs []bufparse.Ref,
+	moduleOverrides []ModuleOverride,
 	includeDocsLink bool,
 ) (*bufYAMLFile, error) {
 	if (fileVersion == FileVersionV1Beta1 || fileVersion == FileVersionV1) && len(moduleConfigs) > 1 {
@@ -290,12 +361,21 @@ func newBufYAMLFile(
 		}
 	}
 	// Zero values are not added to duplicates.
-	if _, err := bufparse.FullNameStringToUniqueValue(moduleConfigs); err != nil {
+	if _, err := bufparse.FullNameStringToUniqueValue(configuredDepModuleRefs); err != nil {
 		return nil, err
 	}
-	if _, err := bufparse.FullNameStringToUniqueValue(configuredDepModuleRefs); err != nil {
+	if _, err := bufparse.FullNameStringToUniqueValue(moduleOverrides); err != nil {
+		return nil, err
+	}
+	moduleFullNameStringToModuleConfig, err := bufparse.FullNameStringToUniqueValue(moduleConfigs)
+	if err != nil {
 		return nil, err
 	}
+	for _, moduleOverride := range moduleOverrides {
+		if _, ok := moduleFullNameStringToModuleConfig[moduleOverride.FullName().String()]; ok {
+			return nil, fmt.Errorf("%s is a module in the workspace and cannot be overridden", moduleOverride.FullName())
+		}
+	}
 	// Since multiple module configs with the same DirPath are allowed in v2, we need a stable sort
 	// so that the relative order among module configs with the same DirPath is preserved from the
 	// external buf.yaml, as specified in BufYAMLFile.ModuleConfigs' doc.
@@ -312,6 +392,13 @@ func newBufYAMLFile(
 				configuredDepModuleRefs[j].FullName().String()
 		},
 	)
+	sort.Slice(
+		moduleOverrides,
+		func(i int, j int) bool {
+			return moduleOverrides[i].FullName().String() <
+				moduleOverrides[j].FullName().String()
+		},
+	)
 	return &bufYAMLFile{
 		fileVersion:             fileVersion,
 		objectData:              objectData,
@@ -320,6 +407,7 @@ func newBufYAMLFile(
 		topLevelBreakingConfig:  topLevelBreakingConfig,
 		pluginConfigs:           pluginConfigs,
 		configuredDepModuleRefs: configuredDepModuleRefs,
+		moduleOverrides:         moduleOverrides,
 		includeDocsLink:         includeDocsLink,
 	}, nil
 }
@@ -356,6 +444,10 @@ func (c *bufYAMLFile) ConfiguredDepModuleRefs() []bufparse.Ref {
 	return slicesext.Copy(c.configuredDepModuleRefs)
 }
 
+func (c *bufYAMLFile) ModuleOverrides() []ModuleOverride {
+	return slicesext.Copy(c.moduleOverrides)
+}
+
 func (c *bufYAMLFile) IncludeDocsLink() bool {
 	return c.includeDocsLink
 }
@@ -366,16 +458,29 @@ func (*bufYAMLFile) isFileInfo()    {}
 
 type bufYAMLFileOptions struct {
 	includeDocsLink bool
+	extendsDirPath  string
+	extendsReadFunc func(string) ([]byte, error)
 }
 
 func newBufYAMLFileOptions() *bufYAMLFileOptions {
-	return &bufYAMLFileOptions{}
+	return &bufYAMLFileOptions{
+		extendsDirPath: ".",
+	}
+}
+
+func newReadBufYAMLFileFunc(
+	bufYAMLFileOptions *bufYAMLFileOptions,
+) func([]byte, ObjectData, bool) (BufYAMLFile, error) {
+	return func(data []byte, objectData ObjectData, allowJSON bool) (BufYAMLFile, error) {
+		return readBufYAMLFile(data, objectData, allowJSON, bufYAMLFileOptions)
+	}
 }
 
 func readBufYAMLFile(
 	data []byte,
 	objectData ObjectData,
 	allowJSON bool,
+	bufYAMLFileOptions *bufYAMLFileOptions,
 ) (BufYAMLFile, error) {
 	// We've always required a file version for buf.yaml files.
 	fileVersion, err := getFileVersionForData(data, allowJSON, true, bufYAMLFileNameToSupportedFileVersions, FileVersionV2, defaultBufYAMLFileVersion)
@@ -394,6 +499,9 @@ func readBufYAMLFile(
 		if fileVersion == FileVersionV1 && len(externalBufYAMLFile.Build.Roots) > 0 {
 			return nil, fmt.Errorf("build.roots cannot be set on version %v: %v", fileVersion, externalBufYAMLFile.Build.Roots)
 		}
+		if externalBufYAMLFile.Breaking.Extends != "" {
+			return nil, fmt.Errorf("breaking.extends cannot be set on version %v", fileVersion)
+		}
 		var moduleFullName bufparse.FullName
 		if externalBufYAMLFile.Name != "" {
 			moduleFullName, err = bufparse.ParseFullName(externalBufYAMLFile.Name)
@@ -455,6 +563,7 @@ func readBufYAMLFile(
 			breakingConfig,
 			nil,
 			configuredDepModuleRefs,
+			nil,
 			includeDocsLink,
 		)
 	case FileVersionV2:
@@ -462,6 +571,13 @@ func readBufYAMLFile(
 		if err := getUnmarshalStrict(allowJSON)(data, &externalBufYAMLFile); err != nil {
 			return nil, fmt.Errorf("invalid as version %v: %w", fileVersion, err)
 		}
+		if err := resolveExternalBufYAMLFileV2Extends(
+			&externalBufYAMLFile,
+			bufYAMLFileOptions.extendsDirPath,
+			bufYAMLFileOptions.extendsReadFunc,
+		); err != nil {
+			return nil, err
+		}
 		externalModules := externalBufYAMLFile.Modules
 		if len(externalModules) == 0 {
 			externalModules = []externalBufYAMLFileModuleV2{
@@ -654,6 +770,14 @@ func readBufYAMLFile(
 		if err != nil {
 			return nil, err
 		}
+		var moduleOverrides []ModuleOverride
+		for _, externalOverride := range externalBufYAMLFile.Overrides {
+			moduleOverride, err := newModuleOverrideForExternalV2(externalOverride)
+			if err != nil {
+				return nil, err
+			}
+			moduleOverrides = append(moduleOverrides, moduleOverride)
+		}
 		return newBufYAMLFile(
 			fileVersion,
 			objectData,
@@ -662,6 +786,7 @@ func readBufYAMLFile(
 			topLevelBreakingConfig,
 			pluginConfigs,
 			configuredDepModuleRefs,
+			moduleOverrides,
 			includeDocsLink,
 		)
 	default:
@@ -868,6 +993,7 @@ func writeBufYAMLFile(writer io.Writer, bufYAMLFile BufYAMLFile) error {
 			externalPlugins = append(externalPlugins, externalPlugin)
 		}
 		externalBufYAMLFile.Plugins = externalPlugins
+		externalBufYAMLFile.Overrides = slicesext.Map(bufYAMLFile.ModuleOverrides(), newExternalV2ForModuleOverride)
 
 		data, err := encoding.MarshalYAML(&externalBufYAMLFile)
 		if err != nil {
@@ -1023,9 +1149,243 @@ func getLintConfigForExternalLintV1Beta1V1(
 		externalLint.RPCAllowGoogleProtobufEmptyResponses,
 		externalLint.ServiceSuffix,
 		externalLint.AllowCommentIgnores,
+		false,
+		nil,
+		nil,
+		nil,
+		nil,
 	), nil
 }
 
+// resolveExternalBufYAMLFileV2Extends resolves the "extends" keys of the top-level
+// and module lint and breaking sections of the given file in place.
+//
+// Each section that sets "extends" is merged on top of the corresponding top-level
+// section of the referenced buf.yaml file, recursively. Keys that are set in the
+// extending section take precedence, except that ignore, ignore_only, ignore_symbols,
+// and warn are appended to those of the base section, and boolean keys are enabled
+// if they are enabled in either section. Paths in the base section are interpreted
+// relative to the extending buf.yaml file.
+func resolveExternalBufYAMLFileV2Extends(
+	externalFile *externalBufYAMLFileV2,
+	dirPath string,
+	extendsReadFunc func(string) ([]byte, error),
+) error {
+	var err error
+	externalFile.Lint, err = resolveExternalBufYAMLFileLintV2Extends(
+		externalFile.Lint,
+		dirPath,
+		extendsReadFunc,
+		make(map[string]struct{}),
+	)
+	if err != nil {
+		return err
+	}
+	externalFile.Breaking, err = resolveExternalBufYAMLFileBreakingV2Extends(
+		externalFile.Breaking,
+		dirPath,
+		extendsReadFunc,
+		make(map[string]struct{}),
+	)
+	if err != nil {
+		return err
+	}
+	for i, externalModule := range externalFile.Modules {
+		externalFile.Modules[i].Lint, err = resolveExternalBufYAMLFileLintV2Extends(
+			externalModule.Lint,
+			dirPath,
+			extendsReadFunc,
+			make(map[string]struct{}),
+		)
+		if err != nil {
+			return err
+		}
+		externalFile.Modules[i].Breaking, err = resolveExternalBufYAMLFileBreakingV2Extends(
+			externalModule.Breaking,
+			dirPath,
+			extendsReadFunc,
+			make(map[string]struct{}),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resolveExternalBufYAMLFileLintV2Extends(
+	externalLint externalBufYAMLFileLintV2,
+	dirPath string,
+	extendsReadFunc func(string) ([]byte, error),
+	seenPaths map[string]struct{},
+) (externalBufYAMLFileLintV2, error) {
+	if externalLint.Extends == "" {
+		return externalLint, nil
+	}
+	baseExternalFile, path, err := readExternalBufYAMLFileV2ForExtends(
+		"lint.extends",
+		externalLint.Extends,
+		dirPath,
+		extendsReadFunc,
+		seenPaths,
+	)
+	if err != nil {
+		return externalLint, err
+	}
+	baseExternalLint, err := resolveExternalBufYAMLFileLintV2Extends(
+		baseExternalFile.Lint,
+		normalpath.Dir(path),
+		extendsReadFunc,
+		seenPaths,
+	)
+	if err != nil {
+		return externalLint, err
+	}
+	return externalBufYAMLFileLintV2{
+		Use:                                  getFirstNonEmptySlice(externalLint.Use, baseExternalLint.Use),
+		Except:                               getFirstNonEmptySlice(externalLint.Except, baseExternalLint.Except),
+		Ignore:                               append(slices.Clone(baseExternalLint.Ignore), externalLint.Ignore...),
+		IgnoreOnly:                           mergeIgnoreOnlys(baseExternalLint.IgnoreOnly, externalLint.IgnoreOnly),
+		EnumZeroValueSuffix:                  cmp.Or(externalLint.EnumZeroValueSuffix, baseExternalLint.EnumZeroValueSuffix),
+		RPCAllowSameRequestResponse:          baseExternalLint.RPCAllowSameRequestResponse || externalLint.RPCAllowSameRequestResponse,
+		RPCAllowGoogleProtobufEmptyRequests:  baseExternalLint.RPCAllowGoogleProtobufEmptyRequests || externalLint.RPCAllowGoogleProtobufEmptyRequests,
+		RPCAllowGoogleProtobufEmptyResponses: baseExternalLint.RPCAllowGoogleProtobufEmptyResponses || externalLint.RPCAllowGoogleProtobufEmptyResponses,
+		ServiceSuffix:                        cmp.Or(externalLint.ServiceSuffix, baseExternalLint.ServiceSuffix),
+		DisallowCommentIgnores:               baseExternalLint.DisallowCommentIgnores || externalLint.DisallowCommentIgnores,
+		RequireCommentIgnoreJustification:    baseExternalLint.RequireCommentIgnoreJustification || externalLint.RequireCommentIgnoreJustification,
+		DisableBuiltin:                       baseExternalLint.DisableBuiltin || externalLint.DisableBuiltin,
+		Warn:                                 append(slices.Clone(baseExternalLint.Warn), externalLint.Warn...),
+		Naming:                               cmp.Or(externalLint.Naming, baseExternalLint.Naming),
+		Comments:                             cmp.Or(externalLint.Comments, baseExternalLint.Comments),
+		PackageDirectoryMappings:             getFirstNonEmptyMap(externalLint.PackageDirectoryMappings, baseExternalLint.PackageDirectoryMappings),
+	}, nil
+}
+
+func resolveExternalBufYAMLFileBreakingV2Extends(
+	externalBreaking externalBufYAMLFileBreakingV1Beta1V1V2,
+	dirPath string,
+	extendsReadFunc func(string) ([]byte, error),
+	seenPaths map[string]struct{},
+) (externalBufYAMLFileBreakingV1Beta1V1V2, error) {
+	if externalBreaking.Extends == "" {
+		return externalBreaking, nil
+	}
+	baseExternalFile, path, err := readExternalBufYAMLFileV2ForExtends(
+		"breaking.extends",
+		externalBreaking.Extends,
+		dirPath,
+		extendsReadFunc,
+		seenPaths,
+	)
+	if err != nil {
+		return externalBreaking, err
+	}
+	baseExternalBreaking, err := resolveExternalBufYAMLFileBreakingV2Extends(
+		baseExternalFile.Breaking,
+		normalpath.Dir(path),
+		extendsReadFunc,
+		seenPaths,
+	)
+	if err != nil {
+		return externalBreaking, err
+	}
+	return externalBufYAMLFileBreakingV1Beta1V1V2{
+		Use:                    getFirstNonEmptySlice(externalBreaking.Use, baseExternalBreaking.Use),
+		Except:                 getFirstNonEmptySlice(externalBreaking.Except, baseExternalBreaking.Except),
+		Ignore:                 append(slices.Clone(baseExternalBreaking.Ignore), externalBreaking.Ignore...),
+		IgnoreOnly:             mergeIgnoreOnlys(baseExternalBreaking.IgnoreOnly, externalBreaking.IgnoreOnly),
+		IgnoreUnstablePackages: baseExternalBreaking.IgnoreUnstablePackages || externalBreaking.IgnoreUnstablePackages,
+		IgnoreSymbols:          append(slices.Clone(baseExternalBreaking.IgnoreSymbols), externalBreaking.IgnoreSymbols...),
+		DisableBuiltin:         baseExternalBreaking.DisableBuiltin || externalBreaking.DisableBuiltin,
+		FailOn:                 getFirstNonEmptySlice(externalBreaking.FailOn, baseExternalBreaking.FailOn),
+	}, nil
+}
+
+// readExternalBufYAMLFileV2ForExtends reads the v2 buf.yaml file referenced by the
+// value of an "extends" key, and returns it along with its normalized path.
+func readExternalBufYAMLFileV2ForExtends(
+	fieldName string,
+	extends string,
+	dirPath string,
+	extendsReadFunc func(string) ([]byte, error),
+	seenPaths map[string]struct{},
+) (externalBufYAMLFileV2, string, error) {
+	if isModuleRefExtends(extends) {
+		// The BSR does not store the lint and breaking configuration of modules.
+		return externalBufYAMLFileV2{}, "", fmt.Errorf("%s %q: extending the configuration of a BSR module is not supported, use a path to a buf.yaml file", fieldName, extends)
+	}
+	if extendsReadFunc == nil {
+		return externalBufYAMLFileV2{}, "", fmt.Errorf("%s %q: extending configuration is not supported in this context", fieldName, extends)
+	}
+	path := normalpath.Normalize(extends)
+	if !filepath.IsAbs(extends) {
+		path = normalpath.Join(dirPath, path)
+	}
+	if _, ok := seenPaths[path]; ok {
+		return externalBufYAMLFileV2{}, "", fmt.Errorf("%s %q: cycle detected", fieldName, extends)
+	}
+	seenPaths[path] = struct{}{}
+	data, err := extendsReadFunc(path)
+	if err != nil {
+		return externalBufYAMLFileV2{}, "", fmt.Errorf("%s %q: %w", fieldName, extends, err)
+	}
+	fileVersion, err := getFileVersionForData(data, true, true, bufYAMLFileNameToSupportedFileVersions, FileVersionV2, defaultBufYAMLFileVersion)
+	if err != nil {
+		return externalBufYAMLFileV2{}, "", fmt.Errorf("%s %q: %w", fieldName, extends, err)
+	}
+	if fileVersion != FileVersionV2 {
+		return externalBufYAMLFileV2{}, "", fmt.Errorf("%s %q: can only extend buf.yaml files of version %v, got %v", fieldName, extends, FileVersionV2, fileVersion)
+	}
+	var externalFile externalBufYAMLFileV2
+	if err := getUnmarshalStrict(true)(data, &externalFile); err != nil {
+		return externalBufYAMLFileV2{}, "", fmt.Errorf("%s %q: invalid as version %v: %w", fieldName, extends, fileVersion, err)
+	}
+	return externalFile, path, nil
+}
+
+// isModuleRefExtends returns true if the value of an "extends" key is a reference
+// to a BSR module, such as "buf.build/acme/policy", rather than a path.
+func isModuleRefExtends(extends string) bool {
+	switch filepath.Ext(extends) {
+	case ".json", ".yaml", ".yml":
+		return false
+	}
+	if strings.HasPrefix(extends, ".") || filepath.IsAbs(extends) {
+		return false
+	}
+	_, err := bufparse.ParseRef(extends)
+	return err == nil
+}
+
+func getFirstNonEmptySlice(values ...[]string) []string {
+	for _, value := range values {
+		if len(value) > 0 {
+			return value
+		}
+	}
+	return nil
+}
+
+func getFirstNonEmptyMap(values ...map[string]string) map[string]string {
+	for _, value := range values {
+		if len(value) > 0 {
+			return value
+		}
+	}
+	return nil
+}
+
+func mergeIgnoreOnlys(baseIgnoreOnly map[string][]string, ignoreOnly map[string][]string) map[string][]string {
+	if len(baseIgnoreOnly) == 0 {
+		return ignoreOnly
+	}
+	mergedIgnoreOnly := maps.Clone(baseIgnoreOnly)
+	for idOrCategory, paths := range ignoreOnly {
+		mergedIgnoreOnly[idOrCategory] = append(slices.Clone(mergedIgnoreOnly[idOrCategory]), paths...)
+	}
+	return mergedIgnoreOnly
+}
+
 func getLintConfigForExternalLintV2(
 	fileVersion FileVersion,
 	externalLint externalBufYAMLFileLintV2,
@@ -1066,6 +1426,18 @@ func getLintConfigForExternalLintV2(
 			return nil, err
 		}
 	}
+	namingConfig, err := getLintNamingConfigForExternalLintNamingV2(externalLint.Naming)
+	if err != nil {
+		return nil, err
+	}
+	commentsConfig, err := getLintCommentsConfigForExternalLintCommentsV2(externalLint.Comments)
+	if err != nil {
+		return nil, err
+	}
+	packageDirectoryMappings, err := getPackageDirectoryMappingsForExternalLintV2(externalLint.PackageDirectoryMappings)
+	if err != nil {
+		return nil, err
+	}
 	return newLintConfig(
 		checkConfig,
 		externalLint.EnumZeroValueSuffix,
@@ -1074,9 +1446,69 @@ func getLintConfigForExternalLintV2(
 		externalLint.RPCAllowGoogleProtobufEmptyResponses,
 		externalLint.ServiceSuffix,
 		!externalLint.DisallowCommentIgnores,
+		externalLint.RequireCommentIgnoreJustification,
+		externalLint.Warn,
+		namingConfig,
+		commentsConfig,
+		packageDirectoryMappings,
 	), nil
 }
 
+// getPackageDirectoryMappingsForExternalLintV2 validates the package prefixes and
+// normalizes the directories of the external mappings.
+func getPackageDirectoryMappingsForExternalLintV2(externalPackageDirectoryMappings map[string]string) (map[string]string, error) {
+	if len(externalPackageDirectoryMappings) == 0 {
+		return nil, nil
+	}
+	packageDirectoryMappings := make(map[string]string, len(externalPackageDirectoryMappings))
+	for packagePrefix, dirPath := range externalPackageDirectoryMappings {
+		if packagePrefix != "" {
+			for _, component := range strings.Split(packagePrefix, ".") {
+				if component == "" || strings.ContainsFunc(component, isInvalidPackageRune) {
+					return nil, fmt.Errorf("invalid lint.package_directory_mappings package prefix %q", packagePrefix)
+				}
+			}
+		}
+		normalDirPath, err := normalpath.NormalizeAndValidate(dirPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lint.package_directory_mappings directory %q for package prefix %q: %w", dirPath, packagePrefix, err)
+		}
+		packageDirectoryMappings[packagePrefix] = normalDirPath
+	}
+	return packageDirectoryMappings, nil
+}
+
+func isInvalidPackageRune(r rune) bool {
+	return !(r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9'))
+}
+
+// getLintNamingConfigForExternalLintNamingV2 returns nil if externalNaming is nil.
+func getLintNamingConfigForExternalLintNamingV2(externalNaming *externalBufYAMLFileLintNamingV2) (LintNamingConfig, error) {
+	if externalNaming == nil {
+		return nil, nil
+	}
+	return newLintNamingConfig(
+		externalNaming.Service,
+		externalNaming.RPC,
+		externalNaming.Message,
+		externalNaming.Field,
+		externalNaming.Enum,
+		externalNaming.EnumValue,
+	)
+}
+
+// getLintCommentsConfigForExternalLintCommentsV2 returns nil if externalComments is nil.
+func getLintCommentsConfigForExternalLintCommentsV2(externalComments *externalBufYAMLFileLintCommentsV2) (LintCommentsConfig, error) {
+	if externalComments == nil {
+		return nil, nil
+	}
+	return newLintCommentsConfig(
+		externalComments.MinLength,
+		externalComments.RequireNamePrefix,
+		externalComments.ExcludePrefixes,
+	)
+}
+
 func getBreakingConfigForExternalBreaking(
 	fileVersion FileVersion,
 	externalBreaking externalBufYAMLFileBreakingV1Beta1V1V2,
@@ -1084,6 +1516,7 @@ func getBreakingConfigForExternalBreaking(
 	requirePathsToBeContainedWithinModuleDirPath bool,
 ) (BreakingConfig, error) {
 	var checkConfig CheckConfig
+	ignoreIDOrCategoryToSymbols := make(map[string][]string)
 	disabled, err := isLintOrBreakingDisabledBasedOnIgnores("breaking.ignore", externalBreaking.Ignore, moduleDirPath)
 	if err != nil {
 		return nil, err
@@ -1096,7 +1529,11 @@ func getBreakingConfigForExternalBreaking(
 			return nil, err
 		}
 		ignoreOnly := make(map[string][]string)
-		for idOrCategory, paths := range externalBreaking.IgnoreOnly {
+		for idOrCategory, pathsAndSymbols := range externalBreaking.IgnoreOnly {
+			paths := slicesext.Filter(pathsAndSymbols, func(value string) bool { return !IsIgnoreOnlySymbolPattern(value) })
+			if symbols := slicesext.Filter(pathsAndSymbols, IsIgnoreOnlySymbolPattern); len(symbols) > 0 {
+				ignoreIDOrCategoryToSymbols[idOrCategory] = symbols
+			}
 			relPaths, err := getRelPathsForLintOrBreakingExternalPaths("breaking.ignore_only", paths, moduleDirPath, requirePathsToBeContainedWithinModuleDirPath)
 			if err != nil {
 				return nil, err
@@ -1117,9 +1554,15 @@ func getBreakingConfigForExternalBreaking(
 			return nil, err
 		}
 	}
-	return newBreakingConfig(
+	if err := validateSymbolPatterns(externalBreaking.IgnoreSymbols, "breaking.ignore_symbols"); err != nil {
+		return nil, err
+	}
+	return NewBreakingConfig(
 		checkConfig,
 		externalBreaking.IgnoreUnstablePackages,
+		externalBreaking.IgnoreSymbols,
+		ignoreIDOrCategoryToSymbols,
+		externalBreaking.FailOn,
 	), nil
 }
 
@@ -1149,6 +1592,8 @@ func isLintOrBreakingDisabledBasedOnIgnores(
 // getLintConfigForExternalLint or getBreakingConfigForExternalBreaking:
 //
 //   - Normalized and validates the path. If the path is invalid, returns error.
+//   - If the path is a glob pattern without a directory, or starting with "**/", adds the
+//     pattern as is, as it matches within every module.
 //   - Checks to make sure the path is not equal to the given module directory path. If so, returns error.
 //   - If the path is not contained within the module directory path, the path is not added to the
 //     returned slice if requirePathsToBeContainedWithinModuleDirPath is false. This can happen when we
@@ -1175,6 +1620,18 @@ func getRelPathsForLintOrBreakingExternalPaths(
 			// user error
 			return nil, fmt.Errorf("%s: invalid path: %w", fieldName, err)
 		}
+		if IsIgnorePathPattern(path) {
+			if err := validateIgnorePathPattern(path); err != nil {
+				// user error
+				return nil, fmt.Errorf("%s: invalid pattern %q: %w", fieldName, path, err)
+			}
+			// Patterns without a directory, and patterns starting with "**/", match within
+			// every module, so they are kept as is.
+			if moduleDirPath == "." || !strings.Contains(path, "/") || strings.HasPrefix(path, "**/") {
+				relPaths = append(relPaths, path)
+				continue
+			}
+		}
 		if !normalpath.EqualsOrContainsPath(moduleDirPath, path, normalpath.Relative) {
 			if !requirePathsToBeContainedWithinModuleDirPath {
 				continue
@@ -1213,6 +1670,34 @@ func getExternalLintV1Beta1V1ForLintConfig(lintConfig LintConfig, moduleDirPath
 	return externalLint
 }
 
+// getExternalLintNamingV2ForLintNamingConfig returns nil if no patterns are set.
+func getExternalLintNamingV2ForLintNamingConfig(namingConfig LintNamingConfig) *externalBufYAMLFileLintNamingV2 {
+	externalNaming := &externalBufYAMLFileLintNamingV2{
+		Service:   namingConfig.ServicePattern(),
+		RPC:       namingConfig.RPCPattern(),
+		Message:   namingConfig.MessagePattern(),
+		Field:     namingConfig.FieldPattern(),
+		Enum:      namingConfig.EnumPattern(),
+		EnumValue: namingConfig.EnumValuePattern(),
+	}
+	if *externalNaming == (externalBufYAMLFileLintNamingV2{}) {
+		return nil
+	}
+	return externalNaming
+}
+
+// getExternalLintCommentsV2ForLintCommentsConfig returns nil if no requirements are set.
+func getExternalLintCommentsV2ForLintCommentsConfig(commentsConfig LintCommentsConfig) *externalBufYAMLFileLintCommentsV2 {
+	if commentsConfig.MinLength() == 0 && !commentsConfig.RequireNamePrefix() && len(commentsConfig.ExcludePrefixes()) == 0 {
+		return nil
+	}
+	return &externalBufYAMLFileLintCommentsV2{
+		MinLength:         commentsConfig.MinLength(),
+		RequireNamePrefix: commentsConfig.RequireNamePrefix(),
+		ExcludePrefixes:   commentsConfig.ExcludePrefixes(),
+	}
+}
+
 func getExternalLintV2ForLintConfig(lintConfig LintConfig, moduleDirPath string) externalBufYAMLFileLintV2 {
 	joinDirPath := func(importPath string) string {
 		return normalpath.Join(moduleDirPath, importPath)
@@ -1232,7 +1717,12 @@ func getExternalLintV2ForLintConfig(lintConfig LintConfig, moduleDirPath string)
 	externalLint.RPCAllowGoogleProtobufEmptyResponses = lintConfig.RPCAllowGoogleProtobufEmptyResponses()
 	externalLint.ServiceSuffix = lintConfig.ServiceSuffix()
 	externalLint.DisallowCommentIgnores = !lintConfig.AllowCommentIgnores()
+	externalLint.RequireCommentIgnoreJustification = lintConfig.RequireCommentIgnoreJustification()
 	externalLint.DisableBuiltin = lintConfig.DisableBuiltin()
+	externalLint.Warn = lintConfig.WarnIDsAndCategories()
+	externalLint.Naming = getExternalLintNamingV2ForLintNamingConfig(lintConfig.NamingConfig())
+	externalLint.Comments = getExternalLintCommentsV2ForLintCommentsConfig(lintConfig.CommentsConfig())
+	externalLint.PackageDirectoryMappings = lintConfig.PackageDirectoryMappings()
 	return externalLint
 }
 
@@ -1249,8 +1739,13 @@ func getExternalBreakingForBreakingConfig(breakingConfig BreakingConfig, moduleD
 	for idOrCategory, importPaths := range breakingConfig.IgnoreIDOrCategoryToPaths() {
 		externalBreaking.IgnoreOnly[idOrCategory] = slicesext.Map(importPaths, joinDirPath)
 	}
+	for idOrCategory, symbols := range breakingConfig.IgnoreIDOrCategoryToSymbols() {
+		externalBreaking.IgnoreOnly[idOrCategory] = append(externalBreaking.IgnoreOnly[idOrCategory], symbols...)
+	}
 	externalBreaking.IgnoreUnstablePackages = breakingConfig.IgnoreUnstablePackages()
+	externalBreaking.IgnoreSymbols = breakingConfig.IgnoreSymbols()
 	externalBreaking.DisableBuiltin = breakingConfig.DisableBuiltin()
+	externalBreaking.FailOn = breakingConfig.FailOnIDsAndCategories()
 	return externalBreaking
 }
 
@@ -1280,6 +1775,14 @@ type externalBufYAMLFileV2 struct {
 	Lint     externalBufYAMLFileLintV2              `json:"lint,omitempty" yaml:"lint,omitempty"`
 	Breaking externalBufYAMLFileBreakingV1Beta1V1V2 `json:"breaking,omitempty" yaml:"breaking,omitempty"`
 	Plugins  []externalBufYAMLFilePluginV2          `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+	// Overrides replace dependencies with local directories.
+	Overrides []externalBufYAMLFileOverrideV2 `json:"overrides,omitempty" yaml:"overrides,omitempty"`
+}
+
+// externalBufYAMLFileOverrideV2 represents a single dependency override in a v2 buf.yaml file.
+type externalBufYAMLFileOverrideV2 struct {
+	Module string `json:"module,omitempty" yaml:"module,omitempty"`
+	Path   string `json:"path,omitempty" yaml:"path,omitempty"`
 }
 
 // externalBufYAMLFileModuleV2 represents a single module configuration within a v2 buf.yaml file.
@@ -1343,8 +1846,11 @@ func (el externalBufYAMLFileLintV1Beta1V1) isEmpty() bool {
 // Note that the lint and breaking ids/categories DID change between versions, make
 // sure to deal with this when parsing what to set as defaults, or how to interpret categories.
 type externalBufYAMLFileLintV2 struct {
-	Use    []string `json:"use,omitempty" yaml:"use,omitempty"`
-	Except []string `json:"except,omitempty" yaml:"except,omitempty"`
+	// Extends is the path to another v2 buf.yaml file whose lint configuration this
+	// configuration is merged on top of.
+	Extends string   `json:"extends,omitempty" yaml:"extends,omitempty"`
+	Use     []string `json:"use,omitempty" yaml:"use,omitempty"`
+	Except  []string `json:"except,omitempty" yaml:"except,omitempty"`
 	// Ignore are the paths to ignore.
 	Ignore []string `json:"ignore,omitempty" yaml:"ignore,omitempty"`
 	/// IgnoreOnly are the ID/category to paths to ignore.
@@ -1355,11 +1861,41 @@ type externalBufYAMLFileLintV2 struct {
 	RPCAllowGoogleProtobufEmptyResponses bool                `json:"rpc_allow_google_protobuf_empty_responses,omitempty" yaml:"rpc_allow_google_protobuf_empty_responses,omitempty"`
 	ServiceSuffix                        string              `json:"service_suffix,omitempty" yaml:"service_suffix,omitempty"`
 	DisallowCommentIgnores               bool                `json:"disallow_comment_ignores,omitempty" yaml:"disallow_comment_ignores,omitempty"`
+	RequireCommentIgnoreJustification    bool                `json:"require_comment_ignore_justification,omitempty" yaml:"require_comment_ignore_justification,omitempty"`
 	DisableBuiltin                       bool                `json:"disable_builtin,omitempty" yaml:"disable_builtin,omitempty"`
+	// Warn are the IDs/categories whose violations are warnings.
+	Warn []string `json:"warn,omitempty" yaml:"warn,omitempty"`
+	// Naming are the patterns that the names of elements must match for the NAMING rules.
+	Naming *externalBufYAMLFileLintNamingV2 `json:"naming,omitempty" yaml:"naming,omitempty"`
+	// Comments are the requirements that comments must meet for the COMMENT_* rules.
+	Comments *externalBufYAMLFileLintCommentsV2 `json:"comments,omitempty" yaml:"comments,omitempty"`
+	// PackageDirectoryMappings are the package prefixes to the directories that the files
+	// of packages with the prefix must be within for the PACKAGE_DIRECTORY_MATCH rule.
+	PackageDirectoryMappings map[string]string `json:"package_directory_mappings,omitempty" yaml:"package_directory_mappings,omitempty"`
+}
+
+// externalBufYAMLFileLintNamingV2 represents the naming patterns within the lint
+// configuration of a v2 buf.yaml file.
+type externalBufYAMLFileLintNamingV2 struct {
+	Service   string `json:"service,omitempty" yaml:"service,omitempty"`
+	RPC       string `json:"rpc,omitempty" yaml:"rpc,omitempty"`
+	Message   string `json:"message,omitempty" yaml:"message,omitempty"`
+	Field     string `json:"field,omitempty" yaml:"field,omitempty"`
+	Enum      string `json:"enum,omitempty" yaml:"enum,omitempty"`
+	EnumValue string `json:"enum_value,omitempty" yaml:"enum_value,omitempty"`
+}
+
+// externalBufYAMLFileLintCommentsV2 represents the comment requirements within the
+// lint configuration of a v2 buf.yaml file.
+type externalBufYAMLFileLintCommentsV2 struct {
+	MinLength         int      `json:"min_length,omitempty" yaml:"min_length,omitempty"`
+	RequireNamePrefix bool     `json:"require_name_prefix,omitempty" yaml:"require_name_prefix,omitempty"`
+	ExcludePrefixes   []string `json:"exclude_prefixes,omitempty" yaml:"exclude_prefixes,omitempty"`
 }
 
 func (el externalBufYAMLFileLintV2) isEmpty() bool {
-	return len(el.Use) == 0 &&
+	return el.Extends == "" &&
+		len(el.Use) == 0 &&
 		len(el.Except) == 0 &&
 		len(el.Ignore) == 0 &&
 		len(el.IgnoreOnly) == 0 &&
@@ -1369,7 +1905,12 @@ func (el externalBufYAMLFileLintV2) isEmpty() bool {
 		!el.RPCAllowGoogleProtobufEmptyResponses &&
 		el.ServiceSuffix == "" &&
 		!el.DisallowCommentIgnores &&
-		!el.DisableBuiltin
+		!el.RequireCommentIgnoreJustification &&
+		!el.DisableBuiltin &&
+		len(el.Warn) == 0 &&
+		el.Naming == nil &&
+		el.Comments == nil &&
+		len(el.PackageDirectoryMappings) == 0
 }
 
 // externalBufYAMLFileBreakingV1Beta1V1V2 represents breaking configuration within a v1beta1, v1,
@@ -1378,23 +1919,34 @@ func (el externalBufYAMLFileLintV2) isEmpty() bool {
 // Note that the lint and breaking ids/categories DID change between versions, make
 // sure to deal with this when parsing what to set as defaults, or how to interpret categories.
 type externalBufYAMLFileBreakingV1Beta1V1V2 struct {
-	Use    []string `json:"use,omitempty" yaml:"use,omitempty"`
-	Except []string `json:"except,omitempty" yaml:"except,omitempty"`
+	// Extends is the path to another v2 buf.yaml file whose breaking configuration this
+	// configuration is merged on top of. Only valid in v2!
+	Extends string   `json:"extends,omitempty" yaml:"extends,omitempty"`
+	Use     []string `json:"use,omitempty" yaml:"use,omitempty"`
+	Except  []string `json:"except,omitempty" yaml:"except,omitempty"`
 	// Ignore are the paths to ignore.
 	Ignore []string `json:"ignore,omitempty" yaml:"ignore,omitempty"`
 	/// IgnoreOnly are the ID/category to paths to ignore.
 	IgnoreOnly             map[string][]string `json:"ignore_only,omitempty" yaml:"ignore_only,omitempty"`
 	IgnoreUnstablePackages bool                `json:"ignore_unstable_packages,omitempty" yaml:"ignore_unstable_packages,omitempty"`
-	DisableBuiltin         bool                `json:"disable_builtin,omitempty" yaml:"disable_builtin,omitempty"`
+	// IgnoreSymbols are the fully-qualified symbol patterns to ignore.
+	IgnoreSymbols  []string `json:"ignore_symbols,omitempty" yaml:"ignore_symbols,omitempty"`
+	DisableBuiltin bool     `json:"disable_builtin,omitempty" yaml:"disable_builtin,omitempty"`
+	// FailOn are the IDs/categories whose breaking changes are failures. If set, the
+	// breaking changes of all other rules are warnings.
+	FailOn []string `json:"fail_on,omitempty" yaml:"fail_on,omitempty"`
 }
 
 func (eb externalBufYAMLFileBreakingV1Beta1V1V2) isEmpty() bool {
-	return len(eb.Use) == 0 &&
+	return eb.Extends == "" &&
+		len(eb.Use) == 0 &&
 		len(eb.Except) == 0 &&
 		len(eb.Ignore) == 0 &&
 		len(eb.IgnoreOnly) == 0 &&
 		!eb.IgnoreUnstablePackages &&
-		!eb.DisableBuiltin
+		len(eb.IgnoreSymbols) == 0 &&
+		!eb.DisableBuiltin &&
+		len(eb.FailOn) == 0
 }
 
 // externalBufYAMLFilePluginV2 represents a single plugin config in a v2 buf.gyaml file.
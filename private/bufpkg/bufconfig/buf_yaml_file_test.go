@@ -16,10 +16,12 @@ package bufconfig
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -119,6 +121,152 @@ lint:
 `,
 	)
 
+	testReadWriteBufYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+lint:
+  use:
+    - DEFAULT
+  require_comment_ignore_justification: true
+modules:
+  - path: .
+`,
+		// expected output
+		`version: v2
+lint:
+  use:
+    - DEFAULT
+  require_comment_ignore_justification: true
+`,
+	)
+
+	testReadWriteBufYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+lint:
+  use:
+    - STANDARD
+  warn:
+    - FIELD_LOWER_SNAKE_CASE
+    - COMMENTS
+modules:
+  - path: .
+`,
+		// expected output
+		`version: v2
+lint:
+  use:
+    - STANDARD
+  warn:
+    - COMMENTS
+    - FIELD_LOWER_SNAKE_CASE
+`,
+	)
+
+	testReadWriteBufYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+breaking:
+  use:
+    - WIRE_JSON
+  fail_on:
+    - WIRE
+    - FIELD_SAME_JSON_NAME
+modules:
+  - path: .
+`,
+		// expected output
+		`version: v2
+breaking:
+  use:
+    - WIRE_JSON
+  fail_on:
+    - FIELD_SAME_JSON_NAME
+    - WIRE
+`,
+	)
+
+	testReadWriteBufYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+lint:
+  use:
+    - NAMING
+  naming:
+    service: ^[A-Z][a-zA-Z0-9]*API$
+    enum_value: ^[A-Z][A-Z0-9_]*$
+modules:
+  - path: .
+`,
+		// expected output
+		`version: v2
+lint:
+  use:
+    - NAMING
+  naming:
+    service: ^[A-Z][a-zA-Z0-9]*API$
+    enum_value: ^[A-Z][A-Z0-9_]*$
+`,
+	)
+
+	testReadWriteBufYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+lint:
+  use:
+    - COMMENTS
+  comments:
+    min_length: 10
+    require_name_prefix: true
+    exclude_prefixes:
+      - TODO
+      - "@"
+modules:
+  - path: .
+`,
+		// expected output
+		`version: v2
+lint:
+  use:
+    - COMMENTS
+  comments:
+    min_length: 10
+    require_name_prefix: true
+    exclude_prefixes:
+      - '@'
+      - TODO
+`,
+	)
+
+	testReadWriteBufYAMLFileRoundTrip(
+		t,
+		// input
+		`version: v2
+lint:
+  use:
+    - PACKAGE_DIRECTORY_MATCH
+  package_directory_mappings:
+    acme: ./protos
+    "": src
+modules:
+  - path: .
+`,
+		// expected output
+		`version: v2
+lint:
+  use:
+    - PACKAGE_DIRECTORY_MATCH
+  package_directory_mappings:
+    "": src
+    acme: protos
+`,
+	)
+
 	testReadWriteBufYAMLFileRoundTrip(
 		t,
 		// input
@@ -592,6 +740,117 @@ modules:
 	require.True(t, moduleConfig1.BreakingConfig().Disabled())
 }
 
+func TestBufYAMLFileIgnorePatterns(t *testing.T) {
+	t.Parallel()
+
+	bufYAMLFile := testReadBufYAMLFile(
+		t,
+		`version: v2
+modules:
+  - path: proto
+  - path: vendor
+lint:
+  ignore:
+    - "**/third_party/**"
+    - "*_internal.proto"
+    - proto/acme/*/v1
+    - vendor/google
+breaking:
+  ignore_only:
+    FIELD_SAME_TYPE:
+      - "vendor/**/legacy"
+`,
+	)
+	moduleConfig0 := bufYAMLFile.ModuleConfigs()[0]
+	moduleConfig1 := bufYAMLFile.ModuleConfigs()[1]
+	require.Equal(
+		t,
+		[]string{"**/third_party/**", "*_internal.proto", "acme/*/v1"},
+		moduleConfig0.LintConfig().IgnorePaths(),
+	)
+	require.Equal(
+		t,
+		[]string{"**/third_party/**", "*_internal.proto", "google"},
+		moduleConfig1.LintConfig().IgnorePaths(),
+	)
+	require.Empty(t, moduleConfig0.BreakingConfig().IgnoreIDOrCategoryToPaths())
+	require.Equal(
+		t,
+		map[string][]string{"FIELD_SAME_TYPE": {"**/legacy"}},
+		moduleConfig1.BreakingConfig().IgnoreIDOrCategoryToPaths(),
+	)
+
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+lint:
+  ignore:
+    - "proto/**.proto"
+`,
+		`lint.ignore: invalid pattern "proto/**.proto"`,
+	)
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+lint:
+  ignore:
+    - "proto/[a"
+`,
+		`lint.ignore: invalid pattern "proto/[a"`,
+	)
+}
+
+func TestBufYAMLFileBreakingIgnoreOnlySymbols(t *testing.T) {
+	t.Parallel()
+
+	bufYAMLFile := testReadBufYAMLFile(
+		t,
+		`version: v2
+modules:
+  - path: proto
+breaking:
+  ignore_only:
+    FIELD_NO_DELETE:
+      - acme.internal.**
+      - proto/acme/legacy
+    RPC_NO_DELETE:
+      - acme.*.v1.LegacyService
+`,
+	)
+	breakingConfig := bufYAMLFile.ModuleConfigs()[0].BreakingConfig()
+	require.Equal(
+		t,
+		map[string][]string{"FIELD_NO_DELETE": {"acme/legacy"}},
+		breakingConfig.IgnoreIDOrCategoryToPaths(),
+	)
+	require.Equal(
+		t,
+		map[string][]string{
+			"FIELD_NO_DELETE": {"acme.internal.**"},
+			"RPC_NO_DELETE":   {"acme.*.v1.LegacyService"},
+		},
+		breakingConfig.IgnoreIDOrCategoryToSymbols(),
+	)
+
+	testReadWriteBufYAMLFileRoundTrip(
+		t,
+		`version: v1
+breaking:
+  ignore_only:
+    FIELD_NO_DELETE:
+      - acme.internal.**
+      - legacy.proto
+`,
+		`version: v1
+breaking:
+  ignore_only:
+    FIELD_NO_DELETE:
+      - legacy.proto
+      - acme.internal.**
+`,
+	)
+}
+
 func TestBufYAMLInvalidIncludes(t *testing.T) {
 	t.Parallel()
 	testReadBufYAMLFileFail(
@@ -688,6 +947,244 @@ modules:
 	)
 }
 
+func TestBufYAMLInvalidLintNaming(t *testing.T) {
+	t.Parallel()
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+lint:
+  naming:
+    field: "[a-z"
+`,
+		`invalid lint.naming.field pattern "[a-z"`,
+	)
+}
+
+func TestBufYAMLInvalidLintComments(t *testing.T) {
+	t.Parallel()
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+lint:
+  comments:
+    min_length: -1
+`,
+		`lint.comments.min_length must not be negative`,
+	)
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+lint:
+  comments:
+    exclude_prefixes:
+      - ""
+`,
+		`lint.comments.exclude_prefixes must not contain empty prefixes`,
+	)
+}
+
+func TestBufYAMLInvalidLintPackageDirectoryMappings(t *testing.T) {
+	t.Parallel()
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+lint:
+  package_directory_mappings:
+    acme..foo: protos
+`,
+		`invalid lint.package_directory_mappings package prefix "acme..foo"`,
+	)
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+lint:
+  package_directory_mappings:
+    acme: ../protos
+`,
+		`invalid lint.package_directory_mappings directory "../protos" for package prefix "acme"`,
+	)
+}
+
+func TestBufYAMLFileExtends(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bucket, err := storagemem.NewReadBucket(
+		map[string][]byte{
+			"policy/buf.yaml": []byte(`version: v2
+lint:
+  extends: root.yaml
+  use:
+    - STANDARD
+  except:
+    - FIELD_LOWER_SNAKE_CASE
+  ignore_only:
+    ENUM_ZERO_VALUE_SUFFIX:
+      - proto/legacy
+  service_suffix: API
+breaking:
+  use:
+    - WIRE_JSON
+  ignore_unstable_packages: true
+`),
+			"policy/root.yaml": []byte(`version: v2
+lint:
+  enum_zero_value_suffix: _NONE
+  disallow_comment_ignores: true
+`),
+			"buf.yaml": []byte(`version: v2
+modules:
+  - path: proto
+lint:
+  extends: policy/buf.yaml
+  except:
+    - PACKAGE_VERSION_SUFFIX
+  ignore_only:
+    ENUM_ZERO_VALUE_SUFFIX:
+      - proto/old
+breaking:
+  extends: policy/buf.yaml
+  ignore:
+    - proto/legacy
+`),
+			"cycle/buf.yaml": []byte(`version: v2
+breaking:
+  extends: other.yaml
+`),
+			"cycle/other.yaml": []byte(`version: v2
+breaking:
+  extends: buf.yaml
+`),
+			"v1/buf.yaml": []byte(`version: v2
+lint:
+  extends: base.yaml
+`),
+			"v1/base.yaml": []byte(`version: v1
+lint:
+  use:
+    - DEFAULT
+`),
+		},
+	)
+	require.NoError(t, err)
+
+	bufYAMLFile, err := GetBufYAMLFileForPrefix(ctx, bucket, ".")
+	require.NoError(t, err)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteBufYAMLFile(buffer, bufYAMLFile))
+	assert.Equal(
+		t,
+		testCleanYAMLData(`version: v2
+modules:
+  - path: proto
+lint:
+  use:
+    - STANDARD
+  except:
+    - PACKAGE_VERSION_SUFFIX
+  ignore_only:
+    ENUM_ZERO_VALUE_SUFFIX:
+      - proto/legacy
+      - proto/old
+  enum_zero_value_suffix: _NONE
+  service_suffix: API
+  disallow_comment_ignores: true
+breaking:
+  use:
+    - WIRE_JSON
+  ignore:
+    - proto/legacy
+  ignore_unstable_packages: true
+`),
+		testCleanYAMLData(buffer.String()),
+	)
+
+	_, err = GetBufYAMLFileForPrefix(ctx, bucket, "cycle")
+	require.ErrorContains(t, err, "cycle detected")
+	_, err = GetBufYAMLFileForPrefix(ctx, bucket, "v1")
+	require.ErrorContains(t, err, "can only extend buf.yaml files of version v2")
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+lint:
+  extends: policy/buf.yaml
+`,
+		"not supported in this context",
+	)
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+lint:
+  extends: buf.build/acme/policy
+`,
+		"extending the configuration of a BSR module is not supported",
+	)
+	testReadBufYAMLFileFail(
+		t,
+		`version: v1
+breaking:
+  extends: policy/buf.yaml
+`,
+		"breaking.extends cannot be set on version v1",
+	)
+}
+
+func TestBufYAMLFileModuleOverrides(t *testing.T) {
+	t.Parallel()
+	testReadWriteBufYAMLFileRoundTrip(
+		t,
+		`version: v2
+modules:
+  - path: proto
+    name: buf.build/acme/app
+overrides:
+  - module: buf.build/acme/shared
+    path: ../shared/./proto
+  - module: buf.build/acme/common
+    path: /src/common
+`,
+		`version: v2
+modules:
+  - path: proto
+    name: buf.build/acme/app
+overrides:
+  - module: buf.build/acme/common
+    path: /src/common
+  - module: buf.build/acme/shared
+    path: ../shared/proto
+`,
+	)
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+overrides:
+  - module: buf.build/acme/shared
+`,
+		"path must be set for the override of buf.build/acme/shared",
+	)
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+overrides:
+  - module: buf.build/acme/shared
+    path: a
+  - module: buf.build/acme/shared
+    path: b
+`,
+		"buf.build/acme/shared",
+	)
+	testReadBufYAMLFileFail(
+		t,
+		`version: v2
+name: buf.build/acme/shared
+overrides:
+  - module: buf.build/acme/shared
+    path: ../shared
+`,
+		"buf.build/acme/shared is a module in the workspace and cannot be overridden",
+	)
+}
+
 func testReadWriteBufYAMLFileRoundTrip(
 	t *testing.T,
 	inputBufYAMLFileData string,
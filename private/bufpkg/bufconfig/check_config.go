@@ -89,11 +89,13 @@ type CheckConfig interface {
 	// Paths are specific to the Module. Users cannot ignore paths outside of their modules for check
 	// configs, which includes any imports from outside of the module.
 	// Paths are relative to roots.
+	// Paths may be glob patterns, see IsIgnorePathPattern.
 	// Paths are sorted.
 	IgnorePaths() []string
 	// Paths are specific to the Module. Users cannot ignore paths outside of their modules for
 	// check configs, which includes any imports from outside of the module.
 	// Paths are relative to roots.
+	// Paths may be glob patterns, see IsIgnorePathPattern.
 	// Paths are sorted.
 	IgnoreIDOrCategoryToPaths() map[string][]string
 	// DisableBuiltin says to disable the Rules and Categories builtin to the Buf CLI and only
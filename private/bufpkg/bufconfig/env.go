@@ -0,0 +1,266 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconfig
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// interpolateEnvForDataIfEnabled interpolates environment variables in the data if the
+// data sets "interpolate_env: true", and otherwise returns the data unchanged.
+func interpolateEnvForDataIfEnabled(data []byte, envFunc func(string) string) ([]byte, error) {
+	var externalInterpolateEnv struct {
+		InterpolateEnv bool `json:"interpolate_env,omitempty" yaml:"interpolate_env,omitempty"`
+	}
+	if err := getUnmarshalNonStrict(true)(data, &externalInterpolateEnv); err != nil {
+		// Let the caller report unmarshal errors as it would without interpolation.
+		return data, nil
+	}
+	if !externalInterpolateEnv.InterpolateEnv {
+		return data, nil
+	}
+	return interpolateEnvForData(data, envFunc)
+}
+
+// interpolateEnvForData interpolates environment variables in all scalar values
+// of the YAML or JSON data.
+//
+// Mapping keys and comments are never interpolated. The interpolated values replace
+// the values in the data in place, so that the lines of the data do not change and
+// errors for the data refer to the lines of the original data. If no values reference
+// an environment variable, the data is returned unchanged.
+func interpolateEnvForData(data []byte, envFunc func(string) string) ([]byte, error) {
+	if len(data) == 0 || !strings.Contains(string(data), "${") {
+		return data, nil
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		// Let the caller report unmarshal errors as it would without interpolation.
+		return data, nil
+	}
+	var replacements []*envReplacement
+	if err := getEnvReplacementsForNode(&node, envFunc, &replacements); err != nil {
+		return nil, err
+	}
+	if len(replacements) == 0 {
+		return data, nil
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	// Replaced from the end, so that the columns of the remaining replacements on a
+	// line are still valid.
+	slices.Reverse(replacements)
+	for _, replacement := range replacements {
+		line := []rune(lines[replacement.line-1])
+		start := replacement.column - 1
+		end := start + len([]rune(replacement.oldText))
+		if end > len(line) || string(line[start:end]) != replacement.oldText {
+			return nil, fmt.Errorf(
+				"line %d: environment variables can only be referenced in values on a single line without escape sequences",
+				replacement.line,
+			)
+		}
+		lines[replacement.line-1] = string(line[:start]) + replacement.newText + string(line[end:])
+	}
+	return []byte(strings.Join(lines, "")), nil
+}
+
+// envReplacement is the replacement of the text of a scalar value at a line and
+// column of the data with the text of the interpolated value.
+type envReplacement struct {
+	line    int
+	column  int
+	oldText string
+	newText string
+}
+
+// getEnvReplacementsForNode appends the replacements for the scalar values of the node
+// to replacements, in the order of the values in the data.
+func getEnvReplacementsForNode(node *yaml.Node, envFunc func(string) string, replacements *[]*envReplacement) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		value, err := interpolateEnv(node.Value, envFunc)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		if value == node.Value {
+			return nil
+		}
+		replacement, err := newEnvReplacement(node, value)
+		if err != nil {
+			return err
+		}
+		*replacements = append(*replacements, replacement)
+		return nil
+	case yaml.MappingNode:
+		// Only interpolate values, keys are at even indexes.
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := getEnvReplacementsForNode(node.Content[i], envFunc, replacements); err != nil {
+				return err
+			}
+		}
+		return nil
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := getEnvReplacementsForNode(child, envFunc, replacements); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+func newEnvReplacement(node *yaml.Node, value string) (*envReplacement, error) {
+	var oldText string
+	switch node.Style {
+	case 0:
+		oldText = node.Value
+	case yaml.SingleQuotedStyle:
+		oldText = "'" + strings.ReplaceAll(node.Value, "'", "''") + "'"
+	case yaml.DoubleQuotedStyle:
+		// The value is only found if it has no escape sequences, which is checked
+		// when the replacement is made.
+		oldText = `"` + node.Value + `"`
+	default:
+		return nil, fmt.Errorf(
+			"line %d: environment variables can only be referenced in values on a single line without escape sequences",
+			node.Line,
+		)
+	}
+	newText := strconv.Quote(value)
+	if node.Style == 0 && isPlainYAMLValue(value) {
+		// Plain values stay plain, so that they are resolved from the interpolated
+		// value, for example "revision: ${REVISION}" is read as an integer.
+		newText = value
+	}
+	return &envReplacement{
+		line:    node.Line,
+		column:  node.Column,
+		oldText: oldText,
+		newText: newText,
+	}, nil
+}
+
+// isPlainYAMLValue returns true if the value is read as the same value when written
+// as a plain value on a single line.
+func isPlainYAMLValue(value string) bool {
+	if value == "" {
+		return true
+	}
+	if strings.ContainsAny(value, "\n\r") {
+		return false
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte("key: "+value), &node); err != nil {
+		return false
+	}
+	if len(node.Content) != 1 || len(node.Content[0].Content) != 2 {
+		return false
+	}
+	valueNode := node.Content[0].Content[1]
+	return valueNode.Kind == yaml.ScalarNode && valueNode.Style == 0 && valueNode.Value == value
+}
+
+// interpolateEnv interpolates environment variables in the value.
+//
+// The following forms are supported:
+//
+//	${VAR}            The value of VAR, or an error if VAR is unset or empty.
+//	${VAR:-default}   The value of VAR, or default if VAR is unset or empty.
+//	${VAR:?message}   The value of VAR, or an error with message if VAR is unset or empty.
+//	$${               A literal "${".
+func interpolateEnv(value string, envFunc func(string) string) (string, error) {
+	var builder strings.Builder
+	for {
+		index := strings.Index(value, "${")
+		if index < 0 {
+			builder.WriteString(value)
+			return builder.String(), nil
+		}
+		if index > 0 && value[index-1] == '$' {
+			builder.WriteString(value[:index-1])
+			builder.WriteString("${")
+			value = value[index+2:]
+			continue
+		}
+		builder.WriteString(value[:index])
+		value = value[index+2:]
+		endIndex := strings.IndexByte(value, '}')
+		if endIndex < 0 {
+			return "", fmt.Errorf("unterminated environment variable reference %q", "${"+value)
+		}
+		resolved, err := resolveEnvExpression(value[:endIndex], envFunc)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(resolved)
+		value = value[endIndex+1:]
+	}
+}
+
+func resolveEnvExpression(expression string, envFunc func(string) string) (string, error) {
+	name, operand, operator := expression, "", ""
+	if index := strings.Index(expression, ":"); index >= 0 {
+		name, operand = expression[:index], expression[index+1:]
+		if operand == "" || (operand[0] != '-' && operand[0] != '?') {
+			return "", fmt.Errorf("invalid environment variable reference %q: expected \":-\" or \":?\" after the variable name", "${"+expression+"}")
+		}
+		operator, operand = operand[:1], operand[1:]
+	}
+	if !isEnvName(name) {
+		return "", fmt.Errorf("invalid environment variable name %q in %q", name, "${"+expression+"}")
+	}
+	if value := envFunc(name); value != "" {
+		return value, nil
+	}
+	switch operator {
+	case "-":
+		return operand, nil
+	case "?":
+		if operand == "" {
+			return "", fmt.Errorf("environment variable %q is required", name)
+		}
+		return "", fmt.Errorf("environment variable %q is required: %s", name, operand)
+	default:
+		return "", fmt.Errorf(
+			"environment variable %q is not set, use %q to default to the empty string or %q for a literal %q",
+			name,
+			"${"+name+":-}",
+			"$${"+expression+"}",
+			"${",
+		)
+	}
+}
+
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
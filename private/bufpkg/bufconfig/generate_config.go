@@ -128,7 +128,7 @@ func newGenerateConfigFromExternalFileV2(
 		return nil, err
 	}
 	return &generateConfig{
-		cleanPluginOuts:       externalFile.Clean,
+		cleanPluginOuts:       externalFile.Clean != nil && *externalFile.Clean,
 		generateManagedConfig: generateManagedConfig,
 		generatePluginConfigs: generatePluginConfigs,
 	}, nil
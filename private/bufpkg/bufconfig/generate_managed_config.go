@@ -17,12 +17,14 @@ package bufconfig
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/bufbuild/buf/private/bufpkg/bufparse"
 	"github.com/bufbuild/buf/private/pkg/normalpath"
 	"github.com/bufbuild/buf/private/pkg/slicesext"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // GenerateManagedConfig is a managed mode configuration.
@@ -99,12 +101,18 @@ func NewManagedDisableRule(
 
 // ManagedOverrideRule is an override rule. An override describes:
 //
-//   - The options to modify. Exactly one of FileOption and FieldOption is not empty.
+//   - The options to modify. Exactly one of FileOption, FieldOption, CustomFileOption
+//     and CustomMessageOption is not empty.
 //   - The value to modify these options with.
 //   - The files/fields for which the options are modified. If all of Path, FullName
 //   - or FieldName are empty, all files/fields are modified. Otherwise, only
 //     file/fields that match the specified Path, FullName and FieldName
 //     is modified.
+//   - Optionally, a Match that files must additionally satisfy for the override
+//     to apply.
+//
+// The value is either a literal Value, or a ValueExpression that computes the value
+// for each file.
 type ManagedOverrideRule interface {
 	// Path is the file path, relative to its module, to disable managed mode for.
 	Path() string
@@ -119,24 +127,121 @@ type ManagedOverrideRule interface {
 	FileOption() FileOption
 	// FieldOption returns the field option to disable managed mode for.
 	FieldOption() FieldOption
+	// CustomFileOption returns the fully-qualified name of the custom file option
+	// to override, such as "acme.v1.owner", or empty if this is not an override
+	// for a custom file option.
+	CustomFileOption() string
+	// CustomMessageOption returns the fully-qualified name of the custom message
+	// option to override for every message in the file, or empty if this is not
+	// an override for a custom message option.
+	CustomMessageOption() string
 	// Value returns the override value.
+	//
+	// For custom options, the value is interpreted according to the JSON mapping
+	// of the type of the option, and may be a scalar, a list, or a map.
+	//
+	// This is nil if ValueExpression is set.
 	Value() interface{}
+	// ValueExpression returns the expression that computes the value for each file,
+	// or nil if the value is a literal Value.
+	ValueExpression() ManagedValueExpression
+	// Match returns the additional conditions a file must satisfy for this
+	// override to apply, or nil if there are none.
+	Match() ManagedOverrideMatch
 
 	isManagedOverrideRule()
 }
 
+// ManagedOverrideRuleOption is an option for a new ManagedOverrideRule.
+type ManagedOverrideRuleOption func(*managedOverrideRuleOptions)
+
+// ManagedOverrideRuleWithMatch returns a new ManagedOverrideRuleOption that restricts
+// the override to files that satisfy the given match.
+func ManagedOverrideRuleWithMatch(match ManagedOverrideMatch) ManagedOverrideRuleOption {
+	return func(managedOverrideRuleOptions *managedOverrideRuleOptions) {
+		managedOverrideRuleOptions.match = match
+	}
+}
+
+// ManagedOverrideRuleWithValueExpression returns a new ManagedOverrideRuleOption that
+// computes the value of the override for each file with the given expression.
+//
+// If set, the value passed to the constructor of the ManagedOverrideRule must be nil.
+func ManagedOverrideRuleWithValueExpression(valueExpression ManagedValueExpression) ManagedOverrideRuleOption {
+	return func(managedOverrideRuleOptions *managedOverrideRuleOptions) {
+		managedOverrideRuleOptions.valueExpression = valueExpression
+	}
+}
+
+// ManagedOverrideMatch is a set of conditions on a file for an override rule to apply.
+//
+// All conditions that are set must be satisfied. At least one condition is set.
+type ManagedOverrideMatch interface {
+	// PathRegex returns the regular expression that the file path, relative to
+	// its module, must match. Empty if not set.
+	PathRegex() string
+	// FileOptionMatch returns the file option the file must have set, or nil if not set.
+	FileOptionMatch() ManagedOptionMatch
+	// MessageOptionMatch returns the message option that at least one message in the
+	// file must have set, or nil if not set.
+	MessageOptionMatch() ManagedOptionMatch
+	// FieldOptionMatch returns the field option that at least one field or extension
+	// in the file must have set, or nil if not set.
+	FieldOptionMatch() ManagedOptionMatch
+
+	isManagedOverrideMatch()
+}
+
+// NewManagedOverrideMatch returns a new ManagedOverrideMatch.
+//
+// Any of fileOptionMatch, messageOptionMatch and fieldOptionMatch may be nil.
+func NewManagedOverrideMatch(
+	pathRegex string,
+	fileOptionMatch ManagedOptionMatch,
+	messageOptionMatch ManagedOptionMatch,
+	fieldOptionMatch ManagedOptionMatch,
+) (ManagedOverrideMatch, error) {
+	return newManagedOverrideMatch(
+		pathRegex,
+		fileOptionMatch,
+		messageOptionMatch,
+		fieldOptionMatch,
+	)
+}
+
+// ManagedOptionMatch matches an option set on a descriptor.
+type ManagedOptionMatch interface {
+	// Name returns the name of the option. This is either the name of a field of
+	// the options message, such as "deprecated", or the fully-qualified name of a
+	// custom option in parentheses, such as "(acme.v1.my_option)".
+	Name() string
+	// Value returns the value the option must have, or nil if the option only
+	// needs to be set. The value is always a scalar: a string, bool, or number.
+	// Enum values are matched by name.
+	Value() interface{}
+
+	isManagedOptionMatch()
+}
+
+// NewManagedOptionMatch returns a new ManagedOptionMatch.
+func NewManagedOptionMatch(name string, value interface{}) (ManagedOptionMatch, error) {
+	return newManagedOptionMatch(name, value)
+}
+
 // NewManagedOverrideRuleForFileOption returns a new ManagedOverrideRule for a file option.
 func NewManagedOverrideRuleForFileOption(
 	path string,
 	moduleFullName string,
 	fileOption FileOption,
 	value interface{},
+	options ...ManagedOverrideRuleOption,
 ) (ManagedOverrideRule, error) {
 	return newFileOptionManagedOverrideRule(
 		path,
 		moduleFullName,
 		fileOption,
 		value,
+		options...,
 	)
 }
 
@@ -147,6 +252,7 @@ func NewManagedOverrideRuleForFieldOption(
 	fieldName string,
 	fieldOption FieldOption,
 	value interface{},
+	options ...ManagedOverrideRuleOption,
 ) (ManagedOverrideRule, error) {
 	return newFieldOptionManagedOverrideRule(
 		path,
@@ -154,6 +260,49 @@ func NewManagedOverrideRuleForFieldOption(
 		fieldName,
 		fieldOption,
 		value,
+		options...,
+	)
+}
+
+// NewManagedOverrideRuleForCustomFileOption returns a new ManagedOverrideRule for a custom
+// file option.
+//
+// The optionName is the fully-qualified name of the extension, optionally in parentheses.
+func NewManagedOverrideRuleForCustomFileOption(
+	path string,
+	moduleFullName string,
+	optionName string,
+	value interface{},
+	options ...ManagedOverrideRuleOption,
+) (ManagedOverrideRule, error) {
+	return newCustomOptionManagedOverrideRule(
+		path,
+		moduleFullName,
+		optionName,
+		false,
+		value,
+		options...,
+	)
+}
+
+// NewManagedOverrideRuleForCustomMessageOption returns a new ManagedOverrideRule for a custom
+// message option.
+//
+// The optionName is the fully-qualified name of the extension, optionally in parentheses.
+func NewManagedOverrideRuleForCustomMessageOption(
+	path string,
+	moduleFullName string,
+	optionName string,
+	value interface{},
+	options ...ManagedOverrideRuleOption,
+) (ManagedOverrideRule, error) {
+	return newCustomOptionManagedOverrideRule(
+		path,
+		moduleFullName,
+		optionName,
+		true,
+		value,
+		options...,
 	)
 }
 
@@ -435,14 +584,42 @@ func newGenerateManagedConfigFromExternalV2(
 		disables = append(disables, disable)
 	}
 	for _, externalOverrideConfig := range externalConfig.Override {
-		if externalOverrideConfig.FileOption == "" && externalOverrideConfig.FieldOption == "" {
-			return nil, errors.New("must set file_option or field_option for an override")
+		var numOptionsSet int
+		for _, optionName := range []string{
+			externalOverrideConfig.FileOption,
+			externalOverrideConfig.FieldOption,
+			externalOverrideConfig.MessageOption,
+		} {
+			if optionName != "" {
+				numOptionsSet++
+			}
+		}
+		if numOptionsSet == 0 {
+			return nil, errors.New("must set file_option, field_option or message_option for an override")
 		}
-		if externalOverrideConfig.FileOption != "" && externalOverrideConfig.FieldOption != "" {
-			return nil, errors.New("exactly one of file_option and field_option must be set for an override")
+		if numOptionsSet > 1 {
+			return nil, errors.New("exactly one of file_option, field_option and message_option must be set for an override")
 		}
-		if externalOverrideConfig.Value == nil {
-			return nil, errors.New("must set value for an override")
+		if externalOverrideConfig.Value == nil && externalOverrideConfig.ValueExpression == "" {
+			return nil, errors.New("must set value or value_expression for an override")
+		}
+		if externalOverrideConfig.Value != nil && externalOverrideConfig.ValueExpression != "" {
+			return nil, errors.New("at most one of value and value_expression can be set for an override")
+		}
+		var overrideRuleOptions []ManagedOverrideRuleOption
+		if externalOverrideConfig.ValueExpression != "" {
+			valueExpression, err := newManagedValueExpression(externalOverrideConfig.ValueExpression)
+			if err != nil {
+				return nil, err
+			}
+			overrideRuleOptions = append(overrideRuleOptions, ManagedOverrideRuleWithValueExpression(valueExpression))
+		}
+		if externalOverrideConfig.Match != nil {
+			match, err := newManagedOverrideMatchFromExternalV2(*externalOverrideConfig.Match)
+			if err != nil {
+				return nil, err
+			}
+			overrideRuleOptions = append(overrideRuleOptions, ManagedOverrideRuleWithMatch(match))
 		}
 		if externalOverrideConfig.FieldOption != "" {
 			fieldOption, err := parseFieldOption(externalOverrideConfig.FieldOption)
@@ -455,6 +632,27 @@ func newGenerateManagedConfigFromExternalV2(
 				externalOverrideConfig.Field,
 				fieldOption,
 				externalOverrideConfig.Value,
+				overrideRuleOptions...,
+			)
+			if err != nil {
+				return nil, err
+			}
+			overrides = append(overrides, override)
+			continue
+		}
+		if externalOverrideConfig.MessageOption != "" {
+			if externalOverrideConfig.Field != "" {
+				return nil, errors.New("must not set field for a message_option override")
+			}
+			if !isCustomOptionName(externalOverrideConfig.MessageOption) {
+				return nil, fmt.Errorf("invalid message_option %q: only custom options, such as \"(acme.v1.my_option)\", can be overridden", externalOverrideConfig.MessageOption)
+			}
+			override, err := NewManagedOverrideRuleForCustomMessageOption(
+				externalOverrideConfig.Path,
+				externalOverrideConfig.Module,
+				externalOverrideConfig.MessageOption,
+				externalOverrideConfig.Value,
+				overrideRuleOptions...,
 			)
 			if err != nil {
 				return nil, err
@@ -465,6 +663,20 @@ func newGenerateManagedConfigFromExternalV2(
 		if externalOverrideConfig.Field != "" {
 			return nil, errors.New("must not set field for a file_option override")
 		}
+		if isCustomOptionName(externalOverrideConfig.FileOption) {
+			override, err := NewManagedOverrideRuleForCustomFileOption(
+				externalOverrideConfig.Path,
+				externalOverrideConfig.Module,
+				externalOverrideConfig.FileOption,
+				externalOverrideConfig.Value,
+				overrideRuleOptions...,
+			)
+			if err != nil {
+				return nil, err
+			}
+			overrides = append(overrides, override)
+			continue
+		}
 		fileOption, err := parseFileOption(externalOverrideConfig.FileOption)
 		if err != nil {
 			return nil, err
@@ -474,6 +686,7 @@ func newGenerateManagedConfigFromExternalV2(
 			externalOverrideConfig.Module,
 			fileOption,
 			externalOverrideConfig.Value,
+			overrideRuleOptions...,
 		)
 		if err != nil {
 			return nil, err
@@ -481,7 +694,7 @@ func newGenerateManagedConfigFromExternalV2(
 		overrides = append(overrides, override)
 	}
 	return &generateManagedConfig{
-		enabled:   externalConfig.Enabled,
+		enabled:   externalConfig.Enabled != nil && *externalConfig.Enabled,
 		disables:  disables,
 		overrides: overrides,
 	}, nil
@@ -567,12 +780,16 @@ func (m *managedDisableRule) FieldOption() FieldOption {
 func (m *managedDisableRule) isManagedDisableRule() {}
 
 type managedOverrideRule struct {
-	path           string
-	moduleFullName string
-	fieldName      string
-	fileOption     FileOption
-	fieldOption    FieldOption
-	value          interface{}
+	path                string
+	moduleFullName      string
+	fieldName           string
+	fileOption          FileOption
+	fieldOption         FieldOption
+	customFileOption    string
+	customMessageOption string
+	value               interface{}
+	valueExpression     ManagedValueExpression
+	match               ManagedOverrideMatch
 }
 
 func newFileOptionManagedOverrideRule(
@@ -580,16 +797,18 @@ func newFileOptionManagedOverrideRule(
 	moduleFullName string,
 	fileOption FileOption,
 	value interface{},
+	options ...ManagedOverrideRuleOption,
 ) (*managedOverrideRule, error) {
+	managedOverrideRuleOptions := newManagedOverrideRuleOptions()
+	for _, option := range options {
+		option(managedOverrideRuleOptions)
+	}
 	// All valid file options have a parse func. This lookup implicitly validates the option.
 	parseOverrideValueFunc, ok := fileOptionToParseOverrideValueFunc[fileOption]
 	if !ok {
 		return nil, fmt.Errorf("invalid fileOption: %v", fileOption)
 	}
-	if value == nil {
-		return nil, fmt.Errorf("value must be specified for override")
-	}
-	parsedValue, err := parseOverrideValueFunc(value)
+	parsedValue, err := parseOverrideValueOrExpression(value, managedOverrideRuleOptions.valueExpression, parseOverrideValueFunc)
 	if err != nil {
 		return nil, fmt.Errorf("invalid value %v for %v: %w", value, fileOption, err)
 	}
@@ -604,10 +823,12 @@ func newFileOptionManagedOverrideRule(
 		}
 	}
 	return &managedOverrideRule{
-		path:           path,
-		moduleFullName: moduleFullName,
-		fileOption:     fileOption,
-		value:          parsedValue,
+		path:            path,
+		moduleFullName:  moduleFullName,
+		fileOption:      fileOption,
+		value:           parsedValue,
+		valueExpression: managedOverrideRuleOptions.valueExpression,
+		match:           managedOverrideRuleOptions.match,
 	}, nil
 }
 
@@ -617,16 +838,18 @@ func newFieldOptionManagedOverrideRule(
 	fieldName string,
 	fieldOption FieldOption,
 	value interface{},
+	options ...ManagedOverrideRuleOption,
 ) (ManagedOverrideRule, error) {
+	managedOverrideRuleOptions := newManagedOverrideRuleOptions()
+	for _, option := range options {
+		option(managedOverrideRuleOptions)
+	}
 	// All valid field options have a parse func. This lookup implicitly validates the option.
 	parseOverrideValueFunc, ok := fieldOptionToParseOverrideValueFunc[fieldOption]
 	if !ok {
 		return nil, fmt.Errorf("invalid fieldOption: %v", fieldOption)
 	}
-	if value == nil {
-		return nil, fmt.Errorf("value must be specified for override")
-	}
-	parsedValue, err := parseOverrideValueFunc(value)
+	parsedValue, err := parseOverrideValueOrExpression(value, managedOverrideRuleOptions.valueExpression, parseOverrideValueFunc)
 	if err != nil {
 		return nil, fmt.Errorf("invalid value %v for %v: %w", value, fieldOption, err)
 	}
@@ -641,14 +864,66 @@ func newFieldOptionManagedOverrideRule(
 		}
 	}
 	return &managedOverrideRule{
-		path:           path,
-		moduleFullName: moduleFullName,
-		fieldName:      fieldName,
-		fieldOption:    fieldOption,
-		value:          parsedValue,
+		path:            path,
+		moduleFullName:  moduleFullName,
+		fieldName:       fieldName,
+		fieldOption:     fieldOption,
+		value:           parsedValue,
+		valueExpression: managedOverrideRuleOptions.valueExpression,
+		match:           managedOverrideRuleOptions.match,
 	}, nil
 }
 
+func newCustomOptionManagedOverrideRule(
+	path string,
+	moduleFullName string,
+	optionName string,
+	isMessageOption bool,
+	value interface{},
+	options ...ManagedOverrideRuleOption,
+) (*managedOverrideRule, error) {
+	managedOverrideRuleOptions := newManagedOverrideRuleOptions()
+	for _, option := range options {
+		option(managedOverrideRuleOptions)
+	}
+	optionFullName := strings.TrimSuffix(strings.TrimPrefix(optionName, "("), ")")
+	if !protoreflect.FullName(optionFullName).IsValid() {
+		return nil, fmt.Errorf("invalid custom option name %q", optionName)
+	}
+	if _, err := parseOverrideValueOrExpression(
+		value,
+		managedOverrideRuleOptions.valueExpression,
+		func(value interface{}) (interface{}, error) {
+			return value, validateCustomOptionValue(value)
+		},
+	); err != nil {
+		return nil, fmt.Errorf("invalid value %v for (%s): %w", value, optionFullName, err)
+	}
+	if moduleFullName != "" {
+		if _, err := bufparse.ParseFullName(moduleFullName); err != nil {
+			return nil, fmt.Errorf("invalid module name for (%s) override: %w", optionFullName, err)
+		}
+	}
+	if path != "" {
+		if err := validatePath(path); err != nil {
+			return nil, fmt.Errorf("invalid path for (%s) override: %w", optionFullName, err)
+		}
+	}
+	managedOverrideRule := &managedOverrideRule{
+		path:            path,
+		moduleFullName:  moduleFullName,
+		value:           value,
+		valueExpression: managedOverrideRuleOptions.valueExpression,
+		match:           managedOverrideRuleOptions.match,
+	}
+	if isMessageOption {
+		managedOverrideRule.customMessageOption = optionFullName
+	} else {
+		managedOverrideRule.customFileOption = optionFullName
+	}
+	return managedOverrideRule, nil
+}
+
 func (m *managedOverrideRule) Path() string {
 	return m.path
 }
@@ -669,12 +944,148 @@ func (m *managedOverrideRule) FieldOption() FieldOption {
 	return m.fieldOption
 }
 
+func (m *managedOverrideRule) CustomFileOption() string {
+	return m.customFileOption
+}
+
+func (m *managedOverrideRule) CustomMessageOption() string {
+	return m.customMessageOption
+}
+
 func (m *managedOverrideRule) Value() interface{} {
 	return m.value
 }
 
+func (m *managedOverrideRule) ValueExpression() ManagedValueExpression {
+	return m.valueExpression
+}
+
+func (m *managedOverrideRule) Match() ManagedOverrideMatch {
+	return m.match
+}
+
 func (m *managedOverrideRule) isManagedOverrideRule() {}
 
+type managedOverrideRuleOptions struct {
+	valueExpression ManagedValueExpression
+	match           ManagedOverrideMatch
+}
+
+func newManagedOverrideRuleOptions() *managedOverrideRuleOptions {
+	return &managedOverrideRuleOptions{}
+}
+
+type managedOverrideMatch struct {
+	pathRegex          string
+	fileOptionMatch    ManagedOptionMatch
+	messageOptionMatch ManagedOptionMatch
+	fieldOptionMatch   ManagedOptionMatch
+}
+
+func newManagedOverrideMatch(
+	pathRegex string,
+	fileOptionMatch ManagedOptionMatch,
+	messageOptionMatch ManagedOptionMatch,
+	fieldOptionMatch ManagedOptionMatch,
+) (*managedOverrideMatch, error) {
+	if pathRegex == "" && fileOptionMatch == nil && messageOptionMatch == nil && fieldOptionMatch == nil {
+		return nil, errors.New("empty match is not allowed for an override")
+	}
+	if pathRegex != "" {
+		if _, err := regexp.Compile(pathRegex); err != nil {
+			return nil, fmt.Errorf("invalid path_regex %q for override match: %w", pathRegex, err)
+		}
+	}
+	return &managedOverrideMatch{
+		pathRegex:          pathRegex,
+		fileOptionMatch:    fileOptionMatch,
+		messageOptionMatch: messageOptionMatch,
+		fieldOptionMatch:   fieldOptionMatch,
+	}, nil
+}
+
+func newManagedOverrideMatchFromExternalV2(
+	externalConfig externalManagedOverrideMatchConfigV2,
+) (*managedOverrideMatch, error) {
+	var fileOptionMatch, messageOptionMatch, fieldOptionMatch ManagedOptionMatch
+	var err error
+	if externalConfig.FileOption != nil {
+		fileOptionMatch, err = newManagedOptionMatch(externalConfig.FileOption.Name, externalConfig.FileOption.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file_option for override match: %w", err)
+		}
+	}
+	if externalConfig.MessageOption != nil {
+		messageOptionMatch, err = newManagedOptionMatch(externalConfig.MessageOption.Name, externalConfig.MessageOption.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message_option for override match: %w", err)
+		}
+	}
+	if externalConfig.FieldOption != nil {
+		fieldOptionMatch, err = newManagedOptionMatch(externalConfig.FieldOption.Name, externalConfig.FieldOption.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field_option for override match: %w", err)
+		}
+	}
+	return newManagedOverrideMatch(
+		externalConfig.PathRegex,
+		fileOptionMatch,
+		messageOptionMatch,
+		fieldOptionMatch,
+	)
+}
+
+func (m *managedOverrideMatch) PathRegex() string {
+	return m.pathRegex
+}
+
+func (m *managedOverrideMatch) FileOptionMatch() ManagedOptionMatch {
+	return m.fileOptionMatch
+}
+
+func (m *managedOverrideMatch) MessageOptionMatch() ManagedOptionMatch {
+	return m.messageOptionMatch
+}
+
+func (m *managedOverrideMatch) FieldOptionMatch() ManagedOptionMatch {
+	return m.fieldOptionMatch
+}
+
+func (m *managedOverrideMatch) isManagedOverrideMatch() {}
+
+type managedOptionMatch struct {
+	name  string
+	value interface{}
+}
+
+func newManagedOptionMatch(name string, value interface{}) (*managedOptionMatch, error) {
+	if name == "" {
+		return nil, errors.New("option name must be specified")
+	}
+	if strings.HasPrefix(name, "(") != strings.HasSuffix(name, ")") || name == "()" || strings.ContainsAny(name, " \t") {
+		return nil, fmt.Errorf("invalid option name %q", name)
+	}
+	switch value.(type) {
+	case nil, string, bool, int, int64, uint64, float64:
+	default:
+		return nil, fmt.Errorf("value for option %q must be a string, bool, or number, got %T", name, value)
+	}
+	return &managedOptionMatch{
+		name:  name,
+		value: value,
+	}, nil
+}
+
+func (m *managedOptionMatch) Name() string {
+	return m.name
+}
+
+func (m *managedOptionMatch) Value() interface{} {
+	return m.value
+}
+
+func (m *managedOptionMatch) isManagedOptionMatch() {}
+
 func disablesAndOverridesFromExceptAndOverrideV1(
 	exceptFileOption FileOption,
 	exceptFullNames []string,
@@ -799,6 +1210,22 @@ func newExternalManagedConfigV2FromGenerateManagedConfig(
 	}
 	var externalOverrides []externalManagedOverrideConfigV2
 	for _, override := range managedConfig.Overrides() {
+		if override.CustomFileOption() != "" || override.CustomMessageOption() != "" {
+			externalOverride := externalManagedOverrideConfigV2{
+				Module:          override.FullName(),
+				Path:            override.Path(),
+				Value:           override.Value(),
+				ValueExpression: getValueExpressionString(override.ValueExpression()),
+				Match:           newExternalManagedOverrideMatchConfigV2FromManagedOverrideMatch(override.Match()),
+			}
+			if override.CustomFileOption() != "" {
+				externalOverride.FileOption = "(" + override.CustomFileOption() + ")"
+			} else {
+				externalOverride.MessageOption = "(" + override.CustomMessageOption() + ")"
+			}
+			externalOverrides = append(externalOverrides, externalOverride)
+			continue
+		}
 		var fileOptionName string
 		if override.FileOption() != FileOptionUnspecified {
 			fileOptionName = override.FileOption().String()
@@ -807,29 +1234,121 @@ func newExternalManagedConfigV2FromGenerateManagedConfig(
 		if override.FieldOption() != FieldOptionUnspecified {
 			fieldOptionName = override.FieldOption().String()
 		}
-		value, err := getOverrideValue(fileOptionName, fieldOptionName, override.Value())
-		if err != nil {
-			return externalGenerateManagedConfigV2{}, err
+		var value interface{}
+		if override.ValueExpression() == nil {
+			var err error
+			value, err = getOverrideValue(fileOptionName, fieldOptionName, override.Value())
+			if err != nil {
+				return externalGenerateManagedConfigV2{}, err
+			}
 		}
 		externalOverrides = append(
 			externalOverrides,
 			externalManagedOverrideConfigV2{
-				FileOption:  fileOptionName,
-				FieldOption: fieldOptionName,
-				Module:      override.FullName(),
-				Path:        override.Path(),
-				Field:       override.FieldName(),
-				Value:       value,
+				FileOption:      fileOptionName,
+				FieldOption:     fieldOptionName,
+				Module:          override.FullName(),
+				Path:            override.Path(),
+				Field:           override.FieldName(),
+				Value:           value,
+				ValueExpression: getValueExpressionString(override.ValueExpression()),
+				Match:           newExternalManagedOverrideMatchConfigV2FromManagedOverrideMatch(override.Match()),
 			},
 		)
 	}
 	return externalGenerateManagedConfigV2{
-		Enabled:  managedConfig.Enabled(),
+		Enabled:  getBoolPointerIfTrue(managedConfig.Enabled()),
 		Disable:  externalDisables,
 		Override: externalOverrides,
 	}, nil
 }
 
+func newExternalManagedOverrideMatchConfigV2FromManagedOverrideMatch(
+	match ManagedOverrideMatch,
+) *externalManagedOverrideMatchConfigV2 {
+	if match == nil {
+		return nil
+	}
+	return &externalManagedOverrideMatchConfigV2{
+		PathRegex:     match.PathRegex(),
+		FileOption:    newExternalManagedOptionMatchConfigV2FromManagedOptionMatch(match.FileOptionMatch()),
+		MessageOption: newExternalManagedOptionMatchConfigV2FromManagedOptionMatch(match.MessageOptionMatch()),
+		FieldOption:   newExternalManagedOptionMatchConfigV2FromManagedOptionMatch(match.FieldOptionMatch()),
+	}
+}
+
+func newExternalManagedOptionMatchConfigV2FromManagedOptionMatch(
+	optionMatch ManagedOptionMatch,
+) *externalManagedOptionMatchConfigV2 {
+	if optionMatch == nil {
+		return nil
+	}
+	return &externalManagedOptionMatchConfigV2{
+		Name:  optionMatch.Name(),
+		Value: optionMatch.Value(),
+	}
+}
+
+func getValueExpressionString(valueExpression ManagedValueExpression) string {
+	if valueExpression == nil {
+		return ""
+	}
+	return valueExpression.Expression()
+}
+
+// parseOverrideValueOrExpression parses the value of an override with parseOverrideValueFunc.
+//
+// Exactly one of value and valueExpression must be set. If valueExpression is set, the
+// value is computed for each file, so nil is returned, and the computed values are
+// parsed when they are computed.
+func parseOverrideValueOrExpression(
+	value interface{},
+	valueExpression ManagedValueExpression,
+	parseOverrideValueFunc func(interface{}) (interface{}, error),
+) (interface{}, error) {
+	if valueExpression != nil {
+		if value != nil {
+			return nil, errors.New("value must not be specified for override with a value expression")
+		}
+		return nil, nil
+	}
+	if value == nil {
+		return nil, errors.New("value must be specified for override")
+	}
+	return parseOverrideValueFunc(value)
+}
+
+// isCustomOptionName returns true if the option name refers to a custom option,
+// that is a fully-qualified extension name in parentheses.
+func isCustomOptionName(optionName string) bool {
+	return strings.HasPrefix(optionName, "(") && strings.HasSuffix(optionName, ")")
+}
+
+// validateCustomOptionValue validates that the value can be represented in JSON, so
+// that it can later be interpreted according to the type of the custom option.
+func validateCustomOptionValue(value interface{}) error {
+	switch value := value.(type) {
+	case string, bool, int, int64, uint64, float64:
+		return nil
+	case []interface{}:
+		for _, element := range value {
+			if err := validateCustomOptionValue(element); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		for _, fieldValue := range value {
+			if err := validateCustomOptionValue(fieldValue); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
 func validatePath(path string) error {
 	normalizedPath, err := normalpath.NormalizeAndValidate(path)
 	if err != nil {
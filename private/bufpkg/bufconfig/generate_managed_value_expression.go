@@ -0,0 +1,112 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconfig
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+const (
+	managedValueExpressionFileVariableName = "file"
+	managedValueExpressionPathKey          = "path"
+	managedValueExpressionPackageKey       = "package"
+	managedValueExpressionModuleKey        = "module"
+)
+
+// ManagedValueExpression is a CEL expression that computes the value of an override
+// for a file.
+//
+// The expression has access to the variable "file", with the keys "path", the file
+// path relative to its module, "package", the package of the file, and "module", the
+// full name of the module of the file. Keys that do not apply to a file are empty
+// strings. The extended string functions, such as replace and split, are available.
+//
+// The expression must evaluate to a string or a bool.
+type ManagedValueExpression interface {
+	// Expression returns the CEL expression.
+	Expression() string
+	// Eval evaluates the expression for the file with the given path relative to its
+	// module, package, and module full name, and returns a string or a bool.
+	Eval(path string, packageName string, moduleFullName string) (interface{}, error)
+
+	isManagedValueExpression()
+}
+
+// NewManagedValueExpression returns a new ManagedValueExpression.
+//
+// The expression is compiled and type-checked.
+func NewManagedValueExpression(expression string) (ManagedValueExpression, error) {
+	return newManagedValueExpression(expression)
+}
+
+// *** PRIVATE ***
+
+type managedValueExpression struct {
+	expression string
+	program    cel.Program
+}
+
+func newManagedValueExpression(expression string) (*managedValueExpression, error) {
+	if expression == "" {
+		return nil, fmt.Errorf("value_expression must not be empty")
+	}
+	env, err := cel.NewEnv(
+		ext.Strings(),
+		cel.Variable(managedValueExpressionFileVariableName, cel.MapType(cel.StringType, cel.StringType)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if err := issues.Err(); err != nil {
+		return nil, fmt.Errorf("invalid value_expression %q: %w", expression, err)
+	}
+	if outputType := ast.OutputType(); !outputType.IsExactType(cel.StringType) && !outputType.IsExactType(cel.BoolType) {
+		return nil, fmt.Errorf("invalid value_expression %q: must evaluate to a string or a bool, but evaluates to %s", expression, outputType)
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value_expression %q: %w", expression, err)
+	}
+	return &managedValueExpression{
+		expression: expression,
+		program:    program,
+	}, nil
+}
+
+func (m *managedValueExpression) Expression() string {
+	return m.expression
+}
+
+func (m *managedValueExpression) Eval(path string, packageName string, moduleFullName string) (interface{}, error) {
+	value, _, err := m.program.Eval(
+		map[string]interface{}{
+			managedValueExpressionFileVariableName: map[string]string{
+				managedValueExpressionPathKey:    path,
+				managedValueExpressionPackageKey: packageName,
+				managedValueExpressionModuleKey:  moduleFullName,
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate value_expression %q for %s: %w", m.expression, path, err)
+	}
+	return value.Value(), nil
+}
+
+func (m *managedValueExpression) isManagedValueExpression() {}
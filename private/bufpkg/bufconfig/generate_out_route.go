@@ -0,0 +1,96 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GenerateOutRoute routes a subset of the files generated by a plugin to an
+// output location other than the out of the plugin.
+//
+// A file matches a route if it matches all of the conditions that are set on the route.
+// Files are routed by the first route that they match, and files that do not match
+// any route are written to the out of the plugin.
+type GenerateOutRoute interface {
+	// Out returns the output location of the files that match the route. This is
+	// never empty.
+	Out() string
+	// Suffix returns the suffix of the generated file names that match the route, for
+	// example "_grpc.pb.go".
+	//
+	// If empty, generated files are not matched by suffix.
+	Suffix() string
+	// Package returns the package of the .proto files whose generated files match
+	// the route.
+	//
+	// This is either a package name, or a package name followed by ".*", which
+	// matches the package and all packages below it.
+	//
+	// If empty, generated files are not matched by package.
+	Package() string
+
+	isGenerateOutRoute()
+}
+
+// NewGenerateOutRoute returns a new GenerateOutRoute.
+//
+// At least one of suffix and pkg must be set.
+func NewGenerateOutRoute(out string, suffix string, pkg string) (GenerateOutRoute, error) {
+	return newGenerateOutRoute(out, suffix, pkg)
+}
+
+// *** PRIVATE ***
+
+type generateOutRoute struct {
+	out    string
+	suffix string
+	pkg    string
+}
+
+func newGenerateOutRoute(out string, suffix string, pkg string) (*generateOutRoute, error) {
+	if out == "" {
+		return nil, errors.New("must specify out for a route")
+	}
+	if suffix == "" && pkg == "" {
+		return nil, fmt.Errorf("must specify suffix or package for route to %s", out)
+	}
+	if pkg != "" && !protoreflect.FullName(strings.TrimSuffix(pkg, ".*")).IsValid() {
+		return nil, fmt.Errorf("invalid package %q for route to %s", pkg, out)
+	}
+	return &generateOutRoute{
+		out:    out,
+		suffix: suffix,
+		pkg:    pkg,
+	}, nil
+}
+
+func (r *generateOutRoute) Out() string {
+	return r.out
+}
+
+func (r *generateOutRoute) Suffix() string {
+	return r.suffix
+}
+
+func (r *generateOutRoute) Package() string {
+	return r.pkg
+}
+
+func (r *generateOutRoute) isGenerateOutRoute() {}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"math"
 	"os/exec"
+	"slices"
 	"strings"
 
 	"github.com/bufbuild/buf/private/bufpkg/bufremoteplugin/bufremotepluginref"
@@ -38,6 +39,9 @@ const (
 	//
 	// This is the only strategy for remote plugins.
 	GenerateStrategyAll GenerateStrategy = 2
+	// GenerateStrategyAllWithImports is the strategy to generate with all files at
+	// once, including all imports and the well-known types.
+	GenerateStrategyAllWithImports GenerateStrategy = 3
 )
 
 // GeneratePluginConfigType is a generate plugin configuration type.
@@ -86,11 +90,11 @@ type GeneratePluginConfig interface {
 	// Opt returns the plugin options as a comma separated string.
 	Opt() string
 	// IncludeImports returns whether to generate code for imported files. This
-	// is always false in v1.
+	// is always false in v1beta1.
 	IncludeImports() bool
 	// IncludeWKT returns whether to generate code for the well-known types.
 	// This returns true only if IncludeImports returns true. This is always
-	// false in v1.
+	// false in v1beta1.
 	IncludeWKT() bool
 	// Strategy returns the generation strategy.
 	//
@@ -112,6 +116,44 @@ type GeneratePluginConfig interface {
 	//
 	// This is not empty only when the plugin is remote.
 	Revision() int
+	// PostCommands returns the commands to run in the output directory after
+	// generation is complete, in order. Each command is the program followed
+	// by its arguments.
+	//
+	// This is always empty in v1beta1 and v1.
+	PostCommands() [][]string
+	// Types returns the fully-qualified names of the types, or packages, to
+	// restrict generation to for this plugin. If empty, all types are included.
+	//
+	// This is always empty in v1beta1 and v1.
+	Types() []string
+	// ExcludeTypes returns the fully-qualified names of the types, or packages,
+	// to exclude from generation for this plugin. Excluded types are still
+	// included when they are required by another included type.
+	//
+	// This is always empty in v1beta1 and v1.
+	ExcludeTypes() []string
+	// Clean returns whether to delete the files generated by the previous
+	// generation of this plugin in its output directory before generating.
+	//
+	// The generated files are tracked in a manifest file in the output directory,
+	// so files that were not generated by the plugin are never deleted.
+	//
+	// This is always false in v1beta1 and v1.
+	Clean() bool
+	// Routes returns the routes of generated files to output locations other
+	// than Out, in order. Each generated file is routed by the first route that
+	// it matches, and is written to Out if it matches no route.
+	//
+	// This is always empty in v1beta1 and v1.
+	Routes() []GenerateOutRoute
+	// LocalFallback returns the local plugin to run instead of a remote plugin if
+	// the remote is unavailable, or nil if there is no fallback.
+	//
+	// The fallback has the same out, options, and other settings as the remote plugin.
+	//
+	// This is always nil for local and protoc built-in plugins, and in v1beta1 and v1.
+	LocalFallback() GeneratePluginConfig
 
 	isGeneratePluginConfig()
 }
@@ -132,6 +174,12 @@ func NewRemoteGeneratePluginConfig(
 		includeImports,
 		includeWKT,
 		revision,
+		nil,
+		nil,
+		nil,
+		false,
+		nil,
+		nil,
 	)
 }
 
@@ -172,6 +220,11 @@ func NewLocalGeneratePluginConfig(
 		includeWKT,
 		strategy,
 		path,
+		nil,
+		nil,
+		nil,
+		false,
+		nil,
 	)
 }
 
@@ -194,6 +247,11 @@ func NewProtocBuiltinGeneratePluginConfig(
 		includeWKT,
 		strategy,
 		protocPath,
+		nil,
+		nil,
+		nil,
+		false,
+		nil,
 	)
 }
 
@@ -218,6 +276,38 @@ func NewGeneratePluginConfigWithIncludeImportsAndWKT(
 	return &generatePluginConfig, nil
 }
 
+// NewGeneratePluginConfigWithNameAndRevision returns a GeneratePluginConfig the same
+// as the input remote plugin, with the name and revision overridden.
+//
+// This is used to pin remote plugins to a specific version and revision.
+func NewGeneratePluginConfigWithNameAndRevision(
+	config GeneratePluginConfig,
+	name string,
+	revision int,
+) (GeneratePluginConfig, error) {
+	originalConfig, ok := config.(*generatePluginConfig)
+	if !ok {
+		return nil, syserror.Newf("unknown implementation of GeneratePluginConfig: %T", config)
+	}
+	if originalConfig.generatePluginConfigType != GeneratePluginConfigTypeRemote {
+		return nil, syserror.Newf("cannot override the name and revision of non-remote plugin %q", originalConfig.name)
+	}
+	remoteHost, err := parseRemoteHostName(name)
+	if err != nil {
+		return nil, err
+	}
+	if remoteHost != originalConfig.remoteHost {
+		return nil, fmt.Errorf("cannot change the remote of plugin %q to %q", originalConfig.name, name)
+	}
+	if revision < 0 || revision > math.MaxInt32 {
+		return nil, fmt.Errorf("revision %d is out of accepted range %d-%d", revision, 0, math.MaxInt32)
+	}
+	generatePluginConfig := *originalConfig
+	generatePluginConfig.name = name
+	generatePluginConfig.revision = revision
+	return &generatePluginConfig, nil
+}
+
 // *** PRIVATE ***
 
 type generatePluginConfig struct {
@@ -232,6 +322,13 @@ type generatePluginConfig struct {
 	protocPath               []string
 	remoteHost               string
 	revision                 int
+	postCommands             [][]string
+	types                    []string
+	excludeTypes             []string
+	clean                    bool
+	routes                   []GenerateOutRoute
+	// localFallback is nil if there is no fallback.
+	localFallback *generatePluginConfig
 }
 
 func newGeneratePluginConfigFromExternalV1Beta1(
@@ -260,6 +357,11 @@ func newGeneratePluginConfigFromExternalV1Beta1(
 			false,
 			strategy,
 			[]string{externalConfig.Path},
+			nil,
+			nil,
+			nil,
+			false,
+			nil,
 		)
 	}
 	return newLocalOrProtocBuiltinGeneratePluginConfig(
@@ -325,9 +427,15 @@ func newGeneratePluginConfigFromExternalV1(
 			externalConfig.Plugin,
 			externalConfig.Out,
 			opt,
-			false,
-			false,
+			externalConfig.IncludeImports,
+			externalConfig.IncludeWKT,
 			externalConfig.Revision,
+			nil,
+			nil,
+			nil,
+			false,
+			nil,
+			nil,
 		)
 	}
 	// At this point the plugin must be local, regardless whether it's specified
@@ -337,10 +445,15 @@ func newGeneratePluginConfigFromExternalV1(
 			pluginIdentifier,
 			externalConfig.Out,
 			opt,
-			false,
-			false,
+			externalConfig.IncludeImports,
+			externalConfig.IncludeWKT,
 			strategy,
 			path,
+			nil,
+			nil,
+			nil,
+			false,
+			nil,
 		)
 	}
 	if externalConfig.ProtocPath != nil {
@@ -348,10 +461,15 @@ func newGeneratePluginConfigFromExternalV1(
 			pluginIdentifier,
 			externalConfig.Out,
 			opt,
-			false,
-			false,
+			externalConfig.IncludeImports,
+			externalConfig.IncludeWKT,
 			strategy,
 			protocPath,
+			nil,
+			nil,
+			nil,
+			false,
+			nil,
 		)
 	}
 	// It could be either local or protoc built-in. We defer to the plugin executor
@@ -360,8 +478,8 @@ func newGeneratePluginConfigFromExternalV1(
 		pluginIdentifier,
 		externalConfig.Out,
 		opt,
-		false,
-		false,
+		externalConfig.IncludeImports,
+		externalConfig.IncludeWKT,
 		strategy,
 	)
 }
@@ -400,8 +518,30 @@ func newGeneratePluginConfigFromExternalV2(
 	if err != nil {
 		return nil, err
 	}
+	postCommands, err := parsePostCommands(externalConfig.Post)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(externalConfig.Types, "") {
+		return nil, errors.New("types must not be empty")
+	}
+	if slices.Contains(externalConfig.ExcludeTypes, "") {
+		return nil, errors.New("exclude_types must not be empty")
+	}
+	routes := make([]GenerateOutRoute, 0, len(externalConfig.Routes))
+	for _, externalRoute := range externalConfig.Routes {
+		route, err := newGenerateOutRoute(externalRoute.Out, externalRoute.Suffix, externalRoute.Package)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, route)
+	}
 	switch {
 	case externalConfig.Remote != nil:
+		localFallbackPath, err := encoding.InterfaceSliceOrStringToStringSlice(externalConfig.LocalFallback)
+		if err != nil {
+			return nil, err
+		}
 		var revision int
 		if externalConfig.Revision != nil {
 			revision = *externalConfig.Revision
@@ -419,6 +559,12 @@ func newGeneratePluginConfigFromExternalV2(
 			externalConfig.IncludeImports,
 			externalConfig.IncludeWKT,
 			revision,
+			postCommands,
+			externalConfig.Types,
+			externalConfig.ExcludeTypes,
+			externalConfig.Clean,
+			routes,
+			localFallbackPath,
 		)
 	case externalConfig.Local != nil:
 		path, err := encoding.InterfaceSliceOrStringToStringSlice(externalConfig.Local)
@@ -432,6 +578,9 @@ func newGeneratePluginConfigFromExternalV2(
 		if externalConfig.ProtocPath != nil {
 			return nil, fmt.Errorf("cannot specify protoc_path for local plugin %s", localPluginName)
 		}
+		if externalConfig.LocalFallback != nil {
+			return nil, fmt.Errorf("cannot specify local_fallback for local plugin %s", localPluginName)
+		}
 		return newLocalGeneratePluginConfig(
 			strings.Join(path, " "),
 			externalConfig.Out,
@@ -440,6 +589,11 @@ func newGeneratePluginConfigFromExternalV2(
 			externalConfig.IncludeWKT,
 			parsedStrategy,
 			path,
+			postCommands,
+			externalConfig.Types,
+			externalConfig.ExcludeTypes,
+			externalConfig.Clean,
+			routes,
 		)
 	case externalConfig.ProtocBuiltin != nil:
 		protocPath, err := encoding.InterfaceSliceOrStringToStringSlice(externalConfig.ProtocPath)
@@ -449,6 +603,9 @@ func newGeneratePluginConfigFromExternalV2(
 		if externalConfig.Revision != nil {
 			return nil, fmt.Errorf("cannot specify revision for protoc built-in plugin %s", *externalConfig.ProtocBuiltin)
 		}
+		if externalConfig.LocalFallback != nil {
+			return nil, fmt.Errorf("cannot specify local_fallback for protoc built-in plugin %s", *externalConfig.ProtocBuiltin)
+		}
 		return newProtocBuiltinGeneratePluginConfig(
 			*externalConfig.ProtocBuiltin,
 			externalConfig.Out,
@@ -457,6 +614,11 @@ func newGeneratePluginConfigFromExternalV2(
 			externalConfig.IncludeWKT,
 			parsedStrategy,
 			protocPath,
+			postCommands,
+			externalConfig.Types,
+			externalConfig.ExcludeTypes,
+			externalConfig.Clean,
+			routes,
 		)
 	default:
 		return nil, syserror.Newf("must specify one of remote, binary and protoc_builtin")
@@ -470,6 +632,12 @@ func newRemoteGeneratePluginConfig(
 	includeImports bool,
 	includeWKT bool,
 	revision int,
+	postCommands [][]string,
+	types []string,
+	excludeTypes []string,
+	clean bool,
+	routes []GenerateOutRoute,
+	localFallbackPath []string,
 ) (*generatePluginConfig, error) {
 	if includeWKT && !includeImports {
 		return nil, errors.New("cannot include well-known types without including imports")
@@ -481,6 +649,26 @@ func newRemoteGeneratePluginConfig(
 	if revision < 0 || revision > math.MaxInt32 {
 		return nil, fmt.Errorf("revision %d is out of accepted range %d-%d", revision, 0, math.MaxInt32)
 	}
+	var localFallback *generatePluginConfig
+	if len(localFallbackPath) > 0 {
+		localFallback, err = newLocalGeneratePluginConfig(
+			strings.Join(localFallbackPath, " "),
+			out,
+			opt,
+			includeImports,
+			includeWKT,
+			nil,
+			localFallbackPath,
+			postCommands,
+			types,
+			excludeTypes,
+			clean,
+			routes,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &generatePluginConfig{
 		generatePluginConfigType: GeneratePluginConfigTypeRemote,
 		name:                     name,
@@ -490,6 +678,12 @@ func newRemoteGeneratePluginConfig(
 		opts:                     opt,
 		includeImports:           includeImports,
 		includeWKT:               includeWKT,
+		postCommands:             postCommands,
+		types:                    types,
+		excludeTypes:             excludeTypes,
+		clean:                    clean,
+		routes:                   routes,
+		localFallback:            localFallback,
 	}, nil
 }
 
@@ -523,6 +717,11 @@ func newLocalGeneratePluginConfig(
 	includeWKT bool,
 	strategy *GenerateStrategy,
 	path []string,
+	postCommands [][]string,
+	types []string,
+	excludeTypes []string,
+	clean bool,
+	routes []GenerateOutRoute,
 ) (*generatePluginConfig, error) {
 	if len(path) == 0 {
 		return nil, errors.New("must specify a path to the plugin")
@@ -539,6 +738,11 @@ func newLocalGeneratePluginConfig(
 		opts:                     opt,
 		includeImports:           includeImports,
 		includeWKT:               includeWKT,
+		postCommands:             postCommands,
+		types:                    types,
+		excludeTypes:             excludeTypes,
+		clean:                    clean,
+		routes:                   routes,
 	}, nil
 }
 
@@ -550,6 +754,11 @@ func newProtocBuiltinGeneratePluginConfig(
 	includeWKT bool,
 	strategy *GenerateStrategy,
 	protocPath []string,
+	postCommands [][]string,
+	types []string,
+	excludeTypes []string,
+	clean bool,
+	routes []GenerateOutRoute,
 ) (*generatePluginConfig, error) {
 	if includeWKT && !includeImports {
 		return nil, errors.New("cannot include well-known types without including imports")
@@ -563,6 +772,11 @@ func newProtocBuiltinGeneratePluginConfig(
 		strategy:                 strategy,
 		includeImports:           includeImports,
 		includeWKT:               includeWKT,
+		postCommands:             postCommands,
+		types:                    types,
+		excludeTypes:             excludeTypes,
+		clean:                    clean,
+		routes:                   routes,
 	}, nil
 }
 
@@ -613,6 +827,34 @@ func (p *generatePluginConfig) Revision() int {
 	return p.revision
 }
 
+func (p *generatePluginConfig) PostCommands() [][]string {
+	return p.postCommands
+}
+
+func (p *generatePluginConfig) Types() []string {
+	return p.types
+}
+
+func (p *generatePluginConfig) ExcludeTypes() []string {
+	return p.excludeTypes
+}
+
+func (p *generatePluginConfig) Clean() bool {
+	return p.clean
+}
+
+func (p *generatePluginConfig) Routes() []GenerateOutRoute {
+	return p.routes
+}
+
+func (p *generatePluginConfig) LocalFallback() GeneratePluginConfig {
+	// Avoid returning a typed nil.
+	if p.localFallback == nil {
+		return nil
+	}
+	return p.localFallback
+}
+
 func (p *generatePluginConfig) isGeneratePluginConfig() {}
 
 func newExternalGeneratePluginConfigV2FromPluginConfig(
@@ -634,12 +876,30 @@ func newExternalGeneratePluginConfigV2FromPluginConfig(
 	case len(opts) > 1:
 		externalPluginConfigV2.Opt = opts
 	}
+	for _, postCommand := range generatePluginConfig.postCommands {
+		externalPluginConfigV2.Post = append(externalPluginConfigV2.Post, postCommand)
+	}
+	externalPluginConfigV2.Types = generatePluginConfig.types
+	externalPluginConfigV2.ExcludeTypes = generatePluginConfig.excludeTypes
+	externalPluginConfigV2.Clean = generatePluginConfig.clean
+	for _, route := range generatePluginConfig.routes {
+		externalPluginConfigV2.Routes = append(
+			externalPluginConfigV2.Routes,
+			externalGenerateOutRouteV2{
+				Out:     route.Out(),
+				Suffix:  route.Suffix(),
+				Package: route.Package(),
+			},
+		)
+	}
 	strategy := generatePluginConfig.strategy
 	switch {
 	case strategy != nil && *strategy == GenerateStrategyDirectory:
 		externalPluginConfigV2.Strategy = toPointer("directory")
 	case strategy != nil && *strategy == GenerateStrategyAll:
 		externalPluginConfigV2.Strategy = toPointer("all")
+	case strategy != nil && *strategy == GenerateStrategyAllWithImports:
+		externalPluginConfigV2.Strategy = toPointer("all_with_imports")
 	}
 	switch generatePluginConfig.Type() {
 	case GeneratePluginConfigTypeRemote:
@@ -647,6 +907,14 @@ func newExternalGeneratePluginConfigV2FromPluginConfig(
 		if revision := generatePluginConfig.Revision(); revision != 0 {
 			externalPluginConfigV2.Revision = &revision
 		}
+		if localFallback := generatePluginConfig.localFallback; localFallback != nil {
+			localFallbackPath := localFallback.Path()
+			if len(localFallbackPath) == 1 {
+				externalPluginConfigV2.LocalFallback = localFallbackPath[0]
+			} else {
+				externalPluginConfigV2.LocalFallback = localFallbackPath
+			}
+		}
 	case GeneratePluginConfigTypeLocal:
 		path := generatePluginConfig.Path()
 		switch {
@@ -683,6 +951,32 @@ func newExternalGeneratePluginConfigV2FromPluginConfig(
 	return externalPluginConfigV2, nil
 }
 
+// parsePostCommands parses the post commands of a plugin. Each command can be
+// either one string, which is split on whitespace, or multiple strings.
+func parsePostCommands(externalPostCommands []any) ([][]string, error) {
+	postCommands := make([][]string, 0, len(externalPostCommands))
+	for _, externalPostCommand := range externalPostCommands {
+		var postCommand []string
+		if value, ok := externalPostCommand.(string); ok {
+			postCommand = strings.Fields(value)
+		} else {
+			var err error
+			postCommand, err = encoding.InterfaceSliceOrStringToStringSlice(externalPostCommand)
+			if err != nil {
+				return nil, fmt.Errorf("invalid post command: %w", err)
+			}
+		}
+		if len(postCommand) == 0 {
+			return nil, errors.New("post commands must not be empty")
+		}
+		postCommands = append(postCommands, postCommand)
+	}
+	if len(postCommands) == 0 {
+		return nil, nil
+	}
+	return postCommands, nil
+}
+
 func parseStrategy(s string) (*GenerateStrategy, error) {
 	var strategy GenerateStrategy
 	switch s {
@@ -692,6 +986,8 @@ func parseStrategy(s string) (*GenerateStrategy, error) {
 		strategy = GenerateStrategyDirectory
 	case "all":
 		strategy = GenerateStrategyAll
+	case "all_with_imports":
+		strategy = GenerateStrategyAllWithImports
 	default:
 		return nil, fmt.Errorf("unknown strategy: %s", s)
 	}
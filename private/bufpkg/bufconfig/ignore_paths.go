@@ -0,0 +1,88 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconfig
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+// IsIgnorePathPattern returns true if the ignore path is a glob pattern, that is if it
+// contains any of "*", "?", or "[".
+//
+// Within a pattern, "**" matches zero or more directories, and all other components
+// are matched as with path.Match. A pattern without a "/" matches a file or directory
+// with a matching name at any depth, for example "*_internal.proto" or "vendor".
+func IsIgnorePathPattern(ignorePath string) bool {
+	return strings.ContainsAny(ignorePath, "*?[")
+}
+
+// IgnorePathPatternMatches returns true if the path, or any of its parent directories,
+// matches the ignore path pattern.
+//
+// This mirrors how ignore paths that are not patterns ignore the files they contain.
+// Both the pattern and the path are expected to be normalized.
+func IgnorePathPatternMatches(pattern string, filePath string) bool {
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	patternComponents := strings.Split(pattern, "/")
+	pathComponents := strings.Split(filePath, "/")
+	for i := 1; i <= len(pathComponents); i++ {
+		if matchIgnorePathPatternComponents(patternComponents, pathComponents[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// *** PRIVATE ***
+
+func validateIgnorePathPattern(pattern string) error {
+	for _, component := range strings.Split(pattern, "/") {
+		if component == "**" {
+			continue
+		}
+		if strings.Contains(component, "**") {
+			return errors.New(`"**" must be an entire path component`)
+		}
+		if _, err := path.Match(component, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matchIgnorePathPatternComponents(patternComponents []string, pathComponents []string) bool {
+	if len(patternComponents) == 0 {
+		return len(pathComponents) == 0
+	}
+	if patternComponents[0] == "**" {
+		for i := 0; i <= len(pathComponents); i++ {
+			if matchIgnorePathPatternComponents(patternComponents[1:], pathComponents[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(pathComponents) == 0 {
+		return false
+	}
+	if matched, err := path.Match(patternComponents[0], pathComponents[0]); err != nil || !matched {
+		return false
+	}
+	return matchIgnorePathPatternComponents(patternComponents[1:], pathComponents[1:])
+}
@@ -0,0 +1,51 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnorePathPatternMatches(t *testing.T) {
+	t.Parallel()
+	for _, testCase := range []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{pattern: "**/vendor/**", path: "vendor/a.proto", expected: true},
+		{pattern: "**/vendor/**", path: "a/b/vendor/c/d.proto", expected: true},
+		{pattern: "**/vendor/**", path: "a/vendored/b.proto", expected: false},
+		{pattern: "vendor", path: "a/vendor/b.proto", expected: true},
+		{pattern: "*_internal.proto", path: "a/b/foo_internal.proto", expected: true},
+		{pattern: "*_internal.proto", path: "a/b/foo.proto", expected: false},
+		{pattern: "acme/*/v1", path: "acme/foo/v1/foo.proto", expected: true},
+		{pattern: "acme/*/v1", path: "acme/foo/bar/v1/foo.proto", expected: false},
+		{pattern: "acme/**/v1", path: "acme/foo/bar/v1/foo.proto", expected: true},
+		{pattern: "acme/v?", path: "acme/v2/foo.proto", expected: true},
+		{pattern: "acme/v[13]", path: "acme/v2/foo.proto", expected: false},
+	} {
+		assert.Equal(
+			t,
+			testCase.expected,
+			IgnorePathPatternMatches(testCase.pattern, testCase.path),
+			"%s %s",
+			testCase.pattern,
+			testCase.path,
+		)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/bufbuild/buf/private/pkg/normalpath"
 	"github.com/bufbuild/buf/private/pkg/slicesext"
 	"github.com/bufbuild/buf/private/pkg/stringutil"
 	"github.com/bufbuild/buf/private/pkg/syserror"
@@ -162,6 +163,17 @@ type InputConfig interface {
 	ExcludePaths() []string
 	// IncludeTypes returns the types to generate. An empty slice means to generate for all types.
 	IncludeTypes() []string
+	// PluginNames returns the names of the plugins to generate with for this input.
+	// An empty slice means to generate with all plugins.
+	//
+	// This is always empty unless read from a v2 buf.gen.yaml file.
+	PluginNames() []string
+	// OutPrefix returns the normalized relative path that the outs of the plugins
+	// are prefixed with when generating for this input. Empty means the outs of
+	// the plugins are not prefixed.
+	//
+	// This is always empty unless read from a v2 buf.gen.yaml file.
+	OutPrefix() string
 
 	isInputConfig()
 }
@@ -204,6 +216,26 @@ func NewModuleInputConfig(
 	}, nil
 }
 
+// NewInputConfigWithLocation returns an InputConfig the same as the input, with the
+// location overridden.
+//
+// This is used to pin module inputs to a specific commit.
+func NewInputConfigWithLocation(
+	config InputConfig,
+	location string,
+) (InputConfig, error) {
+	originalConfig, ok := config.(*inputConfig)
+	if !ok {
+		return nil, syserror.Newf("unknown implementation of InputConfig: %T", config)
+	}
+	if location == "" {
+		return nil, fmt.Errorf("empty location for %s", originalConfig.inputConfigType.String())
+	}
+	inputConfig := *originalConfig
+	inputConfig.location = location
+	return &inputConfig, nil
+}
+
 // NewDirectoryInputConfig returns an input config for a directory.
 func NewDirectoryInputConfig(
 	location string,
@@ -345,6 +377,8 @@ type inputConfig struct {
 	includeTypes        []string
 	targetPaths         []string
 	excludePaths        []string
+	pluginNames         []string
+	outPrefix           string
 }
 
 func newInputConfigFromExternalV2(externalConfig externalInputConfigV2) (InputConfig, error) {
@@ -403,6 +437,20 @@ func newInputConfigFromExternalV2(externalConfig externalInputConfigV2) (InputCo
 	inputConfig.includeTypes = externalConfig.Types
 	inputConfig.targetPaths = externalConfig.TargetPaths
 	inputConfig.excludePaths = externalConfig.ExcludePaths
+	// Plugins and Out.
+	for _, pluginName := range externalConfig.Plugins {
+		if pluginName == "" {
+			return nil, errors.New("plugins must not be empty")
+		}
+	}
+	inputConfig.pluginNames = externalConfig.Plugins
+	if externalConfig.Out != "" {
+		outPrefix, err := normalpath.NormalizeAndValidate(externalConfig.Out)
+		if err != nil {
+			return nil, fmt.Errorf("invalid out %q: %w", externalConfig.Out, err)
+		}
+		inputConfig.outPrefix = outPrefix
+	}
 	// Options depending on input format.
 	var options []string
 	if externalConfig.Compression != nil {
@@ -516,6 +564,14 @@ func (i *inputConfig) IncludeTypes() []string {
 	return i.includeTypes
 }
 
+func (i *inputConfig) PluginNames() []string {
+	return i.pluginNames
+}
+
+func (i *inputConfig) OutPrefix() string {
+	return i.outPrefix
+}
+
 func (i *inputConfig) isInputConfig() {}
 
 func newExternalInputConfigV2FromInputConfig(
@@ -574,5 +630,7 @@ func newExternalInputConfigV2FromInputConfig(
 	externalInputConfigV2.TargetPaths = inputConfig.TargetPaths()
 	externalInputConfigV2.ExcludePaths = inputConfig.ExcludePaths()
 	externalInputConfigV2.Types = inputConfig.IncludeTypes()
+	externalInputConfigV2.Plugins = inputConfig.PluginNames()
+	externalInputConfigV2.Out = inputConfig.OutPrefix()
 	return externalInputConfigV2, nil
 }
@@ -0,0 +1,102 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconfig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+)
+
+// LintCommentsConfig is the configuration of the requirements that comments must
+// meet for the COMMENT_* lint rules.
+//
+// By default, the COMMENT_* lint rules only check that comments are non-empty.
+type LintCommentsConfig interface {
+	// MinLength returns the minimum number of characters of a comment.
+	//
+	// Lines excluded by ExcludePrefixes do not count towards the length. If 0, the
+	// length of comments is not checked.
+	MinLength() int
+	// RequireNamePrefix returns true if comments must start with the name of the
+	// element they document.
+	RequireNamePrefix() bool
+	// ExcludePrefixes returns the prefixes of comment lines that are not considered
+	// documentation, such as annotations or generated markers.
+	//
+	// Lines that start with one of these prefixes are ignored, so that a comment that
+	// only consists of these lines is considered empty.
+	//
+	// Sorted.
+	ExcludePrefixes() []string
+
+	isLintCommentsConfig()
+}
+
+// NewLintCommentsConfig returns a new LintCommentsConfig.
+func NewLintCommentsConfig(
+	minLength int,
+	requireNamePrefix bool,
+	excludePrefixes []string,
+) (LintCommentsConfig, error) {
+	return newLintCommentsConfig(
+		minLength,
+		requireNamePrefix,
+		excludePrefixes,
+	)
+}
+
+// *** PRIVATE ***
+
+type lintCommentsConfig struct {
+	minLength         int
+	requireNamePrefix bool
+	excludePrefixes   []string
+}
+
+func newLintCommentsConfig(
+	minLength int,
+	requireNamePrefix bool,
+	excludePrefixes []string,
+) (*lintCommentsConfig, error) {
+	if minLength < 0 {
+		return nil, fmt.Errorf("lint.comments.min_length must not be negative, but was %d", minLength)
+	}
+	for _, excludePrefix := range excludePrefixes {
+		if excludePrefix == "" {
+			return nil, errors.New("lint.comments.exclude_prefixes must not contain empty prefixes")
+		}
+	}
+	return &lintCommentsConfig{
+		minLength:         minLength,
+		requireNamePrefix: requireNamePrefix,
+		excludePrefixes:   slicesext.ToUniqueSorted(excludePrefixes),
+	}, nil
+}
+
+func (l *lintCommentsConfig) MinLength() int {
+	return l.minLength
+}
+
+func (l *lintCommentsConfig) RequireNamePrefix() bool {
+	return l.requireNamePrefix
+}
+
+func (l *lintCommentsConfig) ExcludePrefixes() []string {
+	return slicesext.Copy(l.excludePrefixes)
+}
+
+func (*lintCommentsConfig) isLintCommentsConfig() {}
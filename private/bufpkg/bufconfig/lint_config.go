@@ -14,6 +14,12 @@
 
 package bufconfig
 
+import (
+	"maps"
+
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+)
+
 var (
 	// DefaultLintConfigV1 is the default lint config for v1.
 	DefaultLintConfigV1 LintConfig = NewLintConfig(
@@ -24,6 +30,11 @@ var (
 		false,
 		"",
 		false,
+		false,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	// DefaultLintConfigV2 is the default lint config for v2.
@@ -35,6 +46,11 @@ var (
 		false,
 		"",
 		true, // We default to allowing comment ignores in v2
+		false,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 )
 
@@ -48,11 +64,51 @@ type LintConfig interface {
 	RPCAllowGoogleProtobufEmptyResponses() bool
 	ServiceSuffix() string
 	AllowCommentIgnores() bool
+	// RequireCommentIgnoreJustification returns true if comment ignores must include
+	// a justification after the rule ID to take effect, such as:
+	//
+	//	// buf:lint:ignore FIELD_LOWER_SNAKE_CASE required for compatibility with legacy clients
+	//
+	// Comment ignores can include an expiry date with "expires=YYYY-MM-DD" regardless
+	// of this setting, and expired comment ignores never take effect.
+	RequireCommentIgnoreJustification() bool
+	// WarnIDsAndCategories returns the rule IDs and category IDs whose violations are
+	// warnings instead of errors.
+	//
+	// Warnings are printed, but do not result in a failure. The rules must also be
+	// configured to be used.
+	//
+	// Sorted.
+	WarnIDsAndCategories() []string
+	// NamingConfig returns the patterns that the names of elements must match for
+	// the NAMING rules.
+	//
+	// Will never be nil.
+	NamingConfig() LintNamingConfig
+	// CommentsConfig returns the requirements that comments must meet for the
+	// COMMENT_* rules.
+	//
+	// Will never be nil.
+	CommentsConfig() LintCommentsConfig
+	// PackageDirectoryMappings returns the map from package prefix to the directory,
+	// relative to the root of the module, that the files of packages with the prefix
+	// must be within for the PACKAGE_DIRECTORY_MATCH rule.
+	//
+	// For example, with "acme": "protos", files with package acme.foo.v1 must be within
+	// the directory protos/foo/v1. The empty prefix matches all packages, and the longest
+	// matching prefix is used.
+	//
+	// May be empty.
+	PackageDirectoryMappings() map[string]string
 
 	isLintConfig()
 }
 
 // NewLintConfig returns a new LintConfig.
+//
+// The namingConfig may be nil, in which case no naming patterns are configured.
+// The commentsConfig may be nil, in which case comments are only required to be non-empty.
+// The packageDirectoryMappings may be nil.
 func NewLintConfig(
 	checkConfig CheckConfig,
 	enumZeroValueSuffix string,
@@ -61,6 +117,11 @@ func NewLintConfig(
 	rpcAllowGoogleProtobufEmptyResponses bool,
 	serviceSuffix string,
 	allowCommentIgnores bool,
+	requireCommentIgnoreJustification bool,
+	warnIDsAndCategories []string,
+	namingConfig LintNamingConfig,
+	commentsConfig LintCommentsConfig,
+	packageDirectoryMappings map[string]string,
 ) LintConfig {
 	return newLintConfig(
 		checkConfig,
@@ -70,6 +131,11 @@ func NewLintConfig(
 		rpcAllowGoogleProtobufEmptyResponses,
 		serviceSuffix,
 		allowCommentIgnores,
+		requireCommentIgnoreJustification,
+		warnIDsAndCategories,
+		namingConfig,
+		commentsConfig,
+		packageDirectoryMappings,
 	)
 }
 
@@ -84,6 +150,11 @@ type lintConfig struct {
 	rpcAllowGoogleProtobufEmptyResponses bool
 	serviceSuffix                        string
 	allowCommentIgnores                  bool
+	requireCommentIgnoreJustification    bool
+	warnIDsAndCategories                 []string
+	namingConfig                         LintNamingConfig
+	commentsConfig                       LintCommentsConfig
+	packageDirectoryMappings             map[string]string
 }
 
 func newLintConfig(
@@ -94,7 +165,18 @@ func newLintConfig(
 	rpcAllowGoogleProtobufEmptyResponses bool,
 	serviceSuffix string,
 	allowCommentIgnores bool,
+	requireCommentIgnoreJustification bool,
+	warnIDsAndCategories []string,
+	namingConfig LintNamingConfig,
+	commentsConfig LintCommentsConfig,
+	packageDirectoryMappings map[string]string,
 ) *lintConfig {
+	if namingConfig == nil {
+		namingConfig = &lintNamingConfig{}
+	}
+	if commentsConfig == nil {
+		commentsConfig = &lintCommentsConfig{}
+	}
 	return &lintConfig{
 		CheckConfig:                          checkConfig,
 		enumZeroValueSuffix:                  enumZeroValueSuffix,
@@ -103,6 +185,11 @@ func newLintConfig(
 		rpcAllowGoogleProtobufEmptyResponses: rpcAllowGoogleProtobufEmptyResponses,
 		serviceSuffix:                        serviceSuffix,
 		allowCommentIgnores:                  allowCommentIgnores,
+		requireCommentIgnoreJustification:    requireCommentIgnoreJustification,
+		warnIDsAndCategories:                 slicesext.ToUniqueSorted(warnIDsAndCategories),
+		namingConfig:                         namingConfig,
+		commentsConfig:                       commentsConfig,
+		packageDirectoryMappings:             maps.Clone(packageDirectoryMappings),
 	}
 }
 
@@ -130,4 +217,24 @@ func (l *lintConfig) AllowCommentIgnores() bool {
 	return l.allowCommentIgnores
 }
 
+func (l *lintConfig) RequireCommentIgnoreJustification() bool {
+	return l.requireCommentIgnoreJustification
+}
+
+func (l *lintConfig) WarnIDsAndCategories() []string {
+	return slicesext.Copy(l.warnIDsAndCategories)
+}
+
+func (l *lintConfig) NamingConfig() LintNamingConfig {
+	return l.namingConfig
+}
+
+func (l *lintConfig) CommentsConfig() LintCommentsConfig {
+	return l.commentsConfig
+}
+
+func (l *lintConfig) PackageDirectoryMappings() map[string]string {
+	return maps.Clone(l.packageDirectoryMappings)
+}
+
 func (*lintConfig) isLintConfig() {}
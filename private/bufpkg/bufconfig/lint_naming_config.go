@@ -0,0 +1,132 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconfig
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// LintNamingConfig is the configuration of the regular expressions that the names
+// of elements must match for the NAMING lint rules.
+//
+// Each pattern may be empty, in which case the names of the elements are not checked.
+type LintNamingConfig interface {
+	// ServicePattern returns the pattern for the names of services.
+	ServicePattern() string
+	// RPCPattern returns the pattern for the names of RPCs.
+	RPCPattern() string
+	// MessagePattern returns the pattern for the names of messages.
+	MessagePattern() string
+	// FieldPattern returns the pattern for the names of fields.
+	FieldPattern() string
+	// EnumPattern returns the pattern for the names of enums.
+	EnumPattern() string
+	// EnumValuePattern returns the pattern for the names of enum values.
+	EnumValuePattern() string
+
+	isLintNamingConfig()
+}
+
+// NewLintNamingConfig returns a new LintNamingConfig.
+//
+// The patterns are validated to be valid regular expressions.
+func NewLintNamingConfig(
+	servicePattern string,
+	rpcPattern string,
+	messagePattern string,
+	fieldPattern string,
+	enumPattern string,
+	enumValuePattern string,
+) (LintNamingConfig, error) {
+	return newLintNamingConfig(
+		servicePattern,
+		rpcPattern,
+		messagePattern,
+		fieldPattern,
+		enumPattern,
+		enumValuePattern,
+	)
+}
+
+// *** PRIVATE ***
+
+type lintNamingConfig struct {
+	servicePattern   string
+	rpcPattern       string
+	messagePattern   string
+	fieldPattern     string
+	enumPattern      string
+	enumValuePattern string
+}
+
+func newLintNamingConfig(
+	servicePattern string,
+	rpcPattern string,
+	messagePattern string,
+	fieldPattern string,
+	enumPattern string,
+	enumValuePattern string,
+) (*lintNamingConfig, error) {
+	for _, keyAndPattern := range [][2]string{
+		{"service", servicePattern},
+		{"rpc", rpcPattern},
+		{"message", messagePattern},
+		{"field", fieldPattern},
+		{"enum", enumPattern},
+		{"enum_value", enumValuePattern},
+	} {
+		if keyAndPattern[1] == "" {
+			continue
+		}
+		if _, err := regexp.Compile(keyAndPattern[1]); err != nil {
+			return nil, fmt.Errorf("invalid lint.naming.%s pattern %q: %w", keyAndPattern[0], keyAndPattern[1], err)
+		}
+	}
+	return &lintNamingConfig{
+		servicePattern:   servicePattern,
+		rpcPattern:       rpcPattern,
+		messagePattern:   messagePattern,
+		fieldPattern:     fieldPattern,
+		enumPattern:      enumPattern,
+		enumValuePattern: enumValuePattern,
+	}, nil
+}
+
+func (l *lintNamingConfig) ServicePattern() string {
+	return l.servicePattern
+}
+
+func (l *lintNamingConfig) RPCPattern() string {
+	return l.rpcPattern
+}
+
+func (l *lintNamingConfig) MessagePattern() string {
+	return l.messagePattern
+}
+
+func (l *lintNamingConfig) FieldPattern() string {
+	return l.fieldPattern
+}
+
+func (l *lintNamingConfig) EnumPattern() string {
+	return l.enumPattern
+}
+
+func (l *lintNamingConfig) EnumValuePattern() string {
+	return l.enumValuePattern
+}
+
+func (*lintNamingConfig) isLintNamingConfig() {}
@@ -0,0 +1,95 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconfig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+)
+
+// ModuleOverride replaces a dependency with a local directory.
+//
+// This is used during development to test changes to a dependency before pushing it.
+type ModuleOverride interface {
+	// FullName is the FullName of the dependency that is overridden.
+	//
+	// Always present.
+	FullName() bufparse.FullName
+	// DirPath is the path to the directory that contains the files of the local module,
+	// that is, the directory that import paths are relative to.
+	//
+	// This is normalized. If relative, it is relative to the directory of the buf.yaml file.
+	//
+	// Always present.
+	DirPath() string
+
+	isModuleOverride()
+}
+
+// NewModuleOverride returns a new ModuleOverride.
+func NewModuleOverride(fullName bufparse.FullName, dirPath string) (ModuleOverride, error) {
+	return newModuleOverride(fullName, dirPath)
+}
+
+// *** PRIVATE ***
+
+type moduleOverride struct {
+	fullName bufparse.FullName
+	dirPath  string
+}
+
+func newModuleOverride(fullName bufparse.FullName, dirPath string) (*moduleOverride, error) {
+	if fullName == nil {
+		return nil, errors.New("module must be set for an override")
+	}
+	if dirPath == "" {
+		return nil, fmt.Errorf("path must be set for the override of %s", fullName)
+	}
+	return &moduleOverride{
+		fullName: fullName,
+		dirPath:  normalpath.Normalize(dirPath),
+	}, nil
+}
+
+func newModuleOverrideForExternalV2(externalOverride externalBufYAMLFileOverrideV2) (*moduleOverride, error) {
+	if externalOverride.Module == "" {
+		return nil, errors.New("overrides: module must be set")
+	}
+	fullName, err := bufparse.ParseFullName(externalOverride.Module)
+	if err != nil {
+		return nil, fmt.Errorf("overrides: %w", err)
+	}
+	return newModuleOverride(fullName, externalOverride.Path)
+}
+
+func newExternalV2ForModuleOverride(moduleOverride ModuleOverride) externalBufYAMLFileOverrideV2 {
+	return externalBufYAMLFileOverrideV2{
+		Module: moduleOverride.FullName().String(),
+		Path:   moduleOverride.DirPath(),
+	}
+}
+
+func (m *moduleOverride) FullName() bufparse.FullName {
+	return m.fullName
+}
+
+func (m *moduleOverride) DirPath() string {
+	return m.dirPath
+}
+
+func (*moduleOverride) isModuleOverride() {}
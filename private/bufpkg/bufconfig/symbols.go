@@ -0,0 +1,79 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IsIgnoreOnlySymbolPattern returns true if the value of a breaking.ignore_only entry
+// is a fully-qualified symbol pattern, such as "acme.internal.**", instead of a path.
+//
+// A value is a symbol pattern if it does not contain a "/", does not end in ".proto",
+// and is a dot-separated list of at least two components, where each component is
+// either a Protobuf identifier, "*", or "**".
+func IsIgnoreOnlySymbolPattern(value string) bool {
+	if strings.Contains(value, "/") || strings.HasSuffix(value, ".proto") {
+		return false
+	}
+	components := strings.Split(value, ".")
+	if len(components) < 2 {
+		return false
+	}
+	for _, component := range components {
+		if component != "*" && component != "**" && !isProtobufIdentifier(component) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateSymbolPatterns validates that every pattern is a valid fully-qualified
+// symbol pattern.
+//
+// A pattern is a dot-separated list of components, where each component is either
+// a Protobuf identifier, "*", or "**". Leading dots are not allowed.
+func validateSymbolPatterns(patterns []string, name string) error {
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return fmt.Errorf("%s contained an empty symbol", name)
+		}
+		for _, component := range strings.Split(pattern, ".") {
+			if component == "*" || component == "**" {
+				continue
+			}
+			if !isProtobufIdentifier(component) {
+				return fmt.Errorf("%s %q is not a valid symbol pattern: invalid component %q", name, pattern, component)
+			}
+		}
+	}
+	return nil
+}
+
+func isProtobufIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufextension provides the extension registry, which records the extension
+// numbers allocated for each extendee, such as google.protobuf.FieldOptions.
+//
+// The registry is maintained with buf beta extension sync, which fails if two
+// extensions are allocated the same number for the same extendee, and is used by the
+// EXTENSION_REGISTRY_NO_CONFLICT lint rule to prevent allocating a number that was
+// previously allocated to another extension, including extensions in other modules
+// that are not built together.
+package bufextension
+
+import (
+	"io"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+)
+
+// DefaultFileName is the default file name of the extension registry.
+const DefaultFileName = "buf.extensions.yaml"
+
+// Registry is an extension registry.
+type Registry interface {
+	// Extendees returns the Records for extendees, sorted by full name.
+	Extendees() []Record
+
+	isRegistry()
+}
+
+// Record records the extension numbers allocated for an extendee.
+type Record interface {
+	// Extendee returns the fully-qualified name of the extended message.
+	Extendee() string
+	// Entries returns the entries, sorted by number and then by name.
+	//
+	// The same number appears with multiple names if the number is allocated to
+	// multiple extensions, which is a conflict.
+	Entries() []Entry
+
+	isRecord()
+}
+
+// Entry is a number allocated to an extension.
+type Entry struct {
+	Number int32
+	// Name is the fully-qualified name of the extension.
+	Name string
+}
+
+// Conflict is a number allocated to multiple extensions of the same extendee.
+type Conflict struct {
+	Extendee string
+	Number   int32
+	// Names are the fully-qualified names of the extensions, sorted.
+	Names []string
+}
+
+// NewRegistry returns a new Registry with the given Records.
+//
+// Records with the same extendee are merged.
+func NewRegistry(records ...Record) Registry {
+	return newRegistryForRecords(records)
+}
+
+// NewRecord returns a new Record for the given extendee.
+func NewRecord(extendee string, entries ...Entry) Record {
+	return newRecord(extendee, entries)
+}
+
+// NewRegistryForImage returns a new Registry with the extension numbers currently
+// allocated in the non-import files of the Image.
+func NewRegistryForImage(image bufimage.Image) Registry {
+	return newRegistryForImage(image)
+}
+
+// MergeRegistries returns a new Registry with the Records of all the given Registries.
+func MergeRegistries(registries ...Registry) Registry {
+	return mergeRegistries(registries...)
+}
+
+// Conflicts returns the Conflicts of the Registry, sorted by extendee and then by number.
+func Conflicts(registry Registry) []Conflict {
+	return conflicts(registry)
+}
+
+// ReadRegistry reads a Registry from the io.Reader.
+func ReadRegistry(reader io.Reader) (Registry, error) {
+	return readRegistry(reader)
+}
+
+// WriteRegistry writes the Registry to the io.Writer.
+func WriteRegistry(writer io.Writer, registry Registry) error {
+	return writeRegistry(writer, registry)
+}
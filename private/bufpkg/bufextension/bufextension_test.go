@@ -0,0 +1,140 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufextension
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeRegistriesAndConflicts(t *testing.T) {
+	t.Parallel()
+	registry1, err := ReadRegistry(
+		strings.NewReader(`version: v1
+extendees:
+  - name: google.protobuf.FieldOptions
+    entries:
+      - number: 50001
+        name: a.v1.bar
+      - number: 50000
+        name: a.v1.foo
+`),
+	)
+	require.NoError(t, err)
+	registry2, err := ReadRegistry(
+		strings.NewReader(`version: v1
+extendees:
+  - name: google.protobuf.FieldOptions
+    entries:
+      - number: 50000
+        name: a.v1.foo
+      - number: 50001
+        name: b.v1.baz
+  - name: google.protobuf.MessageOptions
+    entries:
+      - number: 50001
+        name: b.v1.baz
+`),
+	)
+	require.NoError(t, err)
+	require.Empty(t, Conflicts(registry1))
+	require.Empty(t, Conflicts(registry2))
+	mergedRegistry := MergeRegistries(registry1, registry2)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteRegistry(buffer, mergedRegistry))
+	require.Equal(
+		t,
+		`version: v1
+extendees:
+  - name: google.protobuf.FieldOptions
+    entries:
+      - number: 50000
+        name: a.v1.foo
+      - number: 50001
+        name: a.v1.bar
+      - number: 50001
+        name: b.v1.baz
+  - name: google.protobuf.MessageOptions
+    entries:
+      - number: 50001
+        name: b.v1.baz
+`,
+		buffer.String(),
+	)
+	require.Equal(
+		t,
+		[]Conflict{
+			{
+				Extendee: "google.protobuf.FieldOptions",
+				Number:   50001,
+				Names:    []string{"a.v1.bar", "b.v1.baz"},
+			},
+		},
+		Conflicts(mergedRegistry),
+	)
+}
+
+func TestNewRegistry(t *testing.T) {
+	t.Parallel()
+	registry := NewRegistry(
+		NewRecord(
+			"google.protobuf.FieldOptions",
+			Entry{Number: 50001, Name: "a.v1.bar"},
+			Entry{Number: 50000, Name: "a.v1.foo"},
+		),
+		NewRecord("google.protobuf.FieldOptions", Entry{Number: 50000, Name: "a.v1.foo"}),
+	)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteRegistry(buffer, registry))
+	require.Equal(
+		t,
+		`version: v1
+extendees:
+  - name: google.protobuf.FieldOptions
+    entries:
+      - number: 50000
+        name: a.v1.foo
+      - number: 50001
+        name: a.v1.bar
+`,
+		buffer.String(),
+	)
+}
+
+func TestReadRegistryErrors(t *testing.T) {
+	t.Parallel()
+	_, err := ReadRegistry(strings.NewReader(`version: v2`))
+	require.ErrorContains(t, err, `unknown extension registry version "v2"`)
+	_, err = ReadRegistry(
+		strings.NewReader(`version: v1
+extendees:
+  - name: google.protobuf.FieldOptions
+  - name: google.protobuf.FieldOptions
+`),
+	)
+	require.ErrorContains(t, err, `extendee "google.protobuf.FieldOptions" is recorded more than once`)
+	_, err = ReadRegistry(
+		strings.NewReader(`version: v1
+extendees:
+  - name: google.protobuf.FieldOptions
+    entries:
+      - number: 50000
+`),
+	)
+	require.ErrorContains(t, err, `extendee "google.protobuf.FieldOptions": name is required for number 50000`)
+}
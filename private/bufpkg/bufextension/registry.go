@@ -0,0 +1,284 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufextension
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/encoding"
+	"github.com/bufbuild/buf/private/pkg/syserror"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const registryVersion = "v1"
+
+type registry struct {
+	extendees []Record
+}
+
+func newRegistry(extendeeToEntries map[string]map[Entry]struct{}) *registry {
+	records := make([]Record, 0, len(extendeeToEntries))
+	for extendee, entrySet := range extendeeToEntries {
+		entries := make([]Entry, 0, len(entrySet))
+		for entry := range entrySet {
+			entries = append(entries, entry)
+		}
+		slices.SortFunc(entries, compareEntries)
+		records = append(
+			records,
+			&record{
+				extendee: extendee,
+				entries:  entries,
+			},
+		)
+	}
+	slices.SortFunc(records, func(a Record, b Record) int {
+		return strings.Compare(a.Extendee(), b.Extendee())
+	})
+	return &registry{
+		extendees: records,
+	}
+}
+
+func newRegistryForImage(image bufimage.Image) *registry {
+	extendeeToEntries := make(map[string]map[Entry]struct{})
+	for _, imageFile := range image.Files() {
+		if imageFile.IsImport() {
+			continue
+		}
+		fileDescriptorProto := imageFile.FileDescriptorProto()
+		prefix := fileDescriptorProto.GetPackage()
+		if prefix != "" {
+			prefix += "."
+		}
+		addExtensions(extendeeToEntries, prefix, fileDescriptorProto.GetExtension())
+		addMessages(extendeeToEntries, prefix, fileDescriptorProto.GetMessageType())
+	}
+	return newRegistry(extendeeToEntries)
+}
+
+func newRegistryForRecords(records []Record) *registry {
+	extendeeToEntries := make(map[string]map[Entry]struct{})
+	addRecords(extendeeToEntries, records)
+	return newRegistry(extendeeToEntries)
+}
+
+func mergeRegistries(registries ...Registry) *registry {
+	extendeeToEntries := make(map[string]map[Entry]struct{})
+	for _, registry := range registries {
+		addRecords(extendeeToEntries, registry.Extendees())
+	}
+	return newRegistry(extendeeToEntries)
+}
+
+func conflicts(registry Registry) []Conflict {
+	var conflicts []Conflict
+	for _, record := range registry.Extendees() {
+		// Entries are sorted by number and then by name, so the entries for a number are adjacent.
+		entries := record.Entries()
+		for i := 0; i < len(entries); {
+			j := i + 1
+			for j < len(entries) && entries[j].Number == entries[i].Number {
+				j++
+			}
+			if j-i > 1 {
+				names := make([]string, 0, j-i)
+				for _, entry := range entries[i:j] {
+					names = append(names, entry.Name)
+				}
+				conflicts = append(
+					conflicts,
+					Conflict{
+						Extendee: record.Extendee(),
+						Number:   entries[i].Number,
+						Names:    names,
+					},
+				)
+			}
+			i = j
+		}
+	}
+	return conflicts
+}
+
+func readRegistry(reader io.Reader) (*registry, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var externalRegistry externalRegistryV1
+	if err := encoding.UnmarshalYAMLStrict(data, &externalRegistry); err != nil {
+		return nil, err
+	}
+	if externalRegistry.Version != registryVersion {
+		return nil, fmt.Errorf("unknown extension registry version %q, expected %q", externalRegistry.Version, registryVersion)
+	}
+	extendeeToEntries := make(map[string]map[Entry]struct{}, len(externalRegistry.Extendees))
+	for _, externalRecord := range externalRegistry.Extendees {
+		if externalRecord.Name == "" {
+			return nil, fmt.Errorf("extendee name is required")
+		}
+		if _, ok := extendeeToEntries[externalRecord.Name]; ok {
+			return nil, fmt.Errorf("extendee %q is recorded more than once", externalRecord.Name)
+		}
+		entries := getEntries(extendeeToEntries, externalRecord.Name)
+		for _, externalEntry := range externalRecord.Entries {
+			if externalEntry.Name == "" {
+				return nil, fmt.Errorf("extendee %q: name is required for number %d", externalRecord.Name, externalEntry.Number)
+			}
+			entries[Entry{Number: externalEntry.Number, Name: externalEntry.Name}] = struct{}{}
+		}
+	}
+	return newRegistry(extendeeToEntries), nil
+}
+
+func writeRegistry(writer io.Writer, registry Registry) error {
+	if registry == nil {
+		return syserror.New("nil Registry")
+	}
+	records := registry.Extendees()
+	externalRecords := make([]externalRecordV1, 0, len(records))
+	for _, record := range records {
+		entries := record.Entries()
+		externalEntries := make([]externalEntryV1, len(entries))
+		for i, entry := range entries {
+			externalEntries[i] = externalEntryV1{
+				Number: entry.Number,
+				Name:   entry.Name,
+			}
+		}
+		externalRecords = append(
+			externalRecords,
+			externalRecordV1{
+				Name:    record.Extendee(),
+				Entries: externalEntries,
+			},
+		)
+	}
+	data, err := encoding.MarshalYAML(
+		&externalRegistryV1{
+			Version:   registryVersion,
+			Extendees: externalRecords,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
+
+func (r *registry) Extendees() []Record {
+	return slices.Clone(r.extendees)
+}
+
+func (*registry) isRegistry() {}
+
+type record struct {
+	extendee string
+	entries  []Entry
+}
+
+func newRecord(extendee string, entries []Entry) *record {
+	entries = slices.Clone(entries)
+	slices.SortFunc(entries, compareEntries)
+	return &record{
+		extendee: extendee,
+		entries:  slices.Compact(entries),
+	}
+}
+
+func (r *record) Extendee() string {
+	return r.extendee
+}
+
+func (r *record) Entries() []Entry {
+	return slices.Clone(r.entries)
+}
+
+func (*record) isRecord() {}
+
+func addMessages(
+	extendeeToEntries map[string]map[Entry]struct{},
+	prefix string,
+	messageDescriptorProtos []*descriptorpb.DescriptorProto,
+) {
+	for _, messageDescriptorProto := range messageDescriptorProtos {
+		fullName := prefix + messageDescriptorProto.GetName()
+		addExtensions(extendeeToEntries, fullName+".", messageDescriptorProto.GetExtension())
+		addMessages(extendeeToEntries, fullName+".", messageDescriptorProto.GetNestedType())
+	}
+}
+
+func addExtensions(
+	extendeeToEntries map[string]map[Entry]struct{},
+	prefix string,
+	fieldDescriptorProtos []*descriptorpb.FieldDescriptorProto,
+) {
+	for _, fieldDescriptorProto := range fieldDescriptorProtos {
+		// Extendees are fully-qualified with a leading dot in images.
+		extendee := strings.TrimPrefix(fieldDescriptorProto.GetExtendee(), ".")
+		entries := getEntries(extendeeToEntries, extendee)
+		entries[Entry{Number: fieldDescriptorProto.GetNumber(), Name: prefix + fieldDescriptorProto.GetName()}] = struct{}{}
+	}
+}
+
+func addRecords(extendeeToEntries map[string]map[Entry]struct{}, records []Record) {
+	for _, record := range records {
+		entries := getEntries(extendeeToEntries, record.Extendee())
+		for _, entry := range record.Entries() {
+			entries[entry] = struct{}{}
+		}
+	}
+}
+
+func getEntries(extendeeToEntries map[string]map[Entry]struct{}, extendee string) map[Entry]struct{} {
+	entries, ok := extendeeToEntries[extendee]
+	if !ok {
+		entries = make(map[Entry]struct{})
+		extendeeToEntries[extendee] = entries
+	}
+	return entries
+}
+
+func compareEntries(a Entry, b Entry) int {
+	if c := cmp.Compare(a.Number, b.Number); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Name, b.Name)
+}
+
+// externalRegistryV1 represents a v1 extension registry file.
+type externalRegistryV1 struct {
+	Version   string             `json:"version,omitempty" yaml:"version,omitempty"`
+	Extendees []externalRecordV1 `json:"extendees,omitempty" yaml:"extendees,omitempty"`
+}
+
+// externalRecordV1 represents an extendee in a v1 extension registry file.
+type externalRecordV1 struct {
+	Name    string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Entries []externalEntryV1 `json:"entries,omitempty" yaml:"entries,omitempty"`
+}
+
+// externalEntryV1 represents an extension in a v1 extension registry file.
+type externalEntryV1 struct {
+	Number int32  `json:"number" yaml:"number"`
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
+}
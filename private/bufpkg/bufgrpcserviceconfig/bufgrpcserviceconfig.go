@@ -0,0 +1,47 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufgrpcserviceconfig generates gRPC service configs from services and
+// methods marked with the buf.alpha.grpc.v1alpha1.service_method_config and
+// buf.alpha.grpc.v1alpha1.method_config options.
+package bufgrpcserviceconfig
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GenerateServiceConfig generates the gRPC service config JSON for the services in the file.
+//
+// A method config is produced for each service with the service_method_config option,
+// which applies to all methods of the service, and for each method with the method_config
+// option, which is merged over the service_method_config of its service. Method configs
+// are in the order the services and methods are declared.
+//
+// Returns empty if no service or method in the file has a config.
+func GenerateServiceConfig(fileDescriptor protoreflect.FileDescriptor) (string, error) {
+	serviceConfig, err := getServiceConfig(fileDescriptor)
+	if err != nil {
+		return "", err
+	}
+	if serviceConfig == nil {
+		return "", nil
+	}
+	data, err := json.MarshalIndent(serviceConfig, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,320 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufgrpcserviceconfig
+
+import (
+	"fmt"
+	"strconv"
+
+	grpcv1alpha1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/grpc/v1alpha1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// statusCodeNames are the names of the gRPC status codes that can be used in policies.
+//
+// OK is not included, as successful calls are never retried or hedged.
+var statusCodeNames = map[string]struct{}{
+	"CANCELLED":           {},
+	"UNKNOWN":             {},
+	"INVALID_ARGUMENT":    {},
+	"DEADLINE_EXCEEDED":   {},
+	"NOT_FOUND":           {},
+	"ALREADY_EXISTS":      {},
+	"PERMISSION_DENIED":   {},
+	"RESOURCE_EXHAUSTED":  {},
+	"FAILED_PRECONDITION": {},
+	"ABORTED":             {},
+	"OUT_OF_RANGE":        {},
+	"UNIMPLEMENTED":       {},
+	"INTERNAL":            {},
+	"UNAVAILABLE":         {},
+	"DATA_LOSS":           {},
+	"UNAUTHENTICATED":     {},
+}
+
+// serviceConfig is the JSON representation of a gRPC service config.
+//
+// See https://github.com/grpc/grpc/blob/master/doc/service_config.md.
+type serviceConfig struct {
+	MethodConfig []*methodConfig `json:"methodConfig"`
+}
+
+type methodConfig struct {
+	Name                    []*methodName  `json:"name"`
+	Timeout                 string         `json:"timeout,omitempty"`
+	WaitForReady            *bool          `json:"waitForReady,omitempty"`
+	MaxRequestMessageBytes  *uint32        `json:"maxRequestMessageBytes,omitempty"`
+	MaxResponseMessageBytes *uint32        `json:"maxResponseMessageBytes,omitempty"`
+	RetryPolicy             *retryPolicy   `json:"retryPolicy,omitempty"`
+	HedgingPolicy           *hedgingPolicy `json:"hedgingPolicy,omitempty"`
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	// Method is empty if the method config applies to all methods of the service.
+	Method string `json:"method,omitempty"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          uint32   `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type hedgingPolicy struct {
+	MaxAttempts         uint32   `json:"maxAttempts"`
+	HedgingDelay        string   `json:"hedgingDelay,omitempty"`
+	NonFatalStatusCodes []string `json:"nonFatalStatusCodes,omitempty"`
+}
+
+// getServiceConfig returns the service config for the services in the file, or nil
+// if no service or method in the file has a config.
+func getServiceConfig(fileDescriptor protoreflect.FileDescriptor) (*serviceConfig, error) {
+	var methodConfigs []*methodConfig
+	services := fileDescriptor.Services()
+	for i := range services.Len() {
+		serviceDescriptor := services.Get(i)
+		serviceMethodConfig, err := getExtension[*grpcv1alpha1.MethodConfig](
+			serviceDescriptor.Options(),
+			grpcv1alpha1.E_ServiceMethodConfig,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if serviceMethodConfig != nil {
+			methodConfig, err := newMethodConfig(serviceDescriptor, serviceMethodConfig)
+			if err != nil {
+				return nil, err
+			}
+			methodConfig.Name = []*methodName{{Service: string(serviceDescriptor.FullName())}}
+			methodConfigs = append(methodConfigs, methodConfig)
+		}
+		methods := serviceDescriptor.Methods()
+		for j := range methods.Len() {
+			methodDescriptor := methods.Get(j)
+			methodMethodConfig, err := getExtension[*grpcv1alpha1.MethodConfig](
+				methodDescriptor.Options(),
+				grpcv1alpha1.E_MethodConfig,
+			)
+			if err != nil {
+				return nil, err
+			}
+			if methodMethodConfig == nil {
+				continue
+			}
+			methodConfig, err := newMethodConfig(
+				methodDescriptor,
+				mergeMethodConfigs(serviceMethodConfig, methodMethodConfig),
+			)
+			if err != nil {
+				return nil, err
+			}
+			methodConfig.Name = []*methodName{
+				{
+					Service: string(serviceDescriptor.FullName()),
+					Method:  string(methodDescriptor.Name()),
+				},
+			}
+			methodConfigs = append(methodConfigs, methodConfig)
+		}
+	}
+	if len(methodConfigs) == 0 {
+		return nil, nil
+	}
+	return &serviceConfig{MethodConfig: methodConfigs}, nil
+}
+
+// mergeMethodConfigs returns the service method config with the fields that are set
+// on the method method config replaced.
+//
+// Fields are replaced rather than merged with proto.Merge, so that for example a
+// timeout of 2s on the method overrides a timeout of 1.5s on the service, instead of
+// merging the nanos of the service timeout into the method timeout. Setting one of
+// retry_policy and hedging_policy on the method clears the other.
+func mergeMethodConfigs(
+	serviceMethodConfig *grpcv1alpha1.MethodConfig,
+	methodMethodConfig *grpcv1alpha1.MethodConfig,
+) *grpcv1alpha1.MethodConfig {
+	if serviceMethodConfig == nil {
+		return methodMethodConfig
+	}
+	merged, _ := proto.Clone(serviceMethodConfig).(*grpcv1alpha1.MethodConfig)
+	mergedReflect := merged.ProtoReflect()
+	methodMethodConfig.ProtoReflect().Range(
+		func(fieldDescriptor protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+			mergedReflect.Set(fieldDescriptor, value)
+			return true
+		},
+	)
+	return merged
+}
+
+// newMethodConfig validates the MethodConfig and returns its JSON representation,
+// without the name set.
+func newMethodConfig(
+	descriptor protoreflect.Descriptor,
+	protoMethodConfig *grpcv1alpha1.MethodConfig,
+) (*methodConfig, error) {
+	methodConfig := &methodConfig{}
+	if protoMethodConfig.HasTimeout() {
+		timeout, err := formatPositiveDuration(protoMethodConfig.GetTimeout())
+		if err != nil {
+			return nil, fmt.Errorf("%s: timeout %w", descriptor.FullName(), err)
+		}
+		methodConfig.Timeout = timeout
+	}
+	if protoMethodConfig.HasWaitForReady() {
+		waitForReady := protoMethodConfig.GetWaitForReady()
+		methodConfig.WaitForReady = &waitForReady
+	}
+	if protoMethodConfig.HasMaxRequestMessageBytes() {
+		maxRequestMessageBytes := protoMethodConfig.GetMaxRequestMessageBytes()
+		methodConfig.MaxRequestMessageBytes = &maxRequestMessageBytes
+	}
+	if protoMethodConfig.HasMaxResponseMessageBytes() {
+		maxResponseMessageBytes := protoMethodConfig.GetMaxResponseMessageBytes()
+		methodConfig.MaxResponseMessageBytes = &maxResponseMessageBytes
+	}
+	switch protoMethodConfig.WhichPolicy() {
+	case grpcv1alpha1.MethodConfig_RetryPolicy_case:
+		retryPolicy, err := newRetryPolicy(protoMethodConfig.GetRetryPolicy())
+		if err != nil {
+			return nil, fmt.Errorf("%s: retry_policy.%w", descriptor.FullName(), err)
+		}
+		methodConfig.RetryPolicy = retryPolicy
+	case grpcv1alpha1.MethodConfig_HedgingPolicy_case:
+		hedgingPolicy, err := newHedgingPolicy(protoMethodConfig.GetHedgingPolicy())
+		if err != nil {
+			return nil, fmt.Errorf("%s: hedging_policy.%w", descriptor.FullName(), err)
+		}
+		methodConfig.HedgingPolicy = hedgingPolicy
+	}
+	return methodConfig, nil
+}
+
+// newRetryPolicy validates the RetryPolicy and returns its JSON representation.
+//
+// Errors are prefixed with the name of the invalid field.
+func newRetryPolicy(protoRetryPolicy *grpcv1alpha1.RetryPolicy) (*retryPolicy, error) {
+	if protoRetryPolicy.GetMaxAttempts() <= 1 {
+		return nil, fmt.Errorf("max_attempts must be greater than 1")
+	}
+	initialBackoff, err := formatPositiveDuration(protoRetryPolicy.GetInitialBackoff())
+	if err != nil {
+		return nil, fmt.Errorf("initial_backoff %w", err)
+	}
+	maxBackoff, err := formatPositiveDuration(protoRetryPolicy.GetMaxBackoff())
+	if err != nil {
+		return nil, fmt.Errorf("max_backoff %w", err)
+	}
+	if protoRetryPolicy.GetBackoffMultiplier() <= 0 {
+		return nil, fmt.Errorf("backoff_multiplier must be greater than 0")
+	}
+	if len(protoRetryPolicy.GetRetryableStatusCodes()) == 0 {
+		return nil, fmt.Errorf("retryable_status_codes must not be empty")
+	}
+	if err := validateStatusCodes(protoRetryPolicy.GetRetryableStatusCodes()); err != nil {
+		return nil, fmt.Errorf("retryable_status_codes %w", err)
+	}
+	return &retryPolicy{
+		MaxAttempts:          protoRetryPolicy.GetMaxAttempts(),
+		InitialBackoff:       initialBackoff,
+		MaxBackoff:           maxBackoff,
+		BackoffMultiplier:    protoRetryPolicy.GetBackoffMultiplier(),
+		RetryableStatusCodes: protoRetryPolicy.GetRetryableStatusCodes(),
+	}, nil
+}
+
+// newHedgingPolicy validates the HedgingPolicy and returns its JSON representation.
+//
+// Errors are prefixed with the name of the invalid field.
+func newHedgingPolicy(protoHedgingPolicy *grpcv1alpha1.HedgingPolicy) (*hedgingPolicy, error) {
+	if protoHedgingPolicy.GetMaxAttempts() <= 1 {
+		return nil, fmt.Errorf("max_attempts must be greater than 1")
+	}
+	hedgingPolicy := &hedgingPolicy{
+		MaxAttempts: protoHedgingPolicy.GetMaxAttempts(),
+	}
+	if protoHedgingPolicy.HasHedgingDelay() {
+		hedgingDelay := protoHedgingPolicy.GetHedgingDelay()
+		if err := hedgingDelay.CheckValid(); err != nil || hedgingDelay.AsDuration() < 0 {
+			return nil, fmt.Errorf("hedging_delay must not be negative")
+		}
+		hedgingPolicy.HedgingDelay = formatDuration(hedgingDelay)
+	}
+	if err := validateStatusCodes(protoHedgingPolicy.GetNonFatalStatusCodes()); err != nil {
+		return nil, fmt.Errorf("non_fatal_status_codes %w", err)
+	}
+	hedgingPolicy.NonFatalStatusCodes = protoHedgingPolicy.GetNonFatalStatusCodes()
+	return hedgingPolicy, nil
+}
+
+func validateStatusCodes(statusCodes []string) error {
+	for _, statusCode := range statusCodes {
+		if _, ok := statusCodeNames[statusCode]; !ok {
+			return fmt.Errorf("contains unknown status code %q", statusCode)
+		}
+	}
+	return nil
+}
+
+// formatPositiveDuration formats the Duration, returning an error that completes the
+// sentence "<field> ..." if the Duration is not greater than 0.
+func formatPositiveDuration(duration *durationpb.Duration) (string, error) {
+	if err := duration.CheckValid(); err != nil || duration.AsDuration() <= 0 {
+		return "", fmt.Errorf("must be greater than 0")
+	}
+	return formatDuration(duration), nil
+}
+
+// formatDuration formats the Duration as in the JSON representation of
+// google.protobuf.Duration, such as "1.5s".
+func formatDuration(duration *durationpb.Duration) string {
+	return strconv.FormatFloat(duration.AsDuration().Seconds(), 'f', -1, 64) + "s"
+}
+
+// getExtension returns the value of the extension on the options, or the zero
+// value if the extension is not set.
+//
+// The options are reparsed with protoregistry.GlobalTypes, so that the extension
+// is found regardless of whether it was parsed as a known extension, a dynamic
+// extension, or unknown fields.
+func getExtension[M proto.Message](options proto.Message, extensionType protoreflect.ExtensionType) (M, error) {
+	var zero M
+	if options == nil || !options.ProtoReflect().IsValid() {
+		return zero, nil
+	}
+	data, err := proto.Marshal(options)
+	if err != nil {
+		return zero, err
+	}
+	reparsedOptions := options.ProtoReflect().Type().New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: protoregistry.GlobalTypes}).Unmarshal(data, reparsedOptions); err != nil {
+		return zero, err
+	}
+	if !proto.HasExtension(reparsedOptions, extensionType) {
+		return zero, nil
+	}
+	value, ok := proto.GetExtension(reparsedOptions, extensionType).(M)
+	if !ok {
+		return zero, fmt.Errorf("unexpected type for extension %s", extensionType.TypeDescriptor().FullName())
+	}
+	return value, nil
+}
@@ -46,6 +46,7 @@ func Modify(
 			modifyPhpNamespace,
 			modifyRubyPackage,
 			modifyJsType,
+			newModifyCustomOptionsFunc(image.Resolver()),
 		},
 		options...,
 	)
@@ -264,13 +265,21 @@ func modifyImage(
 	if !config.Enabled() {
 		return nil
 	}
+	overrideMatcher, err := newOverrideMatcher(image, config)
+	if err != nil {
+		return err
+	}
 	sweeper := internal.NewMarkSweeper(image)
 	for _, imageFile := range image.Files() {
 		if datawkt.Exists(imageFile.Path()) {
 			continue
 		}
+		fileConfig, err := overrideMatcher.configForFile(imageFile, config)
+		if err != nil {
+			return err
+		}
 		for _, modifyFunc := range modifyFuncs {
-			if err := modifyFunc(sweeper, imageFile, config, options...); err != nil {
+			if err := modifyFunc(sweeper, imageFile, fileConfig, options...); err != nil {
 				return err
 			}
 		}
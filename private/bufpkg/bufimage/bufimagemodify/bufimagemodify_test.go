@@ -31,6 +31,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
@@ -756,6 +757,340 @@ func TestGetStringOverrideFromConfig(t *testing.T) {
 	}
 }
 
+func TestModifyImageWithOverrideMatch(t *testing.T) {
+	t.Parallel()
+	dirPathToFullName := map[string]string{
+		filepath.Join("testdata", "match"): "buf.build/acme/match",
+	}
+	testcases := []struct {
+		description string
+		match       bufconfig.ManagedOverrideMatch
+		// The files expected to have java_multiple_files set to false.
+		expectedMatchedFilePaths []string
+	}{
+		{
+			description: "path_regex",
+			match:       newTestManagedOverrideMatch(t, "^acme/(modern|payment)/", nil, nil, nil),
+			expectedMatchedFilePaths: []string{
+				"acme/modern/v1/modern.proto",
+				"acme/payment/v1/payment.proto",
+			},
+		},
+		{
+			description: "custom_file_option",
+			match: newTestManagedOverrideMatch(
+				t,
+				"",
+				newTestManagedOptionMatch(t, "(acme.options.v1.legacy_java)", true),
+				nil,
+				nil,
+			),
+			expectedMatchedFilePaths: []string{
+				"acme/legacy/v1/legacy.proto",
+			},
+		},
+		{
+			description: "custom_file_option_value_mismatch",
+			match: newTestManagedOverrideMatch(
+				t,
+				"",
+				newTestManagedOptionMatch(t, "(acme.options.v1.legacy_java)", false),
+				nil,
+				nil,
+			),
+		},
+		{
+			description: "message_option",
+			match: newTestManagedOverrideMatch(
+				t,
+				"",
+				nil,
+				newTestManagedOptionMatch(t, "deprecated", nil),
+				nil,
+			),
+			expectedMatchedFilePaths: []string{
+				"acme/modern/v1/modern.proto",
+			},
+		},
+		{
+			description: "custom_field_option_enum_value",
+			match: newTestManagedOverrideMatch(
+				t,
+				"",
+				nil,
+				nil,
+				newTestManagedOptionMatch(t, "(acme.options.v1.sensitivity)", "SENSITIVITY_HIGH"),
+			),
+			expectedMatchedFilePaths: []string{
+				"acme/payment/v1/payment.proto",
+			},
+		},
+		{
+			description: "all_conditions",
+			match: newTestManagedOverrideMatch(
+				t,
+				"^acme/legacy/",
+				nil,
+				nil,
+				newTestManagedOptionMatch(t, "(acme.options.v1.sensitivity)", nil),
+			),
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.description, func(t *testing.T) {
+			t.Parallel()
+			image := testGetImageFromDirs(t, dirPathToFullName, true)
+			override, err := bufconfig.NewManagedOverrideRuleForFileOption(
+				"",
+				"",
+				bufconfig.FileOptionJavaMultipleFiles,
+				false,
+				bufconfig.ManagedOverrideRuleWithMatch(testcase.match),
+			)
+			require.NoError(t, err)
+			err = Modify(
+				image,
+				bufconfig.NewGenerateManagedConfig(true, nil, []bufconfig.ManagedOverrideRule{override}),
+			)
+			require.NoError(t, err)
+			var matchedFilePaths []string
+			for _, imageFile := range image.Files() {
+				if imageFile.IsImport() {
+					continue
+				}
+				if !imageFile.FileDescriptorProto().GetOptions().GetJavaMultipleFiles() {
+					matchedFilePaths = append(matchedFilePaths, imageFile.Path())
+				}
+			}
+			require.ElementsMatch(t, testcase.expectedMatchedFilePaths, matchedFilePaths)
+		})
+	}
+}
+
+func TestModifyImageWithValueExpression(t *testing.T) {
+	t.Parallel()
+	dirPathToFullName := map[string]string{
+		filepath.Join("testdata", "match"): "buf.build/acme/match",
+	}
+	image := testGetImageFromDirs(t, dirPathToFullName, true)
+	goPackageValueExpression, err := bufconfig.NewManagedValueExpression(
+		`'github.com/acme/gen/' + file.module.split('/')[2] + '/' + file.package.replace('.', '/')`,
+	)
+	require.NoError(t, err)
+	goPackageOverride, err := bufconfig.NewManagedOverrideRuleForFileOption(
+		"",
+		"",
+		bufconfig.FileOptionGoPackage,
+		nil,
+		bufconfig.ManagedOverrideRuleWithValueExpression(goPackageValueExpression),
+	)
+	require.NoError(t, err)
+	javaMultipleFilesValueExpression, err := bufconfig.NewManagedValueExpression(`file.path.startsWith('acme/legacy/')`)
+	require.NoError(t, err)
+	javaMultipleFilesOverride, err := bufconfig.NewManagedOverrideRuleForFileOption(
+		"acme/payment",
+		"",
+		bufconfig.FileOptionJavaMultipleFiles,
+		nil,
+		bufconfig.ManagedOverrideRuleWithValueExpression(javaMultipleFilesValueExpression),
+	)
+	require.NoError(t, err)
+	err = Modify(
+		image,
+		bufconfig.NewGenerateManagedConfig(
+			true,
+			nil,
+			[]bufconfig.ManagedOverrideRule{
+				goPackageOverride,
+				javaMultipleFilesOverride,
+			},
+		),
+	)
+	require.NoError(t, err)
+	pathToGoPackage := make(map[string]string)
+	pathToJavaMultipleFiles := make(map[string]bool)
+	for _, imageFile := range image.Files() {
+		if imageFile.IsImport() {
+			continue
+		}
+		pathToGoPackage[imageFile.Path()] = imageFile.FileDescriptorProto().GetOptions().GetGoPackage()
+		pathToJavaMultipleFiles[imageFile.Path()] = imageFile.FileDescriptorProto().GetOptions().GetJavaMultipleFiles()
+	}
+	require.Equal(
+		t,
+		map[string]string{
+			"acme/legacy/v1/legacy.proto":   "github.com/acme/gen/match/acme/legacy/v1",
+			"acme/modern/v1/modern.proto":   "github.com/acme/gen/match/acme/modern/v1",
+			"acme/options/v1/options.proto": "github.com/acme/gen/match/acme/options/v1",
+			"acme/payment/v1/payment.proto": "github.com/acme/gen/match/acme/payment/v1",
+		},
+		pathToGoPackage,
+	)
+	// The value is only computed for the files in the path of the override, and
+	// java_multiple_files defaults to true in managed mode for the other files.
+	require.Equal(
+		t,
+		map[string]bool{
+			"acme/legacy/v1/legacy.proto":   true,
+			"acme/modern/v1/modern.proto":   true,
+			"acme/options/v1/options.proto": true,
+			"acme/payment/v1/payment.proto": false,
+		},
+		pathToJavaMultipleFiles,
+	)
+	// Computed values are validated for the option.
+	invalidValueExpression, err := bufconfig.NewManagedValueExpression(`file.package`)
+	require.NoError(t, err)
+	invalidOverride, err := bufconfig.NewManagedOverrideRuleForFileOption(
+		"",
+		"",
+		bufconfig.FileOptionOptimizeFor,
+		nil,
+		bufconfig.ManagedOverrideRuleWithValueExpression(invalidValueExpression),
+	)
+	require.NoError(t, err)
+	err = Modify(
+		testGetImageFromDirs(t, dirPathToFullName, true),
+		bufconfig.NewGenerateManagedConfig(true, nil, []bufconfig.ManagedOverrideRule{invalidOverride}),
+	)
+	require.ErrorContains(t, err, `invalid value computed by value_expression "file.package"`)
+}
+
+func TestModifyImageWithCustomOptions(t *testing.T) {
+	t.Parallel()
+	dirPathToFullName := map[string]string{
+		filepath.Join("testdata", "customoption"): "buf.build/acme/weather",
+	}
+	image := testGetImageFromDirs(t, dirPathToFullName, true)
+	err := Modify(
+		image,
+		bufconfig.NewGenerateManagedConfig(
+			true,
+			nil,
+			[]bufconfig.ManagedOverrideRule{
+				newTestManagedOverrideRuleForCustomFileOption(t, "acme/weather", "(acme.options.v1.owner)", "platform"),
+				newTestManagedOverrideRuleForCustomFileOption(t, "acme/weather", "acme.options.v1.tags", []interface{}{"public", "stable"}),
+				newTestManagedOverrideRuleForCustomFileOption(
+					t,
+					"acme/weather",
+					"(acme.options.v1.team)",
+					map[string]interface{}{"name": "weather", "size": 3},
+				),
+				newTestManagedOverrideRuleForCustomMessageOption(t, "acme/weather", "(acme.options.v1.cache_ttl_seconds)", 60),
+				newTestManagedOverrideRuleForCustomMessageOption(t, "acme/weather", "(acme.options.v1.visibility)", "VISIBILITY_PUBLIC"),
+			},
+		),
+	)
+	require.NoError(t, err)
+	imageFile := image.GetFile("acme/weather/v1/weather.proto")
+	require.NotNil(t, imageFile)
+	fileDescriptorProto := imageFile.FileDescriptorProto()
+	// Standard options are kept.
+	require.Equal(t, "com.acme.weather.v1", fileDescriptorProto.GetOptions().GetJavaPackage())
+	fileOptions := testResolveOptions(t, image, fileDescriptorProto.GetOptions())
+	require.Equal(t, "platform", testGetCustomOption(t, image, fileOptions, "acme.options.v1.owner").String())
+	tags := testGetCustomOption(t, image, fileOptions, "acme.options.v1.tags").List()
+	require.Equal(t, 2, tags.Len())
+	require.Equal(t, "public", tags.Get(0).String())
+	require.Equal(t, "stable", tags.Get(1).String())
+	team := testGetCustomOption(t, image, fileOptions, "acme.options.v1.team").Message()
+	require.Equal(t, "weather", team.Get(team.Descriptor().Fields().ByName("name")).String())
+	require.Equal(t, int64(3), team.Get(team.Descriptor().Fields().ByName("size")).Int())
+	// The source code info for the overridden file option is removed.
+	for _, location := range fileDescriptorProto.GetSourceCodeInfo().GetLocation() {
+		require.NotEqual(t, []int32{8, 50001}, location.GetPath())
+	}
+	forecast := fileDescriptorProto.GetMessageType()[0]
+	for _, message := range []*descriptorpb.DescriptorProto{forecast, forecast.GetNestedType()[0]} {
+		messageOptions := testResolveOptions(t, image, message.GetOptions())
+		require.Equal(t, int64(60), testGetCustomOption(t, image, messageOptions, "acme.options.v1.cache_ttl_seconds").Int())
+		visibility := testGetCustomOption(t, image, messageOptions, "acme.options.v1.visibility")
+		require.Equal(t, protoreflect.EnumNumber(2), visibility.Enum())
+	}
+	// Map entries are not modified.
+	mapEntry := forecast.GetNestedType()[1]
+	require.True(t, mapEntry.GetOptions().GetMapEntry())
+	require.Empty(t, mapEntry.GetOptions().ProtoReflect().GetUnknown())
+	// Files that do not match the override are not modified.
+	optionsImageFile := image.GetFile("acme/options/v1/options.proto")
+	require.NotNil(t, optionsImageFile)
+	require.Empty(t, optionsImageFile.FileDescriptorProto().GetOptions().ProtoReflect().GetUnknown())
+}
+
+func TestModifyImageWithCustomOptionsPreserveExisting(t *testing.T) {
+	t.Parallel()
+	dirPathToFullName := map[string]string{
+		filepath.Join("testdata", "customoption"): "buf.build/acme/weather",
+	}
+	image := testGetImageFromDirs(t, dirPathToFullName, false)
+	err := Modify(
+		image,
+		bufconfig.NewGenerateManagedConfig(
+			true,
+			nil,
+			[]bufconfig.ManagedOverrideRule{
+				newTestManagedOverrideRuleForCustomFileOption(t, "acme/weather", "(acme.options.v1.owner)", "platform"),
+				newTestManagedOverrideRuleForCustomMessageOption(t, "acme/weather", "(acme.options.v1.visibility)", "VISIBILITY_PUBLIC"),
+			},
+		),
+		ModifyPreserveExisting(),
+	)
+	require.NoError(t, err)
+	fileDescriptorProto := image.GetFile("acme/weather/v1/weather.proto").FileDescriptorProto()
+	fileOptions := testResolveOptions(t, image, fileDescriptorProto.GetOptions())
+	require.Equal(t, "weather", testGetCustomOption(t, image, fileOptions, "acme.options.v1.owner").String())
+	forecast := fileDescriptorProto.GetMessageType()[0]
+	forecastOptions := testResolveOptions(t, image, forecast.GetOptions())
+	require.Equal(t, protoreflect.EnumNumber(1), testGetCustomOption(t, image, forecastOptions, "acme.options.v1.visibility").Enum())
+	readingOptions := testResolveOptions(t, image, forecast.GetNestedType()[0].GetOptions())
+	require.Equal(t, protoreflect.EnumNumber(2), testGetCustomOption(t, image, readingOptions, "acme.options.v1.visibility").Enum())
+}
+
+func TestModifyImageWithCustomOptionsErrors(t *testing.T) {
+	t.Parallel()
+	dirPathToFullName := map[string]string{
+		filepath.Join("testdata", "customoption"): "buf.build/acme/weather",
+	}
+	testcases := []struct {
+		description      string
+		overrideRule     func(t *testing.T) bufconfig.ManagedOverrideRule
+		expectedErrorMsg string
+	}{
+		{
+			description: "not_found",
+			overrideRule: func(t *testing.T) bufconfig.ManagedOverrideRule {
+				return newTestManagedOverrideRuleForCustomFileOption(t, "", "(acme.options.v1.unknown)", "platform")
+			},
+			expectedErrorMsg: "custom option (acme.options.v1.unknown) not found",
+		},
+		{
+			description: "wrong_options_message",
+			overrideRule: func(t *testing.T) bufconfig.ManagedOverrideRule {
+				return newTestManagedOverrideRuleForCustomMessageOption(t, "", "(acme.options.v1.redacted)", true)
+			},
+			expectedErrorMsg: "custom option (acme.options.v1.redacted) extends google.protobuf.FieldOptions, not google.protobuf.MessageOptions",
+		},
+		{
+			description: "invalid_value",
+			overrideRule: func(t *testing.T) bufconfig.ManagedOverrideRule {
+				return newTestManagedOverrideRuleForCustomMessageOption(t, "", "(acme.options.v1.cache_ttl_seconds)", "sixty")
+			},
+			expectedErrorMsg: "invalid value for custom option (acme.options.v1.cache_ttl_seconds)",
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.description, func(t *testing.T) {
+			t.Parallel()
+			image := testGetImageFromDirs(t, dirPathToFullName, false)
+			err := Modify(
+				image,
+				bufconfig.NewGenerateManagedConfig(true, nil, []bufconfig.ManagedOverrideRule{testcase.overrideRule(t)}),
+			)
+			require.ErrorContains(t, err, testcase.expectedErrorMsg)
+		})
+	}
+}
+
 // TODO FUTURE in v2
 //func TestModifyFieldOption(t *testing.T) {
 //t.Parallel()
@@ -850,3 +1185,71 @@ func newTestFileOptionOverrideRule(
 	require.NoError(t, err)
 	return fileOptionOverride
 }
+
+func newTestManagedOverrideMatch(
+	t *testing.T,
+	pathRegex string,
+	fileOptionMatch bufconfig.ManagedOptionMatch,
+	messageOptionMatch bufconfig.ManagedOptionMatch,
+	fieldOptionMatch bufconfig.ManagedOptionMatch,
+) bufconfig.ManagedOverrideMatch {
+	match, err := bufconfig.NewManagedOverrideMatch(
+		pathRegex,
+		fileOptionMatch,
+		messageOptionMatch,
+		fieldOptionMatch,
+	)
+	require.NoError(t, err)
+	return match
+}
+
+func newTestManagedOptionMatch(
+	t *testing.T,
+	name string,
+	value interface{},
+) bufconfig.ManagedOptionMatch {
+	optionMatch, err := bufconfig.NewManagedOptionMatch(name, value)
+	require.NoError(t, err)
+	return optionMatch
+}
+
+func newTestManagedOverrideRuleForCustomFileOption(
+	t *testing.T,
+	path string,
+	optionName string,
+	value interface{},
+) bufconfig.ManagedOverrideRule {
+	overrideRule, err := bufconfig.NewManagedOverrideRuleForCustomFileOption(path, "", optionName, value)
+	require.NoError(t, err)
+	return overrideRule
+}
+
+func newTestManagedOverrideRuleForCustomMessageOption(
+	t *testing.T,
+	path string,
+	optionName string,
+	value interface{},
+) bufconfig.ManagedOverrideRule {
+	overrideRule, err := bufconfig.NewManagedOverrideRuleForCustomMessageOption(path, "", optionName, value)
+	require.NoError(t, err)
+	return overrideRule
+}
+
+// testResolveOptions re-parses the options with the resolver of the image, so that
+// custom options are no longer unknown fields.
+func testResolveOptions(t *testing.T, image bufimage.Image, options proto.Message) protoreflect.Message {
+	messageType, err := image.Resolver().FindMessageByName(options.ProtoReflect().Descriptor().FullName())
+	require.NoError(t, err)
+	data, err := proto.Marshal(options)
+	require.NoError(t, err)
+	resolvedOptions := messageType.New()
+	require.NoError(t, proto.UnmarshalOptions{Resolver: image.Resolver()}.Unmarshal(data, resolvedOptions.Interface()))
+	return resolvedOptions
+}
+
+func testGetCustomOption(t *testing.T, image bufimage.Image, options protoreflect.Message, name string) protoreflect.Value {
+	extensionType, err := image.Resolver().FindExtensionByName(protoreflect.FullName(name))
+	require.NoError(t, err)
+	require.True(t, options.Has(extensionType.TypeDescriptor()), "custom option %s is not set", name)
+	return options.Get(extensionType.TypeDescriptor())
+}
@@ -0,0 +1,223 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufimagemodify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage/bufimagemodify/internal"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var (
+	// fileOptionsPath is the SourceCodeInfo path for the options of a file.
+	fileOptionsPath = []int32{8}
+
+	fileOptionsFullName    = (&descriptorpb.FileOptions{}).ProtoReflect().Descriptor().FullName()
+	messageOptionsFullName = (&descriptorpb.MessageOptions{}).ProtoReflect().Descriptor().FullName()
+)
+
+// newModifyCustomOptionsFunc returns a modify func that applies the overrides for
+// custom file and message options, resolving the custom options with the given resolver.
+func newModifyCustomOptionsFunc(
+	resolver protoencoding.Resolver,
+) func(internal.MarkSweeper, bufimage.ImageFile, bufconfig.GenerateManagedConfig, ...ModifyOption) error {
+	return func(
+		sweeper internal.MarkSweeper,
+		imageFile bufimage.ImageFile,
+		config bufconfig.GenerateManagedConfig,
+		options ...ModifyOption,
+	) error {
+		return modifyCustomOptions(resolver, sweeper, imageFile, config, options...)
+	}
+}
+
+func modifyCustomOptions(
+	resolver protoencoding.Resolver,
+	sweeper internal.MarkSweeper,
+	imageFile bufimage.ImageFile,
+	config bufconfig.GenerateManagedConfig,
+	options ...ModifyOption,
+) error {
+	modifyOptions := newModifyOptions()
+	for _, option := range options {
+		option(modifyOptions)
+	}
+	// Custom options can only be disabled by disable rules that do not specify an option.
+	if isFileOptionDisabledForFile(imageFile, bufconfig.FileOptionUnspecified, config) {
+		return nil
+	}
+	descriptor := imageFile.FileDescriptorProto()
+	for _, overrideRule := range config.Overrides() {
+		if !fileMatchConfig(imageFile, overrideRule.Path(), overrideRule.FullName()) {
+			continue
+		}
+		if optionName := overrideRule.CustomFileOption(); optionName != "" {
+			number, data, err := encodeCustomOption(resolver, optionName, fileOptionsFullName, overrideRule.Value())
+			if err != nil {
+				return err
+			}
+			if descriptor.Options == nil {
+				descriptor.Options = &descriptorpb.FileOptions{}
+			}
+			if setCustomOption(descriptor.Options, number, data, modifyOptions.preserveExisting) {
+				sweeper.Mark(imageFile, []int32{fileOptionsPath[0], int32(number)})
+			}
+		}
+		if optionName := overrideRule.CustomMessageOption(); optionName != "" {
+			number, data, err := encodeCustomOption(resolver, optionName, messageOptionsFullName, overrideRule.Value())
+			if err != nil {
+				return err
+			}
+			// Source code info for message options is not removed, as the mark sweeper
+			// only handles file and field options. Stale locations do not affect generation.
+			forEachMessage(descriptor.GetMessageType(), func(message *descriptorpb.DescriptorProto) {
+				if message.GetOptions().GetMapEntry() {
+					return
+				}
+				if message.Options == nil {
+					message.Options = &descriptorpb.MessageOptions{}
+				}
+				setCustomOption(message.Options, number, data, modifyOptions.preserveExisting)
+			})
+		}
+	}
+	return nil
+}
+
+// encodeCustomOption returns the field number and the wire encoding of the custom option
+// with the given value.
+//
+// The custom option must extend the options message with the given name. The value is
+// interpreted according to the JSON mapping of the type of the custom option.
+func encodeCustomOption(
+	resolver protoencoding.Resolver,
+	optionName string,
+	optionsFullName protoreflect.FullName,
+	value interface{},
+) (protowire.Number, []byte, error) {
+	if resolver == nil {
+		return 0, nil, fmt.Errorf("custom option (%s) cannot be resolved", optionName)
+	}
+	extensionType, err := resolver.FindExtensionByName(protoreflect.FullName(optionName))
+	if err != nil {
+		if errors.Is(err, protoregistry.NotFound) {
+			return 0, nil, fmt.Errorf("custom option (%s) not found: the file that defines it must be part of the input or its dependencies", optionName)
+		}
+		return 0, nil, err
+	}
+	extensionDescriptor := extensionType.TypeDescriptor()
+	if containingMessageFullName := extensionDescriptor.ContainingMessage().FullName(); containingMessageFullName != optionsFullName {
+		return 0, nil, fmt.Errorf("custom option (%s) extends %s, not %s", optionName, containingMessageFullName, optionsFullName)
+	}
+	optionsType, err := resolver.FindMessageByName(optionsFullName)
+	if err != nil {
+		if !errors.Is(err, protoregistry.NotFound) {
+			return 0, nil, err
+		}
+		optionsType, err = protoregistry.GlobalTypes.FindMessageByName(optionsFullName)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	jsonData, err := json.Marshal(map[string]interface{}{"[" + optionName + "]": value})
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid value for custom option (%s): %w", optionName, err)
+	}
+	options := optionsType.New().Interface()
+	if err := (protojson.UnmarshalOptions{Resolver: resolver}).Unmarshal(jsonData, options); err != nil {
+		return 0, nil, fmt.Errorf("invalid value for custom option (%s): %w", optionName, err)
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(options)
+	if err != nil {
+		return 0, nil, err
+	}
+	return extensionDescriptor.Number(), data, nil
+}
+
+// setCustomOption sets the custom option with the given number to the wire-encoded data,
+// replacing any existing value. It returns true if the options were modified.
+//
+// Custom options are stored as unknown fields of the options messages of the image.
+func setCustomOption(
+	options proto.Message,
+	number protowire.Number,
+	data []byte,
+	preserveExisting bool,
+) bool {
+	reflectOptions := options.ProtoReflect()
+	var knownFieldDescriptor protoreflect.FieldDescriptor
+	reflectOptions.Range(
+		func(fieldDescriptor protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+			if fieldDescriptor.Number() == number {
+				knownFieldDescriptor = fieldDescriptor
+				return false
+			}
+			return true
+		},
+	)
+	existing, remaining := splitUnknownFields(reflectOptions.GetUnknown(), number)
+	if preserveExisting && (knownFieldDescriptor != nil || len(existing) > 0) {
+		return false
+	}
+	if knownFieldDescriptor == nil && bytes.Equal(existing, data) {
+		// The option is already set to the same value, don't modify it.
+		return false
+	}
+	if knownFieldDescriptor != nil {
+		reflectOptions.Clear(knownFieldDescriptor)
+	}
+	reflectOptions.SetUnknown(append(remaining, data...))
+	return true
+}
+
+// splitUnknownFields splits the unknown fields into the fields with the given number
+// and the other fields.
+func splitUnknownFields(unknown protoreflect.RawFields, number protowire.Number) ([]byte, protoreflect.RawFields) {
+	var matching []byte
+	var remaining protoreflect.RawFields
+	for len(unknown) > 0 {
+		fieldNumber, _, length := protowire.ConsumeField(unknown)
+		if length < 0 {
+			// Malformed unknown fields, keep them as-is.
+			return matching, append(remaining, unknown...)
+		}
+		if fieldNumber == number {
+			matching = append(matching, unknown[:length]...)
+		} else {
+			remaining = append(remaining, unknown[:length]...)
+		}
+		unknown = unknown[length:]
+	}
+	return matching, remaining
+}
+
+func forEachMessage(messages []*descriptorpb.DescriptorProto, f func(*descriptorpb.DescriptorProto)) {
+	for _, message := range messages {
+		f(message)
+		forEachMessage(message.GetNestedType(), f)
+	}
+}
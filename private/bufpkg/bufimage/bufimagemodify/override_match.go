@@ -0,0 +1,308 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufimagemodify
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufconfig"
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/protoencoding"
+	"github.com/bufbuild/protocompile/walk"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// overrideMatcher evaluates the match conditions of override rules against image files.
+type overrideMatcher struct {
+	resolver          protoencoding.Resolver
+	pathRegexToRegexp map[string]*regexp.Regexp
+}
+
+func newOverrideMatcher(image bufimage.Image, config bufconfig.GenerateManagedConfig) (*overrideMatcher, error) {
+	pathRegexToRegexp := make(map[string]*regexp.Regexp)
+	for _, overrideRule := range config.Overrides() {
+		match := overrideRule.Match()
+		if match == nil || match.PathRegex() == "" {
+			continue
+		}
+		if _, ok := pathRegexToRegexp[match.PathRegex()]; ok {
+			continue
+		}
+		// This should never fail, since the match has been validated.
+		pathRegexp, err := regexp.Compile(match.PathRegex())
+		if err != nil {
+			return nil, err
+		}
+		pathRegexToRegexp[match.PathRegex()] = pathRegexp
+	}
+	return &overrideMatcher{
+		resolver:          image.Resolver(),
+		pathRegexToRegexp: pathRegexToRegexp,
+	}, nil
+}
+
+// configForFile returns the config with all override rules whose match conditions
+// are not satisfied by the image file removed, and with the values of all override
+// rules with value expressions computed for the image file.
+//
+// If no override rule has match conditions or value expressions, the config is
+// returned as-is.
+func (o *overrideMatcher) configForFile(
+	imageFile bufimage.ImageFile,
+	config bufconfig.GenerateManagedConfig,
+) (bufconfig.GenerateManagedConfig, error) {
+	overrideRules := config.Overrides()
+	filteredOverrideRules := make([]bufconfig.ManagedOverrideRule, 0, len(overrideRules))
+	var computedValue bool
+	for _, overrideRule := range overrideRules {
+		if match := overrideRule.Match(); match != nil {
+			matched, err := o.fileMatches(imageFile, match)
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				continue
+			}
+		}
+		if overrideRule.ValueExpression() != nil {
+			if !fileMatchConfig(imageFile, overrideRule.Path(), overrideRule.FullName()) {
+				// The value is only computed for the files the override rule applies to.
+				continue
+			}
+			var err error
+			overrideRule, err = overrideRuleWithComputedValue(imageFile, overrideRule)
+			if err != nil {
+				return nil, err
+			}
+			computedValue = true
+		}
+		filteredOverrideRules = append(filteredOverrideRules, overrideRule)
+	}
+	if len(filteredOverrideRules) == len(overrideRules) && !computedValue {
+		return config, nil
+	}
+	return bufconfig.NewGenerateManagedConfig(
+		config.Enabled(),
+		config.Disables(),
+		filteredOverrideRules,
+	), nil
+}
+
+// overrideRuleWithComputedValue returns a copy of the override rule with the value
+// computed by its value expression for the image file.
+//
+// The match conditions of the override rule are not copied, as the image file has
+// already been matched.
+func overrideRuleWithComputedValue(
+	imageFile bufimage.ImageFile,
+	overrideRule bufconfig.ManagedOverrideRule,
+) (bufconfig.ManagedOverrideRule, error) {
+	var moduleFullName string
+	if imageFile.FullName() != nil {
+		moduleFullName = imageFile.FullName().String()
+	}
+	valueExpression := overrideRule.ValueExpression()
+	value, err := valueExpression.Eval(
+		imageFile.Path(),
+		imageFile.FileDescriptorProto().GetPackage(),
+		moduleFullName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var computedOverrideRule bufconfig.ManagedOverrideRule
+	switch {
+	case overrideRule.CustomFileOption() != "":
+		computedOverrideRule, err = bufconfig.NewManagedOverrideRuleForCustomFileOption(
+			overrideRule.Path(),
+			overrideRule.FullName(),
+			overrideRule.CustomFileOption(),
+			value,
+		)
+	case overrideRule.CustomMessageOption() != "":
+		computedOverrideRule, err = bufconfig.NewManagedOverrideRuleForCustomMessageOption(
+			overrideRule.Path(),
+			overrideRule.FullName(),
+			overrideRule.CustomMessageOption(),
+			value,
+		)
+	case overrideRule.FieldOption() != bufconfig.FieldOptionUnspecified:
+		computedOverrideRule, err = bufconfig.NewManagedOverrideRuleForFieldOption(
+			overrideRule.Path(),
+			overrideRule.FullName(),
+			overrideRule.FieldName(),
+			overrideRule.FieldOption(),
+			value,
+		)
+	default:
+		computedOverrideRule, err = bufconfig.NewManagedOverrideRuleForFileOption(
+			overrideRule.Path(),
+			overrideRule.FullName(),
+			overrideRule.FileOption(),
+			value,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid value computed by value_expression %q for %s: %w", valueExpression.Expression(), imageFile.Path(), err)
+	}
+	return computedOverrideRule, nil
+}
+
+func (o *overrideMatcher) fileMatches(
+	imageFile bufimage.ImageFile,
+	match bufconfig.ManagedOverrideMatch,
+) (bool, error) {
+	if pathRegex := match.PathRegex(); pathRegex != "" && !o.pathRegexToRegexp[pathRegex].MatchString(imageFile.Path()) {
+		return false, nil
+	}
+	fileDescriptorProto := imageFile.FileDescriptorProto()
+	if optionMatch := match.FileOptionMatch(); optionMatch != nil {
+		matched, err := o.optionsMatch(fileDescriptorProto.GetOptions(), optionMatch)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	messageOptionMatch := match.MessageOptionMatch()
+	fieldOptionMatch := match.FieldOptionMatch()
+	if messageOptionMatch == nil && fieldOptionMatch == nil {
+		return true, nil
+	}
+	var messageMatched, fieldMatched bool
+	if err := walk.DescriptorProtos(
+		fileDescriptorProto,
+		func(_ protoreflect.FullName, message proto.Message) error {
+			var err error
+			switch descriptorProto := message.(type) {
+			case *descriptorpb.DescriptorProto:
+				if messageOptionMatch != nil && !messageMatched {
+					messageMatched, err = o.optionsMatch(descriptorProto.GetOptions(), messageOptionMatch)
+				}
+			case *descriptorpb.FieldDescriptorProto:
+				if fieldOptionMatch != nil && !fieldMatched {
+					fieldMatched, err = o.optionsMatch(descriptorProto.GetOptions(), fieldOptionMatch)
+				}
+			}
+			return err
+		},
+	); err != nil {
+		return false, err
+	}
+	return (messageOptionMatch == nil || messageMatched) && (fieldOptionMatch == nil || fieldMatched), nil
+}
+
+// optionsMatch returns true if the options message has the option described by
+// the option match set.
+//
+// Custom options are stored as unknown fields on the options messages of the image,
+// so the options are re-parsed with the image's resolver before being inspected.
+func (o *overrideMatcher) optionsMatch(
+	options proto.Message,
+	optionMatch bufconfig.ManagedOptionMatch,
+) (bool, error) {
+	if options == nil || !options.ProtoReflect().IsValid() {
+		return false, nil
+	}
+	resolvedOptions, err := o.resolveOptions(options)
+	if err != nil {
+		return false, err
+	}
+	var matched bool
+	resolvedOptions.ProtoReflect().Range(
+		func(fieldDescriptor protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+			if optionName(fieldDescriptor) != optionMatch.Name() {
+				return true
+			}
+			matched = optionValueMatches(fieldDescriptor, value, optionMatch.Value())
+			return false
+		},
+	)
+	return matched, nil
+}
+
+func (o *overrideMatcher) resolveOptions(options proto.Message) (proto.Message, error) {
+	if len(options.ProtoReflect().GetUnknown()) == 0 || o.resolver == nil {
+		return options, nil
+	}
+	messageType, err := o.resolver.FindMessageByName(options.ProtoReflect().Descriptor().FullName())
+	if err != nil {
+		if errors.Is(err, protoregistry.NotFound) {
+			messageType = options.ProtoReflect().Type()
+		} else {
+			return nil, err
+		}
+	}
+	data, err := proto.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	resolvedOptions := messageType.New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: o.resolver}).Unmarshal(data, resolvedOptions); err != nil {
+		return nil, fmt.Errorf("failed to resolve options of type %s: %w", options.ProtoReflect().Descriptor().FullName(), err)
+	}
+	return resolvedOptions, nil
+}
+
+// optionName returns the name of the option as it is written in a match: the
+// field name for standard options, and the parenthesized full name for custom options.
+func optionName(fieldDescriptor protoreflect.FieldDescriptor) string {
+	if fieldDescriptor.IsExtension() {
+		return "(" + string(fieldDescriptor.FullName()) + ")"
+	}
+	return string(fieldDescriptor.Name())
+}
+
+func optionValueMatches(
+	fieldDescriptor protoreflect.FieldDescriptor,
+	value protoreflect.Value,
+	expectedValue interface{},
+) bool {
+	if expectedValue == nil {
+		return true
+	}
+	expectedValueString := fmt.Sprint(expectedValue)
+	if fieldDescriptor.IsList() {
+		list := value.List()
+		for i := 0; i < list.Len(); i++ {
+			if scalarOptionValueString(fieldDescriptor, list.Get(i)) == expectedValueString {
+				return true
+			}
+		}
+		return false
+	}
+	return scalarOptionValueString(fieldDescriptor, value) == expectedValueString
+}
+
+func scalarOptionValueString(fieldDescriptor protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch fieldDescriptor.Kind() {
+	case protoreflect.EnumKind:
+		if enumValueDescriptor := fieldDescriptor.Enum().Values().ByNumber(value.Enum()); enumValueDescriptor != nil {
+			return string(enumValueDescriptor.Name())
+		}
+		return strconv.Itoa(int(value.Enum()))
+	case protoreflect.BytesKind:
+		return string(value.Bytes())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		// Message values cannot be matched against a scalar.
+		return ""
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
@@ -99,6 +99,17 @@ func WithAllowFilterByImportedType() ImageFilterOption {
 	}
 }
 
+// WithExcludeTypes returns an option for ImageFilteredByTypesWithOptions that excludes
+// the given types, or packages, and any types nested within them, from the types to filter
+// by. If no types are given to filter by, all types defined in non-import files are used,
+// less the excluded types. Excluded types are still included in the filtered image if
+// they are required by another included type.
+func WithExcludeTypes(typeNames ...string) ImageFilterOption {
+	return func(opts *imageFilterOptions) {
+		opts.excludeTypes = append(opts.excludeTypes, typeNames...)
+	}
+}
+
 // ImageFilteredByTypes returns a minimal image containing only the descriptors
 // required to define those types. The resulting contains only files in which
 // those descriptors and their transitive closure of required descriptors, with
@@ -221,6 +232,22 @@ func ImageFilteredByTypesWithOptions(image bufimage.Image, types []string, opts
 		}
 		startingPackages = append(startingPackages, pkg)
 	}
+	if len(options.excludeTypes) > 0 {
+		startingDescriptors, err = getStartingDescriptorsWithExcludeTypes(
+			image,
+			imageIndex,
+			len(types) == 0,
+			startingDescriptors,
+			startingPackages,
+			options.excludeTypes,
+		)
+		if err != nil {
+			return nil, err
+		}
+		// The packages were expanded into their elements, so that excluded types can
+		// be removed. No file is kept in its entirety.
+		startingPackages = nil
+	}
 	// Find all types to include in filtered image.
 	closure := newTransitiveClosure()
 	for _, startingPackage := range startingPackages {
@@ -369,6 +396,78 @@ func ImageFilteredByTypesWithOptions(image bufimage.Image, types []string, opts
 	return bufimage.NewImage(includedFiles)
 }
 
+// getStartingDescriptorsWithExcludeTypes returns the starting descriptors, with the
+// starting packages expanded into their elements and the excluded types removed. If
+// all is true, the elements of all non-import files are used as the starting descriptors.
+func getStartingDescriptorsWithExcludeTypes(
+	image bufimage.Image,
+	imageIndex *imageIndex,
+	all bool,
+	startingDescriptors []namedDescriptor,
+	startingPackages []*protoPackage,
+	excludeTypes []string,
+) ([]namedDescriptor, error) {
+	excludeTypeNames := make(map[string]struct{}, len(excludeTypes))
+	excludePackageNames := make(map[string]struct{}, len(excludeTypes))
+	for _, excludeType := range excludeTypes {
+		if _, ok := imageIndex.ByName[excludeType]; ok {
+			excludeTypeNames[excludeType] = struct{}{}
+			continue
+		}
+		if _, ok := imageIndex.Packages[excludeType]; ok {
+			excludePackageNames[excludeType] = struct{}{}
+			continue
+		}
+		return nil, fmt.Errorf("excluding type %q: %w", excludeType, ErrImageFilterTypeNotFound)
+	}
+	if all {
+		seenPackages := make(map[string]struct{})
+		for _, imageFile := range image.Files() {
+			if imageFile.IsImport() {
+				continue
+			}
+			pkgName := imageFile.FileDescriptorProto().GetPackage()
+			if _, ok := seenPackages[pkgName]; ok {
+				continue
+			}
+			seenPackages[pkgName] = struct{}{}
+			for _, descriptor := range imageIndex.Packages[pkgName].elements {
+				if !image.GetFile(imageIndex.ByDescriptor[descriptor].file).IsImport() {
+					startingDescriptors = append(startingDescriptors, descriptor)
+				}
+			}
+		}
+	}
+	for _, startingPackage := range startingPackages {
+		startingDescriptors = append(startingDescriptors, startingPackage.elements...)
+	}
+	isExcluded := func(descriptor namedDescriptor) bool {
+		descriptorInfo := imageIndex.ByDescriptor[descriptor]
+		if _, ok := excludePackageNames[imageIndex.Files[descriptorInfo.file].GetPackage()]; ok {
+			return true
+		}
+		// Check the type and all of its enclosing types.
+		for name := descriptorInfo.fullName; name != ""; {
+			if _, ok := excludeTypeNames[name]; ok {
+				return true
+			}
+			pos := strings.LastIndexByte(name, '.')
+			if pos == -1 {
+				break
+			}
+			name = name[:pos]
+		}
+		return false
+	}
+	filteredDescriptors := make([]namedDescriptor, 0, len(startingDescriptors))
+	for _, startingDescriptor := range startingDescriptors {
+		if !isExcluded(startingDescriptor) {
+			filteredDescriptors = append(filteredDescriptors, startingDescriptor)
+		}
+	}
+	return filteredDescriptors, nil
+}
+
 // StripSourceRetentionOptions strips any options with a retention of "source" from
 // the descriptors in the given image. The image is not mutated but instead a new
 // image is returned. The returned image may share state with the original.
@@ -981,6 +1080,7 @@ type imageFilterOptions struct {
 	includeCustomOptions   bool
 	includeKnownExtensions bool
 	allowImportedTypes     bool
+	excludeTypes           []string
 }
 
 func newImageFilterOptions() *imageFilterOptions {
@@ -137,6 +137,16 @@ func TestPackages(t *testing.T) {
 	runDiffTest(t, "testdata/packages", []string{"foo.bar.baz"}, "foo.bar.baz.txtar")
 }
 
+func TestExcludeTypes(t *testing.T) {
+	t.Parallel()
+	runDiffTest(t, "testdata/packages", []string{"foo.bar.baz"}, "foo.bar.baz-exclude-types.txtar", WithExcludeTypes("foo.bar.baz.NoOp", "foo.bar.baz.Foo.Enum"))
+	runDiffTest(t, "testdata/packages", nil, "all-exclude-packages.txtar", WithExcludeTypes("foo.bar.baz", "other"))
+	_, image, err := getImage(context.Background(), slogtestext.NewLogger(t), "testdata/packages", bufimage.WithExcludeSourceCodeInfo())
+	require.NoError(t, err)
+	_, err = ImageFilteredByTypesWithOptions(image, nil, WithExcludeTypes("foo.bar.Missing"))
+	require.ErrorIs(t, err, ErrImageFilterTypeNotFound)
+}
+
 func TestAny(t *testing.T) {
 	t.Parallel()
 	runDiffTest(t, "testdata/any", []string{"ExtendedAnySyntax"}, "c1.txtar")
@@ -131,7 +131,7 @@ func digestTypeToV1Proto(digestType bufmodule.DigestType) (modulev1.DigestType,
 func v1ProtoToDigestType(protoDigestType modulev1.DigestType) (bufmodule.DigestType, error) {
 	digestType, ok := v1ProtoDigestTypeToDigestType[protoDigestType]
 	if !ok {
-		return 0, fmt.Errorf("unknown modulev1.DigestType: %v", protoDigestType)
+		return 0, &unsupportedDigestTypeError{protoDigestType: protoDigestType.String()}
 	}
 	return digestType, nil
 }
@@ -260,7 +260,7 @@ func v1ProtoDigestToV1Beta1ProtoDigest(
 	}
 	v1beta1ProtoDigestType, ok := v1ProtoDigestTypeToV1Beta1ProtoDigestType[v1ProtoDigest.Type]
 	if !ok {
-		return nil, fmt.Errorf("unknown modulev1.DigestType: %v", v1ProtoDigest.Type)
+		return nil, &unsupportedDigestTypeError{protoDigestType: v1ProtoDigest.Type.String()}
 	}
 	return &modulev1beta1.Digest{
 		Type:  v1beta1ProtoDigestType,
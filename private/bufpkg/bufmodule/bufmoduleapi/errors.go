@@ -16,6 +16,7 @@ package bufmoduleapi
 
 import (
 	"errors"
+	"fmt"
 	"io/fs"
 
 	"connectrpc.com/connect"
@@ -49,6 +50,29 @@ func (n *notFoundError) Unwrap() error {
 	return fs.ErrNotExist
 }
 
+// unsupportedDigestTypeError represents when the registry returned a Digest with a DigestType that
+// is not supported by this version of buf.
+//
+// This happens when the registry has moved to a new DigestType by default. The v1 API does not allow
+// requesting a DigestType, so callers fall back to the v1beta1 API to request a supported DigestType.
+type unsupportedDigestTypeError struct {
+	protoDigestType string
+}
+
+// Error implements error.
+func (u *unsupportedDigestTypeError) Error() string {
+	if u == nil {
+		return ""
+	}
+	return fmt.Sprintf("unsupported digest type returned by the registry: %s", u.protoDigestType)
+}
+
+// isUnsupportedDigestTypeError returns true if the error is an unsupportedDigestTypeError.
+func isUnsupportedDigestTypeError(err error) bool {
+	unsupportedDigestTypeError := &unsupportedDigestTypeError{}
+	return errors.As(err, &unsupportedDigestTypeError)
+}
+
 // maybeNewNotFoundError will convert the error into a NotFoundError if it is a connect Error with code NotFound.
 //
 // It is assumed that the underlying connect Error contains a formatted error message including that the resource was not found.
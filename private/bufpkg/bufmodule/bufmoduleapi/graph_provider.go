@@ -229,11 +229,17 @@ func (a *graphProvider) getV1Beta1ProtoGraphForModuleKeys(
 		if err != nil {
 			return nil, err
 		}
-		return v1ProtoGraphToV1Beta1ProtoGraph(primaryRegistry, graph)
+		v1beta1ProtoGraph, err := v1ProtoGraphToV1Beta1ProtoGraph(primaryRegistry, graph)
+		if err == nil || !isUnsupportedDigestTypeError(err) {
+			return v1beta1ProtoGraph, err
+		}
+		// The registry returned a DigestType we do not support. Fall through to the v1beta1 API, which
+		// allows us to request b5 explicitly.
 	}
 
-	// Legacy federation is allowed, or we are using b4. We may have dependencies on modules from other registries, or we
-	// are using a digest type not supported by the v1 API. Fall back to the v1beta1 API.
+	// Legacy federation is allowed, we are using b4, or the v1 API returned a digest type we do not support.
+	// We may have dependencies on modules from other registries, or we are using a digest type not supported
+	// by the v1 API. Fall back to the v1beta1 API.
 
 	registryCommitIDs := slicesext.Map(moduleKeys, bufmodule.ModuleKeyToRegistryCommitID)
 	v1beta1ProtoDigestType, err := digestTypeToV1Beta1Proto(digestType)
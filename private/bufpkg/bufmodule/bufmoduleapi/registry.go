@@ -118,7 +118,10 @@ func getRegistries[T hasFullName](s []T) ([]string, error) {
 // getSingleRegistryForContentModules returns the single registry for the content modules in Upload.
 //
 // Returns error if there is more than one module.
-func getSingleRegistryForContentModules(contentModules []bufmodule.Module) (string, error) {
+func getSingleRegistryForContentModules(
+	contentModules []bufmodule.Module,
+	getRemoteFullName func(bufparse.FullName) bufparse.FullName,
+) (string, error) {
 	if len(contentModules) == 0 {
 		return "", syserror.New("requires at least one module to resolve registry")
 	}
@@ -128,7 +131,7 @@ func getSingleRegistryForContentModules(contentModules []bufmodule.Module) (stri
 		if moduleFullName == nil {
 			return "", syserror.Newf("expected module name for %s", module.Description())
 		}
-		moduleRegistry := moduleFullName.Registry()
+		moduleRegistry := getRemoteFullName(moduleFullName).Registry()
 		if registry != "" && moduleRegistry != registry {
 			// We don't allow the upload of content across multiple registries, but in the legacy federation
 			// case, we DO allow for depending on other registries.
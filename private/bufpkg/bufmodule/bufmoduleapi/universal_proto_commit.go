@@ -111,7 +111,17 @@ func getUniversalProtoCommitsForRegistryAndCommitIDs(
 		if err != nil {
 			return nil, err
 		}
-		return slicesext.MapError(v1ProtoCommits, newUniversalProtoCommitForV1)
+		universalProtoCommits, err := slicesext.MapError(v1ProtoCommits, newUniversalProtoCommitForV1)
+		if err == nil || !isUnsupportedDigestTypeError(err) {
+			return universalProtoCommits, err
+		}
+		// The registry returned a DigestType we do not support. Request b5 explicitly from the v1beta1 API.
+		v1beta1ProtoResourceRefs := commitIDsToV1Beta1ProtoResourceRefs(commitIDs)
+		v1beta1ProtoCommits, err := getV1Beta1ProtoCommitsForRegistryAndResourceRefs(ctx, moduleClientProvider, registry, v1beta1ProtoResourceRefs, digestType)
+		if err != nil {
+			return nil, err
+		}
+		return slicesext.MapError(v1beta1ProtoCommits, newUniversalProtoCommitForV1Beta1)
 	default:
 		return nil, syserror.Newf("unknown DigestType: %v", digestType)
 	}
@@ -141,7 +151,17 @@ func getUniversalProtoCommitsForRegistryAndModuleRefs(
 		if err != nil {
 			return nil, err
 		}
-		return slicesext.MapError(v1ProtoCommits, newUniversalProtoCommitForV1)
+		universalProtoCommits, err := slicesext.MapError(v1ProtoCommits, newUniversalProtoCommitForV1)
+		if err == nil || !isUnsupportedDigestTypeError(err) {
+			return universalProtoCommits, err
+		}
+		// The registry returned a DigestType we do not support. Request b5 explicitly from the v1beta1 API.
+		v1beta1ProtoResourceRefs := moduleRefsToV1Beta1ProtoResourceRefs(moduleRefs)
+		v1beta1ProtoCommits, err := getV1Beta1ProtoCommitsForRegistryAndResourceRefs(ctx, moduleClientProvider, registry, v1beta1ProtoResourceRefs, digestType)
+		if err != nil {
+			return nil, err
+		}
+		return slicesext.MapError(v1beta1ProtoCommits, newUniversalProtoCommitForV1Beta1)
 	default:
 		return nil, syserror.Newf("unknown DigestType: %v", digestType)
 	}
@@ -26,6 +26,8 @@ import (
 	ownerv1 "buf.build/gen/go/bufbuild/registry/protocolbuffers/go/buf/registry/owner/v1"
 	"connectrpc.com/connect"
 	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmodulemapping"
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
 	"github.com/bufbuild/buf/private/bufpkg/bufregistryapi/bufregistryapimodule"
 	"github.com/bufbuild/buf/private/pkg/slicesext"
 	"github.com/bufbuild/buf/private/pkg/syserror"
@@ -62,6 +64,16 @@ func UploaderWithPublicRegistry(publicRegistry string) UploaderOption {
 	}
 }
 
+// UploaderWithMapper returns a new UploaderOption that maps the names of the uploaded
+// Modules and their dependencies to their names on the registry with the given Mapper.
+//
+// The returned Commits have the names of the Modules in the ModuleSet.
+func UploaderWithMapper(mapper bufmodulemapping.Mapper) UploaderOption {
+	return func(uploader *uploader) {
+		uploader.mapper = mapper
+	}
+}
+
 // *** PRIVATE ***
 
 type uploader struct {
@@ -72,6 +84,7 @@ type uploader struct {
 		bufregistryapimodule.V1Beta1UploadServiceClientProvider
 	}
 	publicRegistry string
+	mapper         bufmodulemapping.Mapper
 }
 
 func newUploader(
@@ -148,7 +161,7 @@ func (a *uploader) Upload(
 		// Nothing to upload.
 		return nil, nil
 	}
-	primaryRegistry, err := getSingleRegistryForContentModules(contentModules)
+	primaryRegistry, err := getSingleRegistryForContentModules(contentModules, a.getRemoteFullName)
 	if err != nil {
 		return nil, err
 	}
@@ -241,7 +254,9 @@ func (a *uploader) Upload(
 				v1beta1ProtoScopedLabelRefs,
 				primaryRegistry,
 				module,
+				a.getRemoteFullName(module.FullName()),
 				uploadOptions.SourceControlURL(),
+				uploadOptions.Observer(),
 			)
 		},
 	)
@@ -256,7 +271,9 @@ func (a *uploader) Upload(
 
 	v1beta1ProtoUploadRequestDepRefs, err := slicesext.MapError(
 		remoteDeps,
-		remoteDepToV1Beta1ProtoUploadRequestDepRef,
+		func(remoteDep bufmodule.RemoteDep) (*modulev1beta1.UploadRequest_DepRef, error) {
+			return remoteDepToV1Beta1ProtoUploadRequestDepRef(remoteDep, a.getRemoteFullName(remoteDep.FullName()))
+		},
 	)
 	if err != nil {
 		return nil, err
@@ -269,7 +286,7 @@ func (a *uploader) Upload(
 			remoteDeps,
 			func(remoteDep bufmodule.RemoteDep) string {
 				// We've already validated two or three times that FullName is present here.
-				return remoteDep.FullName().Registry()
+				return a.getRemoteFullName(remoteDep.FullName()).Registry()
 			},
 		),
 	)
@@ -277,6 +294,10 @@ func (a *uploader) Upload(
 		return nil, err
 	}
 
+	uploadOptions.Observer().UploadStarted(primaryRegistry)
+	// The registry returns the existing Commit instead of creating a new Commit if the
+	// content has not changed, which we detect by the Commit being created before the upload.
+	uploadStartTime := time.Now()
 	var universalProtoCommits []*universalProtoCommit
 	if len(remoteDepRegistries) > 0 && (len(remoteDepRegistries) > 1 || remoteDepRegistries[0] != primaryRegistry) {
 		// If we have dependencies on other registries, or we have multiple registries we depend on, we have
@@ -360,6 +381,11 @@ func (a *uploader) Upload(
 				return universalProtoCommit.CreateTime, nil
 			},
 		)
+		uploadOptions.Observer().CommitReceived(
+			moduleFullName,
+			commits[i],
+			universalProtoCommit.CreateTime.Before(uploadStartTime),
+		)
 	}
 	return commits, nil
 }
@@ -375,6 +401,7 @@ func (a *uploader) createContentModuleIfNotExist(
 	if err != nil {
 		return nil, err
 	}
+	remoteFullName := a.getRemoteFullName(contentModule.FullName())
 	response, err := a.moduleClientProvider.V1ModuleServiceClient(primaryRegistry).CreateModules(
 		ctx,
 		connect.NewRequest(
@@ -383,10 +410,10 @@ func (a *uploader) createContentModuleIfNotExist(
 					{
 						OwnerRef: &ownerv1.OwnerRef{
 							Value: &ownerv1.OwnerRef_Name{
-								Name: contentModule.FullName().Owner(),
+								Name: remoteFullName.Owner(),
 							},
 						},
-						Name:             contentModule.FullName().Name(),
+						Name:             remoteFullName.Name(),
 						Visibility:       v1ProtoCreateModuleVisibility,
 						DefaultLabelName: createDefaultLabel,
 					},
@@ -427,11 +454,12 @@ func (a *uploader) validateContentModulesExist(
 				ModuleRefs: slicesext.Map(
 					contentModules,
 					func(module bufmodule.Module) *modulev1.ModuleRef {
+						remoteFullName := a.getRemoteFullName(module.FullName())
 						return &modulev1.ModuleRef{
 							Value: &modulev1.ModuleRef_Name_{
 								Name: &modulev1.ModuleRef_Name{
-									Owner:  module.FullName().Owner(),
-									Module: module.FullName().Name(),
+									Owner:  remoteFullName.Owner(),
+									Module: remoteFullName.Name(),
 								},
 							},
 						}
@@ -446,12 +474,22 @@ func (a *uploader) validateContentModulesExist(
 	return response.Msg.Modules, nil
 }
 
+// getRemoteFullName returns the FullName of the Module on the registry.
+func (a *uploader) getRemoteFullName(moduleFullName bufparse.FullName) bufparse.FullName {
+	if a.mapper == nil {
+		return moduleFullName
+	}
+	return a.mapper.RemoteFullName(moduleFullName)
+}
+
 func getV1Beta1ProtoUploadRequestContent(
 	ctx context.Context,
 	v1beta1ProtoScopedLabelRefs []*modulev1beta1.ScopedLabelRef,
 	primaryRegistry string,
 	module bufmodule.Module,
+	remoteFullName bufparse.FullName,
 	sourceControlURL string,
+	observer bufmodule.UploadObserver,
 ) (*modulev1beta1.UploadRequest_Content, error) {
 	if !module.IsLocal() {
 		return nil, syserror.New("expected local Module in getProtoLegacyFederationUploadRequestContent")
@@ -459,7 +497,7 @@ func getV1Beta1ProtoUploadRequestContent(
 	if module.FullName() == nil {
 		return nil, syserror.Newf("expected module name for local module: %s", module.Description())
 	}
-	if module.FullName().Registry() != primaryRegistry {
+	if remoteFullName.Registry() != primaryRegistry {
 		// This should never happen - the upload Modules should already be verified above to come from one registry.
 		return nil, syserror.Newf("attempting to upload content for registry other than %s in getProtoLegacyFederationUploadRequestContent", primaryRegistry)
 	}
@@ -468,13 +506,16 @@ func getV1Beta1ProtoUploadRequestContent(
 	if err != nil {
 		return nil, err
 	}
+	for _, v1beta1ProtoFile := range v1beta1ProtoFiles {
+		observer.FileRead(module.FullName(), v1beta1ProtoFile.Path, len(v1beta1ProtoFile.Content))
+	}
 
 	uploadRequestContent := &modulev1beta1.UploadRequest_Content{
 		ModuleRef: &modulev1beta1.ModuleRef{
 			Value: &modulev1beta1.ModuleRef_Name_{
 				Name: &modulev1beta1.ModuleRef_Name{
-					Owner:  module.FullName().Owner(),
-					Module: module.FullName().Name(),
+					Owner:  remoteFullName.Owner(),
+					Module: remoteFullName.Name(),
 				},
 			},
 		},
@@ -489,8 +530,9 @@ func getV1Beta1ProtoUploadRequestContent(
 
 func remoteDepToV1Beta1ProtoUploadRequestDepRef(
 	remoteDep bufmodule.RemoteDep,
+	remoteFullName bufparse.FullName,
 ) (*modulev1beta1.UploadRequest_DepRef, error) {
-	if remoteDep.FullName() == nil {
+	if remoteFullName == nil {
 		return nil, syserror.Newf("expected module name for remote module dependency %q", remoteDep.OpaqueID())
 	}
 	depCommitID := remoteDep.CommitID()
@@ -499,6 +541,6 @@ func remoteDepToV1Beta1ProtoUploadRequestDepRef(
 	}
 	return &modulev1beta1.UploadRequest_DepRef{
 		CommitId: uuidutil.ToDashless(depCommitID),
-		Registry: remoteDep.FullName().Registry(),
+		Registry: remoteFullName.Registry(),
 	}, nil
 }
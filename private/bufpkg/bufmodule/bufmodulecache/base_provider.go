@@ -19,6 +19,7 @@ import (
 	"log/slog"
 	"sync/atomic"
 
+	"github.com/bufbuild/buf/private/pkg/cachestats"
 	"github.com/bufbuild/buf/private/pkg/slicesext"
 	"github.com/bufbuild/buf/private/pkg/syserror"
 	"github.com/bufbuild/buf/private/pkg/uuidutil"
@@ -27,6 +28,7 @@ import (
 
 type baseProvider[K any, V any] struct {
 	logger                   *slog.Logger
+	cacheName                string
 	delegateGetValuesForKeys func(context.Context, []K) ([]V, error)
 	storeGetValuesForKeys    func(context.Context, []K) ([]V, []K, error)
 	storePutValues           func(context.Context, []V) error
@@ -39,6 +41,7 @@ type baseProvider[K any, V any] struct {
 
 func newBaseProvider[K any, V any](
 	logger *slog.Logger,
+	cacheName string,
 	delegateGetValuesForKeys func(context.Context, []K) ([]V, error),
 	storeGetValuesForKeys func(context.Context, []K) ([]V, []K, error),
 	storePutValues func(context.Context, []V) error,
@@ -47,6 +50,7 @@ func newBaseProvider[K any, V any](
 ) *baseProvider[K, V] {
 	return &baseProvider[K, V]{
 		logger:                   logger,
+		cacheName:                cacheName,
 		delegateGetValuesForKeys: delegateGetValuesForKeys,
 		storeGetValuesForKeys:    storeGetValuesForKeys,
 		storePutValues:           storePutValues,
@@ -101,6 +105,7 @@ func (p *baseProvider[K, V]) getValuesForKeys(ctx context.Context, keys []K) ([]
 
 	p.keysRetrieved.Add(int64(len(keys)))
 	p.keysHit.Add(int64(len(foundValues)))
+	cachestats.Record(ctx, p.cacheName, len(keys), len(foundValues))
 
 	indexedValues, err := slicesext.MapError(
 		append(foundValues, delegateValues...),
@@ -49,6 +49,7 @@ func newCommitProvider(
 	return &commitProvider{
 		byModuleKey: newBaseProvider(
 			logger,
+			"commits",
 			delegate.GetCommitsForModuleKeys,
 			store.GetCommitsForModuleKeys,
 			store.PutCommits,
@@ -59,6 +60,7 @@ func newCommitProvider(
 		),
 		byCommitKey: newBaseProvider(
 			logger,
+			"commits",
 			delegate.GetCommitsForCommitKeys,
 			store.GetCommitsForCommitKeys,
 			store.PutCommits,
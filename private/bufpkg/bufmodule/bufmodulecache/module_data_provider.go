@@ -50,6 +50,7 @@ func newModuleDataProvider(
 	return &moduleDataProvider{
 		baseProvider: newBaseProvider(
 			logger,
+			"module_datas",
 			delegate.GetModuleDatasForModuleKeys,
 			store.GetModuleDatasForModuleKeys,
 			store.PutModuleDatas,
@@ -0,0 +1,108 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufmodulemapping provides module mappings, which rewrite the names of modules
+// between the names used locally and the names used on a registry.
+//
+// Mappings allow modules to be resolved from a mirror or fork of a registry without
+// editing the buf.yaml and buf.lock files that refer to them. For example, a module
+// declared as buf.build/acme/api can be resolved, downloaded, and pushed as
+// bsr.internal/mirror/acme-api, while buf.yaml and buf.lock files continue to refer
+// to buf.build/acme/api.
+//
+// A mapping is either between two module full names, or between two registries, in
+// which case all modules of the registry are mapped. Mappings between module full names
+// take precedence over mappings between registries.
+package bufmodulemapping
+
+import (
+	"io"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
+)
+
+// Mapper maps the names of modules between the names used locally and the names used
+// on a registry.
+type Mapper interface {
+	// RemoteFullName returns the FullName on the registry for the local FullName.
+	//
+	// Returns the input FullName if it is not mapped.
+	RemoteFullName(localFullName bufparse.FullName) bufparse.FullName
+	// LocalFullName returns the local FullName for the FullName on the registry.
+	//
+	// Returns the input FullName if it is not mapped.
+	LocalFullName(remoteFullName bufparse.FullName) bufparse.FullName
+	// RemoteRegistry returns the registry for the local registry.
+	//
+	// Only mappings between registries are considered, as a registry does not identify
+	// a single module. Returns the input registry if it is not mapped.
+	RemoteRegistry(localRegistry string) string
+
+	isMapper()
+}
+
+// ReadMapper reads a Mapper from the io.Reader.
+//
+// The format is:
+//
+//	version: v1
+//	mappings:
+//	  - local: buf.build/acme/api
+//	    remote: bsr.internal/mirror/acme-api
+//	  - local: buf.build
+//	    remote: bsr.internal
+func ReadMapper(reader io.Reader) (Mapper, error) {
+	return readMapper(reader)
+}
+
+// NewModuleKeyProvider returns a new ModuleKeyProvider that maps the FullNames of the
+// ModuleRefs with the Mapper before calling the delegate, and maps the FullNames of
+// the returned ModuleKeys back.
+func NewModuleKeyProvider(
+	delegate bufmodule.ModuleKeyProvider,
+	mapper Mapper,
+) bufmodule.ModuleKeyProvider {
+	return newModuleKeyProvider(delegate, mapper)
+}
+
+// NewModuleDataProvider returns a new ModuleDataProvider that maps the FullNames of the
+// ModuleKeys with the Mapper before calling the delegate, and maps the FullNames of the
+// returned ModuleDatas and their dependencies back.
+func NewModuleDataProvider(
+	delegate bufmodule.ModuleDataProvider,
+	mapper Mapper,
+) bufmodule.ModuleDataProvider {
+	return newModuleDataProvider(delegate, mapper)
+}
+
+// NewCommitProvider returns a new CommitProvider that maps the FullNames of the ModuleKeys
+// and the registries of the CommitKeys with the Mapper before calling the delegate, and
+// maps the FullNames of the returned Commits back.
+func NewCommitProvider(
+	delegate bufmodule.CommitProvider,
+	mapper Mapper,
+) bufmodule.CommitProvider {
+	return newCommitProvider(delegate, mapper)
+}
+
+// NewGraphProvider returns a new GraphProvider that maps the FullNames of the ModuleKeys
+// with the Mapper before calling the delegate, and maps the FullNames of the ModuleKeys
+// in the returned Graph back.
+func NewGraphProvider(
+	delegate bufmodule.GraphProvider,
+	mapper Mapper,
+) bufmodule.GraphProvider {
+	return newGraphProvider(delegate, mapper)
+}
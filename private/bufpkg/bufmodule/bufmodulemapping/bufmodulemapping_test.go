@@ -0,0 +1,185 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufmodulemapping
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule/bufmoduletesting"
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+	"github.com/stretchr/testify/require"
+)
+
+const testMappings = `version: v1
+mappings:
+  - local: buf.build/acme/api
+    remote: bsr.internal/mirror/acme-api
+  - local: buf.build
+    remote: bsr.internal
+`
+
+func TestMapper(t *testing.T) {
+	t.Parallel()
+	mapper := testReadMapper(t)
+	testMapFullName := func(expected string, mapFullName func(bufparse.FullName) bufparse.FullName, fullNameString string) {
+		t.Helper()
+		fullName, err := bufparse.ParseFullName(fullNameString)
+		require.NoError(t, err)
+		require.Equal(t, expected, mapFullName(fullName).String())
+	}
+	testMapFullName("bsr.internal/mirror/acme-api", mapper.RemoteFullName, "buf.build/acme/api")
+	testMapFullName("bsr.internal/acme/common", mapper.RemoteFullName, "buf.build/acme/common")
+	testMapFullName("example.com/acme/api", mapper.RemoteFullName, "example.com/acme/api")
+	testMapFullName("buf.build/acme/api", mapper.LocalFullName, "bsr.internal/mirror/acme-api")
+	testMapFullName("buf.build/acme/common", mapper.LocalFullName, "bsr.internal/acme/common")
+	testMapFullName("buf.build/acme/common", mapper.LocalFullName, "buf.build/acme/common")
+	require.Nil(t, mapper.RemoteFullName(nil))
+	require.Equal(t, "bsr.internal", mapper.RemoteRegistry("buf.build"))
+	require.Equal(t, "example.com", mapper.RemoteRegistry("example.com"))
+}
+
+func TestReadMapperInvalid(t *testing.T) {
+	t.Parallel()
+	testReadMapperError := func(expectedErrorString string, mappings string) {
+		t.Helper()
+		_, err := ReadMapper(strings.NewReader(mappings))
+		require.ErrorContains(t, err, expectedErrorString)
+	}
+	testReadMapperError(`unknown module mappings version "v2"`, "version: v2\n")
+	testReadMapperError(
+		"must have both a local and a remote name",
+		`version: v1
+mappings:
+  - local: buf.build/acme/api
+`,
+	)
+	testReadMapperError(
+		"must be between two module names or two registries",
+		`version: v1
+mappings:
+  - local: buf.build/acme/api
+    remote: bsr.internal
+`,
+	)
+	testReadMapperError(
+		`module "buf.build/acme/api" is mapped more than once`,
+		`version: v1
+mappings:
+  - local: buf.build/acme/api
+    remote: bsr.internal/mirror/acme-api
+  - local: buf.build/acme/api
+    remote: bsr.internal/fork/acme-api
+`,
+	)
+	testReadMapperError(
+		`registry "bsr.internal" is the remote of more than one mapping`,
+		`version: v1
+mappings:
+  - local: buf.build
+    remote: bsr.internal
+  - local: example.com
+    remote: bsr.internal
+`,
+	)
+}
+
+func TestProviders(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	mapper := testReadMapper(t)
+	bsrProvider, err := bufmoduletesting.NewOmniProvider(
+		bufmoduletesting.ModuleData{
+			Name: "bsr.internal/acme/common",
+			PathToData: map[string][]byte{
+				"acme/common/v1/common.proto": []byte(`syntax = "proto3"; package acme.common.v1;`),
+			},
+		},
+		bufmoduletesting.ModuleData{
+			Name: "bsr.internal/mirror/acme-api",
+			PathToData: map[string][]byte{
+				"acme/api/v1/api.proto": []byte(`syntax = "proto3"; package acme.api.v1; import "acme/common/v1/common.proto";`),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	moduleRef, err := bufparse.NewRef("buf.build", "acme", "api", "")
+	require.NoError(t, err)
+	moduleKeys, err := NewModuleKeyProvider(bsrProvider, mapper).GetModuleKeysForModuleRefs(
+		ctx,
+		[]bufparse.Ref{moduleRef},
+		bufmodule.DigestTypeB5,
+	)
+	require.NoError(t, err)
+	require.Len(t, moduleKeys, 1)
+	require.Equal(t, "buf.build/acme/api", moduleKeys[0].FullName().String())
+
+	moduleDatas, err := NewModuleDataProvider(bsrProvider, mapper).GetModuleDatasForModuleKeys(ctx, moduleKeys)
+	require.NoError(t, err)
+	require.Len(t, moduleDatas, 1)
+	require.Equal(t, "buf.build/acme/api", moduleDatas[0].ModuleKey().FullName().String())
+	declaredDepModuleKeys, err := moduleDatas[0].DeclaredDepModuleKeys()
+	require.NoError(t, err)
+	require.Equal(t, []string{"buf.build/acme/common"}, testModuleKeysToFullNameStrings(declaredDepModuleKeys))
+	// Checks the digest of the data against the digest of the ModuleKey.
+	_, err = moduleDatas[0].Bucket()
+	require.NoError(t, err)
+
+	graph, err := NewGraphProvider(bsrProvider, mapper).GetGraphForModuleKeys(ctx, moduleKeys)
+	require.NoError(t, err)
+	var edges []string
+	require.NoError(
+		t,
+		graph.WalkEdges(
+			func(from bufmodule.ModuleKey, to bufmodule.ModuleKey) error {
+				edges = append(edges, from.FullName().String()+" -> "+to.FullName().String())
+				return nil
+			},
+		),
+	)
+	require.Equal(t, []string{"buf.build/acme/api -> buf.build/acme/common"}, edges)
+
+	commitProvider := NewCommitProvider(bsrProvider, mapper)
+	commits, err := commitProvider.GetCommitsForModuleKeys(ctx, moduleKeys)
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	require.Equal(t, "buf.build/acme/api", commits[0].ModuleKey().FullName().String())
+	commitKey, err := bufmodule.ModuleKeyToCommitKey(moduleKeys[0])
+	require.NoError(t, err)
+	require.Equal(t, "buf.build", commitKey.Registry())
+	commits, err = commitProvider.GetCommitsForCommitKeys(ctx, []bufmodule.CommitKey{commitKey})
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	require.Equal(t, "buf.build/acme/api", commits[0].ModuleKey().FullName().String())
+}
+
+func testReadMapper(t *testing.T) Mapper {
+	mapper, err := ReadMapper(strings.NewReader(testMappings))
+	require.NoError(t, err)
+	return mapper
+}
+
+func testModuleKeysToFullNameStrings(moduleKeys []bufmodule.ModuleKey) []string {
+	return slicesext.Map(
+		moduleKeys,
+		func(moduleKey bufmodule.ModuleKey) string {
+			return moduleKey.FullName().String()
+		},
+	)
+}
@@ -0,0 +1,92 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufmodulemapping
+
+import (
+	"context"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+)
+
+type commitProvider struct {
+	delegate bufmodule.CommitProvider
+	mapper   Mapper
+}
+
+func newCommitProvider(
+	delegate bufmodule.CommitProvider,
+	mapper Mapper,
+) *commitProvider {
+	return &commitProvider{
+		delegate: delegate,
+		mapper:   mapper,
+	}
+}
+
+func (p *commitProvider) GetCommitsForModuleKeys(
+	ctx context.Context,
+	moduleKeys []bufmodule.ModuleKey,
+) ([]bufmodule.Commit, error) {
+	remoteModuleKeys, err := mapModuleKeysToRemote(p.mapper, moduleKeys)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := p.delegate.GetCommitsForModuleKeys(ctx, remoteModuleKeys)
+	if err != nil {
+		return nil, err
+	}
+	return p.mapCommitsToLocal(commits)
+}
+
+func (p *commitProvider) GetCommitsForCommitKeys(
+	ctx context.Context,
+	commitKeys []bufmodule.CommitKey,
+) ([]bufmodule.Commit, error) {
+	remoteCommitKeys, err := slicesext.MapError(
+		commitKeys,
+		func(commitKey bufmodule.CommitKey) (bufmodule.CommitKey, error) {
+			remoteRegistry := p.mapper.RemoteRegistry(commitKey.Registry())
+			if remoteRegistry == commitKey.Registry() {
+				return commitKey, nil
+			}
+			return bufmodule.NewCommitKey(remoteRegistry, commitKey.CommitID(), commitKey.DigestType())
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := p.delegate.GetCommitsForCommitKeys(ctx, remoteCommitKeys)
+	if err != nil {
+		return nil, err
+	}
+	return p.mapCommitsToLocal(commits)
+}
+
+func (p *commitProvider) mapCommitsToLocal(commits []bufmodule.Commit) ([]bufmodule.Commit, error) {
+	return slicesext.MapError(
+		commits,
+		func(commit bufmodule.Commit) (bufmodule.Commit, error) {
+			localModuleKey, err := mapModuleKey(commit.ModuleKey(), p.mapper.LocalFullName)
+			if err != nil {
+				return nil, err
+			}
+			if localModuleKey == commit.ModuleKey() {
+				return commit, nil
+			}
+			return bufmodule.NewCommit(localModuleKey, commit.CreateTime), nil
+		},
+	)
+}
@@ -0,0 +1,86 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufmodulemapping
+
+import (
+	"context"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/pkg/dag"
+)
+
+type graphProvider struct {
+	delegate bufmodule.GraphProvider
+	mapper   Mapper
+}
+
+func newGraphProvider(
+	delegate bufmodule.GraphProvider,
+	mapper Mapper,
+) *graphProvider {
+	return &graphProvider{
+		delegate: delegate,
+		mapper:   mapper,
+	}
+}
+
+func (p *graphProvider) GetGraphForModuleKeys(
+	ctx context.Context,
+	moduleKeys []bufmodule.ModuleKey,
+) (*dag.Graph[bufmodule.RegistryCommitID, bufmodule.ModuleKey], error) {
+	remoteModuleKeys, err := mapModuleKeysToRemote(p.mapper, moduleKeys)
+	if err != nil {
+		return nil, err
+	}
+	remoteGraph, err := p.delegate.GetGraphForModuleKeys(ctx, remoteModuleKeys)
+	if err != nil {
+		return nil, err
+	}
+	remoteToLocalModuleKey := make(map[bufmodule.RegistryCommitID]bufmodule.ModuleKey)
+	getLocalModuleKey := func(remoteModuleKey bufmodule.ModuleKey) (bufmodule.ModuleKey, error) {
+		registryCommitID := bufmodule.ModuleKeyToRegistryCommitID(remoteModuleKey)
+		if localModuleKey, ok := remoteToLocalModuleKey[registryCommitID]; ok {
+			return localModuleKey, nil
+		}
+		localModuleKey, err := mapModuleKey(remoteModuleKey, p.mapper.LocalFullName)
+		if err != nil {
+			return nil, err
+		}
+		remoteToLocalModuleKey[registryCommitID] = localModuleKey
+		return localModuleKey, nil
+	}
+	// Nodes are walked in insertion order, so the order of the Graph is preserved.
+	localGraph := dag.NewGraph[bufmodule.RegistryCommitID, bufmodule.ModuleKey](bufmodule.ModuleKeyToRegistryCommitID)
+	if err := remoteGraph.WalkNodes(
+		func(remoteModuleKey bufmodule.ModuleKey, _ []bufmodule.ModuleKey, remoteDepModuleKeys []bufmodule.ModuleKey) error {
+			localModuleKey, err := getLocalModuleKey(remoteModuleKey)
+			if err != nil {
+				return err
+			}
+			localGraph.AddNode(localModuleKey)
+			for _, remoteDepModuleKey := range remoteDepModuleKeys {
+				localDepModuleKey, err := getLocalModuleKey(remoteDepModuleKey)
+				if err != nil {
+					return err
+				}
+				localGraph.AddEdge(localModuleKey, localDepModuleKey)
+			}
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+	return localGraph, nil
+}
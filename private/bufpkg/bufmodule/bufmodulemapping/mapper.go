@@ -0,0 +1,162 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufmodulemapping
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
+	"github.com/bufbuild/buf/private/pkg/encoding"
+	"github.com/bufbuild/buf/private/pkg/netext"
+)
+
+const mappingsVersion = "v1"
+
+type mapper struct {
+	localToRemoteFullName map[string]bufparse.FullName
+	remoteToLocalFullName map[string]bufparse.FullName
+	localToRemoteRegistry map[string]string
+	remoteToLocalRegistry map[string]string
+}
+
+func newMapper() *mapper {
+	return &mapper{
+		localToRemoteFullName: make(map[string]bufparse.FullName),
+		remoteToLocalFullName: make(map[string]bufparse.FullName),
+		localToRemoteRegistry: make(map[string]string),
+		remoteToLocalRegistry: make(map[string]string),
+	}
+}
+
+func readMapper(reader io.Reader) (*mapper, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var externalMappings externalMappingsV1
+	if err := encoding.UnmarshalYAMLStrict(data, &externalMappings); err != nil {
+		return nil, err
+	}
+	if externalMappings.Version != mappingsVersion {
+		return nil, fmt.Errorf("unknown module mappings version %q, expected %q", externalMappings.Version, mappingsVersion)
+	}
+	mapper := newMapper()
+	for _, externalMapping := range externalMappings.Mappings {
+		if err := mapper.addMapping(externalMapping.Local, externalMapping.Remote); err != nil {
+			return nil, err
+		}
+	}
+	return mapper, nil
+}
+
+func (m *mapper) RemoteFullName(localFullName bufparse.FullName) bufparse.FullName {
+	return mapFullName(localFullName, m.localToRemoteFullName, m.localToRemoteRegistry)
+}
+
+func (m *mapper) LocalFullName(remoteFullName bufparse.FullName) bufparse.FullName {
+	return mapFullName(remoteFullName, m.remoteToLocalFullName, m.remoteToLocalRegistry)
+}
+
+func (m *mapper) RemoteRegistry(localRegistry string) string {
+	if remoteRegistry, ok := m.localToRemoteRegistry[localRegistry]; ok {
+		return remoteRegistry
+	}
+	return localRegistry
+}
+
+func (*mapper) isMapper() {}
+
+func (m *mapper) addMapping(local string, remote string) error {
+	if local == "" || remote == "" {
+		return errors.New("module mappings must have both a local and a remote name")
+	}
+	localIsRegistry := !strings.Contains(local, "/")
+	remoteIsRegistry := !strings.Contains(remote, "/")
+	if localIsRegistry != remoteIsRegistry {
+		return fmt.Errorf("module mapping from %q to %q must be between two module names or two registries", local, remote)
+	}
+	if localIsRegistry {
+		for _, registry := range []string{local, remote} {
+			if _, err := netext.ValidateHostname(registry); err != nil {
+				return fmt.Errorf("registry %q is not a valid hostname: %w", registry, err)
+			}
+		}
+		if _, ok := m.localToRemoteRegistry[local]; ok {
+			return fmt.Errorf("registry %q is mapped more than once", local)
+		}
+		if _, ok := m.remoteToLocalRegistry[remote]; ok {
+			return fmt.Errorf("registry %q is the remote of more than one mapping", remote)
+		}
+		m.localToRemoteRegistry[local] = remote
+		m.remoteToLocalRegistry[remote] = local
+		return nil
+	}
+	localFullName, err := bufparse.ParseFullName(local)
+	if err != nil {
+		return err
+	}
+	remoteFullName, err := bufparse.ParseFullName(remote)
+	if err != nil {
+		return err
+	}
+	if _, ok := m.localToRemoteFullName[localFullName.String()]; ok {
+		return fmt.Errorf("module %q is mapped more than once", localFullName.String())
+	}
+	if _, ok := m.remoteToLocalFullName[remoteFullName.String()]; ok {
+		return fmt.Errorf("module %q is the remote of more than one mapping", remoteFullName.String())
+	}
+	m.localToRemoteFullName[localFullName.String()] = remoteFullName
+	m.remoteToLocalFullName[remoteFullName.String()] = localFullName
+	return nil
+}
+
+func mapFullName(
+	fullName bufparse.FullName,
+	fullNameToFullName map[string]bufparse.FullName,
+	registryToRegistry map[string]string,
+) bufparse.FullName {
+	if fullName == nil {
+		return nil
+	}
+	if mappedFullName, ok := fullNameToFullName[fullName.String()]; ok {
+		return mappedFullName
+	}
+	mappedRegistry, ok := registryToRegistry[fullName.Registry()]
+	if !ok {
+		return fullName
+	}
+	mappedFullName, err := bufparse.NewFullName(mappedRegistry, fullName.Owner(), fullName.Name())
+	if err != nil {
+		// The registry was validated when the mapping was added, and the owner
+		// and name come from a valid FullName.
+		return fullName
+	}
+	return mappedFullName
+}
+
+// externalMappingsV1 represents a v1 module mappings file.
+type externalMappingsV1 struct {
+	Version  string              `json:"version,omitempty" yaml:"version,omitempty"`
+	Mappings []externalMappingV1 `json:"mappings,omitempty" yaml:"mappings,omitempty"`
+}
+
+// externalMappingV1 represents a mapping in a v1 module mappings file.
+type externalMappingV1 struct {
+	Local  string `json:"local,omitempty" yaml:"local,omitempty"`
+	Remote string `json:"remote,omitempty" yaml:"remote,omitempty"`
+}
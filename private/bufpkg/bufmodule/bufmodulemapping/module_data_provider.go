@@ -0,0 +1,76 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufmodulemapping
+
+import (
+	"context"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+)
+
+type moduleDataProvider struct {
+	delegate bufmodule.ModuleDataProvider
+	mapper   Mapper
+}
+
+func newModuleDataProvider(
+	delegate bufmodule.ModuleDataProvider,
+	mapper Mapper,
+) *moduleDataProvider {
+	return &moduleDataProvider{
+		delegate: delegate,
+		mapper:   mapper,
+	}
+}
+
+func (p *moduleDataProvider) GetModuleDatasForModuleKeys(
+	ctx context.Context,
+	moduleKeys []bufmodule.ModuleKey,
+) ([]bufmodule.ModuleData, error) {
+	remoteModuleKeys, err := mapModuleKeysToRemote(p.mapper, moduleKeys)
+	if err != nil {
+		return nil, err
+	}
+	moduleDatas, err := p.delegate.GetModuleDatasForModuleKeys(ctx, remoteModuleKeys)
+	if err != nil {
+		return nil, err
+	}
+	return slicesext.MapError(
+		moduleDatas,
+		func(moduleData bufmodule.ModuleData) (bufmodule.ModuleData, error) {
+			localModuleKey, err := mapModuleKey(moduleData.ModuleKey(), p.mapper.LocalFullName)
+			if err != nil {
+				return nil, err
+			}
+			// The dependencies may be mapped even if the ModuleKey is not, so we always
+			// construct a new ModuleData.
+			return bufmodule.NewModuleData(
+				ctx,
+				localModuleKey,
+				moduleData.Bucket,
+				func() ([]bufmodule.ModuleKey, error) {
+					declaredDepModuleKeys, err := moduleData.DeclaredDepModuleKeys()
+					if err != nil {
+						return nil, err
+					}
+					return mapModuleKeysToLocal(p.mapper, declaredDepModuleKeys)
+				},
+				moduleData.V1Beta1OrV1BufYAMLObjectData,
+				moduleData.V1Beta1OrV1BufLockObjectData,
+			), nil
+		},
+	)
+}
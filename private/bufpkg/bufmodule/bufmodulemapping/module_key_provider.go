@@ -0,0 +1,104 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufmodulemapping
+
+import (
+	"context"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufmodule"
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
+	"github.com/bufbuild/buf/private/pkg/slicesext"
+)
+
+type moduleKeyProvider struct {
+	delegate bufmodule.ModuleKeyProvider
+	mapper   Mapper
+}
+
+func newModuleKeyProvider(
+	delegate bufmodule.ModuleKeyProvider,
+	mapper Mapper,
+) *moduleKeyProvider {
+	return &moduleKeyProvider{
+		delegate: delegate,
+		mapper:   mapper,
+	}
+}
+
+func (p *moduleKeyProvider) GetModuleKeysForModuleRefs(
+	ctx context.Context,
+	moduleRefs []bufparse.Ref,
+	digestType bufmodule.DigestType,
+) ([]bufmodule.ModuleKey, error) {
+	remoteModuleRefs, err := slicesext.MapError(
+		moduleRefs,
+		func(moduleRef bufparse.Ref) (bufparse.Ref, error) {
+			remoteFullName := p.mapper.RemoteFullName(moduleRef.FullName())
+			if remoteFullName == moduleRef.FullName() {
+				return moduleRef, nil
+			}
+			return bufparse.NewRef(
+				remoteFullName.Registry(),
+				remoteFullName.Owner(),
+				remoteFullName.Name(),
+				moduleRef.Ref(),
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	moduleKeys, err := p.delegate.GetModuleKeysForModuleRefs(ctx, remoteModuleRefs, digestType)
+	if err != nil {
+		return nil, err
+	}
+	return mapModuleKeysToLocal(p.mapper, moduleKeys)
+}
+
+// mapModuleKeysToRemote maps the FullNames of the ModuleKeys to their FullNames on the registry.
+func mapModuleKeysToRemote(mapper Mapper, moduleKeys []bufmodule.ModuleKey) ([]bufmodule.ModuleKey, error) {
+	return slicesext.MapError(
+		moduleKeys,
+		func(moduleKey bufmodule.ModuleKey) (bufmodule.ModuleKey, error) {
+			return mapModuleKey(moduleKey, mapper.RemoteFullName)
+		},
+	)
+}
+
+// mapModuleKeysToLocal maps the FullNames of the ModuleKeys to their local FullNames.
+func mapModuleKeysToLocal(mapper Mapper, moduleKeys []bufmodule.ModuleKey) ([]bufmodule.ModuleKey, error) {
+	return slicesext.MapError(
+		moduleKeys,
+		func(moduleKey bufmodule.ModuleKey) (bufmodule.ModuleKey, error) {
+			return mapModuleKey(moduleKey, mapper.LocalFullName)
+		},
+	)
+}
+
+func mapModuleKey(
+	moduleKey bufmodule.ModuleKey,
+	mapFullName func(bufparse.FullName) bufparse.FullName,
+) (bufmodule.ModuleKey, error) {
+	mappedFullName := mapFullName(moduleKey.FullName())
+	if mappedFullName == moduleKey.FullName() {
+		return moduleKey, nil
+	}
+	// Digests do not depend on the names of modules, so the Digest is unchanged.
+	return bufmodule.NewModuleKey(
+		mappedFullName,
+		moduleKey.CommitID(),
+		moduleKey.Digest,
+	)
+}
@@ -497,7 +497,7 @@ func addModuleToGraphRec(
 		return err
 	}
 	for _, directModuleDep := range directModuleDeps {
-		directDepModuleKey, err := bufmodule.ModuleToModuleKey(module, digestType)
+		directDepModuleKey, err := bufmodule.ModuleToModuleKey(directModuleDep, digestType)
 		if err != nil {
 			return err
 		}
@@ -19,6 +19,7 @@ import (
 	"sync"
 
 	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/syserror"
 )
 
 // ModuleData presents raw Module data read by ModuleKey.
@@ -161,6 +162,8 @@ func newModuleData(
 				if err != nil {
 					return err
 				}
+			default:
+				return syserror.Newf("unknown DigestType: %v", expectedDigest.Type())
 			}
 			if !DigestEqual(expectedDigest, actualDigest) {
 				return &DigestMismatchError{
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/bufbuild/buf/private/bufpkg/bufparse"
 	"github.com/bufbuild/buf/private/pkg/slicesext"
 	"github.com/bufbuild/buf/private/pkg/syserror"
 )
@@ -85,6 +86,32 @@ func UploadWithExcludeUnnamed() UploadOption {
 	}
 }
 
+// UploadWithObserver returns a new UploadOption that reports the progress of the
+// upload to the given UploadObserver.
+func UploadWithObserver(observer UploadObserver) UploadOption {
+	return func(uploadOptions *uploadOptions) {
+		uploadOptions.observer = observer
+	}
+}
+
+// UploadObserver observes the progress of an upload.
+//
+// Methods are called sequentially, in the order of the events.
+type UploadObserver interface {
+	// FileRead is called when a file of a Module is read to be uploaded.
+	//
+	// size is the size of the file in bytes.
+	FileRead(moduleFullName bufparse.FullName, path string, size int)
+	// UploadStarted is called when all files have been read, and the upload
+	// request is sent to the registry.
+	UploadStarted(registry string)
+	// CommitReceived is called for the Commit of each uploaded Module once the upload is complete.
+	//
+	// existing is true if the registry already had a Commit with the same content, in which
+	// case the existing Commit is returned instead of creating a new Commit.
+	CommitReceived(moduleFullName bufparse.FullName, commit Commit, existing bool)
+}
+
 // UploadOptions are the possible options for upload.
 //
 // This is used by Uploader implementations.
@@ -118,6 +145,10 @@ type UploadOptions interface {
 	SourceControlURL() string
 	// ExcludeUnnamed returns whether to exclude unnamed modules.
 	ExcludeUnnamed() bool
+	// Observer returns the UploadObserver to report the progress of the upload to.
+	//
+	// Never nil. If no UploadObserver was set, this is a no-op UploadObserver.
+	Observer() UploadObserver
 
 	isUploadOptions()
 }
@@ -142,6 +173,14 @@ func (nopUploader) Upload(context.Context, ModuleSet, ...UploadOption) ([]Commit
 	return nil, errors.New("unimplemented: no-op Uploader called")
 }
 
+type nopUploadObserver struct{}
+
+func (nopUploadObserver) FileRead(bufparse.FullName, string, int) {}
+
+func (nopUploadObserver) UploadStarted(string) {}
+
+func (nopUploadObserver) CommitReceived(bufparse.FullName, Commit, bool) {}
+
 type uploadOptions struct {
 	labels                 []string
 	tags                   []string
@@ -150,6 +189,7 @@ type uploadOptions struct {
 	createDefaultLabel     string
 	sourceControlURL       string
 	excludeUnnamed         bool
+	observer               UploadObserver
 }
 
 func newUploadOptions() *uploadOptions {
@@ -184,6 +224,13 @@ func (u *uploadOptions) ExcludeUnnamed() bool {
 	return u.excludeUnnamed
 }
 
+func (u *uploadOptions) Observer() UploadObserver {
+	if u.observer == nil {
+		return nopUploadObserver{}
+	}
+	return u.observer
+}
+
 func (u *uploadOptions) validate() error {
 	if u.createIfNotExist && u.createModuleVisibility == 0 {
 		return errors.New("must set a valid ModuleVisibility if CreateIfNotExist was specified")
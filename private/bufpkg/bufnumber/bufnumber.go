@@ -0,0 +1,92 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufnumber provides the number ledger, which records the field numbers
+// allocated for messages that are maintained by multiple teams.
+//
+// Numbers are allocated with buf beta numbers allocate, which assigns the next safe
+// field number of a message and records it in the checked-in ledger. Allocating a
+// number in the ledger before the field is added means that two changes that add
+// fields to the same message conflict in the ledger, instead of silently using the
+// same number.
+package bufnumber
+
+import (
+	"io"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+)
+
+// DefaultFileName is the default file name of the number ledger.
+const DefaultFileName = "buf.numbers.yaml"
+
+// Ledger is a number ledger.
+type Ledger interface {
+	// Messages returns the Records for messages, sorted by full name.
+	Messages() []Record
+
+	isLedger()
+}
+
+// Record records the field numbers allocated for a message.
+type Record interface {
+	// Message returns the fully-qualified name of the message.
+	Message() string
+	// Allocations returns the allocations, sorted by number.
+	Allocations() []Allocation
+
+	isRecord()
+}
+
+// Allocation is a field number allocated to a field.
+type Allocation struct {
+	Number int32
+	// Name is the name of the field the number is allocated to.
+	Name string
+}
+
+// NewLedger returns a new empty Ledger.
+func NewLedger() Ledger {
+	return newLedger(nil)
+}
+
+// Allocate allocates the next safe field number of the message with the given
+// fully-qualified name in the non-import files of the Image to the field with the
+// given name.
+//
+// The next safe field number is the number after the largest number that is used
+// by a field, reserved, or allocated in the Ledger, skipping extension ranges and
+// the numbers reserved for the Protobuf implementation. Lower unused numbers are
+// never allocated, as they may have been used by deleted fields that were not
+// reserved.
+//
+// Returns a new Ledger that includes the allocation, and the allocated number.
+func Allocate(
+	ledger Ledger,
+	image bufimage.Image,
+	messageName string,
+	fieldName string,
+) (Ledger, int32, error) {
+	return allocate(ledger, image, messageName, fieldName)
+}
+
+// ReadLedger reads a Ledger from the io.Reader.
+func ReadLedger(reader io.Reader) (Ledger, error) {
+	return readLedger(reader)
+}
+
+// WriteLedger writes the Ledger to the io.Writer.
+func WriteLedger(writer io.Writer, ledger Ledger) error {
+	return writeLedger(writer, ledger)
+}
@@ -0,0 +1,178 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufnumber
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestAllocate(t *testing.T) {
+	t.Parallel()
+	image := testNewImage(
+		t,
+		&descriptorpb.DescriptorProto{
+			Name: proto.String("Foo"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				testNewField("one", 1),
+				testNewField("four", 4),
+			},
+			ReservedRange: []*descriptorpb.DescriptorProto_ReservedRange{
+				{Start: proto.Int32(5), End: proto.Int32(7)},
+			},
+			ExtensionRange: []*descriptorpb.DescriptorProto_ExtensionRange{
+				{Start: proto.Int32(8), End: proto.Int32(100)},
+			},
+			NestedType: []*descriptorpb.DescriptorProto{
+				{
+					Name: proto.String("Bar"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						testNewField("one", 18999),
+					},
+				},
+			},
+		},
+	)
+	ledger, number, err := Allocate(NewLedger(), image, "a.v1.Foo", "seven")
+	require.NoError(t, err)
+	require.Equal(t, int32(7), number)
+	ledger, number, err = Allocate(ledger, image, "a.v1.Foo", "eight")
+	require.NoError(t, err)
+	require.Equal(t, int32(100), number)
+	ledger, number, err = Allocate(ledger, image, "a.v1.Foo.Bar", "two")
+	require.NoError(t, err)
+	require.Equal(t, int32(20000), number)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteLedger(buffer, ledger))
+	require.Equal(
+		t,
+		`version: v1
+messages:
+  - name: a.v1.Foo
+    allocations:
+      - number: 7
+        name: seven
+      - number: 100
+        name: eight
+  - name: a.v1.Foo.Bar
+    allocations:
+      - number: 20000
+        name: two
+`,
+		buffer.String(),
+	)
+	readLedger, err := ReadLedger(buffer)
+	require.NoError(t, err)
+	require.Equal(t, ledger, readLedger)
+
+	_, _, err = Allocate(ledger, image, "a.v1.Baz", "one")
+	require.ErrorContains(t, err, `message "a.v1.Baz" was not found in the input`)
+	_, _, err = Allocate(ledger, image, "a.v1.Foo", "four")
+	require.ErrorContains(t, err, `message "a.v1.Foo" already has a field named "four"`)
+	_, _, err = Allocate(ledger, image, "a.v1.Foo", "seven")
+	require.ErrorContains(t, err, `field "seven" of message "a.v1.Foo" is already allocated number 7`)
+}
+
+func TestAllocateNoNumbersAvailable(t *testing.T) {
+	t.Parallel()
+	image := testNewImage(
+		t,
+		&descriptorpb.DescriptorProto{
+			Name: proto.String("Foo"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				testNewField("one", 1),
+			},
+			ExtensionRange: []*descriptorpb.DescriptorProto_ExtensionRange{
+				{Start: proto.Int32(2), End: proto.Int32(maxFieldNumber + 1)},
+			},
+		},
+	)
+	_, _, err := Allocate(NewLedger(), image, "a.v1.Foo", "two")
+	require.ErrorContains(t, err, `message "a.v1.Foo" has no field numbers available after 1`)
+}
+
+func TestReadLedgerErrors(t *testing.T) {
+	t.Parallel()
+	_, err := ReadLedger(strings.NewReader(`version: v2`))
+	require.ErrorContains(t, err, `unknown number ledger version "v2"`)
+	_, err = ReadLedger(
+		strings.NewReader(`version: v1
+messages:
+  - name: a.v1.Foo
+  - name: a.v1.Foo
+`),
+	)
+	require.ErrorContains(t, err, `message "a.v1.Foo" is recorded more than once`)
+	_, err = ReadLedger(
+		strings.NewReader(`version: v1
+messages:
+  - name: a.v1.Foo
+    allocations:
+      - number: 7
+        name: seven
+      - number: 7
+        name: other
+`),
+	)
+	require.ErrorContains(t, err, `message "a.v1.Foo": number 7 is allocated to both "seven" and "other"`)
+	_, err = ReadLedger(
+		strings.NewReader(`version: v1
+messages:
+  - name: a.v1.Foo
+    allocations:
+      - number: 0
+        name: zero
+`),
+	)
+	require.ErrorContains(t, err, `message "a.v1.Foo": invalid field number 0 for "zero"`)
+}
+
+func testNewImage(t *testing.T, descriptorProto *descriptorpb.DescriptorProto) bufimage.Image {
+	imageFile, err := bufimage.NewImageFile(
+		&descriptorpb.FileDescriptorProto{
+			Name:        proto.String("a/v1/a.proto"),
+			Package:     proto.String("a.v1"),
+			Syntax:      proto.String("proto2"),
+			MessageType: []*descriptorpb.DescriptorProto{descriptorProto},
+		},
+		nil,
+		uuid.Nil,
+		"",
+		"",
+		false,
+		false,
+		nil,
+	)
+	require.NoError(t, err)
+	image, err := bufimage.NewImage([]bufimage.ImageFile{imageFile})
+	require.NoError(t, err)
+	return image
+}
+
+func testNewField(name string, number int32) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:   descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufnumber
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/encoding"
+	"github.com/bufbuild/buf/private/pkg/syserror"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	ledgerVersion = "v1"
+	// maxFieldNumber is the largest valid field number.
+	maxFieldNumber = 536870911
+	// firstImplementationReservedNumber and lastImplementationReservedNumber are the
+	// range of field numbers reserved for the Protobuf implementation.
+	firstImplementationReservedNumber = 19000
+	lastImplementationReservedNumber  = 19999
+)
+
+type ledger struct {
+	messages []Record
+}
+
+func newLedger(messageToAllocations map[string][]Allocation) *ledger {
+	records := make([]Record, 0, len(messageToAllocations))
+	for message, allocations := range messageToAllocations {
+		allocations = slices.Clone(allocations)
+		slices.SortFunc(allocations, compareAllocations)
+		records = append(
+			records,
+			&record{
+				message:     message,
+				allocations: allocations,
+			},
+		)
+	}
+	slices.SortFunc(records, func(a Record, b Record) int {
+		return strings.Compare(a.Message(), b.Message())
+	})
+	return &ledger{
+		messages: records,
+	}
+}
+
+func allocate(
+	ledger Ledger,
+	image bufimage.Image,
+	messageName string,
+	fieldName string,
+) (Ledger, int32, error) {
+	if ledger == nil {
+		return nil, 0, syserror.New("nil Ledger")
+	}
+	descriptorProto := getMessage(image, messageName)
+	if descriptorProto == nil {
+		return nil, 0, fmt.Errorf("message %q was not found in the input", messageName)
+	}
+	messageToAllocations := make(map[string][]Allocation)
+	for _, record := range ledger.Messages() {
+		messageToAllocations[record.Message()] = record.Allocations()
+	}
+	allocations := messageToAllocations[messageName]
+	var largestNumber int32
+	for _, fieldDescriptorProto := range descriptorProto.GetField() {
+		if fieldDescriptorProto.GetName() == fieldName {
+			return nil, 0, fmt.Errorf("message %q already has a field named %q", messageName, fieldName)
+		}
+		largestNumber = max(largestNumber, fieldDescriptorProto.GetNumber())
+	}
+	for _, reservedRange := range descriptorProto.GetReservedRange() {
+		// The end of a reserved range is exclusive.
+		largestNumber = max(largestNumber, reservedRange.GetEnd()-1)
+	}
+	for _, allocation := range allocations {
+		if allocation.Name == fieldName {
+			return nil, 0, fmt.Errorf("field %q of message %q is already allocated number %d", fieldName, messageName, allocation.Number)
+		}
+		largestNumber = max(largestNumber, allocation.Number)
+	}
+	number := largestNumber + 1
+	for changed := true; changed; {
+		changed = false
+		if firstImplementationReservedNumber <= number && number <= lastImplementationReservedNumber {
+			number = lastImplementationReservedNumber + 1
+			changed = true
+		}
+		for _, extensionRange := range descriptorProto.GetExtensionRange() {
+			// The end of an extension range is exclusive.
+			if extensionRange.GetStart() <= number && number < extensionRange.GetEnd() {
+				number = extensionRange.GetEnd()
+				changed = true
+			}
+		}
+	}
+	if number > maxFieldNumber {
+		return nil, 0, fmt.Errorf("message %q has no field numbers available after %d", messageName, largestNumber)
+	}
+	messageToAllocations[messageName] = append(allocations, Allocation{Number: number, Name: fieldName})
+	return newLedger(messageToAllocations), number, nil
+}
+
+func readLedger(reader io.Reader) (*ledger, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var externalLedger externalLedgerV1
+	if err := encoding.UnmarshalYAMLStrict(data, &externalLedger); err != nil {
+		return nil, err
+	}
+	if externalLedger.Version != ledgerVersion {
+		return nil, fmt.Errorf("unknown number ledger version %q, expected %q", externalLedger.Version, ledgerVersion)
+	}
+	messageToAllocations := make(map[string][]Allocation, len(externalLedger.Messages))
+	for _, externalRecord := range externalLedger.Messages {
+		if externalRecord.Name == "" {
+			return nil, fmt.Errorf("message name is required")
+		}
+		if _, ok := messageToAllocations[externalRecord.Name]; ok {
+			return nil, fmt.Errorf("message %q is recorded more than once", externalRecord.Name)
+		}
+		numberToName := make(map[int32]string, len(externalRecord.Allocations))
+		nameToNumber := make(map[string]int32, len(externalRecord.Allocations))
+		allocations := make([]Allocation, 0, len(externalRecord.Allocations))
+		for _, externalAllocation := range externalRecord.Allocations {
+			if externalAllocation.Name == "" {
+				return nil, fmt.Errorf("message %q: name is required for number %d", externalRecord.Name, externalAllocation.Number)
+			}
+			if externalAllocation.Number < 1 || externalAllocation.Number > maxFieldNumber {
+				return nil, fmt.Errorf("message %q: invalid field number %d for %q", externalRecord.Name, externalAllocation.Number, externalAllocation.Name)
+			}
+			// Changes that allocate numbers concurrently may be merged without a conflict
+			// if the allocations are not adjacent in the file.
+			if name, ok := numberToName[externalAllocation.Number]; ok {
+				return nil, fmt.Errorf("message %q: number %d is allocated to both %q and %q", externalRecord.Name, externalAllocation.Number, name, externalAllocation.Name)
+			}
+			if number, ok := nameToNumber[externalAllocation.Name]; ok {
+				return nil, fmt.Errorf("message %q: %q is allocated both number %d and %d", externalRecord.Name, externalAllocation.Name, number, externalAllocation.Number)
+			}
+			numberToName[externalAllocation.Number] = externalAllocation.Name
+			nameToNumber[externalAllocation.Name] = externalAllocation.Number
+			allocations = append(allocations, Allocation{Number: externalAllocation.Number, Name: externalAllocation.Name})
+		}
+		messageToAllocations[externalRecord.Name] = allocations
+	}
+	return newLedger(messageToAllocations), nil
+}
+
+func writeLedger(writer io.Writer, ledger Ledger) error {
+	if ledger == nil {
+		return syserror.New("nil Ledger")
+	}
+	records := ledger.Messages()
+	externalRecords := make([]externalRecordV1, 0, len(records))
+	for _, record := range records {
+		allocations := record.Allocations()
+		externalAllocations := make([]externalAllocationV1, len(allocations))
+		for i, allocation := range allocations {
+			externalAllocations[i] = externalAllocationV1{
+				Number: allocation.Number,
+				Name:   allocation.Name,
+			}
+		}
+		externalRecords = append(
+			externalRecords,
+			externalRecordV1{
+				Name:        record.Message(),
+				Allocations: externalAllocations,
+			},
+		)
+	}
+	data, err := encoding.MarshalYAML(
+		&externalLedgerV1{
+			Version:  ledgerVersion,
+			Messages: externalRecords,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
+
+func (l *ledger) Messages() []Record {
+	return slices.Clone(l.messages)
+}
+
+func (*ledger) isLedger() {}
+
+type record struct {
+	message     string
+	allocations []Allocation
+}
+
+func (r *record) Message() string {
+	return r.message
+}
+
+func (r *record) Allocations() []Allocation {
+	return slices.Clone(r.allocations)
+}
+
+func (*record) isRecord() {}
+
+// getMessage returns nil if the message is not in the non-import files of the Image.
+func getMessage(image bufimage.Image, messageName string) *descriptorpb.DescriptorProto {
+	for _, imageFile := range image.Files() {
+		if imageFile.IsImport() {
+			continue
+		}
+		fileDescriptorProto := imageFile.FileDescriptorProto()
+		prefix := fileDescriptorProto.GetPackage()
+		if prefix != "" {
+			prefix += "."
+		}
+		if descriptorProto := getMessageInMessages(prefix, fileDescriptorProto.GetMessageType(), messageName); descriptorProto != nil {
+			return descriptorProto
+		}
+	}
+	return nil
+}
+
+func getMessageInMessages(
+	prefix string,
+	descriptorProtos []*descriptorpb.DescriptorProto,
+	messageName string,
+) *descriptorpb.DescriptorProto {
+	for _, descriptorProto := range descriptorProtos {
+		fullName := prefix + descriptorProto.GetName()
+		if fullName == messageName {
+			return descriptorProto
+		}
+		if strings.HasPrefix(messageName, fullName+".") {
+			if nestedDescriptorProto := getMessageInMessages(fullName+".", descriptorProto.GetNestedType(), messageName); nestedDescriptorProto != nil {
+				return nestedDescriptorProto
+			}
+		}
+	}
+	return nil
+}
+
+func compareAllocations(a Allocation, b Allocation) int {
+	if c := cmp.Compare(a.Number, b.Number); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Name, b.Name)
+}
+
+// externalLedgerV1 represents a v1 number ledger file.
+type externalLedgerV1 struct {
+	Version  string             `json:"version,omitempty" yaml:"version,omitempty"`
+	Messages []externalRecordV1 `json:"messages,omitempty" yaml:"messages,omitempty"`
+}
+
+// externalRecordV1 represents a message in a v1 number ledger file.
+type externalRecordV1 struct {
+	Name        string                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Allocations []externalAllocationV1 `json:"allocations,omitempty" yaml:"allocations,omitempty"`
+}
+
+// externalAllocationV1 represents an allocated field number in a v1 number ledger file.
+type externalAllocationV1 struct {
+	Number int32  `json:"number" yaml:"number"`
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
+}
@@ -0,0 +1,132 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufotel generates constants for the names of services and RPCs that
+// are used by OpenTelemetry instrumentation, so that spans and metrics can be
+// referenced without hardcoding strings.
+package bufotel
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// LanguageGo is Go.
+	LanguageGo Language = iota + 1
+	// LanguageTypeScript is TypeScript.
+	LanguageTypeScript
+	// LanguagePython is Python.
+	LanguagePython
+)
+
+var (
+	// AllLanguageStrings is all language strings.
+	AllLanguageStrings = []string{
+		"go",
+		"typescript",
+		"python",
+	}
+
+	stringToLanguage = map[string]Language{
+		"go":         LanguageGo,
+		"typescript": LanguageTypeScript,
+		"python":     LanguagePython,
+	}
+	languageToString = map[Language]string{
+		LanguageGo:         "go",
+		LanguageTypeScript: "typescript",
+		LanguagePython:     "python",
+	}
+	languageToFileExtension = map[Language]string{
+		LanguageGo:         ".go",
+		LanguageTypeScript: ".ts",
+		LanguagePython:     ".py",
+	}
+)
+
+// Language is a language to generate constants for.
+type Language int
+
+// String implements fmt.Stringer.
+func (l Language) String() string {
+	s, ok := languageToString[l]
+	if !ok {
+		return fmt.Sprintf("%d", l)
+	}
+	return s
+}
+
+// ParseLanguage parses the Language.
+//
+// The empty string defaults to LanguageGo.
+func ParseLanguage(s string) (Language, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return LanguageGo, nil
+	}
+	l, ok := stringToLanguage[s]
+	if ok {
+		return l, nil
+	}
+	return 0, fmt.Errorf("unknown language: %q", s)
+}
+
+// GeneratedFilePath returns the path of the file generated for the file with the
+// given path, relative to the same root.
+//
+// For example, acme/pet/v1/pet.proto results in acme/pet/v1/pet_otel.go for LanguageGo.
+func GeneratedFilePath(filePath string, language Language) string {
+	return strings.TrimSuffix(filePath, ".proto") + "_otel" + languageToFileExtension[language]
+}
+
+// GenerateConstants generates the constants for the services in the file.
+//
+// Following the OpenTelemetry semantic conventions for RPC, the following
+// constants are generated, where the names are for LanguageGo and LanguageTypeScript,
+// and are in UPPER_SNAKE_CASE for LanguagePython:
+//
+//   - <Service>Name is the fully-qualified name of the service, which is the value
+//     of the rpc.service attribute.
+//   - <Service><Method>MethodName is the name of the method, which is the value of
+//     the rpc.method attribute.
+//   - <Service><Method>SpanName is the name of the spans for the method, which is
+//     <service>/<method>.
+//   - <Service><Method>FullMethod is the full method, which is /<service>/<method>.
+//     This is the path of requests for the method, as well as the value that gRPC
+//     interceptors are called with.
+//
+// Services and methods are in the order they are declared.
+//
+// For LanguageGo, the file must have the go_package option set.
+//
+// Returns the empty string if the file does not contain any services.
+func GenerateConstants(fileDescriptor protoreflect.FileDescriptor, language Language) (string, error) {
+	constantGroups := getConstantGroups(fileDescriptor)
+	if len(constantGroups) == 0 {
+		return "", nil
+	}
+	switch language {
+	case LanguageGo:
+		return printGo(fileDescriptor, constantGroups)
+	case LanguageTypeScript:
+		return printTypeScript(fileDescriptor.Path(), constantGroups), nil
+	case LanguagePython:
+		return printPython(fileDescriptor.Path(), constantGroups), nil
+	default:
+		return "", fmt.Errorf("unknown language: %v", language)
+	}
+}
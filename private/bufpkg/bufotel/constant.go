@@ -0,0 +1,83 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufotel
+
+import (
+	"fmt"
+
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// constant is a generated constant.
+type constant struct {
+	// name is the name of the constant in PascalCase.
+	name string
+	// value is the string value of the constant.
+	value string
+	// description is the description of the constant, which follows the name of
+	// the constant in its comment.
+	description string
+}
+
+// getConstantGroups returns the constants for the services in the file.
+//
+// There is one group for each service, containing the constant for the name of the
+// service, followed by one group for each method of the service.
+func getConstantGroups(fileDescriptor protoreflect.FileDescriptor) [][]*constant {
+	var constantGroups [][]*constant
+	services := fileDescriptor.Services()
+	for i := 0; i < services.Len(); i++ {
+		service := services.Get(i)
+		serviceName := stringutil.ToPascalCase(string(service.Name()))
+		constantGroups = append(
+			constantGroups,
+			[]*constant{
+				{
+					name:        serviceName + "Name",
+					value:       string(service.FullName()),
+					description: fmt.Sprintf("is the fully-qualified name of the %s service, which is the value of the rpc.service attribute.", service.FullName()),
+				},
+			},
+		)
+		methods := service.Methods()
+		for j := 0; j < methods.Len(); j++ {
+			method := methods.Get(j)
+			methodName := serviceName + stringutil.ToPascalCase(string(method.Name()))
+			spanName := string(service.FullName()) + "/" + string(method.Name())
+			constantGroups = append(
+				constantGroups,
+				[]*constant{
+					{
+						name:        methodName + "MethodName",
+						value:       string(method.Name()),
+						description: fmt.Sprintf("is the name of the %s RPC, which is the value of the rpc.method attribute.", method.FullName()),
+					},
+					{
+						name:        methodName + "SpanName",
+						value:       spanName,
+						description: fmt.Sprintf("is the name of the spans for the %s RPC.", method.FullName()),
+					},
+					{
+						name:        methodName + "FullMethod",
+						value:       "/" + spanName,
+						description: fmt.Sprintf("is the full method of the %s RPC, which is the path of its requests.", method.FullName()),
+					},
+				},
+			)
+		}
+	}
+	return constantGroups
+}
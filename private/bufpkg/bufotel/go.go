@@ -0,0 +1,97 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufotel
+
+import (
+	"fmt"
+	"go/format"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func printGo(fileDescriptor protoreflect.FileDescriptor, constantGroups [][]*constant) (string, error) {
+	packageName, err := getGoPackageName(fileDescriptor)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "// Code generated by protoc-gen-buf-otel. DO NOT EDIT.\n// source: %s\n\n", fileDescriptor.Path())
+	_, _ = fmt.Fprintf(&sb, "package %s\n", packageName)
+	for _, constantGroup := range constantGroups {
+		sb.WriteString("\n")
+		if len(constantGroup) == 1 {
+			printGoConstant(&sb, constantGroup[0], "")
+			continue
+		}
+		sb.WriteString("const (\n")
+		for _, constant := range constantGroup {
+			printGoConstant(&sb, constant, "\t")
+		}
+		sb.WriteString(")\n")
+	}
+	// We format the output so that the constants in blocks are aligned.
+	data, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format Go constants for %s: %w", fileDescriptor.Path(), err)
+	}
+	return string(data), nil
+}
+
+func printGoConstant(sb *strings.Builder, constant *constant, indent string) {
+	_, _ = fmt.Fprintf(sb, "%s// %s %s\n", indent, constant.name, constant.description)
+	if indent == "" {
+		sb.WriteString("const ")
+	} else {
+		sb.WriteString(indent)
+	}
+	_, _ = fmt.Fprintf(sb, "%s = %s\n", constant.name, strconv.Quote(constant.value))
+}
+
+// getGoPackageName returns the Go package name for the file, which is derived from
+// the go_package option in the same way as protoc-gen-go.
+func getGoPackageName(fileDescriptor protoreflect.FileDescriptor) (string, error) {
+	fileOptions, _ := fileDescriptor.Options().(*descriptorpb.FileOptions)
+	goPackage := fileOptions.GetGoPackage()
+	if goPackage == "" {
+		return "", fmt.Errorf("%s: go_package must be set to generate Go constants", fileDescriptor.Path())
+	}
+	if _, packageName, ok := strings.Cut(goPackage, ";"); ok {
+		return packageName, nil
+	}
+	return cleanGoPackageName(path.Base(goPackage)), nil
+}
+
+// cleanGoPackageName replaces the characters that are not valid in identifiers
+// with underscores.
+func cleanGoPackageName(packageName string) string {
+	packageName = strings.Map(
+		func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return '_'
+		},
+		packageName,
+	)
+	if r := []rune(packageName); len(r) == 0 || unicode.IsDigit(r[0]) {
+		packageName = "_" + packageName
+	}
+	return packageName
+}
@@ -0,0 +1,37 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufotel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+)
+
+func printPython(filePath string, constantGroups [][]*constant) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "# Code generated by protoc-gen-buf-otel. DO NOT EDIT.\n# source: %s\n", filePath)
+	for _, constantGroup := range constantGroups {
+		sb.WriteString("\n")
+		for _, constant := range constantGroup {
+			name := stringutil.ToUpperSnakeCase(constant.name)
+			_, _ = fmt.Fprintf(&sb, "# %s %s\n", name, constant.description)
+			_, _ = fmt.Fprintf(&sb, "%s = %s\n", name, strconv.Quote(constant.value))
+		}
+	}
+	return sb.String()
+}
@@ -39,8 +39,23 @@ func NewPluginDataProvider(
 	clientProvider interface {
 		bufregistryapiplugin.V1Beta1DownloadServiceClientProvider
 	},
+	options ...PluginDataProviderOption,
 ) bufplugin.PluginDataProvider {
-	return newPluginDataProvider(logger, clientProvider)
+	return newPluginDataProvider(logger, clientProvider, options...)
+}
+
+// PluginDataProviderOption is an option for a new PluginDataProvider.
+type PluginDataProviderOption func(*pluginDataProvider)
+
+// PluginDataProviderWithWarnOnDigestMismatch returns a new PluginDataProviderOption
+// that says to print a warning instead of returning an error when the digest of a
+// downloaded Plugin does not match the digest of its PluginKey.
+//
+// See bufplugin.PluginDataWithWarnOnDigestMismatch.
+func PluginDataProviderWithWarnOnDigestMismatch() PluginDataProviderOption {
+	return func(pluginDataProvider *pluginDataProvider) {
+		pluginDataProvider.warnOnDigestMismatch = true
+	}
 }
 
 // *** PRIVATE ***
@@ -50,6 +65,7 @@ type pluginDataProvider struct {
 	clientProvider interface {
 		bufregistryapiplugin.V1Beta1DownloadServiceClientProvider
 	}
+	warnOnDigestMismatch bool
 }
 
 func newPluginDataProvider(
@@ -57,11 +73,16 @@ func newPluginDataProvider(
 	clientProvider interface {
 		bufregistryapiplugin.V1Beta1DownloadServiceClientProvider
 	},
+	options ...PluginDataProviderOption,
 ) *pluginDataProvider {
-	return &pluginDataProvider{
+	pluginDataProvider := &pluginDataProvider{
 		logger:         logger,
 		clientProvider: clientProvider,
 	}
+	for _, option := range options {
+		option(pluginDataProvider)
+	}
+	return pluginDataProvider
 }
 
 func (p *pluginDataProvider) GetPluginDatasForPluginKeys(
@@ -142,6 +163,13 @@ func (p *pluginDataProvider) getIndexedPluginDatasForRegistryAndIndexedPluginKey
 		return nil, err
 	}
 
+	var pluginDataOptions []bufplugin.PluginDataOption
+	if p.warnOnDigestMismatch {
+		pluginDataOptions = append(
+			pluginDataOptions,
+			bufplugin.PluginDataWithWarnOnDigestMismatch(p.logger),
+		)
+	}
 	indexedPluginDatas := make([]slicesext.Indexed[bufplugin.PluginData], 0, len(indexedPluginKeys))
 	for _, pluginContent := range pluginContents {
 		commitID, err := uuid.Parse(pluginContent.Commit.Id)
@@ -170,7 +198,7 @@ func (p *pluginDataProvider) getIndexedPluginDatasForRegistryAndIndexedPluginKey
 		default:
 			return nil, fmt.Errorf("unknown CompressionType: %v", compressionType)
 		}
-		pluginData, err := bufplugin.NewPluginData(ctx, indexedPluginKey.Value, getData)
+		pluginData, err := bufplugin.NewPluginData(ctx, indexedPluginKey.Value, getData, pluginDataOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -17,6 +17,7 @@ package bufplugin
 import (
 	"bytes"
 	"context"
+	"log/slog"
 	"sync"
 
 	"github.com/bufbuild/buf/private/bufpkg/bufcas"
@@ -49,14 +50,31 @@ func NewPluginData(
 	ctx context.Context,
 	pluginKey PluginKey,
 	getData func() ([]byte, error),
+	options ...PluginDataOption,
 ) (PluginData, error) {
 	return newPluginData(
 		ctx,
 		pluginKey,
 		getData,
+		options...,
 	)
 }
 
+// PluginDataOption is an option for a new PluginData.
+type PluginDataOption func(*pluginDataOptions)
+
+// PluginDataWithWarnOnDigestMismatch returns a new PluginDataOption that says to
+// log a warning to the given logger instead of returning a *DigestMismatchError
+// when the digest of the data does not match the digest of the PluginKey.
+//
+// This disables tamper-proofing, and should only be used when the source of the
+// data is trusted.
+func PluginDataWithWarnOnDigestMismatch(logger *slog.Logger) PluginDataOption {
+	return func(pluginDataOptions *pluginDataOptions) {
+		pluginDataOptions.digestMismatchLogger = logger
+	}
+}
+
 // *** PRIVATE ***
 
 type pluginData struct {
@@ -70,7 +88,12 @@ func newPluginData(
 	ctx context.Context,
 	pluginKey PluginKey,
 	getData func() ([]byte, error),
+	options ...PluginDataOption,
 ) (*pluginData, error) {
+	pluginDataOptions := &pluginDataOptions{}
+	for _, option := range options {
+		option(pluginDataOptions)
+	}
 	pluginData := &pluginData{
 		pluginKey: pluginKey,
 		getData:   getData,
@@ -95,12 +118,17 @@ func newPluginData(
 			return err
 		}
 		if !DigestEqual(actualDigest, expectedDigest) {
-			return &DigestMismatchError{
+			digestMismatchError := &DigestMismatchError{
 				FullName:       pluginKey.FullName(),
 				CommitID:       pluginKey.CommitID(),
 				ExpectedDigest: expectedDigest,
 				ActualDigest:   actualDigest,
 			}
+			if pluginDataOptions.digestMismatchLogger != nil {
+				pluginDataOptions.digestMismatchLogger.Warn(digestMismatchError.Error())
+				return nil
+			}
+			return digestMismatchError
 		}
 		return nil
 	})
@@ -119,3 +147,7 @@ func (p *pluginData) Data() ([]byte, error) {
 }
 
 func (*pluginData) isPluginData() {}
+
+type pluginDataOptions struct {
+	digestMismatchLogger *slog.Logger
+}
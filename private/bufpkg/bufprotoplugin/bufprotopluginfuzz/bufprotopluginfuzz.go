@@ -0,0 +1,111 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufprotopluginfuzz generates adversarial CodeGeneratorRequests for
+// fuzz-testing plugins.
+//
+// Requests are generated from a seed, and the same seed always results in the
+// same request, so that failures can be reproduced. The generated files are
+// valid Protobuf, but exercise the edges of what is allowed: deeply nested
+// types, names that collide with keywords of target languages or with each
+// other once converted to another case, maximum field numbers and enum values,
+// and combinations of syntaxes and Editions features.
+package bufprotopluginfuzz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// NewRequest returns a new adversarial CodeGeneratorRequest for the seed.
+//
+// The same seed and options always result in the same request.
+func NewRequest(seed uint64, options ...NewRequestOption) (*pluginpb.CodeGeneratorRequest, error) {
+	newRequestOptions := newNewRequestOptions()
+	for _, option := range options {
+		option(newRequestOptions)
+	}
+	request := newGenerator(seed).generateRequest()
+	if newRequestOptions.parameter != "" {
+		request.Parameter = &newRequestOptions.parameter
+	}
+	if err := ValidateRequest(request); err != nil {
+		// This is a bug in the generator, as all generated requests should be valid.
+		return nil, fmt.Errorf("generated invalid request for seed %d: %w", seed, err)
+	}
+	return request, nil
+}
+
+// NewRequestOption is an option for NewRequest.
+type NewRequestOption func(*newRequestOptions)
+
+// NewRequestWithParameter returns a new NewRequestOption that sets the
+// parameter of the request.
+func NewRequestWithParameter(parameter string) NewRequestOption {
+	return func(newRequestOptions *newRequestOptions) {
+		newRequestOptions.parameter = parameter
+	}
+}
+
+// Minimize returns the smallest request that can be derived from the request
+// by removing files, declarations, and options, for which fails still
+// returns true.
+//
+// Only valid requests, as determined by ValidateRequest, are passed to fails.
+// The request is not modified. If fails returns an error, Minimize returns
+// the error.
+func Minimize(
+	ctx context.Context,
+	request *pluginpb.CodeGeneratorRequest,
+	fails func(context.Context, *pluginpb.CodeGeneratorRequest) (bool, error),
+) (*pluginpb.CodeGeneratorRequest, error) {
+	return minimize(ctx, request, fails)
+}
+
+// ValidateRequest validates that the request only contains valid files, and
+// that every file to generate is contained within the request.
+func ValidateRequest(request *pluginpb.CodeGeneratorRequest) error {
+	if len(request.GetFileToGenerate()) == 0 {
+		return errors.New("no files to generate")
+	}
+	fileNames := make(map[string]struct{}, len(request.GetProtoFile()))
+	for _, file := range request.GetProtoFile() {
+		fileNames[file.GetName()] = struct{}{}
+	}
+	for _, fileToGenerate := range request.GetFileToGenerate() {
+		if _, ok := fileNames[fileToGenerate]; !ok {
+			return fmt.Errorf("file to generate %q is not in the request", fileToGenerate)
+		}
+	}
+	for _, sourceFile := range request.GetSourceFileDescriptors() {
+		if _, ok := fileNames[sourceFile.GetName()]; !ok {
+			return fmt.Errorf("source file %q is not in the request", sourceFile.GetName())
+		}
+	}
+	_, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: request.GetProtoFile()})
+	return err
+}
+
+type newRequestOptions struct {
+	parameter string
+}
+
+func newNewRequestOptions() *newRequestOptions {
+	return &newRequestOptions{}
+}
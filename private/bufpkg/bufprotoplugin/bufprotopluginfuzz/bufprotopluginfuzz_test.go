@@ -0,0 +1,98 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufprotopluginfuzz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestNewRequestValid(t *testing.T) {
+	t.Parallel()
+	for seed := uint64(0); seed < 300; seed++ {
+		request, err := NewRequest(seed)
+		require.NoError(t, err)
+		require.NotEmpty(t, request.GetFileToGenerate())
+	}
+}
+
+func TestNewRequestDeterministic(t *testing.T) {
+	t.Parallel()
+	for seed := uint64(0); seed < 20; seed++ {
+		request1, err := NewRequest(seed, NewRequestWithParameter("foo=bar"))
+		require.NoError(t, err)
+		request2, err := NewRequest(seed, NewRequestWithParameter("foo=bar"))
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(request1, request2), "seed %d", seed)
+		assert.Equal(t, "foo=bar", request1.GetParameter())
+	}
+	request1, err := NewRequest(1)
+	require.NoError(t, err)
+	request2, err := NewRequest(2)
+	require.NoError(t, err)
+	assert.False(t, proto.Equal(request1, request2))
+}
+
+func TestMinimize(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	var request *pluginpb.CodeGeneratorRequest
+	var fieldName string
+	// Find a request with a scalar field in a top-level message of the last file
+	// to fail on.
+	for seed := uint64(0); fieldName == ""; seed++ {
+		var err error
+		request, err = NewRequest(seed)
+		require.NoError(t, err)
+		lastFile := request.GetProtoFile()[len(request.GetProtoFile())-1]
+		for _, message := range lastFile.GetMessageType() {
+			for _, field := range message.GetField() {
+				if field.GetTypeName() == "" {
+					fieldName = field.GetName()
+					break
+				}
+			}
+		}
+	}
+	fails := func(_ context.Context, request *pluginpb.CodeGeneratorRequest) (bool, error) {
+		for _, file := range request.GetProtoFile() {
+			for _, message := range file.GetMessageType() {
+				for _, field := range message.GetField() {
+					if field.GetName() == fieldName {
+						return true, nil
+					}
+				}
+			}
+		}
+		return false, nil
+	}
+	original := proto.Clone(request)
+	minimized, err := Minimize(ctx, request, fails)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(original, request), "request was modified")
+	require.NoError(t, ValidateRequest(minimized))
+	failed, err := fails(ctx, minimized)
+	require.NoError(t, err)
+	assert.True(t, failed)
+	require.Len(t, minimized.GetProtoFile(), 1)
+	require.Len(t, minimized.GetProtoFile()[0].GetMessageType(), 1)
+	assert.Len(t, minimized.GetProtoFile()[0].GetMessageType()[0].GetField(), 1)
+	assert.Equal(t, minimized.GetProtoFile(), minimized.GetSourceFileDescriptors())
+}
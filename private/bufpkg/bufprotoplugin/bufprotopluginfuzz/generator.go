@@ -0,0 +1,1280 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufprotopluginfuzz
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+const (
+	maxFieldNumber = 536870911
+	// The field numbers from 19000 to 19999 are reserved for the Protobuf implementation.
+	firstReservedFieldNumber = 19000
+	lastReservedFieldNumber  = 19999
+	// maxNestingDepth is the maximum depth of nested messages, other than for
+	// chains of nested messages.
+	maxNestingDepth = 4
+)
+
+var (
+	packages = []string{
+		"fuzz.v1",
+		"fuzz.v1test",
+		"fuzz.class.v1",
+		"fuzz.go.v1",
+		"Fuzz.V1",
+		"a.b.c.d.e.f.g.h.i.j.k.l.m.n.o.p",
+		"",
+	}
+	// rootPackageNames are the first components of all packages, which no types
+	// may be named in the root scope.
+	rootPackageNames = []string{"fuzz", "Fuzz", "a", "google"}
+	fileNames        = []string{
+		"fuzz",
+		"with-dash",
+		"UPPER",
+		"dots.in.name",
+		"class",
+		"descriptor",
+		"foo_bar",
+		"FooBar",
+		"a/b/c/d/e/f/deep",
+		"1starts_with_digit",
+	}
+	interestingFieldNumbers = []int32{
+		1, 2, 15, 16, 2047, 2048, 18999, 20000, 262143, 262144, 536870910, maxFieldNumber,
+	}
+	interestingEnumNumbers = []int32{
+		0, 1, -1, 2, math.MaxInt32, math.MinInt32, math.MaxInt32 - 1, math.MinInt32 + 1,
+	}
+	scalarTypes = []descriptorpb.FieldDescriptorProto_Type{
+		descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+		descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+		descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_INT32,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+		descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+	}
+	mapKeyTypes = []descriptorpb.FieldDescriptorProto_Type{
+		descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_INT32,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+		descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+	}
+	wellKnownTypeFiles = []protoreflect.FileDescriptor{
+		anypb.File_google_protobuf_any_proto,
+		durationpb.File_google_protobuf_duration_proto,
+		emptypb.File_google_protobuf_empty_proto,
+		structpb.File_google_protobuf_struct_proto,
+		timestamppb.File_google_protobuf_timestamp_proto,
+		wrapperspb.File_google_protobuf_wrappers_proto,
+	}
+	compilerVersions = []*pluginpb.Version{
+		nil,
+		{Major: proto.Int32(3), Minor: proto.Int32(21), Patch: proto.Int32(12)},
+		{Major: proto.Int32(5), Minor: proto.Int32(29), Patch: proto.Int32(3), Suffix: proto.String("rc1")},
+		{Major: proto.Int32(math.MaxInt32), Minor: proto.Int32(0), Patch: proto.Int32(0)},
+	}
+)
+
+type syntax int
+
+const (
+	syntaxProto2 syntax = iota + 1
+	syntaxProto3
+	syntaxEditions
+)
+
+type fileInfo struct {
+	proto  *descriptorpb.FileDescriptorProto
+	syntax syntax
+	// closedEnums is whether enums are closed by default in an Editions file.
+	closedEnums bool
+	// implicitPresence is whether fields have implicit presence by default in
+	// an Editions file.
+	implicitPresence bool
+	// wellKnownType is whether the file is a well-known type file, which is never
+	// generated.
+	wellKnownType bool
+}
+
+type messageInfo struct {
+	file     *fileInfo
+	fullName string
+	proto    *descriptorpb.DescriptorProto
+	// depth is the nesting depth of the message, starting at 0 for top-level messages.
+	depth int
+	// chainLength is the number of messages still to nest within this message, one
+	// within another.
+	chainLength     int
+	fieldNames      map[string]struct{}
+	jsonNames       map[string]struct{}
+	numbers         map[int32]struct{}
+	extensionRanges [][2]int32
+	// extensionNumbers are the numbers used by extensions of this message.
+	extensionNumbers map[int32]struct{}
+	// group is whether the message is the message of a proto2 group field.
+	group bool
+}
+
+type enumInfo struct {
+	file     *fileInfo
+	fullName string
+	proto    *descriptorpb.EnumDescriptorProto
+	closed   bool
+}
+
+type generator struct {
+	rand *rand.Rand
+	// scopes maps each scope, a package or the full name of a message, enum, or
+	// service, to the names declared within the scope.
+	scopes   map[string]map[string]struct{}
+	files    []*fileInfo
+	messages []*messageInfo
+	enums    []*enumInfo
+	counter  int
+}
+
+func newGenerator(seed uint64) *generator {
+	return &generator{
+		rand:   rand.New(rand.NewPCG(seed, 0)),
+		scopes: make(map[string]map[string]struct{}),
+	}
+}
+
+func (g *generator) generateRequest() *pluginpb.CodeGeneratorRequest {
+	for _, rootPackageName := range rootPackageNames {
+		g.declare("", rootPackageName)
+	}
+	if g.oneIn(2) {
+		g.addWellKnownTypeFiles()
+	}
+	numFiles := 1 + g.rand.IntN(4)
+	for i := 0; i < numFiles; i++ {
+		g.generateFile()
+	}
+	request := &pluginpb.CodeGeneratorRequest{
+		CompilerVersion: compilerVersions[g.rand.IntN(len(compilerVersions))],
+	}
+	var generatedFiles []*fileInfo
+	for _, file := range g.files {
+		request.ProtoFile = append(request.ProtoFile, file.proto)
+		if !file.wellKnownType {
+			generatedFiles = append(generatedFiles, file)
+		}
+	}
+	// The last file is always generated, and every other file is generated with
+	// a probability of one half.
+	for i, file := range generatedFiles {
+		if i == len(generatedFiles)-1 || g.oneIn(2) {
+			request.FileToGenerate = append(request.FileToGenerate, file.proto.GetName())
+			request.SourceFileDescriptors = append(request.SourceFileDescriptors, file.proto)
+		}
+	}
+	return request
+}
+
+func (g *generator) addWellKnownTypeFiles() {
+	for _, fileDescriptor := range wellKnownTypeFiles {
+		file := &fileInfo{
+			proto:         protodesc.ToFileDescriptorProto(fileDescriptor),
+			syntax:        syntaxProto3,
+			wellKnownType: true,
+		}
+		g.files = append(g.files, file)
+		for _, messageProto := range file.proto.GetMessageType() {
+			g.messages = append(g.messages, &messageInfo{
+				file:     file,
+				fullName: file.proto.GetPackage() + "." + messageProto.GetName(),
+				proto:    messageProto,
+			})
+		}
+		for _, enumProto := range file.proto.GetEnumType() {
+			g.enums = append(g.enums, &enumInfo{
+				file:     file,
+				fullName: file.proto.GetPackage() + "." + enumProto.GetName(),
+				proto:    enumProto,
+			})
+		}
+	}
+}
+
+func (g *generator) generateFile() {
+	packageName := packages[g.rand.IntN(len(packages))]
+	file := &fileInfo{
+		proto: &descriptorpb.FileDescriptorProto{
+			Name: proto.String(g.newFileName(packageName)),
+		},
+		syntax: syntax(1 + g.rand.IntN(3)),
+	}
+	if packageName != "" {
+		file.proto.Package = proto.String(packageName)
+	}
+	switch file.syntax {
+	case syntaxProto2:
+		// Syntax is unset for proto2 files half of the time, as is done by protoc.
+		if g.oneIn(2) {
+			file.proto.Syntax = proto.String("proto2")
+		}
+	case syntaxProto3:
+		file.proto.Syntax = proto.String("proto3")
+	case syntaxEditions:
+		file.proto.Syntax = proto.String("editions")
+		file.proto.Edition = descriptorpb.Edition_EDITION_2023.Enum()
+		file.proto.Options = &descriptorpb.FileOptions{Features: g.newFileFeatures(file)}
+	}
+	g.setFileOptions(file)
+	g.files = append(g.files, file)
+	firstMessageIndex := len(g.messages)
+	numMessages := 1 + g.rand.IntN(4)
+	for i := 0; i < numMessages; i++ {
+		messageProto := g.newMessage(file, packageName, 0, g.newChainLength())
+		file.proto.MessageType = append(file.proto.MessageType, messageProto)
+	}
+	numEnums := g.rand.IntN(3)
+	for i := 0; i < numEnums; i++ {
+		file.proto.EnumType = append(file.proto.EnumType, g.newEnum(file, packageName))
+	}
+	// Fields are added once all of the messages of the file are declared, so that
+	// fields can refer to any message of the file. The messages of groups are
+	// added to g.messages while adding fields, and are then also populated.
+	for i := firstMessageIndex; i < len(g.messages); i++ {
+		g.populateMessage(g.messages[i])
+	}
+	if file.syntax != syntaxProto3 && !file.implicitPresence {
+		numExtensions := g.rand.IntN(4)
+		for i := 0; i < numExtensions; i++ {
+			g.addExtension(file, packageName)
+		}
+	}
+	numServices := g.rand.IntN(3)
+	for i := 0; i < numServices; i++ {
+		file.proto.Service = append(file.proto.Service, g.newService(file, packageName))
+	}
+	// Add an unused import of a previous file.
+	if len(g.files) > 1 && g.oneIn(6) {
+		g.addDependency(file, g.files[g.rand.IntN(len(g.files)-1)])
+	}
+	if len(file.proto.GetDependency()) > 0 && g.oneIn(6) {
+		file.proto.PublicDependency = []int32{int32(g.rand.IntN(len(file.proto.GetDependency())))}
+	}
+}
+
+func (g *generator) newFileName(packageName string) string {
+	dirPath := "fuzz"
+	if packageName != "" {
+		dirPath = strings.ReplaceAll(packageName, ".", "/")
+	}
+	for {
+		fileName := dirPath + "/" + fileNames[g.rand.IntN(len(fileNames))] + ".proto"
+		if g.declare("files", fileName) {
+			return fileName
+		}
+		dirPath += "/" + fmt.Sprintf("v%d", g.nextCounter())
+	}
+}
+
+func (g *generator) newFileFeatures(file *fileInfo) *descriptorpb.FeatureSet {
+	features := &descriptorpb.FeatureSet{}
+	if g.oneIn(3) {
+		file.implicitPresence = true
+		features.FieldPresence = descriptorpb.FeatureSet_IMPLICIT.Enum()
+	} else if g.oneIn(3) {
+		features.FieldPresence = descriptorpb.FeatureSet_EXPLICIT.Enum()
+	}
+	if g.oneIn(3) {
+		file.closedEnums = true
+		features.EnumType = descriptorpb.FeatureSet_CLOSED.Enum()
+	}
+	if g.oneIn(3) {
+		features.RepeatedFieldEncoding = descriptorpb.FeatureSet_EXPANDED.Enum()
+	}
+	if g.oneIn(3) {
+		features.Utf8Validation = descriptorpb.FeatureSet_NONE.Enum()
+	}
+	if g.oneIn(3) {
+		features.JsonFormat = descriptorpb.FeatureSet_LEGACY_BEST_EFFORT.Enum()
+	}
+	return features
+}
+
+func (g *generator) setFileOptions(file *fileInfo) {
+	options := file.proto.GetOptions()
+	if options == nil {
+		options = &descriptorpb.FileOptions{}
+	}
+	if g.oneIn(2) {
+		options.GoPackage = proto.String("example.com/" + strings.TrimSuffix(file.proto.GetName(), ".proto") + ";" + g.newName())
+	}
+	if g.oneIn(4) {
+		options.JavaPackage = proto.String("com." + g.newName())
+	}
+	if g.oneIn(4) {
+		options.JavaMultipleFiles = proto.Bool(g.oneIn(2))
+	}
+	if g.oneIn(4) {
+		options.JavaOuterClassname = proto.String(toUpperCamelCase(g.newName()) + "X")
+	}
+	if g.oneIn(6) {
+		options.OptimizeFor = descriptorpb.FileOptions_CODE_SIZE.Enum()
+	}
+	if g.oneIn(6) {
+		options.CsharpNamespace = proto.String(toUpperCamelCase(g.newName()) + "X")
+	}
+	if g.oneIn(6) {
+		options.ObjcClassPrefix = proto.String("FZ")
+	}
+	if g.oneIn(6) {
+		options.PhpNamespace = proto.String("Fuzz\\" + toUpperCamelCase(g.newName()) + "X")
+	}
+	if g.oneIn(6) {
+		options.SwiftPrefix = proto.String("FZ")
+	}
+	if g.oneIn(10) {
+		options.Deprecated = proto.Bool(true)
+	}
+	if proto.Size(options) > 0 {
+		file.proto.Options = options
+	}
+}
+
+// newMessage declares a new message and all of the messages and enums nested
+// within it.
+//
+// Fields are added by populateMessage.
+func (g *generator) newMessage(file *fileInfo, scope string, depth int, chainLength int) *descriptorpb.DescriptorProto {
+	name := g.newDeclarationName(scope)
+	message := &messageInfo{
+		file:        file,
+		fullName:    joinName(scope, name),
+		proto:       &descriptorpb.DescriptorProto{Name: proto.String(name)},
+		depth:       depth,
+		chainLength: chainLength,
+	}
+	g.messages = append(g.messages, message)
+	if chainLength > 0 {
+		// Name the nested message the same as its parent some of the time.
+		message.proto.NestedType = append(message.proto.NestedType, g.newMessage(file, message.fullName, depth+1, chainLength-1))
+	} else if depth < maxNestingDepth && g.oneIn(3) {
+		numNestedMessages := 1 + g.rand.IntN(2)
+		for i := 0; i < numNestedMessages; i++ {
+			message.proto.NestedType = append(message.proto.NestedType, g.newMessage(file, message.fullName, depth+1, 0))
+		}
+	}
+	if g.oneIn(4) {
+		message.proto.EnumType = append(message.proto.EnumType, g.newEnum(file, message.fullName))
+	}
+	if file.syntax != syntaxProto3 && g.oneIn(4) {
+		switch g.rand.IntN(3) {
+		case 0:
+			message.extensionRanges = [][2]int32{{100, 200}}
+		case 1:
+			message.extensionRanges = [][2]int32{{1000, maxFieldNumber + 1}}
+		default:
+			message.extensionRanges = [][2]int32{{3, 4}, {maxFieldNumber - 1000, maxFieldNumber + 1}}
+		}
+		for _, extensionRange := range message.extensionRanges {
+			message.proto.ExtensionRange = append(
+				message.proto.ExtensionRange,
+				&descriptorpb.DescriptorProto_ExtensionRange{
+					Start: proto.Int32(extensionRange[0]),
+					End:   proto.Int32(extensionRange[1]),
+				},
+			)
+		}
+	}
+	if g.oneIn(10) {
+		message.proto.Options = &descriptorpb.MessageOptions{Deprecated: proto.Bool(true)}
+	}
+	return message.proto
+}
+
+func (g *generator) newChainLength() int {
+	if g.oneIn(10) {
+		return 8 + g.rand.IntN(25)
+	}
+	return 0
+}
+
+// populateMessage adds the fields, oneofs, and reserved ranges and names to the message.
+func (g *generator) populateMessage(message *messageInfo) {
+	message.fieldNames = make(map[string]struct{})
+	message.jsonNames = make(map[string]struct{})
+	message.numbers = make(map[int32]struct{})
+	var numFields int
+	switch {
+	case g.oneIn(10):
+		numFields = 0
+	case g.oneIn(10):
+		numFields = 50 + g.rand.IntN(250)
+	default:
+		numFields = 1 + g.rand.IntN(8)
+	}
+	var syntheticOneofFields []*descriptorpb.FieldDescriptorProto
+	for len(message.proto.GetField()) < numFields {
+		if !message.group && g.oneIn(6) {
+			// Add a oneof with its fields, which must be declared consecutively.
+			oneofIndex := int32(len(message.proto.GetOneofDecl()))
+			message.proto.OneofDecl = append(
+				message.proto.OneofDecl,
+				&descriptorpb.OneofDescriptorProto{Name: proto.String(g.newScopedName(message.fullName))},
+			)
+			numOneofFields := 1 + g.rand.IntN(3)
+			for i := 0; i < numOneofFields; i++ {
+				field := g.newField(message, true)
+				field.OneofIndex = proto.Int32(oneofIndex)
+				message.proto.Field = append(message.proto.Field, field)
+			}
+			continue
+		}
+		field := g.newField(message, false)
+		if field.GetProto3Optional() {
+			syntheticOneofFields = append(syntheticOneofFields, field)
+		}
+		message.proto.Field = append(message.proto.Field, field)
+	}
+	// Synthetic oneofs must be declared after all other oneofs, and are named as
+	// done by protoc.
+	for _, field := range syntheticOneofFields {
+		oneofName := "_" + field.GetName()
+		for !g.declare(message.fullName, oneofName) {
+			oneofName = "X" + oneofName
+		}
+		field.OneofIndex = proto.Int32(int32(len(message.proto.GetOneofDecl())))
+		message.proto.OneofDecl = append(message.proto.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(oneofName)})
+	}
+	if g.oneIn(4) {
+		numReservedRanges := 1 + g.rand.IntN(3)
+		for i := 0; i < numReservedRanges; i++ {
+			if number, ok := g.newFieldNumber(message); ok {
+				message.proto.ReservedRange = append(
+					message.proto.ReservedRange,
+					&descriptorpb.DescriptorProto_ReservedRange{
+						Start: proto.Int32(number),
+						End:   proto.Int32(number + 1),
+					},
+				)
+			}
+		}
+	}
+	if g.oneIn(4) {
+		for _, name := range []string{g.newName(), g.newName()} {
+			if _, ok := message.fieldNames[name]; !ok && !containsString(message.proto.GetReservedName(), name) {
+				message.proto.ReservedName = append(message.proto.ReservedName, name)
+			}
+		}
+	}
+}
+
+// newField returns a new field of the message.
+func (g *generator) newField(message *messageInfo, inOneof bool) *descriptorpb.FieldDescriptorProto {
+	file := message.file
+	field := &descriptorpb.FieldDescriptorProto{
+		Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	for {
+		name := g.newName()
+		jsonName := jsonCamelCase(name)
+		if jsonName == "" {
+			continue
+		}
+		if _, ok := message.jsonNames[strings.ToLower(jsonName)]; ok {
+			continue
+		}
+		if !g.declare(message.fullName, name) {
+			continue
+		}
+		message.fieldNames[name] = struct{}{}
+		message.jsonNames[strings.ToLower(jsonName)] = struct{}{}
+		field.Name = proto.String(name)
+		field.JsonName = proto.String(jsonName)
+		break
+	}
+	number, ok := g.newFieldNumber(message)
+	if !ok {
+		// All field numbers are used, which will not happen in practice.
+		panic(fmt.Sprintf("no field numbers left in message %s", message.fullName))
+	}
+	message.numbers[number] = struct{}{}
+	field.Number = proto.Int32(number)
+	canRepeat := !inOneof
+	switch r := g.rand.IntN(12); {
+	case r == 0 && canRepeat && message.depth < maxNestingDepth+1:
+		g.setMapType(message, field)
+		return field
+	case r == 1 && file.syntax == syntaxProto2 && canRepeat && message.depth < maxNestingDepth+1:
+		g.setGroupType(message, field)
+	case r < 4:
+		if !g.setMessageType(file, field) {
+			g.setScalarType(field)
+		}
+	case r < 6:
+		if !g.setEnumType(file, field, !inOneof && file.syntax == syntaxEditions && file.implicitPresence) {
+			g.setScalarType(field)
+		}
+	default:
+		g.setScalarType(field)
+	}
+	if canRepeat && g.oneIn(4) {
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	}
+	g.setFieldPresence(file, field, inOneof)
+	g.setFieldOptions(file, field)
+	if !isImplicitPresence(file, field, inOneof) && g.oneIn(3) {
+		g.setDefaultValue(file, field)
+	}
+	if file.syntax != syntaxEditions && g.oneIn(10) {
+		// Custom JSON names are unique within the message, like default JSON names.
+		jsonName := "json_" + field.GetName() + fmt.Sprintf("_%d", g.nextCounter())
+		if _, ok := message.jsonNames[strings.ToLower(jsonName)]; !ok {
+			message.jsonNames[strings.ToLower(jsonName)] = struct{}{}
+			field.JsonName = proto.String(jsonName)
+		}
+	}
+	return field
+}
+
+func (g *generator) setScalarType(field *descriptorpb.FieldDescriptorProto) {
+	field.Type = scalarTypes[g.rand.IntN(len(scalarTypes))].Enum()
+}
+
+// setMessageType sets the type of the field to a message that can be referred to
+// from the file, returning false if there is no such message.
+func (g *generator) setMessageType(file *fileInfo, field *descriptorpb.FieldDescriptorProto) bool {
+	message := g.pickMessage(file)
+	if message == nil {
+		return false
+	}
+	field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	field.TypeName = proto.String("." + message.fullName)
+	g.addDependency(file, message.file)
+	return true
+}
+
+// setEnumType sets the type of the field to an enum that can be referred to from
+// the file, returning false if there is no such enum.
+//
+// If implicitPresence is set, the field is in an Editions file with implicit
+// presence by default, and closed enums require the field to have explicit
+// presence.
+func (g *generator) setEnumType(file *fileInfo, field *descriptorpb.FieldDescriptorProto, implicitPresence bool) bool {
+	enum := g.pickEnum(file, func(enum *enumInfo) bool {
+		switch file.syntax {
+		case syntaxProto2:
+			return enum.closed
+		case syntaxProto3:
+			return !enum.closed
+		default:
+			return true
+		}
+	})
+	if enum == nil {
+		return false
+	}
+	field.Type = descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum()
+	field.TypeName = proto.String("." + enum.fullName)
+	g.addDependency(file, enum.file)
+	if implicitPresence && enum.closed {
+		getFieldFeatures(field).FieldPresence = descriptorpb.FeatureSet_EXPLICIT.Enum()
+	}
+	return true
+}
+
+// setMapType makes the field a map field, adding its map entry message to the message.
+func (g *generator) setMapType(message *messageInfo, field *descriptorpb.FieldDescriptorProto) {
+	file := message.file
+	entryName := toUpperCamelCase(field.GetName()) + "Entry"
+	if !isLetter(entryName[0]) || !g.declare(message.fullName, entryName) {
+		// The name of the map entry is not a valid identifier, or conflicts with
+		// another name, so fall back to a repeated string field.
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+		return
+	}
+	keyField := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("key"),
+		JsonName: proto.String("key"),
+		Number:   proto.Int32(1),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     mapKeyTypes[g.rand.IntN(len(mapKeyTypes))].Enum(),
+	}
+	valueField := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("value"),
+		JsonName: proto.String("value"),
+		Number:   proto.Int32(2),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	switch g.rand.IntN(3) {
+	case 0:
+		if !g.setMessageType(file, valueField) {
+			g.setScalarType(valueField)
+		}
+	case 1:
+		// The first value of the enum of a map value must be zero.
+		enum := g.pickEnum(file, func(enum *enumInfo) bool {
+			if enum.proto.GetValue()[0].GetNumber() != 0 {
+				return false
+			}
+			switch file.syntax {
+			case syntaxProto2:
+				return enum.closed
+			case syntaxProto3:
+				return !enum.closed
+			default:
+				// Map values have implicit presence if the file does, and fields with
+				// implicit presence may only use open enums.
+				return !file.implicitPresence || !enum.closed
+			}
+		})
+		if enum != nil {
+			valueField.Type = descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum()
+			valueField.TypeName = proto.String("." + enum.fullName)
+			g.addDependency(file, enum.file)
+		} else {
+			g.setScalarType(valueField)
+		}
+	default:
+		g.setScalarType(valueField)
+	}
+	message.proto.NestedType = append(
+		message.proto.NestedType,
+		&descriptorpb.DescriptorProto{
+			Name:    proto.String(entryName),
+			Field:   []*descriptorpb.FieldDescriptorProto{keyField, valueField},
+			Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+		},
+	)
+	field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	field.TypeName = proto.String("." + joinName(message.fullName, entryName))
+}
+
+// setGroupType makes the field a proto2 group field, adding the message of the
+// group to the message.
+//
+// The name of the field is the lowercased name of the message of the group. As
+// the name of the field is already set, the message is named after the field.
+func (g *generator) setGroupType(message *messageInfo, field *descriptorpb.FieldDescriptorProto) {
+	groupName := strings.ToUpper(field.GetName()[:1]) + field.GetName()[1:]
+	if groupName == field.GetName() || strings.ToLower(groupName) != field.GetName() || !g.declare(message.fullName, groupName) {
+		// The field name is not lowercase, or the message name conflicts with
+		// another name, so fall back to a bytes field.
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_BYTES.Enum()
+		return
+	}
+	groupMessage := &messageInfo{
+		file:     message.file,
+		fullName: joinName(message.fullName, groupName),
+		proto:    &descriptorpb.DescriptorProto{Name: proto.String(groupName)},
+		depth:    message.depth + 1,
+		group:    true,
+	}
+	// The message is populated by generateFile.
+	g.messages = append(g.messages, groupMessage)
+	message.proto.NestedType = append(message.proto.NestedType, groupMessage.proto)
+	field.Type = descriptorpb.FieldDescriptorProto_TYPE_GROUP.Enum()
+	field.TypeName = proto.String("." + groupMessage.fullName)
+}
+
+// setFieldPresence sets the presence of the field, for proto2 and proto3 by the
+// label, and for Editions by features.
+func (g *generator) setFieldPresence(file *fileInfo, field *descriptorpb.FieldDescriptorProto, inOneof bool) {
+	if inOneof || field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return
+	}
+	isMessage := field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE ||
+		field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_GROUP
+	switch file.syntax {
+	case syntaxProto2:
+		if g.oneIn(12) {
+			field.Label = descriptorpb.FieldDescriptorProto_LABEL_REQUIRED.Enum()
+		}
+	case syntaxProto3:
+		if g.oneIn(3) {
+			field.Proto3Optional = proto.Bool(true)
+		}
+	case syntaxEditions:
+		if field.GetOptions().GetFeatures().GetFieldPresence() != descriptorpb.FeatureSet_FIELD_PRESENCE_UNKNOWN {
+			// Already set to explicit presence for a closed enum.
+			return
+		}
+		switch g.rand.IntN(8) {
+		case 0:
+			getFieldFeatures(field).FieldPresence = descriptorpb.FeatureSet_LEGACY_REQUIRED.Enum()
+		case 1:
+			if !isMessage {
+				getFieldFeatures(field).FieldPresence = descriptorpb.FeatureSet_EXPLICIT.Enum()
+			}
+		case 2:
+			if !isMessage && !g.isClosedEnumField(field) {
+				getFieldFeatures(field).FieldPresence = descriptorpb.FeatureSet_IMPLICIT.Enum()
+			}
+		}
+	}
+}
+
+// setFieldOptions sets options and features on the field that depend on its type.
+func (g *generator) setFieldOptions(file *fileInfo, field *descriptorpb.FieldDescriptorProto) {
+	repeated := field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+		if file.syntax == syntaxEditions && g.oneIn(4) {
+			getFieldFeatures(field).MessageEncoding = descriptorpb.FeatureSet_DELIMITED.Enum()
+		} else if g.oneIn(10) {
+			getFieldOptions(field).Lazy = proto.Bool(true)
+		}
+	case descriptorpb.FieldDescriptorProto_TYPE_GROUP, descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		if file.syntax == syntaxEditions && g.oneIn(4) {
+			getFieldFeatures(field).Utf8Validation = descriptorpb.FeatureSet_NONE.Enum()
+		}
+	default:
+		if repeated && g.oneIn(3) {
+			if file.syntax == syntaxEditions {
+				getFieldFeatures(field).RepeatedFieldEncoding = descriptorpb.FeatureSet_EXPANDED.Enum()
+			} else {
+				getFieldOptions(field).Packed = proto.Bool(g.oneIn(2))
+			}
+		}
+		switch field.GetType() {
+		case descriptorpb.FieldDescriptorProto_TYPE_INT64, descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+			descriptorpb.FieldDescriptorProto_TYPE_SINT64, descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+			descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+			if g.oneIn(6) {
+				getFieldOptions(field).Jstype = descriptorpb.FieldOptions_JS_STRING.Enum()
+			}
+		}
+	}
+	if g.oneIn(10) {
+		getFieldOptions(field).Deprecated = proto.Bool(true)
+	}
+}
+
+// setDefaultValue sets a default value for a singular scalar or enum field, if the
+// field can have one.
+func (g *generator) setDefaultValue(file *fileInfo, field *descriptorpb.FieldDescriptorProto) {
+	if file.syntax == syntaxProto3 || field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return
+	}
+	var defaultValues []string
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, descriptorpb.FieldDescriptorProto_TYPE_FLOAT:
+		defaultValues = []string{"inf", "-inf", "nan", "-0", "1e-45", "3.4028235e+38"}
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32, descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32:
+		defaultValues = []string{"-2147483648", "2147483647", "0"}
+	case descriptorpb.FieldDescriptorProto_TYPE_INT64, descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		defaultValues = []string{"-9223372036854775808", "9223372036854775807"}
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT32, descriptorpb.FieldDescriptorProto_TYPE_FIXED32:
+		defaultValues = []string{"4294967295", "0"}
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT64, descriptorpb.FieldDescriptorProto_TYPE_FIXED64:
+		defaultValues = []string{"18446744073709551615", "0"}
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		defaultValues = []string{"true", "false"}
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		defaultValues = []string{
+			"",
+			`quote " backslash \ newline` + "\n" + `tab` + "\t" + `end`,
+			"*/ /* // <?php ?> ${x} #{x} %s %d {{x}}",
+			"日本語 🎉 ​‮",
+			strings.Repeat("long ", 1000),
+		}
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		defaultValues = []string{`\000\001\377`, `\"\'\\\n`, ""}
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		enum := g.enumForTypeName(field.GetTypeName())
+		if enum == nil {
+			return
+		}
+		values := enum.proto.GetValue()
+		defaultValues = []string{values[g.rand.IntN(len(values))].GetName()}
+	default:
+		return
+	}
+	field.DefaultValue = proto.String(defaultValues[g.rand.IntN(len(defaultValues))])
+}
+
+func (g *generator) addExtension(file *fileInfo, scope string) {
+	var extendees []*messageInfo
+	for _, message := range g.messages {
+		if len(message.extensionRanges) > 0 && g.isVisible(file, message.file) {
+			extendees = append(extendees, message)
+		}
+	}
+	if len(extendees) == 0 {
+		return
+	}
+	extendee := extendees[g.rand.IntN(len(extendees))]
+	if extendee.extensionNumbers == nil {
+		extendee.extensionNumbers = make(map[int32]struct{})
+	}
+	var number int32
+	for i := 0; ; i++ {
+		if i == 100 {
+			return
+		}
+		extensionRange := extendee.extensionRanges[g.rand.IntN(len(extendee.extensionRanges))]
+		candidates := []int32{extensionRange[0], extensionRange[1] - 1}
+		number = candidates[g.rand.IntN(len(candidates))]
+		if g.oneIn(2) {
+			number = extensionRange[0] + g.rand.Int32N(extensionRange[1]-extensionRange[0])
+		}
+		if _, ok := extendee.extensionNumbers[number]; ok {
+			continue
+		}
+		if number >= firstReservedFieldNumber && number <= lastReservedFieldNumber {
+			continue
+		}
+		break
+	}
+	extendee.extensionNumbers[number] = struct{}{}
+	name := g.newScopedName(scope)
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(jsonCamelCase(name)),
+		Number:   proto.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Extendee: proto.String("." + extendee.fullName),
+	}
+	g.addDependency(file, extendee.file)
+	switch g.rand.IntN(3) {
+	case 0:
+		if !g.setMessageType(file, field) {
+			g.setScalarType(field)
+		}
+	case 1:
+		if !g.setEnumType(file, field, false) {
+			g.setScalarType(field)
+		}
+	default:
+		g.setScalarType(field)
+	}
+	if g.oneIn(3) {
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	} else if g.oneIn(3) {
+		g.setDefaultValue(file, field)
+	}
+	file.proto.Extension = append(file.proto.Extension, field)
+}
+
+func (g *generator) newEnum(file *fileInfo, scope string) *descriptorpb.EnumDescriptorProto {
+	name := g.newDeclarationName(scope)
+	enum := &enumInfo{
+		file:     file,
+		fullName: joinName(scope, name),
+		proto:    &descriptorpb.EnumDescriptorProto{Name: proto.String(name)},
+	}
+	switch file.syntax {
+	case syntaxProto2:
+		enum.closed = true
+	case syntaxEditions:
+		enum.closed = file.closedEnums
+		if g.oneIn(4) {
+			enum.closed = !enum.closed
+			enumType := descriptorpb.FeatureSet_OPEN
+			if enum.closed {
+				enumType = descriptorpb.FeatureSet_CLOSED
+			}
+			enum.proto.Options = &descriptorpb.EnumOptions{
+				Features: &descriptorpb.FeatureSet{EnumType: enumType.Enum()},
+			}
+		}
+	}
+	var numValues int
+	if g.oneIn(10) {
+		numValues = 100 + g.rand.IntN(400)
+	} else {
+		numValues = 1 + g.rand.IntN(6)
+	}
+	prefix := toUpperSnakeCase(name) + "_"
+	enumConflictKey := conflictKey(name)
+	conflictKeys := make(map[string]struct{})
+	numbers := make(map[int32]struct{})
+	for len(enum.proto.GetValue()) < numValues {
+		valueName := toUpperSnakeCase(g.newName())
+		if g.oneIn(4) {
+			valueName = prefix + valueName
+		}
+		valueConflictKey := conflictKey(trimEnumPrefix(valueName, enumConflictKey))
+		if _, ok := conflictKeys[valueConflictKey]; ok {
+			continue
+		}
+		// Enum values are declared in the same scope as the enum.
+		if !g.declare(scope, valueName) {
+			continue
+		}
+		conflictKeys[valueConflictKey] = struct{}{}
+		var number int32
+		if len(enum.proto.GetValue()) == 0 && !enum.closed {
+			number = 0
+		} else {
+			for {
+				number = interestingEnumNumbers[g.rand.IntN(len(interestingEnumNumbers))]
+				if g.oneIn(2) {
+					number = int32(g.rand.Uint32())
+				}
+				if _, ok := numbers[number]; !ok {
+					break
+				}
+			}
+		}
+		numbers[number] = struct{}{}
+		enum.proto.Value = append(
+			enum.proto.Value,
+			&descriptorpb.EnumValueDescriptorProto{
+				Name:   proto.String(valueName),
+				Number: proto.Int32(number),
+			},
+		)
+	}
+	if g.oneIn(8) {
+		// Add an alias of an existing value.
+		for {
+			valueName := toUpperSnakeCase(g.newName()) + fmt.Sprintf("_ALIAS%d", g.nextCounter())
+			if !g.declare(scope, valueName) {
+				continue
+			}
+			aliased := enum.proto.GetValue()[g.rand.IntN(len(enum.proto.GetValue()))]
+			enum.proto.Value = append(
+				enum.proto.Value,
+				&descriptorpb.EnumValueDescriptorProto{
+					Name:   proto.String(valueName),
+					Number: proto.Int32(aliased.GetNumber()),
+				},
+			)
+			break
+		}
+		if enum.proto.Options == nil {
+			enum.proto.Options = &descriptorpb.EnumOptions{}
+		}
+		enum.proto.Options.AllowAlias = proto.Bool(true)
+	}
+	if g.oneIn(4) {
+		for i := 0; i < 3; i++ {
+			number := interestingEnumNumbers[g.rand.IntN(len(interestingEnumNumbers))]
+			if _, ok := numbers[number]; ok {
+				continue
+			}
+			numbers[number] = struct{}{}
+			enum.proto.ReservedRange = append(
+				enum.proto.ReservedRange,
+				&descriptorpb.EnumDescriptorProto_EnumReservedRange{
+					Start: proto.Int32(number),
+					End:   proto.Int32(number),
+				},
+			)
+		}
+		reservedName := toUpperSnakeCase(g.newName()) + fmt.Sprintf("_RESERVED%d", g.nextCounter())
+		enum.proto.ReservedName = append(enum.proto.ReservedName, reservedName)
+	}
+	g.enums = append(g.enums, enum)
+	return enum.proto
+}
+
+func (g *generator) newService(file *fileInfo, scope string) *descriptorpb.ServiceDescriptorProto {
+	name := g.newDeclarationName(scope)
+	service := &descriptorpb.ServiceDescriptorProto{Name: proto.String(name)}
+	fullName := joinName(scope, name)
+	numMethods := g.rand.IntN(5)
+	for i := 0; i < numMethods; i++ {
+		inputMessage := g.pickMessage(file)
+		outputMessage := g.pickMessage(file)
+		if inputMessage == nil || outputMessage == nil {
+			break
+		}
+		g.addDependency(file, inputMessage.file)
+		g.addDependency(file, outputMessage.file)
+		method := &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String(g.newScopedName(fullName)),
+			InputType:  proto.String("." + inputMessage.fullName),
+			OutputType: proto.String("." + outputMessage.fullName),
+		}
+		if g.oneIn(3) {
+			method.ClientStreaming = proto.Bool(true)
+		}
+		if g.oneIn(3) {
+			method.ServerStreaming = proto.Bool(true)
+		}
+		if !method.GetClientStreaming() && !method.GetServerStreaming() && g.oneIn(4) {
+			method.Options = &descriptorpb.MethodOptions{
+				IdempotencyLevel: descriptorpb.MethodOptions_NO_SIDE_EFFECTS.Enum(),
+			}
+		}
+		service.Method = append(service.Method, method)
+	}
+	if g.oneIn(10) {
+		service.Options = &descriptorpb.ServiceOptions{Deprecated: proto.Bool(true)}
+	}
+	return service
+}
+
+// newFieldNumber returns a new field number for the message that is not used by
+// any field, and is not within an extension or reserved range.
+func (g *generator) newFieldNumber(message *messageInfo) (int32, bool) {
+	isAvailable := func(number int32) bool {
+		if _, ok := message.numbers[number]; ok {
+			return false
+		}
+		if number >= firstReservedFieldNumber && number <= lastReservedFieldNumber {
+			return false
+		}
+		for _, extensionRange := range message.extensionRanges {
+			if number >= extensionRange[0] && number < extensionRange[1] {
+				return false
+			}
+		}
+		for _, reservedRange := range message.proto.GetReservedRange() {
+			if number >= reservedRange.GetStart() && number < reservedRange.GetEnd() {
+				return false
+			}
+		}
+		return true
+	}
+	for i := 0; i < 100; i++ {
+		number := interestingFieldNumbers[g.rand.IntN(len(interestingFieldNumbers))]
+		if g.oneIn(2) {
+			number = 1 + g.rand.Int32N(maxFieldNumber)
+		}
+		if isAvailable(number) {
+			return number, true
+		}
+	}
+	for number := int32(1); number <= maxFieldNumber; number++ {
+		if isAvailable(number) {
+			return number, true
+		}
+	}
+	return 0, false
+}
+
+// newDeclarationName returns a new name for a message, enum, or service declared
+// within the scope.
+//
+// Some of the time, the name of a nested declaration is the same as the name of
+// the message it is nested in.
+func (g *generator) newDeclarationName(scope string) string {
+	if index := strings.LastIndexByte(scope, '.'); index >= 0 && g.oneIn(4) {
+		if parentName := scope[index+1:]; g.declare(scope, parentName) {
+			return parentName
+		}
+	}
+	return g.newScopedName(scope)
+}
+
+// newScopedName returns a new name that is not yet declared within the scope,
+// and declares it.
+func (g *generator) newScopedName(scope string) string {
+	for {
+		if name := g.newName(); g.declare(scope, name) {
+			return name
+		}
+	}
+}
+
+// newName returns a new identifier, which is usually an adversarial name, and
+// may be very long.
+func (g *generator) newName() string {
+	switch {
+	case g.oneIn(20):
+		length := 64 + g.rand.IntN(449)
+		var builder strings.Builder
+		builder.WriteByte(byte('a' + g.rand.IntN(26)))
+		for builder.Len() < length {
+			builder.WriteByte("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_"[g.rand.IntN(63)])
+		}
+		return builder.String()
+	case g.oneIn(5):
+		return fmt.Sprintf("%s%d", adversarialNames[g.rand.IntN(len(adversarialNames))], g.nextCounter())
+	default:
+		return adversarialNames[g.rand.IntN(len(adversarialNames))]
+	}
+}
+
+// declare declares the name within the scope, returning false if it is already declared.
+func (g *generator) declare(scope string, name string) bool {
+	names, ok := g.scopes[scope]
+	if !ok {
+		names = make(map[string]struct{})
+		g.scopes[scope] = names
+	}
+	if _, ok := names[name]; ok {
+		return false
+	}
+	names[name] = struct{}{}
+	return true
+}
+
+// pickMessage returns a random message that can be referred to from the file,
+// or nil if there is none.
+func (g *generator) pickMessage(file *fileInfo) *messageInfo {
+	var messages []*messageInfo
+	for _, message := range g.messages {
+		if g.isVisible(file, message.file) {
+			messages = append(messages, message)
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return messages[g.rand.IntN(len(messages))]
+}
+
+// pickEnum returns a random enum that can be referred to from the file and that
+// is accepted by the filter, or nil if there is none.
+func (g *generator) pickEnum(file *fileInfo, filter func(*enumInfo) bool) *enumInfo {
+	var enums []*enumInfo
+	for _, enum := range g.enums {
+		if g.isVisible(file, enum.file) && filter(enum) {
+			enums = append(enums, enum)
+		}
+	}
+	if len(enums) == 0 {
+		return nil
+	}
+	return enums[g.rand.IntN(len(enums))]
+}
+
+// isVisible returns true if the declarations of the other file can be referred to
+// from the file. To avoid import cycles, only the file itself and the files that
+// were generated before it are visible.
+func (g *generator) isVisible(file *fileInfo, otherFile *fileInfo) bool {
+	for _, candidate := range g.files {
+		if candidate == otherFile {
+			return true
+		}
+		if candidate == file {
+			return false
+		}
+	}
+	return false
+}
+
+func (g *generator) isClosedEnumField(field *descriptorpb.FieldDescriptorProto) bool {
+	enum := g.enumForTypeName(field.GetTypeName())
+	return enum != nil && enum.closed
+}
+
+func (g *generator) enumForTypeName(typeName string) *enumInfo {
+	for _, enum := range g.enums {
+		if "."+enum.fullName == typeName {
+			return enum
+		}
+	}
+	return nil
+}
+
+func (g *generator) addDependency(file *fileInfo, dependency *fileInfo) {
+	if file == dependency || containsString(file.proto.GetDependency(), dependency.proto.GetName()) {
+		return
+	}
+	file.proto.Dependency = append(file.proto.Dependency, dependency.proto.GetName())
+}
+
+func (g *generator) oneIn(n int) bool {
+	return g.rand.IntN(n) == 0
+}
+
+func (g *generator) nextCounter() int {
+	g.counter++
+	return g.counter
+}
+
+// isImplicitPresence returns true if the field has implicit presence.
+func isImplicitPresence(file *fileInfo, field *descriptorpb.FieldDescriptorProto, inOneof bool) bool {
+	if inOneof || field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED ||
+		field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE ||
+		field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_GROUP {
+		return false
+	}
+	switch file.syntax {
+	case syntaxProto3:
+		return !field.GetProto3Optional()
+	case syntaxEditions:
+		if fieldPresence := field.GetOptions().GetFeatures().GetFieldPresence(); fieldPresence != descriptorpb.FeatureSet_FIELD_PRESENCE_UNKNOWN {
+			return fieldPresence == descriptorpb.FeatureSet_IMPLICIT
+		}
+		return file.implicitPresence
+	default:
+		return false
+	}
+}
+
+func getFieldOptions(field *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldOptions {
+	if field.Options == nil {
+		field.Options = &descriptorpb.FieldOptions{}
+	}
+	return field.Options
+}
+
+func getFieldFeatures(field *descriptorpb.FieldDescriptorProto) *descriptorpb.FeatureSet {
+	options := getFieldOptions(field)
+	if options.Features == nil {
+		options.Features = &descriptorpb.FeatureSet{}
+	}
+	return options.Features
+}
+
+func isLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func joinName(scope string, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufprotopluginfuzz
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// minimize repeatedly removes parts of the request, keeping each removal for
+// which the request is still valid and fails still returns true, until no
+// more parts can be removed.
+//
+// Parts are removed in pre-order, so that whole files and declarations are
+// removed before their contents are tried one by one. Repeated fields are first
+// cleared as a whole, then element by element.
+func minimize(
+	ctx context.Context,
+	request *pluginpb.CodeGeneratorRequest,
+	fails func(context.Context, *pluginpb.CodeGeneratorRequest) (bool, error),
+) (*pluginpb.CodeGeneratorRequest, error) {
+	// The source file descriptors are derived from the remaining files on every
+	// attempt instead of being minimized separately, as they must match the
+	// files to generate.
+	hasSourceFileDescriptors := len(request.GetSourceFileDescriptors()) > 0
+	current := proto.Clone(request).(*pluginpb.CodeGeneratorRequest)
+	current.SourceFileDescriptors = nil
+	for changed := true; changed; {
+		changed = false
+		for index := 0; ; {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			candidate := proto.Clone(current).(*pluginpb.CodeGeneratorRequest)
+			if !newRemover(index).remove(candidate.ProtoReflect()) {
+				break
+			}
+			removeEmptyOneofs(candidate)
+			if hasSourceFileDescriptors {
+				setSourceFileDescriptors(candidate)
+			}
+			if ValidateRequest(candidate) != nil {
+				index++
+				continue
+			}
+			failed, err := fails(ctx, candidate)
+			if err != nil {
+				return nil, err
+			}
+			candidate.SourceFileDescriptors = nil
+			if !failed {
+				index++
+				continue
+			}
+			// The part at the index was removed, so the index now refers to the
+			// next part.
+			current = candidate
+			changed = true
+		}
+	}
+	if hasSourceFileDescriptors {
+		setSourceFileDescriptors(current)
+	}
+	return current, nil
+}
+
+// remover removes the part of a message at an index, where the parts of a
+// message are its populated fields and list elements, in pre-order.
+type remover struct {
+	index int
+}
+
+func newRemover(index int) *remover {
+	return &remover{
+		index: index,
+	}
+}
+
+// remove removes the part at the index, returning false if there are not
+// enough parts in the message.
+func (r *remover) remove(message protoreflect.Message) bool {
+	fields := message.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fieldDescriptor := fields.Get(i)
+		if !message.Has(fieldDescriptor) || fieldDescriptor.IsMap() {
+			continue
+		}
+		if r.next() {
+			message.Clear(fieldDescriptor)
+			return true
+		}
+		switch {
+		case fieldDescriptor.IsList():
+			list := message.Mutable(fieldDescriptor).List()
+			if list.Len() == 1 {
+				// Removing the only element is the same as clearing the field.
+				if fieldDescriptor.Message() != nil && r.remove(list.Get(0).Message()) {
+					return true
+				}
+				continue
+			}
+			for j := 0; j < list.Len(); j++ {
+				if r.next() {
+					for k := j; k < list.Len()-1; k++ {
+						list.Set(k, list.Get(k+1))
+					}
+					list.Truncate(list.Len() - 1)
+					return true
+				}
+				if fieldDescriptor.Message() != nil && r.remove(list.Get(j).Message()) {
+					return true
+				}
+			}
+		case fieldDescriptor.Message() != nil:
+			if r.remove(message.Mutable(fieldDescriptor).Message()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// next returns true if the current part is the part to remove, and otherwise
+// moves on to the next part.
+func (r *remover) next() bool {
+	if r.index == 0 {
+		return true
+	}
+	r.index--
+	return false
+}
+
+// removeEmptyOneofs removes the oneofs without fields from all messages of the
+// request, as removing the last field of a oneof otherwise results in an
+// invalid request.
+func removeEmptyOneofs(request *pluginpb.CodeGeneratorRequest) {
+	for _, file := range request.GetProtoFile() {
+		for _, message := range file.GetMessageType() {
+			removeEmptyOneofsFromMessage(message)
+		}
+	}
+}
+
+func removeEmptyOneofsFromMessage(message *descriptorpb.DescriptorProto) {
+	for _, nestedMessage := range message.GetNestedType() {
+		removeEmptyOneofsFromMessage(nestedMessage)
+	}
+	usedOneofIndexes := make(map[int32]struct{})
+	for _, field := range message.GetField() {
+		if field.OneofIndex != nil {
+			usedOneofIndexes[field.GetOneofIndex()] = struct{}{}
+		}
+	}
+	if len(usedOneofIndexes) == len(message.GetOneofDecl()) {
+		return
+	}
+	newOneofIndexes := make(map[int32]int32, len(usedOneofIndexes))
+	var oneofs []*descriptorpb.OneofDescriptorProto
+	for i, oneof := range message.GetOneofDecl() {
+		if _, ok := usedOneofIndexes[int32(i)]; ok {
+			newOneofIndexes[int32(i)] = int32(len(oneofs))
+			oneofs = append(oneofs, oneof)
+		}
+	}
+	message.OneofDecl = oneofs
+	for _, field := range message.GetField() {
+		if field.OneofIndex != nil {
+			if newOneofIndex, ok := newOneofIndexes[field.GetOneofIndex()]; ok {
+				field.OneofIndex = proto.Int32(newOneofIndex)
+			}
+		}
+	}
+}
+
+func setSourceFileDescriptors(request *pluginpb.CodeGeneratorRequest) {
+	filesToGenerate := make(map[string]struct{}, len(request.GetFileToGenerate()))
+	for _, fileToGenerate := range request.GetFileToGenerate() {
+		filesToGenerate[fileToGenerate] = struct{}{}
+	}
+	var sourceFileDescriptors []*descriptorpb.FileDescriptorProto
+	for _, file := range request.GetProtoFile() {
+		if _, ok := filesToGenerate[file.GetName()]; ok {
+			sourceFileDescriptors = append(sourceFileDescriptors, file)
+		}
+	}
+	request.SourceFileDescriptors = sourceFileDescriptors
+}
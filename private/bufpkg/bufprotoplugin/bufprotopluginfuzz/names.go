@@ -0,0 +1,156 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufprotopluginfuzz
+
+import (
+	"strings"
+	"unicode"
+)
+
+// adversarialNames are identifiers that are keywords or well-known identifiers in
+// the languages that plugins commonly generate, or that collide with each other
+// once converted to another case.
+var adversarialNames = []string{
+	// Protobuf.
+	"syntax", "edition", "package", "import", "option", "message", "enum", "service",
+	"rpc", "returns", "stream", "oneof", "map", "reserved", "extensions", "extend",
+	"optional", "required", "repeated", "group", "max", "to", "weak", "public",
+	"string", "bytes", "bool", "int32", "int64", "uint32", "uint64", "float", "double",
+	"true", "false", "inf", "nan",
+	// Go.
+	"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough",
+	"for", "func", "go", "goto", "if", "interface", "range", "return", "select", "struct",
+	"switch", "type", "var", "nil", "iota", "len", "cap", "error", "any", "init", "main",
+	"Reset", "String", "ProtoMessage", "ProtoReflect", "Descriptor", "XXX_unrecognized",
+	// Java and Kotlin.
+	"class", "object", "abstract", "assert", "boolean", "byte", "char", "extends", "final",
+	"finally", "implements", "instanceof", "long", "native", "new", "private", "protected",
+	"short", "static", "super", "synchronized", "this", "throw", "throws", "transient",
+	"void", "volatile", "Object", "Builder", "OrBuilder", "getClass", "hashCode", "equals",
+	"fun", "val", "when", "is", "in", "companion",
+	// C++.
+	"alignas", "auto", "delete", "explicit", "export", "friend", "inline", "mutable",
+	"namespace", "operator", "register", "signed", "sizeof", "template", "typedef",
+	"typename", "union", "unsigned", "virtual", "NULL", "EOF", "errno", "DEBUG",
+	// C#.
+	"base", "checked", "decimal", "delegate", "event", "fixed", "foreach", "implicit",
+	"internal", "lock", "out", "params", "readonly", "ref", "sbyte", "sealed",
+	"stackalloc", "ushort", "using", "Parser",
+	// Python.
+	"None", "True", "False", "and", "as", "async", "await", "def", "del", "elif", "except",
+	"from", "global", "lambda", "nonlocal", "not", "or", "pass", "raise", "try", "while",
+	"with", "yield", "self", "cls", "print", "DESCRIPTOR",
+	// JavaScript and TypeScript.
+	"arguments", "constructor", "debugger", "function", "let", "prototype", "typeof",
+	"undefined", "__proto__", "toString", "valueOf", "Symbol", "Array", "Map", "Set",
+	"Date", "Promise", "declare", "keyof", "never", "unknown",
+	// Swift, Ruby, PHP, Objective-C, and Rust.
+	"Self", "Type", "Protocol", "associatedtype", "deinit", "extension", "fileprivate",
+	"begin", "end", "ensure", "module", "redo", "rescue", "retry", "unless", "until",
+	"array", "clone", "echo", "empty", "isset", "list", "parent", "unset", "self_",
+	"id", "BOOL", "YES", "NO", "crate", "dyn", "impl", "loop", "match", "mod", "move",
+	"mut", "pub", "trait", "unsafe", "where", "Option", "Result", "Vec", "Box",
+	// Names that collide once converted to another case.
+	"foo_bar", "FooBar", "fooBar", "foo__bar", "_foo_bar", "foo_bar_", "Foo_Bar",
+	"foo1", "foo_1", "foo1bar", "foo_1_bar", "FOO_BAR", "fOO_bAR", "x", "X", "_", "__",
+	"_0", "a_b_c", "ABC", "abc", "HTTPServer", "HttpServer", "http_server", "URL", "url",
+	"get_foo", "set_foo", "has_foo", "clear_foo", "foo_count", "foo_list", "foo_map",
+	"foo_value", "foo_bytes", "mutable_foo", "release_foo", "getFoo", "hasFoo",
+}
+
+// toUpperSnakeCase converts the identifier to UPPER_SNAKE_CASE, inserting an
+// underscore before an uppercase letter that follows a lowercase letter or digit.
+func toUpperSnakeCase(name string) string {
+	var builder strings.Builder
+	for i, c := range name {
+		if i > 0 && unicode.IsUpper(c) {
+			previous := rune(name[i-1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) {
+				builder.WriteByte('_')
+			}
+		}
+		builder.WriteRune(unicode.ToUpper(c))
+	}
+	return builder.String()
+}
+
+// toUpperCamelCase converts the identifier to UpperCamelCase, removing underscores.
+//
+// The result is empty if the identifier only contains underscores.
+func toUpperCamelCase(name string) string {
+	var builder strings.Builder
+	upperNext := true
+	for _, c := range name {
+		switch {
+		case c == '_':
+			upperNext = true
+		case upperNext:
+			builder.WriteRune(unicode.ToUpper(c))
+			upperNext = false
+		default:
+			builder.WriteRune(c)
+		}
+	}
+	return builder.String()
+}
+
+// jsonCamelCase returns the default JSON name of the field name, as computed by protoc.
+func jsonCamelCase(name string) string {
+	var builder strings.Builder
+	wasUnderscore := false
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c != '_' {
+			if wasUnderscore && 'a' <= c && c <= 'z' {
+				c -= 'a' - 'A'
+			}
+			builder.WriteByte(c)
+		}
+		wasUnderscore = c == '_'
+	}
+	return builder.String()
+}
+
+// trimEnumPrefix trims the prefix from the enum value name, ignoring case and
+// underscores, as done by protoc when checking open enums for conflicts.
+//
+// The prefix must be a conflict key, see conflictKey.
+func trimEnumPrefix(name string, prefix string) string {
+	trimmed := name
+	for len(trimmed) > 0 && len(prefix) > 0 {
+		if trimmed[0] == '_' {
+			trimmed = trimmed[1:]
+			continue
+		}
+		if unicode.ToLower(rune(trimmed[0])) != rune(prefix[0]) {
+			return name
+		}
+		trimmed, prefix = trimmed[1:], prefix[1:]
+	}
+	if len(prefix) > 0 {
+		return name
+	}
+	trimmed = strings.TrimLeft(trimmed, "_")
+	if trimmed == "" {
+		return name
+	}
+	return trimmed
+}
+
+// conflictKey returns the key used to detect names that conflict once converted
+// to another case, by lowercasing and removing underscores.
+func conflictKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
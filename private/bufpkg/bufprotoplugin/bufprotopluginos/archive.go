@@ -0,0 +1,133 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufprotopluginos
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storagearchive"
+	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
+)
+
+const (
+	archiveTypeZip archiveType = iota + 1
+	archiveTypeJar
+	archiveTypeTar
+	archiveTypeTarGz
+)
+
+// archiveType is a type of archive output.
+type archiveType int
+
+// getArchiveType returns the archiveType for the path based on its extension.
+//
+// Returns false if the path is not an archive output.
+func getArchiveType(path string) (archiveType, bool) {
+	switch {
+	case filepath.Ext(path) == ".jar":
+		return archiveTypeJar, true
+	case filepath.Ext(path) == ".zip":
+		return archiveTypeZip, true
+	case filepath.Ext(path) == ".tar":
+		return archiveTypeTar, true
+	case filepath.Ext(path) == ".tgz", strings.HasSuffix(path, ".tar.gz"):
+		return archiveTypeTarGz, true
+	default:
+		return 0, false
+	}
+}
+
+func isArchivePath(path string) bool {
+	_, ok := getArchiveType(path)
+	return ok
+}
+
+func writeArchiveFileForPath(
+	ctx context.Context,
+	readBucket storage.ReadBucket,
+	outFilePath string,
+	createOutDirIfNotExists bool,
+) error {
+	archiveType, ok := getArchiveType(outFilePath)
+	if !ok {
+		return fmt.Errorf("not an archive path: %s", outFilePath)
+	}
+	if archiveType == archiveTypeJar {
+		exists, err := storage.Exists(ctx, readBucket, manifestPath)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			manifestReadWriteBucket := storagemem.NewReadWriteBucket()
+			if err := storage.PutPath(ctx, manifestReadWriteBucket, manifestPath, manifestContent); err != nil {
+				return err
+			}
+			readBucket = storage.MultiReadBucket(readBucket, manifestReadWriteBucket)
+		}
+	}
+	if createOutDirIfNotExists {
+		if err := os.MkdirAll(filepath.Dir(outFilePath), 0755); err != nil {
+			return err
+		}
+	}
+	return writeArchiveFile(ctx, readBucket, archiveType, outFilePath)
+}
+
+func writeArchiveFile(
+	ctx context.Context,
+	readBucket storage.ReadBucket,
+	archiveType archiveType,
+	outFilePath string,
+) (retErr error) {
+	file, err := os.Create(outFilePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, file.Close())
+	}()
+	return writeArchive(ctx, readBucket, archiveType, file)
+}
+
+func writeArchive(
+	ctx context.Context,
+	readBucket storage.ReadBucket,
+	archiveType archiveType,
+	writer io.Writer,
+) (retErr error) {
+	switch archiveType {
+	case archiveTypeZip, archiveTypeJar:
+		// protoc does not compress.
+		return storagearchive.Zip(ctx, readBucket, writer, false)
+	case archiveTypeTar:
+		return storagearchive.Tar(ctx, readBucket, writer)
+	case archiveTypeTarGz:
+		gzipWriter := gzip.NewWriter(writer)
+		defer func() {
+			retErr = errors.Join(retErr, gzipWriter.Close())
+		}()
+		return storagearchive.Tar(ctx, readBucket, gzipWriter)
+	default:
+		return fmt.Errorf("unknown archiveType: %v", archiveType)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"io"
 	"log/slog"
 
+	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
 	"google.golang.org/protobuf/types/pluginpb"
 )
@@ -32,7 +33,8 @@ type ResponseWriter interface {
 
 	// AddResponse adds the response to the writer, switching on the file extension.
 	// If there is a .jar extension, this generates a jar. If there is a .zip
-	// extension, this generates a zip. If there is no extension, this outputs
+	// extension, this generates a zip. If there is a .tar, .tar.gz, or .tgz
+	// extension, this generates a tarball. If there is no extension, this outputs
 	// to the directory.
 	//
 	// pluginOut will be unnormalized within this function.
@@ -67,12 +69,46 @@ func ResponseWriterWithCreateOutDirIfNotExists() ResponseWriterOption {
 	}
 }
 
+// ResponseWriterWithDryRun returns a new ResponseWriterOption that results in
+// nothing being written to disk on Close.
+//
+// Instead, the OS path of every file that would have been written is passed to
+// dryRunFunc on Close. Archive outputs are reported as the single archive path.
+func ResponseWriterWithDryRun(dryRunFunc func(path string)) ResponseWriterOption {
+	return func(responseWriterOptions *responseWriterOptions) {
+		responseWriterOptions.dryRunFunc = dryRunFunc
+	}
+}
+
+// IsArchivePath returns true if the path has an extension that results in an
+// archive being generated instead of a directory.
+//
+// This is one of .jar, .zip, .tar, .tar.gz, or .tgz.
+func IsArchivePath(path string) bool {
+	return isArchivePath(path)
+}
+
+// WriteArchive writes the contents of the ReadBucket to an archive at the given
+// OS path, creating the parent directory if it does not exist.
+//
+// The type of archive is determined by the extension of the path, see IsArchivePath.
+// Jar archives will have a manifest added if the ReadBucket does not contain one.
+func WriteArchive(ctx context.Context, readBucket storage.ReadBucket, path string) error {
+	return writeArchiveFileForPath(ctx, readBucket, path, true)
+}
+
 // Cleaner deletes output locations prior to generation.
 //
 // This must be done before any interaction with  ResponseWriters, as multiple plugins may output to a single
 // location.
 type Cleaner interface {
+	// DeleteOuts deletes the given plugin outs.
 	DeleteOuts(ctx context.Context, pluginOuts []string) error
+	// ListOuts returns the OS paths of the existing files that DeleteOuts would
+	// delete for the given plugin outs, without deleting anything.
+	//
+	// The same validation as DeleteOuts is performed.
+	ListOuts(ctx context.Context, pluginOuts []string) ([]string, error)
 }
 
 // NewCleaner returns a new Cleaner.
@@ -23,6 +23,7 @@ import (
 	"github.com/bufbuild/buf/private/pkg/filepathext"
 	"github.com/bufbuild/buf/private/pkg/normalpath"
 	"github.com/bufbuild/buf/private/pkg/osext"
+	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
 	"github.com/bufbuild/buf/private/pkg/syserror"
 )
@@ -43,51 +44,113 @@ func (c *cleaner) DeleteOuts(
 	ctx context.Context,
 	pluginOuts []string,
 ) error {
-	pwd, err := osext.Getwd()
-	if err != nil {
-		return err
-	}
-	pwd, err = reallyCleanPath(pwd)
-	if err != nil {
+	if err := validatePluginOuts(pluginOuts); err != nil {
 		return err
 	}
 	for _, pluginOut := range pluginOuts {
-		if err := validatePluginOut(pwd, pluginOut); err != nil {
+		if err := c.deleteOut(ctx, pluginOut); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+func (c *cleaner) ListOuts(
+	ctx context.Context,
+	pluginOuts []string,
+) ([]string, error) {
+	if err := validatePluginOuts(pluginOuts); err != nil {
+		return nil, err
+	}
+	var paths []string
 	for _, pluginOut := range pluginOuts {
-		if err := c.deleteOut(ctx, pluginOut); err != nil {
-			return err
+		outPaths, err := c.listOut(ctx, pluginOut)
+		if err != nil {
+			return nil, err
 		}
+		paths = append(paths, outPaths...)
 	}
-	return nil
+	return paths, nil
 }
 
 func (c *cleaner) deleteOut(
 	ctx context.Context,
 	pluginOut string,
 ) error {
+	bucket, removePath, err := c.getOutBucketAndPath(pluginOut)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return bucket.DeleteAll(ctx, removePath)
+}
+
+func (c *cleaner) listOut(
+	ctx context.Context,
+	pluginOut string,
+) ([]string, error) {
+	bucket, removePath, err := c.getOutBucketAndPath(pluginOut)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	dirPath := pluginOut
+	if removePath != "." {
+		dirPath = filepath.Dir(pluginOut)
+	}
+	var paths []string
+	if err := bucket.Walk(
+		ctx,
+		removePath,
+		func(objectInfo storage.ObjectInfo) error {
+			paths = append(paths, filepath.Join(dirPath, normalpath.Unnormalize(objectInfo.Path())))
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// getOutBucketAndPath returns the bucket that contains the pluginOut, and the
+// path within the bucket to remove.
+func (c *cleaner) getOutBucketAndPath(pluginOut string) (storage.ReadWriteBucket, string, error) {
 	dirPath := pluginOut
 	removePath := "."
-	switch filepath.Ext(pluginOut) {
-	case ".jar", ".zip":
+	if isArchivePath(pluginOut) {
 		dirPath = normalpath.Dir(pluginOut)
 		removePath = normalpath.Base(pluginOut)
-	default:
-		// Assume output is a directory.
 	}
+	// Otherwise, assume output is a directory.
 	bucket, err := c.storageosProvider.NewReadWriteBucket(
 		dirPath,
 		storageos.ReadWriteBucketWithSymlinksIfSupported(),
 	)
 	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			return nil
-		}
+		return nil, "", err
+	}
+	return bucket, removePath, nil
+}
+
+func validatePluginOuts(pluginOuts []string) error {
+	pwd, err := osext.Getwd()
+	if err != nil {
 		return err
 	}
-	return bucket.DeleteAll(ctx, removePath)
+	pwd, err = reallyCleanPath(pwd)
+	if err != nil {
+		return err
+	}
+	for _, pluginOut := range pluginOuts {
+		if err := validatePluginOut(pwd, pluginOut); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func validatePluginOut(pwd string, pluginOut string) error {
@@ -16,7 +16,6 @@ package bufprotopluginos
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -26,7 +25,6 @@ import (
 	"github.com/bufbuild/buf/private/bufpkg/bufprotoplugin"
 	"github.com/bufbuild/buf/private/pkg/normalpath"
 	"github.com/bufbuild/buf/private/pkg/storage"
-	"github.com/bufbuild/buf/private/pkg/storage/storagearchive"
 	"github.com/bufbuild/buf/private/pkg/storage/storagemem"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
 	"google.golang.org/protobuf/types/pluginpb"
@@ -47,6 +45,9 @@ type responseWriter struct {
 	responseWriter    bufprotoplugin.ResponseWriter
 	// If set, create directories if they don't already exist.
 	createOutDirIfNotExists bool
+	// If set, nothing is written to disk, and the paths of the files
+	// that would have been written are passed to dryRunFunc on Close.
+	dryRunFunc func(string)
 	// Cache the readWriteBuckets by their respective output paths.
 	// These builders are transformed to storage.ReadBuckets and written
 	// to disk once the responseWriter is flushed.
@@ -84,6 +85,7 @@ func newResponseWriter(
 		storageosProvider:       storageosProvider,
 		responseWriter:          bufprotoplugin.NewResponseWriter(logger),
 		createOutDirIfNotExists: responseWriterOptions.createOutDirIfNotExists,
+		dryRunFunc:              responseWriterOptions.dryRunFunc,
 		readWriteBuckets:        make(map[string]storage.ReadWriteBucket),
 	}
 }
@@ -142,40 +144,43 @@ func (w *responseWriter) addResponse(
 	pluginOut string,
 	createOutDirIfNotExists bool,
 ) error {
-	switch filepath.Ext(pluginOut) {
-	case ".jar":
-		return w.writeZip(
-			ctx,
-			response,
-			pluginOut,
-			true,
-			createOutDirIfNotExists,
-		)
-	case ".zip":
-		return w.writeZip(
-			ctx,
-			response,
-			pluginOut,
-			false,
-			createOutDirIfNotExists,
-		)
-	default:
-		return w.writeDirectory(
+	if archiveType, ok := getArchiveType(pluginOut); ok {
+		return w.writeArchive(
 			ctx,
 			response,
 			pluginOut,
+			archiveType,
 			createOutDirIfNotExists,
 		)
 	}
+	return w.writeDirectory(
+		ctx,
+		response,
+		pluginOut,
+		createOutDirIfNotExists,
+	)
 }
 
-func (w *responseWriter) writeZip(
+func (w *responseWriter) writeArchive(
 	ctx context.Context,
 	response *pluginpb.CodeGeneratorResponse,
 	outFilePath string,
-	includeManifest bool,
+	archiveType archiveType,
 	createOutDirIfNotExists bool,
-) (retErr error) {
+) error {
+	includeManifest := archiveType == archiveTypeJar
+	if w.dryRunFunc != nil {
+		return w.addDryRunResponse(
+			ctx,
+			response,
+			outFilePath,
+			includeManifest,
+			func(context.Context, storage.ReadBucket) error {
+				w.dryRunFunc(outFilePath)
+				return nil
+			},
+		)
+	}
 	outDirPath := filepath.Dir(outFilePath)
 	if readWriteBucket, ok := w.readWriteBuckets[outFilePath]; ok {
 		// We already have a readWriteBucket for this outFilePath, so
@@ -194,15 +199,15 @@ func (w *responseWriter) writeZip(
 	fileInfo, err := os.Stat(outDirPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			if createOutDirIfNotExists {
-				if err := os.MkdirAll(outDirPath, 0755); err != nil {
-					return err
-				}
-			} else {
+			if !createOutDirIfNotExists {
+				return err
+			}
+			if err := os.MkdirAll(outDirPath, 0755); err != nil {
 				return err
 			}
+		} else {
+			return err
 		}
-		return err
 	} else if !fileInfo.IsDir() {
 		return fmt.Errorf("not a directory: %s", outDirPath)
 	}
@@ -223,18 +228,10 @@ func (w *responseWriter) writeZip(
 	// Add this readWriteBucket to the set so that other plugins
 	// can write to the same files (re: insertion points).
 	w.readWriteBuckets[outFilePath] = readWriteBucket
-	w.closers = append(w.closers, func() (retErr error) {
+	w.closers = append(w.closers, func() error {
 		// We're done writing all of the content into this
-		// readWriteBucket, so we zip it when we flush.
-		file, err := os.Create(outFilePath)
-		if err != nil {
-			return err
-		}
-		defer func() {
-			retErr = errors.Join(retErr, file.Close())
-		}()
-		// protoc does not compress.
-		return storagearchive.Zip(ctx, readWriteBucket, file, false)
+		// readWriteBucket, so we archive it when we flush.
+		return writeArchiveFile(ctx, readWriteBucket, archiveType, outFilePath)
 	})
 	return nil
 }
@@ -245,6 +242,24 @@ func (w *responseWriter) writeDirectory(
 	outDirPath string,
 	createOutDirIfNotExists bool,
 ) error {
+	if w.dryRunFunc != nil {
+		return w.addDryRunResponse(
+			ctx,
+			response,
+			outDirPath,
+			false,
+			func(ctx context.Context, readBucket storage.ReadBucket) error {
+				return readBucket.Walk(
+					ctx,
+					"",
+					func(objectInfo storage.ObjectInfo) error {
+						w.dryRunFunc(filepath.Join(outDirPath, normalpath.Unnormalize(objectInfo.Path())))
+						return nil
+					},
+				)
+			},
+		)
+	}
 	if readWriteBucket, ok := w.readWriteBuckets[outDirPath]; ok {
 		// We already have a readWriteBucket for this outDirPath, so
 		// we can write to the same bucket.
@@ -292,8 +307,39 @@ func (w *responseWriter) writeDirectory(
 	return nil
 }
 
+// addDryRunResponse writes the response to an in-memory bucket for the out path,
+// and on Close calls reportFunc with the bucket instead of writing to disk.
+func (w *responseWriter) addDryRunResponse(
+	ctx context.Context,
+	response *pluginpb.CodeGeneratorResponse,
+	outPath string,
+	includeManifest bool,
+	reportFunc func(context.Context, storage.ReadBucket) error,
+) error {
+	readWriteBucket, ok := w.readWriteBuckets[outPath]
+	if !ok {
+		readWriteBucket = storagemem.NewReadWriteBucket()
+		if includeManifest {
+			if err := storage.PutPath(ctx, readWriteBucket, manifestPath, manifestContent); err != nil {
+				return err
+			}
+		}
+		w.readWriteBuckets[outPath] = readWriteBucket
+		w.closers = append(w.closers, func() error {
+			return reportFunc(ctx, readWriteBucket)
+		})
+	}
+	return w.responseWriter.WriteResponse(
+		ctx,
+		readWriteBucket,
+		response,
+		bufprotoplugin.WriteResponseWithInsertionPointReadBucket(readWriteBucket),
+	)
+}
+
 type responseWriterOptions struct {
 	createOutDirIfNotExists bool
+	dryRunFunc              func(string)
 }
 
 func newResponseWriterOptions() *responseWriterOptions {
@@ -0,0 +1,117 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufreserved provides the reserved registry, which records every field
+// number and name ever used per message, and every enum value number and name
+// ever used per enum.
+//
+// The registry is maintained with buf beta reserved sync, and is used by the
+// RESERVED_REGISTRY_NO_REUSE lint rule to prevent reusing a number or name that
+// was previously used, even if the current file has no reserved statement for it.
+package bufreserved
+
+import (
+	"io"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DefaultFileName is the default file name of the reserved registry.
+const DefaultFileName = "buf.reserved.yaml"
+
+// Registry is a reserved registry.
+type Registry interface {
+	// Messages returns the Records for messages, sorted by full name.
+	Messages() []Record
+	// Enums returns the Records for enums, sorted by full name.
+	Enums() []Record
+
+	isRegistry()
+}
+
+// Record records the numbers and names used by the fields of a message, or the
+// values of an enum.
+type Record interface {
+	// FullName returns the fully-qualified name of the message or enum.
+	FullName() string
+	// Entries returns the entries, sorted by number and then by name.
+	//
+	// The same number may appear with multiple names, and the same name may appear
+	// with multiple numbers, if a field or enum value was changed over time.
+	Entries() []Entry
+
+	isRecord()
+}
+
+// Entry is a number and name used together by a field or enum value.
+type Entry struct {
+	Number int32
+	Name   string
+}
+
+// NewRegistry returns a new Registry with the given Records for messages and enums.
+//
+// Records with the same full name are merged.
+func NewRegistry(messages []Record, enums []Record) Registry {
+	return newRegistryForRecords(messages, enums)
+}
+
+// NewRecord returns a new Record for the message or enum with the given full name.
+func NewRecord(fullName string, entries ...Entry) Record {
+	return newRecord(fullName, entries)
+}
+
+// NewRegistryForImage returns a new Registry with the numbers and names currently
+// used in the non-import files of the Image.
+//
+// Map entry messages are not recorded, as they are synthesized for map fields.
+func NewRegistryForImage(image bufimage.Image) Registry {
+	return newRegistryForImage(image)
+}
+
+// NewRegistryForFileDescriptorProtos returns a new Registry with the numbers and names
+// used in the FileDescriptorProtos.
+//
+// The FileDescriptorProtos do not need to be linked, so that files can be recorded
+// without their imports, such as files read from the history of a git repository.
+// Map entry messages are not recorded.
+func NewRegistryForFileDescriptorProtos(fileDescriptorProtos ...*descriptorpb.FileDescriptorProto) Registry {
+	return newRegistryForFileDescriptorProtos(fileDescriptorProtos)
+}
+
+// MergeRegistries returns a new Registry with the Records of all the given Registries.
+func MergeRegistries(registries ...Registry) Registry {
+	return mergeRegistries(registries...)
+}
+
+// ExcludeRegistry returns a new Registry with the entries of the Registry that are not
+// in the excluded Registry.
+//
+// This is used to check for reuse against the numbers and names previously used in a
+// set of prior versions that may themselves include the current version, as entries
+// that are still in use are not reuse.
+func ExcludeRegistry(registry Registry, excludedRegistry Registry) Registry {
+	return excludeRegistry(registry, excludedRegistry)
+}
+
+// ReadRegistry reads a Registry from the io.Reader.
+func ReadRegistry(reader io.Reader) (Registry, error) {
+	return readRegistry(reader)
+}
+
+// WriteRegistry writes the Registry to the io.Writer.
+func WriteRegistry(writer io.Writer, registry Registry) error {
+	return writeRegistry(writer, registry)
+}
@@ -0,0 +1,205 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufreserved
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegistry(t *testing.T) {
+	t.Parallel()
+	registry := NewRegistry(
+		[]Record{
+			NewRecord(
+				"a.v1.Foo",
+				Entry{Number: 2, Name: "two"},
+				Entry{Number: 1, Name: "one"},
+				Entry{Number: 2, Name: "two"},
+			),
+			NewRecord("a.v1.Foo", Entry{Number: 2, Name: "renamed"}),
+		},
+		[]Record{
+			NewRecord("a.v1.Bar", Entry{Number: 0, Name: "BAR_UNSPECIFIED"}),
+		},
+	)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteRegistry(buffer, registry))
+	require.Equal(
+		t,
+		`version: v1
+messages:
+  - name: a.v1.Foo
+    entries:
+      - number: 1
+        name: one
+      - number: 2
+        name: renamed
+      - number: 2
+        name: two
+enums:
+  - name: a.v1.Bar
+    entries:
+      - number: 0
+        name: BAR_UNSPECIFIED
+`,
+		buffer.String(),
+	)
+}
+
+func TestMergeRegistries(t *testing.T) {
+	t.Parallel()
+	registry1, err := ReadRegistry(
+		strings.NewReader(`version: v1
+messages:
+  - name: a.v1.Foo
+    entries:
+      - number: 2
+        name: two
+      - number: 1
+        name: one
+enums:
+  - name: a.v1.Bar
+    entries:
+      - number: 0
+        name: BAR_UNSPECIFIED
+`),
+	)
+	require.NoError(t, err)
+	registry2, err := ReadRegistry(
+		strings.NewReader(`version: v1
+messages:
+  - name: a.v1.Foo
+    entries:
+      - number: 1
+        name: one
+      - number: 2
+        name: renamed
+  - name: a.v1.Baz
+    entries:
+      - number: 1
+        name: one
+`),
+	)
+	require.NoError(t, err)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteRegistry(buffer, MergeRegistries(registry1, registry2)))
+	require.Equal(
+		t,
+		`version: v1
+messages:
+  - name: a.v1.Baz
+    entries:
+      - number: 1
+        name: one
+  - name: a.v1.Foo
+    entries:
+      - number: 1
+        name: one
+      - number: 2
+        name: renamed
+      - number: 2
+        name: two
+enums:
+  - name: a.v1.Bar
+    entries:
+      - number: 0
+        name: BAR_UNSPECIFIED
+`,
+		buffer.String(),
+	)
+}
+
+func TestExcludeRegistry(t *testing.T) {
+	t.Parallel()
+	registry, err := ReadRegistry(
+		strings.NewReader(`version: v1
+messages:
+  - name: a.v1.Foo
+    entries:
+      - number: 1
+        name: one
+      - number: 2
+        name: reused
+      - number: 2
+        name: two
+  - name: a.v1.Baz
+    entries:
+      - number: 1
+        name: one
+`),
+	)
+	require.NoError(t, err)
+	excludedRegistry, err := ReadRegistry(
+		strings.NewReader(`version: v1
+messages:
+  - name: a.v1.Foo
+    entries:
+      - number: 1
+        name: one
+      - number: 2
+        name: reused
+  - name: a.v1.Baz
+    entries:
+      - number: 1
+        name: one
+enums:
+  - name: a.v1.Bar
+    entries:
+      - number: 0
+        name: BAR_UNSPECIFIED
+`),
+	)
+	require.NoError(t, err)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, WriteRegistry(buffer, ExcludeRegistry(registry, excludedRegistry)))
+	require.Equal(
+		t,
+		`version: v1
+messages:
+  - name: a.v1.Foo
+    entries:
+      - number: 2
+        name: two
+`,
+		buffer.String(),
+	)
+}
+
+func TestReadRegistryErrors(t *testing.T) {
+	t.Parallel()
+	_, err := ReadRegistry(strings.NewReader(`version: v2`))
+	require.ErrorContains(t, err, `unknown reserved registry version "v2"`)
+	_, err = ReadRegistry(
+		strings.NewReader(`version: v1
+messages:
+  - name: a.v1.Foo
+  - name: a.v1.Foo
+`),
+	)
+	require.ErrorContains(t, err, `message "a.v1.Foo" is recorded more than once`)
+	_, err = ReadRegistry(
+		strings.NewReader(`version: v1
+enums:
+  - name: a.v1.Bar
+    entries:
+      - number: 1
+`),
+	)
+	require.ErrorContains(t, err, `enum "a.v1.Bar": name is required for number 1`)
+}
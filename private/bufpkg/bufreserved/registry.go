@@ -0,0 +1,333 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufreserved
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/buf/private/bufpkg/bufimage"
+	"github.com/bufbuild/buf/private/pkg/encoding"
+	"github.com/bufbuild/buf/private/pkg/syserror"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const registryVersion = "v1"
+
+type registry struct {
+	messages []Record
+	enums    []Record
+}
+
+func newRegistry(
+	messageFullNameToEntries map[string]map[Entry]struct{},
+	enumFullNameToEntries map[string]map[Entry]struct{},
+) *registry {
+	return &registry{
+		messages: newRecords(messageFullNameToEntries),
+		enums:    newRecords(enumFullNameToEntries),
+	}
+}
+
+func newRegistryForRecords(messages []Record, enums []Record) *registry {
+	messageFullNameToEntries := make(map[string]map[Entry]struct{})
+	enumFullNameToEntries := make(map[string]map[Entry]struct{})
+	addRecords(messageFullNameToEntries, messages)
+	addRecords(enumFullNameToEntries, enums)
+	return newRegistry(messageFullNameToEntries, enumFullNameToEntries)
+}
+
+func newRegistryForImage(image bufimage.Image) *registry {
+	var fileDescriptorProtos []*descriptorpb.FileDescriptorProto
+	for _, imageFile := range image.Files() {
+		if imageFile.IsImport() {
+			continue
+		}
+		fileDescriptorProtos = append(fileDescriptorProtos, imageFile.FileDescriptorProto())
+	}
+	return newRegistryForFileDescriptorProtos(fileDescriptorProtos)
+}
+
+func newRegistryForFileDescriptorProtos(fileDescriptorProtos []*descriptorpb.FileDescriptorProto) *registry {
+	messageFullNameToEntries := make(map[string]map[Entry]struct{})
+	enumFullNameToEntries := make(map[string]map[Entry]struct{})
+	for _, fileDescriptorProto := range fileDescriptorProtos {
+		prefix := fileDescriptorProto.GetPackage()
+		if prefix != "" {
+			prefix += "."
+		}
+		addMessages(messageFullNameToEntries, enumFullNameToEntries, prefix, fileDescriptorProto.GetMessageType())
+		addEnums(enumFullNameToEntries, prefix, fileDescriptorProto.GetEnumType())
+	}
+	return newRegistry(messageFullNameToEntries, enumFullNameToEntries)
+}
+
+func mergeRegistries(registries ...Registry) *registry {
+	messageFullNameToEntries := make(map[string]map[Entry]struct{})
+	enumFullNameToEntries := make(map[string]map[Entry]struct{})
+	for _, registry := range registries {
+		addRecords(messageFullNameToEntries, registry.Messages())
+		addRecords(enumFullNameToEntries, registry.Enums())
+	}
+	return newRegistry(messageFullNameToEntries, enumFullNameToEntries)
+}
+
+func excludeRegistry(registry Registry, excludedRegistry Registry) *registry {
+	messageFullNameToEntries := make(map[string]map[Entry]struct{})
+	enumFullNameToEntries := make(map[string]map[Entry]struct{})
+	addRecords(messageFullNameToEntries, registry.Messages())
+	addRecords(enumFullNameToEntries, registry.Enums())
+	removeRecords(messageFullNameToEntries, excludedRegistry.Messages())
+	removeRecords(enumFullNameToEntries, excludedRegistry.Enums())
+	return newRegistry(messageFullNameToEntries, enumFullNameToEntries)
+}
+
+func readRegistry(reader io.Reader) (*registry, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var externalRegistry externalRegistryV1
+	if err := encoding.UnmarshalYAMLStrict(data, &externalRegistry); err != nil {
+		return nil, err
+	}
+	if externalRegistry.Version != registryVersion {
+		return nil, fmt.Errorf("unknown reserved registry version %q, expected %q", externalRegistry.Version, registryVersion)
+	}
+	messageFullNameToEntries, err := getFullNameToEntriesForExternalRecords(externalRegistry.Messages, "message")
+	if err != nil {
+		return nil, err
+	}
+	enumFullNameToEntries, err := getFullNameToEntriesForExternalRecords(externalRegistry.Enums, "enum")
+	if err != nil {
+		return nil, err
+	}
+	return newRegistry(messageFullNameToEntries, enumFullNameToEntries), nil
+}
+
+func writeRegistry(writer io.Writer, registry Registry) error {
+	if registry == nil {
+		return syserror.New("nil Registry")
+	}
+	externalRegistry := externalRegistryV1{
+		Version:  registryVersion,
+		Messages: getExternalRecords(registry.Messages()),
+		Enums:    getExternalRecords(registry.Enums()),
+	}
+	data, err := encoding.MarshalYAML(&externalRegistry)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
+
+func (r *registry) Messages() []Record {
+	return slices.Clone(r.messages)
+}
+
+func (r *registry) Enums() []Record {
+	return slices.Clone(r.enums)
+}
+
+func (*registry) isRegistry() {}
+
+type record struct {
+	fullName string
+	entries  []Entry
+}
+
+func newRecord(fullName string, entries []Entry) *record {
+	entries = slices.Clone(entries)
+	slices.SortFunc(entries, compareEntries)
+	return &record{
+		fullName: fullName,
+		entries:  slices.Compact(entries),
+	}
+}
+
+func (r *record) FullName() string {
+	return r.fullName
+}
+
+func (r *record) Entries() []Entry {
+	return slices.Clone(r.entries)
+}
+
+func (*record) isRecord() {}
+
+func newRecords(fullNameToEntries map[string]map[Entry]struct{}) []Record {
+	records := make([]Record, 0, len(fullNameToEntries))
+	for fullName, entrySet := range fullNameToEntries {
+		entries := make([]Entry, 0, len(entrySet))
+		for entry := range entrySet {
+			entries = append(entries, entry)
+		}
+		slices.SortFunc(entries, compareEntries)
+		records = append(
+			records,
+			&record{
+				fullName: fullName,
+				entries:  entries,
+			},
+		)
+	}
+	slices.SortFunc(records, func(a Record, b Record) int {
+		return strings.Compare(a.FullName(), b.FullName())
+	})
+	return records
+}
+
+func addMessages(
+	messageFullNameToEntries map[string]map[Entry]struct{},
+	enumFullNameToEntries map[string]map[Entry]struct{},
+	prefix string,
+	messageDescriptorProtos []*descriptorpb.DescriptorProto,
+) {
+	for _, messageDescriptorProto := range messageDescriptorProtos {
+		if messageDescriptorProto.GetOptions().GetMapEntry() {
+			continue
+		}
+		fullName := prefix + messageDescriptorProto.GetName()
+		entries := getEntries(messageFullNameToEntries, fullName)
+		for _, fieldDescriptorProto := range messageDescriptorProto.GetField() {
+			entries[Entry{Number: fieldDescriptorProto.GetNumber(), Name: fieldDescriptorProto.GetName()}] = struct{}{}
+		}
+		addMessages(messageFullNameToEntries, enumFullNameToEntries, fullName+".", messageDescriptorProto.GetNestedType())
+		addEnums(enumFullNameToEntries, fullName+".", messageDescriptorProto.GetEnumType())
+	}
+}
+
+func addEnums(
+	enumFullNameToEntries map[string]map[Entry]struct{},
+	prefix string,
+	enumDescriptorProtos []*descriptorpb.EnumDescriptorProto,
+) {
+	for _, enumDescriptorProto := range enumDescriptorProtos {
+		entries := getEntries(enumFullNameToEntries, prefix+enumDescriptorProto.GetName())
+		for _, enumValueDescriptorProto := range enumDescriptorProto.GetValue() {
+			entries[Entry{Number: enumValueDescriptorProto.GetNumber(), Name: enumValueDescriptorProto.GetName()}] = struct{}{}
+		}
+	}
+}
+
+func addRecords(fullNameToEntries map[string]map[Entry]struct{}, records []Record) {
+	for _, record := range records {
+		entries := getEntries(fullNameToEntries, record.FullName())
+		for _, entry := range record.Entries() {
+			entries[entry] = struct{}{}
+		}
+	}
+}
+
+// removeRecords removes the entries of the records, and removes any full name that has
+// no remaining entries.
+func removeRecords(fullNameToEntries map[string]map[Entry]struct{}, records []Record) {
+	for _, record := range records {
+		entries, ok := fullNameToEntries[record.FullName()]
+		if !ok {
+			continue
+		}
+		for _, entry := range record.Entries() {
+			delete(entries, entry)
+		}
+		if len(entries) == 0 {
+			delete(fullNameToEntries, record.FullName())
+		}
+	}
+}
+
+func getEntries(fullNameToEntries map[string]map[Entry]struct{}, fullName string) map[Entry]struct{} {
+	entries, ok := fullNameToEntries[fullName]
+	if !ok {
+		entries = make(map[Entry]struct{})
+		fullNameToEntries[fullName] = entries
+	}
+	return entries
+}
+
+func getFullNameToEntriesForExternalRecords(
+	externalRecords []externalRecordV1,
+	descriptorType string,
+) (map[string]map[Entry]struct{}, error) {
+	fullNameToEntries := make(map[string]map[Entry]struct{}, len(externalRecords))
+	for _, externalRecord := range externalRecords {
+		if externalRecord.Name == "" {
+			return nil, fmt.Errorf("%s name is required", descriptorType)
+		}
+		if _, ok := fullNameToEntries[externalRecord.Name]; ok {
+			return nil, fmt.Errorf("%s %q is recorded more than once", descriptorType, externalRecord.Name)
+		}
+		entries := getEntries(fullNameToEntries, externalRecord.Name)
+		for _, externalEntry := range externalRecord.Entries {
+			if externalEntry.Name == "" {
+				return nil, fmt.Errorf("%s %q: name is required for number %d", descriptorType, externalRecord.Name, externalEntry.Number)
+			}
+			entries[Entry{Number: externalEntry.Number, Name: externalEntry.Name}] = struct{}{}
+		}
+	}
+	return fullNameToEntries, nil
+}
+
+func getExternalRecords(records []Record) []externalRecordV1 {
+	externalRecords := make([]externalRecordV1, 0, len(records))
+	for _, record := range records {
+		entries := record.Entries()
+		externalEntries := make([]externalEntryV1, len(entries))
+		for i, entry := range entries {
+			externalEntries[i] = externalEntryV1{
+				Number: entry.Number,
+				Name:   entry.Name,
+			}
+		}
+		externalRecords = append(
+			externalRecords,
+			externalRecordV1{
+				Name:    record.FullName(),
+				Entries: externalEntries,
+			},
+		)
+	}
+	return externalRecords
+}
+
+func compareEntries(a Entry, b Entry) int {
+	if c := cmp.Compare(a.Number, b.Number); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Name, b.Name)
+}
+
+// externalRegistryV1 represents a v1 reserved registry file.
+type externalRegistryV1 struct {
+	Version  string             `json:"version,omitempty" yaml:"version,omitempty"`
+	Messages []externalRecordV1 `json:"messages,omitempty" yaml:"messages,omitempty"`
+	Enums    []externalRecordV1 `json:"enums,omitempty" yaml:"enums,omitempty"`
+}
+
+// externalRecordV1 represents a message or enum in a v1 reserved registry file.
+type externalRecordV1 struct {
+	Name    string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Entries []externalEntryV1 `json:"entries,omitempty" yaml:"entries,omitempty"`
+}
+
+// externalEntryV1 represents a field or enum value in a v1 reserved registry file.
+type externalEntryV1 struct {
+	Number int32  `json:"number" yaml:"number"`
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
+}
@@ -0,0 +1,99 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bufsql generates SQL DDL from messages marked with the
+// buf.alpha.sql.v1alpha1.table option.
+package bufsql
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// DialectPostgres is the Postgres dialect.
+	DialectPostgres Dialect = iota + 1
+)
+
+var (
+	// AllDialectStrings is all dialect strings.
+	AllDialectStrings = []string{
+		"postgres",
+	}
+
+	stringToDialect = map[string]Dialect{
+		"postgres": DialectPostgres,
+	}
+	dialectToString = map[Dialect]string{
+		DialectPostgres: "postgres",
+	}
+)
+
+// Dialect is a SQL dialect.
+type Dialect int
+
+// String implements fmt.Stringer.
+func (d Dialect) String() string {
+	s, ok := dialectToString[d]
+	if !ok {
+		return fmt.Sprintf("%d", d)
+	}
+	return s
+}
+
+// ParseDialect parses the Dialect.
+//
+// The empty string defaults to DialectPostgres.
+func ParseDialect(s string) (Dialect, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return DialectPostgres, nil
+	}
+	d, ok := stringToDialect[s]
+	if ok {
+		return d, nil
+	}
+	return 0, fmt.Errorf("unknown dialect: %q", s)
+}
+
+// GenerateDDL generates the DDL for the messages in the file marked with the
+// buf.alpha.sql.v1alpha1.table option.
+//
+// A column is produced for each singular scalar or enum field of a table message.
+// Columns are ordered by field number, and tables are ordered by name, so that
+// reordering declarations within a file does not change the output, and newly-added
+// fields are appended to the end of their table. Each enum referenced by a column
+// produces an enum table that the column references, populated with the enum values.
+//
+// Types and constraints are informed by buf.validate.field constraints on the fields,
+// for example string.max_len produces a varchar column, and required produces NOT NULL.
+//
+// Returns the empty string if the file does not contain any tables.
+func GenerateDDL(fileDescriptor protoreflect.FileDescriptor, dialect Dialect) (string, error) {
+	switch dialect {
+	case DialectPostgres:
+	default:
+		return "", fmt.Errorf("unknown dialect: %v", dialect)
+	}
+	tables, enumTables, err := getTablesAndEnumTables(fileDescriptor)
+	if err != nil {
+		return "", err
+	}
+	if len(tables) == 0 {
+		return "", nil
+	}
+	return printPostgres(fileDescriptor.Path(), tables, enumTables), nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufsql
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func printPostgres(filePath string, tables []*table, enumTables []*enumTable) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "-- Code generated by protoc-gen-buf-sql. DO NOT EDIT.\n-- source: %s\n", filePath)
+	// Enum tables are shared across files, and may already exist.
+	for _, enumTable := range enumTables {
+		sb.WriteString("\n")
+		_, _ = fmt.Fprintf(&sb, "CREATE TABLE IF NOT EXISTS %s (\n", postgresQuoteIdentifier(enumTable.name))
+		sb.WriteString("  \"number\" integer PRIMARY KEY,\n")
+		sb.WriteString("  \"name\" text NOT NULL UNIQUE\n")
+		sb.WriteString(");\n")
+		if len(enumTable.values) == 0 {
+			continue
+		}
+		sb.WriteString("\n")
+		_, _ = fmt.Fprintf(&sb, "INSERT INTO %s (\"number\", \"name\") VALUES\n", postgresQuoteIdentifier(enumTable.name))
+		for i, value := range enumTable.values {
+			_, _ = fmt.Fprintf(&sb, "  (%d, %s)", value.number, postgresQuoteString(value.name))
+			if i < len(enumTable.values)-1 {
+				sb.WriteString(",")
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("ON CONFLICT (\"number\") DO NOTHING;\n")
+	}
+	for _, table := range tables {
+		sb.WriteString("\n")
+		_, _ = fmt.Fprintf(&sb, "CREATE TABLE %s (\n", postgresQuoteIdentifier(table.name))
+		lines := make([]string, 0, len(table.columns)+1)
+		for _, column := range table.columns {
+			lines = append(lines, postgresColumnDefinition(column))
+		}
+		if len(table.primaryKey) > 0 {
+			quotedPrimaryKey := make([]string, len(table.primaryKey))
+			for i, primaryKeyColumnName := range table.primaryKey {
+				quotedPrimaryKey[i] = postgresQuoteIdentifier(primaryKeyColumnName)
+			}
+			lines = append(lines, "PRIMARY KEY ("+strings.Join(quotedPrimaryKey, ", ")+")")
+		}
+		for i, line := range lines {
+			sb.WriteString("  ")
+			sb.WriteString(line)
+			if i < len(lines)-1 {
+				sb.WriteString(",")
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString(");\n")
+	}
+	return sb.String()
+}
+
+func postgresColumnDefinition(column *column) string {
+	quotedName := postgresQuoteIdentifier(column.name)
+	parts := []string{quotedName, postgresColumnType(column)}
+	if column.notNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if column.enumTable != nil {
+		parts = append(parts, "REFERENCES "+postgresQuoteIdentifier(column.enumTable.name)+" (\"number\")")
+	}
+	if check := postgresCheck(quotedName, column); check != "" {
+		parts = append(parts, "CHECK ("+check+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+func postgresColumnType(column *column) string {
+	if column.typeOverride != "" {
+		return column.typeOverride
+	}
+	switch column.kind {
+	case protoreflect.BoolKind:
+		return "boolean"
+	case protoreflect.EnumKind, protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "integer"
+	// uint32 does not fit in integer.
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind, protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "bigint"
+	// uint64 does not fit in bigint.
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "numeric(20)"
+	case protoreflect.FloatKind:
+		return "real"
+	case protoreflect.DoubleKind:
+		return "double precision"
+	case protoreflect.StringKind:
+		switch {
+		case column.uuid:
+			return "uuid"
+		case column.maxLen > 0:
+			return "varchar(" + strconv.FormatUint(column.maxLen, 10) + ")"
+		default:
+			return "text"
+		}
+	case protoreflect.BytesKind:
+		return "bytea"
+	default:
+		return "text"
+	}
+}
+
+func postgresCheck(quotedName string, column *column) string {
+	var lower, upper string
+	if column.lowerBound != nil {
+		operator := ">"
+		if column.lowerBound.inclusive {
+			operator = ">="
+		}
+		lower = quotedName + " " + operator + " " + postgresNumber(column.lowerBound.value)
+	}
+	if column.upperBound != nil {
+		operator := "<"
+		if column.upperBound.inclusive {
+			operator = "<="
+		}
+		upper = quotedName + " " + operator + " " + postgresNumber(column.upperBound.value)
+	}
+	switch {
+	case lower == "":
+		return upper
+	case upper == "":
+		return lower
+	// protovalidate treats a lower bound greater than the upper bound as an exclusive range.
+	case compareNumbers(column.lowerBound.value, column.upperBound.value) > 0:
+		return lower + " OR " + upper
+	default:
+		return lower + " AND " + upper
+	}
+}
+
+func postgresNumber(value protoreflect.Value) string {
+	switch v := value.Interface().(type) {
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// compareNumbers compares two values of the same numeric kind.
+func compareNumbers(a protoreflect.Value, b protoreflect.Value) int {
+	switch a.Interface().(type) {
+	case int32, int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case uint32, uint64:
+		return cmp.Compare(a.Uint(), b.Uint())
+	default:
+		return cmp.Compare(a.Float(), b.Float())
+	}
+}
+
+func postgresQuoteIdentifier(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func postgresQuoteString(s string) string {
+	return `'` + strings.ReplaceAll(s, `'`, `''`) + `'`
+}
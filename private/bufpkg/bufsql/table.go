@@ -0,0 +1,337 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufsql
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	sqlv1alpha1 "github.com/bufbuild/buf/private/gen/proto/go/buf/alpha/sql/v1alpha1"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+type table struct {
+	name       string
+	columns    []*column
+	primaryKey []string
+}
+
+type column struct {
+	name string
+	kind protoreflect.Kind
+	// typeOverride is the type set with the column option, if any.
+	typeOverride string
+	// maxLen is the maximum length of a string column, or 0 if unbounded.
+	maxLen uint64
+	uuid   bool
+	// notNull is true if the field has implicit presence or is required.
+	notNull bool
+	// lowerBound and upperBound are the numeric bounds of the column, if any.
+	lowerBound *bound
+	upperBound *bound
+	// enumTable is the enum table the column references, if the field is an enum.
+	enumTable *enumTable
+}
+
+type bound struct {
+	value     protoreflect.Value
+	inclusive bool
+}
+
+type enumTable struct {
+	name   string
+	values []*enumTableValue
+}
+
+type enumTableValue struct {
+	number int32
+	name   string
+}
+
+func getTablesAndEnumTables(fileDescriptor protoreflect.FileDescriptor) ([]*table, []*enumTable, error) {
+	var tables []*table
+	nameToEnumTable := make(map[string]*enumTable)
+	if err := forEachMessage(fileDescriptor.Messages(), func(messageDescriptor protoreflect.MessageDescriptor) error {
+		table, err := getTable(messageDescriptor, nameToEnumTable)
+		if err != nil {
+			return err
+		}
+		if table != nil {
+			tables = append(tables, table)
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	tableNames := make(map[string]struct{}, len(tables)+len(nameToEnumTable))
+	for _, table := range tables {
+		if _, ok := tableNames[table.name]; ok {
+			return nil, nil, fmt.Errorf("%s: duplicate table name %q", fileDescriptor.Path(), table.name)
+		}
+		tableNames[table.name] = struct{}{}
+	}
+	for name := range nameToEnumTable {
+		if _, ok := tableNames[name]; ok {
+			return nil, nil, fmt.Errorf("%s: duplicate table name %q", fileDescriptor.Path(), name)
+		}
+	}
+	slices.SortFunc(tables, func(a *table, b *table) int { return strings.Compare(a.name, b.name) })
+	enumTables := make([]*enumTable, 0, len(nameToEnumTable))
+	for _, enumTable := range nameToEnumTable {
+		enumTables = append(enumTables, enumTable)
+	}
+	slices.SortFunc(enumTables, func(a *enumTable, b *enumTable) int { return strings.Compare(a.name, b.name) })
+	return tables, enumTables, nil
+}
+
+// getTable returns nil if the message is not marked as a table.
+func getTable(messageDescriptor protoreflect.MessageDescriptor, nameToEnumTable map[string]*enumTable) (*table, error) {
+	tableOptions, err := getExtension[*sqlv1alpha1.TableOptions](messageDescriptor.Options(), sqlv1alpha1.E_Table)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", messageDescriptor.FullName(), err)
+	}
+	if tableOptions == nil {
+		return nil, nil
+	}
+	table := &table{
+		name:       tableOptions.GetName(),
+		primaryKey: tableOptions.GetPrimaryKey(),
+	}
+	if table.name == "" {
+		table.name = getDefaultTableName(messageDescriptor)
+	}
+	fieldDescriptors := messageDescriptor.Fields()
+	sortedFieldDescriptors := make([]protoreflect.FieldDescriptor, 0, fieldDescriptors.Len())
+	for i := 0; i < fieldDescriptors.Len(); i++ {
+		sortedFieldDescriptors = append(sortedFieldDescriptors, fieldDescriptors.Get(i))
+	}
+	slices.SortFunc(
+		sortedFieldDescriptors,
+		func(a protoreflect.FieldDescriptor, b protoreflect.FieldDescriptor) int {
+			return int(a.Number()) - int(b.Number())
+		},
+	)
+	columnNames := make(map[string]struct{}, len(sortedFieldDescriptors))
+	for _, fieldDescriptor := range sortedFieldDescriptors {
+		column, err := getColumn(fieldDescriptor, nameToEnumTable)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fieldDescriptor.FullName(), err)
+		}
+		if column == nil {
+			continue
+		}
+		if _, ok := columnNames[column.name]; ok {
+			return nil, fmt.Errorf("%s: duplicate column name %q", fieldDescriptor.FullName(), column.name)
+		}
+		columnNames[column.name] = struct{}{}
+		table.columns = append(table.columns, column)
+	}
+	if len(table.columns) == 0 {
+		return nil, fmt.Errorf("%s: table %q has no columns", messageDescriptor.FullName(), table.name)
+	}
+	for _, primaryKeyColumnName := range table.primaryKey {
+		if _, ok := columnNames[primaryKeyColumnName]; !ok {
+			return nil, fmt.Errorf("%s: primary key column %q not found in table %q", messageDescriptor.FullName(), primaryKeyColumnName, table.name)
+		}
+	}
+	return table, nil
+}
+
+// getColumn returns nil if the field does not produce a column.
+func getColumn(fieldDescriptor protoreflect.FieldDescriptor, nameToEnumTable map[string]*enumTable) (*column, error) {
+	columnOptions, err := getExtension[*sqlv1alpha1.ColumnOptions](fieldDescriptor.Options(), sqlv1alpha1.E_Column)
+	if err != nil {
+		return nil, err
+	}
+	if columnOptions.GetIgnore() {
+		return nil, nil
+	}
+	// Only singular scalar and enum fields produce columns.
+	if fieldDescriptor.IsList() || fieldDescriptor.IsMap() || fieldDescriptor.Message() != nil {
+		return nil, nil
+	}
+	fieldConstraints, err := getExtension[*validate.FieldConstraints](fieldDescriptor.Options(), validate.E_Field)
+	if err != nil {
+		return nil, err
+	}
+	column := &column{
+		name:         columnOptions.GetName(),
+		kind:         fieldDescriptor.Kind(),
+		typeOverride: columnOptions.GetType(),
+		notNull: !fieldDescriptor.HasPresence() ||
+			fieldDescriptor.Cardinality() == protoreflect.Required ||
+			fieldConstraints.GetRequired(),
+	}
+	if column.name == "" {
+		column.name = string(fieldDescriptor.Name())
+	}
+	if stringRules := fieldConstraints.GetString(); stringRules != nil {
+		switch {
+		case stringRules.GetUuid():
+			column.uuid = true
+		case stringRules.HasLen():
+			column.maxLen = stringRules.GetLen()
+		case stringRules.HasMaxLen():
+			column.maxLen = stringRules.GetMaxLen()
+		}
+	}
+	if err := setBounds(column, fieldConstraints); err != nil {
+		return nil, err
+	}
+	if enumDescriptor := fieldDescriptor.Enum(); enumDescriptor != nil {
+		column.enumTable = getEnumTable(enumDescriptor, nameToEnumTable)
+	}
+	return column, nil
+}
+
+// setBounds sets the bounds of numeric columns from the gt, gte, lt, and lte rules
+// of the constraints, if any.
+func setBounds(column *column, fieldConstraints *validate.FieldConstraints) error {
+	if fieldConstraints == nil {
+		return nil
+	}
+	fieldConstraintsMessage := fieldConstraints.ProtoReflect()
+	typeOneofDescriptor := fieldConstraintsMessage.Descriptor().Oneofs().ByName("type")
+	if typeOneofDescriptor == nil {
+		return errors.New("buf.validate.FieldConstraints does not have a type oneof")
+	}
+	rulesFieldDescriptor := fieldConstraintsMessage.WhichOneof(typeOneofDescriptor)
+	if rulesFieldDescriptor == nil || rulesFieldDescriptor.Message() == nil {
+		return nil
+	}
+	rulesMessage := fieldConstraintsMessage.Get(rulesFieldDescriptor).Message()
+	rulesFieldDescriptors := rulesMessage.Descriptor().Fields()
+	for _, boundFieldName := range []protoreflect.Name{"gt", "gte", "lt", "lte"} {
+		boundFieldDescriptor := rulesFieldDescriptors.ByName(boundFieldName)
+		if boundFieldDescriptor == nil || !rulesMessage.Has(boundFieldDescriptor) {
+			continue
+		}
+		// Only bounds of the same kind as the field are supported, for example
+		// timestamp bounds are not.
+		if boundFieldDescriptor.Kind() != column.kind {
+			continue
+		}
+		bound := &bound{
+			value:     rulesMessage.Get(boundFieldDescriptor),
+			inclusive: strings.HasSuffix(string(boundFieldName), "e"),
+		}
+		if strings.HasPrefix(string(boundFieldName), "g") {
+			column.lowerBound = bound
+		} else {
+			column.upperBound = bound
+		}
+	}
+	return nil
+}
+
+func getEnumTable(enumDescriptor protoreflect.EnumDescriptor, nameToEnumTable map[string]*enumTable) *enumTable {
+	name := getDefaultTableName(enumDescriptor)
+	if enumTable, ok := nameToEnumTable[name]; ok {
+		return enumTable
+	}
+	enumTable := &enumTable{
+		name: name,
+	}
+	seenNumbers := make(map[int32]struct{})
+	enumValueDescriptors := enumDescriptor.Values()
+	for i := 0; i < enumValueDescriptors.Len(); i++ {
+		enumValueDescriptor := enumValueDescriptors.Get(i)
+		number := int32(enumValueDescriptor.Number())
+		// Aliases share the row of the first value with the same number.
+		if _, ok := seenNumbers[number]; ok {
+			continue
+		}
+		seenNumbers[number] = struct{}{}
+		enumTable.values = append(
+			enumTable.values,
+			&enumTableValue{
+				number: number,
+				name:   string(enumValueDescriptor.Name()),
+			},
+		)
+	}
+	slices.SortFunc(enumTable.values, func(a *enumTableValue, b *enumTableValue) int { return int(a.number) - int(b.number) })
+	nameToEnumTable[name] = enumTable
+	return enumTable
+}
+
+// getDefaultTableName returns the lower_snake_case name of the descriptor, prefixed
+// by the names of any parent messages, for example foo_bar for the message Bar nested
+// within the message Foo.
+func getDefaultTableName(descriptor protoreflect.Descriptor) string {
+	name := strings.TrimPrefix(
+		string(descriptor.FullName()),
+		string(descriptor.ParentFile().Package())+".",
+	)
+	nameParts := strings.Split(name, ".")
+	for i, namePart := range nameParts {
+		nameParts[i] = stringutil.ToLowerSnakeCase(namePart)
+	}
+	return strings.Join(nameParts, "_")
+}
+
+func forEachMessage(
+	messageDescriptors protoreflect.MessageDescriptors,
+	f func(protoreflect.MessageDescriptor) error,
+) error {
+	for i := 0; i < messageDescriptors.Len(); i++ {
+		messageDescriptor := messageDescriptors.Get(i)
+		if messageDescriptor.IsMapEntry() {
+			continue
+		}
+		if err := f(messageDescriptor); err != nil {
+			return err
+		}
+		if err := forEachMessage(messageDescriptor.Messages(), f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getExtension returns the value of the extension on the options, or the zero
+// value if the extension is not set.
+//
+// The options are reparsed with protoregistry.GlobalTypes, so that the extension
+// is found regardless of whether it was parsed as a known extension, a dynamic
+// extension, or unknown fields.
+func getExtension[M proto.Message](options proto.Message, extensionType protoreflect.ExtensionType) (M, error) {
+	var zero M
+	if options == nil || !options.ProtoReflect().IsValid() {
+		return zero, nil
+	}
+	data, err := proto.Marshal(options)
+	if err != nil {
+		return zero, err
+	}
+	reparsedOptions := options.ProtoReflect().Type().New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: protoregistry.GlobalTypes}).Unmarshal(data, reparsedOptions); err != nil {
+		return zero, err
+	}
+	if !proto.HasExtension(reparsedOptions, extensionType) {
+		return zero, nil
+	}
+	value, ok := proto.GetExtension(reparsedOptions, extensionType).(M)
+	if !ok {
+		return zero, fmt.Errorf("unexpected type for extension %s", extensionType.TypeDescriptor().FullName())
+	}
+	return value, nil
+}
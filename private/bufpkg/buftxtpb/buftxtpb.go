@@ -0,0 +1,119 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package buftxtpb provides support for editing Protobuf text format files.
+//
+// Text format files are conventionally named with the .txtpb or .textproto
+// extensions, and indicate the message they contain with a header comment:
+//
+//	# proto-file: acme/weather/v1/weather.proto
+//	# proto-message: acme.weather.v1.Forecast
+//
+// See https://protobuf.dev/reference/protobuf/textformat-spec/.
+package buftxtpb
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	protoFileHeaderKey    = "proto-file:"
+	protoMessageHeaderKey = "proto-message:"
+)
+
+// fileExtensions are the file extensions for text format files.
+var fileExtensions = map[string]struct{}{
+	".txtpb":     {},
+	".textproto": {},
+}
+
+// IsTxtpbPath returns true if the path has a text format file extension.
+func IsTxtpbPath(path string) bool {
+	_, ok := fileExtensions[filepath.Ext(path)]
+	return ok
+}
+
+// Header is the information from the header comments of a text format file.
+type Header struct {
+	// ProtoFile is the import path of the file that contains the message, from the
+	// "# proto-file:" comment. Empty if not present.
+	ProtoFile string
+	// ProtoMessage is the fully-qualified name of the message, from the
+	// "# proto-message:" comment. Empty if not present.
+	ProtoMessage string
+}
+
+// ParseHeader parses the header comments of the text format data.
+//
+// The header is the set of comments before the first field. If a key is
+// present more than once, the first value is used. Unparseable data results
+// in an empty or partial Header.
+func ParseHeader(data []byte) Header {
+	var header Header
+	// Errors are ignored, we only care about the comments up until the first error.
+	tokens, _ := scan(data)
+	for _, token := range tokens {
+		if token.kind != tokenKindComment {
+			break
+		}
+		comment := strings.TrimSpace(strings.TrimPrefix(token.text, "#"))
+		switch {
+		case strings.HasPrefix(comment, protoFileHeaderKey) && header.ProtoFile == "":
+			header.ProtoFile = strings.TrimSpace(strings.TrimPrefix(comment, protoFileHeaderKey))
+		case strings.HasPrefix(comment, protoMessageHeaderKey) && header.ProtoMessage == "":
+			header.ProtoMessage = strings.TrimSpace(strings.TrimPrefix(comment, protoMessageHeaderKey))
+		}
+	}
+	return header
+}
+
+// Format formats the text format data and writes the result to dest.
+//
+// Fields are put on their own lines and indented by two spaces per level of
+// nesting. Comments and single blank lines between fields are preserved.
+// Separators between fields are removed.
+//
+// The data is only checked for syntax, not validated against a message.
+func Format(dest io.Writer, data []byte) error {
+	return format(dest, data)
+}
+
+// FieldPathAt returns the names of the fields of the messages enclosing the
+// byte offset in the data, from outermost to innermost.
+//
+// An empty path indicates the top-level message. Names of extension and Any
+// fields are returned with their brackets, for example "[acme.v1.ext]".
+//
+// Returns false if the offset is not at a position where a field name can be
+// written, for example within a comment, a string, or after a colon.
+func FieldPathAt(data []byte, offset int) ([]string, bool) {
+	return fieldPathAt(data, offset)
+}
+
+// TokenEndAt returns the byte offset of the end of the token that contains the
+// given byte offset.
+//
+// Returns offset if there is no token at the offset.
+func TokenEndAt(data []byte, offset int) int {
+	// Errors are ignored, we use the tokens up until the first error.
+	tokens, _ := scan(data)
+	for _, token := range tokens {
+		if token.start <= offset && offset < token.end {
+			return token.end
+		}
+	}
+	return offset
+}
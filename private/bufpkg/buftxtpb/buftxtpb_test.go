@@ -0,0 +1,216 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buftxtpb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeader(t *testing.T) {
+	t.Parallel()
+	header := ParseHeader([]byte(`# Weather forecasts.
+#proto-file: acme/weather/v1/weather.proto
+# proto-message:   acme.weather.v1.Forecast
+
+# proto-file: ignored.proto
+location: "Brooklyn"
+# proto-message: ignored.Message
+`))
+	assert.Equal(
+		t,
+		Header{
+			ProtoFile:    "acme/weather/v1/weather.proto",
+			ProtoMessage: "acme.weather.v1.Forecast",
+		},
+		header,
+	)
+	assert.Equal(t, Header{}, ParseHeader([]byte(`location: "Brooklyn"`)))
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+	testFormat(
+		t,
+		"empty",
+		``,
+		``,
+	)
+	testFormat(
+		t,
+		"header_and_fields",
+		`# proto-file: acme/weather/v1/weather.proto
+# proto-message: acme.weather.v1.Forecast
+
+
+location:"Brooklyn",   days: 3;
+temperature :-1.5e-3 # Celsius.
+`,
+		`# proto-file: acme/weather/v1/weather.proto
+# proto-message: acme.weather.v1.Forecast
+
+location: "Brooklyn"
+days: 3
+temperature: -1.5e-3 # Celsius.
+`,
+	)
+	testFormat(
+		t,
+		"nested_messages",
+		`hourly {time: 1 conditions < kind: SUNNY > }
+hourly: { # The afternoon.
+      time: 2
+
+      # It will rain.
+      conditions {kind: RAIN}
+  empty {}
+      # Trailing.
+}
+`,
+		`hourly {
+  time: 1
+  conditions <
+    kind: SUNNY
+  >
+}
+hourly: { # The afternoon.
+  time: 2
+
+  # It will rain.
+  conditions {
+    kind: RAIN
+  }
+  empty {}
+  # Trailing.
+}
+`,
+	)
+	testFormat(
+		t,
+		"lists",
+		`tags: [ "a","b" ,'c']
+hourly: [{time: 1}, # First.
+{time: 2}]
+`,
+		`tags: ["a", "b", 'c']
+hourly: [
+  {
+    time: 1
+  }, # First.
+  {
+    time: 2
+  }
+]
+`,
+	)
+	testFormat(
+		t,
+		"extensions_and_any",
+		`[acme.weather.v1.source]: "radar"
+details {
+  [type.googleapis.com/acme.weather.v1.Radar] { station: "KOKX" }
+}
+`,
+		`[acme.weather.v1.source]: "radar"
+details {
+  [type.googleapis.com/acme.weather.v1.Radar] {
+    station: "KOKX"
+  }
+}
+`,
+	)
+	testFormat(
+		t,
+		"adjacent_strings",
+		`summary: "Cloudy "
+  "with a chance of meatballs"
+title: "Weather " "report"
+`,
+		`summary: "Cloudy "
+  "with a chance of meatballs"
+title: "Weather " "report"
+`,
+	)
+	testFormatError(t, "unterminated_string", `location: "Brooklyn`, `line 1: unterminated string`)
+	testFormatError(t, "unterminated_message", `hourly {`, `unexpected end of file, expected }`)
+	testFormatError(t, "unexpected_token", `hourly }`, `line 1: unexpected "}"`)
+	testFormatError(
+		t,
+		"comment_within_field",
+		"location: # Comment.\n\"Brooklyn\"",
+		`line 1: comments are only supported between fields and list elements`,
+	)
+}
+
+func TestFieldPathAt(t *testing.T) {
+	t.Parallel()
+	testFieldPathAt(t, "top_level", `|`, []string{})
+	testFieldPathAt(t, "top_level_partial", `location: "Brooklyn" da|`, []string{})
+	testFieldPathAt(t, "nested", `hourly { time: 1 conditions < | > }`, []string{"hourly", "conditions"})
+	testFieldPathAt(t, "nested_colon", `hourly: { time: 1 } details: { kind|`, []string{"details"})
+	testFieldPathAt(t, "list_of_messages", `hourly: [{ time: 1 }, { |`, []string{"hourly"})
+	testFieldPathAt(t, "list_of_messages_without_colon", `hourly [{ time: 1 }, { |`, []string{"hourly"})
+	testFieldPathAt(t, "after_list_of_messages", `hourly [{ time: 1 }] |`, []string{})
+	testFieldPathAt(t, "after_extension", `location: "Brooklyn" [acme.ext] { kind: SUNNY } |`, []string{})
+	testFieldPathAt(t, "extension", `[acme.ext] { |`, []string{"[acme.ext]"})
+	testFieldPathAt(t, "after_colon", `location: |`, nil)
+	testFieldPathAt(t, "in_list_of_scalars", `tags: ["a", |`, nil)
+	testFieldPathAt(t, "in_string", `location: "Bro|`, nil)
+	testFieldPathAt(t, "in_comment", `# Comment |`, nil)
+	testFieldPathAt(t, "in_extension_name", `[acme.|`, nil)
+}
+
+func testFormat(t *testing.T, name string, input string, expected string) {
+	t.Run(name, func(t *testing.T) {
+		t.Parallel()
+		buffer := bytes.NewBuffer(nil)
+		require.NoError(t, Format(buffer, []byte(input)))
+		assert.Equal(t, expected, buffer.String())
+		// Formatting is idempotent.
+		formatted := bytes.NewBuffer(nil)
+		require.NoError(t, Format(formatted, buffer.Bytes()))
+		assert.Equal(t, expected, formatted.String())
+	})
+}
+
+func testFormatError(t *testing.T, name string, input string, expectedError string) {
+	t.Run(name, func(t *testing.T) {
+		t.Parallel()
+		err := Format(bytes.NewBuffer(nil), []byte(input))
+		assert.EqualError(t, err, expectedError)
+	})
+}
+
+// testFieldPathAt tests FieldPathAt at the position of the "|" in the input.
+//
+// A nil expected path indicates that FieldPathAt is expected to return false.
+func testFieldPathAt(t *testing.T, name string, input string, expected []string) {
+	t.Run(name, func(t *testing.T) {
+		t.Parallel()
+		offset := strings.Index(input, "|")
+		require.NotEqual(t, -1, offset)
+		path, ok := FieldPathAt([]byte(strings.Replace(input, "|", "", 1)), offset)
+		if expected == nil {
+			assert.False(t, ok)
+			return
+		}
+		require.True(t, ok)
+		assert.Equal(t, expected, path)
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buftxtpb
+
+import "strings"
+
+// frame is a message or list that encloses a position.
+type frame struct {
+	// The name of the field that the message or list is the value of.
+	name   string
+	isList bool
+}
+
+func fieldPathAt(data []byte, offset int) ([]string, bool) {
+	if offset < 0 || offset > len(data) {
+		return nil, false
+	}
+	// Only consider the data before the offset, so that an unterminated string
+	// or comment before the offset results in an error or a trailing comment.
+	tokens, err := scan(data[:offset])
+	if err != nil {
+		return nil, false
+	}
+	if len(tokens) > 0 {
+		last := tokens[len(tokens)-1]
+		if last.end == offset {
+			switch last.kind {
+			case tokenKindComment, tokenKindString, tokenKindNumber:
+				return nil, false
+			case tokenKindIdent:
+				// This is the partial name of the field being completed.
+				tokens = tokens[:len(tokens)-1]
+			}
+		}
+	}
+	significantTokens := make([]*token, 0, len(tokens))
+	for _, token := range tokens {
+		if token.kind != tokenKindComment {
+			significantTokens = append(significantTokens, token)
+		}
+	}
+	var stack []frame
+	inList := func() bool {
+		return len(stack) > 0 && stack[len(stack)-1].isList
+	}
+	// Whether the next token is expected to be a field name, as opposed to a
+	// colon or a value.
+	expectName := true
+	// The name of the most recent field.
+	var name string
+	for i := 0; i < len(significantTokens); i++ {
+		token := significantTokens[i]
+		if expectName {
+			switch {
+			case token.kind == tokenKindIdent:
+				name = token.text
+				expectName = false
+			case token.isPunct("["):
+				// This is an extension or Any name.
+				var builder strings.Builder
+				for ; i < len(significantTokens); i++ {
+					builder.WriteString(significantTokens[i].text)
+					if significantTokens[i].isPunct("]") {
+						break
+					}
+				}
+				name = builder.String()
+				expectName = false
+			case token.isPunct("}"), token.isPunct(">"):
+				if len(stack) > 0 && !inList() {
+					stack = stack[:len(stack)-1]
+				}
+				// Within a list, a message is followed by a comma or the end of the list.
+				expectName = !inList()
+			}
+			continue
+		}
+		switch {
+		case token.isPunct("{"), token.isPunct("<"):
+			if inList() {
+				stack = append(stack, frame{name: stack[len(stack)-1].name})
+			} else {
+				stack = append(stack, frame{name: name})
+			}
+			expectName = true
+		case token.isPunct("["):
+			if !inList() {
+				stack = append(stack, frame{name: name, isList: true})
+			}
+		case token.isPunct("]"):
+			if inList() {
+				stack = stack[:len(stack)-1]
+			}
+			expectName = true
+		case token.kind == tokenKindIdent, token.kind == tokenKindNumber, token.kind == tokenKindString:
+			// This is a scalar value. Within a list, it is followed by a comma
+			// or the end of the list.
+			expectName = !inList()
+		}
+	}
+	if !expectName {
+		return nil, false
+	}
+	path := make([]string, 0, len(stack))
+	for _, frame := range stack {
+		// Messages within lists have the name of the list, so lists are skipped.
+		if !frame.isList {
+			path = append(path, frame.name)
+		}
+	}
+	return path, true
+}
@@ -0,0 +1,199 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buftxtpb
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+const indentString = "  "
+
+// printer prints a messageNode in the canonical format.
+type printer struct {
+	buffer bytes.Buffer
+}
+
+func (p *printer) printMessageBody(message *messageNode, indent int) {
+	// Whether anything has been printed in this message body. Blank lines are
+	// only preserved between elements, never at the start of a body.
+	printedElement := false
+	for _, field := range message.fields {
+		for _, comment := range field.leadingComments {
+			p.printComment(comment, indent, printedElement)
+			printedElement = true
+		}
+		if printedElement && field.nameToken.blankLineBefore {
+			p.buffer.WriteString("\n")
+		}
+		p.printIndent(indent)
+		p.buffer.WriteString(field.name)
+		value := field.value
+		if value.message != nil {
+			if field.hasColon {
+				p.buffer.WriteString(":")
+			}
+			p.buffer.WriteString(" ")
+			p.printMessageValue(value, indent)
+		} else {
+			p.buffer.WriteString(": ")
+			p.printNonMessageValue(value, indent)
+		}
+		p.printTrailingComment(field.trailingComment)
+		p.buffer.WriteString("\n")
+		printedElement = true
+	}
+	for _, comment := range message.trailingComments {
+		p.printComment(comment, indent, printedElement)
+		printedElement = true
+	}
+}
+
+// printMessageValue prints a message value starting at the current position,
+// with the closing delimiter at the given indent.
+func (p *printer) printMessageValue(value *valueNode, indent int) {
+	p.buffer.WriteString(value.openDelimiter)
+	if value.openComment == nil && len(value.message.fields) == 0 && len(value.message.trailingComments) == 0 {
+		p.buffer.WriteString(value.closeDelimiter)
+		return
+	}
+	p.printTrailingComment(value.openComment)
+	p.buffer.WriteString("\n")
+	p.printMessageBody(value.message, indent+1)
+	p.printIndent(indent)
+	p.buffer.WriteString(value.closeDelimiter)
+}
+
+// printNonMessageValue prints a scalar or list value starting at the current
+// position, with any continuation lines at the given indent.
+func (p *printer) printNonMessageValue(value *valueNode, indent int) {
+	if !value.isList {
+		p.printScalarValue(value, indent)
+		return
+	}
+	if isInlineList(value) {
+		p.buffer.WriteString("[")
+		for i, element := range value.listElements {
+			if i > 0 {
+				p.buffer.WriteString(", ")
+			}
+			p.printScalarValue(element, indent)
+		}
+		p.buffer.WriteString("]")
+		return
+	}
+	p.buffer.WriteString("[\n")
+	printedElement := false
+	for i, element := range value.listElements {
+		for _, comment := range element.leadingComments {
+			p.printComment(comment, indent+1, printedElement)
+			printedElement = true
+		}
+		p.printIndent(indent + 1)
+		if element.message != nil {
+			p.printMessageValue(element, indent+1)
+		} else {
+			p.printScalarValue(element, indent+1)
+		}
+		if i < len(value.listElements)-1 {
+			p.buffer.WriteString(",")
+		}
+		p.printTrailingComment(element.trailingComment)
+		p.buffer.WriteString("\n")
+		printedElement = true
+	}
+	for _, comment := range value.listTrailingComments {
+		p.printComment(comment, indent+1, printedElement)
+		printedElement = true
+	}
+	p.printIndent(indent)
+	p.buffer.WriteString("]")
+}
+
+// printScalarValue prints a scalar value starting at the current position.
+//
+// Adjacent strings that were on separate lines are kept on separate lines,
+// indented one level past the given indent.
+func (p *printer) printScalarValue(value *valueNode, indent int) {
+	for i, scalarToken := range value.scalarTokens {
+		if i > 0 {
+			previous := value.scalarTokens[i-1]
+			switch {
+			case previous.isPunct("-"):
+			case scalarToken.line != previous.line:
+				p.buffer.WriteString("\n")
+				p.printIndent(indent + 1)
+			default:
+				p.buffer.WriteString(" ")
+			}
+		}
+		p.buffer.WriteString(scalarToken.text)
+	}
+}
+
+// printComment prints a comment on its own line, preceded by a blank line
+// if there was one in the original file and something has already been
+// printed in the current body.
+func (p *printer) printComment(comment *token, indent int, printedElement bool) {
+	if printedElement && comment.blankLineBefore {
+		p.buffer.WriteString("\n")
+	}
+	p.printIndent(indent)
+	p.buffer.WriteString(strings.TrimRight(comment.text, " \t\r"))
+	p.buffer.WriteString("\n")
+}
+
+func (p *printer) printTrailingComment(comment *token) {
+	if comment == nil {
+		return
+	}
+	p.buffer.WriteString(" ")
+	p.buffer.WriteString(strings.TrimRight(comment.text, " \t\r"))
+}
+
+func (p *printer) printIndent(indent int) {
+	p.buffer.WriteString(strings.Repeat(indentString, indent))
+}
+
+// isInlineList returns true if the list can be printed on a single line, that is
+// it only contains scalar values that all fit on a single line, and has no comments.
+func isInlineList(value *valueNode) bool {
+	if len(value.listTrailingComments) > 0 {
+		return false
+	}
+	for _, element := range value.listElements {
+		if element.message != nil || len(element.leadingComments) > 0 || element.trailingComment != nil {
+			return false
+		}
+		for _, scalarToken := range element.scalarTokens[1:] {
+			if scalarToken.line != element.scalarTokens[0].line {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func format(dest io.Writer, data []byte) error {
+	message, err := parse(data)
+	if err != nil {
+		return err
+	}
+	printer := &printer{}
+	printer.printMessageBody(message, 0)
+	_, err = dest.Write(printer.buffer.Bytes())
+	return err
+}
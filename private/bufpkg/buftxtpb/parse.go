@@ -0,0 +1,276 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buftxtpb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// messageNode is the body of a message, either the top-level message of a
+// file or a message value delimited by braces or angle brackets.
+type messageNode struct {
+	fields []*fieldNode
+	// The comments after the last field, before the closing delimiter or
+	// the end of the file.
+	trailingComments []*token
+}
+
+// fieldNode is a single field within a message.
+type fieldNode struct {
+	leadingComments []*token
+	// The first token of the name.
+	nameToken *token
+	// The full name, including brackets for extension and Any names.
+	name            string
+	hasColon        bool
+	value           *valueNode
+	trailingComment *token
+}
+
+// valueNode is the value of a field or an element of a list.
+type valueNode struct {
+	// Only set for elements of lists.
+	leadingComments []*token
+	// Only set for elements of lists.
+	trailingComment *token
+
+	// Set for scalar values. Contains a single token, a sign followed by a
+	// number or identifier, or multiple adjacent strings.
+	scalarTokens []*token
+
+	// Set for message values.
+	message *messageNode
+	// The opening and closing delimiters of a message value.
+	openDelimiter  string
+	closeDelimiter string
+	// A comment on the same line as the opening delimiter.
+	openComment *token
+
+	// Set for list values.
+	isList               bool
+	listElements         []*valueNode
+	listTrailingComments []*token
+}
+
+// parser parses tokens into a messageNode.
+type parser struct {
+	tokens []*token
+	index  int
+}
+
+// parse parses the data into the top-level messageNode.
+func parse(data []byte) (*messageNode, error) {
+	tokens, err := scan(data)
+	if err != nil {
+		return nil, err
+	}
+	parser := &parser{tokens: tokens}
+	return parser.parseMessage("")
+}
+
+func (p *parser) parseMessage(closeDelimiter string) (*messageNode, error) {
+	message := &messageNode{}
+	for {
+		comments := p.takeComments()
+		token := p.peek()
+		if token == nil {
+			if closeDelimiter != "" {
+				return nil, p.newEOFError(closeDelimiter)
+			}
+			message.trailingComments = comments
+			return message, nil
+		}
+		if closeDelimiter != "" && token.isPunct(closeDelimiter) {
+			p.index++
+			message.trailingComments = comments
+			return message, nil
+		}
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		field.leadingComments = comments
+		if next := p.peek(); next.isPunct(",") || next.isPunct(";") {
+			p.index++
+		}
+		field.trailingComment = p.takeTrailingComment()
+		message.fields = append(message.fields, field)
+	}
+}
+
+func (p *parser) parseField() (*fieldNode, error) {
+	field := &fieldNode{
+		nameToken: p.peek(),
+	}
+	switch token := p.next(); {
+	case token.kind == tokenKindIdent:
+		field.name = token.text
+	case token.isPunct("["):
+		var builder strings.Builder
+		builder.WriteString(token.text)
+		for {
+			token := p.next()
+			if token == nil {
+				return nil, p.newEOFError("]")
+			}
+			switch token.kind {
+			case tokenKindIdent, tokenKindPunct:
+			default:
+				return nil, newUnexpectedTokenError(token)
+			}
+			builder.WriteString(token.text)
+			if token.isPunct("]") {
+				break
+			}
+		}
+		field.name = builder.String()
+	default:
+		return nil, newUnexpectedTokenError(token)
+	}
+	if p.peek().isPunct(":") {
+		p.index++
+		field.hasColon = true
+	}
+	value, err := p.parseValue(true)
+	if err != nil {
+		return nil, err
+	}
+	field.value = value
+	return field, nil
+}
+
+func (p *parser) parseValue(allowList bool) (*valueNode, error) {
+	valueToken := p.next()
+	if valueToken == nil {
+		return nil, p.newEOFError("value")
+	}
+	value := &valueNode{}
+	switch {
+	case valueToken.isPunct("{"), valueToken.isPunct("<"):
+		value.openDelimiter = valueToken.text
+		value.closeDelimiter = "}"
+		if valueToken.text == "<" {
+			value.closeDelimiter = ">"
+		}
+		value.openComment = p.takeTrailingComment()
+		message, err := p.parseMessage(value.closeDelimiter)
+		if err != nil {
+			return nil, err
+		}
+		value.message = message
+	case valueToken.isPunct("[") && allowList:
+		value.isList = true
+		for {
+			comments := p.takeComments()
+			if p.peek().isPunct("]") {
+				p.index++
+				value.listTrailingComments = comments
+				break
+			}
+			element, err := p.parseValue(false)
+			if err != nil {
+				return nil, err
+			}
+			element.leadingComments = comments
+			if next := p.peek(); next.isPunct(",") {
+				p.index++
+			} else if !next.isPunct("]") {
+				if next == nil {
+					return nil, p.newEOFError("]")
+				}
+				if next.kind != tokenKindComment {
+					return nil, newUnexpectedTokenError(next)
+				}
+			}
+			element.trailingComment = p.takeTrailingComment()
+			value.listElements = append(value.listElements, element)
+		}
+	case valueToken.isPunct("-"):
+		next := p.next()
+		if next == nil {
+			return nil, p.newEOFError("number")
+		}
+		if next.kind != tokenKindNumber && next.kind != tokenKindIdent {
+			return nil, newUnexpectedTokenError(next)
+		}
+		value.scalarTokens = []*token{valueToken, next}
+	case valueToken.kind == tokenKindString:
+		value.scalarTokens = []*token{valueToken}
+		for p.peek() != nil && p.peek().kind == tokenKindString {
+			value.scalarTokens = append(value.scalarTokens, p.next())
+		}
+	case valueToken.kind == tokenKindIdent, valueToken.kind == tokenKindNumber:
+		value.scalarTokens = []*token{valueToken}
+	default:
+		return nil, newUnexpectedTokenError(valueToken)
+	}
+	return value, nil
+}
+
+// peek returns the next token without consuming it, or nil if there are
+// no more tokens.
+func (p *parser) peek() *token {
+	if p.index >= len(p.tokens) {
+		return nil
+	}
+	return p.tokens[p.index]
+}
+
+// next consumes and returns the next token, or nil if there are no more tokens.
+//
+// Comments are not allowed where next is called.
+func (p *parser) next() *token {
+	token := p.peek()
+	if token != nil {
+		p.index++
+	}
+	return token
+}
+
+// takeComments consumes all comments at the current position.
+func (p *parser) takeComments() []*token {
+	var comments []*token
+	for token := p.peek(); token != nil && token.kind == tokenKindComment; token = p.peek() {
+		comments = append(comments, token)
+		p.index++
+	}
+	return comments
+}
+
+// takeTrailingComment consumes the comment at the current position if it is
+// on the same line as the previous token.
+func (p *parser) takeTrailingComment() *token {
+	token := p.peek()
+	if token == nil || token.kind != tokenKindComment || p.index == 0 {
+		return nil
+	}
+	if token.line != p.tokens[p.index-1].line {
+		return nil
+	}
+	p.index++
+	return token
+}
+
+func (p *parser) newEOFError(expected string) error {
+	return fmt.Errorf("unexpected end of file, expected %s", expected)
+}
+
+func newUnexpectedTokenError(token *token) error {
+	if token.kind == tokenKindComment {
+		return fmt.Errorf("line %d: comments are only supported between fields and list elements", token.line+1)
+	}
+	return fmt.Errorf("line %d: unexpected %q", token.line+1, token.text)
+}
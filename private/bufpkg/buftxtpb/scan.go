@@ -0,0 +1,142 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buftxtpb
+
+import "fmt"
+
+const (
+	tokenKindIdent tokenKind = iota + 1
+	tokenKindNumber
+	tokenKindString
+	tokenKindPunct
+	tokenKindComment
+)
+
+// tokenKind is the kind of a token.
+type tokenKind int
+
+// token is a single token in a text format file.
+type token struct {
+	kind tokenKind
+	text string
+	// The byte offsets of the start and end of the token.
+	start int
+	end   int
+	// The zero-indexed line the token starts on. Tokens never span lines.
+	line int
+	// Whether there is at least one blank line between this token and the
+	// previous token.
+	blankLineBefore bool
+}
+
+// isPunct returns true if the token is the given punctuation.
+func (t *token) isPunct(punct string) bool {
+	return t != nil && t.kind == tokenKindPunct && t.text == punct
+}
+
+// scan splits the data into tokens.
+//
+// If an error occurs, the tokens scanned up to the error are returned along
+// with the error.
+func scan(data []byte) ([]*token, error) {
+	var tokens []*token
+	line := 0
+	lastTokenLine := -1
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+			continue
+		}
+		start := i
+		var kind tokenKind
+		switch {
+		case c == '#':
+			kind = tokenKindComment
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case c == '"' || c == '\'':
+			kind = tokenKindString
+			i++
+			for {
+				if i >= len(data) || data[i] == '\n' {
+					return tokens, newScanError(line, "unterminated string")
+				}
+				if data[i] == '\\' {
+					i += 2
+					continue
+				}
+				i++
+				if data[i-1] == c {
+					break
+				}
+			}
+		case isDigit(c) || (c == '.' && i+1 < len(data) && isDigit(data[i+1])):
+			kind = tokenKindNumber
+			isHex := c == '0' && i+1 < len(data) && (data[i+1] == 'x' || data[i+1] == 'X')
+			for i < len(data) {
+				d := data[i]
+				if isLetter(d) || isDigit(d) || d == '.' {
+					i++
+					continue
+				}
+				if (d == '+' || d == '-') && !isHex && (data[i-1] == 'e' || data[i-1] == 'E') {
+					i++
+					continue
+				}
+				break
+			}
+		case isLetter(c):
+			kind = tokenKindIdent
+			for i < len(data) && (isLetter(data[i]) || isDigit(data[i])) {
+				i++
+			}
+		case c == '{' || c == '}' || c == '<' || c == '>' || c == '[' || c == ']' ||
+			c == ':' || c == ',' || c == ';' || c == '-' || c == '/' || c == '.':
+			kind = tokenKindPunct
+			i++
+		default:
+			return tokens, newScanError(line, fmt.Sprintf("unexpected character %q", c))
+		}
+		tokens = append(tokens, &token{
+			kind:            kind,
+			text:            string(data[start:i]),
+			start:           start,
+			end:             i,
+			line:            line,
+			blankLineBefore: lastTokenLine >= 0 && line > lastTokenLine+1,
+		})
+		lastTokenLine = line
+	}
+	return tokens, nil
+}
+
+func newScanError(line int, message string) error {
+	return fmt.Errorf("line %d: %s", line+1, message)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || c == '_'
+}
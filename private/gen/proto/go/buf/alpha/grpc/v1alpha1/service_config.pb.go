@@ -0,0 +1,790 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.4
+// 	protoc        (unknown)
+// source: buf/alpha/grpc/v1alpha1/service_config.proto
+
+package grpcv1alpha1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MethodConfig configures the client-side policies for calls to methods.
+//
+// This mirrors the MethodConfig of the gRPC service config, see
+// https://github.com/grpc/grpc/blob/master/doc/service_config.md.
+type MethodConfig struct {
+	state                              protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Timeout                 *durationpb.Duration   `protobuf:"bytes,1,opt,name=timeout,proto3"`
+	xxx_hidden_WaitForReady            bool                   `protobuf:"varint,2,opt,name=wait_for_ready,json=waitForReady,proto3,oneof"`
+	xxx_hidden_MaxRequestMessageBytes  uint32                 `protobuf:"varint,3,opt,name=max_request_message_bytes,json=maxRequestMessageBytes,proto3,oneof"`
+	xxx_hidden_MaxResponseMessageBytes uint32                 `protobuf:"varint,4,opt,name=max_response_message_bytes,json=maxResponseMessageBytes,proto3,oneof"`
+	xxx_hidden_Policy                  isMethodConfig_Policy  `protobuf_oneof:"policy"`
+	XXX_raceDetectHookData             protoimpl.RaceDetectHookData
+	XXX_presence                       [1]uint32
+	unknownFields                      protoimpl.UnknownFields
+	sizeCache                          protoimpl.SizeCache
+}
+
+func (x *MethodConfig) Reset() {
+	*x = MethodConfig{}
+	mi := &file_buf_alpha_grpc_v1alpha1_service_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MethodConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MethodConfig) ProtoMessage() {}
+
+func (x *MethodConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_buf_alpha_grpc_v1alpha1_service_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *MethodConfig) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.xxx_hidden_Timeout
+	}
+	return nil
+}
+
+func (x *MethodConfig) GetWaitForReady() bool {
+	if x != nil {
+		return x.xxx_hidden_WaitForReady
+	}
+	return false
+}
+
+func (x *MethodConfig) GetMaxRequestMessageBytes() uint32 {
+	if x != nil {
+		return x.xxx_hidden_MaxRequestMessageBytes
+	}
+	return 0
+}
+
+func (x *MethodConfig) GetMaxResponseMessageBytes() uint32 {
+	if x != nil {
+		return x.xxx_hidden_MaxResponseMessageBytes
+	}
+	return 0
+}
+
+func (x *MethodConfig) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		if x, ok := x.xxx_hidden_Policy.(*methodConfig_RetryPolicy); ok {
+			return x.RetryPolicy
+		}
+	}
+	return nil
+}
+
+func (x *MethodConfig) GetHedgingPolicy() *HedgingPolicy {
+	if x != nil {
+		if x, ok := x.xxx_hidden_Policy.(*methodConfig_HedgingPolicy); ok {
+			return x.HedgingPolicy
+		}
+	}
+	return nil
+}
+
+func (x *MethodConfig) SetTimeout(v *durationpb.Duration) {
+	x.xxx_hidden_Timeout = v
+}
+
+func (x *MethodConfig) SetWaitForReady(v bool) {
+	x.xxx_hidden_WaitForReady = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 5)
+}
+
+func (x *MethodConfig) SetMaxRequestMessageBytes(v uint32) {
+	x.xxx_hidden_MaxRequestMessageBytes = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 5)
+}
+
+func (x *MethodConfig) SetMaxResponseMessageBytes(v uint32) {
+	x.xxx_hidden_MaxResponseMessageBytes = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 5)
+}
+
+func (x *MethodConfig) SetRetryPolicy(v *RetryPolicy) {
+	if v == nil {
+		x.xxx_hidden_Policy = nil
+		return
+	}
+	x.xxx_hidden_Policy = &methodConfig_RetryPolicy{v}
+}
+
+func (x *MethodConfig) SetHedgingPolicy(v *HedgingPolicy) {
+	if v == nil {
+		x.xxx_hidden_Policy = nil
+		return
+	}
+	x.xxx_hidden_Policy = &methodConfig_HedgingPolicy{v}
+}
+
+func (x *MethodConfig) HasTimeout() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Timeout != nil
+}
+
+func (x *MethodConfig) HasWaitForReady() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *MethodConfig) HasMaxRequestMessageBytes() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *MethodConfig) HasMaxResponseMessageBytes() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *MethodConfig) HasPolicy() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Policy != nil
+}
+
+func (x *MethodConfig) HasRetryPolicy() bool {
+	if x == nil {
+		return false
+	}
+	_, ok := x.xxx_hidden_Policy.(*methodConfig_RetryPolicy)
+	return ok
+}
+
+func (x *MethodConfig) HasHedgingPolicy() bool {
+	if x == nil {
+		return false
+	}
+	_, ok := x.xxx_hidden_Policy.(*methodConfig_HedgingPolicy)
+	return ok
+}
+
+func (x *MethodConfig) ClearTimeout() {
+	x.xxx_hidden_Timeout = nil
+}
+
+func (x *MethodConfig) ClearWaitForReady() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_WaitForReady = false
+}
+
+func (x *MethodConfig) ClearMaxRequestMessageBytes() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_MaxRequestMessageBytes = 0
+}
+
+func (x *MethodConfig) ClearMaxResponseMessageBytes() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_MaxResponseMessageBytes = 0
+}
+
+func (x *MethodConfig) ClearPolicy() {
+	x.xxx_hidden_Policy = nil
+}
+
+func (x *MethodConfig) ClearRetryPolicy() {
+	if _, ok := x.xxx_hidden_Policy.(*methodConfig_RetryPolicy); ok {
+		x.xxx_hidden_Policy = nil
+	}
+}
+
+func (x *MethodConfig) ClearHedgingPolicy() {
+	if _, ok := x.xxx_hidden_Policy.(*methodConfig_HedgingPolicy); ok {
+		x.xxx_hidden_Policy = nil
+	}
+}
+
+const MethodConfig_Policy_not_set_case case_MethodConfig_Policy = 0
+const MethodConfig_RetryPolicy_case case_MethodConfig_Policy = 5
+const MethodConfig_HedgingPolicy_case case_MethodConfig_Policy = 6
+
+func (x *MethodConfig) WhichPolicy() case_MethodConfig_Policy {
+	if x == nil {
+		return MethodConfig_Policy_not_set_case
+	}
+	switch x.xxx_hidden_Policy.(type) {
+	case *methodConfig_RetryPolicy:
+		return MethodConfig_RetryPolicy_case
+	case *methodConfig_HedgingPolicy:
+		return MethodConfig_HedgingPolicy_case
+	default:
+		return MethodConfig_Policy_not_set_case
+	}
+}
+
+type MethodConfig_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// The default timeout for calls.
+	Timeout *durationpb.Duration
+	// Whether calls wait for the channel to be ready instead of failing fast.
+	WaitForReady *bool
+	// The maximum size of a request message in bytes.
+	MaxRequestMessageBytes *uint32
+	// The maximum size of a response message in bytes.
+	MaxResponseMessageBytes *uint32
+	// At most one of retry_policy and hedging_policy can be set.
+
+	// Fields of oneof xxx_hidden_Policy:
+	// The policy for retrying failed calls.
+	RetryPolicy *RetryPolicy
+	// The policy for sending hedged calls.
+	HedgingPolicy *HedgingPolicy
+	// -- end of xxx_hidden_Policy
+}
+
+func (b0 MethodConfig_builder) Build() *MethodConfig {
+	m0 := &MethodConfig{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Timeout = b.Timeout
+	if b.WaitForReady != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 5)
+		x.xxx_hidden_WaitForReady = *b.WaitForReady
+	}
+	if b.MaxRequestMessageBytes != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 5)
+		x.xxx_hidden_MaxRequestMessageBytes = *b.MaxRequestMessageBytes
+	}
+	if b.MaxResponseMessageBytes != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 5)
+		x.xxx_hidden_MaxResponseMessageBytes = *b.MaxResponseMessageBytes
+	}
+	if b.RetryPolicy != nil {
+		x.xxx_hidden_Policy = &methodConfig_RetryPolicy{b.RetryPolicy}
+	}
+	if b.HedgingPolicy != nil {
+		x.xxx_hidden_Policy = &methodConfig_HedgingPolicy{b.HedgingPolicy}
+	}
+	return m0
+}
+
+type case_MethodConfig_Policy protoreflect.FieldNumber
+
+func (x case_MethodConfig_Policy) String() string {
+	md := file_buf_alpha_grpc_v1alpha1_service_config_proto_msgTypes[0].Descriptor()
+	if x == 0 {
+		return "not set"
+	}
+	return protoimpl.X.MessageFieldStringOf(md, protoreflect.FieldNumber(x))
+}
+
+type isMethodConfig_Policy interface {
+	isMethodConfig_Policy()
+}
+
+type methodConfig_RetryPolicy struct {
+	// The policy for retrying failed calls.
+	RetryPolicy *RetryPolicy `protobuf:"bytes,5,opt,name=retry_policy,json=retryPolicy,proto3,oneof"`
+}
+
+type methodConfig_HedgingPolicy struct {
+	// The policy for sending hedged calls.
+	HedgingPolicy *HedgingPolicy `protobuf:"bytes,6,opt,name=hedging_policy,json=hedgingPolicy,proto3,oneof"`
+}
+
+func (*methodConfig_RetryPolicy) isMethodConfig_Policy() {}
+
+func (*methodConfig_HedgingPolicy) isMethodConfig_Policy() {}
+
+// RetryPolicy configures retries of failed calls.
+type RetryPolicy struct {
+	state                           protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_MaxAttempts          uint32                 `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3"`
+	xxx_hidden_InitialBackoff       *durationpb.Duration   `protobuf:"bytes,2,opt,name=initial_backoff,json=initialBackoff,proto3"`
+	xxx_hidden_MaxBackoff           *durationpb.Duration   `protobuf:"bytes,3,opt,name=max_backoff,json=maxBackoff,proto3"`
+	xxx_hidden_BackoffMultiplier    float64                `protobuf:"fixed64,4,opt,name=backoff_multiplier,json=backoffMultiplier,proto3"`
+	xxx_hidden_RetryableStatusCodes []string               `protobuf:"bytes,5,rep,name=retryable_status_codes,json=retryableStatusCodes,proto3"`
+	unknownFields                   protoimpl.UnknownFields
+	sizeCache                       protoimpl.SizeCache
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
+	mi := &file_buf_alpha_grpc_v1alpha1_service_config_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPolicy) ProtoMessage() {}
+
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_buf_alpha_grpc_v1alpha1_service_config_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *RetryPolicy) GetMaxAttempts() uint32 {
+	if x != nil {
+		return x.xxx_hidden_MaxAttempts
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetInitialBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.xxx_hidden_InitialBackoff
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetMaxBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.xxx_hidden_MaxBackoff
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetBackoffMultiplier() float64 {
+	if x != nil {
+		return x.xxx_hidden_BackoffMultiplier
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetRetryableStatusCodes() []string {
+	if x != nil {
+		return x.xxx_hidden_RetryableStatusCodes
+	}
+	return nil
+}
+
+func (x *RetryPolicy) SetMaxAttempts(v uint32) {
+	x.xxx_hidden_MaxAttempts = v
+}
+
+func (x *RetryPolicy) SetInitialBackoff(v *durationpb.Duration) {
+	x.xxx_hidden_InitialBackoff = v
+}
+
+func (x *RetryPolicy) SetMaxBackoff(v *durationpb.Duration) {
+	x.xxx_hidden_MaxBackoff = v
+}
+
+func (x *RetryPolicy) SetBackoffMultiplier(v float64) {
+	x.xxx_hidden_BackoffMultiplier = v
+}
+
+func (x *RetryPolicy) SetRetryableStatusCodes(v []string) {
+	x.xxx_hidden_RetryableStatusCodes = v
+}
+
+func (x *RetryPolicy) HasInitialBackoff() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_InitialBackoff != nil
+}
+
+func (x *RetryPolicy) HasMaxBackoff() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_MaxBackoff != nil
+}
+
+func (x *RetryPolicy) ClearInitialBackoff() {
+	x.xxx_hidden_InitialBackoff = nil
+}
+
+func (x *RetryPolicy) ClearMaxBackoff() {
+	x.xxx_hidden_MaxBackoff = nil
+}
+
+type RetryPolicy_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// The maximum number of attempts, including the original call.
+	//
+	// Must be greater than 1.
+	MaxAttempts uint32
+	// The backoff before the first retry.
+	//
+	// Must be greater than 0.
+	InitialBackoff *durationpb.Duration
+	// The maximum backoff between retries.
+	//
+	// Must be greater than 0.
+	MaxBackoff *durationpb.Duration
+	// The multiplier applied to the backoff after each retry.
+	//
+	// Must be greater than 0.
+	BackoffMultiplier float64
+	// The status codes that are retried, such as "UNAVAILABLE".
+	//
+	// Must not be empty.
+	RetryableStatusCodes []string
+}
+
+func (b0 RetryPolicy_builder) Build() *RetryPolicy {
+	m0 := &RetryPolicy{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_MaxAttempts = b.MaxAttempts
+	x.xxx_hidden_InitialBackoff = b.InitialBackoff
+	x.xxx_hidden_MaxBackoff = b.MaxBackoff
+	x.xxx_hidden_BackoffMultiplier = b.BackoffMultiplier
+	x.xxx_hidden_RetryableStatusCodes = b.RetryableStatusCodes
+	return m0
+}
+
+// HedgingPolicy configures hedged calls.
+type HedgingPolicy struct {
+	state                          protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_MaxAttempts         uint32                 `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3"`
+	xxx_hidden_HedgingDelay        *durationpb.Duration   `protobuf:"bytes,2,opt,name=hedging_delay,json=hedgingDelay,proto3"`
+	xxx_hidden_NonFatalStatusCodes []string               `protobuf:"bytes,3,rep,name=non_fatal_status_codes,json=nonFatalStatusCodes,proto3"`
+	unknownFields                  protoimpl.UnknownFields
+	sizeCache                      protoimpl.SizeCache
+}
+
+func (x *HedgingPolicy) Reset() {
+	*x = HedgingPolicy{}
+	mi := &file_buf_alpha_grpc_v1alpha1_service_config_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HedgingPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HedgingPolicy) ProtoMessage() {}
+
+func (x *HedgingPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_buf_alpha_grpc_v1alpha1_service_config_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *HedgingPolicy) GetMaxAttempts() uint32 {
+	if x != nil {
+		return x.xxx_hidden_MaxAttempts
+	}
+	return 0
+}
+
+func (x *HedgingPolicy) GetHedgingDelay() *durationpb.Duration {
+	if x != nil {
+		return x.xxx_hidden_HedgingDelay
+	}
+	return nil
+}
+
+func (x *HedgingPolicy) GetNonFatalStatusCodes() []string {
+	if x != nil {
+		return x.xxx_hidden_NonFatalStatusCodes
+	}
+	return nil
+}
+
+func (x *HedgingPolicy) SetMaxAttempts(v uint32) {
+	x.xxx_hidden_MaxAttempts = v
+}
+
+func (x *HedgingPolicy) SetHedgingDelay(v *durationpb.Duration) {
+	x.xxx_hidden_HedgingDelay = v
+}
+
+func (x *HedgingPolicy) SetNonFatalStatusCodes(v []string) {
+	x.xxx_hidden_NonFatalStatusCodes = v
+}
+
+func (x *HedgingPolicy) HasHedgingDelay() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_HedgingDelay != nil
+}
+
+func (x *HedgingPolicy) ClearHedgingDelay() {
+	x.xxx_hidden_HedgingDelay = nil
+}
+
+type HedgingPolicy_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// The maximum number of calls sent, including the original call.
+	//
+	// Must be greater than 1.
+	MaxAttempts uint32
+	// The delay between sending each hedged call.
+	HedgingDelay *durationpb.Duration
+	// The status codes that do not stop other hedged calls, such as "UNAVAILABLE".
+	NonFatalStatusCodes []string
+}
+
+func (b0 HedgingPolicy_builder) Build() *HedgingPolicy {
+	m0 := &HedgingPolicy{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_MaxAttempts = b.MaxAttempts
+	x.xxx_hidden_HedgingDelay = b.HedgingDelay
+	x.xxx_hidden_NonFatalStatusCodes = b.NonFatalStatusCodes
+	return m0
+}
+
+var file_buf_alpha_grpc_v1alpha1_service_config_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
+		ExtensionType: (*MethodConfig)(nil),
+		Field:         1170,
+		Name:          "buf.alpha.grpc.v1alpha1.service_method_config",
+		Tag:           "bytes,1170,opt,name=service_method_config",
+		Filename:      "buf/alpha/grpc/v1alpha1/service_config.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*MethodConfig)(nil),
+		Field:         1170,
+		Name:          "buf.alpha.grpc.v1alpha1.method_config",
+		Tag:           "bytes,1170,opt,name=method_config",
+		Filename:      "buf/alpha/grpc/v1alpha1/service_config.proto",
+	},
+}
+
+// Extension fields to descriptorpb.ServiceOptions.
+var (
+	// Configures the default method config for all methods of the service
+	// for protoc-gen-buf-grpc-service-config.
+	//
+	// optional buf.alpha.grpc.v1alpha1.MethodConfig service_method_config = 1170;
+	E_ServiceMethodConfig = &file_buf_alpha_grpc_v1alpha1_service_config_proto_extTypes[0]
+)
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// Configures the method config for the method for protoc-gen-buf-grpc-service-config.
+	//
+	// Fields that are set override the fields of the service_method_config of the service.
+	//
+	// optional buf.alpha.grpc.v1alpha1.MethodConfig method_config = 1170;
+	E_MethodConfig = &file_buf_alpha_grpc_v1alpha1_service_config_proto_extTypes[1]
+)
+
+var File_buf_alpha_grpc_v1alpha1_service_config_proto protoreflect.FileDescriptor
+
+var file_buf_alpha_grpc_v1alpha1_service_config_proto_rawDesc = string([]byte{
+	0x0a, 0x2c, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2f, 0x67, 0x72, 0x70, 0x63,
+	0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x17,
+	0x62, 0x75, 0x66, 0x2e, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe6, 0x03, 0x0a, 0x0c, 0x4d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x33, 0x0a, 0x07, 0x74, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12,
+	0x29, 0x0a, 0x0e, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f, 0x72, 0x65, 0x61, 0x64,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x0c, 0x77, 0x61, 0x69, 0x74, 0x46,
+	0x6f, 0x72, 0x52, 0x65, 0x61, 0x64, 0x79, 0x88, 0x01, 0x01, 0x12, 0x3e, 0x0a, 0x19, 0x6d, 0x61,
+	0x78, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52,
+	0x16, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x1a, 0x6d, 0x61,
+	0x78, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03,
+	0x52, 0x17, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x49, 0x0a, 0x0c,
+	0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x24, 0x2e, 0x62, 0x75, 0x66, 0x2e, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x52, 0x65, 0x74,
+	0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x48, 0x00, 0x52, 0x0b, 0x72, 0x65, 0x74, 0x72,
+	0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x4f, 0x0a, 0x0e, 0x68, 0x65, 0x64, 0x67, 0x69,
+	0x6e, 0x67, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x26, 0x2e, 0x62, 0x75, 0x66, 0x2e, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x48, 0x65, 0x64, 0x67, 0x69, 0x6e,
+	0x67, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x48, 0x00, 0x52, 0x0d, 0x68, 0x65, 0x64, 0x67, 0x69,
+	0x6e, 0x67, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x42, 0x08, 0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f,
+	0x72, 0x65, 0x61, 0x64, 0x79, 0x42, 0x1c, 0x0a, 0x1a, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x42, 0x1d, 0x0a, 0x1b, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x22, 0x95, 0x02, 0x0a, 0x0b, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x41, 0x74, 0x74,
+	0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x42, 0x0a, 0x0f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c,
+	0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x69, 0x6e, 0x69, 0x74, 0x69,
+	0x61, 0x6c, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x12, 0x3a, 0x0a, 0x0b, 0x6d, 0x61, 0x78,
+	0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x42, 0x61,
+	0x63, 0x6b, 0x6f, 0x66, 0x66, 0x12, 0x2d, 0x0a, 0x12, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66,
+	0x5f, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x11, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70,
+	0x6c, 0x69, 0x65, 0x72, 0x12, 0x34, 0x0a, 0x16, 0x72, 0x65, 0x74, 0x72, 0x79, 0x61, 0x62, 0x6c,
+	0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x14, 0x72, 0x65, 0x74, 0x72, 0x79, 0x61, 0x62, 0x6c, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x22, 0xa7, 0x01, 0x0a, 0x0d, 0x48,
+	0x65, 0x64, 0x67, 0x69, 0x6e, 0x67, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x21, 0x0a, 0x0c,
+	0x6d, 0x61, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12,
+	0x3e, 0x0a, 0x0d, 0x68, 0x65, 0x64, 0x67, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x0c, 0x68, 0x65, 0x64, 0x67, 0x69, 0x6e, 0x67, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x12,
+	0x33, 0x0a, 0x16, 0x6e, 0x6f, 0x6e, 0x5f, 0x66, 0x61, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x13, 0x6e, 0x6f, 0x6e, 0x46, 0x61, 0x74, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43,
+	0x6f, 0x64, 0x65, 0x73, 0x3a, 0x7b, 0x0a, 0x15, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f,
+	0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1f, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x92,
+	0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x62, 0x75, 0x66, 0x2e, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
+	0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x13, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x3a, 0x6b, 0x0a, 0x0d, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x5f, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x92, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x62, 0x75, 0x66, 0x2e,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x52, 0x0c, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x42, 0x83,
+	0x02, 0x0a, 0x1b, 0x63, 0x6f, 0x6d, 0x2e, 0x62, 0x75, 0x66, 0x2e, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x42, 0x12,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x50, 0x01, 0x5a, 0x51, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x62, 0x75, 0x66, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2f, 0x62, 0x75, 0x66, 0x2f, 0x70, 0x72,
+	0x69, 0x76, 0x61, 0x74, 0x65, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
+	0x67, 0x6f, 0x2f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2f, 0x67, 0x72, 0x70,
+	0x63, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x3b, 0x67, 0x72, 0x70, 0x63, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xa2, 0x02, 0x03, 0x42, 0x41, 0x47, 0xaa, 0x02, 0x17,
+	0x42, 0x75, 0x66, 0x2e, 0x41, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x56,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xca, 0x02, 0x17, 0x42, 0x75, 0x66, 0x5c, 0x41, 0x6c,
+	0x70, 0x68, 0x61, 0x5c, 0x47, 0x72, 0x70, 0x63, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0xe2, 0x02, 0x23, 0x42, 0x75, 0x66, 0x5c, 0x41, 0x6c, 0x70, 0x68, 0x61, 0x5c, 0x47, 0x72,
+	0x70, 0x63, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x1a, 0x42, 0x75, 0x66, 0x3a, 0x3a, 0x41,
+	0x6c, 0x70, 0x68, 0x61, 0x3a, 0x3a, 0x47, 0x72, 0x70, 0x63, 0x3a, 0x3a, 0x56, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var file_buf_alpha_grpc_v1alpha1_service_config_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_buf_alpha_grpc_v1alpha1_service_config_proto_goTypes = []any{
+	(*MethodConfig)(nil),                // 0: buf.alpha.grpc.v1alpha1.MethodConfig
+	(*RetryPolicy)(nil),                 // 1: buf.alpha.grpc.v1alpha1.RetryPolicy
+	(*HedgingPolicy)(nil),               // 2: buf.alpha.grpc.v1alpha1.HedgingPolicy
+	(*durationpb.Duration)(nil),         // 3: google.protobuf.Duration
+	(*descriptorpb.ServiceOptions)(nil), // 4: google.protobuf.ServiceOptions
+	(*descriptorpb.MethodOptions)(nil),  // 5: google.protobuf.MethodOptions
+}
+var file_buf_alpha_grpc_v1alpha1_service_config_proto_depIdxs = []int32{
+	3,  // 0: buf.alpha.grpc.v1alpha1.MethodConfig.timeout:type_name -> google.protobuf.Duration
+	1,  // 1: buf.alpha.grpc.v1alpha1.MethodConfig.retry_policy:type_name -> buf.alpha.grpc.v1alpha1.RetryPolicy
+	2,  // 2: buf.alpha.grpc.v1alpha1.MethodConfig.hedging_policy:type_name -> buf.alpha.grpc.v1alpha1.HedgingPolicy
+	3,  // 3: buf.alpha.grpc.v1alpha1.RetryPolicy.initial_backoff:type_name -> google.protobuf.Duration
+	3,  // 4: buf.alpha.grpc.v1alpha1.RetryPolicy.max_backoff:type_name -> google.protobuf.Duration
+	3,  // 5: buf.alpha.grpc.v1alpha1.HedgingPolicy.hedging_delay:type_name -> google.protobuf.Duration
+	4,  // 6: buf.alpha.grpc.v1alpha1.service_method_config:extendee -> google.protobuf.ServiceOptions
+	5,  // 7: buf.alpha.grpc.v1alpha1.method_config:extendee -> google.protobuf.MethodOptions
+	0,  // 8: buf.alpha.grpc.v1alpha1.service_method_config:type_name -> buf.alpha.grpc.v1alpha1.MethodConfig
+	0,  // 9: buf.alpha.grpc.v1alpha1.method_config:type_name -> buf.alpha.grpc.v1alpha1.MethodConfig
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	8,  // [8:10] is the sub-list for extension type_name
+	6,  // [6:8] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_buf_alpha_grpc_v1alpha1_service_config_proto_init() }
+func file_buf_alpha_grpc_v1alpha1_service_config_proto_init() {
+	if File_buf_alpha_grpc_v1alpha1_service_config_proto != nil {
+		return
+	}
+	file_buf_alpha_grpc_v1alpha1_service_config_proto_msgTypes[0].OneofWrappers = []any{
+		(*methodConfig_RetryPolicy)(nil),
+		(*methodConfig_HedgingPolicy)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_buf_alpha_grpc_v1alpha1_service_config_proto_rawDesc), len(file_buf_alpha_grpc_v1alpha1_service_config_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 2,
+			NumServices:   0,
+		},
+		GoTypes:           file_buf_alpha_grpc_v1alpha1_service_config_proto_goTypes,
+		DependencyIndexes: file_buf_alpha_grpc_v1alpha1_service_config_proto_depIdxs,
+		MessageInfos:      file_buf_alpha_grpc_v1alpha1_service_config_proto_msgTypes,
+		ExtensionInfos:    file_buf_alpha_grpc_v1alpha1_service_config_proto_extTypes,
+	}.Build()
+	File_buf_alpha_grpc_v1alpha1_service_config_proto = out.File
+	file_buf_alpha_grpc_v1alpha1_service_config_proto_goTypes = nil
+	file_buf_alpha_grpc_v1alpha1_service_config_proto_depIdxs = nil
+}
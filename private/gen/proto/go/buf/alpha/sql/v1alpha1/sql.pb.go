@@ -0,0 +1,332 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.4
+// 	protoc        (unknown)
+// source: buf/alpha/sql/v1alpha1/sql.proto
+
+package sqlv1alpha1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TableOptions configures the table produced for a message.
+type TableOptions struct {
+	state                 protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Name       string                 `protobuf:"bytes,1,opt,name=name,proto3"`
+	xxx_hidden_PrimaryKey []string               `protobuf:"bytes,2,rep,name=primary_key,json=primaryKey,proto3"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *TableOptions) Reset() {
+	*x = TableOptions{}
+	mi := &file_buf_alpha_sql_v1alpha1_sql_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TableOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableOptions) ProtoMessage() {}
+
+func (x *TableOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_buf_alpha_sql_v1alpha1_sql_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *TableOptions) GetName() string {
+	if x != nil {
+		return x.xxx_hidden_Name
+	}
+	return ""
+}
+
+func (x *TableOptions) GetPrimaryKey() []string {
+	if x != nil {
+		return x.xxx_hidden_PrimaryKey
+	}
+	return nil
+}
+
+func (x *TableOptions) SetName(v string) {
+	x.xxx_hidden_Name = v
+}
+
+func (x *TableOptions) SetPrimaryKey(v []string) {
+	x.xxx_hidden_PrimaryKey = v
+}
+
+type TableOptions_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// The name of the table.
+	//
+	// If not set, the snake_case name of the message is used.
+	Name string
+	// The names of the columns that make up the primary key, in order.
+	PrimaryKey []string
+}
+
+func (b0 TableOptions_builder) Build() *TableOptions {
+	m0 := &TableOptions{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Name = b.Name
+	x.xxx_hidden_PrimaryKey = b.PrimaryKey
+	return m0
+}
+
+// ColumnOptions configures the column produced for a field.
+type ColumnOptions struct {
+	state             protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Name   string                 `protobuf:"bytes,1,opt,name=name,proto3"`
+	xxx_hidden_Ignore bool                   `protobuf:"varint,2,opt,name=ignore,proto3"`
+	xxx_hidden_Type   string                 `protobuf:"bytes,3,opt,name=type,proto3"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ColumnOptions) Reset() {
+	*x = ColumnOptions{}
+	mi := &file_buf_alpha_sql_v1alpha1_sql_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColumnOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColumnOptions) ProtoMessage() {}
+
+func (x *ColumnOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_buf_alpha_sql_v1alpha1_sql_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ColumnOptions) GetName() string {
+	if x != nil {
+		return x.xxx_hidden_Name
+	}
+	return ""
+}
+
+func (x *ColumnOptions) GetIgnore() bool {
+	if x != nil {
+		return x.xxx_hidden_Ignore
+	}
+	return false
+}
+
+func (x *ColumnOptions) GetType() string {
+	if x != nil {
+		return x.xxx_hidden_Type
+	}
+	return ""
+}
+
+func (x *ColumnOptions) SetName(v string) {
+	x.xxx_hidden_Name = v
+}
+
+func (x *ColumnOptions) SetIgnore(v bool) {
+	x.xxx_hidden_Ignore = v
+}
+
+func (x *ColumnOptions) SetType(v string) {
+	x.xxx_hidden_Type = v
+}
+
+type ColumnOptions_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// The name of the column.
+	//
+	// If not set, the name of the field is used.
+	Name string
+	// Do not produce a column for the field.
+	Ignore bool
+	// The SQL type of the column, overriding the type derived from the field.
+	Type string
+}
+
+func (b0 ColumnOptions_builder) Build() *ColumnOptions {
+	m0 := &ColumnOptions{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Name = b.Name
+	x.xxx_hidden_Ignore = b.Ignore
+	x.xxx_hidden_Type = b.Type
+	return m0
+}
+
+var file_buf_alpha_sql_v1alpha1_sql_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*TableOptions)(nil),
+		Field:         1170,
+		Name:          "buf.alpha.sql.v1alpha1.table",
+		Tag:           "bytes,1170,opt,name=table",
+		Filename:      "buf/alpha/sql/v1alpha1/sql.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*ColumnOptions)(nil),
+		Field:         1170,
+		Name:          "buf.alpha.sql.v1alpha1.column",
+		Tag:           "bytes,1170,opt,name=column",
+		Filename:      "buf/alpha/sql/v1alpha1/sql.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MessageOptions.
+var (
+	// Marks the message as a SQL table for protoc-gen-buf-sql.
+	//
+	// Messages without this option do not produce any DDL.
+	//
+	// optional buf.alpha.sql.v1alpha1.TableOptions table = 1170;
+	E_Table = &file_buf_alpha_sql_v1alpha1_sql_proto_extTypes[0]
+)
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// Configures the column produced for the field by protoc-gen-buf-sql.
+	//
+	// optional buf.alpha.sql.v1alpha1.ColumnOptions column = 1170;
+	E_Column = &file_buf_alpha_sql_v1alpha1_sql_proto_extTypes[1]
+)
+
+var File_buf_alpha_sql_v1alpha1_sql_proto protoreflect.FileDescriptor
+
+var file_buf_alpha_sql_v1alpha1_sql_proto_rawDesc = string([]byte{
+	0x0a, 0x20, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2f, 0x73, 0x71, 0x6c, 0x2f,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2f, 0x73, 0x71, 0x6c, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x16, 0x62, 0x75, 0x66, 0x2e, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x73, 0x71,
+	0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x43, 0x0a, 0x0c,
+	0x54, 0x61, 0x62, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x4b, 0x65,
+	0x79, 0x22, 0x4f, 0x0a, 0x0d, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x3a, 0x5c, 0x0a, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x92, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x62, 0x75, 0x66, 0x2e, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e,
+	0x73, 0x71, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x54, 0x61, 0x62,
+	0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x3a, 0x5d, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x92, 0x09, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x25, 0x2e, 0x62, 0x75, 0x66, 0x2e, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x73, 0x71, 0x6c,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x42,
+	0xf2, 0x01, 0x0a, 0x1a, 0x63, 0x6f, 0x6d, 0x2e, 0x62, 0x75, 0x66, 0x2e, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x2e, 0x73, 0x71, 0x6c, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x42, 0x08,
+	0x53, 0x71, 0x6c, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x4f, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x66, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2f,
+	0x62, 0x75, 0x66, 0x2f, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x2f, 0x67, 0x65, 0x6e, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x2f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x2f, 0x73, 0x71, 0x6c, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x3b,
+	0x73, 0x71, 0x6c, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xa2, 0x02, 0x03, 0x42, 0x41,
+	0x53, 0xaa, 0x02, 0x16, 0x42, 0x75, 0x66, 0x2e, 0x41, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x53, 0x71,
+	0x6c, 0x2e, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0xca, 0x02, 0x16, 0x42, 0x75, 0x66,
+	0x5c, 0x41, 0x6c, 0x70, 0x68, 0x61, 0x5c, 0x53, 0x71, 0x6c, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0xe2, 0x02, 0x22, 0x42, 0x75, 0x66, 0x5c, 0x41, 0x6c, 0x70, 0x68, 0x61, 0x5c,
+	0x53, 0x71, 0x6c, 0x5c, 0x56, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x5c, 0x47, 0x50, 0x42,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x19, 0x42, 0x75, 0x66, 0x3a, 0x3a,
+	0x41, 0x6c, 0x70, 0x68, 0x61, 0x3a, 0x3a, 0x53, 0x71, 0x6c, 0x3a, 0x3a, 0x56, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var file_buf_alpha_sql_v1alpha1_sql_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_buf_alpha_sql_v1alpha1_sql_proto_goTypes = []any{
+	(*TableOptions)(nil),                // 0: buf.alpha.sql.v1alpha1.TableOptions
+	(*ColumnOptions)(nil),               // 1: buf.alpha.sql.v1alpha1.ColumnOptions
+	(*descriptorpb.MessageOptions)(nil), // 2: google.protobuf.MessageOptions
+	(*descriptorpb.FieldOptions)(nil),   // 3: google.protobuf.FieldOptions
+}
+var file_buf_alpha_sql_v1alpha1_sql_proto_depIdxs = []int32{
+	2, // 0: buf.alpha.sql.v1alpha1.table:extendee -> google.protobuf.MessageOptions
+	3, // 1: buf.alpha.sql.v1alpha1.column:extendee -> google.protobuf.FieldOptions
+	0, // 2: buf.alpha.sql.v1alpha1.table:type_name -> buf.alpha.sql.v1alpha1.TableOptions
+	1, // 3: buf.alpha.sql.v1alpha1.column:type_name -> buf.alpha.sql.v1alpha1.ColumnOptions
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	2, // [2:4] is the sub-list for extension type_name
+	0, // [0:2] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_buf_alpha_sql_v1alpha1_sql_proto_init() }
+func file_buf_alpha_sql_v1alpha1_sql_proto_init() {
+	if File_buf_alpha_sql_v1alpha1_sql_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_buf_alpha_sql_v1alpha1_sql_proto_rawDesc), len(file_buf_alpha_sql_v1alpha1_sql_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 2,
+			NumServices:   0,
+		},
+		GoTypes:           file_buf_alpha_sql_v1alpha1_sql_proto_goTypes,
+		DependencyIndexes: file_buf_alpha_sql_v1alpha1_sql_proto_depIdxs,
+		MessageInfos:      file_buf_alpha_sql_v1alpha1_sql_proto_msgTypes,
+		ExtensionInfos:    file_buf_alpha_sql_v1alpha1_sql_proto_extTypes,
+	}.Build()
+	File_buf_alpha_sql_v1alpha1_sql_proto = out.File
+	file_buf_alpha_sql_v1alpha1_sql_proto_goTypes = nil
+	file_buf_alpha_sql_v1alpha1_sql_proto_depIdxs = nil
+}
@@ -110,6 +110,14 @@ func Run(ctx context.Context, container app.Container, command *Command) error {
 	return app.Run(ctx, container, newRunFunc(command))
 }
 
+// CommandPath returns the path of the running Command, for example "buf mod prune".
+//
+// Returns empty if the context was not passed to the Run function of a Command.
+func CommandPath(ctx context.Context) string {
+	commandPath, _ := ctx.Value(commandPathContextKey{}).(string)
+	return commandPath
+}
+
 // BindMultiple is a convenience function for binding multiple flag functions.
 func BindMultiple(bindFuncs ...func(*pflag.FlagSet)) func(*pflag.FlagSet) {
 	return func(flagSet *pflag.FlagSet) {
@@ -127,6 +135,8 @@ func MarkFlagRequired(flagSet *pflag.FlagSet, flagName string) error {
 
 // *** PRIVATE ***
 
+type commandPathContextKey struct{}
+
 func newRunFunc(command *Command) func(context.Context, app.Container) error {
 	return func(ctx context.Context, container app.Container) error {
 		return run(ctx, container, command)
@@ -323,7 +333,10 @@ func commandToCobra(
 	}
 	if command.Run != nil {
 		cobraCommand.Run = func(_ *cobra.Command, args []string) {
-			runErr := command.Run(ctx, app.NewContainerForArgs(container, args...))
+			runErr := command.Run(
+				context.WithValue(ctx, commandPathContextKey{}, cobraCommand.CommandPath()),
+				app.NewContainerForArgs(container, args...),
+			)
 			if asErr := (&invalidArgumentError{}); errors.As(runErr, &asErr) {
 				// Print usage for failing command if an args error is returned.
 				// This has to be done at this level since the usage must relate
@@ -37,6 +37,7 @@ func TestBasic(t *testing.T) {
 	var actualBar int
 	var actualStdin string
 	var actualEnvValue string
+	var actualCommandPath string
 
 	rootCommand := &Command{
 		Use: "test",
@@ -59,6 +60,7 @@ func TestBasic(t *testing.T) {
 					}
 					actualStdin = string(data)
 					actualEnvValue = container.Env("KEY")
+					actualCommandPath = CommandPath(ctx)
 					return nil
 				},
 			},
@@ -84,6 +86,7 @@ func TestBasic(t *testing.T) {
 	assert.Equal(t, 1, actualBar)
 	assert.Equal(t, "world", actualStdin)
 	assert.Equal(t, "VALUE", actualEnvValue)
+	assert.Equal(t, "test sub", actualCommandPath)
 }
 
 func TestError(t *testing.T) {
@@ -155,6 +155,16 @@ func BuilderWithLoggerProvider(loggerProvider LoggerProvider) BuilderOption {
 	}
 }
 
+// BuilderWithLogComponents sets the components that can be set with the log-filter flag.
+//
+// Loggers are scoped to components with slogext.WithComponent. The default is to
+// allow any component.
+func BuilderWithLogComponents(logComponents ...string) BuilderOption {
+	return func(builder *builder) {
+		builder.logComponents = append(builder.logComponents, logComponents...)
+	}
+}
+
 // ReadConfig reads the configuration from the YAML configuration file config.yaml
 // in the configuration directory.
 //
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/bufbuild/buf/private/pkg/app"
@@ -33,6 +34,7 @@ type builder struct {
 	debug     bool
 	noWarn    bool
 	logFormat string
+	logFilter string
 
 	profile           bool
 	profilePath       string
@@ -48,6 +50,7 @@ type builder struct {
 
 	interceptors   []Interceptor
 	loggerProvider LoggerProvider
+	logComponents  []string
 }
 
 func newBuilder(appName string, options ...BuilderOption) *builder {
@@ -62,8 +65,13 @@ func newBuilder(appName string, options ...BuilderOption) *builder {
 }
 
 func (b *builder) BindRoot(flagSet *pflag.FlagSet) {
-	flagSet.BoolVar(&b.debug, "debug", false, "Turn on debug logging")
+	flagSet.BoolVar(&b.debug, "debug", false, "Turn on debug logging. Equivalent to --log-filter=debug")
 	flagSet.StringVar(&b.logFormat, "log-format", "color", "The log format [text,color,json]")
+	logFilterUsage := `The log levels of components, such as "fetch=debug,plugin=info". A level without a component sets the level of all other logs`
+	if len(b.logComponents) > 0 {
+		logFilterUsage = fmt.Sprintf("%s. Components are [%s]", logFilterUsage, strings.Join(b.logComponents, ","))
+	}
+	flagSet.StringVar(&b.logFilter, "log-filter", "", logFilterUsage)
 	if b.defaultTimeout > 0 {
 		flagSet.DurationVar(&b.timeout, "timeout", b.defaultTimeout, `The duration until timing out, setting it to zero means no timeout`)
 	}
@@ -116,14 +124,21 @@ func (b *builder) run(
 	if err != nil {
 		return err
 	}
+	logFilter, err := parseLogFilter(b.logFilter, logLevel, b.logComponents)
+	if err != nil {
+		return err
+	}
 	nameContainer, err := newNameContainer(appContainer, b.appName)
 	if err != nil {
 		return err
 	}
-	logger, err := b.loggerProvider(nameContainer, logLevel, logFormat)
+	logger, err := b.loggerProvider(nameContainer, logFilter.minLogLevel(), logFormat)
 	if err != nil {
 		return err
 	}
+	if b.logFilter != "" {
+		logger = slog.New(newLogFilterHandler(logger.Handler(), logFilter))
+	}
 	container := newContainer(nameContainer, logger)
 
 	if b.parallelism > 0 {
@@ -0,0 +1,144 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appext
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/slogext"
+)
+
+// logFilter is a parsed --log-filter flag value.
+type logFilter struct {
+	// defaultLogLevel is the level of logs that are not scoped to a component
+	// in componentToLogLevel.
+	defaultLogLevel     LogLevel
+	componentToLogLevel map[string]LogLevel
+}
+
+// parseLogFilter parses a comma-separated list of component=level pairs.
+//
+// A level without a component overrides defaultLogLevel. If components is not
+// empty, only the given components are allowed.
+func parseLogFilter(
+	logFilterString string,
+	defaultLogLevel LogLevel,
+	components []string,
+) (*logFilter, error) {
+	logFilter := &logFilter{
+		defaultLogLevel:     defaultLogLevel,
+		componentToLogLevel: make(map[string]LogLevel),
+	}
+	for _, element := range strings.Split(logFilterString, ",") {
+		element = strings.TrimSpace(element)
+		if element == "" {
+			continue
+		}
+		component, logLevelString, ok := strings.Cut(element, "=")
+		if !ok {
+			logLevel, err := ParseLogLevel(element)
+			if err != nil {
+				return nil, err
+			}
+			logFilter.defaultLogLevel = logLevel
+			continue
+		}
+		component = strings.TrimSpace(component)
+		if component == "" {
+			return nil, fmt.Errorf("no component for log filter %q", element)
+		}
+		if len(components) > 0 && !slices.Contains(components, component) {
+			return nil, fmt.Errorf("unknown log component [%s]: %q", strings.Join(components, ","), component)
+		}
+		if _, ok := logFilter.componentToLogLevel[component]; ok {
+			return nil, fmt.Errorf("duplicate log component: %q", component)
+		}
+		logLevel, err := ParseLogLevel(logLevelString)
+		if err != nil {
+			return nil, err
+		}
+		logFilter.componentToLogLevel[component] = logLevel
+	}
+	return logFilter, nil
+}
+
+// minLogLevel returns the most verbose level of the filter.
+//
+// This is the level that the Logger must be created with so that the filter
+// can turn on logs for specific components.
+func (l *logFilter) minLogLevel() LogLevel {
+	minLogLevel := l.defaultLogLevel
+	for _, logLevel := range l.componentToLogLevel {
+		minLogLevel = min(minLogLevel, logLevel)
+	}
+	return minLogLevel
+}
+
+func (l *logFilter) logLevelForComponent(component string) LogLevel {
+	if logLevel, ok := l.componentToLogLevel[component]; ok {
+		return logLevel
+	}
+	return l.defaultLogLevel
+}
+
+// logFilterHandler is a slog.Handler that filters logs by the levels of the
+// components of the Loggers, as set with slogext.WithComponent.
+type logFilterHandler struct {
+	delegate  slog.Handler
+	logFilter *logFilter
+	level     slog.Level
+}
+
+func newLogFilterHandler(delegate slog.Handler, logFilter *logFilter) *logFilterHandler {
+	return &logFilterHandler{
+		delegate:  delegate,
+		logFilter: logFilter,
+		level:     logFilter.defaultLogLevel.SlogLevel(),
+	}
+}
+
+func (h *logFilterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level && h.delegate.Enabled(ctx, level)
+}
+
+func (h *logFilterHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.delegate.Handle(ctx, record)
+}
+
+func (h *logFilterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	level := h.level
+	for _, attr := range attrs {
+		if attr.Key == slogext.ComponentKey {
+			level = h.logFilter.logLevelForComponent(attr.Value.String()).SlogLevel()
+		}
+	}
+	return &logFilterHandler{
+		delegate:  h.delegate.WithAttrs(attrs),
+		logFilter: h.logFilter,
+		level:     level,
+	}
+}
+
+func (h *logFilterHandler) WithGroup(name string) slog.Handler {
+	return &logFilterHandler{
+		delegate:  h.delegate.WithGroup(name),
+		logFilter: h.logFilter,
+		level:     h.level,
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appext
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/private/pkg/slogext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogFilter(t *testing.T) {
+	t.Parallel()
+	logFilter, err := parseLogFilter("", LogLevelInfo, nil)
+	require.NoError(t, err)
+	assert.Equal(t, LogLevelInfo, logFilter.minLogLevel())
+	logFilter, err = parseLogFilter("fetch=debug, plugin=warn", LogLevelInfo, []string{"fetch", "plugin"})
+	require.NoError(t, err)
+	assert.Equal(t, LogLevelDebug, logFilter.minLogLevel())
+	assert.Equal(t, LogLevelDebug, logFilter.logLevelForComponent("fetch"))
+	assert.Equal(t, LogLevelWarn, logFilter.logLevelForComponent("plugin"))
+	assert.Equal(t, LogLevelInfo, logFilter.logLevelForComponent("cache"))
+	logFilter, err = parseLogFilter("error,fetch=warn", LogLevelDebug, nil)
+	require.NoError(t, err)
+	assert.Equal(t, LogLevelWarn, logFilter.minLogLevel())
+	assert.Equal(t, LogLevelError, logFilter.logLevelForComponent("cache"))
+	_, err = parseLogFilter("fetch=debug", LogLevelInfo, []string{"plugin"})
+	require.Error(t, err)
+	_, err = parseLogFilter("fetch=debug,fetch=info", LogLevelInfo, nil)
+	require.Error(t, err)
+	_, err = parseLogFilter("fetch=verbose", LogLevelInfo, nil)
+	require.Error(t, err)
+	_, err = parseLogFilter("=debug", LogLevelInfo, nil)
+	require.Error(t, err)
+}
+
+func TestLogFilterHandler(t *testing.T) {
+	t.Parallel()
+	logFilter, err := parseLogFilter("fetch=debug,plugin=error", LogLevelInfo, nil)
+	require.NoError(t, err)
+	var sb strings.Builder
+	logger := slog.New(
+		newLogFilterHandler(
+			slog.NewTextHandler(
+				&sb,
+				&slog.HandlerOptions{
+					Level: logFilter.minLogLevel().SlogLevel(),
+					ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+						if attr.Key == slog.TimeKey && len(groups) == 0 {
+							return slog.Attr{}
+						}
+						return attr
+					},
+				},
+			),
+			logFilter,
+		),
+	)
+	logger.Debug("one")
+	logger.Info("two")
+	fetchLogger := slogext.WithComponent(logger, "fetch")
+	fetchLogger.Debug("three")
+	fetchLogger.WithGroup("group").Debug("four")
+	pluginLogger := slogext.WithComponent(logger, "plugin")
+	pluginLogger.Warn("five")
+	pluginLogger.Error("six")
+	assert.Equal(
+		t,
+		`level=INFO msg=two
+level=DEBUG msg=three component=fetch
+level=DEBUG msg=four component=fetch
+level=ERROR msg=six component=plugin
+`,
+		sb.String(),
+	)
+}
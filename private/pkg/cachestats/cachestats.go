@@ -0,0 +1,107 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cachestats records the lookups and hits of caches.
+//
+// Caches record their lookups with Record, which is a no-op unless a Recorder was
+// attached to the context with WithRecorder. This allows the statistics of all caches
+// used by an invocation to be gathered without threading a Recorder through every cache.
+package cachestats
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// CacheStats are the statistics of a cache.
+type CacheStats struct {
+	// Name is the name of the cache.
+	Name string
+	// Lookups is the number of keys that were looked up in the cache.
+	Lookups int
+	// Hits is the number of keys that were found in the cache.
+	Hits int
+}
+
+// Recorder records the lookups and hits of caches.
+//
+// Recorders are safe for concurrent use.
+type Recorder interface {
+	// CacheStats returns the statistics of the caches that had lookups recorded.
+	//
+	// Sorted by name.
+	CacheStats() []CacheStats
+
+	record(name string, lookups int, hits int)
+}
+
+// NewRecorder returns a new Recorder.
+func NewRecorder() Recorder {
+	return newRecorder()
+}
+
+// WithRecorder returns a new context that records the lookups and hits of caches to
+// the Recorder.
+func WithRecorder(ctx context.Context, recorder Recorder) context.Context {
+	return context.WithValue(ctx, recorderContextKey{}, recorder)
+}
+
+// Record records the lookups and hits for the cache with the given name to the Recorder
+// attached to the context.
+//
+// This is a no-op if no Recorder is attached to the context.
+func Record(ctx context.Context, name string, lookups int, hits int) {
+	if recorder, ok := ctx.Value(recorderContextKey{}).(Recorder); ok {
+		recorder.record(name, lookups, hits)
+	}
+}
+
+// *** PRIVATE ***
+
+type recorderContextKey struct{}
+
+type recorder struct {
+	nameToCacheStats map[string]*CacheStats
+	lock             sync.Mutex
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		nameToCacheStats: make(map[string]*CacheStats),
+	}
+}
+
+func (r *recorder) CacheStats() []CacheStats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	cacheStats := make([]CacheStats, 0, len(r.nameToCacheStats))
+	for _, nameCacheStats := range r.nameToCacheStats {
+		cacheStats = append(cacheStats, *nameCacheStats)
+	}
+	sort.Slice(cacheStats, func(i int, j int) bool { return cacheStats[i].Name < cacheStats[j].Name })
+	return cacheStats
+}
+
+func (r *recorder) record(name string, lookups int, hits int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	cacheStats, ok := r.nameToCacheStats[name]
+	if !ok {
+		cacheStats = &CacheStats{Name: name}
+		r.nameToCacheStats[name] = cacheStats
+	}
+	cacheStats.Lookups += lookups
+	cacheStats.Hits += hits
+}
@@ -0,0 +1,42 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cachestats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	// No Recorder is attached, this is a no-op.
+	Record(ctx, "foo", 1, 1)
+	recorder := NewRecorder()
+	ctx = WithRecorder(ctx, recorder)
+	Record(ctx, "foo", 3, 1)
+	Record(ctx, "bar", 1, 0)
+	Record(ctx, "foo", 2, 2)
+	require.Equal(
+		t,
+		[]CacheStats{
+			{Name: "bar", Lookups: 1, Hits: 0},
+			{Name: "foo", Lookups: 5, Hits: 3},
+		},
+		recorder.CacheStats(),
+	)
+}
@@ -0,0 +1,368 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storageutil"
+)
+
+const (
+	attributesFileName = ".gitattributes"
+
+	exportIgnoreAttributeName = "export-ignore"
+	textAttributeName         = "text"
+	eolAttributeName          = "eol"
+
+	// binaryDetectionLength is the length of the prefix of a file that is checked for
+	// NUL bytes to detect binary files, which matches git.
+	binaryDetectionLength = 8000
+)
+
+const (
+	attributeStateUnspecified attributeState = iota
+	attributeStateSet
+	attributeStateUnset
+	attributeStateValue
+)
+
+// builtinAttributeMacros are the macros that are defined by git itself. Macros
+// defined in .gitattributes files with [attr] are not supported.
+var builtinAttributeMacros = map[string][]attribute{
+	"binary": {
+		{name: "diff", state: attributeStateUnset},
+		{name: "merge", state: attributeStateUnset},
+		{name: textAttributeName, state: attributeStateUnset},
+	},
+}
+
+type exportReadBucket struct {
+	delegate storage.ReadBucket
+	// rules are the rules of each .gitattributes file, by the directory of the file.
+	rules map[string][]*attributeRule
+}
+
+type attributeRule struct {
+	// pattern is relative to the directory of the .gitattributes file.
+	pattern string
+	// anchored is true if the pattern contains a slash, in which case it matches
+	// paths relative to the directory of the .gitattributes file. Otherwise, the
+	// pattern matches the base names of paths at any depth.
+	anchored bool
+	// dirOnly is true if the pattern had a trailing slash.
+	dirOnly    bool
+	attributes []attribute
+}
+
+type attribute struct {
+	name  string
+	state attributeState
+	value string
+}
+
+// attributeState is the state of an attribute.
+type attributeState int
+
+func newExportReadBucket(ctx context.Context, delegate storage.ReadBucket) (*exportReadBucket, error) {
+	rules := make(map[string][]*attributeRule)
+	if err := delegate.Walk(
+		ctx,
+		"",
+		func(objectInfo storage.ObjectInfo) error {
+			if normalpath.Base(objectInfo.Path()) != attributesFileName {
+				return nil
+			}
+			data, err := storage.ReadPath(ctx, delegate, objectInfo.Path())
+			if err != nil {
+				return err
+			}
+			rules[normalpath.Dir(objectInfo.Path())] = parseAttributeRules(data)
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+	return &exportReadBucket{
+		delegate: delegate,
+		rules:    rules,
+	}, nil
+}
+
+func (b *exportReadBucket) Get(ctx context.Context, path string) (storage.ReadObjectCloser, error) {
+	if b.isExportIgnored(path) {
+		return nil, &fs.PathError{Op: "read", Path: path, Err: fs.ErrNotExist}
+	}
+	readObjectCloser, err := b.delegate.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	attributes := b.getAttributes(path, false)
+	eol := attributes[eolAttributeName]
+	text := attributes[textAttributeName]
+	if eol.state != attributeStateValue || eol.value != "crlf" || text.state == attributeStateUnset {
+		return readObjectCloser, nil
+	}
+	data, err := io.ReadAll(readObjectCloser)
+	if err := errors.Join(err, readObjectCloser.Close()); err != nil {
+		return nil, err
+	}
+	if text.state == attributeStateValue && text.value == "auto" && isBinary(data) {
+		return newBytesReadObjectCloser(readObjectCloser, data), nil
+	}
+	// The converted contents are not the contents of the file on disk, if any.
+	return newBytesReadObjectCloser(
+		storageutil.NewObjectInfo(readObjectCloser.Path(), readObjectCloser.ExternalPath(), ""),
+		convertLFToCRLF(data),
+	), nil
+}
+
+func (b *exportReadBucket) Stat(ctx context.Context, path string) (storage.ObjectInfo, error) {
+	if b.isExportIgnored(path) {
+		return nil, &fs.PathError{Op: "stat", Path: path, Err: fs.ErrNotExist}
+	}
+	return b.delegate.Stat(ctx, path)
+}
+
+func (b *exportReadBucket) Walk(ctx context.Context, prefix string, f func(storage.ObjectInfo) error) error {
+	return b.delegate.Walk(
+		ctx,
+		prefix,
+		func(objectInfo storage.ObjectInfo) error {
+			if b.isExportIgnored(objectInfo.Path()) {
+				return nil
+			}
+			return f(objectInfo)
+		},
+	)
+}
+
+// isExportIgnored returns true if the file or any of the directories that contain it
+// have the export-ignore attribute.
+func (b *exportReadBucket) isExportIgnored(filePath string) bool {
+	if len(b.rules) == 0 {
+		return false
+	}
+	components := normalpath.Components(filePath)
+	for i := range components {
+		isDir := i < len(components)-1
+		attributes := b.getAttributes(normalpath.Join(components[:i+1]...), isDir)
+		if attributes[exportIgnoreAttributeName].state == attributeStateSet {
+			return true
+		}
+	}
+	return false
+}
+
+// getAttributes returns the attributes of the path.
+//
+// The .gitattributes files of the directories that contain the path are applied from
+// the root of the tree down, and the rules within a file are applied in order, so that
+// later and deeper rules take precedence.
+func (b *exportReadBucket) getAttributes(filePath string, isDir bool) map[string]attribute {
+	attributes := make(map[string]attribute)
+	dirPaths := []string{"."}
+	if parentDirPath := normalpath.Dir(filePath); parentDirPath != "." {
+		components := normalpath.Components(parentDirPath)
+		for i := range components {
+			dirPaths = append(dirPaths, normalpath.Join(components[:i+1]...))
+		}
+	}
+	for _, dirPath := range dirPaths {
+		rules := b.rules[dirPath]
+		if len(rules) == 0 {
+			continue
+		}
+		relPath, err := normalpath.Rel(dirPath, filePath)
+		if err != nil {
+			continue
+		}
+		for _, rule := range rules {
+			if !rule.matches(relPath, isDir) {
+				continue
+			}
+			for _, attribute := range rule.attributes {
+				if attribute.state == attributeStateUnspecified {
+					delete(attributes, attribute.name)
+					continue
+				}
+				attributes[attribute.name] = attribute
+			}
+		}
+	}
+	return attributes
+}
+
+func (r *attributeRule) matches(relPath string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if !r.anchored {
+		matched, _ := path.Match(r.pattern, normalpath.Base(relPath))
+		return matched
+	}
+	return matchPathComponents(strings.Split(r.pattern, "/"), strings.Split(relPath, "/"))
+}
+
+// matchPathComponents matches the components of a path against the components of a
+// pattern, where a ** component matches any number of components.
+func matchPathComponents(patternComponents []string, pathComponents []string) bool {
+	for len(patternComponents) > 0 {
+		if patternComponents[0] == "**" {
+			for i := 0; i <= len(pathComponents); i++ {
+				if matchPathComponents(patternComponents[1:], pathComponents[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(pathComponents) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(patternComponents[0], pathComponents[0]); !matched {
+			return false
+		}
+		patternComponents = patternComponents[1:]
+		pathComponents = pathComponents[1:]
+	}
+	return len(pathComponents) == 0
+}
+
+// parseAttributeRules parses the rules of a .gitattributes file.
+//
+// Lines that cannot be parsed are skipped, as git does.
+func parseAttributeRules(data []byte) []*attributeRule {
+	var rules []*attributeRule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[attr]") {
+			continue
+		}
+		var pattern string
+		if strings.HasPrefix(line, `"`) {
+			// Patterns with special characters are quoted in the style of C strings.
+			end := -1
+			for i := 1; i < len(line); i++ {
+				if line[i] == '\\' {
+					i++
+					continue
+				}
+				if line[i] == '"' {
+					end = i
+					break
+				}
+			}
+			if end < 0 {
+				continue
+			}
+			unquoted, err := strconv.Unquote(line[:end+1])
+			if err != nil {
+				continue
+			}
+			pattern = unquoted
+			line = line[end+1:]
+		} else {
+			pattern = line
+			line = ""
+			if index := strings.IndexAny(pattern, " \t"); index >= 0 {
+				pattern, line = pattern[:index], pattern[index:]
+			}
+		}
+		if strings.HasPrefix(pattern, "!") {
+			// Negative patterns are forbidden in .gitattributes files.
+			continue
+		}
+		rule := &attributeRule{}
+		if strings.HasSuffix(pattern, "/") {
+			rule.dirOnly = true
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+		rule.anchored = strings.Contains(pattern, "/")
+		rule.pattern = strings.TrimPrefix(pattern, "/")
+		if rule.pattern == "" {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			rule.attributes = append(rule.attributes, parseAttribute(field)...)
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func parseAttribute(field string) []attribute {
+	switch {
+	case strings.HasPrefix(field, "-"):
+		return []attribute{{name: field[1:], state: attributeStateUnset}}
+	case strings.HasPrefix(field, "!"):
+		return []attribute{{name: field[1:], state: attributeStateUnspecified}}
+	}
+	if name, value, ok := strings.Cut(field, "="); ok {
+		return []attribute{{name: name, state: attributeStateValue, value: value}}
+	}
+	if macroAttributes, ok := builtinAttributeMacros[field]; ok {
+		return append([]attribute{{name: field, state: attributeStateSet}}, macroAttributes...)
+	}
+	return []attribute{{name: field, state: attributeStateSet}}
+}
+
+// convertLFToCRLF converts LF line endings that are not already part of a CRLF
+// line ending to CRLF.
+func convertLFToCRLF(data []byte) []byte {
+	converted := make([]byte, 0, len(data)+bytes.Count(data, []byte{'\n'}))
+	for i, c := range data {
+		if c == '\n' && (i == 0 || data[i-1] != '\r') {
+			converted = append(converted, '\r')
+		}
+		converted = append(converted, c)
+	}
+	return converted
+}
+
+// isBinary returns true if the data looks binary in the same manner as git, that is
+// if there is a NUL byte within its first 8000 bytes.
+func isBinary(data []byte) bool {
+	if len(data) > binaryDetectionLength {
+		data = data[:binaryDetectionLength]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+type bytesReadObjectCloser struct {
+	storage.ObjectInfo
+	*bytes.Reader
+}
+
+func newBytesReadObjectCloser(objectInfo storage.ObjectInfo, data []byte) *bytesReadObjectCloser {
+	return &bytesReadObjectCloser{
+		ObjectInfo: objectInfo,
+		Reader:     bytes.NewReader(data),
+	}
+}
+
+func (*bytesReadObjectCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bufbuild/buf/private/pkg/app"
+	"github.com/bufbuild/buf/private/pkg/execext"
+	"github.com/bufbuild/buf/private/pkg/stringutil"
+)
+
+func getChangedFilesSinceRef(
+	ctx context.Context,
+	envContainer app.EnvContainer,
+	dir string,
+	ref string,
+) ([]*ChangedFile, error) {
+	environ := app.Environ(envContainer)
+	rootDir, err := runGit(ctx, environ, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get root of git repository for %s: %w", dir, err)
+	}
+	rootDir = strings.TrimSpace(rootDir)
+	mergeBase, err := GetMergeBase(ctx, envContainer, rootDir, ref, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := runGit(
+		ctx,
+		environ,
+		rootDir,
+		"-c",
+		"core.quotePath=false",
+		"diff",
+		"--unified=0",
+		"--no-color",
+		"--no-ext-diff",
+		// Renamed files are considered new, as their lines cannot be mapped to the ref.
+		"--no-renames",
+		strings.TrimSpace(mergeBase),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changes since %s: %w", ref, err)
+	}
+	changedFiles, err := parseUnifiedDiff(diff)
+	if err != nil {
+		return nil, err
+	}
+	untrackedFiles, err := runGit(
+		ctx,
+		environ,
+		rootDir,
+		"-c",
+		"core.quotePath=false",
+		"ls-files",
+		"--others",
+		"--exclude-standard",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get untracked files: %w", err)
+	}
+	for _, untrackedFile := range stringutil.SplitTrimLinesNoEmpty(untrackedFiles) {
+		changedFiles = append(changedFiles, &ChangedFile{Path: untrackedFile, IsNew: true})
+	}
+	for _, changedFile := range changedFiles {
+		changedFile.Path = filepath.Join(rootDir, filepath.FromSlash(changedFile.Path))
+	}
+	slices.SortFunc(changedFiles, func(a *ChangedFile, b *ChangedFile) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return changedFiles, nil
+}
+
+// parseUnifiedDiff parses the output of git diff --unified=0 into ChangedFiles with
+// paths relative to the root of the repository.
+func parseUnifiedDiff(diff string) ([]*ChangedFile, error) {
+	var changedFiles []*ChangedFile
+	var changedFile *ChangedFile
+	var isNew bool
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			changedFile = nil
+			isNew = false
+		case strings.HasPrefix(line, "--- "):
+			isNew = line == "--- /dev/null"
+		case strings.HasPrefix(line, "+++ "):
+			path := strings.TrimPrefix(line, "+++ ")
+			if path == "/dev/null" {
+				// Deleted file.
+				changedFile = nil
+				continue
+			}
+			changedFile = &ChangedFile{
+				Path:  strings.TrimPrefix(path, "b/"),
+				IsNew: isNew,
+			}
+			changedFiles = append(changedFiles, changedFile)
+		case strings.HasPrefix(line, "@@ ") && changedFile != nil && !changedFile.IsNew:
+			// All lines of new files are changed, so the ranges are not needed.
+			lineRange, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			if lineRange.EndLine < lineRange.StartLine {
+				changedFile.DeletedAfterLines = append(changedFile.DeletedAfterLines, lineRange.StartLine)
+			} else {
+				changedFile.LineRanges = append(changedFile.LineRanges, lineRange)
+			}
+		}
+	}
+	return changedFiles, nil
+}
+
+// parseHunkHeader parses the range of new lines from a hunk header of the form
+// "@@ -a,b +c,d @@".
+//
+// If the hunk only removes lines, the returned range is empty, with the line after
+// which the lines were removed as the start line.
+func parseHunkHeader(line string) (LineRange, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return LineRange{}, fmt.Errorf("invalid hunk header: %q", line)
+	}
+	startString, countString, hasCount := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+	start, err := strconv.Atoi(startString)
+	if err != nil {
+		return LineRange{}, fmt.Errorf("invalid hunk header: %q", line)
+	}
+	count := 1
+	if hasCount {
+		count, err = strconv.Atoi(countString)
+		if err != nil {
+			return LineRange{}, fmt.Errorf("invalid hunk header: %q", line)
+		}
+	}
+	return LineRange{StartLine: start, EndLine: start + count - 1}, nil
+}
+
+func runGit(ctx context.Context, environ []string, dir string, args ...string) (string, error) {
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	if err := execext.Run(
+		ctx,
+		gitCommand,
+		execext.WithArgs(args...),
+		execext.WithStdout(stdout),
+		execext.WithStderr(stderr),
+		execext.WithDir(dir),
+		execext.WithEnv(environ),
+	); err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,246 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	commitGraphSignature         = "CGPH"
+	commitGraphVersion           = 1
+	commitGraphHashVersionSHA1   = 1
+	commitGraphHeaderLength      = 8
+	commitGraphChunkEntryLength  = 12
+	commitGraphFanoutLength      = 256 * 4
+	commitGraphCommitDataLength  = objectIDLength + 16
+	commitGraphParentNone        = 0x70000000
+	commitGraphParentExtraEdges  = 0x80000000
+	commitGraphLastExtraEdge     = 0x80000000
+	commitGraphChunkIDOIDFanout  = 0x4f494446 // OIDF
+	commitGraphChunkIDOIDLookup  = 0x4f49444c // OIDL
+	commitGraphChunkIDCommitData = 0x43444154 // CDAT
+	commitGraphChunkIDExtraEdges = 0x45444745 // EDGE
+)
+
+// commitGraph is the commit-graph of a repository, which stores the parents,
+// generation numbers and commit times of commits so that walking the history
+// does not require reading and inflating commit objects.
+//
+// A commit-graph is either a single file, or a chain of layers where each layer
+// contains the commits that were added after the layers before it. Commits are
+// identified by their position within the concatenated layers.
+type commitGraph struct {
+	layers []*commitGraphLayer
+}
+
+type commitGraphLayer struct {
+	fanout     []byte
+	objectIDs  []byte
+	commitData []byte
+	extraEdges []byte
+	numCommits int
+	// numBaseCommits is the number of commits in the layers before this layer.
+	numBaseCommits int
+}
+
+// readCommitGraph reads the commit-graph of the objects directory.
+//
+// Returns nil if the repository has no commit-graph.
+func readCommitGraph(objectsDirPath string) (*commitGraph, error) {
+	infoDirPath := filepath.Join(objectsDirPath, "info")
+	data, err := os.ReadFile(filepath.Join(infoDirPath, "commit-graph"))
+	if err == nil {
+		layer, err := parseCommitGraphLayer(data, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		return &commitGraph{layers: []*commitGraphLayer{layer}}, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	chainData, err := os.ReadFile(filepath.Join(infoDirPath, "commit-graphs", "commit-graph-chain"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	commitGraph := &commitGraph{}
+	var numBaseCommits int
+	scanner := bufio.NewScanner(bytes.NewReader(chainData))
+	for scanner.Scan() {
+		layerHash := strings.TrimSpace(scanner.Text())
+		if layerHash == "" {
+			continue
+		}
+		if _, ok := parseObjectID(layerHash); !ok {
+			return nil, fmt.Errorf("invalid commit-graph chain entry %q", layerHash)
+		}
+		data, err := os.ReadFile(filepath.Join(infoDirPath, "commit-graphs", "graph-"+layerHash+".graph"))
+		if err != nil {
+			return nil, err
+		}
+		layer, err := parseCommitGraphLayer(data, len(commitGraph.layers), numBaseCommits)
+		if err != nil {
+			return nil, err
+		}
+		commitGraph.layers = append(commitGraph.layers, layer)
+		numBaseCommits += layer.numCommits
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(commitGraph.layers) == 0 {
+		return nil, nil
+	}
+	return commitGraph, nil
+}
+
+// find returns the position of the commit with the ID.
+func (g *commitGraph) find(id objectID) (int, bool) {
+	for _, layer := range g.layers {
+		if position, ok := findInFanout(layer.fanout, layer.objectIDs, id); ok {
+			return layer.numBaseCommits + position, true
+		}
+	}
+	return 0, false
+}
+
+// objectID returns the ID of the commit at the position.
+func (g *commitGraph) objectID(position int) (objectID, error) {
+	var id objectID
+	layer, localPosition, err := g.getLayer(position)
+	if err != nil {
+		return id, err
+	}
+	copy(id[:], layer.objectIDs[localPosition*objectIDLength:])
+	return id, nil
+}
+
+// commit returns the positions of the parents, the generation number and the
+// commit time of the commit at the position.
+//
+// The generation number is the topological level of the commit, which is one more
+// than the maximum generation number of its parents. A generation number of zero
+// means that the generation number was not computed.
+func (g *commitGraph) commit(position int) ([]int, uint32, int64, error) {
+	layer, localPosition, err := g.getLayer(position)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	// The tree ID, the first and second parents, then the generation number and
+	// the commit time.
+	data := layer.commitData[localPosition*commitGraphCommitDataLength+objectIDLength:]
+	parent1 := binary.BigEndian.Uint32(data[0:4])
+	parent2 := binary.BigEndian.Uint32(data[4:8])
+	generationAndTimeHigh := binary.BigEndian.Uint32(data[8:12])
+	generation := generationAndTimeHigh >> 2
+	commitTime := int64(generationAndTimeHigh&0x3)<<32 | int64(binary.BigEndian.Uint32(data[12:16]))
+	var parents []int
+	if parent1 != commitGraphParentNone {
+		parents = append(parents, int(parent1))
+	}
+	switch {
+	case parent2 == commitGraphParentNone:
+	case parent2&commitGraphParentExtraEdges == 0:
+		parents = append(parents, int(parent2))
+	default:
+		// Octopus merges list their second and later parents in the extra edges.
+		for i := int(parent2 &^ commitGraphParentExtraEdges); ; i++ {
+			if (i+1)*4 > len(layer.extraEdges) {
+				return nil, 0, 0, errors.New("invalid commit-graph extra edges")
+			}
+			edge := binary.BigEndian.Uint32(layer.extraEdges[i*4:])
+			parents = append(parents, int(edge&^commitGraphLastExtraEdge))
+			if edge&commitGraphLastExtraEdge != 0 {
+				break
+			}
+		}
+	}
+	return parents, generation, commitTime, nil
+}
+
+func (g *commitGraph) getLayer(position int) (*commitGraphLayer, int, error) {
+	for _, layer := range g.layers {
+		if position >= layer.numBaseCommits && position < layer.numBaseCommits+layer.numCommits {
+			return layer, position - layer.numBaseCommits, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("invalid commit-graph position %d", position)
+}
+
+// parseCommitGraphLayer parses a commit-graph file, which is either the whole
+// commit-graph or a layer of a chain with numBaseGraphs layers before it.
+func parseCommitGraphLayer(data []byte, numBaseGraphs int, numBaseCommits int) (*commitGraphLayer, error) {
+	if len(data) < commitGraphHeaderLength || string(data[:4]) != commitGraphSignature {
+		return nil, errors.New("invalid commit-graph signature")
+	}
+	if version := data[4]; version != commitGraphVersion {
+		return nil, fmt.Errorf("unsupported commit-graph version %d", version)
+	}
+	if hashVersion := data[5]; hashVersion != commitGraphHashVersionSHA1 {
+		return nil, fmt.Errorf("unsupported commit-graph hash version %d: %w", hashVersion, errUnsupportedRepository)
+	}
+	numChunks := int(data[6])
+	if int(data[7]) != numBaseGraphs {
+		return nil, fmt.Errorf("commit-graph has %d base graphs, expected %d", data[7], numBaseGraphs)
+	}
+	// The table of contents has an extra entry that marks the end of the last chunk.
+	if len(data) < commitGraphHeaderLength+(numChunks+1)*commitGraphChunkEntryLength {
+		return nil, errors.New("truncated commit-graph")
+	}
+	chunks := make(map[uint32][]byte, numChunks)
+	for i := range numChunks {
+		entry := data[commitGraphHeaderLength+i*commitGraphChunkEntryLength:]
+		chunkID := binary.BigEndian.Uint32(entry[0:4])
+		start := binary.BigEndian.Uint64(entry[4:12])
+		end := binary.BigEndian.Uint64(entry[commitGraphChunkEntryLength+4 : commitGraphChunkEntryLength+12])
+		if start > end || end > uint64(len(data)) {
+			return nil, errors.New("invalid commit-graph chunk offsets")
+		}
+		chunks[chunkID] = data[start:end]
+	}
+	fanout := chunks[commitGraphChunkIDOIDFanout]
+	if len(fanout) != commitGraphFanoutLength {
+		return nil, errors.New("invalid commit-graph OID fanout chunk")
+	}
+	numCommits := int(binary.BigEndian.Uint32(fanout[255*4:]))
+	objectIDs := chunks[commitGraphChunkIDOIDLookup]
+	if len(objectIDs) != numCommits*objectIDLength {
+		return nil, errors.New("invalid commit-graph OID lookup chunk")
+	}
+	commitData := chunks[commitGraphChunkIDCommitData]
+	if len(commitData) != numCommits*commitGraphCommitDataLength {
+		return nil, errors.New("invalid commit-graph commit data chunk")
+	}
+	return &commitGraphLayer{
+		fanout:         fanout,
+		objectIDs:      objectIDs,
+		commitData:     commitData,
+		extraEdges:     chunks[commitGraphChunkIDExtraEdges],
+		numCommits:     numCommits,
+		numBaseCommits: numBaseCommits,
+	}, nil
+}
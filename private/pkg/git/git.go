@@ -25,10 +25,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bufbuild/buf/private/pkg/app"
 	"github.com/bufbuild/buf/private/pkg/execext"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
 	"github.com/bufbuild/buf/private/pkg/storage"
 	"github.com/bufbuild/buf/private/pkg/storage/storageos"
 )
@@ -125,6 +128,57 @@ type ClonerOptions struct {
 	SSHKnownHostsFilesEnvKey string
 }
 
+// ObjectReader reads objects from a git repository without a checkout.
+type ObjectReader interface {
+	// ResolveCommit resolves the Name to the hash of the commit it refers to.
+	//
+	// Names are resolved within the repository, in the same manner as a clone
+	// of the repository would resolve them. A nil Name resolves to HEAD.
+	ResolveCommit(ctx context.Context, name Name) (string, error)
+	// ListBlobs lists the blobs in the tree of the commit, recursively.
+	//
+	// Symbolic links and submodules are not listed.
+	ListBlobs(ctx context.Context, commit string) ([]Blob, error)
+	// ReadBlob reads the contents of the blob with the hash.
+	ReadBlob(ctx context.Context, hash string) ([]byte, error)
+	// Close closes the ObjectReader.
+	Close() error
+}
+
+// NewObjectReader returns a new ObjectReader for the git repository that
+// contains dir, which may also be the .git directory itself.
+//
+// Blobs are read with a single long-running git cat-file process, which is
+// stopped on Close. The ObjectReader must be closed when done.
+func NewObjectReader(
+	ctx context.Context,
+	envContainer app.EnvContainer,
+	dir string,
+) (ObjectReader, error) {
+	return newObjectReader(ctx, envContainer, dir)
+}
+
+// Blob is a file within the tree of a commit.
+type Blob struct {
+	// Path is the normalized path of the blob relative to the root of the repository.
+	Path string
+	// Hash is the hash of the blob.
+	Hash string
+	// Size is the size of the blob in bytes.
+	Size int64
+}
+
+// NewExportReadBucket returns a ReadBucket for the files of the tree of a commit in
+// readBucket as git archive exports them, according to the .gitattributes files in
+// the tree.
+//
+// Files and directories with the export-ignore attribute are not in the returned
+// ReadBucket, and text files with the eol=crlf attribute have their line endings
+// converted to CRLF. The root of readBucket must be the root of the tree.
+func NewExportReadBucket(ctx context.Context, readBucket storage.ReadBucket) (storage.ReadBucket, error) {
+	return newExportReadBucket(ctx, readBucket)
+}
+
 // Lister lists files in git repositories.
 type Lister interface {
 	// ListFilesAndUnstagedFiles lists all files checked into git except those that
@@ -312,6 +366,82 @@ func GetCurrentHEADGitCommit(
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+// GetCommitTime returns the committer time of the given ref in the git repository that
+// contains dir.
+func GetCommitTime(
+	ctx context.Context,
+	envContainer app.EnvContainer,
+	dir string,
+	ref string,
+) (time.Time, error) {
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	if err := execext.Run(
+		ctx,
+		gitCommand,
+		execext.WithArgs("show", "--no-patch", "--format=%ct", ref),
+		execext.WithStdout(stdout),
+		execext.WithStderr(stderr),
+		execext.WithDir(dir),
+		execext.WithEnv(app.Environ(envContainer)),
+	); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get commit time of %s: %w: %s", ref, err, strings.TrimSpace(stderr.String()))
+	}
+	unixSeconds, err := strconv.ParseInt(strings.TrimSpace(stdout.String()), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse commit time of %s: %w", ref, err)
+	}
+	return time.Unix(unixSeconds, 0).UTC(), nil
+}
+
+// GetCommitsForDir returns the hashes of the commits reachable from HEAD that changed
+// files within dir, from newest to oldest.
+func GetCommitsForDir(
+	ctx context.Context,
+	envContainer app.EnvContainer,
+	dir string,
+) ([]string, error) {
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	if err := execext.Run(
+		ctx,
+		gitCommand,
+		execext.WithArgs("rev-list", "HEAD", "--", "."),
+		execext.WithStdout(stdout),
+		execext.WithStderr(stderr),
+		execext.WithDir(dir),
+		execext.WithEnv(app.Environ(envContainer)),
+	); err != nil {
+		return nil, fmt.Errorf("failed to list commits for %s: %w: %s", dir, err, strings.TrimSpace(stderr.String()))
+	}
+	return getAllTrimmedLinesFromBuffer(stdout), nil
+}
+
+// GetDirPathInRepository returns the normalized path of dir relative to the root of
+// the git repository that contains it.
+//
+// Returns "." if dir is the root of the repository.
+func GetDirPathInRepository(
+	ctx context.Context,
+	envContainer app.EnvContainer,
+	dir string,
+) (string, error) {
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	if err := execext.Run(
+		ctx,
+		gitCommand,
+		execext.WithArgs("rev-parse", "--show-prefix"),
+		execext.WithStdout(stdout),
+		execext.WithStderr(stderr),
+		execext.WithDir(dir),
+		execext.WithEnv(app.Environ(envContainer)),
+	); err != nil {
+		return "", fmt.Errorf("failed to get path of %s in git repository: %w: %s", dir, err, strings.TrimSpace(stderr.String()))
+	}
+	return normalpath.Normalize(strings.TrimSpace(stdout.String())), nil
+}
+
 // GetRefsForGitCommitAndRemote returns all refs pointing to a given commit based on the
 // given remote for the given directory. Querying the remote for refs information requires
 // passing the environment for permissions.
@@ -384,6 +514,84 @@ func IsValidRef(
 	return nil
 }
 
+// GetMergeBase returns the commit of the best common ancestor of the two refs in the git
+// repository that contains dir, as in git merge-base.
+//
+// This is used to compare against the point at which a branch was forked from another
+// branch, instead of the tip of the other branch, in the manner of git diff ref1...ref2.
+//
+// The repository is read directly, including packfiles and commit-graphs, which avoids
+// walking the history of large repositories with git. Only repositories and refs that
+// are not supported by the reader, such as repositories with SHA-256 object names or
+// abbreviated hashes, fall back to git merge-base.
+func GetMergeBase(
+	ctx context.Context,
+	envContainer app.EnvContainer,
+	dir string,
+	ref1 string,
+	ref2 string,
+) (string, error) {
+	mergeBase, err := getMergeBaseWithHistory(dir, ref1, ref2)
+	if err == nil {
+		return mergeBase, nil
+	}
+	if !errors.Is(err, errUnsupportedRepository) {
+		return "", fmt.Errorf("failed to get merge base of %s and %s: %w", ref1, ref2, err)
+	}
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	if err := execext.Run(
+		ctx,
+		gitCommand,
+		execext.WithArgs("merge-base", ref1, ref2),
+		execext.WithStdout(stdout),
+		execext.WithStderr(stderr),
+		execext.WithDir(dir),
+		execext.WithEnv(app.Environ(envContainer)),
+	); err != nil {
+		return "", fmt.Errorf("failed to get merge base of %s and %s: %w: %s", ref1, ref2, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// IsAncestor returns true if the commit of the ancestor ref is an ancestor of the commit
+// of the descendant ref in the git repository that contains dir, or is the same commit,
+// as in git merge-base --is-ancestor.
+//
+// The repository is read directly in the same manner as GetMergeBase, and only repositories
+// and refs that are not supported by the reader fall back to git merge-base --is-ancestor.
+func IsAncestor(
+	ctx context.Context,
+	envContainer app.EnvContainer,
+	dir string,
+	ancestorRef string,
+	descendantRef string,
+) (bool, error) {
+	isAncestor, err := isAncestorWithHistory(dir, ancestorRef, descendantRef)
+	if err == nil {
+		return isAncestor, nil
+	}
+	if !errors.Is(err, errUnsupportedRepository) {
+		return false, fmt.Errorf("failed to check if %s is an ancestor of %s: %w", ancestorRef, descendantRef, err)
+	}
+	stderr := bytes.NewBuffer(nil)
+	if err := execext.Run(
+		ctx,
+		gitCommand,
+		execext.WithArgs("merge-base", "--is-ancestor", ancestorRef, descendantRef),
+		execext.WithStderr(stderr),
+		execext.WithDir(dir),
+		execext.WithEnv(app.Environ(envContainer)),
+	); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check if %s is an ancestor of %s: %w: %s", ancestorRef, descendantRef, err, strings.TrimSpace(stderr.String()))
+	}
+	return true, nil
+}
+
 // ReadFileAtRef will read the file at path rolled back to the given ref, if
 // it exists at that ref.
 //
@@ -442,6 +650,69 @@ func ReadFileAtRef(
 	return stdout.Bytes(), nil
 }
 
+// LineRange is a range of lines.
+type LineRange struct {
+	// StartLine is the 1-based first line of the range.
+	StartLine int
+	// EndLine is the 1-based last line of the range, inclusive.
+	EndLine int
+}
+
+// ChangedFile is a file that was added or modified since a ref.
+type ChangedFile struct {
+	// Path is the absolute path of the file.
+	Path string
+	// IsNew is true if the file did not exist at the ref, or is untracked.
+	//
+	// All lines of new files are considered changed.
+	IsNew bool
+	// LineRanges are the ranges of lines that were added or modified, sorted by line.
+	//
+	// Lines that were only removed are not included, as they no longer exist.
+	LineRanges []LineRange
+	// DeletedAfterLines are the 1-based lines after which lines were removed without
+	// being replaced, sorted. 0 is used for lines removed from the start of the file.
+	DeletedAfterLines []int
+}
+
+// ContainsLine returns true if the 1-based line was added or modified.
+func (c *ChangedFile) ContainsLine(line int) bool {
+	if c.IsNew {
+		return true
+	}
+	for _, lineRange := range c.LineRanges {
+		if lineRange.StartLine <= line && line <= lineRange.EndLine {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsDeletionWithin returns true if lines were removed between the 1-based
+// startLine and endLine, inclusive.
+func (c *ChangedFile) ContainsDeletionWithin(startLine int, endLine int) bool {
+	for _, deletedAfterLine := range c.DeletedAfterLines {
+		if startLine <= deletedAfterLine && deletedAfterLine < endLine {
+			return true
+		}
+	}
+	return false
+}
+
+// GetChangedFilesSinceRef returns the files that were added or modified in the working
+// tree of the git repository containing dir since the merge base of ref and HEAD, that is
+// the changes that a pull request against ref would contain, including uncommitted changes.
+//
+// Untracked files that are not ignored are considered new. Deleted files are not returned.
+func GetChangedFilesSinceRef(
+	ctx context.Context,
+	envContainer app.EnvContainer,
+	dir string,
+	ref string,
+) ([]*ChangedFile, error) {
+	return getChangedFilesSinceRef(ctx, envContainer, dir, ref)
+}
+
 func getAllTrimmedLinesFromBuffer(buffer *bytes.Buffer) []string {
 	scanner := bufio.NewScanner(buffer)
 	var lines []string
@@ -450,3 +721,25 @@ func getAllTrimmedLinesFromBuffer(buffer *bytes.Buffer) []string {
 	}
 	return lines
 }
+
+func getMergeBaseWithHistory(dir string, ref1 string, ref2 string) (_ string, retErr error) {
+	history, err := newHistory(dir)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, history.close())
+	}()
+	return history.getMergeBase(ref1, ref2)
+}
+
+func isAncestorWithHistory(dir string, ancestorRef string, descendantRef string) (_ bool, retErr error) {
+	history, err := newHistory(dir)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		retErr = errors.Join(retErr, history.close())
+	}()
+	return history.isAncestorRevision(ancestorRef, descendantRef)
+}
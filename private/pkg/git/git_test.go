@@ -24,6 +24,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -382,6 +383,470 @@ func readBucketForName(ctx context.Context, t *testing.T, path string, options r
 	return readWriteBucket
 }
 
+func TestGetChangedFilesSinceRef(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	container, err := app.NewContainerForOS()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	runCommand(ctx, t, container, "git", "-C", dir, "init")
+	runCommand(ctx, t, container, "git", "-C", dir, "config", "user.email", "tests@buf.build")
+	runCommand(ctx, t, container, "git", "-C", dir, "config", "user.name", "Buf go tests")
+	runCommand(ctx, t, container, "git", "-C", dir, "checkout", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.proto"), []byte("1\n2\n3\n4\n5\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deleted.proto"), []byte("1\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.proto"), []byte("1\n"), 0600))
+	runCommand(ctx, t, container, "git", "-C", dir, "add", ".")
+	runCommand(ctx, t, container, "git", "-C", dir, "commit", "-m", "commit 0")
+	runCommand(ctx, t, container, "git", "-C", dir, "checkout", "-b", "feature")
+	// Line 2 is modified, line 4 is removed, and a line is added at the end.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.proto"), []byte("1\ntwo\n3\n5\n6\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "added.proto"), []byte("1\n"), 0600))
+	runCommand(ctx, t, container, "git", "-C", dir, "add", ".")
+	runCommand(ctx, t, container, "git", "-C", dir, "commit", "-m", "commit 1")
+	// Changes to main since the merge base are not included.
+	runCommand(ctx, t, container, "git", "-C", dir, "checkout", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.proto"), []byte("one\n"), 0600))
+	runCommand(ctx, t, container, "git", "-C", dir, "commit", "-a", "-m", "commit 2")
+	runCommand(ctx, t, container, "git", "-C", dir, "checkout", "feature")
+	// Uncommitted changes are included.
+	require.NoError(t, os.Remove(filepath.Join(dir, "deleted.proto")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "untracked.proto"), []byte("1\n"), 0600))
+
+	changedFiles, err := GetChangedFilesSinceRef(ctx, container, dir, "main")
+	require.NoError(t, err)
+	rootDir, err := runStdout(ctx, container, "git", "-C", dir, "rev-parse", "--show-toplevel")
+	require.NoError(t, err)
+	root := strings.TrimSpace(string(rootDir))
+	assert.Equal(
+		t,
+		[]*ChangedFile{
+			{
+				Path: filepath.Join(root, "a.proto"),
+				LineRanges: []LineRange{
+					{StartLine: 2, EndLine: 2},
+					{StartLine: 5, EndLine: 5},
+				},
+				DeletedAfterLines: []int{3},
+			},
+			{
+				Path:  filepath.Join(root, "added.proto"),
+				IsNew: true,
+			},
+			{
+				Path:  filepath.Join(root, "untracked.proto"),
+				IsNew: true,
+			},
+		},
+		changedFiles,
+	)
+	assert.True(t, changedFiles[0].ContainsLine(2))
+	assert.False(t, changedFiles[0].ContainsLine(3))
+	assert.True(t, changedFiles[0].ContainsDeletionWithin(3, 4))
+	assert.False(t, changedFiles[0].ContainsDeletionWithin(4, 5))
+	assert.True(t, changedFiles[1].ContainsLine(1))
+	_, err = GetChangedFilesSinceRef(ctx, container, dir, "nonexistent")
+	assert.Error(t, err)
+}
+
+func TestGetMergeBase(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	container, err := app.NewContainerForOS()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	runCommand(ctx, t, container, "git", "-C", dir, "init")
+	runCommand(ctx, t, container, "git", "-C", dir, "config", "user.email", "tests@buf.build")
+	runCommand(ctx, t, container, "git", "-C", dir, "config", "user.name", "Buf go tests")
+	runCommand(ctx, t, container, "git", "-C", dir, "checkout", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.proto"), []byte("1\n"), 0600))
+	runCommand(ctx, t, container, "git", "-C", dir, "add", ".")
+	runCommand(ctx, t, container, "git", "-C", dir, "commit", "-m", "commit 0")
+	forkCommit, err := runStdout(ctx, container, "git", "-C", dir, "rev-parse", "HEAD")
+	require.NoError(t, err)
+	runCommand(ctx, t, container, "git", "-C", dir, "checkout", "-b", "feature")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.proto"), []byte("feature\n"), 0600))
+	runCommand(ctx, t, container, "git", "-C", dir, "commit", "-a", "-m", "commit 1")
+	// Advancing main does not move the merge base.
+	runCommand(ctx, t, container, "git", "-C", dir, "checkout", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.proto"), []byte("main\n"), 0600))
+	runCommand(ctx, t, container, "git", "-C", dir, "commit", "-a", "-m", "commit 2")
+	runCommand(ctx, t, container, "git", "-C", dir, "checkout", "feature")
+
+	mergeBase, err := GetMergeBase(ctx, container, dir, "HEAD", "main")
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(forkCommit)), mergeBase)
+	// Abbreviated hashes are not read directly and fall back to git.
+	mergeBase, err = GetMergeBase(ctx, container, dir, "HEAD", strings.TrimSpace(string(forkCommit))[:7])
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(forkCommit)), mergeBase)
+	// Refs that do not exist are reported by the reader instead of falling back to git.
+	_, err = GetMergeBase(ctx, container, dir, "HEAD", "nonexistent")
+	assert.ErrorIs(t, err, ErrInvalidRef)
+
+	isAncestor, err := IsAncestor(ctx, container, dir, "main~1", "HEAD")
+	require.NoError(t, err)
+	assert.True(t, isAncestor)
+	isAncestor, err = IsAncestor(ctx, container, dir, "main", "HEAD")
+	require.NoError(t, err)
+	assert.False(t, isAncestor)
+	isAncestor, err = IsAncestor(ctx, container, dir, strings.TrimSpace(string(forkCommit))[:7], "main")
+	require.NoError(t, err)
+	assert.True(t, isAncestor)
+	_, err = IsAncestor(ctx, container, dir, "nonexistent", "HEAD")
+	assert.ErrorIs(t, err, ErrInvalidRef)
+}
+
+func TestGetCommitTime(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	container, err := app.NewContainerForOS()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	runCommand(ctx, t, container, "git", "-C", dir, "init")
+	runCommand(ctx, t, container, "git", "-C", dir, "config", "user.email", "tests@buf.build")
+	runCommand(ctx, t, container, "git", "-C", dir, "config", "user.name", "Buf go tests")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.proto"), []byte("1\n"), 0600))
+	runCommand(ctx, t, container, "git", "-C", dir, "add", ".")
+	runCommand(ctx, t, container, "git", "-C", dir, "commit", "-m", "commit 0")
+	commitTimeString, err := runStdout(ctx, container, "git", "-C", dir, "log", "-1", "--format=%ct")
+	require.NoError(t, err)
+
+	commitTime, err := GetCommitTime(ctx, container, dir, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(commitTimeString)), strconv.FormatInt(commitTime.Unix(), 10))
+	_, err = GetCommitTime(ctx, container, dir, "nonexistent")
+	assert.Error(t, err)
+}
+
+func TestGetCommitsForDir(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	container, err := app.NewContainerForOS()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	runCommand(ctx, t, container, "git", "-C", dir, "init")
+	runCommand(ctx, t, container, "git", "-C", dir, "config", "user.email", "tests@buf.build")
+	runCommand(ctx, t, container, "git", "-C", dir, "config", "user.name", "Buf go tests")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "proto", "a"), 0777))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "proto", "a", "a.proto"), []byte("1\n"), 0600))
+	runCommand(ctx, t, container, "git", "-C", dir, "add", ".")
+	runCommand(ctx, t, container, "git", "-C", dir, "commit", "-m", "commit 0")
+	commit0, err := runStdout(ctx, container, "git", "-C", dir, "rev-parse", "HEAD")
+	require.NoError(t, err)
+	// Commits that do not change files within the directory are not returned.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("1\n"), 0600))
+	runCommand(ctx, t, container, "git", "-C", dir, "add", ".")
+	runCommand(ctx, t, container, "git", "-C", dir, "commit", "-m", "commit 1")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "proto", "a", "a.proto"), []byte("2\n"), 0600))
+	runCommand(ctx, t, container, "git", "-C", dir, "commit", "-a", "-m", "commit 2")
+	commit2, err := runStdout(ctx, container, "git", "-C", dir, "rev-parse", "HEAD")
+	require.NoError(t, err)
+
+	commits, err := GetCommitsForDir(ctx, container, filepath.Join(dir, "proto"))
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]string{
+			strings.TrimSpace(string(commit2)),
+			strings.TrimSpace(string(commit0)),
+		},
+		commits,
+	)
+	dirPath, err := GetDirPathInRepository(ctx, container, filepath.Join(dir, "proto", "a"))
+	require.NoError(t, err)
+	assert.Equal(t, "proto/a", dirPath)
+	dirPath, err = GetDirPathInRepository(ctx, container, dir)
+	require.NoError(t, err)
+	assert.Equal(t, ".", dirPath)
+}
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	container, err := app.NewContainerForOS()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	git := func(args ...string) {
+		runCommand(ctx, t, container, "git", append([]string{"-C", dir}, args...)...)
+	}
+	commitNumber := 0
+	commit := func() {
+		// Large files that are similar to each other, so that repacking stores deltas.
+		var lines []string
+		for i := range 200 {
+			lines = append(lines, fmt.Sprintf("line %d %d", i, i%(commitNumber+2)))
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%d.txt", commitNumber)), []byte(strings.Join(lines, "\n")), 0600))
+		git("add", ".")
+		git("commit", "-m", fmt.Sprintf("commit %d", commitNumber))
+		commitNumber++
+	}
+	git("init")
+	git("config", "user.email", "tests@buf.build")
+	git("config", "user.name", "Buf go tests")
+	git("config", "gc.auto", "0")
+	git("checkout", "-b", "main")
+	commit()
+	commit()
+	git("checkout", "-b", "feature")
+	commit()
+	commit()
+	git("tag", "-a", "v1", "-m", "v1")
+	git("checkout", "main")
+	commit()
+	git("merge", "--no-edit", "feature")
+	commit()
+	// Criss-cross merges, where x and y have two merge bases.
+	git("checkout", "-b", "x")
+	commit()
+	git("checkout", "-b", "y", "main")
+	commit()
+	git("checkout", "-b", "y1")
+	git("checkout", "x")
+	git("checkout", "-b", "x1")
+	git("checkout", "x")
+	git("merge", "--no-edit", "y1")
+	git("checkout", "y")
+	git("merge", "--no-edit", "x1")
+	// An octopus merge, which has more than two parents.
+	git("checkout", "-b", "o1", "main")
+	commit()
+	git("checkout", "-b", "o2", "main")
+	commit()
+	git("checkout", "main")
+	commit()
+	git("merge", "--no-edit", "o1", "o2")
+	git("checkout", "--orphan", "unrelated")
+	commit()
+	git("checkout", "main")
+
+	branches := []string{"main", "feature", "v1", "x", "y", "x1", "y1", "o1", "o2", "unrelated"}
+	revisions := append(
+		[]string{"HEAD", "HEAD^2", "HEAD^3", "HEAD~2", "HEAD~3^2", "refs/heads/feature", "refs/tags/v1", "x^2~1"},
+		branches...,
+	)
+	testHistory := func(t *testing.T) {
+		history, err := newHistory(dir)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, history.close())
+		})
+		for _, revision := range revisions {
+			expected, err := runStdout(ctx, container, "git", "-C", dir, "rev-parse", revision+"^{commit}")
+			require.NoError(t, err, revision)
+			commit, err := history.resolveCommit(revision)
+			require.NoError(t, err, revision)
+			assert.Equal(t, strings.TrimSpace(string(expected)), commit.String(), revision)
+		}
+		_, err = history.resolveCommit("missing")
+		assert.ErrorIs(t, err, ErrInvalidRef)
+		for _, branch1 := range branches {
+			for _, branch2 := range branches {
+				_, err := runStdout(ctx, container, "git", "-C", dir, "merge-base", "--is-ancestor", branch1, branch2)
+				expectedIsAncestor := err == nil
+				id1, err := history.resolveCommit(branch1)
+				require.NoError(t, err)
+				id2, err := history.resolveCommit(branch2)
+				require.NoError(t, err)
+				isAncestor, err := history.isAncestor(id1, id2)
+				require.NoError(t, err)
+				assert.Equal(t, expectedIsAncestor, isAncestor, "%s %s", branch1, branch2)
+				expected, err := runStdout(ctx, container, "git", "-C", dir, "merge-base", "--all", branch1, branch2)
+				mergeBase, mergeBaseErr := history.getMergeBase(branch1, branch2)
+				if err != nil {
+					assert.Error(t, mergeBaseErr, "%s %s", branch1, branch2)
+					continue
+				}
+				require.NoError(t, mergeBaseErr)
+				assert.Contains(t, strings.Fields(string(expected)), mergeBase, "%s %s", branch1, branch2)
+			}
+		}
+	}
+
+	t.Run("loose", testHistory)
+	git("-c", "repack.useDeltaBaseOffset=false", "repack", "-a", "-d", "-f", "--depth=50", "--window=50")
+	t.Run("ref-delta", testHistory)
+	git("repack", "-a", "-d", "-f", "--depth=50", "--window=50")
+	t.Run("ofs-delta", testHistory)
+	git("commit-graph", "write", "--reachable")
+	t.Run("commit-graph", testHistory)
+	require.NoError(t, os.Remove(filepath.Join(dir, ".git", "objects", "info", "commit-graph")))
+	git("checkout", "feature")
+	commit()
+	git("checkout", "main")
+	t.Run("partial-commit-graph", testHistory)
+	git("commit-graph", "write", "--reachable", "--split")
+	git("checkout", "feature")
+	commit()
+	git("checkout", "main")
+	git("commit-graph", "write", "--reachable", "--split=no-merge")
+	t.Run("commit-graph-chain", testHistory)
+}
+
+func TestExportReadBucket(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	readBucket, err := storagemem.NewReadBucket(
+		map[string][]byte{
+			".gitattributes": []byte(`# Comments and blank lines are skipped.
+
+testdata export-ignore
+/vendor/ export-ignore
+*.crlf text eol=crlf
+*.auto text=auto eol=crlf
+*.bin binary eol=crlf
+"with space.proto" export-ignore
+`),
+			"a.proto":                        []byte("a"),
+			"with space.proto":               []byte("a"),
+			"testdata/a.proto":               []byte("a"),
+			"b/testdata/a.proto":             []byte("a"),
+			"vendor/a.proto":                 []byte("a"),
+			"b/vendor/a.proto":               []byte("a"),
+			"b/.gitattributes":               []byte("ignored.proto export-ignore\nc/a.proto export-ignore\n"),
+			"b/ignored.proto":                []byte("a"),
+			"b/c/a.proto":                    []byte("a"),
+			"b/c/.gitattributes":             []byte("a.proto -export-ignore\n"),
+			"b/c/ignored.proto":              []byte("a"),
+			"line_endings.crlf":              []byte("a\nb\r\nc\n"),
+			"line_endings.auto":              []byte("a\nb\n"),
+			"binary.auto":                    []byte("a\x00\nb\n"),
+			"binary.bin":                     []byte("a\nb\n"),
+			"d/.gitattributes":               []byte("*.crlf -text\n"),
+			"d/line_endings.crlf":            []byte("a\nb\n"),
+			"e/.gitattributes":               []byte("**/x/*.proto export-ignore\n"),
+			"e/x/a.proto":                    []byte("a"),
+			"e/y/x/a.proto":                  []byte("a"),
+			"e/y/a.proto":                    []byte("a"),
+			"unaffected/line_endings.txt":    []byte("a\nb\n"),
+			"unaffected/vendor.proto/a.pb":   []byte("a"),
+			"unaffected/testdata.txt/a.pb":   []byte("a"),
+			"unaffected/subdir/vendor/a.txt": []byte("a"),
+		},
+	)
+	require.NoError(t, err)
+	exportReadBucket, err := NewExportReadBucket(ctx, readBucket)
+	require.NoError(t, err)
+	paths, err := storage.AllPaths(ctx, exportReadBucket, "")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]string{
+			".gitattributes",
+			"a.proto",
+			"b/.gitattributes",
+			"b/c/.gitattributes",
+			"b/c/a.proto",
+			"b/vendor/a.proto",
+			"binary.auto",
+			"binary.bin",
+			"d/.gitattributes",
+			"d/line_endings.crlf",
+			"e/.gitattributes",
+			"e/y/a.proto",
+			"line_endings.auto",
+			"line_endings.crlf",
+			"unaffected/line_endings.txt",
+			"unaffected/subdir/vendor/a.txt",
+			"unaffected/testdata.txt/a.pb",
+			"unaffected/vendor.proto/a.pb",
+		},
+		paths,
+	)
+	_, err = exportReadBucket.Stat(ctx, "testdata/a.proto")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = exportReadBucket.Get(ctx, "b/ignored.proto")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	for path, expected := range map[string]string{
+		"line_endings.crlf":           "a\r\nb\r\nc\r\n",
+		"line_endings.auto":           "a\r\nb\r\n",
+		"binary.auto":                 "a\x00\nb\n",
+		"binary.bin":                  "a\nb\n",
+		"d/line_endings.crlf":         "a\nb\n",
+		"unaffected/line_endings.txt": "a\nb\n",
+	} {
+		data, err := storage.ReadPath(ctx, exportReadBucket, path)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(data), path)
+	}
+}
+
+func TestApplyDelta(t *testing.T) {
+	t.Parallel()
+	base := []byte("hello world")
+	delta := []byte{
+		// Base size and result size.
+		11, 18,
+		// Copy 5 bytes at offset 0.
+		0x80 | 0x10, 5,
+		// Insert ", there".
+		7, ',', ' ', 't', 'h', 'e', 'r', 'e',
+		// Copy 6 bytes at offset 5.
+		0x80 | 0x01 | 0x10, 5, 6,
+	}
+	result, err := applyDelta(base, delta)
+	require.NoError(t, err)
+	assert.Equal(t, "hello, there world", string(result))
+	_, err = applyDelta([]byte("hello"), delta)
+	assert.Error(t, err)
+}
+
+func TestObjectReader(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	container, err := app.NewContainerForOS()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	runCommand(ctx, t, container, "git", "-C", dir, "init")
+	runCommand(ctx, t, container, "git", "-C", dir, "config", "user.email", "tests@buf.build")
+	runCommand(ctx, t, container, "git", "-C", dir, "config", "user.name", "Buf go tests")
+	runCommand(ctx, t, container, "git", "-C", dir, "checkout", "-b", "main")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "b"), 0777))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.proto"), []byte("// commit 0"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b", "b.proto"), []byte("// commit 0"), 0600))
+	require.NoError(t, os.Symlink("a.proto", filepath.Join(dir, "link.proto")))
+	runCommand(ctx, t, container, "git", "-C", dir, "add", ".")
+	runCommand(ctx, t, container, "git", "-C", dir, "commit", "-m", "commit 0")
+	runCommand(ctx, t, container, "git", "-C", dir, "tag", "v0")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.proto"), []byte("// commit 1"), 0600))
+	runCommand(ctx, t, container, "git", "-C", dir, "commit", "-a", "-m", "commit 1")
+
+	objectReader, err := NewObjectReader(ctx, container, filepath.Join(dir, ".git"))
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, objectReader.Close()) })
+
+	readFile := func(name Name, path string) string {
+		commit, err := objectReader.ResolveCommit(ctx, name)
+		require.NoError(t, err)
+		blobs, err := objectReader.ListBlobs(ctx, commit)
+		require.NoError(t, err)
+		var paths []string
+		var data []byte
+		for _, blob := range blobs {
+			paths = append(paths, blob.Path)
+			if blob.Path == path {
+				data, err = objectReader.ReadBlob(ctx, blob.Hash)
+				require.NoError(t, err)
+				assert.Equal(t, blob.Size, int64(len(data)))
+			}
+		}
+		assert.Equal(t, []string{"a.proto", "b/b.proto"}, paths)
+		return string(data)
+	}
+	assert.Equal(t, "// commit 1", readFile(nil, "a.proto"))
+	assert.Equal(t, "// commit 1", readFile(NewBranchName("main"), "a.proto"))
+	assert.Equal(t, "// commit 0", readFile(NewTagName("v0"), "a.proto"))
+	assert.Equal(t, "// commit 0", readFile(NewRefName("HEAD~1"), "a.proto"))
+	assert.Equal(t, "// commit 0", readFile(NewRefNameWithBranch("HEAD~", "main"), "a.proto"))
+	assert.Equal(t, "// commit 0", readFile(NewRefName("main"), "b/b.proto"))
+
+	_, err = objectReader.ResolveCommit(ctx, NewRefName("nonexistent"))
+	assert.ErrorIs(t, err, ErrInvalidRef)
+	_, err = objectReader.ReadBlob(ctx, strings.Repeat("0", 40))
+	assert.Error(t, err)
+}
+
 func createGitDirs(
 	ctx context.Context,
 	t *testing.T,
@@ -0,0 +1,847 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+const (
+	// generationNumberInfinity is the generation number of commits that are not in
+	// the commit-graph. Commits that are not in the commit-graph are newer than
+	// the commits in it, as the commit-graph contains all ancestors of its commits.
+	generationNumberInfinity = math.MaxUint32
+	// maxSymbolicRefDepth is the maximum depth of symbolic refs, which matches the
+	// limit of git.
+	maxSymbolicRefDepth = 5
+	// maxTagDepth is the maximum depth of tags of tags.
+	maxTagDepth = 10
+)
+
+const (
+	mergeBaseParent1 mergeBaseFlags = 1 << iota
+	mergeBaseParent2
+	mergeBaseStale
+	mergeBaseResult
+)
+
+// errUnsupportedRepository is returned when a repository or a revision cannot be
+// read directly, in which case callers fall back to running git.
+var errUnsupportedRepository = errors.New("unsupported git repository")
+
+// history reads the commit history of a local git repository directly, without
+// running git.
+//
+// Commits are read from the commit-graph of the repository when there is one, and
+// from loose objects and packfiles otherwise. Repositories that use SHA-256 object
+// names or the reftable ref storage are not supported.
+type history struct {
+	gitDirPath     string
+	commonDirPath  string
+	objectStore    *objectStore
+	commitGraph    *commitGraph
+	shallowCommits map[objectID]struct{}
+
+	lock       sync.Mutex
+	commits    map[objectID]*historyCommit
+	packedRefs map[string]packedRef
+}
+
+type historyCommit struct {
+	id         objectID
+	parents    []objectID
+	generation uint32
+	time       int64
+}
+
+type packedRef struct {
+	id objectID
+	// peeledID is the commit an annotated tag points to, if known.
+	peeledID *objectID
+}
+
+type mergeBaseFlags uint8
+
+func newHistory(dir string) (_ *history, retErr error) {
+	gitDirPath, err := findGitDirPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	commonDirPath := gitDirPath
+	data, err := os.ReadFile(filepath.Join(gitDirPath, "commondir"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		// Worktrees share the objects and refs of the main repository.
+		commonDirPath = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDirPath) {
+			commonDirPath = filepath.Join(gitDirPath, commonDirPath)
+		}
+		commonDirPath = filepath.Clean(commonDirPath)
+	}
+	if err := checkRepositoryFormat(commonDirPath); err != nil {
+		return nil, err
+	}
+	objectsDirPath := filepath.Join(commonDirPath, "objects")
+	objectStore, err := newObjectStore(objectsDirPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if retErr != nil {
+			retErr = errors.Join(retErr, objectStore.Close())
+		}
+	}()
+	commitGraph, err := readCommitGraph(objectsDirPath)
+	if err != nil {
+		if errors.Is(err, errUnsupportedRepository) {
+			return nil, err
+		}
+		// Like git, ignore a commit-graph that cannot be read and read the commit
+		// objects instead.
+		commitGraph = nil
+	}
+	shallowCommits, err := readShallowCommits(commonDirPath)
+	if err != nil {
+		return nil, err
+	}
+	return &history{
+		gitDirPath:     gitDirPath,
+		commonDirPath:  commonDirPath,
+		objectStore:    objectStore,
+		commitGraph:    commitGraph,
+		shallowCommits: shallowCommits,
+		commits:        make(map[objectID]*historyCommit),
+	}, nil
+}
+
+// getMergeBase returns the hash of the best common ancestor of the commits the
+// revisions resolve to, as in git merge-base.
+//
+// Revisions are full commit hashes or the names of refs such as HEAD, main,
+// origin/main and refs/tags/v1.0.0, optionally followed by ~<n> and ^<n> suffixes
+// that select ancestors.
+func (h *history) getMergeBase(revision1 string, revision2 string) (string, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	id1, err := h.resolveCommit(revision1)
+	if err != nil {
+		return "", err
+	}
+	id2, err := h.resolveCommit(revision2)
+	if err != nil {
+		return "", err
+	}
+	mergeBases, err := h.getMergeBases(id1, id2)
+	if err != nil {
+		return "", err
+	}
+	if len(mergeBases) == 0 {
+		return "", fmt.Errorf("%s and %s have no common ancestor", revision1, revision2)
+	}
+	return mergeBases[0].id.String(), nil
+}
+
+// isAncestorRevision returns true if the commit that the ancestor revision resolves
+// to is an ancestor of the commit that the descendant revision resolves to, or is
+// the same commit, as in git merge-base --is-ancestor.
+func (h *history) isAncestorRevision(ancestor string, descendant string) (bool, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	ancestorID, err := h.resolveCommit(ancestor)
+	if err != nil {
+		return false, err
+	}
+	descendantID, err := h.resolveCommit(descendant)
+	if err != nil {
+		return false, err
+	}
+	return h.isAncestor(ancestorID, descendantID)
+}
+
+func (h *history) close() error {
+	return h.objectStore.Close()
+}
+
+// getMergeBases returns the best common ancestors of the commits, from newest to
+// oldest, using the same algorithm as git.
+//
+// The history is walked from both commits at once, newest commits first, painting
+// each commit with the sides it is reachable from. Commits that are reachable from
+// both sides are merge bases, and their ancestors are marked as stale as they are
+// common ancestors that are not the best. The walk stops once only stale commits
+// are left to walk.
+func (h *history) getMergeBases(id1 objectID, id2 objectID) ([]*historyCommit, error) {
+	commit1, err := h.getCommit(id1)
+	if err != nil {
+		return nil, err
+	}
+	if id1 == id2 {
+		return []*historyCommit{commit1}, nil
+	}
+	commit2, err := h.getCommit(id2)
+	if err != nil {
+		return nil, err
+	}
+	flags := map[objectID]mergeBaseFlags{
+		id1: mergeBaseParent1,
+		id2: mergeBaseParent2,
+	}
+	queue := &commitQueue{commit1, commit2}
+	heap.Init(queue)
+	var results []*historyCommit
+	for queue.hasNonStale(flags) {
+		commit := heap.Pop(queue).(*historyCommit)
+		paintFlags := flags[commit.id] & (mergeBaseParent1 | mergeBaseParent2 | mergeBaseStale)
+		if paintFlags == mergeBaseParent1|mergeBaseParent2 {
+			if flags[commit.id]&mergeBaseResult == 0 {
+				flags[commit.id] |= mergeBaseResult
+				results = append(results, commit)
+			}
+			paintFlags |= mergeBaseStale
+		}
+		for _, parentID := range commit.parents {
+			if flags[parentID]&paintFlags == paintFlags {
+				continue
+			}
+			parent, err := h.getCommit(parentID)
+			if err != nil {
+				return nil, err
+			}
+			flags[parentID] |= paintFlags
+			heap.Push(queue, parent)
+		}
+	}
+	var mergeBases []*historyCommit
+	for _, result := range results {
+		if flags[result.id]&mergeBaseStale == 0 {
+			mergeBases = append(mergeBases, result)
+		}
+	}
+	if len(mergeBases) < 2 {
+		return mergeBases, nil
+	}
+	// With criss-cross merges, a merge base can still be an ancestor of another
+	// merge base that was reached through a different path.
+	var bestMergeBases []*historyCommit
+	for i, mergeBase := range mergeBases {
+		redundant := false
+		for j, other := range mergeBases {
+			if i == j {
+				continue
+			}
+			isAncestor, err := h.isAncestor(mergeBase.id, other.id)
+			if err != nil {
+				return nil, err
+			}
+			if isAncestor {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			bestMergeBases = append(bestMergeBases, mergeBase)
+		}
+	}
+	sort.SliceStable(bestMergeBases, func(i int, j int) bool {
+		return bestMergeBases[i].time > bestMergeBases[j].time
+	})
+	return bestMergeBases, nil
+}
+
+// isAncestor walks the history from the descendant to find the ancestor.
+//
+// Commits with a generation number lower than or equal to that of the ancestor
+// cannot have the ancestor in their history, so the walk does not go past them.
+func (h *history) isAncestor(ancestorID objectID, descendantID objectID) (bool, error) {
+	if ancestorID == descendantID {
+		return true, nil
+	}
+	ancestor, err := h.getCommit(ancestorID)
+	if err != nil {
+		return false, err
+	}
+	visited := map[objectID]struct{}{
+		descendantID: {},
+	}
+	stack := []objectID{descendantID}
+	for len(stack) > 0 {
+		commit, err := h.getCommit(stack[len(stack)-1])
+		if err != nil {
+			return false, err
+		}
+		stack = stack[:len(stack)-1]
+		if ancestor.generation != generationNumberInfinity && commit.generation <= ancestor.generation {
+			continue
+		}
+		for _, parentID := range commit.parents {
+			if parentID == ancestorID {
+				return true, nil
+			}
+			if _, ok := visited[parentID]; ok {
+				continue
+			}
+			visited[parentID] = struct{}{}
+			stack = append(stack, parentID)
+		}
+	}
+	return false, nil
+}
+
+// getCommit returns the commit with the ID, from the commit-graph if it contains
+// the commit, and from the commit object otherwise.
+func (h *history) getCommit(id objectID) (*historyCommit, error) {
+	if commit, ok := h.commits[id]; ok {
+		return commit, nil
+	}
+	commit, err := h.getCommitFromCommitGraph(id)
+	if err != nil {
+		return nil, err
+	}
+	if commit == nil {
+		objectType, data, err := h.objectStore.readObject(id)
+		if err != nil {
+			return nil, err
+		}
+		if objectType != objectTypeCommit {
+			return nil, fmt.Errorf("object %s is a %v, not a commit", id, objectType)
+		}
+		parents, commitTime, err := parseCommitObject(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse commit %s: %w", id, err)
+		}
+		commit = &historyCommit{
+			id:         id,
+			parents:    parents,
+			generation: generationNumberInfinity,
+			time:       commitTime,
+		}
+	}
+	if _, ok := h.shallowCommits[id]; ok {
+		// The parents of the commits at the boundary of a shallow clone are not
+		// in the repository, and git treats these commits as having no parents.
+		commit.parents = nil
+	}
+	h.commits[id] = commit
+	return commit, nil
+}
+
+// getCommitFromCommitGraph returns the commit with the ID from the commit-graph.
+//
+// Returns nil if the commit-graph does not contain the commit.
+func (h *history) getCommitFromCommitGraph(id objectID) (*historyCommit, error) {
+	if h.commitGraph == nil {
+		return nil, nil
+	}
+	position, ok := h.commitGraph.find(id)
+	if !ok {
+		return nil, nil
+	}
+	parentPositions, generation, commitTime, err := h.commitGraph.commit(position)
+	if err != nil {
+		return nil, err
+	}
+	parents := make([]objectID, len(parentPositions))
+	for i, parentPosition := range parentPositions {
+		parents[i], err = h.commitGraph.objectID(parentPosition)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if generation == 0 {
+		// The commit-graph was written without generation numbers.
+		generation = generationNumberInfinity
+	}
+	return &historyCommit{
+		id:         id,
+		parents:    parents,
+		generation: generation,
+		time:       commitTime,
+	}, nil
+}
+
+// resolveCommit resolves the revision to a commit.
+func (h *history) resolveCommit(revision string) (objectID, error) {
+	name := revision
+	var suffix string
+	if index := strings.IndexAny(revision, "~^"); index >= 0 {
+		name = revision[:index]
+		suffix = revision[index:]
+	}
+	id, err := h.resolveName(name)
+	if err != nil {
+		return id, fmt.Errorf("could not find revision %s: %w", revision, err)
+	}
+	id, err = h.peelToCommit(id)
+	if err != nil {
+		return id, err
+	}
+	for suffix != "" {
+		operator := suffix[0]
+		suffix = suffix[1:]
+		digits := strings.TrimLeft(suffix, "0123456789")
+		n := 1
+		if numberString := suffix[:len(suffix)-len(digits)]; numberString != "" {
+			n, err = strconv.Atoi(numberString)
+			if err != nil {
+				return id, fmt.Errorf("invalid revision %s: %w", revision, errUnsupportedRepository)
+			}
+		}
+		suffix = digits
+		commit, err := h.getCommit(id)
+		if err != nil {
+			return id, err
+		}
+		switch operator {
+		case '~':
+			for range n {
+				if len(commit.parents) == 0 {
+					return id, fmt.Errorf("invalid revision %s: %s has no parent", revision, commit.id)
+				}
+				commit, err = h.getCommit(commit.parents[0])
+				if err != nil {
+					return id, err
+				}
+			}
+			id = commit.id
+		case '^':
+			if n == 0 {
+				continue
+			}
+			if n > len(commit.parents) {
+				return id, fmt.Errorf("invalid revision %s: %s has no parent %d", revision, commit.id, n)
+			}
+			id = commit.parents[n-1]
+		default:
+			// Revisions such as <rev>^{tree} and <rev>@{upstream}.
+			return id, fmt.Errorf("unsupported revision %s: %w", revision, errUnsupportedRepository)
+		}
+	}
+	return id, nil
+}
+
+// resolveName resolves a full hash or the name of a ref, using the same rules as
+// git for finding the ref that a short name refers to.
+func (h *history) resolveName(name string) (objectID, error) {
+	if id, ok := parseObjectID(name); ok {
+		return id, nil
+	}
+	if name == "@" {
+		name = "HEAD"
+	}
+	if !isSupportedRefName(name) {
+		return objectID{}, errUnsupportedRepository
+	}
+	var candidates []string
+	if strings.HasPrefix(name, "refs/") || isPseudoRefName(name) {
+		candidates = append(candidates, name)
+	}
+	candidates = append(
+		candidates,
+		"refs/"+name,
+		tagsPrefix+name,
+		headsPrefix+name,
+		"refs/remotes/"+name,
+		"refs/remotes/"+name+"/HEAD",
+	)
+	for _, candidate := range candidates {
+		id, ok, err := h.readRef(candidate)
+		if err != nil {
+			return id, err
+		}
+		if ok {
+			return id, nil
+		}
+	}
+	if len(name) >= 4 && strings.Trim(name, "0123456789abcdefABCDEF") == "" {
+		// Abbreviated hashes are left to git.
+		return objectID{}, errUnsupportedRepository
+	}
+	return objectID{}, ErrInvalidRef
+}
+
+// readRef reads the ref with the full name, following symbolic refs.
+func (h *history) readRef(name string) (objectID, bool, error) {
+	for range maxSymbolicRefDepth {
+		refFilePath := filepath.Join(h.commonDirPath, filepath.FromSlash(name))
+		if isPerWorktreeRefName(name) {
+			refFilePath = filepath.Join(h.gitDirPath, filepath.FromSlash(name))
+		}
+		data, err := readRefFile(refFilePath)
+		if err != nil {
+			return objectID{}, false, err
+		}
+		if data == nil {
+			packedRefs, err := h.getPackedRefs()
+			if err != nil {
+				return objectID{}, false, err
+			}
+			packedRef, ok := packedRefs[name]
+			if !ok {
+				return objectID{}, false, nil
+			}
+			if packedRef.peeledID != nil {
+				return *packedRef.peeledID, true, nil
+			}
+			return packedRef.id, true, nil
+		}
+		// The first line contains the value. FETCH_HEAD has a line per fetched ref,
+		// with the hash followed by a description of the ref.
+		line, _, _ := strings.Cut(string(data), "\n")
+		line = strings.TrimSpace(line)
+		if target, ok := strings.CutPrefix(line, "ref:"); ok {
+			name = strings.TrimSpace(target)
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			return objectID{}, false, fmt.Errorf("invalid ref %s", name)
+		}
+		id, ok := parseObjectID(fields[0])
+		if !ok {
+			return objectID{}, false, fmt.Errorf("invalid ref %s: %w", name, errUnsupportedRepository)
+		}
+		return id, true, nil
+	}
+	return objectID{}, false, fmt.Errorf("symbolic ref %s is too deep", name)
+}
+
+// getPackedRefs returns the refs in the packed-refs file, reading it on first use.
+func (h *history) getPackedRefs() (map[string]packedRef, error) {
+	if h.packedRefs != nil {
+		return h.packedRefs, nil
+	}
+	packedRefs := make(map[string]packedRef)
+	data, err := os.ReadFile(filepath.Join(h.commonDirPath, "packed-refs"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	var previousName string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if peeled, ok := strings.CutPrefix(line, "^"); ok {
+			// The commit that the annotated tag on the line before points to.
+			peeledID, ok := parseObjectID(peeled)
+			if !ok || previousName == "" {
+				return nil, fmt.Errorf("invalid packed-refs line %q", line)
+			}
+			packedRef := packedRefs[previousName]
+			packedRef.peeledID = &peeledID
+			packedRefs[previousName] = packedRef
+			continue
+		}
+		hash, name, ok := strings.Cut(line, " ")
+		id, validID := parseObjectID(hash)
+		if !ok || !validID {
+			return nil, fmt.Errorf("invalid packed-refs line %q: %w", line, errUnsupportedRepository)
+		}
+		packedRefs[name] = packedRef{id: id}
+		previousName = name
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	h.packedRefs = packedRefs
+	return packedRefs, nil
+}
+
+// peelToCommit returns the commit that the object refers to, following annotated tags.
+func (h *history) peelToCommit(id objectID) (objectID, error) {
+	for range maxTagDepth {
+		if h.commitGraph != nil {
+			if _, ok := h.commitGraph.find(id); ok {
+				return id, nil
+			}
+		}
+		objectType, data, err := h.objectStore.readObject(id)
+		if err != nil {
+			return id, err
+		}
+		switch objectType {
+		case objectTypeCommit:
+			return id, nil
+		case objectTypeTag:
+			id, err = parseTagObject(data)
+			if err != nil {
+				return id, err
+			}
+		default:
+			return id, fmt.Errorf("object %s is a %v, not a commit", id, objectType)
+		}
+	}
+	return id, fmt.Errorf("tag %s is too deep", id)
+}
+
+// commitQueue is a priority queue of commits, ordered newest first by generation
+// number and then by commit time.
+type commitQueue []*historyCommit
+
+func (q commitQueue) Len() int {
+	return len(q)
+}
+
+func (q commitQueue) Less(i int, j int) bool {
+	if q[i].generation != q[j].generation {
+		return q[i].generation > q[j].generation
+	}
+	return q[i].time > q[j].time
+}
+
+func (q commitQueue) Swap(i int, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *commitQueue) Push(x any) {
+	*q = append(*q, x.(*historyCommit))
+}
+
+func (q *commitQueue) Pop() any {
+	old := *q
+	commit := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return commit
+}
+
+func (q commitQueue) hasNonStale(flags map[objectID]mergeBaseFlags) bool {
+	for _, commit := range q {
+		if flags[commit.id]&mergeBaseStale == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCommitObject parses the parents and the committer time of a commit object.
+func parseCommitObject(data []byte) ([]objectID, int64, error) {
+	var parents []objectID
+	var commitTime int64
+	for len(data) > 0 {
+		var line []byte
+		line, data, _ = bytes.Cut(data, []byte{'\n'})
+		if len(line) == 0 {
+			// The end of the headers.
+			break
+		}
+		key, value, _ := bytes.Cut(line, []byte{' '})
+		switch string(key) {
+		case "parent":
+			parent, ok := parseObjectID(string(value))
+			if !ok {
+				return nil, 0, fmt.Errorf("invalid parent %q", value)
+			}
+			parents = append(parents, parent)
+		case "committer":
+			// Name <email> time timezone
+			fields := strings.Fields(string(value[bytes.LastIndexByte(value, '>')+1:]))
+			if len(fields) != 2 {
+				return nil, 0, fmt.Errorf("invalid committer %q", value)
+			}
+			var err error
+			commitTime, err = strconv.ParseInt(fields[0], 10, 64)
+			if err != nil {
+				return nil, 0, fmt.Errorf("invalid committer %q", value)
+			}
+		}
+	}
+	return parents, commitTime, nil
+}
+
+// parseTagObject parses the object that a tag object points to.
+func parseTagObject(data []byte) (objectID, error) {
+	line, _, _ := bytes.Cut(data, []byte{'\n'})
+	value, ok := bytes.CutPrefix(line, []byte("object "))
+	if !ok {
+		return objectID{}, fmt.Errorf("invalid tag object %q", line)
+	}
+	id, ok := parseObjectID(string(value))
+	if !ok {
+		return id, fmt.Errorf("invalid tag object %q", line)
+	}
+	return id, nil
+}
+
+// findGitDirPath finds the git directory of the repository that contains dir.
+func findGitDirPath(dir string) (string, error) {
+	dirPath, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		dotGitPath := filepath.Join(dirPath, ".git")
+		fileInfo, err := os.Stat(dotGitPath)
+		if err == nil {
+			if fileInfo.IsDir() {
+				return dotGitPath, nil
+			}
+			// Worktrees and submodules have a .git file that points to the git directory.
+			data, err := os.ReadFile(dotGitPath)
+			if err != nil {
+				return "", err
+			}
+			gitDirPath, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+			if !ok {
+				return "", fmt.Errorf("invalid .git file %s", dotGitPath)
+			}
+			gitDirPath = strings.TrimSpace(gitDirPath)
+			if !filepath.IsAbs(gitDirPath) {
+				gitDirPath = filepath.Join(dirPath, gitDirPath)
+			}
+			return filepath.Clean(gitDirPath), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		if isGitDir(dirPath) {
+			return dirPath, nil
+		}
+		parentDirPath := filepath.Dir(dirPath)
+		if parentDirPath == dirPath {
+			return "", fmt.Errorf("%s is not within a git repository: %w", dir, ErrInvalidGitCheckout)
+		}
+		dirPath = parentDirPath
+	}
+}
+
+// isGitDir returns true if the directory is a git directory, such as a bare
+// repository or the .git directory of a checkout.
+func isGitDir(dirPath string) bool {
+	if fileInfo, err := os.Stat(filepath.Join(dirPath, "HEAD")); err != nil || fileInfo.IsDir() {
+		return false
+	}
+	for _, name := range []string{"objects", "commondir"} {
+		if _, err := os.Stat(filepath.Join(dirPath, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRepositoryFormat returns an error if the repository uses extensions that
+// change how objects or refs are stored.
+func checkRepositoryFormat(commonDirPath string) error {
+	data, err := os.ReadFile(filepath.Join(commonDirPath, "config"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.ToLower(strings.TrimSpace(value))
+		switch {
+		case key == "objectformat" && value != "sha1":
+			return fmt.Errorf("object format %s: %w", value, errUnsupportedRepository)
+		case key == "refstorage" && value != "files":
+			return fmt.Errorf("ref storage %s: %w", value, errUnsupportedRepository)
+		}
+	}
+	return nil
+}
+
+// readShallowCommits reads the commits at the boundary of a shallow clone.
+func readShallowCommits(commonDirPath string) (map[objectID]struct{}, error) {
+	data, err := os.ReadFile(filepath.Join(commonDirPath, "shallow"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	shallowCommits := make(map[objectID]struct{})
+	for _, line := range strings.Fields(string(data)) {
+		id, ok := parseObjectID(line)
+		if !ok {
+			return nil, fmt.Errorf("invalid shallow commit %q", line)
+		}
+		shallowCommits[id] = struct{}{}
+	}
+	return shallowCommits, nil
+}
+
+// readRefFile reads the file of a loose ref.
+//
+// Returns nil if the file does not exist.
+func readRefFile(refFilePath string) ([]byte, error) {
+	fileInfo, err := os.Stat(refFilePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if fileInfo.IsDir() {
+		// For example, refs/remotes/origin when resolving origin.
+		return nil, nil
+	}
+	return os.ReadFile(refFilePath)
+}
+
+// isSupportedRefName returns true if the name is a valid ref name that can be
+// read directly.
+func isSupportedRefName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") ||
+		strings.HasSuffix(name, ".lock") || strings.Contains(name, "..") ||
+		strings.Contains(name, "//") || strings.Contains(name, "@{") {
+		return false
+	}
+	for _, c := range name {
+		if c < 0x20 || c == 0x7f || strings.ContainsRune(` ~^:?*[\`, c) {
+			return false
+		}
+	}
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") {
+			return false
+		}
+	}
+	return true
+}
+
+// isPseudoRefName returns true for names such as HEAD and FETCH_HEAD.
+func isPseudoRefName(name string) bool {
+	return strings.Trim(name, "ABCDEFGHIJKLMNOPQRSTUVWXYZ_") == ""
+}
+
+// isPerWorktreeRefName returns true if the ref is stored in the git directory of a
+// worktree rather than in the common directory shared by all worktrees.
+func isPerWorktreeRefName(name string) bool {
+	return !strings.HasPrefix(name, "refs/") ||
+		strings.HasPrefix(name, "refs/bisect/") ||
+		strings.HasPrefix(name, "refs/worktree/") ||
+		strings.HasPrefix(name, "refs/rewritten/")
+}
@@ -0,0 +1,277 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bufbuild/buf/private/pkg/app"
+	"github.com/bufbuild/buf/private/pkg/execext"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+)
+
+const (
+	blobObjectType   = "blob"
+	symlinkFileMode  = "120000"
+	missingObjectTag = "missing"
+)
+
+type objectReader struct {
+	dir     string
+	environ []string
+
+	lock   sync.Mutex
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr *bytes.Buffer
+	doneC  chan error
+	closed bool
+	// exited and exitErr are set once the result of doneC is received.
+	exited  bool
+	exitErr error
+}
+
+func newObjectReader(
+	ctx context.Context,
+	envContainer app.EnvContainer,
+	dir string,
+) (*objectReader, error) {
+	environ := app.Environ(envContainer)
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	stderr := bytes.NewBuffer(nil)
+	process, err := execext.Start(
+		ctx,
+		gitCommand,
+		execext.WithArgs("cat-file", "--batch"),
+		execext.WithStdin(stdinReader),
+		execext.WithStdout(stdoutWriter),
+		execext.WithStderr(stderr),
+		execext.WithDir(dir),
+		execext.WithEnv(environ),
+	)
+	if err != nil {
+		return nil, err
+	}
+	doneC := make(chan error, 1)
+	go func() {
+		err := process.Wait()
+		// Unblock any pending reads if the process exits early.
+		_ = stdoutWriter.CloseWithError(io.ErrUnexpectedEOF)
+		_ = stdinReader.Close()
+		doneC <- err
+	}()
+	return &objectReader{
+		dir:     dir,
+		environ: environ,
+		stdin:   stdinWriter,
+		stdout:  bufio.NewReader(stdoutReader),
+		stderr:  stderr,
+		doneC:   doneC,
+	}, nil
+}
+
+func (o *objectReader) ResolveCommit(ctx context.Context, name Name) (string, error) {
+	revision := getRevisionForName(name)
+	if strings.HasPrefix(revision, "-") {
+		return "", fmt.Errorf("invalid git revision: %q", revision)
+	}
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	if err := execext.Run(
+		ctx,
+		gitCommand,
+		execext.WithArgs("rev-parse", "--verify", "--quiet", revision+"^{commit}"),
+		execext.WithStdout(stdout),
+		execext.WithStderr(stderr),
+		execext.WithDir(o.dir),
+		execext.WithEnv(o.environ),
+	); err != nil {
+		return "", fmt.Errorf("could not find commit for %s in %s: %w", revision, o.dir, ErrInvalidRef)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func (o *objectReader) ListBlobs(ctx context.Context, commit string) ([]Blob, error) {
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	if err := execext.Run(
+		ctx,
+		gitCommand,
+		execext.WithArgs("ls-tree", "-r", "-z", "--long", "--full-tree", commit),
+		execext.WithStdout(stdout),
+		execext.WithStderr(stderr),
+		execext.WithDir(o.dir),
+		execext.WithEnv(o.environ),
+	); err != nil {
+		return nil, fmt.Errorf("failed to list tree of %s: %w: %s", commit, err, strings.TrimSpace(stderr.String()))
+	}
+	var blobs []Blob
+	for _, entry := range strings.Split(stdout.String(), "\x00") {
+		if entry == "" {
+			continue
+		}
+		blob, ok, err := parseTreeEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			blobs = append(blobs, blob)
+		}
+	}
+	return blobs, nil
+}
+
+func (o *objectReader) ReadBlob(ctx context.Context, hash string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.ContainsAny(hash, " \n") {
+		return nil, fmt.Errorf("invalid git object hash: %q", hash)
+	}
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.closed {
+		return nil, errors.New("git object reader is closed")
+	}
+	if _, err := io.WriteString(o.stdin, hash+"\n"); err != nil {
+		return nil, o.newProcessError(err)
+	}
+	// The header is "<hash> <type> <size>", or "<hash> missing" if the object does not exist.
+	header, err := o.stdout.ReadString('\n')
+	if err != nil {
+		return nil, o.newProcessError(err)
+	}
+	fields := strings.Fields(header)
+	if len(fields) == 2 && fields[1] == missingObjectTag {
+		return nil, fmt.Errorf("git object %s not found", hash)
+	}
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected git cat-file header: %q", header)
+	}
+	if fields[1] != blobObjectType {
+		return nil, fmt.Errorf("git object %s is a %s, expected a %s", hash, fields[1], blobObjectType)
+	}
+	size, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected git cat-file header: %q", header)
+	}
+	// The contents are followed by a newline.
+	data := make([]byte, size+1)
+	if _, err := io.ReadFull(o.stdout, data); err != nil {
+		return nil, o.newProcessError(err)
+	}
+	return data[:size], nil
+}
+
+func (o *objectReader) Close() error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.closed {
+		return nil
+	}
+	o.closed = true
+	if err := o.stdin.Close(); err != nil {
+		return err
+	}
+	return o.wait()
+}
+
+// newProcessError returns an error for a failed read or write to the process.
+//
+// Reads and writes only fail once the process has exited and the pipes have
+// been closed, so stderr is complete at this point.
+//
+// Must be called with the lock held.
+func (o *objectReader) newProcessError(err error) error {
+	if exitErr := o.wait(); exitErr != nil {
+		err = exitErr
+	}
+	return fmt.Errorf("failed to read git objects in %s: %w: %s", o.dir, err, strings.TrimSpace(o.stderr.String()))
+}
+
+// wait waits for the process to exit and returns the error it exited with.
+//
+// Must be called with the lock held.
+func (o *objectReader) wait() error {
+	if !o.exited {
+		o.exitErr = <-o.doneC
+		o.exited = true
+	}
+	return o.exitErr
+}
+
+// getRevisionForName returns the revision that refers to the commit of the Name
+// within the repository.
+//
+// This mirrors how a clone resolves the Name: the clone branch is fetched, and
+// the checkout ref is then resolved relative to it.
+func getRevisionForName(name Name) string {
+	if name == nil {
+		return "HEAD"
+	}
+	checkout, cloneBranch := name.checkout(), name.cloneBranch()
+	switch {
+	case checkout != "" && cloneBranch != "":
+		// HEAD refers to the clone branch after the fetch, so relative refs
+		// such as HEAD~1 are relative to the clone branch.
+		if rest, ok := strings.CutPrefix(checkout, "HEAD"); ok {
+			return cloneBranch + rest
+		}
+		return checkout
+	case cloneBranch != "":
+		return cloneBranch
+	case checkout != "":
+		return checkout
+	default:
+		return "HEAD"
+	}
+}
+
+// parseTreeEntry parses an entry of git ls-tree --long, which is of the form
+// "<mode> <type> <hash> <size>\t<path>".
+//
+// Returns false if the entry is not a regular file, for example if it is a
+// symbolic link or submodule.
+func parseTreeEntry(entry string) (Blob, bool, error) {
+	info, path, ok := strings.Cut(entry, "\t")
+	if !ok {
+		return Blob{}, false, fmt.Errorf("unexpected git ls-tree entry: %q", entry)
+	}
+	fields := strings.Fields(info)
+	if len(fields) != 4 {
+		return Blob{}, false, fmt.Errorf("unexpected git ls-tree entry: %q", entry)
+	}
+	if fields[0] == symlinkFileMode || fields[1] != blobObjectType {
+		return Blob{}, false, nil
+	}
+	size, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return Blob{}, false, fmt.Errorf("unexpected git ls-tree entry: %q", entry)
+	}
+	return Blob{
+		Path: normalpath.Normalize(path),
+		Hash: fields[2],
+		Size: size,
+	}, true, nil
+}
@@ -0,0 +1,639 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	objectIDLength = 20
+
+	objectTypeCommit   objectType = 1
+	objectTypeTree     objectType = 2
+	objectTypeBlob     objectType = 3
+	objectTypeTag      objectType = 4
+	objectTypeOfsDelta objectType = 6
+	objectTypeRefDelta objectType = 7
+
+	// maxAlternatesDepth is the maximum depth of alternates of alternates, which
+	// matches the limit of git.
+	maxAlternatesDepth = 5
+	// maxDeltaChainDepth is the maximum length of a chain of deltas, which guards
+	// against cycles in corrupt packfiles.
+	maxDeltaChainDepth = 10000
+	// maxPackCacheSize is the total size of the delta bases that are cached per
+	// packfile. The cache is cleared once it is exceeded.
+	maxPackCacheSize = 32 << 20
+)
+
+var (
+	packIndexMagic = []byte{0xff, 't', 'O', 'c'}
+	packMagic      = []byte{'P', 'A', 'C', 'K'}
+)
+
+// objectID is the SHA-1 hash of an object.
+type objectID [objectIDLength]byte
+
+// parseObjectID parses the full hex-encoded hash of an object.
+func parseObjectID(value string) (objectID, bool) {
+	var id objectID
+	if len(value) != 2*objectIDLength {
+		return id, false
+	}
+	if _, err := hex.Decode(id[:], []byte(value)); err != nil {
+		return id, false
+	}
+	return id, true
+}
+
+func (i objectID) String() string {
+	return hex.EncodeToString(i[:])
+}
+
+// objectType is the type of an object, as encoded in packfiles.
+type objectType int
+
+func (t objectType) String() string {
+	switch t {
+	case objectTypeCommit:
+		return "commit"
+	case objectTypeTree:
+		return "tree"
+	case objectTypeBlob:
+		return "blob"
+	case objectTypeTag:
+		return "tag"
+	case objectTypeOfsDelta:
+		return "ofs-delta"
+	case objectTypeRefDelta:
+		return "ref-delta"
+	default:
+		return strconv.Itoa(int(t))
+	}
+}
+
+func parseObjectType(value string) (objectType, error) {
+	switch value {
+	case "commit":
+		return objectTypeCommit, nil
+	case "tree":
+		return objectTypeTree, nil
+	case "blob":
+		return objectTypeBlob, nil
+	case "tag":
+		return objectTypeTag, nil
+	default:
+		return 0, fmt.Errorf("unknown object type %q", value)
+	}
+}
+
+// objectStore reads objects from the object database of a repository, and from
+// the object databases of its alternates.
+type objectStore struct {
+	objectsDirPaths []string
+	packFiles       []*packFile
+}
+
+func newObjectStore(objectsDirPath string) (_ *objectStore, retErr error) {
+	objectsDirPaths, err := getObjectsDirPathsWithAlternates(objectsDirPath, 0)
+	if err != nil {
+		return nil, err
+	}
+	objectStore := &objectStore{
+		objectsDirPaths: objectsDirPaths,
+	}
+	defer func() {
+		if retErr != nil {
+			retErr = errors.Join(retErr, objectStore.Close())
+		}
+	}()
+	for _, objectsDirPath := range objectsDirPaths {
+		packIndexFilePaths, err := filepath.Glob(filepath.Join(objectsDirPath, "pack", "*.idx"))
+		if err != nil {
+			return nil, err
+		}
+		// Newer packfiles are more likely to contain the objects we look for.
+		sort.Sort(sort.Reverse(sort.StringSlice(packIndexFilePaths)))
+		for _, packIndexFilePath := range packIndexFilePaths {
+			packFile, err := openPackFile(objectStore, strings.TrimSuffix(packIndexFilePath, ".idx"))
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					// A packfile that is being written or removed concurrently.
+					continue
+				}
+				return nil, err
+			}
+			objectStore.packFiles = append(objectStore.packFiles, packFile)
+		}
+	}
+	return objectStore, nil
+}
+
+// readObject reads the object with the ID, resolving deltas.
+func (s *objectStore) readObject(id objectID) (objectType, []byte, error) {
+	return s.readObjectWithDepth(id, 0)
+}
+
+func (s *objectStore) Close() error {
+	var errs []error
+	for _, packFile := range s.packFiles {
+		errs = append(errs, packFile.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (s *objectStore) readObjectWithDepth(id objectID, depth int) (objectType, []byte, error) {
+	for _, packFile := range s.packFiles {
+		if offset, ok := packFile.index.find(id); ok {
+			return packFile.readObjectAt(offset, depth)
+		}
+	}
+	for _, objectsDirPath := range s.objectsDirPaths {
+		hexID := id.String()
+		data, err := os.ReadFile(filepath.Join(objectsDirPath, hexID[:2], hexID[2:]))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return 0, nil, err
+		}
+		objectType, data, err := parseLooseObject(data)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to read object %s: %w", id, err)
+		}
+		return objectType, data, nil
+	}
+	return 0, nil, fmt.Errorf("object %s not found", id)
+}
+
+// packFile is a packfile and its index.
+type packFile struct {
+	objectStore *objectStore
+	file        *os.File
+	size        int64
+	index       *packIndex
+	// cache contains the delta bases that were read, by offset.
+	cache     map[int64]*packObject
+	cacheSize int
+}
+
+type packObject struct {
+	objectType objectType
+	data       []byte
+}
+
+// openPackFile opens the packfile at path.pack with the index at path.idx.
+func openPackFile(objectStore *objectStore, path string) (_ *packFile, retErr error) {
+	indexData, err := os.ReadFile(path + ".idx")
+	if err != nil {
+		return nil, err
+	}
+	index, err := parsePackIndex(indexData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pack index %s: %w", path+".idx", err)
+	}
+	file, err := os.Open(path + ".pack")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if retErr != nil {
+			retErr = errors.Join(retErr, file.Close())
+		}
+	}()
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return nil, fmt.Errorf("failed to read packfile %s: %w", path+".pack", err)
+	}
+	if !bytes.Equal(header[:4], packMagic) {
+		return nil, fmt.Errorf("invalid packfile %s", path+".pack")
+	}
+	if version := binary.BigEndian.Uint32(header[4:8]); version != 2 && version != 3 {
+		return nil, fmt.Errorf("unsupported packfile version %d for %s: %w", version, path+".pack", errUnsupportedRepository)
+	}
+	return &packFile{
+		objectStore: objectStore,
+		file:        file,
+		size:        fileInfo.Size(),
+		index:       index,
+		cache:       make(map[int64]*packObject),
+	}, nil
+}
+
+func (p *packFile) Close() error {
+	return p.file.Close()
+}
+
+// readObjectAt reads the object at the offset within the packfile, resolving deltas.
+func (p *packFile) readObjectAt(offset int64, depth int) (objectType, []byte, error) {
+	if depth > maxDeltaChainDepth {
+		return 0, nil, fmt.Errorf("delta chain in %s is too deep", p.file.Name())
+	}
+	if offset < 12 || offset >= p.size {
+		return 0, nil, fmt.Errorf("invalid object offset %d in %s", offset, p.file.Name())
+	}
+	reader := bufio.NewReader(io.NewSectionReader(p.file, offset, p.size-offset))
+	objectType, size, err := readPackObjectHeader(reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read object at offset %d in %s: %w", offset, p.file.Name(), err)
+	}
+	switch objectType {
+	case objectTypeCommit, objectTypeTree, objectTypeBlob, objectTypeTag:
+		data, err := inflate(reader, size)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to read object at offset %d in %s: %w", offset, p.file.Name(), err)
+		}
+		return objectType, data, nil
+	case objectTypeOfsDelta:
+		relativeBaseOffset, err := readOfsDeltaOffset(reader)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to read object at offset %d in %s: %w", offset, p.file.Name(), err)
+		}
+		baseOffset := offset - relativeBaseOffset
+		delta, err := inflate(reader, size)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to read object at offset %d in %s: %w", offset, p.file.Name(), err)
+		}
+		base, err := p.readBaseAt(baseOffset, depth+1)
+		if err != nil {
+			return 0, nil, err
+		}
+		data, err := applyDelta(base.data, delta)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to read object at offset %d in %s: %w", offset, p.file.Name(), err)
+		}
+		return base.objectType, data, nil
+	case objectTypeRefDelta:
+		var baseID objectID
+		if _, err := io.ReadFull(reader, baseID[:]); err != nil {
+			return 0, nil, fmt.Errorf("failed to read object at offset %d in %s: %w", offset, p.file.Name(), err)
+		}
+		delta, err := inflate(reader, size)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to read object at offset %d in %s: %w", offset, p.file.Name(), err)
+		}
+		baseObjectType, baseData, err := p.objectStore.readObjectWithDepth(baseID, depth+1)
+		if err != nil {
+			return 0, nil, err
+		}
+		data, err := applyDelta(baseData, delta)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to read object at offset %d in %s: %w", offset, p.file.Name(), err)
+		}
+		return baseObjectType, data, nil
+	default:
+		return 0, nil, fmt.Errorf("invalid object type %v at offset %d in %s", objectType, offset, p.file.Name())
+	}
+}
+
+// readBaseAt reads the delta base at the offset, caching it as delta bases are
+// frequently shared by many objects.
+func (p *packFile) readBaseAt(offset int64, depth int) (*packObject, error) {
+	if base, ok := p.cache[offset]; ok {
+		return base, nil
+	}
+	objectType, data, err := p.readObjectAt(offset, depth)
+	if err != nil {
+		return nil, err
+	}
+	base := &packObject{
+		objectType: objectType,
+		data:       data,
+	}
+	if p.cacheSize+len(data) > maxPackCacheSize {
+		p.cache = make(map[int64]*packObject)
+		p.cacheSize = 0
+	}
+	p.cache[offset] = base
+	p.cacheSize += len(data)
+	return base, nil
+}
+
+// packIndex is a version 2 pack index.
+type packIndex struct {
+	fanout       []byte
+	objectIDs    []byte
+	offsets      []byte
+	largeOffsets []byte
+	count        int
+}
+
+func parsePackIndex(data []byte) (*packIndex, error) {
+	if len(data) < 8 || !bytes.Equal(data[:4], packIndexMagic) {
+		// Version 1 pack indexes have no header, and have not been written by
+		// git by default since 2008.
+		return nil, fmt.Errorf("unsupported pack index version 1: %w", errUnsupportedRepository)
+	}
+	if version := binary.BigEndian.Uint32(data[4:8]); version != 2 {
+		return nil, fmt.Errorf("unsupported pack index version %d: %w", version, errUnsupportedRepository)
+	}
+	data = data[8:]
+	if len(data) < 256*4 {
+		return nil, errors.New("truncated pack index")
+	}
+	fanout := data[:256*4]
+	count := int(binary.BigEndian.Uint32(fanout[255*4:]))
+	data = data[256*4:]
+	// Object IDs, then CRC32s, then offsets, then large offsets, then the checksums
+	// of the packfile and the pack index.
+	if len(data) < count*(objectIDLength+4+4)+2*objectIDLength {
+		return nil, errors.New("truncated pack index")
+	}
+	objectIDs := data[:count*objectIDLength]
+	data = data[count*(objectIDLength+4):]
+	offsets := data[:count*4]
+	largeOffsets := data[count*4 : len(data)-2*objectIDLength]
+	return &packIndex{
+		fanout:       fanout,
+		objectIDs:    objectIDs,
+		offsets:      offsets,
+		largeOffsets: largeOffsets,
+		count:        count,
+	}, nil
+}
+
+// find returns the offset of the object with the ID within the packfile.
+func (i *packIndex) find(id objectID) (int64, bool) {
+	position, ok := findInFanout(i.fanout, i.objectIDs, id)
+	if !ok {
+		return 0, false
+	}
+	offset := binary.BigEndian.Uint32(i.offsets[position*4:])
+	if offset&0x80000000 == 0 {
+		return int64(offset), true
+	}
+	largeOffsetIndex := int(offset & 0x7fffffff)
+	if (largeOffsetIndex+1)*8 > len(i.largeOffsets) {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(i.largeOffsets[largeOffsetIndex*8:])), true
+}
+
+// findInFanout finds the position of the ID within the sorted object IDs, using
+// the fanout table that contains the number of object IDs with a first byte less
+// than or equal to each byte value. This layout is shared by pack indexes and
+// commit-graphs.
+func findInFanout(fanout []byte, objectIDs []byte, id objectID) (int, bool) {
+	var low int
+	if id[0] > 0 {
+		low = int(binary.BigEndian.Uint32(fanout[(int(id[0])-1)*4:]))
+	}
+	high := int(binary.BigEndian.Uint32(fanout[int(id[0])*4:]))
+	if high*objectIDLength > len(objectIDs) || low > high {
+		return 0, false
+	}
+	position := low + sort.Search(high-low, func(j int) bool {
+		start := (low + j) * objectIDLength
+		return bytes.Compare(objectIDs[start:start+objectIDLength], id[:]) >= 0
+	})
+	if position < high && bytes.Equal(objectIDs[position*objectIDLength:(position+1)*objectIDLength], id[:]) {
+		return position, true
+	}
+	return 0, false
+}
+
+// readPackObjectHeader reads the type and the inflated size of an object within
+// a packfile.
+func readPackObjectHeader(reader io.ByteReader) (objectType, int, error) {
+	c, err := reader.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	objectType := objectType((c >> 4) & 0x7)
+	size := uint64(c & 0x0f)
+	shift := uint(4)
+	for c&0x80 != 0 {
+		if shift > 56 {
+			return 0, 0, errors.New("invalid object size")
+		}
+		c, err = reader.ReadByte()
+		if err != nil {
+			return 0, 0, err
+		}
+		size |= uint64(c&0x7f) << shift
+		shift += 7
+	}
+	if size > uint64(math.MaxInt) {
+		return 0, 0, errors.New("invalid object size")
+	}
+	return objectType, int(size), nil
+}
+
+// readOfsDeltaOffset reads the offset of the base of an ofs-delta, relative to
+// the offset of the delta.
+func readOfsDeltaOffset(reader io.ByteReader) (int64, error) {
+	c, err := reader.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	offset := int64(c & 0x7f)
+	for c&0x80 != 0 {
+		if offset > (1<<55)-1 {
+			return 0, errors.New("invalid delta base offset")
+		}
+		c, err = reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		offset = ((offset + 1) << 7) | int64(c&0x7f)
+	}
+	return offset, nil
+}
+
+// applyDelta applies the delta to the base, returning the resulting data.
+//
+// A delta is the size of the base and the size of the result, followed by
+// instructions to either copy a range of the base or insert new data.
+func applyDelta(base []byte, delta []byte) ([]byte, error) {
+	baseSize, delta, err := readDeltaSize(delta)
+	if err != nil {
+		return nil, err
+	}
+	if baseSize != len(base) {
+		return nil, fmt.Errorf("delta base size %d does not match size %d", baseSize, len(base))
+	}
+	resultSize, delta, err := readDeltaSize(delta)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]byte, 0, resultSize)
+	for len(delta) > 0 {
+		instruction := delta[0]
+		delta = delta[1:]
+		switch {
+		case instruction&0x80 != 0:
+			var copyOffset, copySize int
+			for i := range 4 {
+				if instruction&(1<<i) != 0 {
+					if len(delta) == 0 {
+						return nil, errors.New("truncated delta")
+					}
+					copyOffset |= int(delta[0]) << (8 * i)
+					delta = delta[1:]
+				}
+			}
+			for i := range 3 {
+				if instruction&(0x10<<i) != 0 {
+					if len(delta) == 0 {
+						return nil, errors.New("truncated delta")
+					}
+					copySize |= int(delta[0]) << (8 * i)
+					delta = delta[1:]
+				}
+			}
+			if copySize == 0 {
+				copySize = 0x10000
+			}
+			if copyOffset+copySize > len(base) {
+				return nil, errors.New("delta copies beyond the end of its base")
+			}
+			result = append(result, base[copyOffset:copyOffset+copySize]...)
+		case instruction != 0:
+			insertSize := int(instruction)
+			if insertSize > len(delta) {
+				return nil, errors.New("truncated delta")
+			}
+			result = append(result, delta[:insertSize]...)
+			delta = delta[insertSize:]
+		default:
+			return nil, errors.New("invalid delta instruction")
+		}
+		if len(result) > resultSize {
+			return nil, errors.New("delta result exceeds its size")
+		}
+	}
+	if len(result) != resultSize {
+		return nil, fmt.Errorf("delta result size %d does not match size %d", len(result), resultSize)
+	}
+	return result, nil
+}
+
+func readDeltaSize(delta []byte) (int, []byte, error) {
+	var size uint64
+	var shift uint
+	for i, c := range delta {
+		if shift > 56 {
+			break
+		}
+		size |= uint64(c&0x7f) << shift
+		shift += 7
+		if c&0x80 == 0 {
+			if size > uint64(math.MaxInt) {
+				break
+			}
+			return int(size), delta[i+1:], nil
+		}
+	}
+	return 0, nil, errors.New("invalid delta size")
+}
+
+// parseLooseObject parses the zlib-compressed contents of a loose object, which
+// is a header of the type and size, followed by the data.
+func parseLooseObject(compressed []byte) (objectType, []byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return 0, nil, err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	header, data, ok := bytes.Cut(data, []byte{0})
+	if !ok {
+		return 0, nil, errors.New("invalid object header")
+	}
+	typeName, sizeString, ok := strings.Cut(string(header), " ")
+	if !ok {
+		return 0, nil, errors.New("invalid object header")
+	}
+	objectType, err := parseObjectType(typeName)
+	if err != nil {
+		return 0, nil, err
+	}
+	size, err := strconv.Atoi(sizeString)
+	if err != nil || size != len(data) {
+		return 0, nil, errors.New("invalid object size")
+	}
+	return objectType, data, nil
+}
+
+// inflate reads the zlib-compressed data of the size.
+func inflate(reader io.Reader, size int) ([]byte, error) {
+	zlibReader, err := zlib.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer zlibReader.Close()
+	data := make([]byte, size)
+	if _, err := io.ReadFull(zlibReader, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// getObjectsDirPathsWithAlternates returns the objects directory and the objects
+// directories of its alternates, as listed in objects/info/alternates.
+func getObjectsDirPathsWithAlternates(objectsDirPath string, depth int) ([]string, error) {
+	objectsDirPaths := []string{objectsDirPath}
+	if depth >= maxAlternatesDepth {
+		return objectsDirPaths, nil
+	}
+	data, err := os.ReadFile(filepath.Join(objectsDirPath, "info", "alternates"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return objectsDirPaths, nil
+		}
+		return nil, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, `"`) {
+			// Quoted paths are rare enough that we leave them to git.
+			return nil, fmt.Errorf("unsupported quoted alternate %s: %w", line, errUnsupportedRepository)
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(objectsDirPath, line)
+		}
+		alternateObjectsDirPaths, err := getObjectsDirPathsWithAlternates(filepath.Clean(line), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		objectsDirPaths = append(objectsDirPaths, alternateObjectsDirPaths...)
+	}
+	return objectsDirPaths, nil
+}
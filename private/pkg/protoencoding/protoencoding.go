@@ -15,6 +15,8 @@
 package protoencoding
 
 import (
+	"strings"
+
 	"buf.build/go/protoyaml"
 	"github.com/bufbuild/buf/private/pkg/protodescriptor"
 	"google.golang.org/protobuf/proto"
@@ -92,6 +94,15 @@ func JSONMarshalerWithIndent() JSONMarshalerOption {
 	}
 }
 
+// JSONMarshalerWithIndentSpaces says to use an indent of the given number of spaces.
+//
+// If spaces is zero, the output is compact.
+func JSONMarshalerWithIndentSpaces(spaces int) JSONMarshalerOption {
+	return func(jsonMarshaler *jsonMarshaler) {
+		jsonMarshaler.indent = strings.Repeat(" ", spaces)
+	}
+}
+
 // JSONMarshalerWithUseProtoNames says to use proto names.
 func JSONMarshalerWithUseProtoNames() JSONMarshalerOption {
 	return func(jsonMarshaler *jsonMarshaler) {
@@ -166,8 +177,19 @@ type Unmarshaler interface {
 // NewWireUnmarshaler returns a new Unmarshaler for wire.
 //
 // If the resolver is nil, EmptyResolver will be used.
-func NewWireUnmarshaler(resolver Resolver) Unmarshaler {
-	return newWireUnmarshaler(resolver)
+func NewWireUnmarshaler(resolver Resolver, options ...WireUnmarshalerOption) Unmarshaler {
+	return newWireUnmarshaler(resolver, options...)
+}
+
+// WireUnmarshalerOption is an option for a new WireUnmarshaler.
+type WireUnmarshalerOption func(*wireUnmarshaler)
+
+// WireUnmarshalerWithDiscardUnknown says to discard unrecognized fields
+// instead of preserving them as unknown fields on the message.
+func WireUnmarshalerWithDiscardUnknown() WireUnmarshalerOption {
+	return func(wireUnmarshaler *wireUnmarshaler) {
+		wireUnmarshaler.discardUnknown = true
+	}
 }
 
 // NewJSONUnmarshaler returns a new Unmarshaler for json.
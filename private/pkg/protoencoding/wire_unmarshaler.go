@@ -19,21 +19,27 @@ import (
 )
 
 type wireUnmarshaler struct {
-	resolver Resolver
+	resolver       Resolver
+	discardUnknown bool
 }
 
-func newWireUnmarshaler(resolver Resolver) Unmarshaler {
+func newWireUnmarshaler(resolver Resolver, options ...WireUnmarshalerOption) Unmarshaler {
 	if resolver == nil {
 		resolver = EmptyResolver
 	}
-	return &wireUnmarshaler{
+	wireUnmarshaler := &wireUnmarshaler{
 		resolver: resolver,
 	}
+	for _, option := range options {
+		option(wireUnmarshaler)
+	}
+	return wireUnmarshaler
 }
 
 func (m *wireUnmarshaler) Unmarshal(data []byte, message proto.Message) error {
 	options := proto.UnmarshalOptions{
-		Resolver: m.resolver,
+		Resolver:       m.resolver,
+		DiscardUnknown: m.discardUnknown,
 	}
 	return options.Unmarshal(data, message)
 }
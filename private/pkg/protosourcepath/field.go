@@ -28,6 +28,7 @@ const (
 	fieldOptionTypeTag       = int32(8)
 	extensionExtendeeTypeTag = int32(2)
 	fieldDefaultValueTypeTag = int32(7)
+	fieldJSONNameTypeTag     = int32(10)
 )
 
 var (
@@ -84,9 +85,10 @@ func field(token int32, fullSourcePath protoreflect.SourcePath, index int, _ boo
 		// For options, we add the full path and then return the options state to validate
 		// the path.
 		return options, []protoreflect.SourcePath{slicesext.Copy(fullSourcePath)}, nil
-	case fieldDefaultValueTypeTag:
-		// Default value is a terminal path, but was not already added to our associated paths,
-		// since default values are specific to proto2. Add the path and terminate.
+	case fieldDefaultValueTypeTag, fieldJSONNameTypeTag:
+		// Default values and JSON names are terminal paths, but were not already added to our
+		// associated paths, since they are set with pseudo-options that are not present on most
+		// fields. Add the path and terminate.
 		return nil, []protoreflect.SourcePath{currentPath(fullSourcePath, index)}, nil
 	}
 	return nil, nil, newInvalidSourcePathError(fullSourcePath, "invalid field path")
@@ -246,6 +246,8 @@ func TestGetAssociatedSourcePathsProto3(t *testing.T) {
 			".message_type[0].field[0].type":                     {[]int32{4, 0}, []int32{4, 0, 2, 0}},
 			".message_type[0].field[0].name":                     {[]int32{4, 0}, []int32{4, 0, 2, 0}},
 			".message_type[0].field[0].number":                   {[]int32{4, 0}, []int32{4, 0, 2, 0}},
+			".message_type[0].field[0].options":                  {[]int32{4, 0}, []int32{4, 0, 2, 0}, []int32{4, 0, 2, 0, 8}},
+			".message_type[0].field[0].json_name":                {[]int32{4, 0}, []int32{4, 0, 2, 0}, []int32{4, 0, 2, 0, 10}},
 			".message_type[1]":                                   {[]int32{4, 1}},
 			".message_type[1].name":                              {[]int32{4, 1}},
 			".message_type[1].nested_type[0]":                    {[]int32{4, 1}, []int32{4, 1, 3, 0}},
@@ -320,6 +322,8 @@ func TestGetAssociatedSourcePathsProto3(t *testing.T) {
 			".message_type[0].field[0].type":                     {[]int32{4, 0}, []int32{4, 0, 1}, []int32{4, 0, 2, 0}, []int32{4, 0, 2, 0, 1}, []int32{4, 0, 2, 0, 3}, []int32{4, 0, 2, 0, 4}, []int32{4, 0, 2, 0, 5}, []int32{4, 0, 2, 0, 6}},
 			".message_type[0].field[0].name":                     {[]int32{4, 0}, []int32{4, 0, 1}, []int32{4, 0, 2, 0}, []int32{4, 0, 2, 0, 1}, []int32{4, 0, 2, 0, 3}, []int32{4, 0, 2, 0, 4}, []int32{4, 0, 2, 0, 5}, []int32{4, 0, 2, 0, 6}},
 			".message_type[0].field[0].number":                   {[]int32{4, 0}, []int32{4, 0, 1}, []int32{4, 0, 2, 0}, []int32{4, 0, 2, 0, 1}, []int32{4, 0, 2, 0, 3}, []int32{4, 0, 2, 0, 4}, []int32{4, 0, 2, 0, 5}, []int32{4, 0, 2, 0, 6}},
+			".message_type[0].field[0].options":                  {[]int32{4, 0}, []int32{4, 0, 1}, []int32{4, 0, 2, 0}, []int32{4, 0, 2, 0, 1}, []int32{4, 0, 2, 0, 3}, []int32{4, 0, 2, 0, 4}, []int32{4, 0, 2, 0, 5}, []int32{4, 0, 2, 0, 6}, []int32{4, 0, 2, 0, 8}},
+			".message_type[0].field[0].json_name":                {[]int32{4, 0}, []int32{4, 0, 1}, []int32{4, 0, 2, 0}, []int32{4, 0, 2, 0, 1}, []int32{4, 0, 2, 0, 3}, []int32{4, 0, 2, 0, 4}, []int32{4, 0, 2, 0, 5}, []int32{4, 0, 2, 0, 6}, []int32{4, 0, 2, 0, 10}},
 			".message_type[1]":                                   {[]int32{4, 1}, []int32{4, 1, 1}},
 			".message_type[1].name":                              {[]int32{4, 1}, []int32{4, 1, 1}},
 			".message_type[1].nested_type[0]":                    {[]int32{4, 1}, []int32{4, 1, 1}, []int32{4, 1, 3, 0}, []int32{4, 1, 3, 0, 1}},
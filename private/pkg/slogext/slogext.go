@@ -22,6 +22,13 @@ import (
 	"time"
 )
 
+const (
+	// ComponentKey is the key of the attribute that scopes a Logger to a component.
+	//
+	// See WithComponent.
+	ComponentKey = "component"
+)
+
 var (
 	// NopLogger is a no-op Logger.
 	NopLogger = slog.New(NopHandler)
@@ -39,6 +46,14 @@ func ErrorAttr(err error) slog.Attr {
 	return slog.Any("error", err)
 }
 
+// WithComponent returns a new Logger scoped to the given component.
+//
+// Handlers may use the component to filter the logs of the Logger, for example
+// to turn on debug logging for a single component.
+func WithComponent(logger *slog.Logger, component string) *slog.Logger {
+	return logger.With(slog.String(ComponentKey, component))
+}
+
 // DebugProfile will result in the function's elapsed time being printed as a debug log line.
 func DebugProfile(logger *slog.Logger, extraFields ...any) func() {
 	message := getRuntimeFrame(2).Function
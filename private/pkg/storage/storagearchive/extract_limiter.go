@@ -0,0 +1,101 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagearchive
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+)
+
+// extractLimiter enforces the limits on the entries extracted from a single archive.
+//
+// A zero value for any limit means that there is no limit.
+type extractLimiter struct {
+	maxFileSize  int64
+	maxTotalSize int64
+	maxFileCount int
+	maxPathDepth int
+
+	fileCount int
+	totalSize int64
+}
+
+func newExtractLimiter() *extractLimiter {
+	return &extractLimiter{}
+}
+
+// checkEntry checks the limits for an entry in the archive before it is read.
+//
+// Only regular files count towards the maximum file count.
+func (e *extractLimiter) checkEntry(archivePath string, isRegular bool) error {
+	if isRegular {
+		e.fileCount++
+		if e.maxFileCount != 0 && e.fileCount > e.maxFileCount {
+			return fmt.Errorf("%w: archive contains more than %d files", ErrFileCountLimit, e.maxFileCount)
+		}
+	}
+	if e.maxPathDepth != 0 {
+		if pathDepth := len(normalpath.Components(normalpath.Normalize(archivePath))); pathDepth > e.maxPathDepth {
+			return fmt.Errorf("%w: %s has %d components, more than the limit of %d", ErrPathDepthLimit, archivePath, pathDepth, e.maxPathDepth)
+		}
+	}
+	return nil
+}
+
+// checkFileSize checks the size of a file as declared by the archive before it is read.
+func (e *extractLimiter) checkFileSize(archivePath string, size int64) error {
+	if e.maxFileSize != 0 && size > e.maxFileSize {
+		return fmt.Errorf("%w %s:%d", ErrFileSizeLimit, archivePath, size)
+	}
+	if e.maxTotalSize != 0 && e.totalSize+size > e.maxTotalSize {
+		return fmt.Errorf("%w: files exceed %d bytes at %s", ErrTotalSizeLimit, e.maxTotalSize, archivePath)
+	}
+	return nil
+}
+
+// newReader returns a new io.Reader for a file in the archive that returns an error as soon
+// as the bytes actually read exceed the limits, regardless of the size declared by the archive.
+func (e *extractLimiter) newReader(archivePath string, reader io.Reader) io.Reader {
+	if e.maxFileSize == 0 && e.maxTotalSize == 0 {
+		return reader
+	}
+	return &limitedReader{
+		extractLimiter: e,
+		archivePath:    archivePath,
+		reader:         reader,
+	}
+}
+
+type limitedReader struct {
+	extractLimiter *extractLimiter
+	archivePath    string
+	reader         io.Reader
+	size           int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.reader.Read(p)
+	l.size += int64(n)
+	l.extractLimiter.totalSize += int64(n)
+	if maxFileSize := l.extractLimiter.maxFileSize; maxFileSize != 0 && l.size > maxFileSize {
+		return n, fmt.Errorf("%w %s:%d", ErrFileSizeLimit, l.archivePath, l.size)
+	}
+	if maxTotalSize := l.extractLimiter.maxTotalSize; maxTotalSize != 0 && l.extractLimiter.totalSize > maxTotalSize {
+		return n, fmt.Errorf("%w: files exceed %d bytes at %s", ErrTotalSizeLimit, maxTotalSize, l.archivePath)
+	}
+	return n, err
+}
@@ -23,6 +23,7 @@ import (
 	"io"
 	"io/fs"
 	"strings"
+	"time"
 
 	"github.com/bufbuild/buf/private/pkg/normalpath"
 	"github.com/bufbuild/buf/private/pkg/storage"
@@ -35,50 +36,79 @@ var (
 	//
 	// See [WithMaxFileSizeUntarOption]
 	ErrFileSizeLimit = errors.New("file size exceeded read limit")
+	// ErrTotalSizeLimit is returned when the total size of the files read from an archive
+	// exceeds the limit.
+	//
+	// See [UntarWithMaxTotalSize] and [UnzipWithMaxTotalSize].
+	ErrTotalSizeLimit = errors.New("total size of files exceeded read limit")
+	// ErrFileCountLimit is returned when the number of entries in an archive exceeds the limit.
+	//
+	// See [UntarWithMaxFileCount] and [UnzipWithMaxFileCount].
+	ErrFileCountLimit = errors.New("number of files exceeded limit")
+	// ErrPathDepthLimit is returned when the number of components of a path in an archive
+	// exceeds the limit.
+	//
+	// See [UntarWithMaxPathDepth] and [UnzipWithMaxPathDepth].
+	ErrPathDepthLimit = errors.New("path depth exceeded limit")
 )
 
 // Tar tars the given bucket to the writer.
 //
 // Only regular files are added to the writer.
-// All files are written as 0644.
+// All files are written as 0644, in sorted order, with the same modification time,
+// so that the same bucket always results in the same archive.
 func Tar(
 	ctx context.Context,
 	readBucket storage.ReadBucket,
 	writer io.Writer,
+	options ...TarOption,
 ) (retErr error) {
+	tarOptions := newTarOptions()
+	for _, option := range options {
+		option(tarOptions)
+	}
 	tarWriter := tar.NewWriter(writer)
 	defer func() {
 		retErr = errors.Join(retErr, tarWriter.Close())
 	}()
-	return storage.WalkReadObjects(
+	return walkSortedPaths(
 		ctx,
 		readBucket,
-		"",
-		func(readObject storage.ReadObject) error {
-			data, err := io.ReadAll(readObject)
-			if err != nil {
-				return err
-			}
+		func(path string, data []byte) error {
 			if err := tarWriter.WriteHeader(
 				&tar.Header{
 					Typeflag: tar.TypeReg,
-					Name:     readObject.Path(),
+					Name:     path,
 					Size:     int64(len(data)),
-					// If we ever use this outside of testing, we will want to do something about this
-					Mode: 0644,
+					Mode:     0644,
+					ModTime:  tarOptions.modTime,
+					Format:   tar.FormatPAX,
 				},
 			); err != nil {
 				return err
 			}
-			_, err = tarWriter.Write(data)
+			_, err := tarWriter.Write(data)
 			return err
 		},
 	)
 }
 
+// TarOption is an option for Tar.
+type TarOption func(*tarOptions)
+
+// TarWithModTime returns a new TarOption that sets the modification time of all files.
+//
+// The default is the Unix epoch.
+func TarWithModTime(modTime time.Time) TarOption {
+	return func(tarOptions *tarOptions) {
+		tarOptions.modTime = modTime
+	}
+}
+
 // Untar untars the given tar archive from the reader into the bucket.
 //
-// Only regular files are added to the bucket.
+// Only regular files are added to the bucket, symlinks and hard links are skipped.
+// An error is returned if any of the configured limits are exceeded.
 //
 // Paths from the tar archive will be mapped before adding to the bucket.
 // Mapper can be nil.
@@ -95,6 +125,7 @@ func Untar(
 	}
 	tarReader := tar.NewReader(reader)
 	walkChecker := storageutil.NewWalkChecker()
+	extractLimiter := untarOptions.extractLimiter
 	for tarHeader, err := tarReader.Next(); err != io.EOF; tarHeader, err = tarReader.Next() {
 		if err != nil {
 			return err
@@ -105,6 +136,11 @@ func Untar(
 		if tarHeader.Size < 0 {
 			return fmt.Errorf("invalid size for tar file %s: %d", tarHeader.Name, tarHeader.Size)
 		}
+		// Hard links are reported as regular files by FileInfo, but have no content of their own.
+		isRegular := tarHeader.Typeflag != tar.TypeLink && tarHeader.FileInfo().Mode().IsRegular()
+		if err := extractLimiter.checkEntry(tarHeader.Name, isRegular); err != nil {
+			return err
+		}
 		if isAppleExtendedAttributesFile(tarHeader.FileInfo()) {
 			continue
 		}
@@ -112,13 +148,13 @@ func Untar(
 		if err != nil {
 			return err
 		}
-		if !ok || !tarHeader.FileInfo().Mode().IsRegular() {
+		if !ok || !isRegular {
 			continue
 		}
-		if untarOptions.maxFileSize != 0 && tarHeader.Size > untarOptions.maxFileSize {
-			return fmt.Errorf("%w %s:%d", ErrFileSizeLimit, tarHeader.Name, tarHeader.Size)
+		if err := extractLimiter.checkFileSize(tarHeader.Name, tarHeader.Size); err != nil {
+			return err
 		}
-		if err := storage.CopyReader(ctx, writeBucket, tarReader, path); err != nil {
+		if err := storage.CopyReader(ctx, writeBucket, extractLimiter.newReader(tarHeader.Name, tarReader), path); err != nil {
 			return err
 		}
 	}
@@ -133,7 +169,37 @@ type UntarOption func(*untarOptions)
 // The default is to have no limit.
 func UntarWithMaxFileSize(maxFileSize int64) UntarOption {
 	return func(untarOptions *untarOptions) {
-		untarOptions.maxFileSize = maxFileSize
+		untarOptions.extractLimiter.maxFileSize = maxFileSize
+	}
+}
+
+// UntarWithMaxTotalSize returns a new UntarOption that limits the total size of all files.
+//
+// This protects against archives that expand to a size much larger than the archive itself.
+// The default is to have no limit.
+func UntarWithMaxTotalSize(maxTotalSize int64) UntarOption {
+	return func(untarOptions *untarOptions) {
+		untarOptions.extractLimiter.maxTotalSize = maxTotalSize
+	}
+}
+
+// UntarWithMaxFileCount returns a new UntarOption that limits the number of regular files in the archive.
+//
+// The default is to have no limit.
+func UntarWithMaxFileCount(maxFileCount int) UntarOption {
+	return func(untarOptions *untarOptions) {
+		untarOptions.extractLimiter.maxFileCount = maxFileCount
+	}
+}
+
+// UntarWithMaxPathDepth returns a new UntarOption that limits the number of components
+// of each path in the archive.
+//
+// The limit is applied before components are stripped.
+// The default is to have no limit.
+func UntarWithMaxPathDepth(maxPathDepth int) UntarOption {
+	return func(untarOptions *untarOptions) {
+		untarOptions.extractLimiter.maxPathDepth = maxPathDepth
 	}
 }
 
@@ -157,42 +223,64 @@ func UntarWithFilePathMatcher(filePathMatcher func(string) bool) UntarOption {
 // Zip zips the given bucket to the writer.
 //
 // Only regular files are added to the writer.
+// All files are written as 0644, in sorted order, with the same modification time,
+// so that the same bucket always results in the same archive.
 func Zip(
 	ctx context.Context,
 	readBucket storage.ReadBucket,
 	writer io.Writer,
 	compressed bool,
+	options ...ZipOption,
 ) (retErr error) {
+	zipOptions := newZipOptions()
+	for _, option := range options {
+		option(zipOptions)
+	}
 	zipWriter := zip.NewWriter(writer)
 	defer func() {
 		retErr = errors.Join(retErr, zipWriter.Close())
 	}()
-	return storage.WalkReadObjects(
+	return walkSortedPaths(
 		ctx,
 		readBucket,
-		"",
-		func(readObject storage.ReadObject) error {
+		func(path string, data []byte) error {
 			method := zip.Store
 			if compressed {
 				method = zip.Deflate
 			}
 			header := &zip.FileHeader{
-				Name:   readObject.Path(),
-				Method: method,
+				Name:     path,
+				Method:   method,
+				Modified: zipOptions.modTime,
 			}
+			header.SetMode(0644)
 			writer, err := zipWriter.CreateHeader(header)
 			if err != nil {
 				return err
 			}
-			_, err = io.Copy(writer, readObject)
+			_, err = writer.Write(data)
 			return err
 		},
 	)
 }
 
+// ZipOption is an option for Zip.
+type ZipOption func(*zipOptions)
+
+// ZipWithModTime returns a new ZipOption that sets the modification time of all files.
+//
+// The default is the Unix epoch. Zip archives cannot represent times before 1980, so
+// earlier times are only recorded in the extended timestamp field.
+func ZipWithModTime(modTime time.Time) ZipOption {
+	return func(zipOptions *zipOptions) {
+		zipOptions.modTime = modTime
+	}
+}
+
 // Unzip unzips the given zip archive from the reader into the bucket.
 //
-// Only regular files are added to the bucket.
+// Only regular files are added to the bucket, symlinks are skipped.
+// An error is returned if any of the configured limits are exceeded.
 //
 // Paths from the zip archive will be mapped before adding to the bucket.
 // Mapper can be nil.
@@ -219,11 +307,15 @@ func Unzip(
 		return err
 	}
 	walkChecker := storageutil.NewWalkChecker()
+	extractLimiter := unzipOptions.extractLimiter
 	// reads can be done concurrently in the future
 	for _, zipFile := range zipReader.File {
 		if err := walkChecker.Check(ctx); err != nil {
 			return err
 		}
+		if err := extractLimiter.checkEntry(zipFile.Name, zipFile.Mode().IsRegular()); err != nil {
+			return err
+		}
 		path, ok, err := unmapArchivePath(zipFile.Name, unzipOptions.filePathMatcher, unzipOptions.stripComponentCount)
 		if err != nil {
 			return err
@@ -235,7 +327,12 @@ func Unzip(
 			continue
 		}
 		if zipFile.FileInfo().Mode().IsRegular() {
-			if err := copyZipFile(ctx, writeBucket, zipFile, path); err != nil {
+			// The uncompressed size in the header is checked to fail fast, and the
+			// bytes actually read are checked as the header may not be accurate.
+			if err := extractLimiter.checkFileSize(zipFile.Name, int64(zipFile.UncompressedSize64)); err != nil {
+				return err
+			}
+			if err := copyZipFile(ctx, writeBucket, zipFile, path, extractLimiter); err != nil {
 				return err
 			}
 		}
@@ -246,6 +343,45 @@ func Unzip(
 // UnzipOption is an option for Unzip.
 type UnzipOption func(*unzipOptions)
 
+// UnzipWithMaxFileSize returns a new UnzipOption that limits the maximum file size.
+//
+// The default is to have no limit.
+func UnzipWithMaxFileSize(maxFileSize int64) UnzipOption {
+	return func(unzipOptions *unzipOptions) {
+		unzipOptions.extractLimiter.maxFileSize = maxFileSize
+	}
+}
+
+// UnzipWithMaxTotalSize returns a new UnzipOption that limits the total size of all files.
+//
+// This protects against archives that expand to a size much larger than the archive itself.
+// The default is to have no limit.
+func UnzipWithMaxTotalSize(maxTotalSize int64) UnzipOption {
+	return func(unzipOptions *unzipOptions) {
+		unzipOptions.extractLimiter.maxTotalSize = maxTotalSize
+	}
+}
+
+// UnzipWithMaxFileCount returns a new UnzipOption that limits the number of regular files in the archive.
+//
+// The default is to have no limit.
+func UnzipWithMaxFileCount(maxFileCount int) UnzipOption {
+	return func(unzipOptions *unzipOptions) {
+		unzipOptions.extractLimiter.maxFileCount = maxFileCount
+	}
+}
+
+// UnzipWithMaxPathDepth returns a new UnzipOption that limits the number of components
+// of each path in the archive.
+//
+// The limit is applied before components are stripped.
+// The default is to have no limit.
+func UnzipWithMaxPathDepth(maxPathDepth int) UnzipOption {
+	return func(unzipOptions *unzipOptions) {
+		unzipOptions.extractLimiter.maxPathDepth = maxPathDepth
+	}
+}
+
 // UnzipWithStripComponentCount returns a new UnzipOption that strips the specified number of components.
 func UnzipWithStripComponentCount(stripComponentCount uint32) UnzipOption {
 	return func(unzipOptions *unzipOptions) {
@@ -277,11 +413,37 @@ func isAppleExtendedAttributesFile(fileInfo fs.FileInfo) bool {
 	return strings.HasPrefix(fileInfo.Name(), "._")
 }
 
+// walkSortedPaths calls f for every file in the bucket in sorted order.
+//
+// storage.ReadBucket.Walk does not guarantee an order, and archives must be written in the
+// same order every time to be reproducible.
+func walkSortedPaths(
+	ctx context.Context,
+	readBucket storage.ReadBucket,
+	f func(path string, data []byte) error,
+) error {
+	paths, err := storage.AllPaths(ctx, readBucket, "")
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		data, err := storage.ReadPath(ctx, readBucket, path)
+		if err != nil {
+			return err
+		}
+		if err := f(path, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func copyZipFile(
 	ctx context.Context,
 	writeBucket storage.WriteBucket,
 	zipFile *zip.File,
 	path string,
+	extractLimiter *extractLimiter,
 ) (retErr error) {
 	readCloser, err := zipFile.Open()
 	if err != nil {
@@ -290,7 +452,7 @@ func copyZipFile(
 	defer func() {
 		retErr = errors.Join(retErr, readCloser.Close())
 	}()
-	return storage.CopyReader(ctx, writeBucket, readCloser, path)
+	return storage.CopyReader(ctx, writeBucket, extractLimiter.newReader(zipFile.Name, readCloser), path)
 }
 
 func unmapArchivePath(
@@ -318,21 +480,46 @@ func unmapArchivePath(
 	return fullPath, true, nil
 }
 
+type tarOptions struct {
+	modTime time.Time
+}
+
+func newTarOptions() *tarOptions {
+	return &tarOptions{
+		modTime: time.Unix(0, 0).UTC(),
+	}
+}
+
+type zipOptions struct {
+	modTime time.Time
+}
+
+func newZipOptions() *zipOptions {
+	return &zipOptions{
+		modTime: time.Unix(0, 0).UTC(),
+	}
+}
+
 type untarOptions struct {
-	maxFileSize         int64
+	extractLimiter      *extractLimiter
 	stripComponentCount uint32
 	filePathMatcher     func(string) bool
 }
 
 func newUntarOptions() *untarOptions {
-	return &untarOptions{}
+	return &untarOptions{
+		extractLimiter: newExtractLimiter(),
+	}
 }
 
 type unzipOptions struct {
+	extractLimiter      *extractLimiter
 	stripComponentCount uint32
 	filePathMatcher     func(string) bool
 }
 
 func newUnzipOptions() *unzipOptions {
-	return &unzipOptions{}
+	return &unzipOptions{
+		extractLimiter: newExtractLimiter(),
+	}
 }
@@ -0,0 +1,153 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagegit
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"sort"
+
+	"github.com/bufbuild/buf/private/pkg/git"
+	"github.com/bufbuild/buf/private/pkg/normalpath"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storageutil"
+)
+
+type bucket struct {
+	objectReader git.ObjectReader
+	// Sorted by path.
+	objects      []*object
+	pathToObject map[string]*object
+}
+
+func newBucket(
+	ctx context.Context,
+	objectReader git.ObjectReader,
+	commit string,
+) (*bucket, error) {
+	blobs, err := objectReader.ListBlobs(ctx, commit)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]*object, 0, len(blobs))
+	pathToObject := make(map[string]*object, len(blobs))
+	for _, blob := range blobs {
+		path, err := storageutil.ValidatePath(blob.Path)
+		if err != nil {
+			return nil, err
+		}
+		object := &object{
+			ObjectInfo: storageutil.NewObjectInfo(path, path, ""),
+			hash:       blob.Hash,
+		}
+		objects = append(objects, object)
+		pathToObject[path] = object
+	}
+	sort.Slice(
+		objects,
+		func(i int, j int) bool {
+			return objects[i].Path() < objects[j].Path()
+		},
+	)
+	return &bucket{
+		objectReader: objectReader,
+		objects:      objects,
+		pathToObject: pathToObject,
+	}, nil
+}
+
+func (b *bucket) Get(ctx context.Context, path string) (storage.ReadObjectCloser, error) {
+	object, err := b.getObject(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := b.objectReader.ReadBlob(ctx, object.hash)
+	if err != nil {
+		return nil, err
+	}
+	return &readObjectCloser{
+		ObjectInfo: object.ObjectInfo,
+		reader:     bytes.NewReader(data),
+	}, nil
+}
+
+func (b *bucket) Stat(ctx context.Context, path string) (storage.ObjectInfo, error) {
+	object, err := b.getObject(path)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+func (b *bucket) Walk(ctx context.Context, prefix string, f func(storage.ObjectInfo) error) error {
+	prefix, err := storageutil.ValidatePrefix(prefix)
+	if err != nil {
+		return err
+	}
+	walkChecker := storageutil.NewWalkChecker()
+	for _, object := range b.objects {
+		if err := walkChecker.Check(ctx); err != nil {
+			return err
+		}
+		if !normalpath.EqualsOrContainsPath(prefix, object.Path(), normalpath.Relative) {
+			continue
+		}
+		if err := f(object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *bucket) getObject(path string) (*object, error) {
+	path, err := storageutil.ValidatePath(path)
+	if err != nil {
+		return nil, err
+	}
+	object, ok := b.pathToObject[path]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: path, Err: fs.ErrNotExist}
+	}
+	return object, nil
+}
+
+type object struct {
+	storageutil.ObjectInfo
+
+	hash string
+}
+
+type readObjectCloser struct {
+	storageutil.ObjectInfo
+
+	reader *bytes.Reader
+	closed bool
+}
+
+func (r *readObjectCloser) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, storage.ErrClosed
+	}
+	return r.reader.Read(p)
+}
+
+func (r *readObjectCloser) Close() error {
+	if r.closed {
+		return storage.ErrClosed
+	}
+	r.closed = true
+	return nil
+}
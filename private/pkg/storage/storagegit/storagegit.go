@@ -0,0 +1,41 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storagegit implements a read-only storage Bucket for the tree of a git commit.
+//
+// Files are read directly from the objects of the git repository, so no checkout
+// or clone is required.
+package storagegit
+
+import (
+	"context"
+
+	"github.com/bufbuild/buf/private/pkg/git"
+	"github.com/bufbuild/buf/private/pkg/storage"
+)
+
+// NewReadBucket returns a new ReadBucket for the tree of the commit.
+//
+// The tree of the commit is listed once, and the contents of files are read with
+// the ObjectReader when they are requested. The ObjectReader must not be closed
+// until the ReadBucket is no longer used.
+//
+// Symbolic links and submodules are not included in the ReadBucket.
+func NewReadBucket(
+	ctx context.Context,
+	objectReader git.ObjectReader,
+	commit string,
+) (storage.ReadBucket, error) {
+	return newBucket(ctx, objectReader, commit)
+}
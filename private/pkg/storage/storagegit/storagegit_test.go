@@ -0,0 +1,94 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagegit_test
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufbuild/buf/private/pkg/app"
+	"github.com/bufbuild/buf/private/pkg/execext"
+	"github.com/bufbuild/buf/private/pkg/git"
+	"github.com/bufbuild/buf/private/pkg/storage"
+	"github.com/bufbuild/buf/private/pkg/storage/storagegit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBucket(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	container, err := app.NewContainerForOS()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	runGit(ctx, t, dir, "init")
+	runGit(ctx, t, dir, "config", "user.email", "tests@buf.build")
+	runGit(ctx, t, dir, "config", "user.name", "Buf go tests")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "foo", "bar"), 0777))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "buf.yaml"), []byte("version: v2\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo", "a.proto"), []byte("// a"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo", "bar", "b.proto"), []byte("// b"), 0600))
+	runGit(ctx, t, dir, "add", ".")
+	runGit(ctx, t, dir, "commit", "-m", "commit 0")
+	// Changes to the working tree are not part of the commit.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo", "a.proto"), []byte("// changed"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo", "c.proto"), []byte("// c"), 0600))
+
+	objectReader, err := git.NewObjectReader(ctx, container, dir)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, objectReader.Close()) })
+	commit, err := objectReader.ResolveCommit(ctx, nil)
+	require.NoError(t, err)
+	readBucket, err := storagegit.NewReadBucket(ctx, objectReader, commit)
+	require.NoError(t, err)
+
+	paths, err := storage.AllPaths(ctx, readBucket, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"buf.yaml", "foo/a.proto", "foo/bar/b.proto"}, paths)
+	paths, err = storage.AllPaths(ctx, readBucket, "foo/bar")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo/bar/b.proto"}, paths)
+	data, err := storage.ReadPath(ctx, readBucket, "foo/a.proto")
+	require.NoError(t, err)
+	assert.Equal(t, "// a", string(data))
+	objectInfo, err := readBucket.Stat(ctx, "foo/bar/b.proto")
+	require.NoError(t, err)
+	assert.Equal(t, "foo/bar/b.proto", objectInfo.Path())
+	assert.Equal(t, "", objectInfo.LocalPath())
+	objectInfo, err = readBucket.Stat(ctx, "foo/c.proto")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Nil(t, objectInfo)
+	_, err = readBucket.Get(ctx, "foo")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func runGit(ctx context.Context, t *testing.T, dir string, args ...string) {
+	t.Helper()
+	output := bytes.NewBuffer(nil)
+	err := execext.Run(
+		ctx,
+		"git",
+		execext.WithArgs(args...),
+		execext.WithStdout(output),
+		execext.WithStderr(output),
+		execext.WithDir(dir),
+		execext.WithEnv(os.Environ()),
+	)
+	require.NoError(t, err, output.String())
+}
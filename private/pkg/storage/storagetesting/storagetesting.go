@@ -16,6 +16,8 @@
 package storagetesting
 
 import (
+	"archive/tar"
+	"archive/zip"
 	"bytes"
 	"context"
 	"errors"
@@ -1540,6 +1542,129 @@ func RunTestSuite(
 		assert.NoError(t, err)
 	})
 
+	t.Run("limit-unarchive", func(t *testing.T) {
+		t.Parallel()
+		writeBucket := newWriteBucket(t, defaultProvider)
+		files := map[string][]byte{
+			"a.proto":       bytes.Repeat([]byte{0}, 1000),
+			"b/b.proto":     bytes.Repeat([]byte{0}, 1000),
+			"c/d/e/f.proto": bytes.Repeat([]byte{0}, 1000),
+		}
+		for path, data := range files {
+			require.NoError(t, storage.PutPath(context.Background(), writeBucket, path, data))
+		}
+		var tarBuffer bytes.Buffer
+		require.NoError(t, storagearchive.Tar(context.Background(), writeBucketToReadBucket(t, writeBucket), &tarBuffer))
+		var zipBuffer bytes.Buffer
+		require.NoError(t, storagearchive.Zip(context.Background(), writeBucketToReadBucket(t, writeBucket), &zipBuffer, true))
+		untar := func(options ...storagearchive.UntarOption) error {
+			return storagearchive.Untar(
+				context.Background(),
+				bytes.NewReader(tarBuffer.Bytes()),
+				newWriteBucket(t, defaultProvider),
+				options...,
+			)
+		}
+		unzip := func(options ...storagearchive.UnzipOption) error {
+			return storagearchive.Unzip(
+				context.Background(),
+				bytes.NewReader(zipBuffer.Bytes()),
+				int64(zipBuffer.Len()),
+				newWriteBucket(t, defaultProvider),
+				options...,
+			)
+		}
+		assert.NoError(t, untar(storagearchive.UntarWithMaxTotalSize(3000)))
+		assert.ErrorIs(t, untar(storagearchive.UntarWithMaxTotalSize(2999)), storagearchive.ErrTotalSizeLimit)
+		assert.NoError(t, unzip(storagearchive.UnzipWithMaxTotalSize(3000)))
+		assert.ErrorIs(t, unzip(storagearchive.UnzipWithMaxTotalSize(2999)), storagearchive.ErrTotalSizeLimit)
+		assert.NoError(t, unzip(storagearchive.UnzipWithMaxFileSize(1000)))
+		assert.ErrorIs(t, unzip(storagearchive.UnzipWithMaxFileSize(999)), storagearchive.ErrFileSizeLimit)
+		assert.NoError(t, untar(storagearchive.UntarWithMaxFileCount(3)))
+		assert.ErrorIs(t, untar(storagearchive.UntarWithMaxFileCount(2)), storagearchive.ErrFileCountLimit)
+		assert.NoError(t, unzip(storagearchive.UnzipWithMaxFileCount(3)))
+		assert.ErrorIs(t, unzip(storagearchive.UnzipWithMaxFileCount(2)), storagearchive.ErrFileCountLimit)
+		assert.NoError(t, untar(storagearchive.UntarWithMaxPathDepth(4)))
+		assert.ErrorIs(t, untar(storagearchive.UntarWithMaxPathDepth(3)), storagearchive.ErrPathDepthLimit)
+		assert.NoError(t, unzip(storagearchive.UnzipWithMaxPathDepth(4)))
+		assert.ErrorIs(t, unzip(storagearchive.UnzipWithMaxPathDepth(3)), storagearchive.ErrPathDepthLimit)
+	})
+	t.Run("untar-skip-links-and-directories", func(t *testing.T) {
+		t.Parallel()
+		var buffer bytes.Buffer
+		tarWriter := tar.NewWriter(&buffer)
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "a/", Mode: 0755}))
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "a/a.proto", Size: 3, Mode: 0644}))
+		_, err := tarWriter.Write([]byte("abc"))
+		require.NoError(t, err)
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Typeflag: tar.TypeSymlink, Name: "a/b.proto", Linkname: "../../b.proto", Mode: 0777}))
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Typeflag: tar.TypeSymlink, Name: "a/c.proto", Linkname: "/etc/passwd", Mode: 0777}))
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Typeflag: tar.TypeLink, Name: "a/d.proto", Linkname: "../d.proto", Mode: 0777}))
+		require.NoError(t, tarWriter.Close())
+		writeBucket := newWriteBucket(t, defaultProvider)
+		require.NoError(
+			t,
+			storagearchive.Untar(
+				context.Background(),
+				&buffer,
+				writeBucket,
+				storagearchive.UntarWithMaxFileCount(1),
+			),
+		)
+		AssertPathToContent(
+			t,
+			writeBucketToReadBucket(t, writeBucket),
+			"",
+			map[string]string{
+				"a/a.proto": "abc",
+			},
+		)
+	})
+	t.Run("unzip-skip-links-and-directories", func(t *testing.T) {
+		t.Parallel()
+		var buffer bytes.Buffer
+		zipWriter := zip.NewWriter(&buffer)
+		dirHeader := &zip.FileHeader{Name: "a/"}
+		dirHeader.SetMode(fs.ModeDir | 0755)
+		_, err := zipWriter.CreateHeader(dirHeader)
+		require.NoError(t, err)
+		writer, err := zipWriter.Create("a/a.proto")
+		require.NoError(t, err)
+		_, err = writer.Write([]byte("abc"))
+		require.NoError(t, err)
+		for name, linkname := range map[string]string{
+			"a/b.proto": "../../b.proto",
+			"a/c.proto": "/etc/passwd",
+		} {
+			fileHeader := &zip.FileHeader{Name: name}
+			fileHeader.SetMode(fs.ModeSymlink | 0777)
+			writer, err := zipWriter.CreateHeader(fileHeader)
+			require.NoError(t, err)
+			_, err = writer.Write([]byte(linkname))
+			require.NoError(t, err)
+		}
+		require.NoError(t, zipWriter.Close())
+		writeBucket := newWriteBucket(t, defaultProvider)
+		require.NoError(
+			t,
+			storagearchive.Unzip(
+				context.Background(),
+				bytes.NewReader(buffer.Bytes()),
+				int64(buffer.Len()),
+				writeBucket,
+				storagearchive.UnzipWithMaxFileCount(1),
+			),
+		)
+		AssertPathToContent(
+			t,
+			writeBucketToReadBucket(t, writeBucket),
+			"",
+			map[string]string{
+				"a/a.proto": "abc",
+			},
+		)
+	})
+
 	t.Run("walk-on-file-path-that-is-not-pure-prefix", func(t *testing.T) {
 		t.Parallel()
 		readBucket, _ := newReadBucket(t, oneDirPath, defaultProvider)
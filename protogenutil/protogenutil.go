@@ -14,6 +14,13 @@
 
 // Package protogenutil provides support for protoc plugin development with the
 // protoplugin and protogen packages.
+//
+// Plugins are composed of protoplugin.Handlers that are invoked with all files, each file,
+// all Go packages, or each Go package marked for generation, optionally as named plugins.
+// Handlers can be chained with NewChainHandler to generate multiple outputs in a single plugin.
+//
+// Unlike the packages within github.com/bufbuild/buf/private, this package is supported
+// for use by plugin authors outside of Buf.
 package protogenutil
 
 import (
@@ -187,6 +194,29 @@ func NewPerGoPackageHandler(f func(*protogen.Plugin, *GoPackageFileSet) error, o
 	)
 }
 
+// NewChainHandler returns a new protoplugin.Handler that invokes each of the handlers
+// in order with the same request, writing to the same response.
+//
+// The first error returned by a handler is returned, and the remaining handlers are not
+// invoked. Errors that handlers add to the response do not stop the remaining handlers.
+func NewChainHandler(handlers ...protoplugin.Handler) protoplugin.Handler {
+	return protoplugin.HandlerFunc(
+		func(
+			ctx context.Context,
+			pluginEnv protoplugin.PluginEnv,
+			responseWriter protoplugin.ResponseWriter,
+			request protoplugin.Request,
+		) error {
+			for _, handler := range handlers {
+				if err := handler.Handle(ctx, pluginEnv, responseWriter, request); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+}
+
 // HandlerOption is an option for a new Handler.
 type HandlerOption func(*handlerOptions)
 
@@ -0,0 +1,136 @@
+// Copyright 2020-2025 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protogenutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bufbuild/protoplugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestChainHandler(t *testing.T) {
+	t.Parallel()
+	response := testRun(
+		t,
+		NewChainHandler(
+			NewPerFileHandler(
+				func(plugin *protogen.Plugin, file *protogen.File) error {
+					generatedFile := plugin.NewGeneratedFile(file.GeneratedFilenamePrefix+".file.txt", file.GoImportPath)
+					generatedFile.P(file.Desc.Path())
+					return nil
+				},
+			),
+			NewPerGoPackageHandler(
+				func(plugin *protogen.Plugin, goPackageFileSet *GoPackageFileSet) error {
+					generatedFile := plugin.NewGeneratedFile(goPackageFileSet.GeneratedDir+"/package.txt", goPackageFileSet.GoImportPath)
+					for _, file := range goPackageFileSet.Files {
+						generatedFile.P(file.Desc.Path())
+					}
+					return nil
+				},
+			),
+		),
+	)
+	assert.Empty(t, response.GetError())
+	nameToContent := make(map[string]string)
+	for _, file := range response.GetFile() {
+		nameToContent[file.GetName()] = file.GetContent()
+	}
+	assert.Equal(
+		t,
+		map[string]string{
+			"a/v1/a.file.txt":  "a/v1/a.proto\n",
+			"a/v1/a2.file.txt": "a/v1/a2.proto\n",
+			"b/v1/b.file.txt":  "b/v1/b.proto\n",
+			"a/v1/package.txt": "a/v1/a.proto\na/v1/a2.proto\n",
+			"b/v1/package.txt": "b/v1/b.proto\n",
+		},
+		nameToContent,
+	)
+}
+
+func TestChainHandlerError(t *testing.T) {
+	t.Parallel()
+	var invoked bool
+	response := testRun(
+		t,
+		NewChainHandler(
+			NewFileHandler(
+				func(*protogen.Plugin, []*protogen.File) error {
+					return errors.New("first failed")
+				},
+			),
+			NewFileHandler(
+				func(*protogen.Plugin, []*protogen.File) error {
+					invoked = true
+					return nil
+				},
+			),
+		),
+	)
+	// Errors returned by the protogen functions are added to the response.
+	assert.Equal(t, "first failed", response.GetError())
+	assert.True(t, invoked)
+}
+
+func testRun(t *testing.T, handler protoplugin.Handler) *pluginpb.CodeGeneratorResponse {
+	request := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"a/v1/a.proto", "a/v1/a2.proto", "b/v1/b.proto"},
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			testNewFileDescriptorProto("a/v1/a.proto", "a.v1"),
+			testNewFileDescriptorProto("a/v1/a2.proto", "a.v1"),
+			testNewFileDescriptorProto("b/v1/b.proto", "b.v1"),
+		},
+		Parameter: proto.String("paths=source_relative"),
+	}
+	requestData, err := proto.Marshal(request)
+	require.NoError(t, err)
+	stdout := bytes.NewBuffer(nil)
+	require.NoError(
+		t,
+		protoplugin.Run(
+			context.Background(),
+			protoplugin.Env{
+				Stdin:  bytes.NewReader(requestData),
+				Stdout: stdout,
+				Stderr: bytes.NewBuffer(nil),
+			},
+			handler,
+		),
+	)
+	response := &pluginpb.CodeGeneratorResponse{}
+	require.NoError(t, proto.Unmarshal(stdout.Bytes(), response))
+	return response
+}
+
+func testNewFileDescriptorProto(path string, pkg string) *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String(path),
+		Package: proto.String(pkg),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String("example.com/gen/" + pkg),
+		},
+	}
+}